@@ -16,7 +16,10 @@
 //	local	enable telemetry collection but disable uploading
 //	off	disable telemetry collection and uploading
 //	view	run a web viewer for local telemetry data
-//	env	print the current telemetry environment
+//	env	print or change the current telemetry environment
+//	status	summarize the state of telemetry collection and uploading
+//	report	print the exact report that would be uploaded
+//	upload	upload reports that are ready now
 //	clean	remove all local telemetry data
 //
 // Use "gotelemetry help <command>" for details about any command.
@@ -26,5 +29,9 @@
 //
 //	csv	print all known counters
 //	dump	view counter file data
-//	upload	run upload with logging enabled
+//	watch	print counter increments as they happen
+//	crashes	list the crash stacks in the local data
+//	export-upload	export reports for upload from another machine
+//	import-upload	upload reports exported from another machine
+//	simulate-upload	print the report that would be uploaded for a week
 package main
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/format"
@@ -12,6 +13,8 @@ import (
 	"go/token"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -80,3 +83,172 @@ package main
 		t.Errorf("doc.go: mismatching content\ngot:\n%s\nwant:\n%s", got, want)
 	}
 }
+
+func TestEnvTelemetryDir(t *testing.T) {
+	testenv.MustHaveExec(t)
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	cmd := exec.Command(exe, "env")
+	cmd.Env = append(os.Environ(), "GOTELEMETRY_RUN_AS_MAIN=1", "GOTELEMETRYDIR="+dir)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"modefile: " + filepath.Join(dir, "mode"),
+		"localdir: " + filepath.Join(dir, "local"),
+		"uploaddir: " + filepath.Join(dir, "upload"),
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("gotelemetry env output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestEnvJSONAndWrite(t *testing.T) {
+	testenv.MustHaveExec(t)
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	env := func(args ...string) ([]byte, error) {
+		cmd := exec.Command(exe, append([]string{"env"}, args...)...)
+		cmd.Env = append(os.Environ(), "GOTELEMETRY_RUN_AS_MAIN=1", "GOTELEMETRYDIR="+dir)
+		return cmd.Output()
+	}
+
+	if _, err := env("-w", "GOTELEMETRY=local"); err != nil {
+		t.Fatalf("gotelemetry env -w GOTELEMETRY=local failed: %v", err)
+	}
+	for _, arg := range []string{"GOTELEMETRY=maybe", "GOTELEMETRYDIR=/tmp", "GOFLAGS=-v", "GOTELEMETRY"} {
+		if _, err := env("-w", arg); err == nil {
+			t.Errorf("gotelemetry env -w %s succeeded unexpectedly", arg)
+		}
+	}
+
+	out, err := env("-json")
+	if err != nil {
+		t.Fatalf("gotelemetry env -json failed: %v", err)
+	}
+	var got struct {
+		Mode, GOTELEMETRYDIR, ModeFile, LocalDir, UploadDir string
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("gotelemetry env -json printed invalid JSON: %v\n%s", err, out)
+	}
+	if got.Mode != "local" || got.GOTELEMETRYDIR != dir ||
+		got.ModeFile != filepath.Join(dir, "mode") ||
+		got.LocalDir != filepath.Join(dir, "local") ||
+		got.UploadDir != filepath.Join(dir, "upload") {
+		t.Errorf("gotelemetry env -json = %+v, want mode local in %s", got, dir)
+	}
+}
+
+func TestClean(t *testing.T) {
+	testenv.MustHaveExec(t)
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string]string{ // file -> category
+		"mode":           "",
+		"local/weekends": "",
+		"local/prog-go1.23-linux-amd64-2024-09-01.v1.count": "counters",
+		"local/quarantine/bad-2024-09-01.v1.count":          "counters",
+		"local/quarantine/bad-2024-09-01.v1.count.err":      "counters",
+		"local/2024-09-08.json":                             "reports",
+		"local/local.2024-09-08.json":                       "reports",
+		"upload/2024-09-01.json":                            "reports",
+		"local/crash/1234.crash":                            "all",
+		"debug/prog-v1-go1.23-20240901-1234.log":            "all",
+	}
+	create := func() {
+		for name := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, nil, 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	clean := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command(exe, append([]string{"clean"}, args...)...)
+		cmd.Env = append(os.Environ(), "GOTELEMETRY_RUN_AS_MAIN=1", "GOTELEMETRYDIR="+dir)
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("gotelemetry clean %v failed: %v", args, err)
+		}
+		return string(out)
+	}
+
+	for _, test := range []struct {
+		args   []string
+		remove []string // categories removed
+	}{
+		{nil, []string{"counters", "reports"}},
+		{[]string{"-counters"}, []string{"counters"}},
+		{[]string{"-reports"}, []string{"reports"}},
+		{[]string{"-all"}, []string{"counters", "reports", "all"}},
+		{[]string{"-all", "-dry-run"}, nil},
+	} {
+		create()
+		out := clean(test.args...)
+		for name, category := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			_, err := os.Stat(path)
+			removed := os.IsNotExist(err)
+			if want := slices.Contains(test.remove, category); removed != want {
+				t.Errorf("gotelemetry clean %v: %s removed = %t, want %t", test.args, name, removed, want)
+			}
+			if category != "" && slices.Contains(test.args, "-dry-run") && !strings.Contains(out, "would remove "+path) {
+				t.Errorf("gotelemetry clean %v output does not mention %s:\n%s", test.args, name, out)
+			}
+			if removed && !strings.Contains(out, "removed "+path) {
+				t.Errorf("gotelemetry clean %v output does not mention %s:\n%s", test.args, name, out)
+			}
+		}
+	}
+}
+
+func TestStatusQuarantined(t *testing.T) {
+	testenv.MustHaveExec(t)
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	quarantine := filepath.Join(dir, "local", "quarantine")
+	if err := os.MkdirAll(quarantine, 0777); err != nil {
+		t.Fatal(err)
+	}
+	record := `{"Name":"bad-2024-09-01.v1.count","Err":"invalid header","Attempts":3}`
+	if err := os.WriteFile(filepath.Join(quarantine, "bad-2024-09-01.v1.count.err"), []byte(record), 0666); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(exe, "status")
+	cmd.Env = append(os.Environ(), "GOTELEMETRY_RUN_AS_MAIN=1", "GOTELEMETRYDIR="+dir)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"quarantined count files: 1\n",
+		"  bad-2024-09-01.v1.count (3 failed attempts): invalid header\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("gotelemetry status output does not contain %q:\n%s", want, out)
+		}
+	}
+}
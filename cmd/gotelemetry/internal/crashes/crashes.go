@@ -0,0 +1,194 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package crashes collects the crash stacks recorded by the crash monitor
+// in the local telemetry data, for the "gotelemetry crashes" command.
+package crashes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/internal/counter"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// A Crash is a distinct crash stack, with its occurrences summed over all
+// the weeks and program versions in which it was recorded.
+type Crash struct {
+	Name     string   // the stack counter name, such as "crash/crash"
+	Stack    []string // the frames, innermost first
+	Count    int64
+	Programs []string // program@version, sorted
+	Weeks    []string // end dates of the weeks, sorted
+}
+
+// An occurrence is the count of a stack counter in one count file or
+// report.
+type occurrence struct {
+	program, week string
+	counter       string // as stored: compressed, with the stack counter name on the first line
+	count         int64
+}
+
+// Collect returns the crashes recorded in the count files and local reports
+// in dir, sorted by decreasing count. Files that cannot be read are
+// skipped.
+//
+// Only local reports are read, since uploaded reports hold a subset of the
+// same data.
+func Collect(dir string) ([]*Crash, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var occs []occurrence
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasSuffix(name, ".v1.count"):
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			f, err := counter.Parse(name, data)
+			if err != nil {
+				continue
+			}
+			week := f.Meta["TimeEnd"]
+			if t, err := time.Parse(time.RFC3339, week); err == nil {
+				week = t.Format(telemetry.DateOnly)
+			}
+			for c, n := range f.Count {
+				occs = append(occs, occurrence{f.Meta["Program"] + "@" + f.Meta["Version"], week, c, int64(n)})
+			}
+		case strings.HasPrefix(name, "local.") && strings.HasSuffix(name, ".json"):
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			var r telemetry.Report
+			if err := json.Unmarshal(data, &r); err != nil {
+				continue
+			}
+			for _, p := range r.Programs {
+				for c, n := range p.Stacks {
+					occs = append(occs, occurrence{p.Program + "@" + p.Version, r.Week, c, n})
+				}
+			}
+		}
+	}
+	return aggregate(occs), nil
+}
+
+// aggregate combines the occurrences of the same crash stack, ignoring
+// counters other than crash stack counters, and sorts the crashes by
+// decreasing count, then by name and stack.
+func aggregate(occs []occurrence) []*Crash {
+	type set map[string]bool
+	type crash struct {
+		*Crash
+		programs, weeks set
+	}
+	crashes := make(map[string]*crash) // by decoded counter name
+	for _, o := range occs {
+		if !strings.HasPrefix(o.counter, "crash/") || !counter.IsStackCounter(o.counter) {
+			continue
+		}
+		key := counter.DecodeStack(o.counter)
+		c := crashes[key]
+		if c == nil {
+			name, stack, _ := strings.Cut(key, "\n")
+			var frames []string
+			for _, f := range strings.Split(stack, "\n") {
+				if f != "" {
+					frames = append(frames, f)
+				}
+			}
+			c = &crash{
+				Crash:    &Crash{Name: name, Stack: frames},
+				programs: make(set),
+				weeks:    make(set),
+			}
+			crashes[key] = c
+		}
+		c.Count += o.count
+		c.programs[o.program] = true
+		c.weeks[o.week] = true
+	}
+	sorted := func(s set) []string {
+		var l []string
+		for k := range s {
+			l = append(l, k)
+		}
+		sort.Strings(l)
+		return l
+	}
+	result := make([]*Crash, 0, len(crashes))
+	for _, c := range crashes {
+		c.Programs = sorted(c.programs)
+		c.Weeks = sorted(c.weeks)
+		result = append(result, c.Crash)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		x, y := result[i], result[j]
+		if x.Count != y.Count {
+			return x.Count > y.Count
+		}
+		if x.Name != y.Name {
+			return x.Name < y.Name
+		}
+		return strings.Join(x.Stack, "\n") < strings.Join(y.Stack, "\n")
+	})
+	return result
+}
+
+// Write writes a summary of c to w: its count, name, programs, and weeks,
+// followed by its frames, indented.
+func Write(w io.Writer, c *Crash) error {
+	fmt.Fprintf(w, "%d %s (%s; %s)\n", c.Count, c.Name, strings.Join(c.Programs, ", "), weeks(c.Weeks))
+	for _, f := range c.Stack {
+		fmt.Fprintf(w, "\t%s\n", f)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// WriteIssue writes a draft bug report for c to w, in the Markdown used by
+// the issue tracker: a title line, followed by a body describing the crash.
+// The frames record the line relative to the start of each function.
+func WriteIssue(w io.Writer, c *Crash) error {
+	top := "unknown function"
+	for _, f := range c.Stack {
+		// Skip the runtime frames of the panic or fatal error itself.
+		if !strings.HasPrefix(f, "runtime.") {
+			top, _, _ = strings.Cut(f, ":")
+			break
+		}
+	}
+	program, _, _ := strings.Cut(c.Programs[0], "@")
+	fmt.Fprintf(w, "Title: %s: crash in %s\n\n", program, top)
+	fmt.Fprintf(w, "This stack (%s) was reported by telemetry %d times, in %s.\n\n", c.Name, c.Count, weeks(c.Weeks))
+	fmt.Fprintf(w, "Program versions: %s\n\n", strings.Join(c.Programs, ", "))
+	fmt.Fprintf(w, "```\n%s\n```\n\n", strings.Join(c.Stack, "\n"))
+	_, err := fmt.Fprintln(w, "Line numbers are relative to the start of each function.")
+	return err
+}
+
+// weeks describes the range of the given sorted weeks.
+func weeks(ws []string) string {
+	switch len(ws) {
+	case 0:
+		return "no weeks"
+	case 1:
+		return "the week ending " + ws[0]
+	}
+	return fmt.Sprintf("%d weeks from %s to %s", len(ws), ws[0], ws[len(ws)-1])
+}
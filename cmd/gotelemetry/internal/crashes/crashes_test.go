@@ -0,0 +1,61 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashes
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	const (
+		stackA = "crash/crash\nruntime.gopanic:+69\ngolang.org/x/tools/gopls/internal/cache.f:+3\n\".g:+1\n"
+		stackB = "crash/crash\nruntime.throw:+9\nmain.main:+2\n"
+	)
+	occs := []occurrence{
+		{"gopls@v0.16.1", "2024-09-01", stackA, 1},
+		{"gopls@v0.16.2", "2024-09-08", stackA, 2},
+		{"gopls@v0.16.2", "2024-09-08", stackB, 1},
+		{"gopls@v0.16.2", "2024-09-08", "crash/reason:panic", 3},      // not a stack
+		{"gopls@v0.16.2", "2024-09-08", "gopls/bug\nmain.main:+1", 5}, // not a crash
+	}
+	got := aggregate(occs)
+	want := []*Crash{
+		{
+			Name:     "crash/crash",
+			Stack:    []string{"runtime.gopanic:+69", "golang.org/x/tools/gopls/internal/cache.f:+3", "golang.org/x/tools/gopls/internal/cache.g:+1"},
+			Count:    3,
+			Programs: []string{"gopls@v0.16.1", "gopls@v0.16.2"},
+			Weeks:    []string{"2024-09-01", "2024-09-08"},
+		},
+		{
+			Name:     "crash/crash",
+			Stack:    []string{"runtime.throw:+9", "main.main:+2"},
+			Count:    1,
+			Programs: []string{"gopls@v0.16.2"},
+			Weeks:    []string{"2024-09-08"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("aggregate() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteIssue(&buf, got[0]); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"Title: gopls: crash in golang.org/x/tools/gopls/internal/cache.f\n",
+		"3 times, in 2 weeks from 2024-09-01 to 2024-09-08",
+		"Program versions: gopls@v0.16.1, gopls@v0.16.2",
+		"```\nruntime.gopanic:+69\n",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("WriteIssue output does not contain %q:\n%s", s, &buf)
+		}
+	}
+}
@@ -31,7 +31,8 @@ type file struct {
 	report   *telemetry.Report
 }
 
-func Csv() {
+// Csv prints the counters for which keep returns true.
+func Csv(keep func(counter string) bool) {
 	files, err := readdir(telemetry.Default.LocalDir(), nil)
 	if err != nil {
 		log.Fatal(err)
@@ -63,7 +64,7 @@ func Csv() {
 			f.report = &x
 		}
 	}
-	printTable(files)
+	printTable(files, keep)
 }
 
 type record struct {
@@ -72,9 +73,12 @@ type record struct {
 	count                                    int
 }
 
-func printTable(files []*file) {
+func printTable(files []*file, keep func(string) bool) {
 	lines := make(map[string]*record)
 	work := func(k string, v int64, rec *record) {
+		if !keep(k) {
+			return
+		}
 		x, ok := lines[k]
 		if !ok {
 			x = new(record)
@@ -112,6 +116,9 @@ func printTable(files []*file) {
 				for k, v := range p.Stacks {
 					work(k, v, &rec)
 				}
+				for k, v := range p.Events {
+					work(k, v, &rec)
+				}
 			}
 		}
 	}
@@ -0,0 +1,146 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dump formats the counters of count files as rows, one per
+// counter, for the "gotelemetry dump" command.
+package dump
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/telemetry/internal/counter"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{"json", "csv", "table"}
+
+// A Row is the value of a counter in a count file. Merged rows hold the sum
+// of the values of a counter over all the count files recorded by the same
+// program build, and leave File, TimeBegin, and TimeEnd empty.
+type Row struct {
+	File      string `json:",omitempty"`
+	TimeBegin string `json:",omitempty"`
+	TimeEnd   string `json:",omitempty"`
+	Program   string
+	Version   string
+	GoVersion string
+	GOOS      string
+	GOARCH    string
+	Counter   string
+	Count     uint64
+}
+
+// Rows returns the rows for the given count files, which are keyed by file
+// name. If merge is set, the rows for the same counter and program build
+// are summed. The rows are sorted by program build, then counter name, then
+// file.
+func Rows(files map[string]*counter.File, merge bool) []Row {
+	type key struct {
+		file, program, version, goVersion, goos, goarch, counter string
+	}
+	sums := make(map[key]*Row)
+	for name, f := range files {
+		for c, n := range f.Count {
+			r := Row{
+				File:      name,
+				TimeBegin: f.Meta["TimeBegin"],
+				TimeEnd:   f.Meta["TimeEnd"],
+				Program:   f.Meta["Program"],
+				Version:   f.Meta["Version"],
+				GoVersion: f.Meta["GoVersion"],
+				GOOS:      f.Meta["GOOS"],
+				GOARCH:    f.Meta["GOARCH"],
+				Counter:   c,
+			}
+			if merge {
+				r.File, r.TimeBegin, r.TimeEnd = "", "", ""
+			}
+			k := key{r.File, r.Program, r.Version, r.GoVersion, r.GOOS, r.GOARCH, r.Counter}
+			if sums[k] == nil {
+				sums[k] = &r
+			}
+			sums[k].Count += n
+		}
+	}
+	rows := make([]Row, 0, len(sums))
+	for _, r := range sums {
+		rows = append(rows, *r)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		x, y := rows[i], rows[j]
+		for _, cmp := range [...][2]string{
+			{x.Program, y.Program},
+			{x.Version, y.Version},
+			{x.GoVersion, y.GoVersion},
+			{x.GOOS, y.GOOS},
+			{x.GOARCH, y.GOARCH},
+			{x.Counter, y.Counter},
+		} {
+			if cmp[0] != cmp[1] {
+				return cmp[0] < cmp[1]
+			}
+		}
+		return x.File < y.File
+	})
+	return rows
+}
+
+// Write writes rows to w in the given format, which must be one of
+// Formats. If merged is set, the columns that are empty in merged rows are
+// omitted.
+func Write(w io.Writer, rows []Row, format string, merged bool) error {
+	switch format {
+	case "json":
+		if rows == nil {
+			rows = []Row{} // print [], not null
+		}
+		data, err := json.MarshalIndent(rows, "", "\t")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write(header(merged))
+		for _, r := range rows {
+			cw.Write(fields(r, merged))
+		}
+		cw.Flush()
+		return cw.Error()
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header(merged), "\t"))
+		for _, r := range rows {
+			// Stack counter names span several lines; keep each row on one.
+			r.Counter = strings.ReplaceAll(r.Counter, "\n", `\n`)
+			fmt.Fprintln(tw, strings.Join(fields(r, merged), "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(Formats, ", "))
+}
+
+func header(merged bool) []string {
+	h := []string{"Program", "Version", "GoVersion", "GOOS", "GOARCH", "Counter", "Count"}
+	if !merged {
+		h = append([]string{"File", "TimeBegin", "TimeEnd"}, h...)
+	}
+	return h
+}
+
+func fields(r Row, merged bool) []string {
+	f := []string{r.Program, r.Version, r.GoVersion, r.GOOS, r.GOARCH, r.Counter, strconv.FormatUint(r.Count, 10)}
+	if !merged {
+		f = append([]string{r.File, r.TimeBegin, r.TimeEnd}, f...)
+	}
+	return f
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dump
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"golang.org/x/telemetry/internal/counter"
+)
+
+func testFiles() map[string]*counter.File {
+	meta := func(begin, version string) map[string]string {
+		return map[string]string{
+			"TimeBegin": begin,
+			"TimeEnd":   "2024-09-08T00:00:00Z",
+			"Program":   "prog",
+			"Version":   version,
+			"GoVersion": "go1.23.0",
+			"GOOS":      "linux",
+			"GOARCH":    "amd64",
+		}
+	}
+	return map[string]*counter.File{
+		"a.v1.count": {Meta: meta("2024-09-01T00:00:00Z", "v1.0.0"), Count: map[string]uint64{"c": 1, "crash\nmain.f:+1": 2}},
+		"b.v1.count": {Meta: meta("2024-09-02T00:00:00Z", "v1.0.0"), Count: map[string]uint64{"c": 4}},
+		"c.v1.count": {Meta: meta("2024-09-02T00:00:00Z", "v2.0.0"), Count: map[string]uint64{"c": 8}},
+	}
+}
+
+func TestRows(t *testing.T) {
+	row := func(file, begin, version, counter string, count uint64) Row {
+		r := Row{
+			File:      file,
+			TimeBegin: begin,
+			Program:   "prog",
+			Version:   version,
+			GoVersion: "go1.23.0",
+			GOOS:      "linux",
+			GOARCH:    "amd64",
+			Counter:   counter,
+			Count:     count,
+		}
+		if file != "" {
+			r.TimeEnd = "2024-09-08T00:00:00Z"
+		}
+		return r
+	}
+	got := Rows(testFiles(), false)
+	want := []Row{
+		row("a.v1.count", "2024-09-01T00:00:00Z", "v1.0.0", "c", 1),
+		row("b.v1.count", "2024-09-02T00:00:00Z", "v1.0.0", "c", 4),
+		row("a.v1.count", "2024-09-01T00:00:00Z", "v1.0.0", "crash\nmain.f:+1", 2),
+		row("c.v1.count", "2024-09-02T00:00:00Z", "v2.0.0", "c", 8),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rows(merge=false) =\n%+v\nwant\n%+v", got, want)
+	}
+
+	got = Rows(testFiles(), true)
+	want = []Row{
+		row("", "", "v1.0.0", "c", 5),
+		row("", "", "v1.0.0", "crash\nmain.f:+1", 2),
+		row("", "", "v2.0.0", "c", 8),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rows(merge=true) =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rows := Rows(testFiles(), true)
+	write := func(format string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := Write(&buf, rows, format, true); err != nil {
+			t.Fatalf("Write(%s) failed: %v", format, err)
+		}
+		return buf.String()
+	}
+
+	var decoded []Row
+	if err := json.Unmarshal([]byte(write("json")), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, rows) {
+		t.Errorf("JSON round trip = %+v, want %+v", decoded, rows)
+	}
+
+	wantCSV := `Program,Version,GoVersion,GOOS,GOARCH,Counter,Count
+prog,v1.0.0,go1.23.0,linux,amd64,c,5
+prog,v1.0.0,go1.23.0,linux,amd64,"crash
+main.f:+1",2
+prog,v2.0.0,go1.23.0,linux,amd64,c,8
+`
+	if got := write("csv"); got != wantCSV {
+		t.Errorf("CSV output:\n%s\nwant:\n%s", got, wantCSV)
+	}
+
+	wantTable := `Program  Version  GoVersion  GOOS   GOARCH  Counter           Count
+prog     v1.0.0   go1.23.0   linux  amd64   c                 5
+prog     v1.0.0   go1.23.0   linux  amd64   crash\nmain.f:+1  2
+prog     v2.0.0   go1.23.0   linux  amd64   c                 8
+`
+	if got := write("table"); got != wantTable {
+		t.Errorf("table output:\n%s\nwant:\n%s", got, wantTable)
+	}
+
+	if err := Write(&bytes.Buffer{}, rows, "xml", true); err == nil {
+		t.Error("Write(xml) succeeded unexpectedly")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package glob matches counter names against glob patterns, for selecting
+// counters on the gotelemetry command line.
+//
+// In a pattern, '*' matches any sequence of characters other than '/',
+// "**" matches any sequence of characters, '?' matches any single character
+// other than '/', and all other characters match themselves. Since '/'
+// separates the levels of the counter name hierarchy, "gopls/*" matches
+// "gopls/client:vscode" but not "gopls/completion/latency:<10ms", which is
+// matched by "gopls/**".
+//
+// A stack counter is matched by its name as passed to counter.NewStack,
+// which is the first line of the names of its counters.
+package glob
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// A Pattern is a compiled glob pattern.
+type Pattern struct {
+	re *regexp.Regexp
+}
+
+// Compile compiles a glob pattern.
+func Compile(pattern string) (*Pattern, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("empty counter pattern")
+	}
+	var b strings.Builder
+	b.WriteString(`\A`)
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(`.*`)
+				i++
+			} else {
+				b.WriteString(`[^/]*`)
+			}
+		case '?':
+			b.WriteString(`[^/]`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString(`\z`)
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid counter pattern %q: %v", pattern, err)
+	}
+	return &Pattern{re}, nil
+}
+
+// Match reports whether the counter with the given name, as it appears in a
+// count file or report, matches the pattern.
+func (p *Pattern) Match(name string) bool {
+	name, _, _ = strings.Cut(name, "\n") // stack counter
+	return p.re.MatchString(name)
+}
+
+// A Set is a set of patterns, which matches a counter if any of its
+// patterns does. The empty set matches all counters.
+type Set []*Pattern
+
+// CompileSet compiles each of the given patterns.
+func CompileSet(patterns []string) (Set, error) {
+	var s Set
+	for _, pattern := range patterns {
+		p, err := Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, p)
+	}
+	return s, nil
+}
+
+// Match reports whether the counter with the given name matches any of the
+// patterns of s, or s is empty.
+func (s Set) Match(name string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, p := range s {
+		if p.Match(name) {
+			return true
+		}
+	}
+	return false
+}
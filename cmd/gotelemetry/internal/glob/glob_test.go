@@ -0,0 +1,61 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"gopls/**", "gopls/client:vscode", true},
+		{"gopls/**", "gopls/completion/latency:<10ms", true},
+		{"gopls/**", "gopls", false},
+		{"gopls/*", "gopls/client:vscode", true},
+		{"gopls/*", "gopls/completion/latency:<10ms", false},
+		{"crash/*", "crash/crash", true},
+		{"crash/*", "crash/malformed\nruntime.f:+1\nruntime.g:+2", true}, // stack prefix
+		{"runtime.*", "crash/crash\nruntime.f:+1", false},                // frames are not matched
+		{"editor:*", "editor:vim", true},
+		{"editor:v?m", "editor:vim", true},
+		{"editor:v?m", "editor:vim9", false},
+		{"go/*:{a,b}", "go/x:{a,b}", true}, // no special meaning
+		{"go/*:{a,b}", "go/x:a", false},
+		{"a.b", "axb", false}, // '.' is literal
+		{"**", "anything/at/all", true},
+	}
+	for _, test := range tests {
+		p, err := Compile(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.Match(test.name); got != test.want {
+			t.Errorf("Compile(%q).Match(%q) = %t, want %t", test.pattern, test.name, got, test.want)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	if _, err := CompileSet([]string{"gopls/*", ""}); err == nil {
+		t.Error("CompileSet with an empty pattern succeeded unexpectedly")
+	}
+	s, err := CompileSet([]string{"gopls/*", "crash/**"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{
+		"gopls/client:vim":  true,
+		"crash/a/b":         true,
+		"go/buildcache/hit": false,
+	} {
+		if got := s.Match(name); got != want {
+			t.Errorf("Set.Match(%q) = %t, want %t", name, got, want)
+		}
+	}
+	if !Set(nil).Match("anything") {
+		t.Error("empty Set does not match")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// exportPage is the data for a standalone HTML page of the charts of the
+// index page, with the styles, scripts, and data inlined, so that it can be
+// saved and shared, for example by attaching it to an issue.
+type exportPage struct {
+	Generated       string // time the page was generated
+	RequestedConfig string
+	Filter          filter
+	Charts          *chartdata
+}
+
+// handleExport serves the charts selected by the URL query as a standalone
+// HTML file, to be saved by the browser.
+func (s *Server) handleExport(fsys fs.FS) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil
+		}
+		data, err := s.exportPage(r.URL.Query().Get("config"), filter)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="gotelemetry-charts.html"`)
+		return renderTemplate(w, fsys, "export.html", data, http.StatusOK)
+	}
+}
+
+// Export writes the charts of all the local data, as a standalone HTML
+// file, to the named file.
+func (s *Server) Export(file string) error {
+	fsys, err := s.content()
+	if err != nil {
+		return err
+	}
+	data, err := s.exportPage("", filter{})
+	if err != nil {
+		return err
+	}
+	buf, err := executeTemplate(fsys, "export.html", data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0666)
+}
+
+// exportPage returns the data for the export page, charting the reports and
+// counter files selected by filter against the requested config.
+func (s *Server) exportPage(requestedConfig string, filter filter) (*exportPage, error) {
+	if requestedConfig == "" {
+		requestedConfig = "latest"
+	}
+	cfg, err := s.configAt(requestedConfig)
+	if err != nil {
+		log.Printf("Falling back to empty config: %v", err)
+		cfg, _ = s.configAt("empty")
+	}
+	localDir := telemetry.Default.LocalDir()
+	if _, err := os.Stat(localDir); err != nil {
+		return nil, fmt.Errorf(
+			`The telemetry dir %s does not exist.
+There is nothing to report.`, localDir)
+	}
+	reports, err := reports(localDir, cfg)
+	if err != nil {
+		return nil, err
+	}
+	files, err := files(localDir, cfg)
+	if err != nil {
+		return nil, err
+	}
+	charts, err := charts(append(filter.reports(reports), pending(filter.files(files), cfg)...), cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &exportPage{
+		Generated:       time.Now().UTC().Format(time.DateTime + " MST"),
+		RequestedConfig: requestedConfig,
+		Filter:          filter,
+		Charts:          charts,
+	}, nil
+}
+
+// inline returns the contents of a static asset, for inclusion in a page,
+// without the comment that links to its source map.
+func inline(fsys fs.FS, urlPath string) (string, error) {
+	data, err := fs.ReadFile(fsys, strings.TrimPrefix(urlPath, "/"))
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if !bytes.Contains(line, []byte("sourceMappingURL=")) {
+			b.Write(line)
+		}
+	}
+	return b.String(), nil
+}
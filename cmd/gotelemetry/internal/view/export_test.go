@@ -0,0 +1,52 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_exportTemplate(t *testing.T) {
+	fsys, err := (&Server{}).content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := &exportPage{
+		Generated:       "2024-09-09 12:00:00 UTC",
+		RequestedConfig: "v0.1.0",
+		Filter:          filter{Program: "golang.org/x/tools/gopls"},
+		Charts: &chartdata{
+			Programs: []*program{{
+				ID:   "charts:golang.org/x/tools/gopls",
+				Name: "golang.org/x/tools/gopls",
+				Counters: []*counter{{
+					ID:   "charts:golang.org/x/tools/gopls:gopls/client",
+					Name: "gopls/client",
+				}},
+			}},
+		},
+	}
+	buf, err := executeTemplate(fsys, "export.html", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`data-chart-id="charts:golang.org/x/tools/gopls:gopls/client"`,
+		"gopls/client (not in the upload config)",
+		"Program: golang.org/x/tools/gopls.",
+		"window.Page =",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("export page does not contain %q", want)
+		}
+	}
+	// The page must be self-contained.
+	if m := regexp.MustCompile(`(src|href)="/[^"]*"|sourceMappingURL`).FindString(out); m != "" {
+		t.Errorf("export page refers to another file: %s", m)
+	}
+}
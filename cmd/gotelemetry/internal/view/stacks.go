@@ -0,0 +1,195 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/telemetry/internal/config"
+	tcounter "golang.org/x/telemetry/internal/counter"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// stacksPage is the data for the stacks page, which shows the stack
+// counters of the selected reports and counter files as trees of frames.
+type stacksPage struct {
+	Filter          filter
+	RequestedConfig string
+	Groups          []*stackGroup
+}
+
+// A stackGroup holds the stacks of one stack counter of a program.
+type stackGroup struct {
+	ID      string
+	Program string
+	Name    string // the name passed to counter.NewStack
+	Count   int64
+	Active  bool // whether the config includes the stack counter
+	Roots   []*stackNode
+}
+
+// A stackNode is a frame of the stacks of a stackGroup. The children of a
+// frame are its callers, and its count is the sum of the counts of all the
+// stacks that share the frames from the root to it.
+type stackNode struct {
+	Frame    string // e.g. "golang.org/x/tools/gopls/internal/bug.report:+35"
+	URL      string // link to the source of the frame's package, or ""
+	Count    int64
+	Children []*stackNode
+}
+
+func (s *Server) handleStacks(fsys fs.FS) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		requestedConfig := r.URL.Query().Get("config")
+		if requestedConfig == "" {
+			requestedConfig = "latest"
+		}
+		cfg, err := s.configAt(requestedConfig)
+		if err != nil {
+			log.Printf("Falling back to empty config: %v", err)
+			cfg, _ = s.configAt("empty")
+		}
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil
+		}
+		localDir := telemetry.Default.LocalDir()
+		if _, err := os.Stat(localDir); err != nil {
+			return fmt.Errorf(
+				`The telemetry dir %s does not exist.
+There is nothing to report.`, localDir)
+		}
+		reports, err := reports(localDir, cfg)
+		if err != nil {
+			return err
+		}
+		files, err := files(localDir, cfg)
+		if err != nil {
+			return err
+		}
+		data := stacksPage{
+			Filter:          filter,
+			RequestedConfig: requestedConfig,
+			Groups:          stackGroups(append(filter.reports(reports), pending(filter.files(files), cfg)...), cfg),
+		}
+		return renderTemplate(w, fsys, "stacks.html", data, http.StatusOK)
+	}
+}
+
+// stackGroups aggregates the stack counters of the given reports into a
+// tree for each program and stack counter, sorted by program and name.
+func stackGroups(reports []*telemetryReport, cfg *config.Config) []*stackGroup {
+	type key struct{ program, name string }
+	groups := make(map[key]*stackGroup)
+	for _, r := range reports {
+		for _, p := range r.Programs {
+			for name, count := range p.Stacks {
+				frames := strings.Split(tcounter.DecodeStack(name), "\n")
+				k := key{p.Program, frames[0]}
+				g := groups[k]
+				if g == nil {
+					g = &stackGroup{
+						ID:      "stacks:" + p.Program + ":" + frames[0],
+						Program: p.Program,
+						Name:    frames[0],
+						Active:  cfg.HasStack(p.Program, frames[0]),
+					}
+					groups[k] = g
+				}
+				g.Count += count
+				nodes := &g.Roots
+				for _, frame := range frames[1:] {
+					if frame == "" {
+						continue
+					}
+					n := findFrame(nodes, frame)
+					n.Count += count
+					nodes = &n.Children
+				}
+			}
+		}
+	}
+	result := make([]*stackGroup, 0, len(groups))
+	for _, g := range groups {
+		sortNodes(g.Roots)
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Program != result[j].Program {
+			return result[i].Program < result[j].Program
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// findFrame returns the node for frame in *nodes, adding it if necessary.
+func findFrame(nodes *[]*stackNode, frame string) *stackNode {
+	for _, n := range *nodes {
+		if n.Frame == frame {
+			return n
+		}
+	}
+	n := &stackNode{Frame: frame, URL: frameURL(frame)}
+	*nodes = append(*nodes, n)
+	return n
+}
+
+// sortNodes sorts a tree of nodes by decreasing count, then by frame.
+func sortNodes(nodes []*stackNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Count != nodes[j].Count {
+			return nodes[i].Count > nodes[j].Count
+		}
+		return nodes[i].Frame < nodes[j].Frame
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
+
+// frameURL returns a link to the source of the package of the function in
+// a stack frame, on cs.opensource.google, if the frame belongs to the Go
+// repository or a golang.org/x module. Line numbers in frames are relative
+// to the start of the function, so the link is to the package, not the line.
+func frameURL(frame string) string {
+	pkg := framePackage(frame)
+	if pkg == "" {
+		return ""
+	}
+	if rest, ok := strings.CutPrefix(pkg, "golang.org/x/"); ok {
+		repo, dir, _ := strings.Cut(rest, "/")
+		return "https://cs.opensource.google/go/x/" + repo + "/+/master:" + dir
+	}
+	if first, _, _ := strings.Cut(pkg, "/"); !strings.Contains(first, ".") {
+		// The standard library or cmd.
+		return "https://cs.opensource.google/go/go/+/master:src/" + pkg
+	}
+	return ""
+}
+
+// framePackage returns the import path of the package of the function in
+// a stack frame, or "" if it cannot be determined.
+func framePackage(frame string) string {
+	// Trim the line, and any receiver type or type arguments, which may
+	// contain slashes or dots.
+	fn, _, _ := strings.Cut(frame, ":")
+	if i := strings.IndexAny(fn, "(["); i >= 0 {
+		fn = fn[:i]
+	}
+	slash := strings.LastIndex(fn, "/")
+	dot := strings.Index(fn[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return fn[:slash+1+dot]
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func Test_stackGroups(t *testing.T) {
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		Programs: []*telemetry.ProgramConfig{{
+			Name:   "gopls",
+			Stacks: []telemetry.CounterConfig{{Name: "gopls/bug"}},
+		}},
+	})
+	program := func(name string, stacks map[string]int64) *telemetryProgram {
+		return &telemetryProgram{ProgramReport: &telemetry.ProgramReport{Program: name, Stacks: stacks}}
+	}
+	reports := []*telemetryReport{
+		{Programs: []*telemetryProgram{
+			program("gopls", map[string]int64{
+				"gopls/bug\nexample.com/bug.report:+1\n\".Errorf:+2\nexample.com/a.f:+3": 1,
+				"gopls/bug\nexample.com/bug.report:+1\n\".Reportf:+4":                    2,
+			}),
+			program("cmd/go", map[string]int64{"crash\nruntime.panic:+1": 8}),
+		}},
+		{Programs: []*telemetryProgram{
+			program("gopls", map[string]int64{
+				"gopls/bug\nexample.com/bug.report:+1\n\".Errorf:+2\nexample.com/a.f:+3": 4,
+			}),
+		}},
+	}
+
+	// dump renders the groups as an indented tree.
+	var dump func(b *strings.Builder, depth int, nodes []*stackNode)
+	dump = func(b *strings.Builder, depth int, nodes []*stackNode) {
+		for _, n := range nodes {
+			fmt.Fprintf(b, "%s%s %d\n", strings.Repeat("  ", depth), n.Frame, n.Count)
+			dump(b, depth+1, n.Children)
+		}
+	}
+	var b strings.Builder
+	for _, g := range stackGroups(reports, cfg) {
+		fmt.Fprintf(&b, "%s %s %d %t\n", g.Program, g.Name, g.Count, g.Active)
+		dump(&b, 1, g.Roots)
+	}
+	want := `cmd/go crash 8 false
+  runtime.panic:+1 8
+gopls gopls/bug 7 true
+  example.com/bug.report:+1 7
+    example.com/bug.Errorf:+2 5
+      example.com/a.f:+3 5
+    example.com/bug.Reportf:+4 2
+`
+	if got := b.String(); got != want {
+		t.Errorf("stackGroups() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_frameURL(t *testing.T) {
+	for frame, want := range map[string]string{
+		"golang.org/x/tools/gopls/internal/util/bug.report:+35":       "https://cs.opensource.google/go/x/tools/+/master:gopls/internal/util/bug",
+		"golang.org/x/tools/gopls/internal/server.(*server).Hover:+5": "https://cs.opensource.google/go/x/tools/+/master:gopls/internal/server",
+		"golang.org/x/telemetry.Start:+2":                             "https://cs.opensource.google/go/x/telemetry/+/master:",
+		"runtime.gopanic:+50":                                         "https://cs.opensource.google/go/go/+/master:src/runtime",
+		"cmd/go/internal/work.(*Builder).Do.func3:+1":                 "https://cs.opensource.google/go/go/+/master:src/cmd/go/internal/work",
+		"example.com/mod/pkg.F[go.shape.string]:+1":                   "",
+		"truncated": "",
+	} {
+		if got := frameURL(frame); got != want {
+			t.Errorf("frameURL(%q) = %q, want %q", frame, got, want)
+		}
+	}
+}
@@ -8,7 +8,12 @@ package view
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"html/template"
@@ -16,6 +21,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"sort"
@@ -30,6 +36,7 @@ import (
 	tcounter "golang.org/x/telemetry/internal/counter"
 	"golang.org/x/telemetry/internal/telemetry"
 	"golang.org/x/telemetry/internal/unionfs"
+	"golang.org/x/telemetry/internal/uploadable"
 )
 
 type Server struct {
@@ -37,34 +44,125 @@ type Server struct {
 	Dev      bool
 	FsConfig string
 	Open     bool
+
+	// Socket, if set, is the path of a unix domain socket to serve on,
+	// instead of Addr. The socket is accessible only to the current user.
+	Socket string
+
+	// Token, if set, causes the server (when serving on Addr) to require a
+	// random token, which is included in the URL it prints and opens, so
+	// that other users of the machine cannot view the data.
+	Token bool
 }
 
+// tokenCookie is the name of the cookie that holds the access token, once
+// it has been presented in the URL.
+const tokenCookie = "gotelemetry-view-token"
+
 // Serve starts the telemetry viewer and runs indefinitely.
 func (s *Server) Serve() {
-	var fsys fs.FS = contentfs.FS
 	if s.Dev {
-		fsys = os.DirFS("internal/content")
 		contentfs.RunESBuild(true)
 	}
-
-	var err error
-	fsys, err = unionfs.Sub(fsys, "gotelemetryview", "shared")
+	fsys, err := s.content()
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	mux := http.NewServeMux()
-	mux.Handle("/", s.handleIndex(fsys))
+	mux.Handle("/", errorPages(fsys, s.handleIndex(fsys)))
+	mux.Handle("/stacks", errorPages(fsys, s.handleStacks(fsys)))
+	mux.Handle("/export", errorPages(fsys, s.handleExport(fsys)))
+
+	if s.Socket != "" {
+		listener, err := listenUnix(s.Socket)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("server listening on unix socket %s\n", s.Socket)
+		log.Fatal(http.Serve(listener, mux))
+	}
+
 	listener, err := net.Listen("tcp", s.Addr)
 	if err != nil {
 		log.Fatal(err)
 	}
+	var handler http.Handler = mux
 	addr := fmt.Sprintf("http://%s", listener.Addr())
+	if s.Token {
+		token := make([]byte, 16)
+		if _, err := rand.Read(token); err != nil {
+			log.Fatal(err)
+		}
+		handler = requireToken(hex.EncodeToString(token), mux)
+		addr += "/?token=" + hex.EncodeToString(token)
+	}
 	fmt.Printf("server listening at %s\n", addr)
 	if s.Open {
 		browser.Open(addr)
 	}
-	log.Fatal(http.Serve(listener, mux))
+	log.Fatal(http.Serve(listener, handler))
+}
+
+// content returns the templates and static assets of the viewer. In dev
+// mode, the files in internal/content shadow the embedded ones.
+func (s *Server) content() (fs.FS, error) {
+	fsys, err := unionfs.Sub(contentfs.FS, "gotelemetryview", "shared")
+	if err != nil || !s.Dev {
+		return fsys, err
+	}
+	disk, err := unionfs.Sub(unionfs.DirFS("internal/content"), "gotelemetryview", "shared")
+	if err != nil {
+		return nil, err
+	}
+	return append(disk, fsys...), nil
+}
+
+// listenUnix listens on a unix domain socket at path, which only the
+// current user may connect to. A stale socket at path is removed first.
+func listenUnix(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// requireToken returns a handler that serves requests with h only if they
+// carry the given token, either in the token query parameter or in a
+// cookie set when it was first presented.
+func requireToken(token string, h http.Handler) http.Handler {
+	valid := func(t string) bool {
+		return subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if valid(r.URL.Query().Get("token")) {
+			// Links within the pages do not carry the token.
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+		} else if c, err := r.Cookie(tokenCookie); err != nil || !valid(c.Value) {
+			http.Error(w, "missing or invalid token: use the URL printed by gotelemetry view", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
 }
 
 type page struct {
@@ -88,12 +186,137 @@ type page struct {
 
 	// Charts is the counter data from files and reports grouped by program and counter name.
 	Charts *chartdata
+
+	// Filter is the date range and program selected by the URL query.
+	Filter filter
+
+	// Pages links to the other pages of reports.
+	Pages pages
+}
+
+// reportsPerPage is the number of reports listed on each page.
+const reportsPerPage = 52
+
+// A filter selects the reports and counter files to display, from the
+// start, end, and program URL query parameters.
+type filter struct {
+	Start   string // first date (YYYY-MM-DD), or empty
+	End     string // last date (YYYY-MM-DD), or empty
+	Program string // program path, or empty
+
+	start, end time.Time // parsed Start and End; zero if empty
+}
+
+func parseFilter(q url.Values) (filter, error) {
+	f := filter{
+		Start:   q.Get("start"),
+		End:     q.Get("end"),
+		Program: q.Get("program"),
+	}
+	var err error
+	if f.Start != "" {
+		if f.start, err = parseReportDate(f.Start); err != nil {
+			return filter{}, fmt.Errorf("invalid start date %q", f.Start)
+		}
+	}
+	if f.End != "" {
+		if f.end, err = parseReportDate(f.End); err != nil {
+			return filter{}, fmt.Errorf("invalid end date %q", f.End)
+		}
+	}
+	return f, nil
+}
+
+// inRange reports whether the range of days from begin to end (inclusive)
+// overlaps the filter's date range.
+func (f filter) inRange(begin, end time.Time) bool {
+	return (f.start.IsZero() || !end.Before(f.start)) &&
+		(f.end.IsZero() || !begin.After(f.end))
+}
+
+// reports returns the reports whose week ends in the filter's date range,
+// keeping only the programs selected by the filter. Reports with no
+// selected programs are omitted.
+func (f filter) reports(reports []*telemetryReport) []*telemetryReport {
+	var result []*telemetryReport
+	for _, r := range reports {
+		if !f.inRange(r.WeekEnd, r.WeekEnd) {
+			continue
+		}
+		if f.Program != "" {
+			var progs []*telemetryProgram
+			for _, p := range r.Programs {
+				if p.Program == f.Program {
+					progs = append(progs, p)
+				}
+			}
+			if len(progs) == 0 {
+				continue
+			}
+			r2 := *r
+			r2.Programs = progs
+			r = &r2
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// files returns the counter files for the filter's program whose time span
+// overlaps its date range.
+func (f filter) files(files []*counterFile) []*counterFile {
+	var result []*counterFile
+	for _, c := range files {
+		if f.Program != "" && c.Meta["Program"] != f.Program {
+			continue
+		}
+		begin, err1 := time.Parse(time.RFC3339, c.Meta["TimeBegin"])
+		end, err2 := time.Parse(time.RFC3339, c.Meta["TimeEnd"])
+		if err1 == nil && err2 == nil && !f.inRange(begin, end) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// pages describes the page of reports being displayed.
+type pages struct {
+	Page, NumPages int    // 1-based
+	Prev, Next     string // URLs of the previous and next pages, if any
+}
+
+// paginate returns the reports on the page requested by the page URL query
+// parameter of u, and links to the neighboring pages.
+func paginate(reports []*telemetryReport, u *url.URL) ([]*telemetryReport, pages) {
+	numPages := max(1, (len(reports)+reportsPerPage-1)/reportsPerPage)
+	page, err := strconv.Atoi(u.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	page = min(page, numPages)
+	link := func(page int) string {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		return "?" + q.Encode() + "#reports"
+	}
+	p := pages{Page: page, NumPages: numPages}
+	if page > 1 {
+		p.Prev = link(page - 1)
+	}
+	if page < numPages {
+		p.Next = link(page + 1)
+	}
+	start := (page - 1) * reportsPerPage
+	return reports[start:min(start+reportsPerPage, len(reports))], p
 }
 
-// TODO: filtering and pagination for date ranges
 func (s *Server) handleIndex(fsys fs.FS) handlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		if r.URL.Path != "/" {
+			if _, err := fs.Stat(fsys, strings.TrimPrefix(path.Clean(r.URL.Path), "/")); errors.Is(err, fs.ErrNotExist) {
+				return errNotFound
+			}
 			http.FileServer(http.FS(fsys)).ServeHTTP(w, r)
 			return nil
 		}
@@ -106,7 +329,7 @@ func (s *Server) handleIndex(fsys fs.FS) handlerFunc {
 			log.Printf("Falling back to empty config: %v", err)
 			cfg, _ = s.configAt("empty")
 		}
-		cfgVersionList, err := configVersions()
+		cfgVersionList, err := s.configVersions()
 		if err != nil {
 			return err
 		}
@@ -120,6 +343,11 @@ func (s *Server) handleIndex(fsys fs.FS) handlerFunc {
 				`The telemetry dir %s does not exist.
 There is nothing to report.`, telemetry.Default.LocalDir())
 		}
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil
+		}
 		reports, err := reports(localDir, cfg)
 		if err != nil {
 			return err
@@ -128,10 +356,14 @@ There is nothing to report.`, telemetry.Default.LocalDir())
 		if err != nil {
 			return err
 		}
+		reports = filter.reports(reports)
+		files = filter.files(files)
+		// Chart all the selected data, but list only a page of reports.
 		charts, err := charts(append(reports, pending(files, cfg)...), cfg)
 		if err != nil {
 			return err
 		}
+		reports, pages := paginate(reports, r.URL)
 		data := page{
 			Config:          cfg,
 			PrettyConfig:    string(cfgJSON),
@@ -140,6 +372,8 @@ There is nothing to report.`, telemetry.Default.LocalDir())
 			Files:           files,
 			Charts:          charts,
 			RequestedConfig: requestedConfig,
+			Filter:          filter,
+			Pages:           pages,
 		}
 		return renderTemplate(w, fsys, "index.html", data, http.StatusOK)
 	}
@@ -165,13 +399,31 @@ func (s Server) configAt(version string) (ucfg *config.Config, err error) {
 	return ucfg, nil
 }
 
-// configVersions is the set of config versions the user may select from the UI.
-// TODO: get the list of versions available from the proxy.
-func configVersions() ([]string, error) {
+// configVersions is the set of config versions the user may select from the
+// UI: "latest", followed by the versions of the config module available from
+// the module proxy, newest first. If a config file was given with -config,
+// or the proxy cannot be reached, it is just "latest".
+func (s Server) configVersions() ([]string, error) {
+	if s.FsConfig != "" {
+		return []string{"latest"}, nil
+	}
+	versions, err := proxyConfigVersions()
+	if err != nil {
+		log.Printf("Failed to list config versions: %v", err)
+		return []string{"latest"}, nil
+	}
 	v := []string{"latest"}
+	for i := len(versions) - 1; i >= 0; i-- {
+		v = append(v, versions[i])
+	}
 	return v, nil
 }
 
+// proxyConfigVersions lists the config module versions from the proxy.
+var proxyConfigVersions = func() ([]string, error) {
+	return configstore.ListVersions(context.Background(), configstore.ListOptions{})
+}
+
 // reports reads the local report files from a directory.
 func reports(dir string, cfg *config.Config) ([]*telemetryReport, error) {
 	fsys := os.DirFS(dir)
@@ -315,12 +567,13 @@ func newCounterFile(name string, c *tcounter.File, cfg *config.Config) *counterF
 	}
 	var counts []*count
 	var stacks []*stack
+	meta := uploadable.MetaOf(c.Meta)
 	for k, v := range c.Count {
+		d, _ := uploadable.Decide(cfg, meta, k)
+		active := d == uploadable.Upload
 		if summary, details, ok := strings.Cut(k, "\n"); ok {
-			active := cfg.HasStack(c.Meta["Program"], k)
 			stacks = append(stacks, &stack{summary, details, v, active})
 		} else {
-			active := cfg.HasCounter(c.Meta["Program"], k)
 			counts = append(counts, &count{k, v, active})
 		}
 	}
@@ -345,42 +598,35 @@ func newCounterFile(name string, c *tcounter.File, cfg *config.Config) *counterF
 // in the event of a telemetry upload event.
 func summary(cfg *config.Config, meta map[string]string, counts map[string]uint64) template.HTML {
 	msg := " is unregistered. No data from this set would be uploaded to the Go team."
-	if prog := meta["Program"]; !(cfg.HasProgram(prog)) {
+	m := uploadable.MetaOf(meta)
+	var result strings.Builder
+	switch _, reason := uploadable.DecideProgram(cfg, m); reason {
+	case uploadable.UnknownProgram:
 		return template.HTML(fmt.Sprintf(
 			"The program <code>%s</code>"+msg,
-			html.EscapeString(prog),
+			html.EscapeString(m.Program),
 		))
-	}
-	var result strings.Builder
-	if !(cfg.HasGOOS(meta["GOOS"])) || !(cfg.HasGOARCH(meta["GOARCH"])) {
+	case uploadable.UnknownGOOS, uploadable.UnknownGOARCH:
 		return template.HTML(fmt.Sprintf(
 			"The GOOS/GOARCH combination <code>%s/%s</code> "+msg,
-			html.EscapeString(meta["GOOS"]),
-			html.EscapeString(meta["GOARCH"]),
+			html.EscapeString(m.GOOS),
+			html.EscapeString(m.GOARCH),
 		))
-	}
-	goVersion := meta["GoVersion"]
-	if !(cfg.HasGoVersion(goVersion)) {
+	case uploadable.UnknownGoVersion:
 		return template.HTML(fmt.Sprintf(
 			"The go version <code>%s</code> "+msg,
-			html.EscapeString(goVersion),
+			html.EscapeString(m.GoVersion),
 		))
-	}
-	version := meta["Version"]
-	if !(cfg.HasVersion(meta["Program"], version)) {
+	case uploadable.UnknownVersion:
 		return template.HTML(fmt.Sprintf(
 			"The version <code>%s</code> "+msg,
-			html.EscapeString(version),
+			html.EscapeString(m.Version),
 		))
 	}
 	var counters []string
 	for c := range counts {
-		summary, _, ok := strings.Cut(c, "\n")
-		if ok && !cfg.HasStack(meta["Program"], c) {
-			counters = append(counters, fmt.Sprintf("<code>%s</code>", html.EscapeString(summary)))
-		}
-		if !ok && !(cfg.HasCounter(meta["Program"], c)) {
-			counters = append(counters, fmt.Sprintf("<code>%s</code>", html.EscapeString(c)))
+		if d, _ := uploadable.Decide(cfg, m, c); d == uploadable.Drop {
+			counters = append(counters, fmt.Sprintf("<code>%s</code>", html.EscapeString(uploadable.ConfigName(c))))
 		}
 	}
 	if len(counters) > 0 {
@@ -443,6 +689,9 @@ func parseReportDate(s string) (time.Time, error) {
 // charts returns chartdata for a set of telemetry reports. It uses the config
 // to determine if the programs and counters are active.
 func charts(reports []*telemetryReport, cfg *config.Config) (*chartdata, error) {
+	if len(reports) == 0 {
+		return &chartdata{}, nil // e.g., everything was filtered out
+	}
 	data := grouped(reports)
 	// domain is a [min, max] array used in d3.js where min is the minimum
 	// observable time and max is the maximum observable time; both values
@@ -622,12 +871,60 @@ func (f handlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// errNotFound is the error of handlers for missing pages.
+var errNotFound = errors.New(http.StatusText(http.StatusNotFound))
+
+// errorPage is the data of the error templates, 404.html and 500.html.
+type errorPage struct {
+	Code    int
+	Status  string
+	Message string
+}
+
+// errorPages returns a handler that serves requests with h, and renders its
+// errors with the error templates of fsys, or as text if they fail.
+func errorPages(fsys fs.FS, h handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil {
+			return
+		}
+		page := errorPage{Code: http.StatusInternalServerError, Message: err.Error()}
+		if errors.Is(err, errNotFound) {
+			page.Code, page.Message = http.StatusNotFound, ""
+		}
+		page.Status = http.StatusText(page.Code)
+		if rerr := renderTemplate(w, fsys, fmt.Sprintf("%d.html", page.Code), page, page.Code); rerr != nil {
+			log.Printf("rendering the error page of %s: %v", r.URL.Path, rerr)
+			http.Error(w, err.Error(), page.Code)
+		}
+	})
+}
+
 // renderTemplate executes a template response.
 func renderTemplate(w http.ResponseWriter, fsys fs.FS, tmplPath string, data any, code int) error {
-	patterns, err := tmplPatterns(fsys, tmplPath)
+	buf, err := executeTemplate(fsys, tmplPath, data)
 	if err != nil {
 		return err
 	}
+	if code != 0 {
+		w.WriteHeader(code)
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// executeTemplate executes the template at tmplPath, with the templates in
+// the *.tmpl files of fsys.
+func executeTemplate(fsys fs.FS, tmplPath string, data any) (*bytes.Buffer, error) {
+	patterns, err := tmplPatterns(fsys, tmplPath)
+	if err != nil {
+		return nil, err
+	}
 	patterns = append(patterns, tmplPath)
 	funcs := template.FuncMap{
 		"chartName": func(name string) string {
@@ -639,25 +936,28 @@ func renderTemplate(w http.ResponseWriter, fsys fs.FS, tmplPath string, data any
 			name = strings.TrimPrefix(name, "github.com/")
 			return name
 		},
+		"integrity": func(urlPath string) (string, error) {
+			return contentfs.Integrity(fsys, urlPath)
+		},
+		"inlineCSS": func(urlPath string) (template.CSS, error) {
+			data, err := inline(fsys, urlPath)
+			return template.CSS(data), err
+		},
+		"inlineJS": func(urlPath string) (template.JS, error) {
+			data, err := inline(fsys, urlPath)
+			return template.JS(data), err
+		},
 	}
 	tmpl, err := template.New("").Funcs(funcs).ParseFS(fsys, patterns...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	name := path.Base(tmplPath)
 	var buf bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
-		return err
-	}
-	if code != 0 {
-		w.WriteHeader(code)
-	}
-	w.Header().Set("Content-Type", "text/html")
-	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
-	if _, err := w.Write(buf.Bytes()); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return &buf, nil
 }
 
 // tmplPatterns generates a slice of file patterns to use in template.ParseFS.
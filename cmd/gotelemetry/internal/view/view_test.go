@@ -9,11 +9,16 @@ package view
 import (
 	"fmt"
 	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"golang.org/x/telemetry/internal/config"
+	tcounter "golang.org/x/telemetry/internal/counter"
 	"golang.org/x/telemetry/internal/telemetry"
 )
 
@@ -154,3 +159,217 @@ func Test_reportsDomain(t *testing.T) {
 		})
 	}
 }
+
+func Test_filter(t *testing.T) {
+	report := func(week string, programs ...string) *telemetryReport {
+		weekEnd, err := parseReportDate(week)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &telemetryReport{Report: &telemetry.Report{Week: week}, WeekEnd: weekEnd}
+		for _, p := range programs {
+			r.Programs = append(r.Programs, &telemetryProgram{ProgramReport: &telemetry.ProgramReport{Program: p}})
+		}
+		return r
+	}
+	reports := []*telemetryReport{
+		report("2024-01-22", "gopls", "cmd/go"),
+		report("2024-01-15", "cmd/go"),
+		report("2024-01-08", "gopls"),
+	}
+	file := func(program, begin, end string) *counterFile {
+		meta := map[string]string{"Program": program, "TimeBegin": begin, "TimeEnd": end}
+		return &counterFile{File: &tcounter.File{Meta: meta}}
+	}
+	files := []*counterFile{
+		file("gopls", "2024-01-09T00:00:00Z", "2024-01-16T00:00:00Z"),
+		file("cmd/go", "2024-01-23T00:00:00Z", "2024-01-30T00:00:00Z"),
+	}
+
+	tests := []struct {
+		query       string
+		wantReports []string // week:programs
+		wantFiles   []string // programs
+		wantErr     bool
+	}{
+		{"", []string{"2024-01-22:gopls,cmd/go", "2024-01-15:cmd/go", "2024-01-08:gopls"}, []string{"gopls", "cmd/go"}, false},
+		{"start=2024-01-15", []string{"2024-01-22:gopls,cmd/go", "2024-01-15:cmd/go"}, []string{"gopls", "cmd/go"}, false},
+		{"end=2024-01-15", []string{"2024-01-15:cmd/go", "2024-01-08:gopls"}, []string{"gopls"}, false},
+		{"start=2024-01-10&end=2024-01-20", []string{"2024-01-15:cmd/go"}, []string{"gopls"}, false},
+		{"program=gopls", []string{"2024-01-22:gopls", "2024-01-08:gopls"}, []string{"gopls"}, false},
+		{"program=cmd/go&start=2024-01-20", []string{"2024-01-22:cmd/go"}, []string{"cmd/go"}, false},
+		{"start=yesterday", nil, nil, true},
+	}
+	for _, test := range tests {
+		q, err := url.ParseQuery(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := parseFilter(q)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("parseFilter(%q) failed: %v", test.query, err)
+			}
+			continue
+		} else if test.wantErr {
+			t.Errorf("parseFilter(%q) succeeded unexpectedly", test.query)
+			continue
+		}
+		var gotReports []string
+		for _, r := range f.reports(reports) {
+			var progs []string
+			for _, p := range r.Programs {
+				progs = append(progs, p.Program)
+			}
+			gotReports = append(gotReports, r.Week+":"+strings.Join(progs, ","))
+		}
+		if !reflect.DeepEqual(gotReports, test.wantReports) {
+			t.Errorf("%q: reports = %q, want %q", test.query, gotReports, test.wantReports)
+		}
+		var gotFiles []string
+		for _, c := range f.files(files) {
+			gotFiles = append(gotFiles, c.Meta["Program"])
+		}
+		if !reflect.DeepEqual(gotFiles, test.wantFiles) {
+			t.Errorf("%q: files = %q, want %q", test.query, gotFiles, test.wantFiles)
+		}
+	}
+	// Filtering does not modify the original reports.
+	if got := len(reports[0].Programs); got != 2 {
+		t.Errorf("after filtering, first report has %d programs, want 2", got)
+	}
+}
+
+func Test_paginate(t *testing.T) {
+	reports := make([]*telemetryReport, 2*reportsPerPage+1)
+	for i := range reports {
+		reports[i] = &telemetryReport{ID: fmt.Sprint(i)}
+	}
+	tests := []struct {
+		query     string
+		wantPage  int
+		wantFirst string
+		wantLen   int
+		wantPrev  bool
+		wantNext  bool
+	}{
+		{"", 1, "0", reportsPerPage, false, true},
+		{"page=2&program=gopls", 2, fmt.Sprint(reportsPerPage), reportsPerPage, true, true},
+		{"page=3", 3, fmt.Sprint(2 * reportsPerPage), 1, true, false},
+		{"page=99", 3, fmt.Sprint(2 * reportsPerPage), 1, true, false},
+		{"page=x", 1, "0", reportsPerPage, false, true},
+	}
+	for _, test := range tests {
+		u, err := url.Parse("/?" + test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, p := paginate(reports, u)
+		if p.Page != test.wantPage || p.NumPages != 3 || len(got) != test.wantLen || got[0].ID != test.wantFirst ||
+			(p.Prev != "") != test.wantPrev || (p.Next != "") != test.wantNext {
+			t.Errorf("paginate(%q) = %d reports from %s, %+v; want %d from %s on page %d of 3",
+				test.query, len(got), got[0].ID, p, test.wantLen, test.wantFirst, test.wantPage)
+		}
+		if p.Next != "" && strings.Contains(test.query, "program=gopls") && !strings.Contains(p.Next, "program=gopls") {
+			t.Errorf("paginate(%q): next page link %q does not preserve the query", test.query, p.Next)
+		}
+	}
+	if got, p := paginate(nil, &url.URL{}); len(got) != 0 || p.NumPages != 1 {
+		t.Errorf("paginate(nil) = %d reports, %+v; want none on 1 page", len(got), p)
+	}
+}
+
+func Test_requireToken(t *testing.T) {
+	h := requireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	get := func(target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, target := range []string{"/", "/?token=wrong", "/stacks?token="} {
+		if w := get(target); w.Code != http.StatusForbidden {
+			t.Errorf("GET %s: got status %d, want %d", target, w.Code, http.StatusForbidden)
+		}
+	}
+	if w := get("/", &http.Cookie{Name: tokenCookie, Value: "wrong"}); w.Code != http.StatusForbidden {
+		t.Errorf("GET / with wrong cookie: got status %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	w := get("/?token=secret")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /?token=secret: got status %d, want %d", w.Code, http.StatusOK)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != tokenCookie {
+		t.Fatalf("GET /?token=secret: got cookies %v, want %s", cookies, tokenCookie)
+	}
+	// Later requests, such as links to other pages, carry only the cookie.
+	if w := get("/stacks", cookies[0]); w.Code != http.StatusOK {
+		t.Errorf("GET /stacks with cookie: got status %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func Test_configVersions(t *testing.T) {
+	defer func(f func() ([]string, error)) { proxyConfigVersions = f }(proxyConfigVersions)
+	proxyConfigVersions = func() ([]string, error) {
+		return []string{"v0.1.0", "v0.2.0"}, nil
+	}
+	got, err := Server{}.configVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"latest", "v0.2.0", "v0.1.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("configVersions() = %v, want %v", got, want)
+	}
+
+	// A config file takes the place of all versions.
+	got, _ = Server{FsConfig: "config.json"}.configVersions()
+	if want := []string{"latest"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("configVersions() with -config = %v, want %v", got, want)
+	}
+
+	// Failure to reach the proxy is not fatal.
+	proxyConfigVersions = func() ([]string, error) {
+		return nil, fmt.Errorf("offline")
+	}
+	got, err = Server{}.configVersions()
+	if want := []string{"latest"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("configVersions() when offline = %v, %v; want %v, nil", got, err, want)
+	}
+}
+
+func Test_errorPages(t *testing.T) {
+	var s Server
+	fsys, err := s.content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		h         handlerFunc
+		target    string
+		code      int
+		fragments []string
+	}{
+		{s.handleIndex(fsys), "/nosuchpage", http.StatusNotFound, []string{"<h1>404 Not Found</h1>", "There is nothing at this address."}},
+		{s.handleIndex(fsys), "/static/favicon.ico", http.StatusOK, nil},
+		{func(http.ResponseWriter, *http.Request) error { return fmt.Errorf("broken counter file") }, "/", http.StatusInternalServerError, []string{"<h1>500 Internal Server Error</h1>", "broken counter file"}},
+	} {
+		w := httptest.NewRecorder()
+		errorPages(fsys, test.h).ServeHTTP(w, httptest.NewRequest("GET", test.target, nil))
+		if w.Code != test.code {
+			t.Errorf("GET %s: got status %d, want %d", test.target, w.Code, test.code)
+		}
+		for _, f := range test.fragments {
+			if !strings.Contains(w.Body.String(), f) {
+				t.Errorf("GET %s: body does not contain %q:\n%s", test.target, f, w.Body)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package watch reports the changes to the counters in the count files of a
+// telemetry directory, for the "gotelemetry watch" command.
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/telemetry/internal/counter"
+)
+
+// A Change is an increment of a counter in a count file.
+type Change struct {
+	File    string // base name of the count file
+	Program string // program path and version, as program@version
+	Counter string
+	Delta   uint64 // the increment since the previous poll
+	Count   uint64 // the new value of the counter
+}
+
+// A Watcher polls the count files in a directory for changes.
+type Watcher struct {
+	dir  string
+	keep func(name string) bool
+	last map[string]map[string]uint64 // file -> counter -> count; nil before the first poll
+}
+
+// NewWatcher returns a Watcher for the count files in dir, reporting the
+// counters for which keep returns true.
+func NewWatcher(dir string, keep func(name string) bool) *Watcher {
+	return &Watcher{dir: dir, keep: keep}
+}
+
+// Poll reads the count files and returns the counters that were incremented
+// since the previous call, sorted by file and counter. The first call
+// records the current counts, and reports no changes; counters in files
+// created after it are reported as incremented from zero.
+//
+// Files that cannot be read or parsed, for example because a program is
+// extending them, are skipped until the next call.
+func (w *Watcher) Poll() ([]Change, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	first := w.last == nil
+	if first {
+		w.last = make(map[string]map[string]uint64)
+	}
+	var changes []Change
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".count") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(w.dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		f, err := counter.Parse(e.Name(), data)
+		if err != nil {
+			continue
+		}
+		last := w.last[e.Name()]
+		if last == nil {
+			last = make(map[string]uint64)
+			w.last[e.Name()] = last
+		}
+		for name, n := range f.Count {
+			if !w.keep(name) {
+				continue
+			}
+			if !first && n > last[name] {
+				changes = append(changes, Change{
+					File:    e.Name(),
+					Program: f.Meta["Program"] + "@" + f.Meta["Version"],
+					Counter: name,
+					Delta:   n - last[name],
+					Count:   n,
+				})
+			}
+			last[name] = n
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].File != changes[j].File {
+			return changes[i].File < changes[j].File
+		}
+		return changes[i].Counter < changes[j].Counter
+	})
+	return changes, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package watch_test
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/telemetry/cmd/gotelemetry/internal/watch"
+	"golang.org/x/telemetry/internal/regtest"
+	"golang.org/x/telemetry/internal/telemetry"
+	"golang.org/x/telemetry/internal/testenv"
+)
+
+func TestPoll(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewIncProgram(t, "prog", "watched/a", "watched/b", "other")
+	telemetryDir := t.TempDir()
+	run := func() {
+		t.Helper()
+		if out, err := regtest.RunProg(t, telemetryDir, prog); err != nil {
+			t.Fatalf("failed to run program: %s", out)
+		}
+	}
+	w := watch.NewWatcher(telemetry.NewDir(telemetryDir).LocalDir(), func(name string) bool {
+		return strings.HasPrefix(name, "watched/")
+	})
+
+	// Counts from before the first poll are not reported.
+	run()
+	if changes, err := w.Poll(); err != nil || len(changes) != 0 {
+		t.Fatalf("first Poll() = %v, %v; want no changes", changes, err)
+	}
+	if changes, err := w.Poll(); err != nil || len(changes) != 0 {
+		t.Fatalf("Poll() without increments = %v, %v; want no changes", changes, err)
+	}
+
+	run()
+	run()
+	changes, err := w.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range changes {
+		if c.Delta != 2 || c.Count != 3 {
+			t.Errorf("Poll() change %+v, want delta 2, count 3", c)
+		}
+		got = append(got, c.Counter)
+	}
+	if want := "watched/a watched/b"; strings.Join(got, " ") != want {
+		t.Errorf("Poll() changed counters %q, want %q", got, want)
+	}
+}
@@ -12,11 +12,19 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"golang.org/x/telemetry/cmd/gotelemetry/internal/crashes"
 	"golang.org/x/telemetry/cmd/gotelemetry/internal/csv"
+	"golang.org/x/telemetry/cmd/gotelemetry/internal/dump"
+	"golang.org/x/telemetry/cmd/gotelemetry/internal/glob"
 	"golang.org/x/telemetry/cmd/gotelemetry/internal/view"
+	"golang.org/x/telemetry/cmd/gotelemetry/internal/watch"
+	"golang.org/x/telemetry/internal/configstore"
 	"golang.org/x/telemetry/internal/counter"
 	"golang.org/x/telemetry/internal/telemetry"
 	"golang.org/x/telemetry/internal/upload"
@@ -39,6 +47,33 @@ func (c command) name() string {
 var (
 	viewFlags      = flag.NewFlagSet("view", flag.ExitOnError)
 	viewServer     view.Server
+	viewExport     string
+	simulateFlags  = flag.NewFlagSet("simulate-upload", flag.ExitOnError)
+	simulateWeek   string
+	simulateConfig string
+	exportFlags    = flag.NewFlagSet("export-upload", flag.ExitOnError)
+	exportConfig   string
+	envFlags       = flag.NewFlagSet("env", flag.ExitOnError)
+	envJSON        bool
+	envWrite       bool
+	reportFlags    = flag.NewFlagSet("report", flag.ExitOnError)
+	reportWeek     string
+	reportConfig   string
+	dumpFlags      = flag.NewFlagSet("dump", flag.ExitOnError)
+	dumpFormat     string
+	dumpMerge      bool
+	dumpCounter    string
+	crashesFlags   = flag.NewFlagSet("crashes", flag.ExitOnError)
+	crashesIssue   bool
+	watchFlags     = flag.NewFlagSet("watch", flag.ExitOnError)
+	watchInterval  time.Duration
+	uploadFlags    = flag.NewFlagSet("upload", flag.ExitOnError)
+	uploadDryRun   bool
+	cleanFlags     = flag.NewFlagSet("clean", flag.ExitOnError)
+	cleanCounters  bool
+	cleanReports   bool
+	cleanAll       bool
+	cleanDryRun    bool
 	normalCommands = []*command{
 		{
 			usage: "on",
@@ -82,43 +117,155 @@ To enable both collection and uploading, run “gotelemetry on”.`,
 			short: "run a web viewer for local telemetry data",
 			long: `Gotelemetry view runs a web viewer for local telemetry data.
 
-This viewer displays charts for locally collected data, as well as information about the current upload configuration.`,
+This viewer displays charts for locally collected data, as well as information about the current upload configuration.
+
+By default, the viewer listens on a localhost TCP port, and requires a random token that is included in the URL it prints (and opens, with -open). This keeps other users of a shared machine from viewing your data. On systems that support them, -unix serves on a unix domain socket that only you can access instead, for use with a proxy or a client such as “curl --unix-socket”.
+
+The charts can be saved as a single, self-contained HTML file, for example to attach to an issue, using the export link of the viewer, or with the -export flag, which writes the charts of all local data to the given file without starting a server.`,
 			flags: viewFlags,
 			run:   runView,
 		},
 		{
-			usage: "env",
-			short: "print the current telemetry environment",
-			run:   runEnv,
+			usage: "env [-json] [-w KEY=VALUE...]",
+			short: "print or change the current telemetry environment",
+			long: `Gotelemetry env prints the current telemetry mode and the location of the telemetry directory.
+
+The telemetry directory is os.UserConfigDir()/go/telemetry, unless the GOTELEMETRYDIR environment variable is set to an absolute path, in which case that path is used instead. Go programs that use telemetry, including gotelemetry itself, honor GOTELEMETRYDIR.
+
+Gotelemetry env also lists any count files that could not be parsed when preparing reports for upload. Such files are moved to the quarantine directory, where they are kept until removed by “gotelemetry clean”.
+
+The -json flag prints the environment in JSON format, for inclusion in bug reports.
+
+The -w flag sets the given keys to the given values. The only key that may be set is GOTELEMETRY, the telemetry mode (on, local, or off); “gotelemetry env -w GOTELEMETRY=local” is equivalent to “gotelemetry local”. GOTELEMETRYDIR is read from the environment of each program, and cannot be set by gotelemetry.`,
+			flags:   envFlags,
+			hasArgs: true,
+			run:     runEnv,
+		},
+		{
+			usage: "status",
+			short: "summarize the state of telemetry collection and uploading",
+			long: `Gotelemetry status prints the telemetry mode and the date it was set, the number of count files still collecting data, the number of count files and reports waiting for the next upload, the count files that were quarantined because they could not be read and why, the time of the last successful upload, and when the next report is expected.
+
+Count files are turned into reports, and reports are uploaded, by programs that use telemetry, once the week covered by a count file has ended. The next report can therefore be expected soon after the given time, the next time such a program runs.
+
+Gotelemetry status does not change anything or use the network.`,
+			run: runStatus,
+		},
+		{
+			usage: "report [flags]",
+			short: "print the exact report that would be uploaded",
+			long: `Gotelemetry report prints the report that the uploader would create for the week ending on the date given by -week, in exactly the form it would be uploaded. Without -week, it prints the report for the earliest week of the local counter files, which is the next to be uploaded.
+
+The report is built from the local counter files and filtered by the upload config, as by a real upload, except that sampling is disabled: the report's X is 0, so it includes every counter the config permits, whatever its sampling rate. Real reports contain a subset of these counters.
+
+As with simulate-upload, the upload config is read from the Go module cache or a file, and nothing in the telemetry directory is changed. The report is printed even if it would not be uploaded, for example because uploading is disabled.`,
+			flags: reportFlags,
+			run:   runReport,
+		},
+		{
+			usage: "upload [flags]",
+			short: "upload reports that are ready now",
+			long: `Gotelemetry upload prepares reports from count files whose week has ended, and uploads the reports that are ready, logging its progress to standard error. It does the same work as the uploads that programs using telemetry run periodically, so it can be used to flush pending reports (for example, before decommissioning a machine) or to debug upload failures.
+
+Reports are uploaded only when telemetry uploading is enabled (see “gotelemetry on”), and count files are only turned into reports once their week has ended.
+
+With -n, gotelemetry upload logs the count files it would turn into reports and the reports it would upload, without changing anything.`,
+			flags: uploadFlags,
+			run:   runUpload,
 		},
 		{
-			usage: "clean",
+			usage: "clean [flags]",
 			short: "remove all local telemetry data",
-			long: `Gotelemetry clean removes locally collected counters and reports.
+			long: `Gotelemetry clean removes locally collected counters and reports, printing the name of each file it removes.
+
+With -counters, only count files are removed. With -reports, only reports are removed: local reports, reports ready for upload, and copies of uploaded reports. With neither flag, both are removed. With -all, crash files and debug logs are removed too.
 
 Removing counter files that are currently in use may fail on some operating
 systems.
 
 Gotelemetry clean does not affect the current telemetry mode.`,
-			run: runClean,
+			flags: cleanFlags,
+			run:   runClean,
 		},
 	}
 	experimentalCommands = []*command{
 		{
-			usage: "csv",
+			usage: "csv [patterns]",
 			short: "print all known counters",
-			run:   runCSV,
+			long: `Gotelemetry csv prints the counters in the local count files and reports, one per line, as comma-separated values.
+
+If patterns are given, only the counters matching at least one of them are printed. In a pattern, '*' matches any sequence of characters other than '/', "**" matches any sequence of characters, and '?' matches any single character other than '/'. For example, 'gopls/*' matches gopls/client:vscode, and 'gopls/**' also matches gopls/completion/latency:<10ms. Stack counters are matched by their name, not their stack.`,
+			run:     runCSV,
+			hasArgs: true,
 		},
 		{
-			usage:   "dump [files]",
-			short:   "view counter file data",
+			usage: "dump [flags] [files]",
+			short: "view counter file data",
+			long: `Gotelemetry dump prints the contents of the given count files, or of all count files in the local telemetry directory if none are given.
+
+By default, the metadata and counters of each file are printed as a separate JSON document. With -format, the counters of all files are printed as a single document with one row per counter and file, including the file's metadata: a JSON array with -format=json, comma-separated values with a header line with -format=csv, or aligned columns with -format=table.
+
+With -merge, the counts for the same counter and program build (program, version, Go version, GOOS, and GOARCH) are summed over all files, and the file name and time columns are omitted. -merge implies -format=json unless another format is given.
+
+With -counter, only the counters matching the given pattern are printed, using the same pattern syntax as “gotelemetry csv”.`,
+			flags:   dumpFlags,
 			run:     runDump,
 			hasArgs: true,
 		},
 		{
-			usage: "upload",
-			short: "run upload with logging enabled",
-			run:   runUpload,
+			usage: "watch [flags] [patterns]",
+			short: "print counter increments as they happen",
+			long: `Gotelemetry watch polls the count files in the local telemetry directory, and prints each increment of a counter, with the program that recorded it and the counter's new value, until interrupted. This is useful for checking that a newly instrumented counter is incremented as expected.
+
+Counts recorded before the command starts are not printed. If patterns are given, only the counters matching at least one of them are printed, using the same pattern syntax as “gotelemetry csv”.
+
+Counters are only recorded while telemetry is enabled in local or on mode (see “gotelemetry local”).`,
+			flags:   watchFlags,
+			run:     runWatch,
+			hasArgs: true,
+		},
+		{
+			usage: "crashes [flags]",
+			short: "list the crash stacks in the local data",
+			long: `Gotelemetry crashes lists the distinct crash stacks recorded by the crash monitor in the local count files and reports, most frequent first. Each stack is printed with the number of times it was recorded, the program versions that recorded it, and the weeks in which they did, followed by its frames.
+
+With -open-issue, each crash is instead printed as a draft bug report, with a title line and a Markdown body, for filing in the issue tracker of the program that crashed.`,
+			flags: crashesFlags,
+			run:   runCrashes,
+		},
+		{
+			usage: "export-upload [flags] <file>",
+			short: "export reports for upload from another machine",
+			long: `Gotelemetry export-upload writes the reports that are ready for upload to a signed archive, for machines that cannot reach https://telemetry.go.dev/. Reports are first created from the count files that have expired, as an upload would.
+
+The upload config is read from the Go module cache, or from a file if the -config flag names one, so the network is not used.
+
+Copy the archive to a connected machine, and upload it with “gotelemetry import-upload”. Exported reports are not uploaded or exported again from this machine.
+
+Reports are only prepared for upload when telemetry uploading is enabled (see “gotelemetry on”).`,
+			flags:   exportFlags,
+			run:     runExportUpload,
+			hasArgs: true,
+		},
+		{
+			usage: "import-upload <file>",
+			short: "upload reports exported from another machine",
+			long: `Gotelemetry import-upload uploads the reports in an archive created by “gotelemetry export-upload”.
+
+The archive is rejected if its signature or the checksum of any report does not match. The key that signed the archive is printed, so that archives from the same machine can be recognized.`,
+			run:     runImportUpload,
+			hasArgs: true,
+		},
+		{
+			usage: "simulate-upload [flags]",
+			short: "print the report that would be uploaded for a week",
+			long: `Gotelemetry simulate-upload prints the report that would be uploaded for the week ending on the given date, computed from the local counter files, followed by the counters that would be left out of it and why.
+
+The upload config is read from the Go module cache, or from a file if the -config flag names one, so the network is not used. Nothing in the telemetry directory is changed.
+
+As with a real upload, each report has a random sampling value X, so counters with a sampling rate below 1 may be included in some simulations but not others.`,
+			flags: simulateFlags,
+			run:   runSimulateUpload,
 		},
 	}
 )
@@ -128,6 +275,26 @@ func init() {
 	viewFlags.BoolVar(&viewServer.Dev, "dev", false, "rebuild static assets on save")
 	viewFlags.StringVar(&viewServer.FsConfig, "config", "", "load a config from the filesystem")
 	viewFlags.BoolVar(&viewServer.Open, "open", true, "open the browser to the server address")
+	viewFlags.BoolVar(&viewServer.Token, "token", true, "require a random access token, included in the server URL")
+	viewFlags.StringVar(&viewServer.Socket, "unix", "", "serve on the unix domain socket at this path, instead of -addr")
+	viewFlags.StringVar(&viewExport, "export", "", "write the charts to this standalone HTML file, instead of serving")
+	simulateFlags.StringVar(&simulateWeek, "week", "", "end date of the week to simulate, as YYYY-MM-DD")
+	simulateFlags.StringVar(&simulateConfig, "config", "latest", "version of the upload config in the module cache, or a config.json file")
+	exportFlags.StringVar(&exportConfig, "config", "latest", "version of the upload config in the module cache, or a config.json file")
+	envFlags.BoolVar(&envJSON, "json", false, "print the environment in JSON format")
+	envFlags.BoolVar(&envWrite, "w", false, "set the given KEY=VALUE pairs")
+	reportFlags.StringVar(&reportWeek, "week", "", "end date of the week to report, as YYYY-MM-DD (default: the earliest week)")
+	reportFlags.StringVar(&reportConfig, "config", "latest", "version of the upload config in the module cache, or a config.json file")
+	dumpFlags.StringVar(&dumpFormat, "format", "", "output format: "+strings.Join(dump.Formats, ", "))
+	dumpFlags.BoolVar(&dumpMerge, "merge", false, "sum counts over files from the same program build")
+	dumpFlags.StringVar(&dumpCounter, "counter", "", "print only counters matching this pattern, such as 'crash/*'")
+	crashesFlags.BoolVar(&crashesIssue, "open-issue", false, "print each crash as a draft bug report")
+	watchFlags.DurationVar(&watchInterval, "interval", time.Second, "how often to poll the count files")
+	uploadFlags.BoolVar(&uploadDryRun, "n", false, "print the work that would be done, without doing it")
+	cleanFlags.BoolVar(&cleanCounters, "counters", false, "remove count files")
+	cleanFlags.BoolVar(&cleanReports, "reports", false, "remove local, unuploaded, and uploaded reports")
+	cleanFlags.BoolVar(&cleanAll, "all", false, "remove count files, reports, crash files, and debug logs")
+	cleanFlags.BoolVar(&cleanDryRun, "dry-run", false, "print the files that would be removed, without removing them")
 
 	for _, cmd := range append(normalCommands, experimentalCommands...) {
 		name := cmd.name()
@@ -259,27 +426,188 @@ func runOff(_ []string) {
 }
 
 func runView(_ []string) {
+	if viewExport != "" {
+		if err := viewServer.Export(viewExport); err != nil {
+			failf("Export failed: %v\n", err)
+		}
+		fmt.Printf("Wrote charts to %s\n", viewExport)
+		return
+	}
 	viewServer.Serve()
 }
 
-func runEnv(_ []string) {
+// An env is the telemetry environment printed by "gotelemetry env -json".
+type env struct {
+	Mode           string
+	ModeAsOf       string `json:",omitempty"`
+	GOTELEMETRYDIR string // the environment variable, if set
+	Dir            string
+	ModeFile       string
+	LocalDir       string
+	UploadDir      string
+	QuarantineDir  string
+	Quarantined    []upload.QuarantinedFile
+}
+
+func runEnv(args []string) {
+	if envWrite {
+		if len(args) == 0 {
+			failf("usage: gotelemetry env -w KEY=VALUE...\n")
+		}
+		for _, arg := range args {
+			writeEnv(arg)
+		}
+		return
+	}
+	if len(args) > 0 {
+		failf("gotelemetry env does not accept arguments without -w\n")
+	}
 	m, t := telemetry.Default.Mode()
+	if envJSON {
+		quarantined, err := upload.Quarantined(telemetry.Default)
+		if err != nil {
+			failf("Failed to read quarantine dir: %v\n", err)
+		}
+		e := env{
+			Mode:           m,
+			GOTELEMETRYDIR: os.Getenv(telemetry.DirEnv),
+			Dir:            telemetry.Default.Dir(),
+			ModeFile:       telemetry.Default.ModeFile(),
+			LocalDir:       telemetry.Default.LocalDir(),
+			UploadDir:      telemetry.Default.UploadDir(),
+			QuarantineDir:  telemetry.Default.QuarantineDir(),
+			Quarantined:    quarantined,
+		}
+		if !t.IsZero() {
+			e.ModeAsOf = t.Format(telemetry.DateOnly)
+		}
+		js, err := json.MarshalIndent(e, "", "\t")
+		if err != nil {
+			failf("Failed to print environment: %v\n", err)
+		}
+		fmt.Printf("%s\n", js)
+		return
+	}
 	fmt.Printf("mode: %s %s\n", m, t)
 	fmt.Println()
+	fmt.Printf("%s=%s\n", telemetry.DirEnv, os.Getenv(telemetry.DirEnv))
 	fmt.Println("modefile:", telemetry.Default.ModeFile())
 	fmt.Println("localdir:", telemetry.Default.LocalDir())
 	fmt.Println("uploaddir:", telemetry.Default.UploadDir())
+	quarantined, err := upload.Quarantined(telemetry.Default)
+	if err != nil {
+		warnf("failed to read quarantine dir: %v", err)
+	}
+	if len(quarantined) > 0 {
+		fmt.Println()
+		fmt.Println("quarantinedir:", telemetry.Default.QuarantineDir())
+		for _, q := range quarantined {
+			fmt.Printf("quarantined: %s (%d failed attempts): %s\n", q.Name, q.Attempts, q.Err)
+		}
+	}
+}
+
+// writeEnv sets a key of the telemetry environment, given as KEY=VALUE.
+func writeEnv(arg string) {
+	key, value, ok := strings.Cut(arg, "=")
+	if !ok {
+		failf("gotelemetry env -w: argument %q must have the form KEY=VALUE\n", arg)
+	}
+	switch key {
+	case "GOTELEMETRY":
+		switch value {
+		case "on":
+			runOn(nil)
+		case "local":
+			runLocal(nil)
+		case "off":
+			runOff(nil)
+		default:
+			failf("gotelemetry env -w: invalid GOTELEMETRY value %q (want on, local, or off)\n", value)
+		}
+	case telemetry.DirEnv:
+		failf("gotelemetry env -w: %s cannot be set by gotelemetry; set it in the environment instead\n", key)
+	default:
+		failf("gotelemetry env -w: unknown key %s\n", key)
+	}
+}
+
+func runStatus(_ []string) {
+	s, err := upload.ReadStatus(telemetry.Default, time.Now())
+	if err != nil {
+		failf("Failed to read status: %v\n", err)
+	}
+	mode := s.Mode
+	if !s.ModeAsOf.IsZero() {
+		mode += " (since " + s.ModeAsOf.Format(telemetry.DateOnly) + ")"
+	}
+	fmt.Println("mode:", mode)
+	fmt.Println("active count files:", s.ActiveCountFiles)
+	fmt.Println("count files awaiting a report:", s.ExpiredCountFiles)
+	fmt.Println("reports awaiting upload:", s.ReadyReports)
+	quarantined, err := upload.Quarantined(telemetry.Default)
+	if err != nil {
+		warnf("failed to read quarantine dir: %v", err)
+	}
+	fmt.Println("quarantined count files:", len(quarantined))
+	for _, q := range quarantined {
+		fmt.Printf("  %s (%d failed attempts): %s\n", q.Name, q.Attempts, q.Err)
+	}
+	if s.LastUpload.IsZero() {
+		fmt.Println("last upload: never")
+	} else {
+		fmt.Printf("last upload: %s (report for the week ending %s)\n", s.LastUpload.Format(time.DateTime), s.LastUploadWeek)
+	}
+	switch {
+	case s.Mode != "on":
+		fmt.Println("next upload: none, as uploading is disabled (see \"gotelemetry on\")")
+	case s.NextReport.IsZero():
+		fmt.Println("next upload: unknown")
+	case s.ReadyReports > 0 || s.ExpiredCountFiles > 0:
+		fmt.Println("next upload: the next time a program using telemetry runs")
+	default:
+		fmt.Printf("next upload: after %s\n", s.NextReport.Format(telemetry.DateOnly))
+	}
 }
 
 func runClean(_ []string) {
-	// For now, be careful to only remove counter files and reports.
-	// It would probably be OK to just remove everything, but it may
-	// be useful to preserve the weekends file.
-	for dir, suffixes := range map[string][]string{
-		telemetry.Default.LocalDir():  {"." + counter.FileVersion + ".count", ".json"},
-		telemetry.Default.UploadDir(): {".json"},
-	} {
-		entries, err := os.ReadDir(dir)
+	if !cleanCounters && !cleanReports && !cleanAll {
+		cleanCounters, cleanReports = true, true
+	}
+	countSuffix := "." + counter.FileVersion + ".count"
+
+	// For now, be careful to only remove data. It would probably be OK to
+	// just remove everything, but it may be useful to preserve the weekends
+	// file.
+	type target struct {
+		dir      string
+		suffixes []string
+	}
+	var targets []target
+	if cleanCounters || cleanAll {
+		targets = append(targets,
+			target{telemetry.Default.LocalDir(), []string{countSuffix}},
+			// Quarantined count files, and the record of why each was quarantined.
+			target{telemetry.Default.QuarantineDir(), []string{countSuffix, ".err"}},
+		)
+	}
+	if cleanReports || cleanAll {
+		targets = append(targets,
+			// Local reports, and reports ready for upload.
+			target{telemetry.Default.LocalDir(), []string{".json"}},
+			// Copies of uploaded reports.
+			target{telemetry.Default.UploadDir(), []string{".json"}},
+		)
+	}
+	if cleanAll {
+		targets = append(targets,
+			target{filepath.Join(telemetry.Default.LocalDir(), "crash"), []string{".crash"}},
+			target{telemetry.Default.DebugDir(), []string{".log"}},
+		)
+	}
+
+	for _, t := range targets {
+		entries, err := os.ReadDir(t.dir)
 		if err != nil {
 			if !os.IsNotExist(err) {
 				warnf("failed to read telemetry dir: %v", err)
@@ -287,27 +615,85 @@ func runClean(_ []string) {
 			continue
 		}
 		for _, entry := range entries {
-			// TODO: use slices.ContainsFunc once it is available in all supported Go
-			// versions.
-			remove := false
-			for _, suffix := range suffixes {
-				if strings.HasSuffix(entry.Name(), suffix) {
-					remove = true
-					break
-				}
+			if entry.IsDir() || !slices.ContainsFunc(t.suffixes, func(suffix string) bool {
+				return strings.HasSuffix(entry.Name(), suffix)
+			}) {
+				continue
+			}
+			path := filepath.Join(t.dir, entry.Name())
+			if cleanDryRun {
+				fmt.Println("would remove", path)
+				continue
 			}
-			if remove {
-				path := filepath.Join(dir, entry.Name())
-				if err := os.Remove(path); err != nil {
-					warnf("failed to remove %s: %v", path, err)
-				}
+			if err := os.Remove(path); err != nil {
+				warnf("failed to remove %s: %v", path, err)
+				continue
+			}
+			fmt.Println("removed", path)
+		}
+	}
+}
+
+func runCSV(args []string) {
+	patterns, err := glob.CompileSet(args)
+	if err != nil {
+		failf("%v\n", err)
+	}
+	csv.Csv(patterns.Match)
+}
+
+func runCrashes(_ []string) {
+	list, err := crashes.Collect(telemetry.Default.LocalDir())
+	if err != nil && !os.IsNotExist(err) {
+		failf("Failed to read local data: %v\n", err)
+	}
+	if len(list) == 0 {
+		fmt.Println("No crashes recorded.")
+		return
+	}
+	for i, c := range list {
+		if crashesIssue {
+			if i > 0 {
+				fmt.Println("---")
+				fmt.Println()
 			}
+			err = crashes.WriteIssue(os.Stdout, c)
+			fmt.Println()
+		} else {
+			err = crashes.Write(os.Stdout, c)
+		}
+		if err != nil {
+			failf("%v\n", err)
 		}
 	}
 }
 
-func runCSV(_ []string) {
-	csv.Csv()
+func runWatch(args []string) {
+	patterns, err := glob.CompileSet(args)
+	if err != nil {
+		failf("%v\n", err)
+	}
+	if watchInterval <= 0 {
+		failf("invalid -interval %v\n", watchInterval)
+	}
+	if mode, _ := telemetry.Default.Mode(); mode == "off" {
+		warnf("telemetry is off, so counters are not being recorded")
+	}
+	w := watch.NewWatcher(telemetry.Default.LocalDir(), patterns.Match)
+	for {
+		changes, err := w.Poll()
+		if err != nil {
+			failf("%v\n", err)
+		}
+		for _, c := range changes {
+			name, _, stack := strings.Cut(c.Counter, "\n")
+			if stack {
+				name += " (stack)"
+			}
+			fmt.Printf("%s %s %s +%d = %d\n", time.Now().Format(time.TimeOnly), c.Program, name, c.Delta, c.Count)
+		}
+		time.Sleep(watchInterval)
+	}
 }
 
 func runDump(args []string) {
@@ -321,6 +707,21 @@ func runDump(args []string) {
 			args = append(args, filepath.Join(localdir, f.Name()))
 		}
 	}
+	if dumpMerge && dumpFormat == "" {
+		dumpFormat = "json"
+	}
+	if dumpFormat != "" && !slices.Contains(dump.Formats, dumpFormat) {
+		failf("unknown format %q (want one of %s)\n", dumpFormat, strings.Join(dump.Formats, ", "))
+	}
+	var patterns glob.Set
+	if dumpCounter != "" {
+		p, err := glob.Compile(dumpCounter)
+		if err != nil {
+			failf("%v\n", err)
+		}
+		patterns = glob.Set{p}
+	}
+	files := make(map[string]*counter.File)
 	for _, file := range args {
 		if !strings.HasSuffix(file, ".count") {
 			log.Printf("%s: not a counter file, skipping", file)
@@ -336,24 +737,157 @@ func runDump(args []string) {
 			log.Printf("%v, skipping", err)
 			continue
 		}
+		for name := range f.Count {
+			if !patterns.Match(name) {
+				delete(f.Count, name)
+			}
+		}
+		if dumpFormat != "" {
+			files[file] = f
+			continue
+		}
 		js, err := json.MarshalIndent(f, "", "\t")
 		if err != nil {
 			log.Printf("%s: failed to print - %v", file, err)
 		}
 		fmt.Printf("-- %v --\n%s\n", file, js)
 	}
+	if dumpFormat != "" {
+		if err := dump.Write(os.Stdout, dump.Rows(files, dumpMerge), dumpFormat, dumpMerge); err != nil {
+			failf("%v\n", err)
+		}
+	}
 }
 
 func runUpload(_ []string) {
+	if mode, _ := telemetry.Default.Mode(); mode != "on" {
+		warnf("telemetry mode is %q, so no reports will be uploaded", mode)
+	}
 	if err := upload.Run(upload.RunConfig{
 		LogWriter: os.Stderr,
+		DryRun:    uploadDryRun,
 	}); err != nil {
-		fmt.Printf("Upload failed: %v\n", err)
+		failf("Upload failed: %v\n", err)
+	}
+	if uploadDryRun {
+		fmt.Println("Dry run completed; nothing was changed.")
 	} else {
 		fmt.Println("Upload completed.")
 	}
 }
 
+func runExportUpload(args []string) {
+	if len(args) != 1 {
+		failf("usage: gotelemetry export-upload [-config version] <file>")
+	}
+	ucfg, version, err := offlineConfig(exportConfig)
+	if err != nil {
+		failf("Failed to read upload config: %v", err)
+	}
+	n, err := upload.Export("", args[0], ucfg, version)
+	if err != nil {
+		failf("Export failed: %v", err)
+	}
+	if n == 0 {
+		fmt.Println("No reports to export.")
+		return
+	}
+	fmt.Printf("Exported %d reports to %s.\n", n, args[0])
+}
+
+func runImportUpload(args []string) {
+	if len(args) != 1 {
+		failf("usage: gotelemetry import-upload <file>")
+	}
+	vkey, n, err := upload.Import(args[0], upload.RunConfig{
+		LogWriter: os.Stderr,
+	})
+	if vkey != "" {
+		fmt.Printf("Archive signed by %s.\n", vkey)
+	}
+	if err != nil {
+		failf("Import failed (%d reports uploaded): %v", n, err)
+	}
+	fmt.Printf("Uploaded %d reports.\n", n)
+}
+
+func runSimulateUpload(_ []string) {
+	if simulateWeek == "" {
+		failf("usage: gotelemetry simulate-upload -week YYYY-MM-DD [-config version]")
+	}
+	ucfg, version, err := offlineConfig(simulateConfig)
+	if err != nil {
+		failf("Failed to read upload config: %v", err)
+	}
+	sim, err := upload.Simulate(upload.RunConfig{}, simulateWeek, ucfg, version)
+	if err != nil {
+		failf("Simulation failed: %v", err)
+	}
+	js, err := json.MarshalIndent(sim.Upload, "", "\t")
+	if err != nil {
+		failf("Failed to print report: %v", err)
+	}
+	fmt.Printf("%s\n", js)
+	if len(sim.Blockers) > 0 {
+		fmt.Println()
+		fmt.Println("The report would not be uploaded:")
+		for _, b := range sim.Blockers {
+			fmt.Printf("\t%s\n", b)
+		}
+	}
+	if len(sim.Dropped) > 0 {
+		fmt.Println()
+		fmt.Println("Dropped counters:")
+		for _, d := range sim.Dropped {
+			name, _, stack := strings.Cut(d.Counter, "\n")
+			if stack {
+				name += " (stack)"
+			}
+			fmt.Printf("\t%s: %s: %s\n", d.Program, name, d.Reason)
+		}
+	}
+}
+
+func runReport(_ []string) {
+	ucfg, version, err := offlineConfig(reportConfig)
+	if err != nil {
+		failf("Failed to read upload config: %v\n", err)
+	}
+	sim, err := upload.Preview(upload.RunConfig{}, reportWeek, ucfg, version)
+	if err != nil {
+		failf("Failed to create report: %v\n", err)
+	}
+	js, err := json.MarshalIndent(sim.Upload, "", " ")
+	if err != nil {
+		failf("Failed to print report: %v\n", err)
+	}
+	fmt.Printf("%s\n", js)
+}
+
+// offlineConfig returns the upload config with the given version from the Go
+// module cache, without using the network, along with its canonical version.
+// If version names an existing file, the config is read from that file
+// instead.
+func offlineConfig(version string) (*telemetry.UploadConfig, string, error) {
+	if data, err := os.ReadFile(version); err == nil {
+		var cfg telemetry.UploadConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, "", fmt.Errorf("invalid config %s: %v", version, err)
+		}
+		return &cfg, "v0.0.0-0", nil
+	}
+	out, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("locating module cache: %v", err)
+	}
+	// The download cache is laid out as a module proxy.
+	proxy := filepath.ToSlash(filepath.Join(strings.TrimSpace(string(out)), "cache", "download"))
+	if !strings.HasPrefix(proxy, "/") {
+		proxy = "/" + proxy // Windows drive letter
+	}
+	return configstore.Download(version, []string{"GOPROXY=file://" + proxy, "GOSUMDB=off"})
+}
+
 func main() {
 	log.SetFlags(0)
 	flag.Usage = usage
@@ -43,6 +43,21 @@ func New(name string) *Counter {
 	return counter.New(name)
 }
 
+// NewBool returns a counter with the given name that records whether an event
+// occurred during each counter file's period, rather than how many times it
+// occurred. Incrementing the counter sets its value in the current counter
+// file to 1; further increments have no effect until the file is rotated.
+//
+// Boolean counters keep heavy users from dominating the aggregate: uploaded
+// reports carry one bit per week, and charts show the fraction of reporters
+// that observed the event. Their charts should be declared with the
+// "boolean" type in the chart config.
+//
+// Like New, NewBool can be called in global initializers at no startup cost.
+func NewBool(name string) *Counter {
+	return counter.NewBool(name)
+}
+
 // A Counter is a single named event counter.
 // A Counter is safe for use by multiple goroutines simultaneously.
 //
@@ -70,6 +85,10 @@ type Counter = counter.Counter
 // requiring, at a minimum, a call to runtime.Callers.
 type StackCounter = counter.StackCounter
 
+// StackStats holds statistics about the stacks recorded by a StackCounter,
+// as reported by its Stats method.
+type StackStats = counter.StackStats
+
 // NewStack returns a new stack counter with the given name and depth.
 //
 // See "Counter Naming" in the package doc for a description of counter naming
@@ -78,6 +97,29 @@ func NewStack(name string, depth int) *StackCounter {
 	return counter.NewStack(name, depth)
 }
 
+// An Event counts occurrences of an event, broken down by the values of a
+// small, fixed set of attributes. Each distinct combination of values is
+// recorded as a separate counter.
+type Event = counter.Event
+
+// NewEvent returns a new event counter with the given name and attributes.
+// At most four attributes are recorded. Its Inc method takes the attribute
+// values of an occurrence, in the same order:
+//
+//	var switches = counter.NewEvent("go/toolchain/switch", "from", "to")
+//	...
+//	switches.Inc("go1.21.0", "go1.22.1")
+//
+// Values are truncated to 32 bytes, and characters other than ASCII letters,
+// digits, and "+-./_" are replaced by '_'. Only the values enumerated for
+// each attribute in the upload config are uploaded.
+//
+// See "Counter Naming" in the package doc for a description of counter naming
+// conventions.
+func NewEvent(name string, attrs ...string) *Event {
+	return counter.NewEvent(name, attrs...)
+}
+
 // Open prepares telemetry counters for recording to the file system.
 //
 // If the telemetry mode is "off", Open is a no-op. Otherwise, it opens the
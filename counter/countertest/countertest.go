@@ -57,6 +57,12 @@ func ReadStackCounter(c *counter.StackCounter) (stackCounts map[string]uint64, _
 	return ic.ReadStack(c)
 }
 
+// ReadEvent reads the given Event. The result maps the name of the counter
+// for each combination of attribute values to its count.
+func ReadEvent(e *counter.Event) (counts map[string]uint64, _ error) {
+	return ic.ReadEvent(e)
+}
+
 // ReadFile reads the counters and stack counters from the given file.
 func ReadFile(name string) (counters, stackCounters map[string]uint64, _ error) {
 	return ic.ReadFile(name)
@@ -5,16 +5,28 @@
 // Package counter implements a simple counter system for collecting
 // totally public telemetry data.
 //
-// There are two kinds of counters, basic counters and stack counters.
+// There are three kinds of counters: basic counters, stack counters, and
+// event counters.
 // Basic counters are created by [New].
 // Stack counters are created by [NewStack].
-// Both are incremented by calling Inc().
+// Event counters are created by [NewEvent].
+// All are incremented by calling Inc().
+//
+// Basic counters created by [NewBool] record only whether an event happened
+// during a counter file's period: their value in each file is at most 1.
 //
 // Basic counters are very cheap. Stack counters are more expensive, as they
 // require parsing the stack. (Stack counters are implemented as basic counters
 // whose names are the concatenation of the name and the stack trace. There is
 // an upper limit on the size of this name, about 4K bytes. If the name is too
-// long the stack will be truncated and "truncated" appended.)
+// long the stack will be truncated and "truncated" appended; each truncation
+// increments the counter/stack-truncated counter, and StackCounter.Stats
+// reports how many of a counter's stacks were truncated.)
+//
+// Event counters record structured events, such as a switch from one Go
+// toolchain to another, without encoding the details in counter names. Each
+// event has a few named attributes, and each combination of attribute values
+// is recorded as a basic counter named "name{attr1=value1,attr2=value2}".
 //
 // When counter files expire they are turned into reports by the upload
 // package. The first time any counter file is created for a user, a random day
@@ -25,10 +37,10 @@
 //
 // # Counter Naming
 //
-// Counter names passed to [New] and [NewStack] should follow these
-// conventions:
+// Counter names passed to [New], [NewStack], and [NewEvent] should follow
+// these conventions:
 //
-//   - Names cannot contain whitespace or newlines.
+//   - Names cannot contain whitespace or newlines, or the braces '{' and '}'.
 //
 //   - Names must be valid unicode, with no unprintable characters.
 //
@@ -0,0 +1,26 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashmonitor
+
+import ic "golang.org/x/telemetry/internal/crashmonitor"
+
+// RecordPanic increments a telemetry stack counter for a panic recovered by
+// the application, much as the crash monitor enabled by
+// [golang.org/x/telemetry.Start] does for fatal crashes. Call it from the
+// deferred function that recovers the panic:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			crashmonitor.RecordPanic(r, debug.Stack())
+//			...
+//		}
+//	}()
+//
+// The counter is named "crash/panic" followed by the stack of the panicking
+// goroutine, encoded as for fatal crash counters. Neither the recovered value
+// nor any file names or arguments from the stack are recorded.
+func RecordPanic(recovered any, stack []byte) {
+	ic.RecordPanic(recovered, stack)
+}
@@ -7,6 +7,11 @@ package telemetry
 import "golang.org/x/telemetry/internal/telemetry"
 
 // Dir returns the telemetry directory.
+//
+// By default, this is os.UserConfigDir()/go/telemetry. If the GOTELEMETRYDIR
+// environment variable is set, it names the telemetry directory instead, and
+// must be an absolute path. Dir returns "" if there is no telemetry directory,
+// in which case telemetry is off.
 func Dir() string {
 	return telemetry.Default.Dir()
 }
@@ -0,0 +1,152 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// aggregateCacheSize is the number of on-demand chart ranges kept in memory.
+const aggregateCacheSize = 16
+
+// An aggregator computes charts on demand for date ranges that the worker
+// has not precomputed, from the merged reports for each day in the range.
+type aggregator struct {
+	ucfg    *tconfig.Config
+	rules   *charts.Rules
+	merge   storage.BucketHandle
+	maxDays int // longest range computed on demand
+
+	mu    sync.Mutex
+	cache map[string]map[string]any // chart object name -> charts
+	order []string                  // keys of cache, oldest first
+}
+
+func newAggregator(ucfg *tconfig.Config, rules *charts.Rules, merge storage.BucketHandle, maxDays int) *aggregator {
+	return &aggregator{
+		ucfg:    ucfg,
+		rules:   rules,
+		merge:   merge,
+		maxDays: maxDays,
+		cache:   make(map[string]map[string]any),
+	}
+}
+
+// charts returns the charts for the days from start to end inclusive, in the
+// form stored by the worker.
+func (a *aggregator) charts(ctx context.Context, start, end time.Time) (map[string]any, error) {
+	obj := chartObjectName(start, end)
+	a.mu.Lock()
+	cached, ok := a.cache[obj]
+	a.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	if days := int(end.Sub(start)/(24*time.Hour)) + 1; days > a.maxDays {
+		return nil, content.Error(fmt.Errorf("date range of %d days exceeds the maximum of %d", days, a.maxDays), http.StatusBadRequest)
+	}
+	b := charts.NewBuilder(a.ucfg, a.rules)
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		err := charts.ScanMerged(ctx, a.merge, date, b.Add)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, content.Error(fmt.Errorf("no data for %s", date.Format(telemetry.DateOnly)), http.StatusNotFound)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	// Round trip through JSON, so that the result is the same as for charts
+	// loaded from the chart bucket.
+	data, err := json.Marshal(b.Charts(start, end))
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.cache[obj]; !ok {
+		if len(a.order) == aggregateCacheSize {
+			delete(a.cache, a.order[0])
+			a.order = a.order[1:]
+		}
+		a.cache[obj] = result
+		a.order = append(a.order, obj)
+	}
+	return result, nil
+}
+
+// forget discards the cached charts.
+func (a *aggregator) forget() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	clear(a.cache)
+	a.order = nil
+}
+
+// chartObjectName returns the name of the chart object the worker writes
+// for the given date range.
+func chartObjectName(start, end time.Time) string {
+	if start.Equal(end) {
+		return end.Format(telemetry.DateOnly) + ".json"
+	}
+	return start.Format(telemetry.DateOnly) + "_" + end.Format(telemetry.DateOnly) + ".json"
+}
+
+// handleChartRange serves the charts for the range given by the start and
+// end query parameters. Charts precomputed by the worker are served if they
+// exist; otherwise the charts are computed by agg.
+func handleChartRange(render renderer, chartBucket storage.BucketHandle, agg *aggregator) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		q := r.URL.Query()
+		start, err := time.Parse(telemetry.DateOnly, q.Get("start"))
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		end, err := time.Parse(telemetry.DateOnly, q.Get("end"))
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		if end.Before(start) {
+			return content.Error(fmt.Errorf("end date is earlier than start"), http.StatusBadRequest)
+		}
+
+		objName := chartObjectName(start, end)
+		page := chartPage{
+			Date:       strings.TrimSuffix(objName, ".json"),
+			ChartTitle: chartTitle(objName),
+		}
+		page.Charts, err = loadCharts(ctx, objName, chartBucket)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			page.Charts, err = agg.charts(ctx, start, end)
+		}
+		if err != nil {
+			return err
+		}
+		page.Charts = filterPrograms(page.Charts, q.Get("program"))
+		if q.Get("format") == "json" {
+			return content.CachedJSON(w, r, page.Charts, time.Time{}, chartMaxAge)
+		}
+		return render(w, "charts.html", page)
+	}
+}
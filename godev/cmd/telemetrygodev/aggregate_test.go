@@ -0,0 +1,122 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestChartRange(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	mergeBucket, err := storage.NewFSBucket(ctx, dir, "merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chartBucket, err := storage.NewFSBucket(ctx, dir, "chart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(bucket storage.BucketHandle, obj string, values ...any) {
+		t.Helper()
+		w, err := bucket.Object(obj).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc := json.NewEncoder(w)
+		for _, v := range values {
+			if err := enc.Encode(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report := func(x float64) telemetry.Report {
+		return telemetry.Report{
+			Week: "2024-01-01",
+			X:    x,
+			Programs: []*telemetry.ProgramReport{{
+				Program:   "golang.org/x/tools/gopls",
+				Version:   "v0.11.0",
+				GoVersion: "go1.20.1",
+				GOOS:      "linux",
+				GOARCH:    "amd64",
+				Counters:  map[string]int64{"editor:vim": 1},
+			}},
+		}
+	}
+	write(mergeBucket, "2024-01-01.json", report(0.1), report(0.2))
+	write(mergeBucket, "2024-01-02.json", report(0.3))
+	write(chartBucket, "2024-01-03_2024-01-09.json", map[string]any{"NumReports": 42})
+
+	ucfg, err := tconfig.ReadConfig("testdata/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	agg := newAggregator(ucfg, nil, mergeBucket, 7)
+	render := func(w http.ResponseWriter, tmpl string, page any) error {
+		return json.NewEncoder(w).Encode(page.(chartPage))
+	}
+	handler := handleChartRange(render, chartBucket, agg)
+
+	get := func(query string, wantCode int) chartPage {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/charts/?"+query, nil))
+		if rec.Code != wantCode {
+			t.Fatalf("GET %s: status = %d, want %d: %s", query, rec.Code, wantCode, rec.Body)
+		}
+		var page chartPage
+		if wantCode == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return page
+	}
+
+	// Precomputed charts are served from the chart bucket.
+	if page := get("start=2024-01-03&end=2024-01-09", http.StatusOK); page.Charts["NumReports"] != 42.0 {
+		t.Errorf("precomputed charts = %v, want NumReports 42", page.Charts)
+	}
+
+	// Others are computed from the merged reports.
+	page := get("start=2024-01-01&end=2024-01-02", http.StatusOK)
+	if page.Date != "2024-01-01_2024-01-02" || page.Charts["NumReports"] != 3.0 {
+		t.Errorf("computed charts for %s = %v, want NumReports 3", page.Date, page.Charts)
+	}
+
+	// Filtering by program does not change the cached charts.
+	if page := get("start=2024-01-01&end=2024-01-02&program=gopls", http.StatusOK); len(page.Charts["Programs"].([]any)) != 1 {
+		t.Errorf("charts filtered for gopls have %d programs, want 1", len(page.Charts["Programs"].([]any)))
+	}
+	if page := get("start=2024-01-01&end=2024-01-02", http.StatusOK); len(page.Charts["Programs"].([]any)) != 2 {
+		t.Errorf("unfiltered charts after a filtered request have %d programs, want 2", len(page.Charts["Programs"].([]any)))
+	}
+
+	// Computed charts are cached.
+	if err := os.Remove(mergeBucket.Object("2024-01-02.json").(*storage.FSObject).Filename()); err != nil {
+		t.Fatal(err)
+	}
+	if page := get("start=2024-01-01&end=2024-01-02", http.StatusOK); page.Charts["NumReports"] != 3.0 {
+		t.Errorf("cached charts = %v, want NumReports 3", page.Charts)
+	}
+
+	get("start=2024-01-02&end=2024-01-02", http.StatusNotFound)   // not merged
+	get("start=2024-01-01&end=2024-01-08", http.StatusBadRequest) // too long
+	get("start=2024-01-02&end=2024-01-01", http.StatusBadRequest) // reversed
+	get("start=2024-01-02", http.StatusBadRequest)                // missing end
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// The public API serves the aggregated chart data as JSON, for programmatic
+// access to the public dataset:
+//
+//	/api/v1/programs                    the programs and chart names
+//	/api/v1/charts/<program>            the charts of a program
+//	/api/v1/charts/<program>/<chart>    a single chart of a program
+//
+// A program may be given by its path or the last element of its path, such
+// as "gopls". By default, the charts are those of the home page; the start
+// and end query parameters (YYYY-MM-DD) select the charts for another date
+// range, as for /charts/.
+//
+// The API is versioned by its path: fields may be added to the responses,
+// but not removed or changed.
+
+const (
+	// apiMaxAge is how long clients and caches may reuse API responses. New
+	// charts are published once a day.
+	apiMaxAge = time.Hour
+	// apiRangeMaxAge is how long responses for explicit date ranges, whose
+	// data rarely changes, may be reused.
+	apiRangeMaxAge = 24 * time.Hour
+)
+
+// An apiPrograms is the response to /api/v1/programs.
+type apiPrograms struct {
+	DateRange any
+	Programs  []apiProgram
+}
+
+type apiProgram struct {
+	Name   string
+	Charts []string
+}
+
+// handleAPI serves the public API. Requests for a date range are served by
+// rangeHandler.
+func handleAPI(chartBucket storage.BucketHandle, rangeHandler http.Handler) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if q := r.URL.Query(); q.Has("start") || q.Has("end") {
+			rangeHandler.ServeHTTP(w, r)
+			return nil
+		}
+		ctx := r.Context()
+		obj, err := latestChartObject(ctx, chartBucket)
+		if err != nil {
+			return err
+		}
+		if obj == "" {
+			return content.Error(errors.New("no charts"), http.StatusNotFound)
+		}
+		charts, err := loadCharts(ctx, obj, chartBucket)
+		if err != nil {
+			return err
+		}
+		return serveAPI(w, r, charts, apiMaxAge)
+	}
+}
+
+// handleAPIRange serves the public API for the date range given by the
+// start and end query parameters, computing the charts with compute if they
+// were not precomputed.
+func handleAPIRange(chartBucket storage.BucketHandle, compute func(ctx context.Context, start, end time.Time) (map[string]any, error)) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		q := r.URL.Query()
+		start, err := time.Parse(telemetry.DateOnly, q.Get("start"))
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		end, err := time.Parse(telemetry.DateOnly, q.Get("end"))
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		if end.Before(start) {
+			return content.Error(errors.New("end date is earlier than start"), http.StatusBadRequest)
+		}
+		data, err := loadCharts(ctx, chartObjectName(start, end), chartBucket)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			data, err = compute(ctx, start, end)
+		}
+		if err != nil {
+			return err
+		}
+		return serveAPI(w, r, data, apiRangeMaxAge)
+	}
+}
+
+// serveAPI serves the API request r from data, the decoded JSON of a chart
+// object, allowing the response to be cached for maxAge.
+func serveAPI(w http.ResponseWriter, r *http.Request, data map[string]any, maxAge time.Duration) error {
+	programs, _ := data["Programs"].([]any)
+	var resp any
+	switch rest := strings.TrimPrefix(r.URL.Path, "/api/v1/"); {
+	case rest == "programs":
+		progs := apiPrograms{DateRange: data["DateRange"], Programs: []apiProgram{}}
+		for _, p := range programs {
+			p, _ := p.(map[string]any)
+			name, _ := p["Name"].(string)
+			prog := apiProgram{Name: name, Charts: []string{}}
+			for _, c := range programCharts(p) {
+				n, _ := c["Name"].(string)
+				prog.Charts = append(prog.Charts, n)
+			}
+			progs.Programs = append(progs.Programs, prog)
+		}
+		resp = progs
+	case strings.HasPrefix(rest, "charts/"):
+		prog, chart := findProgram(programs, strings.TrimPrefix(rest, "charts/"))
+		if prog == nil {
+			return content.Status(w, http.StatusNotFound)
+		}
+		if chart == "" {
+			resp = map[string]any{"DateRange": data["DateRange"], "NumReports": data["NumReports"], "Program": prog}
+			break
+		}
+		for _, c := range programCharts(prog) {
+			if c["Name"] == chart {
+				resp = map[string]any{"DateRange": data["DateRange"], "NumReports": data["NumReports"], "Program": prog["Name"], "Chart": c}
+			}
+		}
+		if resp == nil {
+			return content.Status(w, http.StatusNotFound)
+		}
+	default:
+		return content.Status(w, http.StatusNotFound)
+	}
+	return content.CachedJSON(w, r, resp, time.Time{}, maxAge)
+}
+
+// findProgram returns the program in programs named by the start of p, and
+// the rest of p, which names a chart. The longest matching name wins, so
+// that a program path is preferred to a shorter name.
+func findProgram(programs []any, p string) (prog map[string]any, chart string) {
+	best := -1
+	for _, x := range programs {
+		x, _ := x.(map[string]any)
+		name, _ := x["Name"].(string)
+		for _, n := range []string{name, path.Base(name)} {
+			if n == "" || len(n) <= best {
+				continue
+			}
+			if p == n {
+				prog, chart, best = x, "", len(n)
+			} else if rest, ok := strings.CutPrefix(p, n+"/"); ok {
+				prog, chart, best = x, rest, len(n)
+			}
+		}
+	}
+	return prog, chart
+}
+
+// programCharts returns the charts of a program in a decoded chart object.
+func programCharts(prog map[string]any) []map[string]any {
+	list, _ := prog["Charts"].([]any)
+	var charts []map[string]any
+	for _, c := range list {
+		if c, ok := c.(map[string]any); ok {
+			charts = append(charts, c)
+		}
+	}
+	return charts
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestAPI(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "charts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeCharts := func(obj, week string) {
+		data := map[string]any{
+			"DateRange":  []string{week, week},
+			"NumReports": 3,
+			"Programs": []any{
+				map[string]any{"Name": "golang.org/x/tools/gopls", "Charts": []any{
+					map[string]any{"Name": "gopls/client", "Data": []any{week}},
+					map[string]any{"Name": "gopls/editor", "Data": []any{}},
+				}},
+				map[string]any{"Name": "cmd/go", "Charts": []any{
+					map[string]any{"Name": "go/invocations", "Data": []any{}},
+				}},
+			},
+		}
+		w, err := bucket.Object(obj).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeCharts("2024-01-01_2024-01-07.json", "2024-01-07")
+	writeCharts("2023-12-01_2023-12-07.json", "2023-12-07")
+	computed := false
+	compute := func(context.Context, time.Time, time.Time) (map[string]any, error) {
+		computed = true
+		return map[string]any{"Programs": []any{}}, nil
+	}
+	h := handleAPI(bucket, handleAPIRange(bucket, compute))
+	get := func(target string) (int, http.Header, map[string]any) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		var body map[string]any
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("GET %s: invalid JSON: %v", target, err)
+			}
+		}
+		return w.Code, w.Result().Header, body
+	}
+
+	code, header, body := get("/api/v1/programs")
+	if code != http.StatusOK {
+		t.Fatalf("GET /api/v1/programs: status %d", code)
+	}
+	if got, want := header.Get("Cache-Control"), "public, max-age=3600"; got != want {
+		t.Errorf("GET /api/v1/programs: Cache-Control = %q, want %q", got, want)
+	}
+	wantPrograms := []any{
+		map[string]any{"Name": "golang.org/x/tools/gopls", "Charts": []any{"gopls/client", "gopls/editor"}},
+		map[string]any{"Name": "cmd/go", "Charts": []any{"go/invocations"}},
+	}
+	if got := body["Programs"]; !reflect.DeepEqual(got, wantPrograms) {
+		t.Errorf("GET /api/v1/programs: Programs = %v, want %v", got, wantPrograms)
+	}
+
+	// Programs may be named by their last path element.
+	for _, target := range []string{"/api/v1/charts/gopls/gopls/client", "/api/v1/charts/golang.org/x/tools/gopls/gopls/client"} {
+		code, _, body := get(target)
+		if code != http.StatusOK {
+			t.Fatalf("GET %s: status %d", target, code)
+		}
+		chart, _ := body["Chart"].(map[string]any)
+		if body["Program"] != "golang.org/x/tools/gopls" || chart["Name"] != "gopls/client" {
+			t.Errorf("GET %s = %v, want gopls/client of gopls", target, body)
+		}
+	}
+	if code, _, body := get("/api/v1/charts/cmd/go"); code != http.StatusOK || body["Program"].(map[string]any)["Name"] != "cmd/go" {
+		t.Errorf("GET /api/v1/charts/cmd/go = %d, %v; want the cmd/go charts", code, body)
+	}
+
+	// A precomputed range is read from the bucket.
+	code, header, body = get("/api/v1/charts/gopls/gopls/client?start=2023-12-01&end=2023-12-07")
+	if code != http.StatusOK || header.Get("Cache-Control") != "public, max-age=86400" {
+		t.Fatalf("GET range: status %d, Cache-Control %q", code, header.Get("Cache-Control"))
+	}
+	if got := body["Chart"].(map[string]any)["Data"]; !reflect.DeepEqual(got, []any{"2023-12-07"}) {
+		t.Errorf("GET range: data %v, want the 2023-12-07 charts", got)
+	}
+	if computed {
+		t.Errorf("precomputed range was computed")
+	}
+	if code, _, _ := get("/api/v1/programs?start=2023-11-01&end=2023-11-07"); code != http.StatusOK || !computed {
+		t.Errorf("GET range not precomputed: status %d, computed %t", code, computed)
+	}
+
+	for _, target := range []string{
+		"/api/v1/charts/gopls/gopls/nonexistent",
+		"/api/v1/charts/nonexistent",
+		"/api/v1/unknown",
+	} {
+		if code, _, _ := get(target); code != http.StatusNotFound {
+			t.Errorf("GET %s: status %d, want %d", target, code, http.StatusNotFound)
+		}
+	}
+	if code, _, _ := get("/api/v1/programs?start=2024-01-07&end=2024-01-01"); code != http.StatusBadRequest {
+		t.Errorf("GET with reversed range: status %d, want %d", code, http.StatusBadRequest)
+	}
+}
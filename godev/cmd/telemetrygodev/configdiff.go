@@ -0,0 +1,239 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/internal/configstore"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+const (
+	// configListInterval is how often the published versions of the config
+	// module are listed again. configstore.ListVersions caches the listing
+	// itself, so most refreshes do not run the go command.
+	configListInterval = time.Minute
+
+	// configListTimeout bounds the time spent listing the published
+	// versions of the config module.
+	configListTimeout = 30 * time.Second
+)
+
+// A configVersions downloads versions of the upload config from the config
+// module. Published versions never change, so they are downloaded once.
+type configVersions struct {
+	download func(version string) (*telemetry.UploadConfig, string, error)
+	list     func(context.Context) ([]string, error) // published versions, oldest first
+
+	mu      sync.Mutex
+	cache   map[string]*telemetry.UploadConfig // by canonical version
+	listed  []string                           // published versions, as last listed
+	next    time.Time                          // when to list them again
+	listing bool                               // a listing is in progress
+}
+
+func newConfigVersions() *configVersions {
+	return &configVersions{
+		download: func(version string) (*telemetry.UploadConfig, string, error) {
+			return configstore.Download(version, nil)
+		},
+		list: func(ctx context.Context) ([]string, error) {
+			return configstore.ListVersions(ctx, configstore.ListOptions{})
+		},
+		cache: make(map[string]*telemetry.UploadConfig),
+	}
+}
+
+// get returns the upload config of the given version, which must be a
+// semantic version or "latest", and its canonical version. A version that
+// cannot be downloaded is reported as 404 Not Found.
+func (v *configVersions) get(version string) (*telemetry.UploadConfig, string, error) {
+	if version != "latest" && !semver.IsValid(version) {
+		return nil, "", content.Error(fmt.Errorf("invalid config version %q", version), http.StatusBadRequest)
+	}
+	v.mu.Lock()
+	cfg, ok := v.cache[version]
+	v.mu.Unlock()
+	if ok {
+		return cfg, version, nil
+	}
+	cfg, canonical, err := v.download(version)
+	if err != nil {
+		return nil, "", content.Error(fmt.Errorf("config version %s: %v", version, err), http.StatusNotFound)
+	}
+	v.mu.Lock()
+	v.cache[canonical] = cfg
+	v.mu.Unlock()
+	return cfg, canonical, nil
+}
+
+// published returns the published versions of the config module, newest
+// first, as last listed, or none if they have not been listed yet.
+//
+// Listing the versions may run the go command, which can be slow, so
+// requests never wait for it: the listing is refreshed in the background.
+func (v *configVersions) published() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.listing && !time.Now().Before(v.next) {
+		v.listing = true
+		go v.refresh()
+	}
+	versions := slices.Clone(v.listed)
+	slices.Reverse(versions)
+	return versions
+}
+
+// refresh lists the published versions of the config module. If listing
+// fails, the previous listing is kept.
+func (v *configVersions) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), configListTimeout)
+	defer cancel()
+	versions, err := v.list(ctx)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err == nil {
+		v.listed = versions
+	}
+	v.next = time.Now().Add(configListInterval)
+	v.listing = false
+}
+
+// A configDiff describes the changes between two versions of the upload
+// config.
+type configDiff struct {
+	From, To  string // canonical versions
+	GOOS      listDiff
+	GOARCH    listDiff
+	GoVersion listDiff
+	Programs  []programDiff // added, removed, or changed programs, by name
+}
+
+// A programDiff describes the changes to the config of a program.
+type programDiff struct {
+	Name     string
+	Added    bool // the program is new in the To version
+	Removed  bool // the program is not in the To version
+	Versions listDiff
+	Counters listDiff
+	Stacks   listDiff
+	Events   listDiff
+}
+
+// A listDiff holds the elements added to and removed from a list.
+type listDiff struct {
+	Label   string // what the list holds, for display
+	Added   []string
+	Removed []string
+}
+
+// Empty reports whether the list did not change.
+func (d listDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// Empty reports whether the config did not change.
+func (d *configDiff) Empty() bool {
+	return d.GOOS.Empty() && d.GOARCH.Empty() && d.GoVersion.Empty() && len(d.Programs) == 0
+}
+
+// diffConfigs returns the changes from the upload config from to the upload
+// config to. Counters, stacks, and events are compared by name.
+func diffConfigs(from, to *telemetry.UploadConfig) *configDiff {
+	d := &configDiff{
+		GOOS:      diffLists("GOOS", from.GOOS, to.GOOS),
+		GOARCH:    diffLists("GOARCH", from.GOARCH, to.GOARCH),
+		GoVersion: diffLists("Go versions", from.GoVersion, to.GoVersion),
+	}
+	programs := func(cfg *telemetry.UploadConfig) map[string]*telemetry.ProgramConfig {
+		m := make(map[string]*telemetry.ProgramConfig)
+		for _, p := range cfg.Programs {
+			m[p.Name] = p
+		}
+		return m
+	}
+	fromPrograms, toPrograms := programs(from), programs(to)
+	names := make(map[string]bool)
+	for name := range fromPrograms {
+		names[name] = true
+	}
+	for name := range toPrograms {
+		names[name] = true
+	}
+	empty := new(telemetry.ProgramConfig)
+	for name := range names {
+		p0, p1 := fromPrograms[name], toPrograms[name]
+		pd := programDiff{Name: name, Added: p0 == nil, Removed: p1 == nil}
+		if p0 == nil {
+			p0 = empty
+		}
+		if p1 == nil {
+			p1 = empty
+		}
+		pd.Versions = diffLists("Versions", p0.Versions, p1.Versions)
+		pd.Counters = diffLists("Counters", counterNames(p0.Counters), counterNames(p1.Counters))
+		pd.Stacks = diffLists("Stacks", counterNames(p0.Stacks), counterNames(p1.Stacks))
+		pd.Events = diffLists("Events", eventNames(p0.Events), eventNames(p1.Events))
+		if pd.Added || pd.Removed || !pd.Versions.Empty() || !pd.Counters.Empty() || !pd.Stacks.Empty() || !pd.Events.Empty() {
+			d.Programs = append(d.Programs, pd)
+		}
+	}
+	sort.Slice(d.Programs, func(i, j int) bool {
+		return d.Programs[i].Name < d.Programs[j].Name
+	})
+	return d
+}
+
+// diffLists returns the sorted elements of to that are not in from, and of
+// from that are not in to.
+func diffLists(label string, from, to []string) listDiff {
+	in := func(list []string) map[string]bool {
+		m := make(map[string]bool)
+		for _, s := range list {
+			m[s] = true
+		}
+		return m
+	}
+	inFrom, inTo := in(from), in(to)
+	d := listDiff{Label: label}
+	for s := range inTo {
+		if !inFrom[s] {
+			d.Added = append(d.Added, s)
+		}
+	}
+	for s := range inFrom {
+		if !inTo[s] {
+			d.Removed = append(d.Removed, s)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	return d
+}
+
+func counterNames(counters []telemetry.CounterConfig) []string {
+	var names []string
+	for _, c := range counters {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func eventNames(events []telemetry.EventConfig) []string {
+	var names []string
+	for _, e := range events {
+		names = append(names, e.Name)
+	}
+	return names
+}
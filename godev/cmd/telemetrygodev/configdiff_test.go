@@ -0,0 +1,168 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/exp/slog"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	from := &telemetry.UploadConfig{
+		GOOS:   []string{"darwin", "linux"},
+		GOARCH: []string{"amd64"},
+		Programs: []*telemetry.ProgramConfig{
+			{
+				Name:     "golang.org/x/tools/gopls",
+				Versions: []string{"v0.1.0"},
+				Counters: []telemetry.CounterConfig{{Name: "gopls/client:{vscode,vim}"}, {Name: "gopls/old"}},
+			},
+			{Name: "cmd/old", Versions: []string{"go1.21.0"}},
+			{Name: "cmd/go", Counters: []telemetry.CounterConfig{{Name: "go/invocations"}}},
+		},
+	}
+	to := &telemetry.UploadConfig{
+		GOOS:   []string{"darwin", "linux", "windows"},
+		GOARCH: []string{"amd64"},
+		Programs: []*telemetry.ProgramConfig{
+			{Name: "cmd/go", Counters: []telemetry.CounterConfig{{Name: "go/invocations", Rate: 0.5}}},
+			{
+				Name:     "golang.org/x/tools/gopls",
+				Versions: []string{"v0.1.0", "v0.2.0"},
+				Counters: []telemetry.CounterConfig{{Name: "gopls/client:{vscode,vim}"}},
+				Stacks:   []telemetry.CounterConfig{{Name: "gopls/bug"}},
+			},
+			{
+				Name:   "cmd/new",
+				Events: []telemetry.EventConfig{{Name: "new/event"}},
+			},
+		},
+	}
+	got := diffConfigs(from, to)
+	want := &configDiff{
+		GOOS:      listDiff{Label: "GOOS", Added: []string{"windows"}},
+		GOARCH:    listDiff{Label: "GOARCH"},
+		GoVersion: listDiff{Label: "Go versions"},
+		Programs: []programDiff{
+			{
+				Name:     "cmd/new",
+				Added:    true,
+				Versions: listDiff{Label: "Versions"},
+				Counters: listDiff{Label: "Counters"},
+				Stacks:   listDiff{Label: "Stacks"},
+				Events:   listDiff{Label: "Events", Added: []string{"new/event"}},
+			},
+			{
+				Name:     "cmd/old",
+				Removed:  true,
+				Versions: listDiff{Label: "Versions", Removed: []string{"go1.21.0"}},
+				Counters: listDiff{Label: "Counters"},
+				Stacks:   listDiff{Label: "Stacks"},
+				Events:   listDiff{Label: "Events"},
+			},
+			{
+				Name:     "golang.org/x/tools/gopls",
+				Versions: listDiff{Label: "Versions", Added: []string{"v0.2.0"}},
+				Counters: listDiff{Label: "Counters", Removed: []string{"gopls/old"}},
+				Stacks:   listDiff{Label: "Stacks", Added: []string{"gopls/bug"}},
+				Events:   listDiff{Label: "Events"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("diffConfigs mismatch (-want +got):\n%s", diff)
+	}
+	if got.Empty() {
+		t.Error("Empty() = true for changed configs")
+	}
+	if !diffConfigs(to, to).Empty() {
+		t.Error("Empty() = false for identical configs")
+	}
+}
+
+func TestConfigDiffPage(t *testing.T) {
+	deployed, err := tconfig.ReadConfig("testdata/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := newUploadConfigSource(deployed, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	configs := map[string]*telemetry.UploadConfig{
+		"v0.1.0": {Programs: []*telemetry.ProgramConfig{{Name: "cmd/go"}}},
+		"v0.2.0": {Programs: []*telemetry.ProgramConfig{{Name: "cmd/go"}, {Name: "cmd/compile"}}},
+	}
+	versions := newConfigVersions()
+	downloads := 0
+	versions.download = func(version string) (*telemetry.UploadConfig, string, error) {
+		downloads++
+		if version == "latest" {
+			version = "v0.2.0"
+		}
+		cfg, ok := configs[version]
+		if !ok {
+			return nil, "", fmt.Errorf("unknown revision %s", version)
+		}
+		return cfg, version, nil
+	}
+	listed := make(chan bool)
+	versions.list = func(context.Context) ([]string, error) {
+		<-listed
+		return []string{"v0.1.0", "v0.2.0"}, nil
+	}
+	h := handleConfig(newRenderer(fsys(false)), source, versions)
+
+	// Until the published versions are listed, the page is served without
+	// them.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/config", nil))
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), `<option value="v0.1.0">`) {
+		t.Errorf("GET /config while listing versions: got status %d, want %d without versions", w.Code, http.StatusOK)
+	}
+	listed <- true
+	for len(versions.published()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for _, test := range []struct {
+		query     string
+		code      int
+		fragments []string
+	}{
+		{"", 200, []string{"Upload Config Changes", `<option value="v0.2.0"><option value="v0.1.0">`}},
+		{"from=v0.1.0&to=v0.2.0", 200, []string{"Changes from v0.1.0 to v0.2.0", "cmd/compile", "(added)"}},
+		{"from=v0.1.0", 200, []string{"Changes from v0.1.0 to v0.2.0"}},
+		{"from=v0.2.0&to=v0.2.0", 200, []string{"No changes."}},
+		{"to=v0.2.0", 400, nil},
+		{"from=main", 400, nil},
+		{"from=v0.3.0", 404, nil},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/config?"+test.query, nil))
+		if w.Code != test.code {
+			t.Errorf("GET /config?%s: got status %d, want %d", test.query, w.Code, test.code)
+			continue
+		}
+		for _, f := range test.fragments {
+			if !strings.Contains(w.Body.String(), f) {
+				t.Errorf("GET /config?%s: missing fragment %q", test.query, f)
+			}
+		}
+	}
+	// v0.1.0 and v0.2.0 are downloaded once, and latest each time it is
+	// requested, along with the failed v0.3.0.
+	if want := 4; downloads != want {
+		t.Errorf("got %d downloads, want %d", downloads, want)
+	}
+}
@@ -15,23 +15,32 @@ import (
 	"io"
 	"io/fs"
 	"log"
+	"maps"
 	"net/http"
-	"os"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"golang.org/x/exp/slog"
 	"golang.org/x/mod/semver"
+	"golang.org/x/telemetry/godev/internal/charts"
 	"golang.org/x/telemetry/godev/internal/config"
 	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/flags"
+	"golang.org/x/telemetry/godev/internal/health"
 	ilog "golang.org/x/telemetry/godev/internal/log"
 	"golang.org/x/telemetry/godev/internal/middleware"
+	"golang.org/x/telemetry/godev/internal/server"
 	"golang.org/x/telemetry/godev/internal/storage"
 	"golang.org/x/telemetry/internal/chartconfig"
 	tconfig "golang.org/x/telemetry/internal/config"
 	contentfs "golang.org/x/telemetry/internal/content"
 	"golang.org/x/telemetry/internal/telemetry"
 	"golang.org/x/telemetry/internal/unionfs"
+	"golang.org/x/telemetry/internal/uploadable"
 )
 
 func main() {
@@ -47,12 +56,28 @@ func main() {
 	handler := newHandler(ctx, cfg)
 
 	fmt.Printf("server listening at http://:%s\n", cfg.ServerPort)
-	log.Fatal(http.ListenAndServe(":"+cfg.ServerPort, handler))
+	if err := server.Serve(ctx, ":"+cfg.ServerPort, handler, cfg.DrainTimeout); err != nil {
+		log.Fatal(err)
+	}
 }
 
+// maintenanceRetryAfter is the delay uploaders are asked to wait before
+// retrying while uploads are paused for maintenance. Uploaders try at most
+// once a day regardless, so this is advisory.
+const maintenanceRetryAfter = time.Hour
+
 // renderer implements shared template rendering for handlers below.
 type renderer func(w http.ResponseWriter, tmpl string, page any) error
 
+// newRenderer returns a renderer of the templates of fsys, which caches the
+// parsed templates.
+func newRenderer(fsys fs.FS) renderer {
+	tmpls := content.NewTemplateCache(fsys)
+	return func(w http.ResponseWriter, tmpl string, page any) error {
+		return tmpls.Template(w, tmpl, page, http.StatusOK)
+	}
+}
+
 func newHandler(ctx context.Context, cfg *config.Config) http.Handler {
 	buckets, err := storage.NewAPI(ctx, cfg)
 	if err != nil {
@@ -62,28 +87,69 @@ func newHandler(ctx context.Context, cfg *config.Config) http.Handler {
 	if err != nil {
 		log.Fatal(err)
 	}
+	ucfgSource := newUploadConfigSource(ucfg, slog.Default())
+	if cfg.ConfigRefreshMinutes > 0 {
+		go ucfgSource.refresh(ctx, time.Duration(cfg.ConfigRefreshMinutes)*time.Minute)
+	}
+	ccfgs, err := chartconfig.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
 	fsys := fsys(cfg.DevMode)
 	mux := http.NewServeMux()
 
-	render := func(w http.ResponseWriter, tmpl string, page any) error {
-		return content.Template(w, fsys, tmpl, page, http.StatusOK)
-	}
+	render := newRenderer(fsys)
 
 	logger := slog.Default()
+	screen := newUploadScreen()
+	flagSource := flags.NewSource(cfg.FlagsFile, flags.Flags{Maintenance: cfg.Maintenance}, logger)
+	maintenance := middleware.Maintenance(func() bool {
+		return flagSource.Flags().Maintenance
+	}, maintenanceRetryAfter)
+	metrics := middleware.NewMetrics()
+	route := metrics.Instrument
 	// TODO(rfindley): use Go 1.22 routing once 1.23 is released and we can bump
 	// the go directive to 1.22.
-	mux.Handle("/", handleRoot(render, fsys, buckets.Chart, logger))
-	mux.Handle("/config", handleConfig(fsys, ucfg))
+	mux.Handle("/", route("root")(handleRoot(render, fsys, buckets.Chart, logger)))
+	mux.Handle("/config", route("config")(handleConfig(render, ucfgSource, newConfigVersions())))
+	uploadLimit := middleware.RateLimit(clientAddr,
+		middleware.Rate{N: int(cfg.UploadRatePerClient), Per: time.Minute},
+		middleware.Rate{N: int(cfg.UploadRate), Per: time.Minute})
 	// TODO(rfindley): restrict this routing to POST
-	mux.Handle("/upload/", handleUpload(ucfg, buckets.Upload))
-	mux.Handle("/charts/", handleCharts(render, buckets.Chart))
-	mux.Handle("/data/", handleData(render, buckets.Merge))
-
+	mux.Handle("/upload/", route("upload")(maintenance(uploadLimit(handleUpload(ucfgSource.Config, reportWindow(cfg), buckets.Upload, screen, logger)))))
+	// Charts for ranges that were not precomputed are aggregated on demand,
+	// which reads the merged reports of every day in the range.
+	chartLimit := middleware.ConcurrencyLimit(int(cfg.MaxConcurrentCharts), cfg.RetryAfter)
+	agg := newAggregator(ucfg, charts.NewRules(ccfgs), buckets.Merge, int(cfg.MaxAggregateDays))
+	mux.Handle("/charts/", route("charts")(handleCharts(render, buckets.Chart, chartLimit(handleChartRange(render, buckets.Chart, agg)))))
+	mux.Handle("/api/v1/", route("api")(handleAPI(buckets.Chart, chartLimit(handleAPIRange(buckets.Chart, agg.charts)))))
+	mux.Handle("/data/", route("data")(handleData(render, buckets.Merge)))
+	mux.Handle("/newcounters/", route("newcounters")(handleNewCounters(buckets.Chart)))
+	mux.Handle("/search", route("search")(handleSearch(render, ucfgSource.Config, buckets.Chart)))
+	mux.Handle("/stats", route("stats")(handleStats(render, buckets.Stats)))
+	mux.Handle("/ops", route("ops")(handleOps(render, screen, flagSource, buckets.Chart, cfg.AdminToken)))
+	mux.Handle("/admin/reload-config", route("reload-config")(handleReloadConfig(ucfgSource, cfg.AdminToken)))
+	mux.Handle("/admin/remove-report", route("remove-report")(handleRemoveReport(cfg, buckets, agg, logger)))
+	mux.Handle("/healthz", health.Live())
+	mux.Handle("/readyz", health.Ready(append(health.Buckets(buckets), health.UploadConfig(cfg.UploadConfig))...))
+	mux.Handle("/metrics", health.Metrics(metrics))
+
+	var csrf middleware.Middleware = func(h http.Handler) http.Handler { return h }
+	if cfg.CSRFProtection {
+		// Uploads are made by the telemetry clients of the go command, not
+		// browsers.
+		csrf = middleware.CSRF("/upload/")
+	}
 	mw := middleware.Chain(
+		metrics.Middleware(),
+		middleware.RequestID(),
+		middleware.SecurityHeaders(cfg.ContentSecurityPolicy),
 		middleware.Log(logger),
 		middleware.Timeout(cfg.RequestTimeout),
 		middleware.RequestSize(cfg.MaxRequestBytes),
 		middleware.Recover(),
+		csrf,
+		content.ErrorPages(fsys),
 	)
 	return mw(mux)
 }
@@ -116,38 +182,9 @@ func handleRoot(render renderer, fsys fs.FS, chartBucket storage.BucketHandle, l
 		page := indexPage{}
 
 		ctx := r.Context()
-		var (
-			chartDate string // end date of chart data
-			chartObj  string // object name of chart file
-		)
-		it := chartBucket.Objects(ctx, "")
-		for {
-			obj, err := it.Next()
-			if errors.Is(err, storage.ErrObjectIteratorDone) {
-				break
-			} else if err != nil {
-				return err
-			}
-			date := strings.TrimSuffix(obj, ".json")
-			if date == obj {
-				// We have discussed eventually have nested subdirectories in the
-				// charts bucket. Defensively check for json files.
-				continue // not a chart object
-			}
-			// Chart objects may be for a single date (<date>.json), or for a date
-			// span (<start>_<end>.json).
-			_, end, aggregate := strings.Cut(date, "_")
-			if aggregate {
-				date = end
-			}
-			if date >= chartDate {
-				chartDate = date
-				// Prefer aggregate charts to daily charts, but consider the latest
-				// available date.
-				if aggregate || date > chartDate {
-					chartObj = obj
-				}
-			}
+		chartObj, err := latestChartObject(ctx, chartBucket)
+		if err != nil {
+			return err
 		}
 		if chartObj == "" {
 			page.ChartError = "No data."
@@ -165,6 +202,46 @@ func handleRoot(render renderer, fsys fs.FS, chartBucket storage.BucketHandle, l
 	}
 }
 
+// latestChartObject returns the name of the chart object shown on the home
+// page, which holds the latest charts, or "" if there is none.
+func latestChartObject(ctx context.Context, chartBucket storage.BucketHandle) (string, error) {
+	var (
+		chartDate string // end date of chart data
+		chartObj  string // object name of chart file
+	)
+	it := chartBucket.Objects(ctx, "")
+	for {
+		obj, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		date := strings.TrimSuffix(obj, ".json")
+		if date == obj || strings.Contains(obj, "/") {
+			// The charts bucket also holds other data in nested
+			// subdirectories, such as new counter indexes. Defensively check
+			// for top-level json files.
+			continue // not a chart object
+		}
+		// Chart objects may be for a single date (<date>.json), or for a date
+		// span (<start>_<end>.json).
+		_, end, aggregate := strings.Cut(date, "_")
+		if aggregate {
+			date = end
+		}
+		if date >= chartDate {
+			chartDate = date
+			// Prefer aggregate charts to daily charts, but consider the latest
+			// available date.
+			if aggregate || date > chartDate {
+				chartObj = obj
+			}
+		}
+	}
+	return chartObj, nil
+}
+
 func chartTitle(objName string) string {
 	start, end, aggregate := strings.Cut(strings.TrimSuffix(objName, ".json"), "_")
 	if aggregate {
@@ -173,20 +250,144 @@ func chartTitle(objName string) string {
 	return fmt.Sprintf("Charts for %s", start)
 }
 
-type chartsPage []string
+// chartsPerPage is the number of chart objects listed on each page of
+// /charts/.
+const chartsPerPage = 100
+
+// chartsPage lists the chart objects selected by the program, from, and to
+// query parameters, a page at a time. With format=json, it is served as
+// JSON.
+type chartsPage struct {
+	Program string // program name or path, applied to the linked charts
+	From    string // earliest end date (YYYY-MM-DD) of the listed charts, or ""
+	To      string // latest start date (YYYY-MM-DD) of the listed charts, or ""
+
+	Charts   []chartListing
+	Page     int    // 1-based
+	NumPages int    // at least 1
+	Prev     string `json:",omitempty"` // URL of the previous page, or ""
+	Next     string `json:",omitempty"` // URL of the next page, or ""
+}
+
+// A chartListing is an entry in the list of chart objects.
+type chartListing struct {
+	Name  string // object name, without .json: <date> or <start>_<end>
+	Start string
+	End   string
+	URL   string
+}
 
 func (chartsPage) Breadcrumbs() []breadcrumb {
 	return []breadcrumb{{Link: "/", Label: "Go Telemetry"}, {Label: "Charts"}}
 }
 
-func handleCharts(render renderer, chartBucket storage.BucketHandle) content.HandlerFunc {
+// listCharts returns the page of the list of chart objects selected by the
+// query. The objects are listed newest first.
+func listCharts(objs []string, q url.Values) (*chartsPage, error) {
+	page := &chartsPage{
+		Program: q.Get("program"),
+		From:    q.Get("from"),
+		To:      q.Get("to"),
+		Page:    1,
+	}
+	for _, d := range []string{page.From, page.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse(telemetry.DateOnly, d); err != nil {
+			return nil, fmt.Errorf("invalid date %q: want YYYY-MM-DD", d)
+		}
+	}
+	if p := q.Get("page"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid page %q", p)
+		}
+		page.Page = n
+	}
+	var listings []chartListing
+	for _, obj := range objs {
+		name := strings.TrimSuffix(obj, ".json")
+		start, end, ok := strings.Cut(name, "_")
+		if !ok {
+			end = start
+		}
+		if page.From != "" && end < page.From || page.To != "" && start > page.To {
+			continue
+		}
+		u := "/charts/" + name
+		if page.Program != "" {
+			u += "?" + url.Values{"program": {page.Program}}.Encode()
+		}
+		listings = append(listings, chartListing{Name: name, Start: start, End: end, URL: u})
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		if listings[i].End != listings[j].End {
+			return listings[i].End > listings[j].End
+		}
+		return listings[i].Start > listings[j].Start // longer ranges first
+	})
+	page.NumPages = max(1, (len(listings)+chartsPerPage-1)/chartsPerPage)
+	first := min((page.Page-1)*chartsPerPage, len(listings))
+	page.Charts = listings[first:min(first+chartsPerPage, len(listings))]
+	link := func(n int) string {
+		lq := url.Values{}
+		for _, k := range []string{"program", "from", "to", "format"} {
+			if v := q.Get(k); v != "" {
+				lq.Set(k, v)
+			}
+		}
+		lq.Set("page", strconv.Itoa(n))
+		return "/charts/?" + lq.Encode()
+	}
+	if page.Page > 1 {
+		page.Prev = link(min(page.Page-1, page.NumPages))
+	}
+	if page.Page < page.NumPages {
+		page.Next = link(page.Page + 1)
+	}
+	return page, nil
+}
+
+// filterPrograms returns charts, the decoded JSON of a chart object,
+// without the charts of programs other than program, if program is set. A
+// program matches by its path, or by its last path element, so that "gopls"
+// selects golang.org/x/tools/gopls.
+//
+// charts is not modified, as it may be shared with other requests: the
+// result is a shallow copy.
+func filterPrograms(charts map[string]any, program string) map[string]any {
+	if program == "" {
+		return charts
+	}
+	progs, _ := charts["Programs"].([]any)
+	var keep []any
+	for _, p := range progs {
+		name, _ := p.(map[string]any)["Name"].(string)
+		if name == program || path.Base(name) == program {
+			keep = append(keep, p)
+		}
+	}
+	filtered := maps.Clone(charts)
+	filtered["Programs"] = keep
+	return filtered
+}
+
+// handleCharts serves the list of charts, the charts for a single chart
+// object, and, if the start and end query parameters are set, the charts for
+// an arbitrary date range, using rangeHandler.
+func handleCharts(render renderer, chartBucket storage.BucketHandle, rangeHandler http.Handler) content.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
+		if q := r.URL.Query(); q.Has("start") || q.Has("end") {
+			rangeHandler.ServeHTTP(w, r)
+			return nil
+		}
 		if p := strings.TrimPrefix(r.URL.Path, "/charts/"); p != "" {
-			return handleChart(ctx, w, p, render, chartBucket)
+			return handleChart(w, r, p, render, chartBucket)
 		}
 		it := chartBucket.Objects(ctx, "")
-		var page chartsPage
+		var objs []string
 		for {
 			obj, err := it.Next()
 			if errors.Is(err, storage.ErrObjectIteratorDone) {
@@ -194,11 +395,17 @@ func handleCharts(render renderer, chartBucket storage.BucketHandle) content.Han
 			} else if err != nil {
 				return err
 			}
-			date := strings.TrimSuffix(obj, ".json")
-			if date == obj {
+			if !strings.HasSuffix(obj, ".json") || strings.Contains(obj, "/") {
 				continue // not a chart object
 			}
-			page = append(page, date)
+			objs = append(objs, obj)
+		}
+		page, err := listCharts(objs, r.URL.Query())
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		if r.URL.Query().Get("format") == "json" {
+			return content.JSON(w, page, http.StatusOK)
 		}
 		return render(w, "allcharts.html", page)
 	}
@@ -218,8 +425,10 @@ func (p chartPage) Breadcrumbs() []breadcrumb {
 	}
 }
 
-func handleChart(ctx context.Context, w http.ResponseWriter, date string, render renderer, chartBucket storage.BucketHandle) error {
+func handleChart(w http.ResponseWriter, r *http.Request, date string, render renderer, chartBucket storage.BucketHandle) error {
 	// TODO(rfindley): refactor to return a content.HandlerFunc once we can use Go 1.22 routing.
+	ctx := r.Context()
+	q := r.URL.Query()
 	page := chartPage{Date: date}
 	var err error
 	objName := date + ".json"
@@ -230,23 +439,107 @@ func handleChart(ctx context.Context, w http.ResponseWriter, date string, render
 	} else if err != nil {
 		return err
 	}
+	page.Charts = filterPrograms(page.Charts, q.Get("program"))
+	if q.Get("format") == "json" {
+		return content.CachedJSON(w, r, page.Charts, time.Time{}, chartMaxAge)
+	}
 	return render(w, "charts.html", page)
 }
 
+// chartMaxAge is how long clients and caches may keep the JSON data of a
+// chart page. The worker recomputes the charts of recent dates daily.
+const chartMaxAge = time.Hour
+
 type dataPage struct {
-	BucketURL string
-	Dates     []string
+	Dates []string
+
+	// Weekly and Monthly are the snapshots of the merged reports of a week
+	// or a month, written by the worker.
+	Weekly, Monthly []snapshot
+}
+
+// A snapshot holds the merged reports of the dates from Start to End, in
+// objects of the merge bucket: Object, compressed with gzip, and Parquet, a
+// table of their counters. Either may be empty if the object is missing.
+type snapshot struct {
+	Object     string
+	Parquet    string
+	Start, End string
+	Days       int
+}
+
+// snapshotPrefix is the prefix of snapshot objects in the merge bucket.
+const snapshotPrefix = "snapshots/"
+
+// parseSnapshot parses the name of a snapshot object, which has the form
+// snapshots/<start>_<end>.json.gz or snapshots/<start>_<end>.parquet.
+func parseSnapshot(obj string) (snapshot, bool) {
+	rest, ok := strings.CutPrefix(obj, snapshotPrefix)
+	if !ok {
+		return snapshot{}, false
+	}
+	var snap snapshot
+	if r, ok := strings.CutSuffix(rest, ".json.gz"); ok {
+		rest, snap.Object = r, obj
+	} else if r, ok := strings.CutSuffix(rest, ".parquet"); ok {
+		rest, snap.Parquet = r, obj
+	} else {
+		return snapshot{}, false
+	}
+	start, end, ok := strings.Cut(rest, "_")
+	if !ok {
+		return snapshot{}, false
+	}
+	t0, err0 := time.Parse(telemetry.DateOnly, start)
+	t1, err1 := time.Parse(telemetry.DateOnly, end)
+	if err0 != nil || err1 != nil {
+		return snapshot{}, false
+	}
+	snap.Start, snap.End = start, end
+	snap.Days = int(t1.Sub(t0)/(24*time.Hour)) + 1
+	return snap, true
+}
+
+// addSnapshot lists a snapshot object on the page, with the object of the
+// other format of the same dates, if it is listed.
+func (p *dataPage) addSnapshot(snap snapshot) {
+	list := &p.Monthly
+	if snap.Days == 7 {
+		list = &p.Weekly
+	}
+	for i := range *list {
+		s := &(*list)[i]
+		if s.Start == snap.Start && s.End == snap.End {
+			if snap.Object != "" {
+				s.Object = snap.Object
+			}
+			if snap.Parquet != "" {
+				s.Parquet = snap.Parquet
+			}
+			return
+		}
+	}
+	*list = append(*list, snap)
 }
 
 func (dataPage) Breadcrumbs() []breadcrumb {
 	return []breadcrumb{{Link: "/", Label: "Go Telemetry"}, {Label: "Data"}}
 }
 
+// downloadTTL is how long the download links of merged reports stay valid.
+const downloadTTL = 15 * time.Minute
+
+// handleData serves the list of merged reports at /data/, and redirects
+// /data/<object> to a signed URL from which the object is downloaded
+// directly from the merge bucket. If the bucket can not sign URLs, as in
+// local development, the object is served instead.
 func handleData(render renderer, mergeBucket storage.BucketHandle) content.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
+		if obj := strings.TrimPrefix(r.URL.Path, "/data/"); obj != "" {
+			return serveDownload(w, r, mergeBucket, obj)
+		}
 		it := mergeBucket.Objects(r.Context(), "")
 		var page dataPage
-		page.BucketURL = mergeBucket.URI()
 		for {
 			obj, err := it.Next()
 			if errors.Is(err, storage.ErrObjectIteratorDone) {
@@ -254,9 +547,13 @@ func handleData(render renderer, mergeBucket storage.BucketHandle) content.Handl
 			} else if err != nil {
 				return err
 			}
-			date := strings.TrimSuffix(obj, ".json")
-			if date == obj {
-				continue // not a data object
+			if snap, ok := parseSnapshot(obj); ok {
+				page.addSnapshot(snap)
+				continue
+			}
+			date, ok := strings.CutSuffix(obj, ".json")
+			if _, err := time.Parse(telemetry.DateOnly, date); !ok || err != nil {
+				continue // not a data object, such as a BigQuery load file
 			}
 			page.Dates = append(page.Dates, date)
 		}
@@ -264,6 +561,43 @@ func handleData(render renderer, mergeBucket storage.BucketHandle) content.Handl
 	}
 }
 
+// serveDownload serves a download of a merged report or snapshot.
+func serveDownload(w http.ResponseWriter, r *http.Request, mergeBucket storage.BucketHandle, obj string) error {
+	if _, ok := parseSnapshot(obj); !ok {
+		date, ok := strings.CutSuffix(obj, ".json")
+		if _, err := time.Parse(telemetry.DateOnly, date); !ok || err != nil {
+			return content.Error(fmt.Errorf("not a merged report: %q", obj), http.StatusNotFound)
+		}
+	}
+	// Signing a URL does not check that the object exists.
+	if _, err := mergeBucket.Object(obj).Generation(r.Context()); err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return content.Error(err, http.StatusNotFound)
+		}
+		return err
+	}
+	u, err := mergeBucket.SignedURL(obj, downloadTTL)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://") {
+		http.Redirect(w, r, u, http.StatusFound)
+		return nil
+	}
+	rc, err := mergeBucket.Object(obj).NewReader(r.Context())
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if strings.HasSuffix(obj, ".gz") {
+		w.Header().Set("Content-Type", "application/gzip")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	_, err = io.Copy(w, rc)
+	return err
+}
+
 func loadCharts(ctx context.Context, chartObj string, bucket storage.BucketHandle) (map[string]any, error) {
 	reader, err := bucket.Object(chartObj).NewReader(ctx)
 	if err != nil {
@@ -274,36 +608,56 @@ func loadCharts(ctx context.Context, chartObj string, bucket storage.BucketHandl
 	if err != nil {
 		return nil, err
 	}
-	var charts map[string]any
-	if err := json.Unmarshal(data, &charts); err != nil {
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, err
 	}
-	return charts, nil
+	// Chart objects may have been written by an older worker.
+	if err := charts.Upgrade(result); err != nil {
+		return nil, fmt.Errorf("%s: %v", chartObj, err)
+	}
+	return result, nil
 }
 
-func handleUpload(ucfg *tconfig.Config, uploadBucket storage.BucketHandle) content.HandlerFunc {
+func handleUpload(ucfg func() *tconfig.Config, window uploadWindow, uploadBucket storage.BucketHandle, screen *uploadScreen, log *slog.Logger) content.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		if r.Method == "POST" {
 			ctx := r.Context()
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				return err
+			}
 			var report telemetry.Report
-			if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			if err := json.Unmarshal(body, &report); err != nil {
 				return content.Error(fmt.Errorf("invalid JSON payload: %v", err), http.StatusBadRequest)
 			}
-			if err := validate(&report, ucfg); err != nil {
+			dropped, err := validate(&report, ucfg(), window, screen.now())
+			if err != nil {
+				if reason := windowReason(err); reason != "" {
+					screen.rejected(reason)
+					log.InfoContext(ctx, "upload outside week window",
+						slog.String("week", report.Week),
+						slog.String("reason", reason))
+				}
 				return content.Error(fmt.Errorf("invalid report: %v", err), http.StatusBadRequest)
 			}
-			// TODO: capture metrics for collisions.
-			name := fmt.Sprintf("%s/%g.json", report.Week, report.X)
-			f, err := uploadBucket.Object(name).NewWriter(ctx)
+			screen.dropped(dropped)
+			// Suspect uploads are tagged as they are written, so that the
+			// merge never reads one before it is tagged.
+			var md map[string]string
+			if reasons := screen.check(&report, body, clientAddr(r)); len(reasons) > 0 {
+				md = map[string]string{storage.SuspectMetadata: strings.Join(reasons, ",")}
+			}
+			name, collision, err := writeUpload(ctx, uploadBucket, &report, md)
 			if err != nil {
 				return err
 			}
-			defer f.Close()
-			if err := json.NewEncoder(f).Encode(report); err != nil {
-				return err
-			}
-			if err := f.Close(); err != nil {
-				return err
+			if collision {
+				screen.collided()
+				log.WarnContext(ctx, "upload collision",
+					slog.String("week", report.Week),
+					slog.Float64("x", report.X),
+					slog.String("object", name))
 			}
 			return content.Status(w, http.StatusOK)
 		}
@@ -311,53 +665,167 @@ func handleUpload(ucfg *tconfig.Config, uploadBucket storage.BucketHandle) conte
 	}
 }
 
-// validate validates the telemetry report data against the latest config.
-func validate(r *telemetry.Report, cfg *tconfig.Config) error {
-	// TODO: reject/drop data arrived too early or too late.
-	if _, err := time.Parse(telemetry.DateOnly, r.Week); err != nil {
-		return fmt.Errorf("invalid week %s", r.Week)
+// maxCollisions is the number of reports with the same week and X that are
+// stored before further uploads of them are rejected.
+const maxCollisions = 100
+
+// writeUpload stores an uploaded report, with the custom metadata md, in
+// the object named by uploadName, and returns its name. The object is only
+// created if it does not exist, so that concurrent uploads of the same
+// report, which may race to the same name, are both kept: the upload that
+// loses the race takes the next name.
+func writeUpload(ctx context.Context, bucket storage.BucketHandle, report *telemetry.Report, md map[string]string) (name string, collision bool, _ error) {
+	for {
+		name, collision, err := uploadName(ctx, bucket, report)
+		if err != nil {
+			return "", false, err
+		}
+		err = writeUploadObject(ctx, bucket.Object(name), report, md)
+		if errors.Is(err, storage.ErrPreconditionFailed) {
+			continue
+		}
+		return name, collision, err
+	}
+}
+
+func writeUploadObject(ctx context.Context, obj storage.ObjectHandle, report *telemetry.Report, md map[string]string) error {
+	f, err := obj.NewWriterWithMetadata(ctx, 0, md)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(report); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// uploadName returns the name of the object in which to store an uploaded
+// report: <week>/<X>.json, or, if a report with the same week and X is
+// already stored, <week>/<X>-<n>.json for the first free n. X is random, so
+// collisions suggest clients that upload the same report more than once; the
+// duplicates are kept for the merge step's data quality checks rather than
+// overwriting the first upload.
+func uploadName(ctx context.Context, bucket storage.BucketHandle, report *telemetry.Report) (name string, collision bool, _ error) {
+	for n := 0; n <= maxCollisions; n++ {
+		name = fmt.Sprintf("%s/%g.json", report.Week, report.X)
+		if n > 0 {
+			name = fmt.Sprintf("%s/%g-%d.json", report.Week, report.X, n)
+		}
+		_, err := bucket.Object(name).Metadata(ctx)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return name, n > 0, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+	}
+	return "", false, content.Error(fmt.Errorf("too many uploads of report %s/%g", report.Week, report.X), http.StatusConflict)
+}
+
+// An uploadWindow bounds the weeks of the reports that the server accepts,
+// relative to the time of upload. Uploaders stop trying to upload reports
+// three weeks after their week ends, so anything much older, or from the
+// future, comes from a broken client clock or a misbehaving client.
+type uploadWindow struct {
+	maxAge    time.Duration // how long after its week ends a report is accepted
+	maxFuture time.Duration // how long before its week ends a report is accepted
+}
+
+func reportWindow(cfg *config.Config) uploadWindow {
+	return uploadWindow{
+		maxAge:    time.Duration(cfg.MaxReportAgeDays) * 24 * time.Hour,
+		maxFuture: time.Duration(cfg.MaxReportFutureDays) * 24 * time.Hour,
+	}
+}
+
+// Errors for reports whose week is outside the uploadWindow.
+var (
+	errTooEarly = errors.New("report arrived too early")
+	errTooLate  = errors.New("report arrived too late")
+)
+
+// windowReason returns the reason, for the ops page, for which validate
+// rejected a report with the error err because of its week, or "".
+func windowReason(err error) string {
+	switch {
+	case errors.Is(err, errTooEarly):
+		return rejectEarly
+	case errors.Is(err, errTooLate):
+		return rejectLate
+	}
+	return ""
+}
+
+// validate validates the telemetry report data against the latest config,
+// and checks that its week is within window of now.
+//
+// Programs and counters that the config does not include are removed from
+// the report, so that clients running tools with an older config still
+// contribute the data that remains valid. validate returns the number of
+// programs and counters removed for each reason, and an error only if the
+// report itself is malformed.
+func validate(r *telemetry.Report, cfg *tconfig.Config, window uploadWindow, now time.Time) (map[uploadable.Reason]int, error) {
+	week, err := time.Parse(telemetry.DateOnly, r.Week)
+	if err != nil {
+		return nil, fmt.Errorf("invalid week %s", r.Week)
+	}
+	if week.After(now.Add(window.maxFuture)) {
+		return nil, fmt.Errorf("%w: week %s", errTooEarly, r.Week)
+	}
+	if now.Sub(week) > window.maxAge {
+		return nil, fmt.Errorf("%w: week %s", errTooLate, r.Week)
 	}
 	if !semver.IsValid(r.Config) {
-		return fmt.Errorf("invalid config %s", r.Config)
+		return nil, fmt.Errorf("invalid config %s", r.Config)
 	}
 	if r.X == 0 {
-		return fmt.Errorf("invalid X %g", r.X)
+		return nil, fmt.Errorf("invalid X %g", r.X)
 	}
-	// TODO: We can probably keep known programs and counters even when a report
-	// includes something that has been removed from the latest config.
+	dropped := make(map[uploadable.Reason]int)
+	programs := r.Programs[:0]
 	for _, p := range r.Programs {
-		if !cfg.HasGOARCH(p.GOARCH) ||
-			!cfg.HasGOOS(p.GOOS) ||
-			!cfg.HasGoVersion(p.GoVersion) ||
-			!cfg.HasProgram(p.Program) ||
-			!cfg.HasVersion(p.Program, p.Version) {
-			return fmt.Errorf("unknown program build %s@%q %q %s/%s", p.Program, p.Version, p.GoVersion, p.GOOS, p.GOARCH)
-		}
-		for c := range p.Counters {
-			if !cfg.HasCounter(p.Program, c) {
-				return fmt.Errorf("unknown counter %s", c)
-			}
+		meta := uploadable.Meta{
+			Program:   p.Program,
+			Version:   p.Version,
+			GoVersion: p.GoVersion,
+			GOOS:      p.GOOS,
+			GOARCH:    p.GOARCH,
 		}
-		for s := range p.Stacks {
-			prefix, _, _ := strings.Cut(s, "\n")
-			if !cfg.HasStack(p.Program, prefix) {
-				return fmt.Errorf("unknown stack %s", s)
+		if d, reason := uploadable.DecideProgram(cfg, meta); d == uploadable.Drop {
+			dropped[reason]++
+			continue
+		}
+		for _, counts := range []map[string]int64{p.Counters, p.Stacks, p.Events} {
+			for name := range counts {
+				if d, reason := uploadable.Decide(cfg, meta, name); d == uploadable.Drop {
+					dropped[reason]++
+					delete(counts, name)
+				}
 			}
 		}
+		programs = append(programs, p)
 	}
-	return nil
+	r.Programs = programs
+	return dropped, nil
 }
 
+// fsys returns the content of the server. If fromOS is set, the content
+// files on disk, which may be edited while the server runs, shadow the
+// embedded content.
 func fsys(fromOS bool) fs.FS {
-	var f fs.FS = contentfs.FS
-	if fromOS {
-		f = os.DirFS("internal/content")
-		contentfs.RunESBuild(true)
-	}
-	f, err := unionfs.Sub(f, "telemetrygodev", "shared")
+	f, err := unionfs.Sub(contentfs.FS, "telemetrygodev", "shared")
 	if err != nil {
 		log.Fatal(err)
 	}
+	if fromOS {
+		contentfs.RunESBuild(true)
+		disk, err := unionfs.Sub(unionfs.DirFS("internal/content"), "telemetrygodev", "shared")
+		if err != nil {
+			log.Fatal(err)
+		}
+		f = append(disk, f...)
+	}
 	return f
 }
 
@@ -365,18 +833,30 @@ type configPage struct {
 	Version      string
 	ChartConfig  string
 	UploadConfig string
+
+	// From and To are the versions compared by Diff, as requested.
+	From, To string
+	Diff     *configDiff // nil unless requested
+	Versions []string    // published versions that may be compared, newest first
 }
 
 func (configPage) Breadcrumbs() []breadcrumb {
 	return []breadcrumb{{Link: "/", Label: "Go Telemetry"}, {Label: "Upload Configuration"}}
 }
 
-func handleConfig(fsys fs.FS, ucfg *tconfig.Config) content.HandlerFunc {
+// handleConfig serves the chart and upload configs. If the "from" query
+// parameter is set, it also shows the changes to the upload config from that
+// version of the config module to the version given by "to", which defaults
+// to the latest version.
+func handleConfig(render renderer, ucfg *uploadConfigSource, versions *configVersions) content.HandlerFunc {
 	ccfg := chartconfig.Raw()
-	cfg := ucfg.UploadConfig
-	version := "default"
 
 	return func(w http.ResponseWriter, r *http.Request) error {
+		cfg := ucfg.Config().UploadConfig
+		version := ucfg.Version()
+		if version == "" {
+			version = "default"
+		}
 		cfgJSON, err := json.MarshalIndent(cfg, "", "\t")
 		if err != nil {
 			cfgJSON = []byte("unknown")
@@ -385,7 +865,28 @@ func handleConfig(fsys fs.FS, ucfg *tconfig.Config) content.HandlerFunc {
 			Version:      version,
 			ChartConfig:  string(ccfg),
 			UploadConfig: string(cfgJSON),
+			From:         r.URL.Query().Get("from"),
+			To:           r.URL.Query().Get("to"),
+			Versions:     versions.published(),
+		}
+		if page.From == "" && page.To != "" {
+			return content.Error(fmt.Errorf("missing from version"), http.StatusBadRequest)
+		}
+		if page.From != "" {
+			if page.To == "" {
+				page.To = "latest"
+			}
+			from, fromVersion, err := versions.get(page.From)
+			if err != nil {
+				return err
+			}
+			to, toVersion, err := versions.get(page.To)
+			if err != nil {
+				return err
+			}
+			page.Diff = diffConfigs(from, to)
+			page.Diff.From, page.Diff.To = fromVersion, toVersion
 		}
-		return content.Template(w, fsys, "config.html", page, http.StatusOK)
+		return render(w, "config.html", page)
 	}
 }
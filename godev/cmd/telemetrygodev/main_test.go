@@ -9,20 +9,29 @@ import (
 	"context"
 	_ "embed"
 	"flag"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	"golang.org/x/exp/slog"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/telemetry/godev/internal/config"
+	"golang.org/x/telemetry/godev/internal/storage"
 	tconfig "golang.org/x/telemetry/internal/config"
 	"golang.org/x/telemetry/internal/telemetry"
 	"golang.org/x/telemetry/internal/testenv"
+	"golang.org/x/telemetry/internal/uploadable"
 )
 
 func TestMain(m *testing.M) {
@@ -87,6 +96,7 @@ func TestPaths(t *testing.T) {
 		cfg := config.NewConfig()
 		cfg.LocalStorage = t.TempDir()
 		cfg.ProjectID = "" // defensive: don't use a real project ID for tests.
+		cfg.AdminToken = "secret"
 
 		// NewConfig assumes that the command is run from the repo root, but tests
 		// run from their test directory. We should fix this, but for now just
@@ -106,13 +116,26 @@ func TestPaths(t *testing.T) {
 		code      int
 		fragments []string
 	}{
-		{"GET", "/", "", 200, []string{"Go Telemetry"}},
+		{"GET", "/", "", 200, []string{"Go Telemetry", `integrity="sha384-`}},
 		{"GET", "/privacy", "", 200, []string{"Privacy Policy"}},
 		{"GET", "/config", "", 200, []string{"Chart Config"}},
+		{"GET", "/ops", "", 401, nil}, // requires the admin token
+		{"GET", "/charts/", "", 200, []string{"Daily Charts", "No charts match."}},
+		{"GET", "/charts/?format=json", "", 200, []string{`"NumPages":1`}},
+		{"GET", "/charts/?from=yesterday", "", 400, nil},
+		{"GET", "/data/", "", 200, []string{"Merged daily reports"}},
+		{"GET", "/healthz", "", 200, []string{"ok"}},
+		{"GET", "/readyz", "", 200, []string{`"upload config":"ok"`}},
+		{"GET", "/metrics", "", 200, []string{`"Requests":`, `"root":{"Requests":`}},
+		{"GET", "/search?q=gotoolchain", "", 200, []string{"Search", "gopls/gotoolchain"}},
+		{"GET", "/stats", "", 200, []string{"Upload Statistics"}},
+		{"GET", "/stats?days=0", "", 400, []string{"invalid days"}},
+		// Browser requests that change state need a CSRF token.
+		{"POST", "/admin/reload-config", "", 403, []string{"CSRF"}},
 		{
 			"POST",
 			"/upload/2023-01-01/123.json",
-			`{"Week":"2023-01-01","LastWeek":"2022-12-25","X":0.123,"Programs":null,"Config":"v0.0.0-20230822160736-17171dbf1d76"}`,
+			`{"Week":"` + time.Now().Format(telemetry.DateOnly) + `","X":0.123,"Programs":null,"Config":"v0.0.0-20230822160736-17171dbf1d76"}`,
 			200,
 			nil, // the body returned by /upload doesn't matter
 		},
@@ -135,6 +158,9 @@ func TestPaths(t *testing.T) {
 			if resp.StatusCode != test.code {
 				t.Errorf("status code = %d, want %d", resp.StatusCode, test.code)
 			}
+			if got := resp.Header.Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("X-Content-Type-Options = %q, want %q", got, "nosniff")
+			}
 
 			content, err := io.ReadAll(resp.Body)
 			if err != nil {
@@ -154,10 +180,13 @@ func TestValidate(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	window := uploadWindow{maxAge: 21 * 24 * time.Hour, maxFuture: 24 * time.Hour}
+	now := time.Date(2023, 6, 20, 0, 0, 0, 0, time.UTC)
 	tests := []struct {
-		name    string
-		report  *telemetry.Report
-		wantErr bool
+		name        string
+		report      *telemetry.Report
+		wantErr     bool
+		wantDropped map[uploadable.Reason]int
 	}{
 		{
 			name:    "empty report",
@@ -217,12 +246,359 @@ func TestValidate(t *testing.T) {
 				Config: "v0.0.1-test",
 			},
 		},
+		{
+			name: "valid report with an event",
+			report: &telemetry.Report{
+				Week: "2023-06-15",
+				X:    1.0,
+				Programs: []*telemetry.ProgramReport{
+					{
+						Program:   "cmd/go",
+						Version:   "v0.10.1",
+						GoVersion: "go1.20.1",
+						GOOS:      "linux",
+						GOARCH:    "arm64",
+						Events: map[string]int64{
+							"go/toolchain/switch{to=go1.20.1}": 1,
+						},
+					},
+				},
+				Config: "v0.0.1-test",
+			},
+		},
+		{
+			name: "report with an unknown event value",
+			report: &telemetry.Report{
+				Week: "2023-06-15",
+				X:    1.0,
+				Programs: []*telemetry.ProgramReport{
+					{
+						Program:   "cmd/go",
+						Version:   "v0.10.1",
+						GoVersion: "go1.20.1",
+						GOOS:      "linux",
+						GOARCH:    "arm64",
+						Events: map[string]int64{
+							"go/toolchain/switch{to=go1.99}": 1,
+						},
+					},
+				},
+				Config: "v0.0.1-test",
+			},
+			wantDropped: map[uploadable.Reason]int{uploadable.UnknownCounter: 1},
+		},
+		{
+			name: "report with a retired program version",
+			report: &telemetry.Report{
+				Week:   "2023-06-15",
+				X:      0.1,
+				Config: "v0.0.1-test",
+				Programs: []*telemetry.ProgramReport{
+					{
+						Program:   "golang.org/x/tools/gopls",
+						Version:   "v0.1.0",
+						GoVersion: "go1.20.1",
+						GOOS:      "linux",
+						GOARCH:    "arm64",
+						Counters:  map[string]int64{"editor:vim": 1},
+					},
+					{
+						Program:   "golang.org/x/tools/gopls",
+						Version:   "v0.10.1",
+						GoVersion: "go1.20.1",
+						GOOS:      "linux",
+						GOARCH:    "arm64",
+						Counters:  map[string]int64{"editor:vim": 1, "editor:retired": 1},
+					},
+				},
+			},
+			wantDropped: map[uploadable.Reason]int{uploadable.UnknownVersion: 1, uploadable.UnknownCounter: 1},
+		},
+		{
+			name:    "report from the future",
+			report:  &telemetry.Report{Week: "2023-06-22", X: 0.1, Config: "v0.0.1-test"},
+			wantErr: true,
+		},
+		{
+			name:    "report from the end of the window",
+			report:  &telemetry.Report{Week: "2023-05-30", X: 0.1, Config: "v0.0.1-test"},
+			wantErr: false,
+		},
+		{
+			name:    "stale report",
+			report:  &telemetry.Report{Week: "2023-05-29", X: 0.1, Config: "v0.0.1-test"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := validate(tt.report, cfg); (err != nil) != tt.wantErr {
+			dropped, err := validate(tt.report, cfg, window, now)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if err == nil && len(dropped)+len(tt.wantDropped) > 0 && !reflect.DeepEqual(dropped, tt.wantDropped) {
+				t.Errorf("validate() dropped %v, want %v", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestListCharts(t *testing.T) {
+	objs := []string{"2024-01-01.json", "2024-01-02.json", "2024-01-01_2024-01-07.json", "2024-02-01.json"}
+	names := func(p *chartsPage) []string {
+		var names []string
+		for _, c := range p.Charts {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	page, err := listCharts(objs, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(page), []string{"2024-02-01", "2024-01-01_2024-01-07", "2024-01-02", "2024-01-01"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("listCharts() = %v, want %v", got, want)
+	}
+
+	// The aggregate chart overlaps the selected range, so it is included.
+	page, err = listCharts(objs, url.Values{"from": {"2024-01-03"}, "to": {"2024-01-31"}, "program": {"gopls"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(page), []string{"2024-01-01_2024-01-07"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("listCharts(from, to) = %v, want %v", got, want)
+	}
+	if got, want := page.Charts[0].URL, "/charts/2024-01-01_2024-01-07?program=gopls"; got != want {
+		t.Errorf("listCharts(program) URL = %s, want %s", got, want)
+	}
+
+	// Pagination.
+	var many []string
+	for i := 0; i < chartsPerPage+1; i++ {
+		many = append(many, fmt.Sprintf("2024-%02d-%02d.json", 1+i/28, 1+i%28))
+	}
+	page, err = listCharts(many, url.Values{"page": {"2"}, "format": {"json"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Charts) != 1 || page.NumPages != 2 || page.Next != "" || page.Prev != "/charts/?format=json&page=1" {
+		t.Errorf("listCharts(page 2) = %d charts of %d pages, prev %q, next %q", len(page.Charts), page.NumPages, page.Prev, page.Next)
+	}
+
+	if _, err := listCharts(objs, url.Values{"page": {"0"}}); err == nil {
+		t.Errorf("listCharts(page=0) succeeded unexpectedly")
+	}
+}
+
+func TestFilterPrograms(t *testing.T) {
+	charts := map[string]any{
+		"Programs": []any{
+			map[string]any{"Name": "golang.org/x/tools/gopls"},
+			map[string]any{"Name": "cmd/go"},
+		},
+	}
+	filtered := filterPrograms(charts, "gopls")
+	want := []any{map[string]any{"Name": "golang.org/x/tools/gopls"}}
+	if got := filtered["Programs"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterPrograms(gopls) = %v, want %v", got, want)
+	}
+	if got := len(charts["Programs"].([]any)); got != 2 {
+		t.Errorf("filterPrograms modified its argument: %d programs, want 2", got)
+	}
+}
+
+func TestUploadCollision(t *testing.T) {
+	ctx := context.Background()
+	cfg, err := tconfig.ReadConfig("testdata/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen := newUploadScreen()
+	screen.now = func() time.Time { return time.Date(2023, 6, 16, 0, 0, 0, 0, time.UTC) }
+	h := handleUpload(func() *tconfig.Config { return cfg }, uploadWindow{maxAge: 21 * 24 * time.Hour}, bucket, screen, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	body := `{"Week":"2023-06-15","X":0.25,"Config":"v0.0.1-test"}`
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/upload/2023-06-15/0.25.json", strings.NewReader(body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("upload %d: status %d", i, w.Code)
+		}
+	}
+	var got []string
+	it := bucket.Objects(ctx, "2023-06-15")
+	for {
+		obj, err := it.Next()
+		if err == storage.ErrObjectIteratorDone {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, obj)
+	}
+	sort.Strings(got)
+	want := []string{"2023-06-15/0.25-1.json", "2023-06-15/0.25-2.json", "2023-06-15/0.25.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stored objects = %v, want %v", got, want)
+	}
+	if got := screen.page().Collisions; got != 2 {
+		t.Errorf("page().Collisions = %d, want 2", got)
+	}
+}
+
+func TestConcurrentUploads(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Uploads of the same report race to the same names, but none of them
+	// replaces another.
+	const n = 8
+	names := make(chan string, n)
+	var g errgroup.Group
+	for i := 0; i < n; i++ {
+		g.Go(func() error {
+			name, _, err := writeUpload(ctx, bucket, &telemetry.Report{Week: "2023-06-15", X: 0.25}, nil)
+			names <- name
+			return err
 		})
 	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	close(names)
+	seen := make(map[string]bool)
+	for name := range names {
+		if seen[name] {
+			t.Errorf("two uploads were stored in %s", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestParseSnapshot(t *testing.T) {
+	for _, test := range []struct {
+		obj  string
+		want snapshot
+		ok   bool
+	}{
+		{"snapshots/2024-01-01_2024-01-07.json.gz", snapshot{"snapshots/2024-01-01_2024-01-07.json.gz", "", "2024-01-01", "2024-01-07", 7}, true},
+		{"snapshots/2024-02-01_2024-02-29.json.gz", snapshot{"snapshots/2024-02-01_2024-02-29.json.gz", "", "2024-02-01", "2024-02-29", 29}, true},
+		{"snapshots/2024-01-01_2024-01-07.parquet", snapshot{"", "snapshots/2024-01-01_2024-01-07.parquet", "2024-01-01", "2024-01-07", 7}, true},
+		{"2024-01-01.json", snapshot{}, false},
+		{"snapshots/2024-01-01.json.gz", snapshot{}, false},
+		{"snapshots/2024-01-01_latest.json.gz", snapshot{}, false},
+	} {
+		got, ok := parseSnapshot(test.obj)
+		if got != test.want || ok != test.ok {
+			t.Errorf("parseSnapshot(%q) = %+v, %t, want %+v, %t", test.obj, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestAddSnapshot(t *testing.T) {
+	var page dataPage
+	for _, obj := range []string{
+		"snapshots/2024-01-01_2024-01-07.json.gz",
+		"snapshots/2024-01-01_2024-01-07.parquet",
+		"snapshots/2024-01-01_2024-01-31.parquet",
+	} {
+		snap, ok := parseSnapshot(obj)
+		if !ok {
+			t.Fatalf("parseSnapshot(%q) failed", obj)
+		}
+		page.addSnapshot(snap)
+	}
+	wantWeekly := []snapshot{{"snapshots/2024-01-01_2024-01-07.json.gz", "snapshots/2024-01-01_2024-01-07.parquet", "2024-01-01", "2024-01-07", 7}}
+	wantMonthly := []snapshot{{"", "snapshots/2024-01-01_2024-01-31.parquet", "2024-01-01", "2024-01-31", 31}}
+	if !reflect.DeepEqual(page.Weekly, wantWeekly) {
+		t.Errorf("Weekly = %+v, want %+v", page.Weekly, wantWeekly)
+	}
+	if !reflect.DeepEqual(page.Monthly, wantMonthly) {
+		t.Errorf("Monthly = %+v, want %+v", page.Monthly, wantMonthly)
+	}
+}
+
+// signingBucket is a bucket whose signed URLs are served by example.com.
+type signingBucket struct {
+	storage.BucketHandle
+}
+
+func (signingBucket) SignedURL(name string, ttl time.Duration) (string, error) {
+	return "https://example.com/" + name, nil
+}
+
+func TestDownload(t *testing.T) {
+	ctx := context.Background()
+	bucket := storage.NewMemBucket("merge")
+	for _, obj := range []string{"2024-01-01.json", "snapshots/2024-01-01_2024-01-07.json.gz"} {
+		w, err := bucket.Object(obj).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, obj)
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, test := range []struct {
+		bucket   storage.BucketHandle
+		path     string
+		code     int
+		location string // of redirects
+		body     string // of served objects
+	}{
+		{bucket, "/data/2024-01-01.json", 200, "", "2024-01-01.json"},
+		{bucket, "/data/snapshots/2024-01-01_2024-01-07.json.gz", 200, "", "snapshots/2024-01-01_2024-01-07.json.gz"},
+		{bucket, "/data/2024-01-02.json", 404, "", ""},
+		{bucket, "/data/manifests/2024-01-01.json", 404, "", ""},
+		{signingBucket{bucket}, "/data/2024-01-01.json", 302, "https://example.com/2024-01-01.json", ""},
+		{signingBucket{bucket}, "/data/2024-01-02.json", 404, "", ""},
+	} {
+		rec := httptest.NewRecorder()
+		handleData(nil, test.bucket).ServeHTTP(rec, httptest.NewRequest("GET", test.path, nil))
+		if rec.Code != test.code {
+			t.Errorf("GET %s: status = %d, want %d", test.path, rec.Code, test.code)
+			continue
+		}
+		if got := rec.Header().Get("Location"); got != test.location {
+			t.Errorf("GET %s: Location = %q, want %q", test.path, got, test.location)
+		}
+		if test.code == 200 && rec.Body.String() != test.body {
+			t.Errorf("GET %s: body = %q, want %q", test.path, rec.Body, test.body)
+		}
+	}
+}
+
+func TestDataDates(t *testing.T) {
+	ctx := context.Background()
+	bucket := storage.NewMemBucket("merge")
+	for _, obj := range []string{"2024-01-01.json", "2024-01-01.manifest", "2024-01-02.json", "bigquery/2024-01-02.json", "snapshots/2024-01-01_2024-01-07.json.gz"} {
+		w, err := bucket.Object(obj).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var page dataPage
+	render := func(w http.ResponseWriter, tmpl string, p any) error {
+		page = p.(dataPage)
+		return nil
+	}
+	rec := httptest.NewRecorder()
+	handleData(render, bucket).ServeHTTP(rec, httptest.NewRequest("GET", "/data/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /data/: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if want := []string{"2024-01-01", "2024-01-02"}; !reflect.DeepEqual(page.Dates, want) {
+		t.Errorf("Dates = %q, want %q", page.Dates, want)
+	}
 }
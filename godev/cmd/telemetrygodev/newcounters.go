@@ -0,0 +1,201 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// newCountersPrefix is the prefix of new counter index objects in the chart
+// bucket. These objects are written by the worker's /newcounters/ endpoint.
+const newCountersPrefix = "newcounters/"
+
+// newCounterIndex is the subset of the worker's new counter index used by the
+// server.
+type newCounterIndex struct {
+	Week     string
+	Programs []struct {
+		Program string
+		New     []string
+	}
+}
+
+// newCounterEntry lists the counters that a program reported for the first
+// time in the week ending on Week.
+type newCounterEntry struct {
+	Week     string
+	Program  string
+	Counters []string
+}
+
+// handleNewCounters serves the counters that first appeared in reports each
+// week, per program.
+//
+//   - /newcounters/ serves a JSON list of entries, newest first.
+//   - /newcounters/feed serves the same entries as an RSS feed.
+//
+// Both accept an optional "program" query parameter, which restricts the
+// results to a single program.
+func handleNewCounters(chartBucket storage.BucketHandle) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		program := r.URL.Query().Get("program")
+		entries, err := loadNewCounters(r.Context(), chartBucket, program)
+		if err != nil {
+			return err
+		}
+		switch p := strings.TrimPrefix(r.URL.Path, "/newcounters/"); p {
+		case "":
+			if entries == nil {
+				entries = []newCounterEntry{} // encode as [], not null
+			}
+			return content.JSON(w, entries, http.StatusOK)
+		case "feed":
+			return newCountersFeed(w, r, program, entries)
+		default:
+			return content.Status(w, http.StatusNotFound)
+		}
+	}
+}
+
+// loadNewCounters reads all new counter indexes from the chart bucket, and
+// returns their non-empty entries for the given program (or all programs, if
+// program is empty), sorted by descending week and then program.
+func loadNewCounters(ctx context.Context, chartBucket storage.BucketHandle, program string) ([]newCounterEntry, error) {
+	var entries []newCounterEntry
+	it := chartBucket.Objects(ctx, newCountersPrefix)
+	for {
+		obj, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if !strings.HasSuffix(obj, ".json") {
+			continue
+		}
+		index, err := readNewCounterIndex(ctx, chartBucket, obj)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range index.Programs {
+			if len(p.New) == 0 || program != "" && p.Program != program {
+				continue
+			}
+			entries = append(entries, newCounterEntry{
+				Week:     index.Week,
+				Program:  p.Program,
+				Counters: p.New,
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Week != entries[j].Week {
+			return entries[i].Week > entries[j].Week
+		}
+		return entries[i].Program < entries[j].Program
+	})
+	return entries, nil
+}
+
+func readNewCounterIndex(ctx context.Context, bucket storage.BucketHandle, obj string) (*newCounterIndex, error) {
+	reader, err := bucket.Object(obj).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	var index newCounterIndex
+	if err := json.NewDecoder(reader).Decode(&index); err != nil {
+		return nil, fmt.Errorf("decoding %s: %v", obj, err)
+	}
+	return &index, nil
+}
+
+// RSS 2.0 feed elements; see https://www.rssboard.org/rss-specification.
+type (
+	rss struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		Channel rssChannel `xml:"channel"`
+	}
+	rssChannel struct {
+		Title       string    `xml:"title"`
+		Link        string    `xml:"link"`
+		Description string    `xml:"description"`
+		Items       []rssItem `xml:"item"`
+	}
+	rssItem struct {
+		Title       string  `xml:"title"`
+		Link        string  `xml:"link"`
+		Description string  `xml:"description"`
+		GUID        rssGUID `xml:"guid"`
+		PubDate     string  `xml:"pubDate,omitempty"`
+	}
+	rssGUID struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+)
+
+// newCountersFeed writes entries as an RSS feed.
+func newCountersFeed(w http.ResponseWriter, r *http.Request, program string, entries []newCounterEntry) error {
+	scheme := "https"
+	if r.TLS == nil && strings.HasPrefix(r.Host, "localhost") {
+		scheme = "http"
+	}
+	base := scheme + "://" + r.Host
+
+	title := "Go Telemetry: new counters"
+	if program != "" {
+		title += " for " + program
+	}
+	feed := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       title,
+			Link:        base + "/newcounters/",
+			Description: "Counters reported for the first time in uploaded telemetry, by week and program.",
+		},
+	}
+	for _, e := range entries {
+		item := rssItem{
+			Title:       fmt.Sprintf("%s: %d new counters in the week ending %s", e.Program, len(e.Counters), e.Week),
+			Link:        base + "/newcounters/?program=" + url.QueryEscape(e.Program),
+			Description: strings.Join(e.Counters, "\n"),
+			GUID:        rssGUID{Value: e.Program + "@" + e.Week},
+		}
+		if t, err := time.Parse(telemetry.DateOnly, e.Week); err == nil {
+			item.PubDate = t.Format(time.RFC1123Z)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/rss+xml")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
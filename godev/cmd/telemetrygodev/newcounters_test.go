@@ -0,0 +1,94 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestNewCounters(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "charts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexes := map[string]string{
+		"newcounters/2024-01-07.json": `{"Week":"2024-01-07","Programs":[{"Program":"cmd/go","New":null,"Known":["a"]}]}`,
+		"newcounters/2024-01-14.json": `{"Week":"2024-01-14","Programs":[{"Program":"cmd/go","New":["b"],"Known":["a","b"]},{"Program":"gopls","New":["c","d"],"Known":["c","d"]}]}`,
+		"2024-01-14.json":             `{}`, // not an index
+	}
+	for name, data := range indexes {
+		w, err := bucket.Object(name).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := handleNewCounters(bucket)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d, want %d", url, w.Code, http.StatusOK)
+		}
+		return w
+	}
+
+	tests := []struct {
+		url  string
+		want []newCounterEntry
+	}{
+		{"/newcounters/", []newCounterEntry{
+			{Week: "2024-01-14", Program: "cmd/go", Counters: []string{"b"}},
+			{Week: "2024-01-14", Program: "gopls", Counters: []string{"c", "d"}},
+		}},
+		{"/newcounters/?program=gopls", []newCounterEntry{
+			{Week: "2024-01-14", Program: "gopls", Counters: []string{"c", "d"}},
+		}},
+		{"/newcounters/?program=unknown", []newCounterEntry{}},
+	}
+	for _, test := range tests {
+		var got []newCounterEntry
+		if err := json.Unmarshal(get(test.url).Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("GET %s mismatch (-want +got):\n%s", test.url, diff)
+		}
+	}
+
+	w := get("/newcounters/feed?program=cmd/go")
+	if got, want := w.Header().Get("Content-Type"), "application/rss+xml"; got != want {
+		t.Errorf("feed Content-Type = %q, want %q", got, want)
+	}
+	var feed rss
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(feed.Channel.Items); got != 1 {
+		t.Fatalf("feed has %d items, want 1:\n%s", got, w.Body)
+	}
+	if got, want := feed.Channel.Items[0].GUID.Value, "cmd/go@2024-01-14"; got != want {
+		t.Errorf("feed item GUID = %q, want %q", got, want)
+	}
+	if got, want := feed.Channel.Items[0].Description, "b"; got != want {
+		t.Errorf("feed item description = %q, want %q", got, want)
+	}
+}
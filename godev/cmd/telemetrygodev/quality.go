@@ -0,0 +1,92 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+// qualityPrefix is the prefix of data quality objects in the chart bucket.
+// These objects are written by the worker's /merge/ endpoint.
+const qualityPrefix = "quality/"
+
+// maxQualityDates is the number of dates of data quality shown on the ops
+// page.
+const maxQualityDates = 28
+
+// dataQuality holds indicators of the quality of the uploads for a date. See
+// the worker's type of the same name.
+type dataQuality struct {
+	Date string
+
+	Merged, Suspect, Malformed int
+	DuplicateX                 int
+	Programs, InvalidPrograms  int
+	Counters, InvalidCounters  int
+}
+
+// Uploads returns the number of uploads for the date, whether merged or not.
+func (q dataQuality) Uploads() int { return q.Merged + q.Suspect + q.Malformed }
+
+func (q dataQuality) SuspectRate() string   { return percent(q.Suspect, q.Uploads()) }
+func (q dataQuality) MalformedRate() string { return percent(q.Malformed, q.Uploads()) }
+func (q dataQuality) DuplicateRate() string { return percent(q.DuplicateX, q.Merged) }
+func (q dataQuality) InvalidProgramRate() string {
+	return percent(q.InvalidPrograms, q.Programs)
+}
+func (q dataQuality) InvalidCounterRate() string {
+	return percent(q.InvalidCounters, q.Counters)
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
+
+// loadQuality reads the data quality of the most recent n dates from the
+// chart bucket, newest first.
+func loadQuality(ctx context.Context, chartBucket storage.BucketHandle, n int) ([]dataQuality, error) {
+	var objs []string
+	it := chartBucket.Objects(ctx, qualityPrefix)
+	for {
+		obj, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(obj, ".json") {
+			objs = append(objs, obj)
+		}
+	}
+	// Object names end with the date, so they sort by date.
+	sort.Sort(sort.Reverse(sort.StringSlice(objs)))
+	objs = objs[:min(n, len(objs))]
+
+	var qs []dataQuality
+	for _, obj := range objs {
+		reader, err := chartBucket.Object(obj).NewReader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var q dataQuality
+		err = json.NewDecoder(reader).Decode(&q)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %v", obj, err)
+		}
+		qs = append(qs, q)
+	}
+	return qs, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestLoadQuality(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "chart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, date := range []string{"2024-01-02", "2024-01-03", "2024-01-01"} {
+		w, err := bucket.Object(qualityPrefix + date + ".json").NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := dataQuality{Date: date, Merged: 3, Suspect: 1, DuplicateX: 1}
+		if err := json.NewEncoder(w).Encode(q); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	qs, err := loadQuality(ctx, bucket, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 2 || qs[0].Date != "2024-01-03" || qs[1].Date != "2024-01-02" {
+		t.Fatalf("loadQuality returned %+v, want the two newest dates, newest first", qs)
+	}
+	q := qs[0]
+	for _, test := range []struct{ name, got, want string }{
+		{"SuspectRate", q.SuspectRate(), "25.0%"},
+		{"DuplicateRate", q.DuplicateRate(), "33.3%"},
+		{"InvalidProgramRate", q.InvalidProgramRate(), "-"},
+	} {
+		if test.got != test.want {
+			t.Errorf("%s() = %q, want %q", test.name, test.got, test.want)
+		}
+	}
+}
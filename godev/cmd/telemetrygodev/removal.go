@@ -0,0 +1,453 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/config"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+	bigquery "google.golang.org/api/bigquery/v2"
+)
+
+// exportPrefix and exportTable are the prefix of the BigQuery load files in
+// the merge bucket and the BigQuery table into which the worker exports
+// merged reports. They must match the worker's.
+const (
+	exportPrefix = "bigquery/"
+	exportTable  = "counters"
+)
+
+// removalPrefix is the prefix of the audit records of report removals in
+// the upload bucket. Uploaded reports are stored under their week, so the
+// records are never mistaken for reports.
+const removalPrefix = "removals/"
+
+// A removal is the audit record of the removal of the data of a report,
+// identified by its X, from storage.
+type removal struct {
+	Time time.Time
+	X    float64
+	Date string `json:",omitempty"` // if set, only data for this date was removed
+
+	Uploads   []string // deleted upload objects
+	Merged    []string // merge objects from which the report was removed
+	Snapshots []string // deleted snapshots that held the report
+	Charts    []string // deleted chart objects computed from the report
+	Exports   []string // deleted BigQuery load files that held the report
+
+	ExportedRows int64 // rows of the report deleted from the BigQuery table
+}
+
+// handleRemoveReport removes the data of the report whose X is given by the
+// "x" query parameter from the upload, merge, and chart buckets, and from
+// the BigQuery export, to honor a request for removal of the data. If the "date" query parameter is set,
+// only the data for that date is removed; otherwise all dates are searched.
+//
+// Like /admin/reload-config, it requires a POST request that carries the
+// admin token. Each removal is recorded in the upload bucket, and the record
+// is returned as JSON.
+func handleRemoveReport(cfg *config.Config, buckets *storage.API, agg *aggregator, log *slog.Logger) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if code := adminStatus(r, "POST", cfg.AdminToken); code != 0 {
+			return content.Status(w, code)
+		}
+		ctx := r.Context()
+		q := r.URL.Query()
+		x, err := strconv.ParseFloat(q.Get("x"), 64)
+		if err != nil {
+			return content.Error(fmt.Errorf("invalid x %q", q.Get("x")), http.StatusBadRequest)
+		}
+		date := q.Get("date")
+		if date != "" {
+			if _, err := time.Parse(telemetry.DateOnly, date); err != nil {
+				return content.Error(fmt.Errorf("invalid date %q: want YYYY-MM-DD", date), http.StatusBadRequest)
+			}
+		}
+		rm := &removal{Time: time.Now().UTC(), X: x, Date: date}
+		if err := removeReport(ctx, cfg, buckets, rm); err != nil {
+			return err
+		}
+		// Charts computed on demand from the merged reports may include the
+		// report.
+		agg.forget()
+		if err := writeRemoval(ctx, buckets.Upload, rm); err != nil {
+			return err
+		}
+		log.InfoContext(ctx, "removed report",
+			slog.Float64("x", x),
+			slog.String("date", date),
+			slog.Int("uploads", len(rm.Uploads)),
+			slog.Int("merged", len(rm.Merged)),
+			slog.Int("snapshots", len(rm.Snapshots)),
+			slog.Int("charts", len(rm.Charts)),
+			slog.Int("exports", len(rm.Exports)),
+			slog.Int64("exported_rows", rm.ExportedRows))
+		return content.JSON(w, rm, http.StatusOK)
+	}
+}
+
+// removeReport removes the data of the report described by rm, recording
+// the affected objects in rm.
+//
+// Charts cannot be edited, as they do not identify the reports they were
+// computed from, so the charts for each date from which the report was
+// removed are deleted. The worker's chart task recomputes them from the
+// remaining reports.
+//
+// The rows of the report are deleted from the BigQuery table, if a dataset
+// is configured, along with any load files of those dates that the worker
+// kept.
+func removeReport(ctx context.Context, cfg *config.Config, buckets *storage.API, rm *removal) error {
+	if cfg.BigQueryDataset != "" {
+		n, err := deleteExportedRows(ctx, cfg, rm)
+		if err != nil {
+			return fmt.Errorf("deleting rows from BigQuery: %w", err)
+		}
+		rm.ExportedRows = n
+	}
+	dates := make(map[string]bool) // dates from which the report was removed
+
+	// Uploads are stored as <week>/<X>.json, or <week>/<X>-<n>.json for
+	// repeated uploads; see uploadName.
+	prefix := ""
+	if rm.Date != "" {
+		prefix = rm.Date + "/"
+	}
+	uploads, err := listObjects(ctx, buckets.Upload, prefix)
+	if err != nil {
+		return err
+	}
+	x := fmt.Sprintf("%g", rm.X)
+	for _, obj := range uploads {
+		if strings.HasPrefix(obj, removalPrefix) {
+			continue
+		}
+		if !isUploadOf(obj, x) {
+			continue
+		}
+		if err := buckets.Upload.Delete(ctx, obj); err != nil {
+			return err
+		}
+		rm.Uploads = append(rm.Uploads, obj)
+		dates[path.Dir(obj)] = true
+	}
+
+	// Merge objects are named <date>.json, and hold a report per line.
+	var merged []string
+	if rm.Date != "" {
+		merged = []string{rm.Date + ".json"}
+	} else {
+		objs, err := listObjects(ctx, buckets.Merge, "")
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			if _, err := time.Parse(telemetry.DateOnly, strings.TrimSuffix(obj, ".json")); err == nil {
+				merged = append(merged, obj)
+			}
+		}
+	}
+	for _, obj := range merged {
+		removed, err := removeMerged(ctx, buckets.Merge, obj, rm.X)
+		if err != nil {
+			return err
+		}
+		if removed {
+			rm.Merged = append(rm.Merged, obj)
+			dates[strings.TrimSuffix(obj, ".json")] = true
+		}
+	}
+	if len(dates) == 0 {
+		return nil
+	}
+
+	// Load files are named bigquery/<date>.json, and hold rows of the
+	// merged reports of the date.
+	var sorted []string
+	for d := range dates {
+		sorted = append(sorted, d)
+	}
+	sort.Strings(sorted)
+	for _, d := range sorted {
+		obj := exportPrefix + d + ".json"
+		if err := buckets.Merge.Delete(ctx, obj); errors.Is(err, storage.ErrObjectNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		rm.Exports = append(rm.Exports, obj)
+	}
+
+	// Snapshots and charts cover a date, or a range of dates.
+	covers := func(start, end string) bool {
+		for d := range dates {
+			if start <= d && d <= end {
+				return true
+			}
+		}
+		return false
+	}
+	snapshots, err := listObjects(ctx, buckets.Merge, snapshotPrefix)
+	if err != nil {
+		return err
+	}
+	for _, obj := range snapshots {
+		snap, ok := parseSnapshot(obj)
+		if !ok || !covers(snap.Start, snap.End) {
+			continue
+		}
+		if err := buckets.Merge.Delete(ctx, obj); err != nil {
+			return err
+		}
+		rm.Snapshots = append(rm.Snapshots, obj)
+	}
+	charts, err := listObjects(ctx, buckets.Chart, "")
+	if err != nil {
+		return err
+	}
+	for _, obj := range charts {
+		name, ok := strings.CutSuffix(obj, ".json")
+		if !ok || strings.Contains(obj, "/") {
+			continue // not a chart object; see latestChartObject
+		}
+		start, end, ok := strings.Cut(name, "_")
+		if !ok {
+			end = start
+		}
+		if !covers(start, end) {
+			continue
+		}
+		if err := buckets.Chart.Delete(ctx, obj); err != nil {
+			return err
+		}
+		if err := buckets.Chart.Delete(ctx, obj+storage.DigestSuffix); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return err
+		}
+		rm.Charts = append(rm.Charts, obj)
+	}
+	return nil
+}
+
+// isUploadOf reports whether obj names an upload of the report whose X,
+// formatted with %g, is x.
+func isUploadOf(obj, x string) bool {
+	base := strings.TrimSuffix(path.Base(obj), ".json")
+	return base == x || strings.HasPrefix(base, x+"-")
+}
+
+// removeMerged rewrites the merge object name without the reports whose X
+// is x, reporting whether there were any. The other reports are kept
+// verbatim, and the digest of the object is updated.
+//
+// The object is written on the condition that it has not changed since it
+// was read, like a merge writes it, so that a removal and a concurrent merge
+// of the date cannot undo each other. The manifest of the merge object is
+// updated after it, so that the next merge of the date finds that the
+// object still matches its manifest.
+func removeMerged(ctx context.Context, bucket storage.BucketHandle, name string, x float64) (bool, error) {
+	obj := bucket.Object(name)
+	gen, err := obj.Generation(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	reader, err := obj.NewReader(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+	var kept bytes.Buffer
+	removed := 0
+	br := bufio.NewReader(reader)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var report struct{ X float64 }
+			if json.Unmarshal(line, &report) == nil && report.X == x {
+				removed++
+			} else {
+				kept.Write(line)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	if err := reader.Close(); err != nil {
+		return false, err
+	}
+	if removed == 0 {
+		return false, nil
+	}
+	writer, err := obj.NewWriterIf(ctx, gen)
+	if err != nil {
+		return false, err
+	}
+	defer writer.Close()
+	if _, err := writer.Write(kept.Bytes()); err != nil {
+		return false, err
+	}
+	if err := writer.Close(); errors.Is(err, storage.ErrPreconditionFailed) {
+		// A merge of the date replaced the object since it was read.
+		return false, content.Error(fmt.Errorf("removing report from %s: %w", name, err), http.StatusConflict)
+	} else if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256(kept.Bytes())
+	if err := storage.WriteDigest(ctx, bucket, name, sum[:]); err != nil {
+		return false, err
+	}
+	return true, removeFromManifest(ctx, bucket, strings.TrimSuffix(name, ".json"), fmt.Sprintf("%g", x), removed)
+}
+
+// A mergeManifest records the upload objects that the worker merged into
+// the merge object for a date. It is stored next to the merge object, as
+// <date>.manifest, and must match the worker's mergeManifest.
+type mergeManifest struct {
+	Merged         int
+	Objects        []string
+	Suspect        map[string]string `json:",omitempty"`
+	Malformed      []string          `json:",omitempty"`
+	UploadsDeleted bool              `json:",omitempty"`
+}
+
+// removeFromManifest updates the manifest of the merge object for date, if
+// any, after n reports whose X is x were removed from the object: it
+// decrements the number of merged reports, and drops the upload objects of
+// the report.
+func removeFromManifest(ctx context.Context, bucket storage.BucketHandle, date, x string, n int) error {
+	obj := bucket.Object(date + ".manifest")
+	r, err := obj.NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	var m mergeManifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return fmt.Errorf("invalid manifest for %s: %v", date, err)
+	}
+	if err := r.Close(); err != nil {
+		return err
+	}
+	m.Merged -= n
+	m.Objects = slices.DeleteFunc(m.Objects, func(obj string) bool { return isUploadOf(obj, x) })
+	m.Malformed = slices.DeleteFunc(m.Malformed, func(obj string) bool { return isUploadOf(obj, x) })
+	maps.DeleteFunc(m.Suspect, func(obj, _ string) bool { return isUploadOf(obj, x) })
+	out, err := obj.NewWriter(ctx)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := json.NewEncoder(out).Encode(&m); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// deleteExportedRows deletes the rows of the report described by rm from the
+// BigQuery table into which the worker exports merged reports, and returns
+// the number of rows deleted.
+func deleteExportedRows(ctx context.Context, cfg *config.Config, rm *removal) (int64, error) {
+	svc, err := bigquery.NewService(ctx)
+	if err != nil {
+		return 0, err
+	}
+	query := fmt.Sprintf("DELETE FROM `%s.%s.%s` WHERE x = @x", cfg.ProjectID, cfg.BigQueryDataset, exportTable)
+	params := []*bigquery.QueryParameter{{
+		Name:           "x",
+		ParameterType:  &bigquery.QueryParameterType{Type: "FLOAT64"},
+		ParameterValue: &bigquery.QueryParameterValue{Value: strconv.FormatFloat(rm.X, 'g', -1, 64)},
+	}}
+	if rm.Date != "" {
+		query += " AND date = @date"
+		params = append(params, &bigquery.QueryParameter{
+			Name:           "date",
+			ParameterType:  &bigquery.QueryParameterType{Type: "DATE"},
+			ParameterValue: &bigquery.QueryParameterValue{Value: rm.Date},
+		})
+	}
+	legacySQL := false
+	resp, err := svc.Jobs.Query(cfg.ProjectID, &bigquery.QueryRequest{
+		Query:           query,
+		UseLegacySql:    &legacySQL,
+		ParameterMode:   "NAMED",
+		QueryParameters: params,
+	}).Context(ctx).Do()
+	if err != nil {
+		return 0, err
+	}
+	// Results of a query that is not complete are waited for with
+	// GetQueryResults, which returns when the query completes or times out.
+	done, rows := resp.JobComplete, resp.NumDmlAffectedRows
+	for !done {
+		res, err := svc.Jobs.GetQueryResults(cfg.ProjectID, resp.JobReference.JobId).
+			Location(resp.JobReference.Location).MaxResults(0).Context(ctx).Do()
+		if err != nil {
+			return 0, err
+		}
+		done, rows = res.JobComplete, res.NumDmlAffectedRows
+	}
+	return rows, nil
+}
+
+// writeRemoval records rm in bucket, under removalPrefix.
+func writeRemoval(ctx context.Context, bucket storage.BucketHandle, rm *removal) error {
+	name := fmt.Sprintf("%s%s-%g.json", removalPrefix, rm.Time.Format("20060102T150405.000000000Z"), rm.X)
+	out, err := bucket.Object(name).NewWriter(ctx)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := json.NewEncoder(out).Encode(rm); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// listObjects returns the names of the objects in bucket with the given
+// prefix, sorted.
+func listObjects(ctx context.Context, bucket storage.BucketHandle, prefix string) ([]string, error) {
+	var objs []string
+	it := bucket.Objects(ctx, prefix)
+	for {
+		obj, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	sort.Strings(objs)
+	return objs, nil
+}
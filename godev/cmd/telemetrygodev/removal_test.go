@@ -0,0 +1,174 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/config"
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestRemoveReport(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	bucket := func(name string) storage.BucketHandle {
+		b, err := storage.NewFSBucket(ctx, dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	buckets := &storage.API{
+		Upload: bucket("upload"),
+		Merge:  bucket("merge"),
+		Chart:  bucket("chart"),
+		Stats:  bucket("stats"),
+	}
+	put := func(b storage.BucketHandle, name, data string) {
+		t.Helper()
+		w, err := b.Object(name).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(buckets.Upload, "2024-01-01/0.25.json", `{"Week":"2024-01-01","X":0.25}`)
+	put(buckets.Upload, "2024-01-01/0.25-1.json", `{"Week":"2024-01-01","X":0.25}`)
+	put(buckets.Upload, "2024-01-01/0.255.json", `{"Week":"2024-01-01","X":0.255}`)
+	put(buckets.Upload, "2024-01-02/0.5.json", `{"Week":"2024-01-02","X":0.5}`)
+	put(buckets.Merge, "2024-01-01.json", "{\"Week\":\"2024-01-01\",\"X\":0.25}\n{\"Week\":\"2024-01-01\",\"X\":0.255}\n{\"Week\":\"2024-01-01\",\"X\":0.25}\n")
+	put(buckets.Merge, "2024-01-02.json", "{\"Week\":\"2024-01-02\",\"X\":0.5}\n")
+	put(buckets.Merge, "2024-01-01.manifest", `{"Merged":3,"Objects":["2024-01-01/0.25-1.json","2024-01-01/0.25.json","2024-01-01/0.255.json","2024-01-01/0.25-2.json"],"Suspect":{"2024-01-01/0.25-2.json":"bot"},"UploadsDeleted":true}`)
+	put(buckets.Merge, "bigquery/2024-01-01.json", "")
+	put(buckets.Merge, "bigquery/2024-01-02.json", "")
+	put(buckets.Merge, "snapshots/2023-12-26_2024-01-01.json.gz", "")
+	put(buckets.Merge, "snapshots/2024-01-02_2024-01-08.json.gz", "")
+	put(buckets.Chart, "2024-01-01.json", "{}")
+	put(buckets.Chart, "2023-12-26_2024-01-01.json", "{}")
+	put(buckets.Chart, "2024-01-02.json", "{}")
+	put(buckets.Chart, "quality/2024-01-01.json", "{}")
+
+	h := handleRemoveReport(&config.Config{AdminToken: "secret"}, buckets, newAggregator(nil, nil, buckets.Merge, 1), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	remove := func(method, query string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, "/admin/remove-report?"+query, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+	for _, test := range []struct {
+		method, query string
+		want          int
+	}{
+		{"GET", "x=0.25", http.StatusMethodNotAllowed},
+		{"POST", "", http.StatusBadRequest},
+		{"POST", "x=0.25&date=yesterday", http.StatusBadRequest},
+	} {
+		if got := remove(test.method, test.query).Code; got != test.want {
+			t.Errorf("%s ?%s: got status %d, want %d", test.method, test.query, got, test.want)
+		}
+	}
+
+	w := remove("POST", "x=0.25")
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST ?x=0.25: got status %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var got removal
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := removal{
+		Time:      got.Time,
+		X:         0.25,
+		Uploads:   []string{"2024-01-01/0.25-1.json", "2024-01-01/0.25.json"},
+		Merged:    []string{"2024-01-01.json"},
+		Snapshots: []string{"snapshots/2023-12-26_2024-01-01.json.gz"},
+		Charts:    []string{"2023-12-26_2024-01-01.json", "2024-01-01.json"},
+		Exports:   []string{"bigquery/2024-01-01.json"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("removal mismatch (-want +got):\n%s", diff)
+	}
+
+	wantObjects := map[storage.BucketHandle][]string{
+		buckets.Upload: {"2024-01-01/0.255.json", "2024-01-02/0.5.json"},
+		buckets.Merge:  {"2024-01-01.json", "2024-01-01.json.sha256", "2024-01-01.manifest", "2024-01-02.json", "bigquery/2024-01-02.json", "snapshots/2024-01-02_2024-01-08.json.gz"},
+		buckets.Chart:  {"2024-01-02.json", "quality/2024-01-01.json"},
+	}
+	for b, want := range wantObjects {
+		objs, err := listObjects(ctx, b, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, obj := range objs {
+			if !strings.HasPrefix(obj, removalPrefix) {
+				got = append(got, obj)
+			}
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("objects in %s mismatch (-want +got):\n%s", b.URI(), diff)
+		}
+	}
+
+	r, err := buckets.Merge.Object("2024-01-01.json").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "{\"Week\":\"2024-01-01\",\"X\":0.255}\n"; got != want {
+		t.Errorf("merged reports after removal = %q, want %q", got, want)
+	}
+	if err := storage.VerifyDigest(ctx, buckets.Merge, "2024-01-01.json"); err != nil {
+		t.Errorf("digest of merged reports after removal: %v", err)
+	}
+
+	// The next merge of the date copies the merged reports only if their
+	// number matches the manifest, since the uploads were deleted.
+	mr, err := buckets.Merge.Object("2024-01-01.manifest").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	var manifest mergeManifest
+	if err := json.NewDecoder(mr).Decode(&manifest); err != nil {
+		t.Fatal(err)
+	}
+	wantManifest := mergeManifest{
+		Merged:         strings.Count(string(data), "\n"),
+		Objects:        []string{"2024-01-01/0.255.json"},
+		UploadsDeleted: true,
+	}
+	if diff := cmp.Diff(wantManifest, manifest); diff != "" {
+		t.Errorf("manifest after removal mismatch (-want +got):\n%s", diff)
+	}
+
+	records, err := listObjects(ctx, buckets.Upload, removalPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Errorf("got audit records %q, want 1", records)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	tconfig "golang.org/x/telemetry/internal/config"
+)
+
+// maxSearchResults bounds the number of results of a search.
+const maxSearchResults = 100
+
+// A searchEntry is a program, chart, or counter that can be found by
+// searching for a fragment of its name.
+type searchEntry struct {
+	Kind    string // "program", "chart", "counter", "stack", or "event"
+	Program string
+	Name    string // the name of the chart or counter, or "" for a program
+	Link    string // the entry's chart on the home page, or the upload config
+}
+
+type searchPage struct {
+	Query     string
+	Results   []searchEntry
+	Truncated bool // whether there were more than maxSearchResults results
+}
+
+func (searchPage) Breadcrumbs() []breadcrumb {
+	return []breadcrumb{{Link: "/", Label: "Go Telemetry"}, {Label: "Search"}}
+}
+
+// handleSearch serves the programs, charts, and counters whose names
+// contain the "q" query parameter, ignoring case. It searches the upload
+// config and the charts shown on the home page, and links each result to its
+// chart there, or to the upload config if it is not charted. With
+// format=json, it serves the results as JSON.
+func handleSearch(render renderer, ucfg func() *tconfig.Config, chartBucket storage.BucketHandle) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		q := r.URL.Query()
+		page := searchPage{Query: strings.TrimSpace(q.Get("q"))}
+		if page.Query != "" {
+			charted, err := latestChartIDs(ctx, chartBucket)
+			if err != nil {
+				return err
+			}
+			page.Results = search(searchIndex(ucfg(), charted), page.Query)
+			if len(page.Results) > maxSearchResults {
+				page.Results = page.Results[:maxSearchResults]
+				page.Truncated = true
+			}
+		}
+		if q.Get("format") == "json" {
+			if page.Results == nil {
+				page.Results = []searchEntry{} // encode as [], not null
+			}
+			return content.JSON(w, page, http.StatusOK)
+		}
+		return render(w, "search.html", page)
+	}
+}
+
+// A chartRef names a program, or a chart of a program, on the home page.
+type chartRef struct {
+	Program string
+	Chart   string // "" for the program itself
+}
+
+// latestChartIDs returns the programs and charts of the latest chart object,
+// keyed by their IDs, which are their anchors on the home page.
+func latestChartIDs(ctx context.Context, chartBucket storage.BucketHandle) (map[string]chartRef, error) {
+	ids := make(map[string]chartRef)
+	obj, err := latestChartObject(ctx, chartBucket)
+	if err != nil || obj == "" {
+		return ids, err
+	}
+	data, err := loadCharts(ctx, obj, chartBucket)
+	if err != nil {
+		return nil, err
+	}
+	programs, _ := data["Programs"].([]any)
+	for _, p := range programs {
+		prog, _ := p.(map[string]any)
+		progName, _ := prog["Name"].(string)
+		id, _ := prog["ID"].(string)
+		ids[id] = chartRef{Program: progName}
+		charts, _ := prog["Charts"].([]any)
+		for _, c := range charts {
+			chart, _ := c.(map[string]any)
+			name, _ := chart["Name"].(string)
+			id, _ := chart["ID"].(string)
+			ids[id] = chartRef{Program: progName, Chart: name}
+		}
+	}
+	return ids, nil
+}
+
+// searchIndex returns the entries for the programs and counters of the
+// upload config, and for the given charts, which are keyed by ID, sorted by
+// program, kind, and name.
+func searchIndex(ucfg *tconfig.Config, charted map[string]chartRef) []searchEntry {
+	// Entries link to the chart with the given ID if it is charted, and
+	// otherwise to the upload config.
+	link := func(ids ...string) string {
+		for _, id := range ids {
+			if _, ok := charted[id]; ok {
+				return "/#" + id
+			}
+		}
+		return "/config"
+	}
+	var entries []searchEntry
+	for id, ref := range charted {
+		kind := "chart"
+		if ref.Chart == "" {
+			kind = "program"
+		}
+		entries = append(entries, searchEntry{kind, ref.Program, ref.Chart, "/#" + id})
+	}
+	for _, p := range ucfg.Programs {
+		progID := "charts:" + p.Name
+		if _, ok := charted[progID]; !ok {
+			entries = append(entries, searchEntry{"program", p.Name, "", "/config"})
+		}
+		for _, c := range p.Counters {
+			chart, _, _ := strings.Cut(c.Name, ":")
+			entries = append(entries, searchEntry{"counter", p.Name, c.Name, link(progID+":"+chart, progID)})
+		}
+		for _, s := range p.Stacks {
+			entries = append(entries, searchEntry{"stack", p.Name, s.Name, link(progID)})
+		}
+		for _, e := range p.Events {
+			entries = append(entries, searchEntry{"event", p.Name, e.Name, link(progID)})
+		}
+	}
+	kinds := map[string]int{"program": 0, "chart": 1, "counter": 2, "stack": 3, "event": 4}
+	sort.Slice(entries, func(i, j int) bool {
+		x, y := entries[i], entries[j]
+		if x.Program != y.Program {
+			return x.Program < y.Program
+		}
+		if x.Kind != y.Kind {
+			return kinds[x.Kind] < kinds[y.Kind]
+		}
+		return x.Name < y.Name
+	})
+	return entries
+}
+
+// search returns the programs whose name, and the charts and counters whose
+// name, contains query, ignoring case.
+func search(entries []searchEntry, query string) []searchEntry {
+	query = strings.ToLower(query)
+	var results []searchEntry
+	for _, e := range entries {
+		name := e.Name
+		if e.Kind == "program" {
+			name = e.Program
+		}
+		if strings.Contains(strings.ToLower(name), query) {
+			results = append(results, e)
+		}
+	}
+	return results
+}
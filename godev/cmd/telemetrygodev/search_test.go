@@ -0,0 +1,49 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	tconfig "golang.org/x/telemetry/internal/config"
+)
+
+func TestSearch(t *testing.T) {
+	cfg, err := tconfig.ReadConfig("testdata/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	charted := map[string]chartRef{
+		"charts:golang.org/x/tools/gopls":         {Program: "golang.org/x/tools/gopls"},
+		"charts:golang.org/x/tools/gopls:editor":  {Program: "golang.org/x/tools/gopls", Chart: "editor"},
+		"charts:golang.org/x/tools/gopls:Version": {Program: "golang.org/x/tools/gopls", Chart: "Version"},
+	}
+	index := searchIndex(cfg, charted)
+
+	tests := []struct {
+		query string
+		want  []searchEntry
+	}{
+		{"EDITOR", []searchEntry{
+			{"chart", "golang.org/x/tools/gopls", "editor", "/#charts:golang.org/x/tools/gopls:editor"},
+			{"counter", "golang.org/x/tools/gopls", "editor:{emacs,vim,vscode,other}", "/#charts:golang.org/x/tools/gopls:editor"},
+		}},
+		{"gopls", []searchEntry{
+			{"program", "golang.org/x/tools/gopls", "", "/#charts:golang.org/x/tools/gopls"},
+			{"stack", "golang.org/x/tools/gopls", "gopls/bug", "/#charts:golang.org/x/tools/gopls"},
+		}},
+		// cmd/go is not charted, so its entries link to the upload config.
+		{"toolchain", []searchEntry{
+			{"event", "cmd/go", "go/toolchain/switch", "/config"},
+		}},
+		{"nonexistent", nil},
+	}
+	for _, test := range tests {
+		if got := search(index, test.query); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("search(%q) =\n%v\nwant\n%v", test.query, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+const (
+	// defaultStatsDays is the number of dates shown on the stats page by
+	// default, and maxStatsDays the most that may be requested.
+	defaultStatsDays = 28
+	maxStatsDays     = 366
+)
+
+// uploadStats are the statistics of the uploads for a date, as written to
+// the stats bucket by the worker's /merge/ endpoint. See the worker's type of
+// the same name.
+type uploadStats struct {
+	Date     string
+	Reports  int
+	UniqueX  int
+	Failures map[string]int
+	Programs map[string]int
+}
+
+// FailureCount returns the total of the failure counts for the date.
+func (s uploadStats) FailureCount() int {
+	n := 0
+	for _, c := range s.Failures {
+		n += c
+	}
+	return n
+}
+
+// A statsCount is a failure reason or program, and its total count over the
+// dates of a statsPage.
+type statsCount struct {
+	Name  string
+	Count int
+}
+
+type statsPage struct {
+	Days     []uploadStats // newest first
+	Failures []statsCount  // by decreasing count
+	Programs []statsCount  // by decreasing count
+}
+
+func (statsPage) Breadcrumbs() []breadcrumb {
+	return []breadcrumb{{Link: "/", Label: "Go Telemetry"}, {Label: "Upload Statistics"}}
+}
+
+// handleStats serves the statistics of the uploads for the most recent
+// dates, 28 by default or the number given by the "days" query parameter.
+// With format=json, it serves them as JSON.
+func handleStats(render renderer, statsBucket storage.BucketHandle) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		q := r.URL.Query()
+		days := defaultStatsDays
+		if s := q.Get("days"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 || n > maxStatsDays {
+				return content.Error(fmt.Errorf("invalid days %q: must be between 1 and %d", s, maxStatsDays), http.StatusBadRequest)
+			}
+			days = n
+		}
+		stats, err := loadStats(r.Context(), statsBucket, days)
+		if err != nil {
+			return err
+		}
+		page := newStatsPage(stats)
+		if q.Get("format") == "json" {
+			return content.JSON(w, page, http.StatusOK)
+		}
+		return render(w, "stats.html", page)
+	}
+}
+
+func newStatsPage(stats []uploadStats) statsPage {
+	failures := make(map[string]int)
+	programs := make(map[string]int)
+	for _, s := range stats {
+		for name, n := range s.Failures {
+			failures[name] += n
+		}
+		for name, n := range s.Programs {
+			programs[name] += n
+		}
+	}
+	if stats == nil {
+		stats = []uploadStats{} // encode as [], not null
+	}
+	return statsPage{
+		Days:     stats,
+		Failures: sortedCounts(failures),
+		Programs: sortedCounts(programs),
+	}
+}
+
+// sortedCounts returns the counts of m by decreasing count, then by name.
+func sortedCounts(m map[string]int) []statsCount {
+	counts := make([]statsCount, 0, len(m))
+	for name, n := range m {
+		counts = append(counts, statsCount{name, n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// loadStats reads the statistics of the most recent n dates from the stats
+// bucket, newest first.
+func loadStats(ctx context.Context, statsBucket storage.BucketHandle, n int) ([]uploadStats, error) {
+	var objs []string
+	it := statsBucket.Objects(ctx, "")
+	for {
+		obj, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(obj, ".json") {
+			objs = append(objs, obj)
+		}
+	}
+	// Object names are dates, so they sort by date.
+	sort.Sort(sort.Reverse(sort.StringSlice(objs)))
+	objs = objs[:min(n, len(objs))]
+
+	var stats []uploadStats
+	for _, obj := range objs {
+		reader, err := statsBucket.Object(obj).NewReader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var s uploadStats
+		err = json.NewDecoder(reader).Decode(&s)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %v", obj, err)
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []uploadStats{
+		{Date: "2024-01-01", Reports: 3, UniqueX: 3, Programs: map[string]int{"gopls": 3}},
+		{Date: "2024-01-02", Reports: 5, UniqueX: 4, Failures: map[string]int{"replay": 1}, Programs: map[string]int{"gopls": 2, "cmd/go": 2}},
+		{Date: "2024-01-03", Reports: 4, UniqueX: 4, Failures: map[string]int{"malformed": 1, "replay": 2}, Programs: map[string]int{"cmd/go": 3}},
+	} {
+		w, err := bucket.Object(s.Date + ".json").NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.NewEncoder(w).Encode(s); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := loadStats(ctx, bucket, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := newStatsPage(stats)
+	var dates []string
+	for _, s := range page.Days {
+		dates = append(dates, s.Date)
+	}
+	if diff := cmp.Diff([]string{"2024-01-03", "2024-01-02"}, dates); diff != "" {
+		t.Errorf("dates mismatch (-want +got):\n%s", diff)
+	}
+	if got := page.Days[0].FailureCount(); got != 3 {
+		t.Errorf("FailureCount() = %d, want 3", got)
+	}
+	wantFailures := []statsCount{{"replay", 3}, {"malformed", 1}}
+	if diff := cmp.Diff(wantFailures, page.Failures); diff != "" {
+		t.Errorf("failures mismatch (-want +got):\n%s", diff)
+	}
+	wantPrograms := []statsCount{{"cmd/go", 5}, {"gopls", 2}}
+	if diff := cmp.Diff(wantPrograms, page.Programs); diff != "" {
+		t.Errorf("programs mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/flags"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+	"golang.org/x/telemetry/internal/uploadable"
+)
+
+// Reasons for which an upload is considered suspicious.
+//
+// Suspicious uploads are accepted and stored like any other, but are tagged
+// with the reasons in their storage.SuspectMetadata, so that the merge step
+// can exclude them. Uploads whose week is implausible are not screened here:
+// they are rejected by validate, before they are screened.
+const (
+	suspectReplay    = "replay"    // identical report uploaded from many addresses
+	suspectMagnitude = "magnitude" // implausibly large counter value
+)
+
+// Reasons for which an upload is rejected because of its week. See
+// uploadWindow.
+const (
+	rejectEarly = "too early" // week ends after the upload window
+	rejectLate  = "too late"  // week ended before the upload window
+)
+
+const (
+	// replayAddrs is the number of distinct client addresses from which an
+	// identical report must be uploaded to be considered a replay. Reports
+	// contain a random X, so legitimate uploads are never identical, but a
+	// single client may retry the same upload.
+	replayAddrs = 3
+
+	// maxReplayEntries bounds the memory used to detect replays. When it is
+	// reached, replay detection starts over.
+	maxReplayEntries = 100_000
+
+	// maxPlausibleCount is the largest counter value that a single program
+	// could plausibly record in a week.
+	maxPlausibleCount = 1 << 32
+)
+
+// An uploadScreen applies heuristics to uploaded reports to detect bot
+// traffic and replays, and keeps counts of the suspicious uploads it has seen
+// since the server started.
+type uploadScreen struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	senders map[[sha256.Size]byte]map[string]bool // report hash -> client addresses
+	counts  map[string]int                        // reason -> suspicious uploads
+	total   int                                   // all screened uploads
+
+	collisions int                       // uploads stored under a deduplicated name
+	drops      map[uploadable.Reason]int // reason -> programs and counters removed by validate
+	rejects    map[string]int            // reason -> uploads rejected for their week
+}
+
+func newUploadScreen() *uploadScreen {
+	return &uploadScreen{
+		now:     time.Now,
+		senders: make(map[[sha256.Size]byte]map[string]bool),
+		counts:  make(map[string]int),
+		drops:   make(map[uploadable.Reason]int),
+		rejects: make(map[string]int),
+	}
+}
+
+// check returns the sorted reasons for which the report, uploaded from addr
+// with the given body, is suspicious, or nil if it looks legitimate.
+func (s *uploadScreen) check(report *telemetry.Report, body []byte, addr string) []string {
+	var reasons []string
+	if implausibleCounts(report) {
+		reasons = append(reasons, suspectMagnitude)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total++
+	sum := sha256.Sum256(body)
+	addrs := s.senders[sum]
+	if addrs == nil {
+		if len(s.senders) >= maxReplayEntries {
+			clear(s.senders)
+		}
+		addrs = make(map[string]bool)
+		s.senders[sum] = addrs
+	}
+	addrs[addr] = true
+	if len(addrs) >= replayAddrs {
+		reasons = append(reasons, suspectReplay)
+	}
+	sort.Strings(reasons)
+	for _, r := range reasons {
+		s.counts[r]++
+	}
+	return reasons
+}
+
+// collided records an upload whose week and X were those of a report that
+// was already stored.
+func (s *uploadScreen) collided() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collisions++
+}
+
+// dropped records the programs and counters that validate removed from an
+// upload, by reason.
+func (s *uploadScreen) dropped(drops map[uploadable.Reason]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for r, n := range drops {
+		s.drops[r] += n
+	}
+}
+
+// rejected records an upload that was rejected for the given reason.
+func (s *uploadScreen) rejected(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejects[reason]++
+}
+
+// implausibleCounts reports whether any counter in the report has a value
+// that no real program would record.
+func implausibleCounts(report *telemetry.Report) bool {
+	for _, p := range report.Programs {
+		for _, counts := range []map[string]int64{p.Counters, p.Stacks, p.Events} {
+			for _, n := range counts {
+				if n < 0 || n > maxPlausibleCount {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// clientAddr returns the address of the client that sent r. Behind the
+// load balancer, this is the last address in X-Forwarded-For, which the
+// load balancer appends: the addresses before it are sent by the client,
+// which can forge them.
+func clientAddr(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		return strings.TrimSpace(fwd[strings.LastIndex(fwd, ",")+1:])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type suspectCount struct {
+	Reason string
+	Count  int
+}
+
+type dropCount struct {
+	Reason string
+	Count  int
+}
+
+type opsPage struct {
+	Flags      flags.Flags
+	FlagsError string // error reading the flags file, if any
+
+	Total      int // uploads screened
+	Suspect    []suspectCount
+	Collisions int // uploads whose week and X collided with a stored report
+	Dropped    []dropCount
+	Rejected   []dropCount // uploads rejected for their week
+
+	Quality []dataQuality // newest first
+}
+
+func (opsPage) Breadcrumbs() []breadcrumb {
+	return []breadcrumb{{Link: "/", Label: "Go Telemetry"}, {Label: "Status"}}
+}
+
+// page returns a snapshot of the screen's counts.
+func (s *uploadScreen) page() opsPage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page := opsPage{Total: s.total, Collisions: s.collisions}
+	for _, r := range []string{suspectReplay, suspectMagnitude} {
+		page.Suspect = append(page.Suspect, suspectCount{r, s.counts[r]})
+	}
+	for _, r := range []uploadable.Reason{
+		uploadable.UnknownProgram,
+		uploadable.UnknownGOOS,
+		uploadable.UnknownGOARCH,
+		uploadable.UnknownGoVersion,
+		uploadable.UnknownVersion,
+		uploadable.UnknownCounter,
+	} {
+		page.Dropped = append(page.Dropped, dropCount{r.String(), s.drops[r]})
+	}
+	for _, r := range []string{rejectEarly, rejectLate} {
+		page.Rejected = append(page.Rejected, dropCount{r, s.rejects[r]})
+	}
+	return page
+}
+
+// handleOps serves the current operational flags, the counts of suspicious
+// uploads seen by this server instance, and the data quality of recently
+// merged uploads.
+//
+// The counts and the errors of the flags file are not public, so like the
+// admin endpoints, it requires the admin token, on GET requests. If token is
+// empty, the page is disabled.
+func handleOps(render renderer, screen *uploadScreen, flagSource *flags.Source, chartBucket storage.BucketHandle, token string) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if code := adminStatus(r, "GET", token); code != 0 {
+			return content.Status(w, code)
+		}
+		page := screen.page()
+		page.Flags = flagSource.Flags()
+		if err := flagSource.Err(); err != nil {
+			page.FlagsError = err.Error()
+		}
+		quality, err := loadQuality(r.Context(), chartBucket, maxQualityDates)
+		if err != nil {
+			return err
+		}
+		page.Quality = quality
+		return render(w, "ops.html", page)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/flags"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestUploadScreen(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := func(week string, count int64) *telemetry.Report {
+		return &telemetry.Report{
+			Week: week,
+			X:    0.5,
+			Programs: []*telemetry.ProgramReport{{
+				Program:  "golang.org/x/tools/gopls",
+				Counters: map[string]int64{"gopls/client:vscode": count},
+			}},
+		}
+	}
+	tests := []struct {
+		name   string
+		report *telemetry.Report
+		want   string
+	}{
+		{"ok", report("2024-02-27", 10), ""},
+		{"huge count", report("2024-02-27", 1<<40), "magnitude"},
+		{"negative count", report("2024-02-27", -1), "magnitude"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newUploadScreen()
+			s.now = func() time.Time { return now }
+			got := strings.Join(s.check(test.report, []byte(test.name), "192.0.2.1"), ",")
+			if got != test.want {
+				t.Errorf("check() = %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		s := newUploadScreen()
+		s.now = func() time.Time { return now }
+		r := report("2024-02-27", 10)
+		body := []byte("identical body")
+		for i, addr := range []string{"192.0.2.1", "192.0.2.1", "192.0.2.2", "192.0.2.3"} {
+			got := strings.Join(s.check(r, body, addr), ",")
+			want := ""
+			if i == 3 {
+				want = "replay"
+			}
+			if got != want {
+				t.Errorf("upload %d from %s: check() = %q, want %q", i, addr, got, want)
+			}
+		}
+		page := s.page()
+		if page.Total != 4 {
+			t.Errorf("page().Total = %d, want 4", page.Total)
+		}
+		for _, c := range page.Suspect {
+			want := 0
+			if c.Reason == "replay" {
+				want = 1
+			}
+			if c.Count != want {
+				t.Errorf("page() count for %s = %d, want %d", c.Reason, c.Count, want)
+			}
+		}
+	})
+}
+
+func TestClientAddr(t *testing.T) {
+	r := httptest.NewRequest("POST", "/upload/", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	if got := clientAddr(r); got != "192.0.2.1" {
+		t.Errorf("clientAddr() = %q, want %q", got, "192.0.2.1")
+	}
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+	if got := clientAddr(r); got != "198.51.100.7" {
+		t.Errorf("clientAddr() with X-Forwarded-For = %q, want %q", got, "198.51.100.7")
+	}
+	// The client may send its own X-Forwarded-For, to which the load
+	// balancer appends the address it sees.
+	r.Header.Set("X-Forwarded-For", "203.0.113.9, 198.51.100.7")
+	if got := clientAddr(r); got != "198.51.100.7" {
+		t.Errorf("clientAddr() with forged X-Forwarded-For = %q, want %q", got, "198.51.100.7")
+	}
+}
+
+func TestHandleOps(t *testing.T) {
+	flagSource := flags.NewSource("", flags.Flags{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	render := newRenderer(fsys(false))
+	for _, test := range []struct {
+		name, token, method, auth string
+		want                      int
+	}{
+		{"disabled", "", "GET", "Bearer ", http.StatusNotFound},
+		{"no token", "secret", "GET", "", http.StatusUnauthorized},
+		{"wrong token", "secret", "GET", "Bearer guess", http.StatusUnauthorized},
+		{"POST", "secret", "POST", "Bearer secret", http.StatusMethodNotAllowed},
+		{"ok", "secret", "GET", "Bearer secret", http.StatusOK},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			h := handleOps(render, newUploadScreen(), flagSource, storage.NewMemBucket("chart"), test.token)
+			req := httptest.NewRequest(test.method, "/ops", nil)
+			if test.auth != "" {
+				req.Header.Set("Authorization", test.auth)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != test.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, test.want, w.Body)
+			}
+			if w.Code == http.StatusOK {
+				for _, fragment := range []string{"maintenance", "Upload Screening", "Data Quality"} {
+					if !strings.Contains(w.Body.String(), fragment) {
+						t.Errorf("missing fragment %q", fragment)
+					}
+				}
+			}
+		})
+	}
+}
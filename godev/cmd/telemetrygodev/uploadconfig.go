@@ -0,0 +1,126 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/mod/semver"
+	"golang.org/x/telemetry/godev/internal/content"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/configstore"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// An uploadConfigSource provides the upload config against which uploads
+// are validated. It starts with the config deployed with the server, and
+// switches to newer versions of the config module when they are published,
+// so that newly added counters are accepted without a redeploy.
+type uploadConfigSource struct {
+	logger   *slog.Logger
+	download func() (*telemetry.UploadConfig, string, error) // the latest config and its version
+
+	mu      sync.Mutex
+	cfg     *tconfig.Config
+	version string // of cfg; may be empty for the deployed config
+}
+
+func newUploadConfigSource(deployed *tconfig.Config, logger *slog.Logger) *uploadConfigSource {
+	return &uploadConfigSource{
+		logger: logger,
+		download: func() (*telemetry.UploadConfig, string, error) {
+			return configstore.Download("latest", nil)
+		},
+		cfg: deployed,
+	}
+}
+
+// Config returns the current upload config.
+func (s *uploadConfigSource) Config() *tconfig.Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg
+}
+
+// Version returns the version of the config module of the current upload
+// config, or "" if it is the config deployed with the server.
+func (s *uploadConfigSource) Version() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version
+}
+
+// reload downloads the latest version of the config module, and switches to
+// it if it is newer than the current config. It returns the version of the
+// config in use, or "" for the deployed config.
+func (s *uploadConfigSource) reload() (string, error) {
+	ucfg, version, err := s.download()
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if semver.Compare(version, s.version) > 0 {
+		s.cfg, s.version = tconfig.NewConfig(ucfg), version
+		s.logger.Info("upload config reloaded", "version", version)
+	}
+	return s.version, nil
+}
+
+// refresh reloads the config every interval until ctx is done.
+func (s *uploadConfigSource) refresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.reload(); err != nil {
+				s.logger.Error("reloading upload config", "err", err)
+			}
+		}
+	}
+}
+
+// adminStatus returns the status with which to reject a request to an admin
+// endpoint, or 0 if it is a request with the given method that carries token
+// as a bearer token. If token is empty, admin endpoints are disabled.
+func adminStatus(r *http.Request, method, token string) int {
+	if token == "" {
+		return http.StatusNotFound
+	}
+	if r.Method != method {
+		return http.StatusMethodNotAllowed
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		return http.StatusUnauthorized
+	}
+	return 0
+}
+
+// handleReloadConfig reloads the upload config on POST requests that carry
+// the admin token as a bearer token. If token is empty, the endpoint is
+// disabled.
+func handleReloadConfig(source *uploadConfigSource, token string) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if code := adminStatus(r, "POST", token); code != 0 {
+			return content.Status(w, code)
+		}
+		version, err := source.reload()
+		if err != nil {
+			return err
+		}
+		return content.Text(w, fmt.Sprintf("upload config version %s", version), http.StatusOK)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slog"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestReloadConfig(t *testing.T) {
+	deployed, err := tconfig.ReadConfig("testdata/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := newUploadConfigSource(deployed, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	latest := "v0.2.0"
+	source.download = func() (*telemetry.UploadConfig, string, error) {
+		return &telemetry.UploadConfig{
+			Programs: []*telemetry.ProgramConfig{{Name: "example.com/new"}},
+		}, latest, nil
+	}
+	h := handleReloadConfig(source, "secret")
+
+	reload := func(method, auth string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, "/admin/reload-config", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, test := range []struct {
+		method, auth string
+		want         int
+	}{
+		{"GET", "Bearer secret", http.StatusMethodNotAllowed},
+		{"POST", "", http.StatusUnauthorized},
+		{"POST", "Bearer wrong", http.StatusUnauthorized},
+		{"POST", "secret", http.StatusUnauthorized},
+	} {
+		if got := reload(test.method, test.auth).Code; got != test.want {
+			t.Errorf("%s with Authorization %q: got status %d, want %d", test.method, test.auth, got, test.want)
+		}
+	}
+	if source.Config() != deployed {
+		t.Fatal("unauthorized requests reloaded the config")
+	}
+
+	w := reload("POST", "Bearer secret")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "v0.2.0") {
+		t.Fatalf("reload: got %d %q, want 200 with version v0.2.0", w.Code, w.Body.String())
+	}
+	cfg := source.Config()
+	if !cfg.HasProgram("example.com/new") || cfg.HasProgram("golang.org/x/tools/gopls") {
+		t.Errorf("reloaded config has the wrong programs")
+	}
+	if v := source.Version(); v != "v0.2.0" {
+		t.Errorf("reloaded config has version %q, want v0.2.0", v)
+	}
+
+	// An older version, such as from a stale module proxy, is ignored.
+	latest = "v0.1.0"
+	if version, err := source.reload(); err != nil || version != "v0.2.0" {
+		t.Errorf("reload of older version = %q, %v, want v0.2.0", version, err)
+	}
+	if source.Config() != cfg {
+		t.Error("reload of older version replaced the config")
+	}
+
+	// Without a token, the endpoint does not exist.
+	w = httptest.NewRecorder()
+	handleReloadConfig(source, "").ServeHTTP(w, httptest.NewRequest("POST", "/admin/reload-config", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("reload without admin token: got status %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// anomalyPrefix is the prefix of anomaly objects in the chart bucket.
+const anomalyPrefix = "anomalies/"
+
+const (
+	// anomalyWindow is the number of days before a date whose daily charts
+	// are the baseline against which the date is compared.
+	anomalyWindow = 7
+
+	// minBaselineDays is the number of days of the window that must have
+	// daily charts for the date to be compared.
+	minBaselineDays = 3
+
+	// minBaseline is the smallest baseline of a metric that is compared, so
+	// that the noise of rarely reported programs and counters is ignored.
+	minBaseline = 10
+
+	// spikeFactor is the factor by which a metric must exceed its baseline
+	// to be reported as a spike.
+	spikeFactor = 10
+
+	// topCounters is the number of counters of each program, with the most
+	// reports over the window, that are compared.
+	topCounters = 10
+)
+
+// Kinds of anomalies.
+const (
+	anomalyDrop  = "drop"  // the metric dropped to zero
+	anomalySpike = "spike" // the metric rose spikeFactor times above its baseline
+)
+
+// anomalies records the anomalies found in the daily charts of a date, so
+// that operators notice pipeline or client regressions quickly.
+type anomalies struct {
+	Date      string
+	Baseline  int // number of days of the window whose daily charts were found
+	Anomalies []anomaly
+}
+
+// An anomaly is a metric of a date that departs from its baseline, the mean
+// of its values over the preceding anomalyWindow days.
+type anomaly struct {
+	Kind     string
+	Program  string
+	Counter  string `json:",omitempty"` // counter, or empty for the program's reports
+	Value    float64
+	Baseline float64
+}
+
+// A metricKey identifies a metric of the daily charts: the number of reports
+// of a program, or of the reports of a program that include a counter.
+type metricKey struct {
+	program, counter string
+}
+
+// detectAnomalies compares the daily charts of date with those of the
+// preceding days, writes the anomalies it finds to the chart bucket, and logs
+// each as an error. It compares the number of reports of each program, and
+// of each of its top counters.
+func detectAnomalies(ctx context.Context, bucket storage.BucketHandle, date time.Time, data *charts.Data) (*anomalies, error) {
+	result := &anomalies{Date: date.Format(telemetry.DateOnly)}
+	baseline := make(map[metricKey]float64) // sum over the window
+	for i := 1; i <= anomalyWindow; i++ {
+		day := date.AddDate(0, 0, -i)
+		prev, err := readDailyCharts(ctx, bucket, day)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Baseline++
+		for k, v := range chartMetrics(prev) {
+			baseline[k] += v
+		}
+	}
+	if result.Baseline >= minBaselineDays {
+		for k := range baseline {
+			baseline[k] /= float64(result.Baseline)
+		}
+		result.Anomalies = compareMetrics(chartMetrics(data), baseline)
+	}
+
+	for _, a := range result.Anomalies {
+		slog.ErrorContext(ctx, "chart anomaly",
+			slog.String("date", result.Date),
+			slog.String("kind", a.Kind),
+			slog.String("program", a.Program),
+			slog.String("counter", a.Counter),
+			slog.Float64("value", a.Value),
+			slog.Float64("baseline", a.Baseline))
+	}
+
+	out, err := bucket.Object(anomalyPrefix + result.Date + ".json").NewWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+	if err := json.NewEncoder(out).Encode(result); err != nil {
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// compareMetrics returns the anomalies of the metrics of a date relative to
+// their baseline. Counters are compared only if they are among the
+// topCounters of their program in the baseline.
+func compareMetrics(metrics, baseline map[metricKey]float64) []anomaly {
+	top := make(map[metricKey]bool)
+	byProgram := make(map[string][]metricKey)
+	for k := range baseline {
+		if k.counter == "" {
+			top[k] = true
+		} else {
+			byProgram[k.program] = append(byProgram[k.program], k)
+		}
+	}
+	for _, keys := range byProgram {
+		sort.Slice(keys, func(i, j int) bool {
+			if baseline[keys[i]] != baseline[keys[j]] {
+				return baseline[keys[i]] > baseline[keys[j]]
+			}
+			return keys[i].counter < keys[j].counter
+		})
+		if len(keys) > topCounters {
+			keys = keys[:topCounters]
+		}
+		for _, k := range keys {
+			top[k] = true
+		}
+	}
+
+	var result []anomaly
+	for k := range top {
+		base, value := baseline[k], metrics[k]
+		if base < minBaseline {
+			continue
+		}
+		a := anomaly{Program: k.program, Counter: k.counter, Value: value, Baseline: base}
+		switch {
+		case value == 0:
+			a.Kind = anomalyDrop
+		case value >= spikeFactor*base:
+			a.Kind = anomalySpike
+		default:
+			continue
+		}
+		result = append(result, a)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Program != result[j].Program {
+			return result[i].Program < result[j].Program
+		}
+		return result[i].Counter < result[j].Counter
+	})
+	return result
+}
+
+// chartMetrics returns the metrics of daily charts. The number of reports of
+// a program is the sum of its GOOS chart, as every program report has a GOOS.
+// The number of reports that include a counter is its value in the
+// partition chart of the counter. Other charts are ignored.
+func chartMetrics(data *charts.Data) map[metricKey]float64 {
+	metrics := make(map[metricKey]float64)
+	for _, p := range data.Programs {
+		for _, c := range p.Charts {
+			switch {
+			case c.Name == "GOOS":
+				for _, d := range c.Data {
+					metrics[metricKey{p.Name, ""}] += d.Value
+				}
+			case c.Type != "partition" || c.Name == "Version" || c.Name == "GOARCH" || c.Name == "GOOS/GOARCH" || c.Name == "GoVersion":
+			default:
+				for _, d := range c.Data {
+					metrics[metricKey{p.Name, c.Name + ":" + d.Key}] += d.Value
+				}
+			}
+		}
+	}
+	return metrics
+}
+
+// readDailyCharts reads the daily charts of a date from the chart bucket.
+func readDailyCharts(ctx context.Context, bucket storage.BucketHandle, date time.Time) (*charts.Data, error) {
+	in, err := bucket.Object(fileName(date, date)).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	return charts.Decode(in)
+}
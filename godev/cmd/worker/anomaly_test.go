@@ -0,0 +1,108 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// dailyCharts returns daily charts with the given number of reports of each
+// program, and of each of its "gopls/client" counters.
+func dailyCharts(reports map[string]float64, clients map[string]float64) *charts.Data {
+	var data charts.Data
+	for program, n := range reports {
+		p := &charts.Program{Name: program, Charts: []*charts.Chart{
+			{Name: "GOOS", Type: "partition", Data: []*charts.Datum{{Key: "linux", Value: n / 2}, {Key: "darwin", Value: n / 2}}},
+			{Name: "Version", Type: "partition", Data: []*charts.Datum{{Key: "devel", Value: n}}},
+		}}
+		if program == "golang.org/x/tools/gopls" {
+			c := &charts.Chart{Name: "gopls/client", Type: "partition"}
+			for client, v := range clients {
+				c.Data = append(c.Data, &charts.Datum{Key: client, Value: v})
+			}
+			p.Charts = append(p.Charts, c)
+		}
+		data.Programs = append(data.Programs, p)
+	}
+	return &data
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "chart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	// Without enough preceding days, there is no baseline.
+	today := dailyCharts(map[string]float64{"cmd/go": 0}, nil)
+	got, err := detectAnomalies(ctx, bucket, date, today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Anomalies) != 0 {
+		t.Errorf("detectAnomalies without baseline = %+v, want none", got.Anomalies)
+	}
+
+	for i := 1; i <= 5; i++ {
+		prev := dailyCharts(
+			map[string]float64{"cmd/go": 100, "golang.org/x/tools/gopls": 20, "cmd/compile": 2},
+			map[string]float64{"vscode": 15, "vim": 4})
+		w, err := bucket.Object(fileName(date.AddDate(0, 0, -i), date.AddDate(0, 0, -i))).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.NewEncoder(w).Encode(prev); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// cmd/go drops to zero, gopls spikes, and cmd/compile is too rare to
+	// compare. The vscode client drops to zero, and vim is too rare.
+	today = dailyCharts(
+		map[string]float64{"golang.org/x/tools/gopls": 200, "cmd/compile": 40},
+		map[string]float64{"vim": 0, "emacs": 100})
+	got, err = detectAnomalies(ctx, bucket, date, today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &anomalies{
+		Date:     "2024-01-10",
+		Baseline: 5,
+		Anomalies: []anomaly{
+			{Kind: anomalyDrop, Program: "cmd/go", Value: 0, Baseline: 100},
+			{Kind: anomalySpike, Program: "golang.org/x/tools/gopls", Value: 200, Baseline: 20},
+			{Kind: anomalyDrop, Program: "golang.org/x/tools/gopls", Counter: "gopls/client:vscode", Value: 0, Baseline: 15},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("detectAnomalies mismatch (-want +got):\n%s", diff)
+	}
+
+	in, err := bucket.Object(anomalyPrefix + date.Format(telemetry.DateOnly) + ".json").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	var stored anomalies
+	if err := json.NewDecoder(in).Decode(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, &stored); diff != "" {
+		t.Errorf("stored anomalies mismatch (-want +got):\n%s", diff)
+	}
+}
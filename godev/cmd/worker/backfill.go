@@ -0,0 +1,148 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// defaultBackfillConcurrency is the number of dates that a backfill
+// processes at once, unless the "concurrency" query parameter says otherwise.
+const defaultBackfillConcurrency = 4
+
+// A backfillStep is a unit of work of a backfill.
+type backfillStep struct {
+	name string // such as "merge 2024-01-01"
+	run  func(context.Context) (string, error)
+}
+
+// handleBackfill regenerates the merged reports and charts of the dates
+// given by the "start" and "end" query parameters, for example after the
+// aggregation logic changed. The "ops" query parameter is a comma-separated
+// list of the operations to run, among:
+//
+//   - merge: merge all the uploads of each date again;
+//   - chart: compute the daily chart of each date, and the weekly chart of
+//     the week ending on each date.
+//
+// All merges finish before charts are computed. At most "concurrency" dates
+// are processed at once.
+//
+// Progress is streamed to the response as plain text, a line per step,
+// followed by a summary line. As the status is sent before the steps run, it
+// does not reflect their failures: the summary line does.
+func handleBackfill(cfg *tconfig.Config, rules *charts.Rules, s *storage.API) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		start, end, err := parseDateRange(r.URL)
+		if err != nil {
+			return err
+		}
+		concurrency := defaultBackfillConcurrency
+		if c := r.URL.Query().Get("concurrency"); c != "" {
+			concurrency, err = strconv.Atoi(c)
+			if err != nil || concurrency < 1 {
+				return content.Error(fmt.Errorf("invalid concurrency %q", c), http.StatusBadRequest)
+			}
+		}
+		var merges, chartSteps []backfillStep
+		for _, op := range strings.Split(r.URL.Query().Get("ops"), ",") {
+			switch op {
+			case "merge":
+				merges = backfillMerges(cfg, rules, s, start, end)
+			case "chart":
+				chartSteps = backfillCharts(cfg, rules, s, start, end)
+			default:
+				return content.Error(fmt.Errorf("unknown backfill operation %q", op), http.StatusBadRequest)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		p := &backfillProgress{w: w, total: len(merges) + len(chartSteps)}
+		p.runAll(ctx, merges, concurrency)
+		p.runAll(ctx, chartSteps, concurrency)
+		fmt.Fprintf(w, "backfill done: %d steps succeeded, %d failed\n", p.done-p.failed, p.failed)
+		slog.InfoContext(ctx, "backfill done", "start", start.Format(telemetry.DateOnly), "end", end.Format(telemetry.DateOnly), "steps", p.done, "failed", p.failed)
+		return nil
+	}
+}
+
+func backfillMerges(cfg *tconfig.Config, rules *charts.Rules, s *storage.API, start, end time.Time) []backfillStep {
+	var steps []backfillStep
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		date := date.Format(telemetry.DateOnly)
+		steps = append(steps, backfillStep{"merge " + date, func(ctx context.Context) (string, error) {
+			return merge(ctx, cfg, rules.Retirements, s, date, true)
+		}})
+	}
+	return steps
+}
+
+func backfillCharts(cfg *tconfig.Config, rules *charts.Rules, s *storage.API, start, end time.Time) []backfillStep {
+	var steps []backfillStep
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		weekStart := date.AddDate(0, 0, -6)
+		steps = append(steps,
+			backfillStep{"chart " + date.Format(telemetry.DateOnly), func(ctx context.Context) (string, error) {
+				return writeCharts(ctx, cfg, rules, s, date, date)
+			}},
+			backfillStep{"chart " + weekStart.Format(telemetry.DateOnly) + "_" + date.Format(telemetry.DateOnly), func(ctx context.Context) (string, error) {
+				return writeCharts(ctx, cfg, rules, s, weekStart, date)
+			}})
+	}
+	return steps
+}
+
+// backfillProgress reports the progress of a backfill.
+type backfillProgress struct {
+	w     http.ResponseWriter
+	total int
+
+	mu     sync.Mutex
+	done   int
+	failed int
+}
+
+// runAll runs steps, at most concurrency at a time, reporting the result of
+// each. A failed step does not stop the others.
+func (p *backfillProgress) runAll(ctx context.Context, steps []backfillStep, concurrency int) {
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+	for _, step := range steps {
+		g.Go(func() error {
+			msg, err := step.run(ctx)
+			p.report(step.name, msg, err)
+			return nil
+		})
+	}
+	g.Wait()
+}
+
+func (p *backfillProgress) report(name, msg string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if err != nil {
+		p.failed++
+		msg = "error: " + err.Error()
+	}
+	fmt.Fprintf(p.w, "[%d/%d] %s: %s\n", p.done, p.total, name, msg)
+	http.NewResponseController(p.w).Flush()
+}
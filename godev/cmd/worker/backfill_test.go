@@ -0,0 +1,84 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestBackfill(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"upload": &s.Upload, "merge": &s.Merge, "chart": &s.Chart, "stats": &s.Stats} {
+		bucket, err := storage.NewFSBucket(ctx, dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b = bucket
+	}
+	for day := 1; day <= 7; day++ {
+		date := fmt.Sprintf("2024-01-%02d", day)
+		w, err := s.Upload.Object(date + "/0.1.json").NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.NewEncoder(w).Encode(telemetry.Report{Week: date, X: 0.1}); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := handleBackfill(config.NewConfig(&telemetry.UploadConfig{}), new(charts.Rules), &s)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/backfill/?start=2024-01-01&end=2024-01-07&ops=merge,chart&concurrency=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("backfill status = %d: %s", rec.Code, rec.Body)
+	}
+	body := rec.Body.String()
+	// The weekly charts that end before 2024-01-07 need the merged reports
+	// of December, which are missing.
+	for _, want := range []string{
+		"[21/21]",
+		"merge 2024-01-01: merged 1 reports",
+		"chart 2024-01-01_2024-01-07: processed 7 reports",
+		"chart 2023-12-31_2024-01-06: error:",
+		"backfill done: 15 steps succeeded, 6 failed",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("backfill output does not contain %q:\n%s", want, body)
+		}
+	}
+	for _, obj := range []string{"2024-01-03.json", "2024-01-01_2024-01-07.json"} {
+		if _, err := s.Chart.Object(obj).NewReader(ctx); err != nil {
+			t.Errorf("chart object %s: %v", obj, err)
+		}
+	}
+
+	for _, query := range []string{
+		"start=2024-01-01&end=2024-01-07&ops=merge,copy",
+		"start=2024-01-01&end=2024-01-07&ops=merge&concurrency=0",
+		"start=2024-01-07&end=2024-01-01&ops=merge",
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/backfill/?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("backfill?%s status = %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
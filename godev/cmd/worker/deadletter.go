@@ -0,0 +1,131 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+// Headers set by Cloud Tasks on the requests of the tasks it runs.
+const (
+	taskNameHeader       = "X-CloudTasks-TaskName"
+	taskRetryCountHeader = "X-CloudTasks-TaskRetryCount"
+)
+
+// A deadLetter describes a task that failed permanently, with what is needed
+// to replay it once the cause of the failure is fixed. It is stored in the
+// dead-letter bucket as <task>.json.
+type deadLetter struct {
+	Task    string    // Cloud Tasks task name, or the ID the task would have
+	Method  string    // method of the request
+	URL     string    // path and query of the request
+	Status  int       // status of the response
+	Error   string    // error of the task
+	Retries int       // number of times Cloud Tasks had retried the task
+	Time    time.Time // time of the failure
+}
+
+// isPermanent reports whether a task that failed with err would fail again
+// if it were retried as is: its request is invalid, or the stored data it
+// reads is. Other failures, such as storage errors, conflicting writes, or
+// missing merged reports, may not happen again, so the task is retried.
+func isPermanent(err error) bool {
+	switch content.ErrorStatus(err) {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return true
+	}
+	return errors.Is(err, charts.ErrInvalid)
+}
+
+// withDeadLetters wraps the handler of a task queued in Cloud Tasks, which
+// retries tasks until they succeed. Tasks that fail permanently respond with
+// a 4xx status, and their descriptor is written to the dead-letter bucket.
+// Retries of a task with a descriptor fail with its status without running
+// the task again, so that the task stops using resources until it is
+// replayed. Requests not made by Cloud Tasks, such as replays, always run.
+func withDeadLetters(bucket storage.BucketHandle, h content.HandlerFunc) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		task := r.Header.Get(taskNameHeader)
+		if task != "" {
+			dl, err := readDeadLetter(ctx, bucket, task)
+			if err == nil {
+				return content.Error(fmt.Errorf("task failed permanently at %s: %s", dl.Time.Format(time.RFC3339), dl.Error), dl.Status)
+			}
+			if !errors.Is(err, storage.ErrObjectNotExist) {
+				slog.WarnContext(ctx, "reading dead letter", "task", task, "error", err)
+			}
+		}
+
+		err := h(w, r)
+		if err == nil || !isPermanent(err) {
+			return err
+		}
+		code := content.ErrorStatus(err)
+		if code >= 500 {
+			code = http.StatusUnprocessableEntity
+		}
+		if task == "" {
+			task = taskID(time.Now(), r.URL.String())
+		}
+		retries, _ := strconv.Atoi(r.Header.Get(taskRetryCountHeader))
+		dl := &deadLetter{
+			Task:    task,
+			Method:  r.Method,
+			URL:     r.URL.RequestURI(),
+			Status:  code,
+			Error:   err.Error(),
+			Retries: retries,
+			Time:    time.Now().UTC(),
+		}
+		if werr := writeDeadLetter(ctx, bucket, dl); werr != nil {
+			slog.ErrorContext(ctx, "writing dead letter", "task", task, "error", werr)
+		}
+		slog.ErrorContext(ctx, "task failed permanently", "task", task, "url", dl.URL, "error", err)
+		return content.Error(err, code)
+	}
+}
+
+func deadLetterName(task string) string {
+	return task + ".json"
+}
+
+func readDeadLetter(ctx context.Context, bucket storage.BucketHandle, task string) (*deadLetter, error) {
+	in, err := bucket.Object(deadLetterName(task)).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	var dl deadLetter
+	if err := json.NewDecoder(in).Decode(&dl); err != nil {
+		return nil, fmt.Errorf("dead letter for %s: %v", task, err)
+	}
+	return &dl, nil
+}
+
+func writeDeadLetter(ctx context.Context, bucket storage.BucketHandle, dl *deadLetter) error {
+	out, err := bucket.Object(deadLetterName(dl.Task)).NewWriter(ctx)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(dl); err != nil {
+		return err
+	}
+	return out.Close()
+}
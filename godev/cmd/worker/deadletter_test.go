@@ -0,0 +1,88 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs := 0
+	h := withDeadLetters(bucket, func(w http.ResponseWriter, r *http.Request) error {
+		runs++
+		switch r.URL.Query().Get("fail") {
+		case "invalid":
+			return fmt.Errorf("reading: %w: bad JSON", charts.ErrInvalid)
+		case "conflict":
+			return content.Error(errors.New("changed meanwhile"), http.StatusConflict)
+		case "storage":
+			return errors.New("storage unavailable")
+		case "param":
+			return content.Error(errors.New("bad date"), http.StatusBadRequest)
+		}
+		return content.Text(w, "ok", http.StatusOK)
+	})
+	serve := func(url, task string) int {
+		req := httptest.NewRequest("POST", url, nil)
+		if task != "" {
+			req.Header.Set(taskNameHeader, task)
+			req.Header.Set(taskRetryCountHeader, "2")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Retryable failures are not dead letters.
+	for _, fail := range []string{"conflict", "storage"} {
+		serve("/chart/?fail="+fail, "retryable-"+fail)
+		if _, err := readDeadLetter(ctx, bucket, "retryable-"+fail); !errors.Is(err, storage.ErrObjectNotExist) {
+			t.Errorf("%s failure: readDeadLetter = %v, want ErrObjectNotExist", fail, err)
+		}
+	}
+
+	// A permanent failure is recorded, and retries of the task fail without
+	// running it.
+	if code := serve("/chart/?fail=invalid", "t1"); code != http.StatusUnprocessableEntity {
+		t.Errorf("invalid data: status = %d, want %d", code, http.StatusUnprocessableEntity)
+	}
+	dl, err := readDeadLetter(ctx, bucket, "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dl.Task != "t1" || dl.Method != "POST" || dl.URL != "/chart/?fail=invalid" || dl.Status != http.StatusUnprocessableEntity || dl.Retries != 2 || dl.Error == "" {
+		t.Errorf("dead letter = %+v", dl)
+	}
+	runs = 0
+	if code := serve("/chart/?fail=invalid", "t1"); code != http.StatusUnprocessableEntity || runs != 0 {
+		t.Errorf("retry of a dead letter: status = %d after %d runs, want %d after 0", code, runs, http.StatusUnprocessableEntity)
+	}
+	// Replays, which are not made by Cloud Tasks, run.
+	if code := serve("/chart/", ""); code != http.StatusOK || runs != 1 {
+		t.Errorf("replay: status = %d after %d runs, want %d after 1", code, runs, http.StatusOK)
+	}
+
+	// Invalid requests keep their status.
+	if code := serve("/merge/?fail=param", "t2"); code != http.StatusBadRequest {
+		t.Errorf("invalid request: status = %d, want %d", code, http.StatusBadRequest)
+	}
+	if _, err := readDeadLetter(ctx, bucket, "t2"); err != nil {
+		t.Errorf("invalid request: readDeadLetter failed: %v", err)
+	}
+}
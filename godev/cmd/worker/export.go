@@ -0,0 +1,242 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/config"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+	bigquery "google.golang.org/api/bigquery/v2"
+)
+
+const (
+	// exportPrefix is the prefix of the BigQuery load files in the merge
+	// bucket. A load file is deleted once it is loaded; see handleExport.
+	exportPrefix = "bigquery/"
+
+	// exportTable is the table of the BigQuery dataset into which merged
+	// reports are loaded. It is partitioned by date.
+	exportTable = "counters"
+
+	// exportPollInterval is the interval at which the state of a BigQuery
+	// load job is checked.
+	exportPollInterval = 5 * time.Second
+)
+
+// An exportRow is a row of the BigQuery table: a counter of a program in a
+// merged report.
+type exportRow struct {
+	Date      string  `json:"date"`
+	X         float64 `json:"x"` // identifies the report among those of the date
+	Config    string  `json:"config"`
+	Program   string  `json:"program"`
+	Version   string  `json:"version"`
+	GoVersion string  `json:"go_version"`
+	GOOS      string  `json:"goos"`
+	GOARCH    string  `json:"goarch"`
+	Kind      string  `json:"kind"` // "counter", "stack", or "event"
+	Counter   string  `json:"counter"`
+	Stack     string  `json:"stack,omitempty"` // for stack counters, the stack
+	Value     int64   `json:"value"`
+}
+
+// exportSchema is the schema of the BigQuery table, matching exportRow.
+var exportSchema = &bigquery.TableSchema{
+	Fields: []*bigquery.TableFieldSchema{
+		{Name: "date", Type: "DATE", Mode: "REQUIRED"},
+		{Name: "x", Type: "FLOAT", Mode: "REQUIRED"},
+		{Name: "config", Type: "STRING"},
+		{Name: "program", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "version", Type: "STRING"},
+		{Name: "go_version", Type: "STRING"},
+		{Name: "goos", Type: "STRING"},
+		{Name: "goarch", Type: "STRING"},
+		{Name: "kind", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "counter", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "stack", Type: "STRING"},
+		{Name: "value", Type: "INTEGER", Mode: "REQUIRED"},
+	},
+}
+
+// handleExport loads the merged reports of the date given by the "date"
+// query parameter into the BigQuery dataset of the config, so that the
+// public data can be analyzed with SQL.
+//
+// The reports are flattened into a row per counter of each program, written
+// as newline-delimited JSON to a load file in the merge bucket, and then
+// loaded into the date's partition of the table, replacing any rows exported
+// before. The load file is deleted after the load, so that it does not
+// outlive the rows if the report is removed. The load is skipped, and the
+// file kept for inspection, if no dataset is configured, or if the worker
+// does not use Cloud Storage, from which BigQuery loads files.
+func handleExport(cfg *config.Config, s *storage.API) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		date, err := time.Parse(telemetry.DateOnly, r.URL.Query().Get("date"))
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		name := exportPrefix + date.Format(telemetry.DateOnly) + ".json"
+		rows, err := writeExportRows(ctx, s, date, name)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return content.Error(err, http.StatusNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("wrote %d rows to %s/%s", rows, s.Merge.URI(), name)
+		switch {
+		case cfg.BigQueryDataset == "":
+			msg += "; no BigQuery dataset is configured"
+		case !cfg.UseGCS:
+			msg += "; BigQuery only loads files from Cloud Storage"
+		default:
+			source := "gs://" + cfg.MergedBucket + "/" + name
+			if err := loadExport(ctx, cfg, source, date); err != nil {
+				return fmt.Errorf("loading %s into BigQuery: %w", source, err)
+			}
+			if err := s.Merge.Delete(ctx, name); err != nil {
+				return err
+			}
+			msg += fmt.Sprintf("; loaded into %s.%s", cfg.BigQueryDataset, exportTable)
+		}
+		return content.Text(w, msg, http.StatusOK)
+	}
+}
+
+// writeExportRows writes the rows of the merged reports of date to the
+// named object of the merge bucket, and returns the number of rows.
+func writeExportRows(ctx context.Context, s *storage.API, date time.Time, name string) (int, error) {
+	// The load file is only replaced if all the rows are written: canceling
+	// the writer's context on failure abandons the new object.
+	wctx, cancel := context.WithCancel(ctx)
+	out, err := s.Merge.Object(name).NewWriter(wctx)
+	if err != nil {
+		cancel()
+		return 0, err
+	}
+	defer out.Close()
+	defer cancel()
+
+	bw := bufio.NewWriter(out)
+	enc := json.NewEncoder(bw)
+	var rows int
+	var encErr error
+	err = charts.ScanMerged(ctx, s.Merge, date, func(report *telemetry.Report) {
+		if encErr != nil {
+			return
+		}
+		for _, row := range exportRows(date, report) {
+			if encErr = enc.Encode(row); encErr != nil {
+				return
+			}
+			rows++
+		}
+	})
+	if err == nil {
+		err = encErr
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return rows, out.Close()
+}
+
+// exportRows flattens a report into rows, ordered by program and counter.
+func exportRows(date time.Time, r *telemetry.Report) []exportRow {
+	var rows []exportRow
+	for _, p := range r.Programs {
+		row := exportRow{
+			Date:      date.Format(telemetry.DateOnly),
+			X:         r.X,
+			Config:    r.Config,
+			Program:   p.Program,
+			Version:   p.Version,
+			GoVersion: p.GoVersion,
+			GOOS:      p.GOOS,
+			GOARCH:    p.GOARCH,
+		}
+		add := func(kind string, counts map[string]int64) {
+			var names []string
+			for name := range counts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				row.Kind, row.Counter, row.Stack, row.Value = kind, name, "", counts[name]
+				if kind == "stack" {
+					row.Counter, row.Stack, _ = strings.Cut(name, "\n")
+				}
+				rows = append(rows, row)
+			}
+		}
+		add("counter", p.Counters)
+		add("stack", p.Stacks)
+		add("event", p.Events)
+	}
+	return rows
+}
+
+// loadExport loads the load file at the Cloud Storage URI source into the
+// partition of the export table for date, and waits for the load to finish.
+func loadExport(ctx context.Context, cfg *config.Config, source string, date time.Time) error {
+	svc, err := bigquery.NewService(ctx)
+	if err != nil {
+		return err
+	}
+	job, err := svc.Jobs.Insert(cfg.ProjectID, &bigquery.Job{
+		Configuration: &bigquery.JobConfiguration{
+			Load: &bigquery.JobConfigurationLoad{
+				SourceUris:   []string{source},
+				SourceFormat: "NEWLINE_DELIMITED_JSON",
+				Schema:       exportSchema,
+				DestinationTable: &bigquery.TableReference{
+					ProjectId: cfg.ProjectID,
+					DatasetId: cfg.BigQueryDataset,
+					// The partition decorator limits the replacement of
+					// rows to those of the date.
+					TableId: exportTable + "$" + date.Format("20060102"),
+				},
+				TimePartitioning:  &bigquery.TimePartitioning{Type: "DAY", Field: "date"},
+				CreateDisposition: "CREATE_IF_NEEDED",
+				WriteDisposition:  "WRITE_TRUNCATE",
+			},
+		},
+	}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	for job.Status == nil || job.Status.State != "DONE" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(exportPollInterval):
+		}
+		job, err = svc.Jobs.Get(cfg.ProjectID, job.JobReference.JobId).
+			Location(job.JobReference.Location).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+	}
+	if e := job.Status.ErrorResult; e != nil {
+		return fmt.Errorf("job %s: %s", job.JobReference.JobId, e.Message)
+	}
+	return nil
+}
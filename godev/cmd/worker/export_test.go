@@ -0,0 +1,103 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/godev/internal/config"
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &storage.API{Merge: bucket}
+	w, err := s.Merge.Object("2024-01-01.json").NewWriter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, `{"Week":"2024-01-01","X":0.5,"Config":"v0.0.1","Programs":[{"Program":"cmd/go","Version":"go1.22.0","GoVersion":"go1.22.0","GOOS":"linux","GOARCH":"amd64","Counters":{"go/invocations":3,"go/build":1},"Stacks":{"go/bug\nmain.main":2}}]}`+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a dataset, the rows are written but not loaded.
+	rec := httptest.NewRecorder()
+	handleExport(&config.Config{}, s).ServeHTTP(rec, httptest.NewRequest("POST", "/export/?date=2024-01-01", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("export status = %d: %s", rec.Code, rec.Body)
+	}
+
+	in, err := s.Merge.Object(exportPrefix + "2024-01-01.json").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	var got []exportRow
+	dec := json.NewDecoder(in)
+	for {
+		var row exportRow
+		if err := dec.Decode(&row); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, row)
+	}
+	row := exportRow{Date: "2024-01-01", X: 0.5, Config: "v0.0.1", Program: "cmd/go", Version: "go1.22.0", GoVersion: "go1.22.0", GOOS: "linux", GOARCH: "amd64"}
+	with := func(kind, counter, stack string, value int64) exportRow {
+		r := row
+		r.Kind, r.Counter, r.Stack, r.Value = kind, counter, stack, value
+		return r
+	}
+	want := []exportRow{
+		with("counter", "go/build", "", 1),
+		with("counter", "go/invocations", "", 3),
+		with("stack", "go/bug", "main.main", 2),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("exported rows mismatch (-want +got):\n%s", diff)
+	}
+
+	rec = httptest.NewRecorder()
+	handleExport(&config.Config{}, s).ServeHTTP(rec, httptest.NewRequest("POST", "/export/?date=2024-01-02", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("export of a date without merged reports: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if _, err := s.Merge.Object(exportPrefix + "2024-01-02.json").NewReader(ctx); !errors.Is(err, storage.ErrObjectNotExist) {
+		t.Errorf("export of a date without merged reports wrote a load file (err = %v)", err)
+	}
+}
+
+func TestQueuedExport(t *testing.T) {
+	cfg := &config.Config{WorkerURL: "w", Env: "prod", MaxReportAgeDays: 21, BigQueryDataset: "telemetry"}
+	now := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	var exports []string
+	for _, url := range queuedTasks(cfg, now) {
+		if strings.HasPrefix(url, "w/export/") {
+			exports = append(exports, url)
+		}
+	}
+	// The date most recently charted.
+	if want := []string{"w/export/?date=2024-01-10"}; !slices.Equal(exports, want) {
+		t.Errorf("queued exports = %v, want %v", exports, want)
+	}
+}
@@ -5,18 +5,18 @@
 package main
 
 import (
-	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"go/version"
+	"io"
 	"io/fs"
 	"log"
 	"net/http"
 	"net/url"
-	"os"
 	"sort"
 	"strings"
 	"time"
@@ -24,17 +24,23 @@ import (
 	cloudtasks "cloud.google.com/go/cloudtasks/apiv2"
 	taskspb "cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
 	"golang.org/x/exp/slog"
-	"golang.org/x/mod/semver"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/telemetry/godev/internal/charts"
 	"golang.org/x/telemetry/godev/internal/config"
 	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/health"
 	ilog "golang.org/x/telemetry/godev/internal/log"
+	"golang.org/x/telemetry/godev/internal/metrics"
 	"golang.org/x/telemetry/godev/internal/middleware"
+	"golang.org/x/telemetry/godev/internal/server"
 	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/chartconfig"
 	tconfig "golang.org/x/telemetry/internal/config"
 	contentfs "golang.org/x/telemetry/internal/content"
 	"golang.org/x/telemetry/internal/telemetry"
 	"golang.org/x/telemetry/internal/unionfs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func main() {
@@ -50,29 +56,68 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	// Storage reads and writes are counted for the endpoint of the request.
+	buckets = metrics.Instrument(buckets)
 	ucfg, err := tconfig.ReadConfig(cfg.UploadConfig)
 	if err != nil {
 		log.Fatal(err)
 	}
+	ccfgs, err := chartconfig.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	rules := charts.NewRules(ccfgs)
 	fsys := fsys(cfg.DevMode)
 	cserv := content.Server(fsys)
 	mux := http.NewServeMux()
 
+	// Charts, new counter indexes, and time series read many merged reports,
+	// so they share a limit.
+	chartLimit := middleware.ConcurrencyLimit(int(cfg.MaxConcurrentCharts), cfg.RetryAfter)
+	mergeLimit := middleware.ConcurrencyLimit(int(cfg.MaxConcurrentMerges), cfg.RetryAfter)
+	reg := metrics.NewRegistry()
+	task := reg.Middleware
+
 	mux.Handle("/", cserv)
-	mux.Handle("/merge/", handleMerge(buckets))
-	mux.Handle("/chart/", handleChart(ucfg, buckets))
-	mux.Handle("/queue-tasks/", handleTasks(cfg))
-	mux.Handle("/copy/", handleCopy(cfg, buckets))
+	mux.Handle("/merge/", task("merge")(mergeLimit(withDeadLetters(buckets.DeadLetter, handleMerge(ucfg, rules.Retirements, buckets)))))
+	mux.Handle("/chart/", task("chart")(chartLimit(withDeadLetters(buckets.DeadLetter, handleChart(ucfg, rules, buckets)))))
+	mux.Handle("/queue-tasks/", task("queue-tasks")(handleTasks(cfg)))
+	mux.Handle("/copy/", task("copy")(handleCopy(cfg, buckets)))
+	mux.Handle("/newcounters/", task("newcounters")(chartLimit(withDeadLetters(buckets.DeadLetter, handleNewCounters(buckets)))))
+	mux.Handle("/timeseries/", task("timeseries")(chartLimit(withDeadLetters(buckets.DeadLetter, handleTimeSeries(buckets)))))
+	mux.Handle("/snapshot/", task("snapshot")(withDeadLetters(buckets.DeadLetter, handleSnapshot(buckets))))
+	mux.Handle("/export/", task("export")(withDeadLetters(buckets.DeadLetter, handleExport(cfg, buckets))))
+	mux.Handle("/verify/", task("verify")(handleVerify(buckets)))
+	mux.Handle("/retention/", task("retention")(withDeadLetters(buckets.DeadLetter, handleRetention(cfg, buckets))))
+	mux.Handle("/healthz", health.Live())
+	mux.Handle("/readyz", health.Ready(append(health.Buckets(buckets), health.UploadConfig(cfg.UploadConfig))...))
+	mux.Handle("/metrics", reg.Handler())
 
 	mw := middleware.Chain(
+		middleware.RequestID(),
 		middleware.Log(slog.Default()),
 		middleware.Timeout(cfg.RequestTimeout),
 		middleware.RequestSize(cfg.MaxRequestBytes),
 		middleware.Recover(),
 	)
+	// Backfills stream their progress and may run for longer than the
+	// request timeout, whose handler buffers responses, so they are served
+	// without it.
+	backfillMW := middleware.Chain(
+		task("backfill"),
+		middleware.RequestID(),
+		middleware.Log(slog.Default()),
+		middleware.RequestSize(cfg.MaxRequestBytes),
+		middleware.Recover(),
+	)
+	root := http.NewServeMux()
+	root.Handle("/backfill/", backfillMW(handleBackfill(ucfg, rules, buckets)))
+	root.Handle("/", mw(mux))
 
 	fmt.Printf("server listening at http://localhost:%s\n", cfg.WorkerPort)
-	log.Fatal(http.ListenAndServe(":"+cfg.WorkerPort, mw(mux)))
+	if err := server.Serve(ctx, ":"+cfg.WorkerPort, root, cfg.DrainTimeout); err != nil {
+		log.Fatal(err)
+	}
 }
 
 // handleCopy copies uploaded reports from prod gcs bucket to dev gcs buckets.
@@ -102,83 +147,178 @@ func handleCopy(cfg *config.Config, dest *storage.API) content.HandlerFunc {
 		g, ctx := errgroup.WithContext(ctx)
 		g.SetLimit(concurrency)
 
+		copied, skipped := 0, 0
 		for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
-			it := sourceBucket.Objects(ctx, date.Format(telemetry.DateOnly))
+			prefix := date.Format(telemetry.DateOnly)
+			// Uploads are not rewritten, so those already copied, with the
+			// same size, are skipped.
+			sizes, err := objectSizes(ctx, destBucket, prefix)
+			if err != nil {
+				g.Wait()
+				return err
+			}
+			it := sourceBucket.ObjectsWithAttrs(ctx, prefix)
 			for {
-				fileName, err := it.Next()
+				attrs, err := it.Next()
 				if errors.Is(err, storage.ErrObjectIteratorDone) {
 					break
 				}
+				if err != nil {
+					g.Wait()
+					return err
+				}
+				if size, ok := sizes[attrs.Name]; ok && size == attrs.Size {
+					skipped++
+					continue
+				}
+				copied++
 				g.Go(func() error {
-					if err != nil {
-						return err
-					}
-					return storage.Copy(ctx, destBucket.Object(fileName), sourceBucket.Object(fileName))
+					return storage.Copy(ctx, destBucket.Object(attrs.Name), sourceBucket.Object(attrs.Name))
 				})
 			}
 		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("copied %d uploads from %s to %s (skipped %d already copied)", copied, sourceBucket.URI(), destBucket.URI(), skipped)
+		return content.Text(w, msg, http.StatusOK)
+	}
+}
 
-		return g.Wait()
+// objectSizes returns the sizes of the objects in bucket with the given
+// prefix, keyed by name.
+func objectSizes(ctx context.Context, bucket storage.BucketHandle, prefix string) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+	it := bucket.ObjectsWithAttrs(ctx, prefix)
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			return sizes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		sizes[attrs.Name] = attrs.Size
 	}
 }
 
 // handleTasks will populate the task queue that processes report
 // data. Cloud Scheduler will be instrumented to call this endpoint
 // daily to copy uploaded reports, merge reports and generate chart data.
-// The copy tasks will copy uploaded data from prod to dev.
-// The merge tasks will merge the previous 7 days reports.
-// The chart tasks generate daily and weekly charts for the 7 days preceding
-// today.
-// - Daily chart: utilizes data exclusively from the specific date.
-// - Weekly chart: encompasses 7 days of data, concluding on the specified date.
-// TODO(golang/go#62575): adjust the date range to align with report
-// upload cutoff.
+// The tasks are those of queuedTasks.
 //
 // TODO(rfindley): use a local task queue when not run with -gcs.
 func handleTasks(cfg *config.Config) content.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
 		now := time.Now().UTC()
-
-		// Copy the past 20 days uploaded reports from prod to dev gcs bucket.
-		if cfg.Env != "prod" {
-			url := cfg.WorkerURL + "/copy/?start=" + now.AddDate(0, 0, -1*20).Format(telemetry.DateOnly) + "&end=" + now.Format(telemetry.DateOnly)
-			if _, err := createHTTPTask(cfg, url); err != nil {
+		for _, url := range queuedTasks(cfg, now) {
+			if _, err := createHTTPTask(ctx, cfg, now, url); err != nil {
 				return err
 			}
 		}
-		for i := 7; i > 0; i-- {
-			date := now.AddDate(0, 0, -1*i).Format(telemetry.DateOnly)
-			url := cfg.WorkerURL + "/merge/?date=" + date
-			if _, err := createHTTPTask(cfg, url); err != nil {
-				return err
-			}
+		return nil
+	}
+}
+
+// queuedTasks returns the URLs of the tasks that handleTasks queues at time
+// now.
+//
+// Reports are stored under the date on which their week ends, and the server
+// accepts them until MaxReportAgeDays after that date, so the reports of a
+// date are complete only once that cutoff has passed.
+//
+//   - The copy tasks copy the uploads of the past 20 days from prod to dev.
+//   - The merge tasks merge the uploads of each date whose reports may still
+//     have arrived since the previous day, up to and including the most
+//     recent date whose cutoff has passed. Merges are incremental, so this
+//     is cheap for the dates with few new uploads.
+//   - The chart tasks chart the 7 most recent dates that were completely
+//     merged by the previous day's tasks: the daily chart of each date, and
+//     the weekly chart of the 7 days ending on it. Charts are thus computed
+//     from complete weeks of uploads, rather than from the few days of
+//     uploads that have arrived for recent dates.
+//   - The new counter and time series tasks cover the week among those
+//     charted that ends on a Sunday, so that each week is counted once.
+//   - The snapshot tasks write downloadable snapshots of the merged reports
+//     of that week, and of the month among those charted that ends, if any.
+func queuedTasks(cfg *config.Config, now time.Time) []string {
+	var urls []string
+	date := func(t time.Time) string { return t.Format(telemetry.DateOnly) }
+
+	// Copy the past 20 days uploaded reports from prod to dev gcs bucket.
+	if cfg.Env != "prod" {
+		urls = append(urls, cfg.WorkerURL+"/copy/?start="+date(now.AddDate(0, 0, -1*20))+"&end="+date(now))
+	}
+
+	cutoff := lastCompleteDate(now, time.Duration(cfg.MaxReportAgeDays)*24*time.Hour)
+	for d := cutoff; d.Before(now.Truncate(24 * time.Hour)); d = d.AddDate(0, 0, 1) {
+		urls = append(urls, cfg.WorkerURL+"/merge/?date="+date(d))
+	}
+
+	for i := 7; i > 0; i-- {
+		// Daily chart: generate chart using one day's data.
+		end := cutoff.AddDate(0, 0, -i)
+		urls = append(urls, cfg.WorkerURL+"/chart/?date="+date(end))
+
+		// Weekly chart: generate chart using the 7 days' data ending on the
+		// date.
+		start := end.AddDate(0, 0, -6)
+		urls = append(urls, cfg.WorkerURL+"/chart/?start="+date(start)+"&end="+date(end))
+
+		// New counters and time series: only weeks ending on Sunday are
+		// indexed, so that each counter is reported as new exactly once.
+		if end.Weekday() == time.Sunday {
+			urls = append(urls,
+				cfg.WorkerURL+"/newcounters/?date="+date(end),
+				cfg.WorkerURL+"/timeseries/?date="+date(end),
+				cfg.WorkerURL+"/snapshot/?start="+date(start)+"&end="+date(end))
 		}
-		// TODO(hxjiang): have an endpoint to produce all the json instead of a hard
-		// coded one day delay.
-		for i := 8; i > 1; i-- {
-			// Daily chart: generate chart using one day's data.
-			date := now.AddDate(0, 0, -1*i).Format(telemetry.DateOnly)
-			url := cfg.WorkerURL + "/chart/?date=" + date
-			if _, err := createHTTPTask(cfg, url); err != nil {
-				return err
-			}
 
-			// Weekly chart: generate chart using past 7 days' data.
-			end := now.AddDate(0, 0, -1*i)
-			start := end.AddDate(0, 0, -6)
-			url = cfg.WorkerURL + "/chart/?start=" + start.Format(telemetry.DateOnly) + "&end=" + end.Format(telemetry.DateOnly)
-			if _, err := createHTTPTask(cfg, url); err != nil {
-				return err
-			}
+		// Monthly snapshot: on the last day of a month.
+		if end.AddDate(0, 0, 1).Day() == 1 {
+			monthStart := end.AddDate(0, 0, 1-end.Day())
+			urls = append(urls, cfg.WorkerURL+"/snapshot/?start="+date(monthStart)+"&end="+date(end))
 		}
-		return nil
 	}
+
+	// BigQuery export: each date once, when it is first charted.
+	if cfg.BigQueryDataset != "" {
+		urls = append(urls, cfg.WorkerURL+"/export/?date="+date(cutoff.AddDate(0, 0, -1)))
+	}
+
+	// Retention: delete expired uploads and dead letters.
+	if cfg.UploadRetentionDays > 0 || cfg.DeadLetterRetentionDays > 0 {
+		urls = append(urls, cfg.WorkerURL+"/retention/")
+	}
+	return urls
+}
+
+// lastCompleteDate returns the most recent date whose reports the server no
+// longer accepts at time now, given that it accepts a report until maxAge
+// after the start of the date on which its week ends.
+func lastCompleteDate(now time.Time, maxAge time.Duration) time.Time {
+	t := now.Add(-maxAge)
+	d := t.Truncate(24 * time.Hour)
+	if d.Equal(t) {
+		// Reports of the date are accepted until the instant t itself.
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
 }
 
 // createHTTPTask constructs a task with a authorization token
 // and HTTP target then adds it to a Queue.
-func createHTTPTask(cfg *config.Config, url string) (*taskspb.Task, error) {
-	ctx := context.Background()
+//
+// The task is created using the given context, so that a canceled or timed out
+// request does not continue to queue work.
+//
+// The task is named by taskID, which is also its request ID, so that the logs
+// of all its attempts share the ID. If a task of the same name was created
+// recently, for example by a retried invocation of /queue-tasks on the same
+// day, Cloud Tasks rejects the new task, and createHTTPTask returns a nil
+// task and no error.
+func createHTTPTask(ctx context.Context, cfg *config.Config, now time.Time, url string) (*taskspb.Task, error) {
 	client, err := cloudtasks.NewClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cloudtasks.NewClient: %w", err)
@@ -186,13 +326,16 @@ func createHTTPTask(cfg *config.Config, url string) (*taskspb.Task, error) {
 	defer client.Close()
 
 	queuePath := fmt.Sprintf("projects/%s/locations/%s/queues/%s", cfg.ProjectID, cfg.LocationID, cfg.QueueID)
+	id := taskID(now, url)
 	req := &taskspb.CreateTaskRequest{
 		Parent: queuePath,
 		Task: &taskspb.Task{
+			Name: queuePath + "/tasks/" + id,
 			MessageType: &taskspb.Task_HttpRequest{
 				HttpRequest: &taskspb.HttpRequest{
 					HttpMethod: taskspb.HttpMethod_POST,
 					Url:        url,
+					Headers:    map[string]string{middleware.RequestIDHeader: id},
 					AuthorizationHeader: &taskspb.HttpRequest_OidcToken{
 						OidcToken: &taskspb.OidcToken{
 							ServiceAccountEmail: cfg.IAPServiceAccount,
@@ -205,59 +348,262 @@ func createHTTPTask(cfg *config.Config, url string) (*taskspb.Task, error) {
 	}
 
 	createdTask, err := client.CreateTask(ctx, req)
+	if status.Code(err) == codes.AlreadyExists {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("cloudtasks.CreateTask: %w", err)
 	}
 	return createdTask, nil
 }
 
-// TODO: monitor duration and processed data volume.
-func handleMerge(s *storage.API) content.HandlerFunc {
+// taskID returns the ID of the task that requests url when queued on the
+// date of now, such as "6ae21be4-merge-date-2024-01-01-on-2024-01-08". The
+// same work queued on the same day has the same ID. The hash prefix spreads
+// the IDs, as Cloud Tasks recommends for named tasks.
+func taskID(now time.Time, rawURL string) string {
+	id := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		parts := []string{strings.Trim(u.Path, "/")}
+		q := u.Query()
+		var keys []string
+		for k := range q {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			parts = append(parts, k, strings.Join(q[k], "_"))
+		}
+		id = strings.Join(parts, "-")
+	}
+	id += "-on-" + now.Format(telemetry.DateOnly)
+	// Task IDs may only hold letters, digits, hyphens, and underscores.
+	id = strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:4]) + "-" + id
+}
+
+// handleMerge merges the reports uploaded on the date given by the "date"
+// query parameter into a single object in the merge bucket, and records the
+// data quality of the uploads in the chart bucket and their statistics in the
+// stats bucket.
+//
+// Merges are incremental: the upload objects processed by the previous merge
+// of the date are listed in its manifest, and only the objects uploaded
+// since are read and appended to the merged reports. The "full" query
+// parameter forces a merge of all the uploads of the date.
+//
+// Counters retired by the chart config are redacted from the merged reports,
+// including from those merged before the counters were retired, and the
+// redactions are recorded in the data quality of the date.
+//
+// The duration of merges and the volume of data they read and write are
+// exported at /metrics.
+func handleMerge(cfg *tconfig.Config, rt charts.Retirements, s *storage.API) content.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 		date := r.URL.Query().Get("date")
 		if _, err := time.Parse(telemetry.DateOnly, date); err != nil {
 			return content.Error(err, http.StatusBadRequest)
 		}
-		it := s.Upload.Objects(ctx, date)
-		mergeWriter, err := s.Merge.Object(date + ".json").NewWriter(ctx)
+		full := r.URL.Query().Get("full") != ""
+		msg, err := merge(ctx, cfg, rt, s, date, full)
 		if err != nil {
 			return err
 		}
-		defer mergeWriter.Close()
-		encoder := json.NewEncoder(mergeWriter)
-		var count int
-		for {
-			obj, err := it.Next()
-			if errors.Is(err, storage.ErrObjectIteratorDone) {
-				break
-			}
-			if err != nil {
-				return err
-			}
-			count++
-			reader, err := s.Upload.Object(obj).NewReader(ctx)
-			if err != nil {
-				return err
-			}
-			defer reader.Close()
-			var report telemetry.Report
-			if err := json.NewDecoder(reader).Decode(&report); err != nil {
-				return err
-			}
-			if err := encoder.Encode(report); err != nil {
-				return err
-			}
-			if err := reader.Close(); err != nil {
-				return err
+		return content.Text(w, msg, http.StatusOK)
+	}
+}
+
+// merge merges the uploads of date, starting over if the merge object does
+// not match its manifest, and returns a description of what it did.
+func merge(ctx context.Context, cfg *tconfig.Config, rt charts.Retirements, s *storage.API, date string, full bool) (string, error) {
+	q, added, err := mergeDate(ctx, cfg, rt, s, date, full)
+	if errors.Is(err, errStaleManifest) {
+		q, added, err = mergeDate(ctx, cfg, rt, s, date, true)
+	}
+	if err != nil {
+		return "", err
+	}
+	msg := fmt.Sprintf("merged %d reports (%d new) into %s/%s (excluded %d suspect and %d malformed reports)", q.Merged, added, s.Merge.URI(), date, q.Suspect, q.Malformed)
+	if len(q.Redacted) > 0 {
+		msg += fmt.Sprintf("; redacted %d retired counters", len(q.Redacted))
+	}
+	return msg, nil
+}
+
+// errStaleManifest reports that the merge object for a date does not hold
+// the reports that its manifest records.
+var errStaleManifest = errors.New("merge object does not match its manifest")
+
+// mergeDate merges the uploads of date, as described at handleMerge, and
+// returns the data quality of the uploads and the number of reports added
+// to the merge object. If full is set, all the uploads are merged, unless
+// the retention task deleted some of them. Otherwise, if the merge object
+// does not match its manifest, mergeDate fails with errStaleManifest, and
+// leaves the merge object unchanged.
+//
+// Reports are streamed from the previous merge object and the uploads to the
+// new merge object, so memory use does not grow with the number of reports.
+func mergeDate(ctx context.Context, cfg *tconfig.Config, rt charts.Retirements, s *storage.API, date string, full bool) (_ *dataQuality, added int, _ error) {
+	// The merge object is written on the condition that it has not changed
+	// since now, before its manifest and reports are read, so that of two
+	// concurrent merges of the date, only one succeeds.
+	gen, err := s.Merge.Object(date + ".json").Generation(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		gen = 0
+	} else if err != nil {
+		return nil, 0, err
+	}
+	quality := newQualityTracker(cfg, date)
+	stats := newStatsTracker(cfg, date)
+	manifest := &mergeManifest{}
+	m, err := readManifest(ctx, s.Merge, date)
+	if err != nil {
+		return nil, 0, err
+	}
+	if m != nil && m.UploadsDeleted {
+		// The uploads that were deleted can not be merged again, so the
+		// merge is incremental even if a full merge was requested.
+		full = false
+	}
+	if m != nil && !full {
+		manifest = m
+	}
+	if manifest.Suspect == nil {
+		manifest.Suspect = make(map[string]string)
+	}
+
+	// The merge object is only replaced if the merge succeeds: canceling
+	// the writer's context on failure abandons the new object.
+	wctx, cancel := context.WithCancel(ctx)
+	mergeWriter, err := s.Merge.Object(date+".json").NewWriterIf(wctx, gen)
+	if err != nil {
+		cancel()
+		return nil, 0, err
+	}
+	defer mergeWriter.Close()
+	defer cancel()
+	digest := sha256.New()
+	out := io.MultiWriter(mergeWriter, digest)
+
+	if len(manifest.Objects) > 0 {
+		// Copy the reports of the previous merge, and count them again, so
+		// that the data quality and statistics cover all the uploads of the
+		// date.
+		// Retired counters are redacted from the copied reports too, so
+		// that merging a date again removes them from its merge object.
+		n, err := copyMerged(ctx, s.Merge, date, out, func(report *telemetry.Report) bool {
+			redacted := rt.Redact(report)
+			quality.redacted(redacted)
+			quality.merged(report)
+			stats.merged(report)
+			return len(redacted) > 0
+		})
+		if errors.Is(err, storage.ErrObjectNotExist) || err == nil && n != manifest.Merged {
+			if manifest.UploadsDeleted {
+				return nil, 0, content.Error(fmt.Errorf("merge object for %s does not match its manifest, and its uploads were deleted", date), http.StatusUnprocessableEntity)
 			}
+			return nil, 0, errStaleManifest
 		}
-		if err := mergeWriter.Close(); err != nil {
-			return err
+		if err != nil {
+			return nil, 0, err
 		}
-		msg := fmt.Sprintf("merged %d reports into %s/%s", count, s.Merge.URI(), date)
-		return content.Text(w, msg, http.StatusOK)
+		for _, reasons := range manifest.Suspect {
+			quality.q.Suspect++
+			stats.suspect(reasons)
+		}
+		for range manifest.Malformed {
+			quality.q.Malformed++
+			stats.malformed()
+		}
+	}
+	processed := make(map[string]bool)
+	for _, obj := range manifest.Objects {
+		processed[obj] = true
 	}
+
+	it := s.Upload.Objects(ctx, date)
+	encoder := json.NewEncoder(out)
+	for {
+		obj, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if processed[obj] {
+			continue
+		}
+		manifest.Objects = append(manifest.Objects, obj)
+		// Exclude uploads that the server tagged as bot traffic or
+		// replays.
+		md, err := s.Upload.Object(obj).Metadata(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		if md[storage.SuspectMetadata] != "" {
+			quality.q.Suspect++
+			stats.suspect(md[storage.SuspectMetadata])
+			manifest.Suspect[obj] = md[storage.SuspectMetadata]
+			continue
+		}
+		reader, err := s.Upload.Object(obj).NewReader(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		// The reader is closed before the next upload is read, so that
+		// merging a date holds a single upload open.
+		var report telemetry.Report
+		decodeErr := json.NewDecoder(reader).Decode(&report)
+		if err := reader.Close(); err != nil {
+			return nil, 0, err
+		}
+		if decodeErr != nil {
+			quality.q.Malformed++
+			stats.malformed()
+			manifest.Malformed = append(manifest.Malformed, obj)
+			continue
+		}
+		quality.redacted(rt.Redact(&report))
+		quality.merged(&report)
+		stats.merged(&report)
+		if err := encoder.Encode(report); err != nil {
+			return nil, 0, err
+		}
+		added++
+	}
+	if err := mergeWriter.Close(); errors.Is(err, storage.ErrPreconditionFailed) {
+		// Another merge of the date finished first. Fail, so that the task
+		// is retried, and merges whatever that merge did not.
+		return nil, 0, content.Error(fmt.Errorf("merging %s: %w", date, err), http.StatusConflict)
+	} else if err != nil {
+		return nil, 0, err
+	}
+	if err := storage.WriteDigest(ctx, s.Merge, date+".json", digest.Sum(nil)); err != nil {
+		return nil, 0, err
+	}
+	// The manifest is written after the merge object. If that fails, the
+	// next merge finds that the counts disagree, and starts over.
+	manifest.Merged = quality.q.Merged
+	sort.Strings(manifest.Objects)
+	sort.Strings(manifest.Malformed)
+	if err := writeManifest(ctx, s.Merge, date, manifest); err != nil {
+		return nil, 0, err
+	}
+	if err := writeQuality(ctx, s.Chart, &quality.q); err != nil {
+		return nil, 0, err
+	}
+	if err := writeStats(ctx, s.Stats, &stats.s); err != nil {
+		return nil, 0, err
+	}
+	return &quality.q, added, nil
 }
 
 func fileName(start, end time.Time) string {
@@ -298,30 +644,17 @@ func parseDateRange(url *url.URL) (start, end time.Time, _ error) {
 	return start, end, nil
 }
 
-func readMergedReports(ctx context.Context, fileName string, s *storage.API) ([]telemetry.Report, error) {
-	in, err := s.Merge.Object(fileName).NewReader(ctx)
+// readMergedReports reads the reports merged for the given date, reporting
+// a missing merge file as 404 Not Found.
+func readMergedReports(ctx context.Context, date time.Time, s *storage.API) ([]telemetry.Report, error) {
+	reports, err := charts.ReadMerged(ctx, s.Merge, date)
 	if errors.Is(err, storage.ErrObjectNotExist) {
-		return nil, content.Error(fmt.Errorf("merge file %s not found", fileName), http.StatusNotFound)
-	}
-	if err != nil {
-		return nil, err
+		return nil, content.Error(err, http.StatusNotFound)
 	}
-	defer in.Close()
-
-	var reports []telemetry.Report
-	scanner := bufio.NewScanner(in)
-	for scanner.Scan() {
-		var report telemetry.Report
-		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil {
-			return nil, err
-		}
-		reports = append(reports, report)
-	}
-
-	return reports, nil
+	return reports, err
 }
 
-func handleChart(cfg *tconfig.Config, s *storage.API) content.HandlerFunc {
+func handleChart(cfg *tconfig.Config, rules *charts.Rules, s *storage.API) content.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		ctx := r.Context()
 
@@ -329,367 +662,81 @@ func handleChart(cfg *tconfig.Config, s *storage.API) content.HandlerFunc {
 		if err != nil {
 			return err
 		}
-
-		var reports []telemetry.Report
-		var xs []float64
-		for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
-			dailyReports, err := readMergedReports(ctx, date.Format(telemetry.DateOnly)+".json", s)
-			if err != nil {
-				return err
-			}
-			for _, r := range dailyReports {
-				reports = append(reports, r)
-				xs = append(xs, r.X)
-			}
-		}
-
-		data := group(reports)
-		charts := charts(cfg, start.Format(telemetry.DateOnly), end.Format(telemetry.DateOnly), data, xs)
-
-		obj := fileName(start, end)
-		out, err := s.Chart.Object(obj).NewWriter(ctx)
+		msg, err := writeCharts(ctx, cfg, rules, s, start, end)
 		if err != nil {
 			return err
 		}
-		defer out.Close()
-
-		if err := json.NewEncoder(out).Encode(charts); err != nil {
-			return err
-		}
-		if err := out.Close(); err != nil {
-			return err
-		}
-
-		msg := fmt.Sprintf("processed %d reports from date %s to %s into %s", len(reports), start.Format(telemetry.DateOnly), end.Format(telemetry.DateOnly), s.Chart.URI()+"/"+obj)
 		return content.Text(w, msg, http.StatusOK)
 	}
 }
 
-type chartdata struct {
-	DateRange  [2]string
-	Programs   []*program
-	NumReports int
-}
-
-type program struct {
-	ID     string
-	Name   string
-	Charts []*chart
-}
-
-type chart struct {
-	ID   string
-	Name string
-	Type string
-	Data []*datum
-}
-
-func (c *chart) String() string {
-	bytes, _ := json.Marshal(c)
-	return string(bytes)
-}
-
-type datum struct {
-	Week  string
-	Key   string
-	Value float64
-}
-
-func charts(cfg *tconfig.Config, start, end string, d data, xs []float64) *chartdata {
-	result := &chartdata{DateRange: [2]string{start, end}, NumReports: len(xs)}
-	for _, p := range cfg.Programs {
-		prog := &program{ID: "charts:" + p.Name, Name: p.Name}
-		result.Programs = append(result.Programs, prog)
-		var charts []*chart
-		program := programName(p.Name)
-		if !telemetry.IsToolchainProgram(p.Name) {
-			charts = append(charts, d.partition(program, versionCounter, toSliceOf[bucketName](p.Versions), partitionOptions{
-				ignoreEmptyBuckets: true,
-				// Don't normalize buckets: we want to see counts for all versions.
-				compareBuckets: compareSemver,
-			}))
-		}
-		charts = append(charts,
-			d.partition(program, goosCounter, toSliceOf[bucketName](cfg.GOOS), partitionOptions{}),
-			d.partition(program, goarchCounter, toSliceOf[bucketName](cfg.GOARCH), partitionOptions{}),
-			d.partition(program, goversionCounter, toSliceOf[bucketName](cfg.GoVersion), partitionOptions{
-				ignoreEmptyBuckets: true,
-				normalizeBucket: func(b bucketName) bucketName {
-					// map go1.2.3 -> go1.2
-					return bucketName(goMajorMinor(string(b)))
-				},
-				compareBuckets: version.Compare,
-			}))
-		for _, c := range p.Counters {
-			// TODO: add support for histogram counters by getting the counter type
-			// from the chart config.
-			chart, _ := splitCounterName(c.Name)
-			var buckets []bucketName
-			for _, counter := range tconfig.Expand(c.Name) {
-				_, bucket := splitCounterName(counter)
-				buckets = append(buckets, bucket)
-			}
-			charts = append(charts, d.partition(program, chart, buckets, partitionOptions{}))
+// writeCharts computes the charts for the dates from start to end inclusive
+// from their merged reports, writes them to the chart bucket, and returns a
+// description of what it did.
+func writeCharts(ctx context.Context, cfg *tconfig.Config, rules *charts.Rules, s *storage.API, start, end time.Time) (string, error) {
+	// Reports are added to the charts as they are read, so that charts
+	// for long date ranges do not need all their reports in memory.
+	b := charts.NewBuilder(cfg, rules)
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		err := charts.ScanMerged(ctx, s.Merge, date, b.Add)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return "", content.Error(err, http.StatusNotFound)
 		}
-		for _, p := range charts {
-			if p != nil {
-				prog.Charts = append(prog.Charts, p)
-			}
+		if err != nil {
+			return "", err
 		}
 	}
-	return result
-}
-
-// toSliceOf converts a slice of once string type to another.
-func toSliceOf[To, From ~string](s []From) []To {
-	var s2 []To
-	for _, v := range s {
-		s2 = append(s2, To(v))
-	}
-	return s2
-}
 
-// compareSemver wraps semver.Compare, to differentiate equivalent semver
-// lexically, as we want all sorting to be stable.
-func compareSemver(x, y string) int {
-	c := semver.Compare(x, y)
-	if c != 0 {
-		return c
-	}
-	return compareLexically(x, y)
-}
+	data := b.Charts(start, end)
 
-func compareLexically(x, y string) int {
-	switch {
-	case x < y:
-		return -1
-	case x == y:
-		return 0
-	default:
-		return 1
+	obj := fileName(start, end)
+	out, err := s.Chart.Object(obj).NewWriter(ctx)
+	if err != nil {
+		return "", err
 	}
-}
+	defer out.Close()
 
-// partitionOptions controls the behavior of partition charts
-type partitionOptions struct {
-	// If ignoreEmptyBuckets is set, don't include data points with 0 count.
-	ignoreEmptyBuckets bool
-
-	// If normalizeBuckets is provided, it is used to map bucket names to new
-	// values. Buckets that map to the same value will be merged.
-	normalizeBucket func(bucketName) bucketName // if set, used to
-
-	// If compareBuckets is provided, it is used to sort the buckets, where
-	// compareBuckets returns -1, 0, or +1 if x < y, x == y, or x > y.
-	// Otherwise, buckets are sorted lexically.
-	compareBuckets func(x, y string) int
-}
-
-// partition builds a chart for the program and the counter. It can return nil
-// if there is no data for the counter in d.
-func (d data) partition(program programName, chartName graphName, buckets []bucketName, opts partitionOptions) *chart {
-	chart := &chart{
-		ID:   fmt.Sprintf("charts:%s:%s", program, chartName),
-		Name: string(chartName),
-		Type: "partition",
+	digest := sha256.New()
+	if err := json.NewEncoder(io.MultiWriter(out, digest)).Encode(data); err != nil {
+		return "", err
 	}
-	pk := programName(program)
-
-	var (
-		merged = make(map[bucketName]map[reportID]struct{}) // normalized bucket name -> merged report IDs
-		empty  = true                                       // keep track of empty reports, so they can be skipped
-		end    weekName                                     // latest week observed
-	)
-	for wk := range d {
-		if wk >= end {
-			end = wk
-		}
-		// We group versions into major minor buckets, we must skip
-		// major minor versions we've already added to the dataset.
-		seen := make(map[bucketName]bool)
-		for _, bucket := range buckets {
-			if seen[bucket] {
-				continue
-			}
-			seen[bucket] = true
-			key := bucket
-			if opts.normalizeBucket != nil {
-				key = opts.normalizeBucket(bucket)
-			}
-			if _, ok := merged[key]; !ok {
-				merged[key] = make(map[reportID]struct{})
-			}
-			for id := range d[wk][pk][chartName][bucket] {
-				empty = false
-				merged[key][id] = struct{}{}
-			}
-		}
+	if err := out.Close(); err != nil {
+		return "", err
 	}
-
-	if empty {
-		return nil
+	if err := storage.WriteDigest(ctx, s.Chart, obj, digest.Sum(nil)); err != nil {
+		return "", err
 	}
 
-	// datum.Week always points to the end date
-	for bucket, v := range merged {
-		if len(v) > 0 || !opts.ignoreEmptyBuckets {
-			d := &datum{
-				Week:  string(end),
-				Key:   string(bucket),
-				Value: float64(len(v)),
-			}
-			chart.Data = append(chart.Data, d)
-		}
-	}
-	compareBuckets := compareLexically
-	if opts.compareBuckets != nil {
-		compareBuckets = opts.compareBuckets
+	msg := fmt.Sprintf("processed %d reports from date %s to %s into %s", data.NumReports, start.Format(telemetry.DateOnly), end.Format(telemetry.DateOnly), s.Chart.URI()+"/"+obj)
+	if redacted := b.Redacted(); len(redacted) > 0 {
+		// The merge objects of the range still hold retired counters, and
+		// need to be merged again.
+		slog.WarnContext(ctx, "redacted retired counters from merged reports", "start", start.Format(telemetry.DateOnly), "end", end.Format(telemetry.DateOnly), "counters", redacted)
+		msg += fmt.Sprintf("; redacted %d retired counters", len(redacted))
 	}
-	// Sort the data based on bucket name to ensure deterministic output.
-	sort.Slice(chart.Data, func(i, j int) bool {
-		return compareBuckets(chart.Data[i].Key, chart.Data[j].Key) < 0
-	})
-
-	return chart
-}
-
-// weekName is the date of the report week in the format "YYYY-MM-DD".
-type weekName string
-
-// programName is the package path of the program, as used in
-// telemetry.ProgramReport and chartconfig.Program.
-// e.g. golang.org/x/tools/gopls, cmd/go.
-type programName string
-
-// graphName is the graph name.
-// A graph plots distribution or timeseries of related counters.
-//
-// TODO(rfindley): rename to chartName.
-type graphName string
-
-// counterName is the counter name. counterName is graphName:bucketName.
-type counterName string
-
-// bucketName is the bucket name.
-type bucketName string
-
-// reportID is the upload report ID.
-// The current implementation uses telemetry.Report.X,
-// a random number, computed by the uploader when creating a Report object.
-// See x/telemetry/internal/upload.(*uploader).createReport.
-type reportID float64
-
-type data map[weekName]map[programName]map[graphName]map[bucketName]map[reportID]int64
-
-// Names of special counters.
-// Unlike other counters, these are constructed from the metadata in the report.
-const (
-	versionCounter   = "Version"
-	goosCounter      = "GOOS"
-	goarchCounter    = "GOARCH"
-	goversionCounter = "GoVersion"
-)
-
-// group groups the report data by week, program, prefix, counter, and x value
-// summing together counter values for each program report in a report.
-func group(reports []telemetry.Report) data {
-	result := make(data)
-	for _, r := range reports {
-		var (
-			week = weekName(r.Week)
-			// x is a random number sent with each upload report.
-			// Since there is no identifier for the uploader, we use x as the uploader ID
-			// to approximate the number of unique uploader.
-			//
-			// Multiple uploads with the same x will overwrite each other, so we set the
-			// value, rather than add it to the existing value.
-			id = reportID(r.X)
-		)
-		for _, p := range r.Programs {
-			program := programName(p.Program)
-
-			result.writeCount(week, program, versionCounter, bucketName(p.Version), id, 1)
-			result.writeCount(week, program, goosCounter, bucketName(p.GOOS), id, 1)
-			result.writeCount(week, program, goarchCounter, bucketName(p.GOARCH), id, 1)
-			result.writeCount(week, program, goversionCounter, bucketName(p.GoVersion), id, 1)
-			for c, value := range p.Counters {
-				chart, bucket := splitCounterName(c)
-				result.writeCount(week, program, chart, bucket, id, value)
-			}
+	if start.Equal(end) {
+		a, err := detectAnomalies(ctx, s.Chart, end, data)
+		if err != nil {
+			return "", fmt.Errorf("detecting anomalies: %w", err)
 		}
+		msg += fmt.Sprintf("; found %d anomalies", len(a.Anomalies))
 	}
-	return result
-}
-
-// writeCount writes the counter values to the result. When a report contains
-// multiple program reports for the same program, the value of the counters
-// in that report are summed together.
-func (d data) writeCount(week weekName, program programName, chart graphName, bucket bucketName, id reportID, value int64) {
-	if _, ok := d[week]; !ok {
-		d[week] = make(map[programName]map[graphName]map[bucketName]map[reportID]int64)
-	}
-	if _, ok := d[week][program]; !ok {
-		d[week][program] = make(map[graphName]map[bucketName]map[reportID]int64)
-	}
-	if _, ok := d[week][program][chart]; !ok {
-		d[week][program][chart] = make(map[bucketName]map[reportID]int64)
-	}
-	if _, ok := d[week][program][chart][bucket]; !ok {
-		d[week][program][chart][bucket] = make(map[reportID]int64)
-	}
-	d[week][program][chart][bucket][id] = value
-}
-
-// splitCounterName gets splits the prefix and bucket splitCounterName of a counter name
-// or a bucket name. For an input with no bucket part prefix and bucket
-// are the same.
-func splitCounterName(name string) (graphName, bucketName) {
-	prefix, bucket, found := strings.Cut(name, ":")
-	if !found {
-		bucket = prefix
-	}
-	return graphName(prefix), bucketName(bucket)
-}
-
-// goMajorMinor gets the go<Maj>,<Min> version for a given go version.
-// For example, go1.20.1 -> go1.20.
-// TODO(hyangah): replace with go/version.Lang (available from go1.22)
-// after our builders stop running go1.21.
-func goMajorMinor(v string) string {
-	v = v[2:]
-	maj, x, ok := cutInt(v)
-	if !ok {
-		return ""
-	}
-	x = x[1:]
-	min, _, ok := cutInt(x)
-	if !ok {
-		return ""
-	}
-	return fmt.Sprintf("go%s.%s", maj, min)
-}
-
-// cutInt scans the leading decimal number at the start of x to an integer
-// and returns that value and the rest of the string.
-func cutInt(x string) (n, rest string, ok bool) {
-	i := 0
-	for i < len(x) && '0' <= x[i] && x[i] <= '9' {
-		i++
-	}
-	if i == 0 || x[0] == '0' && i != 1 {
-		return "", "", false
-	}
-	return x[:i], x[i:], true
+	return msg, nil
 }
 
+// fsys returns the worker's content, with the files on disk layered over
+// the embedded files if fromOS is set.
 func fsys(fromOS bool) fs.FS {
-	var f fs.FS = contentfs.FS
-	if fromOS {
-		f = os.DirFS("internal/content")
-	}
-	f, err := unionfs.Sub(f, "worker", "shared")
+	f, err := unionfs.Sub(contentfs.FS, "worker", "shared")
 	if err != nil {
 		log.Fatal(err)
 	}
+	if fromOS {
+		disk, err := unionfs.Sub(unionfs.DirFS("internal/content"), "worker", "shared")
+		if err != nil {
+			log.Fatal(err)
+		}
+		f = append(disk, f...)
+	}
 	return f
 }
@@ -5,12 +5,21 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
-	"golang.org/x/mod/semver"
+	"golang.org/x/telemetry/godev/internal/charts"
+	wconfig "golang.org/x/telemetry/godev/internal/config"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/chartconfig"
 	"golang.org/x/telemetry/internal/config"
 	"golang.org/x/telemetry/internal/telemetry"
 )
@@ -42,7 +51,6 @@ var exampleReports = []telemetry.Report{
 					"flag:a": 2,
 					"flag:b": 3,
 				},
-				// TODO: add support for stacks
 				Stacks: map[string]int64{
 					"panic": 4,
 				},
@@ -56,7 +64,6 @@ var exampleReports = []telemetry.Report{
 				Counters: map[string]int64{
 					"flag:b": 3,
 				},
-				// TODO: add support for stacks
 				Stacks: map[string]int64{
 					"panic": 2,
 				},
@@ -80,7 +87,6 @@ var exampleReports = []telemetry.Report{
 					"flag:a": 2,
 					"flag:b": 3,
 				},
-				// TODO: add support for stacks
 				Stacks: map[string]int64{
 					"panic": 4,
 				},
@@ -96,7 +102,6 @@ var exampleReports = []telemetry.Report{
 					"flag:a": 2,
 					"flag:b": 3,
 				},
-				// TODO: add support for stacks
 				Stacks: map[string]int64{
 					"panic": 4,
 				},
@@ -121,7 +126,6 @@ var exampleReports = []telemetry.Report{
 					"flag:b": 6,
 					"flag:c": 1,
 				},
-				// TODO: add support for stacks
 				Stacks: map[string]int64{
 					"panic": 7,
 				},
@@ -131,635 +135,6 @@ var exampleReports = []telemetry.Report{
 	},
 }
 
-func TestGroup(t *testing.T) {
-	type args struct {
-		reports []telemetry.Report
-	}
-	tests := []struct {
-		name string
-		args args
-		want data
-	}{
-		{
-			name: "single report",
-			args: args{
-				[]telemetry.Report{
-					{
-						Week:     "2999-01-01",
-						LastWeek: "2998-01-01",
-						X:        0.123456789,
-						Programs: []*telemetry.ProgramReport{
-							{
-								Program:   "example.com/mod/pkg",
-								Version:   "v1.2.3",
-								GoVersion: "go1.2.3",
-								GOOS:      "darwin",
-								GOARCH:    "arm64",
-								Counters: map[string]int64{
-									"main":   1,
-									"flag:a": 2,
-									"flag:b": 3,
-								},
-								// TODO: add support for stacks
-								Stacks: map[string]int64{
-									"panic": 4,
-								},
-							},
-						},
-						Config: "v0.0.1",
-					},
-				},
-			},
-			want: data{
-				weekName("2999-01-01"): {
-					programName("example.com/mod/pkg"): {
-						graphName("Version"): {
-							bucketName("v1.2.3"): {
-								reportID(0.1234567890): 1,
-							},
-						},
-						graphName("GOOS"): {
-							bucketName("darwin"): {
-								reportID(0.1234567890): 1,
-							},
-						},
-						graphName("GOARCH"): {
-							bucketName("arm64"): {
-								reportID(0.1234567890): 1,
-							},
-						},
-						graphName("GoVersion"): {
-							bucketName("go1.2.3"): {
-								reportID(0.1234567890): 1,
-							},
-						},
-						graphName("main"): {
-							bucketName("main"): {
-								reportID(0.1234567890): 1,
-							},
-						},
-						graphName("flag"): {
-							bucketName("a"): {
-								reportID(0.1234567890): 2,
-							},
-							bucketName("b"): {
-								reportID(0.1234567890): 3,
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := group(tt.args.reports)
-			if diff := cmp.Diff(tt.want, got); diff != "" {
-				t.Errorf("nest() mismatch (-want +got):\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestPartition(t *testing.T) {
-	normalVersion := func(b bucketName) bucketName {
-		return bucketName(semver.MajorMinor(string(b)))
-	}
-	normalGoVersion := func(b bucketName) bucketName {
-		return bucketName(goMajorMinor(string(b)))
-	}
-	exampleData := group(exampleReports)
-	type args struct {
-		program programName
-		name    graphName
-		buckets []bucketName
-	}
-	tests := []struct {
-		name string
-		data data
-		args args
-		opts partitionOptions
-		want *chart
-	}{
-		{
-			name: "major.minor.patch version counter",
-			data: exampleData,
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "Version",
-				buckets: []bucketName{"v1.2.3", "v2.3.4", "v4.5.6"},
-			},
-			opts: partitionOptions{normalizeBucket: normalVersion},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:Version",
-				Name: "Version",
-				Type: "partition",
-				Data: []*datum{
-					{Week: "2999-01-01", Key: "v1.2", Value: 2},
-					{Week: "2999-01-01", Key: "v2.3", Value: 2},
-					{Week: "2999-01-01", Key: "v4.5", Value: 0},
-				},
-			},
-		},
-		{
-			name: "all nonempty versions",
-			data: exampleData,
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "Version",
-				buckets: []bucketName{"v1.2.3", "v2.3.4", "v4.5.6"},
-			},
-			opts: partitionOptions{ignoreEmptyBuckets: true},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:Version",
-				Name: "Version",
-				Type: "partition",
-				Data: []*datum{
-					{
-						Week:  "2999-01-01",
-						Key:   "v1.2.3",
-						Value: 2,
-					},
-					{
-						Week:  "2999-01-01",
-						Key:   "v2.3.4",
-						Value: 2,
-					},
-				},
-			},
-		},
-		{
-			name: "major.minor version counter should have same result as major.minor.patch",
-			data: exampleData,
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "Version",
-				buckets: []bucketName{"v1.2.3", "v2.3.4"},
-			},
-			opts: partitionOptions{normalizeBucket: normalVersion},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:Version",
-				Name: "Version",
-				Type: "partition",
-				Data: []*datum{
-					{
-						Week:  "2999-01-01",
-						Key:   "v1.2",
-						Value: 2,
-					},
-					{
-						Week:  "2999-01-01",
-						Key:   "v2.3",
-						Value: 2,
-					},
-				},
-			},
-		},
-		{
-			name: "duplicated counter should be ignored",
-			data: exampleData,
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "Version",
-				buckets: []bucketName{"v1.2.3", "v2.3.4", "v1.2.3"},
-			},
-			opts: partitionOptions{normalizeBucket: normalVersion},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:Version",
-				Name: "Version",
-				Type: "partition",
-				Data: []*datum{
-					{
-						Week:  "2999-01-01",
-						Key:   "v1.2",
-						Value: 2,
-					},
-					{
-						Week:  "2999-01-01",
-						Key:   "v2.3",
-						Value: 2,
-					},
-				},
-			},
-		},
-		{
-			name: "goos counter",
-			data: exampleData,
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "GOOS",
-				buckets: []bucketName{"darwin", "linux"},
-			},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:GOOS",
-				Name: "GOOS",
-				Type: "partition",
-				Data: []*datum{
-					{
-						Week:  "2999-01-01",
-						Key:   "darwin",
-						Value: 2,
-					},
-					{
-						Week:  "2999-01-01",
-						Key:   "linux",
-						Value: 1,
-					},
-				},
-			},
-		},
-		{
-			name: "GoVersion counter",
-			data: exampleData,
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "GoVersion",
-				buckets: []bucketName{"go1.2.3", "go2.3.4"},
-			},
-			opts: partitionOptions{normalizeBucket: normalGoVersion},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:GoVersion",
-				Name: "GoVersion",
-				Type: "partition",
-				Data: []*datum{
-					{
-						Week:  "2999-01-01",
-						Key:   "go1.2",
-						Value: 3,
-					},
-					{
-						Week:  "2999-01-01",
-						Key:   "go2.3",
-						Value: 0,
-					},
-				},
-			},
-		},
-		{
-			name: "three days, multiple versions",
-			data: data{
-				"2999-01-01": {"example.com/mod/pkg": {"Version": {
-					"v1.2.3": {0.1: 2},
-					"v2.3.4": {0.1: 3},
-				},
-				}},
-				"2999-01-04": {"example.com/mod/pkg": {"Version": {
-					"v1.2.3": {0.3: 2},
-					"v2.3.4": {0.4: 5},
-				},
-				}},
-				"2999-01-05": {"example.com/mod/pkg": {"Version": {
-					"v2.3.4": {0.5: 6},
-				}}},
-			},
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "Version",
-				buckets: []bucketName{"v1.2.3", "v2.3.4"},
-			},
-			opts: partitionOptions{normalizeBucket: normalVersion},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:Version",
-				Name: "Version",
-				Type: "partition",
-				Data: []*datum{
-					{
-						Week:  "2999-01-05",
-						Key:   "v1.2",
-						Value: 2,
-					},
-					{
-						Week:  "2999-01-05",
-						Key:   "v2.3",
-						Value: 3,
-					},
-				},
-			},
-		},
-		{
-			name: "three days, multiple GOOS",
-			data: data{
-				"2999-01-01": {"example.com/mod/pkg": {"GOOS": {
-					"darwin": {0.1: 2, 0.2: 2, 0.3: 2},
-					"linux":  {0.1: 2, 0.2: 2},
-				},
-				}},
-				"2999-01-02": {"example.com/mod/pkg": {"GOOS": {
-					"darwin": {0.4: 2, 0.5: 2},
-					"linux":  {0.6: 5},
-				},
-				}},
-				"2999-01-03": {"example.com/mod/pkg": {"GOOS": {
-					"darwin": {0.6: 3},
-				},
-				}},
-			},
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "GOOS",
-				buckets: []bucketName{"darwin", "linux"},
-			},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:GOOS",
-				Name: "GOOS",
-				Type: "partition",
-				Data: []*datum{
-					{
-						Week:  "2999-01-03",
-						Key:   "darwin",
-						Value: 6,
-					},
-					{
-						Week:  "2999-01-03",
-						Key:   "linux",
-						Value: 3,
-					},
-				},
-			},
-		},
-		{
-			name: "two days data, missing GOOS in first day",
-			data: data{
-				"2999-01-01": {"example.com/mod/pkg": {"Version": {
-					"v1.2": {0.1: 2},
-				},
-				}},
-				"2999-01-02": {"example.com/mod/pkg": {"GOOS": {
-					"darwin": {0.3: 2},
-					"linux":  {0.3: 2},
-				},
-				}},
-			},
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "GOOS",
-				buckets: []bucketName{"darwin", "linux"},
-			},
-			want: &chart{
-				ID:   "charts:example.com/mod/pkg:GOOS",
-				Name: "GOOS",
-				Type: "partition",
-				Data: []*datum{
-					{
-						Week:  "2999-01-02",
-						Key:   "darwin",
-						Value: 1,
-					},
-					{
-						Week:  "2999-01-02",
-						Key:   "linux",
-						Value: 1,
-					},
-				},
-			},
-		},
-		{
-			name: "three days, missing version data all days",
-			data: data{
-				"2999-01-01": {"example.com/mod/pkg": {"GOOS": {
-					"GOOS":        {0.1: 2},
-					"GOOS:darwin": {0.1: 2},
-				},
-				}},
-				"2999-01-02": {"example.com/mod/pkg": {"GOOS": {
-					"GOOS":       {0.6: 5},
-					"GOOS:linux": {0.6: 5},
-				},
-				}},
-				"2999-01-03": {"example.com/mod/pkg": {"GOOS": {
-					"GOOS":        {0.6: 3},
-					"GOOS:darwin": {0.6: 3},
-				},
-				}},
-			},
-			args: args{
-				program: "example.com/mod/pkg",
-				name:    "Version",
-				buckets: []bucketName{"v1.2.3", "v2.3.4"},
-			},
-			opts: partitionOptions{normalizeBucket: normalVersion},
-			want: nil,
-		},
-	}
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			got := tc.data.partition(tc.args.program, tc.args.name, tc.args.buckets, tc.opts)
-			if diff := cmp.Diff(tc.want, got); diff != "" {
-				t.Errorf("partition() mismatch (-want +got):\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestCharts(t *testing.T) {
-	exampleData := group(exampleReports)
-	cfg := &config.Config{
-		UploadConfig: &telemetry.UploadConfig{
-			GOOS:       []string{"darwin"},
-			GOARCH:     []string{"amd64"},
-			GoVersion:  []string{"go1.2.3", "go1.19.0"},
-			SampleRate: 1,
-			Programs: []*telemetry.ProgramConfig{
-				{
-					Name:     "cmd/go",
-					Versions: []string{"go1.2.3"},
-					Counters: []telemetry.CounterConfig{{
-						Name: "main",
-					}},
-				},
-				{
-					Name:     "cmd/compiler",
-					Versions: []string{"go1.2.3"},
-					Counters: []telemetry.CounterConfig{{
-						Name: "count1",
-					}},
-				},
-				{
-					Name: "example.com/mod/pkg",
-					// Exercise semver sorting. Notably v1.2.3 has data but is not
-					// present.
-					//
-					// TODO(rfindley): in a follow-up CL, remove the MajMin collapsing of
-					// Versions. It's actually really interesting to see detailed version
-					// information.
-					Versions: []string{"v2.3.4", "v2.3.4-pre.1", "v0.15.0"},
-					Counters: []telemetry.CounterConfig{
-						{Name: "count2"},
-						{Name: "flag:{a,b,c}"},
-					},
-				},
-			},
-		},
-	}
-	want := &chartdata{
-		DateRange: [2]string{"2999-01-01", "2999-01-01"},
-		Programs: []*program{
-			{
-				ID:   "charts:cmd/go",
-				Name: "cmd/go",
-				Charts: []*chart{
-					{
-						ID:   "charts:cmd/go:GOOS",
-						Name: "GOOS",
-						Type: "partition",
-						Data: []*datum{
-							{Week: "2999-01-01", Key: "darwin", Value: 1},
-						},
-					},
-					{
-						ID:   "charts:cmd/go:GoVersion",
-						Name: "GoVersion",
-						Type: "partition",
-						Data: []*datum{
-							{Week: "2999-01-01", Key: "go1.2", Value: 1},
-						},
-					},
-					{
-						ID:   "charts:cmd/go:main",
-						Name: "main",
-						Type: "partition",
-						Data: []*datum{
-							{Week: "2999-01-01", Key: "main", Value: 1},
-						},
-					},
-				},
-			},
-			{
-				ID:   "charts:cmd/compiler",
-				Name: "cmd/compiler",
-			},
-			{
-				ID:   "charts:example.com/mod/pkg",
-				Name: "example.com/mod/pkg",
-				Charts: []*chart{
-					{
-						ID:   "charts:example.com/mod/pkg:Version",
-						Name: "Version",
-						Type: "partition",
-						Data: []*datum{
-							{Week: "2999-01-01", Key: "v2.3.4-pre.1", Value: 1},
-							{Week: "2999-01-01", Key: "v2.3.4", Value: 2},
-						},
-					},
-					{
-						ID:   "charts:example.com/mod/pkg:GOOS",
-						Name: "GOOS",
-						Type: "partition",
-						Data: []*datum{
-							{Week: "2999-01-01", Key: "darwin", Value: 2},
-						},
-					},
-					{
-						ID:   "charts:example.com/mod/pkg:GOARCH",
-						Name: "GOARCH",
-						Type: "partition",
-						Data: []*datum{
-							{Week: "2999-01-01", Key: "amd64", Value: 1},
-						},
-					},
-					{
-						ID:   "charts:example.com/mod/pkg:GoVersion",
-						Name: "GoVersion",
-						Type: "partition",
-						Data: []*datum{
-							{Week: "2999-01-01", Key: "go1.2", Value: 3},
-							{Week: "2999-01-01", Key: "go1.19", Value: 1},
-						},
-					},
-					{
-						ID:   "charts:example.com/mod/pkg:flag",
-						Name: "flag",
-						Type: "partition",
-						Data: []*datum{
-							{Week: "2999-01-01", Key: "a", Value: 3},
-							{Week: "2999-01-01", Key: "b", Value: 3},
-							{Week: "2999-01-01", Key: "c", Value: 1},
-						},
-					},
-				},
-			},
-		},
-		NumReports: 1,
-	}
-	got := charts(cfg, "2999-01-01", "2999-01-01", exampleData, []float64{0.12345})
-	if diff := cmp.Diff(want, got); diff != "" {
-		t.Errorf("charts = %+v\n, (-want +got): %v", got, diff)
-	}
-}
-
-func TestWriteCount(t *testing.T) {
-	type keyValue struct {
-		week    weekName
-		program programName
-		chart   graphName
-		bucket  bucketName
-		x       reportID
-		value   int64
-	}
-	testcases := []struct {
-		name   string
-		inputs []keyValue
-		want   []keyValue
-	}{
-		{
-			name: "program version counter should have value",
-			inputs: []keyValue{
-				{"2987-07-01", "golang.org/x/tools/gopls", "Version", "v0.15.3", 0.00009, 1},
-			},
-			want: []keyValue{
-				{"2987-07-01", "golang.org/x/tools/gopls", "Version", "v0.15.3", 0.00009, 1},
-			},
-		},
-		{
-			name: "only one count with same prefix and counter",
-			inputs: []keyValue{
-				{"2987-06-30", "cmd/go", "go/invocations", "go/invocations", 0.86995, 84},
-			},
-			want: []keyValue{
-				{"2987-06-30", "cmd/go", "go/invocations", "go/invocations", 0.86995, 84},
-			},
-		},
-		{
-			name: "overwrite values when calling multiple times",
-			inputs: []keyValue{
-				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 1},
-				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 2},
-				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 3},
-			},
-			want: []keyValue{
-				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 3},
-			},
-		},
-		{
-			name: "multiple counters",
-			inputs: []keyValue{
-				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 2},
-				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "linux", 0.86018, 4},
-			},
-			want: []keyValue{
-				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 2},
-				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "linux", 0.86018, 4},
-			},
-		},
-	}
-
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			d := make(data)
-			for _, input := range tc.inputs {
-				d.writeCount(input.week, input.program, input.chart, input.bucket, input.x, input.value)
-			}
-
-			for _, want := range tc.want {
-				got := d[want.week][want.program][want.chart][want.bucket][want.x]
-				if want.value != got {
-					t.Errorf("d[%q][%q][%q][%q][%v] = %v, want %v", want.week, want.program, want.chart, want.bucket, want.x, got, want.value)
-				}
-			}
-		})
-	}
-}
-
 func TestParseDateRange(t *testing.T) {
 	testcases := []struct {
 		name      string
@@ -830,3 +205,347 @@ func TestParseDateRange(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeExcludesSuspect(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"upload": &s.Upload, "merge": &s.Merge, "chart": &s.Chart, "stats": &s.Stats} {
+		bucket, err := storage.NewFSBucket(ctx, dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b = bucket
+	}
+	for _, x := range []float64{0.1, 0.2, 0.3} {
+		obj := s.Upload.Object(fmt.Sprintf("2024-01-01/%g.json", x))
+		w, err := obj.NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.NewEncoder(w).Encode(telemetry.Report{Week: "2024-01-01", X: x}); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if x == 0.2 {
+			if err := obj.SetMetadata(ctx, map[string]string{storage.SuspectMetadata: "replay"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handleMerge(config.NewConfig(&telemetry.UploadConfig{}), nil, &s).ServeHTTP(rec, httptest.NewRequest("GET", "/merge/?date=2024-01-01", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("merge status = %d: %s", rec.Code, rec.Body)
+	}
+	r, err := s.Merge.Object("2024-01-01.json").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var xs []float64
+	for dec := json.NewDecoder(r); ; {
+		var report telemetry.Report
+		if err := dec.Decode(&report); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		xs = append(xs, report.X)
+	}
+	if diff := cmp.Diff([]float64{0.1, 0.3}, xs); diff != "" {
+		t.Errorf("merged reports mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMergeIncremental(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"upload": &s.Upload, "merge": &s.Merge, "chart": &s.Chart, "stats": &s.Stats} {
+		bucket, err := storage.NewFSBucket(ctx, dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b = bucket
+	}
+	upload := func(x float64, suspect string) {
+		t.Helper()
+		obj := s.Upload.Object(fmt.Sprintf("2024-01-01/%g.json", x))
+		w, err := obj.NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.NewEncoder(w).Encode(telemetry.Report{Week: "2024-01-01", X: x}); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if suspect != "" {
+			if err := obj.SetMetadata(ctx, map[string]string{storage.SuspectMetadata: suspect}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	merge := func(query string) (xs []float64, q dataQuality) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handleMerge(config.NewConfig(&telemetry.UploadConfig{}), nil, &s).ServeHTTP(rec, httptest.NewRequest("GET", "/merge/?date=2024-01-01"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("merge status = %d: %s", rec.Code, rec.Body)
+		}
+		r, err := s.Merge.Object("2024-01-01.json").NewReader(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		for dec := json.NewDecoder(r); ; {
+			var report telemetry.Report
+			if err := dec.Decode(&report); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			xs = append(xs, report.X)
+		}
+		qr, err := s.Chart.Object(qualityPrefix + "2024-01-01.json").NewReader(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer qr.Close()
+		if err := json.NewDecoder(qr).Decode(&q); err != nil {
+			t.Fatal(err)
+		}
+		return xs, q
+	}
+
+	upload(0.1, "")
+	upload(0.2, "replay")
+	if xs, _ := merge(""); !cmp.Equal(xs, []float64{0.1}) {
+		t.Errorf("first merge = %v, want [0.1]", xs)
+	}
+
+	// A later merge reads only the new upload, so tagging an upload that
+	// was already merged as suspect goes unnoticed.
+	upload(0.3, "")
+	if err := s.Upload.Object("2024-01-01/0.1.json").SetMetadata(ctx, map[string]string{storage.SuspectMetadata: "replay"}); err != nil {
+		t.Fatal(err)
+	}
+	xs, q := merge("")
+	if want := []float64{0.1, 0.3}; !cmp.Equal(xs, want) {
+		t.Errorf("incremental merge = %v, want %v", xs, want)
+	}
+	if q.Merged != 2 || q.Suspect != 1 {
+		t.Errorf("incremental merge quality: Merged = %d, Suspect = %d, want 2, 1", q.Merged, q.Suspect)
+	}
+
+	// A full merge reads all the uploads again.
+	xs, q = merge("&full=1")
+	if want := []float64{0.3}; !cmp.Equal(xs, want) {
+		t.Errorf("full merge = %v, want %v", xs, want)
+	}
+	if q.Merged != 1 || q.Suspect != 2 {
+		t.Errorf("full merge quality: Merged = %d, Suspect = %d, want 1, 2", q.Merged, q.Suspect)
+	}
+
+	// If the merge object no longer matches the manifest, the merge starts
+	// over.
+	w, err := s.Merge.Object("2024-01-01.json").NewWriter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if xs, _ := merge(""); !cmp.Equal(xs, []float64{0.3}) {
+		t.Errorf("merge after the merge object was emptied = %v, want [0.3]", xs)
+	}
+}
+
+func TestMergeRedactsRetired(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"upload": &s.Upload, "merge": &s.Merge, "chart": &s.Chart, "stats": &s.Stats} {
+		bucket, err := storage.NewFSBucket(ctx, dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b = bucket
+	}
+	upload := func(x float64) {
+		t.Helper()
+		w, err := s.Upload.Object(fmt.Sprintf("2024-01-01/%g.json", x)).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report := telemetry.Report{Week: "2024-01-01", X: x, Programs: []*telemetry.ProgramReport{{
+			Program:  "example.com/mod/pkg",
+			Counters: map[string]int64{"pkg/path:home": 1, "pkg/editor:vim": 2},
+		}}}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ccfgs, err := chartconfig.Parse([]byte(`
+counter: pkg/path:{home,tmp}
+program: example.com/mod/pkg
+type: retired
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merge := func(rt charts.Retirements) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handleMerge(config.NewConfig(&telemetry.UploadConfig{}), rt, &s).ServeHTTP(rec, httptest.NewRequest("GET", "/merge/?date=2024-01-01", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("merge status = %d: %s", rec.Code, rec.Body)
+		}
+	}
+
+	// Reports merged before the counter was retired are redacted by the
+	// next merge, along with the new ones.
+	upload(0.1)
+	merge(nil)
+	upload(0.2)
+	merge(charts.NewRetirements(ccfgs))
+
+	reports, err := charts.ReadMerged(ctx, s.Merge, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("merged %d reports, want 2", len(reports))
+	}
+	for _, r := range reports {
+		if want := map[string]int64{"pkg/editor:vim": 2}; !cmp.Equal(r.Programs[0].Counters, want) {
+			t.Errorf("merged report %g has counters %v, want %v", r.X, r.Programs[0].Counters, want)
+		}
+	}
+	qr, err := s.Chart.Object(qualityPrefix + "2024-01-01.json").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer qr.Close()
+	var q dataQuality
+	if err := json.NewDecoder(qr).Decode(&q); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{"pkg/path:home": 2}; !cmp.Equal(q.Redacted, want) {
+		t.Errorf("quality Redacted = %v, want %v", q.Redacted, want)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	cfg := &wconfig.Config{LocalStorage: t.TempDir(), UploadBucket: "dev-telemetry-uploaded"}
+	source, err := storage.NewBucket(ctx, cfg, "prod-telemetry-uploaded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest, err := storage.NewBucket(ctx, cfg, cfg.UploadBucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	put := func(bucket storage.BucketHandle, name, data string) {
+		t.Helper()
+		w, err := bucket.Object(name).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(source, "2024-01-01/0.1.json", "{}")
+	put(source, "2024-01-01/0.2.json", "{}")
+	put(source, "2024-01-01/0.3.json", "{}")
+	put(dest, "2024-01-01/0.1.json", "{}") // already copied
+	put(dest, "2024-01-01/0.2.json", "{")  // partially copied
+
+	rec := httptest.NewRecorder()
+	handleCopy(cfg, &storage.API{Upload: dest}).ServeHTTP(rec, httptest.NewRequest("GET", "/copy/?start=2024-01-01&end=2024-01-01", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("copy status = %d: %s", rec.Code, rec.Body)
+	}
+	want := fmt.Sprintf("copied 2 uploads from %s to %s (skipped 1 already copied)", source.URI(), dest.URI())
+	if got := rec.Body.String(); got != want {
+		t.Errorf("copy = %q, want %q", got, want)
+	}
+	sizes, err := objectSizes(ctx, dest, "2024-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int64{"2024-01-01/0.1.json": 2, "2024-01-01/0.2.json": 2, "2024-01-01/0.3.json": 2}; !cmp.Equal(sizes, want) {
+		t.Errorf("copied objects = %v, want %v", sizes, want)
+	}
+}
+
+func TestTaskID(t *testing.T) {
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		url, want string
+	}{
+		{"http://localhost:8082/merge/?date=2024-01-01", "6ae21be4-merge-date-2024-01-01-on-2024-01-08"},
+		// Query parameters are ordered by name.
+		{"http://localhost:8082/chart/?start=2024-01-01&end=2024-01-07", "d026f554-chart-end-2024-01-07-start-2024-01-01-on-2024-01-08"},
+		{"http://localhost:8082/chart/?end=2024-01-07&start=2024-01-01", "d026f554-chart-end-2024-01-07-start-2024-01-01-on-2024-01-08"},
+	} {
+		if got := taskID(now, test.url); got != test.want {
+			t.Errorf("taskID(%q) = %q, want %q", test.url, got, test.want)
+		}
+	}
+	// The same work queued on another day is a new task.
+	url := "http://localhost:8082/merge/?date=2024-01-01"
+	if taskID(now, url) == taskID(now.AddDate(0, 0, 1), url) {
+		t.Errorf("taskID(%q) is the same on different days", url)
+	}
+}
+
+func TestQueuedTasks(t *testing.T) {
+	cfg := &wconfig.Config{WorkerURL: "w", Env: "prod", MaxReportAgeDays: 21}
+	// Reports whose week ends on 2024-01-11 are accepted until 2024-02-01.
+	now := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	var want []string
+	for d := time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC); d.Month() == time.January; d = d.AddDate(0, 0, 1) {
+		want = append(want, "w/merge/?date="+d.Format(telemetry.DateOnly))
+	}
+	for d := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC); d.Day() <= 10; d = d.AddDate(0, 0, 1) {
+		date := d.Format(telemetry.DateOnly)
+		start := d.AddDate(0, 0, -6).Format(telemetry.DateOnly)
+		want = append(want, "w/chart/?date="+date, "w/chart/?start="+start+"&end="+date)
+		if date == "2024-01-07" { // a Sunday
+			want = append(want, "w/newcounters/?date="+date, "w/timeseries/?date="+date, "w/snapshot/?start="+start+"&end="+date)
+		}
+	}
+	if diff := cmp.Diff(want, queuedTasks(cfg, now)); diff != "" {
+		t.Errorf("queuedTasks mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLastCompleteDate(t *testing.T) {
+	const maxAge = 21 * 24 * time.Hour
+	for _, test := range []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC), "2024-01-11"},
+		// At midnight, the reports of 2024-01-11 are still accepted.
+		{time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "2024-01-10"},
+	} {
+		if got := lastCompleteDate(test.now, maxAge).Format(telemetry.DateOnly); got != test.want {
+			t.Errorf("lastCompleteDate(%v) = %s, want %s", test.now, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// A mergeManifest records the upload objects that were processed into the
+// merge object for a date, so that later merges of the date only read the
+// objects uploaded since. It is stored next to the merge object, as
+// <date>.manifest, which readers of merged reports ignore.
+type mergeManifest struct {
+	// Merged is the number of reports in the merge object. If the merge
+	// object no longer holds that many, for example because reports were
+	// removed from it, the next merge of the date starts over.
+	Merged int
+
+	// Objects holds the names of the processed upload objects, whether
+	// their reports were merged or not. Suspect and Malformed record the
+	// objects that were excluded, so that they are counted in the data
+	// quality and statistics of the date.
+	Objects   []string
+	Suspect   map[string]string `json:",omitempty"` // object name -> suspect reasons
+	Malformed []string          `json:",omitempty"`
+
+	// UploadsDeleted is set once the retention task has deleted processed
+	// upload objects of the date. The merge object then holds the only copy
+	// of their reports, so it is never merged again from scratch.
+	UploadsDeleted bool `json:",omitempty"`
+}
+
+func manifestName(date string) string {
+	return date + ".manifest"
+}
+
+// readManifest reads the manifest of the merge object for date. It returns
+// nil if there is none.
+func readManifest(ctx context.Context, bucket storage.BucketHandle, date string) (*mergeManifest, error) {
+	r, err := bucket.Object(manifestName(date)).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	m := new(mergeManifest)
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return nil, fmt.Errorf("invalid manifest for %s: %v", date, err)
+	}
+	return m, nil
+}
+
+func writeManifest(ctx context.Context, bucket storage.BucketHandle, date string, m *mergeManifest) error {
+	out, err := bucket.Object(manifestName(date)).NewWriter(ctx)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := json.NewEncoder(out).Encode(m); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// copyMerged copies the reports in the merge object for date to w, a line
+// at a time, calling f for each before it is copied. If f changes a report,
+// it returns true, and the changed report is copied instead. copyMerged
+// returns the number of reports copied.
+func copyMerged(ctx context.Context, bucket storage.BucketHandle, date string, w io.Writer, f func(*telemetry.Report) (changed bool)) (int, error) {
+	r, err := bucket.Object(date + ".json").NewReader(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	n := 0
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var report telemetry.Report
+			if err := json.Unmarshal(line, &report); err != nil {
+				return n, fmt.Errorf("%w: merged report for %s: %v", charts.ErrInvalid, date, err)
+			}
+			if f(&report) {
+				data, err := json.Marshal(report)
+				if err != nil {
+					return n, err
+				}
+				line = data
+			}
+			if line[len(line)-1] != '\n' {
+				line = append(line, '\n')
+			}
+			if _, err := w.Write(line); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, r.Close()
+}
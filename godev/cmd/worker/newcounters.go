@@ -0,0 +1,177 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// newCountersPrefix is the prefix of new counter index objects in the chart
+// bucket.
+const newCountersPrefix = "newcounters/"
+
+// A newCounterIndex records, for the week ending on Week, the counters that
+// each program reported for the first time.
+//
+// Known holds every counter observed up to and including Week, and is used as
+// the baseline when computing the index for the following week.
+type newCounterIndex struct {
+	Week     string
+	Programs []*newCounterProgram
+}
+
+type newCounterProgram struct {
+	Program string
+	New     []string // counters first observed in the week
+	Known   []string // all counters observed up to and including the week
+}
+
+// handleNewCounters computes the new counter index for the week ending on the
+// date given by the "date" query parameter, and writes it to the chart bucket.
+//
+// The index for the preceding week is used as the baseline. If it does not
+// exist, the resulting index establishes a baseline and reports no new
+// counters, so that the first week of data does not flag every counter.
+func handleNewCounters(s *storage.API) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		end, err := time.Parse(telemetry.DateOnly, r.URL.Query().Get("date"))
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		start := end.AddDate(0, 0, -6)
+
+		var reports []telemetry.Report
+		for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+			dailyReports, err := readMergedReports(ctx, date, s)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, dailyReports...)
+		}
+
+		prev, err := readNewCounterIndex(ctx, s.Chart, end.AddDate(0, 0, -7).Format(telemetry.DateOnly))
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return err
+		}
+		index := newCounters(end.Format(telemetry.DateOnly), prev, reports)
+
+		obj := newCountersPrefix + index.Week + ".json"
+		out, err := s.Chart.Object(obj).NewWriter(ctx)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		if err := json.NewEncoder(out).Encode(index); err != nil {
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+
+		var n int
+		for _, p := range index.Programs {
+			n += len(p.New)
+		}
+		msg := fmt.Sprintf("found %d new counters in %d reports for the week ending %s; wrote %s", n, len(reports), index.Week, s.Chart.URI()+"/"+obj)
+		return content.Text(w, msg, http.StatusOK)
+	}
+}
+
+// readNewCounterIndex reads the new counter index for the week ending on the
+// given date.
+func readNewCounterIndex(ctx context.Context, bucket storage.BucketHandle, date string) (*newCounterIndex, error) {
+	in, err := bucket.Object(newCountersPrefix + date + ".json").NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	var index newCounterIndex
+	if err := json.NewDecoder(in).Decode(&index); err != nil {
+		return nil, fmt.Errorf("%w: new counter index for %s: %v", charts.ErrInvalid, date, err)
+	}
+	return &index, nil
+}
+
+// newCounters computes the new counter index for the given week from that
+// week's reports, using prev as the baseline of known counters.
+//
+// If prev is nil, all observed counters become known but none are reported as
+// new.
+//
+// Stack counters are identified by their name, without the stack.
+func newCounters(week string, prev *newCounterIndex, reports []telemetry.Report) *newCounterIndex {
+	known := make(map[string]map[string]bool)
+	if prev != nil {
+		for _, p := range prev.Programs {
+			m := make(map[string]bool)
+			for _, c := range p.Known {
+				m[c] = true
+			}
+			known[p.Program] = m
+		}
+	}
+
+	seen := make(map[string]map[string]bool)
+	for _, r := range reports {
+		for _, p := range r.Programs {
+			program := p.Program
+			if seen[program] == nil {
+				seen[program] = make(map[string]bool)
+			}
+			for c := range p.Counters {
+				seen[program][c] = true
+			}
+			for s := range p.Stacks {
+				name, _, _ := strings.Cut(s, "\n")
+				seen[program][name] = true
+			}
+		}
+	}
+
+	programs := make(map[string]bool)
+	for p := range known {
+		programs[p] = true
+	}
+	for p := range seen {
+		programs[p] = true
+	}
+
+	index := &newCounterIndex{Week: week}
+	for program := range programs {
+		p := &newCounterProgram{Program: program}
+		for c := range known[program] {
+			p.Known = append(p.Known, c)
+		}
+		for c := range seen[program] {
+			if known[program][c] {
+				continue
+			}
+			p.Known = append(p.Known, c)
+			if prev != nil {
+				p.New = append(p.New, c)
+			}
+		}
+		sort.Strings(p.New)
+		sort.Strings(p.Known)
+		index.Programs = append(index.Programs, p)
+	}
+	sort.Slice(index.Programs, func(i, j int) bool {
+		return index.Programs[i].Program < index.Programs[j].Program
+	})
+	return index
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestNewCounters(t *testing.T) {
+	week1 := newCounters("2999-01-01", nil, exampleReports)
+	want1 := &newCounterIndex{
+		Week: "2999-01-01",
+		Programs: []*newCounterProgram{
+			{
+				Program: "cmd/go",
+				Known:   []string{"main"},
+			},
+			{
+				Program: "example.com/mod/pkg",
+				Known:   []string{"flag:a", "flag:b", "flag:c", "main", "panic"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want1, week1); diff != "" {
+		t.Errorf("newCounters without baseline mismatch (-want +got):\n%s", diff)
+	}
+
+	reports := []telemetry.Report{
+		{
+			Week: "2999-01-08",
+			X:    0.4,
+			Programs: []*telemetry.ProgramReport{
+				{
+					Program:  "cmd/go",
+					Counters: map[string]int64{"main": 1, "go/invocations": 2},
+				},
+				{
+					Program:  "example.com/mod/pkg",
+					Counters: map[string]int64{"flag:a": 1},
+					Stacks:   map[string]int64{"crash\nframe1\nframe2": 1},
+				},
+				{
+					Program:  "cmd/compile",
+					Counters: map[string]int64{"compile/invocations": 1},
+				},
+			},
+		},
+	}
+	week2 := newCounters("2999-01-08", week1, reports)
+	want2 := &newCounterIndex{
+		Week: "2999-01-08",
+		Programs: []*newCounterProgram{
+			{
+				Program: "cmd/compile",
+				New:     []string{"compile/invocations"},
+				Known:   []string{"compile/invocations"},
+			},
+			{
+				Program: "cmd/go",
+				New:     []string{"go/invocations"},
+				Known:   []string{"go/invocations", "main"},
+			},
+			{
+				Program: "example.com/mod/pkg",
+				New:     []string{"crash"},
+				Known:   []string{"crash", "flag:a", "flag:b", "flag:c", "main", "panic"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want2, week2); diff != "" {
+		t.Errorf("newCounters with baseline mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+	"golang.org/x/telemetry/internal/uploadable"
+)
+
+// qualityPrefix is the prefix of data quality objects in the chart bucket.
+const qualityPrefix = "quality/"
+
+// A dataQuality records indicators of the quality of the uploads for a date,
+// computed when they are merged, so that regressions in client behavior are
+// noticed quickly.
+//
+// Counts of programs and counters cover the merged reports only.
+type dataQuality struct {
+	Date string
+
+	Merged    int // reports merged
+	Suspect   int // reports excluded as suspect
+	Malformed int // uploads that could not be decoded, and were skipped
+
+	// DuplicateX is the number of merged reports whose X is the same as that
+	// of an earlier report for the date. X is random, so duplicates suggest
+	// clients that upload the same report more than once.
+	DuplicateX int
+
+	// Programs and Counters are the number of program reports and of
+	// counters in merged reports. InvalidPrograms and InvalidCounters are
+	// those that the current upload config does not permit, and that
+	// validation would strip: for example, program versions that are
+	// unknown to the config.
+	Programs        int
+	InvalidPrograms int
+	Counters        int
+	InvalidCounters int
+
+	// Redacted maps each counter retired by the chart config to the number
+	// of reports it was redacted from. Counts of redacted counters are not
+	// included in Counters.
+	Redacted map[string]int `json:",omitempty"`
+}
+
+// qualityTracker accumulates the dataQuality of the reports for a date.
+type qualityTracker struct {
+	cfg   *tconfig.Config
+	seenX map[float64]bool
+	q     dataQuality
+}
+
+func newQualityTracker(cfg *tconfig.Config, date string) *qualityTracker {
+	return &qualityTracker{
+		cfg:   cfg,
+		seenX: make(map[float64]bool),
+		q:     dataQuality{Date: date},
+	}
+}
+
+// redacted records the retired counters redacted from a report.
+func (t *qualityTracker) redacted(names []string) {
+	for _, name := range names {
+		if t.q.Redacted == nil {
+			t.q.Redacted = make(map[string]int)
+		}
+		t.q.Redacted[name]++
+	}
+}
+
+// merged records a report that is merged.
+func (t *qualityTracker) merged(report *telemetry.Report) {
+	t.q.Merged++
+	if t.seenX[report.X] {
+		t.q.DuplicateX++
+	}
+	t.seenX[report.X] = true
+	for _, p := range report.Programs {
+		meta := uploadable.Meta{
+			Program:   p.Program,
+			Version:   p.Version,
+			GoVersion: p.GoVersion,
+			GOOS:      p.GOOS,
+			GOARCH:    p.GOARCH,
+		}
+		n := len(p.Counters) + len(p.Stacks) + len(p.Events)
+		t.q.Programs++
+		t.q.Counters += n
+		if d, _ := uploadable.DecideProgram(t.cfg, meta); d == uploadable.Drop {
+			t.q.InvalidPrograms++
+			t.q.InvalidCounters += n
+			continue
+		}
+		for _, counts := range []map[string]int64{p.Counters, p.Stacks, p.Events} {
+			for name := range counts {
+				if d, _ := uploadable.Decide(t.cfg, meta, name); d == uploadable.Drop {
+					t.q.InvalidCounters++
+				}
+			}
+		}
+	}
+}
+
+// writeQuality writes q to the chart bucket, replacing any earlier data
+// quality object for the same date.
+func writeQuality(ctx context.Context, bucket storage.BucketHandle, q *dataQuality) error {
+	out, err := bucket.Object(qualityPrefix + q.Date + ".json").NewWriter(ctx)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := json.NewEncoder(out).Encode(q); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestMergeQuality(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"upload": &s.Upload, "merge": &s.Merge, "chart": &s.Chart, "stats": &s.Stats} {
+		bucket, err := storage.NewFSBucket(ctx, dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b = bucket
+	}
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:      []string{"linux"},
+		GOARCH:    []string{"amd64"},
+		GoVersion: []string{"go1.22.0"},
+		Programs: []*telemetry.ProgramConfig{{
+			Name:     "gopls",
+			Versions: []string{"v0.15.0"},
+			Counters: []telemetry.CounterConfig{{Name: "known", Rate: 1}},
+		}},
+	})
+	program := func(version string) *telemetry.ProgramReport {
+		return &telemetry.ProgramReport{
+			Program:   "gopls",
+			Version:   version,
+			GoVersion: "go1.22.0",
+			GOOS:      "linux",
+			GOARCH:    "amd64",
+			Counters:  map[string]int64{"known": 1, "unknown": 1},
+		}
+	}
+	uploads := map[string]string{
+		"a": mustMarshal(t, telemetry.Report{Week: "2024-01-01", X: 0.1, Programs: []*telemetry.ProgramReport{program("v0.15.0")}}),
+		"b": mustMarshal(t, telemetry.Report{Week: "2024-01-01", X: 0.1, Programs: []*telemetry.ProgramReport{program("v0.16.0")}}),
+		"c": mustMarshal(t, telemetry.Report{Week: "2024-01-01", X: 0.2}),
+		"d": `{"Week": "2024-01-01", "X":`,
+	}
+	for name, data := range uploads {
+		obj := s.Upload.Object(fmt.Sprintf("2024-01-01/%s.json", name))
+		w, err := obj.NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if name == "c" {
+			if err := obj.SetMetadata(ctx, map[string]string{storage.SuspectMetadata: "replay"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handleMerge(cfg, nil, &s).ServeHTTP(rec, httptest.NewRequest("GET", "/merge/?date=2024-01-01", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("merge status = %d: %s", rec.Code, rec.Body)
+	}
+
+	r, err := s.Chart.Object(qualityPrefix + "2024-01-01.json").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var got dataQuality
+	if err := json.NewDecoder(r).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := dataQuality{
+		Date:            "2024-01-01",
+		Merged:          2,
+		Suspect:         1,
+		Malformed:       1,
+		DuplicateX:      1,
+		Programs:        2,
+		InvalidPrograms: 1, // v0.16.0
+		Counters:        4,
+		InvalidCounters: 3, // both counters of v0.16.0, and "unknown" of v0.15.0
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data quality mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
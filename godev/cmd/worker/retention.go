@@ -0,0 +1,182 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/config"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// handleRetention enforces the retention policies of the buckets:
+//
+//   - Uploaded reports are deleted UploadRetentionDays after their date,
+//     once the date can no longer receive reports, and only if they were
+//     merged. Merged reports are kept indefinitely.
+//   - Dead letters are deleted DeadLetterRetentionDays after the failure
+//     of their task.
+//
+// A policy of zero days is not enforced. If the "dryrun" query parameter is
+// set, the objects that would be deleted are logged, but not deleted.
+func handleRetention(cfg *config.Config, s *storage.API) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		dryRun := r.URL.Query().Get("dryrun") != ""
+		now := time.Now().UTC()
+		var uploads, deadLetters int
+		if cfg.UploadRetentionDays > 0 {
+			// Dates are only expired once the server rejects their reports,
+			// so that no upload arrives after its date was cleaned.
+			expiry := now.AddDate(0, 0, -int(cfg.UploadRetentionDays)).Truncate(24 * time.Hour)
+			if cutoff := lastCompleteDate(now, time.Duration(cfg.MaxReportAgeDays)*24*time.Hour); cutoff.Before(expiry) {
+				expiry = cutoff
+			}
+			n, err := deleteUploads(ctx, s, expiry, dryRun)
+			if err != nil {
+				return err
+			}
+			uploads = n
+		}
+		if cfg.DeadLetterRetentionDays > 0 {
+			expiry := now.AddDate(0, 0, -int(cfg.DeadLetterRetentionDays))
+			n, err := deleteDeadLetters(ctx, s.DeadLetter, expiry, dryRun)
+			if err != nil {
+				return err
+			}
+			deadLetters = n
+		}
+		verb := "deleted"
+		if dryRun {
+			verb = "would delete"
+		}
+		msg := fmt.Sprintf("%s %d uploads from %s and %d dead letters from %s", verb, uploads, s.Upload.URI(), deadLetters, s.DeadLetter.URI())
+		return content.Text(w, msg, http.StatusOK)
+	}
+}
+
+// deleteUploads deletes the merged uploads of the dates before expiry, and
+// returns the number of uploads deleted. Uploads that are not recorded in
+// the manifest of their date's merge object are kept, so that they are not
+// lost before a merge.
+func deleteUploads(ctx context.Context, s *storage.API, expiry time.Time, dryRun bool) (int, error) {
+	dates := make(map[string][]string)
+	it := s.Upload.Objects(ctx, "")
+	for {
+		obj, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		date, _, ok := strings.Cut(obj, "/")
+		if !ok {
+			continue
+		}
+		if d, err := time.Parse(telemetry.DateOnly, date); err != nil || !d.Before(expiry) {
+			continue
+		}
+		dates[date] = append(dates[date], obj)
+	}
+	var sorted []string
+	for date := range dates {
+		sorted = append(sorted, date)
+	}
+	sort.Strings(sorted)
+
+	deleted := 0
+	for _, date := range sorted {
+		m, err := readManifest(ctx, s.Merge, date)
+		if err != nil {
+			return deleted, err
+		}
+		merged := make(map[string]bool)
+		if m != nil {
+			for _, obj := range m.Objects {
+				merged[obj] = true
+			}
+		}
+		var expired []string
+		for _, obj := range dates[date] {
+			if merged[obj] {
+				expired = append(expired, obj)
+			} else {
+				slog.WarnContext(ctx, "keeping unmerged upload", "bucket", s.Upload.URI(), "object", obj)
+			}
+		}
+		if len(expired) == 0 || dryRun {
+			for _, obj := range expired {
+				slog.InfoContext(ctx, "would delete upload", "bucket", s.Upload.URI(), "object", obj)
+			}
+			deleted += len(expired)
+			continue
+		}
+		// The manifest records the deletion before it happens, so that no
+		// merge starts over from the uploads once some of them are gone.
+		if !m.UploadsDeleted {
+			m.UploadsDeleted = true
+			if err := writeManifest(ctx, s.Merge, date, m); err != nil {
+				return deleted, err
+			}
+		}
+		for _, obj := range expired {
+			if err := s.Upload.Delete(ctx, obj); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+				return deleted, err
+			}
+			slog.InfoContext(ctx, "deleted upload", "bucket", s.Upload.URI(), "object", obj)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// deleteDeadLetters deletes the dead letters of tasks that failed before
+// expiry, and returns the number of dead letters deleted.
+func deleteDeadLetters(ctx context.Context, bucket storage.BucketHandle, expiry time.Time, dryRun bool) (int, error) {
+	deleted := 0
+	it := bucket.Objects(ctx, "")
+	for {
+		obj, err := it.Next()
+		if errors.Is(err, storage.ErrObjectIteratorDone) {
+			break
+		}
+		if err != nil {
+			return deleted, err
+		}
+		task, ok := strings.CutSuffix(obj, ".json")
+		if !ok {
+			continue
+		}
+		dl, err := readDeadLetter(ctx, bucket, task)
+		if err != nil {
+			slog.WarnContext(ctx, "reading dead letter", "bucket", bucket.URI(), "object", obj, "error", err)
+			continue
+		}
+		if !dl.Time.Before(expiry) {
+			continue
+		}
+		if dryRun {
+			slog.InfoContext(ctx, "would delete dead letter", "bucket", bucket.URI(), "object", obj, "time", dl.Time)
+			deleted++
+			continue
+		}
+		if err := bucket.Delete(ctx, obj); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return deleted, err
+		}
+		slog.InfoContext(ctx, "deleted dead letter", "bucket", bucket.URI(), "object", obj, "time", dl.Time)
+		deleted++
+	}
+	return deleted, nil
+}
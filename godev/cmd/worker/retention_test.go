@@ -0,0 +1,109 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/charts"
+	wconfig "golang.org/x/telemetry/godev/internal/config"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestRetention(t *testing.T) {
+	ctx := context.Background()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"upload": &s.Upload, "merge": &s.Merge, "chart": &s.Chart, "stats": &s.Stats, "deadletter": &s.DeadLetter} {
+		*b = storage.NewMemBucket(name)
+	}
+	const date = "2024-01-01"
+	upload := func(x float64) string {
+		t.Helper()
+		name := fmt.Sprintf("%s/%g.json", date, x)
+		w, err := s.Upload.Object(name).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report := telemetry.Report{Week: date, X: x, Programs: []*telemetry.ProgramReport{{Program: "example.com/mod/pkg"}}}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	exists := func(bucket storage.BucketHandle, name string) bool {
+		t.Helper()
+		_, err := bucket.Object(name).Generation(ctx)
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			t.Fatal(err)
+		}
+		return err == nil
+	}
+	serve := func(h http.Handler, url string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s status = %d: %s", url, rec.Code, rec.Body)
+		}
+	}
+	ucfg := config.NewConfig(&telemetry.UploadConfig{})
+	cfg := &wconfig.Config{UploadRetentionDays: 30, DeadLetterRetentionDays: 30, MaxReportAgeDays: 21}
+
+	merged := upload(0.1)
+	serve(handleMerge(ucfg, nil, &s), "/merge/?date="+date)
+	unmerged := upload(0.2)
+	oldTask := &deadLetter{Task: "old", Time: time.Now().AddDate(0, 0, -31)}
+	newTask := &deadLetter{Task: "new", Time: time.Now()}
+	for _, dl := range []*deadLetter{oldTask, newTask} {
+		if err := writeDeadLetter(ctx, s.DeadLetter, dl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A dry run deletes nothing.
+	serve(handleRetention(cfg, &s), "/retention/?dryrun=1")
+	if !exists(s.Upload, merged) || !exists(s.DeadLetter, deadLetterName(oldTask.Task)) {
+		t.Fatalf("dry run deleted objects")
+	}
+
+	serve(handleRetention(cfg, &s), "/retention/")
+	for _, test := range []struct {
+		bucket storage.BucketHandle
+		name   string
+		want   bool
+	}{
+		{s.Upload, merged, false},
+		{s.Upload, unmerged, true}, // not merged yet
+		{s.DeadLetter, deadLetterName(oldTask.Task), false},
+		{s.DeadLetter, deadLetterName(newTask.Task), true},
+	} {
+		if got := exists(test.bucket, test.name); got != test.want {
+			t.Errorf("%s/%s exists = %t, want %t", test.bucket.URI(), test.name, got, test.want)
+		}
+	}
+
+	// A full merge keeps the reports of the deleted uploads, whose only copy
+	// is the merge object.
+	serve(handleMerge(ucfg, nil, &s), "/merge/?date="+date+"&full=1")
+	reports, err := charts.ReadMerged(ctx, s.Merge, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 {
+		t.Errorf("merged %d reports after deleting uploads, want 2", len(reports))
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/parquet"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// snapshotPrefix is the prefix of snapshot objects in the merge bucket. A
+// snapshot holds the merged reports of a range of dates, so that many days
+// of data can be downloaded at once. Each snapshot is written in two
+// formats: the merged reports concatenated and compressed with gzip, and a
+// Parquet table of their counters.
+const snapshotPrefix = "snapshots/"
+
+// snapshotName returns the name of the snapshot objects for the dates from
+// start to end, without the extension of their format.
+func snapshotName(start, end time.Time) string {
+	return snapshotPrefix + start.Format(telemetry.DateOnly) + "_" + end.Format(telemetry.DateOnly)
+}
+
+// snapshotColumns are the columns of Parquet snapshots, which hold the rows
+// of the BigQuery export.
+var snapshotColumns = []parquet.Column{
+	{Name: "date", Type: parquet.String},
+	{Name: "x", Type: parquet.Double},
+	{Name: "config", Type: parquet.String},
+	{Name: "program", Type: parquet.String},
+	{Name: "version", Type: parquet.String},
+	{Name: "go_version", Type: parquet.String},
+	{Name: "goos", Type: parquet.String},
+	{Name: "goarch", Type: parquet.String},
+	{Name: "kind", Type: parquet.String},
+	{Name: "counter", Type: parquet.String},
+	{Name: "stack", Type: parquet.String},
+	{Name: "value", Type: parquet.Int64},
+}
+
+// handleSnapshot writes the snapshots of the reports merged on the dates
+// given by the "start" and "end" query parameters. Like the merged reports
+// themselves, the gzip snapshot holds one JSON report per line. Dates for
+// which no reports were merged are skipped.
+//
+// The snapshots are only written if all the merged reports are read: on
+// failure, the writers are aborted and no partial snapshot is left for
+// telemetry.go.dev to link.
+func handleSnapshot(s *storage.API) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		start, end, err := parseDateRange(r.URL)
+		if err != nil {
+			return err
+		}
+		name := snapshotName(start, end)
+		days, err := writeSnapshots(r.Context(), s, start, end, name)
+		if err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("wrote the reports of %d days to %s/%s.{json.gz,parquet}", days, s.Merge.URI(), name)
+		return content.Text(w, msg, http.StatusOK)
+	}
+}
+
+// writeSnapshots writes the snapshots of the dates from start to end to the
+// objects of the merge bucket with the given name, and returns the number
+// of dates with merged reports.
+func writeSnapshots(ctx context.Context, s *storage.API, start, end time.Time, name string) (int, error) {
+	// Canceling the writers' context before closing them abandons the
+	// snapshots rather than committing them.
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jsonOut, err := s.Merge.Object(name + ".json.gz").NewWriter(wctx)
+	if err != nil {
+		return 0, err
+	}
+	parquetOut, err := s.Merge.Object(name + ".parquet").NewWriter(wctx)
+	if err != nil {
+		cancel()
+		jsonOut.Close()
+		return 0, err
+	}
+	abort := func(err error) (int, error) {
+		cancel()
+		jsonOut.Close()
+		parquetOut.Close()
+		return 0, err
+	}
+
+	zw := gzip.NewWriter(jsonOut)
+	pw := parquet.NewWriter(parquetOut, snapshotColumns)
+	days := 0
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		in, err := s.Merge.Object(date.Format(telemetry.DateOnly) + ".json").NewReader(ctx)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			continue
+		}
+		if err != nil {
+			return abort(err)
+		}
+		err = copySnapshot(zw, pw, date, in)
+		in.Close()
+		if err != nil {
+			return abort(err)
+		}
+		days++
+	}
+	if err := zw.Close(); err != nil {
+		return abort(err)
+	}
+	if err := pw.Close(); err != nil {
+		return abort(err)
+	}
+	if err := parquetOut.Close(); err != nil {
+		return abort(err)
+	}
+	if err := jsonOut.Close(); err != nil {
+		return 0, err
+	}
+	return days, nil
+}
+
+// copySnapshot copies the merged reports of date read from in to the gzip
+// snapshot zw, and adds their counters to the Parquet snapshot pw.
+func copySnapshot(zw io.Writer, pw *parquet.Writer, date time.Time, in io.Reader) error {
+	scanner := bufio.NewScanner(io.TeeReader(in, zw))
+	for scanner.Scan() {
+		var report telemetry.Report
+		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil {
+			return fmt.Errorf("merged reports of %s: %v", date.Format(telemetry.DateOnly), err)
+		}
+		for _, row := range exportRows(date, &report) {
+			err := pw.Write(row.Date, row.X, row.Config, row.Program, row.Version, row.GoVersion,
+				row.GOOS, row.GOARCH, row.Kind, row.Counter, row.Stack, row.Value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	merge, err := storage.NewFSBucket(ctx, t.TempDir(), "merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &storage.API{Merge: merge}
+	for date, data := range map[string]string{
+		"2024-01-01": "{\"X\":0.1}\n{\"X\":0.2}\n",
+		"2024-01-03": "{\"X\":0.3}\n",
+		"2024-01-08": "{\"X\":0.4}\n", // outside the range
+	} {
+		w, err := merge.Object(date + ".json").NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handleSnapshot(s).ServeHTTP(rec, httptest.NewRequest("POST", "/snapshot/?start=2024-01-01&end=2024-01-07", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("snapshot status = %d: %s", rec.Code, rec.Body)
+	}
+
+	r, err := merge.Object("snapshots/2024-01-01_2024-01-07.json.gz").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\"X\":0.1}\n{\"X\":0.2}\n{\"X\":0.3}\n"
+	if string(got) != want {
+		t.Errorf("snapshot contents = %q, want %q", got, want)
+	}
+
+	pr, err := merge.Object("snapshots/2024-01-01_2024-01-07.parquet").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	table, err := io.ReadAll(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(table, []byte("PAR1")) || !bytes.HasSuffix(table, []byte("PAR1")) {
+		t.Errorf("Parquet snapshot is not a Parquet file: %q", table)
+	}
+}
+
+func TestSnapshotReadError(t *testing.T) {
+	ctx := context.Background()
+	merge := storage.NewMemBucket("merge")
+	for _, date := range []string{"2024-01-01", "2024-01-02"} {
+		w, err := merge.Object(date + ".json").NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, "{\"X\":0.1}\n"); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Reading the second date fails after the first was copied.
+	merge.Fail = func(op, name string) error {
+		if op == storage.MemRead && name == "2024-01-02.json" {
+			return errors.New("read failed")
+		}
+		return nil
+	}
+
+	rec := httptest.NewRecorder()
+	handleSnapshot(&storage.API{Merge: merge}).ServeHTTP(rec, httptest.NewRequest("POST", "/snapshot/?start=2024-01-01&end=2024-01-07", nil))
+	if rec.Code == http.StatusOK {
+		t.Fatalf("snapshot with a failing read succeeded")
+	}
+	merge.Fail = nil
+	for _, obj := range []string{"snapshots/2024-01-01_2024-01-07.json.gz", "snapshots/2024-01-01_2024-01-07.parquet"} {
+		if _, err := merge.Object(obj).Generation(ctx); !errors.Is(err, storage.ErrObjectNotExist) {
+			t.Errorf("after a failed snapshot, Generation(%s) = %v, want %v", obj, err, storage.ErrObjectNotExist)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+	"golang.org/x/telemetry/internal/uploadable"
+)
+
+// failureMalformed is the failure reason of uploads that could not be
+// decoded.
+const failureMalformed = "malformed"
+
+// uploadStats are the public statistics of the uploads for a date, written
+// to the stats bucket when they are merged.
+type uploadStats struct {
+	Date string
+
+	Reports int // uploads received, whether merged or not
+	UniqueX int // distinct values of X among the merged reports
+
+	// Failures counts, by reason, the uploads excluded from the merged
+	// report, and the program reports and counters of merged reports that
+	// the current upload config does not permit. Suspect uploads are
+	// counted once for each reason the server tagged them with.
+	Failures map[string]int
+
+	// Programs counts the merged reports that include each program.
+	Programs map[string]int
+}
+
+// statsTracker accumulates the uploadStats of the uploads for a date.
+type statsTracker struct {
+	cfg   *tconfig.Config
+	seenX map[float64]bool
+	s     uploadStats
+}
+
+func newStatsTracker(cfg *tconfig.Config, date string) *statsTracker {
+	return &statsTracker{
+		cfg:   cfg,
+		seenX: make(map[float64]bool),
+		s: uploadStats{
+			Date:     date,
+			Failures: make(map[string]int),
+			Programs: make(map[string]int),
+		},
+	}
+}
+
+// suspect records an upload tagged as suspect with the given comma-separated
+// reasons.
+func (t *statsTracker) suspect(reasons string) {
+	t.s.Reports++
+	for _, r := range strings.Split(reasons, ",") {
+		t.s.Failures[r]++
+	}
+}
+
+// malformed records an upload that could not be decoded.
+func (t *statsTracker) malformed() {
+	t.s.Reports++
+	t.s.Failures[failureMalformed]++
+}
+
+// merged records a report that is merged.
+func (t *statsTracker) merged(report *telemetry.Report) {
+	t.s.Reports++
+	if !t.seenX[report.X] {
+		t.s.UniqueX++
+		t.seenX[report.X] = true
+	}
+	seen := make(map[string]bool)
+	for _, p := range report.Programs {
+		if !seen[p.Program] {
+			t.s.Programs[p.Program]++
+			seen[p.Program] = true
+		}
+		meta := uploadable.Meta{
+			Program:   p.Program,
+			Version:   p.Version,
+			GoVersion: p.GoVersion,
+			GOOS:      p.GOOS,
+			GOARCH:    p.GOARCH,
+		}
+		if d, reason := uploadable.DecideProgram(t.cfg, meta); d == uploadable.Drop {
+			t.s.Failures[reason.String()]++
+			continue
+		}
+		for _, counts := range []map[string]int64{p.Counters, p.Stacks, p.Events} {
+			for name := range counts {
+				if d, reason := uploadable.Decide(t.cfg, meta, name); d == uploadable.Drop {
+					t.s.Failures[reason.String()]++
+				}
+			}
+		}
+	}
+}
+
+// writeStats writes s to <date>.json in the stats bucket, replacing any
+// earlier statistics for the date.
+func writeStats(ctx context.Context, bucket storage.BucketHandle, s *uploadStats) error {
+	out, err := bucket.Object(s.Date + ".json").NewWriter(ctx)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := json.NewEncoder(out).Encode(s); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestMergeStats(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"upload": &s.Upload, "merge": &s.Merge, "chart": &s.Chart, "stats": &s.Stats} {
+		bucket, err := storage.NewFSBucket(ctx, dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b = bucket
+	}
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:      []string{"linux"},
+		GOARCH:    []string{"amd64"},
+		GoVersion: []string{"go1.22.0"},
+		Programs: []*telemetry.ProgramConfig{
+			{
+				Name:     "gopls",
+				Versions: []string{"v0.15.0"},
+				Counters: []telemetry.CounterConfig{{Name: "known", Rate: 1}},
+			},
+			{
+				Name:     "cmd/go",
+				Versions: []string{"go1.22.0"},
+			},
+		},
+	})
+	program := func(name, version string) *telemetry.ProgramReport {
+		return &telemetry.ProgramReport{
+			Program:   name,
+			Version:   version,
+			GoVersion: "go1.22.0",
+			GOOS:      "linux",
+			GOARCH:    "amd64",
+			Counters:  map[string]int64{"known": 1, "unknown": 1},
+		}
+	}
+	report := func(x float64, programs ...*telemetry.ProgramReport) string {
+		return mustMarshal(t, telemetry.Report{Week: "2024-01-01", X: x, Programs: programs})
+	}
+	uploads := map[string]struct {
+		data    string
+		suspect string
+	}{
+		"a": {data: report(0.1, program("gopls", "v0.15.0"), program("gopls", "v0.16.0"))},
+		"b": {data: report(0.1, program("cmd/go", "go1.22.0"))},
+		"c": {data: report(0.2, program("gopls", "v0.15.0"))},
+		"d": {data: report(0.3), suspect: "replay,magnitude"},
+		"e": {data: `{"Week": "2024-01-01", "X":`},
+	}
+	for name, u := range uploads {
+		obj := s.Upload.Object(fmt.Sprintf("2024-01-01/%s.json", name))
+		w, err := obj.NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(u.data)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if u.suspect != "" {
+			if err := obj.SetMetadata(ctx, map[string]string{storage.SuspectMetadata: u.suspect}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handleMerge(cfg, nil, &s).ServeHTTP(rec, httptest.NewRequest("GET", "/merge/?date=2024-01-01", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("merge status = %d: %s", rec.Code, rec.Body)
+	}
+
+	r, err := s.Stats.Object("2024-01-01.json").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var got uploadStats
+	if err := json.NewDecoder(r).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := uploadStats{
+		Date:    "2024-01-01",
+		Reports: 5,
+		UniqueX: 2,
+		Failures: map[string]int{
+			"replay":                               1,
+			"magnitude":                            1,
+			"malformed":                            1,
+			"program version is not in the config": 1, // gopls v0.16.0
+			"counter is not in the config":         4, // "unknown" twice for gopls v0.15.0, and both counters of cmd/go
+		},
+		Programs: map[string]int{"gopls": 2, "cmd/go": 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("upload stats mismatch (-want +got):\n%s", diff)
+	}
+}
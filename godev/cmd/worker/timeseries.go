@@ -0,0 +1,205 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/charts"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// maxTimeSeriesWeeks is the number of weeks kept in a time series. Older
+// weeks are dropped as new weeks are added.
+const maxTimeSeriesWeeks = 104
+
+// A timeSeries holds the weekly counts of the counters of a program. It is
+// stored in the time series bucket as <program>.json, such as
+// golang.org/x/tools/gopls.json.
+type timeSeries struct {
+	Program  string
+	Counters map[string][]timePoint // counter name -> points, by week
+}
+
+// A timePoint holds the counts of a counter for the week ending on Week.
+type timePoint struct {
+	Week      string
+	Value     int64 // sum of the counter's values in the week's reports
+	Reporters int   // number of reports that included the counter
+}
+
+// counterWeek accumulates the counts of a counter over the reports of a week.
+type counterWeek struct {
+	value     int64
+	reporters map[float64]bool // X values of the reports
+}
+
+// handleTimeSeries adds the week ending on the date given by the "date" query
+// parameter to the time series of each program reported in the merged
+// reports of that week.
+//
+// Stack counters are identified by their name, without the stack, as for the
+// new counter index.
+func handleTimeSeries(s *storage.API) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		end, err := time.Parse(telemetry.DateOnly, r.URL.Query().Get("date"))
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		start := end.AddDate(0, 0, -6)
+		week := end.Format(telemetry.DateOnly)
+
+		counts := make(map[string]map[string]*counterWeek) // program -> counter -> counts
+		n := 0
+		for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+			err := charts.ScanMerged(ctx, s.Merge, date, func(report *telemetry.Report) {
+				addCounterWeeks(counts, report)
+				n++
+			})
+			if errors.Is(err, storage.ErrObjectNotExist) {
+				return content.Error(err, http.StatusNotFound)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for program, counters := range counts {
+			ts, gen, err := readTimeSeries(ctx, s.TimeSeries, program)
+			if errors.Is(err, storage.ErrObjectNotExist) {
+				ts = &timeSeries{Program: program}
+			} else if err != nil {
+				return err
+			}
+			ts.update(week, counters)
+			err = writeTimeSeries(ctx, s.TimeSeries, ts, gen)
+			if errors.Is(err, storage.ErrPreconditionFailed) {
+				// The time series was updated for another week meanwhile.
+				// Fail, so that the task is retried from the updated series.
+				return content.Error(fmt.Errorf("updating the time series of %s: %w", program, err), http.StatusConflict)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		msg := fmt.Sprintf("added the week ending %s to the time series of %d programs in %s, from %d reports", week, len(counts), s.TimeSeries.URI(), n)
+		return content.Text(w, msg, http.StatusOK)
+	}
+}
+
+// addCounterWeeks adds the counters of a report to counts.
+func addCounterWeeks(counts map[string]map[string]*counterWeek, r *telemetry.Report) {
+	for _, p := range r.Programs {
+		if counts[p.Program] == nil {
+			counts[p.Program] = make(map[string]*counterWeek)
+		}
+		add := func(name string, value int64) {
+			c := counts[p.Program][name]
+			if c == nil {
+				c = &counterWeek{reporters: make(map[float64]bool)}
+				counts[p.Program][name] = c
+			}
+			c.value += value
+			c.reporters[r.X] = true
+		}
+		for c, value := range p.Counters {
+			add(c, value)
+		}
+		for s, value := range p.Stacks {
+			name, _, _ := strings.Cut(s, "\n")
+			add(name, value)
+		}
+	}
+}
+
+// update sets the points of the counters for the given week, replacing any
+// that were set before, and drops the points of weeks that are more than
+// maxTimeSeriesWeeks older than the latest week.
+func (ts *timeSeries) update(week string, counters map[string]*counterWeek) {
+	if ts.Counters == nil {
+		ts.Counters = make(map[string][]timePoint)
+	}
+	for name, c := range counters {
+		points := ts.Counters[name]
+		i := sort.Search(len(points), func(i int) bool { return points[i].Week >= week })
+		p := timePoint{Week: week, Value: c.value, Reporters: len(c.reporters)}
+		if i < len(points) && points[i].Week == week {
+			points[i] = p
+		} else {
+			points = append(points[:i], append([]timePoint{p}, points[i:]...)...)
+		}
+		ts.Counters[name] = points
+	}
+
+	var latest string
+	for _, points := range ts.Counters {
+		if w := points[len(points)-1].Week; w > latest {
+			latest = w
+		}
+	}
+	t, err := time.Parse(telemetry.DateOnly, latest)
+	if err != nil {
+		return
+	}
+	oldest := t.AddDate(0, 0, -7*(maxTimeSeriesWeeks-1)).Format(telemetry.DateOnly)
+	for name, points := range ts.Counters {
+		i := sort.Search(len(points), func(i int) bool { return points[i].Week >= oldest })
+		if i == len(points) {
+			delete(ts.Counters, name)
+		} else {
+			ts.Counters[name] = points[i:]
+		}
+	}
+}
+
+func timeSeriesName(program string) string {
+	return program + ".json"
+}
+
+// readTimeSeries reads the time series of a program, and returns it with the
+// generation of its object, or 0 if there is none.
+func readTimeSeries(ctx context.Context, bucket storage.BucketHandle, program string) (*timeSeries, int64, error) {
+	obj := bucket.Object(timeSeriesName(program))
+	// The generation is read first: if the object changes before it is
+	// read, writing it back fails rather than losing the change.
+	gen, err := obj.Generation(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	in, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer in.Close()
+	var ts timeSeries
+	if err := json.NewDecoder(in).Decode(&ts); err != nil {
+		return nil, 0, fmt.Errorf("%w: time series for %s: %v", charts.ErrInvalid, program, err)
+	}
+	return &ts, gen, nil
+}
+
+// writeTimeSeries writes the time series of a program, if the generation of
+// its object is still gen.
+func writeTimeSeries(ctx context.Context, bucket storage.BucketHandle, ts *timeSeries, gen int64) error {
+	out, err := bucket.Object(timeSeriesName(ts.Program)).NewWriterIf(ctx, gen)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if err := json.NewEncoder(out).Encode(ts); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestTimeSeries(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"merge": &s.Merge, "timeseries": &s.TimeSeries} {
+		bucket, err := storage.NewFSBucket(ctx, dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*b = bucket
+	}
+	merged := map[string]string{
+		"2024-01-01": `{"X":0.1,"Programs":[{"Program":"cmd/go","Counters":{"go/invocations":2},"Stacks":{"go/bug\nframe":1}}]}` + "\n" +
+			`{"X":0.2,"Programs":[{"Program":"cmd/go","Counters":{"go/invocations":3}}]}` + "\n",
+		"2024-01-05": `{"X":0.1,"Programs":[{"Program":"cmd/go","Counters":{"go/invocations":1}}]}` + "\n",
+		"2024-01-09": `{"X":0.3,"Programs":[{"Program":"golang.org/x/tools/gopls","Counters":{"gopls/client:vscode":1}}]}` + "\n",
+		"2024-01-10": `{"X":0.4,"Programs":[{"Program":"cmd/go","Counters":{"go/invocations":4}}]}` + "\n",
+	}
+	for date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); date.Day() <= 14; date = date.AddDate(0, 0, 1) {
+		w, err := s.Merge.Object(date.Format(telemetry.DateOnly) + ".json").NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, merged[date.Format(telemetry.DateOnly)]); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The second week is added twice: the second time replaces its points.
+	for _, date := range []string{"2024-01-07", "2024-01-14", "2024-01-14"} {
+		rec := httptest.NewRecorder()
+		handleTimeSeries(&s).ServeHTTP(rec, httptest.NewRequest("POST", "/timeseries/?date="+date, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("timeseries?date=%s status = %d: %s", date, rec.Code, rec.Body)
+		}
+	}
+
+	got, _, err := readTimeSeries(ctx, s.TimeSeries, "cmd/go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &timeSeries{
+		Program: "cmd/go",
+		Counters: map[string][]timePoint{
+			"go/invocations": {
+				{Week: "2024-01-07", Value: 6, Reporters: 2},
+				{Week: "2024-01-14", Value: 4, Reporters: 1},
+			},
+			"go/bug": {
+				{Week: "2024-01-07", Value: 1, Reporters: 1},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("cmd/go time series mismatch (-want +got):\n%s", diff)
+	}
+	if _, _, err := readTimeSeries(ctx, s.TimeSeries, "golang.org/x/tools/gopls"); err != nil {
+		t.Errorf("reading gopls time series: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handleTimeSeries(&s).ServeHTTP(rec, httptest.NewRequest("POST", "/timeseries/?date=2024-01-21", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("timeseries for a week without merged reports: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestTimeSeriesUpdateDropsOldWeeks(t *testing.T) {
+	ts := &timeSeries{
+		Program: "cmd/go",
+		Counters: map[string][]timePoint{
+			"old": {{Week: "2020-01-05", Value: 1, Reporters: 1}},
+			"go/invocations": {
+				{Week: "2020-01-05", Value: 1, Reporters: 1},
+				{Week: "2022-01-02", Value: 2, Reporters: 1},
+			},
+		},
+	}
+	ts.update("2022-01-09", map[string]*counterWeek{
+		"go/invocations": {value: 3, reporters: map[float64]bool{0.1: true, 0.2: true}},
+	})
+	want := map[string][]timePoint{
+		"go/invocations": {
+			{Week: "2022-01-02", Value: 2, Reporters: 1},
+			{Week: "2022-01-09", Value: 3, Reporters: 2},
+		},
+	}
+	if diff := cmp.Diff(want, ts.Counters); diff != "" {
+		t.Errorf("update mismatch (-want +got):\n%s", diff)
+	}
+}
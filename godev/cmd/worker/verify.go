@@ -0,0 +1,78 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// Results of verifying an object against its digest.
+const (
+	verifyOK       = "ok"
+	verifyMismatch = "mismatch"  // truncated or otherwise corrupted
+	verifyNoDigest = "no digest" // written before digests were
+)
+
+// A verification is the result of verifying an object.
+type verification struct {
+	Bucket string
+	Object string
+	Result string
+	Error  string `json:",omitempty"`
+}
+
+// handleVerify checks the objects written for the date given by the "date"
+// query parameter against their digests: the merge object, the daily chart,
+// and the weekly chart ending on the date. Objects that do not exist are
+// skipped. It responds with the result for each object, with status 422
+// Unprocessable Entity if any does not match its digest.
+func handleVerify(s *storage.API) content.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		ctx := r.Context()
+		date, err := time.Parse(telemetry.DateOnly, r.URL.Query().Get("date"))
+		if err != nil {
+			return content.Error(err, http.StatusBadRequest)
+		}
+		objects := []struct {
+			bucket storage.BucketHandle
+			name   string
+		}{
+			{s.Merge, date.Format(telemetry.DateOnly) + ".json"},
+			{s.Chart, fileName(date, date)},
+			{s.Chart, fileName(date.AddDate(0, 0, -6), date)},
+		}
+		results := []*verification{}
+		code := http.StatusOK
+		for _, o := range objects {
+			if _, err := o.bucket.Object(o.name).Generation(ctx); errors.Is(err, storage.ErrObjectNotExist) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			v := &verification{Bucket: o.bucket.URI(), Object: o.name, Result: verifyOK}
+			err := storage.VerifyDigest(ctx, o.bucket, o.name)
+			switch {
+			case errors.Is(err, storage.ErrObjectNotExist):
+				v.Result = verifyNoDigest
+			case errors.Is(err, storage.ErrDigestMismatch):
+				v.Result = verifyMismatch
+				v.Error = err.Error()
+				code = http.StatusUnprocessableEntity
+				slog.ErrorContext(ctx, "object does not match its digest", "bucket", v.Bucket, "object", v.Object, "error", err)
+			case err != nil:
+				return err
+			}
+			results = append(results, v)
+		}
+		return content.JSON(w, results, code)
+	}
+}
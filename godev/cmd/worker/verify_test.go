@@ -0,0 +1,94 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	var s storage.API
+	for name, b := range map[string]*storage.BucketHandle{"upload": &s.Upload, "merge": &s.Merge, "chart": &s.Chart, "stats": &s.Stats} {
+		*b = storage.NewMemBucket(name)
+	}
+	w, err := s.Upload.Object("2024-01-01/0.1.json").NewWriter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewEncoder(w).Encode(telemetry.Report{Week: "2024-01-01", X: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ucfg := config.NewConfig(&telemetry.UploadConfig{})
+	if _, err := merge(ctx, ucfg, nil, &s, "2024-01-01", false); err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := writeCharts(ctx, ucfg, nil, &s, date, date); err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func(wantCode int, want []*verification) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handleVerify(&s).ServeHTTP(rec, httptest.NewRequest("GET", "/verify/?date=2024-01-01", nil))
+		if rec.Code != wantCode {
+			t.Fatalf("verify status = %d, want %d: %s", rec.Code, wantCode, rec.Body)
+		}
+		var got []*verification
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range got {
+			v.Error = ""
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("verify mismatch (-want +got):\n%s", diff)
+		}
+	}
+	verify(http.StatusOK, []*verification{
+		{Bucket: s.Merge.URI(), Object: "2024-01-01.json", Result: verifyOK},
+		{Bucket: s.Chart.URI(), Object: "2024-01-01.json", Result: verifyOK},
+	})
+
+	// Truncate the merge object, as an interrupted write would.
+	r, err := s.Merge.Object("2024-01-01.json").NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err = s.Merge.Object("2024-01-01.json").NewWriter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data[:len(data)/2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	verify(http.StatusUnprocessableEntity, []*verification{
+		{Bucket: s.Merge.URI(), Object: "2024-01-01.json", Result: verifyMismatch},
+		{Bucket: s.Chart.URI(), Object: "2024-01-01.json", Result: verifyOK},
+	})
+}
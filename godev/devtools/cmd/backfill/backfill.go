@@ -0,0 +1,89 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The backfill command asks the worker to regenerate the merged reports and
+// charts of a range of dates, for example after the aggregation logic
+// changed, and prints the worker's progress as it goes.
+//
+// By default, it runs against a local worker, such as one started with
+// go run ./godev/cmd/worker. To run against a deployed worker, pass its URL
+// with -worker, and an identity token with -token, such as the output of
+// gcloud auth print-identity-token.
+//
+// See --help for more details.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/telemetry/godev/internal/config"
+)
+
+var (
+	worker      = flag.String("worker", config.NewConfig().WorkerURL, "The URL of the worker")
+	start       = flag.String("start", "", "The first date to backfill, as YYYY-MM-DD (required)")
+	end         = flag.String("end", "", "The last date to backfill, as YYYY-MM-DD (required)")
+	ops         = flag.String("ops", "merge,chart", "Comma-separated operations to run: merge, chart")
+	concurrency = flag.Int("concurrency", 4, "The number of dates the worker processes at once")
+	token       = flag.String("token", "", "If set, an identity token to authenticate to the worker")
+)
+
+func main() {
+	flag.Parse()
+	if *start == "" || *end == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	q := url.Values{
+		"start":       {*start},
+		"end":         {*end},
+		"ops":         {*ops},
+		"concurrency": {strconv.Itoa(*concurrency)},
+	}
+	req, err := http.NewRequest("POST", strings.TrimSuffix(*worker, "/")+"/backfill/?"+q.Encode(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *token != "" {
+		req.Header.Set("Authorization", "Bearer "+*token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// The worker reports each step as it finishes, and ends with a summary
+	// line, which is missing if the backfill was interrupted.
+	var summary string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fmt.Println(line)
+		if strings.HasPrefix(line, "backfill done:") {
+			summary = line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("reading the worker's progress: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("backfill failed with status %d", resp.StatusCode)
+	}
+	if summary == "" {
+		log.Fatal("backfill was interrupted")
+	}
+	if !strings.HasSuffix(summary, " 0 failed") {
+		os.Exit(1)
+	}
+}
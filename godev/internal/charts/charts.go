@@ -0,0 +1,644 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package charts computes the charts shown on telemetry.go.dev from merged
+// upload reports.
+//
+// The worker precomputes charts for each day and week, and the server
+// computes charts for other date ranges on demand.
+package charts
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/version"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/telemetry/godev/internal/storage"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/counter"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// ErrInvalid is wrapped by the errors of functions that read stored data,
+// such as merged reports or chart data, that is malformed or that this
+// package cannot read.
+var ErrInvalid = errors.New("invalid data")
+
+// Compute returns the charts for the given reports, which were merged for
+// the days from start to end inclusive, applying the rules of the chart
+// config as described at NewBuilder. Retired counters are redacted from the
+// reports.
+func Compute(cfg *tconfig.Config, rules *Rules, start, end time.Time, reports []telemetry.Report) *Data {
+	b := NewBuilder(cfg, rules)
+	for i := range reports {
+		b.Add(&reports[i])
+	}
+	return b.Charts(start, end)
+}
+
+// A Builder computes charts from reports that are added one at a time. Only
+// the counts that the charts need are kept, not the reports, so the charts
+// of long date ranges can be computed without holding all their reports in
+// memory.
+type Builder struct {
+	cfg        *tconfig.Config
+	rules      Rules
+	data       data
+	stacks     stackData
+	numReports int
+	redacted   map[string]int // retired counter -> reports it was redacted from
+}
+
+// NewBuilder returns a Builder for the charts of the given upload config,
+// with the given rules of the chart config applied: the counts of renamed
+// charts are folded into their current names, those of retired counters are
+// redacted, and charts of version buckets are charted at their declared
+// granularities.
+func NewBuilder(cfg *tconfig.Config, rules *Rules) *Builder {
+	b := &Builder{
+		cfg:      cfg,
+		data:     make(data),
+		stacks:   make(stackData),
+		redacted: make(map[string]int),
+	}
+	if rules != nil {
+		b.rules = *rules
+	}
+	return b
+}
+
+// Add adds the counts of a report to the charts, after redacting the
+// report's retired counters.
+func (b *Builder) Add(report *telemetry.Report) {
+	for _, name := range b.rules.Retirements.Redact(report) {
+		b.redacted[name]++
+	}
+	b.data.add(report)
+	b.stacks.add(b.cfg, report)
+	b.numReports++
+}
+
+// Charts returns the charts for the added reports, which were merged for the
+// days from start to end inclusive. The Builder must not be used afterwards.
+func (b *Builder) Charts(start, end time.Time) *Data {
+	b.data.fold(b.rules.Renames)
+	result := charts(b.cfg, &b.rules, start.Format(telemetry.DateOnly), end.Format(telemetry.DateOnly), b.data, b.stacks, b.numReports)
+	result.Version = Version
+	return result
+}
+
+// Redacted returns the number of added reports that each retired counter
+// was redacted from.
+func (b *Builder) Redacted() map[string]int {
+	return b.redacted
+}
+
+// ReadMerged reads the reports merged for the given date from the merge
+// bucket. If they have not been merged, the error wraps
+// storage.ErrObjectNotExist.
+func ReadMerged(ctx context.Context, merge storage.BucketHandle, date time.Time) ([]telemetry.Report, error) {
+	var reports []telemetry.Report
+	err := ScanMerged(ctx, merge, date, func(report *telemetry.Report) {
+		reports = append(reports, *report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ScanMerged calls f for each of the reports merged for the given date, in
+// order, reading them from the merge bucket one at a time. If they have not
+// been merged, the error wraps storage.ErrObjectNotExist.
+func ScanMerged(ctx context.Context, merge storage.BucketHandle, date time.Time, f func(*telemetry.Report)) error {
+	name := date.Format(telemetry.DateOnly) + ".json"
+	in, err := merge.Object(name).NewReader(ctx)
+	if err != nil {
+		return fmt.Errorf("reading merge file %s: %w", name, err)
+	}
+	defer in.Close()
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		var report telemetry.Report
+		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil {
+			return fmt.Errorf("%w: merge file %s: %v", ErrInvalid, name, err)
+		}
+		f(&report)
+	}
+	return nil
+}
+
+// Data holds the charts for a date range.
+type Data struct {
+	// Version is the version of the format of the data. Chart data written
+	// by this package has the current Version; use Decode to read data that
+	// may be older.
+	Version int
+
+	DateRange  [2]string
+	Programs   []*Program
+	NumReports int
+}
+
+// A Program holds the charts for a program.
+type Program struct {
+	ID     string
+	Name   string
+	Charts []*Chart
+}
+
+// A Chart is a chart of the counts of related counters.
+type Chart struct {
+	ID   string
+	Name string
+	Type string
+	Data []*Datum
+
+	// RenamedFrom lists the former names of the chart, whose data is
+	// included in Data.
+	RenamedFrom []string `json:",omitempty"`
+}
+
+func (c *Chart) String() string {
+	bytes, _ := json.Marshal(c)
+	return string(bytes)
+}
+
+// A Datum is a data point of a Chart.
+type Datum struct {
+	Week  string
+	Key   string
+	Value float64
+}
+
+// maxChartWorkers bounds the number of programs whose charts are computed
+// concurrently.
+var maxChartWorkers = runtime.GOMAXPROCS(0)
+
+func charts(cfg *tconfig.Config, rules *Rules, start, end string, d data, s stackData, numReports int) *Data {
+	if rules == nil {
+		rules = new(Rules)
+	}
+	result := &Data{DateRange: [2]string{start, end}, NumReports: numReports}
+	if len(cfg.Programs) > 0 {
+		result.Programs = make([]*Program, len(cfg.Programs))
+	}
+	// The charts of each program only read d and s, so programs are charted
+	// concurrently. Each is stored at the index of its program, so that the
+	// output is in the order of the config regardless of scheduling.
+	var g errgroup.Group
+	g.SetLimit(maxChartWorkers)
+	for i, p := range cfg.Programs {
+		g.Go(func() error {
+			result.Programs[i] = programCharts(cfg, rules, p, d, s)
+			return nil
+		})
+	}
+	g.Wait()
+	return result
+}
+
+// programCharts returns the charts of a program.
+func programCharts(cfg *tconfig.Config, rules *Rules, p *telemetry.ProgramConfig, d data, s stackData) *Program {
+	prog := &Program{ID: "charts:" + p.Name, Name: p.Name}
+	program := programName(p.Name)
+	charts := []*Chart{d.reporters(program)}
+	if !telemetry.IsToolchainProgram(p.Name) {
+		charts = append(charts, d.partition(program, versionCounter, toSliceOf[bucketName](p.Versions), partitionOptions{
+			ignoreEmptyBuckets: true,
+			// Don't normalize buckets: we want to see counts for all versions.
+			compareBuckets: compareSemver,
+		}))
+	}
+	charts = append(charts,
+		d.partition(program, goosCounter, toSliceOf[bucketName](cfg.GOOS), partitionOptions{}),
+		d.partition(program, goarchCounter, toSliceOf[bucketName](cfg.GOARCH), partitionOptions{}),
+		d.partition(program, platformCounter, platforms(cfg), partitionOptions{
+			// Most combinations of GOOS and GOARCH are not ports.
+			ignoreEmptyBuckets: true,
+		}),
+		d.partition(program, goversionCounter, toSliceOf[bucketName](cfg.GoVersion), partitionOptions{
+			ignoreEmptyBuckets: true,
+			normalizeBucket: func(b bucketName) bucketName {
+				// map go1.2.3 -> go1.2
+				return bucketName(goMajorMinor(string(b)))
+			},
+			compareBuckets: version.Compare,
+		}))
+	for _, c := range p.Counters {
+		// TODO: chart histogram counters (tconfig.Kind(c) is
+		// telemetry.KindHistogram) with their buckets in order of their bounds.
+		chart, _ := splitCounterName(c.Name)
+		var buckets []bucketName
+		for _, counter := range tconfig.Expand(c.Name) {
+			_, bucket := splitCounterName(counter)
+			buckets = append(buckets, bucket)
+		}
+		granularities := rules.Granularities[program][chart]
+		if len(granularities) == 0 {
+			granularities = []string{fullVersions}
+		}
+		for i, granularity := range granularities {
+			opts := partitionOptions{fraction: tconfig.Kind(c) == telemetry.KindBoolean}
+			if len(rules.Granularities[program][chart]) > 0 {
+				opts.normalizeBucket = normalizeVersion(granularity)
+				opts.compareBuckets = compareVersions
+			}
+			partition := d.partition(program, chart, buckets, opts)
+			if partition != nil {
+				partition.ID, partition.Name = variant(partition.ID, partition.Name, i, granularity)
+				partition.RenamedFrom = toSliceOf[string](rules.Renames[program][chart])
+			}
+			charts = append(charts, partition)
+		}
+	}
+	for _, e := range p.Events {
+		// Each attribute of an event is charted separately, as the
+		// number of combinations of values may be large.
+		for _, a := range e.Attrs {
+			charts = append(charts, d.partition(program, eventChartName(e.Name, a.Name), toSliceOf[bucketName](a.Values), partitionOptions{}))
+		}
+	}
+	for _, st := range p.Stacks {
+		charts = append(charts, s.chart(program, graphName(st.Name)))
+	}
+	for _, p := range charts {
+		if p != nil {
+			prog.Charts = append(prog.Charts, p)
+		}
+	}
+	return prog
+}
+
+// platformBucket returns the bucket of a platform in the GOOS/GOARCH chart.
+func platformBucket(goos, goarch string) bucketName {
+	return bucketName(goos + "/" + goarch)
+}
+
+// platforms returns the buckets of the GOOS/GOARCH chart: every combination
+// of the GOOS and GOARCH values of the upload config.
+func platforms(cfg *tconfig.Config) []bucketName {
+	var buckets []bucketName
+	for _, goos := range cfg.GOOS {
+		for _, goarch := range cfg.GOARCH {
+			buckets = append(buckets, platformBucket(goos, goarch))
+		}
+	}
+	return buckets
+}
+
+// toSliceOf converts a slice of once string type to another.
+func toSliceOf[To, From ~string](s []From) []To {
+	var s2 []To
+	for _, v := range s {
+		s2 = append(s2, To(v))
+	}
+	return s2
+}
+
+// compareSemver wraps semver.Compare, to differentiate equivalent semver
+// lexically, as we want all sorting to be stable.
+func compareSemver(x, y string) int {
+	c := semver.Compare(x, y)
+	if c != 0 {
+		return c
+	}
+	return compareLexically(x, y)
+}
+
+func compareLexically(x, y string) int {
+	switch {
+	case x < y:
+		return -1
+	case x == y:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// partitionOptions controls the behavior of partition charts
+type partitionOptions struct {
+	// If ignoreEmptyBuckets is set, don't include data points with 0 count.
+	ignoreEmptyBuckets bool
+
+	// If normalizeBuckets is provided, it is used to map bucket names to new
+	// values. Buckets that map to the same value will be merged.
+	normalizeBucket func(bucketName) bucketName // if set, used to
+
+	// If compareBuckets is provided, it is used to sort the buckets, where
+	// compareBuckets returns -1, 0, or +1 if x < y, x == y, or x > y.
+	// Otherwise, buckets are sorted lexically.
+	compareBuckets func(x, y string) int
+
+	// If fraction is set, the value of each bucket is the fraction of the
+	// program's reporters that reported it, rather than their number. This is
+	// used for counters created by counter.NewBool.
+	fraction bool
+}
+
+// reportersChart is the name of the chart of the number of reporters of a
+// program in each week.
+const reportersChart = "Reporters"
+
+// reporters builds a chart of the approximate number of distinct uploaders
+// that reported the program in each week, the number of distinct X values
+// of its reports, so that adoption trends are visible. Its type is "trend":
+// it has a datum for each week, keyed by the chart name. It returns nil if
+// the program has no reports in d.
+func (d data) reporters(program programName) *Chart {
+	chart := &Chart{
+		ID:   fmt.Sprintf("charts:%s:%s", program, reportersChart),
+		Name: reportersChart,
+		Type: "trend",
+	}
+	for wk := range d {
+		ids := make(map[reportID]bool)
+		d.addReporters(ids, wk, program)
+		if len(ids) > 0 {
+			chart.Data = append(chart.Data, &Datum{
+				Week:  string(wk),
+				Key:   reportersChart,
+				Value: float64(len(ids)),
+			})
+		}
+	}
+	if len(chart.Data) == 0 {
+		return nil
+	}
+	sort.Slice(chart.Data, func(i, j int) bool {
+		return chart.Data[i].Week < chart.Data[j].Week
+	})
+	return chart
+}
+
+// addReporters adds the IDs of the reports of the program in the week to
+// ids. Every program report writes the GOOS counter, so its report IDs are
+// the program's reporters.
+func (d data) addReporters(ids map[reportID]bool, wk weekName, program programName) {
+	for _, bucket := range d[wk][program][goosCounter] {
+		for id := range bucket {
+			ids[id] = true
+		}
+	}
+}
+
+// partition builds a chart for the program and the counter. It can return nil
+// if there is no data for the counter in d.
+func (d data) partition(program programName, chartName graphName, buckets []bucketName, opts partitionOptions) *Chart {
+	chart := &Chart{
+		ID:   fmt.Sprintf("charts:%s:%s", program, chartName),
+		Name: string(chartName),
+		Type: "partition",
+	}
+	pk := programName(program)
+
+	var (
+		merged = make(map[bucketName]map[reportID]struct{}) // normalized bucket name -> merged report IDs
+		empty  = true                                       // keep track of empty reports, so they can be skipped
+		end    weekName                                     // latest week observed
+	)
+	for wk := range d {
+		if wk >= end {
+			end = wk
+		}
+		// We group versions into major minor buckets, we must skip
+		// major minor versions we've already added to the dataset.
+		seen := make(map[bucketName]bool)
+		for _, bucket := range buckets {
+			if seen[bucket] {
+				continue
+			}
+			seen[bucket] = true
+			key := bucket
+			if opts.normalizeBucket != nil {
+				key = opts.normalizeBucket(bucket)
+			}
+			if _, ok := merged[key]; !ok {
+				merged[key] = make(map[reportID]struct{})
+			}
+			for id := range d[wk][pk][chartName][bucket] {
+				empty = false
+				merged[key][id] = struct{}{}
+			}
+		}
+	}
+
+	if empty {
+		return nil
+	}
+
+	reporters := 0
+	if opts.fraction {
+		chart.Type = "boolean"
+		ids := make(map[reportID]bool)
+		for wk := range d {
+			d.addReporters(ids, wk, pk)
+		}
+		reporters = len(ids)
+	}
+
+	// datum.Week always points to the end date
+	for bucket, v := range merged {
+		if len(v) > 0 || !opts.ignoreEmptyBuckets {
+			value := float64(len(v))
+			if reporters > 0 {
+				value /= float64(reporters)
+			}
+			d := &Datum{
+				Week:  string(end),
+				Key:   string(bucket),
+				Value: value,
+			}
+			chart.Data = append(chart.Data, d)
+		}
+	}
+	compareBuckets := compareLexically
+	if opts.compareBuckets != nil {
+		compareBuckets = opts.compareBuckets
+	}
+	// Sort the data based on bucket name to ensure deterministic output.
+	sort.Slice(chart.Data, func(i, j int) bool {
+		return compareBuckets(chart.Data[i].Key, chart.Data[j].Key) < 0
+	})
+
+	return chart
+}
+
+// weekName is the date of the report week in the format "YYYY-MM-DD".
+type weekName string
+
+// programName is the package path of the program, as used in
+// telemetry.ProgramReport and chartconfig.Program.
+// e.g. golang.org/x/tools/gopls, cmd/go.
+type programName string
+
+// graphName is the graph name.
+// A graph plots distribution or timeseries of related counters.
+//
+// TODO(rfindley): rename to chartName.
+type graphName string
+
+// counterName is the counter name. counterName is graphName:bucketName.
+type counterName string
+
+// bucketName is the bucket name.
+type bucketName string
+
+// reportID is the upload report ID.
+// The current implementation uses telemetry.Report.X,
+// a random number, computed by the uploader when creating a Report object.
+// See x/telemetry/internal/upload.(*uploader).createReport.
+type reportID float64
+
+type data map[weekName]map[programName]map[graphName]map[bucketName]map[reportID]int64
+
+// Names of special counters.
+// Unlike other counters, these are constructed from the metadata in the report.
+const (
+	versionCounter   = "Version"
+	goosCounter      = "GOOS"
+	goarchCounter    = "GOARCH"
+	goversionCounter = "GoVersion"
+
+	// platformCounter combines GOOS and GOARCH, as in linux/amd64, so that
+	// platforms are charted, not only their operating systems and
+	// architectures.
+	platformCounter = "GOOS/GOARCH"
+)
+
+// group groups the report data by week, program, prefix, counter, and x value
+// summing together counter values for each program report in a report.
+func group(reports []telemetry.Report) data {
+	result := make(data)
+	for i := range reports {
+		result.add(&reports[i])
+	}
+	return result
+}
+
+// add adds the counts of a report to d.
+func (d data) add(r *telemetry.Report) {
+	var (
+		week = weekName(r.Week)
+		// x is a random number sent with each upload report.
+		// Since there is no identifier for the uploader, we use x as the uploader ID
+		// to approximate the number of unique uploader.
+		//
+		// Multiple uploads with the same x will overwrite each other, so we set the
+		// value, rather than add it to the existing value.
+		id = reportID(r.X)
+	)
+	for _, p := range r.Programs {
+		program := programName(p.Program)
+
+		d.writeCount(week, program, versionCounter, bucketName(p.Version), id, 1)
+		d.writeCount(week, program, goosCounter, bucketName(p.GOOS), id, 1)
+		d.writeCount(week, program, goarchCounter, bucketName(p.GOARCH), id, 1)
+		d.writeCount(week, program, platformCounter, platformBucket(p.GOOS, p.GOARCH), id, 1)
+		d.writeCount(week, program, goversionCounter, bucketName(p.GoVersion), id, 1)
+		for c, value := range p.Counters {
+			chart, bucket := splitCounterName(c)
+			d.writeCount(week, program, chart, bucket, id, value)
+		}
+		// Sum the occurrences of each attribute value over the values of
+		// the other attributes.
+		events := make(map[graphName]map[bucketName]int64)
+		for e, value := range p.Events {
+			name, attrs, values, ok := counter.DecodeEvent(e)
+			if !ok {
+				continue
+			}
+			for i, a := range attrs {
+				chart := eventChartName(name, a)
+				if events[chart] == nil {
+					events[chart] = make(map[bucketName]int64)
+				}
+				events[chart][bucketName(values[i])] += value
+			}
+		}
+		for chart, buckets := range events {
+			for bucket, value := range buckets {
+				d.writeCount(week, program, chart, bucket, id, value)
+			}
+		}
+	}
+}
+
+// writeCount writes the counter values to the result. When a report contains
+// multiple program reports for the same program, the value of the counters
+// in that report are summed together.
+func (d data) writeCount(week weekName, program programName, chart graphName, bucket bucketName, id reportID, value int64) {
+	if _, ok := d[week]; !ok {
+		d[week] = make(map[programName]map[graphName]map[bucketName]map[reportID]int64)
+	}
+	if _, ok := d[week][program]; !ok {
+		d[week][program] = make(map[graphName]map[bucketName]map[reportID]int64)
+	}
+	if _, ok := d[week][program][chart]; !ok {
+		d[week][program][chart] = make(map[bucketName]map[reportID]int64)
+	}
+	if _, ok := d[week][program][chart][bucket]; !ok {
+		d[week][program][chart][bucket] = make(map[reportID]int64)
+	}
+	d[week][program][chart][bucket][id] = value
+}
+
+// eventChartName returns the name of the chart of the given attribute of an
+// event counter.
+func eventChartName(event, attr string) graphName {
+	return graphName(event + "{" + attr + "}")
+}
+
+// splitCounterName gets splits the prefix and bucket splitCounterName of a counter name
+// or a bucket name. For an input with no bucket part prefix and bucket
+// are the same.
+func splitCounterName(name string) (graphName, bucketName) {
+	prefix, bucket, found := strings.Cut(name, ":")
+	if !found {
+		bucket = prefix
+	}
+	return graphName(prefix), bucketName(bucket)
+}
+
+// goMajorMinor gets the go<Maj>,<Min> version for a given go version.
+// For example, go1.20.1 -> go1.20.
+// TODO(hyangah): replace with go/version.Lang (available from go1.22)
+// after our builders stop running go1.21.
+func goMajorMinor(v string) string {
+	v = v[2:]
+	maj, x, ok := cutInt(v)
+	if !ok {
+		return ""
+	}
+	x = x[1:]
+	min, _, ok := cutInt(x)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("go%s.%s", maj, min)
+}
+
+// cutInt scans the leading decimal number at the start of x to an integer
+// and returns that value and the rest of the string.
+func cutInt(x string) (n, rest string, ok bool) {
+	i := 0
+	for i < len(x) && '0' <= x[i] && x[i] <= '9' {
+		i++
+	}
+	if i == 0 || x[0] == '0' && i != 1 {
+		return "", "", false
+	}
+	return x[:i], x[i:], true
+}
@@ -0,0 +1,974 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/mod/semver"
+	"golang.org/x/telemetry/godev/internal/storage"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+var exampleReports = []telemetry.Report{
+	{
+		Week:     "2999-01-01",
+		LastWeek: "2998-01-01",
+		X:        0.1,
+		Programs: []*telemetry.ProgramReport{
+			{
+				Program:   "cmd/go",
+				Version:   "go1.2.3",
+				GoVersion: "go1.2.3",
+				GOOS:      "darwin",
+				GOARCH:    "arm64",
+				Counters: map[string]int64{
+					"main": 1,
+				},
+			},
+			{
+				Program:   "example.com/mod/pkg",
+				Version:   "v2.3.4",
+				GoVersion: "go1.2.3",
+				GOOS:      "darwin",
+				GOARCH:    "arm64",
+				Counters: map[string]int64{
+					"main":   1,
+					"flag:a": 2,
+					"flag:b": 3,
+				},
+				Stacks: map[string]int64{
+					"panic": 4,
+				},
+			},
+			{
+				Program:   "example.com/mod/pkg",
+				Version:   "v2.3.4-pre.1",
+				GoVersion: "go1.2.3",
+				GOOS:      "darwin",
+				GOARCH:    "arm64",
+				Counters: map[string]int64{
+					"flag:b": 3,
+				},
+				Stacks: map[string]int64{
+					"panic": 2,
+				},
+			},
+		},
+		Config: "v0.0.1",
+	},
+	{
+		Week:     "2999-01-01",
+		LastWeek: "2998-01-01",
+		X:        0.2,
+		Programs: []*telemetry.ProgramReport{
+			{
+				Program:   "example.com/mod/pkg",
+				Version:   "v1.2.3",
+				GoVersion: "go1.2.3",
+				GOOS:      "darwin",
+				GOARCH:    "arm64",
+				Counters: map[string]int64{
+					"main":   1,
+					"flag:a": 2,
+					"flag:b": 3,
+				},
+				Stacks: map[string]int64{
+					"panic": 4,
+				},
+			},
+			{
+				Program:   "example.com/mod/pkg",
+				Version:   "v2.3.4",
+				GoVersion: "go1.19.0",
+				GOOS:      "darwin",
+				GOARCH:    "arm64",
+				Counters: map[string]int64{
+					"main":   1,
+					"flag:a": 2,
+					"flag:b": 3,
+				},
+				Stacks: map[string]int64{
+					"panic": 4,
+				},
+			},
+		},
+		Config: "v0.0.1",
+	},
+	{
+		Week:     "2999-01-01",
+		LastWeek: "2998-01-01",
+		X:        0.3,
+		Programs: []*telemetry.ProgramReport{
+			{
+				Program:   "example.com/mod/pkg",
+				Version:   "v1.2.3",
+				GoVersion: "go1.2.3",
+				GOOS:      "linux",
+				GOARCH:    "amd64",
+				Counters: map[string]int64{
+					"main":   4,
+					"flag:a": 5,
+					"flag:b": 6,
+					"flag:c": 1,
+				},
+				Stacks: map[string]int64{
+					"panic": 7,
+				},
+			},
+		},
+		Config: "v0.0.1",
+	},
+}
+
+func TestGroup(t *testing.T) {
+	type args struct {
+		reports []telemetry.Report
+	}
+	tests := []struct {
+		name string
+		args args
+		want data
+	}{
+		{
+			name: "single report",
+			args: args{
+				[]telemetry.Report{
+					{
+						Week:     "2999-01-01",
+						LastWeek: "2998-01-01",
+						X:        0.123456789,
+						Programs: []*telemetry.ProgramReport{
+							{
+								Program:   "example.com/mod/pkg",
+								Version:   "v1.2.3",
+								GoVersion: "go1.2.3",
+								GOOS:      "darwin",
+								GOARCH:    "arm64",
+								Counters: map[string]int64{
+									"main":   1,
+									"flag:a": 2,
+									"flag:b": 3,
+								},
+								Stacks: map[string]int64{
+									"panic": 4,
+								},
+								Events: map[string]int64{
+									"switch{from=go1.2,to=go1.3}": 5,
+									"switch{from=go1.1,to=go1.3}": 6,
+								},
+							},
+						},
+						Config: "v0.0.1",
+					},
+				},
+			},
+			want: data{
+				weekName("2999-01-01"): {
+					programName("example.com/mod/pkg"): {
+						graphName("Version"): {
+							bucketName("v1.2.3"): {
+								reportID(0.1234567890): 1,
+							},
+						},
+						graphName("GOOS"): {
+							bucketName("darwin"): {
+								reportID(0.1234567890): 1,
+							},
+						},
+						graphName("GOARCH"): {
+							bucketName("arm64"): {
+								reportID(0.1234567890): 1,
+							},
+						},
+						graphName("GOOS/GOARCH"): {
+							bucketName("darwin/arm64"): {
+								reportID(0.1234567890): 1,
+							},
+						},
+						graphName("GoVersion"): {
+							bucketName("go1.2.3"): {
+								reportID(0.1234567890): 1,
+							},
+						},
+						graphName("main"): {
+							bucketName("main"): {
+								reportID(0.1234567890): 1,
+							},
+						},
+						graphName("flag"): {
+							bucketName("a"): {
+								reportID(0.1234567890): 2,
+							},
+							bucketName("b"): {
+								reportID(0.1234567890): 3,
+							},
+						},
+						graphName("switch{from}"): {
+							bucketName("go1.1"): {
+								reportID(0.1234567890): 6,
+							},
+							bucketName("go1.2"): {
+								reportID(0.1234567890): 5,
+							},
+						},
+						graphName("switch{to}"): {
+							bucketName("go1.3"): {
+								reportID(0.1234567890): 11,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := group(tt.args.reports)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("nest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPartition(t *testing.T) {
+	normalVersion := func(b bucketName) bucketName {
+		return bucketName(semver.MajorMinor(string(b)))
+	}
+	normalGoVersion := func(b bucketName) bucketName {
+		return bucketName(goMajorMinor(string(b)))
+	}
+	exampleData := group(exampleReports)
+	type args struct {
+		program programName
+		name    graphName
+		buckets []bucketName
+	}
+	tests := []struct {
+		name string
+		data data
+		args args
+		opts partitionOptions
+		want *Chart
+	}{
+		{
+			name: "major.minor.patch version counter",
+			data: exampleData,
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "Version",
+				buckets: []bucketName{"v1.2.3", "v2.3.4", "v4.5.6"},
+			},
+			opts: partitionOptions{normalizeBucket: normalVersion},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:Version",
+				Name: "Version",
+				Type: "partition",
+				Data: []*Datum{
+					{Week: "2999-01-01", Key: "v1.2", Value: 2},
+					{Week: "2999-01-01", Key: "v2.3", Value: 2},
+					{Week: "2999-01-01", Key: "v4.5", Value: 0},
+				},
+			},
+		},
+		{
+			name: "all nonempty versions",
+			data: exampleData,
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "Version",
+				buckets: []bucketName{"v1.2.3", "v2.3.4", "v4.5.6"},
+			},
+			opts: partitionOptions{ignoreEmptyBuckets: true},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:Version",
+				Name: "Version",
+				Type: "partition",
+				Data: []*Datum{
+					{
+						Week:  "2999-01-01",
+						Key:   "v1.2.3",
+						Value: 2,
+					},
+					{
+						Week:  "2999-01-01",
+						Key:   "v2.3.4",
+						Value: 2,
+					},
+				},
+			},
+		},
+		{
+			name: "major.minor version counter should have same result as major.minor.patch",
+			data: exampleData,
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "Version",
+				buckets: []bucketName{"v1.2.3", "v2.3.4"},
+			},
+			opts: partitionOptions{normalizeBucket: normalVersion},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:Version",
+				Name: "Version",
+				Type: "partition",
+				Data: []*Datum{
+					{
+						Week:  "2999-01-01",
+						Key:   "v1.2",
+						Value: 2,
+					},
+					{
+						Week:  "2999-01-01",
+						Key:   "v2.3",
+						Value: 2,
+					},
+				},
+			},
+		},
+		{
+			name: "duplicated counter should be ignored",
+			data: exampleData,
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "Version",
+				buckets: []bucketName{"v1.2.3", "v2.3.4", "v1.2.3"},
+			},
+			opts: partitionOptions{normalizeBucket: normalVersion},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:Version",
+				Name: "Version",
+				Type: "partition",
+				Data: []*Datum{
+					{
+						Week:  "2999-01-01",
+						Key:   "v1.2",
+						Value: 2,
+					},
+					{
+						Week:  "2999-01-01",
+						Key:   "v2.3",
+						Value: 2,
+					},
+				},
+			},
+		},
+		{
+			name: "goos counter",
+			data: exampleData,
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "GOOS",
+				buckets: []bucketName{"darwin", "linux"},
+			},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:GOOS",
+				Name: "GOOS",
+				Type: "partition",
+				Data: []*Datum{
+					{
+						Week:  "2999-01-01",
+						Key:   "darwin",
+						Value: 2,
+					},
+					{
+						Week:  "2999-01-01",
+						Key:   "linux",
+						Value: 1,
+					},
+				},
+			},
+		},
+		{
+			name: "GoVersion counter",
+			data: exampleData,
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "GoVersion",
+				buckets: []bucketName{"go1.2.3", "go2.3.4"},
+			},
+			opts: partitionOptions{normalizeBucket: normalGoVersion},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:GoVersion",
+				Name: "GoVersion",
+				Type: "partition",
+				Data: []*Datum{
+					{
+						Week:  "2999-01-01",
+						Key:   "go1.2",
+						Value: 3,
+					},
+					{
+						Week:  "2999-01-01",
+						Key:   "go2.3",
+						Value: 0,
+					},
+				},
+			},
+		},
+		{
+			name: "three days, multiple versions",
+			data: data{
+				"2999-01-01": {"example.com/mod/pkg": {"Version": {
+					"v1.2.3": {0.1: 2},
+					"v2.3.4": {0.1: 3},
+				},
+				}},
+				"2999-01-04": {"example.com/mod/pkg": {"Version": {
+					"v1.2.3": {0.3: 2},
+					"v2.3.4": {0.4: 5},
+				},
+				}},
+				"2999-01-05": {"example.com/mod/pkg": {"Version": {
+					"v2.3.4": {0.5: 6},
+				}}},
+			},
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "Version",
+				buckets: []bucketName{"v1.2.3", "v2.3.4"},
+			},
+			opts: partitionOptions{normalizeBucket: normalVersion},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:Version",
+				Name: "Version",
+				Type: "partition",
+				Data: []*Datum{
+					{
+						Week:  "2999-01-05",
+						Key:   "v1.2",
+						Value: 2,
+					},
+					{
+						Week:  "2999-01-05",
+						Key:   "v2.3",
+						Value: 3,
+					},
+				},
+			},
+		},
+		{
+			name: "three days, multiple GOOS",
+			data: data{
+				"2999-01-01": {"example.com/mod/pkg": {"GOOS": {
+					"darwin": {0.1: 2, 0.2: 2, 0.3: 2},
+					"linux":  {0.1: 2, 0.2: 2},
+				},
+				}},
+				"2999-01-02": {"example.com/mod/pkg": {"GOOS": {
+					"darwin": {0.4: 2, 0.5: 2},
+					"linux":  {0.6: 5},
+				},
+				}},
+				"2999-01-03": {"example.com/mod/pkg": {"GOOS": {
+					"darwin": {0.6: 3},
+				},
+				}},
+			},
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "GOOS",
+				buckets: []bucketName{"darwin", "linux"},
+			},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:GOOS",
+				Name: "GOOS",
+				Type: "partition",
+				Data: []*Datum{
+					{
+						Week:  "2999-01-03",
+						Key:   "darwin",
+						Value: 6,
+					},
+					{
+						Week:  "2999-01-03",
+						Key:   "linux",
+						Value: 3,
+					},
+				},
+			},
+		},
+		{
+			name: "two days data, missing GOOS in first day",
+			data: data{
+				"2999-01-01": {"example.com/mod/pkg": {"Version": {
+					"v1.2": {0.1: 2},
+				},
+				}},
+				"2999-01-02": {"example.com/mod/pkg": {"GOOS": {
+					"darwin": {0.3: 2},
+					"linux":  {0.3: 2},
+				},
+				}},
+			},
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "GOOS",
+				buckets: []bucketName{"darwin", "linux"},
+			},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:GOOS",
+				Name: "GOOS",
+				Type: "partition",
+				Data: []*Datum{
+					{
+						Week:  "2999-01-02",
+						Key:   "darwin",
+						Value: 1,
+					},
+					{
+						Week:  "2999-01-02",
+						Key:   "linux",
+						Value: 1,
+					},
+				},
+			},
+		},
+		{
+			name: "three days, missing version data all days",
+			data: data{
+				"2999-01-01": {"example.com/mod/pkg": {"GOOS": {
+					"GOOS":        {0.1: 2},
+					"GOOS:darwin": {0.1: 2},
+				},
+				}},
+				"2999-01-02": {"example.com/mod/pkg": {"GOOS": {
+					"GOOS":       {0.6: 5},
+					"GOOS:linux": {0.6: 5},
+				},
+				}},
+				"2999-01-03": {"example.com/mod/pkg": {"GOOS": {
+					"GOOS":        {0.6: 3},
+					"GOOS:darwin": {0.6: 3},
+				},
+				}},
+			},
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "Version",
+				buckets: []bucketName{"v1.2.3", "v2.3.4"},
+			},
+			opts: partitionOptions{normalizeBucket: normalVersion},
+			want: nil,
+		},
+		{
+			name: "boolean counter as fraction of reporters",
+			data: exampleData,
+			args: args{
+				program: "example.com/mod/pkg",
+				name:    "flag",
+				buckets: []bucketName{"a", "b", "c"},
+			},
+			opts: partitionOptions{fraction: true},
+			want: &Chart{
+				ID:   "charts:example.com/mod/pkg:flag",
+				Name: "flag",
+				Type: "boolean",
+				Data: []*Datum{
+					{Week: "2999-01-01", Key: "a", Value: 1},
+					{Week: "2999-01-01", Key: "b", Value: 1},
+					{Week: "2999-01-01", Key: "c", Value: 1.0 / 3},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.data.partition(tc.args.program, tc.args.name, tc.args.buckets, tc.opts)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("partition() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCharts(t *testing.T) {
+	exampleData := group(exampleReports)
+	cfg := &config.Config{
+		UploadConfig: &telemetry.UploadConfig{
+			GOOS:       []string{"darwin"},
+			GOARCH:     []string{"amd64"},
+			GoVersion:  []string{"go1.2.3", "go1.19.0"},
+			SampleRate: 1,
+			Programs: []*telemetry.ProgramConfig{
+				{
+					Name:     "cmd/go",
+					Versions: []string{"go1.2.3"},
+					Counters: []telemetry.CounterConfig{{
+						Name: "main",
+					}},
+				},
+				{
+					Name:     "cmd/compiler",
+					Versions: []string{"go1.2.3"},
+					Counters: []telemetry.CounterConfig{{
+						Name: "count1",
+					}},
+				},
+				{
+					Name: "example.com/mod/pkg",
+					// Exercise semver sorting. Notably v1.2.3 has data but is not
+					// present. Versions are charted in full, not collapsed to
+					// major.minor.
+					Versions: []string{"v2.3.4", "v2.3.4-pre.1", "v0.15.0"},
+					Counters: []telemetry.CounterConfig{
+						{Name: "count2"},
+						{Name: "flag:{a,b,c}"},
+					},
+				},
+			},
+		},
+	}
+	want := &Data{
+		DateRange: [2]string{"2999-01-01", "2999-01-01"},
+		Programs: []*Program{
+			{
+				ID:   "charts:cmd/go",
+				Name: "cmd/go",
+				Charts: []*Chart{
+					{
+						ID:   "charts:cmd/go:Reporters",
+						Name: "Reporters",
+						Type: "trend",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "Reporters", Value: 1},
+						},
+					},
+					{
+						ID:   "charts:cmd/go:GOOS",
+						Name: "GOOS",
+						Type: "partition",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "darwin", Value: 1},
+						},
+					},
+					{
+						ID:   "charts:cmd/go:GoVersion",
+						Name: "GoVersion",
+						Type: "partition",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "go1.2", Value: 1},
+						},
+					},
+					{
+						ID:   "charts:cmd/go:main",
+						Name: "main",
+						Type: "partition",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "main", Value: 1},
+						},
+					},
+				},
+			},
+			{
+				ID:   "charts:cmd/compiler",
+				Name: "cmd/compiler",
+			},
+			{
+				ID:   "charts:example.com/mod/pkg",
+				Name: "example.com/mod/pkg",
+				Charts: []*Chart{
+					{
+						ID:   "charts:example.com/mod/pkg:Reporters",
+						Name: "Reporters",
+						Type: "trend",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "Reporters", Value: 3},
+						},
+					},
+					{
+						ID:   "charts:example.com/mod/pkg:Version",
+						Name: "Version",
+						Type: "partition",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "v2.3.4-pre.1", Value: 1},
+							{Week: "2999-01-01", Key: "v2.3.4", Value: 2},
+						},
+					},
+					{
+						ID:   "charts:example.com/mod/pkg:GOOS",
+						Name: "GOOS",
+						Type: "partition",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "darwin", Value: 2},
+						},
+					},
+					{
+						ID:   "charts:example.com/mod/pkg:GOARCH",
+						Name: "GOARCH",
+						Type: "partition",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "amd64", Value: 1},
+						},
+					},
+					{
+						ID:   "charts:example.com/mod/pkg:GoVersion",
+						Name: "GoVersion",
+						Type: "partition",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "go1.2", Value: 3},
+							{Week: "2999-01-01", Key: "go1.19", Value: 1},
+						},
+					},
+					{
+						ID:   "charts:example.com/mod/pkg:flag",
+						Name: "flag",
+						Type: "partition",
+						Data: []*Datum{
+							{Week: "2999-01-01", Key: "a", Value: 3},
+							{Week: "2999-01-01", Key: "b", Value: 3},
+							{Week: "2999-01-01", Key: "c", Value: 1},
+						},
+					},
+				},
+			},
+		},
+		NumReports: 1,
+	}
+	got := charts(cfg, nil, "2999-01-01", "2999-01-01", exampleData, nil, 1)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("charts = %+v\n, (-want +got): %v", got, diff)
+	}
+}
+
+func TestPlatformChart(t *testing.T) {
+	report := func(x float64, goos, goarch string) telemetry.Report {
+		return telemetry.Report{
+			Week: "2999-01-01",
+			X:    x,
+			Programs: []*telemetry.ProgramReport{{
+				Program: "example.com/mod/pkg",
+				Version: "v1.0.0",
+				GOOS:    goos,
+				GOARCH:  goarch,
+			}},
+		}
+	}
+	d := group([]telemetry.Report{
+		report(0.1, "linux", "amd64"),
+		report(0.2, "linux", "amd64"),
+		report(0.3, "darwin", "arm64"),
+		report(0.4, "linux", "arm64"),
+	})
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:     []string{"darwin", "linux", "windows"},
+		GOARCH:   []string{"amd64", "arm64"},
+		Programs: []*telemetry.ProgramConfig{{Name: "example.com/mod/pkg"}},
+	})
+	got := charts(cfg, nil, "2999-01-01", "2999-01-01", d, nil, 4)
+	want := &Chart{
+		ID:   "charts:example.com/mod/pkg:GOOS/GOARCH",
+		Name: "GOOS/GOARCH",
+		Type: "partition",
+		Data: []*Datum{
+			{Week: "2999-01-01", Key: "darwin/arm64", Value: 1},
+			{Week: "2999-01-01", Key: "linux/amd64", Value: 2},
+			{Week: "2999-01-01", Key: "linux/arm64", Value: 1},
+		},
+	}
+	for _, c := range got.Programs[0].Charts {
+		if c.ID == want.ID {
+			if diff := cmp.Diff(want, c); diff != "" {
+				t.Errorf("GOOS/GOARCH chart mismatch (-want +got):\n%s", diff)
+			}
+			return
+		}
+	}
+	t.Errorf("no GOOS/GOARCH chart in %v", got.Programs[0].Charts)
+}
+
+func TestReportersChart(t *testing.T) {
+	report := func(week string, x float64) telemetry.Report {
+		return telemetry.Report{
+			Week: week,
+			X:    x,
+			Programs: []*telemetry.ProgramReport{{
+				Program: "example.com/mod/pkg",
+				Version: "v1.0.0",
+				GOOS:    "linux",
+			}, {
+				Program: "example.com/mod/pkg",
+				Version: "v1.0.0",
+				GOOS:    "darwin",
+			}},
+		}
+	}
+	// Reporters are counted once per week, however many program reports
+	// they uploaded.
+	d := group([]telemetry.Report{
+		report("2999-01-08", 0.1),
+		report("2999-01-01", 0.1),
+		report("2999-01-01", 0.2),
+		report("2999-01-01", 0.2),
+	})
+	got := d.reporters("example.com/mod/pkg")
+	want := &Chart{
+		ID:   "charts:example.com/mod/pkg:Reporters",
+		Name: "Reporters",
+		Type: "trend",
+		Data: []*Datum{
+			{Week: "2999-01-01", Key: "Reporters", Value: 2},
+			{Week: "2999-01-08", Key: "Reporters", Value: 1},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("reporters chart mismatch (-want +got):\n%s", diff)
+	}
+	if got := d.reporters("example.com/mod/other"); got != nil {
+		t.Errorf("reporters chart of an unreported program = %v, want nil", got)
+	}
+}
+
+func TestChartsProgramOrder(t *testing.T) {
+	// Programs are charted concurrently, but listed in the order of the
+	// config.
+	uc := &telemetry.UploadConfig{GOOS: []string{"darwin", "linux"}}
+	var want []string
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("example.com/mod/pkg%02d", 49-i)
+		uc.Programs = append(uc.Programs, &telemetry.ProgramConfig{Name: name})
+		want = append(want, name)
+	}
+	got := charts(config.NewConfig(uc), nil, "2999-01-01", "2999-01-01", group(exampleReports), nil, len(exampleReports))
+	var names []string
+	for _, p := range got.Programs {
+		names = append(names, p.Name)
+	}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("charted programs mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteCount(t *testing.T) {
+	type keyValue struct {
+		week    weekName
+		program programName
+		chart   graphName
+		bucket  bucketName
+		x       reportID
+		value   int64
+	}
+	testcases := []struct {
+		name   string
+		inputs []keyValue
+		want   []keyValue
+	}{
+		{
+			name: "program version counter should have value",
+			inputs: []keyValue{
+				{"2987-07-01", "golang.org/x/tools/gopls", "Version", "v0.15.3", 0.00009, 1},
+			},
+			want: []keyValue{
+				{"2987-07-01", "golang.org/x/tools/gopls", "Version", "v0.15.3", 0.00009, 1},
+			},
+		},
+		{
+			name: "only one count with same prefix and counter",
+			inputs: []keyValue{
+				{"2987-06-30", "cmd/go", "go/invocations", "go/invocations", 0.86995, 84},
+			},
+			want: []keyValue{
+				{"2987-06-30", "cmd/go", "go/invocations", "go/invocations", 0.86995, 84},
+			},
+		},
+		{
+			name: "overwrite values when calling multiple times",
+			inputs: []keyValue{
+				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 1},
+				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 2},
+				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 3},
+			},
+			want: []keyValue{
+				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 3},
+			},
+		},
+		{
+			name: "multiple counters",
+			inputs: []keyValue{
+				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 2},
+				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "linux", 0.86018, 4},
+			},
+			want: []keyValue{
+				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "windows", 0.86018, 2},
+				{"2987-06-30", "golang.org/x/tools/gopls", "GOOS", "linux", 0.86018, 4},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := make(data)
+			for _, input := range tc.inputs {
+				d.writeCount(input.week, input.program, input.chart, input.bucket, input.x, input.value)
+			}
+
+			for _, want := range tc.want {
+				got := d[want.week][want.program][want.chart][want.bucket][want.x]
+				if want.value != got {
+					t.Errorf("d[%q][%q][%q][%q][%v] = %v, want %v", want.week, want.program, want.chart, want.bucket, want.x, got, want.value)
+				}
+			}
+		})
+	}
+}
+
+func TestScanMerged(t *testing.T) {
+	ctx := context.Background()
+	merge, err := storage.NewFSBucket(ctx, t.TempDir(), "merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Split the example reports over two days.
+	start := time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, reports := range [][]telemetry.Report{exampleReports[:1], exampleReports[1:]} {
+		w, err := merge.Object(start.AddDate(0, 0, i).Format(telemetry.DateOnly) + ".json").NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc := json.NewEncoder(w)
+		for _, r := range reports {
+			if err := enc.Encode(r); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{
+		UploadConfig: &telemetry.UploadConfig{
+			GOOS:      []string{"darwin", "linux"},
+			GOARCH:    []string{"amd64", "arm64"},
+			GoVersion: []string{"go1.2.3"},
+			Programs: []*telemetry.ProgramConfig{
+				{Name: "cmd/go", Versions: []string{"go1.2.3"}, Counters: []telemetry.CounterConfig{{Name: "main"}}},
+			},
+		},
+	}
+	end := start.AddDate(0, 0, 1)
+
+	// Charts built from the streamed reports are those computed from all
+	// the reports at once.
+	b := NewBuilder(cfg, nil)
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		if err := ScanMerged(ctx, merge, date, b.Add); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := b.Charts(start, end)
+	want := Compute(cfg, nil, start, end, exampleReports)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("streamed charts mismatch (-want +got):\n%s", diff)
+	}
+	if got.NumReports != len(exampleReports) {
+		t.Errorf("NumReports = %d, want %d", got.NumReports, len(exampleReports))
+	}
+}
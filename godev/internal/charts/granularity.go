@@ -0,0 +1,124 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"go/version"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/telemetry/internal/chartconfig"
+)
+
+// Granularities of the versions in charts of version buckets, declared by
+// the versions fields of the chart config.
+const (
+	fullVersions  = "full"  // v1.2.3
+	minorVersions = "minor" // v1.2
+	majorVersions = "major" // v1
+)
+
+// Granularities holds the granularities at which charts of version buckets
+// are charted, declared by the versions fields of the chart config, keyed
+// by program and chart name. Each granularity produces a variant of the
+// chart; the first is the chart itself.
+type Granularities map[programName]map[graphName][]string
+
+// NewGranularities returns the Granularities declared by the given chart
+// configs.
+func NewGranularities(ccfgs []chartconfig.ChartConfig) Granularities {
+	g := make(Granularities)
+	for _, c := range ccfgs {
+		if len(c.Versions) == 0 {
+			continue
+		}
+		program := programName(c.Program)
+		if g[program] == nil {
+			g[program] = make(map[graphName][]string)
+		}
+		chart, _, _ := strings.Cut(c.Counter, ":")
+		g[program][graphName(chart)] = c.Versions
+	}
+	return g
+}
+
+// variant returns the ID and name of the variant of a chart with the given
+// granularity. The first variant is the chart itself; the others are
+// distinguished by their granularity.
+func variant(id, name string, i int, granularity string) (string, string) {
+	if i == 0 {
+		return id, name
+	}
+	label := map[string]string{fullVersions: "full", minorVersions: "major.minor", majorVersions: "major"}[granularity]
+	return id + ":" + granularity, name + " (" + label + ")"
+}
+
+// normalizeVersion returns a function that maps version buckets to the
+// given granularity. Versions may be Go versions (go1.2.3), semantic
+// versions (v1.2.3), or semantic versions without their v (1.2.3). Other
+// buckets, such as "other", are left unchanged.
+func normalizeVersion(granularity string) func(bucketName) bucketName {
+	if granularity == fullVersions {
+		return nil
+	}
+	return func(b bucketName) bucketName {
+		v := string(b)
+		if version.IsValid(v) {
+			lang := version.Lang(v)
+			if granularity == majorVersions {
+				lang, _, _ = strings.Cut(lang, ".")
+			}
+			return bucketName(lang)
+		}
+		trimmed := !strings.HasPrefix(v, "v")
+		if trimmed {
+			v = "v" + v
+		}
+		if !semver.IsValid(v) {
+			return b
+		}
+		if granularity == majorVersions {
+			v = semver.Major(v)
+		} else {
+			v = semver.MajorMinor(v)
+		}
+		if trimmed {
+			v = v[len("v"):]
+		}
+		return bucketName(v)
+	}
+}
+
+// compareVersions compares version buckets in the forms accepted by
+// normalizeVersion, which are ordered before other buckets.
+func compareVersions(x, y string) int {
+	vx, vy := comparableVersion(x), comparableVersion(y)
+	switch {
+	case vx != "" && vy != "":
+		if c := semver.Compare(vx, vy); c != 0 {
+			return c
+		}
+	case vx != "":
+		return -1
+	case vy != "":
+		return 1
+	}
+	return compareLexically(x, y)
+}
+
+// comparableVersion returns the semantic version that orders like v, or ""
+// if v is not a version. Go versions are ordered by their language
+// version, then lexically.
+func comparableVersion(v string) string {
+	if version.IsValid(v) {
+		v = "v" + strings.TrimPrefix(version.Lang(v), "go")
+	} else if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		return ""
+	}
+	return v
+}
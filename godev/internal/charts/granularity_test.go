@@ -0,0 +1,109 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/internal/chartconfig"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestNormalizeVersion(t *testing.T) {
+	for _, test := range []struct {
+		in           bucketName
+		minor, major bucketName
+	}{
+		{"v1.2.3", "v1.2", "v1"},
+		{"v1.2.3-pre.1", "v1.2", "v1"},
+		{"1.21.3", "1.21", "1"},
+		{"1.21", "1.21", "1"},
+		{"go1.21.3", "go1.21", "go1"},
+		{"go1.22rc1", "go1.22", "go1"},
+		{"other", "other", "other"},
+	} {
+		if got := normalizeVersion(minorVersions)(test.in); got != test.minor {
+			t.Errorf("minor version of %s = %s, want %s", test.in, got, test.minor)
+		}
+		if got := normalizeVersion(majorVersions)(test.in); got != test.major {
+			t.Errorf("major version of %s = %s, want %s", test.in, got, test.major)
+		}
+	}
+	if normalizeVersion(fullVersions) != nil {
+		t.Errorf("full versions are normalized")
+	}
+}
+
+func TestGranularities(t *testing.T) {
+	ccfgs, err := chartconfig.Parse([]byte(`
+counter: pkg/goversion:{1.9.1,1.21.0,1.21.3,1.22.1,other}
+program: example.com/mod/pkg
+type: partition
+versions: minor
+versions: full
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		Programs: []*telemetry.ProgramConfig{{
+			Name:     "example.com/mod/pkg",
+			Counters: []telemetry.CounterConfig{{Name: ccfgs[0].Counter}},
+		}},
+	})
+	report := func(x float64, counter string) telemetry.Report {
+		return telemetry.Report{
+			Week: "2999-01-01",
+			X:    x,
+			Programs: []*telemetry.ProgramReport{{
+				Program:  "example.com/mod/pkg",
+				Version:  "v1.0.0",
+				GOOS:     "linux",
+				Counters: map[string]int64{counter: 1},
+			}},
+		}
+	}
+	start := time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := Compute(cfg, NewRules(ccfgs), start, start, []telemetry.Report{
+		report(0.1, "pkg/goversion:1.21.0"),
+		report(0.2, "pkg/goversion:1.21.3"),
+		report(0.3, "pkg/goversion:1.22.1"),
+		report(0.4, "pkg/goversion:1.9.1"),
+		report(0.5, "pkg/goversion:other"),
+	})
+	got := make(map[string]*Chart)
+	for _, c := range data.Programs[0].Charts {
+		got[c.ID] = c
+	}
+	for _, want := range []*Chart{{
+		ID:   "charts:example.com/mod/pkg:pkg/goversion",
+		Name: "pkg/goversion",
+		Type: "partition",
+		Data: []*Datum{
+			{Week: "2999-01-01", Key: "1.9", Value: 1},
+			{Week: "2999-01-01", Key: "1.21", Value: 2},
+			{Week: "2999-01-01", Key: "1.22", Value: 1},
+			{Week: "2999-01-01", Key: "other", Value: 1},
+		},
+	}, {
+		ID:   "charts:example.com/mod/pkg:pkg/goversion:full",
+		Name: "pkg/goversion (full)",
+		Type: "partition",
+		Data: []*Datum{
+			{Week: "2999-01-01", Key: "1.9.1", Value: 1},
+			{Week: "2999-01-01", Key: "1.21.0", Value: 1},
+			{Week: "2999-01-01", Key: "1.21.3", Value: 1},
+			{Week: "2999-01-01", Key: "1.22.1", Value: 1},
+			{Week: "2999-01-01", Key: "other", Value: 1},
+		},
+	}} {
+		if diff := cmp.Diff(want, got[want.ID]); diff != "" {
+			t.Errorf("chart %s mismatch (-want +got):\n%s", want.ID, diff)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/telemetry/internal/chartconfig"
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/counter"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// Retirements holds the counters retired by the chart config, in records of
+// type retired, keyed by program. The counts of retired counters are
+// redacted from reports, even from those uploaded before the retirement.
+type Retirements map[programName]map[string]bool
+
+// NewRetirements returns the Retirements declared by the given chart
+// configs.
+func NewRetirements(ccfgs []chartconfig.ChartConfig) Retirements {
+	rt := make(Retirements)
+	for _, c := range ccfgs {
+		if c.Type != "retired" {
+			continue
+		}
+		program := programName(c.Program)
+		if rt[program] == nil {
+			rt[program] = make(map[string]bool)
+		}
+		for _, name := range tconfig.Expand(c.Counter) {
+			rt[program][name] = true
+		}
+	}
+	return rt
+}
+
+// Redact removes the counts of retired counters from the report: counters
+// with a retired name, and the stacks and events of retired stack and event
+// counters. It returns the sorted names of the counters that it removed.
+func (rt Retirements) Redact(report *telemetry.Report) []string {
+	if len(rt) == 0 {
+		return nil
+	}
+	redacted := make(map[string]bool)
+	for _, p := range report.Programs {
+		retired := rt[programName(p.Program)]
+		if retired == nil {
+			continue
+		}
+		for c := range p.Counters {
+			if retired[c] {
+				delete(p.Counters, c)
+				redacted[c] = true
+			}
+		}
+		for c := range p.Stacks {
+			prefix, _, _ := strings.Cut(c, "\n")
+			if retired[prefix] {
+				delete(p.Stacks, c)
+				redacted[prefix] = true
+			}
+		}
+		for e := range p.Events {
+			name, _, _, ok := counter.DecodeEvent(e)
+			if ok && retired[name] {
+				delete(p.Events, e)
+				redacted[name] = true
+			}
+		}
+	}
+	if len(redacted) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(redacted))
+	for name := range redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
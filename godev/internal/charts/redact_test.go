@@ -0,0 +1,82 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/internal/chartconfig"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestRedact(t *testing.T) {
+	ccfgs, err := chartconfig.Parse([]byte(`
+counter: pkg/editor:{vim,emacs}
+program: example.com/mod/pkg
+type: partition
+---
+counter: pkg/path:{home,tmp}
+program: example.com/mod/pkg
+type: retired
+---
+counter: pkg/secret
+program: example.com/mod/pkg
+type: retired
+---
+counter: pkg/open
+program: example.com/mod/pkg
+type: retired
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := NewRetirements(ccfgs)
+
+	report := func() *telemetry.Report {
+		return &telemetry.Report{
+			Week: "2999-01-01",
+			X:    0.1,
+			Programs: []*telemetry.ProgramReport{{
+				Program:  "example.com/mod/pkg",
+				Version:  "v1.0.0",
+				Counters: map[string]int64{"pkg/editor:vim": 1, "pkg/path:home": 2, "pkg/path:other": 3},
+				Stacks:   map[string]int64{"pkg/secret\nmain.main": 4, "pkg/bug\nmain.main": 5},
+				Events:   map[string]int64{"pkg/open{kind=file}": 6},
+			}, {
+				Program:  "example.com/mod/other",
+				Version:  "v1.0.0",
+				Counters: map[string]int64{"pkg/path:home": 7},
+			}},
+		}
+	}
+	r := report()
+	got := rt.Redact(r)
+	if want := []string{"pkg/open", "pkg/path:home", "pkg/secret"}; !cmp.Equal(got, want) {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+	want := report()
+	want.Programs[0].Counters = map[string]int64{"pkg/editor:vim": 1, "pkg/path:other": 3}
+	want.Programs[0].Stacks = map[string]int64{"pkg/bug\nmain.main": 5}
+	want.Programs[0].Events = map[string]int64{}
+	if diff := cmp.Diff(want, r); diff != "" {
+		t.Errorf("redacted report mismatch (-want +got):\n%s", diff)
+	}
+	if got := rt.Redact(r); got != nil {
+		t.Errorf("Redact() of a redacted report = %q, want nil", got)
+	}
+
+	cfg := config.NewConfig(&telemetry.UploadConfig{})
+	b := NewBuilder(cfg, &Rules{Retirements: rt})
+	b.Add(report())
+	b.Add(report())
+	if diff := cmp.Diff(map[string]int{"pkg/open": 2, "pkg/path:home": 2, "pkg/secret": 2}, b.Redacted()); diff != "" {
+		t.Errorf("Builder.Redacted() mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := b.data["2999-01-01"]["example.com/mod/pkg"]["pkg/path"]["home"]; ok {
+		t.Errorf("Builder kept the counts of a retired counter")
+	}
+}
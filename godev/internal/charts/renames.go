@@ -0,0 +1,69 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"strings"
+
+	"golang.org/x/telemetry/internal/chartconfig"
+)
+
+// Renames holds the former names of charts, declared by the renamed-from
+// fields of the chart config, keyed by program and current chart name.
+type Renames map[programName]map[graphName][]graphName
+
+// NewRenames returns the Renames declared by the given chart configs.
+func NewRenames(ccfgs []chartconfig.ChartConfig) Renames {
+	r := make(Renames)
+	for _, c := range ccfgs {
+		if len(c.RenamedFrom) == 0 {
+			continue
+		}
+		program := programName(c.Program)
+		if r[program] == nil {
+			r[program] = make(map[graphName][]graphName)
+		}
+		chart, _, _ := strings.Cut(c.Counter, ":")
+		for _, from := range c.RenamedFrom {
+			r[program][graphName(chart)] = append(r[program][graphName(chart)], graphName(from))
+		}
+	}
+	return r
+}
+
+// fold moves the counts of each renamed chart in d into the chart with its
+// current name, so that charts show data uploaded under former names.
+//
+// A counter without a bucket is its own bucket (see splitCounterName), so
+// its bucket is renamed along with its chart.
+func (d data) fold(r Renames) {
+	for _, programs := range d {
+		for program, charts := range programs {
+			for chart, froms := range r[program] {
+				for _, from := range froms {
+					buckets, ok := charts[from]
+					if !ok {
+						continue
+					}
+					delete(charts, from)
+					if charts[chart] == nil {
+						charts[chart] = make(map[bucketName]map[reportID]int64)
+					}
+					for bucket, counts := range buckets {
+						if bucket == bucketName(from) {
+							bucket = bucketName(chart)
+						}
+						if charts[chart][bucket] == nil {
+							charts[chart][bucket] = make(map[reportID]int64)
+						}
+						for id, value := range counts {
+							charts[chart][bucket][id] += value
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/internal/chartconfig"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestFoldRenames(t *testing.T) {
+	ccfgs, err := chartconfig.Parse([]byte(`
+counter: pkg/editor:{vim,emacs}
+program: example.com/mod/pkg
+renamed-from: pkg/old-editor
+---
+counter: pkg/total
+program: example.com/mod/pkg
+renamed-from: pkg/old-total
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rn := NewRenames(ccfgs)
+
+	report := func(x float64, counters map[string]int64) telemetry.Report {
+		return telemetry.Report{
+			Week: "2999-01-01",
+			X:    x,
+			Programs: []*telemetry.ProgramReport{{
+				Program:  "example.com/mod/pkg",
+				Version:  "v1.0.0",
+				Counters: counters,
+			}},
+		}
+	}
+	d := group([]telemetry.Report{
+		report(0.1, map[string]int64{"pkg/old-editor:vim": 2, "pkg/old-total": 5}),
+		report(0.2, map[string]int64{"pkg/editor:vim": 3, "pkg/editor:emacs": 1, "pkg/total": 7}),
+	})
+	d.fold(rn)
+
+	folded := d["2999-01-01"]["example.com/mod/pkg"]
+	want := map[graphName]map[bucketName]map[reportID]int64{
+		"pkg/editor": {
+			"vim":   {0.1: 2, 0.2: 3},
+			"emacs": {0.2: 1},
+		},
+		"pkg/total": {
+			"pkg/total": {0.1: 5, 0.2: 7},
+		},
+	}
+	for chart, buckets := range want {
+		if diff := cmp.Diff(buckets, folded[chart]); diff != "" {
+			t.Errorf("chart %s after fold mismatch (-want +got):\n%s", chart, diff)
+		}
+	}
+	for _, old := range []graphName{"pkg/old-editor", "pkg/old-total"} {
+		if _, ok := folded[old]; ok {
+			t.Errorf("renamed chart %s remains after fold", old)
+		}
+	}
+
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		Programs: []*telemetry.ProgramConfig{{
+			Name:     "example.com/mod/pkg",
+			Counters: []telemetry.CounterConfig{{Name: "pkg/editor:{vim,emacs}"}},
+		}},
+	})
+	got := charts(cfg, &Rules{Renames: rn}, "2999-01-01", "2999-01-01", d, nil, 2)
+	for _, c := range got.Programs[0].Charts {
+		if c.Name == "pkg/editor" {
+			if want := []string{"pkg/old-editor"}; !cmp.Equal(c.RenamedFrom, want) {
+				t.Errorf("chart pkg/editor has RenamedFrom %q, want %q", c.RenamedFrom, want)
+			}
+			return
+		}
+	}
+	t.Errorf("no chart for pkg/editor in %v", got.Programs[0].Charts)
+}
@@ -0,0 +1,24 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import "golang.org/x/telemetry/internal/chartconfig"
+
+// Rules holds what the chart config declares about charting reports beyond
+// the counters of the upload config. A nil *Rules declares nothing.
+type Rules struct {
+	Renames       Renames
+	Retirements   Retirements
+	Granularities Granularities
+}
+
+// NewRules returns the Rules declared by the given chart configs.
+func NewRules(ccfgs []chartconfig.ChartConfig) *Rules {
+	return &Rules{
+		Renames:       NewRenames(ccfgs),
+		Retirements:   NewRetirements(ccfgs),
+		Granularities: NewGranularities(ccfgs),
+	}
+}
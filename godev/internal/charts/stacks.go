@@ -0,0 +1,100 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tconfig "golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/counter"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// maxStacks is the number of stacks of a stack counter that are charted for
+// each week: those that were counted most often.
+const maxStacks = 10
+
+// stackData holds the counts of the stacks of stack counters, summed over
+// reports, by week, program, and counter prefix. The stacks are encoded as
+// in the counter names of the reports, without the prefix.
+type stackData map[weekName]map[programName]map[graphName]map[string]int64
+
+// add adds the counts of the stack counters of a report that the upload
+// config permits to s.
+func (s stackData) add(cfg *tconfig.Config, r *telemetry.Report) {
+	week := weekName(r.Week)
+	for _, p := range r.Programs {
+		program := programName(p.Program)
+		for c, value := range p.Stacks {
+			prefix, stack, _ := strings.Cut(c, "\n")
+			if !cfg.HasStack(p.Program, prefix) {
+				continue
+			}
+			if _, ok := s[week]; !ok {
+				s[week] = make(map[programName]map[graphName]map[string]int64)
+			}
+			if _, ok := s[week][program]; !ok {
+				s[week][program] = make(map[graphName]map[string]int64)
+			}
+			if _, ok := s[week][program][graphName(prefix)]; !ok {
+				s[week][program][graphName(prefix)] = make(map[string]int64)
+			}
+			s[week][program][graphName(prefix)][stack] += value
+		}
+	}
+}
+
+// chart builds the chart of the stacks of the program's stack counter with
+// the given prefix. For each week, it holds the maxStacks stacks with the
+// highest counts, in decreasing order of count, keyed by the decoded stack.
+// It returns nil if the counter has no data in s.
+func (s stackData) chart(program programName, prefix graphName) *Chart {
+	chart := &Chart{
+		ID:   fmt.Sprintf("charts:%s:%s", program, prefix),
+		Name: string(prefix),
+		Type: "stack",
+	}
+	var weeks []weekName
+	for wk := range s {
+		if len(s[wk][program][prefix]) > 0 {
+			weeks = append(weeks, wk)
+		}
+	}
+	if len(weeks) == 0 {
+		return nil
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i] < weeks[j] })
+	for _, wk := range weeks {
+		var data []*Datum
+		for stack, value := range s[wk][program][prefix] {
+			data = append(data, &Datum{
+				Week:  string(wk),
+				Key:   decodeStack(prefix, stack),
+				Value: float64(value),
+			})
+		}
+		sort.Slice(data, func(i, j int) bool {
+			if data[i].Value != data[j].Value {
+				return data[i].Value > data[j].Value
+			}
+			return data[i].Key < data[j].Key
+		})
+		if len(data) > maxStacks {
+			data = data[:maxStacks]
+		}
+		chart.Data = append(chart.Data, data...)
+	}
+	return chart
+}
+
+// decodeStack expands the frames of a stack, which are compressed in
+// counter names, and returns them one per line.
+func decodeStack(prefix graphName, stack string) string {
+	decoded := counter.DecodeStack(string(prefix) + "\n" + stack)
+	_, frames, _ := strings.Cut(decoded, "\n")
+	return frames
+}
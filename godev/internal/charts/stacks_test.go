@@ -0,0 +1,81 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestStackCharts(t *testing.T) {
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		Programs: []*telemetry.ProgramConfig{{
+			Name:   "example.com/mod/pkg",
+			Stacks: []telemetry.CounterConfig{{Name: "bug"}},
+		}},
+	})
+	report := func(week string, x float64, stacks map[string]int64) *telemetry.Report {
+		return &telemetry.Report{
+			Week: week,
+			X:    x,
+			Programs: []*telemetry.ProgramReport{{
+				Program: "example.com/mod/pkg",
+				GOOS:    "linux",
+				Stacks:  stacks,
+			}},
+		}
+	}
+	b := NewBuilder(cfg, nil)
+	b.Add(report("2999-01-01", 0.1, map[string]int64{
+		"bug\nexample.com/mod/pkg.f:+1\n\".g:+2": 2,
+		"bug\nexample.com/mod/pkg.h:+3":          1,
+		"other\nexample.com/mod/pkg.f:+1":        5, // not in the config
+	}))
+	b.Add(report("2999-01-01", 0.2, map[string]int64{
+		"bug\nexample.com/mod/pkg.h:+3": 4,
+	}))
+	// Only the maxStacks most frequent stacks of a week are charted.
+	many := make(map[string]int64)
+	for i := 0; i <= maxStacks; i++ {
+		many[fmt.Sprintf("bug\nexample.com/mod/pkg.f%02d:+1", i)] = int64(maxStacks + 1 - i)
+	}
+	b.Add(report("2999-01-08", 0.3, many))
+
+	start := time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := b.Charts(start, start.AddDate(0, 0, 7))
+	var chart *Chart
+	for _, c := range got.Programs[0].Charts {
+		if c.Type == "stack" {
+			if chart != nil {
+				t.Fatalf("got more than one stack chart: %v, %v", chart, c)
+			}
+			chart = c
+		}
+	}
+	want := &Chart{
+		ID:   "charts:example.com/mod/pkg:bug",
+		Name: "bug",
+		Type: "stack",
+		Data: []*Datum{
+			{Week: "2999-01-01", Key: "example.com/mod/pkg.h:+3", Value: 5},
+			{Week: "2999-01-01", Key: "example.com/mod/pkg.f:+1\nexample.com/mod/pkg.g:+2", Value: 2},
+		},
+	}
+	for i := 0; i < maxStacks; i++ {
+		want.Data = append(want.Data, &Datum{
+			Week:  "2999-01-08",
+			Key:   fmt.Sprintf("example.com/mod/pkg.f%02d:+1", i),
+			Value: float64(maxStacks + 1 - i),
+		})
+	}
+	if diff := cmp.Diff(want, chart); diff != "" {
+		t.Errorf("stack chart mismatch (-want +got):\n%s", diff)
+	}
+}
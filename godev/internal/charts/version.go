@@ -0,0 +1,94 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Version is the version of the format of the chart data written by this
+// package, recorded in Data.Version.
+//
+// The worker writes chart data that the server reads, and the two are
+// deployed independently, so the server may read chart data written by an
+// older worker, or that was stored long ago. To change the format
+// incompatibly, increment Version, append to upgrades a function that
+// converts data of the previous version, and add an example of the previous
+// version to testdata.
+//
+// Version 0 is the format of the chart data written before versions were
+// recorded. Version 1 only adds the Version field.
+const Version = 1
+
+// upgrades[v] converts chart data, decoded as generic JSON, from version v to
+// version v+1.
+var upgrades = []func(data map[string]any) error{
+	0: func(map[string]any) error { return nil },
+}
+
+func init() {
+	if len(upgrades) != Version {
+		panic(fmt.Sprintf("%d chart data upgrades for version %d", len(upgrades), Version))
+	}
+}
+
+// Upgrade converts chart data, decoded as generic JSON, from its version to
+// the current Version, in place. It fails if the data is of a newer version
+// than this package supports.
+func Upgrade(data map[string]any) error {
+	v := 0
+	if n, ok := data["Version"]; ok {
+		f, ok := n.(float64)
+		if !ok || f < 0 || f != float64(int(f)) {
+			return fmt.Errorf("%w: invalid chart data version %v", ErrInvalid, n)
+		}
+		v = int(f)
+	}
+	if v > Version {
+		return fmt.Errorf("%w: chart data version %d is newer than supported version %d", ErrInvalid, v, Version)
+	}
+	for ; v < Version; v++ {
+		if err := upgrades[v](data); err != nil {
+			return fmt.Errorf("%w: upgrading chart data from version %d: %v", ErrInvalid, v, err)
+		}
+	}
+	data["Version"] = float64(Version)
+	return nil
+}
+
+// Decode reads chart data of any supported version from r, and returns it in
+// the current format.
+func Decode(r io.Reader) (*Data, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var data Data
+	var v struct{ Version int }
+	if err := json.Unmarshal(b, &v); err == nil && v.Version == Version {
+		if err := json.Unmarshal(b, &data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		return &data, nil
+	}
+	// Other versions are upgraded in their generic form, as they may not
+	// fit Data.
+	var generic map[string]any
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if err := Upgrade(generic); err != nil {
+		return nil, err
+	}
+	if b, err = json.Marshal(generic); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return &data, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package charts
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+var update = flag.Bool("update", false, "if set, update the golden chart data of the current version")
+
+// goldenFile is the example of chart data of the given version in testdata.
+// All the examples hold the same charts, those of exampleReports.
+func goldenFile(version int) string {
+	return filepath.Join("testdata", fmt.Sprintf("version%d.json", version))
+}
+
+// TestFormat checks that the chart data written by this package has the
+// format of the golden file of the current version, so that the format does
+// not change without a new version.
+func TestFormat(t *testing.T) {
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:      []string{"darwin", "linux"},
+		GOARCH:    []string{"amd64", "arm64"},
+		GoVersion: []string{"go1.2.3"},
+		Programs: []*telemetry.ProgramConfig{
+			{Name: "cmd/go", Versions: []string{"go1.2.3"}, Counters: []telemetry.CounterConfig{{Name: "main"}}},
+		},
+	})
+	start := time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := Compute(cfg, nil, start, start.AddDate(0, 0, 7), exampleReports)
+	got, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	golden := goldenFile(Version)
+	if *update {
+		if err := os.WriteFile(golden, got, 0666); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("chart data does not match %s; if the format changed, increment Version and run go test -update\n%s",
+			golden, cmp.Diff(string(want), string(got)))
+	}
+}
+
+func TestDecode(t *testing.T) {
+	current, err := os.Open(goldenFile(Version))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer current.Close()
+	want, err := Decode(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want.Version != Version {
+		t.Fatalf("Version of %s = %d, want %d", goldenFile(Version), want.Version, Version)
+	}
+
+	// The data of every version decodes to the current format.
+	for v := 0; v < Version; v++ {
+		t.Run(fmt.Sprint(v), func(t *testing.T) {
+			f, err := os.Open(goldenFile(v))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			got, err := Decode(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Decode(%s) mismatch (-want +got):\n%s", goldenFile(v), diff)
+			}
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	for _, test := range []struct {
+		data    string
+		wantErr string
+	}{
+		{`{"DateRange":["2999-01-01","2999-01-01"]}`, ""},
+		{`{"Version":1}`, ""},
+		{fmt.Sprintf(`{"Version":%d}`, Version+1), "newer than supported"},
+		{`{"Version":"1"}`, "invalid chart data version"},
+		{`{"Version":1.5}`, "invalid chart data version"},
+	} {
+		var data map[string]any
+		if err := json.Unmarshal([]byte(test.data), &data); err != nil {
+			t.Fatal(err)
+		}
+		err := Upgrade(data)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Upgrade(%s) = %v, want error containing %q", test.data, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Upgrade(%s) failed: %v", test.data, err)
+		} else if data["Version"] != float64(Version) {
+			t.Errorf("Upgrade(%s): Version = %v, want %d", test.data, data["Version"], Version)
+		}
+	}
+}
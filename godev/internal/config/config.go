@@ -48,26 +48,132 @@ type Config struct {
 	// reports from the upload bucket and saves them here.
 	MergedBucket string
 
+	// CompressMerged is true if the objects written to MergedBucket are
+	// compressed with gzip. Readers decompress them transparently.
+	CompressMerged bool
+
 	// UploadBucket is the storage bucket for report uploads.
 	UploadBucket string
 
 	// ChartDataBucket is the storage bucket for chart data.
 	ChartDataBucket string
 
+	// StatsBucket is the storage bucket for the per-day upload statistics
+	// that the worker writes when it merges reports.
+	StatsBucket string
+
+	// TimeSeriesBucket is the storage bucket for the weekly time series of
+	// counters, which the worker updates a week at a time.
+	TimeSeriesBucket string
+
+	// DeadLetterBucket is the storage bucket for the descriptors of worker
+	// tasks that failed permanently, from which they can be replayed.
+	DeadLetterBucket string
+
+	// BigQueryDataset is the BigQuery dataset into which the worker exports
+	// merged reports. If empty, merged reports are not exported.
+	BigQueryDataset string
+
 	// UploadConfig is the location of the upload config deployed with the server.
 	// It's used to validate telemetry uploads.
 	UploadConfig string
 
+	// ConfigRefreshMinutes is the interval at which the server checks for a
+	// newer version of the upload config module. Zero disables the refresh.
+	ConfigRefreshMinutes int64
+
+	// AdminToken is the bearer token that authorizes requests to the
+	// server's admin endpoints. If empty, the admin endpoints are disabled.
+	AdminToken string
+
+	// ContentSecurityPolicy is the Content-Security-Policy header of the
+	// server's responses. If empty, the header is not set.
+	ContentSecurityPolicy string
+
+	// CSRFProtection is true if the server rejects state-changing requests
+	// from browsers that do not carry a CSRF token. Uploads and requests
+	// authorized with a bearer token are exempt.
+	CSRFProtection bool
+
 	// MaxRequestBytes is the maximum request body size the server will allow.
 	MaxRequestBytes int64
 
 	// RequestTimeout is the default request timeout for the server.
 	RequestTimeout time.Duration
 
+	// DrainTimeout is how long the servers wait for requests in flight to
+	// complete when they are asked to shut down. Cloud Run kills instances
+	// 10 seconds after asking them to shut down.
+	DrainTimeout time.Duration
+
+	// MaxConcurrentCharts is the maximum number of chart and new counter
+	// requests the worker handles at once, and of date range chart requests
+	// the server handles at once. Each reads a range of merged reports into
+	// memory. Zero means no limit.
+	MaxConcurrentCharts int64
+
+	// MaxAggregateDays is the length of the longest date range for which the
+	// server computes charts on demand, when the worker has not precomputed
+	// them.
+	MaxAggregateDays int64
+
+	// UploadRetentionDays is the number of days after which the worker's
+	// retention task deletes uploaded reports that were merged. Merged
+	// reports are kept indefinitely. Zero keeps uploads indefinitely.
+	UploadRetentionDays int64
+
+	// DeadLetterRetentionDays is the number of days after which the
+	// worker's retention task deletes the descriptors of failed tasks. Zero
+	// keeps them indefinitely.
+	DeadLetterRetentionDays int64
+
+	// MaxReportAgeDays and MaxReportFutureDays bound the weeks of the reports
+	// the server accepts: a report's week may end at most MaxReportAgeDays
+	// before, and at most MaxReportFutureDays after, the day of its upload.
+	MaxReportAgeDays    int64
+	MaxReportFutureDays int64
+
+	// UploadRatePerClient and UploadRate are the number of uploads per minute
+	// the server accepts from each client address, and from all clients
+	// together. Zero means no limit.
+	UploadRatePerClient int64
+	UploadRate          int64
+
+	// MaxConcurrentMerges is the maximum number of merge requests the worker
+	// handles at once. Zero means no limit.
+	MaxConcurrentMerges int64
+
+	// RetryAfter is the delay clients are asked to wait before retrying a
+	// request shed by the worker's concurrency limits.
+	RetryAfter time.Duration
+
+	// FlagsFile is the location of a JSON file of operational flags, such as
+	// maintenance mode, which is reread when it changes. See package
+	// godev/internal/flags.
+	FlagsFile string
+
+	// Maintenance is true if the upload endpoint should be paused while
+	// FlagsFile does not exist.
+	Maintenance bool
+
 	// UseGCS is true if the server should use the Cloud Storage API for reading and
 	// writing storage objects.
 	UseGCS bool
 
+	// S3Endpoint, if set, is the endpoint of an S3-compatible object store,
+	// such as Amazon S3 or MinIO, whose buckets are used instead of Cloud
+	// Storage or the filesystem.
+	S3Endpoint string
+
+	// S3Region is the region of the S3 object store, with which its requests
+	// are signed.
+	S3Region string
+
+	// S3AccessKeyID and S3SecretAccessKey are the credentials of the S3
+	// object store.
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+
 	// DevMode is true if the server should read content files from the filesystem.
 	// If false, content files are read from the embed.FS in ../content.go.
 	DevMode bool
@@ -78,40 +184,77 @@ var (
 	useGCS  = flag.Bool("gcs", false, "use Cloud Storage for reading and writing storage objects")
 )
 
+// defaultCSP is the default Content-Security-Policy of the server. Pages pass
+// their data to scripts in inline script elements, and charts are styled
+// inline, so inline scripts and styles are allowed.
+const defaultCSP = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; object-src 'none'; base-uri 'self'; frame-ancestors 'none'"
+
 // NewConfig returns a new config. Getting the config should follow a call to flag.Parse.
 func NewConfig() *Config {
 	environment := env("GO_TELEMETRY_ENV", "local")
 	return &Config{
-		ServerPort:        env("PORT", "8080"),
-		WorkerPort:        env("PORT", "8082"),
-		WorkerURL:         env("GO_TELEMETRY_WORKER_URL", "http://localhost:8082"),
-		ProjectID:         env("GO_TELEMETRY_PROJECT_ID", "go-telemetry"),
-		LocationID:        env("GO_TELEMETRY_LOCATION_ID", ""),
-		QueueID:           environment + "-worker-tasks",
-		IAPServiceAccount: env("GO_TELEMETRY_IAP_SERVICE_ACCOUNT", ""),
-		ClientID:          env("GO_TELEMETRY_CLIENT_ID", ""),
-		LocalStorage:      env("GO_TELEMETRY_LOCAL_STORAGE", ".localstorage"),
-		ChartDataBucket:   environment + "-telemetry-charted",
-		Env:               environment,
-		MergedBucket:      environment + "-telemetry-merged",
-		UploadBucket:      environment + "-telemetry-uploaded",
-		UploadConfig:      env("GO_TELEMETRY_UPLOAD_CONFIG", "./config/config.json"),
-		MaxRequestBytes:   env("GO_TELEMETRY_MAX_REQUEST_BYTES", int64(100*1024)),
-		RequestTimeout:    10 * time.Duration(time.Minute),
-		UseGCS:            *useGCS,
-		DevMode:           *devMode,
+		ServerPort:              env("PORT", "8080"),
+		WorkerPort:              env("PORT", "8082"),
+		WorkerURL:               env("GO_TELEMETRY_WORKER_URL", "http://localhost:8082"),
+		ProjectID:               env("GO_TELEMETRY_PROJECT_ID", "go-telemetry"),
+		LocationID:              env("GO_TELEMETRY_LOCATION_ID", ""),
+		QueueID:                 environment + "-worker-tasks",
+		IAPServiceAccount:       env("GO_TELEMETRY_IAP_SERVICE_ACCOUNT", ""),
+		ClientID:                env("GO_TELEMETRY_CLIENT_ID", ""),
+		LocalStorage:            env("GO_TELEMETRY_LOCAL_STORAGE", ".localstorage"),
+		ChartDataBucket:         environment + "-telemetry-charted",
+		StatsBucket:             environment + "-telemetry-stats",
+		TimeSeriesBucket:        environment + "-telemetry-timeseries",
+		DeadLetterBucket:        environment + "-telemetry-deadletter",
+		Env:                     environment,
+		MergedBucket:            environment + "-telemetry-merged",
+		CompressMerged:          env("GO_TELEMETRY_COMPRESS_MERGED", false),
+		UploadBucket:            environment + "-telemetry-uploaded",
+		BigQueryDataset:         env("GO_TELEMETRY_BIGQUERY_DATASET", ""),
+		UploadConfig:            env("GO_TELEMETRY_UPLOAD_CONFIG", "./config/config.json"),
+		ConfigRefreshMinutes:    env("GO_TELEMETRY_CONFIG_REFRESH_MINUTES", int64(60)),
+		AdminToken:              env("GO_TELEMETRY_ADMIN_TOKEN", ""),
+		ContentSecurityPolicy:   env("GO_TELEMETRY_CSP", defaultCSP),
+		CSRFProtection:          env("GO_TELEMETRY_CSRF_PROTECTION", true),
+		MaxRequestBytes:         env("GO_TELEMETRY_MAX_REQUEST_BYTES", int64(100*1024)),
+		RequestTimeout:          10 * time.Duration(time.Minute),
+		DrainTimeout:            time.Duration(env("GO_TELEMETRY_DRAIN_SECONDS", int64(8))) * time.Second,
+		MaxConcurrentCharts:     env("GO_TELEMETRY_MAX_CONCURRENT_CHARTS", int64(2)),
+		MaxConcurrentMerges:     env("GO_TELEMETRY_MAX_CONCURRENT_MERGES", int64(4)),
+		MaxAggregateDays:        env("GO_TELEMETRY_MAX_AGGREGATE_DAYS", int64(31)),
+		MaxReportAgeDays:        env("GO_TELEMETRY_MAX_REPORT_AGE_DAYS", int64(21)),
+		UploadRetentionDays:     env("GO_TELEMETRY_UPLOAD_RETENTION_DAYS", int64(0)),
+		DeadLetterRetentionDays: env("GO_TELEMETRY_DEADLETTER_RETENTION_DAYS", int64(90)),
+		MaxReportFutureDays:     env("GO_TELEMETRY_MAX_REPORT_FUTURE_DAYS", int64(1)),
+		UploadRatePerClient:     env("GO_TELEMETRY_UPLOAD_RATE_PER_CLIENT", int64(60)),
+		UploadRate:              env("GO_TELEMETRY_UPLOAD_RATE", int64(6000)),
+		RetryAfter:              time.Minute,
+		FlagsFile:               env("GO_TELEMETRY_FLAGS_FILE", ""),
+		Maintenance:             env("GO_TELEMETRY_MAINTENANCE", false),
+		S3Endpoint:              env("GO_TELEMETRY_S3_ENDPOINT", ""),
+		S3Region:                env("GO_TELEMETRY_S3_REGION", "us-east-1"),
+		S3AccessKeyID:           env("AWS_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey:       env("AWS_SECRET_ACCESS_KEY", ""),
+		UseGCS:                  *useGCS,
+		DevMode:                 *devMode,
 	}
 }
 
 // env reads a value from the os environment and returns a fallback
 // when it is unset.
-func env[T string | int64](key string, fallback T) T {
+func env[T string | int64 | bool](key string, fallback T) T {
 	if s, ok := os.LookupEnv(key); ok {
 		switch any(fallback).(type) {
 		case string:
 			return any(s).(T)
 		case int64:
-			v, err := strconv.Atoi(s)
+			v, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				log.Fatalf("bad value %q for %s: %v", s, key, err)
+			}
+			return any(v).(T)
+		case bool:
+			v, err := strconv.ParseBool(s)
 			if err != nil {
 				log.Fatalf("bad value %q for %s: %v", s, key, err)
 			}
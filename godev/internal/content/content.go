@@ -22,15 +22,34 @@
 // Partial templates with the extension ".tmpl" at the root of the file system
 // and in the same directory as the requested page are included in the
 // html/template execution step to allow for sharing and composing logic from
-// multiple templates.
+// multiple templates. The templates of each page are parsed once and cached
+// (see TemplateCache).
 //
 // Markdown templates must have an html layout template set in the frontmatter
 // section. The markdown content is available to the layout template as the
 // field `{{.Content}}`.
+//
+// # Error Pages
+//
+// Errors are written as plain text, unless the request is from a browser and
+// fsys has an error template for the status code, named like "404.html".
+// Server errors without their own template use "500.html". The templates are
+// executed with an ErrorPage. Use ErrorPages to render the errors of
+// handlers outside of a content server in the same way.
+//
+// # Caching
+//
+// Static files are served with an ETag computed from their contents and a
+// Cache-Control header of StaticMaxAge, and conditional requests for them
+// are answered with 304 Not Modified. Handlers can serve data that changes
+// rarely, such as chart data, in the same way with CachedJSON.
 package content
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -39,14 +58,20 @@ import (
 	"log/slog"
 	"net/http"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
 	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
 	meta "github.com/yuin/goldmark-meta"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+	contentfs "golang.org/x/telemetry/internal/content"
 )
 
 // contentServer serves requests for a given file system and renders html
@@ -54,6 +79,7 @@ import (
 type contentServer struct {
 	fsys     fs.FS
 	fserv    http.Handler
+	tmpls    *TemplateCache
 	handlers map[string]HandlerFunc
 }
 
@@ -97,7 +123,7 @@ func Server(fsys fs.FS, handlers ...*handler) http.Handler {
 		}
 		hs[h.path] = h.fn
 	}
-	return &contentServer{fsys, fserv, hs}
+	return &contentServer{fsys, fserv, NewTemplateCache(fsys), hs}
 }
 
 type handler struct {
@@ -110,6 +136,7 @@ func Handler(path string, h HandlerFunc) *handler {
 }
 
 func (c *contentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r = r.WithContext(context.WithValue(r.Context(), errorTemplatesKey{}, c.tmpls))
 	if len(r.URL.Path) > 255 {
 		handleErr(w, r, errors.New("url too long"), http.StatusBadRequest)
 		return
@@ -127,6 +154,9 @@ func (c *contentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filepath, err := stat(c.fsys, r.URL.Path)
+	if err == nil && isErrorTemplate(filepath) {
+		err = fs.ErrNotExist // error pages are not served on their own
+	}
 	if errors.Is(err, fs.ErrNotExist) {
 		handleErr(w, r, errors.New(http.StatusText(http.StatusNotFound)), http.StatusNotFound)
 		return
@@ -138,11 +168,11 @@ func (c *contentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		switch path.Ext(filepath) {
 		case ".html":
-			err = Template(w, c.fsys, filepath, nil, http.StatusOK)
+			err = c.tmpls.Template(w, filepath, nil, http.StatusOK)
 		case ".md":
-			err = markdown(w, c.fsys, filepath, http.StatusOK)
+			err = c.markdown(w, filepath, http.StatusOK)
 		default:
-			c.fserv.ServeHTTP(w, r)
+			err = c.serveFile(w, r, filepath)
 		}
 	}
 	if err != nil {
@@ -150,28 +180,152 @@ func (c *contentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Template executes a template response.
+// StaticMaxAge is how long clients and caches may keep static files.
+const StaticMaxAge = time.Hour
+
+// serveFile serves the static file at filepath in the content file system,
+// with caching headers.
+func (c *contentServer) serveFile(w http.ResponseWriter, r *http.Request, filepath string) error {
+	data, err := fs.ReadFile(c.fsys, filepath)
+	if err != nil {
+		// A directory, most likely; let the file server list it.
+		c.fserv.ServeHTTP(w, r)
+		return nil
+	}
+	// The file server answers conditional requests using the ETag header.
+	setCacheHeaders(w, data, StaticMaxAge)
+	c.fserv.ServeHTTP(w, r)
+	return nil
+}
+
+// setCacheHeaders sets the ETag header of a response with the given body,
+// and a Cache-Control header that allows public caches to keep it for maxAge.
+func setCacheHeaders(w http.ResponseWriter, body []byte, maxAge time.Duration) {
+	sum := sha256.Sum256(body)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+}
+
+// Template executes a template response. It parses the templates on each
+// call; servers that render pages repeatedly should use a TemplateCache.
 // TODO(rfindley): this abstraction no longer holds its weight. Refactor.
 func Template(w http.ResponseWriter, fsys fs.FS, tmplPath string, data any, code int) error {
-	patterns, err := tmplPatterns(fsys, tmplPath)
+	t, err := parseTemplate(fsys, tmplPath)
 	if err != nil {
 		return err
 	}
+	return execute(w, t.tmpl, tmplPath, data, code)
+}
+
+// A TemplateCache executes the templates of a file system, like Template,
+// but parses the templates of each page only once.
+//
+// Cached templates are reparsed when the modification time of one of their
+// files changes, or when partial templates are added or removed, so that
+// templates edited in dev mode, when the file system is read from the OS,
+// are reloaded. The files of an embed.FS have no modification times, so
+// their templates are never checked again.
+type TemplateCache struct {
+	fsys fs.FS
+
+	mu    sync.Mutex
+	pages map[string]*parsedTemplate // by template path
+}
+
+// parsedTemplate is a page template parsed along with its partial templates.
+type parsedTemplate struct {
+	tmpl     *template.Template
+	patterns []string    // the files parsed into tmpl
+	modTimes []time.Time // of the files, before they were parsed
+}
+
+// NewTemplateCache returns an empty cache of the templates of fsys.
+func NewTemplateCache(fsys fs.FS) *TemplateCache {
+	return &TemplateCache{fsys: fsys, pages: make(map[string]*parsedTemplate)}
+}
+
+// Template executes the template response at tmplPath.
+func (c *TemplateCache) Template(w http.ResponseWriter, tmplPath string, data any, code int) error {
+	c.mu.Lock()
+	t := c.pages[tmplPath]
+	c.mu.Unlock()
+	if t == nil || !t.current(c.fsys, tmplPath) {
+		var err error
+		t, err = parseTemplate(c.fsys, tmplPath)
+		if err != nil {
+			return err
+		}
+		c.mu.Lock()
+		c.pages[tmplPath] = t
+		c.mu.Unlock()
+	}
+	return execute(w, t.tmpl, tmplPath, data, code)
+}
+
+// parseTemplate parses the page template at tmplPath of fsys with its
+// partial templates.
+func parseTemplate(fsys fs.FS, tmplPath string) (*parsedTemplate, error) {
+	patterns, err := tmplPatterns(fsys, tmplPath)
+	if err != nil {
+		return nil, err
+	}
 	patterns = append(patterns, tmplPath)
-	tmpl, err := template.New("").Funcs(chartFuncs()).ParseFS(fsys, patterns...)
+	// The files are stated first, so that changes made while they are
+	// parsed are seen by the next check.
+	modTimes, err := statTimes(fsys, patterns)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	tmpl, err := template.New("").Funcs(chartFuncs()).Funcs(assetFuncs(fsys)).ParseFS(fsys, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return &parsedTemplate{tmpl, patterns, modTimes}, nil
+}
+
+// current reports whether t was parsed from the current templates of fsys.
+func (t *parsedTemplate) current(fsys fs.FS, tmplPath string) bool {
+	if !slices.ContainsFunc(t.modTimes, func(mt time.Time) bool { return !mt.IsZero() }) {
+		return true // embedded templates do not change
 	}
+	patterns, err := tmplPatterns(fsys, tmplPath)
+	if err != nil {
+		return false
+	}
+	patterns = append(patterns, tmplPath)
+	if !slices.Equal(patterns, t.patterns) {
+		return false
+	}
+	modTimes, err := statTimes(fsys, patterns)
+	return err == nil && slices.EqualFunc(modTimes, t.modTimes, time.Time.Equal)
+}
+
+// statTimes returns the modification times of the named files of fsys.
+func statTimes(fsys fs.FS, names []string) ([]time.Time, error) {
+	modTimes := make([]time.Time, len(names))
+	for i, name := range names {
+		fi, err := fs.Stat(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = fi.ModTime()
+	}
+	return modTimes, nil
+}
+
+// execute writes the response of the page template at tmplPath, with the
+// given data and status code.
+func execute(w http.ResponseWriter, tmpl *template.Template, tmplPath string, data any, code int) error {
 	name := path.Base(tmplPath)
 	var buf bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
 		return err
 	}
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
 	if code != 0 {
 		w.WriteHeader(code)
 	}
-	w.Header().Set("Content-Type", "text/html")
-	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
 	if _, err := w.Write(buf.Bytes()); err != nil {
 		return err
 	}
@@ -194,34 +348,60 @@ func chartFuncs() template.FuncMap {
 	}
 }
 
+// assetFuncs returns the template funcs for referring to the static assets
+// in fsys.
+func assetFuncs(fsys fs.FS) template.FuncMap {
+	return template.FuncMap{
+		"integrity": func(urlPath string) (string, error) {
+			return contentfs.Integrity(fsys, urlPath)
+		},
+	}
+}
+
 // JSON encodes data as JSON response with a status code.
 func JSON(w http.ResponseWriter, data any, code int) error {
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(data); err != nil {
 		return err
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
 	if code != 0 {
 		w.WriteHeader(code)
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
 	if _, err := w.Write(buf.Bytes()); err != nil {
 		return err
 	}
 	return nil
 }
 
+// CachedJSON encodes data as a JSON response with status 200, an ETag
+// computed from the encoded data, a Last-Modified header of modTime unless
+// it is zero, and a Cache-Control header that allows public caches to keep
+// the response for maxAge. Conditional requests for an unchanged response
+// are answered with 304 Not Modified.
+func CachedJSON(w http.ResponseWriter, r *http.Request, data any, modTime time.Time, maxAge time.Duration) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		return err
+	}
+	setCacheHeaders(w, buf.Bytes(), maxAge)
+	w.Header().Set("Content-Type", "application/json")
+	http.ServeContent(w, r, "", modTime, bytes.NewReader(buf.Bytes()))
+	return nil
+}
+
 // Text formats data as a text response with a status code.
 func Text(w http.ResponseWriter, data any, code int) error {
 	var buf bytes.Buffer
 	if _, err := fmt.Fprint(&buf, data); err != nil {
 		return err
 	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
 	if code != 0 {
 		w.WriteHeader(code)
 	}
-	w.Header().Set("Content-Type", "text/plain")
-	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
 	if _, err := w.Write(buf.Bytes()); err != nil {
 		return err
 	}
@@ -250,6 +430,18 @@ type contentError struct {
 
 func (e *contentError) Error() string { return e.err.Error() }
 
+func (e *contentError) Unwrap() error { return e.err }
+
+// ErrorStatus returns the status code with which a HandlerFunc responds when
+// it returns err: the code annotated by Error, or 500 Internal Server Error.
+func ErrorStatus(err error) int {
+	var cerr *contentError
+	if errors.As(err, &cerr) {
+		return cerr.Code
+	}
+	return http.StatusInternalServerError
+}
+
 // handleErr writes an error as an HTTP response with a status code.
 //
 // err must be non-nil when calling this function.
@@ -265,16 +457,76 @@ func handleErr(w http.ResponseWriter, req *http.Request, err error, code int) {
 		errs = append(errs[:79], '…')
 	}
 	slog.WarnContext(req.Context(), fmt.Sprintf("request for %q failed with status %d: %s", req.URL.Path, code, string(errs)))
+	msg := err.Error()
 	if code == http.StatusInternalServerError {
-		http.Error(w, http.StatusText(http.StatusInternalServerError), code)
-	} else {
-		http.Error(w, err.Error(), code)
+		msg = http.StatusText(http.StatusInternalServerError)
 	}
+	if tmpls, ok := req.Context().Value(errorTemplatesKey{}).(*TemplateCache); ok && acceptsHTML(req) {
+		if renderError(w, tmpls, code, msg) == nil {
+			return
+		}
+	}
+	http.Error(w, msg, code)
+}
+
+// ErrorPage is the data with which error templates are executed.
+type ErrorPage struct {
+	Code    int    // status code of the response
+	Status  string // text of the status code, such as "Not Found"
+	Message string // the error, if it says more than Status
+}
+
+// errorTemplatesKey is the context key of the TemplateCache whose error
+// templates render the errors of a request.
+type errorTemplatesKey struct{}
+
+// ErrorPages returns a middleware that renders the errors of the HandlerFuncs
+// it wraps with the error templates of fsys, as a content server does.
+func ErrorPages(fsys fs.FS) func(http.Handler) http.Handler {
+	tmpls := NewTemplateCache(fsys)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), errorTemplatesKey{}, tmpls)))
+		})
+	}
+}
+
+// renderError writes the error page for status code with the given message.
+// It fails without writing to w if there is no template for code.
+func renderError(w http.ResponseWriter, tmpls *TemplateCache, code int, msg string) error {
+	name := strconv.Itoa(code) + ".html"
+	if _, err := fs.Stat(tmpls.fsys, name); err != nil {
+		if code < 500 {
+			return err
+		}
+		name = "500.html"
+	}
+	page := ErrorPage{Code: code, Status: http.StatusText(code), Message: msg}
+	if page.Message == page.Status {
+		page.Message = ""
+	}
+	return tmpls.Template(w, name, page, code)
+}
+
+// isErrorTemplate reports whether the file at filepath is an error template.
+func isErrorTemplate(filepath string) bool {
+	code, ok := strings.CutSuffix(filepath, ".html")
+	if !ok || len(code) != 3 {
+		return false
+	}
+	n, err := strconv.Atoi(code)
+	return err == nil && 400 <= n && n < 600
+}
+
+// acceptsHTML reports whether the client of req, usually a browser, accepts
+// HTML responses.
+func acceptsHTML(req *http.Request) bool {
+	return strings.Contains(req.Header.Get("Accept"), "text/html")
 }
 
 // markdown renders a markdown template as html.
-func markdown(w http.ResponseWriter, fsys fs.FS, tmplPath string, code int) error {
-	markdown, err := fs.ReadFile(fsys, tmplPath)
+func (c *contentServer) markdown(w http.ResponseWriter, tmplPath string, code int) error {
+	markdown, err := fs.ReadFile(c.fsys, tmplPath)
 	if err != nil {
 		return err
 	}
@@ -291,6 +543,10 @@ func markdown(w http.ResponseWriter, fsys fs.FS, tmplPath string, code int) erro
 			extension.GFM,
 			extension.NewTypographer(),
 			meta.Meta,
+			headings{},
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
 		),
 	)
 	var content bytes.Buffer
@@ -303,11 +559,14 @@ func markdown(w http.ResponseWriter, fsys fs.FS, tmplPath string, code int) erro
 		data = map[string]interface{}{}
 	}
 	data["Content"] = template.HTML(content.String())
+	if toc, _ := ctx.Get(tocKey).([]tocEntry); len(toc) > 0 {
+		data["TOC"] = renderTOC(toc)
+	}
 	layout, ok := data["Layout"]
 	if !ok {
 		return errors.New("missing layout for template " + tmplPath)
 	}
-	return Template(w, fsys, layout.(string), data, code)
+	return c.tmpls.Template(w, layout.(string), data, code)
 }
 
 // stat trys to coerce a urlPath into an openable file then returns the
@@ -13,8 +13,11 @@ import (
 	"net/http/httptest"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/telemetry/internal/testenv"
@@ -201,3 +204,198 @@ func handleError() HandlerFunc {
 		return Error(errors.New("Oh no! Bad Request"), http.StatusBadRequest)
 	}
 }
+
+func TestCaching(t *testing.T) {
+	fsys := os.DirFS("testdata")
+	server := Server(fsys,
+		Handler("/cached", func(w http.ResponseWriter, r *http.Request) error {
+			return CachedJSON(w, r, map[string]string{"Data": "Data"}, time.Time{}, 10*time.Minute)
+		}),
+	)
+	for _, test := range []struct {
+		path, cacheControl string
+	}{
+		{"/style.css", "public, max-age=3600"},
+		{"/cached", "public, max-age=600"},
+	} {
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, httptest.NewRequest("GET", test.path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d, want %d", test.path, rr.Code, http.StatusOK)
+		}
+		etag := rr.Header().Get("ETag")
+		if etag == "" {
+			t.Errorf("GET %s: no ETag", test.path)
+		}
+		if got := rr.Header().Get("Cache-Control"); got != test.cacheControl {
+			t.Errorf("GET %s: Cache-Control = %q, want %q", test.path, got, test.cacheControl)
+		}
+
+		// A conditional request for the same content is not modified.
+		req := httptest.NewRequest("GET", test.path, nil)
+		req.Header.Set("If-None-Match", etag)
+		rr = httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+			t.Errorf("GET %s with If-None-Match: status %d with %d bytes, want %d and none", test.path, rr.Code, rr.Body.Len(), http.StatusNotModified)
+		}
+
+		req = httptest.NewRequest("GET", test.path, nil)
+		req.Header.Set("If-None-Match", `"stale"`)
+		rr = httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Errorf("GET %s with a stale If-None-Match: status %d, want %d", test.path, rr.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestTemplateCache(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, data string, modTime time.Time) {
+		t.Helper()
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filename, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(c *TemplateCache, want string) {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		if err := c.Template(rr, "page.html", "data", http.StatusOK); err != nil {
+			t.Fatal(err)
+		}
+		if got := rr.Body.String(); got != want {
+			t.Errorf("page = %q, want %q", got, want)
+		}
+	}
+	t0 := time.Now().Add(-time.Hour)
+	writeFile("page.html", `{{template "base" .}}`, t0)
+	writeFile("base.tmpl", `{{define "base"}}v1 {{.}}{{end}}`, t0)
+	c := NewTemplateCache(os.DirFS(dir))
+	check(c, "v1 data")
+
+	// Edited templates are reparsed.
+	writeFile("base.tmpl", `{{define "base"}}v2 {{.}}{{end}}`, t0.Add(time.Minute))
+	check(c, "v2 data")
+
+	// So are the templates of a page whose partials were added.
+	writeFile("page.html", `{{template "base" .}} {{template "extra"}}`, t0.Add(time.Minute))
+	writeFile("extra.tmpl", `{{define "extra"}}extra{{end}}`, t0)
+	check(c, "v2 data extra")
+
+	// Files without modification times, like those of an embed.FS, are
+	// parsed once.
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`{{template "base" .}}`)},
+		"base.tmpl": {Data: []byte(`{{define "base"}}v1 {{.}}{{end}}`)},
+	}
+	c = NewTemplateCache(fsys)
+	check(c, "v1 data")
+	fsys["base.tmpl"].Data = []byte(`{{define "base"}}v2 {{.}}{{end}}`)
+	check(c, "v1 data")
+}
+
+func BenchmarkTemplate(b *testing.B) {
+	// The embedded file system has no modification times, as in production.
+	embedded := make(fstest.MapFS)
+	for _, name := range []string{"data.html", "base.tmpl"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			b.Fatal(err)
+		}
+		embedded[name] = &fstest.MapFile{Data: data}
+	}
+	dev := os.DirFS("testdata")
+	render := func(b *testing.B, f func(http.ResponseWriter) error) {
+		for i := 0; i < b.N; i++ {
+			if err := f(httptest.NewRecorder()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.Run("Uncached", func(b *testing.B) {
+		render(b, func(w http.ResponseWriter) error {
+			return Template(w, embedded, "data.html", "Data", http.StatusOK)
+		})
+	})
+	b.Run("Cached", func(b *testing.B) {
+		c := NewTemplateCache(embedded)
+		render(b, func(w http.ResponseWriter) error {
+			return c.Template(w, "data.html", "Data", http.StatusOK)
+		})
+	})
+	b.Run("CachedDevMode", func(b *testing.B) {
+		c := NewTemplateCache(dev)
+		render(b, func(w http.ResponseWriter) error {
+			return c.Template(w, "data.html", "Data", http.StatusOK)
+		})
+	})
+}
+
+func TestErrorPages(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`index`)},
+		"404.html":   {Data: []byte(`page {{.Code}} {{.Status}}{{with .Message}}: {{.}}{{end}}`)},
+		"500.html":   {Data: []byte(`page {{.Code}} {{.Status}}{{with .Message}}: {{.}}{{end}}`)},
+	}
+	server := Server(fsys, Handler("/error", handleError()))
+	mux := http.NewServeMux()
+	mux.Handle("/", server)
+	mux.Handle("/broken", HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+		return errors.New("secret internals")
+	}))
+	mux.Handle("/unavailable", HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+		return Error(errors.New("try later"), http.StatusServiceUnavailable)
+	}))
+	h := ErrorPages(fsys)(mux)
+
+	for _, test := range []struct {
+		path     string
+		html     bool // request from a browser
+		wantCode int
+		wantBody string
+	}{
+		{"/missing", true, http.StatusNotFound, "page 404 Not Found"},
+		{"/missing", false, http.StatusNotFound, "Not Found\n"},
+		{"/404", true, http.StatusNotFound, "page 404 Not Found"}, // not served on its own
+		{"/error", true, http.StatusBadRequest, "Oh no! Bad Request\n"},
+		{"/broken", true, http.StatusInternalServerError, "page 500 Internal Server Error"},
+		{"/broken", false, http.StatusInternalServerError, "Internal Server Error\n"},
+		{"/unavailable", true, http.StatusServiceUnavailable, "page 503 Service Unavailable: try later"},
+	} {
+		req := httptest.NewRequest("GET", test.path, nil)
+		if test.html {
+			req.Header.Set("Accept", "text/html,application/xhtml+xml")
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != test.wantCode || rr.Body.String() != test.wantBody {
+			t.Errorf("GET %s (html %t) = %d %q, want %d %q", test.path, test.html, rr.Code, rr.Body, test.wantCode, test.wantBody)
+		}
+	}
+}
+
+func TestRenderTOC(t *testing.T) {
+	for _, test := range []struct {
+		toc  []tocEntry
+		want string
+	}{
+		{nil, ""},
+		{
+			[]tocEntry{{2, "a", "A"}, {3, "a1", "A & 1"}, {3, "a2", "A2"}, {2, "b", "B"}},
+			`<ul><li><a href="#a">A</a><ul><li><a href="#a1">A &amp; 1</a></li><li><a href="#a2">A2</a></li></ul></li><li><a href="#b">B</a></li></ul>`,
+		},
+		{
+			[]tocEntry{{3, "x", "X"}, {2, "a", "A"}, {3, "a1", "A1"}},
+			`<ul><li><a href="#x">X</a></li><li><a href="#a">A</a><ul><li><a href="#a1">A1</a></li></ul></li></ul>`,
+		},
+	} {
+		if got := string(renderTOC(test.toc)); got != test.want {
+			t.Errorf("renderTOC(%v) =\n%s\nwant\n%s", test.toc, got, test.want)
+		}
+	}
+}
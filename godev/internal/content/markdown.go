@@ -0,0 +1,98 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package content
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// headings is a goldmark extension that appends to each heading with an ID
+// a link to itself, and collects the table of contents of the page from its
+// second and third level headings.
+type headings struct{}
+
+// tocKey is the parser context key of the table of contents of a page, a
+// []tocEntry.
+var tocKey = parser.NewContextKey()
+
+// A tocEntry is a heading in the table of contents of a page.
+type tocEntry struct {
+	Level int
+	ID    string
+	Title string
+}
+
+func (headings) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(util.Prioritized(headings{}, 1000)))
+}
+
+func (headings) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var toc []tocEntry
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		id, ok := h.AttributeString("id")
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+		idStr := string(id.([]byte))
+		if h.Level == 2 || h.Level == 3 {
+			toc = append(toc, tocEntry{h.Level, idStr, string(h.Text(reader.Source()))})
+		}
+		anchor := ast.NewLink()
+		anchor.Destination = []byte("#" + idStr)
+		anchor.Title = []byte("Link to this section")
+		anchor.SetAttributeString("class", []byte("Anchor"))
+		anchor.AppendChild(anchor, ast.NewString([]byte("#")))
+		h.AppendChild(h, anchor)
+		return ast.WalkSkipChildren, nil
+	})
+	pc.Set(tocKey, toc)
+}
+
+// renderTOC renders a table of contents as nested lists of links, with
+// third level headings listed under the preceding second level heading.
+func renderTOC(toc []tocEntry) template.HTML {
+	if len(toc) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("<ul>")
+	depth := 0 // of the open list item
+	section := false
+	for i, e := range toc {
+		d := 0
+		if e.Level == 3 && section {
+			d = 1
+		}
+		section = section || e.Level == 2
+		switch {
+		case i == 0:
+		case d > depth:
+			b.WriteString("<ul>")
+		case d < depth:
+			b.WriteString("</li></ul></li>")
+		default:
+			b.WriteString("</li>")
+		}
+		depth = d
+		b.WriteString(`<li><a href="#` + template.HTMLEscapeString(e.ID) + `">` + template.HTMLEscapeString(e.Title) + "</a>")
+	}
+	b.WriteString("</li>")
+	if depth > 0 {
+		b.WriteString("</ul></li>")
+	}
+	b.WriteString("</ul>")
+	return template.HTML(b.String())
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package flags provides operational flags, such as maintenance mode, that
+// can be changed while the server is running.
+//
+// Flags are read from a JSON file, which is reread whenever it changes, so
+// that operators can, for example, pause uploads during a migration without
+// redeploying by updating the file (typically a mounted volume). The flags
+// in effect when the file does not exist are given by the server's config.
+package flags
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// Flags is the set of operational flags.
+type Flags struct {
+	// Maintenance pauses the upload endpoint, which responds with 503 Service
+	// Unavailable.
+	Maintenance bool `json:"maintenance"`
+
+	// Features holds feature flags by name. Features that are not listed are
+	// disabled.
+	Features map[string]bool `json:"features,omitempty"`
+}
+
+// Enabled reports whether the named feature is enabled.
+func (f Flags) Enabled(feature string) bool {
+	return f.Features[feature]
+}
+
+// FeatureNames returns the sorted names of the features in f, enabled or
+// not.
+func (f Flags) FeatureNames() []string {
+	var names []string
+	for name := range f.Features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// A Source provides the current flags from a file.
+type Source struct {
+	file     string
+	defaults Flags
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	flags   Flags
+	modTime time.Time // of the file from which flags were read
+	size    int64
+	err     error // error reading the file, if any
+}
+
+// NewSource returns a Source of the flags in file, or of defaults while file
+// does not exist. If file is empty, the flags are always defaults.
+func NewSource(file string, defaults Flags, logger *slog.Logger) *Source {
+	return &Source{file: file, defaults: defaults, flags: defaults, logger: logger}
+}
+
+// Flags returns the current flags, rereading the file if it has changed.
+//
+// If the file cannot be read or parsed, Flags continues to return the flags
+// last read successfully, and Err reports the error.
+func (s *Source) Flags() Flags {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == "" {
+		return s.flags
+	}
+	fi, err := os.Stat(s.file)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.flags, s.modTime, s.size, s.err = s.defaults, time.Time{}, 0, nil
+		return s.flags
+	}
+	if err == nil && fi.ModTime().Equal(s.modTime) && fi.Size() == s.size {
+		return s.flags // unchanged
+	}
+	if err == nil {
+		var flags Flags
+		flags, err = readFile(s.file)
+		if err == nil {
+			if flags.Maintenance != s.flags.Maintenance {
+				s.logger.Warn("maintenance mode changed", "maintenance", flags.Maintenance)
+			}
+			s.flags, s.modTime, s.size = flags, fi.ModTime(), fi.Size()
+		}
+	}
+	if err != nil && (s.err == nil || err.Error() != s.err.Error()) {
+		s.logger.Error("reading flags", "file", s.file, "err", err)
+	}
+	s.err = err
+	return s.flags
+}
+
+// Err returns the error from the most recent attempt to read the flags
+// file, if it failed.
+func (s *Source) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func readFile(file string) (Flags, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Flags{}, err
+	}
+	var flags Flags
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return Flags{}, fmt.Errorf("parsing %s: %v", file, err)
+	}
+	return flags, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flags
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/exp/slog"
+)
+
+func TestSource(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "flags.json")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	defaults := Flags{Maintenance: true}
+	s := NewSource(file, defaults, logger)
+
+	check := func(want Flags, wantErr bool) {
+		t.Helper()
+		if diff := cmp.Diff(want, s.Flags()); diff != "" {
+			t.Errorf("Flags() mismatch (-want +got):\n%s", diff)
+		}
+		if err := s.Err(); (err != nil) != wantErr {
+			t.Errorf("Err() = %v, want error: %t", err, wantErr)
+		}
+	}
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without a file, the defaults apply.
+	check(defaults, false)
+
+	write(`{"features": {"b": true, "a": false}}`)
+	want := Flags{Features: map[string]bool{"a": false, "b": true}}
+	check(want, false)
+	if got := s.Flags().FeatureNames(); !cmp.Equal(got, []string{"a", "b"}) {
+		t.Errorf("FeatureNames() = %q, want [a b]", got)
+	}
+	if !s.Flags().Enabled("b") || s.Flags().Enabled("a") || s.Flags().Enabled("c") {
+		t.Errorf("Enabled reports wrong values for %v", s.Flags().Features)
+	}
+
+	// Changes are picked up without a new Source.
+	write(`{"maintenance": true}`)
+	check(Flags{Maintenance: true}, false)
+
+	// A malformed file leaves the flags as they were.
+	write(`{"maintenance": false`)
+	check(Flags{Maintenance: true}, true)
+
+	write(`{"maintenance": false}`)
+	check(Flags{}, false)
+
+	// Removing the file restores the defaults.
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	check(defaults, false)
+}
+
+func TestSourceNoFile(t *testing.T) {
+	s := NewSource("", Flags{Maintenance: true}, slog.Default())
+	if !s.Flags().Maintenance {
+		t.Errorf("Flags().Maintenance = false, want the default true")
+	}
+}
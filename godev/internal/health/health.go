@@ -0,0 +1,118 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package health implements the liveness and readiness endpoints of the
+// telemetry services, for Cloud Run health checks and uptime monitoring.
+//
+// A service is live as long as it can answer requests. It is ready when the
+// dependencies it needs to do useful work, such as its storage buckets and
+// upload config, are available.
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/content"
+	"golang.org/x/telemetry/godev/internal/middleware"
+	"golang.org/x/telemetry/godev/internal/storage"
+	tconfig "golang.org/x/telemetry/internal/config"
+)
+
+// checkTimeout bounds the time taken by all the checks of a readiness
+// request.
+const checkTimeout = 5 * time.Second
+
+// A Check reports whether a dependency of the service is available.
+type Check struct {
+	Name string
+	Run  func(context.Context) error
+}
+
+// Live returns a handler that reports that the service is live.
+func Live() http.Handler {
+	return content.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return content.Text(w, "ok", http.StatusOK)
+	})
+}
+
+// Ready returns a handler that runs the given checks concurrently and
+// responds with their results as a JSON object mapping each check's name to
+// "ok" or its error. The status is 200 OK if all checks pass, and 503
+// Service Unavailable otherwise.
+func Ready(checks ...Check) http.Handler {
+	return content.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+		errs := make([]error, len(checks))
+		done := make(chan int)
+		for i, c := range checks {
+			go func() {
+				errs[i] = c.Run(ctx)
+				done <- i
+			}()
+		}
+		for range checks {
+			<-done
+		}
+		results := make(map[string]string)
+		code := http.StatusOK
+		for i, c := range checks {
+			results[c.Name] = "ok"
+			if errs[i] != nil {
+				results[c.Name] = errs[i].Error()
+				code = http.StatusServiceUnavailable
+			}
+		}
+		return content.JSON(w, results, code)
+	})
+}
+
+// Bucket returns a check that the named bucket can be listed.
+func Bucket(name string, b storage.BucketHandle) Check {
+	return Check{
+		Name: "bucket " + name,
+		Run: func(ctx context.Context) error {
+			// List a prefix that matches few objects, if any.
+			_, err := b.Objects(ctx, "healthz/").Next()
+			if errors.Is(err, storage.ErrObjectIteratorDone) {
+				return nil
+			}
+			return err
+		},
+	}
+}
+
+// Buckets returns checks for each of the buckets of s.
+func Buckets(s *storage.API) []Check {
+	return []Check{
+		Bucket("upload", s.Upload),
+		Bucket("merge", s.Merge),
+		Bucket("chart", s.Chart),
+		Bucket("stats", s.Stats),
+		Bucket("timeseries", s.TimeSeries),
+		Bucket("deadletter", s.DeadLetter),
+	}
+}
+
+// UploadConfig returns a check that the upload config at file can be read
+// and parsed.
+func UploadConfig(file string) Check {
+	return Check{
+		Name: "upload config",
+		Run: func(context.Context) error {
+			_, err := tconfig.ReadConfig(file)
+			return err
+		},
+	}
+}
+
+// Metrics returns a handler that serves a snapshot of m as JSON.
+func Metrics(m *middleware.Metrics) http.Handler {
+	return content.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return content.JSON(w, m.Snapshot(), http.StatusOK)
+	})
+}
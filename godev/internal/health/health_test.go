@@ -0,0 +1,63 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestReady(t *testing.T) {
+	ctx := context.Background()
+	bucket, err := storage.NewFSBucket(ctx, t.TempDir(), "upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := Check{"failing", func(context.Context) error { return errors.New("unavailable") }}
+	config := filepath.Join("..", "..", "..", "config", "config.json")
+
+	for _, test := range []struct {
+		name   string
+		checks []Check
+		code   int
+		want   map[string]string
+	}{
+		{"ready", []Check{Bucket("upload", bucket), UploadConfig(config)}, http.StatusOK, map[string]string{
+			"bucket upload": "ok",
+			"upload config": "ok",
+		}},
+		{"not ready", []Check{Bucket("upload", bucket), failing}, http.StatusServiceUnavailable, map[string]string{
+			"bucket upload": "ok",
+			"failing":       "unavailable",
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Ready(test.checks...).ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+			if w.Code != test.code {
+				t.Errorf("status = %d, want %d", w.Code, test.code)
+			}
+			var got map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("results = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if err := UploadConfig(filepath.Join(t.TempDir(), "missing.json")).Run(ctx); err == nil {
+		t.Errorf("UploadConfig check of a missing file succeeded")
+	}
+}
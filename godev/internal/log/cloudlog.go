@@ -5,17 +5,48 @@
 package log
 
 import (
+	"context"
 	"os"
 	"time"
 
 	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/middleware"
 )
 
+// reportedErrorEvent is the type of log entries that Error Reporting treats
+// as errors whether or not their message contains a stack trace.
+const reportedErrorEvent = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
 func NewGCPLogHandler() slog.Handler {
-	return slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+	return gcpHandler{slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
 		ReplaceAttr: gcpReplaceAttr,
 		Level:       slog.LevelDebug,
-	})
+	})}
+}
+
+// gcpHandler labels the records logged during a request with its request ID,
+// so that the logs and error reports of the request, and of its retries, can
+// be found together, and marks error records for Error Reporting.
+type gcpHandler struct {
+	slog.Handler
+}
+
+func (h gcpHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := middleware.RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.Group("logging.googleapis.com/labels", slog.String("requestID", id)))
+	}
+	if r.Level >= slog.LevelError {
+		r.AddAttrs(slog.String("@type", reportedErrorEvent))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h gcpHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return gcpHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h gcpHandler) WithGroup(name string) slog.Handler {
+	return gcpHandler{h.Handler.WithGroup(name)}
 }
 
 func gcpReplaceAttr(groups []string, a slog.Attr) slog.Attr {
@@ -0,0 +1,47 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/telemetry/godev/internal/middleware"
+)
+
+func TestGCPHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(gcpHandler{slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: gcpReplaceAttr})})
+	h := middleware.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "merging")
+		logger.ErrorContext(r.Context(), "task failed")
+	}))
+	req := httptest.NewRequest("POST", "/merge/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "merge_date_2024-01-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	type entry struct {
+		Message string
+		Labels  map[string]string `json:"logging.googleapis.com/labels"`
+		Type    string            `json:"@type"`
+	}
+	dec := json.NewDecoder(&buf)
+	for _, want := range []entry{
+		{Message: "merging", Labels: map[string]string{"requestID": "merge_date_2024-01-01"}},
+		{Message: "task failed", Labels: map[string]string{"requestID": "merge_date_2024-01-01"}, Type: reportedErrorEvent},
+	} {
+		var got entry
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Message != want.Message || got.Labels["requestID"] != want.Labels["requestID"] || got.Type != want.Type {
+			t.Errorf("log entry = %+v, want %+v", got, want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package metrics records the duration, outcome, and storage volume of the
+// tasks handled by the worker, and exports them in the Prometheus text
+// format.
+//
+// A Registry's Middleware attributes each request to an endpoint. Storage
+// wrapped with Instrument counts the objects and bytes that requests read and
+// write, and attributes them to the endpoint of the request whose context is
+// used.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/telemetry/godev/internal/middleware"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the buckets of the
+// task duration histograms. Tasks range from quick reads to merges and
+// backfills that take many minutes.
+var durationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 3600}
+
+// A Registry holds the metrics of the tasks of each endpoint, for the
+// lifetime of the process.
+type Registry struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointStats
+}
+
+// endpointStats holds the metrics of the tasks of an endpoint.
+type endpointStats struct {
+	inFlight       atomic.Int64
+	failures       atomic.Int64
+	objectsRead    atomic.Int64
+	objectsWritten atomic.Int64
+	bytesRead      atomic.Int64
+	bytesWritten   atomic.Int64
+
+	mu       sync.Mutex
+	codes    map[int]int64 // tasks by status code
+	buckets  []int64       // tasks by duration bucket, and over the last bound
+	duration float64       // total duration of tasks, in seconds
+}
+
+// NewRegistry returns a new Registry with no endpoints.
+func NewRegistry() *Registry {
+	return &Registry{endpoints: make(map[string]*endpointStats)}
+}
+
+func (r *Registry) endpoint(name string) *endpointStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.endpoints[name]
+	if s == nil {
+		s = &endpointStats{
+			codes:   make(map[int]int64),
+			buckets: make([]int64, len(durationBuckets)+1),
+		}
+		r.endpoints[name] = s
+	}
+	return s
+}
+
+type statsKey struct{}
+
+// fromContext returns the stats of the endpoint whose request ctx belongs
+// to, or nil.
+func fromContext(ctx context.Context) *endpointStats {
+	s, _ := ctx.Value(statsKey{}).(*endpointStats)
+	return s
+}
+
+// Middleware returns a Middleware that records the tasks handled by the
+// handlers it wraps as tasks of the named endpoint.
+//
+// Tasks fail if they respond with a 4xx or 5xx status, except for 429 Too
+// Many Requests, with which tasks are shed to be retried later.
+func (r *Registry) Middleware(endpoint string) middleware.Middleware {
+	s := r.endpoint(endpoint)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			s.inFlight.Add(1)
+			defer s.inFlight.Add(-1)
+			w2 := &statusRecorder{w, http.StatusOK}
+			h.ServeHTTP(w2, req.WithContext(context.WithValue(req.Context(), statsKey{}, s)))
+			s.done(w2.status, time.Since(start))
+		})
+	}
+}
+
+// done records a task that ended with the given status after d.
+func (s *endpointStats) done(status int, d time.Duration) {
+	if status >= 400 && status != http.StatusTooManyRequests {
+		s.failures.Add(1)
+	}
+	secs := d.Seconds()
+	i := sort.SearchFloat64s(durationBuckets, secs)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.codes[status]++
+	s.buckets[i]++
+	s.duration += secs
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap returns the underlying ResponseWriter, so that handlers can flush
+// streamed responses with http.ResponseController.
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Handler returns a handler that serves the metrics of r in the Prometheus
+// text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w)
+	})
+}
+
+// WriteTo writes the metrics of r to w in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	var names []string
+	for name := range r.endpoints {
+		names = append(names, name)
+	}
+	r.mu.Unlock()
+	sort.Strings(names)
+
+	p := &printer{w: w}
+	p.header("worker_tasks_total", "counter", "Tasks handled, by endpoint and status code.")
+	for _, name := range names {
+		s := r.endpoint(name)
+		s.mu.Lock()
+		var codes []int
+		for code := range s.codes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			p.printf("worker_tasks_total{endpoint=%q,code=\"%d\"} %d\n", name, code, s.codes[code])
+		}
+		s.mu.Unlock()
+	}
+	p.counters(names, r, "worker_task_failures_total", "Tasks that failed, by endpoint.",
+		func(s *endpointStats) *atomic.Int64 { return &s.failures })
+	p.header("worker_tasks_in_flight", "gauge", "Tasks being handled, by endpoint.")
+	for _, name := range names {
+		p.printf("worker_tasks_in_flight{endpoint=%q} %d\n", name, r.endpoint(name).inFlight.Load())
+	}
+
+	p.header("worker_task_duration_seconds", "histogram", "Duration of tasks, by endpoint.")
+	for _, name := range names {
+		s := r.endpoint(name)
+		s.mu.Lock()
+		var n int64
+		for i, le := range durationBuckets {
+			n += s.buckets[i]
+			p.printf("worker_task_duration_seconds_bucket{endpoint=%q,le=%q} %d\n", name, strconv.FormatFloat(le, 'g', -1, 64), n)
+		}
+		n += s.buckets[len(durationBuckets)]
+		p.printf("worker_task_duration_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", name, n)
+		p.printf("worker_task_duration_seconds_sum{endpoint=%q} %g\n", name, s.duration)
+		p.printf("worker_task_duration_seconds_count{endpoint=%q} %d\n", name, n)
+		s.mu.Unlock()
+	}
+
+	p.counters(names, r, "worker_objects_read_total", "Storage objects opened for reading, by endpoint.",
+		func(s *endpointStats) *atomic.Int64 { return &s.objectsRead })
+	p.counters(names, r, "worker_objects_written_total", "Storage objects written, by endpoint.",
+		func(s *endpointStats) *atomic.Int64 { return &s.objectsWritten })
+	p.counters(names, r, "worker_bytes_read_total", "Bytes read from storage objects, by endpoint.",
+		func(s *endpointStats) *atomic.Int64 { return &s.bytesRead })
+	p.counters(names, r, "worker_bytes_written_total", "Bytes written to storage objects, by endpoint.",
+		func(s *endpointStats) *atomic.Int64 { return &s.bytesWritten })
+	return p.n, p.err
+}
+
+// A printer writes metrics, and records the number of bytes written and the
+// first error.
+type printer struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (p *printer) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(p.w, format, args...)
+	p.n += int64(n)
+	p.err = err
+}
+
+func (p *printer) header(name, typ, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// counters writes a counter of each endpoint.
+func (p *printer) counters(names []string, r *Registry, name, help string, counter func(*endpointStats) *atomic.Int64) {
+	p.header(name, "counter", help)
+	for _, endpoint := range names {
+		p.printf("%s{endpoint=%q} %d\n", name, endpoint, counter(r.endpoint(endpoint)).Load())
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	fs, err := storage.NewFSBucket(ctx, t.TempDir(), "merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bucket := Bucket(fs)
+	reg := NewRegistry()
+
+	// The copy handler reads one object and writes it to another.
+	copyHandler := reg.Middleware("copy")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			http.Error(w, "failed", http.StatusInternalServerError)
+			return
+		}
+		if err := storage.Copy(r.Context(), bucket.Object("dst"), bucket.Object("src")); err != nil {
+			t.Error(err)
+		}
+	}))
+	shed := reg.Middleware("merge")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "busy", http.StatusTooManyRequests)
+	}))
+
+	// Objects written outside of requests are not counted.
+	w, err := bucket.Object("src").NewWriter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, url := range []string{"/copy/", "/copy/", "/copy/?fail=1"} {
+		copyHandler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", url, nil))
+	}
+	shed.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/merge/", nil))
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	got := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE worker_tasks_total counter\n",
+		`worker_tasks_total{endpoint="copy",code="200"} 2` + "\n",
+		`worker_tasks_total{endpoint="copy",code="500"} 1` + "\n",
+		`worker_tasks_total{endpoint="merge",code="429"} 1` + "\n",
+		`worker_task_failures_total{endpoint="copy"} 1` + "\n",
+		`worker_task_failures_total{endpoint="merge"} 0` + "\n",
+		`worker_tasks_in_flight{endpoint="copy"} 0` + "\n",
+		`worker_task_duration_seconds_bucket{endpoint="copy",le="+Inf"} 3` + "\n",
+		`worker_task_duration_seconds_count{endpoint="copy"} 3` + "\n",
+		`worker_objects_read_total{endpoint="copy"} 2` + "\n",
+		`worker_objects_written_total{endpoint="copy"} 2` + "\n",
+		`worker_bytes_read_total{endpoint="copy"} 10` + "\n",
+		`worker_bytes_written_total{endpoint="copy"} 10` + "\n",
+		`worker_bytes_written_total{endpoint="merge"} 0` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("metrics do not contain %q; got:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/telemetry/godev/internal/storage"
+)
+
+// Instrument returns a copy of s whose buckets count the objects and bytes
+// read and written in the context of requests handled by a Registry's
+// Middleware.
+func Instrument(s *storage.API) *storage.API {
+	return &storage.API{
+		Upload:     Bucket(s.Upload),
+		Merge:      Bucket(s.Merge),
+		Chart:      Bucket(s.Chart),
+		Stats:      Bucket(s.Stats),
+		TimeSeries: Bucket(s.TimeSeries),
+		DeadLetter: Bucket(s.DeadLetter),
+	}
+}
+
+// Bucket returns a bucket that counts the objects and bytes of b read and
+// written in the context of requests handled by a Registry's Middleware.
+func Bucket(b storage.BucketHandle) storage.BucketHandle {
+	return bucket{b}
+}
+
+type bucket struct {
+	storage.BucketHandle
+}
+
+func (b bucket) Object(name string) storage.ObjectHandle {
+	return object{b.BucketHandle.Object(name)}
+}
+
+type object struct {
+	storage.ObjectHandle
+}
+
+// Unwrap returns the uninstrumented object, so that storage.Copy can copy
+// objects within Cloud Storage. Such copies are not counted.
+func (o object) Unwrap() storage.ObjectHandle {
+	return o.ObjectHandle
+}
+
+func (o object) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	r, err := o.ObjectHandle.NewReader(ctx)
+	s := fromContext(ctx)
+	if err != nil || s == nil {
+		return r, err
+	}
+	s.objectsRead.Add(1)
+	return &countingReader{r, s}, nil
+}
+
+func (o object) NewWriter(ctx context.Context) (io.WriteCloser, error) {
+	w, err := o.ObjectHandle.NewWriter(ctx)
+	return countWriter(ctx, w, err)
+}
+
+func (o object) NewWriterIf(ctx context.Context, gen int64) (io.WriteCloser, error) {
+	w, err := o.ObjectHandle.NewWriterIf(ctx, gen)
+	return countWriter(ctx, w, err)
+}
+
+func (o object) NewWriterWithMetadata(ctx context.Context, gen int64, md map[string]string) (io.WriteCloser, error) {
+	w, err := o.ObjectHandle.NewWriterWithMetadata(ctx, gen, md)
+	return countWriter(ctx, w, err)
+}
+
+func countWriter(ctx context.Context, w io.WriteCloser, err error) (io.WriteCloser, error) {
+	s := fromContext(ctx)
+	if err != nil || s == nil {
+		return w, err
+	}
+	return &countingWriter{w: w, s: s}, nil
+}
+
+type countingReader struct {
+	io.ReadCloser
+	s *endpointStats
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.s.bytesRead.Add(int64(n))
+	return n, err
+}
+
+// A countingWriter counts the bytes written, and the object once it is
+// committed by a successful Close.
+type countingWriter struct {
+	w      io.WriteCloser
+	s      *endpointStats
+	closed bool
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.s.bytesWritten.Add(int64(n))
+	return n, err
+}
+
+func (w *countingWriter) Close() error {
+	err := w.w.Close()
+	if err == nil && !w.closed {
+		w.s.objectsWritten.Add(1)
+	}
+	w.closed = true
+	return err
+}
@@ -7,9 +7,18 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/exp/slog"
@@ -34,7 +43,145 @@ func Chain(middlewares ...Middleware) Middleware {
 	}
 }
 
-// Log is a middleware that logs request start, end, duration, and status.
+// RequestIDHeader is the header that carries the ID of a request.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLen bounds the length of request IDs accepted from clients.
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// RequestID is a middleware that identifies each request by the value of
+// its X-Request-Id header, or, if it has none, by a new random ID. The ID is
+// stored in the request context, where RequestIDFromContext finds it, and
+// echoed in the X-Request-Id header of the response.
+//
+// Requests that carry the same ID, such as the retries of a Cloud Tasks task
+// created with the header, can then be correlated in the logs.
+func RequestID() Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" || len(id) > maxRequestIDLen {
+				id = randomID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID stored in ctx by the RequestID
+// middleware, or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// randomID returns a new random ID, such as a request ID or CSRF token.
+func randomID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// SecurityHeaders returns a Middleware that sets standard security headers
+// on the responses of the handlers it wraps: X-Content-Type-Options, so that
+// browsers do not guess content types, Referrer-Policy, so that only the
+// origin of the site is sent to other sites, and, unless csp is empty,
+// Content-Security-Policy.
+func SecurityHeaders(csp string) Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hdr := w.Header()
+			hdr.Set("X-Content-Type-Options", "nosniff")
+			hdr.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			if csp != "" {
+				hdr.Set("Content-Security-Policy", csp)
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CSRFHeader is the header in which clients echo their CSRF token.
+const CSRFHeader = "X-CSRF-Token"
+
+// csrfCookie is the name of the cookie that holds a client's CSRF token, and
+// of the form field in which forms echo it.
+const csrfCookie = "csrf_token"
+
+// CSRF returns a Middleware that protects the handlers it wraps from
+// cross-site request forgery with double-submit tokens. Requests with methods
+// other than GET, HEAD, OPTIONS, and TRACE must echo the token of the
+// client's CSRF cookie, as set by CSRFToken, in the X-CSRF-Token header or
+// the csrf_token form field, or they are rejected with 403 Forbidden. Other
+// sites can make a browser send the cookie but cannot read it.
+//
+// Requests whose path has one of the exempt prefixes, such as endpoints for
+// non-browser clients, are not checked, nor are requests that carry an
+// Authorization header, which browsers do not add to cross-site requests.
+func CSRF(exempt ...string) Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET", "HEAD", "OPTIONS", "TRACE":
+				h.ServeHTTP(w, r)
+				return
+			}
+			if r.Header.Get("Authorization") != "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+			for _, prefix := range exempt {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					h.ServeHTTP(w, r)
+					return
+				}
+			}
+			c, err := r.Cookie(csrfCookie)
+			if err != nil || c.Value == "" {
+				http.Error(w, "missing CSRF cookie", http.StatusForbidden)
+				return
+			}
+			token := r.Header.Get(CSRFHeader)
+			if token == "" {
+				token = r.PostFormValue(csrfCookie)
+			}
+			if subtle.ConstantTimeCompare([]byte(token), []byte(c.Value)) != 1 {
+				http.Error(w, "invalid CSRF token", http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CSRFToken returns the CSRF token of the client of r, to be echoed by its
+// state-changing requests to handlers wrapped by CSRF. If the client has no
+// token, CSRFToken creates one and sets it in a cookie of the response w, so
+// it must be called before the response is written. Pages that carry the
+// token, such as forms, must not be cached by shared caches.
+func CSRFToken(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(csrfCookie); err == nil && c.Value != "" {
+		return c.Value
+	}
+	token := randomID()
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    token,
+		Path:     "/",
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteStrictMode,
+	})
+	return token
+}
+
+// Log is a middleware that logs request start, end, duration, and status,
+// along with the request ID, if any.
 func Log(logger *slog.Logger) Middleware {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -45,6 +192,9 @@ func Log(logger *slog.Logger) Middleware {
 				slog.String("uri", r.RequestURI),
 				// TODO(hyangah): set trace context from X-Cloud-Trace-Context
 			)
+			if id := RequestIDFromContext(ctx); id != "" {
+				l = l.With(slog.String("requestID", id))
+			}
 			l.InfoContext(ctx, "request start")
 			w2 := &statusRecorder{w, 200}
 			h.ServeHTTP(w2, r)
@@ -74,7 +224,7 @@ func Recover() Middleware {
 			defer func() {
 				if err := recover(); err != nil {
 					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-					slog.Error(r.RequestURI, fmt.Errorf(`panic("%s")`, err))
+					slog.ErrorContext(r.Context(), r.RequestURI, "error", fmt.Errorf(`panic("%s")`, err))
 					fmt.Println(string(debug.Stack()))
 				}
 			}()
@@ -93,6 +243,352 @@ func RequestSize(n int64) Middleware {
 	}
 }
 
+// ConcurrencyLimit returns a Middleware that allows at most n requests to be
+// handled at once by the handlers it wraps. Requests arriving while n others
+// are in flight are shed with 429 Too Many Requests and a Retry-After header
+// of retryAfter, rounded up to whole seconds, which Cloud Tasks honors when
+// scheduling its next attempt.
+//
+// All handlers wrapped by the same ConcurrencyLimit share its limit. A
+// non-positive n disables the limit.
+func ConcurrencyLimit(n int, retryAfter time.Duration) Middleware {
+	if n <= 0 {
+		return func(h http.Handler) http.Handler { return h }
+	}
+	sem := make(chan struct{}, n)
+	retrySecs := strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+				h.ServeHTTP(w, r)
+			default:
+				w.Header().Set("Retry-After", retrySecs)
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			}
+		})
+	}
+}
+
+// Maintenance returns a Middleware that, while paused reports true, responds
+// to all requests with 503 Service Unavailable and a Retry-After header of
+// retryAfter, rounded up to whole seconds, instead of calling the handlers it
+// wraps.
+func Maintenance(paused func() bool, retryAfter time.Duration) Middleware {
+	retrySecs := strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if paused() {
+				w.Header().Set("Retry-After", retrySecs)
+				http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Metrics counts the requests handled by the handlers wrapped by its
+// Middleware, and records the requests of each route wrapped by its
+// Instrument middlewares. The counts cover the lifetime of the process.
+type Metrics struct {
+	start    time.Time
+	inFlight atomic.Int64
+	requests atomic.Int64
+	statuses [6]atomic.Int64 // by status class: 1xx through 5xx, and unknown
+
+	mu     sync.Mutex
+	routes map[string]*routeStats
+}
+
+// latencyBuckets are the upper bounds of the buckets of the latency
+// histograms of routes. Most pages are served from storage in milliseconds,
+// but charts of date ranges may be aggregated on demand.
+var latencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	25 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	30 * time.Second,
+}
+
+// routeStats holds the counts of the requests of a route.
+type routeStats struct {
+	requests atomic.Int64
+	statuses [6]atomic.Int64 // as in Metrics
+	latency  []atomic.Int64  // by bucket of latencyBuckets, and over the last bound
+	total    atomic.Int64    // latency of all requests, in nanoseconds
+}
+
+// MetricsSnapshot is a snapshot of the counts of a Metrics.
+type MetricsSnapshot struct {
+	Uptime   string
+	InFlight int64            // requests being handled
+	Requests int64            // requests handled
+	Statuses map[string]int64 // requests handled by status class, such as "2xx"
+	Routes   map[string]RouteSnapshot
+}
+
+// RouteSnapshot is a snapshot of the counts of the requests of a route.
+type RouteSnapshot struct {
+	Requests    int64            // requests handled
+	Statuses    map[string]int64 // requests handled by status class
+	MeanLatency string
+	Latency     []LatencyBucket // histogram of request latencies
+}
+
+// A LatencyBucket is a bucket of a latency histogram: the number of requests
+// that took at most LE, and longer than the LE of the previous bucket.
+type LatencyBucket struct {
+	LE    string // a duration, or "+Inf"
+	Count int64
+}
+
+// NewMetrics returns a new Metrics with all counts zero.
+func NewMetrics() *Metrics {
+	return &Metrics{start: time.Now(), routes: make(map[string]*routeStats)}
+}
+
+// Middleware returns a Middleware that counts the requests handled by the
+// handlers it wraps.
+func (m *Metrics) Middleware() Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.inFlight.Add(1)
+			defer m.inFlight.Add(-1)
+			w2 := &statusRecorder{w, 200}
+			h.ServeHTTP(w2, r)
+			m.requests.Add(1)
+			m.statuses[statusClass(w2.status)].Add(1)
+		})
+	}
+}
+
+// Instrument returns a Middleware that records the requests handled by the
+// handlers it wraps as requests of the named route: their number, status
+// classes, and latencies.
+func (m *Metrics) Instrument(route string) Middleware {
+	m.mu.Lock()
+	s := m.routes[route]
+	if s == nil {
+		s = &routeStats{latency: make([]atomic.Int64, len(latencyBuckets)+1)}
+		m.routes[route] = s
+	}
+	m.mu.Unlock()
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			w2 := &statusRecorder{w, 200}
+			h.ServeHTTP(w2, r)
+			d := time.Since(start)
+			s.requests.Add(1)
+			s.statuses[statusClass(w2.status)].Add(1)
+			i := sort.Search(len(latencyBuckets), func(i int) bool { return d <= latencyBuckets[i] })
+			s.latency[i].Add(1)
+			s.total.Add(int64(d))
+		})
+	}
+}
+
+// statusClass returns the index in a statuses array of the status code.
+func statusClass(code int) int {
+	class := code / 100
+	if class < 1 || class > 5 {
+		class = 0
+	}
+	return class
+}
+
+// snapshotStatuses returns the nonzero counts of statuses by class name.
+func snapshotStatuses(statuses *[6]atomic.Int64) map[string]int64 {
+	m := make(map[string]int64)
+	for class := range statuses {
+		if n := statuses[class].Load(); n > 0 {
+			name := "unknown"
+			if class > 0 {
+				name = strconv.Itoa(class) + "xx"
+			}
+			m[name] = n
+		}
+	}
+	return m
+}
+
+// Snapshot returns the current counts of m.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	s := MetricsSnapshot{
+		Uptime:   time.Since(m.start).Round(time.Second).String(),
+		InFlight: m.inFlight.Load(),
+		Requests: m.requests.Load(),
+		Statuses: snapshotStatuses(&m.statuses),
+		Routes:   make(map[string]RouteSnapshot),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, rs := range m.routes {
+		r := RouteSnapshot{
+			Requests: rs.requests.Load(),
+			Statuses: snapshotStatuses(&rs.statuses),
+		}
+		if r.Requests > 0 {
+			r.MeanLatency = (time.Duration(rs.total.Load()) / time.Duration(r.Requests)).String()
+		}
+		for i := range rs.latency {
+			le := "+Inf"
+			if i < len(latencyBuckets) {
+				le = latencyBuckets[i].String()
+			}
+			r.Latency = append(r.Latency, LatencyBucket{le, rs.latency[i].Load()})
+		}
+		s.Routes[name] = r
+	}
+	return s
+}
+
+// A Rate is the rate at which a RateLimit admits requests: on average, N
+// requests per Per, in bursts of up to N requests.
+type Rate struct {
+	N   int
+	Per time.Duration
+}
+
+// maxRateClients bounds the memory used by a RateLimit to track clients.
+// Once it is reached, clients that are not tracked share a single bucket
+// until a sweep forgets the clients whose buckets have refilled.
+const maxRateClients = 100_000
+
+// RateLimit returns a Middleware that limits the rate of requests to the
+// handlers it wraps with token buckets: one for each client, identified by
+// key(r), that refills at perClient, and one shared by all clients that
+// refills at global. Requests over either limit are shed with 503 Service
+// Unavailable and a Retry-After header of the time until the client's next
+// request would be admitted, rounded up to whole seconds. Unlike 429 Too
+// Many Requests, which uploaders released before they honored it treat as
+// a rejection of the report, 503 causes the request to be retried later.
+//
+// All handlers wrapped by the same RateLimit share its limits. A Rate with
+// a non-positive N disables the corresponding limit.
+func RateLimit(key func(*http.Request) string, perClient, global Rate) Middleware {
+	return rateLimit(key, perClient, global, time.Now)
+}
+
+func rateLimit(key func(*http.Request) string, perClient, global Rate, now func() time.Time) Middleware {
+	l := &rateLimiter{
+		now:        now,
+		perClient:  perClient,
+		global:     global,
+		maxClients: maxRateClients,
+		all:        bucket{tokens: float64(global.N)},
+		overflow:   bucket{tokens: float64(perClient.N)},
+		clients:    make(map[string]*bucket),
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if wait := l.admit(key(r)); wait > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+				http.Error(w, "rate limit exceeded", http.StatusServiceUnavailable)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+type rateLimiter struct {
+	now               func() time.Time
+	perClient, global Rate
+	maxClients        int // maximum len(clients)
+
+	mu       sync.Mutex
+	all      bucket             // shared by all clients
+	overflow bucket             // shared by the clients not in clients
+	clients  map[string]*bucket // by key
+	swept    time.Time          // when clients was last swept
+}
+
+// A bucket is a token bucket: requests take a token, and tokens are added
+// at a steady rate up to a limit.
+type bucket struct {
+	tokens float64
+	last   time.Time // when tokens was last updated
+}
+
+// refill adds the tokens earned at rate since b.last, and reports whether
+// the bucket is then full.
+func (b *bucket) refill(rate Rate, now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens += float64(rate.N) * float64(now.Sub(b.last)) / float64(rate.Per)
+	}
+	b.last = now
+	if b.tokens >= float64(rate.N) {
+		b.tokens = float64(rate.N)
+		return true
+	}
+	return false
+}
+
+// wait returns how long until the bucket has a token.
+func (b *bucket) wait(rate Rate) time.Duration {
+	return time.Duration((1 - b.tokens) * float64(rate.Per) / float64(rate.N))
+}
+
+// admit takes a token from the global bucket and the bucket for the client
+// with the given key, and returns 0, or, if either is empty, returns how
+// long until both have a token and takes none.
+func (l *rateLimiter) admit(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+
+	var client *bucket
+	if l.perClient.N > 0 {
+		// A bucket left alone for perClient.Per is full, so sweeping
+		// no more often than that bounds the cost of sweeps to a
+		// fraction of the cost of the requests that filled the map.
+		if now.Sub(l.swept) >= l.perClient.Per {
+			for k, b := range l.clients {
+				if b.refill(l.perClient, now) {
+					delete(l.clients, k)
+				}
+			}
+			l.swept = now
+		}
+		client = l.clients[key]
+		if client == nil {
+			if len(l.clients) < l.maxClients {
+				client = &bucket{tokens: float64(l.perClient.N)}
+				l.clients[key] = client
+			} else {
+				client = &l.overflow
+			}
+		}
+		client.refill(l.perClient, now)
+	}
+	var wait time.Duration
+	if client != nil && client.tokens < 1 {
+		wait = client.wait(l.perClient)
+	}
+	if l.global.N > 0 {
+		l.all.refill(l.global, now)
+		if l.all.tokens < 1 {
+			wait = max(wait, l.all.wait(l.global))
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+	if client != nil {
+		client.tokens--
+	}
+	if l.global.N > 0 {
+		l.all.tokens--
+	}
+	return 0
+}
+
 type statusRecorder struct {
 	http.ResponseWriter
 	status int
@@ -103,6 +599,12 @@ func (rec *statusRecorder) WriteHeader(code int) {
 	rec.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap returns the underlying ResponseWriter, so that handlers can flush
+// streamed responses with http.ResponseController.
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
 // Timeout returns a new Middleware that times out each request after the given
 // duration.
 func Timeout(d time.Duration) Middleware {
@@ -0,0 +1,370 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	var (
+		entered = make(chan struct{})
+		release = make(chan struct{})
+	)
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+	})
+	limit := ConcurrencyLimit(1, 1500*time.Millisecond)
+	h1, h2 := limit(blocking), limit(http.NotFoundHandler())
+
+	done := make(chan int)
+	go func() {
+		w := httptest.NewRecorder()
+		h1.ServeHTTP(w, httptest.NewRequest("GET", "/chart/", nil))
+		done <- w.Code
+	}()
+	<-entered
+
+	// The limit is shared by all handlers it wraps.
+	w := httptest.NewRecorder()
+	h2.ServeHTTP(w, httptest.NewRequest("GET", "/newcounters/", nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("request over the limit: status %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got, want := w.Header().Get("Retry-After"), "2"; got != want {
+		t.Errorf("Retry-After = %q, want %q", got, want)
+	}
+
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("request within the limit: status %d, want %d", code, http.StatusOK)
+	}
+
+	// Once the first request completes, there is room for another.
+	w = httptest.NewRecorder()
+	h2.ServeHTTP(w, httptest.NewRequest("GET", "/newcounters/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("request after release: status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestMaintenance(t *testing.T) {
+	paused := true
+	h := Maintenance(func() bool { return paused }, 90*time.Second)(http.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/upload/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("paused: status %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if got, want := w.Header().Get("Retry-After"), "90"; got != want {
+		t.Errorf("paused: Retry-After = %q, want %q", got, want)
+	}
+
+	paused = false
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/upload/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("not paused: status %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if got := w.Header().Get("Retry-After"); got != "" {
+		t.Errorf("not paused: Retry-After = %q, want none", got)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := func(r *http.Request) string { return r.RemoteAddr }
+	clock := func() time.Time { return now }
+	h := rateLimit(key, Rate{N: 2, Per: time.Minute}, Rate{N: 3, Per: time.Minute}, clock)(http.NotFoundHandler())
+
+	get := func(addr string) (int, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/upload/", nil)
+		r.RemoteAddr = addr
+		h.ServeHTTP(w, r)
+		return w.Code, w.Header().Get("Retry-After")
+	}
+
+	for i := 0; i < 2; i++ {
+		if code, _ := get("192.0.2.1"); code != http.StatusNotFound {
+			t.Fatalf("request %d within the client's burst: status %d", i, code)
+		}
+	}
+	if code, retry := get("192.0.2.1"); code != http.StatusServiceUnavailable || retry != "30" {
+		t.Errorf("request over the client's limit: status %d, Retry-After %q; want %d, %q", code, retry, http.StatusServiceUnavailable, "30")
+	}
+	// Another client has its own bucket, but shares the global limit.
+	if code, _ := get("192.0.2.2"); code != http.StatusNotFound {
+		t.Errorf("request from another client: status %d, want %d", code, http.StatusNotFound)
+	}
+	if code, _ := get("192.0.2.3"); code != http.StatusServiceUnavailable {
+		t.Errorf("request over the global limit: status %d, want %d", code, http.StatusServiceUnavailable)
+	}
+
+	// Buckets refill over time.
+	now = now.Add(30 * time.Second)
+	if code, _ := get("192.0.2.1"); code != http.StatusNotFound {
+		t.Errorf("request after refill: status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestRateLimitMaxClients(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := &rateLimiter{
+		now:        func() time.Time { return now },
+		perClient:  Rate{N: 1, Per: time.Minute},
+		maxClients: 2,
+		overflow:   bucket{tokens: 1},
+		clients:    make(map[string]*bucket),
+	}
+	for _, key := range []string{"a", "b"} {
+		if wait := l.admit(key); wait != 0 {
+			t.Errorf("admit(%q) = %v, want 0", key, wait)
+		}
+	}
+	// Once the map is full, new clients share the overflow bucket.
+	if wait := l.admit("c"); wait != 0 {
+		t.Errorf("admit(%q) with a full map = %v, want 0", "c", wait)
+	}
+	if wait := l.admit("d"); wait == 0 {
+		t.Errorf("admit(%q) with an empty overflow bucket = 0, want > 0", "d")
+	}
+	if len(l.clients) != 2 {
+		t.Errorf("tracked %d clients, want 2", len(l.clients))
+	}
+
+	// The sweep forgets the clients whose buckets have refilled.
+	now = now.Add(time.Minute)
+	if wait := l.admit("d"); wait != 0 {
+		t.Errorf("admit(%q) after a sweep = %v, want 0", "d", wait)
+	}
+	if _, ok := l.clients["d"]; !ok || len(l.clients) != 1 {
+		t.Errorf("after a sweep, tracked clients = %v, want only %q", l.clients, "d")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	h := m.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	for _, p := range []string{"/", "/", "/missing"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil))
+	}
+	s := m.Snapshot()
+	if s.Requests != 3 || s.InFlight != 0 {
+		t.Errorf("Snapshot() = %d requests, %d in flight; want 3, 0", s.Requests, s.InFlight)
+	}
+	if s.Statuses["2xx"] != 2 || s.Statuses["4xx"] != 1 || len(s.Statuses) != 2 {
+		t.Errorf("Snapshot().Statuses = %v, want 2 2xx and 1 4xx", s.Statuses)
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	m := NewMetrics()
+	release := make(chan struct{})
+	slow := m.Instrument("slow")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	fast := m.Instrument("fast")(http.NotFoundHandler())
+	done := make(chan struct{})
+	go func() {
+		slow.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/slow", nil))
+		close(done)
+	}()
+	time.Sleep(300 * time.Millisecond)
+	close(release)
+	<-done
+	for i := 0; i < 2; i++ {
+		fast.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fast", nil))
+	}
+
+	s := m.Snapshot()
+	if len(s.Routes) != 2 {
+		t.Fatalf("Snapshot().Routes = %v, want routes fast and slow", s.Routes)
+	}
+	// Instrument does not count requests in the process-wide totals,
+	// which are counted by Middleware.
+	if s.Requests != 0 {
+		t.Errorf("Snapshot().Requests = %d, want 0", s.Requests)
+	}
+	fs := s.Routes["fast"]
+	if fs.Requests != 2 || fs.Statuses["4xx"] != 2 || len(fs.Statuses) != 1 {
+		t.Errorf("fast route: %d requests, statuses %v; want 2 requests, 2 4xx", fs.Requests, fs.Statuses)
+	}
+	ss := s.Routes["slow"]
+	if ss.Requests != 1 || ss.Statuses["2xx"] != 1 {
+		t.Errorf("slow route: %d requests, statuses %v; want 1 request, 1 2xx", ss.Requests, ss.Statuses)
+	}
+	if len(ss.Latency) != len(latencyBuckets)+1 || ss.Latency[len(ss.Latency)-1].LE != "+Inf" {
+		t.Fatalf("slow route latency = %v, want %d buckets ending in +Inf", ss.Latency, len(latencyBuckets)+1)
+	}
+	// The slow request took over 250ms.
+	var n int64
+	for i, b := range ss.Latency {
+		if i < len(latencyBuckets) && latencyBuckets[i] <= 250*time.Millisecond && b.Count != 0 {
+			t.Errorf("slow route: %d requests in bucket le=%s, want 0", b.Count, b.LE)
+		}
+		n += b.Count
+	}
+	if n != 1 {
+		t.Errorf("slow route: %d requests in latency histogram, want 1", n)
+	}
+	if d, err := time.ParseDuration(ss.MeanLatency); err != nil || d < 300*time.Millisecond {
+		t.Errorf("slow route: MeanLatency = %q, want at least 300ms", ss.MeanLatency)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var got string
+	h := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = RequestIDFromContext(r.Context())
+	}))
+
+	// An incoming ID is propagated.
+	req := httptest.NewRequest("POST", "/merge/", nil)
+	req.Header.Set(RequestIDHeader, "merge_date_2024-01-01")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if got != "merge_date_2024-01-01" {
+		t.Errorf("RequestIDFromContext = %q, want %q", got, "merge_date_2024-01-01")
+	}
+	if id := w.Header().Get(RequestIDHeader); id != got {
+		t.Errorf("response %s = %q, want %q", RequestIDHeader, id, got)
+	}
+
+	// Otherwise, each request gets a new ID.
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		if got == "" || seen[got] {
+			t.Errorf("request %d: RequestIDFromContext = %q, want a new ID", i, got)
+		}
+		seen[got] = true
+		if id := w.Header().Get(RequestIDHeader); id != got {
+			t.Errorf("request %d: response %s = %q, want %q", i, RequestIDHeader, id, got)
+		}
+	}
+
+	if id := RequestIDFromContext(context.Background()); id != "" {
+		t.Errorf("RequestIDFromContext(context.Background()) = %q, want none", id)
+	}
+}
+
+func TestLogRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	h := Chain(RequestID(), Log(logger))(http.NotFoundHandler())
+	req := httptest.NewRequest("GET", "/missing", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d log records, want 2:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "requestID=abc123") {
+			t.Errorf("log record missing request ID: %s", line)
+		}
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	for _, csp := range []string{"default-src 'self'", ""} {
+		w := httptest.NewRecorder()
+		SecurityHeaders(csp)(http.NotFoundHandler()).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		for _, h := range []struct{ name, want string }{
+			{"X-Content-Type-Options", "nosniff"},
+			{"Referrer-Policy", "strict-origin-when-cross-origin"},
+			{"Content-Security-Policy", csp},
+		} {
+			if got := w.Header().Get(h.name); got != h.want {
+				t.Errorf("SecurityHeaders(%q): %s = %q, want %q", csp, h.name, got, h.want)
+			}
+		}
+	}
+}
+
+func TestCSRF(t *testing.T) {
+	// Get a token, as a page with a form would.
+	w := httptest.NewRecorder()
+	token := CSRFToken(w, httptest.NewRequest("GET", "/ops", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != token {
+		t.Fatalf("CSRFToken set cookies %v, want one with token %q", cookies, token)
+	}
+	cookie := cookies[0]
+
+	// A client that has a token keeps it.
+	r := httptest.NewRequest("GET", "/ops", nil)
+	r.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	if got := CSRFToken(w, r); got != token || len(w.Result().Cookies()) != 0 {
+		t.Errorf("CSRFToken with cookie = %q and set cookies %v, want %q and none", got, w.Result().Cookies(), token)
+	}
+
+	h := CSRF("/upload/")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, test := range []struct {
+		name   string
+		method string
+		path   string
+		cookie bool
+		header string // X-CSRF-Token
+		form   string // csrf_token field
+		auth   string
+		want   int
+	}{
+		{"GET", "GET", "/ops", false, "", "", "", http.StatusOK},
+		{"no token", "POST", "/ops", false, "", "", "", http.StatusForbidden},
+		{"cookie only", "POST", "/ops", true, "", "", "", http.StatusForbidden},
+		{"header only", "POST", "/ops", false, token, "", "", http.StatusForbidden},
+		{"wrong header", "POST", "/ops", true, "x" + token, "", "", http.StatusForbidden},
+		{"header", "POST", "/ops", true, token, "", "", http.StatusOK},
+		{"form", "POST", "/ops", true, "", token, "", http.StatusOK},
+		{"wrong form", "DELETE", "/ops", true, "", "x", "", http.StatusForbidden},
+		{"exempt", "POST", "/upload/2024-01-01/0.1.json", false, "", "", "", http.StatusOK},
+		{"bearer", "POST", "/admin/reload-config", false, "", "", "Bearer secret", http.StatusOK},
+	} {
+		var body io.Reader
+		if test.form != "" {
+			body = strings.NewReader(url.Values{"csrf_token": {test.form}}.Encode())
+		}
+		r := httptest.NewRequest(test.method, test.path, body)
+		if test.form != "" {
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		if test.cookie {
+			r.AddCookie(cookie)
+		}
+		if test.header != "" {
+			r.Header.Set(CSRFHeader, test.header)
+		}
+		if test.auth != "" {
+			r.Header.Set("Authorization", test.auth)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != test.want {
+			t.Errorf("%s: status %d, want %d", test.name, w.Code, test.want)
+		}
+	}
+}
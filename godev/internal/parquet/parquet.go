@@ -0,0 +1,357 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parquet writes tables in the Apache Parquet format, which data
+// analysis tools read far more efficiently than JSON.
+//
+// Only what the worker needs is supported: flat schemas of required
+// columns of strings, 64-bit integers and doubles. Values are written with
+// the PLAIN encoding, in a single gzip-compressed page per column of each
+// row group.
+//
+// See https://parquet.apache.org/docs/file-format/ for the format.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// A Type is the type of the values of a column.
+type Type int
+
+const (
+	String Type = iota // string values, stored as UTF-8 byte arrays
+	Int64              // int64 values
+	Double             // float64 values
+)
+
+// A Column describes a column of a table.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// rowGroupRows is the number of rows of a row group. A row group is
+// buffered in memory until it is written.
+const rowGroupRows = 1 << 16
+
+// magic starts and ends a Parquet file.
+const magic = "PAR1"
+
+// A Writer writes the rows of a table to a Parquet file.
+type Writer struct {
+	w       io.Writer
+	off     int64 // number of bytes written to w
+	err     error // first error writing to w
+	columns []Column
+
+	values []bytes.Buffer // encoded values of the current row group, by column
+	rows   int            // rows of the current row group
+	groups []rowGroup     // written row groups
+}
+
+// A rowGroup records where the column chunks of a row group were written.
+type rowGroup struct {
+	rows   int64
+	chunks []columnChunk
+}
+
+type columnChunk struct {
+	offset       int64 // of the page header
+	compressed   int64 // size of the chunk, including the page header
+	uncompressed int64 // size of the chunk before compression
+}
+
+// NewWriter returns a Writer that writes a table with the given columns to
+// w. The file is complete once Close returns.
+func NewWriter(w io.Writer, columns []Column) *Writer {
+	pw := &Writer{
+		w:       w,
+		columns: columns,
+		values:  make([]bytes.Buffer, len(columns)),
+	}
+	pw.write([]byte(magic))
+	return pw
+}
+
+func (w *Writer) write(data []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(data)
+	w.off += int64(n)
+	w.err = err
+}
+
+// Write adds a row to the table. Its values are in the order of the
+// columns, and must be of the Go types of their columns: string, int64, or
+// float64.
+func (w *Writer) Write(row ...any) error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, want %d", len(row), len(w.columns))
+	}
+	for i, v := range row {
+		var ok bool
+		switch w.columns[i].Type {
+		case String:
+			_, ok = v.(string)
+		case Int64:
+			_, ok = v.(int64)
+		case Double:
+			_, ok = v.(float64)
+		}
+		if !ok {
+			return fmt.Errorf("parquet: value %v of type %T for column %s", v, v, w.columns[i].Name)
+		}
+	}
+	for i, v := range row {
+		buf := &w.values[i]
+		switch v := v.(type) {
+		case string:
+			buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(v))))
+			buf.WriteString(v)
+		case int64:
+			buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(v)))
+		case float64:
+			buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)))
+		}
+	}
+	w.rows++
+	if w.rows == rowGroupRows {
+		w.flush()
+	}
+	return w.err
+}
+
+// flush writes the current row group.
+func (w *Writer) flush() {
+	g := rowGroup{rows: int64(w.rows)}
+	for i := range w.columns {
+		data := w.values[i].Bytes()
+		var page bytes.Buffer
+		zw := gzip.NewWriter(&page)
+		zw.Write(data) // writes to a bytes.Buffer do not fail
+		zw.Close()
+
+		var e encoder
+		e.begin()
+		e.i32(1, pageTypeData)
+		e.i32(2, int32(len(data)))
+		e.i32(3, int32(page.Len()))
+		e.structField(5)
+		e.i32(1, int32(w.rows))
+		e.i32(2, encodingPlain)
+		e.i32(3, encodingRLE)
+		e.i32(4, encodingRLE)
+		e.end()
+		e.end()
+
+		g.chunks = append(g.chunks, columnChunk{
+			offset:       w.off,
+			compressed:   int64(len(e.buf) + page.Len()),
+			uncompressed: int64(len(e.buf) + len(data)),
+		})
+		w.write(e.buf)
+		w.write(page.Bytes())
+		w.values[i].Reset()
+	}
+	w.groups = append(w.groups, g)
+	w.rows = 0
+}
+
+// Close writes the rows that remain buffered and the metadata of the file.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.rows > 0 {
+		w.flush()
+	}
+	meta := w.metadata()
+	w.write(meta)
+	w.write(binary.LittleEndian.AppendUint32(nil, uint32(len(meta))))
+	w.write([]byte(magic))
+	if w.err == nil {
+		w.err = errClosed
+		return nil
+	}
+	return w.err
+}
+
+var errClosed = errors.New("parquet: writer is closed")
+
+// metadata returns the encoded FileMetaData of the file.
+func (w *Writer) metadata() []byte {
+	var numRows int64
+	for _, g := range w.groups {
+		numRows += g.rows
+	}
+
+	var e encoder
+	e.begin()
+	e.i32(1, 1) // version
+	e.list(2, typeStruct, len(w.columns)+1)
+	e.begin()
+	e.string(4, "schema")
+	e.i32(5, int32(len(w.columns)))
+	e.end()
+	for _, c := range w.columns {
+		e.begin()
+		e.i32(1, physicalType(c.Type))
+		e.i32(3, repetitionRequired)
+		e.string(4, c.Name)
+		if c.Type == String {
+			e.i32(6, convertedUTF8)
+		}
+		e.end()
+	}
+	e.i64(3, numRows)
+	e.list(4, typeStruct, len(w.groups))
+	for _, g := range w.groups {
+		var size int64
+		for _, c := range g.chunks {
+			size += c.uncompressed
+		}
+		e.begin()
+		e.list(1, typeStruct, len(g.chunks))
+		for i, c := range g.chunks {
+			e.begin()
+			e.i64(2, c.offset)
+			e.structField(3)
+			e.i32(1, physicalType(w.columns[i].Type))
+			e.list(2, typeI32, 2)
+			e.listI32(encodingPlain)
+			e.listI32(encodingRLE)
+			e.list(3, typeBinary, 1)
+			e.listString(w.columns[i].Name)
+			e.i32(4, codecGzip)
+			e.i64(5, g.rows)
+			e.i64(6, c.uncompressed)
+			e.i64(7, c.compressed)
+			e.i64(9, c.offset)
+			e.end()
+			e.end()
+		}
+		e.i64(2, size)
+		e.i64(3, g.rows)
+		e.end()
+	}
+	e.string(6, "golang.org/x/telemetry/godev")
+	e.end()
+	return e.buf
+}
+
+// Values of the enums of the Parquet metadata.
+const (
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	convertedUTF8      = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecGzip          = 2
+	pageTypeData       = 0
+)
+
+func physicalType(t Type) int32 {
+	switch t {
+	case Int64:
+		return typeInt64
+	case Double:
+		return typeDouble
+	}
+	return typeByteArray
+}
+
+// Types of the Thrift compact protocol, in which the metadata is encoded.
+const (
+	typeI32    = 5
+	typeI64    = 6
+	typeBinary = 8
+	typeList   = 9
+	typeStruct = 12
+)
+
+// An encoder encodes Thrift structs with the compact protocol.
+type encoder struct {
+	buf  []byte
+	last []int16 // ID of the last field written to each open struct
+}
+
+// begin starts a struct, either at the top level or as an element of a
+// list.
+func (e *encoder) begin() {
+	e.last = append(e.last, 0)
+}
+
+// end ends the innermost open struct.
+func (e *encoder) end() {
+	e.buf = append(e.buf, 0) // stop field
+	e.last = e.last[:len(e.last)-1]
+}
+
+func (e *encoder) field(id int16, typ byte) {
+	last := &e.last[len(e.last)-1]
+	if delta := id - *last; 0 < delta && delta <= 15 {
+		e.buf = append(e.buf, byte(delta)<<4|typ)
+	} else {
+		e.buf = append(e.buf, typ)
+		e.buf = binary.AppendVarint(e.buf, int64(id))
+	}
+	*last = id
+}
+
+// structField starts a struct that is the value of a field.
+func (e *encoder) structField(id int16) {
+	e.field(id, typeStruct)
+	e.begin()
+}
+
+func (e *encoder) i32(id int16, v int32) {
+	e.field(id, typeI32)
+	e.buf = binary.AppendVarint(e.buf, int64(v))
+}
+
+func (e *encoder) i64(id int16, v int64) {
+	e.field(id, typeI64)
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *encoder) string(id int16, s string) {
+	e.field(id, typeBinary)
+	e.listString(s)
+}
+
+// list starts a list field of n elements of the given type, which are then
+// written with listI32, listString, or begin and end.
+func (e *encoder) list(id int16, elem byte, n int) {
+	e.field(id, typeList)
+	if n < 15 {
+		e.buf = append(e.buf, byte(n)<<4|elem)
+	} else {
+		e.buf = append(e.buf, 0xf0|elem)
+		e.buf = binary.AppendUvarint(e.buf, uint64(n))
+	}
+}
+
+func (e *encoder) listI32(v int32) {
+	e.buf = binary.AppendVarint(e.buf, int64(v))
+}
+
+func (e *encoder) listString(s string) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
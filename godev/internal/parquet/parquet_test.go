@@ -0,0 +1,235 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriter(t *testing.T) {
+	columns := []Column{{"name", String}, {"value", Int64}, {"x", Double}}
+	var want [][]any
+	for i := range rowGroupRows + 2 { // two row groups
+		want = append(want, []any{fmt.Sprintf("counter%d", i%7), int64(i), float64(i) / 4})
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf, columns)
+	for _, row := range want {
+		if err := w.Write(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readTable(buf.Bytes(), columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("rows mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []Column{{"name", String}}
+	if err := NewWriter(&buf, columns).Close(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readTable(buf.Bytes(), columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("read %d rows from an empty table, want 0", len(got))
+	}
+}
+
+func TestWriterTypes(t *testing.T) {
+	w := NewWriter(io.Discard, []Column{{"name", String}, {"value", Int64}})
+	if err := w.Write("a", 1); err == nil {
+		t.Errorf("Write with an int for an Int64 column succeeded")
+	}
+	if err := w.Write("a"); err == nil {
+		t.Errorf("Write with too few values succeeded")
+	}
+	if err := w.Write("a", int64(1)); err != nil {
+		t.Errorf("Write with valid values: %v", err)
+	}
+}
+
+// readTable reads the rows of a Parquet file written by a Writer, checking
+// the schema against columns.
+func readTable(data []byte, columns []Column) ([][]any, error) {
+	if !bytes.HasPrefix(data, []byte(magic)) || !bytes.HasSuffix(data, []byte(magic)) {
+		return nil, fmt.Errorf("missing magic")
+	}
+	n := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	d := &decoder{data: data[len(data)-8-n : len(data)-8]}
+	meta := d.value(typeStruct).(map[int16]any)
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	schema := meta[2].([]any)
+	if len(schema) != len(columns)+1 {
+		return nil, fmt.Errorf("schema has %d elements, want %d", len(schema), len(columns)+1)
+	}
+	for i, c := range columns {
+		elem := schema[i+1].(map[int16]any)
+		if name := string(elem[4].([]byte)); name != c.Name {
+			return nil, fmt.Errorf("column %d is %s, want %s", i, name, c.Name)
+		}
+		if typ := elem[1].(int64); typ != int64(physicalType(c.Type)) {
+			return nil, fmt.Errorf("column %s has type %d, want %d", c.Name, typ, physicalType(c.Type))
+		}
+	}
+
+	var rows [][]any
+	var groups []any
+	if g, ok := meta[4]; ok {
+		groups = g.([]any)
+	}
+	for _, g := range groups {
+		g := g.(map[int16]any)
+		numRows := int(g[3].(int64))
+		group := make([][]any, numRows)
+		for i, c := range g[1].([]any) {
+			md := c.(map[int16]any)[3].(map[int16]any)
+			off := md[9].(int64)
+			d := &decoder{data: data[off:]}
+			header := d.value(typeStruct).(map[int16]any)
+			if d.err != nil {
+				return nil, d.err
+			}
+			size := int(header[3].(int64))
+			page := d.data[:size]
+			zr, err := gzip.NewReader(bytes.NewReader(page))
+			if err != nil {
+				return nil, err
+			}
+			values, err := io.ReadAll(zr)
+			if err != nil {
+				return nil, err
+			}
+			if int64(len(values)) != header[2].(int64) {
+				return nil, fmt.Errorf("page has %d bytes, header says %d", len(values), header[2])
+			}
+			for r := range numRows {
+				var v any
+				switch columns[i].Type {
+				case String:
+					n := binary.LittleEndian.Uint32(values)
+					v, values = string(values[4:4+n]), values[4+n:]
+				case Int64:
+					v, values = int64(binary.LittleEndian.Uint64(values)), values[8:]
+				case Double:
+					v, values = math.Float64frombits(binary.LittleEndian.Uint64(values)), values[8:]
+				}
+				group[r] = append(group[r], v)
+			}
+		}
+		rows = append(rows, group...)
+	}
+	if int64(len(rows)) != meta[3].(int64) {
+		return nil, fmt.Errorf("read %d rows, metadata says %d", len(rows), meta[3])
+	}
+	return rows, nil
+}
+
+// A decoder decodes values of the Thrift compact protocol: structs as maps
+// from field IDs to values, lists as slices, integers as int64, and binary
+// values as byte slices.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) byte() byte {
+	if len(d.data) == 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) value(typ byte) any {
+	if d.err != nil {
+		return nil
+	}
+	switch typ {
+	case typeI32, typeI64:
+		return d.varint()
+	case typeBinary:
+		n := d.uvarint()
+		if uint64(len(d.data)) < n {
+			d.err = io.ErrUnexpectedEOF
+			return nil
+		}
+		b := d.data[:n]
+		d.data = d.data[n:]
+		return b
+	case typeList:
+		b := d.byte()
+		n, elem := uint64(b>>4), b&0xf
+		if n == 15 {
+			n = d.uvarint()
+		}
+		var list []any
+		for range n {
+			list = append(list, d.value(elem))
+		}
+		return list
+	case typeStruct:
+		s := make(map[int16]any)
+		var id int16
+		for d.err == nil {
+			b := d.byte()
+			if b == 0 {
+				break
+			}
+			if delta := int16(b >> 4); delta != 0 {
+				id += delta
+			} else {
+				id = int16(d.varint())
+			}
+			s[id] = d.value(b & 0xf)
+		}
+		return s
+	}
+	d.err = fmt.Errorf("unsupported type %d", typ)
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package server runs the HTTP servers of the telemetry services, and shuts
+// them down gracefully.
+//
+// Cloud Run sends SIGTERM to an instance that it is stopping, for example
+// during a deploy, and kills it if it has not exited a grace period later.
+// Requests in flight when the signal arrives are given until the drain
+// timeout, which should be shorter than the grace period, to complete.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// Serve serves h on the TCP address addr until ctx is done or the process
+// receives SIGINT or SIGTERM. It then stops accepting connections, waits up
+// to drain for the requests in flight to complete, and returns. Requests
+// still in flight after drain are canceled, and Serve reports an error.
+func Serve(ctx context.Context, addr string, h http.Handler, drain time.Duration) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return serve(ctx, ln, h, drain)
+}
+
+func serve(ctx context.Context, ln net.Listener, h http.Handler, drain time.Duration) error {
+	srv := &http.Server{Handler: h}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down", slog.Duration("drain", drain))
+	sctx, cancel := context.WithTimeout(context.Background(), drain)
+	defer cancel()
+	if err := srv.Shutdown(sctx); err != nil {
+		srv.Close()
+		return fmt.Errorf("draining requests: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServeDrain(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		drain   time.Duration
+		wantErr bool
+	}{
+		{"drained", time.Minute, false},
+		{"timed out", 10 * time.Millisecond, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				entered = make(chan struct{})
+				release = make(chan struct{})
+			)
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(entered)
+				select {
+				case <-release:
+					io.WriteString(w, "done")
+				case <-r.Context().Done():
+				}
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			served := make(chan error, 1)
+			go func() { served <- serve(ctx, ln, h, test.drain) }()
+
+			got := make(chan error, 1)
+			go func() {
+				resp, err := http.Get("http://" + ln.Addr().String())
+				if err == nil {
+					_, err = io.ReadAll(resp.Body)
+					resp.Body.Close()
+				}
+				got <- err
+			}()
+			<-entered
+			cancel()
+
+			// Once shutdown starts, no new connections are accepted.
+			for i := 0; ; i++ {
+				c, err := net.Dial("tcp", ln.Addr().String())
+				if err != nil {
+					break
+				}
+				c.Close()
+				if i == 100 {
+					t.Fatal("server still accepting connections after shutdown")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if test.wantErr {
+				if err := <-served; err == nil {
+					t.Error("serve returned nil after drain timeout, want error")
+				}
+				close(release)
+				if err := <-got; err == nil {
+					t.Error("request in flight after drain timeout succeeded, want error")
+				}
+				return
+			}
+			close(release)
+			if err := <-got; err != nil {
+				t.Errorf("request in flight during shutdown: %v", err)
+			}
+			if err := <-served; err != nil {
+				t.Errorf("serve = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestServeListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	err = Serve(context.Background(), ln.Addr().String(), http.NotFoundHandler(), time.Second)
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		t.Errorf("Serve on an address in use = %v, want a listen error", err)
+	}
+}
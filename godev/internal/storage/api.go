@@ -7,9 +7,12 @@ import (
 )
 
 type API struct {
-	Upload BucketHandle
-	Merge  BucketHandle
-	Chart  BucketHandle
+	Upload     BucketHandle
+	Merge      BucketHandle
+	Chart      BucketHandle
+	Stats      BucketHandle
+	TimeSeries BucketHandle
+	DeadLetter BucketHandle
 }
 
 func NewAPI(ctx context.Context, cfg *config.Config) (*API, error) {
@@ -21,14 +24,36 @@ func NewAPI(ctx context.Context, cfg *config.Config) (*API, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cfg.CompressMerged {
+		merge = Compressed(merge)
+	}
 	chart, err := NewBucket(ctx, cfg, cfg.ChartDataBucket)
 	if err != nil {
 		return nil, err
 	}
-	return &API{upload, merge, chart}, nil
+	stats, err := NewBucket(ctx, cfg, cfg.StatsBucket)
+	if err != nil {
+		return nil, err
+	}
+	timeSeries, err := NewBucket(ctx, cfg, cfg.TimeSeriesBucket)
+	if err != nil {
+		return nil, err
+	}
+	deadLetter, err := NewBucket(ctx, cfg, cfg.DeadLetterBucket)
+	if err != nil {
+		return nil, err
+	}
+	return &API{upload, merge, chart, stats, timeSeries, deadLetter}, nil
 }
 
+// NewBucket returns the named bucket of the object store selected by cfg: an
+// S3-compatible store if S3Endpoint is set, Cloud Storage if UseGCS is set,
+// and the filesystem otherwise.
 func NewBucket(ctx context.Context, cfg *config.Config, name string) (BucketHandle, error) {
+	if cfg.S3Endpoint != "" {
+		creds := S3Credentials{cfg.S3AccessKeyID, cfg.S3SecretAccessKey}
+		return NewS3Bucket(ctx, cfg.S3Endpoint, cfg.S3Region, creds, name)
+	}
 	if cfg.UseGCS {
 		return NewGCSBucket(ctx, cfg.ProjectID, name)
 	}
@@ -0,0 +1,73 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+)
+
+// Compressed returns a copy of b, a GCSBucket or FSBucket, whose writers
+// compress objects with gzip. Other buckets are returned unchanged.
+//
+// Compression is transparent to readers: GCS objects are written with a
+// gzip Content-Encoding, which Cloud Storage decodes when serving them, and
+// FSBucket readers decompress the files that start with a gzip header, so
+// objects written before compression was enabled can still be read.
+// Objects whose names end in .gz or .parquet, which callers compress
+// themselves, are stored and read as is.
+func Compressed(b BucketHandle) BucketHandle {
+	switch b := b.(type) {
+	case *GCSBucket:
+		c := *b
+		c.compress = true
+		return &c
+	case *FSBucket:
+		c := *b
+		c.compress = true
+		return &c
+	}
+	return b
+}
+
+// compressible reports whether the named object is compressed by the writers
+// of a Compressed bucket.
+func compressible(name string) bool {
+	return !strings.HasSuffix(name, ".gz") && !strings.HasSuffix(name, ".parquet")
+}
+
+// gzipWriter compresses the data written to an object writer, and closes the
+// writer when it is closed.
+type gzipWriter struct {
+	*gzip.Writer
+	w io.WriteCloser
+}
+
+func newGzipWriter(w io.WriteCloser) *gzipWriter {
+	return &gzipWriter{gzip.NewWriter(w), w}
+}
+
+func (w *gzipWriter) Close() error {
+	if err := w.Writer.Close(); err != nil {
+		w.w.Close()
+		return err
+	}
+	return w.w.Close()
+}
+
+// gzipMagic starts the header of gzip data. JSON, the usual contents of
+// objects, can not start with it.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// isGzip reports whether the contents of f are compressed with gzip. It
+// does not move the offset of f.
+func isGzip(f *os.File) bool {
+	magic := make([]byte, len(gzipMagic))
+	n, _ := f.ReadAt(magic, 0)
+	return bytes.Equal(magic[:n], gzipMagic)
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// DigestSuffix is the suffix of the name of the object holding the SHA-256
+// digest of another object, in the format of sha256sum. The digest is
+// written after the object, so that a truncated write of the object, for
+// example by an instance stopped mid-write, is detected by VerifyDigest.
+const DigestSuffix = ".sha256"
+
+// ErrDigestMismatch is returned by VerifyDigest if the contents of an object
+// do not match its digest.
+var ErrDigestMismatch = errors.New("object does not match its digest")
+
+// WriteDigest writes sum, the SHA-256 digest of the named object, next to
+// the object.
+func WriteDigest(ctx context.Context, bucket BucketHandle, name string, sum []byte) error {
+	out, err := bucket.Object(name + DigestSuffix).NewWriter(ctx)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err := fmt.Fprintf(out, "%x  %s\n", sum, path.Base(name)); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// VerifyDigest reads the named object and checks that it matches the digest
+// written by WriteDigest. It returns ErrObjectNotExist if the object or its
+// digest does not exist, and ErrDigestMismatch if they do not match.
+func VerifyDigest(ctx context.Context, bucket BucketHandle, name string) error {
+	want, err := readDigest(ctx, bucket, name)
+	if err != nil {
+		return err
+	}
+	in, err := bucket.Object(name).NewReader(ctx)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, in); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("%w: %s has digest %s, want %s", ErrDigestMismatch, name, got, want)
+	}
+	return in.Close()
+}
+
+func readDigest(ctx context.Context, bucket BucketHandle, name string) (string, error) {
+	in, err := bucket.Object(name + DigestSuffix).NewReader(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	sum, _, _ := strings.Cut(line, " ")
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != 2*sha256.Size {
+		return "", fmt.Errorf("%w: invalid digest for %s", ErrDigestMismatch, name)
+	}
+	return sum, nil
+}
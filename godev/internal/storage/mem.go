@@ -0,0 +1,318 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"mime"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ BucketHandle = &MemBucket{}
+
+// Operations of a MemBucket, passed to its Fail hook.
+const (
+	MemRead     = "read"     // NewReader
+	MemWrite    = "write"    // the Close of a writer, which commits the object
+	MemList     = "list"     // Objects and ObjectsWithAttrs
+	MemDelete   = "delete"   // Delete
+	MemMetadata = "metadata" // Generation, Metadata, and SetMetadata
+)
+
+// MemBucket is a BucketHandle that holds its objects in memory, for tests.
+// Like a GCSBucket, it commits objects atomically when their writer is
+// closed, and gives each commit a new generation.
+type MemBucket struct {
+	name string
+
+	// Fail, if set, is called with the operation and object name before
+	// each operation on the bucket. If it returns an error, the operation
+	// fails with it, so that tests can inject storage failures. For list
+	// operations, the name is the prefix.
+	Fail func(op, name string) error
+
+	mu      sync.Mutex
+	objects map[string]*memObjectData
+	gen     int64 // generation of the latest commit
+}
+
+type memObjectData struct {
+	data     []byte
+	gen      int64
+	updated  time.Time
+	metadata map[string]string
+}
+
+// NewMemBucket returns an empty MemBucket with the given name.
+func NewMemBucket(name string) *MemBucket {
+	return &MemBucket{name: name, objects: make(map[string]*memObjectData)}
+}
+
+func (b *MemBucket) fail(op, name string) error {
+	if b.Fail == nil {
+		return nil
+	}
+	return b.Fail(op, name)
+}
+
+func (b *MemBucket) Object(name string) ObjectHandle {
+	return &memObject{b, name}
+}
+
+// list returns the sorted names of the objects with the given prefix.
+func (b *MemBucket) list(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := b.fail(MemList, prefix); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var names []string
+	for name := range b.objects {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (b *MemBucket) Objects(ctx context.Context, prefix string) ObjectIterator {
+	names, err := b.list(ctx, prefix)
+	return &FSObjectIterator{ctx, names, err, 0}
+}
+
+func (b *MemBucket) ObjectsWithAttrs(ctx context.Context, prefix string) ObjectAttrsIterator {
+	names, err := b.list(ctx, prefix)
+	return &memObjectAttrsIterator{FSObjectIterator{ctx, names, err, 0}, b}
+}
+
+type memObjectAttrsIterator struct {
+	names  FSObjectIterator
+	bucket *MemBucket
+}
+
+func (it *memObjectAttrsIterator) Next() (*ObjectAttrs, error) {
+	for {
+		name, err := it.names.Next()
+		if err != nil {
+			return nil, err
+		}
+		it.bucket.mu.Lock()
+		o := it.bucket.objects[name]
+		it.bucket.mu.Unlock()
+		if o == nil {
+			continue // deleted since it was listed
+		}
+		return &ObjectAttrs{
+			Name:        name,
+			Size:        int64(len(o.data)),
+			Updated:     o.updated,
+			ContentType: mime.TypeByExtension(path.Ext(name)),
+		}, nil
+	}
+}
+
+func (b *MemBucket) Delete(ctx context.Context, name string) error {
+	return b.Object(name).Delete(ctx)
+}
+
+func (b *MemBucket) DeleteAll(ctx context.Context, prefix string) (int, error) {
+	return deleteAll(ctx, b, prefix, 1)
+}
+
+func (b *MemBucket) URI() string {
+	return "mem://" + b.name
+}
+
+// SignedURL returns the URI of the object, which can not be downloaded.
+func (b *MemBucket) SignedURL(name string, ttl time.Duration) (string, error) {
+	return b.URI() + "/" + name, nil
+}
+
+type memObject struct {
+	bucket *MemBucket
+	name   string
+}
+
+// get returns the data of the object, or ErrObjectNotExist.
+func (o *memObject) get(ctx context.Context, op string) (*memObjectData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := o.bucket.fail(op, o.name); err != nil {
+		return nil, err
+	}
+	o.bucket.mu.Lock()
+	defer o.bucket.mu.Unlock()
+	d := o.bucket.objects[o.name]
+	if d == nil {
+		return nil, ErrObjectNotExist
+	}
+	return d, nil
+}
+
+func (o *memObject) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	d, err := o.get(ctx, MemRead)
+	if err != nil {
+		return nil, err
+	}
+	// Committed data is never modified, so the reader can share it.
+	return &memReader{ctx, bytes.NewReader(d.data)}, nil
+}
+
+func (o *memObject) NewWriter(ctx context.Context) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &memWriter{ctx: ctx, o: o}, nil
+}
+
+func (o *memObject) NewWriterIf(ctx context.Context, gen int64) (io.WriteCloser, error) {
+	return o.NewWriterWithMetadata(ctx, gen, nil)
+}
+
+func (o *memObject) NewWriterWithMetadata(ctx context.Context, gen int64, md map[string]string) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &memWriter{ctx: ctx, o: o, conditional: true, gen: gen, metadata: maps.Clone(md)}, nil
+}
+
+func (o *memObject) Generation(ctx context.Context) (int64, error) {
+	d, err := o.get(ctx, MemMetadata)
+	if err != nil {
+		return 0, err
+	}
+	return d.gen, nil
+}
+
+func (o *memObject) Metadata(ctx context.Context) (map[string]string, error) {
+	d, err := o.get(ctx, MemMetadata)
+	if err != nil {
+		return nil, err
+	}
+	o.bucket.mu.Lock()
+	defer o.bucket.mu.Unlock()
+	return maps.Clone(d.metadata), nil
+}
+
+func (o *memObject) SetMetadata(ctx context.Context, md map[string]string) error {
+	d, err := o.get(ctx, MemMetadata)
+	if err != nil {
+		return err
+	}
+	o.bucket.mu.Lock()
+	defer o.bucket.mu.Unlock()
+	if d.metadata == nil {
+		d.metadata = make(map[string]string)
+	}
+	maps.Copy(d.metadata, md)
+	return nil
+}
+
+func (o *memObject) Delete(ctx context.Context) error {
+	if _, err := o.get(ctx, MemDelete); err != nil {
+		return err
+	}
+	o.bucket.mu.Lock()
+	defer o.bucket.mu.Unlock()
+	if o.bucket.objects[o.name] == nil {
+		return ErrObjectNotExist
+	}
+	delete(o.bucket.objects, o.name)
+	return nil
+}
+
+// memReader is an io.ReadCloser for a memObject that fails once its context
+// is done.
+type memReader struct {
+	ctx context.Context
+	r   *bytes.Reader
+}
+
+func (r *memReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+func (r *memReader) Close() error {
+	return nil
+}
+
+// memWriter is an io.WriteCloser for a memObject. It buffers the data
+// written to it, and commits the object when it is closed.
+type memWriter struct {
+	ctx    context.Context
+	o      *memObject
+	buf    bytes.Buffer
+	closed bool
+	err    error // result of the first Close
+
+	conditional bool  // commit only if the object's generation is gen
+	gen         int64 // 0 if the object must not exist
+
+	metadata map[string]string // custom metadata of the committed object
+}
+
+func (w *memWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.buf.Write(p)
+}
+
+// Close commits the object, unless the writer's context is done. Subsequent
+// calls return the result of the first.
+func (w *memWriter) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	w.err = w.commit()
+	return w.err
+}
+
+func (w *memWriter) commit() error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	b := w.o.bucket
+	if err := b.fail(MemWrite, w.o.name); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if w.conditional {
+		var gen int64
+		if d := b.objects[w.o.name]; d != nil {
+			gen = d.gen
+		}
+		if gen != w.gen {
+			return ErrPreconditionFailed
+		}
+	}
+	b.gen++
+	// As with Cloud Storage, a new object has only the metadata it was
+	// written with.
+	b.objects[w.o.name] = &memObjectData{
+		data:     bytes.Clone(w.buf.Bytes()),
+		gen:      b.gen,
+		updated:  time.Now(),
+		metadata: w.metadata,
+	}
+	return nil
+}
@@ -0,0 +1,574 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+var _ BucketHandle = &S3Bucket{}
+
+// S3Credentials are the credentials with which requests to an S3-compatible
+// object store, such as Amazon S3 or MinIO, are signed.
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Bucket is a bucket of an S3-compatible object store, accessed with its
+// REST API. Requests are signed with AWS Signature Version 4, and address
+// buckets by path, which both Amazon S3 and MinIO accept.
+//
+// S3 has no object generations, so the generation of an S3 object is derived
+// from its ETag. As ETags are digests of the contents of objects, rewriting
+// an object with the same contents keeps its generation. Conditional writes
+// rely on the If-Match and If-None-Match preconditions of PutObject.
+type S3Bucket struct {
+	client   *http.Client
+	endpoint *url.URL
+	region   string
+	creds    S3Credentials
+	bucket   string
+}
+
+// NewS3Bucket returns the named bucket of the object store at endpoint, such
+// as https://s3.us-east-1.amazonaws.com or http://localhost:9000, creating
+// it if it does not exist.
+func NewS3Bucket(ctx context.Context, endpoint, region string, creds S3Credentials, bucket string) (BucketHandle, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %v", err)
+	}
+	b := &S3Bucket{http.DefaultClient, u, region, creds, bucket}
+	resp, err := b.do(ctx, http.MethodHead, "", nil, nil, nil)
+	if err == nil {
+		resp.Body.Close()
+		return b, nil
+	}
+	if !errors.Is(err, ErrObjectNotExist) {
+		return nil, err
+	}
+	var body []byte
+	if region != "" && region != "us-east-1" {
+		body = []byte(`<CreateBucketConfiguration><LocationConstraint>` + region + `</LocationConstraint></CreateBucketConfiguration>`)
+	}
+	resp, err = b.do(ctx, http.MethodPut, "", nil, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return b, nil
+}
+
+func (b *S3Bucket) Object(name string) ObjectHandle {
+	return &S3Object{b, name}
+}
+
+func (b *S3Bucket) Objects(ctx context.Context, prefix string) ObjectIterator {
+	return &S3ObjectIterator{s3Lister{ctx: ctx, bucket: b, prefix: prefix}}
+}
+
+func (b *S3Bucket) ObjectsWithAttrs(ctx context.Context, prefix string) ObjectAttrsIterator {
+	return &S3ObjectAttrsIterator{s3Lister{ctx: ctx, bucket: b, prefix: prefix}}
+}
+
+func (b *S3Bucket) Delete(ctx context.Context, name string) error {
+	return b.Object(name).Delete(ctx)
+}
+
+// s3DeleteConcurrency is the number of objects that S3Bucket.DeleteAll
+// deletes at once.
+const s3DeleteConcurrency = 16
+
+func (b *S3Bucket) DeleteAll(ctx context.Context, prefix string) (int, error) {
+	return deleteAll(ctx, b, prefix, s3DeleteConcurrency)
+}
+
+func (b *S3Bucket) URI() string {
+	return "s3://" + b.bucket
+}
+
+// url returns the URL of the named object, or of the bucket if name is "".
+func (b *S3Bucket) url(name string) *url.URL {
+	u := *b.endpoint
+	u.Path = strings.TrimSuffix(b.endpoint.Path, "/") + "/" + b.bucket
+	u.RawPath = strings.TrimSuffix(b.endpoint.EscapedPath(), "/") + "/" + s3Escape(b.bucket, false)
+	if name != "" {
+		u.Path += "/" + name
+		u.RawPath += "/" + s3Escape(name, false)
+	}
+	return &u
+}
+
+// SignedURL returns a presigned URL of the named object: the signature of
+// the GET request is in its query, instead of the Authorization header.
+func (b *S3Bucket) SignedURL(name string, ttl time.Duration) (string, error) {
+	u := b.url(name)
+	presignS3(u, b.region, b.creds, time.Now(), ttl)
+	return u.String(), nil
+}
+
+// do sends a signed request for the named object, or for the bucket if name
+// is "", and returns the response if its status is 2xx. Otherwise, it
+// returns ErrObjectNotExist for 404 Not Found, ErrPreconditionFailed for
+// 412 Precondition Failed and 409 Conflict, which S3 returns for concurrent
+// conditional writes, and an error describing the failure otherwise.
+func (b *S3Bucket) do(ctx context.Context, method, name string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u := b.url(name)
+	u.RawQuery = s3Query(query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body == nil {
+		req.Body = nil
+		req.ContentLength = 0
+	}
+	signS3(req, body, b.region, b.creds, time.Now())
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, ErrObjectNotExist
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		return nil, ErrPreconditionFailed
+	}
+	var e struct {
+		Code    string
+		Message string
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if xml.Unmarshal(data, &e) != nil || e.Code == "" {
+		e.Code = resp.Status
+	}
+	return nil, fmt.Errorf("s3: %s %s/%s: %s %s", method, b.bucket, name, e.Code, e.Message)
+}
+
+type S3Object struct {
+	bucket *S3Bucket
+	name   string
+}
+
+func (o *S3Object) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := o.bucket.do(ctx, http.MethodGet, o.name, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// NewWriter returns a writer for the object. The object is buffered in
+// memory, and uploaded when the writer is closed, unless ctx is done.
+func (o *S3Object) NewWriter(ctx context.Context) (io.WriteCloser, error) {
+	return &s3Writer{ctx: ctx, o: o}, nil
+}
+
+func (o *S3Object) NewWriterIf(ctx context.Context, gen int64) (io.WriteCloser, error) {
+	return o.NewWriterWithMetadata(ctx, gen, nil)
+}
+
+func (o *S3Object) NewWriterWithMetadata(ctx context.Context, gen int64, md map[string]string) (io.WriteCloser, error) {
+	return &s3Writer{ctx: ctx, o: o, conditional: true, gen: gen, metadata: md}, nil
+}
+
+// head returns the headers of the object.
+func (o *S3Object) head(ctx context.Context) (http.Header, error) {
+	resp, err := o.bucket.do(ctx, http.MethodHead, o.name, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp.Header, nil
+}
+
+func (o *S3Object) Generation(ctx context.Context) (int64, error) {
+	h, err := o.head(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s3Generation(h.Get("ETag")), nil
+}
+
+// s3Generation returns the generation of an object with the given ETag: a
+// positive hash of the ETag.
+func s3Generation(etag string) int64 {
+	h := fnv.New64a()
+	io.WriteString(h, etag)
+	gen := int64(h.Sum64() &^ (1 << 63))
+	if gen == 0 {
+		gen = 1 // 0 is the generation of missing objects
+	}
+	return gen
+}
+
+const s3MetaPrefix = "X-Amz-Meta-"
+
+func (o *S3Object) Metadata(ctx context.Context) (map[string]string, error) {
+	h, err := o.head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s3Metadata(h), nil
+}
+
+func s3Metadata(h http.Header) map[string]string {
+	var md map[string]string
+	for k, v := range h {
+		if key, ok := strings.CutPrefix(k, s3MetaPrefix); ok && len(v) > 0 {
+			if md == nil {
+				md = make(map[string]string)
+			}
+			md[strings.ToLower(key)] = v[0]
+		}
+	}
+	return md
+}
+
+// SetMetadata merges md into the metadata of the object. S3 can only
+// replace the metadata of an object by copying it onto itself, which is
+// done on the condition that the object has not changed meanwhile.
+func (o *S3Object) SetMetadata(ctx context.Context, md map[string]string) error {
+	h, err := o.head(ctx)
+	if err != nil {
+		return err
+	}
+	header := make(http.Header)
+	for k, v := range s3Metadata(h) {
+		header.Set(s3MetaPrefix+k, v)
+	}
+	for k, v := range md {
+		header.Set(s3MetaPrefix+k, v)
+	}
+	if ct := h.Get("Content-Type"); ct != "" {
+		header.Set("Content-Type", ct)
+	}
+	header.Set("X-Amz-Copy-Source", "/"+s3Escape(o.bucket.bucket, false)+"/"+s3Escape(o.name, false))
+	header.Set("X-Amz-Copy-Source-If-Match", h.Get("ETag"))
+	header.Set("X-Amz-Metadata-Directive", "REPLACE")
+	resp, err := o.bucket.do(ctx, http.MethodPut, o.name, nil, header, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Delete deletes the object. S3 deletes missing objects without failing, so
+// the object's existence is checked first.
+func (o *S3Object) Delete(ctx context.Context) error {
+	if _, err := o.head(ctx); err != nil {
+		return err
+	}
+	resp, err := o.bucket.do(ctx, http.MethodDelete, o.name, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// s3Writer is an io.WriteCloser for an S3Object. It buffers the data written
+// to it, and uploads the object when it is closed.
+type s3Writer struct {
+	ctx    context.Context
+	o      *S3Object
+	buf    bytes.Buffer
+	closed bool
+	err    error // result of the first Close
+
+	conditional bool  // upload only if the object's generation is gen
+	gen         int64 // 0 if the object must not exist
+
+	metadata map[string]string // custom metadata of the uploaded object
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.buf.Write(p)
+}
+
+// Close uploads the object, unless the writer's context is done.
+// Subsequent calls return the result of the first.
+func (w *s3Writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	w.err = w.upload()
+	return w.err
+}
+
+func (w *s3Writer) upload() error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	header := make(http.Header)
+	if ct := mime.TypeByExtension(path.Ext(w.o.name)); ct != "" {
+		header.Set("Content-Type", ct)
+	}
+	for k, v := range w.metadata {
+		header.Set(s3MetaPrefix+k, v)
+	}
+	if w.conditional {
+		if w.gen == 0 {
+			header.Set("If-None-Match", "*")
+		} else {
+			// The generation is a hash of the ETag, which the upload
+			// must match.
+			h, err := w.o.head(w.ctx)
+			if errors.Is(err, ErrObjectNotExist) {
+				return ErrPreconditionFailed
+			}
+			if err != nil {
+				return err
+			}
+			etag := h.Get("ETag")
+			if s3Generation(etag) != w.gen {
+				return ErrPreconditionFailed
+			}
+			header.Set("If-Match", etag)
+		}
+	}
+	resp, err := w.o.bucket.do(w.ctx, http.MethodPut, w.o.name, nil, header, w.buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// s3Lister lists the objects of a bucket a page at a time, with the
+// ListObjectsV2 API.
+type s3Lister struct {
+	ctx    context.Context
+	bucket *S3Bucket
+	prefix string
+
+	page  []s3Object
+	token string // continuation token of the next page
+	done  bool   // no more pages
+	err   error
+}
+
+type s3Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+func (l *s3Lister) next() (*s3Object, error) {
+	for len(l.page) == 0 {
+		if l.err != nil {
+			return nil, l.err
+		}
+		if l.done {
+			return nil, ErrObjectIteratorDone
+		}
+		l.err = l.fetch()
+	}
+	o := &l.page[0]
+	l.page = l.page[1:]
+	return o, nil
+}
+
+func (l *s3Lister) fetch() error {
+	q := url.Values{"list-type": {"2"}, "prefix": {l.prefix}}
+	if l.token != "" {
+		q.Set("continuation-token", l.token)
+	}
+	resp, err := l.bucket.do(l.ctx, http.MethodGet, "", q, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Contents              []s3Object
+		IsTruncated           bool
+		NextContinuationToken string
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("s3: listing %s: %v", l.bucket.bucket, err)
+	}
+	l.page = result.Contents
+	l.token = result.NextContinuationToken
+	l.done = !result.IsTruncated || l.token == ""
+	return nil
+}
+
+type S3ObjectIterator struct {
+	l s3Lister
+}
+
+func (it *S3ObjectIterator) Next() (string, error) {
+	o, err := it.l.next()
+	if err != nil {
+		return "", err
+	}
+	return o.Key, nil
+}
+
+type S3ObjectAttrsIterator struct {
+	l s3Lister
+}
+
+// Next returns the attributes of the next object. ListObjectsV2 does not
+// return content types, so they are derived from the extensions of names.
+func (it *S3ObjectAttrsIterator) Next() (*ObjectAttrs, error) {
+	o, err := it.l.next()
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectAttrs{
+		Name:        o.Key,
+		Size:        o.Size,
+		Updated:     o.LastModified,
+		ContentType: mime.TypeByExtension(path.Ext(o.Key)),
+	}, nil
+}
+
+// signS3 signs req, whose body is body, with AWS Signature Version 4 at
+// time t. It signs the Host header and all the headers of req.
+func signS3(req *http.Request, body []byte, region string, creds S3Credentials, t time.Time) {
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Date", t.UTC().Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	var names []string
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", k, headers[k])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := s3Scope(t, region)
+	signature := s3Signature(canonicalRequest, t, region, creds)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s", creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// presignS3 adds to u, the URL of an object, the query parameters that
+// authorize a GET request for the object at time t, and for ttl after.
+// Only the Host header is signed, and the payload is not.
+func presignS3(u *url.URL, region string, creds S3Credentials, t time.Time, ttl time.Duration) {
+	q := u.Query()
+	q.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	q.Set("X-Amz-Credential", creds.AccessKeyID+"/"+s3Scope(t, region))
+	q.Set("X-Amz-Date", t.UTC().Format(s3TimeFormat))
+	q.Set("X-Amz-Expires", fmt.Sprint(int64(ttl/time.Second)))
+	q.Set("X-Amz-SignedHeaders", "host")
+	u.RawQuery = s3Query(q)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	u.RawQuery += "&X-Amz-Signature=" + s3Signature(canonicalRequest, t, region, creds)
+}
+
+const s3TimeFormat = "20060102T150405Z"
+
+// s3Scope returns the credential scope of requests signed at time t.
+func s3Scope(t time.Time, region string) string {
+	return t.UTC().Format("20060102") + "/" + region + "/s3/aws4_request"
+}
+
+// s3Signature returns the signature of a canonical request at time t.
+func s3Signature(canonicalRequest string, t time.Time, region string, creds S3Credentials) string {
+	crHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + t.UTC().Format(s3TimeFormat) + "\n" + s3Scope(t, region) + "\n" + hex.EncodeToString(crHash[:])
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, s := range []string{t.UTC().Format("20060102"), region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Query returns the canonical encoding of q: its parameters sorted by
+// name, and escaped as S3 requires.
+func s3Query(q url.Values) string {
+	var keys []string
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			parts = append(parts, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape escapes s as S3 requires of the paths and query parameters of
+// signed requests: every byte but the unreserved characters is
+// percent-encoded, and so is '/' in query parameters.
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -7,16 +7,25 @@
 package storage
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"mime"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"cloud.google.com/go/storage"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 )
 
@@ -28,33 +37,95 @@ var (
 var (
 	ErrObjectIteratorDone = errors.New("object iterator done")
 	ErrObjectNotExist     = errors.New("object not exist")
+	// ErrPreconditionFailed is returned by the Close method of a writer
+	// from NewWriterIf if the object changed since its generation was read.
+	ErrPreconditionFailed = errors.New("object precondition failed")
 )
 
 type BucketHandle interface {
 	Object(name string) ObjectHandle
 	Objects(ctx context.Context, prefix string) ObjectIterator
+	// ObjectsWithAttrs is like Objects, but lists the attributes of the
+	// objects along with their names.
+	ObjectsWithAttrs(ctx context.Context, prefix string) ObjectAttrsIterator
+	// Delete removes the named object, as the Delete method of its
+	// ObjectHandle.
+	Delete(ctx context.Context, name string) error
+	// DeleteAll removes the objects with the given prefix, and returns the
+	// number of objects it removed. Objects removed meanwhile by others are
+	// not counted. An empty prefix removes all the objects of the bucket.
+	DeleteAll(ctx context.Context, prefix string) (int, error)
+	// SignedURL returns a URL from which the named object can be downloaded
+	// without credentials until ttl has elapsed, so that clients can fetch
+	// large objects directly from the store. Buckets that are not served
+	// over HTTP return the location of the object instead, with no expiry.
+	SignedURL(name string, ttl time.Duration) (string, error)
 	URI() string
 }
 
 type ObjectHandle interface {
 	NewReader(ctx context.Context) (io.ReadCloser, error)
 	NewWriter(ctx context.Context) (io.WriteCloser, error)
+	// NewWriterIf is like NewWriter, but the object is only written if its
+	// generation is still gen, or, if gen is 0, if it does not exist.
+	// Otherwise, the writer's Close returns ErrPreconditionFailed and the
+	// object is left unmodified. This keeps concurrent read-modify-write
+	// cycles on an object from losing each other's changes.
+	NewWriterIf(ctx context.Context, gen int64) (io.WriteCloser, error)
+	// NewWriterWithMetadata is like NewWriterIf, but the object is written
+	// with the custom metadata md, which is committed along with its
+	// content, so that readers never see the object without it.
+	NewWriterWithMetadata(ctx context.Context, gen int64, md map[string]string) (io.WriteCloser, error)
+	// Generation returns the generation of the object, which changes each
+	// time the object is written. It returns ErrObjectNotExist if there is
+	// no such object.
+	Generation(ctx context.Context) (int64, error)
+	// Metadata returns the custom metadata of the object, which is empty
+	// unless set with SetMetadata.
+	Metadata(ctx context.Context) (map[string]string, error)
+	// SetMetadata merges md into the custom metadata of an existing object.
+	SetMetadata(ctx context.Context, md map[string]string) error
+	// Delete removes the object and its metadata. It returns
+	// ErrObjectNotExist if there is no such object.
+	Delete(ctx context.Context) error
 }
 
+// SuspectMetadata is the metadata key with which telemetry.go.dev tags
+// uploaded reports that look like bot traffic or replays. Its value is a
+// comma-separated list of reasons.
+const SuspectMetadata = "suspect"
+
 type ObjectIterator interface {
 	Next() (name string, err error)
 }
 
+// ObjectAttrs holds the attributes of an object listed by ObjectsWithAttrs.
+type ObjectAttrs struct {
+	Name        string
+	Size        int64
+	Updated     time.Time // time of the last write of the object
+	ContentType string
+}
+
+type ObjectAttrsIterator interface {
+	Next() (*ObjectAttrs, error)
+}
+
 type GCSBucket struct {
 	*storage.BucketHandle
-	url string
+	url      string
+	compress bool // see Compressed
 }
 
 // Copy read the content from the source and write the content to the
 // destination.
+//
+// Content is copied in chunks, and Copy stops promptly with ctx.Err() if ctx
+// is canceled or its deadline expires before the copy completes. In that case,
+// the destination is left unmodified.
 func Copy(ctx context.Context, dst, src ObjectHandle) error {
-	srcGCS, srcOk := src.(*GCSObject)
-	dstGCS, dstOk := dst.(*GCSObject)
+	srcGCS, srcOk := unwrap(src).(*GCSObject)
+	dstGCS, dstOk := unwrap(dst).(*GCSObject)
 	if srcOk && dstOk {
 		if _, err := dstGCS.CopierFrom(srcGCS.ObjectHandle).Run(ctx); err != nil {
 			return fmt.Errorf("failed to use gcs copier to copy from %s to %s: %w", srcGCS.ObjectName(), dstGCS.ObjectName(), err)
@@ -62,6 +133,11 @@ func Copy(ctx context.Context, dst, src ObjectHandle) error {
 		return nil
 	}
 
+	// Derive a cancelable context, so that an error on either side aborts
+	// the writer rather than committing a partial object.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	reader, err := src.NewReader(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to create reader for source: %w", err)
@@ -72,13 +148,49 @@ func Copy(ctx context.Context, dst, src ObjectHandle) error {
 	if err != nil {
 		return fmt.Errorf("failed to create writer for destination: %w", err)
 	}
-	defer writer.Close()
-
-	if _, err := io.Copy(writer, reader); err != nil {
+	// abort cancels the writer's context before closing it, so that the
+	// object is left unmodified.
+	abort := func(err error) error {
+		cancel()
+		writer.Close()
 		return err
 	}
 
-	return nil
+	buf := make([]byte, copyChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return abort(err)
+		}
+		n, rerr := reader.Read(buf)
+		if n > 0 {
+			if _, err := writer.Write(buf[:n]); err != nil {
+				return abort(err)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return abort(rerr)
+		}
+	}
+
+	return writer.Close()
+}
+
+// copyChunkSize is the size of the chunks in which Copy streams objects.
+const copyChunkSize = 32 * 1024
+
+// unwrap returns the object that o wraps, if it has an Unwrap method, as
+// the objects of instrumented buckets do, and o otherwise.
+func unwrap(o ObjectHandle) ObjectHandle {
+	for {
+		u, ok := o.(interface{ Unwrap() ObjectHandle })
+		if !ok {
+			return o
+		}
+		o = u.Unwrap()
+	}
 }
 
 func NewGCSBucket(ctx context.Context, project, bucket string) (BucketHandle, error) {
@@ -95,7 +207,7 @@ func NewGCSBucket(ctx context.Context, project, bucket string) (BucketHandle, er
 		}
 	}
 	url := "https://storage.googleapis.com/" + bucket
-	return &GCSBucket{bkt, url}, nil
+	return &GCSBucket{BucketHandle: bkt, url: url}, nil
 }
 
 func (b *GCSBucket) Object(name string) ObjectHandle {
@@ -104,18 +216,151 @@ func (b *GCSBucket) Object(name string) ObjectHandle {
 
 type GCSObject struct {
 	*storage.ObjectHandle
+	compress bool
 }
 
 func NewGCSObject(b *GCSBucket, name string) ObjectHandle {
-	return &GCSObject{b.BucketHandle.Object(name)}
+	return &GCSObject{b.BucketHandle.Object(name), b.compress && compressible(name)}
 }
 
 func (o *GCSObject) NewReader(ctx context.Context) (io.ReadCloser, error) {
-	return o.ObjectHandle.NewReader(ctx)
+	r, err := o.ObjectHandle.NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, ErrObjectNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
 }
 
 func (o *GCSObject) NewWriter(ctx context.Context) (io.WriteCloser, error) {
-	return o.ObjectHandle.NewWriter(ctx), nil
+	w := o.ObjectHandle.NewWriter(ctx)
+	return o.compressWriter(w, w), nil
+}
+
+func (o *GCSObject) NewWriterIf(ctx context.Context, gen int64) (io.WriteCloser, error) {
+	return o.NewWriterWithMetadata(ctx, gen, nil)
+}
+
+func (o *GCSObject) NewWriterWithMetadata(ctx context.Context, gen int64, md map[string]string) (io.WriteCloser, error) {
+	cond := storage.Conditions{GenerationMatch: gen}
+	if gen == 0 {
+		cond = storage.Conditions{DoesNotExist: true}
+	}
+	w := o.ObjectHandle.If(cond).NewWriter(ctx)
+	w.ObjectAttrs.Metadata = md
+	return o.compressWriter(w, &gcsWriter{w}), nil
+}
+
+// compressWriter returns wc, which writes to w, compressing what is written
+// to it if the object is compressed.
+func (o *GCSObject) compressWriter(w *storage.Writer, wc io.WriteCloser) io.WriteCloser {
+	if !o.compress {
+		return wc
+	}
+	w.ContentEncoding = "gzip"
+	return newGzipWriter(wc)
+}
+
+func (o *GCSObject) Generation(ctx context.Context) (int64, error) {
+	attrs, err := o.ObjectHandle.Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return 0, ErrObjectNotExist
+	}
+	if err != nil {
+		return 0, err
+	}
+	return attrs.Generation, nil
+}
+
+// gcsWriter is a conditional writer for a GCSObject, whose Close reports a
+// failed precondition as ErrPreconditionFailed.
+type gcsWriter struct {
+	*storage.Writer
+}
+
+func (w *gcsWriter) Close() error {
+	err := w.Writer.Close()
+	var e *googleapi.Error
+	if errors.As(err, &e) && e.Code == http.StatusPreconditionFailed {
+		return ErrPreconditionFailed
+	}
+	return err
+}
+
+func (o *GCSObject) Metadata(ctx context.Context) (map[string]string, error) {
+	attrs, err := o.ObjectHandle.Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, ErrObjectNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attrs.Metadata, nil
+}
+
+func (o *GCSObject) SetMetadata(ctx context.Context, md map[string]string) error {
+	_, err := o.ObjectHandle.Update(ctx, storage.ObjectAttrsToUpdate{Metadata: md})
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return ErrObjectNotExist
+	}
+	return err
+}
+
+func (o *GCSObject) Delete(ctx context.Context) error {
+	err := o.ObjectHandle.Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return ErrObjectNotExist
+	}
+	return err
+}
+
+func (b *GCSBucket) Delete(ctx context.Context, name string) error {
+	return b.Object(name).Delete(ctx)
+}
+
+// gcsDeleteConcurrency is the number of objects that GCSBucket.DeleteAll
+// deletes at once. Cloud Storage has no batch delete in its Go client, so
+// each object is a request.
+const gcsDeleteConcurrency = 16
+
+func (b *GCSBucket) DeleteAll(ctx context.Context, prefix string) (int, error) {
+	return deleteAll(ctx, b, prefix, gcsDeleteConcurrency)
+}
+
+// deleteAll deletes the objects of b with the given prefix, up to
+// concurrency at once.
+func deleteAll(ctx context.Context, b BucketHandle, prefix string, concurrency int) (int, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	var deleted atomic.Int64
+	it := b.Objects(ctx, prefix)
+	for {
+		name, err := it.Next()
+		if errors.Is(err, ErrObjectIteratorDone) {
+			break
+		}
+		if err != nil {
+			// A failed deletion cancels ctx, failing the listing too.
+			if werr := g.Wait(); werr != nil {
+				err = werr
+			}
+			return int(deleted.Load()), err
+		}
+		g.Go(func() error {
+			err := b.Delete(ctx, name)
+			if errors.Is(err, ErrObjectNotExist) {
+				return nil
+			}
+			if err == nil {
+				deleted.Add(1)
+			}
+			return err
+		})
+	}
+	err := g.Wait()
+	return int(deleted.Load()), err
 }
 
 func (b *GCSBucket) Objects(ctx context.Context, prefix string) ObjectIterator {
@@ -137,12 +382,44 @@ func (it *GCSObjectIterator) Next() (elem string, err error) {
 	return o.Name, nil
 }
 
+func (b *GCSBucket) ObjectsWithAttrs(ctx context.Context, prefix string) ObjectAttrsIterator {
+	q := &storage.Query{Prefix: prefix}
+	if err := q.SetAttrSelection([]string{"Name", "Size", "Updated", "ContentType"}); err != nil {
+		panic(err) // the attributes are valid
+	}
+	return &GCSObjectAttrsIterator{b.BucketHandle.Objects(ctx, q)}
+}
+
+type GCSObjectAttrsIterator struct {
+	*storage.ObjectIterator
+}
+
+func (it *GCSObjectAttrsIterator) Next() (*ObjectAttrs, error) {
+	o, err := it.ObjectIterator.Next()
+	if errors.Is(err, iterator.Done) {
+		return nil, ErrObjectIteratorDone
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectAttrs{Name: o.Name, Size: o.Size, Updated: o.Updated, ContentType: o.ContentType}, nil
+}
+
 func (b *GCSBucket) URI() string {
 	return b.url
 }
 
+func (b *GCSBucket) SignedURL(name string, ttl time.Duration) (string, error) {
+	return b.BucketHandle.SignedURL(name, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(ttl),
+	})
+}
+
 type FSBucket struct {
 	dir, bucket, uri string
+	compress         bool // see Compressed
 }
 
 func NewFSBucket(ctx context.Context, dir, bucket string) (BucketHandle, error) {
@@ -153,7 +430,7 @@ func NewFSBucket(ctx context.Context, dir, bucket string) (BucketHandle, error)
 	if err != nil {
 		return nil, err
 	}
-	return &FSBucket{dir, bucket, uri}, nil
+	return &FSBucket{dir: dir, bucket: bucket, uri: uri}, nil
 }
 
 func (b *FSBucket) Object(name string) ObjectHandle {
@@ -162,11 +439,12 @@ func (b *FSBucket) Object(name string) ObjectHandle {
 
 type FSObject struct {
 	filename string
+	compress bool
 }
 
 func NewFSObject(b *FSBucket, name string) ObjectHandle {
 	filename := filepath.Join(b.dir, b.bucket, filepath.FromSlash(name))
-	return &FSObject{filename}
+	return &FSObject{filename: filename, compress: b.compress && compressible(name)}
 }
 
 func (o *FSObject) Filename() string {
@@ -174,27 +452,328 @@ func (o *FSObject) Filename() string {
 }
 
 func (o *FSObject) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	r, err := os.Open(o.filename)
 	if errors.Is(err, os.ErrNotExist) {
 		return nil, ErrObjectNotExist
 	}
-	return r, err
+	if err != nil {
+		return nil, err
+	}
+	if !compressible(o.filename) || !isGzip(r) {
+		return &fsReader{ctx, r, r}, nil
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return &fsReader{ctx, zr, r}, nil
 }
 
+// NewWriter returns a writer for the object.
+//
+// As with Cloud Storage, the object is not created or replaced until the
+// writer is successfully closed. If ctx is done before then, writes fail and
+// the object is left unmodified.
 func (o *FSObject) NewWriter(ctx context.Context) (io.WriteCloser, error) {
-	if err := os.MkdirAll(filepath.Dir(o.filename), os.ModePerm); err != nil {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(o.filename)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(o.filename)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	w := &fsWriter{ctx: ctx, f: f, filename: o.filename}
+	if o.compress {
+		w.gz = gzip.NewWriter(f)
+	}
+	return w, nil
+}
+
+// NewWriterIf returns a conditional writer for the object, as NewWriter.
+// The generation of an FSObject is its modification time. The condition is
+// checked when the writer is closed, atomically with respect to the other
+// conditional writers of the object, in this process or in others sharing
+// the directory, such as the server and worker in local development.
+func (o *FSObject) NewWriterIf(ctx context.Context, gen int64) (io.WriteCloser, error) {
+	return o.NewWriterWithMetadata(ctx, gen, nil)
+}
+
+// NewWriterWithMetadata returns a conditional writer for the object, as
+// NewWriterIf. The metadata file is written before the object's file is
+// renamed into place, so the object never appears without its metadata.
+func (o *FSObject) NewWriterWithMetadata(ctx context.Context, gen int64, md map[string]string) (io.WriteCloser, error) {
+	w, err := o.NewWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fw := w.(*fsWriter)
+	fw.conditional = true
+	fw.gen = gen
+	fw.metadata = md
+	return fw, nil
+}
+
+func (o *FSObject) Generation(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	fi, err := os.Stat(o.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, ErrObjectNotExist
+	}
+	if err != nil {
+		return 0, err
+	}
+	return fi.ModTime().UnixNano(), nil
+}
+
+// metadataFile returns the name of the file holding the object's metadata,
+// which is hidden from Objects.
+func (o *FSObject) metadataFile() string {
+	return filepath.Join(filepath.Dir(o.filename), "."+filepath.Base(o.filename)+fsMetadataSuffix)
+}
+
+const fsMetadataSuffix = ".metadata"
+
+func (o *FSObject) Metadata(ctx context.Context) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(o.filename); errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotExist
+	}
+	data, err := os.ReadFile(o.metadataFile())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
 		return nil, err
 	}
-	return os.Create(o.filename)
+	var md map[string]string
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("invalid metadata for %s: %v", o.filename, err)
+	}
+	return md, nil
+}
+
+func (o *FSObject) SetMetadata(ctx context.Context, md map[string]string) error {
+	old, err := o.Metadata(ctx)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		old = make(map[string]string)
+	}
+	for k, v := range md {
+		old[k] = v
+	}
+	return o.writeMetadata(old)
+}
+
+// writeMetadata replaces the metadata of the object with md, removing the
+// metadata file if md is empty.
+func (o *FSObject) writeMetadata(md map[string]string) error {
+	if len(md) == 0 {
+		if err := os.Remove(o.metadataFile()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(o.metadataFile(), data, 0666)
+}
+
+func (o *FSObject) Delete(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := os.Remove(o.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrObjectNotExist
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(o.metadataFile()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// fsReader is an io.ReadCloser for an FSObject that fails once its context is
+// done.
+type fsReader struct {
+	ctx context.Context
+	r   io.Reader // f, or a decompressor reading from f
+	f   *os.File
+}
+
+func (r *fsReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+func (r *fsReader) Close() error {
+	return r.f.Close()
+}
+
+// fsWriter is an io.WriteCloser for an FSObject. It writes to a temporary
+// file, which is renamed to the object's file when the writer is closed.
+type fsWriter struct {
+	ctx      context.Context
+	f        *os.File     // temporary file
+	gz       *gzip.Writer // compressor writing to f, if the object is compressed
+	filename string       // destination
+	closed   bool
+	err      error // result of the first Close
+
+	conditional bool  // commit only if the destination's generation is gen
+	gen         int64 // 0 if the destination must not exist
+
+	metadata map[string]string // custom metadata of the committed object
+}
+
+// fsLockTimeout is the age after which the lock file of an FSObject is
+// assumed to have been left by a process that died during a commit.
+const fsLockTimeout = 10 * time.Second
+
+const fsLockSuffix = ".lock"
+
+// lockFSObject serializes the commits of the conditional writers of the
+// named file, so that the check of the condition and the commit are atomic.
+// The lock is a hidden file created exclusively next to the file, so it
+// also excludes writers in other processes. The caller must call unlock
+// once the commit is done.
+func lockFSObject(ctx context.Context, filename string) (unlock func(), _ error) {
+	lock := filepath.Join(filepath.Dir(filename), "."+filepath.Base(filename)+fsLockSuffix)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if fi, err := os.Stat(lock); err == nil && time.Since(fi.ModTime()) > fsLockTimeout {
+			os.Remove(lock)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func (w *fsWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.f.Write(p)
+}
+
+// Close commits the object, unless the writer's context is done. Subsequent
+// calls return the result of the first.
+func (w *fsWriter) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	tmp := w.f.Name()
+	if w.gz != nil {
+		w.err = w.gz.Close()
+	}
+	if err := w.f.Close(); w.err == nil {
+		w.err = err
+	}
+	if w.err == nil {
+		w.err = w.ctx.Err()
+	}
+	if w.err == nil && w.conditional {
+		unlock, err := lockFSObject(w.ctx, w.filename)
+		if err != nil {
+			w.err = err
+		} else {
+			defer unlock()
+			gen, err := (&FSObject{filename: w.filename}).Generation(w.ctx)
+			if errors.Is(err, ErrObjectNotExist) {
+				gen, err = 0, nil
+			}
+			if err != nil {
+				w.err = err
+			} else if gen != w.gen {
+				w.err = ErrPreconditionFailed
+			}
+		}
+	}
+	if w.err == nil {
+		// As with Cloud Storage, a new object has only the metadata it
+		// was written with.
+		o := FSObject{filename: w.filename}
+		w.err = o.writeMetadata(w.metadata)
+	}
+	if w.err == nil {
+		w.err = os.Rename(tmp, w.filename)
+	}
+	if w.err != nil {
+		os.Remove(tmp)
+	}
+	return w.err
+}
+
+func (b *FSBucket) Delete(ctx context.Context, name string) error {
+	return b.Object(name).Delete(ctx)
+}
+
+func (b *FSBucket) DeleteAll(ctx context.Context, prefix string) (int, error) {
+	return deleteAll(ctx, b, prefix, 1)
 }
 
 func (b *FSBucket) Objects(ctx context.Context, prefix string) ObjectIterator {
+	names, err := b.list(ctx, prefix)
+	return &FSObjectIterator{ctx, names, err, 0}
+}
+
+// ObjectsWithAttrs lists the objects with the given prefix, as Objects. The
+// size and update time of an FSObject are those of its file, and its content
+// type is derived from the extension of its name.
+func (b *FSBucket) ObjectsWithAttrs(ctx context.Context, prefix string) ObjectAttrsIterator {
+	names, err := b.list(ctx, prefix)
+	return &FSObjectAttrsIterator{FSObjectIterator{ctx, names, err, 0}, b}
+}
+
+// list returns the names of the objects with the given prefix.
+func (b *FSBucket) list(ctx context.Context, prefix string) ([]string, error) {
 	var names []string
 	err := fs.WalkDir(
 		os.DirFS(filepath.Join(b.dir, b.bucket)),
 		".",
 		func(path string, d fs.DirEntry, err error) error {
-			if d.IsDir() {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if d.IsDir() || isFSTempFile(d.Name()) || isFSMetadataFile(d.Name()) || isFSLockFile(d.Name()) {
 				return nil
 			}
 			name := filepath.ToSlash(path)
@@ -204,16 +783,41 @@ func (b *FSBucket) Objects(ctx context.Context, prefix string) ObjectIterator {
 			return nil
 		},
 	)
-	return &FSObjectIterator{names, err, 0}
+	return names, err
+}
+
+// isFSTempFile reports whether name is the base name of a temporary file
+// created by an FSObject writer.
+func isFSTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".tmp")
+}
+
+// isFSMetadataFile reports whether name is the base name of a file holding
+// the metadata of an FSObject.
+func isFSMetadataFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.HasSuffix(name, fsMetadataSuffix)
+}
+
+// isFSLockFile reports whether name is the base name of the lock file of an
+// FSObject.
+func isFSLockFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.HasSuffix(name, fsLockSuffix)
 }
 
 type FSObjectIterator struct {
+	ctx   context.Context
 	names []string
 	err   error
 	index int
 }
 
 func (it *FSObjectIterator) Next() (name string, err error) {
+	if it.err != nil {
+		return "", it.err
+	}
+	if err := it.ctx.Err(); err != nil {
+		return "", err
+	}
 	if it.index >= len(it.names) {
 		return "", ErrObjectIteratorDone
 	}
@@ -222,6 +826,38 @@ func (it *FSObjectIterator) Next() (name string, err error) {
 	return name, nil
 }
 
+type FSObjectAttrsIterator struct {
+	names  FSObjectIterator
+	bucket *FSBucket
+}
+
+func (it *FSObjectAttrsIterator) Next() (*ObjectAttrs, error) {
+	for {
+		name, err := it.names.Next()
+		if err != nil {
+			return nil, err
+		}
+		fi, err := os.Stat(filepath.Join(it.bucket.dir, it.bucket.bucket, filepath.FromSlash(name)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue // deleted since it was listed
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &ObjectAttrs{
+			Name:        name,
+			Size:        fi.Size(),
+			Updated:     fi.ModTime(),
+			ContentType: mime.TypeByExtension(path.Ext(name)),
+		}, nil
+	}
+}
+
 func (b *FSBucket) URI() string {
 	return b.uri
 }
+
+// SignedURL returns the path of the object's file.
+func (b *FSBucket) SignedURL(name string, ttl time.Duration) (string, error) {
+	return filepath.Join(b.uri, filepath.FromSlash(name)), nil
+}
@@ -5,10 +5,18 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -141,3 +149,515 @@ func read(ctx context.Context, s BucketHandle, object string) (any, error) {
 	}
 	return data, nil
 }
+
+func TestFSCanceled(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBucket(ctx, t.TempDir(), "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := jsondata{"foo", "bar", map[string]int{"cancel": 1}}
+	if err := write(ctx, s, "prefix/source-file", data); err != nil {
+		t.Fatal(err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	// Copy with a canceled context fails, and doesn't create the destination.
+	if err := Copy(canceled, s.Object("prefix/dest-file"), s.Object("prefix/source-file")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Copy() with canceled context = %v, want %v", err, context.Canceled)
+	}
+	if _, err := s.Object("prefix/dest-file").NewReader(ctx); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("after canceled Copy(), NewReader(dest-file) = %v, want %v", err, ErrObjectNotExist)
+	}
+
+	// Readers fail once their context is canceled.
+	readCtx, cancelRead := context.WithCancel(ctx)
+	r, err := s.Object("prefix/source-file").NewReader(readCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	cancelRead()
+	if _, err := r.Read(make([]byte, 1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Read() after cancel = %v, want %v", err, context.Canceled)
+	}
+
+	// Writers don't commit the object if their context is canceled before
+	// Close, and the in-progress object is not visible to Objects.
+	writeCtx, cancelWrite := context.WithCancel(ctx)
+	w, err := s.Object("prefix/partial-file").NewWriter(writeCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("partial")); err != nil {
+		t.Fatal(err)
+	}
+	it := s.Objects(ctx, "prefix/partial")
+	if name, err := it.Next(); !errors.Is(err, ErrObjectIteratorDone) {
+		t.Errorf("Objects() during write returned %q, %v; want %v", name, err, ErrObjectIteratorDone)
+	}
+	cancelWrite()
+	if err := w.Close(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Close() after cancel = %v, want %v", err, context.Canceled)
+	}
+	if _, err := s.Object("prefix/partial-file").NewReader(ctx); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("after canceled write, NewReader(partial-file) = %v, want %v", err, ErrObjectNotExist)
+	}
+
+	// Listing fails with a canceled context.
+	if _, err := s.Objects(canceled, "").Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Objects().Next() with canceled context = %v, want %v", err, context.Canceled)
+	}
+}
+
+// failingObject is an object whose readers fail after reading its content.
+type failingObject struct {
+	ObjectHandle
+	err error
+}
+
+func (o failingObject) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(o.err))), nil
+}
+
+func TestCopyReadError(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBucket(ctx, t.TempDir(), "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	errRead := errors.New("read failed")
+	src := failingObject{s.Object("prefix/source-file"), errRead}
+	if err := Copy(ctx, s.Object("prefix/dest-file"), src); !errors.Is(err, errRead) {
+		t.Errorf("Copy() with failing reader = %v, want %v", err, errRead)
+	}
+	if _, err := s.Object("prefix/dest-file").NewReader(ctx); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("after failed Copy(), NewReader(dest-file) = %v, want %v", err, ErrObjectNotExist)
+	}
+}
+
+func TestFSMetadata(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBucket(ctx, t.TempDir(), "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := s.Object("prefix/object")
+	if err := obj.SetMetadata(ctx, map[string]string{"k": "v"}); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("SetMetadata() on missing object = %v, want %v", err, ErrObjectNotExist)
+	}
+	if err := write(ctx, s, "prefix/object", writeData); err != nil {
+		t.Fatal(err)
+	}
+	if md, err := obj.Metadata(ctx); err != nil || len(md) != 0 {
+		t.Errorf("Metadata() of new object = %v, %v, want empty", md, err)
+	}
+	if err := obj.SetMetadata(ctx, map[string]string{"a": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := obj.SetMetadata(ctx, map[string]string{"b": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	md, err := obj.Metadata(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"a": "1", "b": "2"}, md); diff != "" {
+		t.Errorf("Metadata() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Metadata is not listed as an object.
+	name, err := s.Objects(ctx, "").Next()
+	if err != nil || name != "prefix/object" {
+		t.Errorf("Objects().Next() = %q, %v, want %q", name, err, "prefix/object")
+	}
+
+	// Rewriting the object clears its metadata.
+	if err := write(ctx, s, "prefix/object", writeData); err != nil {
+		t.Fatal(err)
+	}
+	if md, err := obj.Metadata(ctx); err != nil || len(md) != 0 {
+		t.Errorf("Metadata() of rewritten object = %v, %v, want empty", md, err)
+	}
+
+	// An object written with metadata has it as soon as it exists.
+	other := s.Object("prefix/other")
+	w, err := other.NewWriterWithMetadata(ctx, 0, map[string]string{"c": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewEncoder(w).Encode(writeData); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Metadata(ctx); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("Metadata() before Close = %v, want %v", err, ErrObjectNotExist)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	md, err = other.Metadata(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"c": "3"}, md); diff != "" {
+		t.Errorf("Metadata() of object written with metadata mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFSDelete(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBucket(ctx, t.TempDir(), "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := write(ctx, s, "prefix/object", writeData); err != nil {
+		t.Fatal(err)
+	}
+	obj := s.Object("prefix/object")
+	if err := obj.SetMetadata(ctx, map[string]string{"a": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := obj.Delete(ctx); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := obj.NewReader(ctx); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("after Delete(), NewReader() = %v, want %v", err, ErrObjectNotExist)
+	}
+	if err := obj.Delete(ctx); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("second Delete() = %v, want %v", err, ErrObjectNotExist)
+	}
+
+	// A new object of the same name has none of the old metadata.
+	if err := write(ctx, s, "prefix/object", writeData); err != nil {
+		t.Fatal(err)
+	}
+	if md, err := obj.Metadata(ctx); err != nil || len(md) != 0 {
+		t.Errorf("Metadata() of recreated object = %v, %v, want empty", md, err)
+	}
+}
+
+func TestFSNewWriterIf(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBucket(ctx, t.TempDir(), "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testNewWriterIf(t, ctx, s)
+}
+
+func testNewWriterIf(t *testing.T, ctx context.Context, s BucketHandle) {
+	obj := s.Object("object")
+	writeIf := func(gen int64, data string) error {
+		w, err := obj.NewWriterIf(ctx, gen)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+		return w.Close()
+	}
+	if _, err := obj.Generation(ctx); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("Generation() of missing object = %v, want %v", err, ErrObjectNotExist)
+	}
+	if err := writeIf(0, "first"); err != nil {
+		t.Fatalf("creating the object: %v", err)
+	}
+	if err := writeIf(0, "second"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("creating an existing object: got %v, want %v", err, ErrPreconditionFailed)
+	}
+	gen, err := obj.Generation(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeIf(gen, "third"); err != nil {
+		t.Fatalf("replacing the object at its generation: %v", err)
+	}
+	if err := writeIf(gen, "fourth"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("replacing the object at a former generation: got %v, want %v", err, ErrPreconditionFailed)
+	}
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if got, _ := io.ReadAll(r); string(got) != "third" {
+		t.Errorf("object = %q, want %q", got, "third")
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	ctx := context.Background()
+	runTest(t, ctx, NewMemBucket("test-bucket"))
+}
+
+func TestMemNewWriterIf(t *testing.T) {
+	testNewWriterIf(t, context.Background(), NewMemBucket("test-bucket"))
+}
+
+func TestMemFail(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemBucket("test-bucket")
+	if err := write(ctx, s, "2024-01-01/a.json", writeData); err != nil {
+		t.Fatal(err)
+	}
+	errInjected := errors.New("injected")
+	s.Fail = func(op, name string) error {
+		if op == MemWrite && strings.HasPrefix(name, "2024-01-02/") {
+			return errInjected
+		}
+		return nil
+	}
+	if err := write(ctx, s, "2024-01-02/a.json", writeData); !errors.Is(err, errInjected) {
+		t.Errorf("write with an injected failure = %v, want %v", err, errInjected)
+	}
+	if _, err := s.Object("2024-01-02/a.json").Generation(ctx); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("Generation() of an object whose write failed = %v, want %v", err, ErrObjectNotExist)
+	}
+	if _, err := read(ctx, s, "2024-01-01/a.json"); err != nil {
+		t.Errorf("read without an injected failure: %v", err)
+	}
+
+	// Readers fail once their context is canceled, and writers do not
+	// commit.
+	readCtx, cancel := context.WithCancel(ctx)
+	r, err := s.Object("2024-01-01/a.json").NewReader(readCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := s.Object("2024-01-01/b.json").NewWriter(readCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, err := r.Read(make([]byte, 1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Read() after cancel = %v, want %v", err, context.Canceled)
+	}
+	if err := w.Close(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Close() after cancel = %v, want %v", err, context.Canceled)
+	}
+	if n, err := s.DeleteAll(ctx, ""); n != 1 || err != nil {
+		t.Errorf("DeleteAll() = %d, %v; want 1, nil", n, err)
+	}
+}
+
+func TestFSLock(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewFSBucket(ctx, dir, "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another process committing the object holds its lock.
+	lock := filepath.Join(dir, "test-bucket", ".object"+fsLockSuffix)
+	if err := os.WriteFile(lock, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	writeIf := func(ctx context.Context) error {
+		w, err := s.Object("object").NewWriterIf(ctx, 0)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "data"); err != nil {
+			return err
+		}
+		return w.Close()
+	}
+	tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := writeIf(tctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("committing a locked object: got %v, want %v", err, context.DeadlineExceeded)
+	}
+	// A lock left by a process that died is broken.
+	old := time.Now().Add(-2 * fsLockTimeout)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeIf(ctx); err != nil {
+		t.Fatalf("committing an object with a stale lock: %v", err)
+	}
+	if _, err := os.Stat(lock); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock file after commit: %v, want it removed", err)
+	}
+	name, err := s.Objects(ctx, "").Next()
+	if err != nil || name != "object" {
+		t.Errorf("Objects().Next() = %q, %v; want %q", name, err, "object")
+	}
+}
+
+func TestFSObjectsWithAttrs(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBucket(ctx, t.TempDir(), "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Second)
+	if err := write(ctx, s, "prefix/object.json", writeData); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(ctx, s, "other/object.json", writeData); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(writeData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	it := s.ObjectsWithAttrs(ctx, "prefix")
+	attrs, err := it.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs.Updated.Before(start) {
+		t.Errorf("Updated = %v, want after %v", attrs.Updated, start)
+	}
+	attrs.Updated = time.Time{}
+	want := &ObjectAttrs{Name: "prefix/object.json", Size: int64(len(data)) + 1, ContentType: "application/json"}
+	if diff := cmp.Diff(want, attrs); diff != "" {
+		t.Errorf("ObjectsWithAttrs() mismatch (-want +got):\n%s", diff)
+	}
+	if attrs, err := it.Next(); err != ErrObjectIteratorDone {
+		t.Errorf("ObjectsWithAttrs().Next() = %v, %v; want %v", attrs, err, ErrObjectIteratorDone)
+	}
+}
+
+func TestFSDeleteAll(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBucket(ctx, t.TempDir(), "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"2024-01-01/a.json", "2024-01-01/b.json", "2024-01-02/a.json"} {
+		if err := write(ctx, s, name, writeData); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Delete(ctx, "2024-01-02/a.json"); err != nil {
+		t.Errorf("Delete() = %v", err)
+	}
+	if err := s.Delete(ctx, "2024-01-02/a.json"); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("Delete() of a deleted object = %v, want %v", err, ErrObjectNotExist)
+	}
+	if n, err := s.DeleteAll(ctx, "2024-01-01"); n != 2 || err != nil {
+		t.Errorf("DeleteAll() = %d, %v; want 2, nil", n, err)
+	}
+	if name, err := s.Objects(ctx, "").Next(); err != ErrObjectIteratorDone {
+		t.Errorf("Objects().Next() after DeleteAll = %q, %v; want %v", name, err, ErrObjectIteratorDone)
+	}
+}
+
+func TestFSCompressed(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	plain, err := NewFSBucket(ctx, dir, "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed := Compressed(plain)
+	put := func(b BucketHandle, name, data string) {
+		t.Helper()
+		w, err := b.Object(name).NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get := func(b BucketHandle, name string) string {
+		t.Helper()
+		r, err := b.Object(name).NewReader(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	file := func(name string) []byte {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, "test-bucket", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	data := strings.Repeat(`{"Week":"2024-01-01","X":0.5}`+"\n", 100)
+	put(plain, "old.json", data)
+	put(compressed, "new.json", data)
+	put(compressed, "snapshot.json.gz", "already compressed")
+	if f := file("new.json"); !bytes.HasPrefix(f, gzipMagic) || len(f) >= len(data) {
+		t.Errorf("compressed object is %d bytes starting with %q, want less than %d starting with %q", len(f), f[:2], len(data), gzipMagic)
+	}
+	if got := string(file("snapshot.json.gz")); got != "already compressed" {
+		t.Errorf(".gz object stored as %q, want it as is", got)
+	}
+	// Readers of either bucket decompress the objects as needed.
+	for bname, b := range map[string]BucketHandle{"plain": plain, "compressed": compressed} {
+		for _, name := range []string{"old.json", "new.json"} {
+			if got := get(b, name); got != data {
+				t.Errorf("reading %s from the %s bucket: got %d bytes, want %d", name, bname, len(got), len(data))
+			}
+		}
+	}
+}
+
+func TestDigest(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewFSBucket(ctx, t.TempDir(), "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	put := func(data string) {
+		t.Helper()
+		w, err := s.Object("2024-01-01.json").NewWriter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("{}\n{}\n")
+	if err := VerifyDigest(ctx, s, "2024-01-01.json"); !errors.Is(err, ErrObjectNotExist) {
+		t.Errorf("VerifyDigest() without a digest = %v, want %v", err, ErrObjectNotExist)
+	}
+	sum := sha256.Sum256([]byte("{}\n{}\n"))
+	if err := WriteDigest(ctx, s, "2024-01-01.json", sum[:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyDigest(ctx, s, "2024-01-01.json"); err != nil {
+		t.Errorf("VerifyDigest() = %v, want nil", err)
+	}
+	put("{}\n") // truncated
+	if err := VerifyDigest(ctx, s, "2024-01-01.json"); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("VerifyDigest() of a truncated object = %v, want %v", err, ErrDigestMismatch)
+	}
+}
+
+func TestFSSignedURL(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewFSBucket(ctx, dir, "test-bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.SignedURL("prefix/test-object", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "test-bucket", "prefix", "test-object"); got != want {
+		t.Errorf("SignedURL() = %q, want %q", got, want)
+	}
+}
@@ -35,7 +35,8 @@
 //   - description: (optional) a longer description of the chart.
 //   - issue: a Go issue tracker URL proposing the chart configuration.
 //     Multiple issues may be provided by including additional 'issue:' lines.
-//   - type: the chart type. Currently only partition and stack are supported.
+//   - type: the chart type. Currently partition, boolean, stack, and retired
+//     are supported.
 //   - program: the package path of the program for which this chart applies.
 //   - version: (optional) the first program version for which this chart
 //     applies. Must be a valid semver value. If not provided, the chart
@@ -43,6 +44,17 @@
 //   - depth: (optional) stack counters only; the maximum stack depth to collect
 //   - error: (optional) the desired error rate for this chart, which
 //     determines collection rate
+//   - renamed-from: (optional) a former chart name of the counters. Data
+//     uploaded under the former name is shown in this chart, as though it
+//     had been uploaded under the current name. Multiple former names may be
+//     provided by including additional 'renamed-from:' lines.
+//   - versions: (optional) partition charts only; the granularity at which
+//     the buckets of the chart, which are versions, are charted: 'full'
+//     (v1.2.3), 'minor' (v1.2), or 'major' (v1). Buckets of the same
+//     version at that granularity are merged. Multiple granularities may be
+//     provided by including additional 'versions:' lines, each of which
+//     adds a variant of the chart; the first is the chart itself. If not
+//     provided, the buckets are charted as they are.
 //
 // Multiple records are separated by "---" lines.
 //
@@ -71,13 +83,22 @@
 //
 // # Chart types
 //
-// There are two supported chart types for the 'type' field:
+// There are four supported chart types for the 'type' field:
 //
 //   - A 'partition' chart is a bar chart with one bar for each related counter.
 //     The value of the bar is the aggregation of all counts for the program
 //     over the applicable time period.
+//   - A 'boolean' chart is a partition chart over counters created with
+//     counter.NewBool, which are at most 1 per counter file. The value of each
+//     bar is the fraction of the program's reporters that recorded the
+//     counter over the applicable time period.
 //   - A 'stack' chart is not a real chart. It just means that we want to
 //     collect the given stack counter or group of stack counters.
+//   - A 'retired' record is not a chart either. It lists counters that are no
+//     longer collected, and whose data must no longer be shown: for example,
+//     counters removed for privacy reasons. Their counts are redacted from
+//     the merged reports and charts of the server, including those of dates
+//     before their retirement. The issue field explains the retirement.
 //
 // # Example
 //
@@ -122,4 +143,6 @@ type ChartConfig struct {
 	Depth       int
 	Error       float64 // TODO(rfindley) is Error still useful?
 	Version     string
+	RenamedFrom []string `chartconfig:"renamed-from"`
+	Versions    []string
 }
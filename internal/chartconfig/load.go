@@ -44,6 +44,9 @@ func Parse(data []byte) ([]ChartConfig, error) {
 		for i := 0; i < typ.NumField(); i++ {
 			f := typ.Field(i)
 			key := strings.ToLower(f.Name)
+			if k := f.Tag.Get("chartconfig"); k != "" {
+				key = k
+			}
 			if _, ok := fieldParsers[key]; !ok {
 				panic(fmt.Sprintf("no parser for field %q", f.Name))
 			}
@@ -181,6 +184,9 @@ var fieldParsers = map[string]fieldParser{
 	"depth":       parseInt,
 	"error":       parseFloat,
 	"version":     parseString,
+
+	"renamed-from": parseSlice(parseString),
+	"versions":     parseSlice(parseString),
 }
 
 func parseString(v reflect.Value, input string) error {
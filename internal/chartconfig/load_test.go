@@ -51,6 +51,30 @@ version: v2.0.0
 				Version:     "v2.0.0",
 			}},
 		},
+		{
+			"renamed", `
+counter: A:{B,C}
+renamed-from: D
+renamed-from: E
+`,
+			[]chartconfig.ChartConfig{{
+				Counter:     "A:{B,C}",
+				RenamedFrom: []string{"D", "E"},
+			}},
+		},
+		{
+			"versions", `
+counter: A:{v1.0.0,v1.1.0}
+version: v1.0.0
+versions: minor
+versions: full
+`,
+			[]chartconfig.ChartConfig{{
+				Counter:  "A:{v1.0.0,v1.1.0}",
+				Version:  "v1.0.0",
+				Versions: []string{"minor", "full"},
+			}},
+		},
 		{
 			"partial", `
 title: A
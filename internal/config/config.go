@@ -11,6 +11,7 @@ import (
 	"os"
 	"strings"
 
+	"golang.org/x/telemetry/internal/counter"
 	"golang.org/x/telemetry/internal/telemetry"
 )
 
@@ -26,13 +27,23 @@ type Config struct {
 	pgcounter       map[pgkey]bool
 	pgcounterprefix map[pgkey]bool
 	pgstack         map[pgkey]bool
+	pgevent         map[pgkey]*eventConfig
 	rate            map[pgkey]float64
+	depth           map[pgkey]int
+	kind            map[pgkey]telemetry.CounterKind
 }
 
 type pgkey struct {
 	program, key string
 }
 
+// An eventConfig records the attributes of a configured event, and the
+// values that may be uploaded for each.
+type eventConfig struct {
+	attrs  []string
+	values []map[string]bool
+}
+
 func ReadConfig(file string) (*Config, error) {
 	data, err := os.ReadFile(file)
 	if err != nil {
@@ -55,16 +66,21 @@ func NewConfig(cfg *telemetry.UploadConfig) *Config {
 	ucfg.pgcounter = make(map[pgkey]bool, len(ucfg.Programs))
 	ucfg.pgcounterprefix = make(map[pgkey]bool, len(ucfg.Programs))
 	ucfg.pgstack = make(map[pgkey]bool, len(ucfg.Programs))
+	ucfg.pgevent = make(map[pgkey]*eventConfig)
 	ucfg.rate = make(map[pgkey]float64)
+	ucfg.depth = make(map[pgkey]int)
+	ucfg.kind = make(map[pgkey]telemetry.CounterKind)
 	for _, p := range ucfg.Programs {
 		ucfg.program[p.Name] = true
 		for _, v := range p.Versions {
 			ucfg.pgversion[pgkey{p.Name, v}] = true
 		}
 		for _, c := range p.Counters {
+			kind := Kind(c)
 			for _, e := range Expand(c.Name) {
 				ucfg.pgcounter[pgkey{p.Name, e}] = true
 				ucfg.rate[pgkey{p.Name, e}] = c.Rate
+				ucfg.kind[pgkey{p.Name, e}] = kind
 			}
 			prefix, _, found := strings.Cut(c.Name, ":")
 			if found {
@@ -74,6 +90,16 @@ func NewConfig(cfg *telemetry.UploadConfig) *Config {
 		for _, s := range p.Stacks {
 			ucfg.pgstack[pgkey{p.Name, s.Name}] = true
 			ucfg.rate[pgkey{p.Name, s.Name}] = s.Rate
+			ucfg.depth[pgkey{p.Name, s.Name}] = s.Depth
+		}
+		for _, e := range p.Events {
+			ec := &eventConfig{}
+			for _, a := range e.Attrs {
+				ec.attrs = append(ec.attrs, a.Name)
+				ec.values = append(ec.values, set(a.Values))
+			}
+			ucfg.pgevent[pgkey{p.Name, e.Name}] = ec
+			ucfg.rate[pgkey{p.Name, e.Name}] = e.Rate
 		}
 	}
 	return &ucfg
@@ -111,10 +137,64 @@ func (r *Config) HasStack(program, stack string) bool {
 	return r.pgstack[pgkey{program, stack}]
 }
 
+// HasEvent reports whether the event counter with the given name, as
+// recorded in a count file, may be uploaded for the program: the event must
+// be in the config, with the same attributes in the same order, and each
+// attribute value must be one of those enumerated for it.
+func (r *Config) HasEvent(program, name string) bool {
+	event, attrs, values, ok := counter.DecodeEvent(name)
+	if !ok {
+		return false
+	}
+	ec := r.pgevent[pgkey{program, event}]
+	if ec == nil || len(attrs) != len(ec.attrs) {
+		return false
+	}
+	for i, a := range attrs {
+		if a != ec.attrs[i] || !ec.values[i][values[i]] {
+			return false
+		}
+	}
+	return true
+}
+
+// CounterType returns the kind of the counter with the given name, as
+// recorded in a count file, if the config permits it to be uploaded for the
+// program, or "" if not. The name of a stack counter includes its stack,
+// and the name of an event counter its attribute values.
+func (r *Config) CounterType(program, name string) telemetry.CounterKind {
+	if kind, ok := r.kind[pgkey{program, name}]; ok {
+		return kind
+	}
+	if prefix, _, ok := strings.Cut(name, "\n"); ok && r.pgstack[pgkey{program, prefix}] {
+		return telemetry.KindStack
+	}
+	if r.HasEvent(program, name) {
+		return telemetry.KindEvent
+	}
+	return ""
+}
+
+// Kind returns the kind of a counter of an upload config. The counters of
+// configs generated before kinds were recorded are reported as partition
+// counters.
+func Kind(c telemetry.CounterConfig) telemetry.CounterKind {
+	if c.Kind != "" {
+		return c.Kind
+	}
+	return telemetry.KindPartition
+}
+
 func (r *Config) Rate(program, name string) float64 {
 	return r.rate[pgkey{program, name}]
 }
 
+// Depth returns the number of frames of the named stack counter that may be
+// uploaded for the program, or 0 if the depth is not limited.
+func (r *Config) Depth(program, stack string) int {
+	return r.depth[pgkey{program, stack}]
+}
+
 func set(slice []string) map[string]bool {
 	s := make(map[string]bool, len(slice))
 	for _, v := range slice {
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"golang.org/x/telemetry/internal/counter"
 	"golang.org/x/telemetry/internal/telemetry"
 )
 
@@ -95,3 +96,47 @@ func TestInternalConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestCounterType(t *testing.T) {
+	cfg := NewConfig(&telemetry.UploadConfig{
+		Programs: []*telemetry.ProgramConfig{{
+			Name: "golang.org/x/tools/gopls",
+			Counters: []telemetry.CounterConfig{
+				{Name: "gopls/client:{vscode,other}", Kind: telemetry.KindPartition},
+				{Name: "gopls/completion/latency:{<10ms,<50ms}", Kind: telemetry.KindHistogram},
+				{Name: "gopls/gotoolchain", Kind: telemetry.KindBoolean},
+				{Name: "gopls/editor:{emacs,vim}"}, // generated before kinds were recorded
+			},
+			Stacks: []telemetry.CounterConfig{
+				{Name: "gopls/bug", Depth: 16, Kind: telemetry.KindStack},
+			},
+			Events: []telemetry.EventConfig{{
+				Name:  "gopls/request",
+				Attrs: []telemetry.AttrConfig{{Name: "method", Values: []string{"hover"}}},
+			}},
+		}},
+	})
+	const gopls = "golang.org/x/tools/gopls"
+	for _, test := range []struct {
+		name string
+		want telemetry.CounterKind
+	}{
+		{"gopls/client:vscode", telemetry.KindPartition},
+		{"gopls/completion/latency:<50ms", telemetry.KindHistogram},
+		{"gopls/gotoolchain", telemetry.KindBoolean},
+		{"gopls/editor:vim", telemetry.KindPartition},
+		{"gopls/bug\ngolang.org/x/tools/gopls.main:+1", telemetry.KindStack},
+		{"gopls/bug", ""}, // without its stack
+		{counter.EncodeEvent("gopls/request", []string{"method"}, []string{"hover"}), telemetry.KindEvent},
+		{counter.EncodeEvent("gopls/request", []string{"method"}, []string{"rename"}), ""},
+		{"gopls/client:emacs", ""},
+		{"gopls/unknown", ""},
+	} {
+		if got := cfg.CounterType(gopls, test.name); got != test.want {
+			t.Errorf("CounterType(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+	if got := cfg.CounterType("cmd/go", "gopls/client:vscode"); got != "" {
+		t.Errorf("CounterType of another program's counter = %q, want none", got)
+	}
+}
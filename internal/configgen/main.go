@@ -12,7 +12,6 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -32,8 +31,10 @@ import (
 )
 
 var (
-	write = flag.Bool("w", false, "if set, write the config file; otherwise, print to stdout")
-	force = flag.Bool("f", false, "if set, force the write of the config file even if the current content is still valid")
+	write  = flag.Bool("w", false, "if set, write the config file; otherwise, print to stdout")
+	force  = flag.Bool("f", false, "if set, force the write of the config file even if the current content is still valid")
+	record = flag.String("record", "", "if set, record the module versions queried from the proxy to this file")
+	replay = flag.String("replay", "", "if set, read module versions from this file, recorded with -record, rather than querying the proxy")
 
 	// SamplingRate is the fraction of otherwise uploadable reports that will be uploaded
 	SamplingRate = 1.0
@@ -47,26 +48,28 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// The padding heuristics below are based on the example of gopls.
-	//
-	// The goal is to pad enough versions for a quarter.
-	uCfg, err := generate(gcfgs, padding{
-		// 6 releases into the future translates to approximately three months for gopls.
-		releases: 6,
-		// We may release gopls 1.0, but won't release 2.0 in a three month timespan!
-		maj: 1,
-		// We don't usually do more than one minor release a month.
-		majmin: 3,
-		// Since golang/go#55267, which committed to adhering to semver, gopls
-		// hasn't had more than 5 patches per minor version.
-		patch: 6,
-		// Gopls has never had more than 4 prereleases.
-		pre: 4,
-	})
+	var versions versionSource = proxyVersions{}
+	if *replay != "" {
+		versions, err = readRecordedVersions(*replay)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	var recorder *recordingVersions
+	if *record != "" {
+		recorder = &recordingVersions{source: versions}
+		versions = recorder
+	}
 
+	uCfg, err := generate(gcfgs, regularPadding, versions)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if recorder != nil {
+		if err := recorder.write(*record); err != nil {
+			log.Fatal(err)
+		}
+	}
 	cfgJSON, err := json.MarshalIndent(uCfg, "", "\t")
 	if err != nil {
 		log.Fatal(err)
@@ -87,14 +90,7 @@ func main() {
 		if err != nil {
 			log.Fatal(err)
 		}
-		// Guarantee that we have enough padding to do two patches releases tomorrow.
-		minCfg, err := generate(gcfgs, padding{
-			releases: 2,
-			maj:      1,
-			majmin:   1, // we're not ever going to do more than one major/minor release in a day
-			patch:    2,
-			pre:      2, // in a single day, we wouldn't prep more than two prereleases per version
-		})
+		minCfg, err := generate(gcfgs, minimumPadding, versions)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -108,6 +104,35 @@ func main() {
 	}
 }
 
+// The padding heuristics below are based on the example of gopls.
+var (
+	// regularPadding pads enough versions for a quarter.
+	regularPadding = padding{
+		// 6 releases into the future translates to approximately three months for gopls.
+		releases: 6,
+		// We may release gopls 1.0, but won't release 2.0 in a three month timespan!
+		maj: 1,
+		// We don't usually do more than one minor release a month.
+		majmin: 3,
+		// Since golang/go#55267, which committed to adhering to semver, gopls
+		// hasn't had more than 5 patches per minor version.
+		patch: 6,
+		// Gopls has never had more than 4 prereleases.
+		pre: 4,
+	}
+
+	// minimumPadding guarantees that we have enough padding to do two patch
+	// releases tomorrow. The config file is only rewritten (without -f) if it
+	// no longer contains the config generated with this padding.
+	minimumPadding = padding{
+		releases: 2,
+		maj:      1,
+		majmin:   1, // we're not ever going to do more than one major/minor release in a day
+		patch:    2,
+		pre:      2, // in a single day, we wouldn't prep more than two prereleases per version
+	}
+)
+
 // configFile returns the path to the x/telemetry/config config.json file in
 // this repo.
 //
@@ -127,6 +152,28 @@ func configFile() (string, error) {
 	return configFile, nil
 }
 
+// counterKind returns the kind of the counter of a chart. The counters of
+// partition charts whose buckets all name upper bounds, following the naming
+// convention of the counter package, are histograms.
+func counterKind(gcfg chartconfig.ChartConfig) telemetry.CounterKind {
+	switch gcfg.Type {
+	case "boolean":
+		return telemetry.KindBoolean
+	case "stack":
+		return telemetry.KindStack
+	}
+	_, rest, ok := strings.Cut(gcfg.Counter, ":")
+	if !ok {
+		return telemetry.KindPartition
+	}
+	for _, b := range strings.Split(strings.Trim(rest, "{}"), ",") {
+		if !strings.HasPrefix(b, "<") {
+			return telemetry.KindPartition
+		}
+	}
+	return telemetry.KindHistogram
+}
+
 func readConfig(file string) (*telemetry.UploadConfig, error) {
 	data, err := os.ReadFile(file)
 	if err != nil {
@@ -139,9 +186,9 @@ func readConfig(file string) (*telemetry.UploadConfig, error) {
 	return cfg, nil
 }
 
-// generate computes the upload config from chart configs and module
-// information, returning the resulting formatted JSON.
-func generate(gcfgs []chartconfig.ChartConfig, padding padding) (*telemetry.UploadConfig, error) {
+// generate computes the upload config from chart configs and the module
+// versions listed by src, returning the resulting formatted JSON.
+func generate(gcfgs []chartconfig.ChartConfig, padding padding, src versionSource) (*telemetry.UploadConfig, error) {
 	ucfg := &telemetry.UploadConfig{
 		GOOS:   goos(),
 		GOARCH: goarch(),
@@ -149,7 +196,7 @@ func generate(gcfgs []chartconfig.ChartConfig, padding padding) (*telemetry.Uplo
 		SampleRate: SamplingRate,
 	}
 	var err error
-	ucfg.GoVersion, err = goVersions()
+	ucfg.GoVersion, err = goVersions(src)
 	if err != nil {
 		return nil, fmt.Errorf("querying go info: %v", err)
 	}
@@ -167,6 +214,9 @@ func generate(gcfgs []chartconfig.ChartConfig, padding padding) (*telemetry.Uplo
 		minVersions = make(map[string]string)                   // package path -> min version required, or "" for all
 	)
 	for _, gcfg := range gcfgs {
+		if gcfg.Type == "retired" {
+			continue // no longer collected
+		}
 		pcfg := programs[gcfg.Program]
 		if pcfg == nil {
 			pcfg = &telemetry.ProgramConfig{
@@ -180,6 +230,7 @@ func generate(gcfgs []chartconfig.ChartConfig, padding padding) (*telemetry.Uplo
 			Name:  gcfg.Counter,
 			Rate:  1.0, // TODO(rfindley): how should rate be configured?
 			Depth: gcfg.Depth,
+			Kind:  counterKind(gcfg),
 		}
 		if gcfg.Depth > 0 {
 			pcfg.Stacks = append(pcfg.Stacks, ccfg)
@@ -214,7 +265,7 @@ func generate(gcfgs []chartconfig.ChartConfig, padding padding) (*telemetry.Uplo
 				}
 			}
 		} else {
-			versions, err := listProxyVersions(p.Name)
+			versions, err := src.Versions(p.Name)
 			if err != nil {
 				return nil, fmt.Errorf("listing versions for %q: %v", p.Name, err)
 			}
@@ -341,22 +392,22 @@ func goarch() []string {
 	return arches
 }
 
-// goInfo queries the proxy for information about go distributions, including
-// versions, GOOS, and GOARCH values.
-func goVersions() ([]string, error) {
+// goVersions lists the known go versions, using the versions of the
+// golang.org/toolchain module listed by src.
+func goVersions(src versionSource) ([]string, error) {
 	// Trick: read Go distribution information from the module versions of
 	// golang.org/toolchain. These define the set of valid toolchains, and
 	// therefore are a reasonable source for version information.
 	//
 	// A more authoritative source for this information may be
 	// https://go.dev/dl?mode=json&include=all.
-	proxyVersions, err := listProxyVersions("golang.org/toolchain")
+	toolchainVersions, err := src.Versions("golang.org/toolchain")
 	if err != nil {
 		return nil, fmt.Errorf("listing toolchain versions: %v", err)
 	}
 	var goVersionRx = regexp.MustCompile(`^-(go.+)\.[^.]+-[^.]+$`)
 	verSet := make(map[string]struct{})
-	for _, v := range proxyVersions {
+	for _, v := range toolchainVersions {
 		pre := semver.Prerelease(v)
 		match := goVersionRx.FindStringSubmatch(pre)
 		if match == nil {
@@ -385,34 +436,6 @@ func (vs byGoVersion) Less(i, j int) bool {
 	return vs[i] < vs[j]
 }
 
-// versionsForTesting contains versions to use for testing, rather than
-// querying the proxy.
-var versionsForTesting map[string][]string
-
-// listProxyVersions queries the Go module mirror for published versions of the
-// given modulePath.
-//
-// modulePath must be lower-case (or already escaped): this function doesn't do
-// any escaping of upper-cased letters, as is required by the proxy prototol
-// (https://go.dev/ref/mod#goproxy-protocol).
-func listProxyVersions(modulePath string) ([]string, error) {
-	if vers, ok := versionsForTesting[modulePath]; ok {
-		return vers, nil
-	}
-	cmd := exec.Command("go", "list", "-m", "--versions", modulePath)
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("listing versions: %v (stderr: %v)", err, stderr.String())
-	}
-	fields := strings.Fields(strings.TrimSpace(string(out)))
-	if len(fields) == 0 {
-		return nil, fmt.Errorf("invalid version list output: %q", string(out))
-	}
-	return fields[1:], nil
-}
-
 // padding defines constraints on additional versions to pad.
 //
 // These constraints help restrict version padding to "reasonable" versions,
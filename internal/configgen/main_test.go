@@ -8,6 +8,7 @@ package main
 
 import (
 	_ "embed"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
@@ -17,10 +18,7 @@ import (
 )
 
 func TestGenerate(t *testing.T) {
-	defer func(vers map[string][]string) {
-		versionsForTesting = vers
-	}(versionsForTesting)
-	versionsForTesting = map[string][]string{
+	versions := recordedVersions{
 		"golang.org/toolchain":     {"v0.0.1-go1.21.0.linux-arm", "v0.0.1-go1.20.linux-arm"},
 		"golang.org/x/tools/gopls": {"v0.13.0", "v0.14.0", "v0.15.0-pre.1", "v0.15.0"},
 	}
@@ -32,12 +30,18 @@ type: partition
 issue: https://go.dev/issue/61038
 program: golang.org/x/tools/gopls
 version: v0.14.0
+---
+title: Workspace paths
+counter: gopls/workspace:{home,tmp}
+type: retired
+issue: https://go.dev/issue/12345
+program: golang.org/x/tools/gopls
 `
 	gcfgs, err := chartconfig.Parse([]byte(raw))
 	if err != nil {
 		t.Fatal(err)
 	}
-	got, err := generate(gcfgs, padding{2, 1, 1, 2, 2})
+	got, err := generate(gcfgs, padding{2, 1, 1, 2, 2}, versions)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -74,6 +78,7 @@ version: v0.14.0
 			Counters: []telemetry.CounterConfig{{
 				Name: "gopls/editor:{emacs,vim,vscode,other}",
 				Rate: 1.0,
+				Kind: telemetry.KindPartition,
 			}},
 		}},
 	}
@@ -93,6 +98,78 @@ version: v0.14.0
 	}
 }
 
+// TestGenerateRecorded generates the config for the current chart config
+// from the module versions recorded in testdata/versions.json. To update the
+// recording, run:
+//
+//	go run . -record testdata/versions.json
+func TestGenerateRecorded(t *testing.T) {
+	gcfgs, err := chartconfig.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := readRecordedVersions("testdata/versions.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	regular, err := generate(gcfgs, regularPadding, versions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minimum, err := generate(gcfgs, minimumPadding, versions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(regular, minimum) {
+		t.Errorf("config generated with regularPadding does not contain the config generated with minimumPadding")
+	}
+	again, err := generate(gcfgs, regularPadding, versions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(regular, again) {
+		t.Errorf("generate is not deterministic")
+	}
+
+	programs := make(map[string]bool)
+	for _, gcfg := range gcfgs {
+		programs[gcfg.Program] = true
+	}
+	for _, p := range regular.Programs {
+		if len(p.Versions) == 0 {
+			t.Errorf("program %s has no versions", p.Name)
+		}
+		delete(programs, p.Name)
+	}
+	for p := range programs {
+		t.Errorf("program %s is missing from the generated config", p)
+	}
+}
+
+func TestRecordingVersions(t *testing.T) {
+	source := recordedVersions{
+		"example.com/a": {"v1.0.0", "v1.1.0"},
+	}
+	recorder := &recordingVersions{source: source}
+	if _, err := recorder.Versions("example.com/a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.Versions("example.com/b"); err == nil {
+		t.Errorf("Versions(%q) succeeded unexpectedly", "example.com/b")
+	}
+	file := filepath.Join(t.TempDir(), "versions.json")
+	if err := recorder.write(file); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readRecordedVersions(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, source) {
+		t.Errorf("replayed versions = %v, want %v", got, source)
+	}
+}
+
 func TestByGoVersion_Less(t *testing.T) {
 	got := []string{
 		"go1.21.0",
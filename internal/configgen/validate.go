@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"go/version"
+	"strings"
 
 	"golang.org/x/mod/semver"
 	"golang.org/x/telemetry/internal/chartconfig"
@@ -35,8 +36,16 @@ func ValidateChartConfig(cfg chartconfig.ChartConfig) error {
 	if cfg.Counter == "" {
 		reportf("counter must be set")
 	}
-	if cfg.Type == "" {
+	switch cfg.Type {
+	case "":
 		reportf("type must be set")
+	case "partition", "boolean", "stack":
+	case "retired":
+		if len(cfg.RenamedFrom) > 0 {
+			reportf("renamed-from cannot be set for \"retired\" chart types")
+		}
+	default:
+		reportf("unknown type %q: must be partition, boolean, stack, or retired", cfg.Type)
 	}
 	if cfg.Depth < 0 {
 		reportf("invalid depth %d: must be non-negative", cfg.Depth)
@@ -44,6 +53,29 @@ func ValidateChartConfig(cfg chartconfig.ChartConfig) error {
 	if cfg.Depth != 0 && cfg.Type != "stack" {
 		reportf("depth can only be set for \"stack\" chart types")
 	}
+	chart, _, _ := strings.Cut(cfg.Counter, ":")
+	for _, from := range cfg.RenamedFrom {
+		if strings.ContainsAny(from, ":{},") {
+			reportf("renamed-from %q must be a chart name, without buckets", from)
+		} else if from == chart {
+			reportf("renamed-from %q is the current chart name", from)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, g := range cfg.Versions {
+		switch g {
+		case "full", "minor", "major":
+		default:
+			reportf("unknown versions %q: must be full, minor, or major", g)
+		}
+		if seen[g] {
+			reportf("versions %q is repeated", g)
+		}
+		seen[g] = true
+	}
+	if len(cfg.Versions) > 0 && cfg.Type != "partition" {
+		reportf("versions can only be set for \"partition\" chart types")
+	}
 	valid := semver.IsValid
 	if telemetry.IsToolchainProgram(cfg.Program) {
 		valid = version.IsValid
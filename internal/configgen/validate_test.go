@@ -52,11 +52,26 @@ func TestValidate(t *testing.T) {
 		// validation of mandatory fields
 		"description:bar": {"title", "program", "issue", "counter", "type"},
 
+		// validation of chart types
+		"type:histogram": {"unknown type"},
+
 		// validation of semver intervals
 		"version:1.2.3.4": {"semver"},
 
 		// valid of stack configuration
 		"depth:-1": {"non-negative", "stack"},
+
+		// validation of renames
+		"counter:gopls/editor:vim\nrenamed-from:gopls/editor:vim": {"without buckets"},
+		"counter:gopls/editor:vim\nrenamed-from:gopls/editor":     {"current chart name"},
+
+		// validation of version granularities
+		"type:partition\nversions:minor\nversions:patch\nversions:minor": {"unknown versions", "repeated"},
+		"type:stack\nversions:minor":                                     {"partition"},
+
+		// validation of retirements
+		"type:retired\nrenamed-from:gopls/old": {"renamed-from cannot be set"},
+		"type:retired\ndepth:3":                {"stack"},
 	}
 
 	for input, wantErrs := range tests {
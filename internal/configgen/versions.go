@@ -0,0 +1,114 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.22
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// A versionSource lists the published versions of modules, which determine
+// the program and Go versions in the generated config.
+type versionSource interface {
+	// Versions returns the published versions of the module with the given
+	// path.
+	Versions(modulePath string) ([]string, error)
+}
+
+// proxyVersions is a versionSource that queries the Go module mirror.
+type proxyVersions struct{}
+
+// Versions queries the Go module mirror for published versions of the
+// given modulePath.
+//
+// modulePath must be lower-case (or already escaped): this function doesn't do
+// any escaping of upper-cased letters, as is required by the proxy prototol
+// (https://go.dev/ref/mod#goproxy-protocol).
+func (proxyVersions) Versions(modulePath string) ([]string, error) {
+	cmd := exec.Command("go", "list", "-m", "--versions", modulePath)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing versions: %v (stderr: %v)", err, stderr.String())
+	}
+	fields := strings.Fields(strings.TrimSpace(string(out)))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid version list output: %q", string(out))
+	}
+	return fields[1:], nil
+}
+
+// recordedVersions is a versionSource that serves a fixed list of versions for
+// each module, so that config generation is deterministic and doesn't
+// require network access.
+type recordedVersions map[string][]string
+
+func (r recordedVersions) Versions(modulePath string) ([]string, error) {
+	vers, ok := r[modulePath]
+	if !ok {
+		return nil, fmt.Errorf("no recorded versions for %q", modulePath)
+	}
+	// Callers may modify the result.
+	return append([]string(nil), vers...), nil
+}
+
+// readRecordedVersions reads versions written by [recordingVersions.write].
+func readRecordedVersions(file string) (recordedVersions, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var r recordedVersions
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("reading recorded versions: %v", err)
+	}
+	return r, nil
+}
+
+// recordingVersions is a versionSource that remembers the versions returned
+// by another source, so that they can be replayed with recordedVersions.
+type recordingVersions struct {
+	source versionSource
+
+	mu       sync.Mutex
+	recorded recordedVersions
+}
+
+func (r *recordingVersions) Versions(modulePath string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.recorded[modulePath]; ok {
+		return r.recorded.Versions(modulePath)
+	}
+	if r.recorded == nil {
+		r.recorded = make(recordedVersions)
+	}
+	vers, err := r.source.Versions(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	r.recorded[modulePath] = append([]string(nil), vers...)
+	return vers, nil
+}
+
+// write writes the recorded versions to file, in the format read by
+// readRecordedVersions.
+func (r *recordingVersions) write(file string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.recorded, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0666)
+}
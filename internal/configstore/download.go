@@ -11,12 +11,17 @@ package configstore
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"golang.org/x/telemetry/internal/telemetry"
 )
@@ -84,3 +89,97 @@ func Download(version string, envOverlay []string) (*telemetry.UploadConfig, str
 	}
 	return cfg, info.Version, nil
 }
+
+// Versions lists the available versions of the telemetry config module,
+// from oldest to newest, using "go list -m -versions". If envOverlay is
+// provided, it is appended to the environment used for invoking the go
+// command. The go command is killed if ctx is done before it finishes.
+func Versions(ctx context.Context, envOverlay []string) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", "list", "-m", "-json", "-versions", ModulePath+"@latest")
+	needNoConsole(cmd)
+	cmd.Env = append(os.Environ(), envOverlay...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to list config module versions: %w\n%s", err, &stderr)
+	}
+	var info struct {
+		Versions []string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		return nil, fmt.Errorf("failed to list config module versions (invalid JSON): %w", err)
+	}
+	return info.Versions, nil
+}
+
+// DefaultMaxAge is the default MaxAge of ListOptions. New versions of the
+// config are published at most weekly.
+const DefaultMaxAge = time.Hour
+
+// failedListMaxAge is how long a failure to list versions is reused, so that
+// an unreachable proxy is not queried on every call.
+const failedListMaxAge = time.Minute
+
+// ListOptions are options for ListVersions.
+type ListOptions struct {
+	// Env is appended to the environment used for invoking the go command,
+	// as the envOverlay of Versions.
+	Env []string
+
+	// MaxAge is how long a listing is reused by later calls with the same
+	// Env. If zero, DefaultMaxAge is used. If negative, the versions are
+	// listed anew.
+	MaxAge time.Duration
+}
+
+// A versionList is a cached listing of the versions of the config module.
+type versionList struct {
+	mu       sync.Mutex // held while listing
+	versions []string
+	err      error
+	listed   time.Time
+}
+
+var (
+	versionListsMu sync.Mutex
+	versionLists   = make(map[string]*versionList) // by ListOptions.Env
+)
+
+// ListVersions lists the published versions of the telemetry config module
+// from the module proxy, from oldest to newest, as Versions does. Listings
+// are cached for the process, and reused by later calls with the same
+// Env until they are opts.MaxAge old. Failures are reused for a minute,
+// unless they are due to ctx being done.
+//
+// The result may be modified by the caller.
+func ListVersions(ctx context.Context, opts ListOptions) ([]string, error) {
+	maxAge := opts.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultMaxAge
+	}
+	key := strings.Join(opts.Env, "\x00")
+	versionListsMu.Lock()
+	l := versionLists[key]
+	if l == nil {
+		l = new(versionList)
+		versionLists[key] = l
+	}
+	versionListsMu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		maxAge = min(maxAge, failedListMaxAge)
+	}
+	if l.listed.IsZero() || time.Since(l.listed) >= maxAge {
+		versions, err := Versions(ctx, opts.Env)
+		if err != nil && ctx.Err() != nil {
+			// Only this caller gave up on the listing.
+			return nil, err
+		}
+		l.versions, l.err = versions, err
+		l.listed = time.Now()
+	}
+	return slices.Clone(l.versions), l.err
+}
@@ -5,14 +5,19 @@
 package configstore_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os/exec"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/telemetry/internal/configstore"
 	"golang.org/x/telemetry/internal/configtest"
+	"golang.org/x/telemetry/internal/proxy"
 	"golang.org/x/telemetry/internal/telemetry"
 	"golang.org/x/telemetry/internal/testenv"
 )
@@ -75,3 +80,82 @@ func stringify(x any) string {
 	}
 	return string(ret)
 }
+
+func TestVersions(t *testing.T) {
+	testenv.NeedsGo(t)
+
+	env, _ := versionsProxyEnv(t, "v0.2.0", "v0.1.0", "v0.10.0")
+	got, err := configstore.Versions(context.Background(), env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v0.1.0", "v0.2.0", "v0.10.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Versions() = %v, want %v", got, want)
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	testenv.NeedsGo(t)
+
+	env, publish := versionsProxyEnv(t, "v0.1.0")
+	list := func(maxAge time.Duration, want ...string) {
+		t.Helper()
+		got, err := configstore.ListVersions(context.Background(), configstore.ListOptions{Env: env, MaxAge: maxAge})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ListVersions(MaxAge: %v) = %v, want %v", maxAge, got, want)
+		}
+	}
+	list(0, "v0.1.0")
+
+	// A new version is not listed until the cached listing is too old.
+	publish("v0.2.0")
+	list(0, "v0.1.0")
+	list(time.Hour, "v0.1.0")
+	list(-1, "v0.1.0", "v0.2.0")
+	list(0, "v0.1.0", "v0.2.0")
+
+	// Listings are cached by environment.
+	other, _ := versionsProxyEnv(t, "v0.3.0")
+	got, err := configstore.ListVersions(context.Background(), configstore.ListOptions{Env: other})
+	if want := []string{"v0.3.0"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ListVersions with another proxy = %v, %v, want %v", got, err, want)
+	}
+}
+
+// versionsProxyEnv writes a module proxy that serves the given versions of
+// the config module, and returns the environment that uses it and a func
+// that publishes more versions to it.
+func versionsProxyEnv(t *testing.T, versions ...string) (env []string, publish func(versions ...string)) {
+	dir := t.TempDir()
+	var proxyURI string
+	publish = func(versions ...string) {
+		files := make(map[string][]byte)
+		for _, v := range versions {
+			prefix := configstore.ModulePath + "@" + v + "/"
+			files[prefix+"go.mod"] = []byte("module " + configstore.ModulePath + "\n\ngo 1.20\n")
+			files[prefix+"config.json"] = []byte("{}")
+		}
+		var err error
+		proxyURI, err = proxy.WriteProxy(filepath.Join(dir, "proxy"), files)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	publish(versions...)
+	env = []string{
+		"GOPROXY=" + proxyURI,
+		"GONOSUMDB=*",
+		"GOMODCACHE=" + filepath.Join(dir, "modcache"),
+	}
+	t.Cleanup(func() {
+		cmd := exec.Command("go", "clean", "-modcache")
+		cmd.Env = append(cmd.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("go clean -modcache failed: %v\n%s", err, out)
+		}
+	})
+	return env, publish
+}
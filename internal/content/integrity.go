@@ -0,0 +1,28 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package content
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"io/fs"
+	"strings"
+)
+
+// Integrity returns the subresource integrity metadata of the static asset
+// served at urlPath from fsys, such as "/static/charts.min.js", for use in
+// the integrity attribute of the script or link element that loads it.
+//
+// The browser refuses to use an asset that does not match its metadata, so
+// a script bundle that is altered after it is built, for example by a
+// compromised cache, is not run.
+func Integrity(fsys fs.FS, urlPath string) (string, error) {
+	data, err := fs.ReadFile(fsys, strings.TrimPrefix(urlPath, "/"))
+	if err != nil {
+		return "", err
+	}
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
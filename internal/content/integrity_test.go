@@ -0,0 +1,28 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package content
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestIntegrity(t *testing.T) {
+	fsys := fstest.MapFS{
+		"static/a.min.js": {Data: []byte("alert('Hello, World!');")},
+	}
+	got, err := Integrity(fsys, "/static/a.min.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// echo -n "alert('Hello, World!');" | openssl dgst -sha384 -binary | openssl base64 -A
+	const want = "sha384-Bg9twQOyM+pxvxdZFXOxQ8mzISbQpouZ9Miui8VIeVuTR6/BlDEK6CCrnZmaL6iB"
+	if got != want {
+		t.Errorf("Integrity = %q, want %q", got, want)
+	}
+	if _, err := Integrity(fsys, "/static/missing.min.js"); err == nil {
+		t.Error("Integrity of missing asset succeeded")
+	}
+}
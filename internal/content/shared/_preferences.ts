@@ -0,0 +1,124 @@
+/**
+ * @license
+ * Copyright 2024 The Go Authors. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+/**
+ * A Theme selects the color scheme of the page. The "system" theme follows
+ * the prefers-color-scheme media query.
+ */
+export type Theme = "system" | "light" | "dark";
+
+/**
+ * A Palette selects the colors used for chart marks. The "accessible"
+ * palette is distinguishable under the common forms of color blindness.
+ */
+export type Palette = "default" | "accessible";
+
+/**
+ * accessibleColors is the Okabe-Ito palette, in the order recommended by
+ * its authors, without black, which is hard to see in the dark theme.
+ */
+export const accessibleColors = [
+  "#e69f00",
+  "#56b4e9",
+  "#009e73",
+  "#f0e442",
+  "#0072b2",
+  "#d55e00",
+  "#cc79a7",
+  "#999999",
+];
+
+const themeKey = "telemetry.theme";
+const paletteKey = "telemetry.palette";
+const changeEvent = "telemetry-preferences";
+
+const darkQuery = window.matchMedia("(prefers-color-scheme: dark)");
+
+export function getTheme(): Theme {
+  const v = load(themeKey);
+  return v === "light" || v === "dark" ? v : "system";
+}
+
+export function getPalette(): Palette {
+  return load(paletteKey) === "accessible" ? "accessible" : "default";
+}
+
+export function setTheme(theme: Theme) {
+  store(themeKey, theme === "system" ? null : theme);
+  applyPreferences();
+}
+
+export function setPalette(palette: Palette) {
+  store(paletteKey, palette === "default" ? null : palette);
+  applyPreferences();
+}
+
+/**
+ * isDark reports whether the page is currently shown in the dark theme.
+ */
+export function isDark(): boolean {
+  const theme = getTheme();
+  return theme === "dark" || (theme === "system" && darkQuery.matches);
+}
+
+/**
+ * applyPreferences sets the data-theme and data-palette attributes of the
+ * document element, which _color.css uses to select colors, and notifies
+ * the listeners registered with onPreferencesChange.
+ */
+export function applyPreferences() {
+  const root = document.documentElement;
+  const theme = getTheme();
+  if (theme === "system") {
+    delete root.dataset.theme;
+  } else {
+    root.dataset.theme = theme;
+  }
+  root.dataset.palette = getPalette();
+  document.dispatchEvent(new Event(changeEvent));
+}
+
+/**
+ * onPreferencesChange calls fn whenever the theme or palette changes,
+ * including when the system color scheme changes under the "system" theme.
+ */
+export function onPreferencesChange(fn: () => void) {
+  document.addEventListener(changeEvent, fn);
+  darkQuery.addEventListener("change", () => {
+    if (getTheme() === "system") {
+      fn();
+    }
+  });
+}
+
+// localStorage may be unavailable, for example when storage is disabled in
+// the browser. Preferences then last only as long as the page.
+const fallback = new Map<string, string>();
+
+function load(key: string): string | null {
+  try {
+    return localStorage.getItem(key);
+  } catch {
+    return fallback.get(key) ?? null;
+  }
+}
+
+function store(key: string, value: string | null) {
+  try {
+    if (value === null) {
+      localStorage.removeItem(key);
+    } else {
+      localStorage.setItem(key, value);
+    }
+  } catch {
+    if (value === null) {
+      fallback.delete(key);
+    } else {
+      fallback.set(key, value);
+    }
+  }
+}
@@ -6,7 +6,38 @@
  */
 
 import { ToolTipController } from "./_tooltip";
+import {
+  applyPreferences,
+  getPalette,
+  getTheme,
+  onPreferencesChange,
+  Palette,
+  setPalette,
+  setTheme,
+  Theme,
+} from "./_preferences";
 
-for (const el of document.querySelectorAll<HTMLDetailsElement>(".js-tooltip")) {
-  new ToolTipController(el);
-}
+// This script is loaded in the document head, so that the stored theme is
+// applied before the page is first painted.
+applyPreferences();
+
+document.addEventListener("DOMContentLoaded", () => {
+  for (const el of document.querySelectorAll<HTMLDetailsElement>(".js-tooltip")) {
+    new ToolTipController(el);
+  }
+
+  const themeSelect = document.querySelector<HTMLSelectElement>(".js-themeSelect");
+  const paletteSelect = document.querySelector<HTMLSelectElement>(".js-paletteSelect");
+  const update = () => {
+    if (themeSelect) {
+      themeSelect.value = getTheme();
+    }
+    if (paletteSelect) {
+      paletteSelect.value = getPalette();
+    }
+  };
+  themeSelect?.addEventListener("change", () => setTheme(themeSelect.value as Theme));
+  paletteSelect?.addEventListener("change", () => setPalette(paletteSelect.value as Palette));
+  onPreferencesChange(update);
+  update();
+});
@@ -1,11 +1,17 @@
 // Code generated by esbuild. DO NOT EDIT.
-"use strict";(()=>{var e=class{constructor(i){this.el=i;document.addEventListener("click",o=>{this.el.contains(o.target)||this.el.removeAttribute("open")})}};for(let t of document.querySelectorAll(".js-tooltip"))new e(t);})();
+"use strict";(()=>{var l=class{constructor(t){this.el=t;document.addEventListener("click",n=>{this.el.contains(n.target)||this.el.removeAttribute("open")})}};var i="telemetry.theme",m="telemetry.palette",d="telemetry-preferences",y=window.matchMedia("(prefers-color-scheme: dark)");function o(){let e=f(i);return e==="light"||e==="dark"?e:"system"}function c(){return f(m)==="accessible"?"accessible":"default"}function u(e){g(i,e==="system"?null:e),s()}function p(e){g(m,e==="default"?null:e),s()}function s(){let e=document.documentElement,t=o();t==="system"?delete e.dataset.theme:e.dataset.theme=t,e.dataset.palette=c(),document.dispatchEvent(new Event(d))}function h(e){document.addEventListener(d,e),y.addEventListener("change",()=>{o()==="system"&&e()})}var r=new Map;function f(e){try{return localStorage.getItem(e)}catch{return r.get(e)??null}}function g(e,t){try{t===null?localStorage.removeItem(e):localStorage.setItem(e,t)}catch{t===null?r.delete(e):r.set(e,t)}}s();document.addEventListener("DOMContentLoaded",()=>{for(let a of document.querySelectorAll(".js-tooltip"))new l(a);let e=document.querySelector(".js-themeSelect"),t=document.querySelector(".js-paletteSelect"),n=()=>{e&&(e.value=o()),t&&(t.value=c())};e?.addEventListener("change",()=>u(e.value)),t?.addEventListener("change",()=>p(t.value)),h(n),n()});})();
 /**
  * @license
  * Copyright 2021 The Go Authors. All rights reserved.
  * Use of this source code is governed by a BSD-style
  * license that can be found in the LICENSE file.
  */
+/**
+ * @license
+ * Copyright 2024 The Go Authors. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
 /**
  * @license
  * Copyright 2023 The Go Authors. All rights reserved.
@@ -27,6 +27,7 @@ interface Chart {
 }
 
 interface Datum {
+  Week: string;
   Key: string;
   Value: number;
 }
@@ -36,42 +37,73 @@ declare const Page: Page;
 import * as d3 from "d3";
 import * as Plot from "@observablehq/plot";
 import { treeNavController } from "../shared/treenav";
+import {
+  accessibleColors,
+  getPalette,
+  onPreferencesChange,
+} from "../shared/_preferences";
 
-for (const program of Page.Charts?.Programs || []) {
-  for (const counter of program?.Charts || []) {
-    switch (counter.Type) {
-      case "partition":
-        document
-          .querySelector(`[data-chart-id="${counter.ID}"]`)
-          ?.append(partition(counter));
-
-        break;
-      case "histogram":
-        document
-          .querySelector(`[data-chart-id="${counter.ID}"]`)
-          ?.append(histogram(counter));
-
-        break;
-      default:
-        console.error("unknown chart type");
-        break;
+// Charts are drawn again when the palette or theme changes, since the
+// colors of their marks are fixed when they are drawn.
+renderCharts();
+onPreferencesChange(renderCharts);
+
+for (const el of document.querySelectorAll<HTMLElement>(".js-Tree")) {
+  treeNavController(el);
+}
+
+function renderCharts() {
+  for (const program of Page.Charts?.Programs || []) {
+    for (const counter of program?.Charts || []) {
+      const el = document.querySelector(`[data-chart-id="${counter.ID}"]`);
+      switch (counter.Type) {
+        case "partition":
+          el?.replaceChildren(partition(counter));
+          break;
+        case "histogram":
+          el?.replaceChildren(histogram(counter));
+          break;
+        case "stack":
+          el?.replaceChildren(stack(counter));
+          break;
+        case "trend":
+          el?.replaceChildren(trend(counter));
+          break;
+        default:
+          console.error("unknown chart type");
+          break;
+      }
     }
   }
 }
 
-for (const el of document.querySelectorAll<HTMLElement>(".js-Tree")) {
-  treeNavController(el);
+/**
+ * colorOptions returns the color options of a chart, using scheme unless
+ * the accessible palette is selected.
+ */
+function colorOptions(scheme: string) {
+  if (getPalette() === "accessible") {
+    return { range: accessibleColors };
+  }
+  return { scheme };
 }
 
 function partition({ Data, Name }: Chart) {
   Data ??= [];
+  const total = Data.reduce((sum, d) => sum + d.Value, 0);
 
   const max = Data.map((d) => d.Value).reduce((a, b) => Math.max(a, b), 0);
 
   return Plot.plot({
+    ariaLabel: `${Name} chart`,
+    ariaDescription:
+      `Bar chart of the number of reports (${total} in total) for each ` +
+      `value of ${Name}: ` +
+      Data.map((d) => `${d.Key}: ${d.Value}`).join(", ") +
+      ".",
     color: {
       type: "categorical",
-      scheme: "set2",
+      ...colorOptions("set2"),
     },
     nice: true,
     x: {
@@ -105,7 +137,57 @@ function partition({ Data, Name }: Chart) {
   });
 }
 
-function histogram({ Data }: Chart) {
+/**
+ * trend draws the value of a chart in each week, such as the number of
+ * reporters of a program, as vertical bars in the order of the weeks.
+ */
+function trend({ Data, Name }: Chart) {
+  Data ??= [];
+  const max = Data.map((d) => d.Value).reduce((a, b) => Math.max(a, b), 0);
+
+  return Plot.plot({
+    ariaLabel: `${Name} chart`,
+    ariaDescription:
+      `Bar chart of ${Name} in each week: ` +
+      Data.map((d) => `${d.Week}: ${d.Value}`).join(", ") +
+      ".",
+    color: {
+      type: "categorical",
+      ...colorOptions("set2"),
+    },
+    nice: true,
+    x: {
+      label: "Week",
+      labelOffset: Number.MAX_SAFE_INTEGER,
+      tickRotate: 45,
+      domain: Data.map((d) => d.Week),
+    },
+    y: {
+      label: Name,
+      domain: [0, max + 1],
+    },
+    width: 1024,
+    style: {
+      overflow: "visible",
+      background: "transparent",
+      marginBottom: "3rem",
+      fontSize: "0.8rem",
+      marginTop: "1rem",
+    },
+    insetTop: 20,
+    marks: [
+      Plot.barY(Data, {
+        tip: true,
+        fill: (d) => d.Key,
+        x: (d) => d.Week,
+        y: (d) => d.Value,
+      }),
+      Plot.frame(),
+    ],
+  });
+}
+
+function histogram({ Data, Name }: Chart) {
   Data ??= [];
   const n = 3; // number of facet columns
   const fixKey = (k: string) => (isNaN(Number(k)) ? k : Number(k));
@@ -115,6 +197,10 @@ function histogram({ Data }: Chart) {
   const fy = (key: string | number) => Math.floor((index.get(key) ?? 0) / n);
 
   return Plot.plot({
+    ariaLabel: `${Name} chart`,
+    ariaDescription:
+      "Cumulative histograms of the distribution of the values of " +
+      `${keys.length} counters: ${keys.join(", ")}.`,
     marginLeft: 60,
     width: 1024,
     grid: true,
@@ -125,7 +211,7 @@ function histogram({ Data }: Chart) {
     color: {
       type: "ordinal",
       legend: true,
-      scheme: "Spectral",
+      ...colorOptions("Spectral"),
       label: "Counter",
     },
     y: {
@@ -166,4 +252,55 @@ function histogram({ Data }: Chart) {
   });
 }
 
+/**
+ * stack draws the most frequent stacks of a stack counter in each week, as
+ * horizontal bars labeled with the innermost frame of each stack. The full
+ * stack is shown in the tooltip.
+ */
+function stack({ Data, Name }: Chart) {
+  Data ??= [];
+  const frame = (key: string) => key.split("\n")[0] || "(no stack)";
+  const weeks = Array.from(new Set(Data.map((d) => d.Week)));
+
+  return Plot.plot({
+    ariaLabel: `${Name} chart`,
+    ariaDescription:
+      `Bar charts of the counts of the most frequent stacks of ${Name} ` +
+      `in each of ${weeks.length} weeks: ` +
+      Data.map((d) => `${d.Week}, ${frame(d.Key)}: ${d.Value}`).join(", ") +
+      ".",
+    marginLeft: 320,
+    width: 1024,
+    color: {
+      type: "ordinal",
+      ...colorOptions("set2"),
+    },
+    x: {
+      label: "Count",
+      grid: true,
+    },
+    y: {
+      label: null,
+      tickFormat: frame,
+    },
+    fy: {
+      label: weeks.length > 1 ? "Week" : null,
+      domain: weeks,
+    },
+    style: "background:transparent;",
+    marks: [
+      Plot.barX(Data, {
+        x: (d: Datum) => d.Value,
+        y: (d: Datum) => d.Key,
+        fy: (d: Datum) => d.Week,
+        fill: (d: Datum) => frame(d.Key),
+        sort: { y: "-x" },
+        title: (d: Datum) => `${d.Key}\n\ncount: ${d.Value}`,
+        tip: true,
+      }),
+      Plot.frame(),
+    ],
+  });
+}
+
 export {};
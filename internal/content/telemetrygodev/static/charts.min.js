@@ -1,10 +1,10 @@
 // Code generated by esbuild. DO NOT EDIT.
-"use strict";(()=>{function bt(t,e){return t==null||e==null?NaN:t<e?-1:t>e?1:t>=e?0:NaN}function xe(t,e){return t==null||e==null?NaN:e<t?-1:e>t?1:e>=t?0:NaN}function qe(t){let e,n,r;t.length!==2?(e=bt,n=(f,u)=>bt(t(f),u),r=(f,u)=>t(f)-u):(e=t===bt||t===xe?t:qy,n=t,r=t);function o(f,u,c=0,s=f.length){if(c<s){if(e(u,u)!==0)return s;do{let d=c+s>>>1;n(f[d],u)<0?c=d+1:s=d}while(c<s)}return c}function i(f,u,c=0,s=f.length){if(c<s){if(e(u,u)!==0)return s;do{let d=c+s>>>1;n(f[d],u)<=0?c=d+1:s=d}while(c<s)}return c}function a(f,u,c=0,s=f.length){let d=o(f,u,c,s-1);return d>c&&r(f[d-1],u)>-r(f[d],u)?d-1:d}return{left:o,center:a,right:i}}function qy(){return 0}function Ho(t){return t===null?NaN:+t}function*Fp(t,e){if(e===void 0)for(let n of t)n!=null&&(n=+n)>=n&&(yield n);else{let n=-1;for(let r of t)(r=e(r,++n,t))!=null&&(r=+r)>=r&&(yield r)}}var qp=qe(bt),zp=qp.right,zy=qp.left,Yy=qe(Ho).center,un=zp;function Jn(t,e){let n=0;if(e===void 0)for(let r of t)r!=null&&(r=+r)>=r&&++n;else{let r=-1;for(let o of t)(o=e(o,++r,t))!=null&&(o=+o)>=o&&++n}return n}function By(t){return t.length|0}function Uy(t){return!(t>0)}function Xy(t){return typeof t!="object"||"length"in t?t:Array.from(t)}function Hy(t){return e=>t(...e)}function Fa(...t){let e=typeof t[t.length-1]=="function"&&Hy(t.pop());t=t.map(Xy);let n=t.map(By),r=t.length-1,o=new Array(r+1).fill(0),i=[];if(r<0||n.some(Uy))return i;for(;;){i.push(o.map((f,u)=>t[u][f]));let a=r;for(;++o[a]===n[a];){if(a===0)return e?i.map(e):i;o[a--]=0}}}function qa(t,e){var n=0,r=0;return Float64Array.from(t,e===void 0?o=>n+=+o||0:o=>n+=+e(o,r++,t)||0)}function Rr(t,e){let n=0,r,o=0,i=0;if(e===void 0)for(let a of t)a!=null&&(a=+a)>=a&&(r=a-o,o+=r/++n,i+=r*(a-o));else{let a=-1;for(let f of t)(f=e(f,++a,t))!=null&&(f=+f)>=f&&(r=f-o,o+=r/++n,i+=r*(f-o))}if(n>1)return i/(n-1)}function Lr(t,e){let n=Rr(t,e);return n&&Math.sqrt(n)}function Ct(t,e){let n,r;if(e===void 0)for(let o of t)o!=null&&(n===void 0?o>=o&&(n=r=o):(n>o&&(n=o),r<o&&(r=o)));else{let o=-1;for(let i of t)(i=e(i,++o,t))!=null&&(n===void 0?i>=i&&(n=r=i):(n>i&&(n=i),r<i&&(r=i)))}return[n,r]}var ae=class{constructor(){this._partials=new Float64Array(32),this._n=0}add(e){let n=this._partials,r=0;for(let o=0;o<this._n&&o<32;o++){let i=n[o],a=e+i,f=Math.abs(e)<Math.abs(i)?e-(a-i):i-(a-e);f&&(n[r++]=f),e=a}return n[r]=e,this._n=r+1,this}valueOf(){let e=this._partials,n=this._n,r,o,i,a=0;if(n>0){for(a=e[--n];n>0&&(r=a,o=e[--n],a=r+o,i=o-(a-r),!i););n>0&&(i<0&&e[n-1]<0||i>0&&e[n-1]>0)&&(o=i*2,r=a+o,o==r-a&&(a=r))}return a}};var Qt=class extends Map{constructor(e,n=Up){if(super(),Object.defineProperties(this,{_intern:{value:new Map},_key:{value:n}}),e!=null)for(let[r,o]of e)this.set(r,o)}get(e){return super.get(us(this,e))}has(e){return super.has(us(this,e))}set(e,n){return super.set(Yp(this,e),n)}delete(e){return super.delete(Bp(this,e))}},Ce=class extends Set{constructor(e,n=Up){if(super(),Object.defineProperties(this,{_intern:{value:new Map},_key:{value:n}}),e!=null)for(let r of e)this.add(r)}has(e){return super.has(us(this,e))}add(e){return super.add(Yp(this,e))}delete(e){return super.delete(Bp(this,e))}};function us({_intern:t,_key:e},n){let r=e(n);return t.has(r)?t.get(r):n}function Yp({_intern:t,_key:e},n){let r=e(n);return t.has(r)?t.get(r):(t.set(r,n),n)}function Bp({_intern:t,_key:e},n){let r=e(n);return t.has(r)&&(n=t.get(r),t.delete(r)),n}function Up(t){return t!==null&&typeof t=="object"?t.valueOf():t}function Wo(t){return t}function cn(t,...e){return ss(t,Wo,Wo,e)}function Ae(t,e,...n){return ss(t,Wo,e,n)}function cs(t,e,...n){return ss(t,Array.from,e,n)}function ss(t,e,n,r){return function o(i,a){if(a>=r.length)return n(i);let f=new Qt,u=r[a++],c=-1;for(let s of i){let d=u(s,++c,i),l=f.get(d);l?l.push(s):f.set(d,[s])}for(let[s,d]of f)f.set(s,o(d,a));return e(f)}(t,0)}function ls(t,e){return Array.from(e,n=>t[n])}function ze(t,...e){if(typeof t[Symbol.iterator]!="function")throw new TypeError("values is not iterable");t=Array.from(t);let[n]=e;if(n&&n.length!==2||e.length>1){let r=Uint32Array.from(t,(o,i)=>i);return e.length>1?(e=e.map(o=>t.map(o)),r.sort((o,i)=>{for(let a of e){let f=Go(a[o],a[i]);if(f)return f}})):(n=t.map(n),r.sort((o,i)=>Go(n[o],n[i]))),ls(t,r)}return t.sort(ds(n))}function ds(t=bt){if(t===bt)return Go;if(typeof t!="function")throw new TypeError("compare is not a function");return(e,n)=>{let r=t(e,n);return r||r===0?r:(t(n,n)===0)-(t(e,e)===0)}}function Go(t,e){return(t==null||!(t>=t))-(e==null||!(e>=e))||(t<e?-1:t>e?1:0)}function Pr(t,e,n){return(e.length!==2?ze(Ae(t,e,n),([r,o],[i,a])=>bt(o,a)||bt(r,i)):ze(cn(t,n),([r,o],[i,a])=>e(o,a)||bt(r,i))).map(([r])=>r)}var Wy=Math.sqrt(50),Gy=Math.sqrt(10),Vy=Math.sqrt(2);function za(t,e,n){let r=(e-t)/Math.max(0,n),o=Math.floor(Math.log10(r)),i=r/Math.pow(10,o),a=i>=Wy?10:i>=Gy?5:i>=Vy?2:1,f,u,c;return o<0?(c=Math.pow(10,-o)/a,f=Math.round(t*c),u=Math.round(e*c),f/c<t&&++f,u/c>e&&--u,c=-c):(c=Math.pow(10,o)*a,f=Math.round(t/c),u=Math.round(e/c),f*c<t&&++f,u*c>e&&--u),u<f&&.5<=n&&n<2?za(t,e,n*2):[f,u,c]}function $e(t,e,n){if(e=+e,t=+t,n=+n,!(n>0))return[];if(t===e)return[t];let r=e<t,[o,i,a]=r?za(e,t,n):za(t,e,n);if(!(i>=o))return[];let f=i-o+1,u=new Array(f);if(r)if(a<0)for(let c=0;c<f;++c)u[c]=(i-c)/-a;else for(let c=0;c<f;++c)u[c]=(i-c)*a;else if(a<0)for(let c=0;c<f;++c)u[c]=(o+c)/-a;else for(let c=0;c<f;++c)u[c]=(o+c)*a;return u}function tr(t,e,n){return e=+e,t=+t,n=+n,za(t,e,n)[2]}function Or(t,e,n){e=+e,t=+t,n=+n;let r=e<t,o=r?tr(e,t,n):tr(t,e,n);return(r?-1:1)*(o<0?1/-o:o)}function Ya(t){return Math.max(1,Math.ceil(Math.log(Jn(t))/Math.LN2)+1)}function Ht(t,e){let n;if(e===void 0)for(let r of t)r!=null&&(n<r||n===void 0&&r>=r)&&(n=r);else{let r=-1;for(let o of t)(o=e(o,++r,t))!=null&&(n<o||n===void 0&&o>=o)&&(n=o)}return n}function Ba(t,e){let n,r=-1,o=-1;if(e===void 0)for(let i of t)++o,i!=null&&(n<i||n===void 0&&i>=i)&&(n=i,r=o);else for(let i of t)(i=e(i,++o,t))!=null&&(n<i||n===void 0&&i>=i)&&(n=i,r=o);return r}function Jt(t,e){let n;if(e===void 0)for(let r of t)r!=null&&(n>r||n===void 0&&r>=r)&&(n=r);else{let r=-1;for(let o of t)(o=e(o,++r,t))!=null&&(n>o||n===void 0&&o>=o)&&(n=o)}return n}function Ua(t,e){let n,r=-1,o=-1;if(e===void 0)for(let i of t)++o,i!=null&&(n>i||n===void 0&&i>=i)&&(n=i,r=o);else for(let i of t)(i=e(i,++o,t))!=null&&(n>i||n===void 0&&i>=i)&&(n=i,r=o);return r}function Xa(t,e,n=0,r=1/0,o){if(e=Math.floor(e),n=Math.floor(Math.max(0,n)),r=Math.floor(Math.min(t.length-1,r)),!(n<=e&&e<=r))return t;for(o=o===void 0?Go:ds(o);r>n;){if(r-n>600){let u=r-n+1,c=e-n+1,s=Math.log(u),d=.5*Math.exp(2*s/3),l=.5*Math.sqrt(s*d*(u-d)/u)*(c-u/2<0?-1:1),p=Math.max(n,Math.floor(e-c*d/u+l)),m=Math.min(r,Math.floor(e+(u-c)*d/u+l));Xa(t,e,p,m,o)}let i=t[e],a=n,f=r;for(Vo(t,n,e),o(t[r],i)>0&&Vo(t,n,r);a<f;){for(Vo(t,a,f),++a,--f;o(t[a],i)<0;)++a;for(;o(t[f],i)>0;)--f}o(t[n],i)===0?Vo(t,n,f):(++f,Vo(t,f,r)),f<=e&&(n=f+1),e<=f&&(r=f-1)}return t}function Vo(t,e,n){let r=t[e];t[e]=t[n],t[n]=r}function jo(t,e=bt){let n,r=!1;if(e.length===1){let o;for(let i of t){let a=e(i);(r?bt(a,o)>0:bt(a,a)===0)&&(n=i,o=a,r=!0)}}else for(let o of t)(r?e(o,n)>0:e(o,o)===0)&&(n=o,r=!0);return n}function ye(t,e,n){if(t=Float64Array.from(Fp(t,n)),!(!(r=t.length)||isNaN(e=+e))){if(e<=0||r<2)return Jt(t);if(e>=1)return Ht(t);var r,o=(r-1)*e,i=Math.floor(o),a=Ht(Xa(t,i).subarray(0,i+1)),f=Jt(t.subarray(i+1));return a+(f-a)*(o-i)}}function ps(t,e,n=Ho){if(!(!(r=t.length)||isNaN(e=+e))){if(e<=0||r<2)return+n(t[0],0,t);if(e>=1)return+n(t[r-1],r-1,t);var r,o=(r-1)*e,i=Math.floor(o),a=+n(t[i],i,t),f=+n(t[i+1],i+1,t);return a+(f-a)*(o-i)}}function Ha(t,e,n){let r=Jn(t),o=ye(t,.75)-ye(t,.25);return r&&o?Math.ceil((n-e)/(2*o*Math.pow(r,-1/3))):1}function Zo(t,e,n){let r=Jn(t),o=Lr(t);return r&&o?Math.ceil((n-e)*Math.cbrt(r)/(3.49*o)):1}function Wa(t,e){let n=0,r=0;if(e===void 0)for(let o of t)o!=null&&(o=+o)>=o&&(++n,r+=o);else{let o=-1;for(let i of t)(i=e(i,++o,t))!=null&&(i=+i)>=i&&(++n,r+=i)}if(n)return r/n}function sn(t,e){return ye(t,.5,e)}function*Zy(t){for(let e of t)yield*e}function Fr(t){return Array.from(Zy(t))}function Ga(t,e){let n=new Qt;if(e===void 0)for(let i of t)i!=null&&i>=i&&n.set(i,(n.get(i)||0)+1);else{let i=-1;for(let a of t)(a=e(a,++i,t))!=null&&a>=a&&n.set(a,(n.get(a)||0)+1)}let r,o=0;for(let[i,a]of n)a>o&&(o=a,r=i);return r}function Va(t,e=Ky){let n=[],r,o=!1;for(let i of t)o&&n.push(e(r,i)),r=i,o=!0;return n}function Ky(t,e){return[t,e]}function er(t,e,n){t=+t,e=+e,n=(o=arguments.length)<2?(e=t,t=0,1):o<3?1:+n;for(var r=-1,o=Math.max(0,Math.ceil((e-t)/n))|0,i=new Array(o);++r<o;)i[r]=t+r*n;return i}function fe(t,e){let n=0;if(e===void 0)for(let r of t)(r=+r)&&(n+=r);else{let r=-1;for(let o of t)(o=+e(o,++r,t))&&(n+=o)}return n}function An(t){if(typeof t[Symbol.iterator]!="function")throw new TypeError("values is not iterable");return Array.from(t).reverse()}function ja(...t){let e=new Ce;for(let n of t)for(let r of n)e.add(r);return e}function Xp(t){return t}var ms=1,hs=2,gs=3,Za=4,Hp=1e-6;function Qy(t){return"translate("+t+",0)"}function Jy(t){return"translate(0,"+t+")"}function tb(t){return e=>+t(e)}function eb(t,e){return e=Math.max(0,t.bandwidth()-e*2)/2,t.round()&&(e=Math.round(e)),n=>+t(n)+e}function nb(){return!this.__axis}function rb(t,e){var n=[],r=null,o=null,i=6,a=6,f=3,u=typeof window<"u"&&window.devicePixelRatio>1?0:.5,c=t===ms||t===Za?-1:1,s=t===Za||t===hs?"x":"y",d=t===ms||t===gs?Qy:Jy;function l(p){var m=r??(e.ticks?e.ticks.apply(e,n):e.domain()),h=o??(e.tickFormat?e.tickFormat.apply(e,n):Xp),g=Math.max(i,0)+f,b=e.range(),x=+b[0]+u,M=+b[b.length-1]+u,y=(e.bandwidth?eb:tb)(e.copy(),u),w=p.selection?p.selection():p,v=w.selectAll(".domain").data([null]),T=w.selectAll(".tick").data(m,e).order(),E=T.exit(),I=T.enter().append("g").attr("class","tick"),F=T.select("line"),_=T.select("text");v=v.merge(v.enter().insert("path",".tick").attr("class","domain").attr("stroke","currentColor")),T=T.merge(I),F=F.merge(I.append("line").attr("stroke","currentColor").attr(s+"2",c*i)),_=_.merge(I.append("text").attr("fill","currentColor").attr(s,c*g).attr("dy",t===ms?"0em":t===gs?"0.71em":"0.32em")),p!==w&&(v=v.transition(p),T=T.transition(p),F=F.transition(p),_=_.transition(p),E=E.transition(p).attr("opacity",Hp).attr("transform",function(R){return isFinite(R=y(R))?d(R+u):this.getAttribute("transform")}),I.attr("opacity",Hp).attr("transform",function(R){var O=this.parentNode.__axis;return d((O&&isFinite(O=O(R))?O:y(R))+u)})),E.remove(),v.attr("d",t===Za||t===hs?a?"M"+c*a+","+x+"H"+u+"V"+M+"H"+c*a:"M"+u+","+x+"V"+M:a?"M"+x+","+c*a+"V"+u+"H"+M+"V"+c*a:"M"+x+","+u+"H"+M),T.attr("opacity",1).attr("transform",function(R){return d(y(R)+u)}),F.attr(s+"2",c*i),_.attr(s,c*g).text(h),w.filter(nb).attr("fill","none").attr("font-size",10).attr("font-family","sans-serif").attr("text-anchor",t===hs?"start":t===Za?"end":"middle"),w.each(function(){this.__axis=y})}return l.scale=function(p){return arguments.length?(e=p,l):e},l.ticks=function(){return n=Array.from(arguments),l},l.tickArguments=function(p){return arguments.length?(n=p==null?[]:Array.from(p),l):n.slice()},l.tickValues=function(p){return arguments.length?(r=p==null?null:Array.from(p),l):r&&r.slice()},l.tickFormat=function(p){return arguments.length?(o=p,l):o},l.tickSize=function(p){return arguments.length?(i=a=+p,l):i},l.tickSizeInner=function(p){return arguments.length?(i=+p,l):i},l.tickSizeOuter=function(p){return arguments.length?(a=+p,l):a},l.tickPadding=function(p){return arguments.length?(f=+p,l):f},l.offset=function(p){return arguments.length?(u=+p,l):u},l}function xs(t){return rb(gs,t)}var ob={value:()=>{}};function Gp(){for(var t=0,e=arguments.length,n={},r;t<e;++t){if(!(r=arguments[t]+"")||r in n||/[\s.]/.test(r))throw new Error("illegal type: "+r);n[r]=[]}return new Ka(n)}function Ka(t){this._=t}function ib(t,e){return t.trim().split(/^|\s+/).map(function(n){var r="",o=n.indexOf(".");if(o>=0&&(r=n.slice(o+1),n=n.slice(0,o)),n&&!e.hasOwnProperty(n))throw new Error("unknown type: "+n);return{type:n,name:r}})}Ka.prototype=Gp.prototype={constructor:Ka,on:function(t,e){var n=this._,r=ib(t+"",n),o,i=-1,a=r.length;if(arguments.length<2){for(;++i<a;)if((o=(t=r[i]).type)&&(o=ab(n[o],t.name)))return o;return}if(e!=null&&typeof e!="function")throw new Error("invalid callback: "+e);for(;++i<a;)if(o=(t=r[i]).type)n[o]=Wp(n[o],t.name,e);else if(e==null)for(o in n)n[o]=Wp(n[o],t.name,null);return this},copy:function(){var t={},e=this._;for(var n in e)t[n]=e[n].slice();return new Ka(t)},call:function(t,e){if((o=arguments.length-2)>0)for(var n=new Array(o),r=0,o,i;r<o;++r)n[r]=arguments[r+2];if(!this._.hasOwnProperty(t))throw new Error("unknown type: "+t);for(i=this._[t],r=0,o=i.length;r<o;++r)i[r].value.apply(e,n)},apply:function(t,e,n){if(!this._.hasOwnProperty(t))throw new Error("unknown type: "+t);for(var r=this._[t],o=0,i=r.length;o<i;++o)r[o].value.apply(e,n)}};function ab(t,e){for(var n=0,r=t.length,o;n<r;++n)if((o=t[n]).name===e)return o.value}function Wp(t,e,n){for(var r=0,o=t.length;r<o;++r)if(t[r].name===e){t[r]=ob,t=t.slice(0,r).concat(t.slice(r+1));break}return n!=null&&t.push({name:e,value:n}),t}var ys=Gp;var Qa="http://www.w3.org/1999/xhtml",Ye={svg:"http://www.w3.org/2000/svg",xhtml:Qa,xlink:"http://www.w3.org/1999/xlink",xml:"http://www.w3.org/XML/1998/namespace",xmlns:"http://www.w3.org/2000/xmlns/"};function ln(t){var e=t+="",n=e.indexOf(":");return n>=0&&(e=t.slice(0,n))!=="xmlns"&&(t=t.slice(n+1)),Ye.hasOwnProperty(e)?{space:Ye[e],local:t}:t}function fb(t){return function(){var e=this.ownerDocument,n=this.namespaceURI;return n===Qa&&e.documentElement.namespaceURI===Qa?e.createElement(t):e.createElementNS(n,t)}}function ub(t){return function(){return this.ownerDocument.createElementNS(t.space,t.local)}}function dn(t){var e=ln(t);return(e.local?ub:fb)(e)}function cb(){}function nr(t){return t==null?cb:function(){return this.querySelector(t)}}function Vp(t){typeof t!="function"&&(t=nr(t));for(var e=this._groups,n=e.length,r=new Array(n),o=0;o<n;++o)for(var i=e[o],a=i.length,f=r[o]=new Array(a),u,c,s=0;s<a;++s)(u=i[s])&&(c=t.call(u,u.__data__,s,i))&&("__data__"in u&&(c.__data__=u.__data__),f[s]=c);return new vt(r,this._parents)}function bs(t){return t==null?[]:Array.isArray(t)?t:Array.from(t)}function sb(){return[]}function Ko(t){return t==null?sb:function(){return this.querySelectorAll(t)}}function lb(t){return function(){return bs(t.apply(this,arguments))}}function jp(t){typeof t=="function"?t=lb(t):t=Ko(t);for(var e=this._groups,n=e.length,r=[],o=[],i=0;i<n;++i)for(var a=e[i],f=a.length,u,c=0;c<f;++c)(u=a[c])&&(r.push(t.call(u,u.__data__,c,a)),o.push(u));return new vt(r,o)}function Qo(t){return function(){return this.matches(t)}}function Ja(t){return function(e){return e.matches(t)}}var db=Array.prototype.find;function pb(t){return function(){return db.call(this.children,t)}}function mb(){return this.firstElementChild}function Zp(t){return this.select(t==null?mb:pb(typeof t=="function"?t:Ja(t)))}var hb=Array.prototype.filter;function gb(){return Array.from(this.children)}function xb(t){return function(){return hb.call(this.children,t)}}function Kp(t){return this.selectAll(t==null?gb:xb(typeof t=="function"?t:Ja(t)))}function Qp(t){typeof t!="function"&&(t=Qo(t));for(var e=this._groups,n=e.length,r=new Array(n),o=0;o<n;++o)for(var i=e[o],a=i.length,f=r[o]=[],u,c=0;c<a;++c)(u=i[c])&&t.call(u,u.__data__,c,i)&&f.push(u);return new vt(r,this._parents)}function tf(t){return new Array(t.length)}function Jp(){return new vt(this._enter||this._groups.map(tf),this._parents)}function Jo(t,e){this.ownerDocument=t.ownerDocument,this.namespaceURI=t.namespaceURI,this._next=null,this._parent=t,this.__data__=e}Jo.prototype={constructor:Jo,appendChild:function(t){return this._parent.insertBefore(t,this._next)},insertBefore:function(t,e){return this._parent.insertBefore(t,e)},querySelector:function(t){return this._parent.querySelector(t)},querySelectorAll:function(t){return this._parent.querySelectorAll(t)}};function tm(t){return function(){return t}}function yb(t,e,n,r,o,i){for(var a=0,f,u=e.length,c=i.length;a<c;++a)(f=e[a])?(f.__data__=i[a],r[a]=f):n[a]=new Jo(t,i[a]);for(;a<u;++a)(f=e[a])&&(o[a]=f)}function bb(t,e,n,r,o,i,a){var f,u,c=new Map,s=e.length,d=i.length,l=new Array(s),p;for(f=0;f<s;++f)(u=e[f])&&(l[f]=p=a.call(u,u.__data__,f,e)+"",c.has(p)?o[f]=u:c.set(p,u));for(f=0;f<d;++f)p=a.call(t,i[f],f,i)+"",(u=c.get(p))?(r[f]=u,u.__data__=i[f],c.delete(p)):n[f]=new Jo(t,i[f]);for(f=0;f<s;++f)(u=e[f])&&c.get(l[f])===u&&(o[f]=u)}function wb(t){return t.__data__}function em(t,e){if(!arguments.length)return Array.from(this,wb);var n=e?bb:yb,r=this._parents,o=this._groups;typeof t!="function"&&(t=tm(t));for(var i=o.length,a=new Array(i),f=new Array(i),u=new Array(i),c=0;c<i;++c){var s=r[c],d=o[c],l=d.length,p=vb(t.call(s,s&&s.__data__,c,r)),m=p.length,h=f[c]=new Array(m),g=a[c]=new Array(m),b=u[c]=new Array(l);n(s,d,h,g,b,p,e);for(var x=0,M=0,y,w;x<m;++x)if(y=h[x]){for(x>=M&&(M=x+1);!(w=g[M])&&++M<m;);y._next=w||null}}return a=new vt(a,r),a._enter=f,a._exit=u,a}function vb(t){return typeof t=="object"&&"length"in t?t:Array.from(t)}function nm(){return new vt(this._exit||this._groups.map(tf),this._parents)}function rm(t,e,n){var r=this.enter(),o=this,i=this.exit();return typeof t=="function"?(r=t(r),r&&(r=r.selection())):r=r.append(t+""),e!=null&&(o=e(o),o&&(o=o.selection())),n==null?i.remove():n(i),r&&o?r.merge(o).order():o}function om(t){for(var e=t.selection?t.selection():t,n=this._groups,r=e._groups,o=n.length,i=r.length,a=Math.min(o,i),f=new Array(o),u=0;u<a;++u)for(var c=n[u],s=r[u],d=c.length,l=f[u]=new Array(d),p,m=0;m<d;++m)(p=c[m]||s[m])&&(l[m]=p);for(;u<o;++u)f[u]=n[u];return new vt(f,this._parents)}function im(){for(var t=this._groups,e=-1,n=t.length;++e<n;)for(var r=t[e],o=r.length-1,i=r[o],a;--o>=0;)(a=r[o])&&(i&&a.compareDocumentPosition(i)^4&&i.parentNode.insertBefore(a,i),i=a);return this}function am(t){t||(t=Mb);function e(d,l){return d&&l?t(d.__data__,l.__data__):!d-!l}for(var n=this._groups,r=n.length,o=new Array(r),i=0;i<r;++i){for(var a=n[i],f=a.length,u=o[i]=new Array(f),c,s=0;s<f;++s)(c=a[s])&&(u[s]=c);u.sort(e)}return new vt(o,this._parents).order()}function Mb(t,e){return t<e?-1:t>e?1:t>=e?0:NaN}function fm(){var t=arguments[0];return arguments[0]=this,t.apply(null,arguments),this}function um(){return Array.from(this)}function cm(){for(var t=this._groups,e=0,n=t.length;e<n;++e)for(var r=t[e],o=0,i=r.length;o<i;++o){var a=r[o];if(a)return a}return null}function sm(){let t=0;for(let e of this)++t;return t}function lm(){return!this.node()}function dm(t){for(var e=this._groups,n=0,r=e.length;n<r;++n)for(var o=e[n],i=0,a=o.length,f;i<a;++i)(f=o[i])&&t.call(f,f.__data__,i,o);return this}function Sb(t){return function(){this.removeAttribute(t)}}function Tb(t){return function(){this.removeAttributeNS(t.space,t.local)}}function _b(t,e){return function(){this.setAttribute(t,e)}}function Cb(t,e){return function(){this.setAttributeNS(t.space,t.local,e)}}function Ab(t,e){return function(){var n=e.apply(this,arguments);n==null?this.removeAttribute(t):this.setAttribute(t,n)}}function $b(t,e){return function(){var n=e.apply(this,arguments);n==null?this.removeAttributeNS(t.space,t.local):this.setAttributeNS(t.space,t.local,n)}}function pm(t,e){var n=ln(t);if(arguments.length<2){var r=this.node();return n.local?r.getAttributeNS(n.space,n.local):r.getAttribute(n)}return this.each((e==null?n.local?Tb:Sb:typeof e=="function"?n.local?$b:Ab:n.local?Cb:_b)(n,e))}function ef(t){return t.ownerDocument&&t.ownerDocument.defaultView||t.document&&t||t.defaultView}function Eb(t){return function(){this.style.removeProperty(t)}}function Ib(t,e,n){return function(){this.style.setProperty(t,e,n)}}function kb(t,e,n){return function(){var r=e.apply(this,arguments);r==null?this.style.removeProperty(t):this.style.setProperty(t,r,n)}}function mm(t,e,n){return arguments.length>1?this.each((e==null?Eb:typeof e=="function"?kb:Ib)(t,e,n??"")):$n(this.node(),t)}function $n(t,e){return t.style.getPropertyValue(e)||ef(t).getComputedStyle(t,null).getPropertyValue(e)}function Nb(t){return function(){delete this[t]}}function Db(t,e){return function(){this[t]=e}}function Rb(t,e){return function(){var n=e.apply(this,arguments);n==null?delete this[t]:this[t]=n}}function hm(t,e){return arguments.length>1?this.each((e==null?Nb:typeof e=="function"?Rb:Db)(t,e)):this.node()[t]}function gm(t){return t.trim().split(/^|\s+/)}function ws(t){return t.classList||new xm(t)}function xm(t){this._node=t,this._names=gm(t.getAttribute("class")||"")}xm.prototype={add:function(t){var e=this._names.indexOf(t);e<0&&(this._names.push(t),this._node.setAttribute("class",this._names.join(" ")))},remove:function(t){var e=this._names.indexOf(t);e>=0&&(this._names.splice(e,1),this._node.setAttribute("class",this._names.join(" ")))},contains:function(t){return this._names.indexOf(t)>=0}};function ym(t,e){for(var n=ws(t),r=-1,o=e.length;++r<o;)n.add(e[r])}function bm(t,e){for(var n=ws(t),r=-1,o=e.length;++r<o;)n.remove(e[r])}function Lb(t){return function(){ym(this,t)}}function Pb(t){return function(){bm(this,t)}}function Ob(t,e){return function(){(e.apply(this,arguments)?ym:bm)(this,t)}}function wm(t,e){var n=gm(t+"");if(arguments.length<2){for(var r=ws(this.node()),o=-1,i=n.length;++o<i;)if(!r.contains(n[o]))return!1;return!0}return this.each((typeof e=="function"?Ob:e?Lb:Pb)(n,e))}function Fb(){this.textContent=""}function qb(t){return function(){this.textContent=t}}function zb(t){return function(){var e=t.apply(this,arguments);this.textContent=e??""}}function vm(t){return arguments.length?this.each(t==null?Fb:(typeof t=="function"?zb:qb)(t)):this.node().textContent}function Yb(){this.innerHTML=""}function Bb(t){return function(){this.innerHTML=t}}function Ub(t){return function(){var e=t.apply(this,arguments);this.innerHTML=e??""}}function Mm(t){return arguments.length?this.each(t==null?Yb:(typeof t=="function"?Ub:Bb)(t)):this.node().innerHTML}function Xb(){this.nextSibling&&this.parentNode.appendChild(this)}function Sm(){return this.each(Xb)}function Hb(){this.previousSibling&&this.parentNode.insertBefore(this,this.parentNode.firstChild)}function Tm(){return this.each(Hb)}function _m(t){var e=typeof t=="function"?t:dn(t);return this.select(function(){return this.appendChild(e.apply(this,arguments))})}function Wb(){return null}function Cm(t,e){var n=typeof t=="function"?t:dn(t),r=e==null?Wb:typeof e=="function"?e:nr(e);return this.select(function(){return this.insertBefore(n.apply(this,arguments),r.apply(this,arguments)||null)})}function Gb(){var t=this.parentNode;t&&t.removeChild(this)}function Am(){return this.each(Gb)}function Vb(){var t=this.cloneNode(!1),e=this.parentNode;return e?e.insertBefore(t,this.nextSibling):t}function jb(){var t=this.cloneNode(!0),e=this.parentNode;return e?e.insertBefore(t,this.nextSibling):t}function $m(t){return this.select(t?jb:Vb)}function Em(t){return arguments.length?this.property("__data__",t):this.node().__data__}function Zb(t){return function(e){t.call(this,e,this.__data__)}}function Kb(t){return t.trim().split(/^|\s+/).map(function(e){var n="",r=e.indexOf(".");return r>=0&&(n=e.slice(r+1),e=e.slice(0,r)),{type:e,name:n}})}function Qb(t){return function(){var e=this.__on;if(e){for(var n=0,r=-1,o=e.length,i;n<o;++n)i=e[n],(!t.type||i.type===t.type)&&i.name===t.name?this.removeEventListener(i.type,i.listener,i.options):e[++r]=i;++r?e.length=r:delete this.__on}}}function Jb(t,e,n){return function(){var r=this.__on,o,i=Zb(e);if(r){for(var a=0,f=r.length;a<f;++a)if((o=r[a]).type===t.type&&o.name===t.name){this.removeEventListener(o.type,o.listener,o.options),this.addEventListener(o.type,o.listener=i,o.options=n),o.value=e;return}}this.addEventListener(t.type,i,n),o={type:t.type,name:t.name,value:e,listener:i,options:n},r?r.push(o):this.__on=[o]}}function Im(t,e,n){var r=Kb(t+""),o,i=r.length,a;if(arguments.length<2){var f=this.node().__on;if(f){for(var u=0,c=f.length,s;u<c;++u)for(o=0,s=f[u];o<i;++o)if((a=r[o]).type===s.type&&a.name===s.name)return s.value}return}for(f=e?Jb:Qb,o=0;o<i;++o)this.each(f(r[o],e,n));return this}function km(t,e,n){var r=ef(t),o=r.CustomEvent;typeof o=="function"?o=new o(e,n):(o=r.document.createEvent("Event"),n?(o.initEvent(e,n.bubbles,n.cancelable),o.detail=n.detail):o.initEvent(e,!1,!1)),t.dispatchEvent(o)}function t2(t,e){return function(){return km(this,t,e)}}function e2(t,e){return function(){return km(this,t,e.apply(this,arguments))}}function Nm(t,e){return this.each((typeof e=="function"?e2:t2)(t,e))}function*Dm(){for(var t=this._groups,e=0,n=t.length;e<n;++e)for(var r=t[e],o=0,i=r.length,a;o<i;++o)(a=r[o])&&(yield a)}var vs=[null];function vt(t,e){this._groups=t,this._parents=e}function Rm(){return new vt([[document.documentElement]],vs)}function n2(){return this}vt.prototype=Rm.prototype={constructor:vt,select:Vp,selectAll:jp,selectChild:Zp,selectChildren:Kp,filter:Qp,data:em,enter:Jp,exit:nm,join:rm,merge:om,selection:n2,order:im,sort:am,call:fm,nodes:um,node:cm,size:sm,empty:lm,each:dm,attr:pm,style:mm,property:hm,classed:wm,text:vm,html:Mm,raise:Sm,lower:Tm,append:_m,insert:Cm,remove:Am,clone:$m,datum:Em,on:Im,dispatch:Nm,[Symbol.iterator]:Dm};var pn=Rm;function mn(t){return typeof t=="string"?new vt([[document.querySelector(t)]],[document.documentElement]):new vt([[t]],vs)}function Lm(t){let e;for(;e=t.sourceEvent;)t=e;return t}function Ms(t,e){if(t=Lm(t),e===void 0&&(e=t.currentTarget),e){var n=e.ownerSVGElement||e;if(n.createSVGPoint){var r=n.createSVGPoint();return r.x=t.clientX,r.y=t.clientY,r=r.matrixTransform(e.getScreenCTM().inverse()),[r.x,r.y]}if(e.getBoundingClientRect){var o=e.getBoundingClientRect();return[t.clientX-o.left-e.clientLeft,t.clientY-o.top-e.clientTop]}}return[t.pageX,t.pageY]}function hn(t,e,n){t.prototype=e.prototype=n,n.constructor=t}function En(t,e){var n=Object.create(t.prototype);for(var r in e)n[r]=e[r];return n}function Ue(){}var In=.7,ir=1/In,qr="\\s*([+-]?\\d+)\\s*",ti="\\s*([+-]?(?:\\d*\\.)?\\d+(?:[eE][+-]?\\d+)?)\\s*",Be="\\s*([+-]?(?:\\d*\\.)?\\d+(?:[eE][+-]?\\d+)?)%\\s*",r2=/^#([0-9a-f]{3,8})$/,o2=new RegExp(`^rgb\\(${qr},${qr},${qr}\\)$`),i2=new RegExp(`^rgb\\(${Be},${Be},${Be}\\)$`),a2=new RegExp(`^rgba\\(${qr},${qr},${qr},${ti}\\)$`),f2=new RegExp(`^rgba\\(${Be},${Be},${Be},${ti}\\)$`),u2=new RegExp(`^hsl\\(${ti},${Be},${Be}\\)$`),c2=new RegExp(`^hsla\\(${ti},${Be},${Be},${ti}\\)$`),Pm={aliceblue:15792383,antiquewhite:16444375,aqua:65535,aquamarine:8388564,azure:15794175,beige:16119260,bisque:16770244,black:0,blanchedalmond:16772045,blue:255,blueviolet:9055202,brown:10824234,burlywood:14596231,cadetblue:6266528,chartreuse:8388352,chocolate:13789470,coral:16744272,cornflowerblue:6591981,cornsilk:16775388,crimson:14423100,cyan:65535,darkblue:139,darkcyan:35723,darkgoldenrod:12092939,darkgray:11119017,darkgreen:25600,darkgrey:11119017,darkkhaki:12433259,darkmagenta:9109643,darkolivegreen:5597999,darkorange:16747520,darkorchid:10040012,darkred:9109504,darksalmon:15308410,darkseagreen:9419919,darkslateblue:4734347,darkslategray:3100495,darkslategrey:3100495,darkturquoise:52945,darkviolet:9699539,deeppink:16716947,deepskyblue:49151,dimgray:6908265,dimgrey:6908265,dodgerblue:2003199,firebrick:11674146,floralwhite:16775920,forestgreen:2263842,fuchsia:16711935,gainsboro:14474460,ghostwhite:16316671,gold:16766720,goldenrod:14329120,gray:8421504,green:32768,greenyellow:11403055,grey:8421504,honeydew:15794160,hotpink:16738740,indianred:13458524,indigo:4915330,ivory:16777200,khaki:15787660,lavender:15132410,lavenderblush:16773365,lawngreen:8190976,lemonchiffon:16775885,lightblue:11393254,lightcoral:15761536,lightcyan:14745599,lightgoldenrodyellow:16448210,lightgray:13882323,lightgreen:9498256,lightgrey:13882323,lightpink:16758465,lightsalmon:16752762,lightseagreen:2142890,lightskyblue:8900346,lightslategray:7833753,lightslategrey:7833753,lightsteelblue:11584734,lightyellow:16777184,lime:65280,limegreen:3329330,linen:16445670,magenta:16711935,maroon:8388608,mediumaquamarine:6737322,mediumblue:205,mediumorchid:12211667,mediumpurple:9662683,mediumseagreen:3978097,mediumslateblue:8087790,mediumspringgreen:64154,mediumturquoise:4772300,mediumvioletred:13047173,midnightblue:1644912,mintcream:16121850,mistyrose:16770273,moccasin:16770229,navajowhite:16768685,navy:128,oldlace:16643558,olive:8421376,olivedrab:7048739,orange:16753920,orangered:16729344,orchid:14315734,palegoldenrod:15657130,palegreen:10025880,paleturquoise:11529966,palevioletred:14381203,papayawhip:16773077,peachpuff:16767673,peru:13468991,pink:16761035,plum:14524637,powderblue:11591910,purple:8388736,rebeccapurple:6697881,red:16711680,rosybrown:12357519,royalblue:4286945,saddlebrown:9127187,salmon:16416882,sandybrown:16032864,seagreen:3050327,seashell:16774638,sienna:10506797,silver:12632256,skyblue:8900331,slateblue:6970061,slategray:7372944,slategrey:7372944,snow:16775930,springgreen:65407,steelblue:4620980,tan:13808780,teal:32896,thistle:14204888,tomato:16737095,turquoise:4251856,violet:15631086,wheat:16113331,white:16777215,whitesmoke:16119285,yellow:16776960,yellowgreen:10145074};hn(Ue,ue,{copy(t){return Object.assign(new this.constructor,this,t)},displayable(){return this.rgb().displayable()},hex:Om,formatHex:Om,formatHex8:s2,formatHsl:l2,formatRgb:Fm,toString:Fm});function Om(){return this.rgb().formatHex()}function s2(){return this.rgb().formatHex8()}function l2(){return Xm(this).formatHsl()}function Fm(){return this.rgb().formatRgb()}function ue(t){var e,n;return t=(t+"").trim().toLowerCase(),(e=r2.exec(t))?(n=e[1].length,e=parseInt(e[1],16),n===6?qm(e):n===3?new Tt(e>>8&15|e>>4&240,e>>4&15|e&240,(e&15)<<4|e&15,1):n===8?nf(e>>24&255,e>>16&255,e>>8&255,(e&255)/255):n===4?nf(e>>12&15|e>>8&240,e>>8&15|e>>4&240,e>>4&15|e&240,((e&15)<<4|e&15)/255):null):(e=o2.exec(t))?new Tt(e[1],e[2],e[3],1):(e=i2.exec(t))?new Tt(e[1]*255/100,e[2]*255/100,e[3]*255/100,1):(e=a2.exec(t))?nf(e[1],e[2],e[3],e[4]):(e=f2.exec(t))?nf(e[1]*255/100,e[2]*255/100,e[3]*255/100,e[4]):(e=u2.exec(t))?Bm(e[1],e[2]/100,e[3]/100,1):(e=c2.exec(t))?Bm(e[1],e[2]/100,e[3]/100,e[4]):Pm.hasOwnProperty(t)?qm(Pm[t]):t==="transparent"?new Tt(NaN,NaN,NaN,0):null}function qm(t){return new Tt(t>>16&255,t>>8&255,t&255,1)}function nf(t,e,n,r){return r<=0&&(t=e=n=NaN),new Tt(t,e,n,r)}function ei(t){return t instanceof Ue||(t=ue(t)),t?(t=t.rgb(),new Tt(t.r,t.g,t.b,t.opacity)):new Tt}function be(t,e,n,r){return arguments.length===1?ei(t):new Tt(t,e,n,r??1)}function Tt(t,e,n,r){this.r=+t,this.g=+e,this.b=+n,this.opacity=+r}hn(Tt,be,En(Ue,{brighter(t){return t=t==null?ir:Math.pow(ir,t),new Tt(this.r*t,this.g*t,this.b*t,this.opacity)},darker(t){return t=t==null?In:Math.pow(In,t),new Tt(this.r*t,this.g*t,this.b*t,this.opacity)},rgb(){return this},clamp(){return new Tt(or(this.r),or(this.g),or(this.b),of(this.opacity))},displayable(){return-.5<=this.r&&this.r<255.5&&-.5<=this.g&&this.g<255.5&&-.5<=this.b&&this.b<255.5&&0<=this.opacity&&this.opacity<=1},hex:zm,formatHex:zm,formatHex8:d2,formatRgb:Ym,toString:Ym}));function zm(){return`#${rr(this.r)}${rr(this.g)}${rr(this.b)}`}function d2(){return`#${rr(this.r)}${rr(this.g)}${rr(this.b)}${rr((isNaN(this.opacity)?1:this.opacity)*255)}`}function Ym(){let t=of(this.opacity);return`${t===1?"rgb(":"rgba("}${or(this.r)}, ${or(this.g)}, ${or(this.b)}${t===1?")":`, ${t})`}`}function of(t){return isNaN(t)?1:Math.max(0,Math.min(1,t))}function or(t){return Math.max(0,Math.min(255,Math.round(t)||0))}function rr(t){return t=or(t),(t<16?"0":"")+t.toString(16)}function Bm(t,e,n,r){return r<=0?t=e=n=NaN:n<=0||n>=1?t=e=NaN:e<=0&&(t=NaN),new Ee(t,e,n,r)}function Xm(t){if(t instanceof Ee)return new Ee(t.h,t.s,t.l,t.opacity);if(t instanceof Ue||(t=ue(t)),!t)return new Ee;if(t instanceof Ee)return t;t=t.rgb();var e=t.r/255,n=t.g/255,r=t.b/255,o=Math.min(e,n,r),i=Math.max(e,n,r),a=NaN,f=i-o,u=(i+o)/2;return f?(e===i?a=(n-r)/f+(n<r)*6:n===i?a=(r-e)/f+2:a=(e-n)/f+4,f/=u<.5?i+o:2-i-o,a*=60):f=u>0&&u<1?0:a,new Ee(a,f,u,t.opacity)}function ni(t,e,n,r){return arguments.length===1?Xm(t):new Ee(t,e,n,r??1)}function Ee(t,e,n,r){this.h=+t,this.s=+e,this.l=+n,this.opacity=+r}hn(Ee,ni,En(Ue,{brighter(t){return t=t==null?ir:Math.pow(ir,t),new Ee(this.h,this.s,this.l*t,this.opacity)},darker(t){return t=t==null?In:Math.pow(In,t),new Ee(this.h,this.s,this.l*t,this.opacity)},rgb(){var t=this.h%360+(this.h<0)*360,e=isNaN(t)||isNaN(this.s)?0:this.s,n=this.l,r=n+(n<.5?n:1-n)*e,o=2*n-r;return new Tt(Ss(t>=240?t-240:t+120,o,r),Ss(t,o,r),Ss(t<120?t+240:t-120,o,r),this.opacity)},clamp(){return new Ee(Um(this.h),rf(this.s),rf(this.l),of(this.opacity))},displayable(){return(0<=this.s&&this.s<=1||isNaN(this.s))&&0<=this.l&&this.l<=1&&0<=this.opacity&&this.opacity<=1},formatHsl(){let t=of(this.opacity);return`${t===1?"hsl(":"hsla("}${Um(this.h)}, ${rf(this.s)*100}%, ${rf(this.l)*100}%${t===1?")":`, ${t})`}`}}));function Um(t){return t=(t||0)%360,t<0?t+360:t}function rf(t){return Math.max(0,Math.min(1,t||0))}function Ss(t,e,n){return(t<60?e+(n-e)*t/60:t<180?n:t<240?e+(n-e)*(240-t)/60:e)*255}var af=Math.PI/180,ff=180/Math.PI;var uf=18,Hm=.96422,Wm=1,Gm=.82521,Vm=4/29,zr=6/29,jm=3*zr*zr,p2=zr*zr*zr;function Zm(t){if(t instanceof Xe)return new Xe(t.l,t.a,t.b,t.opacity);if(t instanceof gn)return Km(t);t instanceof Tt||(t=ei(t));var e=As(t.r),n=As(t.g),r=As(t.b),o=Ts((.2225045*e+.7168786*n+.0606169*r)/Wm),i,a;return e===n&&n===r?i=a=o:(i=Ts((.4360747*e+.3850649*n+.1430804*r)/Hm),a=Ts((.0139322*e+.0971045*n+.7141733*r)/Gm)),new Xe(116*o-16,500*(i-o),200*(o-a),t.opacity)}function Yr(t,e,n,r){return arguments.length===1?Zm(t):new Xe(t,e,n,r??1)}function Xe(t,e,n,r){this.l=+t,this.a=+e,this.b=+n,this.opacity=+r}hn(Xe,Yr,En(Ue,{brighter(t){return new Xe(this.l+uf*(t??1),this.a,this.b,this.opacity)},darker(t){return new Xe(this.l-uf*(t??1),this.a,this.b,this.opacity)},rgb(){var t=(this.l+16)/116,e=isNaN(this.a)?t:t+this.a/500,n=isNaN(this.b)?t:t-this.b/200;return e=Hm*_s(e),t=Wm*_s(t),n=Gm*_s(n),new Tt(Cs(3.1338561*e-1.6168667*t-.4906146*n),Cs(-.9787684*e+1.9161415*t+.033454*n),Cs(.0719453*e-.2289914*t+1.4052427*n),this.opacity)}}));function Ts(t){return t>p2?Math.pow(t,1/3):t/jm+Vm}function _s(t){return t>zr?t*t*t:jm*(t-Vm)}function Cs(t){return 255*(t<=.0031308?12.92*t:1.055*Math.pow(t,1/2.4)-.055)}function As(t){return(t/=255)<=.04045?t/12.92:Math.pow((t+.055)/1.055,2.4)}function m2(t){if(t instanceof gn)return new gn(t.h,t.c,t.l,t.opacity);if(t instanceof Xe||(t=Zm(t)),t.a===0&&t.b===0)return new gn(NaN,0<t.l&&t.l<100?0:NaN,t.l,t.opacity);var e=Math.atan2(t.b,t.a)*ff;return new gn(e<0?e+360:e,Math.sqrt(t.a*t.a+t.b*t.b),t.l,t.opacity)}function ri(t,e,n,r){return arguments.length===1?m2(t):new gn(t,e,n,r??1)}function gn(t,e,n,r){this.h=+t,this.c=+e,this.l=+n,this.opacity=+r}function Km(t){if(isNaN(t.h))return new Xe(t.l,0,0,t.opacity);var e=t.h*af;return new Xe(t.l,Math.cos(e)*t.c,Math.sin(e)*t.c,t.opacity)}hn(gn,ri,En(Ue,{brighter(t){return new gn(this.h,this.c,this.l+uf*(t??1),this.opacity)},darker(t){return new gn(this.h,this.c,this.l-uf*(t??1),this.opacity)},rgb(){return Km(this).rgb()}}));var eh=-.14861,$s=1.78277,Es=-.29227,cf=-.90649,oi=1.97294,Qm=oi*cf,Jm=oi*$s,th=$s*Es-cf*eh;function h2(t){if(t instanceof ar)return new ar(t.h,t.s,t.l,t.opacity);t instanceof Tt||(t=ei(t));var e=t.r/255,n=t.g/255,r=t.b/255,o=(th*r+Qm*e-Jm*n)/(th+Qm-Jm),i=r-o,a=(oi*(n-o)-Es*i)/cf,f=Math.sqrt(a*a+i*i)/(oi*o*(1-o)),u=f?Math.atan2(a,i)*ff-120:NaN;return new ar(u<0?u+360:u,f,o,t.opacity)}function te(t,e,n,r){return arguments.length===1?h2(t):new ar(t,e,n,r??1)}function ar(t,e,n,r){this.h=+t,this.s=+e,this.l=+n,this.opacity=+r}hn(ar,te,En(Ue,{brighter(t){return t=t==null?ir:Math.pow(ir,t),new ar(this.h,this.s,this.l*t,this.opacity)},darker(t){return t=t==null?In:Math.pow(In,t),new ar(this.h,this.s,this.l*t,this.opacity)},rgb(){var t=isNaN(this.h)?0:(this.h+120)*af,e=+this.l,n=isNaN(this.s)?0:this.s*e*(1-e),r=Math.cos(t),o=Math.sin(t);return new Tt(255*(e+n*(eh*r+$s*o)),255*(e+n*(Es*r+cf*o)),255*(e+n*(oi*r)),this.opacity)}}));function Is(t,e,n,r,o){var i=t*t,a=i*t;return((1-3*t+3*i-a)*e+(4-6*i+3*a)*n+(1+3*t+3*i-3*a)*r+a*o)/6}function nh(t){var e=t.length-1;return function(n){var r=n<=0?n=0:n>=1?(n=1,e-1):Math.floor(n*e),o=t[r],i=t[r+1],a=r>0?t[r-1]:2*o-i,f=r<e-1?t[r+2]:2*i-o;return Is((n-r/e)*e,a,o,i,f)}}function rh(t){var e=t.length;return function(n){var r=Math.floor(((n%=1)<0?++n:n)*e),o=t[(r+e-1)%e],i=t[r%e],a=t[(r+1)%e],f=t[(r+2)%e];return Is((n-r/e)*e,o,i,a,f)}}var Br=t=>()=>t;function oh(t,e){return function(n){return t+n*e}}function g2(t,e,n){return t=Math.pow(t,n),e=Math.pow(e,n)-t,n=1/n,function(r){return Math.pow(t+r*e,n)}}function Ur(t,e){var n=e-t;return n?oh(t,n>180||n<-180?n-360*Math.round(n/360):n):Br(isNaN(t)?e:t)}function ih(t){return(t=+t)==1?xt:function(e,n){return n-e?g2(e,n,t):Br(isNaN(e)?n:e)}}function xt(t,e){var n=e-t;return n?oh(t,n):Br(isNaN(t)?e:t)}var we=function t(e){var n=ih(e);function r(o,i){var a=n((o=be(o)).r,(i=be(i)).r),f=n(o.g,i.g),u=n(o.b,i.b),c=xt(o.opacity,i.opacity);return function(s){return o.r=a(s),o.g=f(s),o.b=u(s),o.opacity=c(s),o+""}}return r.gamma=t,r}(1);function ah(t){return function(e){var n=e.length,r=new Array(n),o=new Array(n),i=new Array(n),a,f;for(a=0;a<n;++a)f=be(e[a]),r[a]=f.r||0,o[a]=f.g||0,i[a]=f.b||0;return r=t(r),o=t(o),i=t(i),f.opacity=1,function(u){return f.r=r(u),f.g=o(u),f.b=i(u),f+""}}}var ks=ah(nh),x2=ah(rh);function fh(t,e){e||(e=[]);var n=t?Math.min(e.length,t.length):0,r=e.slice(),o;return function(i){for(o=0;o<n;++o)r[o]=t[o]*(1-i)+e[o]*i;return r}}function uh(t){return ArrayBuffer.isView(t)&&!(t instanceof DataView)}function ch(t,e){var n=e?e.length:0,r=t?Math.min(n,t.length):0,o=new Array(r),i=new Array(n),a;for(a=0;a<r;++a)o[a]=Ie(t[a],e[a]);for(;a<n;++a)i[a]=e[a];return function(f){for(a=0;a<r;++a)i[a]=o[a](f);return i}}function sh(t,e){var n=new Date;return t=+t,e=+e,function(r){return n.setTime(t*(1-r)+e*r),n}}function ht(t,e){return t=+t,e=+e,function(n){return t*(1-n)+e*n}}function lh(t,e){var n={},r={},o;(t===null||typeof t!="object")&&(t={}),(e===null||typeof e!="object")&&(e={});for(o in e)o in t?n[o]=Ie(t[o],e[o]):r[o]=e[o];return function(i){for(o in n)r[o]=n[o](i);return r}}var Ds=/[-+]?(?:\d+\.?\d*|\.?\d+)(?:[eE][-+]?\d+)?/g,Ns=new RegExp(Ds.source,"g");function y2(t){return function(){return t}}function b2(t){return function(e){return t(e)+""}}function ii(t,e){var n=Ds.lastIndex=Ns.lastIndex=0,r,o,i,a=-1,f=[],u=[];for(t=t+"",e=e+"";(r=Ds.exec(t))&&(o=Ns.exec(e));)(i=o.index)>n&&(i=e.slice(n,i),f[a]?f[a]+=i:f[++a]=i),(r=r[0])===(o=o[0])?f[a]?f[a]+=o:f[++a]=o:(f[++a]=null,u.push({i:a,x:ht(r,o)})),n=Ns.lastIndex;return n<e.length&&(i=e.slice(n),f[a]?f[a]+=i:f[++a]=i),f.length<2?u[0]?b2(u[0].x):y2(e):(e=u.length,function(c){for(var s=0,d;s<e;++s)f[(d=u[s]).i]=d.x(c);return f.join("")})}function Ie(t,e){var n=typeof e,r;return e==null||n==="boolean"?Br(e):(n==="number"?ht:n==="string"?(r=ue(e))?(e=r,we):ii:e instanceof ue?we:e instanceof Date?sh:uh(e)?fh:Array.isArray(e)?ch:typeof e.valueOf!="function"&&typeof e.toString!="function"||isNaN(e)?lh:ht)(t,e)}function fr(t,e){return t=+t,e=+e,function(n){return Math.round(t*(1-n)+e*n)}}var dh=180/Math.PI,sf={translateX:0,translateY:0,rotate:0,skewX:0,scaleX:1,scaleY:1};function Rs(t,e,n,r,o,i){var a,f,u;return(a=Math.sqrt(t*t+e*e))&&(t/=a,e/=a),(u=t*n+e*r)&&(n-=t*u,r-=e*u),(f=Math.sqrt(n*n+r*r))&&(n/=f,r/=f,u/=f),t*r<e*n&&(t=-t,e=-e,u=-u,a=-a),{translateX:o,translateY:i,rotate:Math.atan2(e,t)*dh,skewX:Math.atan(u)*dh,scaleX:a,scaleY:f}}var lf;function ph(t){let e=new(typeof DOMMatrix=="function"?DOMMatrix:WebKitCSSMatrix)(t+"");return e.isIdentity?sf:Rs(e.a,e.b,e.c,e.d,e.e,e.f)}function mh(t){return t==null?sf:(lf||(lf=document.createElementNS("http://www.w3.org/2000/svg","g")),lf.setAttribute("transform",t),(t=lf.transform.baseVal.consolidate())?(t=t.matrix,Rs(t.a,t.b,t.c,t.d,t.e,t.f)):sf)}function hh(t,e,n,r){function o(c){return c.length?c.pop()+" ":""}function i(c,s,d,l,p,m){if(c!==d||s!==l){var h=p.push("translate(",null,e,null,n);m.push({i:h-4,x:ht(c,d)},{i:h-2,x:ht(s,l)})}else(d||l)&&p.push("translate("+d+e+l+n)}function a(c,s,d,l){c!==s?(c-s>180?s+=360:s-c>180&&(c+=360),l.push({i:d.push(o(d)+"rotate(",null,r)-2,x:ht(c,s)})):s&&d.push(o(d)+"rotate("+s+r)}function f(c,s,d,l){c!==s?l.push({i:d.push(o(d)+"skewX(",null,r)-2,x:ht(c,s)}):s&&d.push(o(d)+"skewX("+s+r)}function u(c,s,d,l,p,m){if(c!==d||s!==l){var h=p.push(o(p)+"scale(",null,",",null,")");m.push({i:h-4,x:ht(c,d)},{i:h-2,x:ht(s,l)})}else(d!==1||l!==1)&&p.push(o(p)+"scale("+d+","+l+")")}return function(c,s){var d=[],l=[];return c=t(c),s=t(s),i(c.translateX,c.translateY,s.translateX,s.translateY,d,l),a(c.rotate,s.rotate,d,l),f(c.skewX,s.skewX,d,l),u(c.scaleX,c.scaleY,s.scaleX,s.scaleY,d,l),c=s=null,function(p){for(var m=-1,h=l.length,g;++m<h;)d[(g=l[m]).i]=g.x(p);return d.join("")}}}var Ls=hh(ph,"px, ","px)","deg)"),Ps=hh(mh,", ",")",")");function gh(t){return function(e,n){var r=t((e=ni(e)).h,(n=ni(n)).h),o=xt(e.s,n.s),i=xt(e.l,n.l),a=xt(e.opacity,n.opacity);return function(f){return e.h=r(f),e.s=o(f),e.l=i(f),e.opacity=a(f),e+""}}}var Os=gh(Ur),w2=gh(xt);function df(t,e){var n=xt((t=Yr(t)).l,(e=Yr(e)).l),r=xt(t.a,e.a),o=xt(t.b,e.b),i=xt(t.opacity,e.opacity);return function(a){return t.l=n(a),t.a=r(a),t.b=o(a),t.opacity=i(a),t+""}}function xh(t){return function(e,n){var r=t((e=ri(e)).h,(n=ri(n)).h),o=xt(e.c,n.c),i=xt(e.l,n.l),a=xt(e.opacity,n.opacity);return function(f){return e.h=r(f),e.c=o(f),e.l=i(f),e.opacity=a(f),e+""}}}var Fs=xh(Ur),v2=xh(xt);function yh(t){return function e(n){n=+n;function r(o,i){var a=t((o=te(o)).h,(i=te(i)).h),f=xt(o.s,i.s),u=xt(o.l,i.l),c=xt(o.opacity,i.opacity);return function(s){return o.h=a(s),o.s=f(s),o.l=u(Math.pow(s,n)),o.opacity=c(s),o+""}}return r.gamma=e,r}(1)}var M2=yh(Ur),Xr=yh(xt);function He(t,e){e===void 0&&(e=t,t=Ie);for(var n=0,r=e.length-1,o=e[0],i=new Array(r<0?0:r);n<r;)i[n]=t(o,o=e[++n]);return function(a){var f=Math.max(0,Math.min(r-1,Math.floor(a*=r)));return i[f](a-f)}}function Wt(t,e){for(var n=new Array(e),r=0;r<e;++r)n[r]=t(r/(e-1));return n}var Hr=0,fi=0,ai=0,wh=1e3,pf,ui,mf=0,ur=0,hf=0,ci=typeof performance=="object"&&performance.now?performance:Date,vh=typeof window=="object"&&window.requestAnimationFrame?window.requestAnimationFrame.bind(window):function(t){setTimeout(t,17)};function li(){return ur||(vh(S2),ur=ci.now()+hf)}function S2(){ur=0}function si(){this._call=this._time=this._next=null}si.prototype=gf.prototype={constructor:si,restart:function(t,e,n){if(typeof t!="function")throw new TypeError("callback is not a function");n=(n==null?li():+n)+(e==null?0:+e),!this._next&&ui!==this&&(ui?ui._next=this:pf=this,ui=this),this._call=t,this._time=n,qs()},stop:function(){this._call&&(this._call=null,this._time=1/0,qs())}};function gf(t,e,n){var r=new si;return r.restart(t,e,n),r}function Mh(){li(),++Hr;for(var t=pf,e;t;)(e=ur-t._time)>=0&&t._call.call(void 0,e),t=t._next;--Hr}function bh(){ur=(mf=ci.now())+hf,Hr=fi=0;try{Mh()}finally{Hr=0,_2(),ur=0}}function T2(){var t=ci.now(),e=t-mf;e>wh&&(hf-=e,mf=t)}function _2(){for(var t,e=pf,n,r=1/0;e;)e._call?(r>e._time&&(r=e._time),t=e,e=e._next):(n=e._next,e._next=null,e=t?t._next=n:pf=n);ui=t,qs(r)}function qs(t){if(!Hr){fi&&(fi=clearTimeout(fi));var e=t-ur;e>24?(t<1/0&&(fi=setTimeout(bh,t-ci.now()-hf)),ai&&(ai=clearInterval(ai))):(ai||(mf=ci.now(),ai=setInterval(T2,wh)),Hr=1,vh(bh))}}function xf(t,e,n){var r=new si;return e=e==null?0:+e,r.restart(o=>{r.stop(),t(o+e)},e,n),r}var C2=ys("start","end","cancel","interrupt"),A2=[],_h=0,Sh=1,bf=2,yf=3,Th=4,wf=5,di=6;function kn(t,e,n,r,o,i){var a=t.__transition;if(!a)t.__transition={};else if(n in a)return;$2(t,n,{name:e,index:r,group:o,on:C2,tween:A2,time:i.time,delay:i.delay,duration:i.duration,ease:i.ease,timer:null,state:_h})}function pi(t,e){var n=At(t,e);if(n.state>_h)throw new Error("too late; already scheduled");return n}function Ft(t,e){var n=At(t,e);if(n.state>yf)throw new Error("too late; already running");return n}function At(t,e){var n=t.__transition;if(!n||!(n=n[e]))throw new Error("transition not found");return n}function $2(t,e,n){var r=t.__transition,o;r[e]=n,n.timer=gf(i,0,n.time);function i(c){n.state=Sh,n.timer.restart(a,n.delay,n.time),n.delay<=c&&a(c-n.delay)}function a(c){var s,d,l,p;if(n.state!==Sh)return u();for(s in r)if(p=r[s],p.name===n.name){if(p.state===yf)return xf(a);p.state===Th?(p.state=di,p.timer.stop(),p.on.call("interrupt",t,t.__data__,p.index,p.group),delete r[s]):+s<e&&(p.state=di,p.timer.stop(),p.on.call("cancel",t,t.__data__,p.index,p.group),delete r[s])}if(xf(function(){n.state===yf&&(n.state=Th,n.timer.restart(f,n.delay,n.time),f(c))}),n.state=bf,n.on.call("start",t,t.__data__,n.index,n.group),n.state===bf){for(n.state=yf,o=new Array(l=n.tween.length),s=0,d=-1;s<l;++s)(p=n.tween[s].value.call(t,t.__data__,n.index,n.group))&&(o[++d]=p);o.length=d+1}}function f(c){for(var s=c<n.duration?n.ease.call(null,c/n.duration):(n.timer.restart(u),n.state=wf,1),d=-1,l=o.length;++d<l;)o[d].call(t,s);n.state===wf&&(n.on.call("end",t,t.__data__,n.index,n.group),u())}function u(){n.state=di,n.timer.stop(),delete r[e];for(var c in r)return;delete t.__transition}}function vf(t,e){var n=t.__transition,r,o,i=!0,a;if(n){e=e==null?null:e+"";for(a in n){if((r=n[a]).name!==e){i=!1;continue}o=r.state>bf&&r.state<wf,r.state=di,r.timer.stop(),r.on.call(o?"interrupt":"cancel",t,t.__data__,r.index,r.group),delete n[a]}i&&delete t.__transition}}function Ch(t){return this.each(function(){vf(this,t)})}function E2(t,e){var n,r;return function(){var o=Ft(this,t),i=o.tween;if(i!==n){r=n=i;for(var a=0,f=r.length;a<f;++a)if(r[a].name===e){r=r.slice(),r.splice(a,1);break}}o.tween=r}}function I2(t,e,n){var r,o;if(typeof n!="function")throw new Error;return function(){var i=Ft(this,t),a=i.tween;if(a!==r){o=(r=a).slice();for(var f={name:e,value:n},u=0,c=o.length;u<c;++u)if(o[u].name===e){o[u]=f;break}u===c&&o.push(f)}i.tween=o}}function Ah(t,e){var n=this._id;if(t+="",arguments.length<2){for(var r=At(this.node(),n).tween,o=0,i=r.length,a;o<i;++o)if((a=r[o]).name===t)return a.value;return null}return this.each((e==null?E2:I2)(n,t,e))}function Wr(t,e,n){var r=t._id;return t.each(function(){var o=Ft(this,r);(o.value||(o.value={}))[e]=n.apply(this,arguments)}),function(o){return At(o,r).value[e]}}function Mf(t,e){var n;return(typeof e=="number"?ht:e instanceof ue?we:(n=ue(e))?(e=n,we):ii)(t,e)}function k2(t){return function(){this.removeAttribute(t)}}function N2(t){return function(){this.removeAttributeNS(t.space,t.local)}}function D2(t,e,n){var r,o=n+"",i;return function(){var a=this.getAttribute(t);return a===o?null:a===r?i:i=e(r=a,n)}}function R2(t,e,n){var r,o=n+"",i;return function(){var a=this.getAttributeNS(t.space,t.local);return a===o?null:a===r?i:i=e(r=a,n)}}function L2(t,e,n){var r,o,i;return function(){var a,f=n(this),u;return f==null?void this.removeAttribute(t):(a=this.getAttribute(t),u=f+"",a===u?null:a===r&&u===o?i:(o=u,i=e(r=a,f)))}}function P2(t,e,n){var r,o,i;return function(){var a,f=n(this),u;return f==null?void this.removeAttributeNS(t.space,t.local):(a=this.getAttributeNS(t.space,t.local),u=f+"",a===u?null:a===r&&u===o?i:(o=u,i=e(r=a,f)))}}function $h(t,e){var n=ln(t),r=n==="transform"?Ps:Mf;return this.attrTween(t,typeof e=="function"?(n.local?P2:L2)(n,r,Wr(this,"attr."+t,e)):e==null?(n.local?N2:k2)(n):(n.local?R2:D2)(n,r,e))}function O2(t,e){return function(n){this.setAttribute(t,e.call(this,n))}}function F2(t,e){return function(n){this.setAttributeNS(t.space,t.local,e.call(this,n))}}function q2(t,e){var n,r;function o(){var i=e.apply(this,arguments);return i!==r&&(n=(r=i)&&F2(t,i)),n}return o._value=e,o}function z2(t,e){var n,r;function o(){var i=e.apply(this,arguments);return i!==r&&(n=(r=i)&&O2(t,i)),n}return o._value=e,o}function Eh(t,e){var n="attr."+t;if(arguments.length<2)return(n=this.tween(n))&&n._value;if(e==null)return this.tween(n,null);if(typeof e!="function")throw new Error;var r=ln(t);return this.tween(n,(r.local?q2:z2)(r,e))}function Y2(t,e){return function(){pi(this,t).delay=+e.apply(this,arguments)}}function B2(t,e){return e=+e,function(){pi(this,t).delay=e}}function Ih(t){var e=this._id;return arguments.length?this.each((typeof t=="function"?Y2:B2)(e,t)):At(this.node(),e).delay}function U2(t,e){return function(){Ft(this,t).duration=+e.apply(this,arguments)}}function X2(t,e){return e=+e,function(){Ft(this,t).duration=e}}function kh(t){var e=this._id;return arguments.length?this.each((typeof t=="function"?U2:X2)(e,t)):At(this.node(),e).duration}function H2(t,e){if(typeof e!="function")throw new Error;return function(){Ft(this,t).ease=e}}function Nh(t){var e=this._id;return arguments.length?this.each(H2(e,t)):At(this.node(),e).ease}function W2(t,e){return function(){var n=e.apply(this,arguments);if(typeof n!="function")throw new Error;Ft(this,t).ease=n}}function Dh(t){if(typeof t!="function")throw new Error;return this.each(W2(this._id,t))}function Rh(t){typeof t!="function"&&(t=Qo(t));for(var e=this._groups,n=e.length,r=new Array(n),o=0;o<n;++o)for(var i=e[o],a=i.length,f=r[o]=[],u,c=0;c<a;++c)(u=i[c])&&t.call(u,u.__data__,c,i)&&f.push(u);return new ee(r,this._parents,this._name,this._id)}function Lh(t){if(t._id!==this._id)throw new Error;for(var e=this._groups,n=t._groups,r=e.length,o=n.length,i=Math.min(r,o),a=new Array(r),f=0;f<i;++f)for(var u=e[f],c=n[f],s=u.length,d=a[f]=new Array(s),l,p=0;p<s;++p)(l=u[p]||c[p])&&(d[p]=l);for(;f<r;++f)a[f]=e[f];return new ee(a,this._parents,this._name,this._id)}function G2(t){return(t+"").trim().split(/^|\s+/).every(function(e){var n=e.indexOf(".");return n>=0&&(e=e.slice(0,n)),!e||e==="start"})}function V2(t,e,n){var r,o,i=G2(e)?pi:Ft;return function(){var a=i(this,t),f=a.on;f!==r&&(o=(r=f).copy()).on(e,n),a.on=o}}function Ph(t,e){var n=this._id;return arguments.length<2?At(this.node(),n).on.on(t):this.each(V2(n,t,e))}function j2(t){return function(){var e=this.parentNode;for(var n in this.__transition)if(+n!==t)return;e&&e.removeChild(this)}}function Oh(){return this.on("end.remove",j2(this._id))}function Fh(t){var e=this._name,n=this._id;typeof t!="function"&&(t=nr(t));for(var r=this._groups,o=r.length,i=new Array(o),a=0;a<o;++a)for(var f=r[a],u=f.length,c=i[a]=new Array(u),s,d,l=0;l<u;++l)(s=f[l])&&(d=t.call(s,s.__data__,l,f))&&("__data__"in s&&(d.__data__=s.__data__),c[l]=d,kn(c[l],e,n,l,c,At(s,n)));return new ee(i,this._parents,e,n)}function qh(t){var e=this._name,n=this._id;typeof t!="function"&&(t=Ko(t));for(var r=this._groups,o=r.length,i=[],a=[],f=0;f<o;++f)for(var u=r[f],c=u.length,s,d=0;d<c;++d)if(s=u[d]){for(var l=t.call(s,s.__data__,d,u),p,m=At(s,n),h=0,g=l.length;h<g;++h)(p=l[h])&&kn(p,e,n,h,l,m);i.push(l),a.push(s)}return new ee(i,a,e,n)}var Z2=pn.prototype.constructor;function zh(){return new Z2(this._groups,this._parents)}function K2(t,e){var n,r,o;return function(){var i=$n(this,t),a=(this.style.removeProperty(t),$n(this,t));return i===a?null:i===n&&a===r?o:o=e(n=i,r=a)}}function Yh(t){return function(){this.style.removeProperty(t)}}function Q2(t,e,n){var r,o=n+"",i;return function(){var a=$n(this,t);return a===o?null:a===r?i:i=e(r=a,n)}}function J2(t,e,n){var r,o,i;return function(){var a=$n(this,t),f=n(this),u=f+"";return f==null&&(u=f=(this.style.removeProperty(t),$n(this,t))),a===u?null:a===r&&u===o?i:(o=u,i=e(r=a,f))}}function tw(t,e){var n,r,o,i="style."+e,a="end."+i,f;return function(){var u=Ft(this,t),c=u.on,s=u.value[i]==null?f||(f=Yh(e)):void 0;(c!==n||o!==s)&&(r=(n=c).copy()).on(a,o=s),u.on=r}}function Bh(t,e,n){var r=(t+="")=="transform"?Ls:Mf;return e==null?this.styleTween(t,K2(t,r)).on("end.style."+t,Yh(t)):typeof e=="function"?this.styleTween(t,J2(t,r,Wr(this,"style."+t,e))).each(tw(this._id,t)):this.styleTween(t,Q2(t,r,e),n).on("end.style."+t,null)}function ew(t,e,n){return function(r){this.style.setProperty(t,e.call(this,r),n)}}function nw(t,e,n){var r,o;function i(){var a=e.apply(this,arguments);return a!==o&&(r=(o=a)&&ew(t,a,n)),r}return i._value=e,i}function Uh(t,e,n){var r="style."+(t+="");if(arguments.length<2)return(r=this.tween(r))&&r._value;if(e==null)return this.tween(r,null);if(typeof e!="function")throw new Error;return this.tween(r,nw(t,e,n??""))}function rw(t){return function(){this.textContent=t}}function ow(t){return function(){var e=t(this);this.textContent=e??""}}function Xh(t){return this.tween("text",typeof t=="function"?ow(Wr(this,"text",t)):rw(t==null?"":t+""))}function iw(t){return function(e){this.textContent=t.call(this,e)}}function aw(t){var e,n;function r(){var o=t.apply(this,arguments);return o!==n&&(e=(n=o)&&iw(o)),e}return r._value=t,r}function Hh(t){var e="text";if(arguments.length<1)return(e=this.tween(e))&&e._value;if(t==null)return this.tween(e,null);if(typeof t!="function")throw new Error;return this.tween(e,aw(t))}function Wh(){for(var t=this._name,e=this._id,n=Sf(),r=this._groups,o=r.length,i=0;i<o;++i)for(var a=r[i],f=a.length,u,c=0;c<f;++c)if(u=a[c]){var s=At(u,e);kn(u,t,n,c,a,{time:s.time+s.delay+s.duration,delay:0,duration:s.duration,ease:s.ease})}return new ee(r,this._parents,t,n)}function Gh(){var t,e,n=this,r=n._id,o=n.size();return new Promise(function(i,a){var f={value:a},u={value:function(){--o===0&&i()}};n.each(function(){var c=Ft(this,r),s=c.on;s!==t&&(e=(t=s).copy(),e._.cancel.push(f),e._.interrupt.push(f),e._.end.push(u)),c.on=e}),o===0&&i()})}var fw=0;function ee(t,e,n,r){this._groups=t,this._parents=e,this._name=n,this._id=r}function Vh(t){return pn().transition(t)}function Sf(){return++fw}var xn=pn.prototype;ee.prototype=Vh.prototype={constructor:ee,select:Fh,selectAll:qh,selectChild:xn.selectChild,selectChildren:xn.selectChildren,filter:Rh,merge:Lh,selection:zh,transition:Wh,call:xn.call,nodes:xn.nodes,node:xn.node,size:xn.size,empty:xn.empty,each:xn.each,on:Ph,attr:$h,attrTween:Eh,style:Bh,styleTween:Uh,text:Xh,textTween:Hh,remove:Oh,tween:Ah,delay:Ih,duration:kh,ease:Nh,easeVarying:Dh,end:Gh,[Symbol.iterator]:xn[Symbol.iterator]};function Tf(t){return((t*=2)<=1?t*t*t:(t-=2)*t*t+2)/2}var uw={time:null,delay:0,duration:250,ease:Tf};function cw(t,e){for(var n;!(n=t.__transition)||!(n=n[e]);)if(!(t=t.parentNode))throw new Error(`transition ${e} not found`);return n}function jh(t){var e,n;t instanceof ee?(e=t._id,t=t._name):(e=Sf(),(n=uw).time=li(),t=t==null?null:t+"");for(var r=this._groups,o=r.length,i=0;i<o;++i)for(var a=r[i],f=a.length,u,c=0;c<f;++c)(u=a[c])&&kn(u,t,e,c,a,n||cw(u,e));return new ee(r,this._parents,t,e)}pn.prototype.interrupt=Ch;pn.prototype.transition=jh;var{abs:LC,max:PC,min:OC}=Math;function Zh(t){return[+t[0],+t[1]]}function sw(t){return[Zh(t[0]),Zh(t[1])]}var FC={name:"x",handles:["w","e"].map(zs),input:function(t,e){return t==null?null:[[+t[0],e[0][1]],[+t[1],e[1][1]]]},output:function(t){return t&&[t[0][0],t[1][0]]}},qC={name:"y",handles:["n","s"].map(zs),input:function(t,e){return t==null?null:[[e[0][0],+t[0]],[e[1][0],+t[1]]]},output:function(t){return t&&[t[0][1],t[1][1]]}},zC={name:"xy",handles:["n","w","e","s","nw","ne","sw","se"].map(zs),input:function(t){return t==null?null:sw(t)},output:function(t){return t}};function zs(t){return{type:t}}var Ys=Math.PI,Bs=2*Ys,cr=1e-6,lw=Bs-cr;function Kh(t){this._+=t[0];for(let e=1,n=t.length;e<n;++e)this._+=arguments[e]+t[e]}function dw(t){let e=Math.floor(t);if(!(e>=0))throw new Error(`invalid digits: ${t}`);if(e>15)return Kh;let n=10**e;return function(r){this._+=r[0];for(let o=1,i=r.length;o<i;++o)this._+=Math.round(arguments[o]*n)/n+r[o]}}var Gr=class{constructor(e){this._x0=this._y0=this._x1=this._y1=null,this._="",this._append=e==null?Kh:dw(e)}moveTo(e,n){this._append`M${this._x0=this._x1=+e},${this._y0=this._y1=+n}`}closePath(){this._x1!==null&&(this._x1=this._x0,this._y1=this._y0,this._append`Z`)}lineTo(e,n){this._append`L${this._x1=+e},${this._y1=+n}`}quadraticCurveTo(e,n,r,o){this._append`Q${+e},${+n},${this._x1=+r},${this._y1=+o}`}bezierCurveTo(e,n,r,o,i,a){this._append`C${+e},${+n},${+r},${+o},${this._x1=+i},${this._y1=+a}`}arcTo(e,n,r,o,i){if(e=+e,n=+n,r=+r,o=+o,i=+i,i<0)throw new Error(`negative radius: ${i}`);let a=this._x1,f=this._y1,u=r-e,c=o-n,s=a-e,d=f-n,l=s*s+d*d;if(this._x1===null)this._append`M${this._x1=e},${this._y1=n}`;else if(l>cr)if(!(Math.abs(d*u-c*s)>cr)||!i)this._append`L${this._x1=e},${this._y1=n}`;else{let p=r-a,m=o-f,h=u*u+c*c,g=p*p+m*m,b=Math.sqrt(h),x=Math.sqrt(l),M=i*Math.tan((Ys-Math.acos((h+l-g)/(2*b*x)))/2),y=M/x,w=M/b;Math.abs(y-1)>cr&&this._append`L${e+y*s},${n+y*d}`,this._append`A${i},${i},0,0,${+(d*p>s*m)},${this._x1=e+w*u},${this._y1=n+w*c}`}}arc(e,n,r,o,i,a){if(e=+e,n=+n,r=+r,a=!!a,r<0)throw new Error(`negative radius: ${r}`);let f=r*Math.cos(o),u=r*Math.sin(o),c=e+f,s=n+u,d=1^a,l=a?o-i:i-o;this._x1===null?this._append`M${c},${s}`:(Math.abs(this._x1-c)>cr||Math.abs(this._y1-s)>cr)&&this._append`L${c},${s}`,r&&(l<0&&(l=l%Bs+Bs),l>lw?this._append`A${r},${r},0,1,${d},${e-f},${n-u}A${r},${r},0,1,${d},${this._x1=c},${this._y1=s}`:l>cr&&this._append`A${r},${r},0,${+(l>=Ys)},${d},${this._x1=e+r*Math.cos(i)},${this._y1=n+r*Math.sin(i)}`)}rect(e,n,r,o){this._append`M${this._x0=this._x1=+e},${this._y0=this._y1=+n}h${r=+r}v${+o}h${-r}Z`}toString(){return this._}};function Qh(){return new Gr}Qh.prototype=Gr.prototype;function Vr(t=3){return new Gr(+t)}function Jh(t){return Math.abs(t=Math.round(t))>=1e21?t.toLocaleString("en").replace(/,/g,""):t.toString(10)}function sr(t,e){if((n=(t=e?t.toExponential(e-1):t.toExponential()).indexOf("e"))<0)return null;var n,r=t.slice(0,n);return[r.length>1?r[0]+r.slice(2):r,+t.slice(n+1)]}function We(t){return t=sr(Math.abs(t)),t?t[1]:NaN}function t0(t,e){return function(n,r){for(var o=n.length,i=[],a=0,f=t[0],u=0;o>0&&f>0&&(u+f+1>r&&(f=Math.max(1,r-u)),i.push(n.substring(o-=f,o+f)),!((u+=f+1)>r));)f=t[a=(a+1)%t.length];return i.reverse().join(e)}}function e0(t){return function(e){return e.replace(/[0-9]/g,function(n){return t[+n]})}}var pw=/^(?:(.)?([<>=^]))?([+\-( ])?([$#])?(0)?(\d+)?(,)?(\.\d+)?(~)?([a-z%])?$/i;function Ge(t){if(!(e=pw.exec(t)))throw new Error("invalid format: "+t);var e;return new _f({fill:e[1],align:e[2],sign:e[3],symbol:e[4],zero:e[5],width:e[6],comma:e[7],precision:e[8]&&e[8].slice(1),trim:e[9],type:e[10]})}Ge.prototype=_f.prototype;function _f(t){this.fill=t.fill===void 0?" ":t.fill+"",this.align=t.align===void 0?">":t.align+"",this.sign=t.sign===void 0?"-":t.sign+"",this.symbol=t.symbol===void 0?"":t.symbol+"",this.zero=!!t.zero,this.width=t.width===void 0?void 0:+t.width,this.comma=!!t.comma,this.precision=t.precision===void 0?void 0:+t.precision,this.trim=!!t.trim,this.type=t.type===void 0?"":t.type+""}_f.prototype.toString=function(){return this.fill+this.align+this.sign+this.symbol+(this.zero?"0":"")+(this.width===void 0?"":Math.max(1,this.width|0))+(this.comma?",":"")+(this.precision===void 0?"":"."+Math.max(0,this.precision|0))+(this.trim?"~":"")+this.type};function n0(t){t:for(var e=t.length,n=1,r=-1,o;n<e;++n)switch(t[n]){case".":r=o=n;break;case"0":r===0&&(r=n),o=n;break;default:if(!+t[n])break t;r>0&&(r=0);break}return r>0?t.slice(0,r)+t.slice(o+1):t}var Us;function r0(t,e){var n=sr(t,e);if(!n)return t+"";var r=n[0],o=n[1],i=o-(Us=Math.max(-8,Math.min(8,Math.floor(o/3)))*3)+1,a=r.length;return i===a?r:i>a?r+new Array(i-a+1).join("0"):i>0?r.slice(0,i)+"."+r.slice(i):"0."+new Array(1-i).join("0")+sr(t,Math.max(0,e+i-1))[0]}function Xs(t,e){var n=sr(t,e);if(!n)return t+"";var r=n[0],o=n[1];return o<0?"0."+new Array(-o).join("0")+r:r.length>o+1?r.slice(0,o+1)+"."+r.slice(o+1):r+new Array(o-r.length+2).join("0")}var Hs={"%":(t,e)=>(t*100).toFixed(e),b:t=>Math.round(t).toString(2),c:t=>t+"",d:Jh,e:(t,e)=>t.toExponential(e),f:(t,e)=>t.toFixed(e),g:(t,e)=>t.toPrecision(e),o:t=>Math.round(t).toString(8),p:(t,e)=>Xs(t*100,e),r:Xs,s:r0,X:t=>Math.round(t).toString(16).toUpperCase(),x:t=>Math.round(t).toString(16)};function Ws(t){return t}var o0=Array.prototype.map,i0=["y","z","a","f","p","n","\xB5","m","","k","M","G","T","P","E","Z","Y"];function a0(t){var e=t.grouping===void 0||t.thousands===void 0?Ws:t0(o0.call(t.grouping,Number),t.thousands+""),n=t.currency===void 0?"":t.currency[0]+"",r=t.currency===void 0?"":t.currency[1]+"",o=t.decimal===void 0?".":t.decimal+"",i=t.numerals===void 0?Ws:e0(o0.call(t.numerals,String)),a=t.percent===void 0?"%":t.percent+"",f=t.minus===void 0?"\u2212":t.minus+"",u=t.nan===void 0?"NaN":t.nan+"";function c(d){d=Ge(d);var l=d.fill,p=d.align,m=d.sign,h=d.symbol,g=d.zero,b=d.width,x=d.comma,M=d.precision,y=d.trim,w=d.type;w==="n"?(x=!0,w="g"):Hs[w]||(M===void 0&&(M=12),y=!0,w="g"),(g||l==="0"&&p==="=")&&(g=!0,l="0",p="=");var v=h==="$"?n:h==="#"&&/[boxX]/.test(w)?"0"+w.toLowerCase():"",T=h==="$"?r:/[%p]/.test(w)?a:"",E=Hs[w],I=/[defgprs%]/.test(w);M=M===void 0?6:/[gprs]/.test(w)?Math.max(1,Math.min(21,M)):Math.max(0,Math.min(20,M));function F(_){var R=v,O=T,L,S,A;if(w==="c")O=E(_)+O,_="";else{_=+_;var N=_<0||1/_<0;if(_=isNaN(_)?u:E(Math.abs(_),M),y&&(_=n0(_)),N&&+_==0&&m!=="+"&&(N=!1),R=(N?m==="("?m:f:m==="-"||m==="("?"":m)+R,O=(w==="s"?i0[8+Us/3]:"")+O+(N&&m==="("?")":""),I){for(L=-1,S=_.length;++L<S;)if(A=_.charCodeAt(L),48>A||A>57){O=(A===46?o+_.slice(L+1):_.slice(L))+O,_=_.slice(0,L);break}}}x&&!g&&(_=e(_,1/0));var P=R.length+_.length+O.length,D=P<b?new Array(b-P+1).join(l):"";switch(x&&g&&(_=e(D+_,D.length?b-O.length:1/0),D=""),p){case"<":_=R+_+O+D;break;case"=":_=R+D+_+O;break;case"^":_=D.slice(0,P=D.length>>1)+R+_+O+D.slice(P);break;default:_=D+R+_+O;break}return i(_)}return F.toString=function(){return d+""},F}function s(d,l){var p=c((d=Ge(d),d.type="f",d)),m=Math.max(-8,Math.min(8,Math.floor(We(l)/3)))*3,h=Math.pow(10,-m),g=i0[8+m/3];return function(b){return p(h*b)+g}}return{format:c,formatPrefix:s}}var Cf,Ve,Af;Gs({thousands:",",grouping:[3],currency:["$",""]});function Gs(t){return Cf=a0(t),Ve=Cf.format,Af=Cf.formatPrefix,Cf}function Vs(t){return Math.max(0,-We(Math.abs(t)))}function js(t,e){return Math.max(0,Math.max(-8,Math.min(8,Math.floor(We(e)/3)))*3-We(Math.abs(t)))}function Zs(t,e){return t=Math.abs(t),e=Math.abs(e)-t,Math.max(0,We(e)-We(t))+1}var U=1e-6,$f=1e-12,V=Math.PI,ft=V/2,Ks=V/4,Dt=V*2,$t=180/V,ut=V/180,K=Math.abs,ve=Math.atan,qt=Math.atan2,B=Math.cos;var Ef=Math.exp;var lr=Math.log,If=Math.pow,Y=Math.sin,Gt=Math.sign||function(t){return t>0?1:t<0?-1:0},mt=Math.sqrt,jr=Math.tan;function kf(t){return t>1?0:t<-1?V:Math.acos(t)}function Mt(t){return t>1?ft:t<-1?-ft:Math.asin(t)}function Rt(){}function Nf(t,e){t&&u0.hasOwnProperty(t.type)&&u0[t.type](t,e)}var f0={Feature:function(t,e){Nf(t.geometry,e)},FeatureCollection:function(t,e){for(var n=t.features,r=-1,o=n.length;++r<o;)Nf(n[r].geometry,e)}},u0={Sphere:function(t,e){e.sphere()},Point:function(t,e){t=t.coordinates,e.point(t[0],t[1],t[2])},MultiPoint:function(t,e){for(var n=t.coordinates,r=-1,o=n.length;++r<o;)t=n[r],e.point(t[0],t[1],t[2])},LineString:function(t,e){Qs(t.coordinates,e,0)},MultiLineString:function(t,e){for(var n=t.coordinates,r=-1,o=n.length;++r<o;)Qs(n[r],e,0)},Polygon:function(t,e){c0(t.coordinates,e)},MultiPolygon:function(t,e){for(var n=t.coordinates,r=-1,o=n.length;++r<o;)c0(n[r],e)},GeometryCollection:function(t,e){for(var n=t.geometries,r=-1,o=n.length;++r<o;)Nf(n[r],e)}};function Qs(t,e,n){var r=-1,o=t.length-n,i;for(e.lineStart();++r<o;)i=t[r],e.point(i[0],i[1],i[2]);e.lineEnd()}function c0(t,e){var n=-1,r=t.length;for(e.polygonStart();++n<r;)Qs(t[n],e,1);e.polygonEnd()}function ke(t,e){t&&f0.hasOwnProperty(t.type)?f0[t.type](t,e):Nf(t,e)}function mi(t){return[qt(t[1],t[0]),Mt(t[2])]}function je(t){var e=t[0],n=t[1],r=B(n);return[r*B(e),r*Y(e),Y(n)]}function hi(t,e){return t[0]*e[0]+t[1]*e[1]+t[2]*e[2]}function Zr(t,e){return[t[1]*e[2]-t[2]*e[1],t[2]*e[0]-t[0]*e[2],t[0]*e[1]-t[1]*e[0]]}function Df(t,e){t[0]+=e[0],t[1]+=e[1],t[2]+=e[2]}function gi(t,e){return[t[0]*e,t[1]*e,t[2]*e]}function xi(t){var e=mt(t[0]*t[0]+t[1]*t[1]+t[2]*t[2]);t[0]/=e,t[1]/=e,t[2]/=e}function yi(t,e){function n(r,o){return r=t(r,o),e(r[0],r[1])}return t.invert&&e.invert&&(n.invert=function(r,o){return r=e.invert(r,o),r&&t.invert(r[0],r[1])}),n}function Js(t,e){return K(t)>V&&(t-=Math.round(t/Dt)*Dt),[t,e]}Js.invert=Js;function tl(t,e,n){return(t%=Dt)?e||n?yi(l0(t),d0(e,n)):l0(t):e||n?d0(e,n):Js}function s0(t){return function(e,n){return e+=t,K(e)>V&&(e-=Math.round(e/Dt)*Dt),[e,n]}}function l0(t){var e=s0(t);return e.invert=s0(-t),e}function d0(t,e){var n=B(t),r=Y(t),o=B(e),i=Y(e);function a(f,u){var c=B(u),s=B(f)*c,d=Y(f)*c,l=Y(u),p=l*n+s*r;return[qt(d*o-p*i,s*n-l*r),Mt(p*o+d*i)]}return a.invert=function(f,u){var c=B(u),s=B(f)*c,d=Y(f)*c,l=Y(u),p=l*o-d*i;return[qt(d*o+l*i,s*n+p*r),Mt(p*n-s*r)]},a}function p0(t){t=tl(t[0]*ut,t[1]*ut,t.length>2?t[2]*ut:0);function e(n){return n=t(n[0]*ut,n[1]*ut),n[0]*=$t,n[1]*=$t,n}return e.invert=function(n){return n=t.invert(n[0]*ut,n[1]*ut),n[0]*=$t,n[1]*=$t,n},e}function h0(t,e,n,r,o,i){if(n){var a=B(e),f=Y(e),u=r*n;o==null?(o=e+r*Dt,i=e-u/2):(o=m0(a,o),i=m0(a,i),(r>0?o<i:o>i)&&(o+=r*Dt));for(var c,s=o;r>0?s>i:s<i;s-=u)c=mi([a,-f*B(s),-f*Y(s)]),t.point(c[0],c[1])}}function m0(t,e){e=je(e),e[0]-=t,xi(e);var n=kf(-e[1]);return((-e[2]<0?-n:n)+Dt-U)%Dt}function Rf(){var t=[],e;return{point:function(n,r,o){e.push([n,r,o])},lineStart:function(){t.push(e=[])},lineEnd:Rt,rejoin:function(){t.length>1&&t.push(t.pop().concat(t.shift()))},result:function(){var n=t;return t=[],e=null,n}}}function Kr(t,e){return K(t[0]-e[0])<U&&K(t[1]-e[1])<U}function Lf(t,e,n,r){this.x=t,this.z=e,this.o=n,this.e=r,this.v=!1,this.n=this.p=null}function Pf(t,e,n,r,o){var i=[],a=[],f,u;if(t.forEach(function(m){if(!((h=m.length-1)<=0)){var h,g=m[0],b=m[h],x;if(Kr(g,b)){if(!g[2]&&!b[2]){for(o.lineStart(),f=0;f<h;++f)o.point((g=m[f])[0],g[1]);o.lineEnd();return}b[0]+=2*U}i.push(x=new Lf(g,m,null,!0)),a.push(x.o=new Lf(g,null,x,!1)),i.push(x=new Lf(b,m,null,!1)),a.push(x.o=new Lf(b,null,x,!0))}}),!!i.length){for(a.sort(e),g0(i),g0(a),f=0,u=a.length;f<u;++f)a[f].e=n=!n;for(var c=i[0],s,d;;){for(var l=c,p=!0;l.v;)if((l=l.n)===c)return;s=l.z,o.lineStart();do{if(l.v=l.o.v=!0,l.e){if(p)for(f=0,u=s.length;f<u;++f)o.point((d=s[f])[0],d[1]);else r(l.x,l.n.x,1,o);l=l.n}else{if(p)for(s=l.p.z,f=s.length-1;f>=0;--f)o.point((d=s[f])[0],d[1]);else r(l.x,l.p.x,-1,o);l=l.p}l=l.o,s=l.z,p=!p}while(!l.v);o.lineEnd()}}}function g0(t){if(e=t.length){for(var e,n=0,r=t[0],o;++n<e;)r.n=o=t[n],o.p=r,r=o;r.n=o=t[0],o.p=r}}function el(t){return K(t[0])<=V?t[0]:Gt(t[0])*((K(t[0])+V)%Dt-V)}function x0(t,e){var n=el(e),r=e[1],o=Y(r),i=[Y(n),-B(n),0],a=0,f=0,u=new ae;o===1?r=ft+U:o===-1&&(r=-ft-U);for(var c=0,s=t.length;c<s;++c)if(l=(d=t[c]).length)for(var d,l,p=d[l-1],m=el(p),h=p[1]/2+Ks,g=Y(h),b=B(h),x=0;x<l;++x,m=y,g=v,b=T,p=M){var M=d[x],y=el(M),w=M[1]/2+Ks,v=Y(w),T=B(w),E=y-m,I=E>=0?1:-1,F=I*E,_=F>V,R=g*v;if(u.add(qt(R*I*Y(F),b*T+R*B(F))),a+=_?E+I*Dt:E,_^m>=n^y>=n){var O=Zr(je(p),je(M));xi(O);var L=Zr(i,O);xi(L);var S=(_^E>=0?-1:1)*Mt(L[2]);(r>S||r===S&&(O[0]||O[1]))&&(f+=_^E>=0?1:-1)}}return(a<-U||a<U&&u<-$f)^f&1}function Of(t,e,n,r){return function(o){var i=e(o),a=Rf(),f=e(a),u=!1,c,s,d,l={point:p,lineStart:h,lineEnd:g,polygonStart:function(){l.point=b,l.lineStart=x,l.lineEnd=M,s=[],c=[]},polygonEnd:function(){l.point=p,l.lineStart=h,l.lineEnd=g,s=Fr(s);var y=x0(c,r);s.length?(u||(o.polygonStart(),u=!0),Pf(s,hw,y,n,o)):y&&(u||(o.polygonStart(),u=!0),o.lineStart(),n(null,null,1,o),o.lineEnd()),u&&(o.polygonEnd(),u=!1),s=c=null},sphere:function(){o.polygonStart(),o.lineStart(),n(null,null,1,o),o.lineEnd(),o.polygonEnd()}};function p(y,w){t(y,w)&&o.point(y,w)}function m(y,w){i.point(y,w)}function h(){l.point=m,i.lineStart()}function g(){l.point=p,i.lineEnd()}function b(y,w){d.push([y,w]),f.point(y,w)}function x(){f.lineStart(),d=[]}function M(){b(d[0][0],d[0][1]),f.lineEnd();var y=f.clean(),w=a.result(),v,T=w.length,E,I,F;if(d.pop(),c.push(d),d=null,!!T){if(y&1){if(I=w[0],(E=I.length-1)>0){for(u||(o.polygonStart(),u=!0),o.lineStart(),v=0;v<E;++v)o.point((F=I[v])[0],F[1]);o.lineEnd()}return}T>1&&y&2&&w.push(w.pop().concat(w.shift())),s.push(w.filter(mw))}}return l}}function mw(t){return t.length>1}function hw(t,e){return((t=t.x)[0]<0?t[1]-ft-U:ft-t[1])-((e=e.x)[0]<0?e[1]-ft-U:ft-e[1])}var nl=Of(function(){return!0},gw,yw,[-V,-ft]);function gw(t){var e=NaN,n=NaN,r=NaN,o;return{lineStart:function(){t.lineStart(),o=1},point:function(i,a){var f=i>0?V:-V,u=K(i-e);K(u-V)<U?(t.point(e,n=(n+a)/2>0?ft:-ft),t.point(r,n),t.lineEnd(),t.lineStart(),t.point(f,n),t.point(i,n),o=0):r!==f&&u>=V&&(K(e-r)<U&&(e-=r*U),K(i-f)<U&&(i-=f*U),n=xw(e,n,i,a),t.point(r,n),t.lineEnd(),t.lineStart(),t.point(f,n),o=0),t.point(e=i,n=a),r=f},lineEnd:function(){t.lineEnd(),e=n=NaN},clean:function(){return 2-o}}}function xw(t,e,n,r){var o,i,a=Y(t-n);return K(a)>U?ve((Y(e)*(i=B(r))*Y(n)-Y(r)*(o=B(e))*Y(t))/(o*i*a)):(e+r)/2}function yw(t,e,n,r){var o;if(t==null)o=n*ft,r.point(-V,o),r.point(0,o),r.point(V,o),r.point(V,0),r.point(V,-o),r.point(0,-o),r.point(-V,-o),r.point(-V,0),r.point(-V,o);else if(K(t[0]-e[0])>U){var i=t[0]<e[0]?V:-V;o=n*i/2,r.point(-i,o),r.point(0,o),r.point(i,o)}else r.point(e[0],e[1])}function y0(t){var e=B(t),n=6*ut,r=e>0,o=K(e)>U;function i(s,d,l,p){h0(p,t,n,l,s,d)}function a(s,d){return B(s)*B(d)>e}function f(s){var d,l,p,m,h;return{lineStart:function(){m=p=!1,h=1},point:function(g,b){var x=[g,b],M,y=a(g,b),w=r?y?0:c(g,b):y?c(g+(g<0?V:-V),b):0;if(!d&&(m=p=y)&&s.lineStart(),y!==p&&(M=u(d,x),(!M||Kr(d,M)||Kr(x,M))&&(x[2]=1)),y!==p)h=0,y?(s.lineStart(),M=u(x,d),s.point(M[0],M[1])):(M=u(d,x),s.point(M[0],M[1],2),s.lineEnd()),d=M;else if(o&&d&&r^y){var v;!(w&l)&&(v=u(x,d,!0))&&(h=0,r?(s.lineStart(),s.point(v[0][0],v[0][1]),s.point(v[1][0],v[1][1]),s.lineEnd()):(s.point(v[1][0],v[1][1]),s.lineEnd(),s.lineStart(),s.point(v[0][0],v[0][1],3)))}y&&(!d||!Kr(d,x))&&s.point(x[0],x[1]),d=x,p=y,l=w},lineEnd:function(){p&&s.lineEnd(),d=null},clean:function(){return h|(m&&p)<<1}}}function u(s,d,l){var p=je(s),m=je(d),h=[1,0,0],g=Zr(p,m),b=hi(g,g),x=g[0],M=b-x*x;if(!M)return!l&&s;var y=e*b/M,w=-e*x/M,v=Zr(h,g),T=gi(h,y),E=gi(g,w);Df(T,E);var I=v,F=hi(T,I),_=hi(I,I),R=F*F-_*(hi(T,T)-1);if(!(R<0)){var O=mt(R),L=gi(I,(-F-O)/_);if(Df(L,T),L=mi(L),!l)return L;var S=s[0],A=d[0],N=s[1],P=d[1],D;A<S&&(D=S,S=A,A=D);var tt=A-S,Z=K(tt-V)<U,W=Z||tt<U;if(!Z&&P<N&&(D=N,N=P,P=D),W?Z?N+P>0^L[1]<(K(L[0]-S)<U?N:P):N<=L[1]&&L[1]<=P:tt>V^(S<=L[0]&&L[0]<=A)){var Q=gi(I,(-F+O)/_);return Df(Q,T),[L,mi(Q)]}}}function c(s,d){var l=r?t:V-t,p=0;return s<-l?p|=1:s>l&&(p|=2),d<-l?p|=4:d>l&&(p|=8),p}return Of(a,f,i,r?[0,-t]:[-V,t-V])}function b0(t,e,n,r,o,i){var a=t[0],f=t[1],u=e[0],c=e[1],s=0,d=1,l=u-a,p=c-f,m;if(m=n-a,!(!l&&m>0)){if(m/=l,l<0){if(m<s)return;m<d&&(d=m)}else if(l>0){if(m>d)return;m>s&&(s=m)}if(m=o-a,!(!l&&m<0)){if(m/=l,l<0){if(m>d)return;m>s&&(s=m)}else if(l>0){if(m<s)return;m<d&&(d=m)}if(m=r-f,!(!p&&m>0)){if(m/=p,p<0){if(m<s)return;m<d&&(d=m)}else if(p>0){if(m>d)return;m>s&&(s=m)}if(m=i-f,!(!p&&m<0)){if(m/=p,p<0){if(m>d)return;m>s&&(s=m)}else if(p>0){if(m<s)return;m<d&&(d=m)}return s>0&&(t[0]=a+s*l,t[1]=f+s*p),d<1&&(e[0]=a+d*l,e[1]=f+d*p),!0}}}}}var bi=1e9,Ff=-bi;function Qr(t,e,n,r){function o(c,s){return t<=c&&c<=n&&e<=s&&s<=r}function i(c,s,d,l){var p=0,m=0;if(c==null||(p=a(c,d))!==(m=a(s,d))||u(c,s)<0^d>0)do l.point(p===0||p===3?t:n,p>1?r:e);while((p=(p+d+4)%4)!==m);else l.point(s[0],s[1])}function a(c,s){return K(c[0]-t)<U?s>0?0:3:K(c[0]-n)<U?s>0?2:1:K(c[1]-e)<U?s>0?1:0:s>0?3:2}function f(c,s){return u(c.x,s.x)}function u(c,s){var d=a(c,1),l=a(s,1);return d!==l?d-l:d===0?s[1]-c[1]:d===1?c[0]-s[0]:d===2?c[1]-s[1]:s[0]-c[0]}return function(c){var s=c,d=Rf(),l,p,m,h,g,b,x,M,y,w,v,T={point:E,lineStart:R,lineEnd:O,polygonStart:F,polygonEnd:_};function E(S,A){o(S,A)&&s.point(S,A)}function I(){for(var S=0,A=0,N=p.length;A<N;++A)for(var P=p[A],D=1,tt=P.length,Z=P[0],W,Q,at=Z[0],Ut=Z[1];D<tt;++D)W=at,Q=Ut,Z=P[D],at=Z[0],Ut=Z[1],Q<=r?Ut>r&&(at-W)*(r-Q)>(Ut-Q)*(t-W)&&++S:Ut<=r&&(at-W)*(r-Q)<(Ut-Q)*(t-W)&&--S;return S}function F(){s=d,l=[],p=[],v=!0}function _(){var S=I(),A=v&&S,N=(l=Fr(l)).length;(A||N)&&(c.polygonStart(),A&&(c.lineStart(),i(null,null,1,c),c.lineEnd()),N&&Pf(l,f,S,i,c),c.polygonEnd()),s=c,l=p=m=null}function R(){T.point=L,p&&p.push(m=[]),w=!0,y=!1,x=M=NaN}function O(){l&&(L(h,g),b&&y&&d.rejoin(),l.push(d.result())),T.point=E,y&&s.lineEnd()}function L(S,A){var N=o(S,A);if(p&&m.push([S,A]),w)h=S,g=A,b=N,w=!1,N&&(s.lineStart(),s.point(S,A));else if(N&&y)s.point(S,A);else{var P=[x=Math.max(Ff,Math.min(bi,x)),M=Math.max(Ff,Math.min(bi,M))],D=[S=Math.max(Ff,Math.min(bi,S)),A=Math.max(Ff,Math.min(bi,A))];b0(P,D,t,e,n,r)?(y||(s.lineStart(),s.point(P[0],P[1])),s.point(D[0],D[1]),N||s.lineEnd(),v=!1):N&&(s.lineStart(),s.point(S,A),v=!1)}x=S,M=A,y=N}return T}}var wi=t=>t;var rl=new ae,ol=new ae,w0,v0,il,al,Nn={point:Rt,lineStart:Rt,lineEnd:Rt,polygonStart:function(){Nn.lineStart=bw,Nn.lineEnd=vw},polygonEnd:function(){Nn.lineStart=Nn.lineEnd=Nn.point=Rt,rl.add(K(ol)),ol=new ae},result:function(){var t=rl/2;return rl=new ae,t}};function bw(){Nn.point=ww}function ww(t,e){Nn.point=M0,w0=il=t,v0=al=e}function M0(t,e){ol.add(al*t-il*e),il=t,al=e}function vw(){M0(w0,v0)}var fl=Nn;var Jr=1/0,qf=Jr,vi=-Jr,zf=vi,Mw={point:Sw,lineStart:Rt,lineEnd:Rt,polygonStart:Rt,polygonEnd:Rt,result:function(){var t=[[Jr,qf],[vi,zf]];return vi=zf=-(qf=Jr=1/0),t}};function Sw(t,e){t<Jr&&(Jr=t),t>vi&&(vi=t),e<qf&&(qf=e),e>zf&&(zf=e)}var to=Mw;var ul=0,cl=0,Mi=0,Yf=0,Bf=0,eo=0,sl=0,ll=0,Si=0,_0,C0,Ze,Ke,Ne={point:dr,lineStart:S0,lineEnd:T0,polygonStart:function(){Ne.lineStart=Cw,Ne.lineEnd=Aw},polygonEnd:function(){Ne.point=dr,Ne.lineStart=S0,Ne.lineEnd=T0},result:function(){var t=Si?[sl/Si,ll/Si]:eo?[Yf/eo,Bf/eo]:Mi?[ul/Mi,cl/Mi]:[NaN,NaN];return ul=cl=Mi=Yf=Bf=eo=sl=ll=Si=0,t}};function dr(t,e){ul+=t,cl+=e,++Mi}function S0(){Ne.point=Tw}function Tw(t,e){Ne.point=_w,dr(Ze=t,Ke=e)}function _w(t,e){var n=t-Ze,r=e-Ke,o=mt(n*n+r*r);Yf+=o*(Ze+t)/2,Bf+=o*(Ke+e)/2,eo+=o,dr(Ze=t,Ke=e)}function T0(){Ne.point=dr}function Cw(){Ne.point=$w}function Aw(){A0(_0,C0)}function $w(t,e){Ne.point=A0,dr(_0=Ze=t,C0=Ke=e)}function A0(t,e){var n=t-Ze,r=e-Ke,o=mt(n*n+r*r);Yf+=o*(Ze+t)/2,Bf+=o*(Ke+e)/2,eo+=o,o=Ke*t-Ze*e,sl+=o*(Ze+t),ll+=o*(Ke+e),Si+=o*3,dr(Ze=t,Ke=e)}var dl=Ne;function Uf(t){this._context=t}Uf.prototype={_radius:4.5,pointRadius:function(t){return this._radius=t,this},polygonStart:function(){this._line=0},polygonEnd:function(){this._line=NaN},lineStart:function(){this._point=0},lineEnd:function(){this._line===0&&this._context.closePath(),this._point=NaN},point:function(t,e){switch(this._point){case 0:{this._context.moveTo(t,e),this._point=1;break}case 1:{this._context.lineTo(t,e);break}default:{this._context.moveTo(t+this._radius,e),this._context.arc(t,e,this._radius,0,Dt);break}}},result:Rt};var ml=new ae,pl,$0,E0,Ti,_i,Xf={point:Rt,lineStart:function(){Xf.point=Ew},lineEnd:function(){pl&&I0($0,E0),Xf.point=Rt},polygonStart:function(){pl=!0},polygonEnd:function(){pl=null},result:function(){var t=+ml;return ml=new ae,t}};function Ew(t,e){Xf.point=I0,$0=Ti=t,E0=_i=e}function I0(t,e){Ti-=t,_i-=e,ml.add(mt(Ti*Ti+_i*_i)),Ti=t,_i=e}var hl=Xf;var k0,Hf,N0,D0,no=class{constructor(e){this._append=e==null?R0:Iw(e),this._radius=4.5,this._=""}pointRadius(e){return this._radius=+e,this}polygonStart(){this._line=0}polygonEnd(){this._line=NaN}lineStart(){this._point=0}lineEnd(){this._line===0&&(this._+="Z"),this._point=NaN}point(e,n){switch(this._point){case 0:{this._append`M${e},${n}`,this._point=1;break}case 1:{this._append`L${e},${n}`;break}default:{if(this._append`M${e},${n}`,this._radius!==N0||this._append!==Hf){let r=this._radius,o=this._;this._="",this._append`m0,${r}a${r},${r} 0 1,1 0,${-2*r}a${r},${r} 0 1,1 0,${2*r}z`,N0=r,Hf=this._append,D0=this._,this._=o}this._+=D0;break}}}result(){let e=this._;return this._="",e.length?e:null}};function R0(t){let e=1;this._+=t[0];for(let n=t.length;e<n;++e)this._+=arguments[e]+t[e]}function Iw(t){let e=Math.floor(t);if(!(e>=0))throw new RangeError(`invalid digits: ${t}`);if(e>15)return R0;if(e!==k0){let n=10**e;k0=e,Hf=function(o){let i=1;this._+=o[0];for(let a=o.length;i<a;++i)this._+=Math.round(arguments[i]*n)/n+o[i]}}return Hf}function Ci(t,e){let n=3,r=4.5,o,i;function a(f){return f&&(typeof r=="function"&&i.pointRadius(+r.apply(this,arguments)),ke(f,o(i))),i.result()}return a.area=function(f){return ke(f,o(fl)),fl.result()},a.measure=function(f){return ke(f,o(hl)),hl.result()},a.bounds=function(f){return ke(f,o(to)),to.result()},a.centroid=function(f){return ke(f,o(dl)),dl.result()},a.projection=function(f){return arguments.length?(o=f==null?(t=null,wi):(t=f).stream,a):t},a.context=function(f){return arguments.length?(i=f==null?(e=null,new no(n)):new Uf(e=f),typeof r!="function"&&i.pointRadius(r),a):e},a.pointRadius=function(f){return arguments.length?(r=typeof f=="function"?f:(i.pointRadius(+f),+f),a):r},a.digits=function(f){if(!arguments.length)return n;if(f==null)n=null;else{let u=Math.floor(f);if(!(u>=0))throw new RangeError(`invalid digits: ${f}`);n=u}return e===null&&(i=new no(n)),a},a.projection(t).digits(n).context(e)}function Ai(t){return{stream:ro(t)}}function ro(t){return function(e){var n=new gl;for(var r in t)n[r]=t[r];return n.stream=e,n}}function gl(){}gl.prototype={constructor:gl,point:function(t,e){this.stream.point(t,e)},sphere:function(){this.stream.sphere()},lineStart:function(){this.stream.lineStart()},lineEnd:function(){this.stream.lineEnd()},polygonStart:function(){this.stream.polygonStart()},polygonEnd:function(){this.stream.polygonEnd()}};function xl(t,e,n){var r=t.clipExtent&&t.clipExtent();return t.scale(150).translate([0,0]),r!=null&&t.clipExtent(null),ke(n,t.stream(to)),e(to.result()),r!=null&&t.clipExtent(r),t}function $i(t,e,n){return xl(t,function(r){var o=e[1][0]-e[0][0],i=e[1][1]-e[0][1],a=Math.min(o/(r[1][0]-r[0][0]),i/(r[1][1]-r[0][1])),f=+e[0][0]+(o-a*(r[1][0]+r[0][0]))/2,u=+e[0][1]+(i-a*(r[1][1]+r[0][1]))/2;t.scale(150*a).translate([f,u])},n)}function Wf(t,e,n){return $i(t,[[0,0],e],n)}function Gf(t,e,n){return xl(t,function(r){var o=+e,i=o/(r[1][0]-r[0][0]),a=(o-i*(r[1][0]+r[0][0]))/2,f=-i*r[0][1];t.scale(150*i).translate([a,f])},n)}function Vf(t,e,n){return xl(t,function(r){var o=+e,i=o/(r[1][1]-r[0][1]),a=-i*r[0][0],f=(o-i*(r[1][1]+r[0][1]))/2;t.scale(150*i).translate([a,f])},n)}var L0=16,kw=B(30*ut);function yl(t,e){return+e?Dw(t,e):Nw(t)}function Nw(t){return ro({point:function(e,n){e=t(e,n),this.stream.point(e[0],e[1])}})}function Dw(t,e){function n(r,o,i,a,f,u,c,s,d,l,p,m,h,g){var b=c-r,x=s-o,M=b*b+x*x;if(M>4*e&&h--){var y=a+l,w=f+p,v=u+m,T=mt(y*y+w*w+v*v),E=Mt(v/=T),I=K(K(v)-1)<U||K(i-d)<U?(i+d)/2:qt(w,y),F=t(I,E),_=F[0],R=F[1],O=_-r,L=R-o,S=x*O-b*L;(S*S/M>e||K((b*O+x*L)/M-.5)>.3||a*l+f*p+u*m<kw)&&(n(r,o,i,a,f,u,_,R,I,y/=T,w/=T,v,h,g),g.point(_,R),n(_,R,I,y,w,v,c,s,d,l,p,m,h,g))}}return function(r){var o,i,a,f,u,c,s,d,l,p,m,h,g={point:b,lineStart:x,lineEnd:y,polygonStart:function(){r.polygonStart(),g.lineStart=w},polygonEnd:function(){r.polygonEnd(),g.lineStart=x}};function b(E,I){E=t(E,I),r.point(E[0],E[1])}function x(){d=NaN,g.point=M,r.lineStart()}function M(E,I){var F=je([E,I]),_=t(E,I);n(d,l,s,p,m,h,d=_[0],l=_[1],s=E,p=F[0],m=F[1],h=F[2],L0,r),r.point(d,l)}function y(){g.point=b,r.lineEnd()}function w(){x(),g.point=v,g.lineEnd=T}function v(E,I){M(o=E,I),i=d,a=l,f=p,u=m,c=h,g.point=M}function T(){n(d,l,s,p,m,h,i,a,o,f,u,c,L0,r),g.lineEnd=y,y()}return g}}var Rw=ro({point:function(t,e){this.stream.point(t*ut,e*ut)}});function Lw(t){return ro({point:function(e,n){var r=t(e,n);return this.stream.point(r[0],r[1])}})}function Pw(t,e,n,r,o){function i(a,f){return a*=r,f*=o,[e+t*a,n-t*f]}return i.invert=function(a,f){return[(a-e)/t*r,(n-f)/t*o]},i}function P0(t,e,n,r,o,i){if(!i)return Pw(t,e,n,r,o);var a=B(i),f=Y(i),u=a*t,c=f*t,s=a/t,d=f/t,l=(f*n-a*e)/t,p=(f*e+a*n)/t;function m(h,g){return h*=r,g*=o,[u*h-c*g+e,n-c*h-u*g]}return m.invert=function(h,g){return[r*(s*h-d*g+l),o*(p-d*h-s*g)]},m}function zt(t){return bl(function(){return t})()}function bl(t){var e,n=150,r=480,o=250,i=0,a=0,f=0,u=0,c=0,s,d=0,l=1,p=1,m=null,h=nl,g=null,b,x,M,y=wi,w=.5,v,T,E,I,F;function _(S){return E(S[0]*ut,S[1]*ut)}function R(S){return S=E.invert(S[0],S[1]),S&&[S[0]*$t,S[1]*$t]}_.stream=function(S){return I&&F===S?I:I=Rw(Lw(s)(h(v(y(F=S)))))},_.preclip=function(S){return arguments.length?(h=S,m=void 0,L()):h},_.postclip=function(S){return arguments.length?(y=S,g=b=x=M=null,L()):y},_.clipAngle=function(S){return arguments.length?(h=+S?y0(m=S*ut):(m=null,nl),L()):m*$t},_.clipExtent=function(S){return arguments.length?(y=S==null?(g=b=x=M=null,wi):Qr(g=+S[0][0],b=+S[0][1],x=+S[1][0],M=+S[1][1]),L()):g==null?null:[[g,b],[x,M]]},_.scale=function(S){return arguments.length?(n=+S,O()):n},_.translate=function(S){return arguments.length?(r=+S[0],o=+S[1],O()):[r,o]},_.center=function(S){return arguments.length?(i=S[0]%360*ut,a=S[1]%360*ut,O()):[i*$t,a*$t]},_.rotate=function(S){return arguments.length?(f=S[0]%360*ut,u=S[1]%360*ut,c=S.length>2?S[2]%360*ut:0,O()):[f*$t,u*$t,c*$t]},_.angle=function(S){return arguments.length?(d=S%360*ut,O()):d*$t},_.reflectX=function(S){return arguments.length?(l=S?-1:1,O()):l<0},_.reflectY=function(S){return arguments.length?(p=S?-1:1,O()):p<0},_.precision=function(S){return arguments.length?(v=yl(T,w=S*S),L()):mt(w)},_.fitExtent=function(S,A){return $i(_,S,A)},_.fitSize=function(S,A){return Wf(_,S,A)},_.fitWidth=function(S,A){return Gf(_,S,A)},_.fitHeight=function(S,A){return Vf(_,S,A)};function O(){var S=P0(n,0,0,l,p,d).apply(null,e(i,a)),A=P0(n,r-S[0],o-S[1],l,p,d);return s=tl(f,u,c),T=yi(e,A),E=yi(s,T),v=yl(T,w),L()}function L(){return I=F=null,_}return function(){return e=t.apply(this,arguments),_.invert=e.invert&&R,O()}}function oo(t){var e=0,n=V/3,r=bl(t),o=r(e,n);return o.parallels=function(i){return arguments.length?r(e=i[0]*ut,n=i[1]*ut):[e*$t,n*$t]},o}function O0(t){var e=B(t);function n(r,o){return[r*e,Y(o)/e]}return n.invert=function(r,o){return[r/e,Mt(o*e)]},n}function F0(t,e){var n=Y(t),r=(n+Y(e))/2;if(K(r)<U)return O0(t);var o=1+n*(2*r-n),i=mt(o)/r;function a(f,u){var c=mt(o-2*r*Y(u))/r;return[c*Y(f*=r),i-c*B(f)]}return a.invert=function(f,u){var c=i-u,s=qt(f,K(c))*Gt(c);return c*r<0&&(s-=V*Gt(f)*Gt(c)),[s/r,Mt((o-(f*f+c*c)*r*r)/(2*r))]},a}function Dn(){return oo(F0).scale(155.424).center([0,33.6442])}function Ei(){return Dn().parallels([29.5,45.5]).scale(1070).translate([480,250]).rotate([96,0]).center([-.6,38.7])}function Ow(t){var e=t.length;return{point:function(n,r){for(var o=-1;++o<e;)t[o].point(n,r)},sphere:function(){for(var n=-1;++n<e;)t[n].sphere()},lineStart:function(){for(var n=-1;++n<e;)t[n].lineStart()},lineEnd:function(){for(var n=-1;++n<e;)t[n].lineEnd()},polygonStart:function(){for(var n=-1;++n<e;)t[n].polygonStart()},polygonEnd:function(){for(var n=-1;++n<e;)t[n].polygonEnd()}}}function wl(){var t,e,n=Ei(),r,o=Dn().rotate([154,0]).center([-2,58.5]).parallels([55,65]),i,a=Dn().rotate([157,0]).center([-3,19.9]).parallels([8,18]),f,u,c={point:function(l,p){u=[l,p]}};function s(l){var p=l[0],m=l[1];return u=null,r.point(p,m),u||(i.point(p,m),u)||(f.point(p,m),u)}s.invert=function(l){var p=n.scale(),m=n.translate(),h=(l[0]-m[0])/p,g=(l[1]-m[1])/p;return(g>=.12&&g<.234&&h>=-.425&&h<-.214?o:g>=.166&&g<.234&&h>=-.214&&h<-.115?a:n).invert(l)},s.stream=function(l){return t&&e===l?t:t=Ow([n.stream(e=l),o.stream(l),a.stream(l)])},s.precision=function(l){return arguments.length?(n.precision(l),o.precision(l),a.precision(l),d()):n.precision()},s.scale=function(l){return arguments.length?(n.scale(l),o.scale(l*.35),a.scale(l),s.translate(n.translate())):n.scale()},s.translate=function(l){if(!arguments.length)return n.translate();var p=n.scale(),m=+l[0],h=+l[1];return r=n.translate(l).clipExtent([[m-.455*p,h-.238*p],[m+.455*p,h+.238*p]]).stream(c),i=o.translate([m-.307*p,h+.201*p]).clipExtent([[m-.425*p+U,h+.12*p+U],[m-.214*p-U,h+.234*p-U]]).stream(c),f=a.translate([m-.205*p,h+.212*p]).clipExtent([[m-.214*p+U,h+.166*p+U],[m-.115*p-U,h+.234*p-U]]).stream(c),d()},s.fitExtent=function(l,p){return $i(s,l,p)},s.fitSize=function(l,p){return Wf(s,l,p)},s.fitWidth=function(l,p){return Gf(s,l,p)},s.fitHeight=function(l,p){return Vf(s,l,p)};function d(){return t=e=null,s}return s.scale(1070)}function jf(t){return function(e,n){var r=B(e),o=B(n),i=t(r*o);return i===1/0?[2,0]:[i*o*Y(e),i*Y(n)]}}function Qe(t){return function(e,n){var r=mt(e*e+n*n),o=t(r),i=Y(o),a=B(o);return[qt(e*i,r*a),Mt(r&&n*i/r)]}}var vl=jf(function(t){return mt(2/(1+t))});vl.invert=Qe(function(t){return 2*Mt(t/2)});function Ml(){return zt(vl).scale(124.75).clipAngle(180-.001)}var Sl=jf(function(t){return(t=kf(t))&&t/Y(t)});Sl.invert=Qe(function(t){return t});function Tl(){return zt(Sl).scale(79.4188).clipAngle(180-.001)}function io(t,e){return[t,lr(jr((ft+e)/2))]}io.invert=function(t,e){return[t,2*ve(Ef(e))-ft]};function _l(){return Cl(io).scale(961/Dt)}function Cl(t){var e=zt(t),n=e.center,r=e.scale,o=e.translate,i=e.clipExtent,a=null,f,u,c;e.scale=function(d){return arguments.length?(r(d),s()):r()},e.translate=function(d){return arguments.length?(o(d),s()):o()},e.center=function(d){return arguments.length?(n(d),s()):n()},e.clipExtent=function(d){return arguments.length?(d==null?a=f=u=c=null:(a=+d[0][0],f=+d[0][1],u=+d[1][0],c=+d[1][1]),s()):a==null?null:[[a,f],[u,c]]};function s(){var d=V*r(),l=e(p0(e.rotate()).invert([0,0]));return i(a==null?[[l[0]-d,l[1]-d],[l[0]+d,l[1]+d]]:t===io?[[Math.max(l[0]-d,a),f],[Math.min(l[0]+d,u),c]]:[[a,Math.max(l[1]-d,f)],[u,Math.min(l[1]+d,c)]])}return s()}function Zf(t){return jr((ft+t)/2)}function q0(t,e){var n=B(t),r=t===e?Y(t):lr(n/B(e))/lr(Zf(e)/Zf(t)),o=n*If(Zf(t),r)/r;if(!r)return io;function i(a,f){o>0?f<-ft+U&&(f=-ft+U):f>ft-U&&(f=ft-U);var u=o/If(Zf(f),r);return[u*Y(r*a),o-u*B(r*a)]}return i.invert=function(a,f){var u=o-f,c=Gt(r)*mt(a*a+u*u),s=qt(a,K(u))*Gt(u);return u*r<0&&(s-=V*Gt(a)*Gt(u)),[s/r,2*ve(If(o/c,1/r))-ft]},i}function Al(){return oo(q0).scale(109.5).parallels([30,30])}function ao(t,e){return[t,e]}ao.invert=ao;function $l(){return zt(ao).scale(152.63)}function z0(t,e){var n=B(t),r=t===e?Y(t):(n-B(e))/(e-t),o=n/r+t;if(K(r)<U)return ao;function i(a,f){var u=o-f,c=r*a;return[u*Y(c),o-u*B(c)]}return i.invert=function(a,f){var u=o-f,c=qt(a,K(u))*Gt(u);return u*r<0&&(c-=V*Gt(a)*Gt(u)),[c/r,o-Gt(r)*mt(a*a+u*u)]},i}function El(){return oo(z0).scale(131.154).center([0,13.9389])}var Ii=1.340264,ki=-.081106,Ni=893e-6,Di=.003796,Kf=mt(3)/2,Fw=12;function Il(t,e){var n=Mt(Kf*Y(e)),r=n*n,o=r*r*r;return[t*B(n)/(Kf*(Ii+3*ki*r+o*(7*Ni+9*Di*r))),n*(Ii+ki*r+o*(Ni+Di*r))]}Il.invert=function(t,e){for(var n=e,r=n*n,o=r*r*r,i=0,a,f,u;i<Fw&&(f=n*(Ii+ki*r+o*(Ni+Di*r))-e,u=Ii+3*ki*r+o*(7*Ni+9*Di*r),n-=a=f/u,r=n*n,o=r*r*r,!(K(a)<$f));++i);return[Kf*t*(Ii+3*ki*r+o*(7*Ni+9*Di*r))/B(n),Mt(Y(n)/Kf)]};function kl(){return zt(Il).scale(177.158)}function Nl(t,e){var n=B(e),r=B(t)*n;return[n*Y(t)/r,Y(e)/r]}Nl.invert=Qe(ve);function Dl(){return zt(Nl).scale(144.049).clipAngle(60)}function Rl(t,e){return[B(e)*Y(t),Y(e)]}Rl.invert=Qe(Mt);function Ll(){return zt(Rl).scale(249.5).clipAngle(90+U)}function Pl(t,e){var n=B(e),r=1+B(t)*n;return[n*Y(t)/r,Y(e)/r]}Pl.invert=Qe(function(t){return 2*ve(t)});function Ol(){return zt(Pl).scale(250).clipAngle(142)}function Fl(t,e){return[lr(jr((ft+e)/2)),-t]}Fl.invert=function(t,e){return[-e,2*ve(Ef(t))-ft]};function ql(){var t=Cl(Fl),e=t.center,n=t.rotate;return t.center=function(r){return arguments.length?e([-r[1],r[0]]):(r=e(),[r[1],-r[0]])},t.rotate=function(r){return arguments.length?n([r[0],r[1],r.length>2?r[2]+90:90]):(r=n(),[r[0],r[1],r[2]-90])},n([0,0,90]).scale(159.155)}function _t(t,e){switch(arguments.length){case 0:break;case 1:this.range(t);break;default:this.range(e).domain(t);break}return this}function Ri(t,e){switch(arguments.length){case 0:break;case 1:{typeof t=="function"?this.interpolator(t):this.range(t);break}default:{this.domain(t),typeof e=="function"?this.interpolator(e):this.range(e);break}}return this}var Li=Symbol("implicit");function pr(){var t=new Qt,e=[],n=[],r=Li;function o(i){let a=t.get(i);if(a===void 0){if(r!==Li)return r;t.set(i,a=e.push(i)-1)}return n[a%n.length]}return o.domain=function(i){if(!arguments.length)return e.slice();e=[],t=new Qt;for(let a of i)t.has(a)||t.set(a,e.push(a)-1);return o},o.range=function(i){return arguments.length?(n=Array.from(i),o):n.slice()},o.unknown=function(i){return arguments.length?(r=i,o):r},o.copy=function(){return pr(e,n).unknown(r)},_t.apply(o,arguments),o}function Rn(){var t=pr().unknown(void 0),e=t.domain,n=t.range,r=0,o=1,i,a,f=!1,u=0,c=0,s=.5;delete t.unknown;function d(){var l=e().length,p=o<r,m=p?o:r,h=p?r:o;i=(h-m)/Math.max(1,l-u+c*2),f&&(i=Math.floor(i)),m+=(h-m-i*(l-u))*s,a=i*(1-u),f&&(m=Math.round(m),a=Math.round(a));var g=er(l).map(function(b){return m+i*b});return n(p?g.reverse():g)}return t.domain=function(l){return arguments.length?(e(l),d()):e()},t.range=function(l){return arguments.length?([r,o]=l,r=+r,o=+o,d()):[r,o]},t.rangeRound=function(l){return[r,o]=l,r=+r,o=+o,f=!0,d()},t.bandwidth=function(){return a},t.step=function(){return i},t.round=function(l){return arguments.length?(f=!!l,d()):f},t.padding=function(l){return arguments.length?(u=Math.min(1,c=+l),d()):u},t.paddingInner=function(l){return arguments.length?(u=Math.min(1,l),d()):u},t.paddingOuter=function(l){return arguments.length?(c=+l,d()):c},t.align=function(l){return arguments.length?(s=Math.max(0,Math.min(1,l)),d()):s},t.copy=function(){return Rn(e(),[r,o]).round(f).paddingInner(u).paddingOuter(c).align(s)},_t.apply(d(),arguments)}function Y0(t){var e=t.copy;return t.padding=t.paddingOuter,delete t.paddingInner,delete t.paddingOuter,t.copy=function(){return Y0(e())},t}function zl(){return Y0(Rn.apply(null,arguments).paddingInner(1))}function Yl(t){return function(){return t}}function fo(t){return+t}var B0=[0,1];function oe(t){return t}function Bl(t,e){return(e-=t=+t)?function(n){return(n-t)/e}:Yl(isNaN(e)?NaN:.5)}function qw(t,e){var n;return t>e&&(n=t,t=e,e=n),function(r){return Math.max(t,Math.min(e,r))}}function zw(t,e,n){var r=t[0],o=t[1],i=e[0],a=e[1];return o<r?(r=Bl(o,r),i=n(a,i)):(r=Bl(r,o),i=n(i,a)),function(f){return i(r(f))}}function Yw(t,e,n){var r=Math.min(t.length,e.length)-1,o=new Array(r),i=new Array(r),a=-1;for(t[r]<t[0]&&(t=t.slice().reverse(),e=e.slice().reverse());++a<r;)o[a]=Bl(t[a],t[a+1]),i[a]=n(e[a],e[a+1]);return function(f){var u=un(t,f,1,r)-1;return i[u](o[u](f))}}function Je(t,e){return e.domain(t.domain()).range(t.range()).interpolate(t.interpolate()).clamp(t.clamp()).unknown(t.unknown())}function mr(){var t=B0,e=B0,n=Ie,r,o,i,a=oe,f,u,c;function s(){var l=Math.min(t.length,e.length);return a!==oe&&(a=qw(t[0],t[l-1])),f=l>2?Yw:zw,u=c=null,d}function d(l){return l==null||isNaN(l=+l)?i:(u||(u=f(t.map(r),e,n)))(r(a(l)))}return d.invert=function(l){return a(o((c||(c=f(e,t.map(r),ht)))(l)))},d.domain=function(l){return arguments.length?(t=Array.from(l,fo),s()):t.slice()},d.range=function(l){return arguments.length?(e=Array.from(l),s()):e.slice()},d.rangeRound=function(l){return e=Array.from(l),n=fr,s()},d.clamp=function(l){return arguments.length?(a=l?!0:oe,s()):a!==oe},d.interpolate=function(l){return arguments.length?(n=l,s()):n},d.unknown=function(l){return arguments.length?(i=l,d):i},function(l,p){return r=l,o=p,s()}}function Pi(){return mr()(oe,oe)}function Ul(t,e,n,r){var o=Or(t,e,n),i;switch(r=Ge(r??",f"),r.type){case"s":{var a=Math.max(Math.abs(t),Math.abs(e));return r.precision==null&&!isNaN(i=js(o,a))&&(r.precision=i),Af(r,a)}case"":case"e":case"g":case"p":case"r":{r.precision==null&&!isNaN(i=Zs(o,Math.max(Math.abs(t),Math.abs(e))))&&(r.precision=i-(r.type==="e"));break}case"f":case"%":{r.precision==null&&!isNaN(i=Vs(o))&&(r.precision=i-(r.type==="%")*2);break}}return Ve(r)}function yn(t){var e=t.domain;return t.ticks=function(n){var r=e();return $e(r[0],r[r.length-1],n??10)},t.tickFormat=function(n,r){var o=e();return Ul(o[0],o[o.length-1],n??10,r)},t.nice=function(n){n==null&&(n=10);var r=e(),o=0,i=r.length-1,a=r[o],f=r[i],u,c,s=10;for(f<a&&(c=a,a=f,f=c,c=o,o=i,i=c);s-- >0;){if(c=tr(a,f,n),c===u)return r[o]=a,r[i]=f,e(r);if(c>0)a=Math.floor(a/c)*c,f=Math.ceil(f/c)*c;else if(c<0)a=Math.ceil(a*c)/c,f=Math.floor(f*c)/c;else break;u=c}return t},t}function hr(){var t=Pi();return t.copy=function(){return Je(t,hr())},_t.apply(t,arguments),yn(t)}function Oi(t){var e;function n(r){return r==null||isNaN(r=+r)?e:r}return n.invert=n,n.domain=n.range=function(r){return arguments.length?(t=Array.from(r,fo),n):t.slice()},n.unknown=function(r){return arguments.length?(e=r,n):e},n.copy=function(){return Oi(t).unknown(e)},t=arguments.length?Array.from(t,fo):[0,1],yn(n)}function Fi(t,e){t=t.slice();var n=0,r=t.length-1,o=t[n],i=t[r],a;return i<o&&(a=n,n=r,r=a,a=o,o=i,i=a),t[n]=e.floor(o),t[r]=e.ceil(i),t}function U0(t){return Math.log(t)}function X0(t){return Math.exp(t)}function Bw(t){return-Math.log(-t)}function Uw(t){return-Math.exp(-t)}function Xw(t){return isFinite(t)?+("1e"+t):t<0?0:t}function Hw(t){return t===10?Xw:t===Math.E?Math.exp:e=>Math.pow(t,e)}function Ww(t){return t===Math.E?Math.log:t===10&&Math.log10||t===2&&Math.log2||(t=Math.log(t),e=>Math.log(e)/t)}function H0(t){return(e,n)=>-t(-e,n)}function Xl(t){let e=t(U0,X0),n=e.domain,r=10,o,i;function a(){return o=Ww(r),i=Hw(r),n()[0]<0?(o=H0(o),i=H0(i),t(Bw,Uw)):t(U0,X0),e}return e.base=function(f){return arguments.length?(r=+f,a()):r},e.domain=function(f){return arguments.length?(n(f),a()):n()},e.ticks=f=>{let u=n(),c=u[0],s=u[u.length-1],d=s<c;d&&([c,s]=[s,c]);let l=o(c),p=o(s),m,h,g=f==null?10:+f,b=[];if(!(r%1)&&p-l<g){if(l=Math.floor(l),p=Math.ceil(p),c>0){for(;l<=p;++l)for(m=1;m<r;++m)if(h=l<0?m/i(-l):m*i(l),!(h<c)){if(h>s)break;b.push(h)}}else for(;l<=p;++l)for(m=r-1;m>=1;--m)if(h=l>0?m/i(-l):m*i(l),!(h<c)){if(h>s)break;b.push(h)}b.length*2<g&&(b=$e(c,s,g))}else b=$e(l,p,Math.min(p-l,g)).map(i);return d?b.reverse():b},e.tickFormat=(f,u)=>{if(f==null&&(f=10),u==null&&(u=r===10?"s":","),typeof u!="function"&&(!(r%1)&&(u=Ge(u)).precision==null&&(u.trim=!0),u=Ve(u)),f===1/0)return u;let c=Math.max(1,r*f/e.ticks().length);return s=>{let d=s/i(Math.round(o(s)));return d*r<r-.5&&(d*=r),d<=c?u(s):""}},e.nice=()=>n(Fi(n(),{floor:f=>i(Math.floor(o(f))),ceil:f=>i(Math.ceil(o(f)))})),e}function qi(){let t=Xl(mr()).domain([1,10]);return t.copy=()=>Je(t,qi()).base(t.base()),_t.apply(t,arguments),t}function W0(t){return function(e){return Math.sign(e)*Math.log1p(Math.abs(e/t))}}function G0(t){return function(e){return Math.sign(e)*Math.expm1(Math.abs(e))*t}}function Hl(t){var e=1,n=t(W0(e),G0(e));return n.constant=function(r){return arguments.length?t(W0(e=+r),G0(e)):e},yn(n)}function zi(){var t=Hl(mr());return t.copy=function(){return Je(t,zi()).constant(t.constant())},_t.apply(t,arguments)}function V0(t){return function(e){return e<0?-Math.pow(-e,t):Math.pow(e,t)}}function Gw(t){return t<0?-Math.sqrt(-t):Math.sqrt(t)}function Vw(t){return t<0?-t*t:t*t}function Wl(t){var e=t(oe,oe),n=1;function r(){return n===1?t(oe,oe):n===.5?t(Gw,Vw):t(V0(n),V0(1/n))}return e.exponent=function(o){return arguments.length?(n=+o,r()):n},yn(e)}function Yi(){var t=Wl(mr());return t.copy=function(){return Je(t,Yi()).exponent(t.exponent())},_t.apply(t,arguments),t}function Bi(){var t=[],e=[],n=[],r;function o(){var a=0,f=Math.max(1,e.length);for(n=new Array(f-1);++a<f;)n[a-1]=ps(t,a/f);return i}function i(a){return a==null||isNaN(a=+a)?r:e[un(n,a)]}return i.invertExtent=function(a){var f=e.indexOf(a);return f<0?[NaN,NaN]:[f>0?n[f-1]:t[0],f<n.length?n[f]:t[t.length-1]]},i.domain=function(a){if(!arguments.length)return t.slice();t=[];for(let f of a)f!=null&&!isNaN(f=+f)&&t.push(f);return t.sort(bt),o()},i.range=function(a){return arguments.length?(e=Array.from(a),o()):e.slice()},i.unknown=function(a){return arguments.length?(r=a,i):r},i.quantiles=function(){return n.slice()},i.copy=function(){return Bi().domain(t).range(e).unknown(r)},_t.apply(i,arguments)}function Ui(){var t=[.5],e=[0,1],n,r=1;function o(i){return i!=null&&i<=i?e[un(t,i,0,r)]:n}return o.domain=function(i){return arguments.length?(t=Array.from(i),r=Math.min(t.length,e.length-1),o):t.slice()},o.range=function(i){return arguments.length?(e=Array.from(i),r=Math.min(t.length,e.length-1),o):e.slice()},o.invertExtent=function(i){var a=e.indexOf(i);return[t[a-1],t[a]]},o.unknown=function(i){return arguments.length?(n=i,o):n},o.copy=function(){return Ui().domain(t).range(e).unknown(n)},_t.apply(o,arguments)}var Gl=new Date,Vl=new Date;function dt(t,e,n,r){function o(i){return t(i=arguments.length===0?new Date:new Date(+i)),i}return o.floor=i=>(t(i=new Date(+i)),i),o.ceil=i=>(t(i=new Date(i-1)),e(i,1),t(i),i),o.round=i=>{let a=o(i),f=o.ceil(i);return i-a<f-i?a:f},o.offset=(i,a)=>(e(i=new Date(+i),a==null?1:Math.floor(a)),i),o.range=(i,a,f)=>{let u=[];if(i=o.ceil(i),f=f==null?1:Math.floor(f),!(i<a)||!(f>0))return u;let c;do u.push(c=new Date(+i)),e(i,f),t(i);while(c<i&&i<a);return u},o.filter=i=>dt(a=>{if(a>=a)for(;t(a),!i(a);)a.setTime(a-1)},(a,f)=>{if(a>=a)if(f<0)for(;++f<=0;)for(;e(a,-1),!i(a););else for(;--f>=0;)for(;e(a,1),!i(a););}),n&&(o.count=(i,a)=>(Gl.setTime(+i),Vl.setTime(+a),t(Gl),t(Vl),Math.floor(n(Gl,Vl))),o.every=i=>(i=Math.floor(i),!isFinite(i)||!(i>0)?null:i>1?o.filter(r?a=>r(a)%i===0:a=>o.count(0,a)%i===0):o)),o}var Xi=dt(()=>{},(t,e)=>{t.setTime(+t+e)},(t,e)=>e-t);Xi.every=t=>(t=Math.floor(t),!isFinite(t)||!(t>0)?null:t>1?dt(e=>{e.setTime(Math.floor(e/t)*t)},(e,n)=>{e.setTime(+e+n*t)},(e,n)=>(n-e)/t):Xi);var Pk=Xi.range;var Vt=dt(t=>{t.setTime(t-t.getMilliseconds())},(t,e)=>{t.setTime(+t+e*1e3)},(t,e)=>(e-t)/1e3,t=>t.getUTCSeconds()),j0=Vt.range;var Ln=dt(t=>{t.setTime(t-t.getMilliseconds()-t.getSeconds()*1e3)},(t,e)=>{t.setTime(+t+e*6e4)},(t,e)=>(e-t)/6e4,t=>t.getMinutes()),jw=Ln.range,Pn=dt(t=>{t.setUTCSeconds(0,0)},(t,e)=>{t.setTime(+t+e*6e4)},(t,e)=>(e-t)/6e4,t=>t.getUTCMinutes()),Zw=Pn.range;var On=dt(t=>{t.setTime(t-t.getMilliseconds()-t.getSeconds()*1e3-t.getMinutes()*6e4)},(t,e)=>{t.setTime(+t+e*36e5)},(t,e)=>(e-t)/36e5,t=>t.getHours()),Kw=On.range,Fn=dt(t=>{t.setUTCMinutes(0,0,0)},(t,e)=>{t.setTime(+t+e*36e5)},(t,e)=>(e-t)/36e5,t=>t.getUTCHours()),Qw=Fn.range;var De=dt(t=>t.setHours(0,0,0,0),(t,e)=>t.setDate(t.getDate()+e),(t,e)=>(e-t-(e.getTimezoneOffset()-t.getTimezoneOffset())*6e4)/864e5,t=>t.getDate()-1),Jw=De.range,yr=dt(t=>{t.setUTCHours(0,0,0,0)},(t,e)=>{t.setUTCDate(t.getUTCDate()+e)},(t,e)=>(e-t)/864e5,t=>t.getUTCDate()-1),t6=yr.range,uo=dt(t=>{t.setUTCHours(0,0,0,0)},(t,e)=>{t.setUTCDate(t.getUTCDate()+e)},(t,e)=>(e-t)/864e5,t=>Math.floor(t/864e5)),e6=uo.range;function br(t){return dt(e=>{e.setDate(e.getDate()-(e.getDay()+7-t)%7),e.setHours(0,0,0,0)},(e,n)=>{e.setDate(e.getDate()+n*7)},(e,n)=>(n-e-(n.getTimezoneOffset()-e.getTimezoneOffset())*6e4)/6048e5)}var ce=br(0),qn=br(1),Qf=br(2),Jf=br(3),en=br(4),tu=br(5),eu=br(6),K0=ce.range,n6=qn.range,r6=Qf.range,o6=Jf.range,i6=en.range,a6=tu.range,f6=eu.range;function wr(t){return dt(e=>{e.setUTCDate(e.getUTCDate()-(e.getUTCDay()+7-t)%7),e.setUTCHours(0,0,0,0)},(e,n)=>{e.setUTCDate(e.getUTCDate()+n*7)},(e,n)=>(n-e)/6048e5)}var se=wr(0),zn=wr(1),nu=wr(2),ru=wr(3),nn=wr(4),ou=wr(5),iu=wr(6),Q0=se.range,u6=zn.range,c6=nu.range,s6=ru.range,l6=nn.range,d6=ou.range,p6=iu.range;var Yn=dt(t=>{t.setDate(1),t.setHours(0,0,0,0)},(t,e)=>{t.setMonth(t.getMonth()+e)},(t,e)=>e.getMonth()-t.getMonth()+(e.getFullYear()-t.getFullYear())*12,t=>t.getMonth()),m6=Yn.range,Bn=dt(t=>{t.setUTCDate(1),t.setUTCHours(0,0,0,0)},(t,e)=>{t.setUTCMonth(t.getUTCMonth()+e)},(t,e)=>e.getUTCMonth()-t.getUTCMonth()+(e.getUTCFullYear()-t.getUTCFullYear())*12,t=>t.getUTCMonth()),h6=Bn.range;var jt=dt(t=>{t.setMonth(0,1),t.setHours(0,0,0,0)},(t,e)=>{t.setFullYear(t.getFullYear()+e)},(t,e)=>e.getFullYear()-t.getFullYear(),t=>t.getFullYear());jt.every=t=>!isFinite(t=Math.floor(t))||!(t>0)?null:dt(e=>{e.setFullYear(Math.floor(e.getFullYear()/t)*t),e.setMonth(0,1),e.setHours(0,0,0,0)},(e,n)=>{e.setFullYear(e.getFullYear()+n*t)});var g6=jt.range,Zt=dt(t=>{t.setUTCMonth(0,1),t.setUTCHours(0,0,0,0)},(t,e)=>{t.setUTCFullYear(t.getUTCFullYear()+e)},(t,e)=>e.getUTCFullYear()-t.getUTCFullYear(),t=>t.getUTCFullYear());Zt.every=t=>!isFinite(t=Math.floor(t))||!(t>0)?null:dt(e=>{e.setUTCFullYear(Math.floor(e.getUTCFullYear()/t)*t),e.setUTCMonth(0,1),e.setUTCHours(0,0,0,0)},(e,n)=>{e.setUTCFullYear(e.getUTCFullYear()+n*t)});var x6=Zt.range;function tg(t,e,n,r,o,i){let a=[[Vt,1,1e3],[Vt,5,5*1e3],[Vt,15,15*1e3],[Vt,30,30*1e3],[i,1,6e4],[i,5,5*6e4],[i,15,15*6e4],[i,30,30*6e4],[o,1,36e5],[o,3,3*36e5],[o,6,6*36e5],[o,12,12*36e5],[r,1,864e5],[r,2,2*864e5],[n,1,6048e5],[e,1,2592e6],[e,3,3*2592e6],[t,1,31536e6]];function f(c,s,d){let l=s<c;l&&([c,s]=[s,c]);let p=d&&typeof d.range=="function"?d:u(c,s,d),m=p?p.range(c,+s+1):[];return l?m.reverse():m}function u(c,s,d){let l=Math.abs(s-c)/d,p=qe(([,,g])=>g).right(a,l);if(p===a.length)return t.every(Or(c/31536e6,s/31536e6,d));if(p===0)return Xi.every(Math.max(Or(c,s,d),1));let[m,h]=a[l/a[p-1][2]<a[p][2]/l?p-1:p];return m.every(h)}return[f,u]}var[jl,Wi]=tg(Zt,Bn,se,uo,Fn,Pn),[Zl,Kl]=tg(jt,Yn,ce,De,On,Ln);function Ql(t){if(0<=t.y&&t.y<100){var e=new Date(-1,t.m,t.d,t.H,t.M,t.S,t.L);return e.setFullYear(t.y),e}return new Date(t.y,t.m,t.d,t.H,t.M,t.S,t.L)}function Jl(t){if(0<=t.y&&t.y<100){var e=new Date(Date.UTC(-1,t.m,t.d,t.H,t.M,t.S,t.L));return e.setUTCFullYear(t.y),e}return new Date(Date.UTC(t.y,t.m,t.d,t.H,t.M,t.S,t.L))}function Gi(t,e,n){return{y:t,m:e,d:n,H:0,M:0,S:0,L:0}}function td(t){var e=t.dateTime,n=t.date,r=t.time,o=t.periods,i=t.days,a=t.shortDays,f=t.months,u=t.shortMonths,c=Vi(o),s=ji(o),d=Vi(i),l=ji(i),p=Vi(a),m=ji(a),h=Vi(f),g=ji(f),b=Vi(u),x=ji(u),M={a:N,A:P,b:D,B:tt,c:null,d:ag,e:ag,f:Y6,g:K6,G:J6,H:F6,I:q6,j:z6,L:lg,m:B6,M:U6,p:Z,q:W,Q:cg,s:sg,S:X6,u:H6,U:W6,V:G6,w:V6,W:j6,x:null,X:null,y:Z6,Y:Q6,Z:tv,"%":ug},y={a:Q,A:at,b:Ut,B:Fe,c:null,d:fg,e:fg,f:ov,g:mv,G:gv,H:ev,I:nv,j:rv,L:pg,m:iv,M:av,p:G,q:X,Q:cg,s:sg,S:fv,u:uv,U:cv,V:sv,w:lv,W:dv,x:null,X:null,y:pv,Y:hv,Z:xv,"%":ug},w={a:F,A:_,b:R,B:O,c:L,d:og,e:og,f:R6,g:rg,G:ng,H:ig,I:ig,j:I6,L:D6,m:E6,M:k6,p:I,q:$6,Q:P6,s:O6,S:N6,u:S6,U:T6,V:_6,w:M6,W:C6,x:S,X:A,y:rg,Y:ng,Z:A6,"%":L6};M.x=v(n,M),M.X=v(r,M),M.c=v(e,M),y.x=v(n,y),y.X=v(r,y),y.c=v(e,y);function v($,q){return function(z){var C=[],j=-1,J=0,ot=$.length,lt,Xt,Zn;for(z instanceof Date||(z=new Date(+z));++j<ot;)$.charCodeAt(j)===37&&(C.push($.slice(J,j)),(Xt=eg[lt=$.charAt(++j)])!=null?lt=$.charAt(++j):Xt=lt==="e"?" ":"0",(Zn=q[lt])&&(lt=Zn(z,Xt)),C.push(lt),J=j+1);return C.push($.slice(J,j)),C.join("")}}function T($,q){return function(z){var C=Gi(1900,void 0,1),j=E(C,$,z+="",0),J,ot;if(j!=z.length)return null;if("Q"in C)return new Date(C.Q);if("s"in C)return new Date(C.s*1e3+("L"in C?C.L:0));if(q&&!("Z"in C)&&(C.Z=0),"p"in C&&(C.H=C.H%12+C.p*12),C.m===void 0&&(C.m="q"in C?C.q:0),"V"in C){if(C.V<1||C.V>53)return null;"w"in C||(C.w=1),"Z"in C?(J=Jl(Gi(C.y,0,1)),ot=J.getUTCDay(),J=ot>4||ot===0?zn.ceil(J):zn(J),J=yr.offset(J,(C.V-1)*7),C.y=J.getUTCFullYear(),C.m=J.getUTCMonth(),C.d=J.getUTCDate()+(C.w+6)%7):(J=Ql(Gi(C.y,0,1)),ot=J.getDay(),J=ot>4||ot===0?qn.ceil(J):qn(J),J=De.offset(J,(C.V-1)*7),C.y=J.getFullYear(),C.m=J.getMonth(),C.d=J.getDate()+(C.w+6)%7)}else("W"in C||"U"in C)&&("w"in C||(C.w="u"in C?C.u%7:"W"in C?1:0),ot="Z"in C?Jl(Gi(C.y,0,1)).getUTCDay():Ql(Gi(C.y,0,1)).getDay(),C.m=0,C.d="W"in C?(C.w+6)%7+C.W*7-(ot+5)%7:C.w+C.U*7-(ot+6)%7);return"Z"in C?(C.H+=C.Z/100|0,C.M+=C.Z%100,Jl(C)):Ql(C)}}function E($,q,z,C){for(var j=0,J=q.length,ot=z.length,lt,Xt;j<J;){if(C>=ot)return-1;if(lt=q.charCodeAt(j++),lt===37){if(lt=q.charAt(j++),Xt=w[lt in eg?q.charAt(j++):lt],!Xt||(C=Xt($,z,C))<0)return-1}else if(lt!=z.charCodeAt(C++))return-1}return C}function I($,q,z){var C=c.exec(q.slice(z));return C?($.p=s.get(C[0].toLowerCase()),z+C[0].length):-1}function F($,q,z){var C=p.exec(q.slice(z));return C?($.w=m.get(C[0].toLowerCase()),z+C[0].length):-1}function _($,q,z){var C=d.exec(q.slice(z));return C?($.w=l.get(C[0].toLowerCase()),z+C[0].length):-1}function R($,q,z){var C=b.exec(q.slice(z));return C?($.m=x.get(C[0].toLowerCase()),z+C[0].length):-1}function O($,q,z){var C=h.exec(q.slice(z));return C?($.m=g.get(C[0].toLowerCase()),z+C[0].length):-1}function L($,q,z){return E($,e,q,z)}function S($,q,z){return E($,n,q,z)}function A($,q,z){return E($,r,q,z)}function N($){return a[$.getDay()]}function P($){return i[$.getDay()]}function D($){return u[$.getMonth()]}function tt($){return f[$.getMonth()]}function Z($){return o[+($.getHours()>=12)]}function W($){return 1+~~($.getMonth()/3)}function Q($){return a[$.getUTCDay()]}function at($){return i[$.getUTCDay()]}function Ut($){return u[$.getUTCMonth()]}function Fe($){return f[$.getUTCMonth()]}function G($){return o[+($.getUTCHours()>=12)]}function X($){return 1+~~($.getUTCMonth()/3)}return{format:function($){var q=v($+="",M);return q.toString=function(){return $},q},parse:function($){var q=T($+="",!1);return q.toString=function(){return $},q},utcFormat:function($){var q=v($+="",y);return q.toString=function(){return $},q},utcParse:function($){var q=T($+="",!0);return q.toString=function(){return $},q}}}var eg={"-":"",_:" ",0:"0"},Lt=/^\s*\d+/,b6=/^%/,w6=/[\\^$*+?|[\]().{}]/g;function nt(t,e,n){var r=t<0?"-":"",o=(r?-t:t)+"",i=o.length;return r+(i<n?new Array(n-i+1).join(e)+o:o)}function v6(t){return t.replace(w6,"\\$&")}function Vi(t){return new RegExp("^(?:"+t.map(v6).join("|")+")","i")}function ji(t){return new Map(t.map((e,n)=>[e.toLowerCase(),n]))}function M6(t,e,n){var r=Lt.exec(e.slice(n,n+1));return r?(t.w=+r[0],n+r[0].length):-1}function S6(t,e,n){var r=Lt.exec(e.slice(n,n+1));return r?(t.u=+r[0],n+r[0].length):-1}function T6(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.U=+r[0],n+r[0].length):-1}function _6(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.V=+r[0],n+r[0].length):-1}function C6(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.W=+r[0],n+r[0].length):-1}function ng(t,e,n){var r=Lt.exec(e.slice(n,n+4));return r?(t.y=+r[0],n+r[0].length):-1}function rg(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.y=+r[0]+(+r[0]>68?1900:2e3),n+r[0].length):-1}function A6(t,e,n){var r=/^(Z)|([+-]\d\d)(?::?(\d\d))?/.exec(e.slice(n,n+6));return r?(t.Z=r[1]?0:-(r[2]+(r[3]||"00")),n+r[0].length):-1}function $6(t,e,n){var r=Lt.exec(e.slice(n,n+1));return r?(t.q=r[0]*3-3,n+r[0].length):-1}function E6(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.m=r[0]-1,n+r[0].length):-1}function og(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.d=+r[0],n+r[0].length):-1}function I6(t,e,n){var r=Lt.exec(e.slice(n,n+3));return r?(t.m=0,t.d=+r[0],n+r[0].length):-1}function ig(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.H=+r[0],n+r[0].length):-1}function k6(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.M=+r[0],n+r[0].length):-1}function N6(t,e,n){var r=Lt.exec(e.slice(n,n+2));return r?(t.S=+r[0],n+r[0].length):-1}function D6(t,e,n){var r=Lt.exec(e.slice(n,n+3));return r?(t.L=+r[0],n+r[0].length):-1}function R6(t,e,n){var r=Lt.exec(e.slice(n,n+6));return r?(t.L=Math.floor(r[0]/1e3),n+r[0].length):-1}function L6(t,e,n){var r=b6.exec(e.slice(n,n+1));return r?n+r[0].length:-1}function P6(t,e,n){var r=Lt.exec(e.slice(n));return r?(t.Q=+r[0],n+r[0].length):-1}function O6(t,e,n){var r=Lt.exec(e.slice(n));return r?(t.s=+r[0],n+r[0].length):-1}function ag(t,e){return nt(t.getDate(),e,2)}function F6(t,e){return nt(t.getHours(),e,2)}function q6(t,e){return nt(t.getHours()%12||12,e,2)}function z6(t,e){return nt(1+De.count(jt(t),t),e,3)}function lg(t,e){return nt(t.getMilliseconds(),e,3)}function Y6(t,e){return lg(t,e)+"000"}function B6(t,e){return nt(t.getMonth()+1,e,2)}function U6(t,e){return nt(t.getMinutes(),e,2)}function X6(t,e){return nt(t.getSeconds(),e,2)}function H6(t){var e=t.getDay();return e===0?7:e}function W6(t,e){return nt(ce.count(jt(t)-1,t),e,2)}function dg(t){var e=t.getDay();return e>=4||e===0?en(t):en.ceil(t)}function G6(t,e){return t=dg(t),nt(en.count(jt(t),t)+(jt(t).getDay()===4),e,2)}function V6(t){return t.getDay()}function j6(t,e){return nt(qn.count(jt(t)-1,t),e,2)}function Z6(t,e){return nt(t.getFullYear()%100,e,2)}function K6(t,e){return t=dg(t),nt(t.getFullYear()%100,e,2)}function Q6(t,e){return nt(t.getFullYear()%1e4,e,4)}function J6(t,e){var n=t.getDay();return t=n>=4||n===0?en(t):en.ceil(t),nt(t.getFullYear()%1e4,e,4)}function tv(t){var e=t.getTimezoneOffset();return(e>0?"-":(e*=-1,"+"))+nt(e/60|0,"0",2)+nt(e%60,"0",2)}function fg(t,e){return nt(t.getUTCDate(),e,2)}function ev(t,e){return nt(t.getUTCHours(),e,2)}function nv(t,e){return nt(t.getUTCHours()%12||12,e,2)}function rv(t,e){return nt(1+yr.count(Zt(t),t),e,3)}function pg(t,e){return nt(t.getUTCMilliseconds(),e,3)}function ov(t,e){return pg(t,e)+"000"}function iv(t,e){return nt(t.getUTCMonth()+1,e,2)}function av(t,e){return nt(t.getUTCMinutes(),e,2)}function fv(t,e){return nt(t.getUTCSeconds(),e,2)}function uv(t){var e=t.getUTCDay();return e===0?7:e}function cv(t,e){return nt(se.count(Zt(t)-1,t),e,2)}function mg(t){var e=t.getUTCDay();return e>=4||e===0?nn(t):nn.ceil(t)}function sv(t,e){return t=mg(t),nt(nn.count(Zt(t),t)+(Zt(t).getUTCDay()===4),e,2)}function lv(t){return t.getUTCDay()}function dv(t,e){return nt(zn.count(Zt(t)-1,t),e,2)}function pv(t,e){return nt(t.getUTCFullYear()%100,e,2)}function mv(t,e){return t=mg(t),nt(t.getUTCFullYear()%100,e,2)}function hv(t,e){return nt(t.getUTCFullYear()%1e4,e,4)}function gv(t,e){var n=t.getUTCDay();return t=n>=4||n===0?nn(t):nn.ceil(t),nt(t.getUTCFullYear()%1e4,e,4)}function xv(){return"+0000"}function ug(){return"%"}function cg(t){return+t}function sg(t){return Math.floor(+t/1e3)}var co,Un,hg,bn,gg;ed({dateTime:"%x, %X",date:"%-m/%-d/%Y",time:"%-I:%M:%S %p",periods:["AM","PM"],days:["Sunday","Monday","Tuesday","Wednesday","Thursday","Friday","Saturday"],shortDays:["Sun","Mon","Tue","Wed","Thu","Fri","Sat"],months:["January","February","March","April","May","June","July","August","September","October","November","December"],shortMonths:["Jan","Feb","Mar","Apr","May","Jun","Jul","Aug","Sep","Oct","Nov","Dec"]});function ed(t){return co=td(t),Un=co.format,hg=co.parse,bn=co.utcFormat,gg=co.utcParse,co}function yv(t){return new Date(t)}function bv(t){return t instanceof Date?+t:+new Date(+t)}function au(t,e,n,r,o,i,a,f,u,c){var s=Pi(),d=s.invert,l=s.domain,p=c(".%L"),m=c(":%S"),h=c("%I:%M"),g=c("%I %p"),b=c("%a %d"),x=c("%b %d"),M=c("%B"),y=c("%Y");function w(v){return(u(v)<v?p:f(v)<v?m:a(v)<v?h:i(v)<v?g:r(v)<v?o(v)<v?b:x:n(v)<v?M:y)(v)}return s.invert=function(v){return new Date(d(v))},s.domain=function(v){return arguments.length?l(Array.from(v,bv)):l().map(yv)},s.ticks=function(v){var T=l();return t(T[0],T[T.length-1],v??10)},s.tickFormat=function(v,T){return T==null?w:c(T)},s.nice=function(v){var T=l();return(!v||typeof v.range!="function")&&(v=e(T[0],T[T.length-1],v??10)),v?l(Fi(T,v)):s},s.copy=function(){return Je(s,au(t,e,n,r,o,i,a,f,u,c))},s}function fu(){return _t.apply(au(Zl,Kl,jt,Yn,ce,De,On,Ln,Vt,Un).domain([new Date(2e3,0,1),new Date(2e3,0,2)]),arguments)}function uu(){return _t.apply(au(jl,Wi,Zt,Bn,se,yr,Fn,Pn,Vt,bn).domain([Date.UTC(2e3,0,1),Date.UTC(2e3,0,2)]),arguments)}function Zi(t,e){return e.domain(t.domain()).interpolator(t.interpolator()).clamp(t.clamp()).unknown(t.unknown())}function cu(){var t=0,e=.5,n=1,r=1,o,i,a,f,u,c=oe,s,d=!1,l;function p(h){return isNaN(h=+h)?l:(h=.5+((h=+s(h))-i)*(r*h<r*i?f:u),c(d?Math.max(0,Math.min(1,h)):h))}p.domain=function(h){return arguments.length?([t,e,n]=h,o=s(t=+t),i=s(e=+e),a=s(n=+n),f=o===i?0:.5/(i-o),u=i===a?0:.5/(a-i),r=i<o?-1:1,p):[t,e,n]},p.clamp=function(h){return arguments.length?(d=!!h,p):d},p.interpolator=function(h){return arguments.length?(c=h,p):c};function m(h){return function(g){var b,x,M;return arguments.length?([b,x,M]=g,c=He(h,[b,x,M]),p):[c(0),c(.5),c(1)]}}return p.range=m(Ie),p.rangeRound=m(fr),p.unknown=function(h){return arguments.length?(l=h,p):l},function(h){return s=h,o=h(t),i=h(e),a=h(n),f=o===i?0:.5/(i-o),u=i===a?0:.5/(a-i),r=i<o?-1:1,p}}function Ki(){var t=yn(cu()(oe));return t.copy=function(){return Zi(t,Ki())},Ri.apply(t,arguments)}function su(){var t=Xl(cu()).domain([.1,1,10]);return t.copy=function(){return Zi(t,su()).base(t.base())},Ri.apply(t,arguments)}function lu(){var t=Hl(cu());return t.copy=function(){return Zi(t,lu()).constant(t.constant())},Ri.apply(t,arguments)}function du(){var t=Wl(cu());return t.copy=function(){return Zi(t,du()).exponent(t.exponent())},Ri.apply(t,arguments)}function k(t){for(var e=t.length/6|0,n=new Array(e),r=0;r<e;)n[r]="#"+t.slice(r*6,++r*6);return n}var nd=k("1f77b4ff7f0e2ca02cd627289467bd8c564be377c27f7f7fbcbd2217becf");var rd=k("7fc97fbeaed4fdc086ffff99386cb0f0027fbf5b17666666");var od=k("1b9e77d95f027570b3e7298a66a61ee6ab02a6761d666666");var id=k("a6cee31f78b4b2df8a33a02cfb9a99e31a1cfdbf6fff7f00cab2d66a3d9affff99b15928");var ad=k("fbb4aeb3cde3ccebc5decbe4fed9a6ffffcce5d8bdfddaecf2f2f2");var fd=k("b3e2cdfdcdaccbd5e8f4cae4e6f5c9fff2aef1e2cccccccc");var ud=k("e41a1c377eb84daf4a984ea3ff7f00ffff33a65628f781bf999999");var cd=k("66c2a5fc8d628da0cbe78ac3a6d854ffd92fe5c494b3b3b3");var sd=k("8dd3c7ffffb3bebadafb807280b1d3fdb462b3de69fccde5d9d9d9bc80bdccebc5ffed6f");var ld=k("4e79a7f28e2ce1575976b7b259a14fedc949af7aa1ff9da79c755fbab0ab");var H=t=>ks(t[t.length-1]);var pu=new Array(3).concat("d8b365f5f5f55ab4ac","a6611adfc27d80cdc1018571","a6611adfc27df5f5f580cdc1018571","8c510ad8b365f6e8c3c7eae55ab4ac01665e","8c510ad8b365f6e8c3f5f5f5c7eae55ab4ac01665e","8c510abf812ddfc27df6e8c3c7eae580cdc135978f01665e","8c510abf812ddfc27df6e8c3f5f5f5c7eae580cdc135978f01665e","5430058c510abf812ddfc27df6e8c3c7eae580cdc135978f01665e003c30","5430058c510abf812ddfc27df6e8c3f5f5f5c7eae580cdc135978f01665e003c30").map(k),mu=H(pu);var hu=new Array(3).concat("af8dc3f7f7f77fbf7b","7b3294c2a5cfa6dba0008837","7b3294c2a5cff7f7f7a6dba0008837","762a83af8dc3e7d4e8d9f0d37fbf7b1b7837","762a83af8dc3e7d4e8f7f7f7d9f0d37fbf7b1b7837","762a839970abc2a5cfe7d4e8d9f0d3a6dba05aae611b7837","762a839970abc2a5cfe7d4e8f7f7f7d9f0d3a6dba05aae611b7837","40004b762a839970abc2a5cfe7d4e8d9f0d3a6dba05aae611b783700441b","40004b762a839970abc2a5cfe7d4e8f7f7f7d9f0d3a6dba05aae611b783700441b").map(k),gu=H(hu);var xu=new Array(3).concat("e9a3c9f7f7f7a1d76a","d01c8bf1b6dab8e1864dac26","d01c8bf1b6daf7f7f7b8e1864dac26","c51b7de9a3c9fde0efe6f5d0a1d76a4d9221","c51b7de9a3c9fde0eff7f7f7e6f5d0a1d76a4d9221","c51b7dde77aef1b6dafde0efe6f5d0b8e1867fbc414d9221","c51b7dde77aef1b6dafde0eff7f7f7e6f5d0b8e1867fbc414d9221","8e0152c51b7dde77aef1b6dafde0efe6f5d0b8e1867fbc414d9221276419","8e0152c51b7dde77aef1b6dafde0eff7f7f7e6f5d0b8e1867fbc414d9221276419").map(k),yu=H(xu);var bu=new Array(3).concat("998ec3f7f7f7f1a340","5e3c99b2abd2fdb863e66101","5e3c99b2abd2f7f7f7fdb863e66101","542788998ec3d8daebfee0b6f1a340b35806","542788998ec3d8daebf7f7f7fee0b6f1a340b35806","5427888073acb2abd2d8daebfee0b6fdb863e08214b35806","5427888073acb2abd2d8daebf7f7f7fee0b6fdb863e08214b35806","2d004b5427888073acb2abd2d8daebfee0b6fdb863e08214b358067f3b08","2d004b5427888073acb2abd2d8daebf7f7f7fee0b6fdb863e08214b358067f3b08").map(k),wu=H(bu);var Qi=new Array(3).concat("ef8a62f7f7f767a9cf","ca0020f4a58292c5de0571b0","ca0020f4a582f7f7f792c5de0571b0","b2182bef8a62fddbc7d1e5f067a9cf2166ac","b2182bef8a62fddbc7f7f7f7d1e5f067a9cf2166ac","b2182bd6604df4a582fddbc7d1e5f092c5de4393c32166ac","b2182bd6604df4a582fddbc7f7f7f7d1e5f092c5de4393c32166ac","67001fb2182bd6604df4a582fddbc7d1e5f092c5de4393c32166ac053061","67001fb2182bd6604df4a582fddbc7f7f7f7d1e5f092c5de4393c32166ac053061").map(k),so=H(Qi);var vu=new Array(3).concat("ef8a62ffffff999999","ca0020f4a582bababa404040","ca0020f4a582ffffffbababa404040","b2182bef8a62fddbc7e0e0e09999994d4d4d","b2182bef8a62fddbc7ffffffe0e0e09999994d4d4d","b2182bd6604df4a582fddbc7e0e0e0bababa8787874d4d4d","b2182bd6604df4a582fddbc7ffffffe0e0e0bababa8787874d4d4d","67001fb2182bd6604df4a582fddbc7e0e0e0bababa8787874d4d4d1a1a1a","67001fb2182bd6604df4a582fddbc7ffffffe0e0e0bababa8787874d4d4d1a1a1a").map(k),Mu=H(vu);var Ji=new Array(3).concat("fc8d59ffffbf91bfdb","d7191cfdae61abd9e92c7bb6","d7191cfdae61ffffbfabd9e92c7bb6","d73027fc8d59fee090e0f3f891bfdb4575b4","d73027fc8d59fee090ffffbfe0f3f891bfdb4575b4","d73027f46d43fdae61fee090e0f3f8abd9e974add14575b4","d73027f46d43fdae61fee090ffffbfe0f3f8abd9e974add14575b4","a50026d73027f46d43fdae61fee090e0f3f8abd9e974add14575b4313695","a50026d73027f46d43fdae61fee090ffffbfe0f3f8abd9e974add14575b4313695").map(k),lo=H(Ji);var Su=new Array(3).concat("fc8d59ffffbf91cf60","d7191cfdae61a6d96a1a9641","d7191cfdae61ffffbfa6d96a1a9641","d73027fc8d59fee08bd9ef8b91cf601a9850","d73027fc8d59fee08bffffbfd9ef8b91cf601a9850","d73027f46d43fdae61fee08bd9ef8ba6d96a66bd631a9850","d73027f46d43fdae61fee08bffffbfd9ef8ba6d96a66bd631a9850","a50026d73027f46d43fdae61fee08bd9ef8ba6d96a66bd631a9850006837","a50026d73027f46d43fdae61fee08bffffbfd9ef8ba6d96a66bd631a9850006837").map(k),Tu=H(Su);var _u=new Array(3).concat("fc8d59ffffbf99d594","d7191cfdae61abdda42b83ba","d7191cfdae61ffffbfabdda42b83ba","d53e4ffc8d59fee08be6f59899d5943288bd","d53e4ffc8d59fee08bffffbfe6f59899d5943288bd","d53e4ff46d43fdae61fee08be6f598abdda466c2a53288bd","d53e4ff46d43fdae61fee08bffffbfe6f598abdda466c2a53288bd","9e0142d53e4ff46d43fdae61fee08be6f598abdda466c2a53288bd5e4fa2","9e0142d53e4ff46d43fdae61fee08bffffbfe6f598abdda466c2a53288bd5e4fa2").map(k),Cu=H(_u);var Au=new Array(3).concat("e5f5f999d8c92ca25f","edf8fbb2e2e266c2a4238b45","edf8fbb2e2e266c2a42ca25f006d2c","edf8fbccece699d8c966c2a42ca25f006d2c","edf8fbccece699d8c966c2a441ae76238b45005824","f7fcfde5f5f9ccece699d8c966c2a441ae76238b45005824","f7fcfde5f5f9ccece699d8c966c2a441ae76238b45006d2c00441b").map(k),$u=H(Au);var Eu=new Array(3).concat("e0ecf49ebcda8856a7","edf8fbb3cde38c96c688419d","edf8fbb3cde38c96c68856a7810f7c","edf8fbbfd3e69ebcda8c96c68856a7810f7c","edf8fbbfd3e69ebcda8c96c68c6bb188419d6e016b","f7fcfde0ecf4bfd3e69ebcda8c96c68c6bb188419d6e016b","f7fcfde0ecf4bfd3e69ebcda8c96c68c6bb188419d810f7c4d004b").map(k),Iu=H(Eu);var ku=new Array(3).concat("e0f3dba8ddb543a2ca","f0f9e8bae4bc7bccc42b8cbe","f0f9e8bae4bc7bccc443a2ca0868ac","f0f9e8ccebc5a8ddb57bccc443a2ca0868ac","f0f9e8ccebc5a8ddb57bccc44eb3d32b8cbe08589e","f7fcf0e0f3dbccebc5a8ddb57bccc44eb3d32b8cbe08589e","f7fcf0e0f3dbccebc5a8ddb57bccc44eb3d32b8cbe0868ac084081").map(k),Nu=H(ku);var Du=new Array(3).concat("fee8c8fdbb84e34a33","fef0d9fdcc8afc8d59d7301f","fef0d9fdcc8afc8d59e34a33b30000","fef0d9fdd49efdbb84fc8d59e34a33b30000","fef0d9fdd49efdbb84fc8d59ef6548d7301f990000","fff7ecfee8c8fdd49efdbb84fc8d59ef6548d7301f990000","fff7ecfee8c8fdd49efdbb84fc8d59ef6548d7301fb300007f0000").map(k),Ru=H(Du);var Lu=new Array(3).concat("ece2f0a6bddb1c9099","f6eff7bdc9e167a9cf02818a","f6eff7bdc9e167a9cf1c9099016c59","f6eff7d0d1e6a6bddb67a9cf1c9099016c59","f6eff7d0d1e6a6bddb67a9cf3690c002818a016450","fff7fbece2f0d0d1e6a6bddb67a9cf3690c002818a016450","fff7fbece2f0d0d1e6a6bddb67a9cf3690c002818a016c59014636").map(k),Pu=H(Lu);var Ou=new Array(3).concat("ece7f2a6bddb2b8cbe","f1eef6bdc9e174a9cf0570b0","f1eef6bdc9e174a9cf2b8cbe045a8d","f1eef6d0d1e6a6bddb74a9cf2b8cbe045a8d","f1eef6d0d1e6a6bddb74a9cf3690c00570b0034e7b","fff7fbece7f2d0d1e6a6bddb74a9cf3690c00570b0034e7b","fff7fbece7f2d0d1e6a6bddb74a9cf3690c00570b0045a8d023858").map(k),Fu=H(Ou);var qu=new Array(3).concat("e7e1efc994c7dd1c77","f1eef6d7b5d8df65b0ce1256","f1eef6d7b5d8df65b0dd1c77980043","f1eef6d4b9dac994c7df65b0dd1c77980043","f1eef6d4b9dac994c7df65b0e7298ace125691003f","f7f4f9e7e1efd4b9dac994c7df65b0e7298ace125691003f","f7f4f9e7e1efd4b9dac994c7df65b0e7298ace125698004367001f").map(k),zu=H(qu);var Yu=new Array(3).concat("fde0ddfa9fb5c51b8a","feebe2fbb4b9f768a1ae017e","feebe2fbb4b9f768a1c51b8a7a0177","feebe2fcc5c0fa9fb5f768a1c51b8a7a0177","feebe2fcc5c0fa9fb5f768a1dd3497ae017e7a0177","fff7f3fde0ddfcc5c0fa9fb5f768a1dd3497ae017e7a0177","fff7f3fde0ddfcc5c0fa9fb5f768a1dd3497ae017e7a017749006a").map(k),Bu=H(Yu);var Uu=new Array(3).concat("edf8b17fcdbb2c7fb8","ffffcca1dab441b6c4225ea8","ffffcca1dab441b6c42c7fb8253494","ffffccc7e9b47fcdbb41b6c42c7fb8253494","ffffccc7e9b47fcdbb41b6c41d91c0225ea80c2c84","ffffd9edf8b1c7e9b47fcdbb41b6c41d91c0225ea80c2c84","ffffd9edf8b1c7e9b47fcdbb41b6c41d91c0225ea8253494081d58").map(k),Xu=H(Uu);var Hu=new Array(3).concat("f7fcb9addd8e31a354","ffffccc2e69978c679238443","ffffccc2e69978c67931a354006837","ffffccd9f0a3addd8e78c67931a354006837","ffffccd9f0a3addd8e78c67941ab5d238443005a32","ffffe5f7fcb9d9f0a3addd8e78c67941ab5d238443005a32","ffffe5f7fcb9d9f0a3addd8e78c67941ab5d238443006837004529").map(k),Wu=H(Hu);var Gu=new Array(3).concat("fff7bcfec44fd95f0e","ffffd4fed98efe9929cc4c02","ffffd4fed98efe9929d95f0e993404","ffffd4fee391fec44ffe9929d95f0e993404","ffffd4fee391fec44ffe9929ec7014cc4c028c2d04","ffffe5fff7bcfee391fec44ffe9929ec7014cc4c028c2d04","ffffe5fff7bcfee391fec44ffe9929ec7014cc4c02993404662506").map(k),Vu=H(Gu);var ju=new Array(3).concat("ffeda0feb24cf03b20","ffffb2fecc5cfd8d3ce31a1c","ffffb2fecc5cfd8d3cf03b20bd0026","ffffb2fed976feb24cfd8d3cf03b20bd0026","ffffb2fed976feb24cfd8d3cfc4e2ae31a1cb10026","ffffccffeda0fed976feb24cfd8d3cfc4e2ae31a1cb10026","ffffccffeda0fed976feb24cfd8d3cfc4e2ae31a1cbd0026800026").map(k),Zu=H(ju);var Ku=new Array(3).concat("deebf79ecae13182bd","eff3ffbdd7e76baed62171b5","eff3ffbdd7e76baed63182bd08519c","eff3ffc6dbef9ecae16baed63182bd08519c","eff3ffc6dbef9ecae16baed64292c62171b5084594","f7fbffdeebf7c6dbef9ecae16baed64292c62171b5084594","f7fbffdeebf7c6dbef9ecae16baed64292c62171b508519c08306b").map(k),Qu=H(Ku);var Ju=new Array(3).concat("e5f5e0a1d99b31a354","edf8e9bae4b374c476238b45","edf8e9bae4b374c47631a354006d2c","edf8e9c7e9c0a1d99b74c47631a354006d2c","edf8e9c7e9c0a1d99b74c47641ab5d238b45005a32","f7fcf5e5f5e0c7e9c0a1d99b74c47641ab5d238b45005a32","f7fcf5e5f5e0c7e9c0a1d99b74c47641ab5d238b45006d2c00441b").map(k),tc=H(Ju);var ec=new Array(3).concat("f0f0f0bdbdbd636363","f7f7f7cccccc969696525252","f7f7f7cccccc969696636363252525","f7f7f7d9d9d9bdbdbd969696636363252525","f7f7f7d9d9d9bdbdbd969696737373525252252525","fffffff0f0f0d9d9d9bdbdbd969696737373525252252525","fffffff0f0f0d9d9d9bdbdbd969696737373525252252525000000").map(k),nc=H(ec);var rc=new Array(3).concat("efedf5bcbddc756bb1","f2f0f7cbc9e29e9ac86a51a3","f2f0f7cbc9e29e9ac8756bb154278f","f2f0f7dadaebbcbddc9e9ac8756bb154278f","f2f0f7dadaebbcbddc9e9ac8807dba6a51a34a1486","fcfbfdefedf5dadaebbcbddc9e9ac8807dba6a51a34a1486","fcfbfdefedf5dadaebbcbddc9e9ac8807dba6a51a354278f3f007d").map(k),oc=H(rc);var ic=new Array(3).concat("fee0d2fc9272de2d26","fee5d9fcae91fb6a4acb181d","fee5d9fcae91fb6a4ade2d26a50f15","fee5d9fcbba1fc9272fb6a4ade2d26a50f15","fee5d9fcbba1fc9272fb6a4aef3b2ccb181d99000d","fff5f0fee0d2fcbba1fc9272fb6a4aef3b2ccb181d99000d","fff5f0fee0d2fcbba1fc9272fb6a4aef3b2ccb181da50f1567000d").map(k),ac=H(ic);var fc=new Array(3).concat("fee6cefdae6be6550d","feeddefdbe85fd8d3cd94701","feeddefdbe85fd8d3ce6550da63603","feeddefdd0a2fdae6bfd8d3ce6550da63603","feeddefdd0a2fdae6bfd8d3cf16913d948018c2d04","fff5ebfee6cefdd0a2fdae6bfd8d3cf16913d948018c2d04","fff5ebfee6cefdd0a2fdae6bfd8d3cf16913d94801a636037f2704").map(k),uc=H(fc);function cc(t){return t=Math.max(0,Math.min(1,t)),"rgb("+Math.max(0,Math.min(255,Math.round(-4.54-t*(35.34-t*(2381.73-t*(6402.7-t*(7024.72-t*2710.57)))))))+", "+Math.max(0,Math.min(255,Math.round(32.49+t*(170.73+t*(52.82-t*(131.46-t*(176.58-t*67.37)))))))+", "+Math.max(0,Math.min(255,Math.round(81.24+t*(442.36-t*(2482.43-t*(6167.24-t*(6614.94-t*2475.67)))))))+")"}var sc=Xr(te(300,.5,0),te(-240,.5,1));var dc=Xr(te(-100,.75,.35),te(80,1.5,.8)),pc=Xr(te(260,.75,.35),te(80,1.5,.8)),lc=te();function mc(t){(t<0||t>1)&&(t-=Math.floor(t));var e=Math.abs(t-.5);return lc.h=360*t-100,lc.s=1.5-1.5*e,lc.l=.8-.9*e,lc+""}var hc=be(),wv=Math.PI/3,vv=Math.PI*2/3;function gc(t){var e;return t=(.5-t)*Math.PI,hc.r=255*(e=Math.sin(t))*e,hc.g=255*(e=Math.sin(t+wv))*e,hc.b=255*(e=Math.sin(t+vv))*e,hc+""}function xc(t){return t=Math.max(0,Math.min(1,t)),"rgb("+Math.max(0,Math.min(255,Math.round(34.61+t*(1172.33-t*(10793.56-t*(33300.12-t*(38394.49-t*14825.05)))))))+", "+Math.max(0,Math.min(255,Math.round(23.31+t*(557.33+t*(1225.33-t*(3574.96-t*(1073.77+t*707.56)))))))+", "+Math.max(0,Math.min(255,Math.round(27.2+t*(3211.1-t*(15327.97-t*(27814-t*(22569.18-t*6838.66)))))))+")"}function yc(t){var e=t.length;return function(n){return t[Math.max(0,Math.min(e-1,Math.floor(n*e)))]}}var bc=yc(k("44015444025645045745055946075a46085c460a5d460b5e470d60470e6147106347116447136548146748166848176948186a481a6c481b6d481c6e481d6f481f70482071482173482374482475482576482677482878482979472a7a472c7a472d7b472e7c472f7d46307e46327e46337f463480453581453781453882443983443a83443b84433d84433e85423f854240864241864142874144874045884046883f47883f48893e49893e4a893e4c8a3d4d8a3d4e8a3c4f8a3c508b3b518b3b528b3a538b3a548c39558c39568c38588c38598c375a8c375b8d365c8d365d8d355e8d355f8d34608d34618d33628d33638d32648e32658e31668e31678e31688e30698e306a8e2f6b8e2f6c8e2e6d8e2e6e8e2e6f8e2d708e2d718e2c718e2c728e2c738e2b748e2b758e2a768e2a778e2a788e29798e297a8e297b8e287c8e287d8e277e8e277f8e27808e26818e26828e26828e25838e25848e25858e24868e24878e23888e23898e238a8d228b8d228c8d228d8d218e8d218f8d21908d21918c20928c20928c20938c1f948c1f958b1f968b1f978b1f988b1f998a1f9a8a1e9b8a1e9c891e9d891f9e891f9f881fa0881fa1881fa1871fa28720a38620a48621a58521a68522a78522a88423a98324aa8325ab8225ac8226ad8127ad8128ae8029af7f2ab07f2cb17e2db27d2eb37c2fb47c31b57b32b67a34b67935b77937b87838b9773aba763bbb753dbc743fbc7340bd7242be7144bf7046c06f48c16e4ac16d4cc26c4ec36b50c46a52c56954c56856c66758c7655ac8645cc8635ec96260ca6063cb5f65cb5e67cc5c69cd5b6ccd5a6ece5870cf5773d05675d05477d1537ad1517cd2507fd34e81d34d84d44b86d54989d5488bd6468ed64590d74393d74195d84098d83e9bd93c9dd93ba0da39a2da37a5db36a8db34aadc32addc30b0dd2fb2dd2db5de2bb8de29bade28bddf26c0df25c2df23c5e021c8e020cae11fcde11dd0e11cd2e21bd5e21ad8e219dae319dde318dfe318e2e418e5e419e7e419eae51aece51befe51cf1e51df4e61ef6e620f8e621fbe723fde725")),wc=yc(k("00000401000501010601010802010902020b02020d03030f03031204041405041606051806051a07061c08071e0907200a08220b09240c09260d0a290e0b2b100b2d110c2f120d31130d34140e36150e38160f3b180f3d19103f1a10421c10441d11471e114920114b21114e22115024125325125527125829115a2a115c2c115f2d11612f116331116533106734106936106b38106c390f6e3b0f703d0f713f0f72400f74420f75440f764510774710784910784a10794c117a4e117b4f127b51127c52137c54137d56147d57157e59157e5a167e5c167f5d177f5f187f601880621980641a80651a80671b80681c816a1c816b1d816d1d816e1e81701f81721f817320817521817621817822817922827b23827c23827e24828025828125818326818426818627818827818928818b29818c29818e2a81902a81912b81932b80942c80962c80982d80992d809b2e7f9c2e7f9e2f7fa02f7fa1307ea3307ea5317ea6317da8327daa337dab337cad347cae347bb0357bb2357bb3367ab5367ab73779b83779ba3878bc3978bd3977bf3a77c03a76c23b75c43c75c53c74c73d73c83e73ca3e72cc3f71cd4071cf4070d0416fd2426fd3436ed5446dd6456cd8456cd9466bdb476adc4869de4968df4a68e04c67e24d66e34e65e44f64e55064e75263e85362e95462ea5661eb5760ec5860ed5a5fee5b5eef5d5ef05f5ef1605df2625df2645cf3655cf4675cf4695cf56b5cf66c5cf66e5cf7705cf7725cf8745cf8765cf9785df9795df97b5dfa7d5efa7f5efa815ffb835ffb8560fb8761fc8961fc8a62fc8c63fc8e64fc9065fd9266fd9467fd9668fd9869fd9a6afd9b6bfe9d6cfe9f6dfea16efea36ffea571fea772fea973feaa74feac76feae77feb078feb27afeb47bfeb67cfeb77efeb97ffebb81febd82febf84fec185fec287fec488fec68afec88cfeca8dfecc8ffecd90fecf92fed194fed395fed597fed799fed89afdda9cfddc9efddea0fde0a1fde2a3fde3a5fde5a7fde7a9fde9aafdebacfcecaefceeb0fcf0b2fcf2b4fcf4b6fcf6b8fcf7b9fcf9bbfcfbbdfcfdbf")),vc=yc(k("00000401000501010601010802010a02020c02020e03021004031204031405041706041907051b08051d09061f0a07220b07240c08260d08290e092b10092d110a30120a32140b34150b37160b39180c3c190c3e1b0c411c0c431e0c451f0c48210c4a230c4c240c4f260c51280b53290b552b0b572d0b592f0a5b310a5c320a5e340a5f3609613809623909633b09643d09653e0966400a67420a68440a68450a69470b6a490b6a4a0c6b4c0c6b4d0d6c4f0d6c510e6c520e6d540f6d550f6d57106e59106e5a116e5c126e5d126e5f136e61136e62146e64156e65156e67166e69166e6a176e6c186e6d186e6f196e71196e721a6e741a6e751b6e771c6d781c6d7a1d6d7c1d6d7d1e6d7f1e6c801f6c82206c84206b85216b87216b88226a8a226a8c23698d23698f24699025689225689326679526679727669827669a28659b29649d29649f2a63a02a63a22b62a32c61a52c60a62d60a82e5fa92e5eab2f5ead305dae305cb0315bb1325ab3325ab43359b63458b73557b93556ba3655bc3754bd3853bf3952c03a51c13a50c33b4fc43c4ec63d4dc73e4cc83f4bca404acb4149cc4248ce4347cf4446d04545d24644d34743d44842d54a41d74b3fd84c3ed94d3dda4e3cdb503bdd513ade5238df5337e05536e15635e25734e35933e45a31e55c30e65d2fe75e2ee8602de9612bea632aeb6429eb6628ec6726ed6925ee6a24ef6c23ef6e21f06f20f1711ff1731df2741cf3761bf37819f47918f57b17f57d15f67e14f68013f78212f78410f8850ff8870ef8890cf98b0bf98c0af98e09fa9008fa9207fa9407fb9606fb9706fb9906fb9b06fb9d07fc9f07fca108fca309fca50afca60cfca80dfcaa0ffcac11fcae12fcb014fcb216fcb418fbb61afbb81dfbba1ffbbc21fbbe23fac026fac228fac42afac62df9c72ff9c932f9cb35f8cd37f8cf3af7d13df7d340f6d543f6d746f5d949f5db4cf4dd4ff4df53f4e156f3e35af3e55df2e661f2e865f2ea69f1ec6df1ed71f1ef75f1f179f2f27df2f482f3f586f3f68af4f88ef5f992f6fa96f8fb9af9fc9dfafda1fcffa4")),Mc=yc(k("0d088710078813078916078a19068c1b068d1d068e20068f2206902406912605912805922a05932c05942e05952f059631059733059735049837049938049a3a049a3c049b3e049c3f049c41049d43039e44039e46039f48039f4903a04b03a14c02a14e02a25002a25102a35302a35502a45601a45801a45901a55b01a55c01a65e01a66001a66100a76300a76400a76600a76700a86900a86a00a86c00a86e00a86f00a87100a87201a87401a87501a87701a87801a87a02a87b02a87d03a87e03a88004a88104a78305a78405a78606a68707a68808a68a09a58b0aa58d0ba58e0ca48f0da4910ea3920fa39410a29511a19613a19814a099159f9a169f9c179e9d189d9e199da01a9ca11b9ba21d9aa31e9aa51f99a62098a72197a82296aa2395ab2494ac2694ad2793ae2892b02991b12a90b22b8fb32c8eb42e8db52f8cb6308bb7318ab83289ba3388bb3488bc3587bd3786be3885bf3984c03a83c13b82c23c81c33d80c43e7fc5407ec6417dc7427cc8437bc9447aca457acb4679cc4778cc4977cd4a76ce4b75cf4c74d04d73d14e72d24f71d35171d45270d5536fd5546ed6556dd7566cd8576bd9586ada5a6ada5b69db5c68dc5d67dd5e66de5f65de6164df6263e06363e16462e26561e26660e3685fe4695ee56a5de56b5de66c5ce76e5be76f5ae87059e97158e97257ea7457eb7556eb7655ec7754ed7953ed7a52ee7b51ef7c51ef7e50f07f4ff0804ef1814df1834cf2844bf3854bf3874af48849f48948f58b47f58c46f68d45f68f44f79044f79143f79342f89441f89540f9973ff9983ef99a3efa9b3dfa9c3cfa9e3bfb9f3afba139fba238fca338fca537fca636fca835fca934fdab33fdac33fdae32fdaf31fdb130fdb22ffdb42ffdb52efeb72dfeb82cfeba2cfebb2bfebd2afebe2afec029fdc229fdc328fdc527fdc627fdc827fdca26fdcb26fccd25fcce25fcd025fcd225fbd324fbd524fbd724fad824fada24f9dc24f9dd25f8df25f8e125f7e225f7e425f6e626f6e826f5e926f5eb27f4ed27f3ee27f3f027f2f227f1f426f1f525f0f724f0f921"));var dd=Math.cos;var po=Math.min,ta=Math.sin,rt=Math.sqrt;var vr=Math.PI,rP=vr/2,mo=2*vr;var Mv=rt(3),ea={draw(t,e){let n=rt(e+po(e/28,.75))*.59436,r=n/2,o=r*Mv;t.moveTo(0,n),t.lineTo(0,-n),t.moveTo(-o,-r),t.lineTo(o,r),t.moveTo(-o,r),t.lineTo(o,-r)}};var ho={draw(t,e){let n=rt(e/vr);t.moveTo(n,0),t.arc(0,0,n,0,mo)}};var na={draw(t,e){let n=rt(e/5)/2;t.moveTo(-3*n,-n),t.lineTo(-n,-n),t.lineTo(-n,-3*n),t.lineTo(n,-3*n),t.lineTo(n,-n),t.lineTo(3*n,-n),t.lineTo(3*n,n),t.lineTo(n,n),t.lineTo(n,3*n),t.lineTo(-n,3*n),t.lineTo(-n,n),t.lineTo(-3*n,n),t.closePath()}};var xg=rt(1/3),Sv=xg*2,ra={draw(t,e){let n=rt(e/Sv),r=n*xg;t.moveTo(0,-n),t.lineTo(r,0),t.lineTo(0,n),t.lineTo(-r,0),t.closePath()}};var oa={draw(t,e){let n=rt(e)*.62625;t.moveTo(0,-n),t.lineTo(n,0),t.lineTo(0,n),t.lineTo(-n,0),t.closePath()}};var ia={draw(t,e){let n=rt(e-po(e/7,2))*.87559;t.moveTo(-n,0),t.lineTo(n,0),t.moveTo(0,n),t.lineTo(0,-n)}};var aa={draw(t,e){let n=rt(e),r=-n/2;t.rect(r,r,n,n)}};var fa={draw(t,e){let n=rt(e)*.4431;t.moveTo(n,n),t.lineTo(n,-n),t.lineTo(-n,-n),t.lineTo(-n,n),t.closePath()}};var Tv=.8908130915292852,yg=ta(vr/10)/ta(7*vr/10),_v=ta(mo/10)*yg,Cv=-dd(mo/10)*yg,ua={draw(t,e){let n=rt(e*Tv),r=_v*n,o=Cv*n;t.moveTo(0,-n),t.lineTo(r,o);for(let i=1;i<5;++i){let a=mo*i/5,f=dd(a),u=ta(a);t.lineTo(u*n,-f*n),t.lineTo(f*r-u*o,u*r+f*o)}t.closePath()}};var pd=rt(3),ca={draw(t,e){let n=-rt(e/(pd*3));t.moveTo(0,n*2),t.lineTo(-pd*n,-n),t.lineTo(pd*n,-n),t.closePath()}};var Av=rt(3),sa={draw(t,e){let n=rt(e)*.6824,r=n/2,o=n*Av/2;t.moveTo(0,-n),t.lineTo(o,r),t.lineTo(-o,r),t.closePath()}};var Me=-.5,Se=rt(3)/2,md=1/rt(12),$v=(md/2+1)*3,la={draw(t,e){let n=rt(e/$v),r=n/2,o=n*md,i=r,a=n*md+n,f=-i,u=a;t.moveTo(r,o),t.lineTo(i,a),t.lineTo(f,u),t.lineTo(Me*r-Se*o,Se*r+Me*o),t.lineTo(Me*i-Se*a,Se*i+Me*a),t.lineTo(Me*f-Se*u,Se*f+Me*u),t.lineTo(Me*r+Se*o,Me*o-Se*r),t.lineTo(Me*i+Se*a,Me*a-Se*i),t.lineTo(Me*f+Se*u,Me*u-Se*f),t.closePath()}};var go={draw(t,e){let n=rt(e-po(e/6,1.7))*.6189;t.moveTo(-n,-n),t.lineTo(n,n),t.moveTo(-n,n),t.lineTo(n,-n)}};var Sc=[ho,na,ra,aa,ua,ca,la],hd=[ho,ia,go,sa,ea,fa,oa];function Xn(t,e,n){this.k=t,this.x=e,this.y=n}Xn.prototype={constructor:Xn,scale:function(t){return t===1?this:new Xn(this.k*t,this.x,this.y)},translate:function(t,e){return t===0&e===0?this:new Xn(this.k,this.x+this.k*t,this.y+this.k*e)},apply:function(t){return[t[0]*this.k+this.x,t[1]*this.k+this.y]},applyX:function(t){return t*this.k+this.x},applyY:function(t){return t*this.k+this.y},invert:function(t){return[(t[0]-this.x)/this.k,(t[1]-this.y)/this.k]},invertX:function(t){return(t-this.x)/this.k},invertY:function(t){return(t-this.y)/this.k},rescaleX:function(t){return t.copy().domain(t.range().map(this.invertX,this).map(t.invert,t))},rescaleY:function(t){return t.copy().domain(t.range().map(this.invertY,this).map(t.invert,t))},toString:function(){return"translate("+this.x+","+this.y+") scale("+this.k+")"}};var gd=new Xn(1,0,0);xd.prototype=Xn.prototype;function xd(t){for(;!t.__zoom;)if(!(t=t.parentNode))return gd;return t.__zoom}function Re(t){return t!=null&&!Number.isNaN(t)}function Kt(t,e){return+Re(e)-+Re(t)||bt(t,e)}function Mr(t,e){return+Re(e)-+Re(t)||xe(t,e)}function Tc(t){return t!=null&&`${t}`!=""}function yd(t){return isFinite(t)?t:NaN}function da(t){return t>0&&isFinite(t)?t:NaN}function _c(t){return t<0&&isFinite(t)?t:NaN}function Cc(t,e){if(t instanceof Date||(t=new Date(+t)),isNaN(t))return typeof e=="function"?e(t):e;let n=t.getUTCHours(),r=t.getUTCMinutes(),o=t.getUTCSeconds(),i=t.getUTCMilliseconds();return`${Iv(t.getUTCFullYear(),4)}-${wn(t.getUTCMonth()+1,2)}-${wn(t.getUTCDate(),2)}${n||r||o||i?`T${wn(n,2)}:${wn(r,2)}${o||i?`:${wn(o,2)}${i?`.${wn(i,3)}`:""}`:""}Z`:""}`}function Iv(t){return t<0?`-${wn(-t,6)}`:t>9999?`+${wn(t,6)}`:wn(t,4)}function wn(t,e){return`${t}`.padStart(e,"0")}var kv=/^(?:[-+]\d{2})?\d{4}(?:-\d{2}(?:-\d{2})?)?(?:T\d{2}:\d{2}(?::\d{2}(?:\.\d{3})?)?(?:Z|[-+]\d{2}:?\d{2})?)?$/;function pa(t,e){return kv.test(t+="")?new Date(t):typeof e=="function"?e(t):e}var Ac=1e3,bd=Ac*60,wd=bd*60,ha=wd*24,Nv=ha*7,bg=ha*30,Dv=ha*365,ma=[["millisecond",.5*Ac],["second",Ac],["second",30*Ac],["minute",bd],["minute",30*bd],["hour",wd],["hour",12*wd],["day",ha],["day",2*ha],["week",Nv],["month",bg],["month",3*bg],["year",Dv]],Rv=new Map([["second",Vt],["minute",Ln],["hour",On],["day",De],["week",ce],["month",Yn],["year",jt],["monday",qn],["tuesday",Qf],["wednesday",Jf],["thursday",en],["friday",tu],["saturday",eu],["sunday",ce]]),Lv=new Map([["second",Vt],["minute",Pn],["hour",Fn],["day",uo],["week",se],["month",Bn],["year",Zt],["monday",zn],["tuesday",nu],["wednesday",ru],["thursday",nn],["friday",ou],["saturday",iu],["sunday",se]]);function wg(t,e){let n=`${t}`.toLowerCase();n.endsWith("s")&&(n=n.slice(0,-1));let r=1,o=/^(?:(\d+)\s+)/.exec(n);switch(o&&(n=n.slice(o[0].length),r=+o[1]),n){case"quarter":n="month",r*=3;break;case"half":n="month",r*=6;break}let i=e.get(n);if(!i)throw new Error(`unknown interval: ${t}`);if(!(r>1))return i;if(!i.every)throw new Error(`non-periodic interval: ${n}`);return i.every(r)}function vg(t){return wg(t,Rv)}function $c(t){return wg(t,Lv)}function Mg(t){if(!t)return!1;let e=t.floor(new Date(Date.UTC(2e3,11,31)));return Zt(e)>=e}function Sg(t){if(!t)return!1;let e=t.floor(new Date(2e3,11,31));return jt(e)>=e}function Tg(t,e,n,r){let o=t.type==="time"?Un:bn,i=r==="left"||r==="right"?(a,f)=>`
+"use strict";(()=>{function bt(t,e){return t==null||e==null?NaN:t<e?-1:t>e?1:t>=e?0:NaN}function be(t,e){return t==null||e==null?NaN:e<t?-1:e>t?1:e>=t?0:NaN}function ze(t){let e,r,n;t.length!==2?(e=bt,r=(f,u)=>bt(t(f),u),n=(f,u)=>t(f)-u):(e=t===bt||t===be?t:Ly,r=t,n=t);function o(f,u,c=0,s=f.length){if(c<s){if(e(u,u)!==0)return s;do{let p=c+s>>>1;r(f[p],u)<0?c=p+1:s=p}while(c<s)}return c}function i(f,u,c=0,s=f.length){if(c<s){if(e(u,u)!==0)return s;do{let p=c+s>>>1;r(f[p],u)<=0?c=p+1:s=p}while(c<s)}return c}function a(f,u,c=0,s=f.length){let p=o(f,u,c,s-1);return p>c&&n(f[p-1],u)>-n(f[p],u)?p-1:p}return{left:o,center:a,right:i}}function Ly(){return 0}function Go(t){return t===null?NaN:+t}function*Pm(t,e){if(e===void 0)for(let r of t)r!=null&&(r=+r)>=r&&(yield r);else{let r=-1;for(let n of t)(n=e(n,++r,t))!=null&&(n=+n)>=n&&(yield n)}}var Lm=ze(bt),Fy=Lm.right,T3=Lm.left,_3=ze(Go).center,lr=Fy;function en(t,e){let r=0;if(e===void 0)for(let n of t)n!=null&&(n=+n)>=n&&++r;else{let n=-1;for(let o of t)(o=e(o,++n,t))!=null&&(o=+o)>=o&&++r}return r}function qy(t){return t.length|0}function Oy(t){return!(t>0)}function Yy(t){return typeof t!="object"||"length"in t?t:Array.from(t)}function By(t){return e=>t(...e)}function Ua(...t){let e=typeof t[t.length-1]=="function"&&By(t.pop());t=t.map(Yy);let r=t.map(qy),n=t.length-1,o=new Array(n+1).fill(0),i=[];if(n<0||r.some(Oy))return i;for(;;){i.push(o.map((f,u)=>t[u][f]));let a=n;for(;++o[a]===r[a];){if(a===0)return e?i.map(e):i;o[a--]=0}}}function Wa(t,e){var r=0,n=0;return Float64Array.from(t,e===void 0?o=>r+=+o||0:o=>r+=+e(o,n++,t)||0)}function On(t,e){let r=0,n,o=0,i=0;if(e===void 0)for(let a of t)a!=null&&(a=+a)>=a&&(n=a-o,o+=n/++r,i+=n*(a-o));else{let a=-1;for(let f of t)(f=e(f,++a,t))!=null&&(f=+f)>=f&&(n=f-o,o+=n/++r,i+=n*(f-o))}if(r>1)return i/(r-1)}function Yn(t,e){let r=On(t,e);return r&&Math.sqrt(r)}function Ct(t,e){let r,n;if(e===void 0)for(let o of t)o!=null&&(r===void 0?o>=o&&(r=n=o):(r>o&&(r=o),n<o&&(n=o)));else{let o=-1;for(let i of t)(i=e(i,++o,t))!=null&&(r===void 0?i>=i&&(r=n=i):(r>i&&(r=i),n<i&&(n=i)))}return[r,n]}var fe=class{constructor(){this._partials=new Float64Array(32),this._n=0}add(e){let r=this._partials,n=0;for(let o=0;o<this._n&&o<32;o++){let i=r[o],a=e+i,f=Math.abs(e)<Math.abs(i)?e-(a-i):i-(a-e);f&&(r[n++]=f),e=a}return r[n]=e,this._n=n+1,this}valueOf(){let e=this._partials,r=this._n,n,o,i,a=0;if(r>0){for(a=e[--r];r>0&&(n=a,o=e[--r],a=n+o,i=o-(a-n),!i););r>0&&(i<0&&e[r-1]<0||i>0&&e[r-1]>0)&&(o=i*2,n=a+o,o==n-a&&(a=n))}return a}};var Jt=class extends Map{constructor(e,r=Om){if(super(),Object.defineProperties(this,{_intern:{value:new Map},_key:{value:r}}),e!=null)for(let[n,o]of e)this.set(n,o)}get(e){return super.get(hs(this,e))}has(e){return super.has(hs(this,e))}set(e,r){return super.set(Fm(this,e),r)}delete(e){return super.delete(qm(this,e))}},Ae=class extends Set{constructor(e,r=Om){if(super(),Object.defineProperties(this,{_intern:{value:new Map},_key:{value:r}}),e!=null)for(let n of e)this.add(n)}has(e){return super.has(hs(this,e))}add(e){return super.add(Fm(this,e))}delete(e){return super.delete(qm(this,e))}};function hs({_intern:t,_key:e},r){let n=e(r);return t.has(n)?t.get(n):r}function Fm({_intern:t,_key:e},r){let n=e(r);return t.has(n)?t.get(n):(t.set(n,r),r)}function qm({_intern:t,_key:e},r){let n=e(r);return t.has(n)&&(r=t.get(n),t.delete(n)),r}function Om(t){return t!==null&&typeof t=="object"?t.valueOf():t}function Xo(t){return t}function pr(t,...e){return xs(t,Xo,Xo,e)}function Ee(t,e,...r){return xs(t,Xo,e,r)}function gs(t,e,...r){return xs(t,Array.from,e,r)}function xs(t,e,r,n){return function o(i,a){if(a>=n.length)return r(i);let f=new Jt,u=n[a++],c=-1;for(let s of i){let p=u(s,++c,i),l=f.get(p);l?l.push(s):f.set(p,[s])}for(let[s,p]of f)f.set(s,o(p,a));return e(f)}(t,0)}function ys(t,e){return Array.from(e,r=>t[r])}function Ue(t,...e){if(typeof t[Symbol.iterator]!="function")throw new TypeError("values is not iterable");t=Array.from(t);let[r]=e;if(r&&r.length!==2||e.length>1){let n=Uint32Array.from(t,(o,i)=>i);return e.length>1?(e=e.map(o=>t.map(o)),n.sort((o,i)=>{for(let a of e){let f=Bn(a[o],a[i]);if(f)return f}})):(r=t.map(r),n.sort((o,i)=>Bn(r[o],r[i]))),ys(t,n)}return t.sort(bs(r))}function bs(t=bt){if(t===bt)return Bn;if(typeof t!="function")throw new TypeError("compare is not a function");return(e,r)=>{let n=t(e,r);return n||n===0?n:(t(r,r)===0)-(t(e,e)===0)}}function Bn(t,e){return(t==null||!(t>=t))-(e==null||!(e>=e))||(t<e?-1:t>e?1:0)}function zn(t,e,r){return(e.length!==2?Ue(Ee(t,e,r),([n,o],[i,a])=>bt(o,a)||bt(n,i)):Ue(pr(t,r),([n,o],[i,a])=>e(o,a)||bt(n,i))).map(([n])=>n)}var zy=Math.sqrt(50),Uy=Math.sqrt(10),Wy=Math.sqrt(2);function Ha(t,e,r){let n=(e-t)/Math.max(0,r),o=Math.floor(Math.log10(n)),i=n/Math.pow(10,o),a=i>=zy?10:i>=Uy?5:i>=Wy?2:1,f,u,c;return o<0?(c=Math.pow(10,-o)/a,f=Math.round(t*c),u=Math.round(e*c),f/c<t&&++f,u/c>e&&--u,c=-c):(c=Math.pow(10,o)*a,f=Math.round(t/c),u=Math.round(e/c),f*c<t&&++f,u*c>e&&--u),u<f&&.5<=r&&r<2?Ha(t,e,r*2):[f,u,c]}function ke(t,e,r){if(e=+e,t=+t,r=+r,!(r>0))return[];if(t===e)return[t];let n=e<t,[o,i,a]=n?Ha(e,t,r):Ha(t,e,r);if(!(i>=o))return[];let f=i-o+1,u=new Array(f);if(n)if(a<0)for(let c=0;c<f;++c)u[c]=(i-c)/-a;else for(let c=0;c<f;++c)u[c]=(i-c)*a;else if(a<0)for(let c=0;c<f;++c)u[c]=(o+c)/-a;else for(let c=0;c<f;++c)u[c]=(o+c)*a;return u}function rn(t,e,r){return e=+e,t=+t,r=+r,Ha(t,e,r)[2]}function Un(t,e,r){e=+e,t=+t,r=+r;let n=e<t,o=n?rn(e,t,r):rn(t,e,r);return(n?-1:1)*(o<0?1/-o:o)}function Ga(t){return Math.max(1,Math.ceil(Math.log(en(t))/Math.LN2)+1)}function Ht(t,e){let r;if(e===void 0)for(let n of t)n!=null&&(r<n||r===void 0&&n>=n)&&(r=n);else{let n=-1;for(let o of t)(o=e(o,++n,t))!=null&&(r<o||r===void 0&&o>=o)&&(r=o)}return r}function Vo(t,e){let r,n=-1,o=-1;if(e===void 0)for(let i of t)++o,i!=null&&(r<i||r===void 0&&i>=i)&&(r=i,n=o);else for(let i of t)(i=e(i,++o,t))!=null&&(r<i||r===void 0&&i>=i)&&(r=i,n=o);return n}function te(t,e){let r;if(e===void 0)for(let n of t)n!=null&&(r>n||r===void 0&&n>=n)&&(r=n);else{let n=-1;for(let o of t)(o=e(o,++n,t))!=null&&(r>o||r===void 0&&o>=o)&&(r=o)}return r}function jo(t,e){let r,n=-1,o=-1;if(e===void 0)for(let i of t)++o,i!=null&&(r>i||r===void 0&&i>=i)&&(r=i,n=o);else for(let i of t)(i=e(i,++o,t))!=null&&(r>i||r===void 0&&i>=i)&&(r=i,n=o);return n}function Xa(t,e,r=0,n=1/0,o){if(e=Math.floor(e),r=Math.floor(Math.max(0,r)),n=Math.floor(Math.min(t.length-1,n)),!(r<=e&&e<=n))return t;for(o=o===void 0?Bn:bs(o);n>r;){if(n-r>600){let u=n-r+1,c=e-r+1,s=Math.log(u),p=.5*Math.exp(2*s/3),l=.5*Math.sqrt(s*p*(u-p)/u)*(c-u/2<0?-1:1),m=Math.max(r,Math.floor(e-c*p/u+l)),d=Math.min(n,Math.floor(e+(u-c)*p/u+l));Xa(t,e,m,d,o)}let i=t[e],a=r,f=n;for(Ko(t,r,e),o(t[n],i)>0&&Ko(t,r,n);a<f;){for(Ko(t,a,f),++a,--f;o(t[a],i)<0;)++a;for(;o(t[f],i)>0;)--f}o(t[r],i)===0?Ko(t,r,f):(++f,Ko(t,f,n)),f<=e&&(r=f+1),e<=f&&(n=f-1)}return t}function Ko(t,e,r){let n=t[e];t[e]=t[r],t[r]=n}function Wn(t,e=bt){let r,n=!1;if(e.length===1){let o;for(let i of t){let a=e(i);(n?bt(a,o)>0:bt(a,a)===0)&&(r=i,o=a,n=!0)}}else for(let o of t)(n?e(o,r)>0:e(o,o)===0)&&(r=o,n=!0);return r}function we(t,e,r){if(t=Float64Array.from(Pm(t,r)),!(!(n=t.length)||isNaN(e=+e))){if(e<=0||n<2)return te(t);if(e>=1)return Ht(t);var n,o=(n-1)*e,i=Math.floor(o),a=Ht(Xa(t,i).subarray(0,i+1)),f=te(t.subarray(i+1));return a+(f-a)*(o-i)}}function ws(t,e,r=Go){if(!(!(n=t.length)||isNaN(e=+e))){if(e<=0||n<2)return+r(t[0],0,t);if(e>=1)return+r(t[n-1],n-1,t);var n,o=(n-1)*e,i=Math.floor(o),a=+r(t[i],i,t),f=+r(t[i+1],i+1,t);return a+(f-a)*(o-i)}}function Va(t,e,r){let n=en(t),o=we(t,.75)-we(t,.25);return n&&o?Math.ceil((r-e)/(2*o*Math.pow(n,-1/3))):1}function Zo(t,e,r){let n=en(t),o=Yn(t);return n&&o?Math.ceil((r-e)*Math.cbrt(n)/(3.49*o)):1}function ja(t,e){let r=0,n=0;if(e===void 0)for(let o of t)o!=null&&(o=+o)>=o&&(++r,n+=o);else{let o=-1;for(let i of t)(i=e(i,++o,t))!=null&&(i=+i)>=i&&(++r,n+=i)}if(r)return n/r}function mr(t,e){return we(t,.5,e)}function*Hy(t){for(let e of t)yield*e}function Hn(t){return Array.from(Hy(t))}function Ka(t,e){let r=new Jt;if(e===void 0)for(let i of t)i!=null&&i>=i&&r.set(i,(r.get(i)||0)+1);else{let i=-1;for(let a of t)(a=e(a,++i,t))!=null&&a>=a&&r.set(a,(r.get(a)||0)+1)}let n,o=0;for(let[i,a]of r)a>o&&(o=a,n=i);return n}function Za(t,e=Gy){let r=[],n,o=!1;for(let i of t)o&&r.push(e(n,i)),n=i,o=!0;return r}function Gy(t,e){return[t,e]}function nn(t,e,r){t=+t,e=+e,r=(o=arguments.length)<2?(e=t,t=0,1):o<3?1:+r;for(var n=-1,o=Math.max(0,Math.ceil((e-t)/r))|0,i=new Array(o);++n<o;)i[n]=t+n*r;return i}function ue(t,e){let r=0;if(e===void 0)for(let n of t)(n=+n)&&(r+=n);else{let n=-1;for(let o of t)(o=+e(o,++n,t))&&(r+=o)}return r}function Ar(t){if(typeof t[Symbol.iterator]!="function")throw new TypeError("values is not iterable");return Array.from(t).reverse()}function Qa(...t){let e=new Ae;for(let r of t)for(let n of r)e.add(n);return e}function Ym(t){return t}var vs=1,Ms=2,Ss=3,Ja=4,Bm=1e-6;function Xy(t){return"translate("+t+",0)"}function Vy(t){return"translate(0,"+t+")"}function jy(t){return e=>+t(e)}function Ky(t,e){return e=Math.max(0,t.bandwidth()-e*2)/2,t.round()&&(e=Math.round(e)),r=>+t(r)+e}function Zy(){return!this.__axis}function Qy(t,e){var r=[],n=null,o=null,i=6,a=6,f=3,u=typeof window<"u"&&window.devicePixelRatio>1?0:.5,c=t===vs||t===Ja?-1:1,s=t===Ja||t===Ms?"x":"y",p=t===vs||t===Ss?Xy:Vy;function l(m){var d=n??(e.ticks?e.ticks.apply(e,r):e.domain()),h=o??(e.tickFormat?e.tickFormat.apply(e,r):Ym),g=Math.max(i,0)+f,b=e.range(),x=+b[0]+u,M=+b[b.length-1]+u,y=(e.bandwidth?Ky:jy)(e.copy(),u),w=m.selection?m.selection():m,v=w.selectAll(".domain").data([null]),T=w.selectAll(".tick").data(d,e).order(),E=T.exit(),k=T.enter().append("g").attr("class","tick"),q=T.select("line"),_=T.select("text");v=v.merge(v.enter().insert("path",".tick").attr("class","domain").attr("stroke","currentColor")),T=T.merge(k),q=q.merge(k.append("line").attr("stroke","currentColor").attr(s+"2",c*i)),_=_.merge(k.append("text").attr("fill","currentColor").attr(s,c*g).attr("dy",t===vs?"0em":t===Ss?"0.71em":"0.32em")),m!==w&&(v=v.transition(m),T=T.transition(m),q=q.transition(m),_=_.transition(m),E=E.transition(m).attr("opacity",Bm).attr("transform",function(R){return isFinite(R=y(R))?p(R+u):this.getAttribute("transform")}),k.attr("opacity",Bm).attr("transform",function(R){var F=this.parentNode.__axis;return p((F&&isFinite(F=F(R))?F:y(R))+u)})),E.remove(),v.attr("d",t===Ja||t===Ms?a?"M"+c*a+","+x+"H"+u+"V"+M+"H"+c*a:"M"+u+","+x+"V"+M:a?"M"+x+","+c*a+"V"+u+"H"+M+"V"+c*a:"M"+x+","+u+"H"+M),T.attr("opacity",1).attr("transform",function(R){return p(y(R)+u)}),q.attr(s+"2",c*i),_.attr(s,c*g).text(h),w.filter(Zy).attr("fill","none").attr("font-size",10).attr("font-family","sans-serif").attr("text-anchor",t===Ms?"start":t===Ja?"end":"middle"),w.each(function(){this.__axis=y})}return l.scale=function(m){return arguments.length?(e=m,l):e},l.ticks=function(){return r=Array.from(arguments),l},l.tickArguments=function(m){return arguments.length?(r=m==null?[]:Array.from(m),l):r.slice()},l.tickValues=function(m){return arguments.length?(n=m==null?null:Array.from(m),l):n&&n.slice()},l.tickFormat=function(m){return arguments.length?(o=m,l):o},l.tickSize=function(m){return arguments.length?(i=a=+m,l):i},l.tickSizeInner=function(m){return arguments.length?(i=+m,l):i},l.tickSizeOuter=function(m){return arguments.length?(a=+m,l):a},l.tickPadding=function(m){return arguments.length?(f=+m,l):f},l.offset=function(m){return arguments.length?(u=+m,l):u},l}function Ts(t){return Qy(Ss,t)}var Jy={value:()=>{}};function Um(){for(var t=0,e=arguments.length,r={},n;t<e;++t){if(!(n=arguments[t]+"")||n in r||/[\s.]/.test(n))throw new Error("illegal type: "+n);r[n]=[]}return new tf(r)}function tf(t){this._=t}function tb(t,e){return t.trim().split(/^|\s+/).map(function(r){var n="",o=r.indexOf(".");if(o>=0&&(n=r.slice(o+1),r=r.slice(0,o)),r&&!e.hasOwnProperty(r))throw new Error("unknown type: "+r);return{type:r,name:n}})}tf.prototype=Um.prototype={constructor:tf,on:function(t,e){var r=this._,n=tb(t+"",r),o,i=-1,a=n.length;if(arguments.length<2){for(;++i<a;)if((o=(t=n[i]).type)&&(o=eb(r[o],t.name)))return o;return}if(e!=null&&typeof e!="function")throw new Error("invalid callback: "+e);for(;++i<a;)if(o=(t=n[i]).type)r[o]=zm(r[o],t.name,e);else if(e==null)for(o in r)r[o]=zm(r[o],t.name,null);return this},copy:function(){var t={},e=this._;for(var r in e)t[r]=e[r].slice();return new tf(t)},call:function(t,e){if((o=arguments.length-2)>0)for(var r=new Array(o),n=0,o,i;n<o;++n)r[n]=arguments[n+2];if(!this._.hasOwnProperty(t))throw new Error("unknown type: "+t);for(i=this._[t],n=0,o=i.length;n<o;++n)i[n].value.apply(e,r)},apply:function(t,e,r){if(!this._.hasOwnProperty(t))throw new Error("unknown type: "+t);for(var n=this._[t],o=0,i=n.length;o<i;++o)n[o].value.apply(e,r)}};function eb(t,e){for(var r=0,n=t.length,o;r<n;++r)if((o=t[r]).name===e)return o.value}function zm(t,e,r){for(var n=0,o=t.length;n<o;++n)if(t[n].name===e){t[n]=Jy,t=t.slice(0,n).concat(t.slice(n+1));break}return r!=null&&t.push({name:e,value:r}),t}var ef=Um;var rf="http://www.w3.org/1999/xhtml",We={svg:"http://www.w3.org/2000/svg",xhtml:rf,xlink:"http://www.w3.org/1999/xlink",xml:"http://www.w3.org/XML/1998/namespace",xmlns:"http://www.w3.org/2000/xmlns/"};function dr(t){var e=t+="",r=e.indexOf(":");return r>=0&&(e=t.slice(0,r))!=="xmlns"&&(t=t.slice(r+1)),We.hasOwnProperty(e)?{space:We[e],local:t}:t}function rb(t){return function(){var e=this.ownerDocument,r=this.namespaceURI;return r===rf&&e.documentElement.namespaceURI===rf?e.createElement(t):e.createElementNS(r,t)}}function nb(t){return function(){return this.ownerDocument.createElementNS(t.space,t.local)}}function He(t){var e=dr(t);return(e.local?nb:rb)(e)}function ob(){}function on(t){return t==null?ob:function(){return this.querySelector(t)}}function Wm(t){typeof t!="function"&&(t=on(t));for(var e=this._groups,r=e.length,n=new Array(r),o=0;o<r;++o)for(var i=e[o],a=i.length,f=n[o]=new Array(a),u,c,s=0;s<a;++s)(u=i[s])&&(c=t.call(u,u.__data__,s,i))&&("__data__"in u&&(c.__data__=u.__data__),f[s]=c);return new wt(n,this._parents)}function nf(t){return t==null?[]:Array.isArray(t)?t:Array.from(t)}function ib(){return[]}function Qo(t){return t==null?ib:function(){return this.querySelectorAll(t)}}function ab(t){return function(){return nf(t.apply(this,arguments))}}function Hm(t){typeof t=="function"?t=ab(t):t=Qo(t);for(var e=this._groups,r=e.length,n=[],o=[],i=0;i<r;++i)for(var a=e[i],f=a.length,u,c=0;c<f;++c)(u=a[c])&&(n.push(t.call(u,u.__data__,c,a)),o.push(u));return new wt(n,o)}function Jo(t){return function(){return this.matches(t)}}function of(t){return function(e){return e.matches(t)}}var fb=Array.prototype.find;function ub(t){return function(){return fb.call(this.children,t)}}function cb(){return this.firstElementChild}function Gm(t){return this.select(t==null?cb:ub(typeof t=="function"?t:of(t)))}var sb=Array.prototype.filter;function lb(){return Array.from(this.children)}function pb(t){return function(){return sb.call(this.children,t)}}function Xm(t){return this.selectAll(t==null?lb:pb(typeof t=="function"?t:of(t)))}function Vm(t){typeof t!="function"&&(t=Jo(t));for(var e=this._groups,r=e.length,n=new Array(r),o=0;o<r;++o)for(var i=e[o],a=i.length,f=n[o]=[],u,c=0;c<a;++c)(u=i[c])&&t.call(u,u.__data__,c,i)&&f.push(u);return new wt(n,this._parents)}function af(t){return new Array(t.length)}function jm(){return new wt(this._enter||this._groups.map(af),this._parents)}function ti(t,e){this.ownerDocument=t.ownerDocument,this.namespaceURI=t.namespaceURI,this._next=null,this._parent=t,this.__data__=e}ti.prototype={constructor:ti,appendChild:function(t){return this._parent.insertBefore(t,this._next)},insertBefore:function(t,e){return this._parent.insertBefore(t,e)},querySelector:function(t){return this._parent.querySelector(t)},querySelectorAll:function(t){return this._parent.querySelectorAll(t)}};function Km(t){return function(){return t}}function mb(t,e,r,n,o,i){for(var a=0,f,u=e.length,c=i.length;a<c;++a)(f=e[a])?(f.__data__=i[a],n[a]=f):r[a]=new ti(t,i[a]);for(;a<u;++a)(f=e[a])&&(o[a]=f)}function db(t,e,r,n,o,i,a){var f,u,c=new Map,s=e.length,p=i.length,l=new Array(s),m;for(f=0;f<s;++f)(u=e[f])&&(l[f]=m=a.call(u,u.__data__,f,e)+"",c.has(m)?o[f]=u:c.set(m,u));for(f=0;f<p;++f)m=a.call(t,i[f],f,i)+"",(u=c.get(m))?(n[f]=u,u.__data__=i[f],c.delete(m)):r[f]=new ti(t,i[f]);for(f=0;f<s;++f)(u=e[f])&&c.get(l[f])===u&&(o[f]=u)}function hb(t){return t.__data__}function Zm(t,e){if(!arguments.length)return Array.from(this,hb);var r=e?db:mb,n=this._parents,o=this._groups;typeof t!="function"&&(t=Km(t));for(var i=o.length,a=new Array(i),f=new Array(i),u=new Array(i),c=0;c<i;++c){var s=n[c],p=o[c],l=p.length,m=gb(t.call(s,s&&s.__data__,c,n)),d=m.length,h=f[c]=new Array(d),g=a[c]=new Array(d),b=u[c]=new Array(l);r(s,p,h,g,b,m,e);for(var x=0,M=0,y,w;x<d;++x)if(y=h[x]){for(x>=M&&(M=x+1);!(w=g[M])&&++M<d;);y._next=w||null}}return a=new wt(a,n),a._enter=f,a._exit=u,a}function gb(t){return typeof t=="object"&&"length"in t?t:Array.from(t)}function Qm(){return new wt(this._exit||this._groups.map(af),this._parents)}function Jm(t,e,r){var n=this.enter(),o=this,i=this.exit();return typeof t=="function"?(n=t(n),n&&(n=n.selection())):n=n.append(t+""),e!=null&&(o=e(o),o&&(o=o.selection())),r==null?i.remove():r(i),n&&o?n.merge(o).order():o}function td(t){for(var e=t.selection?t.selection():t,r=this._groups,n=e._groups,o=r.length,i=n.length,a=Math.min(o,i),f=new Array(o),u=0;u<a;++u)for(var c=r[u],s=n[u],p=c.length,l=f[u]=new Array(p),m,d=0;d<p;++d)(m=c[d]||s[d])&&(l[d]=m);for(;u<o;++u)f[u]=r[u];return new wt(f,this._parents)}function ed(){for(var t=this._groups,e=-1,r=t.length;++e<r;)for(var n=t[e],o=n.length-1,i=n[o],a;--o>=0;)(a=n[o])&&(i&&a.compareDocumentPosition(i)^4&&i.parentNode.insertBefore(a,i),i=a);return this}function rd(t){t||(t=xb);function e(p,l){return p&&l?t(p.__data__,l.__data__):!p-!l}for(var r=this._groups,n=r.length,o=new Array(n),i=0;i<n;++i){for(var a=r[i],f=a.length,u=o[i]=new Array(f),c,s=0;s<f;++s)(c=a[s])&&(u[s]=c);u.sort(e)}return new wt(o,this._parents).order()}function xb(t,e){return t<e?-1:t>e?1:t>=e?0:NaN}function nd(){var t=arguments[0];return arguments[0]=this,t.apply(null,arguments),this}function od(){return Array.from(this)}function id(){for(var t=this._groups,e=0,r=t.length;e<r;++e)for(var n=t[e],o=0,i=n.length;o<i;++o){var a=n[o];if(a)return a}return null}function ad(){let t=0;for(let e of this)++t;return t}function fd(){return!this.node()}function ud(t){for(var e=this._groups,r=0,n=e.length;r<n;++r)for(var o=e[r],i=0,a=o.length,f;i<a;++i)(f=o[i])&&t.call(f,f.__data__,i,o);return this}function yb(t){return function(){this.removeAttribute(t)}}function bb(t){return function(){this.removeAttributeNS(t.space,t.local)}}function wb(t,e){return function(){this.setAttribute(t,e)}}function vb(t,e){return function(){this.setAttributeNS(t.space,t.local,e)}}function Mb(t,e){return function(){var r=e.apply(this,arguments);r==null?this.removeAttribute(t):this.setAttribute(t,r)}}function Sb(t,e){return function(){var r=e.apply(this,arguments);r==null?this.removeAttributeNS(t.space,t.local):this.setAttributeNS(t.space,t.local,r)}}function cd(t,e){var r=dr(t);if(arguments.length<2){var n=this.node();return r.local?n.getAttributeNS(r.space,r.local):n.getAttribute(r)}return this.each((e==null?r.local?bb:yb:typeof e=="function"?r.local?Sb:Mb:r.local?vb:wb)(r,e))}function ei(t){return t.ownerDocument&&t.ownerDocument.defaultView||t.document&&t||t.defaultView}function Tb(t){return function(){this.style.removeProperty(t)}}function _b(t,e,r){return function(){this.style.setProperty(t,e,r)}}function Cb(t,e,r){return function(){var n=e.apply(this,arguments);n==null?this.style.removeProperty(t):this.style.setProperty(t,n,r)}}function sd(t,e,r){return arguments.length>1?this.each((e==null?Tb:typeof e=="function"?Cb:_b)(t,e,r??"")):Er(this.node(),t)}function Er(t,e){return t.style.getPropertyValue(e)||ei(t).getComputedStyle(t,null).getPropertyValue(e)}function $b(t){return function(){delete this[t]}}function Ab(t,e){return function(){this[t]=e}}function Eb(t,e){return function(){var r=e.apply(this,arguments);r==null?delete this[t]:this[t]=r}}function ld(t,e){return arguments.length>1?this.each((e==null?$b:typeof e=="function"?Eb:Ab)(t,e)):this.node()[t]}function pd(t){return t.trim().split(/^|\s+/)}function _s(t){return t.classList||new md(t)}function md(t){this._node=t,this._names=pd(t.getAttribute("class")||"")}md.prototype={add:function(t){var e=this._names.indexOf(t);e<0&&(this._names.push(t),this._node.setAttribute("class",this._names.join(" ")))},remove:function(t){var e=this._names.indexOf(t);e>=0&&(this._names.splice(e,1),this._node.setAttribute("class",this._names.join(" ")))},contains:function(t){return this._names.indexOf(t)>=0}};function dd(t,e){for(var r=_s(t),n=-1,o=e.length;++n<o;)r.add(e[n])}function hd(t,e){for(var r=_s(t),n=-1,o=e.length;++n<o;)r.remove(e[n])}function kb(t){return function(){dd(this,t)}}function Ib(t){return function(){hd(this,t)}}function Nb(t,e){return function(){(e.apply(this,arguments)?dd:hd)(this,t)}}function gd(t,e){var r=pd(t+"");if(arguments.length<2){for(var n=_s(this.node()),o=-1,i=r.length;++o<i;)if(!n.contains(r[o]))return!1;return!0}return this.each((typeof e=="function"?Nb:e?kb:Ib)(r,e))}function Db(){this.textContent=""}function Rb(t){return function(){this.textContent=t}}function Pb(t){return function(){var e=t.apply(this,arguments);this.textContent=e??""}}function xd(t){return arguments.length?this.each(t==null?Db:(typeof t=="function"?Pb:Rb)(t)):this.node().textContent}function Lb(){this.innerHTML=""}function Fb(t){return function(){this.innerHTML=t}}function qb(t){return function(){var e=t.apply(this,arguments);this.innerHTML=e??""}}function yd(t){return arguments.length?this.each(t==null?Lb:(typeof t=="function"?qb:Fb)(t)):this.node().innerHTML}function Ob(){this.nextSibling&&this.parentNode.appendChild(this)}function bd(){return this.each(Ob)}function Yb(){this.previousSibling&&this.parentNode.insertBefore(this,this.parentNode.firstChild)}function wd(){return this.each(Yb)}function vd(t){var e=typeof t=="function"?t:He(t);return this.select(function(){return this.appendChild(e.apply(this,arguments))})}function Bb(){return null}function Md(t,e){var r=typeof t=="function"?t:He(t),n=e==null?Bb:typeof e=="function"?e:on(e);return this.select(function(){return this.insertBefore(r.apply(this,arguments),n.apply(this,arguments)||null)})}function zb(){var t=this.parentNode;t&&t.removeChild(this)}function Sd(){return this.each(zb)}function Ub(){var t=this.cloneNode(!1),e=this.parentNode;return e?e.insertBefore(t,this.nextSibling):t}function Wb(){var t=this.cloneNode(!0),e=this.parentNode;return e?e.insertBefore(t,this.nextSibling):t}function Td(t){return this.select(t?Wb:Ub)}function _d(t){return arguments.length?this.property("__data__",t):this.node().__data__}function Hb(t){return function(e){t.call(this,e,this.__data__)}}function Gb(t){return t.trim().split(/^|\s+/).map(function(e){var r="",n=e.indexOf(".");return n>=0&&(r=e.slice(n+1),e=e.slice(0,n)),{type:e,name:r}})}function Xb(t){return function(){var e=this.__on;if(e){for(var r=0,n=-1,o=e.length,i;r<o;++r)i=e[r],(!t.type||i.type===t.type)&&i.name===t.name?this.removeEventListener(i.type,i.listener,i.options):e[++n]=i;++n?e.length=n:delete this.__on}}}function Vb(t,e,r){return function(){var n=this.__on,o,i=Hb(e);if(n){for(var a=0,f=n.length;a<f;++a)if((o=n[a]).type===t.type&&o.name===t.name){this.removeEventListener(o.type,o.listener,o.options),this.addEventListener(o.type,o.listener=i,o.options=r),o.value=e;return}}this.addEventListener(t.type,i,r),o={type:t.type,name:t.name,value:e,listener:i,options:r},n?n.push(o):this.__on=[o]}}function Cd(t,e,r){var n=Gb(t+""),o,i=n.length,a;if(arguments.length<2){var f=this.node().__on;if(f){for(var u=0,c=f.length,s;u<c;++u)for(o=0,s=f[u];o<i;++o)if((a=n[o]).type===s.type&&a.name===s.name)return s.value}return}for(f=e?Vb:Xb,o=0;o<i;++o)this.each(f(n[o],e,r));return this}function $d(t,e,r){var n=ei(t),o=n.CustomEvent;typeof o=="function"?o=new o(e,r):(o=n.document.createEvent("Event"),r?(o.initEvent(e,r.bubbles,r.cancelable),o.detail=r.detail):o.initEvent(e,!1,!1)),t.dispatchEvent(o)}function jb(t,e){return function(){return $d(this,t,e)}}function Kb(t,e){return function(){return $d(this,t,e.apply(this,arguments))}}function Ad(t,e){return this.each((typeof e=="function"?Kb:jb)(t,e))}function*Ed(){for(var t=this._groups,e=0,r=t.length;e<r;++e)for(var n=t[e],o=0,i=n.length,a;o<i;++o)(a=n[o])&&(yield a)}var ff=[null];function wt(t,e){this._groups=t,this._parents=e}function kd(){return new wt([[document.documentElement]],ff)}function Zb(){return this}wt.prototype=kd.prototype={constructor:wt,select:Wm,selectAll:Hm,selectChild:Gm,selectChildren:Xm,filter:Vm,data:Zm,enter:jm,exit:Qm,join:Jm,merge:td,selection:Zb,order:ed,sort:rd,call:nd,nodes:od,node:id,size:ad,empty:fd,each:ud,attr:cd,style:sd,property:ld,classed:gd,text:xd,html:yd,raise:bd,lower:wd,append:vd,insert:Md,remove:Sd,clone:Td,datum:_d,on:Cd,dispatch:Ad,[Symbol.iterator]:Ed};var hr=kd;function Ie(t){return typeof t=="string"?new wt([[document.querySelector(t)]],[document.documentElement]):new wt([[t]],ff)}function Cs(t){let e;for(;e=t.sourceEvent;)t=e;return t}function ri(t,e){if(t=Cs(t),e===void 0&&(e=t.currentTarget),e){var r=e.ownerSVGElement||e;if(r.createSVGPoint){var n=r.createSVGPoint();return n.x=t.clientX,n.y=t.clientY,n=n.matrixTransform(e.getScreenCTM().inverse()),[n.x,n.y]}if(e.getBoundingClientRect){var o=e.getBoundingClientRect();return[t.clientX-o.left-e.clientLeft,t.clientY-o.top-e.clientTop]}}return[t.pageX,t.pageY]}function gr(t,e,r){t.prototype=e.prototype=r,r.constructor=t}function kr(t,e){var r=Object.create(t.prototype);for(var n in e)r[n]=e[n];return r}function Xe(){}var Ir=.7,un=1/Ir,Gn="\\s*([+-]?\\d+)\\s*",ni="\\s*([+-]?(?:\\d*\\.)?\\d+(?:[eE][+-]?\\d+)?)\\s*",Ge="\\s*([+-]?(?:\\d*\\.)?\\d+(?:[eE][+-]?\\d+)?)%\\s*",Qb=/^#([0-9a-f]{3,8})$/,Jb=new RegExp(`^rgb\\(${Gn},${Gn},${Gn}\\)$`),t2=new RegExp(`^rgb\\(${Ge},${Ge},${Ge}\\)$`),e2=new RegExp(`^rgba\\(${Gn},${Gn},${Gn},${ni}\\)$`),r2=new RegExp(`^rgba\\(${Ge},${Ge},${Ge},${ni}\\)$`),n2=new RegExp(`^hsl\\(${ni},${Ge},${Ge}\\)$`),o2=new RegExp(`^hsla\\(${ni},${Ge},${Ge},${ni}\\)$`),Id={aliceblue:15792383,antiquewhite:16444375,aqua:65535,aquamarine:8388564,azure:15794175,beige:16119260,bisque:16770244,black:0,blanchedalmond:16772045,blue:255,blueviolet:9055202,brown:10824234,burlywood:14596231,cadetblue:6266528,chartreuse:8388352,chocolate:13789470,coral:16744272,cornflowerblue:6591981,cornsilk:16775388,crimson:14423100,cyan:65535,darkblue:139,darkcyan:35723,darkgoldenrod:12092939,darkgray:11119017,darkgreen:25600,darkgrey:11119017,darkkhaki:12433259,darkmagenta:9109643,darkolivegreen:5597999,darkorange:16747520,darkorchid:10040012,darkred:9109504,darksalmon:15308410,darkseagreen:9419919,darkslateblue:4734347,darkslategray:3100495,darkslategrey:3100495,darkturquoise:52945,darkviolet:9699539,deeppink:16716947,deepskyblue:49151,dimgray:6908265,dimgrey:6908265,dodgerblue:2003199,firebrick:11674146,floralwhite:16775920,forestgreen:2263842,fuchsia:16711935,gainsboro:14474460,ghostwhite:16316671,gold:16766720,goldenrod:14329120,gray:8421504,green:32768,greenyellow:11403055,grey:8421504,honeydew:15794160,hotpink:16738740,indianred:13458524,indigo:4915330,ivory:16777200,khaki:15787660,lavender:15132410,lavenderblush:16773365,lawngreen:8190976,lemonchiffon:16775885,lightblue:11393254,lightcoral:15761536,lightcyan:14745599,lightgoldenrodyellow:16448210,lightgray:13882323,lightgreen:9498256,lightgrey:13882323,lightpink:16758465,lightsalmon:16752762,lightseagreen:2142890,lightskyblue:8900346,lightslategray:7833753,lightslategrey:7833753,lightsteelblue:11584734,lightyellow:16777184,lime:65280,limegreen:3329330,linen:16445670,magenta:16711935,maroon:8388608,mediumaquamarine:6737322,mediumblue:205,mediumorchid:12211667,mediumpurple:9662683,mediumseagreen:3978097,mediumslateblue:8087790,mediumspringgreen:64154,mediumturquoise:4772300,mediumvioletred:13047173,midnightblue:1644912,mintcream:16121850,mistyrose:16770273,moccasin:16770229,navajowhite:16768685,navy:128,oldlace:16643558,olive:8421376,olivedrab:7048739,orange:16753920,orangered:16729344,orchid:14315734,palegoldenrod:15657130,palegreen:10025880,paleturquoise:11529966,palevioletred:14381203,papayawhip:16773077,peachpuff:16767673,peru:13468991,pink:16761035,plum:14524637,powderblue:11591910,purple:8388736,rebeccapurple:6697881,red:16711680,rosybrown:12357519,royalblue:4286945,saddlebrown:9127187,salmon:16416882,sandybrown:16032864,seagreen:3050327,seashell:16774638,sienna:10506797,silver:12632256,skyblue:8900331,slateblue:6970061,slategray:7372944,slategrey:7372944,snow:16775930,springgreen:65407,steelblue:4620980,tan:13808780,teal:32896,thistle:14204888,tomato:16737095,turquoise:4251856,violet:15631086,wheat:16113331,white:16777215,whitesmoke:16119285,yellow:16776960,yellowgreen:10145074};gr(Xe,ce,{copy(t){return Object.assign(new this.constructor,this,t)},displayable(){return this.rgb().displayable()},hex:Nd,formatHex:Nd,formatHex8:i2,formatHsl:a2,formatRgb:Dd,toString:Dd});function Nd(){return this.rgb().formatHex()}function i2(){return this.rgb().formatHex8()}function a2(){return Od(this).formatHsl()}function Dd(){return this.rgb().formatRgb()}function ce(t){var e,r;return t=(t+"").trim().toLowerCase(),(e=Qb.exec(t))?(r=e[1].length,e=parseInt(e[1],16),r===6?Rd(e):r===3?new Tt(e>>8&15|e>>4&240,e>>4&15|e&240,(e&15)<<4|e&15,1):r===8?uf(e>>24&255,e>>16&255,e>>8&255,(e&255)/255):r===4?uf(e>>12&15|e>>8&240,e>>8&15|e>>4&240,e>>4&15|e&240,((e&15)<<4|e&15)/255):null):(e=Jb.exec(t))?new Tt(e[1],e[2],e[3],1):(e=t2.exec(t))?new Tt(e[1]*255/100,e[2]*255/100,e[3]*255/100,1):(e=e2.exec(t))?uf(e[1],e[2],e[3],e[4]):(e=r2.exec(t))?uf(e[1]*255/100,e[2]*255/100,e[3]*255/100,e[4]):(e=n2.exec(t))?Fd(e[1],e[2]/100,e[3]/100,1):(e=o2.exec(t))?Fd(e[1],e[2]/100,e[3]/100,e[4]):Id.hasOwnProperty(t)?Rd(Id[t]):t==="transparent"?new Tt(NaN,NaN,NaN,0):null}function Rd(t){return new Tt(t>>16&255,t>>8&255,t&255,1)}function uf(t,e,r,n){return n<=0&&(t=e=r=NaN),new Tt(t,e,r,n)}function oi(t){return t instanceof Xe||(t=ce(t)),t?(t=t.rgb(),new Tt(t.r,t.g,t.b,t.opacity)):new Tt}function ve(t,e,r,n){return arguments.length===1?oi(t):new Tt(t,e,r,n??1)}function Tt(t,e,r,n){this.r=+t,this.g=+e,this.b=+r,this.opacity=+n}gr(Tt,ve,kr(Xe,{brighter(t){return t=t==null?un:Math.pow(un,t),new Tt(this.r*t,this.g*t,this.b*t,this.opacity)},darker(t){return t=t==null?Ir:Math.pow(Ir,t),new Tt(this.r*t,this.g*t,this.b*t,this.opacity)},rgb(){return this},clamp(){return new Tt(fn(this.r),fn(this.g),fn(this.b),sf(this.opacity))},displayable(){return-.5<=this.r&&this.r<255.5&&-.5<=this.g&&this.g<255.5&&-.5<=this.b&&this.b<255.5&&0<=this.opacity&&this.opacity<=1},hex:Pd,formatHex:Pd,formatHex8:f2,formatRgb:Ld,toString:Ld}));function Pd(){return`#${an(this.r)}${an(this.g)}${an(this.b)}`}function f2(){return`#${an(this.r)}${an(this.g)}${an(this.b)}${an((isNaN(this.opacity)?1:this.opacity)*255)}`}function Ld(){let t=sf(this.opacity);return`${t===1?"rgb(":"rgba("}${fn(this.r)}, ${fn(this.g)}, ${fn(this.b)}${t===1?")":`, ${t})`}`}function sf(t){return isNaN(t)?1:Math.max(0,Math.min(1,t))}function fn(t){return Math.max(0,Math.min(255,Math.round(t)||0))}function an(t){return t=fn(t),(t<16?"0":"")+t.toString(16)}function Fd(t,e,r,n){return n<=0?t=e=r=NaN:r<=0||r>=1?t=e=NaN:e<=0&&(t=NaN),new Ne(t,e,r,n)}function Od(t){if(t instanceof Ne)return new Ne(t.h,t.s,t.l,t.opacity);if(t instanceof Xe||(t=ce(t)),!t)return new Ne;if(t instanceof Ne)return t;t=t.rgb();var e=t.r/255,r=t.g/255,n=t.b/255,o=Math.min(e,r,n),i=Math.max(e,r,n),a=NaN,f=i-o,u=(i+o)/2;return f?(e===i?a=(r-n)/f+(r<n)*6:r===i?a=(n-e)/f+2:a=(e-r)/f+4,f/=u<.5?i+o:2-i-o,a*=60):f=u>0&&u<1?0:a,new Ne(a,f,u,t.opacity)}function ii(t,e,r,n){return arguments.length===1?Od(t):new Ne(t,e,r,n??1)}function Ne(t,e,r,n){this.h=+t,this.s=+e,this.l=+r,this.opacity=+n}gr(Ne,ii,kr(Xe,{brighter(t){return t=t==null?un:Math.pow(un,t),new Ne(this.h,this.s,this.l*t,this.opacity)},darker(t){return t=t==null?Ir:Math.pow(Ir,t),new Ne(this.h,this.s,this.l*t,this.opacity)},rgb(){var t=this.h%360+(this.h<0)*360,e=isNaN(t)||isNaN(this.s)?0:this.s,r=this.l,n=r+(r<.5?r:1-r)*e,o=2*r-n;return new Tt($s(t>=240?t-240:t+120,o,n),$s(t,o,n),$s(t<120?t+240:t-120,o,n),this.opacity)},clamp(){return new Ne(qd(this.h),cf(this.s),cf(this.l),sf(this.opacity))},displayable(){return(0<=this.s&&this.s<=1||isNaN(this.s))&&0<=this.l&&this.l<=1&&0<=this.opacity&&this.opacity<=1},formatHsl(){let t=sf(this.opacity);return`${t===1?"hsl(":"hsla("}${qd(this.h)}, ${cf(this.s)*100}%, ${cf(this.l)*100}%${t===1?")":`, ${t})`}`}}));function qd(t){return t=(t||0)%360,t<0?t+360:t}function cf(t){return Math.max(0,Math.min(1,t||0))}function $s(t,e,r){return(t<60?e+(r-e)*t/60:t<180?r:t<240?e+(r-e)*(240-t)/60:e)*255}var lf=Math.PI/180,pf=180/Math.PI;var mf=18,Yd=.96422,Bd=1,zd=.82521,Ud=4/29,Xn=6/29,Wd=3*Xn*Xn,u2=Xn*Xn*Xn;function Hd(t){if(t instanceof Ve)return new Ve(t.l,t.a,t.b,t.opacity);if(t instanceof xr)return Gd(t);t instanceof Tt||(t=oi(t));var e=Is(t.r),r=Is(t.g),n=Is(t.b),o=As((.2225045*e+.7168786*r+.0606169*n)/Bd),i,a;return e===r&&r===n?i=a=o:(i=As((.4360747*e+.3850649*r+.1430804*n)/Yd),a=As((.0139322*e+.0971045*r+.7141733*n)/zd)),new Ve(116*o-16,500*(i-o),200*(o-a),t.opacity)}function Vn(t,e,r,n){return arguments.length===1?Hd(t):new Ve(t,e,r,n??1)}function Ve(t,e,r,n){this.l=+t,this.a=+e,this.b=+r,this.opacity=+n}gr(Ve,Vn,kr(Xe,{brighter(t){return new Ve(this.l+mf*(t??1),this.a,this.b,this.opacity)},darker(t){return new Ve(this.l-mf*(t??1),this.a,this.b,this.opacity)},rgb(){var t=(this.l+16)/116,e=isNaN(this.a)?t:t+this.a/500,r=isNaN(this.b)?t:t-this.b/200;return e=Yd*Es(e),t=Bd*Es(t),r=zd*Es(r),new Tt(ks(3.1338561*e-1.6168667*t-.4906146*r),ks(-.9787684*e+1.9161415*t+.033454*r),ks(.0719453*e-.2289914*t+1.4052427*r),this.opacity)}}));function As(t){return t>u2?Math.pow(t,1/3):t/Wd+Ud}function Es(t){return t>Xn?t*t*t:Wd*(t-Ud)}function ks(t){return 255*(t<=.0031308?12.92*t:1.055*Math.pow(t,1/2.4)-.055)}function Is(t){return(t/=255)<=.04045?t/12.92:Math.pow((t+.055)/1.055,2.4)}function c2(t){if(t instanceof xr)return new xr(t.h,t.c,t.l,t.opacity);if(t instanceof Ve||(t=Hd(t)),t.a===0&&t.b===0)return new xr(NaN,0<t.l&&t.l<100?0:NaN,t.l,t.opacity);var e=Math.atan2(t.b,t.a)*pf;return new xr(e<0?e+360:e,Math.sqrt(t.a*t.a+t.b*t.b),t.l,t.opacity)}function ai(t,e,r,n){return arguments.length===1?c2(t):new xr(t,e,r,n??1)}function xr(t,e,r,n){this.h=+t,this.c=+e,this.l=+r,this.opacity=+n}function Gd(t){if(isNaN(t.h))return new Ve(t.l,0,0,t.opacity);var e=t.h*lf;return new Ve(t.l,Math.cos(e)*t.c,Math.sin(e)*t.c,t.opacity)}gr(xr,ai,kr(Xe,{brighter(t){return new xr(this.h,this.c,this.l+mf*(t??1),this.opacity)},darker(t){return new xr(this.h,this.c,this.l-mf*(t??1),this.opacity)},rgb(){return Gd(this).rgb()}}));var Kd=-.14861,Ns=1.78277,Ds=-.29227,df=-.90649,fi=1.97294,Xd=fi*df,Vd=fi*Ns,jd=Ns*Ds-df*Kd;function s2(t){if(t instanceof cn)return new cn(t.h,t.s,t.l,t.opacity);t instanceof Tt||(t=oi(t));var e=t.r/255,r=t.g/255,n=t.b/255,o=(jd*n+Xd*e-Vd*r)/(jd+Xd-Vd),i=n-o,a=(fi*(r-o)-Ds*i)/df,f=Math.sqrt(a*a+i*i)/(fi*o*(1-o)),u=f?Math.atan2(a,i)*pf-120:NaN;return new cn(u<0?u+360:u,f,o,t.opacity)}function ee(t,e,r,n){return arguments.length===1?s2(t):new cn(t,e,r,n??1)}function cn(t,e,r,n){this.h=+t,this.s=+e,this.l=+r,this.opacity=+n}gr(cn,ee,kr(Xe,{brighter(t){return t=t==null?un:Math.pow(un,t),new cn(this.h,this.s,this.l*t,this.opacity)},darker(t){return t=t==null?Ir:Math.pow(Ir,t),new cn(this.h,this.s,this.l*t,this.opacity)},rgb(){var t=isNaN(this.h)?0:(this.h+120)*lf,e=+this.l,r=isNaN(this.s)?0:this.s*e*(1-e),n=Math.cos(t),o=Math.sin(t);return new Tt(255*(e+r*(Kd*n+Ns*o)),255*(e+r*(Ds*n+df*o)),255*(e+r*(fi*n)),this.opacity)}}));function Rs(t,e,r,n,o){var i=t*t,a=i*t;return((1-3*t+3*i-a)*e+(4-6*i+3*a)*r+(1+3*t+3*i-3*a)*n+a*o)/6}function Ps(t){var e=t.length-1;return function(r){var n=r<=0?r=0:r>=1?(r=1,e-1):Math.floor(r*e),o=t[n],i=t[n+1],a=n>0?t[n-1]:2*o-i,f=n<e-1?t[n+2]:2*i-o;return Rs((r-n/e)*e,a,o,i,f)}}function Ls(t){var e=t.length;return function(r){var n=Math.floor(((r%=1)<0?++r:r)*e),o=t[(n+e-1)%e],i=t[n%e],a=t[(n+1)%e],f=t[(n+2)%e];return Rs((r-n/e)*e,o,i,a,f)}}var jn=t=>()=>t;function Zd(t,e){return function(r){return t+r*e}}function l2(t,e,r){return t=Math.pow(t,r),e=Math.pow(e,r)-t,r=1/r,function(n){return Math.pow(t+n*e,r)}}function sn(t,e){var r=e-t;return r?Zd(t,r>180||r<-180?r-360*Math.round(r/360):r):jn(isNaN(t)?e:t)}function Qd(t){return(t=+t)==1?xt:function(e,r){return r-e?l2(e,r,t):jn(isNaN(e)?r:e)}}function xt(t,e){var r=e-t;return r?Zd(t,r):jn(isNaN(t)?e:t)}var Me=function t(e){var r=Qd(e);function n(o,i){var a=r((o=ve(o)).r,(i=ve(i)).r),f=r(o.g,i.g),u=r(o.b,i.b),c=xt(o.opacity,i.opacity);return function(s){return o.r=a(s),o.g=f(s),o.b=u(s),o.opacity=c(s),o+""}}return n.gamma=t,n}(1);function Jd(t){return function(e){var r=e.length,n=new Array(r),o=new Array(r),i=new Array(r),a,f;for(a=0;a<r;++a)f=ve(e[a]),n[a]=f.r||0,o[a]=f.g||0,i[a]=f.b||0;return n=t(n),o=t(o),i=t(i),f.opacity=1,function(u){return f.r=n(u),f.g=o(u),f.b=i(u),f+""}}}var Fs=Jd(Ps),p2=Jd(Ls);function hf(t,e){e||(e=[]);var r=t?Math.min(e.length,t.length):0,n=e.slice(),o;return function(i){for(o=0;o<r;++o)n[o]=t[o]*(1-i)+e[o]*i;return n}}function qs(t){return ArrayBuffer.isView(t)&&!(t instanceof DataView)}function th(t,e){var r=e?e.length:0,n=t?Math.min(r,t.length):0,o=new Array(n),i=new Array(r),a;for(a=0;a<n;++a)o[a]=se(t[a],e[a]);for(;a<r;++a)i[a]=e[a];return function(f){for(a=0;a<n;++a)i[a]=o[a](f);return i}}function Os(t,e){var r=new Date;return t=+t,e=+e,function(n){return r.setTime(t*(1-n)+e*n),r}}function ht(t,e){return t=+t,e=+e,function(r){return t*(1-r)+e*r}}function Ys(t,e){var r={},n={},o;(t===null||typeof t!="object")&&(t={}),(e===null||typeof e!="object")&&(e={});for(o in e)o in t?r[o]=se(t[o],e[o]):n[o]=e[o];return function(i){for(o in r)n[o]=r[o](i);return n}}var zs=/[-+]?(?:\d+\.?\d*|\.?\d+)(?:[eE][-+]?\d+)?/g,Bs=new RegExp(zs.source,"g");function m2(t){return function(){return t}}function d2(t){return function(e){return t(e)+""}}function ui(t,e){var r=zs.lastIndex=Bs.lastIndex=0,n,o,i,a=-1,f=[],u=[];for(t=t+"",e=e+"";(n=zs.exec(t))&&(o=Bs.exec(e));)(i=o.index)>r&&(i=e.slice(r,i),f[a]?f[a]+=i:f[++a]=i),(n=n[0])===(o=o[0])?f[a]?f[a]+=o:f[++a]=o:(f[++a]=null,u.push({i:a,x:ht(n,o)})),r=Bs.lastIndex;return r<e.length&&(i=e.slice(r),f[a]?f[a]+=i:f[++a]=i),f.length<2?u[0]?d2(u[0].x):m2(e):(e=u.length,function(c){for(var s=0,p;s<e;++s)f[(p=u[s]).i]=p.x(c);return f.join("")})}function se(t,e){var r=typeof e,n;return e==null||r==="boolean"?jn(e):(r==="number"?ht:r==="string"?(n=ce(e))?(e=n,Me):ui:e instanceof ce?Me:e instanceof Date?Os:qs(e)?hf:Array.isArray(e)?th:typeof e.valueOf!="function"&&typeof e.toString!="function"||isNaN(e)?Ys:ht)(t,e)}function Nr(t,e){return t=+t,e=+e,function(r){return Math.round(t*(1-r)+e*r)}}var eh=180/Math.PI,gf={translateX:0,translateY:0,rotate:0,skewX:0,scaleX:1,scaleY:1};function Us(t,e,r,n,o,i){var a,f,u;return(a=Math.sqrt(t*t+e*e))&&(t/=a,e/=a),(u=t*r+e*n)&&(r-=t*u,n-=e*u),(f=Math.sqrt(r*r+n*n))&&(r/=f,n/=f,u/=f),t*n<e*r&&(t=-t,e=-e,u=-u,a=-a),{translateX:o,translateY:i,rotate:Math.atan2(e,t)*eh,skewX:Math.atan(u)*eh,scaleX:a,scaleY:f}}var xf;function rh(t){let e=new(typeof DOMMatrix=="function"?DOMMatrix:WebKitCSSMatrix)(t+"");return e.isIdentity?gf:Us(e.a,e.b,e.c,e.d,e.e,e.f)}function nh(t){return t==null?gf:(xf||(xf=document.createElementNS("http://www.w3.org/2000/svg","g")),xf.setAttribute("transform",t),(t=xf.transform.baseVal.consolidate())?(t=t.matrix,Us(t.a,t.b,t.c,t.d,t.e,t.f)):gf)}function oh(t,e,r,n){function o(c){return c.length?c.pop()+" ":""}function i(c,s,p,l,m,d){if(c!==p||s!==l){var h=m.push("translate(",null,e,null,r);d.push({i:h-4,x:ht(c,p)},{i:h-2,x:ht(s,l)})}else(p||l)&&m.push("translate("+p+e+l+r)}function a(c,s,p,l){c!==s?(c-s>180?s+=360:s-c>180&&(c+=360),l.push({i:p.push(o(p)+"rotate(",null,n)-2,x:ht(c,s)})):s&&p.push(o(p)+"rotate("+s+n)}function f(c,s,p,l){c!==s?l.push({i:p.push(o(p)+"skewX(",null,n)-2,x:ht(c,s)}):s&&p.push(o(p)+"skewX("+s+n)}function u(c,s,p,l,m,d){if(c!==p||s!==l){var h=m.push(o(m)+"scale(",null,",",null,")");d.push({i:h-4,x:ht(c,p)},{i:h-2,x:ht(s,l)})}else(p!==1||l!==1)&&m.push(o(m)+"scale("+p+","+l+")")}return function(c,s){var p=[],l=[];return c=t(c),s=t(s),i(c.translateX,c.translateY,s.translateX,s.translateY,p,l),a(c.rotate,s.rotate,p,l),f(c.skewX,s.skewX,p,l),u(c.scaleX,c.scaleY,s.scaleX,s.scaleY,p,l),c=s=null,function(m){for(var d=-1,h=l.length,g;++d<h;)p[(g=l[d]).i]=g.x(m);return p.join("")}}}var Ws=oh(rh,"px, ","px)","deg)"),Hs=oh(nh,", ",")",")");function ih(t){return function(e,r){var n=t((e=ii(e)).h,(r=ii(r)).h),o=xt(e.s,r.s),i=xt(e.l,r.l),a=xt(e.opacity,r.opacity);return function(f){return e.h=n(f),e.s=o(f),e.l=i(f),e.opacity=a(f),e+""}}}var Gs=ih(sn),h2=ih(xt);function yf(t,e){var r=xt((t=Vn(t)).l,(e=Vn(e)).l),n=xt(t.a,e.a),o=xt(t.b,e.b),i=xt(t.opacity,e.opacity);return function(a){return t.l=r(a),t.a=n(a),t.b=o(a),t.opacity=i(a),t+""}}function ah(t){return function(e,r){var n=t((e=ai(e)).h,(r=ai(r)).h),o=xt(e.c,r.c),i=xt(e.l,r.l),a=xt(e.opacity,r.opacity);return function(f){return e.h=n(f),e.c=o(f),e.l=i(f),e.opacity=a(f),e+""}}}var Xs=ah(sn),g2=ah(xt);function fh(t){return function e(r){r=+r;function n(o,i){var a=t((o=ee(o)).h,(i=ee(i)).h),f=xt(o.s,i.s),u=xt(o.l,i.l),c=xt(o.opacity,i.opacity);return function(s){return o.h=a(s),o.s=f(s),o.l=u(Math.pow(s,r)),o.opacity=c(s),o+""}}return n.gamma=e,n}(1)}var x2=fh(sn),Kn=fh(xt);function je(t,e){e===void 0&&(e=t,t=se);for(var r=0,n=e.length-1,o=e[0],i=new Array(n<0?0:n);r<n;)i[r]=t(o,o=e[++r]);return function(a){var f=Math.max(0,Math.min(n-1,Math.floor(a*=n)));return i[f](a-f)}}function Gt(t,e){for(var r=new Array(e),n=0;n<e;++n)r[n]=t(n/(e-1));return r}var Zn=0,si=0,ci=0,ch=1e3,bf,li,wf=0,ln=0,vf=0,pi=typeof performance=="object"&&performance.now?performance:Date,sh=typeof window=="object"&&window.requestAnimationFrame?window.requestAnimationFrame.bind(window):function(t){setTimeout(t,17)};function di(){return ln||(sh(y2),ln=pi.now()+vf)}function y2(){ln=0}function mi(){this._call=this._time=this._next=null}mi.prototype=Mf.prototype={constructor:mi,restart:function(t,e,r){if(typeof t!="function")throw new TypeError("callback is not a function");r=(r==null?di():+r)+(e==null?0:+e),!this._next&&li!==this&&(li?li._next=this:bf=this,li=this),this._call=t,this._time=r,Vs()},stop:function(){this._call&&(this._call=null,this._time=1/0,Vs())}};function Mf(t,e,r){var n=new mi;return n.restart(t,e,r),n}function b2(){di(),++Zn;for(var t=bf,e;t;)(e=ln-t._time)>=0&&t._call.call(void 0,e),t=t._next;--Zn}function uh(){ln=(wf=pi.now())+vf,Zn=si=0;try{b2()}finally{Zn=0,v2(),ln=0}}function w2(){var t=pi.now(),e=t-wf;e>ch&&(vf-=e,wf=t)}function v2(){for(var t,e=bf,r,n=1/0;e;)e._call?(n>e._time&&(n=e._time),t=e,e=e._next):(r=e._next,e._next=null,e=t?t._next=r:bf=r);li=t,Vs(n)}function Vs(t){if(!Zn){si&&(si=clearTimeout(si));var e=t-ln;e>24?(t<1/0&&(si=setTimeout(uh,t-pi.now()-vf)),ci&&(ci=clearInterval(ci))):(ci||(wf=pi.now(),ci=setInterval(w2,ch)),Zn=1,sh(uh))}}function Sf(t,e,r){var n=new mi;return e=e==null?0:+e,n.restart(o=>{n.stop(),t(o+e)},e,r),n}var M2=ef("start","end","cancel","interrupt"),S2=[],ph=0,js=1,_f=2,Tf=3,lh=4,Cf=5,hi=6;function Dr(t,e,r,n,o,i){var a=t.__transition;if(!a)t.__transition={};else if(r in a)return;T2(t,r,{name:e,index:n,group:o,on:M2,tween:S2,time:i.time,delay:i.delay,duration:i.duration,ease:i.ease,timer:null,state:ph})}function gi(t,e){var r=$t(t,e);if(r.state>ph)throw new Error("too late; already scheduled");return r}function Ot(t,e){var r=$t(t,e);if(r.state>Tf)throw new Error("too late; already running");return r}function $t(t,e){var r=t.__transition;if(!r||!(r=r[e]))throw new Error("transition not found");return r}function T2(t,e,r){var n=t.__transition,o;n[e]=r,r.timer=Mf(i,0,r.time);function i(c){r.state=js,r.timer.restart(a,r.delay,r.time),r.delay<=c&&a(c-r.delay)}function a(c){var s,p,l,m;if(r.state!==js)return u();for(s in n)if(m=n[s],m.name===r.name){if(m.state===Tf)return Sf(a);m.state===lh?(m.state=hi,m.timer.stop(),m.on.call("interrupt",t,t.__data__,m.index,m.group),delete n[s]):+s<e&&(m.state=hi,m.timer.stop(),m.on.call("cancel",t,t.__data__,m.index,m.group),delete n[s])}if(Sf(function(){r.state===Tf&&(r.state=lh,r.timer.restart(f,r.delay,r.time),f(c))}),r.state=_f,r.on.call("start",t,t.__data__,r.index,r.group),r.state===_f){for(r.state=Tf,o=new Array(l=r.tween.length),s=0,p=-1;s<l;++s)(m=r.tween[s].value.call(t,t.__data__,r.index,r.group))&&(o[++p]=m);o.length=p+1}}function f(c){for(var s=c<r.duration?r.ease.call(null,c/r.duration):(r.timer.restart(u),r.state=Cf,1),p=-1,l=o.length;++p<l;)o[p].call(t,s);r.state===Cf&&(r.on.call("end",t,t.__data__,r.index,r.group),u())}function u(){r.state=hi,r.timer.stop(),delete n[e];for(var c in n)return;delete t.__transition}}function $f(t,e){var r=t.__transition,n,o,i=!0,a;if(r){e=e==null?null:e+"";for(a in r){if((n=r[a]).name!==e){i=!1;continue}o=n.state>_f&&n.state<Cf,n.state=hi,n.timer.stop(),n.on.call(o?"interrupt":"cancel",t,t.__data__,n.index,n.group),delete r[a]}i&&delete t.__transition}}function mh(t){return this.each(function(){$f(this,t)})}function _2(t,e){var r,n;return function(){var o=Ot(this,t),i=o.tween;if(i!==r){n=r=i;for(var a=0,f=n.length;a<f;++a)if(n[a].name===e){n=n.slice(),n.splice(a,1);break}}o.tween=n}}function C2(t,e,r){var n,o;if(typeof r!="function")throw new Error;return function(){var i=Ot(this,t),a=i.tween;if(a!==n){o=(n=a).slice();for(var f={name:e,value:r},u=0,c=o.length;u<c;++u)if(o[u].name===e){o[u]=f;break}u===c&&o.push(f)}i.tween=o}}function dh(t,e){var r=this._id;if(t+="",arguments.length<2){for(var n=$t(this.node(),r).tween,o=0,i=n.length,a;o<i;++o)if((a=n[o]).name===t)return a.value;return null}return this.each((e==null?_2:C2)(r,t,e))}function Qn(t,e,r){var n=t._id;return t.each(function(){var o=Ot(this,n);(o.value||(o.value={}))[e]=r.apply(this,arguments)}),function(o){return $t(o,n).value[e]}}function Af(t,e){var r;return(typeof e=="number"?ht:e instanceof ce?Me:(r=ce(e))?(e=r,Me):ui)(t,e)}function $2(t){return function(){this.removeAttribute(t)}}function A2(t){return function(){this.removeAttributeNS(t.space,t.local)}}function E2(t,e,r){var n,o=r+"",i;return function(){var a=this.getAttribute(t);return a===o?null:a===n?i:i=e(n=a,r)}}function k2(t,e,r){var n,o=r+"",i;return function(){var a=this.getAttributeNS(t.space,t.local);return a===o?null:a===n?i:i=e(n=a,r)}}function I2(t,e,r){var n,o,i;return function(){var a,f=r(this),u;return f==null?void this.removeAttribute(t):(a=this.getAttribute(t),u=f+"",a===u?null:a===n&&u===o?i:(o=u,i=e(n=a,f)))}}function N2(t,e,r){var n,o,i;return function(){var a,f=r(this),u;return f==null?void this.removeAttributeNS(t.space,t.local):(a=this.getAttributeNS(t.space,t.local),u=f+"",a===u?null:a===n&&u===o?i:(o=u,i=e(n=a,f)))}}function hh(t,e){var r=dr(t),n=r==="transform"?Hs:Af;return this.attrTween(t,typeof e=="function"?(r.local?N2:I2)(r,n,Qn(this,"attr."+t,e)):e==null?(r.local?A2:$2)(r):(r.local?k2:E2)(r,n,e))}function D2(t,e){return function(r){this.setAttribute(t,e.call(this,r))}}function R2(t,e){return function(r){this.setAttributeNS(t.space,t.local,e.call(this,r))}}function P2(t,e){var r,n;function o(){var i=e.apply(this,arguments);return i!==n&&(r=(n=i)&&R2(t,i)),r}return o._value=e,o}function L2(t,e){var r,n;function o(){var i=e.apply(this,arguments);return i!==n&&(r=(n=i)&&D2(t,i)),r}return o._value=e,o}function gh(t,e){var r="attr."+t;if(arguments.length<2)return(r=this.tween(r))&&r._value;if(e==null)return this.tween(r,null);if(typeof e!="function")throw new Error;var n=dr(t);return this.tween(r,(n.local?P2:L2)(n,e))}function F2(t,e){return function(){gi(this,t).delay=+e.apply(this,arguments)}}function q2(t,e){return e=+e,function(){gi(this,t).delay=e}}function xh(t){var e=this._id;return arguments.length?this.each((typeof t=="function"?F2:q2)(e,t)):$t(this.node(),e).delay}function O2(t,e){return function(){Ot(this,t).duration=+e.apply(this,arguments)}}function Y2(t,e){return e=+e,function(){Ot(this,t).duration=e}}function yh(t){var e=this._id;return arguments.length?this.each((typeof t=="function"?O2:Y2)(e,t)):$t(this.node(),e).duration}function B2(t,e){if(typeof e!="function")throw new Error;return function(){Ot(this,t).ease=e}}function bh(t){var e=this._id;return arguments.length?this.each(B2(e,t)):$t(this.node(),e).ease}function z2(t,e){return function(){var r=e.apply(this,arguments);if(typeof r!="function")throw new Error;Ot(this,t).ease=r}}function wh(t){if(typeof t!="function")throw new Error;return this.each(z2(this._id,t))}function vh(t){typeof t!="function"&&(t=Jo(t));for(var e=this._groups,r=e.length,n=new Array(r),o=0;o<r;++o)for(var i=e[o],a=i.length,f=n[o]=[],u,c=0;c<a;++c)(u=i[c])&&t.call(u,u.__data__,c,i)&&f.push(u);return new Xt(n,this._parents,this._name,this._id)}function Mh(t){if(t._id!==this._id)throw new Error;for(var e=this._groups,r=t._groups,n=e.length,o=r.length,i=Math.min(n,o),a=new Array(n),f=0;f<i;++f)for(var u=e[f],c=r[f],s=u.length,p=a[f]=new Array(s),l,m=0;m<s;++m)(l=u[m]||c[m])&&(p[m]=l);for(;f<n;++f)a[f]=e[f];return new Xt(a,this._parents,this._name,this._id)}function U2(t){return(t+"").trim().split(/^|\s+/).every(function(e){var r=e.indexOf(".");return r>=0&&(e=e.slice(0,r)),!e||e==="start"})}function W2(t,e,r){var n,o,i=U2(e)?gi:Ot;return function(){var a=i(this,t),f=a.on;f!==n&&(o=(n=f).copy()).on(e,r),a.on=o}}function Sh(t,e){var r=this._id;return arguments.length<2?$t(this.node(),r).on.on(t):this.each(W2(r,t,e))}function H2(t){return function(){var e=this.parentNode;for(var r in this.__transition)if(+r!==t)return;e&&e.removeChild(this)}}function Th(){return this.on("end.remove",H2(this._id))}function _h(t){var e=this._name,r=this._id;typeof t!="function"&&(t=on(t));for(var n=this._groups,o=n.length,i=new Array(o),a=0;a<o;++a)for(var f=n[a],u=f.length,c=i[a]=new Array(u),s,p,l=0;l<u;++l)(s=f[l])&&(p=t.call(s,s.__data__,l,f))&&("__data__"in s&&(p.__data__=s.__data__),c[l]=p,Dr(c[l],e,r,l,c,$t(s,r)));return new Xt(i,this._parents,e,r)}function Ch(t){var e=this._name,r=this._id;typeof t!="function"&&(t=Qo(t));for(var n=this._groups,o=n.length,i=[],a=[],f=0;f<o;++f)for(var u=n[f],c=u.length,s,p=0;p<c;++p)if(s=u[p]){for(var l=t.call(s,s.__data__,p,u),m,d=$t(s,r),h=0,g=l.length;h<g;++h)(m=l[h])&&Dr(m,e,r,h,l,d);i.push(l),a.push(s)}return new Xt(i,a,e,r)}var G2=hr.prototype.constructor;function $h(){return new G2(this._groups,this._parents)}function X2(t,e){var r,n,o;return function(){var i=Er(this,t),a=(this.style.removeProperty(t),Er(this,t));return i===a?null:i===r&&a===n?o:o=e(r=i,n=a)}}function Ah(t){return function(){this.style.removeProperty(t)}}function V2(t,e,r){var n,o=r+"",i;return function(){var a=Er(this,t);return a===o?null:a===n?i:i=e(n=a,r)}}function j2(t,e,r){var n,o,i;return function(){var a=Er(this,t),f=r(this),u=f+"";return f==null&&(u=f=(this.style.removeProperty(t),Er(this,t))),a===u?null:a===n&&u===o?i:(o=u,i=e(n=a,f))}}function K2(t,e){var r,n,o,i="style."+e,a="end."+i,f;return function(){var u=Ot(this,t),c=u.on,s=u.value[i]==null?f||(f=Ah(e)):void 0;(c!==r||o!==s)&&(n=(r=c).copy()).on(a,o=s),u.on=n}}function Eh(t,e,r){var n=(t+="")=="transform"?Ws:Af;return e==null?this.styleTween(t,X2(t,n)).on("end.style."+t,Ah(t)):typeof e=="function"?this.styleTween(t,j2(t,n,Qn(this,"style."+t,e))).each(K2(this._id,t)):this.styleTween(t,V2(t,n,e),r).on("end.style."+t,null)}function Z2(t,e,r){return function(n){this.style.setProperty(t,e.call(this,n),r)}}function Q2(t,e,r){var n,o;function i(){var a=e.apply(this,arguments);return a!==o&&(n=(o=a)&&Z2(t,a,r)),n}return i._value=e,i}function kh(t,e,r){var n="style."+(t+="");if(arguments.length<2)return(n=this.tween(n))&&n._value;if(e==null)return this.tween(n,null);if(typeof e!="function")throw new Error;return this.tween(n,Q2(t,e,r??""))}function J2(t){return function(){this.textContent=t}}function tw(t){return function(){var e=t(this);this.textContent=e??""}}function Ih(t){return this.tween("text",typeof t=="function"?tw(Qn(this,"text",t)):J2(t==null?"":t+""))}function ew(t){return function(e){this.textContent=t.call(this,e)}}function rw(t){var e,r;function n(){var o=t.apply(this,arguments);return o!==r&&(e=(r=o)&&ew(o)),e}return n._value=t,n}function Nh(t){var e="text";if(arguments.length<1)return(e=this.tween(e))&&e._value;if(t==null)return this.tween(e,null);if(typeof t!="function")throw new Error;return this.tween(e,rw(t))}function Dh(){for(var t=this._name,e=this._id,r=Ef(),n=this._groups,o=n.length,i=0;i<o;++i)for(var a=n[i],f=a.length,u,c=0;c<f;++c)if(u=a[c]){var s=$t(u,e);Dr(u,t,r,c,a,{time:s.time+s.delay+s.duration,delay:0,duration:s.duration,ease:s.ease})}return new Xt(n,this._parents,t,r)}function Rh(){var t,e,r=this,n=r._id,o=r.size();return new Promise(function(i,a){var f={value:a},u={value:function(){--o===0&&i()}};r.each(function(){var c=Ot(this,n),s=c.on;s!==t&&(e=(t=s).copy(),e._.cancel.push(f),e._.interrupt.push(f),e._.end.push(u)),c.on=e}),o===0&&i()})}var nw=0;function Xt(t,e,r,n){this._groups=t,this._parents=e,this._name=r,this._id=n}function Ks(t){return hr().transition(t)}function Ef(){return++nw}var yr=hr.prototype;Xt.prototype=Ks.prototype={constructor:Xt,select:_h,selectAll:Ch,selectChild:yr.selectChild,selectChildren:yr.selectChildren,filter:vh,merge:Mh,selection:$h,transition:Dh,call:yr.call,nodes:yr.nodes,node:yr.node,size:yr.size,empty:yr.empty,each:yr.each,on:Sh,attr:hh,attrTween:gh,style:Eh,styleTween:kh,text:Ih,textTween:Nh,remove:Th,tween:dh,delay:xh,duration:yh,ease:bh,easeVarying:wh,end:Rh,[Symbol.iterator]:yr[Symbol.iterator]};function Zs(t){return((t*=2)<=1?t*t*t:(t-=2)*t*t+2)/2}var ow={time:null,delay:0,duration:250,ease:Zs};function iw(t,e){for(var r;!(r=t.__transition)||!(r=r[e]);)if(!(t=t.parentNode))throw new Error(`transition ${e} not found`);return r}function Ph(t){var e,r;t instanceof Xt?(e=t._id,t=t._name):(e=Ef(),(r=ow).time=di(),t=t==null?null:t+"");for(var n=this._groups,o=n.length,i=0;i<o;++i)for(var a=n[i],f=a.length,u,c=0;c<f;++c)(u=a[c])&&Dr(u,t,e,c,a,r||iw(u,e));return new Xt(n,this._parents,t,e)}hr.prototype.interrupt=mh;hr.prototype.transition=Ph;var{abs:sA,max:lA,min:pA}=Math;function Lh(t){return[+t[0],+t[1]]}function aw(t){return[Lh(t[0]),Lh(t[1])]}var mA={name:"x",handles:["w","e"].map(Qs),input:function(t,e){return t==null?null:[[+t[0],e[0][1]],[+t[1],e[1][1]]]},output:function(t){return t&&[t[0][0],t[1][0]]}},dA={name:"y",handles:["n","s"].map(Qs),input:function(t,e){return t==null?null:[[e[0][0],+t[0]],[e[1][0],+t[1]]]},output:function(t){return t&&[t[0][1],t[1][1]]}},hA={name:"xy",handles:["n","w","e","s","nw","ne","sw","se"].map(Qs),input:function(t){return t==null?null:aw(t)},output:function(t){return t}};function Qs(t){return{type:t}}var Js=Math.PI,tl=2*Js,pn=1e-6,fw=tl-pn;function Fh(t){this._+=t[0];for(let e=1,r=t.length;e<r;++e)this._+=arguments[e]+t[e]}function uw(t){let e=Math.floor(t);if(!(e>=0))throw new Error(`invalid digits: ${t}`);if(e>15)return Fh;let r=10**e;return function(n){this._+=n[0];for(let o=1,i=n.length;o<i;++o)this._+=Math.round(arguments[o]*r)/r+n[o]}}var mn=class{constructor(e){this._x0=this._y0=this._x1=this._y1=null,this._="",this._append=e==null?Fh:uw(e)}moveTo(e,r){this._append`M${this._x0=this._x1=+e},${this._y0=this._y1=+r}`}closePath(){this._x1!==null&&(this._x1=this._x0,this._y1=this._y0,this._append`Z`)}lineTo(e,r){this._append`L${this._x1=+e},${this._y1=+r}`}quadraticCurveTo(e,r,n,o){this._append`Q${+e},${+r},${this._x1=+n},${this._y1=+o}`}bezierCurveTo(e,r,n,o,i,a){this._append`C${+e},${+r},${+n},${+o},${this._x1=+i},${this._y1=+a}`}arcTo(e,r,n,o,i){if(e=+e,r=+r,n=+n,o=+o,i=+i,i<0)throw new Error(`negative radius: ${i}`);let a=this._x1,f=this._y1,u=n-e,c=o-r,s=a-e,p=f-r,l=s*s+p*p;if(this._x1===null)this._append`M${this._x1=e},${this._y1=r}`;else if(l>pn)if(!(Math.abs(p*u-c*s)>pn)||!i)this._append`L${this._x1=e},${this._y1=r}`;else{let m=n-a,d=o-f,h=u*u+c*c,g=m*m+d*d,b=Math.sqrt(h),x=Math.sqrt(l),M=i*Math.tan((Js-Math.acos((h+l-g)/(2*b*x)))/2),y=M/x,w=M/b;Math.abs(y-1)>pn&&this._append`L${e+y*s},${r+y*p}`,this._append`A${i},${i},0,0,${+(p*m>s*d)},${this._x1=e+w*u},${this._y1=r+w*c}`}}arc(e,r,n,o,i,a){if(e=+e,r=+r,n=+n,a=!!a,n<0)throw new Error(`negative radius: ${n}`);let f=n*Math.cos(o),u=n*Math.sin(o),c=e+f,s=r+u,p=1^a,l=a?o-i:i-o;this._x1===null?this._append`M${c},${s}`:(Math.abs(this._x1-c)>pn||Math.abs(this._y1-s)>pn)&&this._append`L${c},${s}`,n&&(l<0&&(l=l%tl+tl),l>fw?this._append`A${n},${n},0,1,${p},${e-f},${r-u}A${n},${n},0,1,${p},${this._x1=c},${this._y1=s}`:l>pn&&this._append`A${n},${n},0,${+(l>=Js)},${p},${this._x1=e+n*Math.cos(i)},${this._y1=r+n*Math.sin(i)}`)}rect(e,r,n,o){this._append`M${this._x0=this._x1=+e},${this._y0=this._y1=+r}h${n=+n}v${+o}h${-n}Z`}toString(){return this._}};function cw(){return new mn}cw.prototype=mn.prototype;function Jn(t=3){return new mn(+t)}function qh(t){return Math.abs(t=Math.round(t))>=1e21?t.toLocaleString("en").replace(/,/g,""):t.toString(10)}function dn(t,e){if((r=(t=e?t.toExponential(e-1):t.toExponential()).indexOf("e"))<0)return null;var r,n=t.slice(0,r);return[n.length>1?n[0]+n.slice(2):n,+t.slice(r+1)]}function Ke(t){return t=dn(Math.abs(t)),t?t[1]:NaN}function Oh(t,e){return function(r,n){for(var o=r.length,i=[],a=0,f=t[0],u=0;o>0&&f>0&&(u+f+1>n&&(f=Math.max(1,n-u)),i.push(r.substring(o-=f,o+f)),!((u+=f+1)>n));)f=t[a=(a+1)%t.length];return i.reverse().join(e)}}function Yh(t){return function(e){return e.replace(/[0-9]/g,function(r){return t[+r]})}}var sw=/^(?:(.)?([<>=^]))?([+\-( ])?([$#])?(0)?(\d+)?(,)?(\.\d+)?(~)?([a-z%])?$/i;function Ze(t){if(!(e=sw.exec(t)))throw new Error("invalid format: "+t);var e;return new el({fill:e[1],align:e[2],sign:e[3],symbol:e[4],zero:e[5],width:e[6],comma:e[7],precision:e[8]&&e[8].slice(1),trim:e[9],type:e[10]})}Ze.prototype=el.prototype;function el(t){this.fill=t.fill===void 0?" ":t.fill+"",this.align=t.align===void 0?">":t.align+"",this.sign=t.sign===void 0?"-":t.sign+"",this.symbol=t.symbol===void 0?"":t.symbol+"",this.zero=!!t.zero,this.width=t.width===void 0?void 0:+t.width,this.comma=!!t.comma,this.precision=t.precision===void 0?void 0:+t.precision,this.trim=!!t.trim,this.type=t.type===void 0?"":t.type+""}el.prototype.toString=function(){return this.fill+this.align+this.sign+this.symbol+(this.zero?"0":"")+(this.width===void 0?"":Math.max(1,this.width|0))+(this.comma?",":"")+(this.precision===void 0?"":"."+Math.max(0,this.precision|0))+(this.trim?"~":"")+this.type};function Bh(t){t:for(var e=t.length,r=1,n=-1,o;r<e;++r)switch(t[r]){case".":n=o=r;break;case"0":n===0&&(n=r),o=r;break;default:if(!+t[r])break t;n>0&&(n=0);break}return n>0?t.slice(0,n)+t.slice(o+1):t}var rl;function zh(t,e){var r=dn(t,e);if(!r)return t+"";var n=r[0],o=r[1],i=o-(rl=Math.max(-8,Math.min(8,Math.floor(o/3)))*3)+1,a=n.length;return i===a?n:i>a?n+new Array(i-a+1).join("0"):i>0?n.slice(0,i)+"."+n.slice(i):"0."+new Array(1-i).join("0")+dn(t,Math.max(0,e+i-1))[0]}function nl(t,e){var r=dn(t,e);if(!r)return t+"";var n=r[0],o=r[1];return o<0?"0."+new Array(-o).join("0")+n:n.length>o+1?n.slice(0,o+1)+"."+n.slice(o+1):n+new Array(o-n.length+2).join("0")}var ol={"%":(t,e)=>(t*100).toFixed(e),b:t=>Math.round(t).toString(2),c:t=>t+"",d:qh,e:(t,e)=>t.toExponential(e),f:(t,e)=>t.toFixed(e),g:(t,e)=>t.toPrecision(e),o:t=>Math.round(t).toString(8),p:(t,e)=>nl(t*100,e),r:nl,s:zh,X:t=>Math.round(t).toString(16).toUpperCase(),x:t=>Math.round(t).toString(16)};function il(t){return t}var Uh=Array.prototype.map,Wh=["y","z","a","f","p","n","\xB5","m","","k","M","G","T","P","E","Z","Y"];function Hh(t){var e=t.grouping===void 0||t.thousands===void 0?il:Oh(Uh.call(t.grouping,Number),t.thousands+""),r=t.currency===void 0?"":t.currency[0]+"",n=t.currency===void 0?"":t.currency[1]+"",o=t.decimal===void 0?".":t.decimal+"",i=t.numerals===void 0?il:Yh(Uh.call(t.numerals,String)),a=t.percent===void 0?"%":t.percent+"",f=t.minus===void 0?"\u2212":t.minus+"",u=t.nan===void 0?"NaN":t.nan+"";function c(p){p=Ze(p);var l=p.fill,m=p.align,d=p.sign,h=p.symbol,g=p.zero,b=p.width,x=p.comma,M=p.precision,y=p.trim,w=p.type;w==="n"?(x=!0,w="g"):ol[w]||(M===void 0&&(M=12),y=!0,w="g"),(g||l==="0"&&m==="=")&&(g=!0,l="0",m="=");var v=h==="$"?r:h==="#"&&/[boxX]/.test(w)?"0"+w.toLowerCase():"",T=h==="$"?n:/[%p]/.test(w)?a:"",E=ol[w],k=/[defgprs%]/.test(w);M=M===void 0?6:/[gprs]/.test(w)?Math.max(1,Math.min(21,M)):Math.max(0,Math.min(20,M));function q(_){var R=v,F=T,P,S,$;if(w==="c")F=E(_)+F,_="";else{_=+_;var N=_<0||1/_<0;if(_=isNaN(_)?u:E(Math.abs(_),M),y&&(_=Bh(_)),N&&+_==0&&d!=="+"&&(N=!1),R=(N?d==="("?d:f:d==="-"||d==="("?"":d)+R,F=(w==="s"?Wh[8+rl/3]:"")+F+(N&&d==="("?")":""),k){for(P=-1,S=_.length;++P<S;)if($=_.charCodeAt(P),48>$||$>57){F=($===46?o+_.slice(P+1):_.slice(P))+F,_=_.slice(0,P);break}}}x&&!g&&(_=e(_,1/0));var L=R.length+_.length+F.length,D=L<b?new Array(b-L+1).join(l):"";switch(x&&g&&(_=e(D+_,D.length?b-F.length:1/0),D=""),m){case"<":_=R+_+F+D;break;case"=":_=R+D+_+F;break;case"^":_=D.slice(0,L=D.length>>1)+R+_+F+D.slice(L);break;default:_=D+R+_+F;break}return i(_)}return q.toString=function(){return p+""},q}function s(p,l){var m=c((p=Ze(p),p.type="f",p)),d=Math.max(-8,Math.min(8,Math.floor(Ke(l)/3)))*3,h=Math.pow(10,-d),g=Wh[8+d/3];return function(b){return m(h*b)+g}}return{format:c,formatPrefix:s}}var kf,Qe,If;Gh({thousands:",",grouping:[3],currency:["$",""]});function Gh(t){return kf=Hh(t),Qe=kf.format,If=kf.formatPrefix,kf}function al(t){return Math.max(0,-Ke(Math.abs(t)))}function fl(t,e){return Math.max(0,Math.max(-8,Math.min(8,Math.floor(Ke(e)/3)))*3-Ke(Math.abs(t)))}function ul(t,e){return t=Math.abs(t),e=Math.abs(e)-t,Math.max(0,Ke(e)-Ke(t))+1}var U=1e-6,Nf=1e-12,V=Math.PI,ft=V/2,cl=V/4,Dt=V*2,At=180/V,ut=V/180,Z=Math.abs,Se=Math.atan,Yt=Math.atan2,z=Math.cos;var Df=Math.exp;var hn=Math.log,Rf=Math.pow,B=Math.sin,Vt=Math.sign||function(t){return t>0?1:t<0?-1:0},dt=Math.sqrt,to=Math.tan;function Pf(t){return t>1?0:t<-1?V:Math.acos(t)}function Mt(t){return t>1?ft:t<-1?-ft:Math.asin(t)}function Rt(){}function Lf(t,e){t&&Vh.hasOwnProperty(t.type)&&Vh[t.type](t,e)}var Xh={Feature:function(t,e){Lf(t.geometry,e)},FeatureCollection:function(t,e){for(var r=t.features,n=-1,o=r.length;++n<o;)Lf(r[n].geometry,e)}},Vh={Sphere:function(t,e){e.sphere()},Point:function(t,e){t=t.coordinates,e.point(t[0],t[1],t[2])},MultiPoint:function(t,e){for(var r=t.coordinates,n=-1,o=r.length;++n<o;)t=r[n],e.point(t[0],t[1],t[2])},LineString:function(t,e){sl(t.coordinates,e,0)},MultiLineString:function(t,e){for(var r=t.coordinates,n=-1,o=r.length;++n<o;)sl(r[n],e,0)},Polygon:function(t,e){jh(t.coordinates,e)},MultiPolygon:function(t,e){for(var r=t.coordinates,n=-1,o=r.length;++n<o;)jh(r[n],e)},GeometryCollection:function(t,e){for(var r=t.geometries,n=-1,o=r.length;++n<o;)Lf(r[n],e)}};function sl(t,e,r){var n=-1,o=t.length-r,i;for(e.lineStart();++n<o;)i=t[n],e.point(i[0],i[1],i[2]);e.lineEnd()}function jh(t,e){var r=-1,n=t.length;for(e.polygonStart();++r<n;)sl(t[r],e,1);e.polygonEnd()}function De(t,e){t&&Xh.hasOwnProperty(t.type)?Xh[t.type](t,e):Lf(t,e)}function xi(t){return[Yt(t[1],t[0]),Mt(t[2])]}function Je(t){var e=t[0],r=t[1],n=z(r);return[n*z(e),n*B(e),B(r)]}function yi(t,e){return t[0]*e[0]+t[1]*e[1]+t[2]*e[2]}function eo(t,e){return[t[1]*e[2]-t[2]*e[1],t[2]*e[0]-t[0]*e[2],t[0]*e[1]-t[1]*e[0]]}function Ff(t,e){t[0]+=e[0],t[1]+=e[1],t[2]+=e[2]}function bi(t,e){return[t[0]*e,t[1]*e,t[2]*e]}function wi(t){var e=dt(t[0]*t[0]+t[1]*t[1]+t[2]*t[2]);t[0]/=e,t[1]/=e,t[2]/=e}function vi(t,e){function r(n,o){return n=t(n,o),e(n[0],n[1])}return t.invert&&e.invert&&(r.invert=function(n,o){return n=e.invert(n,o),n&&t.invert(n[0],n[1])}),r}function ll(t,e){return Z(t)>V&&(t-=Math.round(t/Dt)*Dt),[t,e]}ll.invert=ll;function qf(t,e,r){return(t%=Dt)?e||r?vi(Zh(t),Qh(e,r)):Zh(t):e||r?Qh(e,r):ll}function Kh(t){return function(e,r){return e+=t,Z(e)>V&&(e-=Math.round(e/Dt)*Dt),[e,r]}}function Zh(t){var e=Kh(t);return e.invert=Kh(-t),e}function Qh(t,e){var r=z(t),n=B(t),o=z(e),i=B(e);function a(f,u){var c=z(u),s=z(f)*c,p=B(f)*c,l=B(u),m=l*r+s*n;return[Yt(p*o-m*i,s*r-l*n),Mt(m*o+p*i)]}return a.invert=function(f,u){var c=z(u),s=z(f)*c,p=B(f)*c,l=B(u),m=l*o-p*i;return[Yt(p*o+l*i,s*r+m*n),Mt(m*r-s*n)]},a}function pl(t){t=qf(t[0]*ut,t[1]*ut,t.length>2?t[2]*ut:0);function e(r){return r=t(r[0]*ut,r[1]*ut),r[0]*=At,r[1]*=At,r}return e.invert=function(r){return r=t.invert(r[0]*ut,r[1]*ut),r[0]*=At,r[1]*=At,r},e}function t0(t,e,r,n,o,i){if(r){var a=z(e),f=B(e),u=n*r;o==null?(o=e+n*Dt,i=e-u/2):(o=Jh(a,o),i=Jh(a,i),(n>0?o<i:o>i)&&(o+=n*Dt));for(var c,s=o;n>0?s>i:s<i;s-=u)c=xi([a,-f*z(s),-f*B(s)]),t.point(c[0],c[1])}}function Jh(t,e){e=Je(e),e[0]-=t,wi(e);var r=Pf(-e[1]);return((-e[2]<0?-r:r)+Dt-U)%Dt}function Of(){var t=[],e;return{point:function(r,n,o){e.push([r,n,o])},lineStart:function(){t.push(e=[])},lineEnd:Rt,rejoin:function(){t.length>1&&t.push(t.pop().concat(t.shift()))},result:function(){var r=t;return t=[],e=null,r}}}function ro(t,e){return Z(t[0]-e[0])<U&&Z(t[1]-e[1])<U}function Yf(t,e,r,n){this.x=t,this.z=e,this.o=r,this.e=n,this.v=!1,this.n=this.p=null}function Bf(t,e,r,n,o){var i=[],a=[],f,u;if(t.forEach(function(d){if(!((h=d.length-1)<=0)){var h,g=d[0],b=d[h],x;if(ro(g,b)){if(!g[2]&&!b[2]){for(o.lineStart(),f=0;f<h;++f)o.point((g=d[f])[0],g[1]);o.lineEnd();return}b[0]+=2*U}i.push(x=new Yf(g,d,null,!0)),a.push(x.o=new Yf(g,null,x,!1)),i.push(x=new Yf(b,d,null,!1)),a.push(x.o=new Yf(b,null,x,!0))}}),!!i.length){for(a.sort(e),e0(i),e0(a),f=0,u=a.length;f<u;++f)a[f].e=r=!r;for(var c=i[0],s,p;;){for(var l=c,m=!0;l.v;)if((l=l.n)===c)return;s=l.z,o.lineStart();do{if(l.v=l.o.v=!0,l.e){if(m)for(f=0,u=s.length;f<u;++f)o.point((p=s[f])[0],p[1]);else n(l.x,l.n.x,1,o);l=l.n}else{if(m)for(s=l.p.z,f=s.length-1;f>=0;--f)o.point((p=s[f])[0],p[1]);else n(l.x,l.p.x,-1,o);l=l.p}l=l.o,s=l.z,m=!m}while(!l.v);o.lineEnd()}}}function e0(t){if(e=t.length){for(var e,r=0,n=t[0],o;++r<e;)n.n=o=t[r],o.p=n,n=o;n.n=o=t[0],o.p=n}}function ml(t){return Z(t[0])<=V?t[0]:Vt(t[0])*((Z(t[0])+V)%Dt-V)}function r0(t,e){var r=ml(e),n=e[1],o=B(n),i=[B(r),-z(r),0],a=0,f=0,u=new fe;o===1?n=ft+U:o===-1&&(n=-ft-U);for(var c=0,s=t.length;c<s;++c)if(l=(p=t[c]).length)for(var p,l,m=p[l-1],d=ml(m),h=m[1]/2+cl,g=B(h),b=z(h),x=0;x<l;++x,d=y,g=v,b=T,m=M){var M=p[x],y=ml(M),w=M[1]/2+cl,v=B(w),T=z(w),E=y-d,k=E>=0?1:-1,q=k*E,_=q>V,R=g*v;if(u.add(Yt(R*k*B(q),b*T+R*z(q))),a+=_?E+k*Dt:E,_^d>=r^y>=r){var F=eo(Je(m),Je(M));wi(F);var P=eo(i,F);wi(P);var S=(_^E>=0?-1:1)*Mt(P[2]);(n>S||n===S&&(F[0]||F[1]))&&(f+=_^E>=0?1:-1)}}return(a<-U||a<U&&u<-Nf)^f&1}function zf(t,e,r,n){return function(o){var i=e(o),a=Of(),f=e(a),u=!1,c,s,p,l={point:m,lineStart:h,lineEnd:g,polygonStart:function(){l.point=b,l.lineStart=x,l.lineEnd=M,s=[],c=[]},polygonEnd:function(){l.point=m,l.lineStart=h,l.lineEnd=g,s=Hn(s);var y=r0(c,n);s.length?(u||(o.polygonStart(),u=!0),Bf(s,pw,y,r,o)):y&&(u||(o.polygonStart(),u=!0),o.lineStart(),r(null,null,1,o),o.lineEnd()),u&&(o.polygonEnd(),u=!1),s=c=null},sphere:function(){o.polygonStart(),o.lineStart(),r(null,null,1,o),o.lineEnd(),o.polygonEnd()}};function m(y,w){t(y,w)&&o.point(y,w)}function d(y,w){i.point(y,w)}function h(){l.point=d,i.lineStart()}function g(){l.point=m,i.lineEnd()}function b(y,w){p.push([y,w]),f.point(y,w)}function x(){f.lineStart(),p=[]}function M(){b(p[0][0],p[0][1]),f.lineEnd();var y=f.clean(),w=a.result(),v,T=w.length,E,k,q;if(p.pop(),c.push(p),p=null,!!T){if(y&1){if(k=w[0],(E=k.length-1)>0){for(u||(o.polygonStart(),u=!0),o.lineStart(),v=0;v<E;++v)o.point((q=k[v])[0],q[1]);o.lineEnd()}return}T>1&&y&2&&w.push(w.pop().concat(w.shift())),s.push(w.filter(lw))}}return l}}function lw(t){return t.length>1}function pw(t,e){return((t=t.x)[0]<0?t[1]-ft-U:ft-t[1])-((e=e.x)[0]<0?e[1]-ft-U:ft-e[1])}var Uf=zf(function(){return!0},mw,hw,[-V,-ft]);function mw(t){var e=NaN,r=NaN,n=NaN,o;return{lineStart:function(){t.lineStart(),o=1},point:function(i,a){var f=i>0?V:-V,u=Z(i-e);Z(u-V)<U?(t.point(e,r=(r+a)/2>0?ft:-ft),t.point(n,r),t.lineEnd(),t.lineStart(),t.point(f,r),t.point(i,r),o=0):n!==f&&u>=V&&(Z(e-n)<U&&(e-=n*U),Z(i-f)<U&&(i-=f*U),r=dw(e,r,i,a),t.point(n,r),t.lineEnd(),t.lineStart(),t.point(f,r),o=0),t.point(e=i,r=a),n=f},lineEnd:function(){t.lineEnd(),e=r=NaN},clean:function(){return 2-o}}}function dw(t,e,r,n){var o,i,a=B(t-r);return Z(a)>U?Se((B(e)*(i=z(n))*B(r)-B(n)*(o=z(e))*B(t))/(o*i*a)):(e+n)/2}function hw(t,e,r,n){var o;if(t==null)o=r*ft,n.point(-V,o),n.point(0,o),n.point(V,o),n.point(V,0),n.point(V,-o),n.point(0,-o),n.point(-V,-o),n.point(-V,0),n.point(-V,o);else if(Z(t[0]-e[0])>U){var i=t[0]<e[0]?V:-V;o=r*i/2,n.point(-i,o),n.point(0,o),n.point(i,o)}else n.point(e[0],e[1])}function dl(t){var e=z(t),r=6*ut,n=e>0,o=Z(e)>U;function i(s,p,l,m){t0(m,t,r,l,s,p)}function a(s,p){return z(s)*z(p)>e}function f(s){var p,l,m,d,h;return{lineStart:function(){d=m=!1,h=1},point:function(g,b){var x=[g,b],M,y=a(g,b),w=n?y?0:c(g,b):y?c(g+(g<0?V:-V),b):0;if(!p&&(d=m=y)&&s.lineStart(),y!==m&&(M=u(p,x),(!M||ro(p,M)||ro(x,M))&&(x[2]=1)),y!==m)h=0,y?(s.lineStart(),M=u(x,p),s.point(M[0],M[1])):(M=u(p,x),s.point(M[0],M[1],2),s.lineEnd()),p=M;else if(o&&p&&n^y){var v;!(w&l)&&(v=u(x,p,!0))&&(h=0,n?(s.lineStart(),s.point(v[0][0],v[0][1]),s.point(v[1][0],v[1][1]),s.lineEnd()):(s.point(v[1][0],v[1][1]),s.lineEnd(),s.lineStart(),s.point(v[0][0],v[0][1],3)))}y&&(!p||!ro(p,x))&&s.point(x[0],x[1]),p=x,m=y,l=w},lineEnd:function(){m&&s.lineEnd(),p=null},clean:function(){return h|(d&&m)<<1}}}function u(s,p,l){var m=Je(s),d=Je(p),h=[1,0,0],g=eo(m,d),b=yi(g,g),x=g[0],M=b-x*x;if(!M)return!l&&s;var y=e*b/M,w=-e*x/M,v=eo(h,g),T=bi(h,y),E=bi(g,w);Ff(T,E);var k=v,q=yi(T,k),_=yi(k,k),R=q*q-_*(yi(T,T)-1);if(!(R<0)){var F=dt(R),P=bi(k,(-q-F)/_);if(Ff(P,T),P=xi(P),!l)return P;var S=s[0],$=p[0],N=s[1],L=p[1],D;$<S&&(D=S,S=$,$=D);var tt=$-S,K=Z(tt-V)<U,G=K||tt<U;if(!K&&L<N&&(D=N,N=L,L=D),G?K?N+L>0^P[1]<(Z(P[0]-S)<U?N:L):N<=P[1]&&P[1]<=L:tt>V^(S<=P[0]&&P[0]<=$)){var Q=bi(k,(-q+F)/_);return Ff(Q,T),[P,xi(Q)]}}}function c(s,p){var l=n?t:V-t,m=0;return s<-l?m|=1:s>l&&(m|=2),p<-l?m|=4:p>l&&(m|=8),m}return zf(a,f,i,n?[0,-t]:[-V,t-V])}function n0(t,e,r,n,o,i){var a=t[0],f=t[1],u=e[0],c=e[1],s=0,p=1,l=u-a,m=c-f,d;if(d=r-a,!(!l&&d>0)){if(d/=l,l<0){if(d<s)return;d<p&&(p=d)}else if(l>0){if(d>p)return;d>s&&(s=d)}if(d=o-a,!(!l&&d<0)){if(d/=l,l<0){if(d>p)return;d>s&&(s=d)}else if(l>0){if(d<s)return;d<p&&(p=d)}if(d=n-f,!(!m&&d>0)){if(d/=m,m<0){if(d<s)return;d<p&&(p=d)}else if(m>0){if(d>p)return;d>s&&(s=d)}if(d=i-f,!(!m&&d<0)){if(d/=m,m<0){if(d>p)return;d>s&&(s=d)}else if(m>0){if(d<s)return;d<p&&(p=d)}return s>0&&(t[0]=a+s*l,t[1]=f+s*m),p<1&&(e[0]=a+p*l,e[1]=f+p*m),!0}}}}}var Mi=1e9,Wf=-Mi;function no(t,e,r,n){function o(c,s){return t<=c&&c<=r&&e<=s&&s<=n}function i(c,s,p,l){var m=0,d=0;if(c==null||(m=a(c,p))!==(d=a(s,p))||u(c,s)<0^p>0)do l.point(m===0||m===3?t:r,m>1?n:e);while((m=(m+p+4)%4)!==d);else l.point(s[0],s[1])}function a(c,s){return Z(c[0]-t)<U?s>0?0:3:Z(c[0]-r)<U?s>0?2:1:Z(c[1]-e)<U?s>0?1:0:s>0?3:2}function f(c,s){return u(c.x,s.x)}function u(c,s){var p=a(c,1),l=a(s,1);return p!==l?p-l:p===0?s[1]-c[1]:p===1?c[0]-s[0]:p===2?c[1]-s[1]:s[0]-c[0]}return function(c){var s=c,p=Of(),l,m,d,h,g,b,x,M,y,w,v,T={point:E,lineStart:R,lineEnd:F,polygonStart:q,polygonEnd:_};function E(S,$){o(S,$)&&s.point(S,$)}function k(){for(var S=0,$=0,N=m.length;$<N;++$)for(var L=m[$],D=1,tt=L.length,K=L[0],G,Q,at=K[0],Ut=K[1];D<tt;++D)G=at,Q=Ut,K=L[D],at=K[0],Ut=K[1],Q<=n?Ut>n&&(at-G)*(n-Q)>(Ut-Q)*(t-G)&&++S:Ut<=n&&(at-G)*(n-Q)<(Ut-Q)*(t-G)&&--S;return S}function q(){s=p,l=[],m=[],v=!0}function _(){var S=k(),$=v&&S,N=(l=Hn(l)).length;($||N)&&(c.polygonStart(),$&&(c.lineStart(),i(null,null,1,c),c.lineEnd()),N&&Bf(l,f,S,i,c),c.polygonEnd()),s=c,l=m=d=null}function R(){T.point=P,m&&m.push(d=[]),w=!0,y=!1,x=M=NaN}function F(){l&&(P(h,g),b&&y&&p.rejoin(),l.push(p.result())),T.point=E,y&&s.lineEnd()}function P(S,$){var N=o(S,$);if(m&&d.push([S,$]),w)h=S,g=$,b=N,w=!1,N&&(s.lineStart(),s.point(S,$));else if(N&&y)s.point(S,$);else{var L=[x=Math.max(Wf,Math.min(Mi,x)),M=Math.max(Wf,Math.min(Mi,M))],D=[S=Math.max(Wf,Math.min(Mi,S)),$=Math.max(Wf,Math.min(Mi,$))];n0(L,D,t,e,r,n)?(y||(s.lineStart(),s.point(L[0],L[1])),s.point(D[0],D[1]),N||s.lineEnd(),v=!1):N&&(s.lineStart(),s.point(S,$),v=!1)}x=S,M=$,y=N}return T}}var Si=t=>t;var hl=new fe,gl=new fe,o0,i0,xl,yl,Rr={point:Rt,lineStart:Rt,lineEnd:Rt,polygonStart:function(){Rr.lineStart=gw,Rr.lineEnd=yw},polygonEnd:function(){Rr.lineStart=Rr.lineEnd=Rr.point=Rt,hl.add(Z(gl)),gl=new fe},result:function(){var t=hl/2;return hl=new fe,t}};function gw(){Rr.point=xw}function xw(t,e){Rr.point=a0,o0=xl=t,i0=yl=e}function a0(t,e){gl.add(yl*t-xl*e),xl=t,yl=e}function yw(){a0(o0,i0)}var bl=Rr;var oo=1/0,Hf=oo,Ti=-oo,Gf=Ti,bw={point:ww,lineStart:Rt,lineEnd:Rt,polygonStart:Rt,polygonEnd:Rt,result:function(){var t=[[oo,Hf],[Ti,Gf]];return Ti=Gf=-(Hf=oo=1/0),t}};function ww(t,e){t<oo&&(oo=t),t>Ti&&(Ti=t),e<Hf&&(Hf=e),e>Gf&&(Gf=e)}var io=bw;var wl=0,vl=0,_i=0,Xf=0,Vf=0,ao=0,Ml=0,Sl=0,Ci=0,c0,s0,tr,er,Re={point:gn,lineStart:f0,lineEnd:u0,polygonStart:function(){Re.lineStart=Sw,Re.lineEnd=Tw},polygonEnd:function(){Re.point=gn,Re.lineStart=f0,Re.lineEnd=u0},result:function(){var t=Ci?[Ml/Ci,Sl/Ci]:ao?[Xf/ao,Vf/ao]:_i?[wl/_i,vl/_i]:[NaN,NaN];return wl=vl=_i=Xf=Vf=ao=Ml=Sl=Ci=0,t}};function gn(t,e){wl+=t,vl+=e,++_i}function f0(){Re.point=vw}function vw(t,e){Re.point=Mw,gn(tr=t,er=e)}function Mw(t,e){var r=t-tr,n=e-er,o=dt(r*r+n*n);Xf+=o*(tr+t)/2,Vf+=o*(er+e)/2,ao+=o,gn(tr=t,er=e)}function u0(){Re.point=gn}function Sw(){Re.point=_w}function Tw(){l0(c0,s0)}function _w(t,e){Re.point=l0,gn(c0=tr=t,s0=er=e)}function l0(t,e){var r=t-tr,n=e-er,o=dt(r*r+n*n);Xf+=o*(tr+t)/2,Vf+=o*(er+e)/2,ao+=o,o=er*t-tr*e,Ml+=o*(tr+t),Sl+=o*(er+e),Ci+=o*3,gn(tr=t,er=e)}var Tl=Re;function jf(t){this._context=t}jf.prototype={_radius:4.5,pointRadius:function(t){return this._radius=t,this},polygonStart:function(){this._line=0},polygonEnd:function(){this._line=NaN},lineStart:function(){this._point=0},lineEnd:function(){this._line===0&&this._context.closePath(),this._point=NaN},point:function(t,e){switch(this._point){case 0:{this._context.moveTo(t,e),this._point=1;break}case 1:{this._context.lineTo(t,e);break}default:{this._context.moveTo(t+this._radius,e),this._context.arc(t,e,this._radius,0,Dt);break}}},result:Rt};var Cl=new fe,_l,p0,m0,$i,Ai,Kf={point:Rt,lineStart:function(){Kf.point=Cw},lineEnd:function(){_l&&d0(p0,m0),Kf.point=Rt},polygonStart:function(){_l=!0},polygonEnd:function(){_l=null},result:function(){var t=+Cl;return Cl=new fe,t}};function Cw(t,e){Kf.point=d0,p0=$i=t,m0=Ai=e}function d0(t,e){$i-=t,Ai-=e,Cl.add(dt($i*$i+Ai*Ai)),$i=t,Ai=e}var $l=Kf;var h0,Zf,g0,x0,fo=class{constructor(e){this._append=e==null?y0:$w(e),this._radius=4.5,this._=""}pointRadius(e){return this._radius=+e,this}polygonStart(){this._line=0}polygonEnd(){this._line=NaN}lineStart(){this._point=0}lineEnd(){this._line===0&&(this._+="Z"),this._point=NaN}point(e,r){switch(this._point){case 0:{this._append`M${e},${r}`,this._point=1;break}case 1:{this._append`L${e},${r}`;break}default:{if(this._append`M${e},${r}`,this._radius!==g0||this._append!==Zf){let n=this._radius,o=this._;this._="",this._append`m0,${n}a${n},${n} 0 1,1 0,${-2*n}a${n},${n} 0 1,1 0,${2*n}z`,g0=n,Zf=this._append,x0=this._,this._=o}this._+=x0;break}}}result(){let e=this._;return this._="",e.length?e:null}};function y0(t){let e=1;this._+=t[0];for(let r=t.length;e<r;++e)this._+=arguments[e]+t[e]}function $w(t){let e=Math.floor(t);if(!(e>=0))throw new RangeError(`invalid digits: ${t}`);if(e>15)return y0;if(e!==h0){let r=10**e;h0=e,Zf=function(o){let i=1;this._+=o[0];for(let a=o.length;i<a;++i)this._+=Math.round(arguments[i]*r)/r+o[i]}}return Zf}function Ei(t,e){let r=3,n=4.5,o,i;function a(f){return f&&(typeof n=="function"&&i.pointRadius(+n.apply(this,arguments)),De(f,o(i))),i.result()}return a.area=function(f){return De(f,o(bl)),bl.result()},a.measure=function(f){return De(f,o($l)),$l.result()},a.bounds=function(f){return De(f,o(io)),io.result()},a.centroid=function(f){return De(f,o(Tl)),Tl.result()},a.projection=function(f){return arguments.length?(o=f==null?(t=null,Si):(t=f).stream,a):t},a.context=function(f){return arguments.length?(i=f==null?(e=null,new fo(r)):new jf(e=f),typeof n!="function"&&i.pointRadius(n),a):e},a.pointRadius=function(f){return arguments.length?(n=typeof f=="function"?f:(i.pointRadius(+f),+f),a):n},a.digits=function(f){if(!arguments.length)return r;if(f==null)r=null;else{let u=Math.floor(f);if(!(u>=0))throw new RangeError(`invalid digits: ${f}`);r=u}return e===null&&(i=new fo(r)),a},a.projection(t).digits(r).context(e)}function ki(t){return{stream:uo(t)}}function uo(t){return function(e){var r=new Al;for(var n in t)r[n]=t[n];return r.stream=e,r}}function Al(){}Al.prototype={constructor:Al,point:function(t,e){this.stream.point(t,e)},sphere:function(){this.stream.sphere()},lineStart:function(){this.stream.lineStart()},lineEnd:function(){this.stream.lineEnd()},polygonStart:function(){this.stream.polygonStart()},polygonEnd:function(){this.stream.polygonEnd()}};function El(t,e,r){var n=t.clipExtent&&t.clipExtent();return t.scale(150).translate([0,0]),n!=null&&t.clipExtent(null),De(r,t.stream(io)),e(io.result()),n!=null&&t.clipExtent(n),t}function Ii(t,e,r){return El(t,function(n){var o=e[1][0]-e[0][0],i=e[1][1]-e[0][1],a=Math.min(o/(n[1][0]-n[0][0]),i/(n[1][1]-n[0][1])),f=+e[0][0]+(o-a*(n[1][0]+n[0][0]))/2,u=+e[0][1]+(i-a*(n[1][1]+n[0][1]))/2;t.scale(150*a).translate([f,u])},r)}function Qf(t,e,r){return Ii(t,[[0,0],e],r)}function Jf(t,e,r){return El(t,function(n){var o=+e,i=o/(n[1][0]-n[0][0]),a=(o-i*(n[1][0]+n[0][0]))/2,f=-i*n[0][1];t.scale(150*i).translate([a,f])},r)}function tu(t,e,r){return El(t,function(n){var o=+e,i=o/(n[1][1]-n[0][1]),a=-i*n[0][0],f=(o-i*(n[1][1]+n[0][1]))/2;t.scale(150*i).translate([a,f])},r)}var b0=16,Aw=z(30*ut);function kl(t,e){return+e?kw(t,e):Ew(t)}function Ew(t){return uo({point:function(e,r){e=t(e,r),this.stream.point(e[0],e[1])}})}function kw(t,e){function r(n,o,i,a,f,u,c,s,p,l,m,d,h,g){var b=c-n,x=s-o,M=b*b+x*x;if(M>4*e&&h--){var y=a+l,w=f+m,v=u+d,T=dt(y*y+w*w+v*v),E=Mt(v/=T),k=Z(Z(v)-1)<U||Z(i-p)<U?(i+p)/2:Yt(w,y),q=t(k,E),_=q[0],R=q[1],F=_-n,P=R-o,S=x*F-b*P;(S*S/M>e||Z((b*F+x*P)/M-.5)>.3||a*l+f*m+u*d<Aw)&&(r(n,o,i,a,f,u,_,R,k,y/=T,w/=T,v,h,g),g.point(_,R),r(_,R,k,y,w,v,c,s,p,l,m,d,h,g))}}return function(n){var o,i,a,f,u,c,s,p,l,m,d,h,g={point:b,lineStart:x,lineEnd:y,polygonStart:function(){n.polygonStart(),g.lineStart=w},polygonEnd:function(){n.polygonEnd(),g.lineStart=x}};function b(E,k){E=t(E,k),n.point(E[0],E[1])}function x(){p=NaN,g.point=M,n.lineStart()}function M(E,k){var q=Je([E,k]),_=t(E,k);r(p,l,s,m,d,h,p=_[0],l=_[1],s=E,m=q[0],d=q[1],h=q[2],b0,n),n.point(p,l)}function y(){g.point=b,n.lineEnd()}function w(){x(),g.point=v,g.lineEnd=T}function v(E,k){M(o=E,k),i=p,a=l,f=m,u=d,c=h,g.point=M}function T(){r(p,l,s,m,d,h,i,a,o,f,u,c,b0,n),g.lineEnd=y,y()}return g}}var Iw=uo({point:function(t,e){this.stream.point(t*ut,e*ut)}});function Nw(t){return uo({point:function(e,r){var n=t(e,r);return this.stream.point(n[0],n[1])}})}function Dw(t,e,r,n,o){function i(a,f){return a*=n,f*=o,[e+t*a,r-t*f]}return i.invert=function(a,f){return[(a-e)/t*n,(r-f)/t*o]},i}function w0(t,e,r,n,o,i){if(!i)return Dw(t,e,r,n,o);var a=z(i),f=B(i),u=a*t,c=f*t,s=a/t,p=f/t,l=(f*r-a*e)/t,m=(f*e+a*r)/t;function d(h,g){return h*=n,g*=o,[u*h-c*g+e,r-c*h-u*g]}return d.invert=function(h,g){return[n*(s*h-p*g+l),o*(m-p*h-s*g)]},d}function Pt(t){return Il(function(){return t})()}function Il(t){var e,r=150,n=480,o=250,i=0,a=0,f=0,u=0,c=0,s,p=0,l=1,m=1,d=null,h=Uf,g=null,b,x,M,y=Si,w=.5,v,T,E,k,q;function _(S){return E(S[0]*ut,S[1]*ut)}function R(S){return S=E.invert(S[0],S[1]),S&&[S[0]*At,S[1]*At]}_.stream=function(S){return k&&q===S?k:k=Iw(Nw(s)(h(v(y(q=S)))))},_.preclip=function(S){return arguments.length?(h=S,d=void 0,P()):h},_.postclip=function(S){return arguments.length?(y=S,g=b=x=M=null,P()):y},_.clipAngle=function(S){return arguments.length?(h=+S?dl(d=S*ut):(d=null,Uf),P()):d*At},_.clipExtent=function(S){return arguments.length?(y=S==null?(g=b=x=M=null,Si):no(g=+S[0][0],b=+S[0][1],x=+S[1][0],M=+S[1][1]),P()):g==null?null:[[g,b],[x,M]]},_.scale=function(S){return arguments.length?(r=+S,F()):r},_.translate=function(S){return arguments.length?(n=+S[0],o=+S[1],F()):[n,o]},_.center=function(S){return arguments.length?(i=S[0]%360*ut,a=S[1]%360*ut,F()):[i*At,a*At]},_.rotate=function(S){return arguments.length?(f=S[0]%360*ut,u=S[1]%360*ut,c=S.length>2?S[2]%360*ut:0,F()):[f*At,u*At,c*At]},_.angle=function(S){return arguments.length?(p=S%360*ut,F()):p*At},_.reflectX=function(S){return arguments.length?(l=S?-1:1,F()):l<0},_.reflectY=function(S){return arguments.length?(m=S?-1:1,F()):m<0},_.precision=function(S){return arguments.length?(v=kl(T,w=S*S),P()):dt(w)},_.fitExtent=function(S,$){return Ii(_,S,$)},_.fitSize=function(S,$){return Qf(_,S,$)},_.fitWidth=function(S,$){return Jf(_,S,$)},_.fitHeight=function(S,$){return tu(_,S,$)};function F(){var S=w0(r,0,0,l,m,p).apply(null,e(i,a)),$=w0(r,n-S[0],o-S[1],l,m,p);return s=qf(f,u,c),T=vi(e,$),E=vi(s,T),v=kl(T,w),P()}function P(){return k=q=null,_}return function(){return e=t.apply(this,arguments),_.invert=e.invert&&R,F()}}function co(t){var e=0,r=V/3,n=Il(t),o=n(e,r);return o.parallels=function(i){return arguments.length?n(e=i[0]*ut,r=i[1]*ut):[e*At,r*At]},o}function v0(t){var e=z(t);function r(n,o){return[n*e,B(o)/e]}return r.invert=function(n,o){return[n/e,Mt(o*e)]},r}function Rw(t,e){var r=B(t),n=(r+B(e))/2;if(Z(n)<U)return v0(t);var o=1+r*(2*n-r),i=dt(o)/n;function a(f,u){var c=dt(o-2*n*B(u))/n;return[c*B(f*=n),i-c*z(f)]}return a.invert=function(f,u){var c=i-u,s=Yt(f,Z(c))*Vt(c);return c*n<0&&(s-=V*Vt(f)*Vt(c)),[s/n,Mt((o-(f*f+c*c)*n*n)/(2*n))]},a}function Pr(){return co(Rw).scale(155.424).center([0,33.6442])}function Ni(){return Pr().parallels([29.5,45.5]).scale(1070).translate([480,250]).rotate([96,0]).center([-.6,38.7])}function Pw(t){var e=t.length;return{point:function(r,n){for(var o=-1;++o<e;)t[o].point(r,n)},sphere:function(){for(var r=-1;++r<e;)t[r].sphere()},lineStart:function(){for(var r=-1;++r<e;)t[r].lineStart()},lineEnd:function(){for(var r=-1;++r<e;)t[r].lineEnd()},polygonStart:function(){for(var r=-1;++r<e;)t[r].polygonStart()},polygonEnd:function(){for(var r=-1;++r<e;)t[r].polygonEnd()}}}function Nl(){var t,e,r=Ni(),n,o=Pr().rotate([154,0]).center([-2,58.5]).parallels([55,65]),i,a=Pr().rotate([157,0]).center([-3,19.9]).parallels([8,18]),f,u,c={point:function(l,m){u=[l,m]}};function s(l){var m=l[0],d=l[1];return u=null,n.point(m,d),u||(i.point(m,d),u)||(f.point(m,d),u)}s.invert=function(l){var m=r.scale(),d=r.translate(),h=(l[0]-d[0])/m,g=(l[1]-d[1])/m;return(g>=.12&&g<.234&&h>=-.425&&h<-.214?o:g>=.166&&g<.234&&h>=-.214&&h<-.115?a:r).invert(l)},s.stream=function(l){return t&&e===l?t:t=Pw([r.stream(e=l),o.stream(l),a.stream(l)])},s.precision=function(l){return arguments.length?(r.precision(l),o.precision(l),a.precision(l),p()):r.precision()},s.scale=function(l){return arguments.length?(r.scale(l),o.scale(l*.35),a.scale(l),s.translate(r.translate())):r.scale()},s.translate=function(l){if(!arguments.length)return r.translate();var m=r.scale(),d=+l[0],h=+l[1];return n=r.translate(l).clipExtent([[d-.455*m,h-.238*m],[d+.455*m,h+.238*m]]).stream(c),i=o.translate([d-.307*m,h+.201*m]).clipExtent([[d-.425*m+U,h+.12*m+U],[d-.214*m-U,h+.234*m-U]]).stream(c),f=a.translate([d-.205*m,h+.212*m]).clipExtent([[d-.214*m+U,h+.166*m+U],[d-.115*m-U,h+.234*m-U]]).stream(c),p()},s.fitExtent=function(l,m){return Ii(s,l,m)},s.fitSize=function(l,m){return Qf(s,l,m)},s.fitWidth=function(l,m){return Jf(s,l,m)},s.fitHeight=function(l,m){return tu(s,l,m)};function p(){return t=e=null,s}return s.scale(1070)}function eu(t){return function(e,r){var n=z(e),o=z(r),i=t(n*o);return i===1/0?[2,0]:[i*o*B(e),i*B(r)]}}function rr(t){return function(e,r){var n=dt(e*e+r*r),o=t(n),i=B(o),a=z(o);return[Yt(e*i,n*a),Mt(n&&r*i/n)]}}var M0=eu(function(t){return dt(2/(1+t))});M0.invert=rr(function(t){return 2*Mt(t/2)});function Dl(){return Pt(M0).scale(124.75).clipAngle(180-.001)}var S0=eu(function(t){return(t=Pf(t))&&t/B(t)});S0.invert=rr(function(t){return t});function Rl(){return Pt(S0).scale(79.4188).clipAngle(180-.001)}function Di(t,e){return[t,hn(to((ft+e)/2))]}Di.invert=function(t,e){return[t,2*Se(Df(e))-ft]};function Pl(){return Ll(Di).scale(961/Dt)}function Ll(t){var e=Pt(t),r=e.center,n=e.scale,o=e.translate,i=e.clipExtent,a=null,f,u,c;e.scale=function(p){return arguments.length?(n(p),s()):n()},e.translate=function(p){return arguments.length?(o(p),s()):o()},e.center=function(p){return arguments.length?(r(p),s()):r()},e.clipExtent=function(p){return arguments.length?(p==null?a=f=u=c=null:(a=+p[0][0],f=+p[0][1],u=+p[1][0],c=+p[1][1]),s()):a==null?null:[[a,f],[u,c]]};function s(){var p=V*n(),l=e(pl(e.rotate()).invert([0,0]));return i(a==null?[[l[0]-p,l[1]-p],[l[0]+p,l[1]+p]]:t===Di?[[Math.max(l[0]-p,a),f],[Math.min(l[0]+p,u),c]]:[[a,Math.max(l[1]-p,f)],[u,Math.min(l[1]+p,c)]])}return s()}function ru(t){return to((ft+t)/2)}function Lw(t,e){var r=z(t),n=t===e?B(t):hn(r/z(e))/hn(ru(e)/ru(t)),o=r*Rf(ru(t),n)/n;if(!n)return Di;function i(a,f){o>0?f<-ft+U&&(f=-ft+U):f>ft-U&&(f=ft-U);var u=o/Rf(ru(f),n);return[u*B(n*a),o-u*z(n*a)]}return i.invert=function(a,f){var u=o-f,c=Vt(n)*dt(a*a+u*u),s=Yt(a,Z(u))*Vt(u);return u*n<0&&(s-=V*Vt(a)*Vt(u)),[s/n,2*Se(Rf(o/c,1/n))-ft]},i}function Fl(){return co(Lw).scale(109.5).parallels([30,30])}function Ri(t,e){return[t,e]}Ri.invert=Ri;function ql(){return Pt(Ri).scale(152.63)}function Fw(t,e){var r=z(t),n=t===e?B(t):(r-z(e))/(e-t),o=r/n+t;if(Z(n)<U)return Ri;function i(a,f){var u=o-f,c=n*a;return[u*B(c),o-u*z(c)]}return i.invert=function(a,f){var u=o-f,c=Yt(a,Z(u))*Vt(u);return u*n<0&&(c-=V*Vt(a)*Vt(u)),[c/n,o-Vt(n)*dt(a*a+u*u)]},i}function Ol(){return co(Fw).scale(131.154).center([0,13.9389])}var Pi=1.340264,Li=-.081106,Fi=893e-6,qi=.003796,nu=dt(3)/2,qw=12;function T0(t,e){var r=Mt(nu*B(e)),n=r*r,o=n*n*n;return[t*z(r)/(nu*(Pi+3*Li*n+o*(7*Fi+9*qi*n))),r*(Pi+Li*n+o*(Fi+qi*n))]}T0.invert=function(t,e){for(var r=e,n=r*r,o=n*n*n,i=0,a,f,u;i<qw&&(f=r*(Pi+Li*n+o*(Fi+qi*n))-e,u=Pi+3*Li*n+o*(7*Fi+9*qi*n),r-=a=f/u,n=r*r,o=n*n*n,!(Z(a)<Nf));++i);return[nu*t*(Pi+3*Li*n+o*(7*Fi+9*qi*n))/z(r),Mt(B(r)/nu)]};function Yl(){return Pt(T0).scale(177.158)}function _0(t,e){var r=z(e),n=z(t)*r;return[r*B(t)/n,B(e)/n]}_0.invert=rr(Se);function Bl(){return Pt(_0).scale(144.049).clipAngle(60)}function C0(t,e){return[z(e)*B(t),B(e)]}C0.invert=rr(Mt);function zl(){return Pt(C0).scale(249.5).clipAngle(90+U)}function $0(t,e){var r=z(e),n=1+z(t)*r;return[r*B(t)/n,B(e)/n]}$0.invert=rr(function(t){return 2*Se(t)});function Ul(){return Pt($0).scale(250).clipAngle(142)}function A0(t,e){return[hn(to((ft+e)/2)),-t]}A0.invert=function(t,e){return[-e,2*Se(Df(t))-ft]};function Wl(){var t=Ll(A0),e=t.center,r=t.rotate;return t.center=function(n){return arguments.length?e([-n[1],n[0]]):(n=e(),[n[1],-n[0]])},t.rotate=function(n){return arguments.length?r([n[0],n[1],n.length>2?n[2]+90:90]):(n=r(),[n[0],n[1],n[2]-90])},r([0,0,90]).scale(159.155)}function _t(t,e){switch(arguments.length){case 0:break;case 1:this.range(t);break;default:this.range(e).domain(t);break}return this}function so(t,e){switch(arguments.length){case 0:break;case 1:{typeof t=="function"?this.interpolator(t):this.range(t);break}default:{this.domain(t),typeof e=="function"?this.interpolator(e):this.range(e);break}}return this}var Oi=Symbol("implicit");function xn(){var t=new Jt,e=[],r=[],n=Oi;function o(i){let a=t.get(i);if(a===void 0){if(n!==Oi)return n;t.set(i,a=e.push(i)-1)}return r[a%r.length]}return o.domain=function(i){if(!arguments.length)return e.slice();e=[],t=new Jt;for(let a of i)t.has(a)||t.set(a,e.push(a)-1);return o},o.range=function(i){return arguments.length?(r=Array.from(i),o):r.slice()},o.unknown=function(i){return arguments.length?(n=i,o):n},o.copy=function(){return xn(e,r).unknown(n)},_t.apply(o,arguments),o}function Lr(){var t=xn().unknown(void 0),e=t.domain,r=t.range,n=0,o=1,i,a,f=!1,u=0,c=0,s=.5;delete t.unknown;function p(){var l=e().length,m=o<n,d=m?o:n,h=m?n:o;i=(h-d)/Math.max(1,l-u+c*2),f&&(i=Math.floor(i)),d+=(h-d-i*(l-u))*s,a=i*(1-u),f&&(d=Math.round(d),a=Math.round(a));var g=nn(l).map(function(b){return d+i*b});return r(m?g.reverse():g)}return t.domain=function(l){return arguments.length?(e(l),p()):e()},t.range=function(l){return arguments.length?([n,o]=l,n=+n,o=+o,p()):[n,o]},t.rangeRound=function(l){return[n,o]=l,n=+n,o=+o,f=!0,p()},t.bandwidth=function(){return a},t.step=function(){return i},t.round=function(l){return arguments.length?(f=!!l,p()):f},t.padding=function(l){return arguments.length?(u=Math.min(1,c=+l),p()):u},t.paddingInner=function(l){return arguments.length?(u=Math.min(1,l),p()):u},t.paddingOuter=function(l){return arguments.length?(c=+l,p()):c},t.align=function(l){return arguments.length?(s=Math.max(0,Math.min(1,l)),p()):s},t.copy=function(){return Lr(e(),[n,o]).round(f).paddingInner(u).paddingOuter(c).align(s)},_t.apply(p(),arguments)}function E0(t){var e=t.copy;return t.padding=t.paddingOuter,delete t.paddingInner,delete t.paddingOuter,t.copy=function(){return E0(e())},t}function Hl(){return E0(Lr.apply(null,arguments).paddingInner(1))}function Gl(t){return function(){return t}}function lo(t){return+t}var k0=[0,1];function re(t){return t}function Xl(t,e){return(e-=t=+t)?function(r){return(r-t)/e}:Gl(isNaN(e)?NaN:.5)}function Ow(t,e){var r;return t>e&&(r=t,t=e,e=r),function(n){return Math.max(t,Math.min(e,n))}}function Yw(t,e,r){var n=t[0],o=t[1],i=e[0],a=e[1];return o<n?(n=Xl(o,n),i=r(a,i)):(n=Xl(n,o),i=r(i,a)),function(f){return i(n(f))}}function Bw(t,e,r){var n=Math.min(t.length,e.length)-1,o=new Array(n),i=new Array(n),a=-1;for(t[n]<t[0]&&(t=t.slice().reverse(),e=e.slice().reverse());++a<n;)o[a]=Xl(t[a],t[a+1]),i[a]=r(e[a],e[a+1]);return function(f){var u=lr(t,f,1,n)-1;return i[u](o[u](f))}}function nr(t,e){return e.domain(t.domain()).range(t.range()).interpolate(t.interpolate()).clamp(t.clamp()).unknown(t.unknown())}function yn(){var t=k0,e=k0,r=se,n,o,i,a=re,f,u,c;function s(){var l=Math.min(t.length,e.length);return a!==re&&(a=Ow(t[0],t[l-1])),f=l>2?Bw:Yw,u=c=null,p}function p(l){return l==null||isNaN(l=+l)?i:(u||(u=f(t.map(n),e,r)))(n(a(l)))}return p.invert=function(l){return a(o((c||(c=f(e,t.map(n),ht)))(l)))},p.domain=function(l){return arguments.length?(t=Array.from(l,lo),s()):t.slice()},p.range=function(l){return arguments.length?(e=Array.from(l),s()):e.slice()},p.rangeRound=function(l){return e=Array.from(l),r=Nr,s()},p.clamp=function(l){return arguments.length?(a=l?!0:re,s()):a!==re},p.interpolate=function(l){return arguments.length?(r=l,s()):r},p.unknown=function(l){return arguments.length?(i=l,p):i},function(l,m){return n=l,o=m,s()}}function Yi(){return yn()(re,re)}function ou(t,e,r,n){var o=Un(t,e,r),i;switch(n=Ze(n??",f"),n.type){case"s":{var a=Math.max(Math.abs(t),Math.abs(e));return n.precision==null&&!isNaN(i=fl(o,a))&&(n.precision=i),If(n,a)}case"":case"e":case"g":case"p":case"r":{n.precision==null&&!isNaN(i=ul(o,Math.max(Math.abs(t),Math.abs(e))))&&(n.precision=i-(n.type==="e"));break}case"f":case"%":{n.precision==null&&!isNaN(i=al(o))&&(n.precision=i-(n.type==="%")*2);break}}return Qe(n)}function or(t){var e=t.domain;return t.ticks=function(r){var n=e();return ke(n[0],n[n.length-1],r??10)},t.tickFormat=function(r,n){var o=e();return ou(o[0],o[o.length-1],r??10,n)},t.nice=function(r){r==null&&(r=10);var n=e(),o=0,i=n.length-1,a=n[o],f=n[i],u,c,s=10;for(f<a&&(c=a,a=f,f=c,c=o,o=i,i=c);s-- >0;){if(c=rn(a,f,r),c===u)return n[o]=a,n[i]=f,e(n);if(c>0)a=Math.floor(a/c)*c,f=Math.ceil(f/c)*c;else if(c<0)a=Math.ceil(a*c)/c,f=Math.floor(f*c)/c;else break;u=c}return t},t}function bn(){var t=Yi();return t.copy=function(){return nr(t,bn())},_t.apply(t,arguments),or(t)}function Bi(t){var e;function r(n){return n==null||isNaN(n=+n)?e:n}return r.invert=r,r.domain=r.range=function(n){return arguments.length?(t=Array.from(n,lo),r):t.slice()},r.unknown=function(n){return arguments.length?(e=n,r):e},r.copy=function(){return Bi(t).unknown(e)},t=arguments.length?Array.from(t,lo):[0,1],or(r)}function zi(t,e){t=t.slice();var r=0,n=t.length-1,o=t[r],i=t[n],a;return i<o&&(a=r,r=n,n=a,a=o,o=i,i=a),t[r]=e.floor(o),t[n]=e.ceil(i),t}function I0(t){return Math.log(t)}function N0(t){return Math.exp(t)}function zw(t){return-Math.log(-t)}function Uw(t){return-Math.exp(-t)}function Ww(t){return isFinite(t)?+("1e"+t):t<0?0:t}function Hw(t){return t===10?Ww:t===Math.E?Math.exp:e=>Math.pow(t,e)}function Gw(t){return t===Math.E?Math.log:t===10&&Math.log10||t===2&&Math.log2||(t=Math.log(t),e=>Math.log(e)/t)}function D0(t){return(e,r)=>-t(-e,r)}function iu(t){let e=t(I0,N0),r=e.domain,n=10,o,i;function a(){return o=Gw(n),i=Hw(n),r()[0]<0?(o=D0(o),i=D0(i),t(zw,Uw)):t(I0,N0),e}return e.base=function(f){return arguments.length?(n=+f,a()):n},e.domain=function(f){return arguments.length?(r(f),a()):r()},e.ticks=f=>{let u=r(),c=u[0],s=u[u.length-1],p=s<c;p&&([c,s]=[s,c]);let l=o(c),m=o(s),d,h,g=f==null?10:+f,b=[];if(!(n%1)&&m-l<g){if(l=Math.floor(l),m=Math.ceil(m),c>0){for(;l<=m;++l)for(d=1;d<n;++d)if(h=l<0?d/i(-l):d*i(l),!(h<c)){if(h>s)break;b.push(h)}}else for(;l<=m;++l)for(d=n-1;d>=1;--d)if(h=l>0?d/i(-l):d*i(l),!(h<c)){if(h>s)break;b.push(h)}b.length*2<g&&(b=ke(c,s,g))}else b=ke(l,m,Math.min(m-l,g)).map(i);return p?b.reverse():b},e.tickFormat=(f,u)=>{if(f==null&&(f=10),u==null&&(u=n===10?"s":","),typeof u!="function"&&(!(n%1)&&(u=Ze(u)).precision==null&&(u.trim=!0),u=Qe(u)),f===1/0)return u;let c=Math.max(1,n*f/e.ticks().length);return s=>{let p=s/i(Math.round(o(s)));return p*n<n-.5&&(p*=n),p<=c?u(s):""}},e.nice=()=>r(zi(r(),{floor:f=>i(Math.floor(o(f))),ceil:f=>i(Math.ceil(o(f)))})),e}function Ui(){let t=iu(yn()).domain([1,10]);return t.copy=()=>nr(t,Ui()).base(t.base()),_t.apply(t,arguments),t}function R0(t){return function(e){return Math.sign(e)*Math.log1p(Math.abs(e/t))}}function P0(t){return function(e){return Math.sign(e)*Math.expm1(Math.abs(e))*t}}function au(t){var e=1,r=t(R0(e),P0(e));return r.constant=function(n){return arguments.length?t(R0(e=+n),P0(e)):e},or(r)}function Wi(){var t=au(yn());return t.copy=function(){return nr(t,Wi()).constant(t.constant())},_t.apply(t,arguments)}function L0(t){return function(e){return e<0?-Math.pow(-e,t):Math.pow(e,t)}}function Xw(t){return t<0?-Math.sqrt(-t):Math.sqrt(t)}function Vw(t){return t<0?-t*t:t*t}function fu(t){var e=t(re,re),r=1;function n(){return r===1?t(re,re):r===.5?t(Xw,Vw):t(L0(r),L0(1/r))}return e.exponent=function(o){return arguments.length?(r=+o,n()):r},or(e)}function Hi(){var t=fu(yn());return t.copy=function(){return nr(t,Hi()).exponent(t.exponent())},_t.apply(t,arguments),t}function Gi(){var t=[],e=[],r=[],n;function o(){var a=0,f=Math.max(1,e.length);for(r=new Array(f-1);++a<f;)r[a-1]=ws(t,a/f);return i}function i(a){return a==null||isNaN(a=+a)?n:e[lr(r,a)]}return i.invertExtent=function(a){var f=e.indexOf(a);return f<0?[NaN,NaN]:[f>0?r[f-1]:t[0],f<r.length?r[f]:t[t.length-1]]},i.domain=function(a){if(!arguments.length)return t.slice();t=[];for(let f of a)f!=null&&!isNaN(f=+f)&&t.push(f);return t.sort(bt),o()},i.range=function(a){return arguments.length?(e=Array.from(a),o()):e.slice()},i.unknown=function(a){return arguments.length?(n=a,i):n},i.quantiles=function(){return r.slice()},i.copy=function(){return Gi().domain(t).range(e).unknown(n)},_t.apply(i,arguments)}function Xi(){var t=[.5],e=[0,1],r,n=1;function o(i){return i!=null&&i<=i?e[lr(t,i,0,n)]:r}return o.domain=function(i){return arguments.length?(t=Array.from(i),n=Math.min(t.length,e.length-1),o):t.slice()},o.range=function(i){return arguments.length?(e=Array.from(i),n=Math.min(t.length,e.length-1),o):e.slice()},o.invertExtent=function(i){var a=e.indexOf(i);return[t[a-1],t[a]]},o.unknown=function(i){return arguments.length?(r=i,o):r},o.copy=function(){return Xi().domain(t).range(e).unknown(r)},_t.apply(o,arguments)}var Vl=new Date,jl=new Date;function pt(t,e,r,n){function o(i){return t(i=arguments.length===0?new Date:new Date(+i)),i}return o.floor=i=>(t(i=new Date(+i)),i),o.ceil=i=>(t(i=new Date(i-1)),e(i,1),t(i),i),o.round=i=>{let a=o(i),f=o.ceil(i);return i-a<f-i?a:f},o.offset=(i,a)=>(e(i=new Date(+i),a==null?1:Math.floor(a)),i),o.range=(i,a,f)=>{let u=[];if(i=o.ceil(i),f=f==null?1:Math.floor(f),!(i<a)||!(f>0))return u;let c;do u.push(c=new Date(+i)),e(i,f),t(i);while(c<i&&i<a);return u},o.filter=i=>pt(a=>{if(a>=a)for(;t(a),!i(a);)a.setTime(a-1)},(a,f)=>{if(a>=a)if(f<0)for(;++f<=0;)for(;e(a,-1),!i(a););else for(;--f>=0;)for(;e(a,1),!i(a););}),r&&(o.count=(i,a)=>(Vl.setTime(+i),jl.setTime(+a),t(Vl),t(jl),Math.floor(r(Vl,jl))),o.every=i=>(i=Math.floor(i),!isFinite(i)||!(i>0)?null:i>1?o.filter(n?a=>n(a)%i===0:a=>o.count(0,a)%i===0):o)),o}var Vi=pt(()=>{},(t,e)=>{t.setTime(+t+e)},(t,e)=>e-t);Vi.every=t=>(t=Math.floor(t),!isFinite(t)||!(t>0)?null:t>1?pt(e=>{e.setTime(Math.floor(e/t)*t)},(e,r)=>{e.setTime(+e+r*t)},(e,r)=>(r-e)/t):Vi);var fP=Vi.range;var jt=pt(t=>{t.setTime(t-t.getMilliseconds())},(t,e)=>{t.setTime(+t+e*1e3)},(t,e)=>(e-t)/1e3,t=>t.getUTCSeconds()),pP=jt.range;var Fr=pt(t=>{t.setTime(t-t.getMilliseconds()-t.getSeconds()*1e3)},(t,e)=>{t.setTime(+t+e*6e4)},(t,e)=>(e-t)/6e4,t=>t.getMinutes()),gP=Fr.range,qr=pt(t=>{t.setUTCSeconds(0,0)},(t,e)=>{t.setTime(+t+e*6e4)},(t,e)=>(e-t)/6e4,t=>t.getUTCMinutes()),xP=qr.range;var Or=pt(t=>{t.setTime(t-t.getMilliseconds()-t.getSeconds()*1e3-t.getMinutes()*6e4)},(t,e)=>{t.setTime(+t+e*36e5)},(t,e)=>(e-t)/36e5,t=>t.getHours()),vP=Or.range,Yr=pt(t=>{t.setUTCMinutes(0,0,0)},(t,e)=>{t.setTime(+t+e*36e5)},(t,e)=>(e-t)/36e5,t=>t.getUTCHours()),MP=Yr.range;var Le=pt(t=>t.setHours(0,0,0,0),(t,e)=>t.setDate(t.getDate()+e),(t,e)=>(e-t-(e.getTimezoneOffset()-t.getTimezoneOffset())*6e4)/864e5,t=>t.getDate()-1),CP=Le.range,vn=pt(t=>{t.setUTCHours(0,0,0,0)},(t,e)=>{t.setUTCDate(t.getUTCDate()+e)},(t,e)=>(e-t)/864e5,t=>t.getUTCDate()-1),$P=vn.range,po=pt(t=>{t.setUTCHours(0,0,0,0)},(t,e)=>{t.setUTCDate(t.getUTCDate()+e)},(t,e)=>(e-t)/864e5,t=>Math.floor(t/864e5)),AP=po.range;function Mn(t){return pt(e=>{e.setDate(e.getDate()-(e.getDay()+7-t)%7),e.setHours(0,0,0,0)},(e,r)=>{e.setDate(e.getDate()+r*7)},(e,r)=>(r-e-(r.getTimezoneOffset()-e.getTimezoneOffset())*6e4)/6048e5)}var le=Mn(0),Br=Mn(1),cu=Mn(2),su=Mn(3),ir=Mn(4),lu=Mn(5),pu=Mn(6),NP=le.range,DP=Br.range,RP=cu.range,PP=su.range,LP=ir.range,FP=lu.range,qP=pu.range;function Sn(t){return pt(e=>{e.setUTCDate(e.getUTCDate()-(e.getUTCDay()+7-t)%7),e.setUTCHours(0,0,0,0)},(e,r)=>{e.setUTCDate(e.getUTCDate()+r*7)},(e,r)=>(r-e)/6048e5)}var pe=Sn(0),zr=Sn(1),mu=Sn(2),du=Sn(3),ar=Sn(4),hu=Sn(5),gu=Sn(6),OP=pe.range,YP=zr.range,BP=mu.range,zP=du.range,UP=ar.range,WP=hu.range,HP=gu.range;var Ur=pt(t=>{t.setDate(1),t.setHours(0,0,0,0)},(t,e)=>{t.setMonth(t.getMonth()+e)},(t,e)=>e.getMonth()-t.getMonth()+(e.getFullYear()-t.getFullYear())*12,t=>t.getMonth()),VP=Ur.range,Wr=pt(t=>{t.setUTCDate(1),t.setUTCHours(0,0,0,0)},(t,e)=>{t.setUTCMonth(t.getUTCMonth()+e)},(t,e)=>e.getUTCMonth()-t.getUTCMonth()+(e.getUTCFullYear()-t.getUTCFullYear())*12,t=>t.getUTCMonth()),jP=Wr.range;var Kt=pt(t=>{t.setMonth(0,1),t.setHours(0,0,0,0)},(t,e)=>{t.setFullYear(t.getFullYear()+e)},(t,e)=>e.getFullYear()-t.getFullYear(),t=>t.getFullYear());Kt.every=t=>!isFinite(t=Math.floor(t))||!(t>0)?null:pt(e=>{e.setFullYear(Math.floor(e.getFullYear()/t)*t),e.setMonth(0,1),e.setHours(0,0,0,0)},(e,r)=>{e.setFullYear(e.getFullYear()+r*t)});var QP=Kt.range,Zt=pt(t=>{t.setUTCMonth(0,1),t.setUTCHours(0,0,0,0)},(t,e)=>{t.setUTCFullYear(t.getUTCFullYear()+e)},(t,e)=>e.getUTCFullYear()-t.getUTCFullYear(),t=>t.getUTCFullYear());Zt.every=t=>!isFinite(t=Math.floor(t))||!(t>0)?null:pt(e=>{e.setUTCFullYear(Math.floor(e.getUTCFullYear()/t)*t),e.setUTCMonth(0,1),e.setUTCHours(0,0,0,0)},(e,r)=>{e.setUTCFullYear(e.getUTCFullYear()+r*t)});var JP=Zt.range;function q0(t,e,r,n,o,i){let a=[[jt,1,1e3],[jt,5,5*1e3],[jt,15,15*1e3],[jt,30,30*1e3],[i,1,6e4],[i,5,5*6e4],[i,15,15*6e4],[i,30,30*6e4],[o,1,36e5],[o,3,3*36e5],[o,6,6*36e5],[o,12,12*36e5],[n,1,864e5],[n,2,2*864e5],[r,1,6048e5],[e,1,2592e6],[e,3,3*2592e6],[t,1,31536e6]];function f(c,s,p){let l=s<c;l&&([c,s]=[s,c]);let m=p&&typeof p.range=="function"?p:u(c,s,p),d=m?m.range(c,+s+1):[];return l?d.reverse():d}function u(c,s,p){let l=Math.abs(s-c)/p,m=ze(([,,g])=>g).right(a,l);if(m===a.length)return t.every(Un(c/31536e6,s/31536e6,p));if(m===0)return Vi.every(Math.max(Un(c,s,p),1));let[d,h]=a[l/a[m-1][2]<a[m][2]/l?m-1:m];return d.every(h)}return[f,u]}var[Zl,ji]=q0(Zt,Wr,pe,po,Yr,qr),[Ql,Jl]=q0(Kt,Ur,le,Le,Or,Fr);function tp(t){if(0<=t.y&&t.y<100){var e=new Date(-1,t.m,t.d,t.H,t.M,t.S,t.L);return e.setFullYear(t.y),e}return new Date(t.y,t.m,t.d,t.H,t.M,t.S,t.L)}function ep(t){if(0<=t.y&&t.y<100){var e=new Date(Date.UTC(-1,t.m,t.d,t.H,t.M,t.S,t.L));return e.setUTCFullYear(t.y),e}return new Date(Date.UTC(t.y,t.m,t.d,t.H,t.M,t.S,t.L))}function Ki(t,e,r){return{y:t,m:e,d:r,H:0,M:0,S:0,L:0}}function rp(t){var e=t.dateTime,r=t.date,n=t.time,o=t.periods,i=t.days,a=t.shortDays,f=t.months,u=t.shortMonths,c=Zi(o),s=Qi(o),p=Zi(i),l=Qi(i),m=Zi(a),d=Qi(a),h=Zi(f),g=Qi(f),b=Zi(u),x=Qi(u),M={a:N,A:L,b:D,B:tt,c:null,d:W0,e:W0,f:x6,g:$6,G:E6,H:d6,I:h6,j:g6,L:j0,m:y6,M:b6,p:K,q:G,Q:X0,s:V0,S:w6,u:v6,U:M6,V:S6,w:T6,W:_6,x:null,X:null,y:C6,Y:A6,Z:k6,"%":G0},y={a:Q,A:at,b:Ut,B:Be,c:null,d:H0,e:H0,f:R6,g:W6,G:G6,H:I6,I:N6,j:D6,L:Z0,m:P6,M:L6,p:X,q:W,Q:X0,s:V0,S:F6,u:q6,U:O6,V:Y6,w:B6,W:z6,x:null,X:null,y:U6,Y:H6,Z:X6,"%":G0},w={a:q,A:_,b:R,B:F,c:P,d:z0,e:z0,f:s6,g:B0,G:Y0,H:U0,I:U0,j:a6,L:c6,m:i6,M:f6,p:k,q:o6,Q:p6,s:m6,S:u6,u:Jw,U:t6,V:e6,w:Qw,W:r6,x:S,X:$,y:B0,Y:Y0,Z:n6,"%":l6};M.x=v(r,M),M.X=v(n,M),M.c=v(e,M),y.x=v(r,y),y.X=v(n,y),y.c=v(e,y);function v(A,O){return function(Y){var C=[],j=-1,J=0,ot=A.length,lt,Wt,Qr;for(Y instanceof Date||(Y=new Date(+Y));++j<ot;)A.charCodeAt(j)===37&&(C.push(A.slice(J,j)),(Wt=O0[lt=A.charAt(++j)])!=null?lt=A.charAt(++j):Wt=lt==="e"?" ":"0",(Qr=O[lt])&&(lt=Qr(Y,Wt)),C.push(lt),J=j+1);return C.push(A.slice(J,j)),C.join("")}}function T(A,O){return function(Y){var C=Ki(1900,void 0,1),j=E(C,A,Y+="",0),J,ot;if(j!=Y.length)return null;if("Q"in C)return new Date(C.Q);if("s"in C)return new Date(C.s*1e3+("L"in C?C.L:0));if(O&&!("Z"in C)&&(C.Z=0),"p"in C&&(C.H=C.H%12+C.p*12),C.m===void 0&&(C.m="q"in C?C.q:0),"V"in C){if(C.V<1||C.V>53)return null;"w"in C||(C.w=1),"Z"in C?(J=ep(Ki(C.y,0,1)),ot=J.getUTCDay(),J=ot>4||ot===0?zr.ceil(J):zr(J),J=vn.offset(J,(C.V-1)*7),C.y=J.getUTCFullYear(),C.m=J.getUTCMonth(),C.d=J.getUTCDate()+(C.w+6)%7):(J=tp(Ki(C.y,0,1)),ot=J.getDay(),J=ot>4||ot===0?Br.ceil(J):Br(J),J=Le.offset(J,(C.V-1)*7),C.y=J.getFullYear(),C.m=J.getMonth(),C.d=J.getDate()+(C.w+6)%7)}else("W"in C||"U"in C)&&("w"in C||(C.w="u"in C?C.u%7:"W"in C?1:0),ot="Z"in C?ep(Ki(C.y,0,1)).getUTCDay():tp(Ki(C.y,0,1)).getDay(),C.m=0,C.d="W"in C?(C.w+6)%7+C.W*7-(ot+5)%7:C.w+C.U*7-(ot+6)%7);return"Z"in C?(C.H+=C.Z/100|0,C.M+=C.Z%100,ep(C)):tp(C)}}function E(A,O,Y,C){for(var j=0,J=O.length,ot=Y.length,lt,Wt;j<J;){if(C>=ot)return-1;if(lt=O.charCodeAt(j++),lt===37){if(lt=O.charAt(j++),Wt=w[lt in O0?O.charAt(j++):lt],!Wt||(C=Wt(A,Y,C))<0)return-1}else if(lt!=Y.charCodeAt(C++))return-1}return C}function k(A,O,Y){var C=c.exec(O.slice(Y));return C?(A.p=s.get(C[0].toLowerCase()),Y+C[0].length):-1}function q(A,O,Y){var C=m.exec(O.slice(Y));return C?(A.w=d.get(C[0].toLowerCase()),Y+C[0].length):-1}function _(A,O,Y){var C=p.exec(O.slice(Y));return C?(A.w=l.get(C[0].toLowerCase()),Y+C[0].length):-1}function R(A,O,Y){var C=b.exec(O.slice(Y));return C?(A.m=x.get(C[0].toLowerCase()),Y+C[0].length):-1}function F(A,O,Y){var C=h.exec(O.slice(Y));return C?(A.m=g.get(C[0].toLowerCase()),Y+C[0].length):-1}function P(A,O,Y){return E(A,e,O,Y)}function S(A,O,Y){return E(A,r,O,Y)}function $(A,O,Y){return E(A,n,O,Y)}function N(A){return a[A.getDay()]}function L(A){return i[A.getDay()]}function D(A){return u[A.getMonth()]}function tt(A){return f[A.getMonth()]}function K(A){return o[+(A.getHours()>=12)]}function G(A){return 1+~~(A.getMonth()/3)}function Q(A){return a[A.getUTCDay()]}function at(A){return i[A.getUTCDay()]}function Ut(A){return u[A.getUTCMonth()]}function Be(A){return f[A.getUTCMonth()]}function X(A){return o[+(A.getUTCHours()>=12)]}function W(A){return 1+~~(A.getUTCMonth()/3)}return{format:function(A){var O=v(A+="",M);return O.toString=function(){return A},O},parse:function(A){var O=T(A+="",!1);return O.toString=function(){return A},O},utcFormat:function(A){var O=v(A+="",y);return O.toString=function(){return A},O},utcParse:function(A){var O=T(A+="",!0);return O.toString=function(){return A},O}}}var O0={"-":"",_:" ",0:"0"},Lt=/^\s*\d+/,jw=/^%/,Kw=/[\\^$*+?|[\]().{}]/g;function rt(t,e,r){var n=t<0?"-":"",o=(n?-t:t)+"",i=o.length;return n+(i<r?new Array(r-i+1).join(e)+o:o)}function Zw(t){return t.replace(Kw,"\\$&")}function Zi(t){return new RegExp("^(?:"+t.map(Zw).join("|")+")","i")}function Qi(t){return new Map(t.map((e,r)=>[e.toLowerCase(),r]))}function Qw(t,e,r){var n=Lt.exec(e.slice(r,r+1));return n?(t.w=+n[0],r+n[0].length):-1}function Jw(t,e,r){var n=Lt.exec(e.slice(r,r+1));return n?(t.u=+n[0],r+n[0].length):-1}function t6(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.U=+n[0],r+n[0].length):-1}function e6(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.V=+n[0],r+n[0].length):-1}function r6(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.W=+n[0],r+n[0].length):-1}function Y0(t,e,r){var n=Lt.exec(e.slice(r,r+4));return n?(t.y=+n[0],r+n[0].length):-1}function B0(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.y=+n[0]+(+n[0]>68?1900:2e3),r+n[0].length):-1}function n6(t,e,r){var n=/^(Z)|([+-]\d\d)(?::?(\d\d))?/.exec(e.slice(r,r+6));return n?(t.Z=n[1]?0:-(n[2]+(n[3]||"00")),r+n[0].length):-1}function o6(t,e,r){var n=Lt.exec(e.slice(r,r+1));return n?(t.q=n[0]*3-3,r+n[0].length):-1}function i6(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.m=n[0]-1,r+n[0].length):-1}function z0(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.d=+n[0],r+n[0].length):-1}function a6(t,e,r){var n=Lt.exec(e.slice(r,r+3));return n?(t.m=0,t.d=+n[0],r+n[0].length):-1}function U0(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.H=+n[0],r+n[0].length):-1}function f6(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.M=+n[0],r+n[0].length):-1}function u6(t,e,r){var n=Lt.exec(e.slice(r,r+2));return n?(t.S=+n[0],r+n[0].length):-1}function c6(t,e,r){var n=Lt.exec(e.slice(r,r+3));return n?(t.L=+n[0],r+n[0].length):-1}function s6(t,e,r){var n=Lt.exec(e.slice(r,r+6));return n?(t.L=Math.floor(n[0]/1e3),r+n[0].length):-1}function l6(t,e,r){var n=jw.exec(e.slice(r,r+1));return n?r+n[0].length:-1}function p6(t,e,r){var n=Lt.exec(e.slice(r));return n?(t.Q=+n[0],r+n[0].length):-1}function m6(t,e,r){var n=Lt.exec(e.slice(r));return n?(t.s=+n[0],r+n[0].length):-1}function W0(t,e){return rt(t.getDate(),e,2)}function d6(t,e){return rt(t.getHours(),e,2)}function h6(t,e){return rt(t.getHours()%12||12,e,2)}function g6(t,e){return rt(1+Le.count(Kt(t),t),e,3)}function j0(t,e){return rt(t.getMilliseconds(),e,3)}function x6(t,e){return j0(t,e)+"000"}function y6(t,e){return rt(t.getMonth()+1,e,2)}function b6(t,e){return rt(t.getMinutes(),e,2)}function w6(t,e){return rt(t.getSeconds(),e,2)}function v6(t){var e=t.getDay();return e===0?7:e}function M6(t,e){return rt(le.count(Kt(t)-1,t),e,2)}function K0(t){var e=t.getDay();return e>=4||e===0?ir(t):ir.ceil(t)}function S6(t,e){return t=K0(t),rt(ir.count(Kt(t),t)+(Kt(t).getDay()===4),e,2)}function T6(t){return t.getDay()}function _6(t,e){return rt(Br.count(Kt(t)-1,t),e,2)}function C6(t,e){return rt(t.getFullYear()%100,e,2)}function $6(t,e){return t=K0(t),rt(t.getFullYear()%100,e,2)}function A6(t,e){return rt(t.getFullYear()%1e4,e,4)}function E6(t,e){var r=t.getDay();return t=r>=4||r===0?ir(t):ir.ceil(t),rt(t.getFullYear()%1e4,e,4)}function k6(t){var e=t.getTimezoneOffset();return(e>0?"-":(e*=-1,"+"))+rt(e/60|0,"0",2)+rt(e%60,"0",2)}function H0(t,e){return rt(t.getUTCDate(),e,2)}function I6(t,e){return rt(t.getUTCHours(),e,2)}function N6(t,e){return rt(t.getUTCHours()%12||12,e,2)}function D6(t,e){return rt(1+vn.count(Zt(t),t),e,3)}function Z0(t,e){return rt(t.getUTCMilliseconds(),e,3)}function R6(t,e){return Z0(t,e)+"000"}function P6(t,e){return rt(t.getUTCMonth()+1,e,2)}function L6(t,e){return rt(t.getUTCMinutes(),e,2)}function F6(t,e){return rt(t.getUTCSeconds(),e,2)}function q6(t){var e=t.getUTCDay();return e===0?7:e}function O6(t,e){return rt(pe.count(Zt(t)-1,t),e,2)}function Q0(t){var e=t.getUTCDay();return e>=4||e===0?ar(t):ar.ceil(t)}function Y6(t,e){return t=Q0(t),rt(ar.count(Zt(t),t)+(Zt(t).getUTCDay()===4),e,2)}function B6(t){return t.getUTCDay()}function z6(t,e){return rt(zr.count(Zt(t)-1,t),e,2)}function U6(t,e){return rt(t.getUTCFullYear()%100,e,2)}function W6(t,e){return t=Q0(t),rt(t.getUTCFullYear()%100,e,2)}function H6(t,e){return rt(t.getUTCFullYear()%1e4,e,4)}function G6(t,e){var r=t.getUTCDay();return t=r>=4||r===0?ar(t):ar.ceil(t),rt(t.getUTCFullYear()%1e4,e,4)}function X6(){return"+0000"}function G0(){return"%"}function X0(t){return+t}function V0(t){return Math.floor(+t/1e3)}var mo,Hr,V6,wr,j6;J0({dateTime:"%x, %X",date:"%-m/%-d/%Y",time:"%-I:%M:%S %p",periods:["AM","PM"],days:["Sunday","Monday","Tuesday","Wednesday","Thursday","Friday","Saturday"],shortDays:["Sun","Mon","Tue","Wed","Thu","Fri","Sat"],months:["January","February","March","April","May","June","July","August","September","October","November","December"],shortMonths:["Jan","Feb","Mar","Apr","May","Jun","Jul","Aug","Sep","Oct","Nov","Dec"]});function J0(t){return mo=rp(t),Hr=mo.format,V6=mo.parse,wr=mo.utcFormat,j6=mo.utcParse,mo}function K6(t){return new Date(t)}function Z6(t){return t instanceof Date?+t:+new Date(+t)}function xu(t,e,r,n,o,i,a,f,u,c){var s=Yi(),p=s.invert,l=s.domain,m=c(".%L"),d=c(":%S"),h=c("%I:%M"),g=c("%I %p"),b=c("%a %d"),x=c("%b %d"),M=c("%B"),y=c("%Y");function w(v){return(u(v)<v?m:f(v)<v?d:a(v)<v?h:i(v)<v?g:n(v)<v?o(v)<v?b:x:r(v)<v?M:y)(v)}return s.invert=function(v){return new Date(p(v))},s.domain=function(v){return arguments.length?l(Array.from(v,Z6)):l().map(K6)},s.ticks=function(v){var T=l();return t(T[0],T[T.length-1],v??10)},s.tickFormat=function(v,T){return T==null?w:c(T)},s.nice=function(v){var T=l();return(!v||typeof v.range!="function")&&(v=e(T[0],T[T.length-1],v??10)),v?l(zi(T,v)):s},s.copy=function(){return nr(s,xu(t,e,r,n,o,i,a,f,u,c))},s}function yu(){return _t.apply(xu(Ql,Jl,Kt,Ur,le,Le,Or,Fr,jt,Hr).domain([new Date(2e3,0,1),new Date(2e3,0,2)]),arguments)}function bu(){return _t.apply(xu(Zl,ji,Zt,Wr,pe,vn,Yr,qr,jt,wr).domain([Date.UTC(2e3,0,1),Date.UTC(2e3,0,2)]),arguments)}function Ji(t,e){return e.domain(t.domain()).interpolator(t.interpolator()).clamp(t.clamp()).unknown(t.unknown())}function wu(){var t=0,e=.5,r=1,n=1,o,i,a,f,u,c=re,s,p=!1,l;function m(h){return isNaN(h=+h)?l:(h=.5+((h=+s(h))-i)*(n*h<n*i?f:u),c(p?Math.max(0,Math.min(1,h)):h))}m.domain=function(h){return arguments.length?([t,e,r]=h,o=s(t=+t),i=s(e=+e),a=s(r=+r),f=o===i?0:.5/(i-o),u=i===a?0:.5/(a-i),n=i<o?-1:1,m):[t,e,r]},m.clamp=function(h){return arguments.length?(p=!!h,m):p},m.interpolator=function(h){return arguments.length?(c=h,m):c};function d(h){return function(g){var b,x,M;return arguments.length?([b,x,M]=g,c=je(h,[b,x,M]),m):[c(0),c(.5),c(1)]}}return m.range=d(se),m.rangeRound=d(Nr),m.unknown=function(h){return arguments.length?(l=h,m):l},function(h){return s=h,o=h(t),i=h(e),a=h(r),f=o===i?0:.5/(i-o),u=i===a?0:.5/(a-i),n=i<o?-1:1,m}}function ta(){var t=or(wu()(re));return t.copy=function(){return Ji(t,ta())},so.apply(t,arguments)}function vu(){var t=iu(wu()).domain([.1,1,10]);return t.copy=function(){return Ji(t,vu()).base(t.base())},so.apply(t,arguments)}function Mu(){var t=au(wu());return t.copy=function(){return Ji(t,Mu()).constant(t.constant())},so.apply(t,arguments)}function Su(){var t=fu(wu());return t.copy=function(){return Ji(t,Su()).exponent(t.exponent())},so.apply(t,arguments)}function I(t){for(var e=t.length/6|0,r=new Array(e),n=0;n<e;)r[n]="#"+t.slice(n*6,++n*6);return r}var np=I("1f77b4ff7f0e2ca02cd627289467bd8c564be377c27f7f7fbcbd2217becf");var op=I("7fc97fbeaed4fdc086ffff99386cb0f0027fbf5b17666666");var ip=I("1b9e77d95f027570b3e7298a66a61ee6ab02a6761d666666");var ap=I("a6cee31f78b4b2df8a33a02cfb9a99e31a1cfdbf6fff7f00cab2d66a3d9affff99b15928");var fp=I("fbb4aeb3cde3ccebc5decbe4fed9a6ffffcce5d8bdfddaecf2f2f2");var up=I("b3e2cdfdcdaccbd5e8f4cae4e6f5c9fff2aef1e2cccccccc");var cp=I("e41a1c377eb84daf4a984ea3ff7f00ffff33a65628f781bf999999");var sp=I("66c2a5fc8d628da0cbe78ac3a6d854ffd92fe5c494b3b3b3");var lp=I("8dd3c7ffffb3bebadafb807280b1d3fdb462b3de69fccde5d9d9d9bc80bdccebc5ffed6f");var pp=I("4e79a7f28e2ce1575976b7b259a14fedc949af7aa1ff9da79c755fbab0ab");var H=t=>Fs(t[t.length-1]);var Tu=new Array(3).concat("d8b365f5f5f55ab4ac","a6611adfc27d80cdc1018571","a6611adfc27df5f5f580cdc1018571","8c510ad8b365f6e8c3c7eae55ab4ac01665e","8c510ad8b365f6e8c3f5f5f5c7eae55ab4ac01665e","8c510abf812ddfc27df6e8c3c7eae580cdc135978f01665e","8c510abf812ddfc27df6e8c3f5f5f5c7eae580cdc135978f01665e","5430058c510abf812ddfc27df6e8c3c7eae580cdc135978f01665e003c30","5430058c510abf812ddfc27df6e8c3f5f5f5c7eae580cdc135978f01665e003c30").map(I),_u=H(Tu);var Cu=new Array(3).concat("af8dc3f7f7f77fbf7b","7b3294c2a5cfa6dba0008837","7b3294c2a5cff7f7f7a6dba0008837","762a83af8dc3e7d4e8d9f0d37fbf7b1b7837","762a83af8dc3e7d4e8f7f7f7d9f0d37fbf7b1b7837","762a839970abc2a5cfe7d4e8d9f0d3a6dba05aae611b7837","762a839970abc2a5cfe7d4e8f7f7f7d9f0d3a6dba05aae611b7837","40004b762a839970abc2a5cfe7d4e8d9f0d3a6dba05aae611b783700441b","40004b762a839970abc2a5cfe7d4e8f7f7f7d9f0d3a6dba05aae611b783700441b").map(I),$u=H(Cu);var Au=new Array(3).concat("e9a3c9f7f7f7a1d76a","d01c8bf1b6dab8e1864dac26","d01c8bf1b6daf7f7f7b8e1864dac26","c51b7de9a3c9fde0efe6f5d0a1d76a4d9221","c51b7de9a3c9fde0eff7f7f7e6f5d0a1d76a4d9221","c51b7dde77aef1b6dafde0efe6f5d0b8e1867fbc414d9221","c51b7dde77aef1b6dafde0eff7f7f7e6f5d0b8e1867fbc414d9221","8e0152c51b7dde77aef1b6dafde0efe6f5d0b8e1867fbc414d9221276419","8e0152c51b7dde77aef1b6dafde0eff7f7f7e6f5d0b8e1867fbc414d9221276419").map(I),Eu=H(Au);var ku=new Array(3).concat("998ec3f7f7f7f1a340","5e3c99b2abd2fdb863e66101","5e3c99b2abd2f7f7f7fdb863e66101","542788998ec3d8daebfee0b6f1a340b35806","542788998ec3d8daebf7f7f7fee0b6f1a340b35806","5427888073acb2abd2d8daebfee0b6fdb863e08214b35806","5427888073acb2abd2d8daebf7f7f7fee0b6fdb863e08214b35806","2d004b5427888073acb2abd2d8daebfee0b6fdb863e08214b358067f3b08","2d004b5427888073acb2abd2d8daebf7f7f7fee0b6fdb863e08214b358067f3b08").map(I),Iu=H(ku);var ea=new Array(3).concat("ef8a62f7f7f767a9cf","ca0020f4a58292c5de0571b0","ca0020f4a582f7f7f792c5de0571b0","b2182bef8a62fddbc7d1e5f067a9cf2166ac","b2182bef8a62fddbc7f7f7f7d1e5f067a9cf2166ac","b2182bd6604df4a582fddbc7d1e5f092c5de4393c32166ac","b2182bd6604df4a582fddbc7f7f7f7d1e5f092c5de4393c32166ac","67001fb2182bd6604df4a582fddbc7d1e5f092c5de4393c32166ac053061","67001fb2182bd6604df4a582fddbc7f7f7f7d1e5f092c5de4393c32166ac053061").map(I),ho=H(ea);var Nu=new Array(3).concat("ef8a62ffffff999999","ca0020f4a582bababa404040","ca0020f4a582ffffffbababa404040","b2182bef8a62fddbc7e0e0e09999994d4d4d","b2182bef8a62fddbc7ffffffe0e0e09999994d4d4d","b2182bd6604df4a582fddbc7e0e0e0bababa8787874d4d4d","b2182bd6604df4a582fddbc7ffffffe0e0e0bababa8787874d4d4d","67001fb2182bd6604df4a582fddbc7e0e0e0bababa8787874d4d4d1a1a1a","67001fb2182bd6604df4a582fddbc7ffffffe0e0e0bababa8787874d4d4d1a1a1a").map(I),Du=H(Nu);var ra=new Array(3).concat("fc8d59ffffbf91bfdb","d7191cfdae61abd9e92c7bb6","d7191cfdae61ffffbfabd9e92c7bb6","d73027fc8d59fee090e0f3f891bfdb4575b4","d73027fc8d59fee090ffffbfe0f3f891bfdb4575b4","d73027f46d43fdae61fee090e0f3f8abd9e974add14575b4","d73027f46d43fdae61fee090ffffbfe0f3f8abd9e974add14575b4","a50026d73027f46d43fdae61fee090e0f3f8abd9e974add14575b4313695","a50026d73027f46d43fdae61fee090ffffbfe0f3f8abd9e974add14575b4313695").map(I),go=H(ra);var Ru=new Array(3).concat("fc8d59ffffbf91cf60","d7191cfdae61a6d96a1a9641","d7191cfdae61ffffbfa6d96a1a9641","d73027fc8d59fee08bd9ef8b91cf601a9850","d73027fc8d59fee08bffffbfd9ef8b91cf601a9850","d73027f46d43fdae61fee08bd9ef8ba6d96a66bd631a9850","d73027f46d43fdae61fee08bffffbfd9ef8ba6d96a66bd631a9850","a50026d73027f46d43fdae61fee08bd9ef8ba6d96a66bd631a9850006837","a50026d73027f46d43fdae61fee08bffffbfd9ef8ba6d96a66bd631a9850006837").map(I),Pu=H(Ru);var Lu=new Array(3).concat("fc8d59ffffbf99d594","d7191cfdae61abdda42b83ba","d7191cfdae61ffffbfabdda42b83ba","d53e4ffc8d59fee08be6f59899d5943288bd","d53e4ffc8d59fee08bffffbfe6f59899d5943288bd","d53e4ff46d43fdae61fee08be6f598abdda466c2a53288bd","d53e4ff46d43fdae61fee08bffffbfe6f598abdda466c2a53288bd","9e0142d53e4ff46d43fdae61fee08be6f598abdda466c2a53288bd5e4fa2","9e0142d53e4ff46d43fdae61fee08bffffbfe6f598abdda466c2a53288bd5e4fa2").map(I),Fu=H(Lu);var qu=new Array(3).concat("e5f5f999d8c92ca25f","edf8fbb2e2e266c2a4238b45","edf8fbb2e2e266c2a42ca25f006d2c","edf8fbccece699d8c966c2a42ca25f006d2c","edf8fbccece699d8c966c2a441ae76238b45005824","f7fcfde5f5f9ccece699d8c966c2a441ae76238b45005824","f7fcfde5f5f9ccece699d8c966c2a441ae76238b45006d2c00441b").map(I),Ou=H(qu);var Yu=new Array(3).concat("e0ecf49ebcda8856a7","edf8fbb3cde38c96c688419d","edf8fbb3cde38c96c68856a7810f7c","edf8fbbfd3e69ebcda8c96c68856a7810f7c","edf8fbbfd3e69ebcda8c96c68c6bb188419d6e016b","f7fcfde0ecf4bfd3e69ebcda8c96c68c6bb188419d6e016b","f7fcfde0ecf4bfd3e69ebcda8c96c68c6bb188419d810f7c4d004b").map(I),Bu=H(Yu);var zu=new Array(3).concat("e0f3dba8ddb543a2ca","f0f9e8bae4bc7bccc42b8cbe","f0f9e8bae4bc7bccc443a2ca0868ac","f0f9e8ccebc5a8ddb57bccc443a2ca0868ac","f0f9e8ccebc5a8ddb57bccc44eb3d32b8cbe08589e","f7fcf0e0f3dbccebc5a8ddb57bccc44eb3d32b8cbe08589e","f7fcf0e0f3dbccebc5a8ddb57bccc44eb3d32b8cbe0868ac084081").map(I),Uu=H(zu);var Wu=new Array(3).concat("fee8c8fdbb84e34a33","fef0d9fdcc8afc8d59d7301f","fef0d9fdcc8afc8d59e34a33b30000","fef0d9fdd49efdbb84fc8d59e34a33b30000","fef0d9fdd49efdbb84fc8d59ef6548d7301f990000","fff7ecfee8c8fdd49efdbb84fc8d59ef6548d7301f990000","fff7ecfee8c8fdd49efdbb84fc8d59ef6548d7301fb300007f0000").map(I),Hu=H(Wu);var Gu=new Array(3).concat("ece2f0a6bddb1c9099","f6eff7bdc9e167a9cf02818a","f6eff7bdc9e167a9cf1c9099016c59","f6eff7d0d1e6a6bddb67a9cf1c9099016c59","f6eff7d0d1e6a6bddb67a9cf3690c002818a016450","fff7fbece2f0d0d1e6a6bddb67a9cf3690c002818a016450","fff7fbece2f0d0d1e6a6bddb67a9cf3690c002818a016c59014636").map(I),Xu=H(Gu);var Vu=new Array(3).concat("ece7f2a6bddb2b8cbe","f1eef6bdc9e174a9cf0570b0","f1eef6bdc9e174a9cf2b8cbe045a8d","f1eef6d0d1e6a6bddb74a9cf2b8cbe045a8d","f1eef6d0d1e6a6bddb74a9cf3690c00570b0034e7b","fff7fbece7f2d0d1e6a6bddb74a9cf3690c00570b0034e7b","fff7fbece7f2d0d1e6a6bddb74a9cf3690c00570b0045a8d023858").map(I),ju=H(Vu);var Ku=new Array(3).concat("e7e1efc994c7dd1c77","f1eef6d7b5d8df65b0ce1256","f1eef6d7b5d8df65b0dd1c77980043","f1eef6d4b9dac994c7df65b0dd1c77980043","f1eef6d4b9dac994c7df65b0e7298ace125691003f","f7f4f9e7e1efd4b9dac994c7df65b0e7298ace125691003f","f7f4f9e7e1efd4b9dac994c7df65b0e7298ace125698004367001f").map(I),Zu=H(Ku);var Qu=new Array(3).concat("fde0ddfa9fb5c51b8a","feebe2fbb4b9f768a1ae017e","feebe2fbb4b9f768a1c51b8a7a0177","feebe2fcc5c0fa9fb5f768a1c51b8a7a0177","feebe2fcc5c0fa9fb5f768a1dd3497ae017e7a0177","fff7f3fde0ddfcc5c0fa9fb5f768a1dd3497ae017e7a0177","fff7f3fde0ddfcc5c0fa9fb5f768a1dd3497ae017e7a017749006a").map(I),Ju=H(Qu);var tc=new Array(3).concat("edf8b17fcdbb2c7fb8","ffffcca1dab441b6c4225ea8","ffffcca1dab441b6c42c7fb8253494","ffffccc7e9b47fcdbb41b6c42c7fb8253494","ffffccc7e9b47fcdbb41b6c41d91c0225ea80c2c84","ffffd9edf8b1c7e9b47fcdbb41b6c41d91c0225ea80c2c84","ffffd9edf8b1c7e9b47fcdbb41b6c41d91c0225ea8253494081d58").map(I),ec=H(tc);var rc=new Array(3).concat("f7fcb9addd8e31a354","ffffccc2e69978c679238443","ffffccc2e69978c67931a354006837","ffffccd9f0a3addd8e78c67931a354006837","ffffccd9f0a3addd8e78c67941ab5d238443005a32","ffffe5f7fcb9d9f0a3addd8e78c67941ab5d238443005a32","ffffe5f7fcb9d9f0a3addd8e78c67941ab5d238443006837004529").map(I),nc=H(rc);var oc=new Array(3).concat("fff7bcfec44fd95f0e","ffffd4fed98efe9929cc4c02","ffffd4fed98efe9929d95f0e993404","ffffd4fee391fec44ffe9929d95f0e993404","ffffd4fee391fec44ffe9929ec7014cc4c028c2d04","ffffe5fff7bcfee391fec44ffe9929ec7014cc4c028c2d04","ffffe5fff7bcfee391fec44ffe9929ec7014cc4c02993404662506").map(I),ic=H(oc);var ac=new Array(3).concat("ffeda0feb24cf03b20","ffffb2fecc5cfd8d3ce31a1c","ffffb2fecc5cfd8d3cf03b20bd0026","ffffb2fed976feb24cfd8d3cf03b20bd0026","ffffb2fed976feb24cfd8d3cfc4e2ae31a1cb10026","ffffccffeda0fed976feb24cfd8d3cfc4e2ae31a1cb10026","ffffccffeda0fed976feb24cfd8d3cfc4e2ae31a1cbd0026800026").map(I),fc=H(ac);var uc=new Array(3).concat("deebf79ecae13182bd","eff3ffbdd7e76baed62171b5","eff3ffbdd7e76baed63182bd08519c","eff3ffc6dbef9ecae16baed63182bd08519c","eff3ffc6dbef9ecae16baed64292c62171b5084594","f7fbffdeebf7c6dbef9ecae16baed64292c62171b5084594","f7fbffdeebf7c6dbef9ecae16baed64292c62171b508519c08306b").map(I),cc=H(uc);var sc=new Array(3).concat("e5f5e0a1d99b31a354","edf8e9bae4b374c476238b45","edf8e9bae4b374c47631a354006d2c","edf8e9c7e9c0a1d99b74c47631a354006d2c","edf8e9c7e9c0a1d99b74c47641ab5d238b45005a32","f7fcf5e5f5e0c7e9c0a1d99b74c47641ab5d238b45005a32","f7fcf5e5f5e0c7e9c0a1d99b74c47641ab5d238b45006d2c00441b").map(I),lc=H(sc);var pc=new Array(3).concat("f0f0f0bdbdbd636363","f7f7f7cccccc969696525252","f7f7f7cccccc969696636363252525","f7f7f7d9d9d9bdbdbd969696636363252525","f7f7f7d9d9d9bdbdbd969696737373525252252525","fffffff0f0f0d9d9d9bdbdbd969696737373525252252525","fffffff0f0f0d9d9d9bdbdbd969696737373525252252525000000").map(I),mc=H(pc);var dc=new Array(3).concat("efedf5bcbddc756bb1","f2f0f7cbc9e29e9ac86a51a3","f2f0f7cbc9e29e9ac8756bb154278f","f2f0f7dadaebbcbddc9e9ac8756bb154278f","f2f0f7dadaebbcbddc9e9ac8807dba6a51a34a1486","fcfbfdefedf5dadaebbcbddc9e9ac8807dba6a51a34a1486","fcfbfdefedf5dadaebbcbddc9e9ac8807dba6a51a354278f3f007d").map(I),hc=H(dc);var gc=new Array(3).concat("fee0d2fc9272de2d26","fee5d9fcae91fb6a4acb181d","fee5d9fcae91fb6a4ade2d26a50f15","fee5d9fcbba1fc9272fb6a4ade2d26a50f15","fee5d9fcbba1fc9272fb6a4aef3b2ccb181d99000d","fff5f0fee0d2fcbba1fc9272fb6a4aef3b2ccb181d99000d","fff5f0fee0d2fcbba1fc9272fb6a4aef3b2ccb181da50f1567000d").map(I),xc=H(gc);var yc=new Array(3).concat("fee6cefdae6be6550d","feeddefdbe85fd8d3cd94701","feeddefdbe85fd8d3ce6550da63603","feeddefdd0a2fdae6bfd8d3ce6550da63603","feeddefdd0a2fdae6bfd8d3cf16913d948018c2d04","fff5ebfee6cefdd0a2fdae6bfd8d3cf16913d948018c2d04","fff5ebfee6cefdd0a2fdae6bfd8d3cf16913d94801a636037f2704").map(I),bc=H(yc);function wc(t){return t=Math.max(0,Math.min(1,t)),"rgb("+Math.max(0,Math.min(255,Math.round(-4.54-t*(35.34-t*(2381.73-t*(6402.7-t*(7024.72-t*2710.57)))))))+", "+Math.max(0,Math.min(255,Math.round(32.49+t*(170.73+t*(52.82-t*(131.46-t*(176.58-t*67.37)))))))+", "+Math.max(0,Math.min(255,Math.round(81.24+t*(442.36-t*(2482.43-t*(6167.24-t*(6614.94-t*2475.67)))))))+")"}var vc=Kn(ee(300,.5,0),ee(-240,.5,1));var Sc=Kn(ee(-100,.75,.35),ee(80,1.5,.8)),Tc=Kn(ee(260,.75,.35),ee(80,1.5,.8)),Mc=ee();function _c(t){(t<0||t>1)&&(t-=Math.floor(t));var e=Math.abs(t-.5);return Mc.h=360*t-100,Mc.s=1.5-1.5*e,Mc.l=.8-.9*e,Mc+""}var Cc=ve(),Q6=Math.PI/3,J6=Math.PI*2/3;function $c(t){var e;return t=(.5-t)*Math.PI,Cc.r=255*(e=Math.sin(t))*e,Cc.g=255*(e=Math.sin(t+Q6))*e,Cc.b=255*(e=Math.sin(t+J6))*e,Cc+""}function Ac(t){return t=Math.max(0,Math.min(1,t)),"rgb("+Math.max(0,Math.min(255,Math.round(34.61+t*(1172.33-t*(10793.56-t*(33300.12-t*(38394.49-t*14825.05)))))))+", "+Math.max(0,Math.min(255,Math.round(23.31+t*(557.33+t*(1225.33-t*(3574.96-t*(1073.77+t*707.56)))))))+", "+Math.max(0,Math.min(255,Math.round(27.2+t*(3211.1-t*(15327.97-t*(27814-t*(22569.18-t*6838.66)))))))+")"}function Ec(t){var e=t.length;return function(r){return t[Math.max(0,Math.min(e-1,Math.floor(r*e)))]}}var kc=Ec(I("44015444025645045745055946075a46085c460a5d460b5e470d60470e6147106347116447136548146748166848176948186a481a6c481b6d481c6e481d6f481f70482071482173482374482475482576482677482878482979472a7a472c7a472d7b472e7c472f7d46307e46327e46337f463480453581453781453882443983443a83443b84433d84433e85423f854240864241864142874144874045884046883f47883f48893e49893e4a893e4c8a3d4d8a3d4e8a3c4f8a3c508b3b518b3b528b3a538b3a548c39558c39568c38588c38598c375a8c375b8d365c8d365d8d355e8d355f8d34608d34618d33628d33638d32648e32658e31668e31678e31688e30698e306a8e2f6b8e2f6c8e2e6d8e2e6e8e2e6f8e2d708e2d718e2c718e2c728e2c738e2b748e2b758e2a768e2a778e2a788e29798e297a8e297b8e287c8e287d8e277e8e277f8e27808e26818e26828e26828e25838e25848e25858e24868e24878e23888e23898e238a8d228b8d228c8d228d8d218e8d218f8d21908d21918c20928c20928c20938c1f948c1f958b1f968b1f978b1f988b1f998a1f9a8a1e9b8a1e9c891e9d891f9e891f9f881fa0881fa1881fa1871fa28720a38620a48621a58521a68522a78522a88423a98324aa8325ab8225ac8226ad8127ad8128ae8029af7f2ab07f2cb17e2db27d2eb37c2fb47c31b57b32b67a34b67935b77937b87838b9773aba763bbb753dbc743fbc7340bd7242be7144bf7046c06f48c16e4ac16d4cc26c4ec36b50c46a52c56954c56856c66758c7655ac8645cc8635ec96260ca6063cb5f65cb5e67cc5c69cd5b6ccd5a6ece5870cf5773d05675d05477d1537ad1517cd2507fd34e81d34d84d44b86d54989d5488bd6468ed64590d74393d74195d84098d83e9bd93c9dd93ba0da39a2da37a5db36a8db34aadc32addc30b0dd2fb2dd2db5de2bb8de29bade28bddf26c0df25c2df23c5e021c8e020cae11fcde11dd0e11cd2e21bd5e21ad8e219dae319dde318dfe318e2e418e5e419e7e419eae51aece51befe51cf1e51df4e61ef6e620f8e621fbe723fde725")),Ic=Ec(I("00000401000501010601010802010902020b02020d03030f03031204041405041606051806051a07061c08071e0907200a08220b09240c09260d0a290e0b2b100b2d110c2f120d31130d34140e36150e38160f3b180f3d19103f1a10421c10441d11471e114920114b21114e22115024125325125527125829115a2a115c2c115f2d11612f116331116533106734106936106b38106c390f6e3b0f703d0f713f0f72400f74420f75440f764510774710784910784a10794c117a4e117b4f127b51127c52137c54137d56147d57157e59157e5a167e5c167f5d177f5f187f601880621980641a80651a80671b80681c816a1c816b1d816d1d816e1e81701f81721f817320817521817621817822817922827b23827c23827e24828025828125818326818426818627818827818928818b29818c29818e2a81902a81912b81932b80942c80962c80982d80992d809b2e7f9c2e7f9e2f7fa02f7fa1307ea3307ea5317ea6317da8327daa337dab337cad347cae347bb0357bb2357bb3367ab5367ab73779b83779ba3878bc3978bd3977bf3a77c03a76c23b75c43c75c53c74c73d73c83e73ca3e72cc3f71cd4071cf4070d0416fd2426fd3436ed5446dd6456cd8456cd9466bdb476adc4869de4968df4a68e04c67e24d66e34e65e44f64e55064e75263e85362e95462ea5661eb5760ec5860ed5a5fee5b5eef5d5ef05f5ef1605df2625df2645cf3655cf4675cf4695cf56b5cf66c5cf66e5cf7705cf7725cf8745cf8765cf9785df9795df97b5dfa7d5efa7f5efa815ffb835ffb8560fb8761fc8961fc8a62fc8c63fc8e64fc9065fd9266fd9467fd9668fd9869fd9a6afd9b6bfe9d6cfe9f6dfea16efea36ffea571fea772fea973feaa74feac76feae77feb078feb27afeb47bfeb67cfeb77efeb97ffebb81febd82febf84fec185fec287fec488fec68afec88cfeca8dfecc8ffecd90fecf92fed194fed395fed597fed799fed89afdda9cfddc9efddea0fde0a1fde2a3fde3a5fde5a7fde7a9fde9aafdebacfcecaefceeb0fcf0b2fcf2b4fcf4b6fcf6b8fcf7b9fcf9bbfcfbbdfcfdbf")),Nc=Ec(I("00000401000501010601010802010a02020c02020e03021004031204031405041706041907051b08051d09061f0a07220b07240c08260d08290e092b10092d110a30120a32140b34150b37160b39180c3c190c3e1b0c411c0c431e0c451f0c48210c4a230c4c240c4f260c51280b53290b552b0b572d0b592f0a5b310a5c320a5e340a5f3609613809623909633b09643d09653e0966400a67420a68440a68450a69470b6a490b6a4a0c6b4c0c6b4d0d6c4f0d6c510e6c520e6d540f6d550f6d57106e59106e5a116e5c126e5d126e5f136e61136e62146e64156e65156e67166e69166e6a176e6c186e6d186e6f196e71196e721a6e741a6e751b6e771c6d781c6d7a1d6d7c1d6d7d1e6d7f1e6c801f6c82206c84206b85216b87216b88226a8a226a8c23698d23698f24699025689225689326679526679727669827669a28659b29649d29649f2a63a02a63a22b62a32c61a52c60a62d60a82e5fa92e5eab2f5ead305dae305cb0315bb1325ab3325ab43359b63458b73557b93556ba3655bc3754bd3853bf3952c03a51c13a50c33b4fc43c4ec63d4dc73e4cc83f4bca404acb4149cc4248ce4347cf4446d04545d24644d34743d44842d54a41d74b3fd84c3ed94d3dda4e3cdb503bdd513ade5238df5337e05536e15635e25734e35933e45a31e55c30e65d2fe75e2ee8602de9612bea632aeb6429eb6628ec6726ed6925ee6a24ef6c23ef6e21f06f20f1711ff1731df2741cf3761bf37819f47918f57b17f57d15f67e14f68013f78212f78410f8850ff8870ef8890cf98b0bf98c0af98e09fa9008fa9207fa9407fb9606fb9706fb9906fb9b06fb9d07fc9f07fca108fca309fca50afca60cfca80dfcaa0ffcac11fcae12fcb014fcb216fcb418fbb61afbb81dfbba1ffbbc21fbbe23fac026fac228fac42afac62df9c72ff9c932f9cb35f8cd37f8cf3af7d13df7d340f6d543f6d746f5d949f5db4cf4dd4ff4df53f4e156f3e35af3e55df2e661f2e865f2ea69f1ec6df1ed71f1ef75f1f179f2f27df2f482f3f586f3f68af4f88ef5f992f6fa96f8fb9af9fc9dfafda1fcffa4")),Dc=Ec(I("0d088710078813078916078a19068c1b068d1d068e20068f2206902406912605912805922a05932c05942e05952f059631059733059735049837049938049a3a049a3c049b3e049c3f049c41049d43039e44039e46039f48039f4903a04b03a14c02a14e02a25002a25102a35302a35502a45601a45801a45901a55b01a55c01a65e01a66001a66100a76300a76400a76600a76700a86900a86a00a86c00a86e00a86f00a87100a87201a87401a87501a87701a87801a87a02a87b02a87d03a87e03a88004a88104a78305a78405a78606a68707a68808a68a09a58b0aa58d0ba58e0ca48f0da4910ea3920fa39410a29511a19613a19814a099159f9a169f9c179e9d189d9e199da01a9ca11b9ba21d9aa31e9aa51f99a62098a72197a82296aa2395ab2494ac2694ad2793ae2892b02991b12a90b22b8fb32c8eb42e8db52f8cb6308bb7318ab83289ba3388bb3488bc3587bd3786be3885bf3984c03a83c13b82c23c81c33d80c43e7fc5407ec6417dc7427cc8437bc9447aca457acb4679cc4778cc4977cd4a76ce4b75cf4c74d04d73d14e72d24f71d35171d45270d5536fd5546ed6556dd7566cd8576bd9586ada5a6ada5b69db5c68dc5d67dd5e66de5f65de6164df6263e06363e16462e26561e26660e3685fe4695ee56a5de56b5de66c5ce76e5be76f5ae87059e97158e97257ea7457eb7556eb7655ec7754ed7953ed7a52ee7b51ef7c51ef7e50f07f4ff0804ef1814df1834cf2844bf3854bf3874af48849f48948f58b47f58c46f68d45f68f44f79044f79143f79342f89441f89540f9973ff9983ef99a3efa9b3dfa9c3cfa9e3bfb9f3afba139fba238fca338fca537fca636fca835fca934fdab33fdac33fdae32fdaf31fdb130fdb22ffdb42ffdb52efeb72dfeb82cfeba2cfebb2bfebd2afebe2afec029fdc229fdc328fdc527fdc627fdc827fdca26fdcb26fccd25fcce25fcd025fcd225fbd324fbd524fbd724fad824fada24f9dc24f9dd25f8df25f8e125f7e225f7e425f6e626f6e826f5e926f5eb27f4ed27f3ee27f3f027f2f227f1f426f1f525f0f724f0f921"));var mp=Math.cos;var xo=Math.min,na=Math.sin,nt=Math.sqrt;var Tn=Math.PI,kz=Tn/2,yo=2*Tn;var t8=nt(3),oa={draw(t,e){let r=nt(e+xo(e/28,.75))*.59436,n=r/2,o=n*t8;t.moveTo(0,r),t.lineTo(0,-r),t.moveTo(-o,-n),t.lineTo(o,n),t.moveTo(-o,n),t.lineTo(o,-n)}};var bo={draw(t,e){let r=nt(e/Tn);t.moveTo(r,0),t.arc(0,0,r,0,yo)}};var ia={draw(t,e){let r=nt(e/5)/2;t.moveTo(-3*r,-r),t.lineTo(-r,-r),t.lineTo(-r,-3*r),t.lineTo(r,-3*r),t.lineTo(r,-r),t.lineTo(3*r,-r),t.lineTo(3*r,r),t.lineTo(r,r),t.lineTo(r,3*r),t.lineTo(-r,3*r),t.lineTo(-r,r),t.lineTo(-3*r,r),t.closePath()}};var tg=nt(1/3),e8=tg*2,aa={draw(t,e){let r=nt(e/e8),n=r*tg;t.moveTo(0,-r),t.lineTo(n,0),t.lineTo(0,r),t.lineTo(-n,0),t.closePath()}};var fa={draw(t,e){let r=nt(e)*.62625;t.moveTo(0,-r),t.lineTo(r,0),t.lineTo(0,r),t.lineTo(-r,0),t.closePath()}};var ua={draw(t,e){let r=nt(e-xo(e/7,2))*.87559;t.moveTo(-r,0),t.lineTo(r,0),t.moveTo(0,r),t.lineTo(0,-r)}};var ca={draw(t,e){let r=nt(e),n=-r/2;t.rect(n,n,r,r)}};var sa={draw(t,e){let r=nt(e)*.4431;t.moveTo(r,r),t.lineTo(r,-r),t.lineTo(-r,-r),t.lineTo(-r,r),t.closePath()}};var r8=.8908130915292852,eg=na(Tn/10)/na(7*Tn/10),n8=na(yo/10)*eg,o8=-mp(yo/10)*eg,la={draw(t,e){let r=nt(e*r8),n=n8*r,o=o8*r;t.moveTo(0,-r),t.lineTo(n,o);for(let i=1;i<5;++i){let a=yo*i/5,f=mp(a),u=na(a);t.lineTo(u*r,-f*r),t.lineTo(f*n-u*o,u*n+f*o)}t.closePath()}};var dp=nt(3),pa={draw(t,e){let r=-nt(e/(dp*3));t.moveTo(0,r*2),t.lineTo(-dp*r,-r),t.lineTo(dp*r,-r),t.closePath()}};var i8=nt(3),ma={draw(t,e){let r=nt(e)*.6824,n=r/2,o=r*i8/2;t.moveTo(0,-r),t.lineTo(o,n),t.lineTo(-o,n),t.closePath()}};var Te=-.5,_e=nt(3)/2,hp=1/nt(12),a8=(hp/2+1)*3,da={draw(t,e){let r=nt(e/a8),n=r/2,o=r*hp,i=n,a=r*hp+r,f=-i,u=a;t.moveTo(n,o),t.lineTo(i,a),t.lineTo(f,u),t.lineTo(Te*n-_e*o,_e*n+Te*o),t.lineTo(Te*i-_e*a,_e*i+Te*a),t.lineTo(Te*f-_e*u,_e*f+Te*u),t.lineTo(Te*n+_e*o,Te*o-_e*n),t.lineTo(Te*i+_e*a,Te*a-_e*i),t.lineTo(Te*f+_e*u,Te*u-_e*f),t.closePath()}};var ha={draw(t,e){let r=nt(e-xo(e/6,1.7))*.6189;t.moveTo(-r,-r),t.lineTo(r,r),t.moveTo(-r,r),t.lineTo(r,-r)}};var gp=[bo,ia,aa,ca,la,pa,da],xp=[bo,ua,ha,ma,oa,sa,fa];function _n(t,e,r){this.k=t,this.x=e,this.y=r}_n.prototype={constructor:_n,scale:function(t){return t===1?this:new _n(this.k*t,this.x,this.y)},translate:function(t,e){return t===0&e===0?this:new _n(this.k,this.x+this.k*t,this.y+this.k*e)},apply:function(t){return[t[0]*this.k+this.x,t[1]*this.k+this.y]},applyX:function(t){return t*this.k+this.x},applyY:function(t){return t*this.k+this.y},invert:function(t){return[(t[0]-this.x)/this.k,(t[1]-this.y)/this.k]},invertX:function(t){return(t-this.x)/this.k},invertY:function(t){return(t-this.y)/this.k},rescaleX:function(t){return t.copy().domain(t.range().map(this.invertX,this).map(t.invert,t))},rescaleY:function(t){return t.copy().domain(t.range().map(this.invertY,this).map(t.invert,t))},toString:function(){return"translate("+this.x+","+this.y+") scale("+this.k+")"}};var rg=new _n(1,0,0);yp.prototype=_n.prototype;function yp(t){for(;!t.__zoom;)if(!(t=t.parentNode))return rg;return t.__zoom}function Fe(t){return t!=null&&!Number.isNaN(t)}function Qt(t,e){return+Fe(e)-+Fe(t)||bt(t,e)}function Cn(t,e){return+Fe(e)-+Fe(t)||be(t,e)}function Rc(t){return t!=null&&`${t}`!=""}function bp(t){return isFinite(t)?t:NaN}function ga(t){return t>0&&isFinite(t)?t:NaN}function Pc(t){return t<0&&isFinite(t)?t:NaN}function Lc(t,e){if(t instanceof Date||(t=new Date(+t)),isNaN(t))return typeof e=="function"?e(t):e;let r=t.getUTCHours(),n=t.getUTCMinutes(),o=t.getUTCSeconds(),i=t.getUTCMilliseconds();return`${u8(t.getUTCFullYear(),4)}-${vr(t.getUTCMonth()+1,2)}-${vr(t.getUTCDate(),2)}${r||n||o||i?`T${vr(r,2)}:${vr(n,2)}${o||i?`:${vr(o,2)}${i?`.${vr(i,3)}`:""}`:""}Z`:""}`}function u8(t){return t<0?`-${vr(-t,6)}`:t>9999?`+${vr(t,6)}`:vr(t,4)}function vr(t,e){return`${t}`.padStart(e,"0")}var c8=/^(?:[-+]\d{2})?\d{4}(?:-\d{2}(?:-\d{2})?)?(?:T\d{2}:\d{2}(?::\d{2}(?:\.\d{3})?)?(?:Z|[-+]\d{2}:?\d{2})?)?$/;function xa(t,e){return c8.test(t+="")?new Date(t):typeof e=="function"?e(t):e}var Fc=1e3,wp=Fc*60,vp=wp*60,ba=vp*24,s8=ba*7,ng=ba*30,l8=ba*365,ya=[["millisecond",.5*Fc],["second",Fc],["second",30*Fc],["minute",wp],["minute",30*wp],["hour",vp],["hour",12*vp],["day",ba],["day",2*ba],["week",s8],["month",ng],["month",3*ng],["year",l8]],p8=new Map([["second",jt],["minute",Fr],["hour",Or],["day",Le],["week",le],["month",Ur],["year",Kt],["monday",Br],["tuesday",cu],["wednesday",su],["thursday",ir],["friday",lu],["saturday",pu],["sunday",le]]),m8=new Map([["second",jt],["minute",qr],["hour",Yr],["day",po],["week",pe],["month",Wr],["year",Zt],["monday",zr],["tuesday",mu],["wednesday",du],["thursday",ar],["friday",hu],["saturday",gu],["sunday",pe]]);function og(t,e){let r=`${t}`.toLowerCase();r.endsWith("s")&&(r=r.slice(0,-1));let n=1,o=/^(?:(\d+)\s+)/.exec(r);switch(o&&(r=r.slice(o[0].length),n=+o[1]),r){case"quarter":r="month",n*=3;break;case"half":r="month",n*=6;break}let i=e.get(r);if(!i)throw new Error(`unknown interval: ${t}`);if(!(n>1))return i;if(!i.every)throw new Error(`non-periodic interval: ${r}`);return i.every(n)}function ig(t){return og(t,p8)}function qc(t){return og(t,m8)}function ag(t){if(!t)return!1;let e=t.floor(new Date(Date.UTC(2e3,11,31)));return Zt(e)>=e}function fg(t){if(!t)return!1;let e=t.floor(new Date(2e3,11,31));return Kt(e)>=e}function ug(t,e,r,n){let o=t.type==="time"?Hr:wr,i=n==="left"||n==="right"?(a,f)=>`
 ${a}
-${f}`:r==="top"?(a,f)=>`${f}
+${f}`:n==="top"?(a,f)=>`${f}
 ${a}`:(a,f)=>`${a}
-${f}`;switch(Pv(t,e,n)){case"millisecond":return Sr(o(".%L"),o(":%M:%S"),i);case"second":return Sr(o(":%S"),o("%-I:%M"),i);case"minute":return Sr(o("%-I:%M"),o("%p"),i);case"hour":return Sr(o("%-I %p"),o("%b %-d"),i);case"day":return Sr(o("%-d"),o("%b"),i);case"week":return Sr(o("%-d"),o("%b"),i);case"month":return Sr(o("%b"),o("%Y"),i);case"year":return o("%Y")}throw new Error("unable to format time ticks")}function Pv(t,e,n){let r=sn(Va(e,(u,c)=>Math.abs(c-u)||NaN));if(r>0)return ma[qe(([,u])=>u).right(ma,r,1,ma.length)-1][0];let[o,i]=Ct(t.domain()),a=typeof n=="number"?n:10,f=Math.abs(i-o)/a;return ma[qe(([,u])=>Math.log(u)).center(ma,Math.log(f))][0]}function Sr(t,e,n){return(r,o,i)=>{let a=t(r,o),f=e(r,o),u=o-vn(i);return o!==u&&i[u]!==void 0&&f===e(i[u],u)?a:n(a,f)}}var ga=Object.getPrototypeOf(Uint8Array),Ov=Object.prototype.toString;function gt(t,e,n){let r=typeof e;return r==="string"?_g(t,vd(e),n):r==="function"?_g(t,e,n):r==="number"||e instanceof Date||r==="boolean"?pt(t,Hn(e),n):typeof e?.transform=="function"?Cg(e.transform(t),n):Cg(e,n)}function _g(t,e,n){return pt(t,n?.prototype instanceof ga?Fv(e):e,n)}function Cg(t,e){return e===void 0?kt(t):t instanceof e?t:e.prototype instanceof ga&&!(t instanceof ga)?e.from(t,Md):e.from(t)}function Fv(t){return(e,n)=>Md(t(e,n))}var xo=[null],vd=t=>e=>e[t],Ec={transform:ne},wt={transform:t=>t};var Ag=()=>1,$g=()=>!0,Et=t=>t==null?t:`${t}`,ct=t=>t==null?t:+t;var xa=t=>t?t[0]:void 0,Ic=t=>t?t[1]:void 0;var Hn=t=>()=>t;function Eg(t){let e=+`${t}`.slice(1)/100;return(n,r)=>ye(n,e,r)}function Tr(t){return t instanceof ga?t:pt(t,Md,Float64Array)}function Md(t){return t==null?NaN:Number(t)}function Ig(t){return pt(t,Sd)}function Sd(t){return t instanceof Date&&!isNaN(t)?t:typeof t=="string"?pa(t):t==null||isNaN(t=+t)?void 0:new Date(t)}function ie(t,e){return t===void 0&&(t=e),t===null?[void 0,"none"]:vo(t)?[void 0,t]:[t,void 0]}function It(t,e){return t===void 0&&(t=e),t===null||typeof t=="number"?[void 0,t]:[t,void 0]}function _r(t,e,n){if(t!=null)return le(t,e,n)}function le(t,e,n){let r=`${t}`.toLowerCase();if(!n.includes(r))throw new Error(`invalid ${e}: ${t}`);return r}function kt(t){return t==null||t instanceof Array||t instanceof ga?t:Array.from(t)}function pt(t,e,n=Array){return t==null?t:t instanceof n?t.map(e):n.from(t,e)}function ya(t,e=Array){return t instanceof e?t.slice():e.from(t)}function kg({x:t,x1:e,x2:n}){return t!==void 0||e!==void 0||n!==void 0}function Td({y:t,y1:e,y2:n}){return t!==void 0||e!==void 0||n!==void 0}function Ng(t){return kg(t)||Td(t)||t.interval!==void 0}function Mn(t){return t?.toString===Ov}function yo(t){return Mn(t)&&(t.type!==void 0||t.domain!==void 0)}function ba(t){return Mn(t)&&typeof t.transform!="function"}function Cr(t){return ba(t)&&t.value===void 0&&t.channel===void 0}function Dg(t,e,n,r=wt){return e===void 0&&n===void 0?(e=0,n=t===void 0?r:t):e===void 0?e=t===void 0?0:t:n===void 0&&(n=t===void 0?0:t),[e,n]}function Ar(t,e){return t===void 0&&e===void 0?[xa,Ic]:[t,e]}function Rg({z:t,fill:e,stroke:n}={}){return t===void 0&&([t]=ie(e)),t===void 0&&([t]=ie(n)),t}function ne(t){let e=t.length,n=new Uint32Array(e);for(let r=0;r<e;++r)n[r]=r;return n}function _d(t,e){return pt(e,n=>t[n])}function Lg(t,e,n){return t.subarray?t.subarray(e,n):t.slice(e,n)}function bo(t){return t!==null&&typeof t=="object"?t.valueOf():t}function Pg(t,e){if(e[t]!==void 0)return e[t];switch(t){case"x1":case"x2":t="x";break;case"y1":case"y2":t="y";break}return e[t]}function wo(t){let e;return[{transform:()=>e,label:Le(t)},n=>e=n]}function Te(t){return t==null?[t]:wo(t)}function Le(t,e){return typeof t=="string"?t:t&&t.label!==void 0?t.label:e}function wa(t,e){return{transform(n){let r=t.transform(n),o=e.transform(n);return Yt(r)||Yt(o)?pt(r,(i,a)=>new Date((+r[a]+ +o[a])/2)):pt(r,(i,a)=>(+r[a]+ +o[a])/2,Float64Array)},label:t.label}}function va(t,e){let n=Cd(e?.interval,e?.type);return n?pt(t,n):t}function Cd(t,e){let n=kc(t,e);return n&&(r=>Re(r)?n.floor(r):r)}function kc(t,e){if(t!=null){if(typeof t=="number"){0<t&&t<1&&Number.isInteger(1/t)&&(t=-1/t);let n=Math.abs(t);return t<0?{floor:r=>Math.floor(r*n)/n,offset:r=>(r*n+1)/n,range:(r,o)=>er(Math.ceil(r*n),o*n).map(i=>i/n)}:{floor:r=>Math.floor(r/n)*n,offset:r=>r+n,range:(r,o)=>er(Math.ceil(r/n),o/n).map(i=>i*n)}}if(typeof t=="string")return(e==="time"?vg:$c)(t);if(typeof t.floor!="function")throw new Error("invalid interval; missing floor method");if(typeof t.offset!="function")throw new Error("invalid interval; missing offset method");return t}}function rn(t,e){if(t=kc(t,e),t&&typeof t.range!="function")throw new Error("invalid interval: missing range method");return t}function Og(t,e){if(t=rn(t,e),t&&typeof t.ceil!="function")throw new Error("invalid interval: missing ceil method");return t}function on(t){return t===void 0||ba(t)?t:{value:t}}function Fg(t){return t==null?null:{transform:e=>gt(e,t,Float64Array),label:Le(t)}}function re(t){return t&&typeof t[Symbol.iterator]=="function"}function Nc(t){for(let e of t)if(e!=null)return typeof e!="object"||e instanceof Date}function Ad(t){for(let e of t){if(e==null)continue;let n=typeof e;return n==="string"||n==="boolean"}}function Yt(t){for(let e of t)if(e!=null)return e instanceof Date}function qg(t){for(let e of t)if(e!=null)return typeof e=="string"&&isNaN(e)&&pa(e)}function zg(t){for(let e of t)if(e!=null){if(typeof e!="string")return!1;if(e.trim())return!isNaN(e)}}function Yg(t){for(let e of t)if(e!=null)return typeof e=="number"}function Dc(t,e){let n;for(let r of t)if(r!=null){if(!e(r))return!1;n=!0}return n}function vo(t){return typeof t!="string"?!1:(t=t.toLowerCase().trim(),t==="none"||t==="currentcolor"||t.startsWith("url(")&&t.endsWith(")")||t.startsWith("var(")&&t.endsWith(")")||ue(t)!==null)}function Bg(t){return typeof t=="number"&&(0<=t&&t<=1||isNaN(t))}function Pt(t){return t==null||$r(t)}function $r(t){return/^\s*none\s*$/i.test(t)}function Ug(t){return/^\s*round\s*$/i.test(t)}function $d(t,e){return _r(t,e,["middle","top-left","top","top-right","right","bottom-right","bottom","bottom-left","left"])}function Mo(t="middle"){return $d(t,"frameAnchor")}function vn(t){if(t==null)return;let e=t[0],n=t[t.length-1];return xe(e,n)}function Xg(t={},...e){let n=t;for(let r of e)for(let o in r)if(n[o]===void 0){let i=r[o];n===t?n={...n,[o]:i}:n[o]=i}return n}function qv(t){console.warn("named iterables are deprecated; please use an object instead");let e=new Set;return Object.fromEntries(Array.from(t,n=>{let{name:r}=n;if(r==null)throw new Error("missing name");let o=`${r}`;if(o==="__proto__")throw new Error(`illegal name: ${o}`);if(e.has(o))throw new Error(`duplicate name: ${o}`);return e.add(o),[r,n]}))}function Ed(t){return re(t)?qv(t):t}var de=Symbol("position"),pe=Symbol("color"),So=Symbol("radius"),To=Symbol("length"),_o=Symbol("opacity"),Co=Symbol("symbol"),zv=Symbol("projection"),st=new Map([["x",de],["y",de],["fx",de],["fy",de],["r",So],["color",pe],["opacity",_o],["symbol",Co],["length",To],["projection",zv]]);var Yv=Math.sqrt(3),Bv=2/Yv,Uv={draw(t,e){let n=Math.sqrt(e/Math.PI),r=n*Bv,o=r/2;t.moveTo(0,r),t.lineTo(n,o),t.lineTo(n,-o),t.lineTo(0,-r),t.lineTo(-n,-o),t.lineTo(-n,o),t.closePath()}},Hg=new Map([["asterisk",ea],["circle",ho],["cross",na],["diamond",ra],["diamond2",oa],["hexagon",Uv],["plus",ia],["square",aa],["square2",fa],["star",ua],["times",go],["triangle",ca],["triangle2",sa],["wye",la]]);function Wg(t){return t&&typeof t.draw=="function"}function Gg(t){return Wg(t)?!0:typeof t!="string"?!1:Hg.has(t.toLowerCase())}function Ao(t){if(t==null||Wg(t))return t;let e=Hg.get(`${t}`.toLowerCase());if(e)return e;throw new Error(`invalid symbol: ${t}`)}function $o({filter:t,sort:e,reverse:n,transform:r,initializer:o,...i}={},a){if(r===void 0&&(t!=null&&(r=Vg(t)),e!=null&&!Cr(e)&&(r=Id(r,Zg(e))),n&&(r=Id(r,jg))),a!=null&&o!=null)throw new Error("transforms cannot be applied after initializers");return{...i,...(e===null||Cr(e))&&{sort:e},transform:Id(r,a)}}function Er({filter:t,sort:e,reverse:n,initializer:r,...o}={},i){return r===void 0&&(t!=null&&(r=Vg(t)),e!=null&&!Cr(e)&&(r=kd(r,Zg(e))),n&&(r=kd(r,jg))),{...o,...(e===null||Cr(e))&&{sort:e},initializer:kd(r,i)}}function Id(t,e){return t==null?e===null?void 0:e:e==null?t===null?void 0:t:function(n,r,o){return{data:n,facets:r}=t.call(this,n,r,o),e.call(this,kt(n),r,o)}}function kd(t,e){return t==null?e===null?void 0:e:e==null?t===null?void 0:t:function(n,r,o,...i){let a,f,u,c,s,d;return{data:f=n,facets:u=r,channels:a}=t.call(this,n,r,o,...i),{data:s=f,facets:d=u,channels:c}=e.call(this,f,u,{...o,...a},...i),{data:s,facets:d,channels:{...a,...c}}}}function Vg(t){return(e,n)=>{let r=gt(e,t);return{data:e,facets:n.map(o=>o.filter(i=>r[i]))}}}function jg(t,e){return{data:t,facets:e.map(n=>n.slice().reverse())}}function Zg(t){return(typeof t=="function"&&t.length!==1?Xv:Hv)(t)}function Xv(t){return(e,n)=>{let r=(o,i)=>t(e[o],e[i]);return{data:e,facets:n.map(o=>o.slice().sort(r))}}}function Hv(t){let e,n;({channel:e,value:t,order:n}={...on(t)});let r=e?.startsWith("-");if(r&&(e=e.slice(1)),n===void 0&&(n=r?Mr:Kt),typeof n!="function")switch(`${n}`.toLowerCase()){case"ascending":n=Kt;break;case"descending":n=Mr;break;default:throw new Error(`invalid order: ${n}`)}return(o,i,a)=>{let f;if(e===void 0)f=gt(o,t);else{if(a===void 0)throw new Error("channel sort requires an initializer");if(f=a[e],!f)return{};f=f.value}let u=(c,s)=>n(f[c],f[s]);return{data:o,facets:i.map(c=>c.slice().sort(u))}}}function Sa(t,...e){for(let{name:n}of t)if(e.includes(n))return!0;return!1}function Jg(t,e,n=Nd){let r=Object.entries(t);return e.title!=null&&t.title===void 0&&r.push(["title",Zv]),e.href!=null&&t.href===void 0&&r.push(["href",Pd]),r.filter(([,o])=>o!==void 0).map(([o,i])=>i===null?Wv(o):n(o,i,e))}function Nd(t,e,n,r=Dd){let o;Mn(e)&&"reduce"in e&&(o=e.scale,e=e.reduce);let i=r(t,e,n),[a,f]=wo(i.label),u;return{name:t,output:o===void 0?a:{value:a,scale:o},initialize(c){i.initialize(c),u=f([])},scope(c,s){i.scope(c,s)},reduce(c,s){u.push(i.reduce(c,s))}}}function Wv(t){return{name:t,initialize(){},scope(){},reduce(){}}}function Dd(t,e,n,r=Ta){let o=Pg(t,n),i=r(e,o),a,f;return{label:Le(i===Ma?null:o,i.label),initialize(u){a=o===void 0?u:gt(u,o),i.scope==="data"&&(f=i.reduceIndex(ne(u),a))},scope(u,c){i.scope===u&&(f=i.reduceIndex(c,a))},reduce(u,c){return i.scope==null?i.reduceIndex(u,a,c):i.reduceIndex(u,a,f,c)}}}function Rd(t,e){return e?ze(cn(t,n=>e[n]),xa):[[,t]]}function Ta(t,e,n=Gv){if(t==null)return n(t);if(typeof t.reduceIndex=="function")return t;if(typeof t.reduce=="function"&&Mn(t))return Vv(t);if(typeof t=="function")return jv(t);if(/^p\d{2}$/i.test(t))return Sn(Eg(t));switch(`${t}`.toLowerCase()){case"first":return Pd;case"last":return Kv;case"identity":return Ld;case"count":return Ma;case"distinct":return Qv;case"sum":return e==null?Ma:Jv;case"proportion":return Qg(e,"data");case"proportion-facet":return Qg(e,"facet");case"deviation":return Sn(Lr);case"min":return Sn(Jt);case"min-index":return Sn(Ua);case"max":return Sn(Ht);case"max-index":return Sn(Ba);case"mean":return Kg(Wa);case"median":return Kg(sn);case"variance":return Sn(Rr);case"mode":return Sn(Ga)}return n(t)}function Gv(t){throw new Error(`invalid reduce: ${t}`)}function t1(t,e){for(let n in e){let r=e[n];if(r!==void 0&&!t.some(o=>o.name===n))return r}}function e1(t,e,n){if(e){let r=e.output.transform(),o=(i,a)=>Kt(r[i],r[a]);t.forEach(i=>i.sort(o))}n&&t.forEach(r=>r.reverse())}function Vv(t){return console.warn("deprecated reduce interface; implement reduceIndex instead."),{...t,reduceIndex:t.reduce.bind(t)}}function jv(t){return{reduceIndex(e,n,r){return t(_d(n,e),r)}}}function Sn(t){return{reduceIndex(e,n){return t(e,r=>n[r])}}}function Kg(t){return{reduceIndex(e,n){let r=t(e,o=>n[o]);return Yt(n)?new Date(r):r}}}var Ld={reduceIndex(t,e){return _d(e,t)}},Pd={reduceIndex(t,e){return e[t[0]]}},Zv={reduceIndex(t,e){let r=ze(Ae(t,i=>i.length,i=>e[i]),Ic),o=r.slice(-5).reverse();if(o.length<r.length){let i=r.slice(0,-4);o[5-1]=[`\u2026 ${i.length.toLocaleString("en-US")} more`,fe(i,Ic)]}return o.map(([i,a])=>`${i} (${a.toLocaleString("en-US")})`).join(`
-`)}},Kv={reduceIndex(t,e){return e[t[t.length-1]]}},Ma={label:"Frequency",reduceIndex(t){return t.length}},Qv={label:"Distinct",reduceIndex(t,e){let n=new Ce;for(let r of t)n.add(e[r]);return n.size}},Jv=Sn(fe);function Qg(t,e){return t==null?{scope:e,label:"Frequency",reduceIndex:(n,r,o=1)=>n.length/o}:{scope:e,reduceIndex:(n,r,o=1)=>fe(n,i=>r[i])/o}}function Eo(t,{scale:e,type:n,value:r,filter:o,hint:i},a){return i===void 0&&typeof r?.transform=="function"&&(i=r.hint),Fd(a,{scale:e,type:n,value:gt(t,r),label:Le(r),filter:o,hint:i})}function r1(t,e){return Object.fromEntries(Object.entries(t).map(([n,r])=>[n,Eo(e,r,n)]))}function o1(t,e){let n=Object.fromEntries(Object.entries(t).map(([r,{scale:o,value:i}])=>{let a=o==null?null:e[o];return[r,a==null?i:pt(i,a)]}));return n.channels=t,n}function Fd(t,e){let{scale:n,value:r}=e;if(n===!0||n==="auto")switch(t){case"fill":case"stroke":case"color":e.scale=n!==!0&&Dc(r,vo)?null:"color";break;case"fillOpacity":case"strokeOpacity":case"opacity":e.scale=n!==!0&&Dc(r,Bg)?null:"opacity";break;case"symbol":n!==!0&&Dc(r,Gg)?(e.scale=null,e.value=pt(r,Ao)):e.scale="symbol";break;default:e.scale=st.has(t)?t:null;break}else if(n===!1)e.scale=null;else if(n!=null&&!st.has(n))throw new Error(`unknown scale: ${n}`);return e}function i1(t,e,n,r,o){let{order:i,reverse:a,reduce:f=!0,limit:u}=o;for(let c in o){if(!st.has(c))continue;let{value:s,order:d=i,reverse:l=a,reduce:p=f,limit:m=u}=on(o[c]),h=s?.startsWith("-");if(h&&(s=s.slice(1)),d=d===void 0?h!==(s==="width"||s==="height")?f1:a1:n8(d),p==null||p===!1)continue;let g=c==="fx"||c==="fy"?e8(e,r[c]):t8(n,c);if(!g)throw new Error(`missing channel for scale: ${c}`);let b=g.value,[x=0,M=1/0]=re(m)?m:m<0?[m]:[0,m];if(s==null)g.domain=()=>{let y=Array.from(new Ce(b));return l&&(y=y.reverse()),(x!==0||M!==1/0)&&(y=y.slice(x,M)),y};else{let y=s==="data"?t:s==="height"?n1(n,"y1","y2"):s==="width"?n1(n,"x1","x2"):Od(n,s,s==="y"?"y2":s==="x"?"x2":void 0),w=Ta(p===!0?"max":p,y);g.domain=()=>{let v=cs(ne(b),T=>w.reduceIndex(T,y),T=>b[T]);return d&&v.sort(d),l&&v.reverse(),(x!==0||M!==1/0)&&(v=v.slice(x,M)),v.map(xa)}}}}function t8(t,e){for(let n in t){let r=t[n];if(r.scale===e)return r}}function e8(t,e){let n=t.original;if(n===t)return e;let r=e.value,o=e.value=[];for(let i=0;i<n.length;++i){let a=r[n[i][0]];for(let f of t[i])o[f]=a}return e}function n1(t,e,n){let r=Od(t,e),o=Od(t,n);return pt(o,(i,a)=>Math.abs(i-r[a]),Float64Array)}function Od(t,e,n){let r=t[e];if(!r&&n!==void 0&&(r=t[n]),r)return r.value;throw new Error(`missing channel: ${e}`)}function n8(t){if(t==null||typeof t=="function")return t;switch(`${t}`.toLowerCase()){case"ascending":return a1;case"descending":return f1}throw new Error(`invalid order: ${t}`)}function a1([t,e],[n,r]){return Kt(e,r)||Kt(t,n)}function f1([t,e],[n,r]){return Mr(e,r)||Kt(t,n)}function u1(t,e){let n=t[e];if(n){for(;n.source;)n=n.source;return n.source===null?null:n}}function Io(t={}){let{document:e=typeof window<"u"?window.document:void 0}=t;return{document:e}}function yt(t,{document:e}){return mn(dn(t).call(e.documentElement))}var qd=0;function c1(){let t=qd;return qd=0,t}function an(t){console.warn(t),++qd}var l1=Math.PI,Tn=2*l1,s1=.618;function d1({projection:t,inset:e=0,insetTop:n=e,insetRight:r=e,insetBottom:o=e,insetLeft:i=e}={},a){if(t==null)return;if(typeof t.stream=="function")return t;let f,u,c="frame";if(Mn(t)){let w;if({type:t,domain:u,inset:w,insetTop:n=w!==void 0?w:n,insetRight:r=w!==void 0?w:r,insetBottom:o=w!==void 0?w:o,insetLeft:i=w!==void 0?w:i,clip:c=c,...f}=t,t==null)return}typeof t!="function"&&({type:t}=p1(t));let{width:s,height:d,marginLeft:l,marginRight:p,marginTop:m,marginBottom:h}=a,g=s-l-p-i-r,b=d-m-h-n-o;if(t=t?.({width:g,height:b,clip:c,...f}),t==null)return;c=r8(c,l,m,s-p,d-h);let x=l+i,M=m+n,y;if(u!=null){let[[w,v],[T,E]]=Ci(t).bounds(u),I=Math.min(g/(T-w),b/(E-v));I>0?(x-=(I*(w+T)-g)/2,M-=(I*(v+E)-b)/2,y=Ai({point(F,_){this.stream.point(F*I+x,_*I+M)}})):an("Warning: the projection could not be fit to the specified domain; using the default scale.")}return y??=x===0&&M===0?m1():Ai({point(w,v){this.stream.point(w+x,v+M)}}),{stream:w=>t.stream(y.stream(c(w)))}}function p1(t){switch(`${t}`.toLowerCase()){case"albers-usa":return Pe(wl,.7463,.4673);case"albers":return Rc(Ei,.7463,.4673);case"azimuthal-equal-area":return Pe(Ml,4,4);case"azimuthal-equidistant":return Pe(Tl,Tn,Tn);case"conic-conformal":return Rc(Al,Tn,Tn);case"conic-equal-area":return Rc(Dn,6.1702,2.9781);case"conic-equidistant":return Rc(El,7.312,3.6282);case"equal-earth":return Pe(kl,5.4133,2.6347);case"equirectangular":return Pe($l,Tn,l1);case"gnomonic":return Pe(Dl,3.4641,3.4641);case"identity":return{type:m1};case"reflect-y":return{type:o8};case"mercator":return Pe(_l,Tn,Tn);case"orthographic":return Pe(Ll,2,2);case"stereographic":return Pe(Ol,2,2);case"transverse-mercator":return Pe(ql,Tn,Tn);default:throw new Error(`unknown projection type: ${t}`)}}function r8(t,e,n,r,o){if(t===!1||t==null||typeof t=="number")return i=>i;switch(t===!0&&(t="frame"),`${t}`.toLowerCase()){case"frame":return Qr(e,n,r,o);default:throw new Error(`unknown projection clip type: ${t}`)}}function Pe(t,e,n){return{type:({width:r,height:o,rotate:i,precision:a=.15,clip:f})=>{let u=t();return a!=null&&u.precision?.(a),i!=null&&u.rotate?.(i),typeof f=="number"&&u.clipAngle?.(f),u.scale(Math.min(r/e,o/n)),u.translate([r/2,o/2]),u},aspectRatio:n/e}}function Rc(t,e,n){let{type:r,aspectRatio:o}=Pe(t,e,n);return{type:i=>{let{parallels:a,domain:f,width:u,height:c}=i,s=r(i);return a!=null&&(s.parallels(a),f===void 0&&s.fitSize([u,c],{type:"Sphere"})),s},aspectRatio:o}}var m1=Hn({stream:t=>t}),o8=Hn(Ai({point(t,e){this.stream.point(t,-e)}}));function h1(t,e,n,r){let o=n[t],i=n[e],a=o.length,f=n[t]=new Float64Array(a).fill(NaN),u=n[e]=new Float64Array(a).fill(NaN),c,s=r.stream({point(d,l){f[c]=d,u[c]=l}});for(c=0;c<a;++c)s.point(o[c],i[c])}function g1({projection:t}={}){return t==null?!1:typeof t.stream=="function"?!0:(Mn(t)&&(t=t.type),t!=null)}function x1(t){if(typeof t?.stream=="function")return s1;if(Mn(t)&&(t=t.type),t!=null){if(typeof t!="function"){let{aspectRatio:e}=p1(t);if(e)return e}return s1}}function y1(t){let e=[],n=[],r={scale:"x",value:e},o={scale:"y",value:n},i={point(a,f){e.push(a),n.push(f)},lineStart(){},lineEnd(){},polygonStart(){},polygonEnd(){},sphere(){}};for(let a of t.value)ke(a,i);return[r,o]}var S1=new Map([["accent",rd],["category10",nd],["dark2",od],["paired",id],["pastel1",ad],["pastel2",fd],["set1",ud],["set2",cd],["set3",sd],["tableau10",ld]]);function T1(t){return t!=null&&S1.has(`${t}`.toLowerCase())}var b1=new Map([...S1,["brbg",_n(pu,mu)],["prgn",_n(hu,gu)],["piyg",_n(xu,yu)],["puor",_n(bu,wu)],["rdbu",_n(Qi,so)],["rdgy",_n(vu,Mu)],["rdylbu",_n(Ji,lo)],["rdylgn",_n(Su,Tu)],["spectral",_n(_u,Cu)],["burd",w1(Qi,so)],["buylrd",w1(Ji,lo)],["blues",Ot(Ku,Qu)],["greens",Ot(Ju,tc)],["greys",Ot(ec,nc)],["oranges",Ot(fc,uc)],["purples",Ot(rc,oc)],["reds",Ot(ic,ac)],["turbo",Cn(xc)],["viridis",Cn(bc)],["magma",Cn(wc)],["inferno",Cn(vc)],["plasma",Cn(Mc)],["cividis",Cn(cc)],["cubehelix",Cn(sc)],["warm",Cn(dc)],["cool",Cn(pc)],["bugn",Ot(Au,$u)],["bupu",Ot(Eu,Iu)],["gnbu",Ot(ku,Nu)],["orrd",Ot(Du,Ru)],["pubu",Ot(Ou,Fu)],["pubugn",Ot(Lu,Pu)],["purd",Ot(qu,zu)],["rdpu",Ot(Yu,Bu)],["ylgn",Ot(Hu,Wu)],["ylgnbu",Ot(Uu,Xu)],["ylorbr",Ot(Gu,Vu)],["ylorrd",Ot(ju,Zu)],["rainbow",v1(mc)],["sinebow",v1(gc)]]);function Ot(t,e){return({length:n})=>n===1?[t[3][1]]:n===2?[t[3][1],t[3][2]]:(n=Math.max(3,Math.floor(n)),n>9?Wt(e,n):t[n])}function _n(t,e){return({length:n})=>n===2?[t[3][0],t[3][2]]:(n=Math.max(3,Math.floor(n)),n>11?Wt(e,n):t[n])}function w1(t,e){return({length:n})=>n===2?[t[3][2],t[3][0]]:(n=Math.max(3,Math.floor(n)),n>11?Wt(r=>e(1-r),n):t[n].slice().reverse())}function Cn(t){return({length:e})=>Wt(t,Math.max(2,Math.floor(e)))}function v1(t){return({length:e})=>Wt(t,Math.floor(e)+1).slice(0,-1)}function zd(t){let e=`${t}`.toLowerCase();if(!b1.has(e))throw new Error(`unknown ordinal scheme: ${e}`);return b1.get(e)}function _a(t,e){let n=zd(t),r=typeof n=="function"?n({length:e}):n;return r.length!==e?r.slice(0,e):r}function _1(t,e="greys"){let n=new Set,[r,o]=_a(e,2);for(let i of t)if(i!=null)if(i===!0)n.add(o);else if(i===!1)n.add(r);else return;return[...n]}var M1=new Map([["brbg",mu],["prgn",gu],["piyg",yu],["puor",wu],["rdbu",so],["rdgy",Mu],["rdylbu",lo],["rdylgn",Tu],["spectral",Cu],["burd",t=>so(1-t)],["buylrd",t=>lo(1-t)],["blues",Qu],["greens",tc],["greys",nc],["purples",oc],["reds",ac],["oranges",uc],["turbo",xc],["viridis",bc],["magma",wc],["inferno",vc],["plasma",Mc],["cividis",cc],["cubehelix",sc],["warm",dc],["cool",pc],["bugn",$u],["bupu",Iu],["gnbu",Nu],["orrd",Ru],["pubugn",Pu],["pubu",Fu],["purd",zu],["rdpu",Bu],["ylgnbu",Xu],["ylgn",Wu],["ylorbr",Vu],["ylorrd",Zu],["rainbow",mc],["sinebow",gc]]);function ko(t){let e=`${t}`.toLowerCase();if(!M1.has(e))throw new Error(`unknown quantitative scheme: ${e}`);return M1.get(e)}var i8=new Set(["brbg","prgn","piyg","puor","rdbu","rdgy","rdylbu","rdylgn","spectral","burd","buylrd"]);function C1(t){return t!=null&&i8.has(`${t}`.toLowerCase())}var Bd=t=>e=>t(1-e),Yd=[0,1],A1=new Map([["number",ht],["rgb",we],["hsl",Os],["hcl",Fs],["lab",df]]);function Ud(t){let e=`${t}`.toLowerCase();if(!A1.has(e))throw new Error(`unknown interpolator: ${e}`);return A1.get(e)}function Do(t,e,n,{type:r,nice:o,clamp:i,zero:a,domain:f=L1(t,n),unknown:u,round:c,scheme:s,interval:d,range:l=st.get(t)===So?c8(n,f):st.get(t)===To?s8(n,f):st.get(t)===_o?Yd:void 0,interpolate:p=st.get(t)===pe?s==null&&l!==void 0?we:ko(s!==void 0?s:r==="cyclical"?"rainbow":"turbo"):c?fr:ht,reverse:m}){if(d=rn(d,r),(r==="cyclical"||r==="sequential")&&(r="linear"),typeof p!="function"&&(p=Ud(p)),m=!!m,l!==void 0){let h=(f=kt(f)).length,g=(l=kt(l)).length;if(h!==g){if(p.length===1)throw new Error("invalid piecewise interpolator");p=He(p,l),l=void 0}}if(p.length===1?(m&&(p=Bd(p),m=!1),l===void 0&&(l=Float64Array.from(f,(h,g)=>g/(f.length-1)),l.length===2&&(l=Yd)),e.interpolate((l===Yd?Hn:Ca)(p))):e.interpolate(p),a){let[h,g]=Ct(f);(h>0||g<0)&&(f=ya(f),vn(f)!==Math.sign(h)?f[f.length-1]=0:f[0]=0)}return m&&(f=An(f)),e.domain(f).unknown(u),o&&(e.nice(a8(o,r)),f=e.domain()),l!==void 0&&e.range(l),i&&e.clamp(i),{type:r,domain:f,range:l,scale:e,interpolate:p,interval:d}}function a8(t,e){return t===!0?void 0:typeof t=="number"?t:Og(t,e)}function $1(t,e,n){return Do(t,hr(),e,n)}function E1(t,e,n){return Xd(t,e,{...n,exponent:.5})}function Xd(t,e,{exponent:n=1,...r}){return Do(t,Yi().exponent(n),e,{...r,type:"pow"})}function I1(t,e,{base:n=10,domain:r=l8(e),...o}){return Do(t,qi().base(n),e,{...o,domain:r})}function k1(t,e,{constant:n=1,...r}){return Do(t,zi().constant(n),e,r)}function N1(t,e,{range:n,quantiles:r=n===void 0?5:(n=[...n]).length,n:o=r,scheme:i="rdylbu",domain:a=d8(e),unknown:f,interpolate:u,reverse:c}){return n===void 0&&(n=u!==void 0?Wt(u,o):st.get(t)===pe?_a(i,o):void 0),a.length>0&&(a=Bi(a,n===void 0?{length:o}:n).quantiles()),Lc(t,e,{domain:a,range:n,reverse:c,unknown:f})}function D1(t,e,{range:n,n:r=n===void 0?5:(n=[...n]).length,scheme:o="rdylbu",domain:i=L1(t,e),unknown:a,interpolate:f,reverse:u}){let[c,s]=Ct(i),d;return n===void 0?(d=$e(c,s,r),d[0]<=c&&d.splice(0,1),d[d.length-1]>=s&&d.pop(),r=d.length+1,n=f!==void 0?Wt(f,r):st.get(t)===pe?_a(o,r):void 0):(d=Wt(ht(c,s),r+1).slice(1,-1),c instanceof Date&&(d=d.map(l=>new Date(l)))),vn(kt(i))<0&&d.reverse(),Lc(t,e,{domain:d,range:n,reverse:u,unknown:a})}function Lc(t,e,{domain:n=[0],unknown:r,scheme:o="rdylbu",interpolate:i,range:a=i!==void 0?Wt(i,n.length+1):st.get(t)===pe?_a(o,n.length+1):void 0,reverse:f}){n=kt(n);let u=vn(n);if(!isNaN(u)&&!f8(n,u))throw new Error(`the ${t} scale has a non-monotonic domain`);return f&&(a=An(a)),{type:"threshold",scale:Ui(u<0?An(n):n,a===void 0?[]:a).unknown(r),domain:n,range:a}}function f8(t,e){for(let n=1,r=t.length,o=t[0];n<r;++n){let i=xe(o,o=t[n]);if(i!==0&&i!==e)return!1}return!0}function R1(){return{type:"identity",scale:Oi()}}function No(t,e=yd){return t.length?[Jt(t,({value:n})=>n===void 0?n:Jt(n,e)),Ht(t,({value:n})=>n===void 0?n:Ht(n,e))]:[0,1]}function L1(t,e){let n=st.get(t);return(n===So||n===_o||n===To?u8:No)(e)}function u8(t){return[0,t.length?Ht(t,({value:e})=>e===void 0?e:Ht(e,yd)):1]}function c8(t,e){let n=t.find(({radius:a})=>a!==void 0);if(n!==void 0)return[0,n.radius];let r=ye(t,.5,({value:a})=>a===void 0?NaN:ye(a,.25,da)),o=e.map(a=>3*Math.sqrt(a/r)),i=30/Ht(o);return i<1?o.map(a=>a*i):o}function s8(t,e){let n=sn(t,({value:i})=>i===void 0?NaN:sn(i,Math.abs)),r=e.map(i=>12*i/n),o=60/Ht(r);return o<1?r.map(i=>i*o):r}function l8(t){for(let{value:e}of t)if(e!==void 0)for(let n of e){if(n>0)return No(t,da);if(n<0)return No(t,_c)}return[1,10]}function d8(t){let e=[];for(let{value:n}of t)if(n!==void 0)for(let r of n)e.push(r);return e}function Ca(t){return(e,n)=>r=>t(e+r*(n-e))}function Pc(t,e,n,r,{type:o,nice:i,clamp:a,domain:f=No(r),unknown:u,pivot:c=0,scheme:s,range:d,symmetric:l=!0,interpolate:p=st.get(t)===pe?s==null&&d!==void 0?we:ko(s!==void 0?s:"rdbu"):ht,reverse:m}){c=+c,f=kt(f);let[h,g]=f;if(f.length>2&&an(`Warning: the diverging ${t} scale domain contains extra elements.`),xe(h,g)<0&&([h,g]=[g,h],m=!m),h=Math.min(h,c),g=Math.max(g,c),typeof p!="function"&&(p=Ud(p)),d!==void 0&&(p=p.length===1?Ca(p)(...d):He(p,d)),m&&(p=Bd(p)),l){let b=n.apply(c),x=b-n.apply(h),M=n.apply(g)-b;x<M?h=n.invert(b-M):x>M&&(g=n.invert(b+x))}return e.domain([h,c,g]).unknown(u).interpolator(p),a&&e.clamp(a),i&&e.nice(i),{type:o,domain:[h,g],pivot:c,interpolate:p,scale:e}}function P1(t,e,n){return Pc(t,Ki(),p8,e,n)}function O1(t,e,n){return Hd(t,e,{...n,exponent:.5})}function Hd(t,e,{exponent:n=1,...r}){return Pc(t,du().exponent(n=+n),g8(n),e,{...r,type:"diverging-pow"})}function F1(t,e,{base:n=10,pivot:r=1,domain:o=No(e,r<0?_c:da),...i}){return Pc(t,su().base(n=+n),m8,e,{domain:o,pivot:r,...i})}function q1(t,e,{constant:n=1,...r}){return Pc(t,lu().constant(n=+n),x8(n),e,r)}var p8={apply(t){return t},invert(t){return t}},m8={apply:Math.log,invert:Math.exp},h8={apply(t){return Math.sign(t)*Math.sqrt(Math.abs(t))},invert(t){return Math.sign(t)*(t*t)}};function g8(t){return t===.5?h8:{apply(e){return Math.sign(e)*Math.pow(Math.abs(e),t)},invert(e){return Math.sign(e)*Math.pow(Math.abs(e),1/t)}}}function x8(t){return{apply(e){return Math.sign(e)*Math.log1p(Math.abs(e/t))},invert(e){return Math.sign(e)*Math.expm1(Math.abs(e))*t}}}function z1(t,e,n,r){return Do(t,e,n,r)}function Y1(t,e,n){return z1(t,fu(),e,n)}function B1(t,e,n){return z1(t,uu(),e,n)}var Ro=Symbol("ordinal");function X1(t,e,n,{type:r,interval:o,domain:i,range:a,reverse:f,hint:u}){return o=rn(o,r),i===void 0&&(i=j1(n,o,t)),(r==="categorical"||r===Ro)&&(r="ordinal"),f&&(i=An(i)),e.domain(i),a!==void 0&&(typeof a=="function"&&(a=a(i)),e.range(a)),{type:r,domain:i,range:a,scale:e,hint:u,interval:o}}function H1(t,e,{type:n,interval:r,domain:o,range:i,scheme:a,unknown:f,...u}){r=rn(r,n),o===void 0&&(o=j1(e,r,t));let c;if(st.get(t)===Co)c=y8(e),i=i===void 0?b8(c):pt(i,Ao);else if(st.get(t)===pe&&(i===void 0&&(n==="ordinal"||n===Ro)&&(i=_1(o,a),i!==void 0&&(a=void 0)),a===void 0&&i===void 0&&(a=n==="ordinal"?"turbo":"tableau10"),a!==void 0))if(i!==void 0){let s=ko(a),d=i[0],l=i[1]-i[0];i=({length:p})=>Wt(m=>s(d+l*m),p)}else i=zd(a);if(f===Li)throw new Error(`implicit unknown on ${t} scale is not supported`);return X1(t,pr().unknown(f),e,{...u,type:n,domain:o,range:i,hint:c})}function W1(t,e,{align:n=.5,padding:r=.5,...o}){return V1(zl().align(n).padding(r),e,o,t)}function G1(t,e,{align:n=.5,padding:r=.1,paddingInner:o=r,paddingOuter:i=t==="fx"||t==="fy"?0:r,...a}){return V1(Rn().align(n).paddingInner(o).paddingOuter(i),e,a,t)}function V1(t,e,n,r){let{round:o}=n;return o!==void 0&&t.round(o=!!o),t=X1(r,t,e,n),t.round=o,t}function j1(t,e,n){let r=new Ce;for(let{value:o,domain:i}of t){if(i!==void 0)return i();if(o!==void 0)for(let a of o)r.add(a)}if(e!==void 0){let[o,i]=Ct(r).map(e.floor,e);return e.range(o,e.offset(i))}if(r.size>1e4&&st.get(n)===de)throw new Error(`implicit ordinal domain of ${n} scale has more than 10,000 values`);return ze(r,Kt)}function U1(t,e){let n;for(let{hint:r}of t){let o=r?.[e];if(o!==void 0){if(n===void 0)n=o;else if(n!==o)return}}return n}function y8(t){return{fill:U1(t,"fill"),stroke:U1(t,"stroke")}}function b8(t){return Pt(t.fill)?hd:Sc}function Aa(t,{label:e,inset:n=0,insetTop:r=n,insetRight:o=n,insetBottom:i=n,insetLeft:a=n,round:f,nice:u,clamp:c,zero:s,align:d,padding:l,projection:p,facet:{label:m=e}={},...h}={}){let g={};for(let[b,x]of t){let M=h[b],y=M8(b,x,{round:st.get(b)===de?f:void 0,nice:u,clamp:c,zero:s,align:d,padding:l,projection:p,...M});if(y){let{label:w=b==="fx"||b==="fy"?m:e,percent:v,transform:T,inset:E,insetTop:I=E!==void 0?E:b==="y"?r:0,insetRight:F=E!==void 0?E:b==="x"?o:0,insetBottom:_=E!==void 0?E:b==="y"?i:0,insetLeft:R=E!==void 0?E:b==="x"?a:0}=M||{};if(T==null)T=void 0;else if(typeof T!="function")throw new Error("invalid scale transform; not a function");y.percent=!!v,y.label=w===void 0?w8(x,y):w,y.transform=T,b==="x"||b==="fx"?(y.insetLeft=+R,y.insetRight=+F):(b==="y"||b==="fy")&&(y.insetTop=+I,y.insetBottom=+_),g[b]=y}}return g}function Gd(t){return Object.fromEntries(Object.entries(t).filter(([,{scale:e}])=>e).map(([e,{scale:n,type:r,interval:o,label:i}])=>(n.type=r,o!=null&&(n.interval=o),i!=null&&(n.label=i),[e,n])))}function J1(t,e){let{x:n,y:r,fx:o,fy:i}=t,a=o||i?Fc(e):e;o&&Z1(o,a),i&&K1(i,a);let f=o||i?Vd(t,e):e;n&&Z1(n,f),r&&K1(r,f)}function w8(t=[],e){let n;for(let{label:r}of t)if(r!==void 0){if(n===void 0)n=r;else if(n!==r)return}if(n!==void 0)return!fn(e)&&e.percent&&(n=`${n} (%)`),{inferred:!0,toString:()=>n}}function Fc(t){let{marginTop:e,marginRight:n,marginBottom:r,marginLeft:o,width:i,height:a,facet:{marginTop:f,marginRight:u,marginBottom:c,marginLeft:s}}=t;return{marginTop:Math.max(e,f),marginRight:Math.max(n,u),marginBottom:Math.max(r,c),marginLeft:Math.max(o,s),width:i,height:a}}function Vd({fx:t,fy:e},n){let{marginTop:r,marginRight:o,marginBottom:i,marginLeft:a,width:f,height:u}=Fc(n);return{marginTop:r,marginRight:o,marginBottom:i,marginLeft:a,width:t?t.scale.bandwidth()+a+o:f,height:e?e.scale.bandwidth()+r+i:u,facet:{width:f,height:u}}}function Z1(t,e){if(t.range===void 0){let{insetLeft:n,insetRight:r}=t,{width:o,marginLeft:i=0,marginRight:a=0}=e,f=i+n,u=o-a-r;t.range=[f,Math.max(f,u)],fn(t)||(t.range=ex(t)),t.scale.range(t.range)}tx(t)}function K1(t,e){if(t.range===void 0){let{insetTop:n,insetBottom:r}=t,{height:o,marginTop:i=0,marginBottom:a=0}=e,f=i+n,u=o-a-r;t.range=[Math.max(f,u),f],fn(t)?t.range.reverse():t.range=ex(t),t.scale.range(t.range)}tx(t)}function tx(t){t.round===void 0&&T8(t)&&v8(t)<=30&&t.scale.round(!0)}function v8({scale:t}){let e=t.domain().length,[n,r]=t.range(),o=t.paddingInner?t.paddingInner():1,i=t.paddingOuter?t.paddingOuter():t.padding(),a=e-o,f=Math.abs(r-n)/Math.max(1,a+i*2);return(f-Math.floor(f))*a}function ex(t){let e=t.scale.domain().length+jd(t);if(!(e>2))return t.range;let[n,r]=t.range;return Array.from({length:e},(o,i)=>n+i/(e-1)*(r-n))}function M8(t,e=[],n={}){let r=S8(t,e,n);if(n.type===void 0&&n.domain===void 0&&n.range===void 0&&n.interval==null&&t!=="fx"&&t!=="fy"&&fn({type:r})){let o=e.map(({value:i})=>i).filter(i=>i!==void 0);o.some(Yt)?an(`Warning: some data associated with the ${t} scale are dates. Dates are typically associated with a "utc" or "time" scale rather than a "${Lo(r)}" scale. If you are using a bar mark, you probably want a rect mark with the interval option instead; if you are using a group transform, you probably want a bin transform instead. If you want to treat this data as ordinal, you can specify the interval of the ${t} scale (e.g., d3.utcDay), or you can suppress this warning by setting the type of the ${t} scale to "${Lo(r)}".`):o.some(qg)?an(`Warning: some data associated with the ${t} scale are strings that appear to be dates (e.g., YYYY-MM-DD). If these strings represent dates, you should parse them to Date objects. Dates are typically associated with a "utc" or "time" scale rather than a "${Lo(r)}" scale. If you are using a bar mark, you probably want a rect mark with the interval option instead; if you are using a group transform, you probably want a bin transform instead. If you want to treat this data as ordinal, you can suppress this warning by setting the type of the ${t} scale to "${Lo(r)}".`):o.some(zg)&&an(`Warning: some data associated with the ${t} scale are strings that appear to be numbers. If these strings represent numbers, you should parse or coerce them to numbers. Numbers are typically associated with a "linear" scale rather than a "${Lo(r)}" scale. If you want to treat this data as ordinal, you can specify the interval of the ${t} scale (e.g., 1 for integers), or you can suppress this warning by setting the type of the ${t} scale to "${Lo(r)}".`)}switch(n.type=r,r){case"diverging":case"diverging-sqrt":case"diverging-pow":case"diverging-log":case"diverging-symlog":case"cyclical":case"sequential":case"linear":case"sqrt":case"threshold":case"quantile":case"pow":case"log":case"symlog":n=Oc(e,n,Tr);break;case"identity":switch(st.get(t)){case de:n=Oc(e,n,Tr);break;case Co:n=Oc(e,n,_8);break}break;case"utc":case"time":n=Oc(e,n,Ig);break}switch(r){case"diverging":return P1(t,e,n);case"diverging-sqrt":return O1(t,e,n);case"diverging-pow":return Hd(t,e,n);case"diverging-log":return F1(t,e,n);case"diverging-symlog":return q1(t,e,n);case"categorical":case"ordinal":case Ro:return H1(t,e,n);case"cyclical":case"sequential":case"linear":return $1(t,e,n);case"sqrt":return E1(t,e,n);case"threshold":return Lc(t,e,n);case"quantile":return N1(t,e,n);case"quantize":return D1(t,e,n);case"pow":return Xd(t,e,n);case"log":return I1(t,e,n);case"symlog":return k1(t,e,n);case"utc":return B1(t,e,n);case"time":return Y1(t,e,n);case"point":return W1(t,e,n);case"band":return G1(t,e,n);case"identity":return st.get(t)===de?R1():{type:"identity"};case void 0:return;default:throw new Error(`unknown scale type: ${r}`)}}function Lo(t){return typeof t=="symbol"?t.description:t}var Q1={toString:()=>"projection"};function S8(t,e,{type:n,domain:r,range:o,scheme:i,pivot:a,projection:f}){if(t==="fx"||t==="fy")return"band";(t==="x"||t==="y")&&f!=null&&(n=Q1);for(let{type:c}of e)if(c!==void 0){if(n===void 0)n=c;else if(n!==c)throw new Error(`scale incompatible with channel: ${n} !== ${c}`)}if(n===Q1)return;if(n!==void 0)return n;if(r===void 0&&!e.some(({value:c})=>c!==void 0))return;let u=st.get(t);if(u===So)return"sqrt";if(u===_o||u===To)return"linear";if(u===Co)return"ordinal";if((r||o||[]).length>2)return Wd(u);if(r!==void 0){if(Ad(r))return Wd(u);if(Yt(r))return"utc"}else{let c=e.map(({value:s})=>s).filter(s=>s!==void 0);if(c.some(Ad))return Wd(u);if(c.some(Yt))return"utc"}if(u===pe){if(a!=null||C1(i))return"diverging";if(T1(i))return"categorical"}return"linear"}function Wd(t){switch(t){case de:return"point";case pe:return Ro;default:return"ordinal"}}function qc({type:t}){return t==="time"||t==="utc"}function fn({type:t}){return t==="ordinal"||t==="point"||t==="band"||t===Ro}function jd({type:t}){return t==="threshold"}function T8({type:t}){return t==="point"||t==="band"}function Wn(t){if(t===void 0)return!0;let e=t.domain(),n=t(e[0]);for(let r=1,o=e.length;r<o;++r)if(t(e[r])-n)return!1;return!0}function Oc(t,{domain:e,...n},r){for(let o of t)o.value!==void 0&&(o.value=r(o.value));return{domain:e===void 0?e:r(e),...n}}function _8(t){return pt(t,Ao)}function nx(t){return e=>{if(!st.has(e=`${e}`))throw new Error(`unknown scale: ${e}`);return e in t?C8(t[e]):void 0}}function C8({scale:t,type:e,domain:n,range:r,interpolate:o,interval:i,transform:a,percent:f,pivot:u}){if(e==="identity")return{type:"identity",apply:s=>s,invert:s=>s};let c=t.unknown?t.unknown():void 0;return{type:e,domain:ya(n),...r!==void 0&&{range:ya(r)},...a!==void 0&&{transform:a},...f&&{percent:f},...c!==void 0&&{unknown:c},...i!==void 0&&{interval:i},...o!==void 0&&{interpolate:o},...t.clamp&&{clamp:t.clamp()},...u!==void 0&&{pivot:u,symmetric:!1},...t.base&&{base:t.base()},...t.exponent&&{exponent:t.exponent()},...t.constant&&{constant:t.constant()},...t.align&&{align:t.align(),round:t.round()},...t.padding&&(t.paddingInner?{paddingInner:t.paddingInner(),paddingOuter:t.paddingOuter()}:{padding:t.padding()}),...t.bandwidth&&{bandwidth:t.bandwidth(),step:t.step()},apply:s=>t(s),...t.invert&&{invert:s=>t.invert(s)}}}function zc(t){let e,n;return(...r)=>((n?.length!==r.length||n.some((o,i)=>o!==r[i]))&&(n=r,e=t(...r)),e)}var A8=zc(t=>new Intl.NumberFormat(t)),Rq=zc((t,e)=>new Intl.DateTimeFormat(t,{timeZone:"UTC",...e&&{month:e}})),Lq=zc((t,e)=>new Intl.DateTimeFormat(t,{timeZone:"UTC",...e&&{weekday:e}}));function $8(t="en-US"){let e=A8(t);return n=>n!=null&&!isNaN(n)?e.format(n):void 0}function E8(t){return Cc(t,"Invalid Date")}function I8(t="en-US"){let e=$8(t);return n=>(n instanceof Date?E8:typeof n=="number"?e:Et)(n)}var _e=I8();var Bt=(typeof window<"u"?window.devicePixelRatio>1:typeof it>"u")?0:.5,k8=0;function rx(){return`plot-clip-${++k8}`}function ox(t,{title:e,href:n,ariaLabel:r,ariaDescription:o,ariaHidden:i,target:a,fill:f,fillOpacity:u,stroke:c,strokeWidth:s,strokeOpacity:d,strokeLinejoin:l,strokeLinecap:p,strokeMiterlimit:m,strokeDasharray:h,strokeDashoffset:g,opacity:b,mixBlendMode:x,imageFilter:M,paintOrder:y,pointerEvents:w,shapeRendering:v,channels:T},{ariaLabel:E,fill:I="currentColor",fillOpacity:F,stroke:_="none",strokeOpacity:R,strokeWidth:O,strokeLinecap:L,strokeLinejoin:S,strokeMiterlimit:A,paintOrder:N}){I===null&&(f=null,u=null),_===null&&(c=null,d=null),Pt(I)?!Pt(_)&&(!Pt(f)||T?.fill)&&(_="none"):Pt(_)&&(!Pt(c)||T?.stroke)&&(I="none");let[P,D]=ie(f,I),[tt,Z]=It(u,F),[W,Q]=ie(c,_),[at,Ut]=It(d,R),[Fe,G]=It(b);$r(Q)||(s===void 0&&(s=O),p===void 0&&(p=L),l===void 0&&(l=S),m===void 0&&!Ug(l)&&(m=A),!$r(D)&&y===void 0&&(y=N));let[X,$]=It(s);return I!==null&&(t.fill=St(D,"currentColor"),t.fillOpacity=$a(Z,1)),_!==null&&(t.stroke=St(Q,"none"),t.strokeWidth=$a($,1),t.strokeOpacity=$a(Ut,1),t.strokeLinejoin=St(l,"miter"),t.strokeLinecap=St(p,"butt"),t.strokeMiterlimit=$a(m,4),t.strokeDasharray=St(h,"none"),t.strokeDashoffset=St(g,"0")),t.target=Et(a),t.ariaLabel=Et(E),t.ariaDescription=Et(o),t.ariaHidden=Et(i),t.opacity=$a(G,1),t.mixBlendMode=St(x,"normal"),t.imageFilter=St(M,"none"),t.paintOrder=St(y,"normal"),t.pointerEvents=St(w,"auto"),t.shapeRendering=St(v,"auto"),{title:{value:e,optional:!0,filter:null},href:{value:n,optional:!0,filter:null},ariaLabel:{value:r,optional:!0,filter:null},fill:{value:P,scale:"auto",optional:!0},fillOpacity:{value:tt,scale:"auto",optional:!0},stroke:{value:W,scale:"auto",optional:!0},strokeOpacity:{value:at,scale:"auto",optional:!0},strokeWidth:{value:X,optional:!0},opacity:{value:Fe,scale:"auto",optional:!0}}}function N8(t,e){e&&t.filter(n=>Tc(e[n])).append("title").call(D8,e)}function D8(t,e){e&&t.text(n=>_e(e[n]))}function Oe(t,{target:e,tip:n},{ariaLabel:r,title:o,fill:i,fillOpacity:a,stroke:f,strokeOpacity:u,strokeWidth:c,opacity:s,href:d}){r&&et(t,"aria-label",l=>r[l]),i&&et(t,"fill",l=>i[l]),a&&et(t,"fill-opacity",l=>a[l]),f&&et(t,"stroke",l=>f[l]),u&&et(t,"stroke-opacity",l=>u[l]),c&&et(t,"stroke-width",l=>c[l]),s&&et(t,"opacity",l=>s[l]),d&&L8(t,l=>d[l],e),n||N8(t,o)}function ix(t){return t===!0?t="frame":t===!1&&(t=null),_r(t,"clip",["frame","sphere"])}function R8(t,e,n,r){let o;switch(e.clip){case"frame":{let{width:i,height:a,marginLeft:f,marginRight:u,marginTop:c,marginBottom:s}=n,d=rx();o=`url(#${d})`,t=yt("svg:g",r).call(l=>l.append("svg:clipPath").attr("id",d).append("rect").attr("x",f).attr("y",c).attr("width",i-u-f).attr("height",a-c-s)).each(function(){this.appendChild(t.node()),t.node=()=>this});break}case"sphere":{let{projection:i}=r;if(!i)throw new Error('the "sphere" clip option requires a projection');let a=rx();o=`url(#${a})`,t.append("clipPath").attr("id",a).append("path").attr("d",Ci(i)({type:"Sphere"}));break}}et(t,"aria-label",e.ariaLabel),et(t,"aria-description",e.ariaDescription),et(t,"aria-hidden",e.ariaHidden),et(t,"clip-path",o)}function me(t,e,n,r){R8(t,e,n,r),et(t,"fill",e.fill),et(t,"fill-opacity",e.fillOpacity),et(t,"stroke",e.stroke),et(t,"stroke-width",e.strokeWidth),et(t,"stroke-opacity",e.strokeOpacity),et(t,"stroke-linejoin",e.strokeLinejoin),et(t,"stroke-linecap",e.strokeLinecap),et(t,"stroke-miterlimit",e.strokeMiterlimit),et(t,"stroke-dasharray",e.strokeDasharray),et(t,"stroke-dashoffset",e.strokeDashoffset),et(t,"shape-rendering",e.shapeRendering),et(t,"filter",e.imageFilter),et(t,"paint-order",e.paintOrder);let{pointerEvents:o=r.pointerSticky===!1?"none":void 0}=e;et(t,"pointer-events",o)}function he(t,e){P8(t,"mix-blend-mode",e.mixBlendMode),et(t,"opacity",e.opacity)}function L8(t,e,n){t.each(function(r){let o=e(r);if(o!=null){let i=this.ownerDocument.createElementNS(Ye.svg,"a");i.setAttribute("fill","inherit"),i.setAttributeNS(Ye.xlink,"href",o),n!=null&&i.setAttribute("target",n),this.parentNode.insertBefore(i,this).appendChild(this)}})}function et(t,e,n){n!=null&&t.attr(e,n)}function P8(t,e,n){n!=null&&t.style(e,n)}function ge(t,e,{x:n,y:r},o=Bt,i=Bt){o+=e.dx,i+=e.dy,n?.bandwidth&&(o+=n.bandwidth()/2),r?.bandwidth&&(i+=r.bandwidth()/2),(o||i)&&t.attr("transform",`translate(${o},${i})`)}function St(t,e){if((t=Et(t))!==e)return t}function $a(t,e){if((t=ct(t))!==e)return t}var O8=/^-?([_a-z]|[\240-\377]|\\[0-9a-f]{1,6}(\r\n|[ \t\r\n\f])?|\\[^\r\n\f0-9a-f])([_a-z0-9-]|[\240-\377]|\\[0-9a-f]{1,6}(\r\n|[ \t\r\n\f])?|\\[^\r\n\f0-9a-f])*$/i;function Po(t){if(t===void 0)return"plot-d6a7b5";if(t=`${t}`,!O8.test(t))throw new Error(`invalid class name: ${t}`);return t}function Oo(t,e){if(typeof e=="string")t.property("style",e);else if(e!=null)for(let n of t)Object.assign(n.style,e)}function Gn({frameAnchor:t},{width:e,height:n,marginTop:r,marginRight:o,marginBottom:i,marginLeft:a}){return[/left$/.test(t)?a:/right$/.test(t)?e-o:(a+e-o)/2,/^top/.test(t)?r:/^bottom/.test(t)?n-i:(r+n-i)/2]}function fx(t,e,n={}){let r=.5-Bt,o=.5+Bt,i=.5+Bt,a=.5-Bt;for(let{marginTop:h,marginRight:g,marginBottom:b,marginLeft:x}of e)h>r&&(r=h),g>o&&(o=g),b>i&&(i=b),x>a&&(a=x);let{margin:f,marginTop:u=f!==void 0?f:r,marginRight:c=f!==void 0?f:o,marginBottom:s=f!==void 0?f:i,marginLeft:d=f!==void 0?f:a}=n;u=+u,c=+c,s=+s,d=+d;let{width:l=640,height:p=F8(t,n,{width:l,marginTopDefault:r,marginRightDefault:o,marginBottomDefault:i,marginLeftDefault:a})+Math.max(0,u-r+s-i)}=n;l=+l,p=+p;let m={width:l,height:p,marginTop:u,marginRight:c,marginBottom:s,marginLeft:d};if(t.fx||t.fy){let{margin:h,marginTop:g=h!==void 0?h:u,marginRight:b=h!==void 0?h:c,marginBottom:x=h!==void 0?h:s,marginLeft:M=h!==void 0?h:d}=n.facet??{};g=+g,b=+b,x=+x,M=+M,m.facet={marginTop:g,marginRight:b,marginBottom:x,marginLeft:M}}return m}function F8({x:t,y:e,fy:n,fx:r},{projection:o,aspectRatio:i},{width:a,marginTopDefault:f,marginRightDefault:u,marginBottomDefault:c,marginLeftDefault:s}){let d=n?n.scale.domain().length:1,l=x1(o);if(l){let m=r?r.scale.domain().length:1,h=(1.1*d-.1)/(1.1*m-.1)*l,g=Math.max(.1,Math.min(10,h));return Math.round((a-s-u)*g+f+c)}let p=e?fn(e)?e.scale.domain().length:Math.max(7,17/d):1;if(i!=null){if(i=+i,!(isFinite(i)&&i>0))throw new Error(`invalid aspectRatio: ${i}`);let m=ax("y",e)/(ax("x",t)*i),h=r?r.scale.bandwidth():1,g=n?n.scale.bandwidth():1,b=h*(a-s-u)-t.insetLeft-t.insetRight;return(m*b+e.insetTop+e.insetBottom)/g+f+c}return!!(e||n)*Math.max(1,Math.min(60,p*d))*20+!!r*30+60}function ax(t,e){if(!e)throw new Error(`aspectRatio requires ${t} scale`);let{type:n,domain:r}=e,o;switch(n){case"linear":case"utc":case"time":o=Number;break;case"pow":{let f=e.scale.exponent();o=u=>Math.pow(u,f);break}case"log":o=Math.log;break;case"point":case"band":return r.length;default:throw new Error(`unsupported ${t} scale for aspectRatio: ${n}`)}let[i,a]=Ct(r);return Math.abs(o(a)-o(i))}function cx(t,e){let{fx:n,fy:r}=Aa(t,e),o=n?.scale.domain(),i=r?.scale.domain();return o&&i?Fa(o,i).map(([a,f],u)=>({x:a,y:f,i:u})):o?o.map((a,f)=>({x:a,i:f})):i?i.map((a,f)=>({y:a,i:f})):void 0}function sx(t,{x:e,y:n}){return e&&=tp(e),n&&=tp(n),t.filter(e&&n?r=>e.has(r.x)&&n.has(r.y):e?r=>e.has(r.x):r=>n.has(r.y)).sort(e&&n?(r,o)=>e.get(r.x)-e.get(o.x)||n.get(r.y)-n.get(o.y):e?(r,o)=>e.get(r.x)-e.get(o.x):(r,o)=>n.get(r.y)-n.get(o.y))}function Bc(t,{fx:e,fy:n}){let r=ne(t),o=e?.value,i=n?.value;return e&&n?Ae(r,a=>(a.fx=o[a[0]],a.fy=i[a[0]],a),a=>o[a],a=>i[a]):e?Ae(r,a=>(a.fx=o[a[0]],a),a=>o[a]):Ae(r,a=>(a.fy=i[a[0]],a),a=>i[a])}function lx(t,e,{marginTop:n,marginLeft:r}){return t&&e?({x:o,y:i})=>`translate(${t(o)-r},${e(i)-n})`:t?({x:o})=>`translate(${t(o)-r},0)`:({y:o})=>`translate(0,${e(o)-n})`}function dx(t){let e=[],n=new Uint32Array(fe(t,r=>r.length));for(let r of t){let o=0;for(let i of t)r!==i&&(n.set(i,o),o+=i.length);e.push(n.slice(0,o))}return e}var q8=new Map([["top",Zd],["right",Jd],["bottom",Kd],["left",Qd],["top-left",Yc(Zd,Qd)],["top-right",Yc(Zd,Jd)],["bottom-left",Yc(Kd,Qd)],["bottom-right",Yc(Kd,Jd)],["top-empty",Y8],["right-empty",X8],["bottom-empty",B8],["left-empty",U8],["empty",H8]]);function px(t){if(t==null)return null;let e=q8.get(`${t}`.toLowerCase());if(e)return e;throw new Error(`invalid facet anchor: ${t}`)}var ux=new WeakMap;function tp(t){let e=ux.get(t);return e||ux.set(t,e=new Qt(pt(t,(n,r)=>[n,r]))),e}function Vn(t,e){return tp(t).get(e)}function z8(t,e,n){return e=bo(e),n=bo(n),t.find(r=>Object.is(bo(r.x),e)&&Object.is(bo(r.y),n))}function Uc(t,e,n){return z8(t,e,n)?.empty}function Zd(t,{y:e},{y:n}){return e?Vn(e,n)===0:!0}function Kd(t,{y:e},{y:n}){return e?Vn(e,n)===e.length-1:!0}function Qd(t,{x:e},{x:n}){return e?Vn(e,n)===0:!0}function Jd(t,{x:e},{x:n}){return e?Vn(e,n)===e.length-1:!0}function Y8(t,{y:e},{x:n,y:r,empty:o}){if(o)return!1;if(!e)return;let i=Vn(e,r);if(i>0)return Uc(t,n,e[i-1])}function B8(t,{y:e},{x:n,y:r,empty:o}){if(o)return!1;if(!e)return;let i=Vn(e,r);if(i<e.length-1)return Uc(t,n,e[i+1])}function U8(t,{x:e},{x:n,y:r,empty:o}){if(o)return!1;if(!e)return;let i=Vn(e,n);if(i>0)return Uc(t,e[i-1],r)}function X8(t,{x:e},{x:n,y:r,empty:o}){if(o)return!1;if(!e)return;let i=Vn(e,n);if(i<e.length-1)return Uc(t,e[i+1],r)}function H8(t,e,{empty:n}){return n}function Yc(t,e){return function(){return t.apply(null,arguments)&&e.apply(null,arguments)}}function Xc(t,{channels:{fx:e,fy:n},groups:r}){return e&&n?t.map(({x:o,y:i})=>r.get(o)?.get(i)??[]):e?t.map(({x:o})=>r.get(o)??[]):t.map(({y:o})=>r.get(o)??[])}var Nt=class{constructor(e,n={},r={},o){let{facet:i="auto",facetAnchor:a,fx:f,fy:u,sort:c,dx:s=0,dy:d=0,margin:l=0,marginTop:p=l,marginRight:m=l,marginBottom:h=l,marginLeft:g=l,clip:b,channels:x,tip:M,render:y}=r;if(this.data=e,this.sort=Cr(c)?c:null,this.initializer=Er(r).initializer,this.transform=this.initializer?r.transform:$o(r).transform,i===null||i===!1?this.facet=null:(this.facet=le(i===!0?"include":i,"facet",["auto","include","exclude","super"]),this.fx=e===xo&&typeof f=="string"?[f]:f,this.fy=e===xo&&typeof u=="string"?[u]:u),this.facetAnchor=px(a),n=Ed(n),x!==void 0&&(n={...W8(x),...n}),o!==void 0&&(n={...ox(this,r,o),...n}),this.channels=Object.fromEntries(Object.entries(n).map(([w,v])=>{if(ba(v.value)){let{value:T,scale:E=v.scale}=v.value;v={...v,scale:E,value:T}}if(e===xo&&typeof v.value=="string"){let{value:T}=v;v={...v,value:[T]}}return[w,v]}).filter(([w,{value:v,optional:T}])=>{if(v!=null)return!0;if(T)return!1;throw new Error(`missing channel value: ${w}`)})),this.dx=+s,this.dy=+d,this.marginTop=+p,this.marginRight=+m,this.marginBottom=+h,this.marginLeft=+g,this.clip=ix(b),this.tip=G8(M),this.facet==="super"){if(f||u)throw new Error("super-faceting cannot use fx or fy");for(let w in this.channels){let{scale:v}=n[w];if(!(v!=="x"&&v!=="y"))throw new Error("super-faceting cannot use x or y")}}y!=null&&(this.render=np(y,this.render))}initialize(e,n,r){let o=kt(this.data);e===void 0&&o!=null&&(e=[ne(o)]);let i=e;this.transform!=null&&({facets:e,data:o}=this.transform(o,e,r),o=kt(o)),e!==void 0&&(e.original=i);let a=r1(this.channels,o);return this.sort!=null&&i1(o,e,a,n,this.sort),{data:o,facets:e,channels:a}}filter(e,n,r){for(let o in n){let{filter:i=Re}=n[o];if(i!==null){let a=r[o];e=e.filter(f=>i(a[f]))}}return e}project(e,n,r){for(let o in e)if(e[o].scale==="x"&&/^x|x$/.test(o)){let i=o.replace(/^x|x$/,"y");i in e&&e[i].scale==="y"&&h1(o,i,n,r.projection)}}scale(e,n,r){let o=o1(e,n);return r.projection&&this.project(e,o,r),o}};function ep(...t){return t.plot=Nt.prototype.plot,t}function np(t,e){if(t==null)return e===null?void 0:e;if(e==null)return t===null?void 0:t;if(typeof t!="function")throw new TypeError(`invalid render transform: ${t}`);if(typeof e!="function")throw new TypeError(`invalid render transform: ${e}`);return function(n,r,o,i,a,f){return t.call(this,n,r,o,i,a,(u,c,s,d,l)=>e.call(this,u,c,s,d,l,f))}}function W8(t){return Object.fromEntries(Object.entries(Ed(t)).map(([e,n])=>(n=on(n),n.filter===void 0&&n.scale==null&&(n={...n,filter:null}),[e,n])))}function G8(t){return t===!0?"xy":t===!1?null:_r(t,"tip",["x","y","xy"])}function Ea(t,e){return t?.tip===!0?{...t,tip:e}:t}var mx=new WeakMap;function rp(t,e,{x:n,y:r,px:o,py:i,maxRadius:a=40,channels:f,render:u,...c}={}){return a=+a,o!=null&&(n??=null,f={...f,px:{value:o,scale:"x"}}),i!=null&&(r??=null,f={...f,py:{value:i,scale:"y"}}),{x:n,y:r,channels:f,...c,render:np(function(s,d,l,p,m,h){m={...m,pointerSticky:!1};let g=m.ownerSVGElement,{data:b}=m.getMarkState(this),x=mx.get(g);x||mx.set(g,x={sticky:!1,roots:[],renders:[]});let M=x.renders.push(Q)-1,{x:y,y:w,fx:v,fy:T}=d,E=v?v(s.fx)-p.marginLeft:0,I=T?T(s.fy)-p.marginTop:0;y?.bandwidth&&(E+=y.bandwidth()/2),w?.bandwidth&&(I+=w.bandwidth()/2);let F=s.fi!=null,_;if(F){let G=x.facetStates;G||(x.facetStates=G=new Map),_=G.get(this),_||G.set(this,_=new Map)}let[R,O]=Gn(this,p),{px:L,py:S}=l,A=L?G=>L[G]:op(l,R),N=S?G=>S[G]:ip(l,O),P,D,tt,Z;function W(G,X){if(F)if(Z&&(Z=cancelAnimationFrame(Z)),G==null)_.delete(s.fi);else{_.set(s.fi,X),Z=requestAnimationFrame(()=>{Z=null;for(let $ of _.values())if($<X){G=null;break}Q(G)});return}Q(G)}function Q(G){if(P===G&&tt===x.sticky)return;P=G,tt=m.pointerSticky=x.sticky;let X=P==null?[]:[P];F&&(X.fx=s.fx,X.fy=s.fy,X.fi=s.fi);let $=h(X,d,l,p,m);if(D){if(F){let q=D.parentNode,z=D.getAttribute("transform"),C=$.getAttribute("transform");z?$.setAttribute("transform",z):$.removeAttribute("transform"),C?q.setAttribute("transform",C):q.removeAttribute("transform"),$.removeAttribute("aria-label"),$.removeAttribute("aria-description"),$.removeAttribute("aria-hidden")}D.replaceWith($)}return x.roots[M]=D=$,m.dispatchValue(P==null?null:b[P]),$}function at(G){if(x.sticky||G.pointerType==="mouse"&&G.buttons===1)return;let[X,$]=Ms(G);X-=E,$-=I;let q=null,z=a*a;for(let C of s){let j=t*(A(C)-X),J=e*(N(C)-$),ot=j*j+J*J;ot<=z&&(q=C,z=ot)}W(q,z)}function Ut(G){G.pointerType==="mouse"&&P!=null&&(x.sticky&&x.roots.some(X=>X?.contains(G.target))||(x.sticky?(x.sticky=!1,x.renders.forEach(X=>X(null))):(x.sticky=!0,Q(P)),G.stopImmediatePropagation()))}function Fe(G){G.pointerType==="mouse"&&(x.sticky||W(null))}return g.addEventListener("pointerenter",at),g.addEventListener("pointermove",at),g.addEventListener("pointerdown",Ut),g.addEventListener("pointerleave",Fe),Q(null)},u)}}function hx(t){return rp(1,1,t)}function gx(t){return rp(1,.01,t)}function xx(t){return rp(.01,1,t)}function op({x1:t,x2:e,x:n=t},r){return t&&e?o=>(t[o]+e[o])/2:n?o=>n[o]:()=>r}function ip({y1:t,y2:e,y:n=t},r){return t&&e?o=>(t[o]+e[o])/2:n?o=>n[o]:()=>r}function Hc(t){return fn(t)&&t.interval===void 0?void 0:"tabular-nums"}function yx(t,e){let{label:n=t.label,tickSize:r=6,width:o=240,height:i=44+r,marginTop:a=18,marginRight:f=0,marginBottom:u=16+r,marginLeft:c=0,style:s,ticks:d=(o-c-f)/64,tickFormat:l,fontVariant:p=Hc(t),round:m=!0,opacity:h,className:g}=e,b=Io(e);g=Po(g),h=It(h)[1],l===null&&(l=()=>null);let x=yt("svg",b).attr("class",`${g}-ramp`).attr("font-family","system-ui, sans-serif").attr("font-size",10).attr("width",o).attr("height",i).attr("viewBox",`0 0 ${o} ${i}`).call(R=>R.append("style").text(`.${g}-ramp {
+${f}`;switch(d8(t,e,r)){case"millisecond":return $n(o(".%L"),o(":%M:%S"),i);case"second":return $n(o(":%S"),o("%-I:%M"),i);case"minute":return $n(o("%-I:%M"),o("%p"),i);case"hour":return $n(o("%-I %p"),o("%b %-d"),i);case"day":return $n(o("%-d"),o("%b"),i);case"week":return $n(o("%-d"),o("%b"),i);case"month":return $n(o("%b"),o("%Y"),i);case"year":return o("%Y")}throw new Error("unable to format time ticks")}function d8(t,e,r){let n=mr(Za(e,(u,c)=>Math.abs(c-u)||NaN));if(n>0)return ya[ze(([,u])=>u).right(ya,n,1,ya.length)-1][0];let[o,i]=Ct(t.domain()),a=typeof r=="number"?r:10,f=Math.abs(i-o)/a;return ya[ze(([,u])=>Math.log(u)).center(ya,Math.log(f))][0]}function $n(t,e,r){return(n,o,i)=>{let a=t(n,o),f=e(n,o),u=o-Mr(i);return o!==u&&i[u]!==void 0&&f===e(i[u],u)?a:r(a,f)}}var wa=Object.getPrototypeOf(Uint8Array),h8=Object.prototype.toString;function gt(t,e,r){let n=typeof e;return n==="string"?cg(t,Mp(e),r):n==="function"?cg(t,e,r):n==="number"||e instanceof Date||n==="boolean"?mt(t,Gr(e),r):typeof e?.transform=="function"?sg(e.transform(t),r):sg(e,r)}function cg(t,e,r){return mt(t,r?.prototype instanceof wa?g8(e):e,r)}function sg(t,e){return e===void 0?It(t):t instanceof e?t:e.prototype instanceof wa&&!(t instanceof wa)?e.from(t,Sp):e.from(t)}function g8(t){return(e,r)=>Sp(t(e,r))}var wo=[null],Mp=t=>e=>e[t],Oc={transform:oe},vt={transform:t=>t};var lg=()=>1,pg=()=>!0,Et=t=>t==null?t:`${t}`,ct=t=>t==null?t:+t;var va=t=>t?t[0]:void 0,Yc=t=>t?t[1]:void 0;var Gr=t=>()=>t;function mg(t){let e=+`${t}`.slice(1)/100;return(r,n)=>we(r,e,n)}function An(t){return t instanceof wa?t:mt(t,Sp,Float64Array)}function Sp(t){return t==null?NaN:Number(t)}function dg(t){return mt(t,Tp)}function Tp(t){return t instanceof Date&&!isNaN(t)?t:typeof t=="string"?xa(t):t==null||isNaN(t=+t)?void 0:new Date(t)}function ae(t,e){return t===void 0&&(t=e),t===null?[void 0,"none"]:To(t)?[void 0,t]:[t,void 0]}function kt(t,e){return t===void 0&&(t=e),t===null||typeof t=="number"?[void 0,t]:[t,void 0]}function En(t,e,r){if(t!=null)return me(t,e,r)}function me(t,e,r){let n=`${t}`.toLowerCase();if(!r.includes(n))throw new Error(`invalid ${e}: ${t}`);return n}function It(t){return t==null||t instanceof Array||t instanceof wa?t:Array.from(t)}function mt(t,e,r=Array){return t==null?t:t instanceof r?t.map(e):r.from(t,e)}function Ma(t,e=Array){return t instanceof e?t.slice():e.from(t)}function hg({x:t,x1:e,x2:r}){return t!==void 0||e!==void 0||r!==void 0}function _p({y:t,y1:e,y2:r}){return t!==void 0||e!==void 0||r!==void 0}function gg(t){return hg(t)||_p(t)||t.interval!==void 0}function Sr(t){return t?.toString===h8}function vo(t){return Sr(t)&&(t.type!==void 0||t.domain!==void 0)}function Sa(t){return Sr(t)&&typeof t.transform!="function"}function kn(t){return Sa(t)&&t.value===void 0&&t.channel===void 0}function xg(t,e,r,n=vt){return e===void 0&&r===void 0?(e=0,r=t===void 0?n:t):e===void 0?e=t===void 0?0:t:r===void 0&&(r=t===void 0?0:t),[e,r]}function In(t,e){return t===void 0&&e===void 0?[va,Yc]:[t,e]}function yg({z:t,fill:e,stroke:r}={}){return t===void 0&&([t]=ae(e)),t===void 0&&([t]=ae(r)),t}function oe(t){let e=t.length,r=new Uint32Array(e);for(let n=0;n<e;++n)r[n]=n;return r}function Cp(t,e){return mt(e,r=>t[r])}function bg(t,e,r){return t.subarray?t.subarray(e,r):t.slice(e,r)}function Mo(t){return t!==null&&typeof t=="object"?t.valueOf():t}function wg(t,e){if(e[t]!==void 0)return e[t];switch(t){case"x1":case"x2":t="x";break;case"y1":case"y2":t="y";break}return e[t]}function So(t){let e;return[{transform:()=>e,label:qe(t)},r=>e=r]}function Ce(t){return t==null?[t]:So(t)}function qe(t,e){return typeof t=="string"?t:t&&t.label!==void 0?t.label:e}function Ta(t,e){return{transform(r){let n=t.transform(r),o=e.transform(r);return Bt(n)||Bt(o)?mt(n,(i,a)=>new Date((+n[a]+ +o[a])/2)):mt(n,(i,a)=>(+n[a]+ +o[a])/2,Float64Array)},label:t.label}}function _a(t,e){let r=$p(e?.interval,e?.type);return r?mt(t,r):t}function $p(t,e){let r=Bc(t,e);return r&&(n=>Fe(n)?r.floor(n):n)}function Bc(t,e){if(t!=null){if(typeof t=="number"){0<t&&t<1&&Number.isInteger(1/t)&&(t=-1/t);let r=Math.abs(t);return t<0?{floor:n=>Math.floor(n*r)/r,offset:n=>(n*r+1)/r,range:(n,o)=>nn(Math.ceil(n*r),o*r).map(i=>i/r)}:{floor:n=>Math.floor(n/r)*r,offset:n=>n+r,range:(n,o)=>nn(Math.ceil(n/r),o/r).map(i=>i*r)}}if(typeof t=="string")return(e==="time"?ig:qc)(t);if(typeof t.floor!="function")throw new Error("invalid interval; missing floor method");if(typeof t.offset!="function")throw new Error("invalid interval; missing offset method");return t}}function fr(t,e){if(t=Bc(t,e),t&&typeof t.range!="function")throw new Error("invalid interval: missing range method");return t}function vg(t,e){if(t=fr(t,e),t&&typeof t.ceil!="function")throw new Error("invalid interval: missing ceil method");return t}function ur(t){return t===void 0||Sa(t)?t:{value:t}}function Mg(t){return t==null?null:{transform:e=>gt(e,t,Float64Array),label:qe(t)}}function ie(t){return t&&typeof t[Symbol.iterator]=="function"}function zc(t){for(let e of t)if(e!=null)return typeof e!="object"||e instanceof Date}function Ap(t){for(let e of t){if(e==null)continue;let r=typeof e;return r==="string"||r==="boolean"}}function Bt(t){for(let e of t)if(e!=null)return e instanceof Date}function Sg(t){for(let e of t)if(e!=null)return typeof e=="string"&&isNaN(e)&&xa(e)}function Tg(t){for(let e of t)if(e!=null){if(typeof e!="string")return!1;if(e.trim())return!isNaN(e)}}function _g(t){for(let e of t)if(e!=null)return typeof e=="number"}function Uc(t,e){let r;for(let n of t)if(n!=null){if(!e(n))return!1;r=!0}return r}function To(t){return typeof t!="string"?!1:(t=t.toLowerCase().trim(),t==="none"||t==="currentcolor"||t.startsWith("url(")&&t.endsWith(")")||t.startsWith("var(")&&t.endsWith(")")||ce(t)!==null)}function Cg(t){return typeof t=="number"&&(0<=t&&t<=1||isNaN(t))}function Ft(t){return t==null||Nn(t)}function Nn(t){return/^\s*none\s*$/i.test(t)}function $g(t){return/^\s*round\s*$/i.test(t)}function Ep(t,e){return En(t,e,["middle","top-left","top","top-right","right","bottom-right","bottom","bottom-left","left"])}function _o(t="middle"){return Ep(t,"frameAnchor")}function Mr(t){if(t==null)return;let e=t[0],r=t[t.length-1];return be(e,r)}function Ag(t={},...e){let r=t;for(let n of e)for(let o in n)if(r[o]===void 0){let i=n[o];r===t?r={...r,[o]:i}:r[o]=i}return r}function x8(t){console.warn("named iterables are deprecated; please use an object instead");let e=new Set;return Object.fromEntries(Array.from(t,r=>{let{name:n}=r;if(n==null)throw new Error("missing name");let o=`${n}`;if(o==="__proto__")throw new Error(`illegal name: ${o}`);if(e.has(o))throw new Error(`duplicate name: ${o}`);return e.add(o),[n,r]}))}function kp(t){return ie(t)?x8(t):t}var de=Symbol("position"),he=Symbol("color"),Co=Symbol("radius"),$o=Symbol("length"),Ao=Symbol("opacity"),Eo=Symbol("symbol"),y8=Symbol("projection"),st=new Map([["x",de],["y",de],["fx",de],["fy",de],["r",Co],["color",he],["opacity",Ao],["symbol",Eo],["length",$o],["projection",y8]]);var b8=Math.sqrt(3),w8=2/b8,v8={draw(t,e){let r=Math.sqrt(e/Math.PI),n=r*w8,o=n/2;t.moveTo(0,n),t.lineTo(r,o),t.lineTo(r,-o),t.lineTo(0,-n),t.lineTo(-r,-o),t.lineTo(-r,o),t.closePath()}},Eg=new Map([["asterisk",oa],["circle",bo],["cross",ia],["diamond",aa],["diamond2",fa],["hexagon",v8],["plus",ua],["square",ca],["square2",sa],["star",la],["times",ha],["triangle",pa],["triangle2",ma],["wye",da]]);function kg(t){return t&&typeof t.draw=="function"}function Ig(t){return kg(t)?!0:typeof t!="string"?!1:Eg.has(t.toLowerCase())}function ko(t){if(t==null||kg(t))return t;let e=Eg.get(`${t}`.toLowerCase());if(e)return e;throw new Error(`invalid symbol: ${t}`)}function Io({filter:t,sort:e,reverse:r,transform:n,initializer:o,...i}={},a){if(n===void 0&&(t!=null&&(n=Ng(t)),e!=null&&!kn(e)&&(n=Ip(n,Rg(e))),r&&(n=Ip(n,Dg))),a!=null&&o!=null)throw new Error("transforms cannot be applied after initializers");return{...i,...(e===null||kn(e))&&{sort:e},transform:Ip(n,a)}}function Dn({filter:t,sort:e,reverse:r,initializer:n,...o}={},i){return n===void 0&&(t!=null&&(n=Ng(t)),e!=null&&!kn(e)&&(n=Np(n,Rg(e))),r&&(n=Np(n,Dg))),{...o,...(e===null||kn(e))&&{sort:e},initializer:Np(n,i)}}function Ip(t,e){return t==null?e===null?void 0:e:e==null?t===null?void 0:t:function(r,n,o){return{data:r,facets:n}=t.call(this,r,n,o),e.call(this,It(r),n,o)}}function Np(t,e){return t==null?e===null?void 0:e:e==null?t===null?void 0:t:function(r,n,o,...i){let a,f,u,c,s,p;return{data:f=r,facets:u=n,channels:a}=t.call(this,r,n,o,...i),{data:s=f,facets:p=u,channels:c}=e.call(this,f,u,{...o,...a},...i),{data:s,facets:p,channels:{...a,...c}}}}function Ng(t){return(e,r)=>{let n=gt(e,t);return{data:e,facets:r.map(o=>o.filter(i=>n[i]))}}}function Dg(t,e){return{data:t,facets:e.map(r=>r.slice().reverse())}}function Rg(t){return(typeof t=="function"&&t.length!==1?M8:S8)(t)}function M8(t){return(e,r)=>{let n=(o,i)=>t(e[o],e[i]);return{data:e,facets:r.map(o=>o.slice().sort(n))}}}function S8(t){let e,r;({channel:e,value:t,order:r}={...ur(t)});let n=e?.startsWith("-");if(n&&(e=e.slice(1)),r===void 0&&(r=n?Cn:Qt),typeof r!="function")switch(`${r}`.toLowerCase()){case"ascending":r=Qt;break;case"descending":r=Cn;break;default:throw new Error(`invalid order: ${r}`)}return(o,i,a)=>{let f;if(e===void 0)f=gt(o,t);else{if(a===void 0)throw new Error("channel sort requires an initializer");if(f=a[e],!f)return{};f=f.value}let u=(c,s)=>r(f[c],f[s]);return{data:o,facets:i.map(c=>c.slice().sort(u))}}}function $a(t,...e){for(let{name:r}of t)if(e.includes(r))return!0;return!1}function Fg(t,e,r=Dp){let n=Object.entries(t);return e.title!=null&&t.title===void 0&&n.push(["title",A8]),e.href!=null&&t.href===void 0&&n.push(["href",Fp]),n.filter(([,o])=>o!==void 0).map(([o,i])=>i===null?T8(o):r(o,i,e))}function Dp(t,e,r,n=Rp){let o;Sr(e)&&"reduce"in e&&(o=e.scale,e=e.reduce);let i=n(t,e,r),[a,f]=So(i.label),u;return{name:t,output:o===void 0?a:{value:a,scale:o},initialize(c){i.initialize(c),u=f([])},scope(c,s){i.scope(c,s)},reduce(c,s){u.push(i.reduce(c,s))}}}function T8(t){return{name:t,initialize(){},scope(){},reduce(){}}}function Rp(t,e,r,n=Aa){let o=wg(t,r),i=n(e,o),a,f;return{label:qe(i===Ca?null:o,i.label),initialize(u){a=o===void 0?u:gt(u,o),i.scope==="data"&&(f=i.reduceIndex(oe(u),a))},scope(u,c){i.scope===u&&(f=i.reduceIndex(c,a))},reduce(u,c){return i.scope==null?i.reduceIndex(u,a,c):i.reduceIndex(u,a,f,c)}}}function Pp(t,e){return e?Ue(pr(t,r=>e[r]),va):[[,t]]}function Aa(t,e,r=_8){if(t==null)return r(t);if(typeof t.reduceIndex=="function")return t;if(typeof t.reduce=="function"&&Sr(t))return C8(t);if(typeof t=="function")return $8(t);if(/^p\d{2}$/i.test(t))return Tr(mg(t));switch(`${t}`.toLowerCase()){case"first":return Fp;case"last":return E8;case"identity":return Lp;case"count":return Ca;case"distinct":return k8;case"sum":return e==null?Ca:I8;case"proportion":return Lg(e,"data");case"proportion-facet":return Lg(e,"facet");case"deviation":return Tr(Yn);case"min":return Tr(te);case"min-index":return Tr(jo);case"max":return Tr(Ht);case"max-index":return Tr(Vo);case"mean":return Pg(ja);case"median":return Pg(mr);case"variance":return Tr(On);case"mode":return Tr(Ka)}return r(t)}function _8(t){throw new Error(`invalid reduce: ${t}`)}function qg(t,e){for(let r in e){let n=e[r];if(n!==void 0&&!t.some(o=>o.name===r))return n}}function Og(t,e,r){if(e){let n=e.output.transform(),o=(i,a)=>Qt(n[i],n[a]);t.forEach(i=>i.sort(o))}r&&t.forEach(n=>n.reverse())}function C8(t){return console.warn("deprecated reduce interface; implement reduceIndex instead."),{...t,reduceIndex:t.reduce.bind(t)}}function $8(t){return{reduceIndex(e,r,n){return t(Cp(r,e),n)}}}function Tr(t){return{reduceIndex(e,r){return t(e,n=>r[n])}}}function Pg(t){return{reduceIndex(e,r){let n=t(e,o=>r[o]);return Bt(r)?new Date(n):n}}}var Lp={reduceIndex(t,e){return Cp(e,t)}},Fp={reduceIndex(t,e){return e[t[0]]}},A8={reduceIndex(t,e){let n=Ue(Ee(t,i=>i.length,i=>e[i]),Yc),o=n.slice(-5).reverse();if(o.length<n.length){let i=n.slice(0,-4);o[5-1]=[`\u2026 ${i.length.toLocaleString("en-US")} more`,ue(i,Yc)]}return o.map(([i,a])=>`${i} (${a.toLocaleString("en-US")})`).join(`
+`)}},E8={reduceIndex(t,e){return e[t[t.length-1]]}},Ca={label:"Frequency",reduceIndex(t){return t.length}},k8={label:"Distinct",reduceIndex(t,e){let r=new Ae;for(let n of t)r.add(e[n]);return r.size}},I8=Tr(ue);function Lg(t,e){return t==null?{scope:e,label:"Frequency",reduceIndex:(r,n,o=1)=>r.length/o}:{scope:e,reduceIndex:(r,n,o=1)=>ue(r,i=>n[i])/o}}function No(t,{scale:e,type:r,value:n,filter:o,hint:i},a){return i===void 0&&typeof n?.transform=="function"&&(i=n.hint),Op(a,{scale:e,type:r,value:gt(t,n),label:qe(n),filter:o,hint:i})}function Bg(t,e){return Object.fromEntries(Object.entries(t).map(([r,n])=>[r,No(e,n,r)]))}function zg(t,e){let r=Object.fromEntries(Object.entries(t).map(([n,{scale:o,value:i}])=>{let a=o==null?null:e[o];return[n,a==null?i:mt(i,a)]}));return r.channels=t,r}function Op(t,e){let{scale:r,value:n}=e;if(r===!0||r==="auto")switch(t){case"fill":case"stroke":case"color":e.scale=r!==!0&&Uc(n,To)?null:"color";break;case"fillOpacity":case"strokeOpacity":case"opacity":e.scale=r!==!0&&Uc(n,Cg)?null:"opacity";break;case"symbol":r!==!0&&Uc(n,Ig)?(e.scale=null,e.value=mt(n,ko)):e.scale="symbol";break;default:e.scale=st.has(t)?t:null;break}else if(r===!1)e.scale=null;else if(r!=null&&!st.has(r))throw new Error(`unknown scale: ${r}`);return e}function Ug(t,e,r,n,o){let{order:i,reverse:a,reduce:f=!0,limit:u}=o;for(let c in o){if(!st.has(c))continue;let{value:s,order:p=i,reverse:l=a,reduce:m=f,limit:d=u}=ur(o[c]),h=s?.startsWith("-");if(h&&(s=s.slice(1)),p=p===void 0?h!==(s==="width"||s==="height")?Hg:Wg:R8(p),m==null||m===!1)continue;let g=c==="fx"||c==="fy"?D8(e,n[c]):N8(r,c);if(!g)throw new Error(`missing channel for scale: ${c}`);let b=g.value,[x=0,M=1/0]=ie(d)?d:d<0?[d]:[0,d];if(s==null)g.domain=()=>{let y=Array.from(new Ae(b));return l&&(y=y.reverse()),(x!==0||M!==1/0)&&(y=y.slice(x,M)),y};else{let y=s==="data"?t:s==="height"?Yg(r,"y1","y2"):s==="width"?Yg(r,"x1","x2"):qp(r,s,s==="y"?"y2":s==="x"?"x2":void 0),w=Aa(m===!0?"max":m,y);g.domain=()=>{let v=gs(oe(b),T=>w.reduceIndex(T,y),T=>b[T]);return p&&v.sort(p),l&&v.reverse(),(x!==0||M!==1/0)&&(v=v.slice(x,M)),v.map(va)}}}}function N8(t,e){for(let r in t){let n=t[r];if(n.scale===e)return n}}function D8(t,e){let r=t.original;if(r===t)return e;let n=e.value,o=e.value=[];for(let i=0;i<r.length;++i){let a=n[r[i][0]];for(let f of t[i])o[f]=a}return e}function Yg(t,e,r){let n=qp(t,e),o=qp(t,r);return mt(o,(i,a)=>Math.abs(i-n[a]),Float64Array)}function qp(t,e,r){let n=t[e];if(!n&&r!==void 0&&(n=t[r]),n)return n.value;throw new Error(`missing channel: ${e}`)}function R8(t){if(t==null||typeof t=="function")return t;switch(`${t}`.toLowerCase()){case"ascending":return Wg;case"descending":return Hg}throw new Error(`invalid order: ${t}`)}function Wg([t,e],[r,n]){return Qt(e,n)||Qt(t,r)}function Hg([t,e],[r,n]){return Cn(e,n)||Qt(t,r)}function Gg(t,e){let r=t[e];if(r){for(;r.source;)r=r.source;return r.source===null?null:r}}function Do(t={}){let{document:e=typeof window<"u"?window.document:void 0}=t;return{document:e}}function yt(t,{document:e}){return Ie(He(t).call(e.documentElement))}var Yp=0;function Xg(){let t=Yp;return Yp=0,t}function cr(t){console.warn(t),++Yp}var jg=Math.PI,_r=2*jg,Vg=.618;function Kg({projection:t,inset:e=0,insetTop:r=e,insetRight:n=e,insetBottom:o=e,insetLeft:i=e}={},a){if(t==null)return;if(typeof t.stream=="function")return t;let f,u,c="frame";if(Sr(t)){let w;if({type:t,domain:u,inset:w,insetTop:r=w!==void 0?w:r,insetRight:n=w!==void 0?w:n,insetBottom:o=w!==void 0?w:o,insetLeft:i=w!==void 0?w:i,clip:c=c,...f}=t,t==null)return}typeof t!="function"&&({type:t}=Zg(t));let{width:s,height:p,marginLeft:l,marginRight:m,marginTop:d,marginBottom:h}=a,g=s-l-m-i-n,b=p-d-h-r-o;if(t=t?.({width:g,height:b,clip:c,...f}),t==null)return;c=P8(c,l,d,s-m,p-h);let x=l+i,M=d+r,y;if(u!=null){let[[w,v],[T,E]]=Ei(t).bounds(u),k=Math.min(g/(T-w),b/(E-v));k>0?(x-=(k*(w+T)-g)/2,M-=(k*(v+E)-b)/2,y=ki({point(q,_){this.stream.point(q*k+x,_*k+M)}})):cr("Warning: the projection could not be fit to the specified domain; using the default scale.")}return y??=x===0&&M===0?Qg():ki({point(w,v){this.stream.point(w+x,v+M)}}),{stream:w=>t.stream(y.stream(c(w)))}}function Zg(t){switch(`${t}`.toLowerCase()){case"albers-usa":return Oe(Nl,.7463,.4673);case"albers":return Wc(Ni,.7463,.4673);case"azimuthal-equal-area":return Oe(Dl,4,4);case"azimuthal-equidistant":return Oe(Rl,_r,_r);case"conic-conformal":return Wc(Fl,_r,_r);case"conic-equal-area":return Wc(Pr,6.1702,2.9781);case"conic-equidistant":return Wc(Ol,7.312,3.6282);case"equal-earth":return Oe(Yl,5.4133,2.6347);case"equirectangular":return Oe(ql,_r,jg);case"gnomonic":return Oe(Bl,3.4641,3.4641);case"identity":return{type:Qg};case"reflect-y":return{type:L8};case"mercator":return Oe(Pl,_r,_r);case"orthographic":return Oe(zl,2,2);case"stereographic":return Oe(Ul,2,2);case"transverse-mercator":return Oe(Wl,_r,_r);default:throw new Error(`unknown projection type: ${t}`)}}function P8(t,e,r,n,o){if(t===!1||t==null||typeof t=="number")return i=>i;switch(t===!0&&(t="frame"),`${t}`.toLowerCase()){case"frame":return no(e,r,n,o);default:throw new Error(`unknown projection clip type: ${t}`)}}function Oe(t,e,r){return{type:({width:n,height:o,rotate:i,precision:a=.15,clip:f})=>{let u=t();return a!=null&&u.precision?.(a),i!=null&&u.rotate?.(i),typeof f=="number"&&u.clipAngle?.(f),u.scale(Math.min(n/e,o/r)),u.translate([n/2,o/2]),u},aspectRatio:r/e}}function Wc(t,e,r){let{type:n,aspectRatio:o}=Oe(t,e,r);return{type:i=>{let{parallels:a,domain:f,width:u,height:c}=i,s=n(i);return a!=null&&(s.parallels(a),f===void 0&&s.fitSize([u,c],{type:"Sphere"})),s},aspectRatio:o}}var Qg=Gr({stream:t=>t}),L8=Gr(ki({point(t,e){this.stream.point(t,-e)}}));function Jg(t,e,r,n){let o=r[t],i=r[e],a=o.length,f=r[t]=new Float64Array(a).fill(NaN),u=r[e]=new Float64Array(a).fill(NaN),c,s=n.stream({point(p,l){f[c]=p,u[c]=l}});for(c=0;c<a;++c)s.point(o[c],i[c])}function t1({projection:t}={}){return t==null?!1:typeof t.stream=="function"?!0:(Sr(t)&&(t=t.type),t!=null)}function e1(t){if(typeof t?.stream=="function")return Vg;if(Sr(t)&&(t=t.type),t!=null){if(typeof t!="function"){let{aspectRatio:e}=Zg(t);if(e)return e}return Vg}}function r1(t){let e=[],r=[],n={scale:"x",value:e},o={scale:"y",value:r},i={point(a,f){e.push(a),r.push(f)},lineStart(){},lineEnd(){},polygonStart(){},polygonEnd(){},sphere(){}};for(let a of t.value)De(a,i);return[n,o]}var f1=new Map([["accent",op],["category10",np],["dark2",ip],["paired",ap],["pastel1",fp],["pastel2",up],["set1",cp],["set2",sp],["set3",lp],["tableau10",pp]]);function u1(t){return t!=null&&f1.has(`${t}`.toLowerCase())}var n1=new Map([...f1,["brbg",Cr(Tu,_u)],["prgn",Cr(Cu,$u)],["piyg",Cr(Au,Eu)],["puor",Cr(ku,Iu)],["rdbu",Cr(ea,ho)],["rdgy",Cr(Nu,Du)],["rdylbu",Cr(ra,go)],["rdylgn",Cr(Ru,Pu)],["spectral",Cr(Lu,Fu)],["burd",o1(ea,ho)],["buylrd",o1(ra,go)],["blues",qt(uc,cc)],["greens",qt(sc,lc)],["greys",qt(pc,mc)],["oranges",qt(yc,bc)],["purples",qt(dc,hc)],["reds",qt(gc,xc)],["turbo",$r(Ac)],["viridis",$r(kc)],["magma",$r(Ic)],["inferno",$r(Nc)],["plasma",$r(Dc)],["cividis",$r(wc)],["cubehelix",$r(vc)],["warm",$r(Sc)],["cool",$r(Tc)],["bugn",qt(qu,Ou)],["bupu",qt(Yu,Bu)],["gnbu",qt(zu,Uu)],["orrd",qt(Wu,Hu)],["pubu",qt(Vu,ju)],["pubugn",qt(Gu,Xu)],["purd",qt(Ku,Zu)],["rdpu",qt(Qu,Ju)],["ylgn",qt(rc,nc)],["ylgnbu",qt(tc,ec)],["ylorbr",qt(oc,ic)],["ylorrd",qt(ac,fc)],["rainbow",i1(_c)],["sinebow",i1($c)]]);function qt(t,e){return({length:r})=>r===1?[t[3][1]]:r===2?[t[3][1],t[3][2]]:(r=Math.max(3,Math.floor(r)),r>9?Gt(e,r):t[r])}function Cr(t,e){return({length:r})=>r===2?[t[3][0],t[3][2]]:(r=Math.max(3,Math.floor(r)),r>11?Gt(e,r):t[r])}function o1(t,e){return({length:r})=>r===2?[t[3][2],t[3][0]]:(r=Math.max(3,Math.floor(r)),r>11?Gt(n=>e(1-n),r):t[r].slice().reverse())}function $r(t){return({length:e})=>Gt(t,Math.max(2,Math.floor(e)))}function i1(t){return({length:e})=>Gt(t,Math.floor(e)+1).slice(0,-1)}function Bp(t){let e=`${t}`.toLowerCase();if(!n1.has(e))throw new Error(`unknown ordinal scheme: ${e}`);return n1.get(e)}function Ea(t,e){let r=Bp(t),n=typeof r=="function"?r({length:e}):r;return n.length!==e?n.slice(0,e):n}function c1(t,e="greys"){let r=new Set,[n,o]=Ea(e,2);for(let i of t)if(i!=null)if(i===!0)r.add(o);else if(i===!1)r.add(n);else return;return[...r]}var a1=new Map([["brbg",_u],["prgn",$u],["piyg",Eu],["puor",Iu],["rdbu",ho],["rdgy",Du],["rdylbu",go],["rdylgn",Pu],["spectral",Fu],["burd",t=>ho(1-t)],["buylrd",t=>go(1-t)],["blues",cc],["greens",lc],["greys",mc],["purples",hc],["reds",xc],["oranges",bc],["turbo",Ac],["viridis",kc],["magma",Ic],["inferno",Nc],["plasma",Dc],["cividis",wc],["cubehelix",vc],["warm",Sc],["cool",Tc],["bugn",Ou],["bupu",Bu],["gnbu",Uu],["orrd",Hu],["pubugn",Xu],["pubu",ju],["purd",Zu],["rdpu",Ju],["ylgnbu",ec],["ylgn",nc],["ylorbr",ic],["ylorrd",fc],["rainbow",_c],["sinebow",$c]]);function Ro(t){let e=`${t}`.toLowerCase();if(!a1.has(e))throw new Error(`unknown quantitative scheme: ${e}`);return a1.get(e)}var F8=new Set(["brbg","prgn","piyg","puor","rdbu","rdgy","rdylbu","rdylgn","spectral","burd","buylrd"]);function s1(t){return t!=null&&F8.has(`${t}`.toLowerCase())}var Up=t=>e=>t(1-e),zp=[0,1],l1=new Map([["number",ht],["rgb",Me],["hsl",Gs],["hcl",Xs],["lab",yf]]);function Wp(t){let e=`${t}`.toLowerCase();if(!l1.has(e))throw new Error(`unknown interpolator: ${e}`);return l1.get(e)}function Lo(t,e,r,{type:n,nice:o,clamp:i,zero:a,domain:f=b1(t,r),unknown:u,round:c,scheme:s,interval:p,range:l=st.get(t)===Co?B8(r,f):st.get(t)===$o?z8(r,f):st.get(t)===Ao?zp:void 0,interpolate:m=st.get(t)===he?s==null&&l!==void 0?Me:Ro(s!==void 0?s:n==="cyclical"?"rainbow":"turbo"):c?Nr:ht,reverse:d}){if(p=fr(p,n),(n==="cyclical"||n==="sequential")&&(n="linear"),typeof m!="function"&&(m=Wp(m)),d=!!d,l!==void 0){let h=(f=It(f)).length,g=(l=It(l)).length;if(h!==g){if(m.length===1)throw new Error("invalid piecewise interpolator");m=je(m,l),l=void 0}}if(m.length===1?(d&&(m=Up(m),d=!1),l===void 0&&(l=Float64Array.from(f,(h,g)=>g/(f.length-1)),l.length===2&&(l=zp)),e.interpolate((l===zp?Gr:ka)(m))):e.interpolate(m),a){let[h,g]=Ct(f);(h>0||g<0)&&(f=Ma(f),Mr(f)!==Math.sign(h)?f[f.length-1]=0:f[0]=0)}return d&&(f=Ar(f)),e.domain(f).unknown(u),o&&(e.nice(q8(o,n)),f=e.domain()),l!==void 0&&e.range(l),i&&e.clamp(i),{type:n,domain:f,range:l,scale:e,interpolate:m,interval:p}}function q8(t,e){return t===!0?void 0:typeof t=="number"?t:vg(t,e)}function p1(t,e,r){return Lo(t,bn(),e,r)}function m1(t,e,r){return Hp(t,e,{...r,exponent:.5})}function Hp(t,e,{exponent:r=1,...n}){return Lo(t,Hi().exponent(r),e,{...n,type:"pow"})}function d1(t,e,{base:r=10,domain:n=U8(e),...o}){return Lo(t,Ui().base(r),e,{...o,domain:n})}function h1(t,e,{constant:r=1,...n}){return Lo(t,Wi().constant(r),e,n)}function g1(t,e,{range:r,quantiles:n=r===void 0?5:(r=[...r]).length,n:o=n,scheme:i="rdylbu",domain:a=W8(e),unknown:f,interpolate:u,reverse:c}){return r===void 0&&(r=u!==void 0?Gt(u,o):st.get(t)===he?Ea(i,o):void 0),a.length>0&&(a=Gi(a,r===void 0?{length:o}:r).quantiles()),Hc(t,e,{domain:a,range:r,reverse:c,unknown:f})}function x1(t,e,{range:r,n=r===void 0?5:(r=[...r]).length,scheme:o="rdylbu",domain:i=b1(t,e),unknown:a,interpolate:f,reverse:u}){let[c,s]=Ct(i),p;return r===void 0?(p=ke(c,s,n),p[0]<=c&&p.splice(0,1),p[p.length-1]>=s&&p.pop(),n=p.length+1,r=f!==void 0?Gt(f,n):st.get(t)===he?Ea(o,n):void 0):(p=Gt(ht(c,s),n+1).slice(1,-1),c instanceof Date&&(p=p.map(l=>new Date(l)))),Mr(It(i))<0&&p.reverse(),Hc(t,e,{domain:p,range:r,reverse:u,unknown:a})}function Hc(t,e,{domain:r=[0],unknown:n,scheme:o="rdylbu",interpolate:i,range:a=i!==void 0?Gt(i,r.length+1):st.get(t)===he?Ea(o,r.length+1):void 0,reverse:f}){r=It(r);let u=Mr(r);if(!isNaN(u)&&!O8(r,u))throw new Error(`the ${t} scale has a non-monotonic domain`);return f&&(a=Ar(a)),{type:"threshold",scale:Xi(u<0?Ar(r):r,a===void 0?[]:a).unknown(n),domain:r,range:a}}function O8(t,e){for(let r=1,n=t.length,o=t[0];r<n;++r){let i=be(o,o=t[r]);if(i!==0&&i!==e)return!1}return!0}function y1(){return{type:"identity",scale:Bi()}}function Po(t,e=bp){return t.length?[te(t,({value:r})=>r===void 0?r:te(r,e)),Ht(t,({value:r})=>r===void 0?r:Ht(r,e))]:[0,1]}function b1(t,e){let r=st.get(t);return(r===Co||r===Ao||r===$o?Y8:Po)(e)}function Y8(t){return[0,t.length?Ht(t,({value:e})=>e===void 0?e:Ht(e,bp)):1]}function B8(t,e){let r=t.find(({radius:a})=>a!==void 0);if(r!==void 0)return[0,r.radius];let n=we(t,.5,({value:a})=>a===void 0?NaN:we(a,.25,ga)),o=e.map(a=>3*Math.sqrt(a/n)),i=30/Ht(o);return i<1?o.map(a=>a*i):o}function z8(t,e){let r=mr(t,({value:i})=>i===void 0?NaN:mr(i,Math.abs)),n=e.map(i=>12*i/r),o=60/Ht(n);return o<1?n.map(i=>i*o):n}function U8(t){for(let{value:e}of t)if(e!==void 0)for(let r of e){if(r>0)return Po(t,ga);if(r<0)return Po(t,Pc)}return[1,10]}function W8(t){let e=[];for(let{value:r}of t)if(r!==void 0)for(let n of r)e.push(n);return e}function ka(t){return(e,r)=>n=>t(e+n*(r-e))}function Gc(t,e,r,n,{type:o,nice:i,clamp:a,domain:f=Po(n),unknown:u,pivot:c=0,scheme:s,range:p,symmetric:l=!0,interpolate:m=st.get(t)===he?s==null&&p!==void 0?Me:Ro(s!==void 0?s:"rdbu"):ht,reverse:d}){c=+c,f=It(f);let[h,g]=f;if(f.length>2&&cr(`Warning: the diverging ${t} scale domain contains extra elements.`),be(h,g)<0&&([h,g]=[g,h],d=!d),h=Math.min(h,c),g=Math.max(g,c),typeof m!="function"&&(m=Wp(m)),p!==void 0&&(m=m.length===1?ka(m)(...p):je(m,p)),d&&(m=Up(m)),l){let b=r.apply(c),x=b-r.apply(h),M=r.apply(g)-b;x<M?h=r.invert(b-M):x>M&&(g=r.invert(b+x))}return e.domain([h,c,g]).unknown(u).interpolator(m),a&&e.clamp(a),i&&e.nice(i),{type:o,domain:[h,g],pivot:c,interpolate:m,scale:e}}function w1(t,e,r){return Gc(t,ta(),H8,e,r)}function v1(t,e,r){return Gp(t,e,{...r,exponent:.5})}function Gp(t,e,{exponent:r=1,...n}){return Gc(t,Su().exponent(r=+r),V8(r),e,{...n,type:"diverging-pow"})}function M1(t,e,{base:r=10,pivot:n=1,domain:o=Po(e,n<0?Pc:ga),...i}){return Gc(t,vu().base(r=+r),G8,e,{domain:o,pivot:n,...i})}function S1(t,e,{constant:r=1,...n}){return Gc(t,Mu().constant(r=+r),j8(r),e,n)}var H8={apply(t){return t},invert(t){return t}},G8={apply:Math.log,invert:Math.exp},X8={apply(t){return Math.sign(t)*Math.sqrt(Math.abs(t))},invert(t){return Math.sign(t)*(t*t)}};function V8(t){return t===.5?X8:{apply(e){return Math.sign(e)*Math.pow(Math.abs(e),t)},invert(e){return Math.sign(e)*Math.pow(Math.abs(e),1/t)}}}function j8(t){return{apply(e){return Math.sign(e)*Math.log1p(Math.abs(e/t))},invert(e){return Math.sign(e)*Math.expm1(Math.abs(e))*t}}}function T1(t,e,r,n){return Lo(t,e,r,n)}function _1(t,e,r){return T1(t,yu(),e,r)}function C1(t,e,r){return T1(t,bu(),e,r)}var Fo=Symbol("ordinal");function A1(t,e,r,{type:n,interval:o,domain:i,range:a,reverse:f,hint:u}){return o=fr(o,n),i===void 0&&(i=D1(r,o,t)),(n==="categorical"||n===Fo)&&(n="ordinal"),f&&(i=Ar(i)),e.domain(i),a!==void 0&&(typeof a=="function"&&(a=a(i)),e.range(a)),{type:n,domain:i,range:a,scale:e,hint:u,interval:o}}function E1(t,e,{type:r,interval:n,domain:o,range:i,scheme:a,unknown:f,...u}){n=fr(n,r),o===void 0&&(o=D1(e,n,t));let c;if(st.get(t)===Eo)c=K8(e),i=i===void 0?Z8(c):mt(i,ko);else if(st.get(t)===he&&(i===void 0&&(r==="ordinal"||r===Fo)&&(i=c1(o,a),i!==void 0&&(a=void 0)),a===void 0&&i===void 0&&(a=r==="ordinal"?"turbo":"tableau10"),a!==void 0))if(i!==void 0){let s=Ro(a),p=i[0],l=i[1]-i[0];i=({length:m})=>Gt(d=>s(p+l*d),m)}else i=Bp(a);if(f===Oi)throw new Error(`implicit unknown on ${t} scale is not supported`);return A1(t,xn().unknown(f),e,{...u,type:r,domain:o,range:i,hint:c})}function k1(t,e,{align:r=.5,padding:n=.5,...o}){return N1(Hl().align(r).padding(n),e,o,t)}function I1(t,e,{align:r=.5,padding:n=.1,paddingInner:o=n,paddingOuter:i=t==="fx"||t==="fy"?0:n,...a}){return N1(Lr().align(r).paddingInner(o).paddingOuter(i),e,a,t)}function N1(t,e,r,n){let{round:o}=r;return o!==void 0&&t.round(o=!!o),t=A1(n,t,e,r),t.round=o,t}function D1(t,e,r){let n=new Ae;for(let{value:o,domain:i}of t){if(i!==void 0)return i();if(o!==void 0)for(let a of o)n.add(a)}if(e!==void 0){let[o,i]=Ct(n).map(e.floor,e);return e.range(o,e.offset(i))}if(n.size>1e4&&st.get(r)===de)throw new Error(`implicit ordinal domain of ${r} scale has more than 10,000 values`);return Ue(n,Qt)}function $1(t,e){let r;for(let{hint:n}of t){let o=n?.[e];if(o!==void 0){if(r===void 0)r=o;else if(r!==o)return}}return r}function K8(t){return{fill:$1(t,"fill"),stroke:$1(t,"stroke")}}function Z8(t){return Ft(t.fill)?xp:gp}function Ia(t,{label:e,inset:r=0,insetTop:n=r,insetRight:o=r,insetBottom:i=r,insetLeft:a=r,round:f,nice:u,clamp:c,zero:s,align:p,padding:l,projection:m,facet:{label:d=e}={},...h}={}){let g={};for(let[b,x]of t){let M=h[b],y=t5(b,x,{round:st.get(b)===de?f:void 0,nice:u,clamp:c,zero:s,align:p,padding:l,projection:m,...M});if(y){let{label:w=b==="fx"||b==="fy"?d:e,percent:v,transform:T,inset:E,insetTop:k=E!==void 0?E:b==="y"?n:0,insetRight:q=E!==void 0?E:b==="x"?o:0,insetBottom:_=E!==void 0?E:b==="y"?i:0,insetLeft:R=E!==void 0?E:b==="x"?a:0}=M||{};if(T==null)T=void 0;else if(typeof T!="function")throw new Error("invalid scale transform; not a function");y.percent=!!v,y.label=w===void 0?Q8(x,y):w,y.transform=T,b==="x"||b==="fx"?(y.insetLeft=+R,y.insetRight=+q):(b==="y"||b==="fy")&&(y.insetTop=+k,y.insetBottom=+_),g[b]=y}}return g}function Vp(t){return Object.fromEntries(Object.entries(t).filter(([,{scale:e}])=>e).map(([e,{scale:r,type:n,interval:o,label:i}])=>(r.type=n,o!=null&&(r.interval=o),i!=null&&(r.label=i),[e,r])))}function F1(t,e){let{x:r,y:n,fx:o,fy:i}=t,a=o||i?Vc(e):e;o&&R1(o,a),i&&P1(i,a);let f=o||i?jp(t,e):e;r&&R1(r,f),n&&P1(n,f)}function Q8(t=[],e){let r;for(let{label:n}of t)if(n!==void 0){if(r===void 0)r=n;else if(r!==n)return}if(r!==void 0)return!sr(e)&&e.percent&&(r=`${r} (%)`),{inferred:!0,toString:()=>r}}function Vc(t){let{marginTop:e,marginRight:r,marginBottom:n,marginLeft:o,width:i,height:a,facet:{marginTop:f,marginRight:u,marginBottom:c,marginLeft:s}}=t;return{marginTop:Math.max(e,f),marginRight:Math.max(r,u),marginBottom:Math.max(n,c),marginLeft:Math.max(o,s),width:i,height:a}}function jp({fx:t,fy:e},r){let{marginTop:n,marginRight:o,marginBottom:i,marginLeft:a,width:f,height:u}=Vc(r);return{marginTop:n,marginRight:o,marginBottom:i,marginLeft:a,width:t?t.scale.bandwidth()+a+o:f,height:e?e.scale.bandwidth()+n+i:u,facet:{width:f,height:u}}}function R1(t,e){if(t.range===void 0){let{insetLeft:r,insetRight:n}=t,{width:o,marginLeft:i=0,marginRight:a=0}=e,f=i+r,u=o-a-n;t.range=[f,Math.max(f,u)],sr(t)||(t.range=O1(t)),t.scale.range(t.range)}q1(t)}function P1(t,e){if(t.range===void 0){let{insetTop:r,insetBottom:n}=t,{height:o,marginTop:i=0,marginBottom:a=0}=e,f=i+r,u=o-a-n;t.range=[Math.max(f,u),f],sr(t)?t.range.reverse():t.range=O1(t),t.scale.range(t.range)}q1(t)}function q1(t){t.round===void 0&&r5(t)&&J8(t)<=30&&t.scale.round(!0)}function J8({scale:t}){let e=t.domain().length,[r,n]=t.range(),o=t.paddingInner?t.paddingInner():1,i=t.paddingOuter?t.paddingOuter():t.padding(),a=e-o,f=Math.abs(n-r)/Math.max(1,a+i*2);return(f-Math.floor(f))*a}function O1(t){let e=t.scale.domain().length+Kp(t);if(!(e>2))return t.range;let[r,n]=t.range;return Array.from({length:e},(o,i)=>r+i/(e-1)*(n-r))}function t5(t,e=[],r={}){let n=e5(t,e,r);if(r.type===void 0&&r.domain===void 0&&r.range===void 0&&r.interval==null&&t!=="fx"&&t!=="fy"&&sr({type:n})){let o=e.map(({value:i})=>i).filter(i=>i!==void 0);o.some(Bt)?cr(`Warning: some data associated with the ${t} scale are dates. Dates are typically associated with a "utc" or "time" scale rather than a "${qo(n)}" scale. If you are using a bar mark, you probably want a rect mark with the interval option instead; if you are using a group transform, you probably want a bin transform instead. If you want to treat this data as ordinal, you can specify the interval of the ${t} scale (e.g., d3.utcDay), or you can suppress this warning by setting the type of the ${t} scale to "${qo(n)}".`):o.some(Sg)?cr(`Warning: some data associated with the ${t} scale are strings that appear to be dates (e.g., YYYY-MM-DD). If these strings represent dates, you should parse them to Date objects. Dates are typically associated with a "utc" or "time" scale rather than a "${qo(n)}" scale. If you are using a bar mark, you probably want a rect mark with the interval option instead; if you are using a group transform, you probably want a bin transform instead. If you want to treat this data as ordinal, you can suppress this warning by setting the type of the ${t} scale to "${qo(n)}".`):o.some(Tg)&&cr(`Warning: some data associated with the ${t} scale are strings that appear to be numbers. If these strings represent numbers, you should parse or coerce them to numbers. Numbers are typically associated with a "linear" scale rather than a "${qo(n)}" scale. If you want to treat this data as ordinal, you can specify the interval of the ${t} scale (e.g., 1 for integers), or you can suppress this warning by setting the type of the ${t} scale to "${qo(n)}".`)}switch(r.type=n,n){case"diverging":case"diverging-sqrt":case"diverging-pow":case"diverging-log":case"diverging-symlog":case"cyclical":case"sequential":case"linear":case"sqrt":case"threshold":case"quantile":case"pow":case"log":case"symlog":r=Xc(e,r,An);break;case"identity":switch(st.get(t)){case de:r=Xc(e,r,An);break;case Eo:r=Xc(e,r,n5);break}break;case"utc":case"time":r=Xc(e,r,dg);break}switch(n){case"diverging":return w1(t,e,r);case"diverging-sqrt":return v1(t,e,r);case"diverging-pow":return Gp(t,e,r);case"diverging-log":return M1(t,e,r);case"diverging-symlog":return S1(t,e,r);case"categorical":case"ordinal":case Fo:return E1(t,e,r);case"cyclical":case"sequential":case"linear":return p1(t,e,r);case"sqrt":return m1(t,e,r);case"threshold":return Hc(t,e,r);case"quantile":return g1(t,e,r);case"quantize":return x1(t,e,r);case"pow":return Hp(t,e,r);case"log":return d1(t,e,r);case"symlog":return h1(t,e,r);case"utc":return C1(t,e,r);case"time":return _1(t,e,r);case"point":return k1(t,e,r);case"band":return I1(t,e,r);case"identity":return st.get(t)===de?y1():{type:"identity"};case void 0:return;default:throw new Error(`unknown scale type: ${n}`)}}function qo(t){return typeof t=="symbol"?t.description:t}var L1={toString:()=>"projection"};function e5(t,e,{type:r,domain:n,range:o,scheme:i,pivot:a,projection:f}){if(t==="fx"||t==="fy")return"band";(t==="x"||t==="y")&&f!=null&&(r=L1);for(let{type:c}of e)if(c!==void 0){if(r===void 0)r=c;else if(r!==c)throw new Error(`scale incompatible with channel: ${r} !== ${c}`)}if(r===L1)return;if(r!==void 0)return r;if(n===void 0&&!e.some(({value:c})=>c!==void 0))return;let u=st.get(t);if(u===Co)return"sqrt";if(u===Ao||u===$o)return"linear";if(u===Eo)return"ordinal";if((n||o||[]).length>2)return Xp(u);if(n!==void 0){if(Ap(n))return Xp(u);if(Bt(n))return"utc"}else{let c=e.map(({value:s})=>s).filter(s=>s!==void 0);if(c.some(Ap))return Xp(u);if(c.some(Bt))return"utc"}if(u===he){if(a!=null||s1(i))return"diverging";if(u1(i))return"categorical"}return"linear"}function Xp(t){switch(t){case de:return"point";case he:return Fo;default:return"ordinal"}}function jc({type:t}){return t==="time"||t==="utc"}function sr({type:t}){return t==="ordinal"||t==="point"||t==="band"||t===Fo}function Kp({type:t}){return t==="threshold"}function r5({type:t}){return t==="point"||t==="band"}function Xr(t){if(t===void 0)return!0;let e=t.domain(),r=t(e[0]);for(let n=1,o=e.length;n<o;++n)if(t(e[n])-r)return!1;return!0}function Xc(t,{domain:e,...r},n){for(let o of t)o.value!==void 0&&(o.value=n(o.value));return{domain:e===void 0?e:n(e),...r}}function n5(t){return mt(t,ko)}function Y1(t){return e=>{if(!st.has(e=`${e}`))throw new Error(`unknown scale: ${e}`);return e in t?o5(t[e]):void 0}}function o5({scale:t,type:e,domain:r,range:n,interpolate:o,interval:i,transform:a,percent:f,pivot:u}){if(e==="identity")return{type:"identity",apply:s=>s,invert:s=>s};let c=t.unknown?t.unknown():void 0;return{type:e,domain:Ma(r),...n!==void 0&&{range:Ma(n)},...a!==void 0&&{transform:a},...f&&{percent:f},...c!==void 0&&{unknown:c},...i!==void 0&&{interval:i},...o!==void 0&&{interpolate:o},...t.clamp&&{clamp:t.clamp()},...u!==void 0&&{pivot:u,symmetric:!1},...t.base&&{base:t.base()},...t.exponent&&{exponent:t.exponent()},...t.constant&&{constant:t.constant()},...t.align&&{align:t.align(),round:t.round()},...t.padding&&(t.paddingInner?{paddingInner:t.paddingInner(),paddingOuter:t.paddingOuter()}:{padding:t.padding()}),...t.bandwidth&&{bandwidth:t.bandwidth(),step:t.step()},apply:s=>t(s),...t.invert&&{invert:s=>t.invert(s)}}}function Kc(t){let e,r;return(...n)=>((r?.length!==n.length||r.some((o,i)=>o!==n[i]))&&(r=n,e=t(...n)),e)}var i5=Kc(t=>new Intl.NumberFormat(t)),gG=Kc((t,e)=>new Intl.DateTimeFormat(t,{timeZone:"UTC",...e&&{month:e}})),xG=Kc((t,e)=>new Intl.DateTimeFormat(t,{timeZone:"UTC",...e&&{weekday:e}}));function a5(t="en-US"){let e=i5(t);return r=>r!=null&&!isNaN(r)?e.format(r):void 0}function f5(t){return Lc(t,"Invalid Date")}function u5(t="en-US"){let e=a5(t);return r=>(r instanceof Date?f5:typeof r=="number"?e:Et)(r)}var $e=u5();var zt=(typeof window<"u"?window.devicePixelRatio>1:typeof it>"u")?0:.5,c5=0;function B1(){return`plot-clip-${++c5}`}function z1(t,{title:e,href:r,ariaLabel:n,ariaDescription:o,ariaHidden:i,target:a,fill:f,fillOpacity:u,stroke:c,strokeWidth:s,strokeOpacity:p,strokeLinejoin:l,strokeLinecap:m,strokeMiterlimit:d,strokeDasharray:h,strokeDashoffset:g,opacity:b,mixBlendMode:x,imageFilter:M,paintOrder:y,pointerEvents:w,shapeRendering:v,channels:T},{ariaLabel:E,fill:k="currentColor",fillOpacity:q,stroke:_="none",strokeOpacity:R,strokeWidth:F,strokeLinecap:P,strokeLinejoin:S,strokeMiterlimit:$,paintOrder:N}){k===null&&(f=null,u=null),_===null&&(c=null,p=null),Ft(k)?!Ft(_)&&(!Ft(f)||T?.fill)&&(_="none"):Ft(_)&&(!Ft(c)||T?.stroke)&&(k="none");let[L,D]=ae(f,k),[tt,K]=kt(u,q),[G,Q]=ae(c,_),[at,Ut]=kt(p,R),[Be,X]=kt(b);Nn(Q)||(s===void 0&&(s=F),m===void 0&&(m=P),l===void 0&&(l=S),d===void 0&&!$g(l)&&(d=$),!Nn(D)&&y===void 0&&(y=N));let[W,A]=kt(s);return k!==null&&(t.fill=St(D,"currentColor"),t.fillOpacity=Na(K,1)),_!==null&&(t.stroke=St(Q,"none"),t.strokeWidth=Na(A,1),t.strokeOpacity=Na(Ut,1),t.strokeLinejoin=St(l,"miter"),t.strokeLinecap=St(m,"butt"),t.strokeMiterlimit=Na(d,4),t.strokeDasharray=St(h,"none"),t.strokeDashoffset=St(g,"0")),t.target=Et(a),t.ariaLabel=Et(E),t.ariaDescription=Et(o),t.ariaHidden=Et(i),t.opacity=Na(X,1),t.mixBlendMode=St(x,"normal"),t.imageFilter=St(M,"none"),t.paintOrder=St(y,"normal"),t.pointerEvents=St(w,"auto"),t.shapeRendering=St(v,"auto"),{title:{value:e,optional:!0,filter:null},href:{value:r,optional:!0,filter:null},ariaLabel:{value:n,optional:!0,filter:null},fill:{value:L,scale:"auto",optional:!0},fillOpacity:{value:tt,scale:"auto",optional:!0},stroke:{value:G,scale:"auto",optional:!0},strokeOpacity:{value:at,scale:"auto",optional:!0},strokeWidth:{value:W,optional:!0},opacity:{value:Be,scale:"auto",optional:!0}}}function s5(t,e){e&&t.filter(r=>Rc(e[r])).append("title").call(l5,e)}function l5(t,e){e&&t.text(r=>$e(e[r]))}function Ye(t,{target:e,tip:r},{ariaLabel:n,title:o,fill:i,fillOpacity:a,stroke:f,strokeOpacity:u,strokeWidth:c,opacity:s,href:p}){n&&et(t,"aria-label",l=>n[l]),i&&et(t,"fill",l=>i[l]),a&&et(t,"fill-opacity",l=>a[l]),f&&et(t,"stroke",l=>f[l]),u&&et(t,"stroke-opacity",l=>u[l]),c&&et(t,"stroke-width",l=>c[l]),s&&et(t,"opacity",l=>s[l]),p&&m5(t,l=>p[l],e),r||s5(t,o)}function U1(t){return t===!0?t="frame":t===!1&&(t=null),En(t,"clip",["frame","sphere"])}function p5(t,e,r,n){let o;switch(e.clip){case"frame":{let{width:i,height:a,marginLeft:f,marginRight:u,marginTop:c,marginBottom:s}=r,p=B1();o=`url(#${p})`,t=yt("svg:g",n).call(l=>l.append("svg:clipPath").attr("id",p).append("rect").attr("x",f).attr("y",c).attr("width",i-u-f).attr("height",a-c-s)).each(function(){this.appendChild(t.node()),t.node=()=>this});break}case"sphere":{let{projection:i}=n;if(!i)throw new Error('the "sphere" clip option requires a projection');let a=B1();o=`url(#${a})`,t.append("clipPath").attr("id",a).append("path").attr("d",Ei(i)({type:"Sphere"}));break}}et(t,"aria-label",e.ariaLabel),et(t,"aria-description",e.ariaDescription),et(t,"aria-hidden",e.ariaHidden),et(t,"clip-path",o)}function ge(t,e,r,n){p5(t,e,r,n),et(t,"fill",e.fill),et(t,"fill-opacity",e.fillOpacity),et(t,"stroke",e.stroke),et(t,"stroke-width",e.strokeWidth),et(t,"stroke-opacity",e.strokeOpacity),et(t,"stroke-linejoin",e.strokeLinejoin),et(t,"stroke-linecap",e.strokeLinecap),et(t,"stroke-miterlimit",e.strokeMiterlimit),et(t,"stroke-dasharray",e.strokeDasharray),et(t,"stroke-dashoffset",e.strokeDashoffset),et(t,"shape-rendering",e.shapeRendering),et(t,"filter",e.imageFilter),et(t,"paint-order",e.paintOrder);let{pointerEvents:o=n.pointerSticky===!1?"none":void 0}=e;et(t,"pointer-events",o)}function xe(t,e){d5(t,"mix-blend-mode",e.mixBlendMode),et(t,"opacity",e.opacity)}function m5(t,e,r){t.each(function(n){let o=e(n);if(o!=null){let i=this.ownerDocument.createElementNS(We.svg,"a");i.setAttribute("fill","inherit"),i.setAttributeNS(We.xlink,"href",o),r!=null&&i.setAttribute("target",r),this.parentNode.insertBefore(i,this).appendChild(this)}})}function et(t,e,r){r!=null&&t.attr(e,r)}function d5(t,e,r){r!=null&&t.style(e,r)}function ye(t,e,{x:r,y:n},o=zt,i=zt){o+=e.dx,i+=e.dy,r?.bandwidth&&(o+=r.bandwidth()/2),n?.bandwidth&&(i+=n.bandwidth()/2),(o||i)&&t.attr("transform",`translate(${o},${i})`)}function St(t,e){if((t=Et(t))!==e)return t}function Na(t,e){if((t=ct(t))!==e)return t}var h5=/^-?([_a-z]|[\240-\377]|\\[0-9a-f]{1,6}(\r\n|[ \t\r\n\f])?|\\[^\r\n\f0-9a-f])([_a-z0-9-]|[\240-\377]|\\[0-9a-f]{1,6}(\r\n|[ \t\r\n\f])?|\\[^\r\n\f0-9a-f])*$/i;function Oo(t){if(t===void 0)return"plot-d6a7b5";if(t=`${t}`,!h5.test(t))throw new Error(`invalid class name: ${t}`);return t}function Yo(t,e){if(typeof e=="string")t.property("style",e);else if(e!=null)for(let r of t)Object.assign(r.style,e)}function Vr({frameAnchor:t},{width:e,height:r,marginTop:n,marginRight:o,marginBottom:i,marginLeft:a}){return[/left$/.test(t)?a:/right$/.test(t)?e-o:(a+e-o)/2,/^top/.test(t)?n:/^bottom/.test(t)?r-i:(n+r-i)/2]}function H1(t,e,r={}){let n=.5-zt,o=.5+zt,i=.5+zt,a=.5-zt;for(let{marginTop:h,marginRight:g,marginBottom:b,marginLeft:x}of e)h>n&&(n=h),g>o&&(o=g),b>i&&(i=b),x>a&&(a=x);let{margin:f,marginTop:u=f!==void 0?f:n,marginRight:c=f!==void 0?f:o,marginBottom:s=f!==void 0?f:i,marginLeft:p=f!==void 0?f:a}=r;u=+u,c=+c,s=+s,p=+p;let{width:l=640,height:m=g5(t,r,{width:l,marginTopDefault:n,marginRightDefault:o,marginBottomDefault:i,marginLeftDefault:a})+Math.max(0,u-n+s-i)}=r;l=+l,m=+m;let d={width:l,height:m,marginTop:u,marginRight:c,marginBottom:s,marginLeft:p};if(t.fx||t.fy){let{margin:h,marginTop:g=h!==void 0?h:u,marginRight:b=h!==void 0?h:c,marginBottom:x=h!==void 0?h:s,marginLeft:M=h!==void 0?h:p}=r.facet??{};g=+g,b=+b,x=+x,M=+M,d.facet={marginTop:g,marginRight:b,marginBottom:x,marginLeft:M}}return d}function g5({x:t,y:e,fy:r,fx:n},{projection:o,aspectRatio:i},{width:a,marginTopDefault:f,marginRightDefault:u,marginBottomDefault:c,marginLeftDefault:s}){let p=r?r.scale.domain().length:1,l=e1(o);if(l){let d=n?n.scale.domain().length:1,h=(1.1*p-.1)/(1.1*d-.1)*l,g=Math.max(.1,Math.min(10,h));return Math.round((a-s-u)*g+f+c)}let m=e?sr(e)?e.scale.domain().length:Math.max(7,17/p):1;if(i!=null){if(i=+i,!(isFinite(i)&&i>0))throw new Error(`invalid aspectRatio: ${i}`);let d=W1("y",e)/(W1("x",t)*i),h=n?n.scale.bandwidth():1,g=r?r.scale.bandwidth():1,b=h*(a-s-u)-t.insetLeft-t.insetRight;return(d*b+e.insetTop+e.insetBottom)/g+f+c}return!!(e||r)*Math.max(1,Math.min(60,m*p))*20+!!n*30+60}function W1(t,e){if(!e)throw new Error(`aspectRatio requires ${t} scale`);let{type:r,domain:n}=e,o;switch(r){case"linear":case"utc":case"time":o=Number;break;case"pow":{let f=e.scale.exponent();o=u=>Math.pow(u,f);break}case"log":o=Math.log;break;case"point":case"band":return n.length;default:throw new Error(`unsupported ${t} scale for aspectRatio: ${r}`)}let[i,a]=Ct(n);return Math.abs(o(a)-o(i))}function X1(t,e){let{fx:r,fy:n}=Ia(t,e),o=r?.scale.domain(),i=n?.scale.domain();return o&&i?Ua(o,i).map(([a,f],u)=>({x:a,y:f,i:u})):o?o.map((a,f)=>({x:a,i:f})):i?i.map((a,f)=>({y:a,i:f})):void 0}function V1(t,{x:e,y:r}){return e&&=em(e),r&&=em(r),t.filter(e&&r?n=>e.has(n.x)&&r.has(n.y):e?n=>e.has(n.x):n=>r.has(n.y)).sort(e&&r?(n,o)=>e.get(n.x)-e.get(o.x)||r.get(n.y)-r.get(o.y):e?(n,o)=>e.get(n.x)-e.get(o.x):(n,o)=>r.get(n.y)-r.get(o.y))}function Qc(t,{fx:e,fy:r}){let n=oe(t),o=e?.value,i=r?.value;return e&&r?Ee(n,a=>(a.fx=o[a[0]],a.fy=i[a[0]],a),a=>o[a],a=>i[a]):e?Ee(n,a=>(a.fx=o[a[0]],a),a=>o[a]):Ee(n,a=>(a.fy=i[a[0]],a),a=>i[a])}function j1(t,e,{marginTop:r,marginLeft:n}){return t&&e?({x:o,y:i})=>`translate(${t(o)-n},${e(i)-r})`:t?({x:o})=>`translate(${t(o)-n},0)`:({y:o})=>`translate(0,${e(o)-r})`}function K1(t){let e=[],r=new Uint32Array(ue(t,n=>n.length));for(let n of t){let o=0;for(let i of t)n!==i&&(r.set(i,o),o+=i.length);e.push(r.slice(0,o))}return e}var x5=new Map([["top",Zp],["right",tm],["bottom",Qp],["left",Jp],["top-left",Zc(Zp,Jp)],["top-right",Zc(Zp,tm)],["bottom-left",Zc(Qp,Jp)],["bottom-right",Zc(Qp,tm)],["top-empty",b5],["right-empty",M5],["bottom-empty",w5],["left-empty",v5],["empty",S5]]);function Z1(t){if(t==null)return null;let e=x5.get(`${t}`.toLowerCase());if(e)return e;throw new Error(`invalid facet anchor: ${t}`)}var G1=new WeakMap;function em(t){let e=G1.get(t);return e||G1.set(t,e=new Jt(mt(t,(r,n)=>[r,n]))),e}function jr(t,e){return em(t).get(e)}function y5(t,e,r){return e=Mo(e),r=Mo(r),t.find(n=>Object.is(Mo(n.x),e)&&Object.is(Mo(n.y),r))}function Jc(t,e,r){return y5(t,e,r)?.empty}function Zp(t,{y:e},{y:r}){return e?jr(e,r)===0:!0}function Qp(t,{y:e},{y:r}){return e?jr(e,r)===e.length-1:!0}function Jp(t,{x:e},{x:r}){return e?jr(e,r)===0:!0}function tm(t,{x:e},{x:r}){return e?jr(e,r)===e.length-1:!0}function b5(t,{y:e},{x:r,y:n,empty:o}){if(o)return!1;if(!e)return;let i=jr(e,n);if(i>0)return Jc(t,r,e[i-1])}function w5(t,{y:e},{x:r,y:n,empty:o}){if(o)return!1;if(!e)return;let i=jr(e,n);if(i<e.length-1)return Jc(t,r,e[i+1])}function v5(t,{x:e},{x:r,y:n,empty:o}){if(o)return!1;if(!e)return;let i=jr(e,r);if(i>0)return Jc(t,e[i-1],n)}function M5(t,{x:e},{x:r,y:n,empty:o}){if(o)return!1;if(!e)return;let i=jr(e,r);if(i<e.length-1)return Jc(t,e[i+1],n)}function S5(t,e,{empty:r}){return r}function Zc(t,e){return function(){return t.apply(null,arguments)&&e.apply(null,arguments)}}function ts(t,{channels:{fx:e,fy:r},groups:n}){return e&&r?t.map(({x:o,y:i})=>n.get(o)?.get(i)??[]):e?t.map(({x:o})=>n.get(o)??[]):t.map(({y:o})=>n.get(o)??[])}var Nt=class{constructor(e,r={},n={},o){let{facet:i="auto",facetAnchor:a,fx:f,fy:u,sort:c,dx:s=0,dy:p=0,margin:l=0,marginTop:m=l,marginRight:d=l,marginBottom:h=l,marginLeft:g=l,clip:b,channels:x,tip:M,render:y}=n;if(this.data=e,this.sort=kn(c)?c:null,this.initializer=Dn(n).initializer,this.transform=this.initializer?n.transform:Io(n).transform,i===null||i===!1?this.facet=null:(this.facet=me(i===!0?"include":i,"facet",["auto","include","exclude","super"]),this.fx=e===wo&&typeof f=="string"?[f]:f,this.fy=e===wo&&typeof u=="string"?[u]:u),this.facetAnchor=Z1(a),r=kp(r),x!==void 0&&(r={...T5(x),...r}),o!==void 0&&(r={...z1(this,n,o),...r}),this.channels=Object.fromEntries(Object.entries(r).map(([w,v])=>{if(Sa(v.value)){let{value:T,scale:E=v.scale}=v.value;v={...v,scale:E,value:T}}if(e===wo&&typeof v.value=="string"){let{value:T}=v;v={...v,value:[T]}}return[w,v]}).filter(([w,{value:v,optional:T}])=>{if(v!=null)return!0;if(T)return!1;throw new Error(`missing channel value: ${w}`)})),this.dx=+s,this.dy=+p,this.marginTop=+m,this.marginRight=+d,this.marginBottom=+h,this.marginLeft=+g,this.clip=U1(b),this.tip=_5(M),this.facet==="super"){if(f||u)throw new Error("super-faceting cannot use fx or fy");for(let w in this.channels){let{scale:v}=r[w];if(!(v!=="x"&&v!=="y"))throw new Error("super-faceting cannot use x or y")}}y!=null&&(this.render=nm(y,this.render))}initialize(e,r,n){let o=It(this.data);e===void 0&&o!=null&&(e=[oe(o)]);let i=e;this.transform!=null&&({facets:e,data:o}=this.transform(o,e,n),o=It(o)),e!==void 0&&(e.original=i);let a=Bg(this.channels,o);return this.sort!=null&&Ug(o,e,a,r,this.sort),{data:o,facets:e,channels:a}}filter(e,r,n){for(let o in r){let{filter:i=Fe}=r[o];if(i!==null){let a=n[o];e=e.filter(f=>i(a[f]))}}return e}project(e,r,n){for(let o in e)if(e[o].scale==="x"&&/^x|x$/.test(o)){let i=o.replace(/^x|x$/,"y");i in e&&e[i].scale==="y"&&Jg(o,i,r,n.projection)}}scale(e,r,n){let o=zg(e,r);return n.projection&&this.project(e,o,n),o}};function rm(...t){return t.plot=Nt.prototype.plot,t}function nm(t,e){if(t==null)return e===null?void 0:e;if(e==null)return t===null?void 0:t;if(typeof t!="function")throw new TypeError(`invalid render transform: ${t}`);if(typeof e!="function")throw new TypeError(`invalid render transform: ${e}`);return function(r,n,o,i,a,f){return t.call(this,r,n,o,i,a,(u,c,s,p,l)=>e.call(this,u,c,s,p,l,f))}}function T5(t){return Object.fromEntries(Object.entries(kp(t)).map(([e,r])=>(r=ur(r),r.filter===void 0&&r.scale==null&&(r={...r,filter:null}),[e,r])))}function _5(t){return t===!0?"xy":t===!1?null:En(t,"tip",["x","y","xy"])}function Da(t,e){return t?.tip===!0?{...t,tip:e}:t}var Q1=new WeakMap;function om(t,e,{x:r,y:n,px:o,py:i,maxRadius:a=40,channels:f,render:u,...c}={}){return a=+a,o!=null&&(r??=null,f={...f,px:{value:o,scale:"x"}}),i!=null&&(n??=null,f={...f,py:{value:i,scale:"y"}}),{x:r,y:n,channels:f,...c,render:nm(function(s,p,l,m,d,h){d={...d,pointerSticky:!1};let g=d.ownerSVGElement,{data:b}=d.getMarkState(this),x=Q1.get(g);x||Q1.set(g,x={sticky:!1,roots:[],renders:[]});let M=x.renders.push(Q)-1,{x:y,y:w,fx:v,fy:T}=p,E=v?v(s.fx)-m.marginLeft:0,k=T?T(s.fy)-m.marginTop:0;y?.bandwidth&&(E+=y.bandwidth()/2),w?.bandwidth&&(k+=w.bandwidth()/2);let q=s.fi!=null,_;if(q){let X=x.facetStates;X||(x.facetStates=X=new Map),_=X.get(this),_||X.set(this,_=new Map)}let[R,F]=Vr(this,m),{px:P,py:S}=l,$=P?X=>P[X]:im(l,R),N=S?X=>S[X]:am(l,F),L,D,tt,K;function G(X,W){if(q)if(K&&(K=cancelAnimationFrame(K)),X==null)_.delete(s.fi);else{_.set(s.fi,W),K=requestAnimationFrame(()=>{K=null;for(let A of _.values())if(A<W){X=null;break}Q(X)});return}Q(X)}function Q(X){if(L===X&&tt===x.sticky)return;L=X,tt=d.pointerSticky=x.sticky;let W=L==null?[]:[L];q&&(W.fx=s.fx,W.fy=s.fy,W.fi=s.fi);let A=h(W,p,l,m,d);if(D){if(q){let O=D.parentNode,Y=D.getAttribute("transform"),C=A.getAttribute("transform");Y?A.setAttribute("transform",Y):A.removeAttribute("transform"),C?O.setAttribute("transform",C):O.removeAttribute("transform"),A.removeAttribute("aria-label"),A.removeAttribute("aria-description"),A.removeAttribute("aria-hidden")}D.replaceWith(A)}return x.roots[M]=D=A,d.dispatchValue(L==null?null:b[L]),A}function at(X){if(x.sticky||X.pointerType==="mouse"&&X.buttons===1)return;let[W,A]=ri(X);W-=E,A-=k;let O=null,Y=a*a;for(let C of s){let j=t*($(C)-W),J=e*(N(C)-A),ot=j*j+J*J;ot<=Y&&(O=C,Y=ot)}G(O,Y)}function Ut(X){X.pointerType==="mouse"&&L!=null&&(x.sticky&&x.roots.some(W=>W?.contains(X.target))||(x.sticky?(x.sticky=!1,x.renders.forEach(W=>W(null))):(x.sticky=!0,Q(L)),X.stopImmediatePropagation()))}function Be(X){X.pointerType==="mouse"&&(x.sticky||G(null))}return g.addEventListener("pointerenter",at),g.addEventListener("pointermove",at),g.addEventListener("pointerdown",Ut),g.addEventListener("pointerleave",Be),Q(null)},u)}}function J1(t){return om(1,1,t)}function tx(t){return om(1,.01,t)}function ex(t){return om(.01,1,t)}function im({x1:t,x2:e,x:r=t},n){return t&&e?o=>(t[o]+e[o])/2:r?o=>r[o]:()=>n}function am({y1:t,y2:e,y:r=t},n){return t&&e?o=>(t[o]+e[o])/2:r?o=>r[o]:()=>n}function es(t){return sr(t)&&t.interval===void 0?void 0:"tabular-nums"}function rx(t,e){let{label:r=t.label,tickSize:n=6,width:o=240,height:i=44+n,marginTop:a=18,marginRight:f=0,marginBottom:u=16+n,marginLeft:c=0,style:s,ticks:p=(o-c-f)/64,tickFormat:l,fontVariant:m=es(t),round:d=!0,opacity:h,className:g}=e,b=Do(e);g=Oo(g),h=kt(h)[1],l===null&&(l=()=>null);let x=yt("svg",b).attr("class",`${g}-ramp`).attr("font-family","system-ui, sans-serif").attr("font-size",10).attr("width",o).attr("height",i).attr("viewBox",`0 0 ${o} ${i}`).call(R=>R.append("style").text(`.${g}-ramp {
   display: block;
   background: white;
   height: auto;
@@ -14,9 +14,9 @@ ${f}`;switch(Pv(t,e,n)){case"millisecond":return Sr(o(".%L"),o(":%M:%S"),i);case
 }
 .${g}-ramp text {
   white-space: pre;
-}`)).call(Oo,s),M=R=>R.selectAll(".tick line").attr("y1",a+u-i),y,w=m?(R,O)=>R.rangeRound(O):(R,O)=>R.range(O),{type:v,domain:T,range:E,interpolate:I,scale:F,pivot:_}=t;if(I){let R=E===void 0?I:He(I.length===1?Ca(I):I,E);y=w(F.copy(),Wt(ht(c,o-f),Math.min(T.length+(_!==void 0),E===void 0?1/0:E.length)));let O=256,L=b.document.createElement("canvas");L.width=O,L.height=1;let S=L.getContext("2d");for(let A=0,N=O-1;A<O;++A)S.fillStyle=R(A/N),S.fillRect(A,0,1,1);x.append("image").attr("opacity",h).attr("x",c).attr("y",a).attr("width",o-c-f).attr("height",i-a-u).attr("preserveAspectRatio","none").attr("xlink:href",L.toDataURL())}else if(v==="threshold"){let R=T,O=l===void 0?L=>L:typeof l=="string"?Ve(l):l;y=w(hr().domain([-1,E.length-1]),[c,o-f]),x.append("g").attr("fill-opacity",h).selectAll().data(E).enter().append("rect").attr("x",(L,S)=>y(S-1)).attr("y",a).attr("width",(L,S)=>y(S)-y(S-1)).attr("height",i-a-u).attr("fill",L=>L),d=pt(R,(L,S)=>S),l=L=>O(R[L],L)}else y=w(Rn().domain(T),[c,o-f]),x.append("g").attr("fill-opacity",h).selectAll().data(T).enter().append("rect").attr("x",y).attr("y",a).attr("width",Math.max(0,y.bandwidth()-1)).attr("height",i-a-u).attr("fill",F),M=()=>{};return x.append("g").attr("transform",`translate(0,${i-u})`).call(xs(y).ticks(Array.isArray(d)?null:d,typeof l=="string"?l:void 0).tickFormat(typeof l=="function"?l:void 0).tickSize(r).tickValues(Array.isArray(d)?d:null)).attr("font-size",null).attr("font-family",null).attr("font-variant",St(p,"normal")).call(M).call(R=>R.select(".domain").remove()),n!==void 0&&x.append("text").attr("x",c).attr("y",a-6).attr("fill","currentColor").attr("font-weight","bold").text(n),x.node()}var ap=Math.PI/180;function up(t,{marker:e,markerStart:n=e,markerMid:r=e,markerEnd:o=e}={}){t.markerStart=fp(n),t.markerMid=fp(r),t.markerEnd=fp(o)}function fp(t){if(t==null||t===!1)return null;if(t===!0)return wx;if(typeof t=="function")return t;switch(`${t}`.toLowerCase()){case"none":return null;case"arrow":return bx("auto");case"arrow-reverse":return bx("auto-start-reverse");case"dot":return V8;case"circle":case"circle-fill":return wx;case"circle-stroke":return j8}throw new Error(`invalid marker: ${t}`)}function bx(t){return(e,n)=>yt("svg:marker",n).attr("viewBox","-5 -5 10 10").attr("markerWidth",6.67).attr("markerHeight",6.67).attr("orient",t).attr("fill","none").attr("stroke",e).attr("stroke-width",1.5).attr("stroke-linecap","round").attr("stroke-linejoin","round").call(r=>r.append("path").attr("d","M-1.5,-3l3,3l-3,3")).node()}function V8(t,e){return yt("svg:marker",e).attr("viewBox","-5 -5 10 10").attr("markerWidth",6.67).attr("markerHeight",6.67).attr("fill",t).attr("stroke","none").call(n=>n.append("circle").attr("r",2.5)).node()}function wx(t,e){return yt("svg:marker",e).attr("viewBox","-5 -5 10 10").attr("markerWidth",6.67).attr("markerHeight",6.67).attr("fill",t).attr("stroke","white").attr("stroke-width",1.5).call(n=>n.append("circle").attr("r",3)).node()}function j8(t,e){return yt("svg:marker",e).attr("viewBox","-5 -5 10 10").attr("markerWidth",6.67).attr("markerHeight",6.67).attr("fill","white").attr("stroke",t).attr("stroke-width",1.5).call(n=>n.append("circle").attr("r",3)).node()}var Z8=0;function cp(t,e,{stroke:n},r){return K8(t,e,n&&(o=>n[o]),r)}function K8(t,{markerStart:e,markerMid:n,markerEnd:r,stroke:o},i=()=>o,a){let f=new Map;function u(c){return function(s){let d=i(s),l=f.get(c);l||f.set(c,l=new Map);let p=l.get(d);if(!p){let m=this.parentNode.insertBefore(c(d,a),this),h=`plot-marker-${++Z8}`;m.setAttribute("id",h),l.set(d,p=`url(#${h})`)}return p}}e&&t.attr("marker-start",u(e)),n&&t.attr("marker-mid",u(n)),r&&t.attr("marker-end",u(r))}function Ia({inset:t,insetLeft:e,insetRight:n,...r}={}){return[e,n]=vx(t,e,n),{inset:t,insetLeft:e,insetRight:n,...r}}function Wc({inset:t,insetTop:e,insetBottom:n,...r}={}){return[e,n]=vx(t,e,n),{inset:t,insetTop:e,insetBottom:n,...r}}function vx(t,e,n){return t===void 0&&e===void 0&&n===void 0?Bt?[1,0]:[.5,.5]:[e,n]}function Mx(t,{interval:e}){return t={...on(t)},t.interval=kc(t.interval===void 0?e:t.interval),t}function Sx(t,e,n,r){let{[t]:o,[`${t}1`]:i,[`${t}2`]:a}=n,{value:f,interval:u}=Mx(o,n);if(f==null||u==null&&!r)return n;let c=Le(o);if(u==null){let p,m={transform:h=>p||(p=gt(h,f)),label:c};return{...n,[t]:void 0,[`${t}1`]:i===void 0?m:i,[`${t}2`]:a===void 0?m:a}}let s,d;function l(p){return d!==void 0&&p===s?d:d=pt(gt(s=p,f),m=>u.floor(m))}return e({...n,[t]:void 0,[`${t}1`]:i===void 0?{transform:l,label:c}:i,[`${t}2`]:a===void 0?{transform:p=>l(p).map(m=>u.offset(m)),label:c}:a})}function Tx(t,e,n){let{[t]:r}=n,{value:o,interval:i}=Mx(r,n);return o==null||i==null?n:e({...n,[t]:{label:Le(r),transform:a=>{let f=pt(gt(a,o),c=>i.floor(c)),u=f.map(c=>i.offset(c));return f.map(Yt(f)?(c,s)=>c==null||isNaN(c=+c)||(s=u[s],s==null)||isNaN(s=+s)?void 0:new Date((c+s)/2):(c,s)=>c==null||(s=u[s],s==null)?NaN:(+c+ +s)/2)}}})}function sp(t={}){return Sx("x",Ia,t)}function Gc(t={}){return Sx("y",Wc,t)}function _x(t={}){return Tx("x",Ia,t)}function Cx(t={}){return Tx("y",Wc,t)}var Ax={ariaLabel:"rule",fill:null,stroke:"currentColor"},lp=class extends Nt{constructor(e,n={}){let{x:r,y1:o,y2:i,inset:a=0,insetTop:f=a,insetBottom:u=a}=n;super(e,{x:{value:r,scale:"x",optional:!0},y1:{value:o,scale:"y",optional:!0},y2:{value:i,scale:"y",optional:!0}},Ea(n,"x"),Ax),this.insetTop=ct(f),this.insetBottom=ct(u),up(this,n)}render(e,n,r,o,i){let{x:a,y:f}=n,{x:u,y1:c,y2:s}=r,{width:d,height:l,marginTop:p,marginRight:m,marginLeft:h,marginBottom:g}=o,{insetTop:b,insetBottom:x}=this;return yt("svg:g",i).call(me,this,o,i).call(ge,this,{x:u&&a},Bt,0).call(M=>M.selectAll().data(e).enter().append("line").call(he,this).attr("x1",u?y=>u[y]:(h+d-m)/2).attr("x2",u?y=>u[y]:(h+d-m)/2).attr("y1",c&&!Wn(f)?y=>c[y]+b:p+b).attr("y2",s&&!Wn(f)?f.bandwidth?y=>s[y]+f.bandwidth()-x:y=>s[y]-x:l-g-x).call(Oe,this,r).call(cp,this,r,i)).node()}},dp=class extends Nt{constructor(e,n={}){let{x1:r,x2:o,y:i,inset:a=0,insetRight:f=a,insetLeft:u=a}=n;super(e,{y:{value:i,scale:"y",optional:!0},x1:{value:r,scale:"x",optional:!0},x2:{value:o,scale:"x",optional:!0}},Ea(n,"y"),Ax),this.insetRight=ct(f),this.insetLeft=ct(u),up(this,n)}render(e,n,r,o,i){let{x:a,y:f}=n,{y:u,x1:c,x2:s}=r,{width:d,height:l,marginTop:p,marginRight:m,marginLeft:h,marginBottom:g}=o,{insetLeft:b,insetRight:x}=this;return yt("svg:g",i).call(me,this,o,i).call(ge,this,{y:u&&f},0,Bt).call(M=>M.selectAll().data(e).enter().append("line").call(he,this).attr("x1",c&&!Wn(a)?y=>c[y]+b:h+b).attr("x2",s&&!Wn(a)?a.bandwidth?y=>s[y]+a.bandwidth()-x:y=>s[y]-x:d-m-x).attr("y1",u?y=>u[y]:(p+l-g)/2).attr("y2",u?y=>u[y]:(p+l-g)/2).call(Oe,this,r).call(cp,this,r,i)).node()}};function $x(t,e){let{x:n=wt,y:r,y1:o,y2:i,...a}=Gc(e);return[o,i]=Ix(r,o,i),new lp(t,{...a,x:n,y1:o,y2:i})}function Ex(t,e){let{y:n=wt,x:r,x1:o,x2:i,...a}=sp(e);return[o,i]=Ix(r,o,i),new dp(t,{...a,y:n,x1:o,x2:i})}function Ix(t,e,n){if(t==null){if(e===void 0){if(n!==void 0)return[0,n]}else if(n===void 0)return[0,e]}else{if(e===void 0)return n===void 0?[0,t]:[t,n];if(n===void 0)return[t,e]}return[e,n]}function Vc(t,...e){let n=e.length;for(let r=0,o=!0;r<n;++r)typeof e[r]!="function"&&(o&&(t=t.slice(),o=!1),t.splice(r,2,t[r]+e[r]+t[r+1]),e.splice(r,1),--r,--n);return r=>{let o=t[0];for(let i=0;i<n;++i)o+=e[i](r)+t[i+1];return o}}var Q8={ariaLabel:"text",strokeLinejoin:"round",strokeWidth:3,paintOrder:"stroke"},Lx="\xAD",Fo=class extends Nt{constructor(e,n={}){let{x:r,y:o,text:i=re(e)&&Nc(e)?wt:Ec,frameAnchor:a,textAnchor:f=/right$/i.test(a)?"end":/left$/i.test(a)?"start":"middle",lineAnchor:u=/^top/i.test(a)?"top":/^bottom/i.test(a)?"bottom":"middle",lineHeight:c=1,lineWidth:s=1/0,textOverflow:d,monospace:l,fontFamily:p=l?"ui-monospace, monospace":void 0,fontSize:m,fontStyle:h,fontVariant:g,fontWeight:b,rotate:x}=n,[M,y]=It(x,0),[w,v]=n5(m);if(super(e,{x:{value:r,scale:"x",optional:!0},y:{value:o,scale:"y",optional:!0},fontSize:{value:w,optional:!0},rotate:{value:Fg(M),optional:!0},text:{value:i,filter:Tc,optional:!0}},n,Q8),this.rotate=y,this.textAnchor=St(f,"middle"),this.lineAnchor=le(u,"lineAnchor",["top","middle","bottom"]),this.lineHeight=+c,this.lineWidth=+s,this.textOverflow=mp(d),this.monospace=!!l,this.fontFamily=Et(p),this.fontSize=v,this.fontStyle=Et(h),this.fontVariant=Et(g),this.fontWeight=Et(b),this.frameAnchor=Mo(a),!(this.lineWidth>=0))throw new Error(`invalid lineWidth: ${s}`);this.splitLines=yp(this),this.clipLine=bp(this)}render(e,n,r,o,i){let{x:a,y:f}=n,{x:u,y:c,rotate:s,text:d,title:l,fontSize:p}=r,{rotate:m}=this,[h,g]=Gn(this,o);return yt("svg:g",i).call(me,this,o,i).call(xp,this,d,o).call(ge,this,{x:u&&a,y:c&&f}).call(b=>b.selectAll().data(e).enter().append("text").call(he,this).call(J8,this,d,l).attr("transform",Vc`translate(${u?x=>u[x]:h},${c?x=>c[x]:g})${s?x=>` rotate(${s[x]})`:m?` rotate(${m})`:""}`).call(et,"font-size",p&&(x=>p[x])).call(Oe,this,r)).node()}};function mp(t){return t==null?null:le(t,"textOverflow",["clip","ellipsis","clip-start","clip-end","ellipsis-start","ellipsis-middle","ellipsis-end"]).replace(/^(clip|ellipsis)$/,"$1-end")}function J8(t,e,n,r){if(!n)return;let{lineAnchor:o,lineHeight:i,textOverflow:a,splitLines:f,clipLine:u}=e;t.each(function(c){let s=f(_e(n[c])??"").map(u),d=s.length,l=o==="top"?.71:o==="bottom"?1-d:(164-d*100)/200;if(d>1){let p=0;for(let m=0;m<d;++m){if(++p,!s[m])continue;let h=this.ownerDocument.createElementNS(Ye.svg,"tspan");h.setAttribute("x",0),m===p-1?h.setAttribute("y",`${(l+m)*i}em`):h.setAttribute("dy",`${p*i}em`),h.textContent=s[m],this.appendChild(h),p=0}}else l&&this.setAttribute("y",`${l*i}em`),this.textContent=s[0];if(a&&!r&&s[0]!==n[c]){let p=this.ownerDocument.createElementNS(Ye.svg,"title");p.textContent=n[c],this.appendChild(p)}})}function qo(t,{x:e,y:n,...r}={}){return r.frameAnchor===void 0&&([e,n]=Ar(e,n)),new Fo(t,{...r,x:e,y:n})}function hp(t,{x:e=wt,...n}={}){return new Fo(t,Cx({...n,x:e}))}function gp(t,{y:e=wt,...n}={}){return new Fo(t,_x({...n,y:e}))}function xp(t,e,n){et(t,"text-anchor",e.textAnchor),et(t,"font-family",e.fontFamily),et(t,"font-size",e.fontSize),et(t,"font-style",e.fontStyle),et(t,"font-variant",e.fontVariant===void 0?t5(n):e.fontVariant),et(t,"font-weight",e.fontWeight)}function t5(t){return t&&(Yg(t)||Yt(t))?"tabular-nums":void 0}var e5=new Set(["inherit","initial","revert","unset","xx-small","x-small","small","medium","large","x-large","xx-large","xxx-large","larger","smaller"]);function n5(t){return t==null||typeof t=="number"?[void 0,t]:typeof t!="string"?[t,void 0]:(t=t.trim().toLowerCase(),e5.has(t)||/^[+-]?\d*\.?\d+(e[+-]?\d+)?(\w*|%)$/.test(t)?[void 0,t]:[t,void 0])}function r5(t,e,n){let r=[],o,i=0;for(let[a,f,u]of o5(t)){if(o===void 0&&(o=a),i>o&&n(t,o,f)>e&&(r.push(t.slice(o,i)+(t[i-1]===Lx?"-":"")),o=a),u){r.push(t.slice(o,f)),o=void 0;continue}i=f}return r}function*o5(t){let e=0,n=0,r=t.length;for(;n<r;){let o=1;switch(t[n]){case Lx:case"-":++n,yield[e,n,!1],e=n;break;case" ":for(yield[e,n,!1];t[++n]===" ";);e=n;break;case"\r":t[n+1]===`
+}`)).call(Yo,s),M=R=>R.selectAll(".tick line").attr("y1",a+u-i),y,w=d?(R,F)=>R.rangeRound(F):(R,F)=>R.range(F),{type:v,domain:T,range:E,interpolate:k,scale:q,pivot:_}=t;if(k){let R=E===void 0?k:je(k.length===1?ka(k):k,E);y=w(q.copy(),Gt(ht(c,o-f),Math.min(T.length+(_!==void 0),E===void 0?1/0:E.length)));let F=256,P=b.document.createElement("canvas");P.width=F,P.height=1;let S=P.getContext("2d");for(let $=0,N=F-1;$<F;++$)S.fillStyle=R($/N),S.fillRect($,0,1,1);x.append("image").attr("opacity",h).attr("x",c).attr("y",a).attr("width",o-c-f).attr("height",i-a-u).attr("preserveAspectRatio","none").attr("xlink:href",P.toDataURL())}else if(v==="threshold"){let R=T,F=l===void 0?P=>P:typeof l=="string"?Qe(l):l;y=w(bn().domain([-1,E.length-1]),[c,o-f]),x.append("g").attr("fill-opacity",h).selectAll().data(E).enter().append("rect").attr("x",(P,S)=>y(S-1)).attr("y",a).attr("width",(P,S)=>y(S)-y(S-1)).attr("height",i-a-u).attr("fill",P=>P),p=mt(R,(P,S)=>S),l=P=>F(R[P],P)}else y=w(Lr().domain(T),[c,o-f]),x.append("g").attr("fill-opacity",h).selectAll().data(T).enter().append("rect").attr("x",y).attr("y",a).attr("width",Math.max(0,y.bandwidth()-1)).attr("height",i-a-u).attr("fill",q),M=()=>{};return x.append("g").attr("transform",`translate(0,${i-u})`).call(Ts(y).ticks(Array.isArray(p)?null:p,typeof l=="string"?l:void 0).tickFormat(typeof l=="function"?l:void 0).tickSize(n).tickValues(Array.isArray(p)?p:null)).attr("font-size",null).attr("font-family",null).attr("font-variant",St(m,"normal")).call(M).call(R=>R.select(".domain").remove()),r!==void 0&&x.append("text").attr("x",c).attr("y",a-6).attr("fill","currentColor").attr("font-weight","bold").text(r),x.node()}var fm=Math.PI/180;function cm(t,{marker:e,markerStart:r=e,markerMid:n=e,markerEnd:o=e}={}){t.markerStart=um(r),t.markerMid=um(n),t.markerEnd=um(o)}function um(t){if(t==null||t===!1)return null;if(t===!0)return ox;if(typeof t=="function")return t;switch(`${t}`.toLowerCase()){case"none":return null;case"arrow":return nx("auto");case"arrow-reverse":return nx("auto-start-reverse");case"dot":return C5;case"circle":case"circle-fill":return ox;case"circle-stroke":return $5}throw new Error(`invalid marker: ${t}`)}function nx(t){return(e,r)=>yt("svg:marker",r).attr("viewBox","-5 -5 10 10").attr("markerWidth",6.67).attr("markerHeight",6.67).attr("orient",t).attr("fill","none").attr("stroke",e).attr("stroke-width",1.5).attr("stroke-linecap","round").attr("stroke-linejoin","round").call(n=>n.append("path").attr("d","M-1.5,-3l3,3l-3,3")).node()}function C5(t,e){return yt("svg:marker",e).attr("viewBox","-5 -5 10 10").attr("markerWidth",6.67).attr("markerHeight",6.67).attr("fill",t).attr("stroke","none").call(r=>r.append("circle").attr("r",2.5)).node()}function ox(t,e){return yt("svg:marker",e).attr("viewBox","-5 -5 10 10").attr("markerWidth",6.67).attr("markerHeight",6.67).attr("fill",t).attr("stroke","white").attr("stroke-width",1.5).call(r=>r.append("circle").attr("r",3)).node()}function $5(t,e){return yt("svg:marker",e).attr("viewBox","-5 -5 10 10").attr("markerWidth",6.67).attr("markerHeight",6.67).attr("fill","white").attr("stroke",t).attr("stroke-width",1.5).call(r=>r.append("circle").attr("r",3)).node()}var A5=0;function sm(t,e,{stroke:r},n){return E5(t,e,r&&(o=>r[o]),n)}function E5(t,{markerStart:e,markerMid:r,markerEnd:n,stroke:o},i=()=>o,a){let f=new Map;function u(c){return function(s){let p=i(s),l=f.get(c);l||f.set(c,l=new Map);let m=l.get(p);if(!m){let d=this.parentNode.insertBefore(c(p,a),this),h=`plot-marker-${++A5}`;d.setAttribute("id",h),l.set(p,m=`url(#${h})`)}return m}}e&&t.attr("marker-start",u(e)),r&&t.attr("marker-mid",u(r)),n&&t.attr("marker-end",u(n))}function Ra({inset:t,insetLeft:e,insetRight:r,...n}={}){return[e,r]=ix(t,e,r),{inset:t,insetLeft:e,insetRight:r,...n}}function rs({inset:t,insetTop:e,insetBottom:r,...n}={}){return[e,r]=ix(t,e,r),{inset:t,insetTop:e,insetBottom:r,...n}}function ix(t,e,r){return t===void 0&&e===void 0&&r===void 0?zt?[1,0]:[.5,.5]:[e,r]}function ax(t,{interval:e}){return t={...ur(t)},t.interval=Bc(t.interval===void 0?e:t.interval),t}function fx(t,e,r,n){let{[t]:o,[`${t}1`]:i,[`${t}2`]:a}=r,{value:f,interval:u}=ax(o,r);if(f==null||u==null&&!n)return r;let c=qe(o);if(u==null){let m,d={transform:h=>m||(m=gt(h,f)),label:c};return{...r,[t]:void 0,[`${t}1`]:i===void 0?d:i,[`${t}2`]:a===void 0?d:a}}let s,p;function l(m){return p!==void 0&&m===s?p:p=mt(gt(s=m,f),d=>u.floor(d))}return e({...r,[t]:void 0,[`${t}1`]:i===void 0?{transform:l,label:c}:i,[`${t}2`]:a===void 0?{transform:m=>l(m).map(d=>u.offset(d)),label:c}:a})}function ux(t,e,r){let{[t]:n}=r,{value:o,interval:i}=ax(n,r);return o==null||i==null?r:e({...r,[t]:{label:qe(n),transform:a=>{let f=mt(gt(a,o),c=>i.floor(c)),u=f.map(c=>i.offset(c));return f.map(Bt(f)?(c,s)=>c==null||isNaN(c=+c)||(s=u[s],s==null)||isNaN(s=+s)?void 0:new Date((c+s)/2):(c,s)=>c==null||(s=u[s],s==null)?NaN:(+c+ +s)/2)}}})}function lm(t={}){return fx("x",Ra,t)}function ns(t={}){return fx("y",rs,t)}function cx(t={}){return ux("x",Ra,t)}function sx(t={}){return ux("y",rs,t)}var lx={ariaLabel:"rule",fill:null,stroke:"currentColor"},pm=class extends Nt{constructor(e,r={}){let{x:n,y1:o,y2:i,inset:a=0,insetTop:f=a,insetBottom:u=a}=r;super(e,{x:{value:n,scale:"x",optional:!0},y1:{value:o,scale:"y",optional:!0},y2:{value:i,scale:"y",optional:!0}},Da(r,"x"),lx),this.insetTop=ct(f),this.insetBottom=ct(u),cm(this,r)}render(e,r,n,o,i){let{x:a,y:f}=r,{x:u,y1:c,y2:s}=n,{width:p,height:l,marginTop:m,marginRight:d,marginLeft:h,marginBottom:g}=o,{insetTop:b,insetBottom:x}=this;return yt("svg:g",i).call(ge,this,o,i).call(ye,this,{x:u&&a},zt,0).call(M=>M.selectAll().data(e).enter().append("line").call(xe,this).attr("x1",u?y=>u[y]:(h+p-d)/2).attr("x2",u?y=>u[y]:(h+p-d)/2).attr("y1",c&&!Xr(f)?y=>c[y]+b:m+b).attr("y2",s&&!Xr(f)?f.bandwidth?y=>s[y]+f.bandwidth()-x:y=>s[y]-x:l-g-x).call(Ye,this,n).call(sm,this,n,i)).node()}},mm=class extends Nt{constructor(e,r={}){let{x1:n,x2:o,y:i,inset:a=0,insetRight:f=a,insetLeft:u=a}=r;super(e,{y:{value:i,scale:"y",optional:!0},x1:{value:n,scale:"x",optional:!0},x2:{value:o,scale:"x",optional:!0}},Da(r,"y"),lx),this.insetRight=ct(f),this.insetLeft=ct(u),cm(this,r)}render(e,r,n,o,i){let{x:a,y:f}=r,{y:u,x1:c,x2:s}=n,{width:p,height:l,marginTop:m,marginRight:d,marginLeft:h,marginBottom:g}=o,{insetLeft:b,insetRight:x}=this;return yt("svg:g",i).call(ge,this,o,i).call(ye,this,{y:u&&f},0,zt).call(M=>M.selectAll().data(e).enter().append("line").call(xe,this).attr("x1",c&&!Xr(a)?y=>c[y]+b:h+b).attr("x2",s&&!Xr(a)?a.bandwidth?y=>s[y]+a.bandwidth()-x:y=>s[y]-x:p-d-x).attr("y1",u?y=>u[y]:(m+l-g)/2).attr("y2",u?y=>u[y]:(m+l-g)/2).call(Ye,this,n).call(sm,this,n,i)).node()}};function px(t,e){let{x:r=vt,y:n,y1:o,y2:i,...a}=ns(e);return[o,i]=dx(n,o,i),new pm(t,{...a,x:r,y1:o,y2:i})}function mx(t,e){let{y:r=vt,x:n,x1:o,x2:i,...a}=lm(e);return[o,i]=dx(n,o,i),new mm(t,{...a,y:r,x1:o,x2:i})}function dx(t,e,r){if(t==null){if(e===void 0){if(r!==void 0)return[0,r]}else if(r===void 0)return[0,e]}else{if(e===void 0)return r===void 0?[0,t]:[t,r];if(r===void 0)return[t,e]}return[e,r]}function os(t,...e){let r=e.length;for(let n=0,o=!0;n<r;++n)typeof e[n]!="function"&&(o&&(t=t.slice(),o=!1),t.splice(n,2,t[n]+e[n]+t[n+1]),e.splice(n,1),--n,--r);return n=>{let o=t[0];for(let i=0;i<r;++i)o+=e[i](n)+t[i+1];return o}}var k5={ariaLabel:"text",strokeLinejoin:"round",strokeWidth:3,paintOrder:"stroke"},bx="\xAD",Pa=class extends Nt{constructor(e,r={}){let{x:n,y:o,text:i=ie(e)&&zc(e)?vt:Oc,frameAnchor:a,textAnchor:f=/right$/i.test(a)?"end":/left$/i.test(a)?"start":"middle",lineAnchor:u=/^top/i.test(a)?"top":/^bottom/i.test(a)?"bottom":"middle",lineHeight:c=1,lineWidth:s=1/0,textOverflow:p,monospace:l,fontFamily:m=l?"ui-monospace, monospace":void 0,fontSize:d,fontStyle:h,fontVariant:g,fontWeight:b,rotate:x}=r,[M,y]=kt(x,0),[w,v]=R5(d);if(super(e,{x:{value:n,scale:"x",optional:!0},y:{value:o,scale:"y",optional:!0},fontSize:{value:w,optional:!0},rotate:{value:Mg(M),optional:!0},text:{value:i,filter:Rc,optional:!0}},r,k5),this.rotate=y,this.textAnchor=St(f,"middle"),this.lineAnchor=me(u,"lineAnchor",["top","middle","bottom"]),this.lineHeight=+c,this.lineWidth=+s,this.textOverflow=hm(p),this.monospace=!!l,this.fontFamily=Et(m),this.fontSize=v,this.fontStyle=Et(h),this.fontVariant=Et(g),this.fontWeight=Et(b),this.frameAnchor=_o(a),!(this.lineWidth>=0))throw new Error(`invalid lineWidth: ${s}`);this.splitLines=xm(this),this.clipLine=ym(this)}render(e,r,n,o,i){let{x:a,y:f}=r,{x:u,y:c,rotate:s,text:p,title:l,fontSize:m}=n,{rotate:d}=this,[h,g]=Vr(this,o);return yt("svg:g",i).call(ge,this,o,i).call(gm,this,p,o).call(ye,this,{x:u&&a,y:c&&f}).call(b=>b.selectAll().data(e).enter().append("text").call(xe,this).call(I5,this,p,l).attr("transform",os`translate(${u?x=>u[x]:h},${c?x=>c[x]:g})${s?x=>` rotate(${s[x]})`:d?` rotate(${d})`:""}`).call(et,"font-size",m&&(x=>m[x])).call(Ye,this,n)).node()}};function hm(t){return t==null?null:me(t,"textOverflow",["clip","ellipsis","clip-start","clip-end","ellipsis-start","ellipsis-middle","ellipsis-end"]).replace(/^(clip|ellipsis)$/,"$1-end")}function I5(t,e,r,n){if(!r)return;let{lineAnchor:o,lineHeight:i,textOverflow:a,splitLines:f,clipLine:u}=e;t.each(function(c){let s=f($e(r[c])??"").map(u),p=s.length,l=o==="top"?.71:o==="bottom"?1-p:(164-p*100)/200;if(p>1){let m=0;for(let d=0;d<p;++d){if(++m,!s[d])continue;let h=this.ownerDocument.createElementNS(We.svg,"tspan");h.setAttribute("x",0),d===m-1?h.setAttribute("y",`${(l+d)*i}em`):h.setAttribute("dy",`${m*i}em`),h.textContent=s[d],this.appendChild(h),m=0}}else l&&this.setAttribute("y",`${l*i}em`),this.textContent=s[0];if(a&&!n&&s[0]!==r[c]){let m=this.ownerDocument.createElementNS(We.svg,"title");m.textContent=r[c],this.appendChild(m)}})}function Bo(t,{x:e,y:r,...n}={}){return n.frameAnchor===void 0&&([e,r]=In(e,r)),new Pa(t,{...n,x:e,y:r})}function wx(t,{x:e=vt,...r}={}){return new Pa(t,sx({...r,x:e}))}function vx(t,{y:e=vt,...r}={}){return new Pa(t,cx({...r,y:e}))}function gm(t,e,r){et(t,"text-anchor",e.textAnchor),et(t,"font-family",e.fontFamily),et(t,"font-size",e.fontSize),et(t,"font-style",e.fontStyle),et(t,"font-variant",e.fontVariant===void 0?N5(r):e.fontVariant),et(t,"font-weight",e.fontWeight)}function N5(t){return t&&(_g(t)||Bt(t))?"tabular-nums":void 0}var D5=new Set(["inherit","initial","revert","unset","xx-small","x-small","small","medium","large","x-large","xx-large","xxx-large","larger","smaller"]);function R5(t){return t==null||typeof t=="number"?[void 0,t]:typeof t!="string"?[t,void 0]:(t=t.trim().toLowerCase(),D5.has(t)||/^[+-]?\d*\.?\d+(e[+-]?\d+)?(\w*|%)$/.test(t)?[void 0,t]:[t,void 0])}function P5(t,e,r){let n=[],o,i=0;for(let[a,f,u]of L5(t)){if(o===void 0&&(o=a),i>o&&r(t,o,f)>e&&(n.push(t.slice(o,i)+(t[i-1]===bx?"-":"")),o=a),u){n.push(t.slice(o,f)),o=void 0;continue}i=f}return n}function*L5(t){let e=0,r=0,n=t.length;for(;r<n;){let o=1;switch(t[r]){case bx:case"-":++r,yield[e,r,!1],e=r;break;case" ":for(yield[e,r,!1];t[++r]===" ";);e=r;break;case"\r":t[r+1]===`
 `&&++o;case`
-`:yield[e,n,!0],n+=o,e=n;break;default:++n;break}}yield[e,n,!0]}var kx={a:56,b:63,c:57,d:63,e:58,f:37,g:62,h:60,i:26,j:26,k:55,l:26,m:88,n:60,o:60,p:62,q:62,r:39,s:54,t:38,u:60,v:55,w:79,x:54,y:55,z:55,A:69,B:67,C:73,D:74,E:61,F:58,G:76,H:75,I:28,J:55,K:67,L:58,M:89,N:75,O:78,P:65,Q:78,R:67,S:65,T:65,U:75,V:69,W:98,X:69,Y:67,Z:67,0:64,1:48,2:62,3:64,4:66,5:63,6:65,7:58,8:65,9:65," ":29,"!":32,'"':49,"'":31,"(":39,")":39,",":31,"-":48,".":31,"/":32,":":31,";":31,"?":52,"\u2018":31,"\u2019":31,"\u201C":47,"\u201D":47,"\u2026":82};function jc(t,e=0,n=t.length){let r=0;for(let o=e;o<n;o=zo(t,o))r+=kx[t[o]]??(Ox(t,o)?120:kx.e);return r}function Zc(t,e=0,n=t.length){let r=0;for(let o=e;o<n;o=zo(t,o))r+=Ox(t,o)?200:100;return r}function yp({monospace:t,lineWidth:e,textOverflow:n}){if(n!=null||e==1/0)return i=>i.split(/\r\n?|\n/g);let r=t?Zc:jc,o=e*100;return i=>r5(i,o,r)}function bp({monospace:t,lineWidth:e,textOverflow:n}){if(n==null||e==1/0)return i=>i;let r=t?Zc:jc,o=e*100;switch(n){case"clip-start":return i=>Dx(i,o,r,"");case"clip-end":return i=>Nx(i,o,r,"");case"ellipsis-start":return i=>Dx(i,o,r,Ir);case"ellipsis-middle":return i=>i5(i,o,r,Ir);case"ellipsis-end":return i=>Nx(i,o,r,Ir)}}var Ir="\u2026";function kr(t,e,n,r){let o=[],i=0;for(let a=0,f=0,u=t.length;a<u;a=f){f=zo(t,a);let c=n(t,a,f);if(i+c>e){for(i+=r;i>e&&a>0;)f=a,a=o.pop(),i-=n(t,a,f);return[a,e-i]}i+=c,o.push(a)}return[-1,0]}function Nx(t,e,n,r){t=t.trim();let o=n(r),[i]=kr(t,e,n,o);return i<0?t:t.slice(0,i).trimEnd()+r}function i5(t,e,n,r){t=t.trim();let o=n(t);if(o<=e)return t;let i=n(r)/2,[a,f]=kr(t,e/2,n,i),[u]=kr(t,o-e/2-f+i,n,-i);return u<0?r:t.slice(0,a).trimEnd()+r+t.slice(zo(t,u)).trimStart()}function Dx(t,e,n,r){t=t.trim();let o=n(t);if(o<=e)return t;let i=n(r),[a]=kr(t,o-e+i,n,-i);return a<0?r:r+t.slice(zo(t,a)).trimStart()}var pp=/[\p{Combining_Mark}\p{Emoji_Modifier}]+/uy,Rx=/\p{Extended_Pictographic}/uy;function zo(t,e){return e+=a5(t,e)?2:1,u5(t,e)&&(e=pp.lastIndex),f5(t,e)?zo(t,e+1):e}function Px(t,e){return t.charCodeAt(e)<128}function a5(t,e){let n=t.charCodeAt(e);if(n>=55296&&n<56320){let r=t.charCodeAt(e+1);return r>=56320&&r<57344}return!1}function f5(t,e){return t.charCodeAt(e)===8205}function u5(t,e){return Px(t,e)?!1:(pp.lastIndex=e,pp.test(t))}function Ox(t,e){return Px(t,e)?!1:(Rx.lastIndex=e,Rx.test(t))}var c5={ariaLabel:"vector",fill:"none",stroke:"currentColor",strokeWidth:1.5,strokeLinejoin:"round",strokeLinecap:"round"},Fx=3.5,s5=Fx*5,qx={draw(t,e,n){let r=e*n/s5;t.moveTo(0,0),t.lineTo(0,-e),t.moveTo(-r,r-e),t.lineTo(0,-e),t.lineTo(r,r-e)}},l5={draw(t,e,n){t.moveTo(-n,0),t.lineTo(0,-e),t.lineTo(n,0)}},d5=new Map([["arrow",qx],["spike",l5]]);function p5(t){return t&&typeof t.draw=="function"}function m5(t){if(p5(t))return t;let e=d5.get(`${t}`.toLowerCase());if(e)return e;throw new Error(`invalid shape: ${t}`)}var Kc=class extends Nt{constructor(e,n={}){let{x:r,y:o,r:i=Fx,length:a,rotate:f,shape:u=qx,anchor:c="middle",frameAnchor:s}=n,[d,l]=It(a,12),[p,m]=It(f,0);super(e,{x:{value:r,scale:"x",optional:!0},y:{value:o,scale:"y",optional:!0},length:{value:d,scale:"length",optional:!0},rotate:{value:p,optional:!0}},n,c5),this.r=+i,this.length=l,this.rotate=m,this.shape=m5(u),this.anchor=le(c,"anchor",["start","middle","end"]),this.frameAnchor=Mo(s)}render(e,n,r,o,i){let{x:a,y:f}=n,{x:u,y:c,length:s,rotate:d}=r,{length:l,rotate:p,anchor:m,shape:h,r:g}=this,[b,x]=Gn(this,o);return yt("svg:g",i).call(me,this,o,i).call(ge,this,{x:u&&a,y:c&&f}).call(M=>M.selectAll().data(e).enter().append("path").call(he,this).attr("transform",Vc`translate(${u?y=>u[y]:b},${c?y=>c[y]:x})${d?y=>` rotate(${d[y]})`:p?` rotate(${p})`:""}${m==="start"?"":m==="end"?s?y=>` translate(0,${s[y]})`:` translate(0,${l})`:s?y=>` translate(0,${s[y]/2})`:` translate(0,${l/2})`}`).attr("d",s?y=>{let w=Vr();return h.draw(w,s[y],g),w}:(()=>{let y=Vr();return h.draw(y,l,g),y})()).call(Oe,this,r)).node()}};function zx(t,e={}){let{x:n=wt,...r}=e;return new Kc(t,{...r,x:n})}function Yx(t,e={}){let{y:n=wt,...r}=e;return new Kc(t,{...r,y:n})}function jn(t,e){return arguments.length<2&&!re(t)&&(e=t,t=null),e===void 0&&(e={}),[t,e]}function Qc({anchor:t}={},e){return t===void 0?e[0]:le(t,"anchor",e)}function Bx(t){return Qc(t,["left","right"])}function Ux(t){return Qc(t,["right","left"])}function Xx(t){return Qc(t,["bottom","top"])}function Hx(t){return Qc(t,["top","bottom"])}function Jc(){let[t,e]=jn(...arguments);return Wx("y",Bx(e),t,e)}function wp(){let[t,e]=jn(...arguments);return Wx("fy",Ux(e),t,e)}function Nr(){let[t,e]=jn(...arguments);return Gx("x",Xx(e),t,e)}function vp(){let[t,e]=jn(...arguments);return Gx("fx",Hx(e),t,e)}function Wx(t,e,n,{color:r="currentColor",opacity:o=1,stroke:i=r,strokeOpacity:a=o,strokeWidth:f=1,fill:u=r,fillOpacity:c=o,textAnchor:s,textStroke:d,textStrokeOpacity:l,textStrokeWidth:p,tickSize:m=t==="y"?6:0,tickPadding:h,tickRotate:g,x:b,margin:x,marginTop:M=x===void 0?20:x,marginRight:y=x===void 0?e==="right"?40:0:x,marginBottom:w=x===void 0?20:x,marginLeft:v=x===void 0?e==="left"?40:0:x,label:T,labelAnchor:E,labelArrow:I,labelOffset:F,..._}){return m=ct(m),h=ct(h),g=ct(g),E!==void 0&&(E=le(E,"labelAnchor",["center","top","bottom"])),I=ey(I),ep(m&&!Pt(i)?h5(t,e,n,{stroke:i,strokeOpacity:a,strokeWidth:f,tickSize:m,tickPadding:h,tickRotate:g,x:b,..._}):null,Pt(u)?null:x5(t,e,n,{fill:u,fillOpacity:c,stroke:d,strokeOpacity:l,strokeWidth:p,textAnchor:s,tickSize:m,tickPadding:h,tickRotate:g,x:b,marginTop:M,marginRight:y,marginBottom:w,marginLeft:v,..._}),!Pt(u)&&T!==null?qo([],Kx({fill:u,fillOpacity:c,..._},function(R,O,L,S,A){let N=S[t],{marginTop:P,marginRight:D,marginBottom:tt,marginLeft:Z}=t==="y"&&A.inset||A,W=E??(N.bandwidth?"center":"top"),Q=F??(e==="right"?D:Z)-3;return W==="center"?(this.textAnchor=void 0,this.lineAnchor=e==="right"?"bottom":"top",this.frameAnchor=e,this.rotate=-90):(this.textAnchor=e==="right"?"end":"start",this.lineAnchor=W,this.frameAnchor=`${W}-${e}`,this.rotate=0),this.dy=W==="top"?3-P:W==="bottom"?tt-3:0,this.dx=e==="right"?Q:-Q,this.ariaLabel=`${t}-axis label`,{facets:[[0]],channels:{text:{value:[ty(t,N,{anchor:e,label:T,labelAnchor:W,labelArrow:I})]}}}})):null)}function Gx(t,e,n,{color:r="currentColor",opacity:o=1,stroke:i=r,strokeOpacity:a=o,strokeWidth:f=1,fill:u=r,fillOpacity:c=o,textAnchor:s,textStroke:d,textStrokeOpacity:l,textStrokeWidth:p,tickSize:m=t==="x"?6:0,tickPadding:h,tickRotate:g,y:b,margin:x,marginTop:M=x===void 0?e==="top"?30:0:x,marginRight:y=x===void 0?20:x,marginBottom:w=x===void 0?e==="bottom"?30:0:x,marginLeft:v=x===void 0?20:x,label:T,labelAnchor:E,labelArrow:I,labelOffset:F,..._}){return m=ct(m),h=ct(h),g=ct(g),E!==void 0&&(E=le(E,"labelAnchor",["center","left","right"])),I=ey(I),ep(m&&!Pt(i)?g5(t,e,n,{stroke:i,strokeOpacity:a,strokeWidth:f,tickSize:m,tickPadding:h,tickRotate:g,y:b,..._}):null,Pt(u)?null:y5(t,e,n,{fill:u,fillOpacity:c,stroke:d,strokeOpacity:l,strokeWidth:p,textAnchor:s,tickSize:m,tickPadding:h,tickRotate:g,y:b,marginTop:M,marginRight:y,marginBottom:w,marginLeft:v,..._}),!Pt(u)&&T!==null?qo([],Kx({fill:u,fillOpacity:c,..._},function(R,O,L,S,A){let N=S[t],{marginTop:P,marginRight:D,marginBottom:tt,marginLeft:Z}=t==="x"&&A.inset||A,W=E??(N.bandwidth?"center":"right"),Q=F??(e==="top"?P:tt)-3;return W==="center"?(this.frameAnchor=e,this.textAnchor=void 0):(this.frameAnchor=`${e}-${W}`,this.textAnchor=W==="right"?"end":"start"),this.lineAnchor=e,this.dy=e==="top"?-Q:Q,this.dx=W==="right"?D-3:W==="left"?3-Z:0,this.ariaLabel=`${t}-axis label`,{facets:[[0]],channels:{text:{value:[ty(t,N,{anchor:e,label:T,labelAnchor:W,labelArrow:I})]}}}})):null)}function h5(t,e,n,{strokeWidth:r=1,strokeLinecap:o=null,strokeLinejoin:i=null,facetAnchor:a=e+(t==="y"?"-empty":""),frameAnchor:f=e,tickSize:u,inset:c=0,insetLeft:s=c,insetRight:d=c,dx:l=0,y:p=t==="y"?void 0:null,...m}){return Yo(Yx,t,`${t}-axis tick`,n,{strokeWidth:r,strokeLinecap:o,strokeLinejoin:i,facetAnchor:a,frameAnchor:f,y:p,...m,dx:e==="left"?+l-Bt+ +s:+l+Bt-d,anchor:"start",length:u,shape:e==="left"?v5:M5})}function g5(t,e,n,{strokeWidth:r=1,strokeLinecap:o=null,strokeLinejoin:i=null,facetAnchor:a=e+(t==="x"?"-empty":""),frameAnchor:f=e,tickSize:u,inset:c=0,insetTop:s=c,insetBottom:d=c,dy:l=0,x:p=t==="x"?void 0:null,...m}){return Yo(zx,t,`${t}-axis tick`,n,{strokeWidth:r,strokeLinejoin:i,strokeLinecap:o,facetAnchor:a,frameAnchor:f,x:p,...m,dy:e==="bottom"?+l-Bt-d:+l+Bt+ +s,anchor:"start",length:u,shape:e==="bottom"?b5:w5})}function x5(t,e,n,{facetAnchor:r=e+(t==="y"?"-empty":""),frameAnchor:o=e,tickSize:i,tickRotate:a=0,tickPadding:f=Math.max(3,9-i)+(Math.abs(a)>60?4*Math.cos(a*ap):0),tickFormat:u,text:c=typeof u=="function"?u:void 0,textAnchor:s=Math.abs(a)>60?"middle":e==="left"?"end":"start",lineAnchor:d=a>60?"top":a<-60?"bottom":"middle",fontVariant:l,inset:p=0,insetLeft:m=p,insetRight:h=p,dx:g=0,y:b=t==="y"?void 0:null,...x}){return Yo(gp,t,`${t}-axis tick label`,n,{facetAnchor:r,frameAnchor:o,text:c===void 0?null:c,textAnchor:s,lineAnchor:d,fontVariant:l,rotate:a,y:b,...x,dx:e==="left"?+g-i-f+ +m:+g+ +i+ +f-h},function(M,y,w,v){l===void 0&&(this.fontVariant=Jx(M)),c===void 0&&(v.text=Qx(M,y,w,u,e))})}function y5(t,e,n,{facetAnchor:r=e+(t==="x"?"-empty":""),frameAnchor:o=e,tickSize:i,tickRotate:a=0,tickPadding:f=Math.max(3,9-i)+(Math.abs(a)>=10?4*Math.cos(a*ap):0),tickFormat:u,text:c=typeof u=="function"?u:void 0,textAnchor:s=Math.abs(a)>=10?a<0^e==="bottom"?"start":"end":"middle",lineAnchor:d=Math.abs(a)>=10?"middle":e==="bottom"?"top":"bottom",fontVariant:l,inset:p=0,insetTop:m=p,insetBottom:h=p,dy:g=0,x:b=t==="x"?void 0:null,...x}){return Yo(hp,t,`${t}-axis tick label`,n,{facetAnchor:r,frameAnchor:o,text:c===void 0?null:c,textAnchor:s,lineAnchor:d,fontVariant:l,rotate:a,x:b,...x,dy:e==="bottom"?+g+ +i+ +f-h:+g-i-f+ +m},function(M,y,w,v){l===void 0&&(this.fontVariant=Jx(M)),c===void 0&&(v.text=Qx(M,y,w,u,e))})}function Mp(){let[t,e]=jn(...arguments);return Vx("y",Bx(e),t,e)}function Sp(){let[t,e]=jn(...arguments);return Vx("fy",Ux(e),t,e)}function Tp(){let[t,e]=jn(...arguments);return jx("x",Xx(e),t,e)}function _p(){let[t,e]=jn(...arguments);return jx("fx",Hx(e),t,e)}function Vx(t,e,n,{y:r=t==="y"?void 0:null,x:o=null,x1:i=e==="left"?o:null,x2:a=e==="right"?o:null,...f}){return Yo(Ex,t,`${t}-grid`,n,{y:r,x1:i,x2:a,...Zx(f)})}function jx(t,e,n,{x:r=t==="x"?void 0:null,y:o=null,y1:i=e==="top"?o:null,y2:a=e==="bottom"?o:null,...f}){return Yo($x,t,`${t}-grid`,n,{x:r,y1:i,y2:a,...Zx(f)})}function Zx({color:t="currentColor",opacity:e=.1,stroke:n=t,strokeOpacity:r=e,strokeWidth:o=1,...i}){return{stroke:n,strokeOpacity:r,strokeWidth:o,...i}}function Kx({fill:t,fillOpacity:e,fontFamily:n,fontSize:r,fontStyle:o,fontWeight:i,monospace:a,pointerEvents:f,shapeRendering:u},c){return[,t]=ie(t),[,e]=It(e),{facet:"super",x:null,y:null,fill:t,fillOpacity:e,fontFamily:n,fontSize:r,fontStyle:o,fontWeight:i,monospace:a,pointerEvents:f,shapeRendering:u,initializer:c}}function Yo(t,e,n,r,o,i){let a;function f(s,d,l,p,m,h){let g=s==null&&(e==="fx"||e==="fy"),{[e]:b}=p;if(!b)throw new Error(`missing scale: ${e}`);let{ticks:x,tickSpacing:M,interval:y}=o;if(qc(b)&&typeof x=="string"&&(y=x,x=void 0),s==null){if(re(x))s=kt(x);else if(b.ticks)if(x!==void 0)s=b.ticks(x);else if(y=rn(y===void 0?b.interval:y,b.type),y!==void 0){let[v,T]=Ct(b.domain());s=y.range(v,y.offset(y.floor(T)))}else{let[v,T]=Ct(b.range());x=(T-v)/(M===void 0?e==="x"?80:35:M),s=b.ticks(x)}else s=b.domain();e==="y"||e==="x"?d=[ne(s)]:a[e]={scale:e,value:wt}}i?.call(this,b,s,x,a);let w=Object.fromEntries(Object.entries(a).map(([v,T])=>[v,{...T,value:gt(s,T.value)}]));return g&&(d=h.filterFacets(s,w)),{data:s,facets:d,channels:w}}let u=Er(o).initializer,c=t(r,Er({...o,initializer:f},u));return r==null?(a=c.channels,c.channels={}):a={},c.ariaLabel=n,c}function Qx(t,e,n,r,o){return{value:Bo(t,e,n,r,o)}}function Bo(t,e,n,r,o){return r===void 0&&qc(t)?Tg(t,e,n,o):t.tickFormat?t.tickFormat(re(n)?null:n,r):r===void 0?Mg(t.interval)?bn("%Y"):Sg(t.interval)?Un("%Y"):_e:typeof r=="string"?(Yt(t.domain())?bn:Ve)(r):Hn(r)}var b5={draw(t,e){t.moveTo(0,0),t.lineTo(0,e)}},w5={draw(t,e){t.moveTo(0,0),t.lineTo(0,-e)}},v5={draw(t,e){t.moveTo(0,0),t.lineTo(-e,0)}},M5={draw(t,e){t.moveTo(0,0),t.lineTo(e,0)}};function Jx(t){return t.bandwidth&&!t.interval?void 0:"tabular-nums"}function S5(t){return Math.sign(vn(t.domain()))*Math.sign(vn(t.range()))}function ty(t,e,{anchor:n,label:r=e.label,labelAnchor:o,labelArrow:i}={}){if(!(r==null||r.inferred&&T5(e)&&/^(date|time|year)$/i.test(r))){if(r=String(r),i==="auto"&&(i=(!e.bandwidth||e.interval)&&!/[↑↓→←]/.test(r)),!i)return r;if(i===!0){let a=S5(e);a&&(i=/x$/.test(t)||o==="center"?/x$/.test(t)===a<0?"left":"right":a<0?"up":"down")}switch(i){case"left":return`\u2190 ${r}`;case"right":return`${r} \u2192`;case"up":return n==="right"?`${r} \u2191`:`\u2191 ${r}`;case"down":return n==="right"?`${r} \u2193`:`\u2193 ${r}`}return r}}function ey(t="auto"){return Pt(t)?!1:typeof t=="boolean"?t:le(t,"labelArrow",["auto","up","right","down","left"])}function T5(t){return qc(t)||t.interval!=null}function ny(t,e){if(e==null)return e;let n=t(e);if(!n)throw new Error(`scale not found: ${e}`);return n}function ry(t,{opacity:e,...n}={}){if(!fn(t)&&!jd(t))throw new Error(`swatches legend requires ordinal or threshold color scale (not ${t.type})`);return iy(t,n,(r,o,i,a)=>r.append("svg").attr("width",i).attr("height",a).attr("fill",o.scale).attr("fill-opacity",It(e)[1]).append("rect").attr("width","100%").attr("height","100%"))}function oy(t,{fill:e=t.hint?.fill!==void 0?t.hint.fill:"none",fillOpacity:n=1,stroke:r=t.hint?.stroke!==void 0?t.hint.stroke:Pt(e)?"currentColor":"none",strokeOpacity:o=1,strokeWidth:i=1.5,r:a=4.5,...f}={},u){let[c,s]=ie(e),[d,l]=ie(r),p=ny(u,c),m=ny(u,d),h=a*a*Math.PI;return n=It(n)[1],o=It(o)[1],i=It(i)[1],iy(t,f,(g,b,x,M)=>g.append("svg").attr("viewBox","-8 -8 16 16").attr("width",x).attr("height",M).attr("fill",c==="color"?y=>p.scale(y):s).attr("fill-opacity",n).attr("stroke",d==="color"?y=>m.scale(y):l).attr("stroke-opacity",o).attr("stroke-width",i).append("path").attr("d",y=>{let w=Vr();return t.scale(y).draw(w,h),w}))}function iy(t,e={},n){let{columns:r,tickFormat:o,fontVariant:i=Hc(t),swatchSize:a=15,swatchWidth:f=a,swatchHeight:u=a,marginLeft:c=0,className:s,style:d,width:l}=e,p=Io(e);s=Po(s),typeof o!="function"&&(o=Bo(t.scale,t.domain,void 0,o));let m=yt("div",p).attr("class",`${s}-swatches ${s}-swatches-${r!=null?"columns":"wrap"}`),h;return r!=null?(h=`.${s}-swatches-columns .${s}-swatch {
+`:yield[e,r,!0],r+=o,e=r;break;default:++r;break}}yield[e,r,!0]}var hx={a:56,b:63,c:57,d:63,e:58,f:37,g:62,h:60,i:26,j:26,k:55,l:26,m:88,n:60,o:60,p:62,q:62,r:39,s:54,t:38,u:60,v:55,w:79,x:54,y:55,z:55,A:69,B:67,C:73,D:74,E:61,F:58,G:76,H:75,I:28,J:55,K:67,L:58,M:89,N:75,O:78,P:65,Q:78,R:67,S:65,T:65,U:75,V:69,W:98,X:69,Y:67,Z:67,0:64,1:48,2:62,3:64,4:66,5:63,6:65,7:58,8:65,9:65," ":29,"!":32,'"':49,"'":31,"(":39,")":39,",":31,"-":48,".":31,"/":32,":":31,";":31,"?":52,"\u2018":31,"\u2019":31,"\u201C":47,"\u201D":47,"\u2026":82};function is(t,e=0,r=t.length){let n=0;for(let o=e;o<r;o=zo(t,o))n+=hx[t[o]]??(Sx(t,o)?120:hx.e);return n}function as(t,e=0,r=t.length){let n=0;for(let o=e;o<r;o=zo(t,o))n+=Sx(t,o)?200:100;return n}function xm({monospace:t,lineWidth:e,textOverflow:r}){if(r!=null||e==1/0)return i=>i.split(/\r\n?|\n/g);let n=t?as:is,o=e*100;return i=>P5(i,o,n)}function ym({monospace:t,lineWidth:e,textOverflow:r}){if(r==null||e==1/0)return i=>i;let n=t?as:is,o=e*100;switch(r){case"clip-start":return i=>xx(i,o,n,"");case"clip-end":return i=>gx(i,o,n,"");case"ellipsis-start":return i=>xx(i,o,n,Rn);case"ellipsis-middle":return i=>F5(i,o,n,Rn);case"ellipsis-end":return i=>gx(i,o,n,Rn)}}var Rn="\u2026";function Pn(t,e,r,n){let o=[],i=0;for(let a=0,f=0,u=t.length;a<u;a=f){f=zo(t,a);let c=r(t,a,f);if(i+c>e){for(i+=n;i>e&&a>0;)f=a,a=o.pop(),i-=r(t,a,f);return[a,e-i]}i+=c,o.push(a)}return[-1,0]}function gx(t,e,r,n){t=t.trim();let o=r(n),[i]=Pn(t,e,r,o);return i<0?t:t.slice(0,i).trimEnd()+n}function F5(t,e,r,n){t=t.trim();let o=r(t);if(o<=e)return t;let i=r(n)/2,[a,f]=Pn(t,e/2,r,i),[u]=Pn(t,o-e/2-f+i,r,-i);return u<0?n:t.slice(0,a).trimEnd()+n+t.slice(zo(t,u)).trimStart()}function xx(t,e,r,n){t=t.trim();let o=r(t);if(o<=e)return t;let i=r(n),[a]=Pn(t,o-e+i,r,-i);return a<0?n:n+t.slice(zo(t,a)).trimStart()}var dm=/[\p{Combining_Mark}\p{Emoji_Modifier}]+/uy,yx=/\p{Extended_Pictographic}/uy;function zo(t,e){return e+=q5(t,e)?2:1,Y5(t,e)&&(e=dm.lastIndex),O5(t,e)?zo(t,e+1):e}function Mx(t,e){return t.charCodeAt(e)<128}function q5(t,e){let r=t.charCodeAt(e);if(r>=55296&&r<56320){let n=t.charCodeAt(e+1);return n>=56320&&n<57344}return!1}function O5(t,e){return t.charCodeAt(e)===8205}function Y5(t,e){return Mx(t,e)?!1:(dm.lastIndex=e,dm.test(t))}function Sx(t,e){return Mx(t,e)?!1:(yx.lastIndex=e,yx.test(t))}var B5={ariaLabel:"vector",fill:"none",stroke:"currentColor",strokeWidth:1.5,strokeLinejoin:"round",strokeLinecap:"round"},Tx=3.5,z5=Tx*5,_x={draw(t,e,r){let n=e*r/z5;t.moveTo(0,0),t.lineTo(0,-e),t.moveTo(-n,n-e),t.lineTo(0,-e),t.lineTo(n,n-e)}},U5={draw(t,e,r){t.moveTo(-r,0),t.lineTo(0,-e),t.lineTo(r,0)}},W5=new Map([["arrow",_x],["spike",U5]]);function H5(t){return t&&typeof t.draw=="function"}function G5(t){if(H5(t))return t;let e=W5.get(`${t}`.toLowerCase());if(e)return e;throw new Error(`invalid shape: ${t}`)}var fs=class extends Nt{constructor(e,r={}){let{x:n,y:o,r:i=Tx,length:a,rotate:f,shape:u=_x,anchor:c="middle",frameAnchor:s}=r,[p,l]=kt(a,12),[m,d]=kt(f,0);super(e,{x:{value:n,scale:"x",optional:!0},y:{value:o,scale:"y",optional:!0},length:{value:p,scale:"length",optional:!0},rotate:{value:m,optional:!0}},r,B5),this.r=+i,this.length=l,this.rotate=d,this.shape=G5(u),this.anchor=me(c,"anchor",["start","middle","end"]),this.frameAnchor=_o(s)}render(e,r,n,o,i){let{x:a,y:f}=r,{x:u,y:c,length:s,rotate:p}=n,{length:l,rotate:m,anchor:d,shape:h,r:g}=this,[b,x]=Vr(this,o);return yt("svg:g",i).call(ge,this,o,i).call(ye,this,{x:u&&a,y:c&&f}).call(M=>M.selectAll().data(e).enter().append("path").call(xe,this).attr("transform",os`translate(${u?y=>u[y]:b},${c?y=>c[y]:x})${p?y=>` rotate(${p[y]})`:m?` rotate(${m})`:""}${d==="start"?"":d==="end"?s?y=>` translate(0,${s[y]})`:` translate(0,${l})`:s?y=>` translate(0,${s[y]/2})`:` translate(0,${l/2})`}`).attr("d",s?y=>{let w=Jn();return h.draw(w,s[y],g),w}:(()=>{let y=Jn();return h.draw(y,l,g),y})()).call(Ye,this,n)).node()}};function Cx(t,e={}){let{x:r=vt,...n}=e;return new fs(t,{...n,x:r})}function $x(t,e={}){let{y:r=vt,...n}=e;return new fs(t,{...n,y:r})}function Kr(t,e){return arguments.length<2&&!ie(t)&&(e=t,t=null),e===void 0&&(e={}),[t,e]}function us({anchor:t}={},e){return t===void 0?e[0]:me(t,"anchor",e)}function Ax(t){return us(t,["left","right"])}function Ex(t){return us(t,["right","left"])}function kx(t){return us(t,["bottom","top"])}function Ix(t){return us(t,["top","bottom"])}function bm(){let[t,e]=Kr(...arguments);return Rx("y",Ax(e),t,e)}function Nx(){let[t,e]=Kr(...arguments);return Rx("fy",Ex(e),t,e)}function Ln(){let[t,e]=Kr(...arguments);return Px("x",kx(e),t,e)}function Dx(){let[t,e]=Kr(...arguments);return Px("fx",Ix(e),t,e)}function Rx(t,e,r,{color:n="currentColor",opacity:o=1,stroke:i=n,strokeOpacity:a=o,strokeWidth:f=1,fill:u=n,fillOpacity:c=o,textAnchor:s,textStroke:p,textStrokeOpacity:l,textStrokeWidth:m,tickSize:d=t==="y"?6:0,tickPadding:h,tickRotate:g,x:b,margin:x,marginTop:M=x===void 0?20:x,marginRight:y=x===void 0?e==="right"?40:0:x,marginBottom:w=x===void 0?20:x,marginLeft:v=x===void 0?e==="left"?40:0:x,label:T,labelAnchor:E,labelArrow:k,labelOffset:q,..._}){return d=ct(d),h=ct(h),g=ct(g),E!==void 0&&(E=me(E,"labelAnchor",["center","top","bottom"])),k=Xx(k),rm(d&&!Ft(i)?X5(t,e,r,{stroke:i,strokeOpacity:a,strokeWidth:f,tickSize:d,tickPadding:h,tickRotate:g,x:b,..._}):null,Ft(u)?null:j5(t,e,r,{fill:u,fillOpacity:c,stroke:p,strokeOpacity:l,strokeWidth:m,textAnchor:s,tickSize:d,tickPadding:h,tickRotate:g,x:b,marginTop:M,marginRight:y,marginBottom:w,marginLeft:v,..._}),!Ft(u)&&T!==null?Bo([],Ux({fill:u,fillOpacity:c,..._},function(R,F,P,S,$){let N=S[t],{marginTop:L,marginRight:D,marginBottom:tt,marginLeft:K}=t==="y"&&$.inset||$,G=E??(N.bandwidth?"center":"top"),Q=q??(e==="right"?D:K)-3;return G==="center"?(this.textAnchor=void 0,this.lineAnchor=e==="right"?"bottom":"top",this.frameAnchor=e,this.rotate=-90):(this.textAnchor=e==="right"?"end":"start",this.lineAnchor=G,this.frameAnchor=`${G}-${e}`,this.rotate=0),this.dy=G==="top"?3-L:G==="bottom"?tt-3:0,this.dx=e==="right"?Q:-Q,this.ariaLabel=`${t}-axis label`,{facets:[[0]],channels:{text:{value:[Gx(t,N,{anchor:e,label:T,labelAnchor:G,labelArrow:k})]}}}})):null)}function Px(t,e,r,{color:n="currentColor",opacity:o=1,stroke:i=n,strokeOpacity:a=o,strokeWidth:f=1,fill:u=n,fillOpacity:c=o,textAnchor:s,textStroke:p,textStrokeOpacity:l,textStrokeWidth:m,tickSize:d=t==="x"?6:0,tickPadding:h,tickRotate:g,y:b,margin:x,marginTop:M=x===void 0?e==="top"?30:0:x,marginRight:y=x===void 0?20:x,marginBottom:w=x===void 0?e==="bottom"?30:0:x,marginLeft:v=x===void 0?20:x,label:T,labelAnchor:E,labelArrow:k,labelOffset:q,..._}){return d=ct(d),h=ct(h),g=ct(g),E!==void 0&&(E=me(E,"labelAnchor",["center","left","right"])),k=Xx(k),rm(d&&!Ft(i)?V5(t,e,r,{stroke:i,strokeOpacity:a,strokeWidth:f,tickSize:d,tickPadding:h,tickRotate:g,y:b,..._}):null,Ft(u)?null:K5(t,e,r,{fill:u,fillOpacity:c,stroke:p,strokeOpacity:l,strokeWidth:m,textAnchor:s,tickSize:d,tickPadding:h,tickRotate:g,y:b,marginTop:M,marginRight:y,marginBottom:w,marginLeft:v,..._}),!Ft(u)&&T!==null?Bo([],Ux({fill:u,fillOpacity:c,..._},function(R,F,P,S,$){let N=S[t],{marginTop:L,marginRight:D,marginBottom:tt,marginLeft:K}=t==="x"&&$.inset||$,G=E??(N.bandwidth?"center":"right"),Q=q??(e==="top"?L:tt)-3;return G==="center"?(this.frameAnchor=e,this.textAnchor=void 0):(this.frameAnchor=`${e}-${G}`,this.textAnchor=G==="right"?"end":"start"),this.lineAnchor=e,this.dy=e==="top"?-Q:Q,this.dx=G==="right"?D-3:G==="left"?3-K:0,this.ariaLabel=`${t}-axis label`,{facets:[[0]],channels:{text:{value:[Gx(t,N,{anchor:e,label:T,labelAnchor:G,labelArrow:k})]}}}})):null)}function X5(t,e,r,{strokeWidth:n=1,strokeLinecap:o=null,strokeLinejoin:i=null,facetAnchor:a=e+(t==="y"?"-empty":""),frameAnchor:f=e,tickSize:u,inset:c=0,insetLeft:s=c,insetRight:p=c,dx:l=0,y:m=t==="y"?void 0:null,...d}){return Uo($x,t,`${t}-axis tick`,r,{strokeWidth:n,strokeLinecap:o,strokeLinejoin:i,facetAnchor:a,frameAnchor:f,y:m,...d,dx:e==="left"?+l-zt+ +s:+l+zt-p,anchor:"start",length:u,shape:e==="left"?J5:tv})}function V5(t,e,r,{strokeWidth:n=1,strokeLinecap:o=null,strokeLinejoin:i=null,facetAnchor:a=e+(t==="x"?"-empty":""),frameAnchor:f=e,tickSize:u,inset:c=0,insetTop:s=c,insetBottom:p=c,dy:l=0,x:m=t==="x"?void 0:null,...d}){return Uo(Cx,t,`${t}-axis tick`,r,{strokeWidth:n,strokeLinejoin:i,strokeLinecap:o,facetAnchor:a,frameAnchor:f,x:m,...d,dy:e==="bottom"?+l-zt-p:+l+zt+ +s,anchor:"start",length:u,shape:e==="bottom"?Z5:Q5})}function j5(t,e,r,{facetAnchor:n=e+(t==="y"?"-empty":""),frameAnchor:o=e,tickSize:i,tickRotate:a=0,tickPadding:f=Math.max(3,9-i)+(Math.abs(a)>60?4*Math.cos(a*fm):0),tickFormat:u,text:c=typeof u=="function"?u:void 0,textAnchor:s=Math.abs(a)>60?"middle":e==="left"?"end":"start",lineAnchor:p=a>60?"top":a<-60?"bottom":"middle",fontVariant:l,inset:m=0,insetLeft:d=m,insetRight:h=m,dx:g=0,y:b=t==="y"?void 0:null,...x}){return Uo(vx,t,`${t}-axis tick label`,r,{facetAnchor:n,frameAnchor:o,text:c===void 0?null:c,textAnchor:s,lineAnchor:p,fontVariant:l,rotate:a,y:b,...x,dx:e==="left"?+g-i-f+ +d:+g+ +i+ +f-h},function(M,y,w,v){l===void 0&&(this.fontVariant=Hx(M)),c===void 0&&(v.text=Wx(M,y,w,u,e))})}function K5(t,e,r,{facetAnchor:n=e+(t==="x"?"-empty":""),frameAnchor:o=e,tickSize:i,tickRotate:a=0,tickPadding:f=Math.max(3,9-i)+(Math.abs(a)>=10?4*Math.cos(a*fm):0),tickFormat:u,text:c=typeof u=="function"?u:void 0,textAnchor:s=Math.abs(a)>=10?a<0^e==="bottom"?"start":"end":"middle",lineAnchor:p=Math.abs(a)>=10?"middle":e==="bottom"?"top":"bottom",fontVariant:l,inset:m=0,insetTop:d=m,insetBottom:h=m,dy:g=0,x:b=t==="x"?void 0:null,...x}){return Uo(wx,t,`${t}-axis tick label`,r,{facetAnchor:n,frameAnchor:o,text:c===void 0?null:c,textAnchor:s,lineAnchor:p,fontVariant:l,rotate:a,x:b,...x,dy:e==="bottom"?+g+ +i+ +f-h:+g-i-f+ +d},function(M,y,w,v){l===void 0&&(this.fontVariant=Hx(M)),c===void 0&&(v.text=Wx(M,y,w,u,e))})}function Lx(){let[t,e]=Kr(...arguments);return Yx("y",Ax(e),t,e)}function Fx(){let[t,e]=Kr(...arguments);return Yx("fy",Ex(e),t,e)}function qx(){let[t,e]=Kr(...arguments);return Bx("x",kx(e),t,e)}function Ox(){let[t,e]=Kr(...arguments);return Bx("fx",Ix(e),t,e)}function Yx(t,e,r,{y:n=t==="y"?void 0:null,x:o=null,x1:i=e==="left"?o:null,x2:a=e==="right"?o:null,...f}){return Uo(mx,t,`${t}-grid`,r,{y:n,x1:i,x2:a,...zx(f)})}function Bx(t,e,r,{x:n=t==="x"?void 0:null,y:o=null,y1:i=e==="top"?o:null,y2:a=e==="bottom"?o:null,...f}){return Uo(px,t,`${t}-grid`,r,{x:n,y1:i,y2:a,...zx(f)})}function zx({color:t="currentColor",opacity:e=.1,stroke:r=t,strokeOpacity:n=e,strokeWidth:o=1,...i}){return{stroke:r,strokeOpacity:n,strokeWidth:o,...i}}function Ux({fill:t,fillOpacity:e,fontFamily:r,fontSize:n,fontStyle:o,fontWeight:i,monospace:a,pointerEvents:f,shapeRendering:u},c){return[,t]=ae(t),[,e]=kt(e),{facet:"super",x:null,y:null,fill:t,fillOpacity:e,fontFamily:r,fontSize:n,fontStyle:o,fontWeight:i,monospace:a,pointerEvents:f,shapeRendering:u,initializer:c}}function Uo(t,e,r,n,o,i){let a;function f(s,p,l,m,d,h){let g=s==null&&(e==="fx"||e==="fy"),{[e]:b}=m;if(!b)throw new Error(`missing scale: ${e}`);let{ticks:x,tickSpacing:M,interval:y}=o;if(jc(b)&&typeof x=="string"&&(y=x,x=void 0),s==null){if(ie(x))s=It(x);else if(b.ticks)if(x!==void 0)s=b.ticks(x);else if(y=fr(y===void 0?b.interval:y,b.type),y!==void 0){let[v,T]=Ct(b.domain());s=y.range(v,y.offset(y.floor(T)))}else{let[v,T]=Ct(b.range());x=(T-v)/(M===void 0?e==="x"?80:35:M),s=b.ticks(x)}else s=b.domain();e==="y"||e==="x"?p=[oe(s)]:a[e]={scale:e,value:vt}}i?.call(this,b,s,x,a);let w=Object.fromEntries(Object.entries(a).map(([v,T])=>[v,{...T,value:gt(s,T.value)}]));return g&&(p=h.filterFacets(s,w)),{data:s,facets:p,channels:w}}let u=Dn(o).initializer,c=t(n,Dn({...o,initializer:f},u));return n==null?(a=c.channels,c.channels={}):a={},c.ariaLabel=r,c}function Wx(t,e,r,n,o){return{value:Wo(t,e,r,n,o)}}function Wo(t,e,r,n,o){return n===void 0&&jc(t)?ug(t,e,r,o):t.tickFormat?t.tickFormat(ie(r)?null:r,n):n===void 0?ag(t.interval)?wr("%Y"):fg(t.interval)?Hr("%Y"):$e:typeof n=="string"?(Bt(t.domain())?wr:Qe)(n):Gr(n)}var Z5={draw(t,e){t.moveTo(0,0),t.lineTo(0,e)}},Q5={draw(t,e){t.moveTo(0,0),t.lineTo(0,-e)}},J5={draw(t,e){t.moveTo(0,0),t.lineTo(-e,0)}},tv={draw(t,e){t.moveTo(0,0),t.lineTo(e,0)}};function Hx(t){return t.bandwidth&&!t.interval?void 0:"tabular-nums"}function ev(t){return Math.sign(Mr(t.domain()))*Math.sign(Mr(t.range()))}function Gx(t,e,{anchor:r,label:n=e.label,labelAnchor:o,labelArrow:i}={}){if(!(n==null||n.inferred&&rv(e)&&/^(date|time|year)$/i.test(n))){if(n=String(n),i==="auto"&&(i=(!e.bandwidth||e.interval)&&!/[↑↓→←]/.test(n)),!i)return n;if(i===!0){let a=ev(e);a&&(i=/x$/.test(t)||o==="center"?/x$/.test(t)===a<0?"left":"right":a<0?"up":"down")}switch(i){case"left":return`\u2190 ${n}`;case"right":return`${n} \u2192`;case"up":return r==="right"?`${n} \u2191`:`\u2191 ${n}`;case"down":return r==="right"?`${n} \u2193`:`\u2193 ${n}`}return n}}function Xx(t="auto"){return Ft(t)?!1:typeof t=="boolean"?t:me(t,"labelArrow",["auto","up","right","down","left"])}function rv(t){return jc(t)||t.interval!=null}function Vx(t,e){if(e==null)return e;let r=t(e);if(!r)throw new Error(`scale not found: ${e}`);return r}function jx(t,{opacity:e,...r}={}){if(!sr(t)&&!Kp(t))throw new Error(`swatches legend requires ordinal or threshold color scale (not ${t.type})`);return Zx(t,r,(n,o,i,a)=>n.append("svg").attr("width",i).attr("height",a).attr("fill",o.scale).attr("fill-opacity",kt(e)[1]).append("rect").attr("width","100%").attr("height","100%"))}function Kx(t,{fill:e=t.hint?.fill!==void 0?t.hint.fill:"none",fillOpacity:r=1,stroke:n=t.hint?.stroke!==void 0?t.hint.stroke:Ft(e)?"currentColor":"none",strokeOpacity:o=1,strokeWidth:i=1.5,r:a=4.5,...f}={},u){let[c,s]=ae(e),[p,l]=ae(n),m=Vx(u,c),d=Vx(u,p),h=a*a*Math.PI;return r=kt(r)[1],o=kt(o)[1],i=kt(i)[1],Zx(t,f,(g,b,x,M)=>g.append("svg").attr("viewBox","-8 -8 16 16").attr("width",x).attr("height",M).attr("fill",c==="color"?y=>m.scale(y):s).attr("fill-opacity",r).attr("stroke",p==="color"?y=>d.scale(y):l).attr("stroke-opacity",o).attr("stroke-width",i).append("path").attr("d",y=>{let w=Jn();return t.scale(y).draw(w,h),w}))}function Zx(t,e={},r){let{columns:n,tickFormat:o,fontVariant:i=es(t),swatchSize:a=15,swatchWidth:f=a,swatchHeight:u=a,marginLeft:c=0,className:s,style:p,width:l}=e,m=Do(e);s=Oo(s),typeof o!="function"&&(o=Wo(t.scale,t.domain,void 0,o));let d=yt("div",m).attr("class",`${s}-swatches ${s}-swatches-${n!=null?"columns":"wrap"}`),h;return n!=null?(h=`.${s}-swatches-columns .${s}-swatch {
   display: flex;
   align-items: center;
   break-inside: avoid;
@@ -29,7 +29,7 @@ ${f}`;switch(Pv(t,e,n)){case"millisecond":return Sr(o(".%L"),o(":%M:%S"),i);case
   white-space: nowrap;
   overflow: hidden;
   text-overflow: ellipsis;
-}`,m.style("columns",r).selectAll().data(t.domain).enter().append("div").attr("class",`${s}-swatch`).call(n,t,f,u).call(g=>g.append("div").attr("class",`${s}-swatch-label`).attr("title",o).text(o))):(h=`.${s}-swatches-wrap {
+}`,d.style("columns",n).selectAll().data(t.domain).enter().append("div").attr("class",`${s}-swatch`).call(r,t,f,u).call(g=>g.append("div").attr("class",`${s}-swatch-label`).attr("title",o).text(o))):(h=`.${s}-swatches-wrap {
   display: flex;
   align-items: center;
   min-height: 33px;
@@ -39,7 +39,7 @@ ${f}`;switch(Pv(t,e,n)){case"millisecond":return Sr(o(".%L"),o(":%M:%S"),i);case
   display: inline-flex;
   align-items: center;
   margin-right: 1em;
-}`,m.selectAll().data(t.domain).enter().append("span").attr("class",`${s}-swatch`).call(n,t,f,u).append(function(){return this.ownerDocument.createTextNode(o.apply(this,arguments))})),m.call(g=>g.insert("style","*").text(`.${s}-swatches {
+}`,d.selectAll().data(t.domain).enter().append("span").attr("class",`${s}-swatch`).call(r,t,f,u).append(function(){return this.ownerDocument.createTextNode(o.apply(this,arguments))})),d.call(g=>g.insert("style","*").text(`.${s}-swatches {
   font-family: system-ui, sans-serif;
   font-size: 10px;
   margin-bottom: 0.5em;
@@ -48,7 +48,7 @@ ${f}`;switch(Pv(t,e,n)){case"millisecond":return Sr(o(".%L"),o(":%M:%S"),i);case
   margin-right: 0.5em;
   overflow: visible;
 }
-${h}`)).style("margin-left",c?`${+c}px`:null).style("width",l===void 0?null:`${+l}px`).style("font-variant",St(i,"normal")).call(Oo,d).node()}var Cp=new Map([["symbol",oy],["color",uy],["opacity",_5]]);function ay(t,e,n={}){return(r,o)=>{if(!Cp.has(r))throw new Error(`unknown legend type: ${r}`);if(r in t)return Cp.get(r)(t[r],fy(e,n[r],o),i=>t[i])}}function fy({className:t,...e},{label:n,ticks:r,tickFormat:o}={},i){return Xg(i,{className:t,...e},{label:n,ticks:r,tickFormat:o})}function uy(t,{legend:e=!0,...n}){if(e===!0&&(e=t.type==="ordinal"?"swatches":"ramp"),t.domain!==void 0)switch(`${e}`.toLowerCase()){case"swatches":return ry(t,n);case"ramp":return yx(t,n);default:throw new Error(`unknown legend type: ${e}`)}}function _5({type:t,interpolate:e,...n},{legend:r=!0,color:o=be(0,0,0),...i}){if(!e)throw new Error(`${t} opacity scales are not supported`);if(r===!0&&(r="ramp"),`${r}`.toLowerCase()!=="ramp")throw new Error(`${r} opacity legends are not supported`);return uy({type:t,...n,interpolate:C5(o)},{legend:r,...i})}function C5(t){let{r:e,g:n,b:r}=be(t)||be(0,0,0);return o=>`rgba(${e},${n},${r},${o})`}function cy(t,e,n){let r=[];for(let[o,i]of Cp){let a=n[o];if(a?.legend&&o in t){let f=i(t[o],fy(e,t[o],a),u=>t[u]);f!=null&&r.push(f)}}return r}var A5={ariaLabel:"frame",fill:"none",stroke:"currentColor"},$5={ariaLabel:"frame",fill:null,stroke:"currentColor",strokeLinecap:"square"},ts=class extends Nt{constructor(e={}){let{anchor:n=null,inset:r=0,insetTop:o=r,insetRight:i=r,insetBottom:a=r,insetLeft:f=r,rx:u,ry:c}=e;super(xo,void 0,e,n==null?A5:$5),this.anchor=_r(n,"anchor",["top","right","bottom","left"]),this.insetTop=ct(o),this.insetRight=ct(i),this.insetBottom=ct(a),this.insetLeft=ct(f),this.rx=ct(u),this.ry=ct(c)}render(e,n,r,o,i){let{marginTop:a,marginRight:f,marginBottom:u,marginLeft:c,width:s,height:d}=o,{anchor:l,insetTop:p,insetRight:m,insetBottom:h,insetLeft:g,rx:b,ry:x}=this,M=c+g,y=s-f-m,w=a+p,v=d-u-h;return yt(l?"svg:line":"svg:rect",i).datum(0).call(me,this,o,i).call(he,this).call(Oe,this,r).call(ge,this,{}).call(l==="left"?T=>T.attr("x1",M).attr("x2",M).attr("y1",w).attr("y2",v):l==="right"?T=>T.attr("x1",y).attr("x2",y).attr("y1",w).attr("y2",v):l==="top"?T=>T.attr("x1",M).attr("x2",y).attr("y1",w).attr("y2",w):l==="bottom"?T=>T.attr("x1",M).attr("x2",y).attr("y1",v).attr("y2",v):T=>T.attr("x",M).attr("y",w).attr("width",y-M).attr("height",v-w).attr("rx",b).attr("ry",x)).node()}};function Uo(t){return new ts(t)}var Ap={ariaLabel:"tip",fill:"white",stroke:"currentColor"},E5=new Set(["geometry","href","src","ariaLabel"]),Ep=class extends Nt{constructor(e,n={}){n.tip&&(n={...n,tip:!1}),n.title===void 0&&re(e)&&Nc(e)&&(n={...n,title:wt});let{x:r,y:o,x1:i,x2:a,y1:f,y2:u,anchor:c,monospace:s,fontFamily:d=s?"ui-monospace, monospace":void 0,fontSize:l,fontStyle:p,fontVariant:m,fontWeight:h,lineHeight:g=1,lineWidth:b=20,frameAnchor:x,textAnchor:M="start",textOverflow:y,textPadding:w=8,title:v,pointerSize:T=12,pathFilter:E="drop-shadow(0 3px 4px rgba(0,0,0,0.2))"}=n;super(e,{x:{value:i!=null&&a!=null?null:r,scale:"x",optional:!0},y:{value:f!=null&&u!=null?null:o,scale:"y",optional:!0},x1:{value:i,scale:"x",optional:a==null},y1:{value:f,scale:"y",optional:u==null},x2:{value:a,scale:"x",optional:i==null},y2:{value:u,scale:"y",optional:f==null},title:{value:v,optional:!0}},n,Ap),this.anchor=$d(c,"anchor"),this.previousAnchor=this.anchor??"top-left",this.frameAnchor=Mo(x),this.textAnchor=St(M,"middle"),this.textPadding=+w,this.pointerSize=+T,this.pathFilter=Et(E),this.lineHeight=+g,this.lineWidth=+b,this.textOverflow=mp(y),this.monospace=!!s,this.fontFamily=Et(d),this.fontSize=ct(l),this.fontStyle=Et(p),this.fontVariant=Et(m),this.fontWeight=Et(h);for(let I in Ap)I in this.channels&&(this[I]=Ap[I]);this.splitLines=yp(this),this.clipLine=bp(this)}render(e,n,r,o,i){let a=this,{x:f,y:u,fx:c,fy:s}=n,{ownerSVGElement:d,document:l}=i,{anchor:p,monospace:m,lineHeight:h,lineWidth:g}=this,{textPadding:b,pointerSize:x,pathFilter:M}=this,{marginTop:y,marginLeft:w}=o,v=D5(r),{x1:T,y1:E,x2:I,y2:F,x:_=T??I,y:R=E??F}=r,O=c?c(e.fx)-w:0,L=s?s(e.fy)-y:0,[S,A]=Gn(this,o),N=op(r,S),P=ip(r,A),D=m?Zc:jc,tt=D(Ir),Z=c&&Bo(c),W=s&&Bo(s);function*Q(G,X){if("title"in G){let $=G.title.value[X];for(let q of a.splitLines(_e($)))yield{name:"",value:a.clipLine(q)};return}for(let $ in G){if($==="x1"&&"x2"in G||$==="y1"&&"y2"in G)continue;let q=G[$],z=q.value[X];if(!(!Re(z)&&q.scale==null))if($==="x2"&&"x1"in G)yield{name:$p(n,q,"x"),value:sy(G.x1,q,X)};else if($==="y2"&&"y1"in G)yield{name:$p(n,q,"y"),value:sy(G.y1,q,X)};else{let C=q.scale,j={name:$p(n,q,$),value:_e(z)};(C==="color"||C==="opacity")&&(j[C]=r[$][X]),yield j}}e.fi!=null&&c&&(yield{name:String(c.label??"fx"),value:Z(e.fx)}),e.fi!=null&&s&&(yield{name:String(s.label??"fy"),value:W(e.fy)})}let at=yt("svg:g",i).call(me,this,o,i).call(xp,this).call(ge,this,{x:_&&f,y:R&&u}).call(G=>G.selectAll().data(e).enter().append("g").attr("transform",X=>`translate(${Math.round(N(X))},${Math.round(P(X))})`).call(he,this).call(X=>X.append("path").attr("filter",M)).call(X=>X.append("text").each(function($){let q=mn(this);this.setAttribute("fill","currentColor"),this.setAttribute("fill-opacity",1),this.setAttribute("stroke","none");let z=new Set;for(let C of Q(v,$)){let j=C.name;j&&z.has(j)||(z.add(j),Ut(q,C))}})));function Ut(G,{name:X,value:$,color:q,opacity:z}){let C=q!=null||z!=null,j,J=g*100,[ot]=kr(X,J,D,tt);if(ot>=0)X=X.slice(0,ot).trimEnd()+Ir,j=$.trim(),$="";else{(X||!$&&!C)&&($=" "+$);let[Xt]=kr($,J-D(X),D,tt);Xt>=0&&($=$.slice(0,Xt).trimEnd()+Ir,j=$.trim())}let lt=G.append("tspan").attr("x",0).attr("dy",`${h}em`).text("\u200B");X&&lt.append("tspan").attr("font-weight","bold").text(X),$&&lt.append(()=>l.createTextNode($)),C&&lt.append("tspan").text(" \u25A0").attr("fill",q).attr("fill-opacity",z).style("user-select","none"),j&&lt.append("title").text(j)}function Fe(){let{width:G,height:X}=o.facet??o;at.selectChildren().each(function($){let{x:q,width:z,height:C}=this.getBBox();z=Math.round(z),C=Math.round(C);let j=p;if(j===void 0){j=a.previousAnchor;let lt=N($)+O,Xt=P($)+L,Zn=lt+z+b*2<G,Ra=lt-z-b*2>0,La=Xt+C+x+b*2+7<X,Pa=Xt-C-x-b*2>0,Oa=(/-left$/.test(j)?Zn||!Ra:Zn&&!Ra)?"left":"right",fs=(/^top-/.test(j)?La||!Pa:La&&!Pa)?"top":"bottom";j=a.previousAnchor=`${fs}-${Oa}`}let J=this.firstChild,ot=this.lastChild;if(J.setAttribute("d",N5(j,x,b,z,C)),q)for(let lt of ot.childNodes)lt.setAttribute("x",-q);ot.setAttribute("y",`${+I5(j,ot.childNodes.length,h).toFixed(6)}em`),ot.setAttribute("transform",`translate(${k5(j,x,b,z,C)})`)})}return d.isConnected?Promise.resolve().then(Fe):typeof requestAnimationFrame<"u"&&requestAnimationFrame(Fe),at.node()}};function ly(t,{x:e,y:n,...r}={}){return r.frameAnchor===void 0&&([e,n]=Ar(e,n)),new Ep(t,{...r,x:e,y:n})}function I5(t,e,n){return/^top(?:-|$)/.test(t)?.94-n:-.29-e*n}function k5(t,e,n,r,o){switch(t){case"middle":return[-r/2,o/2];case"top-left":return[n,e+n];case"top":return[-r/2,e/2+n];case"top-right":return[-r-n,e+n];case"right":return[-e/2-r-n,o/2];case"bottom-left":return[n,-e-n];case"bottom":return[-r/2,-e/2-n];case"bottom-right":return[-r-n,-e-n];case"left":return[n+e/2,o/2]}}function N5(t,e,n,r,o){let i=r+n*2,a=o+n*2;switch(t){case"middle":return`M${-i/2},${-a/2}h${i}v${a}h${-i}z`;case"top-left":return`M0,0l${e},${e}h${i-e}v${a}h${-i}z`;case"top":return`M0,0l${e/2},${e/2}h${(i-e)/2}v${a}h${-i}v${-a}h${(i-e)/2}z`;case"top-right":return`M0,0l${-e},${e}h${e-i}v${a}h${i}z`;case"right":return`M0,0l${-e/2},${-e/2}v${e/2-a/2}h${-i}v${a}h${i}v${e/2-a/2}z`;case"bottom-left":return`M0,0l${e},${-e}h${i-e}v${-a}h${-i}z`;case"bottom":return`M0,0l${e/2},${-e/2}h${(i-e)/2}v${-a}h${-i}v${a}h${(i-e)/2}z`;case"bottom-right":return`M0,0l${-e},${-e}h${e-i}v${-a}h${i}z`;case"left":return`M0,0l${e/2},${-e/2}v${e/2-a/2}h${i}v${a}h${-i}v${e/2-a/2}z`}}function D5({channels:t}){let e={};for(let n in t){if(E5.has(n))continue;let r=u1(t,n);r&&(e[n]=r)}return e}function sy(t,e,n){return e.hint?.length?`${_e(e.value[n]-t.value[n])}`:`${_e(t.value[n])}\u2013${_e(e.value[n])}`}function $p(t,e,n){return String(t[e.scale]?.label??e?.label??n)}function Na(t={}){let{facet:e,style:n,caption:r,ariaLabel:o,ariaDescription:i}=t,a=Po(t.className),f=t.marks===void 0?[]:dy(t.marks);f.push(...q5(f));let u=O5(e,t),c=new Map;for(let A of f){let N=py(A,u,t);N&&c.set(A,N)}let s=new Map;u&&ka(s,[u],t),ka(s,c,t);let d=dy(z5(f,s,t));for(let A of d){let N=py(A,u,t);N&&c.set(A,N)}f.unshift(...d);let l=cx(s,t);if(l!==void 0){let A=u?Xc(l,u):void 0;for(let P of f){if(P.facet===null||P.facet==="super")continue;let D=c.get(P);D!==void 0&&(D.facetsIndex=P.fx!=null||P.fy!=null?Xc(l,D):A)}let N=new Set;for(let{facetsIndex:P}of c.values())P?.forEach((D,tt)=>{D?.length>0&&N.add(tt)});l.forEach(0<N.size&&N.size<l.length?(P,D)=>P.empty=!N.has(D):P=>P.empty=!1);for(let P of f)if(P.facet==="exclude"){let D=c.get(P);D!==void 0&&(D.facetsIndex=dx(D.facetsIndex))}}for(let A of st.keys())yo(t[A])&&A!=="fx"&&A!=="fy"&&s.set(A,[]);let p=new Map;for(let A of f){if(p.has(A))throw new Error("duplicate mark; each mark must be unique");let{facetsIndex:N,channels:P}=c.get(A)??{},{data:D,facets:tt,channels:Z}=A.initialize(N,P,t);Np(Z,t),p.set(A,{data:D,facets:tt,channels:Z})}let m=Aa(ka(s,p,t),t),h=Gd(m),g=fx(m,f,t);J1(m,g);let{fx:b,fy:x}=h,M=b||x?Vd(m,g):g,y=b||x?G5(h,g):g,w=Io(t),v=w.document,T=dn("svg").call(v.documentElement),E=T;w.ownerSVGElement=T,w.className=a,w.projection=d1(t,M),w.filterFacets=(A,N)=>Xc(l,{channels:N,groups:Bc(A,N)}),w.getMarkState=A=>{let N=p.get(A),P=c.get(A);return{...N,channels:{...N.channels,...P?.channels}}},w.dispatchValue=A=>{E.value!==A&&(E.value=A,E.dispatchEvent(new Event("input",{bubbles:!0})))};let I=new Set;for(let[A,N]of p)if(A.initializer!=null){let P=A.facet==="super"?y:M,D=A.initializer(N.data,N.facets,N.channels,h,P,w);if(D.data!==void 0&&(N.data=D.data),D.facets!==void 0&&(N.facets=D.facets),D.channels!==void 0){let{fx:tt,fy:Z,...W}=D.channels;P5(W),Object.assign(N.channels,W);for(let Q of Object.values(W)){let{scale:at}=Q;at!=null&&st.get(at)!==de&&(gy(Q,t),I.add(at))}(tt!=null||Z!=null)&&c.set(A,!0)}}if(I.size){let A=new Map;ka(A,p,t,D=>I.has(D)),ka(s,p,t,D=>I.has(D));let N=W5(Aa(A,t),m),P=Gd(N);Object.assign(m,N),Object.assign(h,P)}let F,_;l!==void 0&&(F={x:b?.domain(),y:x?.domain()},l=sx(l,F),_=lx(b,x,g));for(let[A,N]of p)N.values=A.scale(N.channels,h,w);let{width:R,height:O}=g;mn(T).attr("class",a).attr("fill","currentColor").attr("font-family","system-ui, sans-serif").attr("font-size",10).attr("text-anchor","middle").attr("width",R).attr("height",O).attr("viewBox",`0 0 ${R} ${O}`).attr("aria-label",o).attr("aria-description",i).call(A=>A.append("style").text(`.${a} {
+${h}`)).style("margin-left",c?`${+c}px`:null).style("width",l===void 0?null:`${+l}px`).style("font-variant",St(i,"normal")).call(Yo,p).node()}var wm=new Map([["symbol",Kx],["color",ty],["opacity",nv]]);function Qx(t,e,r={}){return(n,o)=>{if(!wm.has(n))throw new Error(`unknown legend type: ${n}`);if(n in t)return wm.get(n)(t[n],Jx(e,r[n],o),i=>t[i])}}function Jx({className:t,...e},{label:r,ticks:n,tickFormat:o}={},i){return Ag(i,{className:t,...e},{label:r,ticks:n,tickFormat:o})}function ty(t,{legend:e=!0,...r}){if(e===!0&&(e=t.type==="ordinal"?"swatches":"ramp"),t.domain!==void 0)switch(`${e}`.toLowerCase()){case"swatches":return jx(t,r);case"ramp":return rx(t,r);default:throw new Error(`unknown legend type: ${e}`)}}function nv({type:t,interpolate:e,...r},{legend:n=!0,color:o=ve(0,0,0),...i}){if(!e)throw new Error(`${t} opacity scales are not supported`);if(n===!0&&(n="ramp"),`${n}`.toLowerCase()!=="ramp")throw new Error(`${n} opacity legends are not supported`);return ty({type:t,...r,interpolate:ov(o)},{legend:n,...i})}function ov(t){let{r:e,g:r,b:n}=ve(t)||ve(0,0,0);return o=>`rgba(${e},${r},${n},${o})`}function ey(t,e,r){let n=[];for(let[o,i]of wm){let a=r[o];if(a?.legend&&o in t){let f=i(t[o],Jx(e,t[o],a),u=>t[u]);f!=null&&n.push(f)}}return n}var iv={ariaLabel:"frame",fill:"none",stroke:"currentColor"},av={ariaLabel:"frame",fill:null,stroke:"currentColor",strokeLinecap:"square"},vm=class extends Nt{constructor(e={}){let{anchor:r=null,inset:n=0,insetTop:o=n,insetRight:i=n,insetBottom:a=n,insetLeft:f=n,rx:u,ry:c}=e;super(wo,void 0,e,r==null?iv:av),this.anchor=En(r,"anchor",["top","right","bottom","left"]),this.insetTop=ct(o),this.insetRight=ct(i),this.insetBottom=ct(a),this.insetLeft=ct(f),this.rx=ct(u),this.ry=ct(c)}render(e,r,n,o,i){let{marginTop:a,marginRight:f,marginBottom:u,marginLeft:c,width:s,height:p}=o,{anchor:l,insetTop:m,insetRight:d,insetBottom:h,insetLeft:g,rx:b,ry:x}=this,M=c+g,y=s-f-d,w=a+m,v=p-u-h;return yt(l?"svg:line":"svg:rect",i).datum(0).call(ge,this,o,i).call(xe,this).call(Ye,this,n).call(ye,this,{}).call(l==="left"?T=>T.attr("x1",M).attr("x2",M).attr("y1",w).attr("y2",v):l==="right"?T=>T.attr("x1",y).attr("x2",y).attr("y1",w).attr("y2",v):l==="top"?T=>T.attr("x1",M).attr("x2",y).attr("y1",w).attr("y2",w):l==="bottom"?T=>T.attr("x1",M).attr("x2",y).attr("y1",v).attr("y2",v):T=>T.attr("x",M).attr("y",w).attr("width",y-M).attr("height",v-w).attr("rx",b).attr("ry",x)).node()}};function Zr(t){return new vm(t)}var Mm={ariaLabel:"tip",fill:"white",stroke:"currentColor"},fv=new Set(["geometry","href","src","ariaLabel"]),Tm=class extends Nt{constructor(e,r={}){r.tip&&(r={...r,tip:!1}),r.title===void 0&&ie(e)&&zc(e)&&(r={...r,title:vt});let{x:n,y:o,x1:i,x2:a,y1:f,y2:u,anchor:c,monospace:s,fontFamily:p=s?"ui-monospace, monospace":void 0,fontSize:l,fontStyle:m,fontVariant:d,fontWeight:h,lineHeight:g=1,lineWidth:b=20,frameAnchor:x,textAnchor:M="start",textOverflow:y,textPadding:w=8,title:v,pointerSize:T=12,pathFilter:E="drop-shadow(0 3px 4px rgba(0,0,0,0.2))"}=r;super(e,{x:{value:i!=null&&a!=null?null:n,scale:"x",optional:!0},y:{value:f!=null&&u!=null?null:o,scale:"y",optional:!0},x1:{value:i,scale:"x",optional:a==null},y1:{value:f,scale:"y",optional:u==null},x2:{value:a,scale:"x",optional:i==null},y2:{value:u,scale:"y",optional:f==null},title:{value:v,optional:!0}},r,Mm),this.anchor=Ep(c,"anchor"),this.previousAnchor=this.anchor??"top-left",this.frameAnchor=_o(x),this.textAnchor=St(M,"middle"),this.textPadding=+w,this.pointerSize=+T,this.pathFilter=Et(E),this.lineHeight=+g,this.lineWidth=+b,this.textOverflow=hm(y),this.monospace=!!s,this.fontFamily=Et(p),this.fontSize=ct(l),this.fontStyle=Et(m),this.fontVariant=Et(d),this.fontWeight=Et(h);for(let k in Mm)k in this.channels&&(this[k]=Mm[k]);this.splitLines=xm(this),this.clipLine=ym(this)}render(e,r,n,o,i){let a=this,{x:f,y:u,fx:c,fy:s}=r,{ownerSVGElement:p,document:l}=i,{anchor:m,monospace:d,lineHeight:h,lineWidth:g}=this,{textPadding:b,pointerSize:x,pathFilter:M}=this,{marginTop:y,marginLeft:w}=o,v=lv(n),{x1:T,y1:E,x2:k,y2:q,x:_=T??k,y:R=E??q}=n,F=c?c(e.fx)-w:0,P=s?s(e.fy)-y:0,[S,$]=Vr(this,o),N=im(n,S),L=am(n,$),D=d?as:is,tt=D(Rn),K=c&&Wo(c),G=s&&Wo(s);function*Q(X,W){if("title"in X){let A=X.title.value[W];for(let O of a.splitLines($e(A)))yield{name:"",value:a.clipLine(O)};return}for(let A in X){if(A==="x1"&&"x2"in X||A==="y1"&&"y2"in X)continue;let O=X[A],Y=O.value[W];if(!(!Fe(Y)&&O.scale==null))if(A==="x2"&&"x1"in X)yield{name:Sm(r,O,"x"),value:ry(X.x1,O,W)};else if(A==="y2"&&"y1"in X)yield{name:Sm(r,O,"y"),value:ry(X.y1,O,W)};else{let C=O.scale,j={name:Sm(r,O,A),value:$e(Y)};(C==="color"||C==="opacity")&&(j[C]=n[A][W]),yield j}}e.fi!=null&&c&&(yield{name:String(c.label??"fx"),value:K(e.fx)}),e.fi!=null&&s&&(yield{name:String(s.label??"fy"),value:G(e.fy)})}let at=yt("svg:g",i).call(ge,this,o,i).call(gm,this).call(ye,this,{x:_&&f,y:R&&u}).call(X=>X.selectAll().data(e).enter().append("g").attr("transform",W=>`translate(${Math.round(N(W))},${Math.round(L(W))})`).call(xe,this).call(W=>W.append("path").attr("filter",M)).call(W=>W.append("text").each(function(A){let O=Ie(this);this.setAttribute("fill","currentColor"),this.setAttribute("fill-opacity",1),this.setAttribute("stroke","none");let Y=new Set;for(let C of Q(v,A)){let j=C.name;j&&Y.has(j)||(Y.add(j),Ut(O,C))}})));function Ut(X,{name:W,value:A,color:O,opacity:Y}){let C=O!=null||Y!=null,j,J=g*100,[ot]=Pn(W,J,D,tt);if(ot>=0)W=W.slice(0,ot).trimEnd()+Rn,j=A.trim(),A="";else{(W||!A&&!C)&&(A=" "+A);let[Wt]=Pn(A,J-D(W),D,tt);Wt>=0&&(A=A.slice(0,Wt).trimEnd()+Rn,j=A.trim())}let lt=X.append("tspan").attr("x",0).attr("dy",`${h}em`).text("\u200B");W&&lt.append("tspan").attr("font-weight","bold").text(W),A&&lt.append(()=>l.createTextNode(A)),C&&lt.append("tspan").text(" \u25A0").attr("fill",O).attr("fill-opacity",Y).style("user-select","none"),j&&lt.append("title").text(j)}function Be(){let{width:X,height:W}=o.facet??o;at.selectChildren().each(function(A){let{x:O,width:Y,height:C}=this.getBBox();Y=Math.round(Y),C=Math.round(C);let j=m;if(j===void 0){j=a.previousAnchor;let lt=N(A)+F,Wt=L(A)+P,Qr=lt+Y+b*2<X,Oa=lt-Y-b*2>0,Ya=Wt+C+x+b*2+7<W,Ba=Wt-C-x-b*2>0,za=(/-left$/.test(j)?Qr||!Oa:Qr&&!Oa)?"left":"right",ds=(/^top-/.test(j)?Ya||!Ba:Ya&&!Ba)?"top":"bottom";j=a.previousAnchor=`${ds}-${za}`}let J=this.firstChild,ot=this.lastChild;if(J.setAttribute("d",sv(j,x,b,Y,C)),O)for(let lt of ot.childNodes)lt.setAttribute("x",-O);ot.setAttribute("y",`${+uv(j,ot.childNodes.length,h).toFixed(6)}em`),ot.setAttribute("transform",`translate(${cv(j,x,b,Y,C)})`)})}return p.isConnected?Promise.resolve().then(Be):typeof requestAnimationFrame<"u"&&requestAnimationFrame(Be),at.node()}};function ny(t,{x:e,y:r,...n}={}){return n.frameAnchor===void 0&&([e,r]=In(e,r)),new Tm(t,{...n,x:e,y:r})}function uv(t,e,r){return/^top(?:-|$)/.test(t)?.94-r:-.29-e*r}function cv(t,e,r,n,o){switch(t){case"middle":return[-n/2,o/2];case"top-left":return[r,e+r];case"top":return[-n/2,e/2+r];case"top-right":return[-n-r,e+r];case"right":return[-e/2-n-r,o/2];case"bottom-left":return[r,-e-r];case"bottom":return[-n/2,-e/2-r];case"bottom-right":return[-n-r,-e-r];case"left":return[r+e/2,o/2]}}function sv(t,e,r,n,o){let i=n+r*2,a=o+r*2;switch(t){case"middle":return`M${-i/2},${-a/2}h${i}v${a}h${-i}z`;case"top-left":return`M0,0l${e},${e}h${i-e}v${a}h${-i}z`;case"top":return`M0,0l${e/2},${e/2}h${(i-e)/2}v${a}h${-i}v${-a}h${(i-e)/2}z`;case"top-right":return`M0,0l${-e},${e}h${e-i}v${a}h${i}z`;case"right":return`M0,0l${-e/2},${-e/2}v${e/2-a/2}h${-i}v${a}h${i}v${e/2-a/2}z`;case"bottom-left":return`M0,0l${e},${-e}h${i-e}v${-a}h${-i}z`;case"bottom":return`M0,0l${e/2},${-e/2}h${(i-e)/2}v${-a}h${-i}v${a}h${(i-e)/2}z`;case"bottom-right":return`M0,0l${-e},${-e}h${e-i}v${-a}h${i}z`;case"left":return`M0,0l${e/2},${-e/2}v${e/2-a/2}h${i}v${a}h${-i}v${e/2-a/2}z`}}function lv({channels:t}){let e={};for(let r in t){if(fv.has(r))continue;let n=Gg(t,r);n&&(e[r]=n)}return e}function ry(t,e,r){return e.hint?.length?`${$e(e.value[r]-t.value[r])}`:`${$e(t.value[r])}\u2013${$e(e.value[r])}`}function Sm(t,e,r){return String(t[e.scale]?.label??e?.label??r)}function Fn(t={}){let{facet:e,style:r,caption:n,ariaLabel:o,ariaDescription:i}=t,a=Oo(t.className),f=t.marks===void 0?[]:oy(t.marks);f.push(...xv(f));let u=hv(e,t),c=new Map;for(let $ of f){let N=iy($,u,t);N&&c.set($,N)}let s=new Map;u&&La(s,[u],t),La(s,c,t);let p=oy(yv(f,s,t));for(let $ of p){let N=iy($,u,t);N&&c.set($,N)}f.unshift(...p);let l=X1(s,t);if(l!==void 0){let $=u?ts(l,u):void 0;for(let L of f){if(L.facet===null||L.facet==="super")continue;let D=c.get(L);D!==void 0&&(D.facetsIndex=L.fx!=null||L.fy!=null?ts(l,D):$)}let N=new Set;for(let{facetsIndex:L}of c.values())L?.forEach((D,tt)=>{D?.length>0&&N.add(tt)});l.forEach(0<N.size&&N.size<l.length?(L,D)=>L.empty=!N.has(D):L=>L.empty=!1);for(let L of f)if(L.facet==="exclude"){let D=c.get(L);D!==void 0&&(D.facetsIndex=K1(D.facetsIndex))}}for(let $ of st.keys())vo(t[$])&&$!=="fx"&&$!=="fy"&&s.set($,[]);let m=new Map;for(let $ of f){if(m.has($))throw new Error("duplicate mark; each mark must be unique");let{facetsIndex:N,channels:L}=c.get($)??{},{data:D,facets:tt,channels:K}=$.initialize(N,L,t);$m(K,t),m.set($,{data:D,facets:tt,channels:K})}let d=Ia(La(s,m,t),t),h=Vp(d),g=H1(d,f,t);F1(d,g);let{fx:b,fy:x}=h,M=b||x?jp(d,g):g,y=b||x?_v(h,g):g,w=Do(t),v=w.document,T=He("svg").call(v.documentElement),E=T;w.ownerSVGElement=T,w.className=a,w.projection=Kg(t,M),w.filterFacets=($,N)=>ts(l,{channels:N,groups:Qc($,N)}),w.getMarkState=$=>{let N=m.get($),L=c.get($);return{...N,channels:{...N.channels,...L?.channels}}},w.dispatchValue=$=>{E.value!==$&&(E.value=$,E.dispatchEvent(new Event("input",{bubbles:!0})))};let k=new Set;for(let[$,N]of m)if($.initializer!=null){let L=$.facet==="super"?y:M,D=$.initializer(N.data,N.facets,N.channels,h,L,w);if(D.data!==void 0&&(N.data=D.data),D.facets!==void 0&&(N.facets=D.facets),D.channels!==void 0){let{fx:tt,fy:K,...G}=D.channels;dv(G),Object.assign(N.channels,G);for(let Q of Object.values(G)){let{scale:at}=Q;at!=null&&st.get(at)!==de&&(uy(Q,t),k.add(at))}(tt!=null||K!=null)&&c.set($,!0)}}if(k.size){let $=new Map;La($,m,t,D=>k.has(D)),La(s,m,t,D=>k.has(D));let N=Tv(Ia($,t),d),L=Vp(N);Object.assign(d,N),Object.assign(h,L)}let q,_;l!==void 0&&(q={x:b?.domain(),y:x?.domain()},l=V1(l,q),_=j1(b,x,g));for(let[$,N]of m)N.values=$.scale(N.channels,h,w);let{width:R,height:F}=g;Ie(T).attr("class",a).attr("fill","currentColor").attr("font-family","system-ui, sans-serif").attr("font-size",10).attr("text-anchor","middle").attr("width",R).attr("height",F).attr("viewBox",`0 0 ${R} ${F}`).attr("aria-label",o).attr("aria-description",i).call($=>$.append("style").text(`.${a} {
   display: block;
   background: white;
   height: auto;
@@ -58,7 +58,10 @@ ${h}`)).style("margin-left",c?`${+c}px`:null).style("width",l===void 0?null:`${+
 .${a} text,
 .${a} tspan {
   white-space: pre;
-}`)).call(Oo,n);for(let A of f){let{channels:N,values:P,facets:D}=p.get(A);if(l===void 0||A.facet==="super"){let tt=null;if(D&&(tt=D[0],tt=A.filter(tt,N,P),tt.length===0))continue;let Z=A.render(tt,h,P,y,w);if(Z==null)continue;T.appendChild(Z)}else{let tt;for(let Z of l){if(!(A.facetAnchor?.(l,F,Z)??!Z.empty))continue;let W=null;if(D){let at=c.has(A);if(W=D[at?Z.i:0],W=A.filter(W,N,P),W.length===0)continue;!at&&W===D[0]&&(W=Lg(W)),W.fx=Z.x,W.fy=Z.y,W.fi=Z.i}let Q=A.render(W,h,P,M,w);if(Q!=null){(tt??=mn(T).append("g")).append(()=>Q).datum(Z);for(let at of["aria-label","aria-description","aria-hidden","transform"])Q.hasAttribute(at)&&(tt.attr(at,Q.getAttribute(at)),Q.removeAttribute(at))}}tt?.selectChildren().attr("transform",_)}}let L=cy(m,w,t);if(r!=null||L.length>0){E=v.createElement("figure"),E.style.maxWidth="initial";for(let A of L)E.appendChild(A);if(E.appendChild(T),r!=null){let A=v.createElement("figcaption");A.appendChild(r?.ownerDocument?r:v.createTextNode(r)),E.appendChild(A)}}E.scale=nx(m),E.legend=ay(m,w,t);let S=c1();return S>0&&mn(T).append("text").attr("x",R).attr("y",20).attr("dy","-1em").attr("text-anchor","end").attr("font-family","initial").text("\u26A0\uFE0F").append("title").text(`${S.toLocaleString("en-US")} warning${S===1?"":"s"}. Please check the console.`),E}function R5({marks:t=[],...e}={}){return Na({...e,marks:[...t,this]})}Nt.prototype.plot=R5;function dy(t){return t.flat(1/0).filter(e=>e!=null).map(L5)}function L5(t){return typeof t.render=="function"?t:new kp(t)}var kp=class extends Nt{constructor(e){if(typeof e!="function")throw new TypeError("invalid mark; missing render function");super(),this.render=e}render(){}};function Np(t,e){for(let n in t)gy(t[n],e);return t}function gy(t,e){let{scale:n,transform:r=!0}=t;if(n==null||!r)return;let{type:o,percent:i,interval:a,transform:f=i?u=>u*100:Cd(a,o)}=e[n]??{};f!=null&&(t.value=pt(t.value,f),t.transform=!1)}function P5(t){for(let e in t)Fd(e,t[e])}function ka(t,e,n,r=$g){for(let{channels:o}of e.values())for(let i in o){let a=o[i],{scale:f}=a;if(f!=null&&r(f))if(f==="projection"){if(!g1(n)){let u=n.x?.domain===void 0,c=n.y?.domain===void 0;if(u||c){let[s,d]=y1(a);u&&Ip(t,"x",s),c&&Ip(t,"y",d)}}}else Ip(t,f,a)}return t}function Ip(t,e,n){let r=t.get(e);r!==void 0?r.push(n):t.set(e,[n])}function O5(t,e){if(t==null)return;let{x:n,y:r}=t;if(n==null&&r==null)return;let o=kt(t.data);if(o==null)throw new Error("missing facet data");let i={};n!=null&&(i.fx=Eo(o,{value:n,scale:"fx"})),r!=null&&(i.fy=Eo(o,{value:r,scale:"fy"})),Np(i,e);let a=Bc(o,i);return{channels:i,groups:a,data:t.data}}function py(t,e,n){if(t.facet===null||t.facet==="super")return;let{fx:r,fy:o}=t;if(r!=null||o!=null){let u=kt(t.data??r??o);if(u===void 0)throw new Error(`missing facet data in ${t.ariaLabel}`);if(u===null)return;let c={};return r!=null&&(c.fx=Eo(u,{value:r,scale:"fx"})),o!=null&&(c.fy=Eo(u,{value:o,scale:"fy"})),Np(c,n),{channels:c,groups:Bc(u,c)}}if(e===void 0)return;let{channels:i,groups:a,data:f}=e;if(t.facet!=="auto"||t.data===f)return{channels:i,groups:a};f.length>0&&(a.size>1||a.size===1&&i.fx&&i.fy&&[...a][0][1].size>1)&&kt(t.data)?.length===f.length&&an(`Warning: the ${t.ariaLabel} mark appears to use faceted data, but isn\u2019t faceted. The mark data has the same length as the facet data and the mark facet option is "auto", but the mark data and facet data are distinct. If this mark should be faceted, set the mark facet option to true; otherwise, suppress this warning by setting the mark facet option to false.`)}function F5(t,e={}){return Er({...e,x:null,y:null},(n,r,o,i,a,f)=>f.getMarkState(t))}function q5(t){let e=[];for(let n of t){let r=n.tip;if(r){let i=(r==="x"?gx:r==="y"?xx:hx)(F5(n));i.title=null,e.push(ly(n.data,i))}}return e}function z5(t,e,n){let{projection:r,x:o={},y:i={},fx:a={},fy:f={},axis:u,grid:c,facet:s={},facet:{axis:d=u,grid:l}=s,x:{axis:p=u,grid:m=p===null?null:c}=o,y:{axis:h=u,grid:g=h===null?null:c}=i,fx:{axis:b=d,grid:x=b===null?null:l}=a,fy:{axis:M=d,grid:y=M===null?null:l}=f}=n;(r||!yo(o)&&!my("x",t))&&(p=m=null),(r||!yo(i)&&!my("y",t))&&(h=g=null),e.has("fx")||(b=x=null),e.has("fy")||(M=y=null),p===void 0&&(p=!rs(t,"x")),h===void 0&&(h=!rs(t,"y")),b===void 0&&(b=!rs(t,"fx")),M===void 0&&(M=!rs(t,"fy")),p===!0&&(p="bottom"),h===!0&&(h="left"),b===!0&&(b=p==="top"||p===null?"bottom":"top"),M===!0&&(M=h==="right"||h===null?"left":"right");let w=[];return ns(w,y,Sp,f),es(w,M,wp,"right","left",s,f),ns(w,x,_p,a),es(w,b,vp,"top","bottom",s,a),ns(w,g,Mp,i),es(w,h,Jc,"left","right",n,i),ns(w,m,Tp,o),es(w,p,Nr,"bottom","top",n,o),w}function es(t,e,n,r,o,i,a){if(!e)return;let f=Y5(e);a=B5(f?r:e,i,a);let{line:u}=a;(n===Jc||n===Nr)&&u&&!$r(u)&&t.push(Uo(U5(a))),t.push(n(a)),f&&t.push(n({...a,anchor:o,label:null}))}function ns(t,e,n,r){!e||$r(e)||t.push(n(X5(e,r)))}function Y5(t){return/^\s*both\s*$/i.test(t)}function B5(t,e,{line:n=e.line,ticks:r,tickSize:o,tickSpacing:i,tickPadding:a,tickFormat:f,tickRotate:u,fontVariant:c,ariaLabel:s,ariaDescription:d,label:l=e.label,labelAnchor:p,labelArrow:m=e.labelArrow,labelOffset:h}){return{anchor:t,line:n,ticks:r,tickSize:o,tickSpacing:i,tickPadding:a,tickFormat:f,tickRotate:u,fontVariant:c,ariaLabel:s,ariaDescription:d,label:l,labelAnchor:p,labelArrow:m,labelOffset:h}}function U5(t){let{anchor:e,line:n}=t;return{anchor:e,facetAnchor:e+"-empty",stroke:n===!0?void 0:n}}function X5(t,{stroke:e=vo(t)?t:void 0,ticks:n=H5(t)?t:void 0,tickSpacing:r,ariaLabel:o,ariaDescription:i}){return{stroke:e,ticks:n,tickSpacing:r,ariaLabel:o,ariaDescription:i}}function H5(t){switch(typeof t){case"number":return!0;case"string":return!vo(t)}return re(t)||typeof t?.range=="function"}function rs(t,e){let n=`${e}-axis `;return t.some(r=>r.ariaLabel?.startsWith(n))}function my(t,e){for(let n of e)for(let r in n.channels){let{scale:o}=n.channels[r];if(o===t||o==="projection")return!0}return!1}function W5(t,e){for(let n in t){let r=t[n],o=e[n];r.label===void 0&&o&&(r.label=o.label)}return t}function G5({fx:t,fy:e},n){let{marginTop:r,marginRight:o,marginBottom:i,marginLeft:a,width:f,height:u}=Fc(n),c=t&&hy(t),s=e&&hy(e);return{marginTop:e?s[0]:r,marginRight:t?f-c[1]:o,marginBottom:e?u-s[1]:i,marginLeft:t?c[0]:a,inset:{marginTop:n.marginTop,marginRight:n.marginRight,marginBottom:n.marginBottom,marginLeft:n.marginLeft},width:f,height:u}}function hy(t){let e=t.domain(),n=t(e[0]),r=t(e[e.length-1]);return r<n&&([n,r]=[r,n]),[n,r+t.bandwidth()]}function Dp(t={y:"count"},e={}){[t,e]=j5(t,e);let{x:n,y:r}=e;return V5(Z5(n,e,wt),null,null,r,t,Ia(e))}function V5(t,e,n,r,{data:o=Ld,filter:i=Ma,sort:a,reverse:f,...u}={},c={}){t=xy(t),e=xy(e),u=Q5(u,c),o=vy(o,wt),a=a==null?void 0:by("sort",a,c),i=i==null?void 0:wy("filter",i,c),n!=null&&Sa(u,"x","x1","x2")&&(n=null),r!=null&&Sa(u,"y","y1","y2")&&(r=null);let[s,d]=Te(t),[l,p]=Te(t),[m,h]=Te(e),[g,b]=Te(e),[x,M]=n!=null?[n,"x"]:r!=null?[r,"y"]:[],[y,w]=Te(x),{x:v,y:T,z:E,fill:I,stroke:F,x1:_,x2:R,y1:O,y2:L,domain:S,cumulative:A,thresholds:N,interval:P,...D}=c,[tt,Z]=Te(E),[W]=ie(I),[Q]=ie(F),[at,Ut]=Te(W),[Fe,G]=Te(Q);return{..."z"in c&&{z:tt||E},..."fill"in c&&{fill:at||I},..."stroke"in c&&{stroke:Fe||F},...$o(D,(X,$,q)=>{let z=va(gt(X,x),q?.[M]),C=gt(X,E),j=gt(X,W),J=gt(X,Q),ot=t1(u,{z:C,fill:j,stroke:J}),lt=[],Xt=[],Zn=z&&w([]),Ra=C&&Z([]),La=j&&Ut([]),Pa=J&&G([]),Oa=t&&d([]),fs=t&&p([]),Pp=e&&h([]),Ny=e&&b([]),Dy=n3(t?.(X),e?.(X)),Ry=0;for(let Dr of u)Dr.initialize(X);a&&a.initialize(X),i&&i.initialize(X);for(let Dr of $){let Op=[];for(let Xo of u)Xo.scope("facet",Dr);a&&a.scope("facet",Dr),i&&i.scope("facet",Dr);for(let[Xo,Ly]of Rd(Dr,ot))for(let[Py,Oy]of Rd(Ly,z))for(let[Kn,Qn]of Dy(Oy))if(!(i&&!i.reduce(Kn,Qn))){Op.push(Ry++),Xt.push(o.reduceIndex(Kn,X,Qn)),z&&Zn.push(Py),C&&Ra.push(ot===C?Xo:C[Kn[0]]),j&&La.push(ot===j?Xo:j[Kn[0]]),J&&Pa.push(ot===J?Xo:J[Kn[0]]),Oa&&(Oa.push(Qn.x1),fs.push(Qn.x2)),Pp&&(Pp.push(Qn.y1),Ny.push(Qn.y2));for(let Fy of u)Fy.reduce(Kn,Qn);a&&a.reduce(Kn)}lt.push(Op)}return e1(lt,a,f),{data:Xt,facets:lt}}),...!Sa(u,"x")&&(s?{x1:s,x2:l,x:wa(s,l)}:{x:v,x1:_,x2:R}),...!Sa(u,"y")&&(m?{y1:m,y2:g,y:wa(m,g)}:{y:T,y1:O,y2:L}),...y&&{[M]:y},...Object.fromEntries(u.map(({name:X,output:$})=>[X,$]))}}function j5({cumulative:t,domain:e,thresholds:n,interval:r,...o},i){return[o,{cumulative:t,domain:e,thresholds:n,interval:r,...i}]}function Z5(t,{cumulative:e,domain:n,thresholds:r,interval:o},i){return t={...on(t)},t.domain===void 0&&(t.domain=n),t.cumulative===void 0&&(t.cumulative=e),t.thresholds===void 0&&(t.thresholds=r),t.interval===void 0&&(t.interval=o),t.value===void 0&&(t.value=i),t.thresholds=K5(t.thresholds,t.interval),t}function xy(t){if(t==null)return;let{value:e,cumulative:n,domain:r=Ct,thresholds:o}=t,i=a=>{let f=gt(a,e),u;if(Yt(f)||t3(o)){f=pt(f,Sd,Float64Array);let[s,d]=typeof r=="function"?r(f):r,l=typeof o=="function"&&!Da(o)?o(f,s,d):o;typeof l=="number"&&(l=Wi(s,d,l)),Da(l)&&(r===Ct&&(s=l.floor(s),d=l.offset(l.floor(d))),l=l.range(s,l.offset(d))),u=l}else{f=Tr(f);let[s,d]=typeof r=="function"?r(f):r,l=typeof o=="function"&&!Da(o)?o(f,s,d):o;if(typeof l=="number")if(r===Ct){let p=tr(s,d,l);if(isFinite(p))if(p>0){let m=Math.round(s/p),h=Math.round(d/p);m*p<=s||--m,h*p>d||++h;let g=h-m+1;l=new Float64Array(g);for(let b=0;b<g;++b)l[b]=(m+b)*p}else if(p<0){p=-p;let m=Math.round(s*p),h=Math.round(d*p);m/p<=s||--m,h/p>d||++h;let g=h-m+1;l=new Float64Array(g);for(let b=0;b<g;++b)l[b]=(m+b)/p}else l=[s];else l=[s]}else l=$e(s,d,l);else Da(l)&&(r===Ct&&(s=l.floor(s),d=l.offset(l.floor(d))),l=l.range(s,l.offset(d)));u=l}let c=[];if(u.length===1)c.push([u[0],u[0]]);else for(let s=1;s<u.length;++s)c.push([u[s-1],u[s]]);return c.bin=(n<0?o3:n>0?r3:Rp)(c,u,f),c};return i.label=Le(e),i}function K5(t,e,n=yy){if(t===void 0)return e===void 0?n:rn(e);if(typeof t=="string"){switch(t.toLowerCase()){case"freedman-diaconis":return Ha;case"scott":return Zo;case"sturges":return Ya;case"auto":return yy}return $c(t)}return t}function Q5(t,e){return Jg(t,e,by)}function by(t,e,n){return Nd(t,e,n,wy)}function wy(t,e,n){return Dd(t,e,n,vy)}function vy(t,e){return Ta(t,e,J5)}function J5(t){switch(`${t}`.toLowerCase()){case"x":return i3;case"x1":return f3;case"x2":return u3;case"y":return a3;case"y1":return c3;case"y2":return s3}throw new Error(`invalid bin reduce: ${t}`)}function yy(t,e,n){return Math.min(200,Zo(t,e,n))}function t3(t){return e3(t)||re(t)&&Yt(t)}function e3(t){return Da(t)&&typeof t=="function"&&t()instanceof Date}function Da(t){return typeof t?.range=="function"}function n3(t,e){return t&&e?function*(n){let r=t.bin(n);for(let[o,[i,a]]of t.entries()){let f=e.bin(r[o]);for(let[u,[c,s]]of e.entries())yield[f[u],{x1:i,y1:c,x2:a,y2:s}]}}:t?function*(n){let r=t.bin(n);for(let[o,[i,a]]of t.entries())yield[r[o],{x1:i,x2:a}]}:function*(n){let r=e.bin(n);for(let[o,[i,a]]of e.entries())yield[r[o],{y1:i,y2:a}]}}function Rp(t,e,n){return e=Tr(e),r=>{let o=t.map(()=>[]);for(let i of r)o[un(e,n[i])-1]?.push(i);return o}}function r3(t,e,n){let r=Rp(t,e,n);return o=>{let i=r(o);for(let a=1,f=i.length;a<f;++a){let u=i[a-1],c=i[a];for(let s of u)c.push(s)}return i}}function o3(t,e,n){let r=Rp(t,e,n);return o=>{let i=r(o);for(let a=i.length-2;a>=0;--a){let f=i[a+1],u=i[a];for(let c of f)u.push(c)}return i}}function My(t,e){let n=(+t+ +e)/2;return t instanceof Date?new Date(n):n}var i3={reduceIndex(t,e,{x1:n,x2:r}){return My(n,r)}},a3={reduceIndex(t,e,{y1:n,y2:r}){return My(n,r)}},f3={reduceIndex(t,e,{x1:n}){return n}},u3={reduceIndex(t,e,{x2:n}){return n}},c3={reduceIndex(t,e,{y1:n}){return n}},s3={reduceIndex(t,e,{y2:n}){return n}};function Sy(t={}){return Td(t)?t:{...t,y:wt}}function l3(t={},e={}){arguments.length===1&&([t,e]=d3(t));let{x1:n,x:r=n,y:o,...i}=e,[a,f,u,c]=m3(r,o,"x","y",t,i);return{...a,x1:n,x:f,y1:u,y2:c,y:wa(u,c)}}function Cy({y:t,y1:e,y2:n,...r}={}){return r=Ea(r,"x"),e===void 0&&n===void 0?l3({y:t,...r}):([e,n]=Dg(t,e,n),{...r,y1:e,y2:n})}function d3(t){let{offset:e,order:n,reverse:r,...o}=t;return[{offset:e,order:n,reverse:r},o]}var p3={length:!0};function m3(t,e=Ag,n,r,{offset:o,order:i,reverse:a},f){let u=Rg(f),[c,s]=Te(t),[d,l]=wo(e),[p,m]=wo(e);return d.hint=p.hint=p3,o=h3(o),i=y3(i,o,r),[$o(f,(h,g,b)=>{let x=t==null?void 0:s(va(gt(h,t),b?.[n])),M=gt(h,e,Float64Array),y=gt(h,u),w=i&&i(h,x,M,y),v=h.length,T=l(new Float64Array(v)),E=m(new Float64Array(v)),I=[];for(let F of g){let _=x?Array.from(cn(F,R=>x[R]).values()):[F];if(w)for(let R of _)R.sort(w);for(let R of _){let O=0,L=0;a&&R.reverse();for(let S of R){let A=M[S];A<0?O=E[S]=(T[S]=O)+A:A>0?L=E[S]=(T[S]=L)+A:E[S]=T[S]=L}}I.push(_)}return o&&o(I,T,E,y),{data:h,facets:g}}),c,d,p]}function h3(t){if(t!=null){if(typeof t=="function")return t;switch(`${t}`.toLowerCase()){case"expand":case"normalize":return g3;case"center":case"silhouette":return x3;case"wiggle":return $y}throw new Error(`unknown offset: ${t}`)}}function Ay(t,e){let n=0,r=0;for(let o of t){let i=e[o];i<n&&(n=i),i>r&&(r=i)}return[n,r]}function g3(t,e,n){for(let r of t)for(let o of r){let[i,a]=Ay(o,n);for(let f of o){let u=1/(a-i||1);e[f]=u*(e[f]-i),n[f]=u*(n[f]-i)}}}function x3(t,e,n){for(let r of t){for(let o of r){let[i,a]=Ay(o,n);for(let f of o){let u=(a+i)/2;e[f]-=u,n[f]-=u}}Ey(r,e,n)}Iy(t,e,n)}function $y(t,e,n,r){for(let o of t){let i=new Qt,a=0;for(let f of o){let u=-1,c=f.map(p=>Math.abs(n[p]-e[p])),s=f.map(p=>{u=r?r[p]:++u;let m=n[p]-e[p],h=i.has(u)?m-i.get(u):0;return i.set(u,m),h}),d=[0,...qa(s)];for(let p of f)e[p]+=a,n[p]+=a;let l=fe(c);l&&(a-=fe(c,(p,m)=>(s[m]/2+d[m])*p)/l)}Ey(o,e,n)}Iy(t,e,n)}function Ey(t,e,n){let r=Jt(t,o=>Jt(o,i=>e[i]));for(let o of t)for(let i of o)e[i]-=r,n[i]-=r}function Iy(t,e,n){let r=t.length;if(r===1)return;let o=t.map(f=>f.flat()),i=o.map(f=>(Jt(f,u=>e[u])+Ht(f,u=>n[u]))/2),a=Jt(i);for(let f=0;f<r;f++){let u=a-i[f];for(let c of o[f])e[c]+=u,n[c]+=u}}function y3(t,e,n){if(t===void 0&&e===$y)return Ty(Kt);if(t!=null){if(typeof t=="string"){let r=t.startsWith("-"),o=r?Mr:Kt;switch((r?t.slice(1):t).toLowerCase()){case"value":case n:return b3(o);case"z":return w3(o);case"sum":return v3(o);case"appearance":return M3(o);case"inside-out":return Ty(o)}return _y(vd(t))}if(typeof t=="function")return(t.length===1?_y:S3)(t);if(Array.isArray(t))return T3(t);throw new Error(`invalid order: ${t}`)}}function b3(t){return(e,n,r)=>(o,i)=>t(r[o],r[i])}function w3(t){return(e,n,r,o)=>(i,a)=>t(o[i],o[a])}function v3(t){return os(t,(e,n,r,o)=>Pr(ne(e),i=>fe(i,a=>r[a]),i=>o[i]))}function M3(t){return os(t,(e,n,r,o)=>Pr(ne(e),i=>n[jo(i,a=>r[a])],i=>o[i]))}function Ty(t){return os(t,(e,n,r,o)=>{let i=ne(e),a=Pr(i,d=>n[jo(d,l=>r[l])],d=>o[d]),f=Ae(i,d=>fe(d,l=>r[l]),d=>o[d]),u=[],c=[],s=0;for(let d of a)s<0?(s+=f.get(d),u.push(d)):(s-=f.get(d),c.push(d));return c.reverse().concat(u)})}function _y(t){return e=>{let n=gt(e,t);return(r,o)=>Kt(n[r],n[o])}}function S3(t){return e=>(n,r)=>t(e[n],e[r])}function T3(t){return os(Kt,()=>t)}function os(t,e){return(n,r,o,i)=>{if(!i)throw new Error("missing channel: z");let a=new Qt(e(n,r,o,i).map((f,u)=>[f,u]));return(f,u)=>t(a.get(i[f]),a.get(i[u]))}}var Lp=class extends Nt{constructor(e,n,r={},o){super(e,n,r,o);let{inset:i=0,insetTop:a=i,insetRight:f=i,insetBottom:u=i,insetLeft:c=i,rx:s,ry:d}=r;this.insetTop=ct(a),this.insetRight=ct(f),this.insetBottom=ct(u),this.insetLeft=ct(c),this.rx=St(s,"auto"),this.ry=St(d,"auto")}render(e,n,r,o,i){let{rx:a,ry:f}=this;return yt("svg:g",i).call(me,this,o,i).call(this._transform,this,n).call(u=>u.selectAll().data(e).enter().append("rect").call(he,this).attr("x",this._x(n,r,o)).attr("width",this._width(n,r,o)).attr("y",this._y(n,r,o)).attr("height",this._height(n,r,o)).call(et,"rx",a).call(et,"ry",f).call(Oe,this,r)).node()}_x(e,{x:n},{marginLeft:r}){let{insetLeft:o}=this;return n?i=>n[i]+o:r+o}_y(e,{y:n},{marginTop:r}){let{insetTop:o}=this;return n?i=>n[i]+o:r+o}_width({x:e},{x:n},{marginRight:r,marginLeft:o,width:i}){let{insetLeft:a,insetRight:f}=this,u=n&&e?e.bandwidth():i-r-o;return Math.max(0,u-a-f)}_height({y:e},{y:n},{marginTop:r,marginBottom:o,height:i}){let{insetTop:a,insetBottom:f}=this,u=n&&e?e.bandwidth():i-r-o;return Math.max(0,u-a-f)}},_3={ariaLabel:"bar"};var is=class extends Lp{constructor(e,n={}){let{x:r,y1:o,y2:i}=n;super(e,{y1:{value:o,scale:"y"},y2:{value:i,scale:"y"},x:{value:r,scale:"x",type:"band",optional:!0}},n,_3)}_transform(e,n,{y:r}){e.call(ge,n,{y:r},0,0)}_y({y:e},{y1:n,y2:r},{marginTop:o}){let{insetTop:i}=this;return Wn(e)?o+i:a=>Math.min(n[a],r[a])+i}_height({y:e},{y1:n,y2:r},{marginTop:o,marginBottom:i,height:a}){let{insetTop:f,insetBottom:u}=this;return Wn(e)?a-o-i-f-u:c=>Math.max(0,Math.abs(r[c]-n[c])-f-u)}};function as(t,e={}){return Ng(e)||(e={...e,x:Ec,y2:wt}),new is(t,Cy(Gc(Sy(e))))}function ky(t){let e=t.querySelectorAll(".js-Tree-heading"),n=()=>{let o=[];for(let u of e){let c=u.getBoundingClientRect();c.height&&c.top<80&&o.unshift(u)}o.length==0&&e[0]instanceof HTMLHeadingElement&&(o=[e[0]]);let i=1/0,a=[];for(let u of o){let c=Number(u.tagName[1]);c<i&&(i=c,a.push(u))}let f=t.querySelectorAll(".js-Tree-item");for(let u of f){let c=u.dataset.headingId,s=!1,d=!1;for(let l of a)if(l.id===c){l===a[0]?s=!0:d=!0;break}u.setAttribute("aria-selected",s?"true":"false"),u.setAttribute("aria-expanded",d?"true":"false")}},r=new IntersectionObserver(A3(n,20));for(let o of e)r.observe(o)}function A3(t,e){let n;return(...r)=>{clearTimeout(n),n=setTimeout(()=>t(...r),e)}}for(let t of Page.Charts?.Programs||[])for(let e of t?.Charts||[])switch(e.Type){case"partition":document.querySelector(`[data-chart-id="${e.ID}"]`)?.append($3(e));break;case"histogram":document.querySelector(`[data-chart-id="${e.ID}"]`)?.append(E3(e));break;default:console.error("unknown chart type");break}for(let t of document.querySelectorAll(".js-Tree"))ky(t);function $3({Data:t,Name:e}){t??=[];let n=t.map(r=>r.Value).reduce((r,o)=>Math.max(r,o),0);return Na({color:{type:"categorical",scheme:"set2"},nice:!0,x:{label:e,labelOffset:Number.MAX_SAFE_INTEGER,tickRotate:45,domain:t.map(r=>r.Key)},y:{label:"Reports",domain:[0,n+1]},width:1024,style:{overflow:"visible",background:"transparent",marginBottom:"3rem",fontSize:"0.8rem",marginTop:"1rem"},insetTop:20,marks:[as(t,{tip:!0,fill:r=>isNaN(Number(r.Key))?r.Key:Number(r.Key),x:r=>r.Key,y:r=>r.Value}),Uo()]})}function E3({Data:t}){t??=[];let e=3,n=f=>isNaN(Number(f))?f:Number(f),r=Array.from(ja(t.map(f=>n(f.Key)))),o=new Map(r.map((f,u)=>[f,u])),i=f=>(o.get(f)??0)%e,a=f=>Math.floor((o.get(f)??0)/e);return Na({marginLeft:60,width:1024,grid:!0,nice:!0,x:{label:"Distribution"},color:{type:"ordinal",legend:!0,scheme:"Spectral",label:"Counter"},y:{insetTop:16,domain:[0,1]},fx:{ticks:[]},fy:{ticks:[]},style:"background:transparent;",marks:[as(t,Dp({y:"proportion-facet",x:"x1",interval:.1,cumulative:1},{tip:!0,fill:f=>n(f.Key),x:f=>f.Value,fx:f=>i(n(f.Key)),fy:f=>a(n(f.Key))})),qo(r,{frameAnchor:"top",dy:3,fx:i,fy:a}),Nr({anchor:"bottom",tickSpacing:35}),Nr({anchor:"top",tickSpacing:35}),Uo()]})}})();
+}`)).call(Yo,r);for(let $ of f){let{channels:N,values:L,facets:D}=m.get($);if(l===void 0||$.facet==="super"){let tt=null;if(D&&(tt=D[0],tt=$.filter(tt,N,L),tt.length===0))continue;let K=$.render(tt,h,L,y,w);if(K==null)continue;T.appendChild(K)}else{let tt;for(let K of l){if(!($.facetAnchor?.(l,q,K)??!K.empty))continue;let G=null;if(D){let at=c.has($);if(G=D[at?K.i:0],G=$.filter(G,N,L),G.length===0)continue;!at&&G===D[0]&&(G=bg(G)),G.fx=K.x,G.fy=K.y,G.fi=K.i}let Q=$.render(G,h,L,M,w);if(Q!=null){(tt??=Ie(T).append("g")).append(()=>Q).datum(K);for(let at of["aria-label","aria-description","aria-hidden","transform"])Q.hasAttribute(at)&&(tt.attr(at,Q.getAttribute(at)),Q.removeAttribute(at))}}tt?.selectChildren().attr("transform",_)}}let P=ey(d,w,t);if(n!=null||P.length>0){E=v.createElement("figure"),E.style.maxWidth="initial";for(let $ of P)E.appendChild($);if(E.appendChild(T),n!=null){let $=v.createElement("figcaption");$.appendChild(n?.ownerDocument?n:v.createTextNode(n)),E.appendChild($)}}E.scale=Y1(d),E.legend=Qx(d,w,t);let S=Xg();return S>0&&Ie(T).append("text").attr("x",R).attr("y",20).attr("dy","-1em").attr("text-anchor","end").attr("font-family","initial").text("\u26A0\uFE0F").append("title").text(`${S.toLocaleString("en-US")} warning${S===1?"":"s"}. Please check the console.`),E}function pv({marks:t=[],...e}={}){return Fn({...e,marks:[...t,this]})}Nt.prototype.plot=pv;function oy(t){return t.flat(1/0).filter(e=>e!=null).map(mv)}function mv(t){return typeof t.render=="function"?t:new Cm(t)}var Cm=class extends Nt{constructor(e){if(typeof e!="function")throw new TypeError("invalid mark; missing render function");super(),this.render=e}render(){}};function $m(t,e){for(let r in t)uy(t[r],e);return t}function uy(t,e){let{scale:r,transform:n=!0}=t;if(r==null||!n)return;let{type:o,percent:i,interval:a,transform:f=i?u=>u*100:$p(a,o)}=e[r]??{};f!=null&&(t.value=mt(t.value,f),t.transform=!1)}function dv(t){for(let e in t)Op(e,t[e])}function La(t,e,r,n=pg){for(let{channels:o}of e.values())for(let i in o){let a=o[i],{scale:f}=a;if(f!=null&&n(f))if(f==="projection"){if(!t1(r)){let u=r.x?.domain===void 0,c=r.y?.domain===void 0;if(u||c){let[s,p]=r1(a);u&&_m(t,"x",s),c&&_m(t,"y",p)}}}else _m(t,f,a)}return t}function _m(t,e,r){let n=t.get(e);n!==void 0?n.push(r):t.set(e,[r])}function hv(t,e){if(t==null)return;let{x:r,y:n}=t;if(r==null&&n==null)return;let o=It(t.data);if(o==null)throw new Error("missing facet data");let i={};r!=null&&(i.fx=No(o,{value:r,scale:"fx"})),n!=null&&(i.fy=No(o,{value:n,scale:"fy"})),$m(i,e);let a=Qc(o,i);return{channels:i,groups:a,data:t.data}}function iy(t,e,r){if(t.facet===null||t.facet==="super")return;let{fx:n,fy:o}=t;if(n!=null||o!=null){let u=It(t.data??n??o);if(u===void 0)throw new Error(`missing facet data in ${t.ariaLabel}`);if(u===null)return;let c={};return n!=null&&(c.fx=No(u,{value:n,scale:"fx"})),o!=null&&(c.fy=No(u,{value:o,scale:"fy"})),$m(c,r),{channels:c,groups:Qc(u,c)}}if(e===void 0)return;let{channels:i,groups:a,data:f}=e;if(t.facet!=="auto"||t.data===f)return{channels:i,groups:a};f.length>0&&(a.size>1||a.size===1&&i.fx&&i.fy&&[...a][0][1].size>1)&&It(t.data)?.length===f.length&&cr(`Warning: the ${t.ariaLabel} mark appears to use faceted data, but isn\u2019t faceted. The mark data has the same length as the facet data and the mark facet option is "auto", but the mark data and facet data are distinct. If this mark should be faceted, set the mark facet option to true; otherwise, suppress this warning by setting the mark facet option to false.`)}function gv(t,e={}){return Dn({...e,x:null,y:null},(r,n,o,i,a,f)=>f.getMarkState(t))}function xv(t){let e=[];for(let r of t){let n=r.tip;if(n){let i=(n==="x"?tx:n==="y"?ex:J1)(gv(r));i.title=null,e.push(ny(r.data,i))}}return e}function yv(t,e,r){let{projection:n,x:o={},y:i={},fx:a={},fy:f={},axis:u,grid:c,facet:s={},facet:{axis:p=u,grid:l}=s,x:{axis:m=u,grid:d=m===null?null:c}=o,y:{axis:h=u,grid:g=h===null?null:c}=i,fx:{axis:b=p,grid:x=b===null?null:l}=a,fy:{axis:M=p,grid:y=M===null?null:l}=f}=r;(n||!vo(o)&&!ay("x",t))&&(m=d=null),(n||!vo(i)&&!ay("y",t))&&(h=g=null),e.has("fx")||(b=x=null),e.has("fy")||(M=y=null),m===void 0&&(m=!ls(t,"x")),h===void 0&&(h=!ls(t,"y")),b===void 0&&(b=!ls(t,"fx")),M===void 0&&(M=!ls(t,"fy")),m===!0&&(m="bottom"),h===!0&&(h="left"),b===!0&&(b=m==="top"||m===null?"bottom":"top"),M===!0&&(M=h==="right"||h===null?"left":"right");let w=[];return ss(w,y,Fx,f),cs(w,M,Nx,"right","left",s,f),ss(w,x,Ox,a),cs(w,b,Dx,"top","bottom",s,a),ss(w,g,Lx,i),cs(w,h,bm,"left","right",r,i),ss(w,d,qx,o),cs(w,m,Ln,"bottom","top",r,o),w}function cs(t,e,r,n,o,i,a){if(!e)return;let f=bv(e);a=wv(f?n:e,i,a);let{line:u}=a;(r===bm||r===Ln)&&u&&!Nn(u)&&t.push(Zr(vv(a))),t.push(r(a)),f&&t.push(r({...a,anchor:o,label:null}))}function ss(t,e,r,n){!e||Nn(e)||t.push(r(Mv(e,n)))}function bv(t){return/^\s*both\s*$/i.test(t)}function wv(t,e,{line:r=e.line,ticks:n,tickSize:o,tickSpacing:i,tickPadding:a,tickFormat:f,tickRotate:u,fontVariant:c,ariaLabel:s,ariaDescription:p,label:l=e.label,labelAnchor:m,labelArrow:d=e.labelArrow,labelOffset:h}){return{anchor:t,line:r,ticks:n,tickSize:o,tickSpacing:i,tickPadding:a,tickFormat:f,tickRotate:u,fontVariant:c,ariaLabel:s,ariaDescription:p,label:l,labelAnchor:m,labelArrow:d,labelOffset:h}}function vv(t){let{anchor:e,line:r}=t;return{anchor:e,facetAnchor:e+"-empty",stroke:r===!0?void 0:r}}function Mv(t,{stroke:e=To(t)?t:void 0,ticks:r=Sv(t)?t:void 0,tickSpacing:n,ariaLabel:o,ariaDescription:i}){return{stroke:e,ticks:r,tickSpacing:n,ariaLabel:o,ariaDescription:i}}function Sv(t){switch(typeof t){case"number":return!0;case"string":return!To(t)}return ie(t)||typeof t?.range=="function"}function ls(t,e){let r=`${e}-axis `;return t.some(n=>n.ariaLabel?.startsWith(r))}function ay(t,e){for(let r of e)for(let n in r.channels){let{scale:o}=r.channels[n];if(o===t||o==="projection")return!0}return!1}function Tv(t,e){for(let r in t){let n=t[r],o=e[r];n.label===void 0&&o&&(n.label=o.label)}return t}function _v({fx:t,fy:e},r){let{marginTop:n,marginRight:o,marginBottom:i,marginLeft:a,width:f,height:u}=Vc(r),c=t&&fy(t),s=e&&fy(e);return{marginTop:e?s[0]:n,marginRight:t?f-c[1]:o,marginBottom:e?u-s[1]:i,marginLeft:t?c[0]:a,inset:{marginTop:r.marginTop,marginRight:r.marginRight,marginBottom:r.marginBottom,marginLeft:r.marginLeft},width:f,height:u}}function fy(t){let e=t.domain(),r=t(e[0]),n=t(e[e.length-1]);return n<r&&([r,n]=[n,r]),[r,n+t.bandwidth()]}function Am(t={y:"count"},e={}){[t,e]=$v(t,e);let{x:r,y:n}=e;return Cv(Av(r,e,vt),null,null,n,t,Ra(e))}function Cv(t,e,r,n,{data:o=Lp,filter:i=Ca,sort:a,reverse:f,...u}={},c={}){t=cy(t),e=cy(e),u=kv(u,c),o=my(o,vt),a=a==null?void 0:ly("sort",a,c),i=i==null?void 0:py("filter",i,c),r!=null&&$a(u,"x","x1","x2")&&(r=null),n!=null&&$a(u,"y","y1","y2")&&(n=null);let[s,p]=Ce(t),[l,m]=Ce(t),[d,h]=Ce(e),[g,b]=Ce(e),[x,M]=r!=null?[r,"x"]:n!=null?[n,"y"]:[],[y,w]=Ce(x),{x:v,y:T,z:E,fill:k,stroke:q,x1:_,x2:R,y1:F,y2:P,domain:S,cumulative:$,thresholds:N,interval:L,...D}=c,[tt,K]=Ce(E),[G]=ae(k),[Q]=ae(q),[at,Ut]=Ce(G),[Be,X]=Ce(Q);return{..."z"in c&&{z:tt||E},..."fill"in c&&{fill:at||k},..."stroke"in c&&{stroke:Be||q},...Io(D,(W,A,O)=>{let Y=_a(gt(W,x),O?.[M]),C=gt(W,E),j=gt(W,G),J=gt(W,Q),ot=qg(u,{z:C,fill:j,stroke:J}),lt=[],Wt=[],Qr=Y&&w([]),Oa=C&&K([]),Ya=j&&Ut([]),Ba=J&&X([]),za=t&&p([]),ds=t&&m([]),Dm=e&&h([]),Ey=e&&b([]),ky=Rv(t?.(W),e?.(W)),Iy=0;for(let qn of u)qn.initialize(W);a&&a.initialize(W),i&&i.initialize(W);for(let qn of A){let Rm=[];for(let Ho of u)Ho.scope("facet",qn);a&&a.scope("facet",qn),i&&i.scope("facet",qn);for(let[Ho,Ny]of Pp(qn,ot))for(let[Dy,Ry]of Pp(Ny,Y))for(let[Jr,tn]of ky(Ry))if(!(i&&!i.reduce(Jr,tn))){Rm.push(Iy++),Wt.push(o.reduceIndex(Jr,W,tn)),Y&&Qr.push(Dy),C&&Oa.push(ot===C?Ho:C[Jr[0]]),j&&Ya.push(ot===j?Ho:j[Jr[0]]),J&&Ba.push(ot===J?Ho:J[Jr[0]]),za&&(za.push(tn.x1),ds.push(tn.x2)),Dm&&(Dm.push(tn.y1),Ey.push(tn.y2));for(let Py of u)Py.reduce(Jr,tn);a&&a.reduce(Jr)}lt.push(Rm)}return Og(lt,a,f),{data:Wt,facets:lt}}),...!$a(u,"x")&&(s?{x1:s,x2:l,x:Ta(s,l)}:{x:v,x1:_,x2:R}),...!$a(u,"y")&&(d?{y1:d,y2:g,y:Ta(d,g)}:{y:T,y1:F,y2:P}),...y&&{[M]:y},...Object.fromEntries(u.map(({name:W,output:A})=>[W,A]))}}function $v({cumulative:t,domain:e,thresholds:r,interval:n,...o},i){return[o,{cumulative:t,domain:e,thresholds:r,interval:n,...i}]}function Av(t,{cumulative:e,domain:r,thresholds:n,interval:o},i){return t={...ur(t)},t.domain===void 0&&(t.domain=r),t.cumulative===void 0&&(t.cumulative=e),t.thresholds===void 0&&(t.thresholds=n),t.interval===void 0&&(t.interval=o),t.value===void 0&&(t.value=i),t.thresholds=Ev(t.thresholds,t.interval),t}function cy(t){if(t==null)return;let{value:e,cumulative:r,domain:n=Ct,thresholds:o}=t,i=a=>{let f=gt(a,e),u;if(Bt(f)||Nv(o)){f=mt(f,Tp,Float64Array);let[s,p]=typeof n=="function"?n(f):n,l=typeof o=="function"&&!Fa(o)?o(f,s,p):o;typeof l=="number"&&(l=ji(s,p,l)),Fa(l)&&(n===Ct&&(s=l.floor(s),p=l.offset(l.floor(p))),l=l.range(s,l.offset(p))),u=l}else{f=An(f);let[s,p]=typeof n=="function"?n(f):n,l=typeof o=="function"&&!Fa(o)?o(f,s,p):o;if(typeof l=="number")if(n===Ct){let m=rn(s,p,l);if(isFinite(m))if(m>0){let d=Math.round(s/m),h=Math.round(p/m);d*m<=s||--d,h*m>p||++h;let g=h-d+1;l=new Float64Array(g);for(let b=0;b<g;++b)l[b]=(d+b)*m}else if(m<0){m=-m;let d=Math.round(s*m),h=Math.round(p*m);d/m<=s||--d,h/m>p||++h;let g=h-d+1;l=new Float64Array(g);for(let b=0;b<g;++b)l[b]=(d+b)/m}else l=[s];else l=[s]}else l=ke(s,p,l);else Fa(l)&&(n===Ct&&(s=l.floor(s),p=l.offset(l.floor(p))),l=l.range(s,l.offset(p)));u=l}let c=[];if(u.length===1)c.push([u[0],u[0]]);else for(let s=1;s<u.length;++s)c.push([u[s-1],u[s]]);return c.bin=(r<0?Lv:r>0?Pv:Em)(c,u,f),c};return i.label=qe(e),i}function Ev(t,e,r=sy){if(t===void 0)return e===void 0?r:fr(e);if(typeof t=="string"){switch(t.toLowerCase()){case"freedman-diaconis":return Va;case"scott":return Zo;case"sturges":return Ga;case"auto":return sy}return qc(t)}return t}function kv(t,e){return Fg(t,e,ly)}function ly(t,e,r){return Dp(t,e,r,py)}function py(t,e,r){return Rp(t,e,r,my)}function my(t,e){return Aa(t,e,Iv)}function Iv(t){switch(`${t}`.toLowerCase()){case"x":return Fv;case"x1":return Ov;case"x2":return Yv;case"y":return qv;case"y1":return Bv;case"y2":return zv}throw new Error(`invalid bin reduce: ${t}`)}function sy(t,e,r){return Math.min(200,Zo(t,e,r))}function Nv(t){return Dv(t)||ie(t)&&Bt(t)}function Dv(t){return Fa(t)&&typeof t=="function"&&t()instanceof Date}function Fa(t){return typeof t?.range=="function"}function Rv(t,e){return t&&e?function*(r){let n=t.bin(r);for(let[o,[i,a]]of t.entries()){let f=e.bin(n[o]);for(let[u,[c,s]]of e.entries())yield[f[u],{x1:i,y1:c,x2:a,y2:s}]}}:t?function*(r){let n=t.bin(r);for(let[o,[i,a]]of t.entries())yield[n[o],{x1:i,x2:a}]}:function*(r){let n=e.bin(r);for(let[o,[i,a]]of e.entries())yield[n[o],{y1:i,y2:a}]}}function Em(t,e,r){return e=An(e),n=>{let o=t.map(()=>[]);for(let i of n)o[lr(e,r[i])-1]?.push(i);return o}}function Pv(t,e,r){let n=Em(t,e,r);return o=>{let i=n(o);for(let a=1,f=i.length;a<f;++a){let u=i[a-1],c=i[a];for(let s of u)c.push(s)}return i}}function Lv(t,e,r){let n=Em(t,e,r);return o=>{let i=n(o);for(let a=i.length-2;a>=0;--a){let f=i[a+1],u=i[a];for(let c of f)u.push(c)}return i}}function dy(t,e){let r=(+t+ +e)/2;return t instanceof Date?new Date(r):r}var Fv={reduceIndex(t,e,{x1:r,x2:n}){return dy(r,n)}},qv={reduceIndex(t,e,{y1:r,y2:n}){return dy(r,n)}},Ov={reduceIndex(t,e,{x1:r}){return r}},Yv={reduceIndex(t,e,{x2:r}){return r}},Bv={reduceIndex(t,e,{y1:r}){return r}},zv={reduceIndex(t,e,{y2:r}){return r}};function hy(t={}){return _p(t)?t:{...t,y:vt}}function Uv(t={},e={}){arguments.length===1&&([t,e]=Wv(t));let{x1:r,x:n=r,y:o,...i}=e,[a,f,u,c]=Gv(n,o,"x","y",t,i);return{...a,x1:r,x:f,y1:u,y2:c,y:Ta(u,c)}}function yy({y:t,y1:e,y2:r,...n}={}){return n=Da(n,"x"),e===void 0&&r===void 0?Uv({y:t,...n}):([e,r]=xg(t,e,r),{...n,y1:e,y2:r})}function Wv(t){let{offset:e,order:r,reverse:n,...o}=t;return[{offset:e,order:r,reverse:n},o]}var Hv={length:!0};function Gv(t,e=lg,r,n,{offset:o,order:i,reverse:a},f){let u=yg(f),[c,s]=Ce(t),[p,l]=So(e),[m,d]=So(e);return p.hint=m.hint=Hv,o=Xv(o),i=Kv(i,o,n),[Io(f,(h,g,b)=>{let x=t==null?void 0:s(_a(gt(h,t),b?.[r])),M=gt(h,e,Float64Array),y=gt(h,u),w=i&&i(h,x,M,y),v=h.length,T=l(new Float64Array(v)),E=d(new Float64Array(v)),k=[];for(let q of g){let _=x?Array.from(pr(q,R=>x[R]).values()):[q];if(w)for(let R of _)R.sort(w);for(let R of _){let F=0,P=0;a&&R.reverse();for(let S of R){let $=M[S];$<0?F=E[S]=(T[S]=F)+$:$>0?P=E[S]=(T[S]=P)+$:E[S]=T[S]=P}}k.push(_)}return o&&o(k,T,E,y),{data:h,facets:g}}),c,p,m]}function Xv(t){if(t!=null){if(typeof t=="function")return t;switch(`${t}`.toLowerCase()){case"expand":case"normalize":return Vv;case"center":case"silhouette":return jv;case"wiggle":return wy}throw new Error(`unknown offset: ${t}`)}}function by(t,e){let r=0,n=0;for(let o of t){let i=e[o];i<r&&(r=i),i>n&&(n=i)}return[r,n]}function Vv(t,e,r){for(let n of t)for(let o of n){let[i,a]=by(o,r);for(let f of o){let u=1/(a-i||1);e[f]=u*(e[f]-i),r[f]=u*(r[f]-i)}}}function jv(t,e,r){for(let n of t){for(let o of n){let[i,a]=by(o,r);for(let f of o){let u=(a+i)/2;e[f]-=u,r[f]-=u}}vy(n,e,r)}My(t,e,r)}function wy(t,e,r,n){for(let o of t){let i=new Jt,a=0;for(let f of o){let u=-1,c=f.map(m=>Math.abs(r[m]-e[m])),s=f.map(m=>{u=n?n[m]:++u;let d=r[m]-e[m],h=i.has(u)?d-i.get(u):0;return i.set(u,d),h}),p=[0,...Wa(s)];for(let m of f)e[m]+=a,r[m]+=a;let l=ue(c);l&&(a-=ue(c,(m,d)=>(s[d]/2+p[d])*m)/l)}vy(o,e,r)}My(t,e,r)}function vy(t,e,r){let n=te(t,o=>te(o,i=>e[i]));for(let o of t)for(let i of o)e[i]-=n,r[i]-=n}function My(t,e,r){let n=t.length;if(n===1)return;let o=t.map(f=>f.flat()),i=o.map(f=>(te(f,u=>e[u])+Ht(f,u=>r[u]))/2),a=te(i);for(let f=0;f<n;f++){let u=a-i[f];for(let c of o[f])e[c]+=u,r[c]+=u}}function Kv(t,e,r){if(t===void 0&&e===wy)return gy(Qt);if(t!=null){if(typeof t=="string"){let n=t.startsWith("-"),o=n?Cn:Qt;switch((n?t.slice(1):t).toLowerCase()){case"value":case r:return Zv(o);case"z":return Qv(o);case"sum":return Jv(o);case"appearance":return t3(o);case"inside-out":return gy(o)}return xy(Mp(t))}if(typeof t=="function")return(t.length===1?xy:e3)(t);if(Array.isArray(t))return r3(t);throw new Error(`invalid order: ${t}`)}}function Zv(t){return(e,r,n)=>(o,i)=>t(n[o],n[i])}function Qv(t){return(e,r,n,o)=>(i,a)=>t(o[i],o[a])}function Jv(t){return ps(t,(e,r,n,o)=>zn(oe(e),i=>ue(i,a=>n[a]),i=>o[i]))}function t3(t){return ps(t,(e,r,n,o)=>zn(oe(e),i=>r[Wn(i,a=>n[a])],i=>o[i]))}function gy(t){return ps(t,(e,r,n,o)=>{let i=oe(e),a=zn(i,p=>r[Wn(p,l=>n[l])],p=>o[p]),f=Ee(i,p=>ue(p,l=>n[l]),p=>o[p]),u=[],c=[],s=0;for(let p of a)s<0?(s+=f.get(p),u.push(p)):(s-=f.get(p),c.push(p));return c.reverse().concat(u)})}function xy(t){return e=>{let r=gt(e,t);return(n,o)=>Qt(r[n],r[o])}}function e3(t){return e=>(r,n)=>t(e[r],e[n])}function r3(t){return ps(Qt,()=>t)}function ps(t,e){return(r,n,o,i)=>{if(!i)throw new Error("missing channel: z");let a=new Jt(e(r,n,o,i).map((f,u)=>[f,u]));return(f,u)=>t(a.get(i[f]),a.get(i[u]))}}var km=class extends Nt{constructor(e,r,n={},o){super(e,r,n,o);let{inset:i=0,insetTop:a=i,insetRight:f=i,insetBottom:u=i,insetLeft:c=i,rx:s,ry:p}=n;this.insetTop=ct(a),this.insetRight=ct(f),this.insetBottom=ct(u),this.insetLeft=ct(c),this.rx=St(s,"auto"),this.ry=St(p,"auto")}render(e,r,n,o,i){let{rx:a,ry:f}=this;return yt("svg:g",i).call(ge,this,o,i).call(this._transform,this,r).call(u=>u.selectAll().data(e).enter().append("rect").call(xe,this).attr("x",this._x(r,n,o)).attr("width",this._width(r,n,o)).attr("y",this._y(r,n,o)).attr("height",this._height(r,n,o)).call(et,"rx",a).call(et,"ry",f).call(Ye,this,n)).node()}_x(e,{x:r},{marginLeft:n}){let{insetLeft:o}=this;return r?i=>r[i]+o:n+o}_y(e,{y:r},{marginTop:n}){let{insetTop:o}=this;return r?i=>r[i]+o:n+o}_width({x:e},{x:r},{marginRight:n,marginLeft:o,width:i}){let{insetLeft:a,insetRight:f}=this,u=r&&e?e.bandwidth():i-n-o;return Math.max(0,u-a-f)}_height({y:e},{y:r},{marginTop:n,marginBottom:o,height:i}){let{insetTop:a,insetBottom:f}=this,u=r&&e?e.bandwidth():i-n-o;return Math.max(0,u-a-f)}},n3={ariaLabel:"bar"};var Im=class extends km{constructor(e,r={}){let{x:n,y1:o,y2:i}=r;super(e,{y1:{value:o,scale:"y"},y2:{value:i,scale:"y"},x:{value:n,scale:"x",type:"band",optional:!0}},r,n3)}_transform(e,r,{y:n}){e.call(ye,r,{y:n},0,0)}_y({y:e},{y1:r,y2:n},{marginTop:o}){let{insetTop:i}=this;return Xr(e)?o+i:a=>Math.min(r[a],n[a])+i}_height({y:e},{y1:r,y2:n},{marginTop:o,marginBottom:i,height:a}){let{insetTop:f,insetBottom:u}=this;return Xr(e)?a-o-i-f-u:c=>Math.max(0,Math.abs(n[c]-r[c])-f-u)}};function qa(t,e={}){return gg(e)||(e={...e,x:Oc,y2:vt}),new Im(t,yy(ns(hy(e))))}function Sy(t){let e=t.querySelectorAll(".js-Tree-heading"),r=()=>{let o=[];for(let u of e){let c=u.getBoundingClientRect();c.height&&c.top<80&&o.unshift(u)}o.length==0&&e[0]instanceof HTMLHeadingElement&&(o=[e[0]]);let i=1/0,a=[];for(let u of o){let c=Number(u.tagName[1]);c<i&&(i=c,a.push(u))}let f=t.querySelectorAll(".js-Tree-item");for(let u of f){let c=u.dataset.headingId,s=!1,p=!1;for(let l of a)if(l.id===c){l===a[0]?s=!0:p=!0;break}u.setAttribute("aria-selected",s?"true":"false"),u.setAttribute("aria-expanded",p?"true":"false")}},n=new IntersectionObserver(o3(r,20));for(let o of e)n.observe(o)}function o3(t,e){let r;return(...n)=>{clearTimeout(r),r=setTimeout(()=>t(...n),e)}}var Ty=["#e69f00","#56b4e9","#009e73","#f0e442","#0072b2","#d55e00","#cc79a7","#999999"],i3="telemetry.theme",a3="telemetry.palette",f3="telemetry-preferences",u3=window.matchMedia("(prefers-color-scheme: dark)");function c3(){let t=$y(i3);return t==="light"||t==="dark"?t:"system"}function _y(){return $y(a3)==="accessible"?"accessible":"default"}function Cy(t){document.addEventListener(f3,t),u3.addEventListener("change",()=>{c3()==="system"&&t()})}var s3=new Map;function $y(t){try{return localStorage.getItem(t)}catch{return s3.get(t)??null}}Ay();Cy(Ay);for(let t of document.querySelectorAll(".js-Tree"))Sy(t);function Ay(){for(let t of Page.Charts?.Programs||[])for(let e of t?.Charts||[]){let r=document.querySelector(`[data-chart-id="${e.ID}"]`);switch(e.Type){case"partition":r?.replaceChildren(l3(e));break;case"histogram":r?.replaceChildren(m3(e));break;case"stack":r?.replaceChildren(d3(e));break;case"trend":r?.replaceChildren(p3(e));break;default:console.error("unknown chart type");break}}}function ms(t){return _y()==="accessible"?{range:Ty}:{scheme:t}}function l3({Data:t,Name:e}){t??=[];let r=t.reduce((o,i)=>o+i.Value,0),n=t.map(o=>o.Value).reduce((o,i)=>Math.max(o,i),0);return Fn({ariaLabel:`${e} chart`,ariaDescription:`Bar chart of the number of reports (${r} in total) for each value of ${e}: `+t.map(o=>`${o.Key}: ${o.Value}`).join(", ")+".",color:{type:"categorical",...ms("set2")},nice:!0,x:{label:e,labelOffset:Number.MAX_SAFE_INTEGER,tickRotate:45,domain:t.map(o=>o.Key)},y:{label:"Reports",domain:[0,n+1]},width:1024,style:{overflow:"visible",background:"transparent",marginBottom:"3rem",fontSize:"0.8rem",marginTop:"1rem"},insetTop:20,marks:[qa(t,{tip:!0,fill:o=>isNaN(Number(o.Key))?o.Key:Number(o.Key),x:o=>o.Key,y:o=>o.Value}),Zr()]})}function p3({Data:t,Name:e}){t??=[];let r=t.map(n=>n.Value).reduce((n,o)=>Math.max(n,o),0);return Fn({ariaLabel:`${e} chart`,ariaDescription:`Bar chart of ${e} in each week: `+t.map(n=>`${n.Week}: ${n.Value}`).join(", ")+".",color:{type:"categorical",...ms("set2")},nice:!0,x:{label:"Week",labelOffset:Number.MAX_SAFE_INTEGER,tickRotate:45,domain:t.map(n=>n.Week)},y:{label:e,domain:[0,r+1]},width:1024,style:{overflow:"visible",background:"transparent",marginBottom:"3rem",fontSize:"0.8rem",marginTop:"1rem"},insetTop:20,marks:[qa(t,{tip:!0,fill:n=>n.Key,x:n=>n.Week,y:n=>n.Value}),Zr()]})}function m3({Data:t,Name:e}){t??=[];let r=3,n=u=>isNaN(Number(u))?u:Number(u),o=Array.from(Qa(t.map(u=>n(u.Key)))),i=new Map(o.map((u,c)=>[u,c])),a=u=>(i.get(u)??0)%r,f=u=>Math.floor((i.get(u)??0)/r);return Fn({ariaLabel:`${e} chart`,ariaDescription:`Cumulative histograms of the distribution of the values of ${o.length} counters: ${o.join(", ")}.`,marginLeft:60,width:1024,grid:!0,nice:!0,x:{label:"Distribution"},color:{type:"ordinal",legend:!0,...ms("Spectral"),label:"Counter"},y:{insetTop:16,domain:[0,1]},fx:{ticks:[]},fy:{ticks:[]},style:"background:transparent;",marks:[qa(t,Am({y:"proportion-facet",x:"x1",interval:.1,cumulative:1},{tip:!0,fill:u=>n(u.Key),x:u=>u.Value,fx:u=>a(n(u.Key)),fy:u=>f(n(u.Key))})),Bo(o,{frameAnchor:"top",dy:3,fx:a,fy:f}),Ln({anchor:"bottom",tickSpacing:35}),Ln({anchor:"top",tickSpacing:35}),Zr()]})}function d3({Data:t,Name:e}){t??=[];let r=o=>o.split(`
+`)[0]||"(no stack)",n=Array.from(new Set(t.map(o=>o.Week)));return Fn({ariaLabel:`${e} chart`,ariaDescription:`Bar charts of the counts of the most frequent stacks of ${e} in each of ${n.length} weeks: `+t.map(o=>`${o.Week}, ${r(o.Key)}: ${o.Value}`).join(", ")+".",marginLeft:320,width:1024,color:{type:"ordinal",...ms("set2")},x:{label:"Count",grid:!0},y:{label:null,tickFormat:r},fy:{label:n.length>1?"Week":null,domain:n},style:"background:transparent;",marks:[(void 0)(t,{x:o=>o.Value,y:o=>o.Key,fy:o=>o.Week,fill:o=>r(o.Key),sort:{y:"-x"},title:o=>`${o.Key}
+
+count: ${o.Value}`,tip:!0}),Zr()]})}})();
 /**
  * @license
  * Copyright 2024 The Go Authors. All rights reserved.
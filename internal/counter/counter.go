@@ -67,6 +67,10 @@ type Counter struct {
 	name string
 	file *file
 
+	// saturate is set for counters created by NewBool, whose value in each
+	// counter file is at most 1.
+	saturate bool
+
 	next  atomic.Pointer[Counter]
 	state counterState
 	ptr   counterPtr
@@ -141,6 +145,13 @@ func New(name string) *Counter {
 	return &Counter{name: name, file: &defaultFile}
 }
 
+// NewBool returns a counter with the given name that records whether an
+// event occurred at all, rather than how often: its value in any one counter
+// file is at most 1, no matter how many times it is incremented.
+func NewBool(name string) *Counter {
+	return &Counter{name: name, file: &defaultFile, saturate: true}
+}
+
 // Inc adds 1 to the counter.
 func (c *Counter) Inc() {
 	c.Add(1)
@@ -156,6 +167,9 @@ func (c *Counter) Add(n int64) {
 	if n == 0 {
 		return
 	}
+	if c.saturate {
+		n = 1
+	}
 	c.file.register(c)
 
 	state := c.state.load()
@@ -167,7 +181,7 @@ func (c *Counter) Add(n int64) {
 			}
 			// Counter unlocked or counter shared; has an initialized count pointer; acquired shared lock.
 			if c.ptr.count == nil {
-				for !c.state.update(&state, state.addExtra(uint64(n))) {
+				for !c.state.update(&state, c.addExtra(state, uint64(n))) {
 					// keep trying - we already took the reader lock
 					state = c.state.load()
 				}
@@ -180,14 +194,14 @@ func (c *Counter) Add(n int64) {
 			return
 
 		case state.locked():
-			if !c.state.update(&state, state.addExtra(uint64(n))) {
+			if !c.state.update(&state, c.addExtra(state, uint64(n))) {
 				continue
 			}
 			debugPrintf("Add %q += %d: locked extra=%d\n", c.name, n, state.extra())
 			return
 
 		case !state.havePtr():
-			if !c.state.update(&state, state.addExtra(uint64(n)).setLocked()) {
+			if !c.state.update(&state, c.addExtra(state, uint64(n)).setLocked()) {
 				continue
 			}
 			debugPrintf("Add %q += %d: noptr extra=%d\n", c.name, n, state.extra())
@@ -197,6 +211,15 @@ func (c *Counter) Add(n int64) {
 	}
 }
 
+// addExtra returns state with n added to its extra count, which is capped at 1
+// for saturating counters.
+func (c *Counter) addExtra(state counterStateBits, n uint64) counterStateBits {
+	if c.saturate {
+		return state.clearExtra() | 1<<stateExtraShift
+	}
+	return state.addExtra(n)
+}
+
 func (c *Counter) releaseReader(state counterStateBits) {
 	for ; ; state = c.state.load() {
 		// If we are the last reader and havePtr was cleared
@@ -271,6 +294,13 @@ func (c *Counter) add(n uint64) uint64 {
 		if sum < old {
 			sum = ^uint64(0)
 		}
+		if c.saturate && sum > 1 {
+			if old >= 1 {
+				runtime.KeepAlive(c.ptr.m)
+				return old
+			}
+			sum = 1
+		}
 		if count.CompareAndSwap(old, sum) {
 			runtime.KeepAlive(c.ptr.m)
 			return sum
@@ -25,6 +25,7 @@ import (
 	"sync"
 	"testing"
 	"time"
+	"unsafe"
 
 	"golang.org/x/telemetry/internal/telemetry"
 	"golang.org/x/telemetry/internal/testenv"
@@ -67,6 +68,44 @@ func TestBasic(t *testing.T) {
 	}
 }
 
+func TestBool(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	setup(t)
+	var f file
+	defer close(&f)
+	c := f.NewBool("used")
+	c.Inc()
+	c.Add(5) // before the file is mapped
+	if got, err := Read(c); err != nil || got != 1 {
+		t.Errorf("Read before rotate = %d, %v, want 1, nil", got, err)
+	}
+	f.rotate()
+	if f.err != nil {
+		t.Fatal(f.err)
+	}
+	current := f.current.Load()
+	if current == nil {
+		t.Fatal("no mapped file")
+	}
+	c.Inc()
+	c.Add(100)
+
+	name := current.f.Name()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pf, err := Parse(name, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint64{"used": 1}
+	if !reflect.DeepEqual(pf.Count, want) {
+		t.Errorf("pf.Count = %v, want %v", pf.Count, want)
+	}
+}
+
 func TestMissingLocalDir(t *testing.T) {
 	testenv.SkipIfUnsupportedPlatform(t)
 	err := os.RemoveAll(telemetry.Default.LocalDir())
@@ -179,6 +218,50 @@ func TestLarge(t *testing.T) {
 	}
 }
 
+// BenchmarkParallelInc measures the throughput of increments of counters by
+// many goroutines. In the "distinct" case, each goroutine increments its own
+// counter; these counters are allocated consecutively in the counter file, so
+// that their throughput depends on whether they share cache lines.
+func BenchmarkParallelInc(b *testing.B) {
+	testenv.SkipIfUnsupportedPlatform(b)
+
+	for _, shared := range []bool{true, false} {
+		name := "distinct"
+		if shared {
+			name = "shared"
+		}
+		b.Run(name, func(b *testing.B) {
+			setup(b)
+			var f file
+			defer close(&f)
+			f.rotate()
+
+			var (
+				mu       sync.Mutex
+				counters []*Counter
+			)
+			next := func() *Counter {
+				mu.Lock()
+				defer mu.Unlock()
+				if shared && len(counters) > 0 {
+					return counters[0]
+				}
+				c := f.New(fmt.Sprint("gophers", len(counters)))
+				c.Inc() // allocate the counter in the file
+				counters = append(counters, c)
+				return c
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				c := next()
+				for pb.Next() {
+					c.Inc()
+				}
+			})
+		})
+	}
+}
+
 func TestCorruption_Truncation(t *testing.T) {
 	testenv.SkipIfUnsupportedPlatform(t)
 
@@ -245,6 +328,29 @@ func TestRepeatedNew(t *testing.T) {
 	}
 }
 
+func TestRecordAlignment(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	setup(t)
+	var f file
+	defer close(&f)
+	f.rotate()
+	// Names of varying lengths, including ones longer than a record unit.
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprint("gophers", strings.Repeat("x", i))
+		f.New(name).Inc()
+		ptr := f.lookup(name)
+		if ptr.m == nil {
+			t.Fatalf("counter %q not allocated", name)
+		}
+		off := uintptr(unsafe.Pointer(ptr.count)) - uintptr(unsafe.Pointer(&ptr.m.mapping.Data[0]))
+		const cacheLine = 64
+		if off%cacheLine != 0 {
+			t.Errorf("counter %q at offset %d, not cache line aligned", name, off)
+		}
+	}
+}
+
 func hexDump(data []byte) string {
 	lines := strings.SplitAfter(hex.Dump(data), "\n")
 	var keep []string
@@ -543,6 +649,82 @@ func TestStack(t *testing.T) {
 	}
 }
 
+func TestStackTruncation(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	setup(t)
+	var f file
+	defer close(&f)
+	f.rotate()
+
+	// A prefix just short of the limit leaves no room for the stack.
+	c := f.NewStack(strings.Repeat("x", maxNameLen-10), 5)
+	c.Inc()
+	c.Inc() // same stack: not truncated again
+	short := f.NewStack("short", 5)
+	short.Inc()
+
+	if got, want := c.Stats(), (StackStats{Stacks: 2, Truncated: 2}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if got, want := short.Stats(), (StackStats{Stacks: 1}); got != want {
+		t.Errorf("short.Stats() = %+v, want %+v", got, want)
+	}
+	for _, name := range c.Names() {
+		if len(name) != maxNameLen || !strings.HasSuffix(name, "\ntruncated\n") {
+			t.Errorf("name has length %d and suffix %q, want %d and truncated", len(name), name[len(name)-12:], maxNameLen)
+		}
+	}
+	got, err := Read(f.New(stackTruncatedCounter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 2 {
+		t.Errorf("%s = %d, want 2", stackTruncatedCounter, got)
+	}
+}
+
+func TestEvent(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	setup(t)
+	var f file
+	defer close(&f)
+	f.rotate()
+
+	e := f.NewEvent("switch", "from", "to")
+	e.Inc("go1.22.0", "go1.23.1")
+	e.Inc("go1.22.0", "go1.23.1")
+	e.Inc("go1.22.0")                                // missing value
+	e.Inc("go1.22.0", "go1.23.1", "extra")           // extra value
+	e.Inc("go1.22.0,x=y}", strings.Repeat("9", 100)) // unsafe and long values
+
+	got, err := ReadEvent(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint64{
+		"switch{from=go1.22.0,to=go1.23.1}": 3,
+		"switch{from=go1.22.0,to=}":         1,
+		"switch{from=go1.22.0_x_y_,to=" + strings.Repeat("9", maxEventValueLen) + "}": 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadEvent() = %v, want %v", got, want)
+	}
+	for name := range got {
+		if !IsEventCounter(name) {
+			t.Errorf("IsEventCounter(%q) = false", name)
+		}
+		event, attrs, values, ok := DecodeEvent(name)
+		if !ok || event != "switch" || !reflect.DeepEqual(attrs, []string{"from", "to"}) || len(values) != 2 {
+			t.Errorf("DecodeEvent(%q) = %q, %q, %q, %v", name, event, attrs, values, ok)
+		}
+	}
+	for _, name := range []string{"switch", "editor:vim", "crash\nf{x=1}"} {
+		if IsEventCounter(name) {
+			t.Errorf("IsEventCounter(%q) = true", name)
+		}
+	}
+}
+
 // fn calls itself n times recursively while incrementing the stack counter.
 func fn(t *testing.T, n int, c *StackCounter) {
 	c.Inc()
@@ -551,7 +733,7 @@ func fn(t *testing.T, n int, c *StackCounter) {
 	}
 }
 
-func setup(t *testing.T) {
+func setup(t testing.TB) {
 	log.SetFlags(log.Lshortfile)
 	telemetry.Default = telemetry.NewDir(t.TempDir()) // new dir for each test
 	os.MkdirAll(telemetry.Default.LocalDir(), 0777)
@@ -565,6 +747,14 @@ func (f *file) New(name string) *Counter {
 	return &Counter{name: name, file: f}
 }
 
+func (f *file) NewBool(name string) *Counter {
+	return &Counter{name: name, file: f, saturate: true}
+}
+
 func (f *file) NewStack(name string, depth int) *StackCounter {
 	return &StackCounter{name: name, depth: depth, file: f}
 }
+
+func (f *file) NewEvent(name string, attrs ...string) *Event {
+	return &Event{name: name, attrs: attrs, file: f}
+}
@@ -0,0 +1,16 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix || js || wasip1
+
+package counter
+
+import (
+	"errors"
+	"syscall"
+)
+
+func init() {
+	isDiskFull = func(err error) bool { return errors.Is(err, syscall.ENOSPC) }
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package counter
+
+import (
+	"strings"
+	"sync"
+)
+
+// On the disk, and upstream, an event looks like a set of regular
+// counters, one for each combination of attribute values observed, named
+//
+//	name{attr1=value1,attr2=value2}
+//
+// with the attributes in the order given to NewEvent. Braces never appear in
+// the names of other counters (in the upload config they denote buckets),
+// so event counters are easily recognized.
+
+const (
+	// maxEventAttrs is the maximum number of attributes of an event.
+	maxEventAttrs = 4
+	// maxEventValueLen is the maximum length of an event attribute value.
+	// Longer values are truncated.
+	maxEventValueLen = 32
+)
+
+// An Event is a counter of occurrences of an event, broken down by the
+// values of a small, fixed set of attributes.
+type Event struct {
+	name  string
+	attrs []string
+	file  *file
+
+	mu       sync.Mutex
+	counters map[string]*Counter // by encoded name
+}
+
+// NewEvent returns an event counter with the given name and attributes.
+// Attributes beyond the first maxEventAttrs are ignored.
+func NewEvent(name string, attrs ...string) *Event {
+	if len(attrs) > maxEventAttrs {
+		attrs = attrs[:maxEventAttrs]
+	}
+	return &Event{name: name, attrs: attrs, file: &defaultFile}
+}
+
+// Inc records an occurrence of the event with the given attribute values,
+// one for each attribute in the order given to NewEvent. Missing values are
+// recorded as empty, and extra values are ignored.
+func (e *Event) Inc(values ...string) {
+	name := EncodeEvent(e.name, e.attrs, values)
+
+	e.mu.Lock()
+	ctr := e.counters[name]
+	if ctr == nil {
+		ctr = &Counter{name: name, file: e.file}
+		if e.counters == nil {
+			e.counters = make(map[string]*Counter)
+		}
+		e.counters[name] = ctr
+	}
+	e.mu.Unlock()
+
+	ctr.Inc()
+}
+
+// Counters returns the known Counters for an Event.
+// There may be more in the count file.
+func (e *Event) Counters() []*Counter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	counters := make([]*Counter, 0, len(e.counters))
+	for _, c := range e.counters {
+		counters = append(counters, c)
+	}
+	return counters
+}
+
+// EncodeEvent returns the name of the counter to use for an occurrence of
+// the named event with the given attribute values.
+func EncodeEvent(name string, attrs, values []string) string {
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, a := range attrs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(a)
+		b.WriteByte('=')
+		if i < len(values) {
+			b.WriteString(sanitizeEventValue(values[i]))
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// sanitizeEventValue truncates v to maxEventValueLen bytes and replaces the
+// characters that are not allowed in event values with '_'.
+func sanitizeEventValue(v string) string {
+	if len(v) > maxEventValueLen {
+		v = v[:maxEventValueLen]
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		case strings.ContainsRune("+-./_", r):
+			return r
+		}
+		return '_'
+	}, v)
+}
+
+// DecodeEvent parses the name of an event counter, returning the name of
+// the event and its attributes and values. It reports false if name is not
+// the name of an event counter.
+func DecodeEvent(name string) (event string, attrs, values []string, ok bool) {
+	event, rest, ok := strings.Cut(name, "{")
+	if !ok || !strings.HasSuffix(rest, "}") {
+		return "", nil, nil, false
+	}
+	rest = strings.TrimSuffix(rest, "}")
+	if rest == "" {
+		return event, nil, nil, true
+	}
+	for _, kv := range strings.Split(rest, ",") {
+		a, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return "", nil, nil, false
+		}
+		attrs = append(attrs, a)
+		values = append(values, v)
+	}
+	return event, attrs, values, true
+}
+
+// ReadEvent reads the given event counter.
+// This is the implementation of
+// golang.org/x/telemetry/counter/countertest.ReadEvent.
+func ReadEvent(e *Event) (map[string]uint64, error) {
+	ret := map[string]uint64{}
+	for _, ctr := range e.Counters() {
+		v, err := Read(ctr)
+		if err != nil {
+			return nil, err
+		}
+		ret[ctr.Name()] = v
+	}
+	return ret, nil
+}
+
+// IsEventCounter reports whether the counter name is for an event counter.
+func IsEventCounter(name string) bool {
+	_, _, _, ok := DecodeEvent(name)
+	return ok && !IsStackCounter(name)
+}
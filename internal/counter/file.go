@@ -36,6 +36,9 @@ type file struct {
 	buildInfo          *debug.BuildInfo
 	timeBegin, timeEnd time.Time
 	err                error
+	// diskFullBackoff is nonzero while the counter file cannot be opened
+	// because the disk is full. It is the delay before the next attempt.
+	diskFullBackoff time.Duration
 	// current holds the current file mapping, which may change when the file is
 	// rotated or extended.
 	//
@@ -220,12 +223,31 @@ func counterSpan() (begin, end time.Time, _ error) {
 	return begin, end, nil
 }
 
+// Bounds on the delay between attempts to rotate the counter file while the
+// disk is full.
+const (
+	minDiskFullBackoff = 1 * time.Minute
+	maxDiskFullBackoff = 1 * time.Hour
+)
+
+// isDiskFull reports whether err indicates that the disk is full.
+// It is set by platform-specific files.
+var isDiskFull = func(err error) bool { return false }
+
 // rotate1 rotates the current counter file, returning its expiry, or the zero
 // time if rotation failed.
+//
+// If the counter file cannot be opened because the disk is full, rotate1
+// does not fail permanently, but returns the time at which rotation should be
+// retried. Until then, counters count in memory. Once rotation succeeds, the
+// counter/rotate-enospc counter records the recovery.
 func (f *file) rotate1() time.Time {
 	// Cleanup must be performed while unlocked, since invalidateCounters may
 	// involve calls to f.lookup.
-	var previous *mappedFile // read below while holding the f.mu.
+	var (
+		previous  *mappedFile // read below while holding the f.mu.
+		recovered bool        // set below if the disk is no longer full
+	)
 	defer func() {
 		// Counters must be invalidated whenever the mapped file changes.
 		if next := f.current.Load(); next != previous {
@@ -235,6 +257,9 @@ func (f *file) rotate1() time.Time {
 				previous.close() // safe to call multiple times
 			}
 		}
+		if recovered {
+			(&Counter{name: "counter/rotate-enospc", file: f}).Inc()
+		}
 	}()
 
 	f.mu.Lock()
@@ -251,6 +276,15 @@ func (f *file) rotate1() time.Time {
 		f.err = err
 		f.current.Store(nil)
 	}
+	// diskFull records that the counter file could not be opened because
+	// the disk is full, and returns the time of the next attempt.
+	diskFull := func(err error) time.Time {
+		debugPrintf("rotate: disk full: %v", err)
+		f.current.Store(nil) // count in memory, not in a stale file
+		f.timeBegin, f.timeEnd = time.Time{}, time.Time{}
+		f.diskFullBackoff = min(max(2*f.diskFullBackoff, minDiskFullBackoff), maxDiskFullBackoff)
+		return time.Now().Add(f.diskFullBackoff)
+	}
 
 	if mode, _ := telemetry.Default.Mode(); mode == "off" {
 		// TODO(rfindley): do we ever want to make ErrDisabled recoverable?
@@ -302,6 +336,9 @@ func (f *file) rotate1() time.Time {
 	)
 	dir := telemetry.Default.LocalDir()
 	if err := os.MkdirAll(dir, 0777); err != nil {
+		if isDiskFull(err) {
+			return diskFull(err)
+		}
 		fail(fmt.Errorf("making local dir: %v", err))
 		return time.Time{}
 	}
@@ -309,6 +346,9 @@ func (f *file) rotate1() time.Time {
 
 	m, err := openMapped(name, meta)
 	if err != nil {
+		if isDiskFull(err) {
+			return diskFull(err)
+		}
 		// Mapping failed:
 		// If there used to be a mapped file, after cleanup
 		// incrementing counters will only change their internal state.
@@ -319,6 +359,10 @@ func (f *file) rotate1() time.Time {
 
 	debugPrintf("using %v", m.f.Name())
 	f.current.Store(m)
+	if f.diskFullBackoff != 0 {
+		f.diskFullBackoff = 0
+		recovered = true
+	}
 	return f.timeEnd
 }
 
@@ -412,7 +456,7 @@ func Open(rotate bool) func() {
 const (
 	FileVersion = "v1"
 	hdrPrefix   = "# telemetry/counter file " + FileVersion + "\n"
-	recordUnit  = 32
+	recordUnit  = 64 // cache line size; see [mappedFile]
 	maxMetaLen  = 512
 	numHash     = 512 // 2kB for hash table
 	maxNameLen  = 4 * 1024
@@ -433,6 +477,12 @@ const (
 //	hdrLen+hashOff, 4*numHash:         hash table, stores uint32 heads of a linked list of records, keyed by name hash
 //	hdrLen+hashOff+4*numHash to limit: counter records: see record syntax below
 //
+// Records start at multiples of recordUnit, the size of a cache line, so that
+// counters incremented concurrently don't contend for the same line. (Older
+// versions of this package used 32-byte records. Since records are found by
+// following offsets, files with either alignment can be read and extended by
+// any version, and the file format version is unchanged.)
+//
 // The record layout is as follows:
 //
 //	offset, byte size: description
@@ -219,3 +219,65 @@ func (b counterStateBits) String() string {
 	extra := uint64(b&stateExtra) >> stateExtraShift
 	return fmt.Sprintf("rdrs:0x%x locked:%v\thavePtr:%v\textra:%d", rdrs, locked, havePtr, extra)
 }
+
+// TestRotateDiskFull checks that a counter file that cannot be opened because
+// the disk is full does not stop counting, and that rotation recovers once
+// space returns.
+func TestRotateDiskFull(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	setup(t)
+	now := getnow()
+	CounterTime = func() time.Time { return now }
+
+	// Learn the name of the counter file, and block it with a directory.
+	var f0 file
+	f0.rotate1()
+	m := f0.current.Load()
+	if m == nil {
+		t.Fatalf("rotate failed: %v", f0.err)
+	}
+	name := m.f.Name()
+	m.close()
+	if err := os.Remove(name); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(name, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	// Treat the resulting error as a full disk.
+	defer func(prev func(error) bool) { isDiskFull = prev }(isDiskFull)
+	isDiskFull = func(error) bool { return true }
+
+	var f file
+	defer close(&f)
+	c := f.New("gophers")
+	c.Inc()
+	before := time.Now()
+	retry := f.rotate1()
+	if f.err != nil || f.current.Load() != nil {
+		t.Fatalf("after disk full, err = %v, current = %v; want nil, nil", f.err, f.current.Load())
+	}
+	if d := retry.Sub(before); d < minDiskFullBackoff || d > minDiskFullBackoff+time.Minute {
+		t.Errorf("first retry after %v, want %v", d, minDiskFullBackoff)
+	}
+	if retry := f.rotate1(); retry.Sub(before) < 2*minDiskFullBackoff {
+		t.Errorf("second retry after %v, want at least %v", retry.Sub(before), 2*minDiskFullBackoff)
+	}
+	c.Inc()
+
+	// Free the space.
+	if err := os.Remove(name); err != nil {
+		t.Fatal(err)
+	}
+	if expiry := f.rotate1(); !expiry.Equal(f.timeEnd) || f.current.Load() == nil {
+		t.Fatalf("rotate after recovery returned %v (current %v), want file expiry %v", expiry, f.current.Load(), f.timeEnd)
+	}
+	got, err := readFile(&f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Count["gophers"] != 2 || got.Count["counter/rotate-enospc"] != 1 {
+		t.Errorf("counts = %v, want gophers=2 and counter/rotate-enospc=1", got.Count)
+	}
+}
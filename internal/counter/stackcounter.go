@@ -25,9 +25,23 @@ type StackCounter struct {
 	mu sync.Mutex
 	// as this is a detail of the implementation, it could be replaced
 	// by a more efficient mechanism
-	stacks []stack
+	stacks    []stack
+	truncated int // number of stacks whose names were truncated
 }
 
+// StackStats holds statistics about the stacks recorded by a StackCounter.
+type StackStats struct {
+	// Stacks is the number of distinct stacks recorded.
+	Stacks int
+	// Truncated is the number of those stacks whose encoded names exceeded
+	// the maximum counter name length and were truncated.
+	Truncated int
+}
+
+// stackTruncatedCounter is the name of the counter incremented each time a
+// stack counter name is truncated.
+const stackTruncatedCounter = "counter/stack-truncated"
+
 type stack struct {
 	pcs     []uintptr
 	counter *Counter
@@ -61,11 +75,16 @@ func (c *StackCounter) Inc() {
 
 	if ctr == nil {
 		// Create new counter.
+		name, truncated := encodeStack(pcs, c.name)
 		ctr = &Counter{
-			name: EncodeStack(pcs, c.name),
+			name: name,
 			file: c.file,
 		}
 		c.stacks = append(c.stacks, stack{pcs: pcs, counter: ctr})
+		if truncated {
+			c.truncated++
+			(&Counter{name: stackTruncatedCounter, file: c.file}).Inc()
+		}
 	}
 
 	ctr.Inc()
@@ -74,7 +93,17 @@ func (c *StackCounter) Inc() {
 // EncodeStack returns the name of the counter to
 // use for the given stack of program counters.
 // The name encodes the stack.
+//
+// If the encoded name would exceed the maximum counter name length, the
+// stack is truncated and its last line is replaced by "truncated".
 func EncodeStack(pcs []uintptr, prefix string) string {
+	name, _ := encodeStack(pcs, prefix)
+	return name
+}
+
+// encodeStack is like EncodeStack, but also reports whether the name was
+// truncated.
+func encodeStack(pcs []uintptr, prefix string) (name string, truncated bool) {
 	var locs []string
 	lastImport := ""
 	frs := runtime.CallersFrames(pcs)
@@ -106,12 +135,13 @@ func EncodeStack(pcs []uintptr, prefix string) string {
 		}
 	}
 
-	name := prefix + "\n" + strings.Join(locs, "\n")
+	name = prefix + "\n" + strings.Join(locs, "\n")
 	if len(name) > maxNameLen {
 		const bad = "\ntruncated\n"
 		name = name[:maxNameLen-len(bad)] + bad
+		truncated = true
 	}
-	return name
+	return name, truncated
 }
 
 // DecodeStack expands the (compressed) stack encoded in the counter name.
@@ -157,6 +187,15 @@ func (c *StackCounter) Names() []string {
 	return names
 }
 
+// Stats reports statistics about the stacks recorded by c in this process.
+// Tool authors may use it to choose a depth for which stack names fit
+// within the counter name limit.
+func (c *StackCounter) Stats() StackStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return StackStats{Stacks: len(c.stacks), Truncated: c.truncated}
+}
+
 // Counters returns the known Counters for a StackCounter.
 // There may be more in the count file.
 func (c *StackCounter) Counters() []*Counter {
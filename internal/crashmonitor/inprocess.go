@@ -0,0 +1,178 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashmonitor
+
+// This file reports crashes without a child process, for environments in
+// which a program may not execute itself.
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// staleCrashFileAge is the age beyond which a crash file that will never be
+// recorded is deleted: it was written by an executable that has since been
+// replaced, or its crash could not be parsed. On systems where crash files
+// can not be locked, this includes the files of processes that exited
+// without crashing, and is also the longest that a process can run before
+// its crash would be lost.
+const staleCrashFileAge = 30 * 24 * time.Hour
+
+// On systems that support it, a process locks its crash file until it
+// exits, so that the files of processes that exited without crashing can
+// be told apart from those of running processes, and removed.
+var (
+	// lockCrashFile locks f for the lifetime of the process.
+	lockCrashFile func(f *os.File) error
+
+	// crashFileLocked reports whether the named crash file is locked by a
+	// running process.
+	crashFileLocked func(name string) (bool, error)
+)
+
+// crashFile is the crash file of this process, which is kept open so that
+// its lock is held until the process exits.
+var crashFile *os.File
+
+// InProcess sets up crash reporting for a process that cannot start a child
+// process to monitor it.
+//
+// A process that crashes has no opportunity to record the crash itself: the
+// runtime writes its report and exits immediately. So InProcess directs crash
+// reports to a new file in dir, and first records any crashes found in files
+// written by earlier processes of the same executable. Crashes are therefore
+// recorded only when the program next runs.
+func InProcess(dir string, opts Options) error {
+	id, err := executableID()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	recordCrashFiles(dir, id, time.Now(), opts)
+
+	f, err := os.CreateTemp(dir, "*.crash")
+	if err != nil {
+		return err
+	}
+	// Lock the file before writing to it: a complete file that is not
+	// locked belongs to a process that has exited.
+	if lockCrashFile != nil {
+		if err := lockCrashFile(f); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return err
+		}
+	}
+	fmt.Fprintf(f, "executable %s\n", id)
+	writeSentinel(f)
+	// Ensure that we get pc=0x%x values in the traceback.
+	debug.SetTraceback("system")
+	if err := setCrashOutput(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	crashFile = f
+	return nil
+}
+
+// executableID identifies the executable of this process. A crash report can
+// only be symbolized by the executable that crashed.
+func executableID() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(exe)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%q %d %d", exe, fi.Size(), fi.ModTime().UnixNano()), nil
+}
+
+// recordCrashFiles records the crashes in the crash files in dir that were
+// written by the executable with the given id, and removes them. It also
+// removes the crash files of processes that exited without crashing, and
+// crash files that are older than staleCrashFileAge and will never be
+// recorded.
+//
+// A file is claimed by renaming it before its crash is recorded, so that
+// concurrent processes of the same executable do not both record it. Files
+// whose crash cannot be parsed are renamed with the suffix ".malformed"
+// rather than removed, so that they can be investigated until they are
+// stale.
+func recordCrashFiles(dir, id string, now time.Time, opts Options) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.crash"))
+	if err != nil {
+		return // malformed pattern; can't happen
+	}
+	for _, file := range files {
+		fi, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		stale := now.Sub(fi.ModTime()) > staleCrashFileAge
+		// Whether the process that wrote the file has exited is only known
+		// if crash files are locked.
+		exited := false
+		if crashFileLocked != nil {
+			locked, err := crashFileLocked(file)
+			if err != nil || locked {
+				continue
+			}
+			exited = true
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		header, report, _ := bytes.Cut(data, []byte("\n"))
+		ours := string(header) == "executable "+id
+		// If the only line of the report is the sentinel, there was no crash,
+		// and the process that wrote it may still be running.
+		crashed := bytes.Count(report, []byte("\n")) >= 2
+		complete := bytes.HasPrefix(report, []byte("sentinel ")) && bytes.HasSuffix(report, []byte("\n"))
+		base := strings.TrimSuffix(file, ".crash")
+		switch {
+		case ours && crashed:
+			claimed := base + ".claimed"
+			if err := os.Rename(file, claimed); err != nil {
+				continue // claimed by another process
+			}
+			if err := recordCrash(report, opts); err != nil {
+				log.Printf("failed to record crash in %s: %v", file, err)
+				os.Rename(claimed, base+".malformed")
+				continue
+			}
+			os.Remove(claimed)
+		case exited && complete && !crashed:
+			os.Remove(file)
+		case stale:
+			os.Remove(file)
+		}
+	}
+
+	// Claimed files are left by processes that exited while recording
+	// their crash.
+	for _, pattern := range []string{"*.malformed", "*.claimed"} {
+		files, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return
+		}
+		for _, file := range files {
+			if fi, err := os.Stat(file); err == nil && now.Sub(fi.ModTime()) > staleCrashFileAge {
+				os.Remove(file)
+			}
+		}
+	}
+}
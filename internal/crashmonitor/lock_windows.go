@@ -0,0 +1,38 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashmonitor
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Locks on Windows are mandatory, so the lock covers a byte far beyond the
+// end of the file, leaving its content readable by other processes.
+
+func init() {
+	lockCrashFile = func(f *os.File) error {
+		return lockByte(f, windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY)
+	}
+	crashFileLocked = func(name string) (bool, error) {
+		f, err := os.Open(name)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close() // releases the lock
+		err = lockByte(f, windows.LOCKFILE_FAIL_IMMEDIATELY)
+		if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+			return true, nil
+		}
+		return false, err
+	}
+}
+
+func lockByte(f *os.File, flags uint32) error {
+	ol := &windows.Overlapped{OffsetHigh: 1 << 30}
+	return windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, 1, 0, ol)
+}
@@ -37,10 +37,44 @@ func Parent(pipe *os.File) {
 	setCrashOutput(pipe)
 }
 
+// DefaultStackDepth is the number of frames of the crashing goroutine's
+// stack that are recorded if [Options.StackDepth] is zero.
+const DefaultStackDepth = 16
+
+// Options configures the counters that record a crash.
+type Options struct {
+	// StackDepth is the maximum number of frames of the crashing
+	// goroutine's stack recorded in the crash/crash counter, or zero
+	// for DefaultStackDepth.
+	//
+	// Stacks are truncated on upload to the depth in the upload
+	// config, so deeper stacks are only uploaded once approved.
+	StackDepth int
+
+	// GoroutineHeads, if set, additionally records the head of every
+	// goroutine, starting with the crashing one, in the
+	// crash/goroutine-heads counter. This helps to diagnose crashes
+	// such as deadlocks that don't lie on the stack of any one
+	// goroutine. At most StackDepth goroutines are recorded.
+	GoroutineHeads bool
+
+	// OpenCounters, if set, is called before the counters for a crash
+	// are incremented, for processes that open the counter file only
+	// when there is a crash to record.
+	OpenCounters func()
+}
+
+func (opts Options) depth() int {
+	if opts.StackDepth <= 0 {
+		return DefaultStackDepth
+	}
+	return opts.StackDepth
+}
+
 // Child runs the part of the crashmonitor that runs in the child process.
 // It expects its stdin to be connected via a pipe to the parent which has
 // run Parent.
-func Child() {
+func Child(opts Options) {
 	// Wait for parent process's dying gasp.
 	// If the parent dies for any reason this read will return.
 	data, err := io.ReadAll(os.Stdin)
@@ -56,14 +90,7 @@ func Child() {
 
 	log.Printf("parent reported crash:\n%s", data)
 
-	// Parse the stack out of the crash report
-	// and record a telemetry count for it.
-	name, err := telemetryCounterName(data)
-	if err != nil {
-		// Keep count of how often this happens
-		// so that we can investigate if necessary.
-		incrementCounter("crash/malformed")
-
+	if err := recordCrash(data, opts); err != nil {
 		// Something went wrong.
 		// Save the crash securely in the file system.
 		f, err := os.CreateTemp(os.TempDir(), "*.crash")
@@ -80,12 +107,39 @@ func Child() {
 		log.Fatalf("crash report saved at %s", f.Name())
 	}
 
-	incrementCounter(name)
-
 	childExitHook()
 	log.Fatalf("telemetry crash recorded")
 }
 
+// recordCrash increments the counters for the crash report produced by the
+// Go runtime.
+func recordCrash(data []byte, opts Options) error {
+	if opts.OpenCounters != nil {
+		opts.OpenCounters()
+	}
+
+	// Record the kind of crash, independent of its stack.
+	incrementCounter("crash/reason:" + crashReason(data))
+
+	// Parse the stack out of the crash report
+	// and record a telemetry count for it.
+	name, err := telemetryCounterName(data, opts.depth())
+	if err != nil {
+		// Keep count of how often this happens
+		// so that we can investigate if necessary.
+		incrementCounter("crash/malformed")
+		return err
+	}
+	incrementCounter(name)
+
+	if opts.GoroutineHeads {
+		if name := goroutineHeadsCounterName(data, opts.depth()); name != "" {
+			incrementCounter(name)
+		}
+	}
+	return nil
+}
+
 // (stubbed by test)
 var (
 	incrementCounter = func(name string) { counter.New(name).Inc() }
@@ -106,18 +160,105 @@ func writeSentinel(out io.Writer) {
 	fmt.Fprintf(out, "sentinel %x\n", sentinel())
 }
 
+// Reasons for fatal errors and runtime error panics, keyed by a prefix of the
+// error message. The reported message may continue with details that are
+// specific to the crash, which are never recorded.
+var (
+	fatalErrorReasons = []struct{ msg, reason string }{
+		{"concurrent map writes", "concurrent-map-writes"},
+		{"concurrent map read and map write", "concurrent-map-read-and-write"},
+		{"concurrent map iteration and map write", "concurrent-map-iteration-and-write"},
+		{"runtime: out of memory", "out-of-memory"},
+		{"stack overflow", "stack-overflow"},
+		{"all goroutines are asleep - deadlock!", "deadlock"},
+	}
+	runtimeErrorReasons = []struct{ msg, reason string }{
+		{"invalid memory address or nil pointer dereference", "nil-dereference"},
+		{"index out of range", "index-out-of-range"},
+		{"slice bounds out of range", "slice-bounds-out-of-range"},
+		{"integer divide by zero", "divide-by-zero"},
+	}
+	signalReasons = []string{"SIGSEGV", "SIGBUS", "SIGFPE", "SIGILL", "SIGABRT", "SIGTRAP", "SIGQUIT"}
+)
+
+// crashReason returns the kind of crash described by a crash report produced
+// by the Go runtime, for use as the bucket of the crash/reason counter.
+//
+// The result is one of a fixed set of names, so that no part of the crash
+// message (which may contain PII) is recorded: the reasons in
+// fatalErrorReasons and runtimeErrorReasons, a signal name from
+// signalReasons, or one of "signal", "fatal-error", "runtime-error", "panic",
+// and "unknown".
+func crashReason(crash []byte) string {
+	reason := ""
+	signal := ""
+	for _, line := range strings.Split(string(crash), "\n") {
+		switch {
+		case reason == "" && strings.HasPrefix(line, "fatal error: "):
+			reason = "fatal-error"
+			msg := strings.TrimPrefix(line, "fatal error: ")
+			for _, r := range fatalErrorReasons {
+				if strings.HasPrefix(msg, r.msg) {
+					reason = r.reason
+					break
+				}
+			}
+		case reason == "" && strings.HasPrefix(line, "panic: "):
+			reason = "panic"
+			if msg, ok := strings.CutPrefix(line, "panic: runtime error: "); ok {
+				reason = "runtime-error"
+				for _, r := range runtimeErrorReasons {
+					if strings.HasPrefix(msg, r.msg) {
+						reason = r.reason
+						break
+					}
+				}
+			}
+		case signal == "" && strings.HasPrefix(line, "[signal "):
+			// [signal SIGSEGV: segmentation violation code=... addr=... pc=...]
+			signal = signalName(strings.TrimPrefix(line, "[signal "))
+		case signal == "" && strings.HasPrefix(line, "SIG") && strings.Contains(line, ": "):
+			// SIGSEGV: segmentation violation (a signal in non-Go code)
+			signal = signalName(line)
+		}
+	}
+	switch reason {
+	case "", "fatal-error", "runtime-error", "panic":
+		// The signal, if any, is more informative.
+		if signal != "" {
+			return signal
+		}
+	}
+	if reason == "" {
+		return "unknown"
+	}
+	return reason
+}
+
+// signalName returns the name of the signal at the start of s, if it is one
+// of signalReasons, or "signal" otherwise.
+func signalName(s string) string {
+	name, _, _ := strings.Cut(s, ":")
+	for _, sig := range signalReasons {
+		if name == sig {
+			return sig
+		}
+	}
+	return "signal"
+}
+
 // telemetryCounterName parses a crash report produced by the Go
-// runtime, extracts the stack of the first runnable goroutine,
-// converts each line into telemetry form ("symbol:relative-line"),
-// and returns this as the name of a counter.
-func telemetryCounterName(crash []byte) (string, error) {
+// runtime, extracts at most depth frames of the stack of the crashing
+// goroutine, converts each line into telemetry form
+// ("symbol:relative-line"), and returns this as the name of a counter.
+func telemetryCounterName(crash []byte, depth int) (string, error) {
 	pcs, err := parseStackPCs(string(crash))
 	if err != nil {
 		return "", err
 	}
 
 	// Limit the number of frames we request.
-	pcs = pcs[:min(len(pcs), 16)]
+	pcs = pcs[:min(len(pcs), depth)]
 
 	if len(pcs) == 0 {
 		// This can occur if all goroutines are idle, as when
@@ -161,15 +302,185 @@ func telemetryCounterName(crash []byte) (string, error) {
 	return counter.EncodeStack(pcs, prefix), nil
 }
 
+// goroutineHeadsCounterName returns the name of a counter whose stack
+// holds the head of each of at most n goroutines in a crash report,
+// starting with the crashing goroutine. The head of a goroutine is its
+// innermost frame outside the runtime, where it was running or blocked.
+//
+// It returns "" if the heads cannot be determined.
+func goroutineHeadsCounterName(crash []byte, n int) string {
+	parentSentinel, goroutines, err := parseCrash(string(crash))
+	if err != nil || parentSentinel == 0 {
+		return ""
+	}
+	if i := crashingGoroutine(goroutines); i > 0 {
+		crashing := goroutines[i]
+		copy(goroutines[1:i+1], goroutines[:i])
+		goroutines[0] = crashing
+	}
+	var pcs []uintptr
+	for _, g := range goroutines {
+		if len(pcs) == n {
+			break
+		}
+		frames, err := g.frames(parentSentinel)
+		if err != nil || len(frames) == 0 {
+			continue // e.g. "goroutine running on other thread"
+		}
+		head := frames[0]
+		for _, fr := range frames {
+			if !strings.HasPrefix(fr.symbol, "runtime.") && fr.symbol != "panic" {
+				head = fr
+				break
+			}
+		}
+		pcs = append(pcs, head.pc)
+	}
+	if len(pcs) == 0 {
+		return ""
+	}
+	return counter.EncodeStack(pcs, "crash/goroutine-heads")
+}
+
 // parseStackPCs parses the parent process's program counters for the
-// first running goroutine out of a GOTRACEBACK=system traceback,
-// adjusting them so that they are valid for the child process's text
-// segment.
+// crashing goroutine out of a GOTRACEBACK=system traceback, adjusting
+// them so that they are valid for the child process's text segment.
 //
 // This function returns only program counter values, ensuring that
 // there is no possibility of strings from the crash report (which may
 // contain PII) leaking into the telemetry system.
 func parseStackPCs(crash string) ([]uintptr, error) {
+	parentSentinel, goroutines, err := parseCrash(crash)
+	if err != nil {
+		return nil, err
+	}
+	i := crashingGoroutine(goroutines)
+	if i < 0 {
+		return nil, nil
+	}
+	if parentSentinel == 0 {
+		return nil, fmt.Errorf("no sentinel value in crash report")
+	}
+	frames, err := goroutines[i].frames(parentSentinel)
+	if err != nil {
+		return nil, err
+	}
+	pcs := make([]uintptr, len(frames))
+	for i, fr := range frames {
+		pcs[i] = fr.pc
+	}
+	return pcs, nil
+}
+
+// A goroutine is the traceback of one goroutine in a crash report.
+type goroutine struct {
+	running bool     // status is [running]
+	lines   []string // the lines of its frames, excluding "created by"
+}
+
+// parseCrash splits a GOTRACEBACK=system traceback into the parent
+// process's sentinel value, which is zero if absent, and the tracebacks of
+// its goroutines.
+func parseCrash(crash string) (parentSentinel uint64, goroutines []goroutine, err error) {
+	var (
+		g       *goroutine // current goroutine, if any
+		created bool       // have we seen g's "created by" line?
+	)
+	for _, line := range strings.Split(crash, "\n") {
+		// Read sentinel value.
+		if parentSentinel == 0 && strings.HasPrefix(line, "sentinel ") {
+			_, err := fmt.Sscanf(line, "sentinel %x", &parentSentinel)
+			if err != nil {
+				return 0, nil, fmt.Errorf("can't read sentinel line")
+			}
+			continue
+		}
+
+		// Search for "goroutine GID [STATUS]:"
+		if g == nil {
+			if strings.HasPrefix(line, "goroutine ") && strings.HasSuffix(line, ":") {
+				goroutines = append(goroutines, goroutine{
+					running: strings.Contains(line, " [running]:"),
+				})
+				g = &goroutines[len(goroutines)-1]
+				created = false
+			}
+			continue
+		}
+
+		switch {
+		case line == "":
+			// A blank line marks end of a goroutine stack.
+			g = nil
+		case strings.HasPrefix(line, "created by "):
+			// Skip the final "created by SYMBOL in goroutine GID" part.
+			created = true
+		case !created:
+			g.lines = append(g.lines, line)
+		}
+	}
+	return parentSentinel, goroutines, nil
+}
+
+// crashingGoroutine returns the index of the goroutine that crashed: the
+// first running goroutine that is panicking or throwing, or failing that,
+// the first running goroutine. It returns -1 if no goroutine is running.
+//
+// The runtime lists the crashing goroutine first in most crash reports,
+// but we don't rely on the order when the stacks tell us more.
+func crashingGoroutine(goroutines []goroutine) int {
+	first := -1
+	for i, g := range goroutines {
+		if !g.running {
+			continue
+		}
+		if g.panicking() {
+			return i
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	return first
+}
+
+// panicSymbols are the functions that appear on the stack of a goroutine
+// that panics or throws a fatal error. The runtime reports calls to
+// runtime.gopanic as "panic".
+var panicSymbols = []string{
+	"panic",
+	"runtime.gopanic",
+	"runtime.sigpanic",
+	"runtime.throw",
+	"runtime.fatal",
+	"runtime.fatalthrow",
+	"runtime.fatalpanic",
+}
+
+// panicking reports whether g is panicking or throwing a fatal error.
+func (g goroutine) panicking() bool {
+	for _, line := range g.lines {
+		for _, sym := range panicSymbols {
+			if strings.HasPrefix(line, sym+"(") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// A frame is a frame of a goroutine's stack that has a PC.
+type frame struct {
+	symbol string
+	pc     uintptr // valid for the child process's text segment
+}
+
+// frames parses the frames of g that have a PC, adjusting the PCs for the
+// difference between the parent's and child's text segments.
+//
+// The symbols are used only to interpret the stack; they must not be
+// recorded, as only program counter values are safe from PII.
+func (g goroutine) frames(parentSentinel uint64) ([]frame, error) {
 	// getSymbol parses the symbol name out of a line of the form:
 	// SYMBOL(ARGS)
 	//
@@ -210,50 +521,13 @@ func parseStackPCs(crash string) ([]uintptr, error) {
 	}
 
 	var (
-		pcs            []uintptr
-		parentSentinel uint64
-		childSentinel  = sentinel()
-		on             = false // are we in the first running goroutine?
-		lines          = strings.Split(crash, "\n")
-		symLine        = true // within a goroutine, every other line is a symbol or file/line/pc location, starting with symbol.
-		currSymbol     string
-		prevSymbol     string // symbol of the most recent previous frame with a PC.
+		frames        []frame
+		childSentinel = sentinel()
+		symLine       = true // every other line is a symbol or file/line/pc location, starting with symbol.
+		currSymbol    string
+		prevSymbol    string // symbol of the most recent previous frame with a PC.
 	)
-	for i := 0; i < len(lines); i++ {
-		line := lines[i]
-
-		// Read sentinel value.
-		if parentSentinel == 0 && strings.HasPrefix(line, "sentinel ") {
-			_, err := fmt.Sscanf(line, "sentinel %x", &parentSentinel)
-			if err != nil {
-				return nil, fmt.Errorf("can't read sentinel line")
-			}
-			continue
-		}
-
-		// Search for "goroutine GID [STATUS]"
-		if !on {
-			if strings.HasPrefix(line, "goroutine ") &&
-				strings.Contains(line, " [running]:") {
-				on = true
-
-				if parentSentinel == 0 {
-					return nil, fmt.Errorf("no sentinel value in crash report")
-				}
-			}
-			continue
-		}
-
-		// A blank line marks end of a goroutine stack.
-		if line == "" {
-			break
-		}
-
-		// Skip the final "created by SYMBOL in goroutine GID" part.
-		if strings.HasPrefix(line, "created by ") {
-			break
-		}
-
+	for _, line := range g.lines {
 		// Expect a pair of lines:
 		//   SYMBOL(ARGS)
 		//   \tFILE:LINE +0xRELPC sp=0x%x fp=0x%x pc=0x%x
@@ -323,7 +597,7 @@ func parseStackPCs(crash string) ([]uintptr, error) {
 				pc++
 			}
 
-			pcs = append(pcs, uintptr(pc))
+			frames = append(frames, frame{currSymbol, uintptr(pc)})
 
 			// Done with this frame. Next line is a new frame.
 			prevSymbol = currSymbol
@@ -331,7 +605,7 @@ func parseStackPCs(crash string) ([]uintptr, error) {
 			symLine = true
 		}
 	}
-	return pcs, nil
+	return frames, nil
 }
 
 func min(x, y int) int {
@@ -6,9 +6,18 @@ package crashmonitor
 
 // This file opens back doors for testing.
 
+import "os"
+
 var (
 	WriteSentinel        = writeSentinel
 	TelemetryCounterName = telemetryCounterName
+	ParseStackPCs        = parseStackPCs
+	Sentinel             = sentinel
+
+	GoroutineHeadsCounterName = goroutineHeadsCounterName
+
+	PanicCounterNameFromText = panicCounterNameFromText
+	CrashReason              = crashReason
 )
 
 func SetIncrementCounter(f func(name string)) {
@@ -18,3 +27,17 @@ func SetIncrementCounter(f func(name string)) {
 func SetChildExitHook(f func()) {
 	childExitHook = f
 }
+
+var (
+	RecordCrashFiles = recordCrashFiles
+	ExecutableID     = executableID
+)
+
+// LockCrashFile locks f as the crash file of a running process does, and
+// reports whether crash files can be locked on this system.
+func LockCrashFile(f *os.File) (bool, error) {
+	if lockCrashFile == nil {
+		return false, nil
+	}
+	return true, lockCrashFile(f)
+}
@@ -97,7 +97,7 @@ func TestViaStderr(t *testing.T) {
 	// Standard panic.
 	t.Run("panic", func(t *testing.T) {
 		_, _, stderr := runSelf(t, "via-stderr.panic")
-		got, err := crashmonitor.TelemetryCounterName(stderr)
+		got, err := crashmonitor.TelemetryCounterName(stderr, crashmonitor.DefaultStackDepth)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -132,7 +132,7 @@ func TestViaStderr(t *testing.T) {
 	// Panic via trap.
 	t.Run("trap", func(t *testing.T) {
 		_, _, stderr := runSelf(t, "via-stderr.trap")
-		got, err := crashmonitor.TelemetryCounterName(stderr)
+		got, err := crashmonitor.TelemetryCounterName(stderr, crashmonitor.DefaultStackDepth)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -160,6 +160,99 @@ func TestViaStderr(t *testing.T) {
 	})
 }
 
+func TestRecordPanic(t *testing.T) {
+	var got []string
+	crashmonitor.SetIncrementCounter(func(name string) { got = append(got, name) })
+	t.Cleanup(func() {
+		crashmonitor.SetIncrementCounter(func(name string) { counter.New(name).Inc() })
+	})
+
+	func() {
+		defer func() {
+			crashmonitor.RecordPanic(recover(), debug.Stack())
+		}()
+		grandchildPanic()
+	}()
+	crashmonitor.RecordPanic(nil, nil) // not panicking: no counter
+
+	if len(got) != 1 {
+		t.Fatalf("recorded %d counters, want 1: %q", len(got), got)
+	}
+	name := counter.DecodeStack(got[0])
+	const wantPrefix = "crash/panic\n" +
+		"runtime.gopanic:"
+	if !strings.HasPrefix(name, wantPrefix) {
+		t.Errorf("got counter name <<%s>>, want prefix <<%s>>", name, wantPrefix)
+	}
+	if !strings.Contains(name, "\ngolang.org/x/telemetry/internal/crashmonitor_test.grandchildPanic:") {
+		t.Errorf("got counter name <<%s>>, want a frame for grandchildPanic", name)
+	}
+}
+
+func TestPanicCounterNameFromText(t *testing.T) {
+	const stack = `goroutine 1 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+main.main.func1()
+	/home/user/src/main.go:11 +0x1c
+panic({0x55cc08?, 0x56f150?})
+	/usr/local/go/src/runtime/panic.go:859 +0x125
+example.com/pkg.(*T).method(...)
+	/home/user/src/pkg/pkg.go:42
+main.main()
+	/home/user/src/main.go:16 +0x3e
+`
+	got, err := crashmonitor.PanicCounterNameFromText(stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "crash/panic\n" +
+		"runtime.gopanic:=859\n" +
+		"example.com/pkg.(*T).method:=42\n" +
+		"main.main:=16"
+	if got != want {
+		t.Errorf("got counter name <<%s>>, want <<%s>>", got, want)
+	}
+}
+
+func TestCrashReason(t *testing.T) {
+	tests := []struct {
+		crash string
+		want  string
+	}{
+		{"sentinel 1\nfatal error: concurrent map writes\n\ngoroutine 1 [running]:\n", "concurrent-map-writes"},
+		{"sentinel 1\nfatal error: concurrent map read and map write\n", "concurrent-map-read-and-write"},
+		{"sentinel 1\nfatal error: runtime: out of memory\n\nruntime stack:\n", "out-of-memory"},
+		{"sentinel 1\nruntime: goroutine stack exceeds 1000000000-byte limit\nfatal error: stack overflow\n", "stack-overflow"},
+		{"sentinel 1\nfatal error: all goroutines are asleep - deadlock!\n", "deadlock"},
+		{"sentinel 1\nfatal error: something new\n", "fatal-error"},
+		{"sentinel 1\nfatal error: unexpected signal during runtime execution\n[signal SIGBUS: bus error code=0x2 addr=0x0 pc=0x0]\n", "SIGBUS"},
+		{"sentinel 1\npanic: runtime error: index out of range [3] with length 2\n", "index-out-of-range"},
+		{"sentinel 1\npanic: runtime error: invalid memory address or nil pointer dereference\n[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x0]\n", "nil-dereference"},
+		{"sentinel 1\npanic: runtime error: something new\n", "runtime-error"},
+		{"sentinel 1\npanic: secret user data [recovered]\n\tpanic: more\n", "panic"},
+		{"sentinel 1\nSIGABRT: abort\nPC=0x0 m=0 sigcode=0\n", "SIGABRT"},
+		{"sentinel 1\nSIGUSR1: user defined signal 1\n", "signal"},
+		{"sentinel 1\n", "unknown"},
+	}
+	for _, test := range tests {
+		if got := crashmonitor.CrashReason([]byte(test.crash)); got != test.want {
+			t.Errorf("CrashReason(%q) = %q, want %q", test.crash, got, test.want)
+		}
+	}
+
+	// Check the reasons for real crash reports.
+	for entry, want := range map[string]string{
+		"via-stderr.panic": "panic",
+		"via-stderr.trap":  "nil-dereference",
+	} {
+		_, _, stderr := runSelf(t, entry)
+		if got := crashmonitor.CrashReason(stderr); got != want {
+			t.Errorf("CrashReason(%s) = %q, want %q", entry, got, want)
+		}
+	}
+}
+
 func waitForExitFile(t *testing.T, exitFile string) {
 	deadline := time.Now().Add(10 * time.Second)
 	for {
@@ -298,3 +391,175 @@ func sanitize(name string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+func init() {
+	// This entry point is dispatched here rather than in TestMain so as not
+	// to disturb the line numbers asserted by the tests above.
+	if os.Getenv("CRASHMONITOR_TEST_ENTRYPOINT") == "nosidecar.panic" {
+		telemetry.Start(telemetry.Config{
+			ReportCrashes: true,
+			NoSidecar:     true,
+			TelemetryDir:  os.Getenv("CRASHMONITOR_TELEMETRY_DIR"),
+		})
+		childPanic()
+	}
+}
+
+// TestInProcess tests crash reporting without a sidecar, which records the
+// crash saved by one process when another process of the same executable
+// starts.
+func TestInProcess(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	testenv.MustHaveExec(t)
+
+	if !crashmonitor.Supported() {
+		t.Skip("crashmonitor not supported")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	telemetryDir := t.TempDir()
+	cmd := exec.Command(exe)
+	cmd.Env = append(os.Environ(),
+		"CRASHMONITOR_TEST_ENTRYPOINT=nosidecar.panic",
+		"CRASHMONITOR_TELEMETRY_DIR="+telemetryDir,
+	)
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("program did not crash:\n%s", out)
+	}
+
+	crashDir := filepath.Join(telemetryDir, "local", "crash")
+	id, err := crashmonitor.ExecutableID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A crash-free file of this executable, as written by a process that is
+	// still running, a crash-free file of a process that has exited, and
+	// stale files of another executable and of a malformed crash.
+	running := filepath.Join(crashDir, "running.crash")
+	f, err := os.Create(running)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lockable, err := crashmonitor.LockCrashFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("executable " + id + "\nsentinel 1234\n"); err != nil {
+		t.Fatal(err)
+	}
+	exited := filepath.Join(crashDir, "exited.crash")
+	if err := os.WriteFile(exited, []byte("executable "+id+"\nsentinel 1234\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(crashDir, "stale.crash")
+	if err := os.WriteFile(stale, []byte("executable other\nsentinel 1234\ngoroutine 1 [running]:\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	malformed := filepath.Join(crashDir, "old.malformed")
+	if err := os.WriteFile(malformed, []byte("executable other\nsentinel 1234\n?\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-365 * 24 * time.Hour)
+	for _, file := range []string{stale, malformed} {
+		if err := os.Chtimes(file, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	crashmonitor.SetIncrementCounter(func(name string) { got = append(got, name) })
+	crashmonitor.RecordCrashFiles(crashDir, id, time.Now(), crashmonitor.Options{})
+
+	if len(got) != 2 || got[0] != "crash/reason:panic" {
+		t.Fatalf("recorded counters %q, want crash/reason:panic and a stack", got)
+	}
+	if stack := counter.DecodeStack(got[1]); !strings.Contains(stack, "crashmonitor_test.grandchildPanic:=79\n") {
+		t.Errorf("recorded stack counter <<%s>>, want grandchildPanic", stack)
+	}
+	files, err := filepath.Glob(filepath.Join(crashDir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{running}
+	if !lockable {
+		// The file of the exited process looks like that of a running one.
+		want = []string{exited, running}
+	}
+	if strings.Join(files, "\n") != strings.Join(want, "\n") {
+		t.Errorf("after recording, crash files are %q, want %q", files, want)
+	}
+}
+
+// TestStackDepth checks that the crash stack is limited to the requested
+// depth, and that the goroutine heads begin with the crashing goroutine.
+func TestStackDepth(t *testing.T) {
+	_, _, stderr := runSelf(t, "via-stderr.panic")
+
+	got, err := crashmonitor.TelemetryCounterName(stderr, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "crash/crash\n" +
+		"runtime.gopanic:--\n" +
+		"golang.org/x/telemetry/internal/crashmonitor_test.grandchildPanic:=79"
+	if got := sanitize(counter.DecodeStack(got)); got != want {
+		t.Errorf("got counter name <<%s>>, want <<%s>>", got, want)
+	}
+
+	heads := crashmonitor.GoroutineHeadsCounterName(stderr, crashmonitor.DefaultStackDepth)
+	frames := strings.Split(counter.DecodeStack(heads), "\n")
+	if len(frames) < 2 || frames[0] != "crash/goroutine-heads" ||
+		frames[1] != "golang.org/x/telemetry/internal/crashmonitor_test.grandchildPanic:=79" {
+		t.Errorf("got goroutine heads <<%s>>, want crash/goroutine-heads starting with grandchildPanic", counter.DecodeStack(heads))
+	}
+}
+
+func TestParseStackPCsCrashingGoroutine(t *testing.T) {
+	sentinel := fmt.Sprintf("sentinel %x\n", crashmonitor.Sentinel())
+	for _, test := range []struct {
+		name  string
+		crash string
+		want  []uintptr
+	}{
+		{
+			name: "first running",
+			crash: "goroutine 1 [chan receive]:\n" +
+				"main.wait()\n\t/src/main.go:1 +0x1 sp=0x0 fp=0x0 pc=0x10\n\n" +
+				"goroutine 2 [running]:\n" +
+				"main.f()\n\t/src/main.go:2 +0x1 sp=0x0 fp=0x0 pc=0x20\n\n" +
+				"goroutine 3 [running]:\n" +
+				"main.g()\n\t/src/main.go:3 +0x1 sp=0x0 fp=0x0 pc=0x30\n",
+			want: []uintptr{0x20},
+		},
+		{
+			name: "panicking",
+			crash: "goroutine 2 [running]:\n" +
+				"main.f()\n\t/src/main.go:2 +0x1 sp=0x0 fp=0x0 pc=0x20\n\n" +
+				"goroutine 3 [running]:\n" +
+				"panic({0x0, 0x0})\n\t/goroot/src/runtime/panic.go:1 +0x1 sp=0x0 fp=0x0 pc=0x40\n" +
+				"main.g()\n\t/src/main.go:3 +0x1 sp=0x0 fp=0x0 pc=0x30\n" +
+				"created by main.main in goroutine 1\n\t/src/main.go:4 +0x1 sp=0x0 fp=0x0 pc=0x50\n",
+			want: []uintptr{0x40, 0x30},
+		},
+		{
+			name: "none running",
+			crash: "goroutine 1 [select (no cases)]:\n" +
+				"main.main()\n\t/src/main.go:1 +0x1 sp=0x0 fp=0x0 pc=0x10\n",
+			want: nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := crashmonitor.ParseStackPCs(sentinel + test.crash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Errorf("ParseStackPCs = %#x, want %#x", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crashmonitor
+
+// This file records panics that the application recovers from.
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"golang.org/x/telemetry/internal/counter"
+)
+
+// panicPrefix appears at the start of the names of counters recorded by
+// RecordPanic. It differs from the prefix of fatal crashes, so that the two
+// can be told apart.
+const panicPrefix = "crash/panic"
+
+// maxPanicFrames is the maximum number of frames in a recorded panic stack,
+// as for fatal crashes.
+const maxPanicFrames = 16
+
+// RecordPanic increments a stack counter for a panic that was recovered by
+// the application. It should be called from the deferred function that
+// recovered the panic, with the recovered value and the goroutine's stack as
+// reported by [runtime/debug.Stack].
+//
+// The counter name is computed from the program counters of the panicking
+// goroutine, which are still on the stack while the deferred function runs,
+// and has the same form as the name of a fatal crash counter, but with the
+// prefix "crash/panic". If RecordPanic is called elsewhere, the counter name
+// is instead derived from the function names and absolute line numbers in
+// stack. In either case, neither the recovered value nor file names are
+// recorded.
+func RecordPanic(recovered any, stack []byte) {
+	if recovered == nil {
+		return // not panicking
+	}
+	pcs := make([]uintptr, 64)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	if name, ok := panicCounterName(pcs); ok {
+		incrementCounter(name)
+		return
+	}
+	name, err := panicCounterNameFromText(string(stack))
+	if err != nil {
+		incrementCounter("crash/malformed")
+		return
+	}
+	incrementCounter(name)
+}
+
+// panicCounterName returns the counter name for the panicking frames of pcs,
+// which start at the runtime's panic function. It reports false if pcs does
+// not contain a panic.
+func panicCounterName(pcs []uintptr) (string, bool) {
+	for i, pc := range pcs {
+		// pc is a return PC; back up into the call instruction.
+		if f := runtime.FuncForPC(pc - 1); f != nil && f.Name() == "runtime.gopanic" {
+			pcs = pcs[i:]
+			pcs = pcs[:min(len(pcs), maxPanicFrames)]
+			return counter.EncodeStack(pcs, panicPrefix), true
+		}
+	}
+	return "", false
+}
+
+// panicCounterNameFromText returns a counter name for the panicking frames of
+// a goroutine stack in the format of runtime/debug.Stack. Lacking program
+// counters, each frame is recorded as its symbol and absolute line number,
+// as EncodeStack does for frames that have no function information.
+func panicCounterNameFromText(stack string) (string, error) {
+	var (
+		locs    []string
+		lines   = strings.Split(stack, "\n")
+		symbol  string
+		panicky = !strings.Contains(stack, "\npanic(") // if no panic frame, use the whole stack
+	)
+	for _, line := range lines {
+		switch {
+		case line == "", strings.HasPrefix(line, "goroutine "):
+			continue
+		case strings.HasPrefix(line, "created by "):
+			// End of the goroutine's frames.
+		case !strings.HasPrefix(line, "\t"):
+			// SYMBOL(ARGS)
+			i := strings.LastIndex(line, "(")
+			if i <= 0 {
+				return "", fmt.Errorf("no symbol for stack frame: %s", line)
+			}
+			symbol = line[:i]
+			if symbol == "panic" {
+				symbol = "runtime.gopanic"
+				panicky = true
+			}
+			continue
+		default:
+			// \tFILE:LINE +0xRELPC
+			if !panicky {
+				continue
+			}
+			fileLine, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+			i := strings.LastIndex(fileLine, ":")
+			if i < 0 || symbol == "" {
+				return "", fmt.Errorf("no line for stack frame: %s", line)
+			}
+			locs = append(locs, fmt.Sprintf("%s:=%s", symbol, fileLine[i+1:]))
+			symbol = ""
+			continue
+		}
+		break
+	}
+	if len(locs) == 0 {
+		return "", fmt.Errorf("no frames in stack")
+	}
+	locs = locs[:min(len(locs), maxPanicFrames)]
+	return panicPrefix + "\n" + strings.Join(locs, "\n"), nil
+}
@@ -21,6 +21,7 @@ import (
 	icounter "golang.org/x/telemetry/internal/counter"
 	"golang.org/x/telemetry/internal/telemetry"
 	"golang.org/x/telemetry/internal/testenv"
+	"golang.org/x/telemetry/internal/uploadable"
 )
 
 func TestRunProg(t *testing.T) {
@@ -192,14 +193,14 @@ func stringify(a any) string {
 // For simplicity in the comparison code, the returned maps represent a stack counter
 // with its counter name prefix and "\n". For example, if there are "stackcounter\npkg.F:..."
 // and "stackcounter\npkg.G:..", "stackcounter\n" will hold the sum of those counters.
-func parseCounters(uc *telemetry.UploadConfig, telemetryDir string) (uploadable, notUploadable map[string]uint64, _ error) {
+func parseCounters(uc *telemetry.UploadConfig, telemetryDir string) (included, excluded map[string]uint64, _ error) {
 	cfg := config.NewConfig(uc)
 	localDir := filepath.Join(telemetryDir, "local")
 	entries, err := os.ReadDir(localDir)
 	if err != nil {
 		return nil, nil, err
 	}
-	uploadable, notUploadable = make(map[string]uint64), make(map[string]uint64)
+	included, excluded = make(map[string]uint64), make(map[string]uint64)
 	for _, entry := range entries {
 		if entry.IsDir() || filepath.Ext(entry.Name()) != ".count" {
 			continue
@@ -213,31 +214,20 @@ func parseCounters(uc *telemetry.UploadConfig, telemetryDir string) (uploadable,
 		if err != nil { // ignore unparsable file
 			continue
 		}
-		// The following is temporary until the upload package implements the exact same logic.
-		// TODO(hyangah): replace with the shared logic between the uploader and the local viewer.
-		maybeUploadable := true &&
-			cfg.HasGOOS(parsed.Meta["GOOS"]) &&
-			cfg.HasGOARCH(parsed.Meta["GOARCH"]) &&
-			cfg.HasGoVersion(parsed.Meta["GoVersion"]) &&
-			cfg.HasProgram(parsed.Meta["Program"]) &&
-			cfg.HasVersion(parsed.Meta["Program"], parsed.Meta["Version"])
-
+		meta := uploadable.MetaOf(parsed.Meta)
 		for k, v := range parsed.Count {
-			counterPrefix, _, isStackCounter := strings.Cut(k, "\n")
-			isUploadable := maybeUploadable
+			d, _ := uploadable.Decide(cfg, meta, k)
+			isUploadable := d == uploadable.Upload
 			key := k
-			if isStackCounter {
-				isUploadable = isUploadable && cfg.HasStack(parsed.Meta["Program"], counterPrefix)
-				key = counterPrefix + "\n"
-			} else {
-				isUploadable = isUploadable && cfg.HasCounter(parsed.Meta["Program"], k)
+			if _, _, isStackCounter := strings.Cut(k, "\n"); isStackCounter {
+				key = uploadable.ConfigName(k) + "\n"
 			}
 			if isUploadable {
-				uploadable[key] = uploadable[key] + v
+				included[key] = included[key] + v
 			} else {
-				notUploadable[key] = notUploadable[key] + v
+				excluded[key] = excluded[key] + v
 			}
 		}
 	}
-	return uploadable, notUploadable, nil
+	return included, excluded, nil
 }
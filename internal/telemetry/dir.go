@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -30,7 +31,7 @@ var Default Dir
 
 // A Dir holds paths to telemetry data inside a directory.
 type Dir struct {
-	dir, local, upload, debug, modefile string
+	dir, local, upload, debug, quarantine, modefile, promptfile string
 }
 
 // NewDir creates a new Dir encapsulating paths in the given dir.
@@ -39,20 +40,44 @@ type Dir struct {
 // the telemetry directory layout.
 func NewDir(dir string) Dir {
 	return Dir{
-		dir:      dir,
-		local:    filepath.Join(dir, "local"),
-		upload:   filepath.Join(dir, "upload"),
-		debug:    filepath.Join(dir, "debug"),
-		modefile: filepath.Join(dir, "mode"),
+		dir:        dir,
+		local:      filepath.Join(dir, "local"),
+		upload:     filepath.Join(dir, "upload"),
+		debug:      filepath.Join(dir, "debug"),
+		quarantine: filepath.Join(dir, "local", "quarantine"),
+		modefile:   filepath.Join(dir, "mode"),
+		promptfile: filepath.Join(dir, "prompt"),
 	}
 }
 
+// DirEnv is the environment variable that, if set, overrides the default
+// telemetry directory, os.UserConfigDir()/go/telemetry.
+//
+// Its value must be an absolute path: it is inherited by child processes,
+// such as the telemetry sidecar, which may run in a different working
+// directory. A relative path disables telemetry rather than risk writing
+// data in an unexpected place.
+const DirEnv = "GOTELEMETRYDIR"
+
 func init() {
+	if dir := defaultDir(); dir != "" {
+		Default = NewDir(dir)
+	}
+}
+
+// defaultDir returns the default telemetry directory, or "" if there is none.
+func defaultDir() string {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		if !filepath.IsAbs(dir) {
+			return ""
+		}
+		return filepath.Clean(dir)
+	}
 	cfgDir, err := os.UserConfigDir()
 	if err != nil {
-		return
+		return ""
 	}
-	Default = NewDir(filepath.Join(cfgDir, "go", "telemetry"))
+	return filepath.Join(cfgDir, "go", "telemetry")
 }
 
 func (d Dir) Dir() string {
@@ -71,10 +96,20 @@ func (d Dir) DebugDir() string {
 	return d.debug
 }
 
+// QuarantineDir returns the directory holding count files that the uploader
+// could not parse.
+func (d Dir) QuarantineDir() string {
+	return d.quarantine
+}
+
 func (d Dir) ModeFile() string {
 	return d.modefile
 }
 
+func (d Dir) PromptFile() string {
+	return d.promptfile
+}
+
 // SetMode updates the telemetry mode with the given mode.
 // Acceptable values for mode are "on", "off", or "local".
 //
@@ -146,6 +181,55 @@ func (d Dir) Mode() (string, time.Time) {
 	return mode, time.Time{}
 }
 
+// Prompts returns the number of times the user has been prompted to choose
+// the telemetry mode, as recorded by RecordPrompt, and the date of the most
+// recent prompt.
+//
+// If the user has never been prompted, or the prompt file cannot be read,
+// Prompts returns 0 and the zero time.
+func (d Dir) Prompts() (int, time.Time) {
+	if d.promptfile == "" {
+		return 0, time.Time{}
+	}
+	data, err := os.ReadFile(d.promptfile)
+	if err != nil {
+		return 0, time.Time{}
+	}
+	// The prompt file contains the count and date, as in "2 2024-09-26".
+	count, date, _ := strings.Cut(strings.TrimSpace(string(data)), " ")
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return 0, time.Time{}
+	}
+	asof, err := time.Parse(DateOnly, date)
+	if err != nil {
+		asof = time.Time{}
+	}
+	return n, asof
+}
+
+// RecordPrompt records that the user has been prompted to choose the
+// telemetry mode, incrementing the count reported by Prompts.
+func (d Dir) RecordPrompt() error {
+	return d.RecordPromptAsOf(time.Now())
+}
+
+// RecordPromptAsOf is like RecordPrompt, but accepts an explicit time to use
+// as the date of the prompt. This exists only for testing purposes.
+func (d Dir) RecordPromptAsOf(asofTime time.Time) error {
+	if d.promptfile == "" {
+		return fmt.Errorf("cannot determine telemetry prompt file name")
+	}
+	if err := os.MkdirAll(filepath.Dir(d.promptfile), 0755); err != nil {
+		return fmt.Errorf("cannot create a telemetry prompt file: %w", err)
+	}
+	// Concurrent prompts by different programs may be counted once, which
+	// is harmless: the count only distinguishes first prompts from others.
+	n, _ := d.Prompts()
+	data := fmt.Sprintf("%d %s", n+1, asofTime.UTC().Format(DateOnly))
+	return os.WriteFile(d.promptfile, []byte(data), 0666)
+}
+
 // DisabledOnPlatform indicates whether telemetry is disabled
 // due to bugs in the current platform.
 //
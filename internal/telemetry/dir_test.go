@@ -7,6 +7,7 @@ package telemetry
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -27,6 +28,34 @@ func TestDefaults(t *testing.T) {
 	}
 }
 
+func TestDefaultDir(t *testing.T) {
+	abs := t.TempDir()
+	cfg := t.TempDir()
+	// Point os.UserConfigDir at cfg, on the platforms where that is possible.
+	t.Setenv("XDG_CONFIG_HOME", cfg)
+	t.Setenv("AppData", cfg)
+	userDefault := ""
+	if cfgDir, err := os.UserConfigDir(); err == nil {
+		userDefault = filepath.Join(cfgDir, "go", "telemetry")
+	}
+
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", userDefault},
+		{abs, abs},                 // takes precedence over os.UserConfigDir
+		{abs + "/./x/..", abs},     // cleaned
+		{"relative/telemetry", ""}, // rejected
+	}
+	for _, tt := range tests {
+		t.Setenv(DirEnv, tt.env)
+		if got := defaultDir(); got != tt.want {
+			t.Errorf("with %s=%q, defaultDir() = %q, want %q", DirEnv, tt.env, got, tt.want)
+		}
+	}
+}
+
 func TestTelemetryModeWithNoModeConfig(t *testing.T) {
 	tests := []struct {
 		dir  Dir
@@ -97,3 +126,31 @@ func TestMode(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordPrompt(t *testing.T) {
+	dir := NewDir(t.TempDir())
+	if n, asof := dir.Prompts(); n != 0 || !asof.IsZero() {
+		t.Fatalf("Prompts() = %d, %v before any prompt, want 0, zero time", n, asof)
+	}
+	first := time.Date(2024, time.September, 26, 12, 0, 0, 0, time.UTC)
+	second := first.Add(48 * time.Hour)
+	for _, asof := range []time.Time{first, second} {
+		if err := dir.RecordPromptAsOf(asof); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := time.Date(2024, time.September, 28, 0, 0, 0, 0, time.UTC)
+	if n, asof := dir.Prompts(); n != 2 || asof != want {
+		t.Errorf("Prompts() = %d, %v, want 2, %v", n, asof, want)
+	}
+
+	if err := os.WriteFile(dir.PromptFile(), []byte("bogus"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := dir.Prompts(); n != 0 {
+		t.Errorf("Prompts() = %d for a malformed prompt file, want 0", n)
+	}
+	if err := (Dir{}).RecordPrompt(); err == nil {
+		t.Error("RecordPrompt succeeded for an uninitialized Dir")
+	}
+}
@@ -23,12 +23,55 @@ type ProgramConfig struct {
 	Versions []string        // versions present in a counterconfig
 	Counters []CounterConfig `json:",omitempty"`
 	Stacks   []CounterConfig `json:",omitempty"`
+	Events   []EventConfig   `json:",omitempty"`
 }
 
 type CounterConfig struct {
-	Name  string  // The "collapsed" counter: <chart>:{<bucket1>,<bucket2>,...}
-	Rate  float64 // If X <= Rate, report this counter
-	Depth int     `json:",omitempty"` // for stack counters
+	Name  string      // The "collapsed" counter: <chart>:{<bucket1>,<bucket2>,...}
+	Rate  float64     // If X <= Rate, report this counter
+	Depth int         `json:",omitempty"` // for stack counters
+	Kind  CounterKind `json:",omitempty"` // unset in configs generated before kinds were recorded
+}
+
+// A CounterKind identifies the kind of a counter in an upload config, which
+// determines how it is reported and charted.
+type CounterKind string
+
+const (
+	// A partition counter is a bucket of a chart whose buckets partition
+	// the reporters of a program, such as "gopls/client:vscode".
+	KindPartition CounterKind = "partition"
+
+	// A histogram counter is a partition counter whose buckets are ranges of
+	// values named by their upper bounds, such as "gopls/completion/latency:<50ms".
+	KindHistogram CounterKind = "histogram"
+
+	// A boolean counter is created by counter.NewBool, and is charted as the
+	// fraction of reporters that recorded it.
+	KindBoolean CounterKind = "boolean"
+
+	// A stack counter is created by counter.NewStack, and is reported with
+	// the stacks that incremented it.
+	KindStack CounterKind = "stack"
+
+	// An event counter is created by counter.NewEvent, and is configured by
+	// an EventConfig rather than a CounterConfig.
+	KindEvent CounterKind = "event"
+)
+
+// An EventConfig describes an event counter created by counter.NewEvent.
+// Only occurrences whose attributes match Attrs exactly, in order, and whose
+// values are all enumerated in the config are uploaded.
+type EventConfig struct {
+	Name  string
+	Attrs []AttrConfig
+	Rate  float64 // If X <= Rate, report this event
+}
+
+// An AttrConfig describes an attribute of an event counter.
+type AttrConfig struct {
+	Name   string
+	Values []string // the values that may be uploaded
 }
 
 // A Report is the weekly aggregate of counters.
@@ -48,4 +91,5 @@ type ProgramReport struct {
 	GOARCH    string
 	Counters  map[string]int64
 	Stacks    map[string]int64
+	Events    map[string]int64 `json:",omitempty"` // event counters, named as in count files
 }
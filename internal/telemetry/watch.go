@@ -0,0 +1,40 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+// WatchMode starts a goroutine that polls the mode file every interval,
+// calling f with the new mode each time it changes, until ctx is done.
+//
+// The mode at the time of the call is the baseline: f is not called for it.
+// Changes to the mode date alone, as from setting the same mode again, are
+// not reported. Nor are invalid modes, such as the empty mode observed while
+// SetMode is rewriting the mode file.
+func (d Dir) WatchMode(ctx context.Context, interval time.Duration, f func(mode string)) {
+	last, _ := d.Mode()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			mode, _ := d.Mode()
+			switch mode {
+			case "on", "off", "local":
+				if mode != last {
+					last = mode
+					f(mode)
+				}
+			}
+		}
+	}()
+}
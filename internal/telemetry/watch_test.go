@@ -0,0 +1,42 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchMode(t *testing.T) {
+	dir := NewDir(t.TempDir())
+	if err := dir.SetMode("on"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	modes := make(chan string, 10)
+	dir.WatchMode(ctx, time.Millisecond, func(mode string) { modes <- mode })
+
+	next := func() string {
+		t.Helper()
+		select {
+		case mode := <-modes:
+			return mode
+		case <-time.After(30 * time.Second):
+			t.Fatal("timed out waiting for mode change")
+			return ""
+		}
+	}
+	for _, want := range []string{"off", "local"} {
+		if err := dir.SetMode(want); err != nil {
+			t.Fatal(err)
+		}
+		if got := next(); got != want {
+			t.Errorf("after SetMode(%q), WatchMode reported %q", want, got)
+		}
+	}
+}
@@ -3,13 +3,33 @@
 // license that can be found in the LICENSE file.
 
 // Package unionfs allows multiple file systems to be read as a union.
+//
+// The file systems of a union are layers, listed from top to bottom. A file
+// in a layer shadows the file or directory of the same name in the layers
+// below it. Directories are merged: a directory of the union lists the
+// entries of the directory in each layer, down to the first layer in which
+// its name is not a directory.
+//
+// If the top layer is a WritableFS, such as one returned by DirFS, files
+// written to the union are written to it, and so shadow the files of the
+// layers below.
 package unionfs
 
 import (
+	"errors"
+	"io"
 	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
 )
 
-var _ fs.ReadDirFS = FS{}
+var (
+	_ fs.ReadDirFS = FS{}
+	_ fs.StatFS    = FS{}
+	_ WritableFS   = FS{}
+)
 
 // A FS is an FS presenting the union of the file systems in the slice. If
 // multiple file systems provide a particular file, Open uses the FS listed
@@ -17,7 +37,8 @@ var _ fs.ReadDirFS = FS{}
 type FS []fs.FS
 
 // Sub returns an FS corresponding to the merged subtree rooted at a set of
-// fsys's dirs.
+// fsys's dirs. The layers of the result are writable if fsys is a writable
+// file system that implements fs.SubFS, as those returned by DirFS do.
 func Sub(fsys fs.FS, dirs ...string) (FS, error) {
 	var subs FS
 	for _, dir := range dirs {
@@ -38,23 +59,83 @@ func (fsys FS) Open(name string) (fs.File, error) {
 	for _, sub := range fsys {
 		f, err := sub.Open(name)
 		if err == nil {
+			info, err := f.Stat()
+			if err == nil && info.IsDir() {
+				return &dirFile{File: f, fsys: fsys, name: name}, nil
+			}
 			return f, nil
 		}
 		if errOut == nil {
 			errOut = err
 		}
+		if shadowed(sub, name) {
+			break
+		}
+	}
+	return nil, errOut
+}
+
+// shadowed reports whether a parent directory of name is a file in fsys,
+// which shadows name in the layers below fsys.
+func shadowed(fsys fs.FS, name string) bool {
+	if !fs.ValidPath(name) {
+		return false
+	}
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if info, err := fs.Stat(fsys, dir); err == nil {
+			return !info.IsDir()
+		}
+	}
+	return false
+}
+
+func (fsys FS) Stat(name string) (fs.FileInfo, error) {
+	var errOut error
+	for _, sub := range fsys {
+		info, err := fs.Stat(sub, name)
+		if err == nil {
+			return info, nil
+		}
+		if errOut == nil {
+			errOut = err
+		}
+		if shadowed(sub, name) {
+			break
+		}
 	}
 	return nil, errOut
 }
 
+// ReadDir reads the named directory of each layer, down to the first layer
+// in which name, or one of its parents, is a file, and returns the entries
+// of the union sorted by filename. Entries of upper layers shadow those of
+// lower layers.
 func (fsys FS) ReadDir(name string) ([]fs.DirEntry, error) {
 	var all []fs.DirEntry
 	var seen map[string]bool // seen[name] is true if name is listed in all; lazily initialized
 	var errOut error
+	found := false // whether name was found in a layer
 	for _, sub := range fsys {
+		info, err := fs.Stat(sub, name)
+		if err != nil {
+			if errOut == nil {
+				errOut = err
+			}
+			if shadowed(sub, name) {
+				break
+			}
+			continue
+		}
+		if !info.IsDir() {
+			if !found {
+				return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+			}
+			break // name is shadowed in this and lower layers
+		}
+		found = true
 		list, err := fs.ReadDir(sub, name)
 		if err != nil {
-			errOut = err
+			return nil, err
 		}
 		if len(all) == 0 {
 			all = append(all, list...)
@@ -75,8 +156,96 @@ func (fsys FS) ReadDir(name string) ([]fs.DirEntry, error) {
 			}
 		}
 	}
-	if len(all) > 0 {
-		return all, nil
+	if !found {
+		return nil, errOut
 	}
-	return nil, errOut
+	sort.Slice(all, func(i, j int) bool { return all[i].Name() < all[j].Name() })
+	return all, nil
+}
+
+// A dirFile is a directory of the union opened by Open, whose entries are
+// those of the directory in each layer.
+type dirFile struct {
+	fs.File
+	fsys    FS
+	name    string
+	entries []fs.DirEntry // read on the first call to ReadDir
+	read    bool
+}
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		list := d.entries
+		d.entries = nil
+		return list, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	list := d.entries[:n]
+	d.entries = d.entries[n:]
+	return list, nil
+}
+
+// A WritableFS is a file system whose files can be written.
+type WritableFS interface {
+	fs.FS
+
+	// WriteFile writes data to the named file, creating it and its parent
+	// directories if necessary, as os.WriteFile does.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// WriteFile writes data to the named file of the top layer of fsys, where it
+// shadows the file of the same name of the layers below. It reports an error
+// if the top layer is not a WritableFS.
+func (fsys FS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if len(fsys) > 0 {
+		if w, ok := fsys[0].(WritableFS); ok {
+			return w.WriteFile(name, data, perm)
+		}
+	}
+	return &fs.PathError{Op: "write", Path: name, Err: fs.ErrPermission}
+}
+
+// DirFS returns a writable file system for the tree of files rooted at the
+// directory dir, which is read as os.DirFS reads it.
+func DirFS(dir string) WritableFS {
+	return dirFS(dir)
+}
+
+type dirFS string
+
+func (dir dirFS) Open(name string) (fs.File, error) {
+	return os.DirFS(string(dir)).Open(name)
+}
+
+func (dir dirFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(os.DirFS(string(dir)), name)
+}
+
+func (dir dirFS) Sub(name string) (fs.FS, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "sub", Path: name, Err: fs.ErrInvalid}
+	}
+	return dirFS(filepath.Join(string(dir), filepath.FromSlash(name))), nil
+}
+
+func (dir dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	file := filepath.Join(string(dir), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Join(string(dir), filepath.FromSlash(path.Dir(name))), 0o777); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, perm)
 }
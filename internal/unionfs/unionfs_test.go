@@ -5,10 +5,14 @@
 package unionfs
 
 import (
+	"errors"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"testing/fstest"
 )
 
 func TestFS_Open(t *testing.T) {
@@ -111,3 +115,94 @@ func TestFS_ReadDir(t *testing.T) {
 		})
 	}
 }
+
+func TestFS_Layers(t *testing.T) {
+	fsys := FS{
+		fstest.MapFS{
+			"a":       {Data: []byte("a from 0")},
+			"d/x":     {Data: []byte("x from 0")},
+			"shadow":  {Data: []byte("file from 0")},
+			"e/f/g":   {Data: []byte("g from 0")},
+			"onlytop": {Data: []byte("onlytop")},
+		},
+		fstest.MapFS{
+			"a":        {Data: []byte("a from 1")},
+			"d/y":      {Data: []byte("y from 1")},
+			"shadow/z": {Data: []byte("z from 1")},
+		},
+		fstest.MapFS{
+			"d/x":    {Data: []byte("x from 2")},
+			"d/z":    {Data: []byte("z from 2")},
+			"e/f/h":  {Data: []byte("h from 2")},
+			"bottom": {Data: []byte("bottom")},
+		},
+	}
+	if err := fstest.TestFS(fsys, "a", "d/x", "d/y", "d/z", "shadow", "e/f/g", "e/f/h", "onlytop", "bottom"); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"a":      "a from 0",
+		"d/x":    "x from 0",
+		"d/z":    "z from 2",
+		"shadow": "file from 0",
+		"bottom": "bottom",
+	} {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil || string(data) != want {
+			t.Errorf("ReadFile(%q) = %q, %v, want %q", name, data, err, want)
+		}
+	}
+	// The file shadow in the top layer hides the directory below it.
+	if _, err := fs.ReadFile(fsys, "shadow/z"); err == nil {
+		t.Errorf("ReadFile(%q) succeeded, want error", "shadow/z")
+	}
+	if _, err := fsys.ReadDir("shadow"); err == nil {
+		t.Errorf("ReadDir(%q) succeeded, want error", "shadow")
+	}
+}
+
+func TestFS_WriteFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"top", "bottom"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bottom", "base.tmpl"), []byte("embedded"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	fsys, err := Sub(DirFS(dir), "top", "bottom")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fsys.WriteFile("base.tmpl", []byte("edited"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("static/new.css", []byte("body {}"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"base.tmpl":      "edited",
+		"static/new.css": "body {}",
+	} {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil || string(data) != want {
+			t.Errorf("ReadFile(%q) = %q, %v, want %q", name, data, err, want)
+		}
+	}
+	// Writes go to the top layer, leaving the layers below unchanged.
+	data, err := os.ReadFile(filepath.Join(dir, "bottom", "base.tmpl"))
+	if err != nil || string(data) != "embedded" {
+		t.Errorf("bottom layer base.tmpl = %q, %v, want %q", data, err, "embedded")
+	}
+
+	if err := fsys.WriteFile("../escape", nil, 0o666); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("WriteFile(%q) = %v, want %v", "../escape", err, fs.ErrInvalid)
+	}
+	readOnly := FS{os.DirFS(dir)}
+	if err := readOnly.WriteFile("x", nil, 0o666); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("WriteFile to a read-only union = %v, want %v", err, fs.ErrPermission)
+	}
+}
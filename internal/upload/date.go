@@ -5,8 +5,10 @@
 package upload
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -22,7 +24,7 @@ var distantPast = 21 * 24 * time.Hour
 func (u *uploader) tooOld(date string, uploadStartTime time.Time) bool {
 	t, err := time.Parse(telemetry.DateOnly, date)
 	if err != nil {
-		u.logger.Printf("tooOld: %v", err)
+		u.logger.Warn("tooOld: bad date", "date", date, "err", err)
 		return false
 	}
 	age := uploadStartTime.Sub(t)
@@ -56,6 +58,118 @@ func (u *uploader) counterDateSpan(fname string) (begin, end time.Time, _ error)
 	return begin, end, nil
 }
 
+// clockSkewCounter is the counter added to the local reports of programs
+// whose count files were re-dated due to clock skew. Its value is the number
+// of such count files. Like other counters, it is dropped from uploaded
+// reports unless the upload config includes it.
+const clockSkewCounter = "upload/clockskew"
+
+// maxSpan is the longest valid span of a count file: files begin on the day
+// they are created, and expire on the next weekend day.
+const maxSpan = 7 * 24 * time.Hour
+
+// countFileSpan is like counterDateSpan, but additionally cross-checks the
+// span recorded in the count file against the file's modification time and
+// the weekends file, to detect count files written with a badly skewed clock.
+//
+// If the span is inconsistent, it is logged. If the uploader tolerates clock
+// skew, the returned span is instead derived from the file's modification
+// time, and the file is recorded as skewed so that its report is annotated
+// with [clockSkewCounter]. Otherwise, the recorded span is returned as is.
+func (u *uploader) countFileSpan(fname string) (begin, end time.Time, _ error) {
+	if s, ok := u.spans.Load(fname); ok {
+		s := s.(fileSpan)
+		return s.begin, s.end, nil
+	}
+	begin, end, err := u.counterDateSpan(fname)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	fi, err := os.Stat(fname)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	span := fileSpan{begin: begin, end: end}
+	weekend, haveWeekend := u.weekend()
+	if reason := clockSkew(begin, end, fi.ModTime().UTC(), weekend, haveWeekend); reason != "" {
+		u.logger.Warn("count file span is inconsistent with local clock", "file", filepath.Base(fname), "reason", reason, "begin", begin, "end", end, "mtime", fi.ModTime(), "tolerated", u.tolerateClockSkew)
+		if u.tolerateClockSkew {
+			span.begin, span.end = mtimeSpan(fi.ModTime().UTC(), weekend, haveWeekend)
+			span.skewed = true
+		}
+	}
+	u.spans.Store(fname, span)
+	return span.begin, span.end, nil
+}
+
+// A fileSpan is the span of a count file, as computed by countFileSpan.
+type fileSpan struct {
+	begin, end time.Time
+	skewed     bool // span was derived from the modification time
+}
+
+// isSkewed reports whether the count file was re-dated by countFileSpan.
+func (u *uploader) isSkewed(fname string) bool {
+	s, ok := u.spans.Load(fname)
+	return ok && s.(fileSpan).skewed
+}
+
+// clockSkew returns a description of the inconsistency between the span
+// recorded in a count file and its modification time or the configured
+// weekend, or "" if they are consistent.
+//
+// Count files are modified only while they are active, so a file last modified
+// well outside its recorded span was written while the clock was skewed.
+func clockSkew(begin, end, mtime time.Time, weekend time.Weekday, haveWeekend bool) string {
+	const slack = 24 * time.Hour // allow for time zones and delayed flushes
+	switch {
+	case !end.After(begin):
+		return "span ends before it begins"
+	case end.Sub(begin) > maxSpan:
+		return "span is longer than a week"
+	case haveWeekend && end.Weekday() != weekend:
+		return "span does not end on the weekend day"
+	case mtime.Before(begin.Add(-slack)):
+		return "modified before span begins"
+	case mtime.After(end.Add(slack)):
+		return "modified after span ends"
+	}
+	return ""
+}
+
+// mtimeSpan returns the span of a count file last modified at mtime, as it
+// would have been recorded with an accurate clock: it ends on the next weekend
+// day after mtime, and begins a week earlier.
+func mtimeSpan(mtime time.Time, weekend time.Weekday, haveWeekend bool) (begin, end time.Time) {
+	year, month, day := mtime.Date()
+	incr := 7
+	if haveWeekend {
+		incr = int(weekend - mtime.Weekday())
+		if incr <= 0 {
+			incr += 7
+		}
+	}
+	end = time.Date(year, month, day+incr, 0, 0, 0, 0, time.UTC)
+	return end.Add(-maxSpan), end
+}
+
+// weekend returns the weekday recorded in the weekends file, and whether it
+// could be read.
+func (u *uploader) weekend() (time.Weekday, bool) {
+	u.weekendOnce.Do(func() {
+		buf, err := os.ReadFile(filepath.Join(u.dir.LocalDir(), "weekends"))
+		if err != nil {
+			return
+		}
+		buf = bytes.TrimSpace(buf)
+		if len(buf) == 0 || buf[0] < '0' || buf[0] > '6' {
+			return
+		}
+		u.weekendDay, u.haveWeekend = time.Weekday(buf[0]-'0'), true
+	})
+	return u.weekendDay, u.haveWeekend
+}
+
 // avoid parsing count files multiple times
 type parsedCache struct {
 	mu sync.Mutex
@@ -423,3 +423,136 @@ func doTest(t *testing.T, u *uploader, test *Test, known *countFileInfo) int {
 	}
 	return ufiles - len(test.uploads)
 }
+
+func TestClockSkew(t *testing.T) {
+	date := func(s string) time.Time { return mustParseDate(s) }
+	tests := []struct {
+		name               string
+		begin, end, mtime  string
+		weekend            time.Weekday
+		haveWeekend        bool
+		wantSkew           bool
+		wantBegin, wantEnd string
+	}{
+		{"consistent", "2024-01-01", "2024-01-05", "2024-01-03", time.Friday, true, false, "", ""},
+		{"no weekend file", "2024-01-01", "2024-01-05", "2024-01-03", 0, false, false, "", ""},
+		{"empty span", "2024-01-05", "2024-01-05", "2024-01-05", time.Friday, true, true, "2024-01-05", "2024-01-12"},
+		{"long span", "2024-01-01", "2024-01-12", "2024-01-03", time.Friday, true, true, "2023-12-29", "2024-01-05"},
+		{"wrong weekend", "2024-01-01", "2024-01-04", "2024-01-03", time.Friday, true, true, "2023-12-29", "2024-01-05"},
+		{"future span", "2099-01-01", "2099-01-02", "2024-01-03", time.Friday, true, true, "2023-12-29", "2024-01-05"},
+		{"past span", "2001-01-01", "2001-01-05", "2024-01-03", time.Friday, true, true, "2023-12-29", "2024-01-05"},
+		{"past span, no weekend file", "2001-01-01", "2001-01-05", "2024-01-03", 0, false, true, "2024-01-03", "2024-01-10"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reason := clockSkew(date(test.begin), date(test.end), date(test.mtime), test.weekend, test.haveWeekend)
+			if gotSkew := reason != ""; gotSkew != test.wantSkew {
+				t.Fatalf("clockSkew(...) = %q, want skew: %t", reason, test.wantSkew)
+			}
+			if !test.wantSkew {
+				return
+			}
+			begin, end := mtimeSpan(date(test.mtime), test.weekend, test.haveWeekend)
+			if got, want := begin.Format(dateFormat), test.wantBegin; got != want {
+				t.Errorf("mtimeSpan begin = %s, want %s", got, want)
+			}
+			if got, want := end.Format(dateFormat), test.wantEnd; got != want {
+				t.Errorf("mtimeSpan end = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestTolerateClockSkew(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewProgram(t, "prog", func() int {
+		counter.NewStack("aStack", 4).Inc()
+		return 0
+	})
+	telemetryDir := t.TempDir()
+	if out, err := regtest.RunProg(t, telemetryDir, prog); err != nil {
+		t.Fatalf("failed to run program: %s", out)
+	}
+	cs := readCountFileInfo(t, filepath.Join(telemetryDir, "local"))
+	uc := CreateTestUploadConfig(t, []string{clockSkewCounter}, []string{"aStack"})
+	env := configtest.LocalProxyEnv(t, uc, "v1.2.3")
+
+	for _, tolerate := range []bool{false, true} {
+		t.Run(fmt.Sprint(tolerate), func(t *testing.T) {
+			srv, uploaded := CreateTestUploadServer(t)
+			dir := t.TempDir()
+			now := time.Now().UTC()
+			// Set the mode first, so that the uploader downloads the config.
+			if err := telemetry.NewDir(dir).SetModeAsOf("on", now.Add(-365*24*time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			uploader, err := newUploader(RunConfig{
+				TelemetryDir:      dir,
+				UploadURL:         srv.URL,
+				Env:               env,
+				TolerateClockSkew: tolerate,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer uploader.Close()
+			uploader.startTime = now
+
+			// Write a count file that claims to span dates far in the future,
+			// but was last modified 10 days ago: the clock was skewed when it
+			// was written.
+			contents := bytes.Join([][]byte{
+				cs.buf[:cs.beginOffset],
+				[]byte("2099-01-01"),
+				cs.buf[cs.beginOffset+len("YYYY-MM-DD") : cs.endOffset],
+				[]byte("2099-01-02"),
+				cs.buf[cs.endOffset+len("YYYY-MM-DD"):],
+			}, nil)
+			if err := os.MkdirAll(uploader.dir.LocalDir(), 0777); err != nil {
+				t.Fatal(err)
+			}
+			fname := filepath.Join(uploader.dir.LocalDir(), cs.namePrefix+"2099-01-01.v1.count")
+			if err := os.WriteFile(fname, contents, 0666); err != nil {
+				t.Fatal(err)
+			}
+			mtime := now.Add(-10 * 24 * time.Hour)
+			if err := os.Chtimes(fname, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := uploader.Run(); err != nil {
+				t.Fatal(err)
+			}
+
+			wantUploads := 0
+			if tolerate {
+				wantUploads = 1
+			}
+			if got := len(uploaded()); got != wantUploads {
+				t.Fatalf("got %d uploads, want %d", got, wantUploads)
+			}
+			if _, err := os.Stat(fname); (err == nil) == tolerate {
+				t.Errorf("count file exists: %t, want %t", err == nil, !tolerate)
+			}
+			if !tolerate {
+				return
+			}
+			locals, err := filepath.Glob(filepath.Join(uploader.dir.LocalDir(), "local.*.json"))
+			if err != nil || len(locals) != 1 {
+				t.Fatalf("got local reports %v (err: %v), want 1", locals, err)
+			}
+			data, err := os.ReadFile(locals[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Contains(data, []byte(`"`+clockSkewCounter+`": 1`)) {
+				t.Errorf("local report does not contain %s annotation:\n%s", clockSkewCounter, data)
+			}
+			// The config includes the annotation, so it is uploaded.
+			if data := uploaded()[0]; !bytes.Contains(data, []byte(`"`+clockSkewCounter+`": 1`)) {
+				t.Errorf("uploaded report does not contain %s annotation:\n%s", clockSkewCounter, data)
+			}
+		})
+	}
+}
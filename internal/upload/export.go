@@ -0,0 +1,298 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upload
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// An export archive is a zip file holding the exported reports, named
+// YYYY-MM-DD.json as in the local directory, along with:
+//
+//   - exportManifest: a note signed by the exporting machine's key, listing
+//     the SHA-256 checksum of each report.
+//   - exportKey: the verifier key for that signature.
+//
+// The signing key is created on first export and kept in the telemetry
+// directory, so archives from the same machine carry the same verifier key.
+// The signature makes accidental or partial modification of an archive
+// evident; it is not a proof of origin, as the key travels with the archive.
+const (
+	exportManifest = "MANIFEST"
+	exportKey      = "KEY"
+	exportHeader   = "golang.org/x/telemetry export"
+
+	exportKeyFile = "export.key" // in the telemetry directory
+	exportKeyName = "telemetry-export"
+)
+
+// Export bundles the reports that are ready for upload from the telemetry
+// directory dir (or the default directory, if dir is empty) into a signed
+// archive at outFile, for upload from another machine with [Import]. It
+// returns the number of exported reports.
+//
+// Before bundling, Export creates the reports of count files that have
+// expired, as an upload would, using the given upload config and config
+// version. Export is meant for machines that cannot reach the upload server,
+// so the config is supplied by the caller rather than downloaded.
+//
+// As with uploading, Export requires the telemetry mode to be "on". Exported
+// reports are moved to the upload directory, as if they had been uploaded,
+// so they are neither uploaded nor exported again. If there are no reports
+// to export, Export does not create outFile.
+func Export(dir, outFile string, ucfg *telemetry.UploadConfig, configVersion string) (int, error) {
+	tdir := telemetry.Default
+	if dir != "" {
+		tdir = telemetry.NewDir(dir)
+	}
+	if mode, _ := tdir.Mode(); mode != "on" {
+		return 0, fmt.Errorf("telemetry mode is %q; exporting reports requires mode \"on\"", mode)
+	}
+	u := &uploader{
+		config:        ucfg,
+		configVersion: configVersion,
+		dir:           tdir,
+		startTime:     time.Now().UTC(),
+		logger:        newLogger(io.Discard, 0),
+	}
+	todo := u.findWork()
+	reports, err := u.reports(&todo)
+	if err != nil {
+		return 0, err
+	}
+	var ready []string
+	for _, f := range reports {
+		if !todo.uploaded[filepath.Base(f)] {
+			ready = append(ready, f)
+		}
+	}
+	if len(ready) == 0 {
+		return 0, nil
+	}
+	sort.Strings(ready)
+
+	signer, vkey, err := exportSigner(tdir)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeExport(outFile, ready, signer, vkey); err != nil {
+		os.Remove(outFile)
+		return 0, err
+	}
+	for _, f := range ready {
+		if err := os.Rename(f, filepath.Join(tdir.UploadDir(), filepath.Base(f))); err != nil {
+			return 0, fmt.Errorf("recording exported report: %v", err)
+		}
+	}
+	return len(ready), nil
+}
+
+// writeExport writes an export archive containing the given report files.
+func writeExport(outFile string, reports []string, signer note.Signer, vkey string) error {
+	f, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+
+	manifest := exportHeader + "\n"
+	for _, r := range reports {
+		data, err := os.ReadFile(r)
+		if err != nil {
+			return err
+		}
+		name := filepath.Base(r)
+		manifest += fmt.Sprintf("%x %s\n", sha256.Sum256(data), name)
+		if err := writeZipFile(zw, name, data); err != nil {
+			return err
+		}
+	}
+	msg, err := note.Sign(&note.Note{Text: manifest}, signer)
+	if err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, exportManifest, msg); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, exportKey, []byte(vkey+"\n")); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// exportSigner returns the signer for export archives from dir, and the
+// corresponding verifier key, creating the key if it does not yet exist.
+func exportSigner(dir telemetry.Dir) (note.Signer, string, error) {
+	keyFile := filepath.Join(dir.Dir(), exportKeyFile)
+	data, err := os.ReadFile(keyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		skey, vkey, err := note.GenerateKey(rand.Reader, exportKeyName)
+		if err != nil {
+			return nil, "", err
+		}
+		data = []byte(skey + "\n" + vkey + "\n")
+		if err := os.WriteFile(keyFile, data, 0600); err != nil {
+			return nil, "", fmt.Errorf("saving export key: %v", err)
+		}
+	} else if err != nil {
+		return nil, "", fmt.Errorf("reading export key: %v", err)
+	}
+	skey, vkey, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	signer, err := note.NewSigner(skey)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid export key %s: %v", keyFile, err)
+	}
+	return signer, vkey, nil
+}
+
+// Import uploads the reports in an archive created by [Export], after
+// checking the archive's signature and report checksums. Only the
+// UploadURL, LogWriter, and LogLevel fields of config are used.
+//
+// Import returns the verifier key of the archive, which identifies the
+// exporting machine, and the number of uploaded reports. Archives that fail
+// verification are rejected as a whole; otherwise, Import attempts to upload
+// every report, and reports the first failure.
+func Import(archive string, config RunConfig) (vkey string, uploaded int, _ error) {
+	uploadURL := config.UploadURL
+	if uploadURL == "" {
+		uploadURL = "https://telemetry.go.dev/upload"
+	}
+	logWriter := config.LogWriter
+	if logWriter == nil {
+		logWriter = io.Discard
+	}
+	logger := newLogger(logWriter, config.LogLevel)
+
+	reports, vkey, err := readExport(archive)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid export archive %s: %v", archive, err)
+	}
+	logger.Info("importing reports", "archive", archive, "key", vkey, "count", len(reports))
+
+	var firstErr error
+	for _, name := range sortedKeys(reports) {
+		date := strings.TrimSuffix(name, ".json")
+		if err := postReport(uploadURL+"/"+date, reports[name]); err != nil {
+			logger.Error("upload failed", "file", name, "err", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		logger.Info("uploaded", "file", name)
+		uploaded++
+	}
+	return vkey, uploaded, firstErr
+}
+
+// readExport reads and verifies an export archive, returning its reports by
+// file name, and its verifier key.
+func readExport(archive string) (map[string][]byte, string, error) {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, "", err
+	}
+	defer zr.Close()
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, "", err
+		}
+		files[f.Name] = data
+	}
+
+	vkey := strings.TrimSpace(string(files[exportKey]))
+	verifier, err := note.NewVerifier(vkey)
+	if err != nil {
+		return nil, "", fmt.Errorf("bad key: %v", err)
+	}
+	n, err := note.Open(files[exportManifest], note.VerifierList(verifier))
+	if err != nil {
+		return nil, "", fmt.Errorf("bad manifest: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(n.Text, "\n"), "\n")
+	if lines[0] != exportHeader {
+		return nil, "", fmt.Errorf("bad manifest header %q", lines[0])
+	}
+	reports := make(map[string][]byte)
+	for _, line := range lines[1:] {
+		sum, name, ok := strings.Cut(line, " ")
+		if _, err := time.Parse(dateFormat+".json", name); !ok || err != nil {
+			return nil, "", fmt.Errorf("bad manifest line %q", line)
+		}
+		data, ok := files[name]
+		if !ok {
+			return nil, "", fmt.Errorf("missing report %s", name)
+		}
+		if fmt.Sprintf("%x", sha256.Sum256(data)) != sum {
+			return nil, "", fmt.Errorf("checksum mismatch for %s", name)
+		}
+		var report telemetry.Report
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, "", fmt.Errorf("malformed report %s: %v", name, err)
+		}
+		reports[name] = data
+	}
+	return reports, vkey, nil
+}
+
+// postReport uploads a single report to the given endpoint.
+func postReport(endpoint string, data []byte) error {
+	resp, err := http.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", endpoint, resp.Status)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
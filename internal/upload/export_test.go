@@ -0,0 +1,145 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upload
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestExportImport(t *testing.T) {
+	dir := telemetry.NewDir(t.TempDir())
+	if err := dir.SetModeAsOf("on", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []string{dir.LocalDir(), dir.UploadDir()} {
+		if err := os.MkdirAll(d, 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report := func(week string, x float64) []byte {
+		data, err := json.Marshal(telemetry.Report{Week: week, X: x, Config: "v0.0.1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	files := map[string][]byte{
+		filepath.Join(dir.LocalDir(), "2024-01-01.json"):       report("2024-01-01", 0.1),
+		filepath.Join(dir.LocalDir(), "2024-01-08.json"):       report("2024-01-08", 0.2),
+		filepath.Join(dir.LocalDir(), "local.2024-01-08.json"): report("2024-01-08", 0.2),
+		filepath.Join(dir.UploadDir(), "2023-12-25.json"):      report("2023-12-25", 0.3),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(name, data, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archive := filepath.Join(t.TempDir(), "export.zip")
+	n, err := Export(dir.Dir(), archive, &telemetry.UploadConfig{}, "v0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Export exported %d reports, want 2", n)
+	}
+	for _, name := range []string{"2024-01-01.json", "2024-01-08.json"} {
+		if _, err := os.Stat(filepath.Join(dir.UploadDir(), name)); err != nil {
+			t.Errorf("exported report %s not moved to the upload dir: %v", name, err)
+		}
+	}
+
+	// Exported reports are not exported again.
+	again := filepath.Join(t.TempDir(), "again.zip")
+	if n, err := Export(dir.Dir(), again, &telemetry.UploadConfig{}, "v0.0.1"); err != nil || n != 0 {
+		t.Errorf("second Export = %d, %v, want 0, nil", n, err)
+	}
+	if _, err := os.Stat(again); !os.IsNotExist(err) {
+		t.Errorf("second Export created an archive (stat err: %v)", err)
+	}
+
+	srv, uploaded := CreateTestUploadServer(t)
+	vkey, n, err := Import(archive, RunConfig{UploadURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Import uploaded %d reports, want 2", n)
+	}
+	if !strings.HasPrefix(vkey, exportKeyName+"+") {
+		t.Errorf("Import key = %q, want prefix %q", vkey, exportKeyName+"+")
+	}
+	var weeks []string
+	for _, data := range uploaded() {
+		var r telemetry.Report
+		if err := json.Unmarshal(data, &r); err != nil {
+			t.Fatal(err)
+		}
+		weeks = append(weeks, r.Week)
+	}
+	sort.Strings(weeks)
+	if got, want := strings.Join(weeks, ","), "2024-01-01,2024-01-08"; got != want {
+		t.Errorf("uploaded weeks = %s, want %s", got, want)
+	}
+
+	// Altering a report invalidates the archive.
+	tampered := filepath.Join(t.TempDir(), "tampered.zip")
+	rewriteZip(t, archive, tampered, func(name string, data []byte) []byte {
+		if name == "2024-01-01.json" {
+			return report("2024-01-01", 0.9)
+		}
+		return data
+	})
+	if _, _, err := Import(tampered, RunConfig{UploadURL: srv.URL}); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Import(tampered) = %v, want checksum error", err)
+	}
+	if got := len(uploaded()); got != 2 {
+		t.Errorf("after tampered import, server has %d reports, want 2", got)
+	}
+}
+
+// rewriteZip copies the zip archive from to the file to, replacing the
+// contents of each file with the result of edit.
+func rewriteZip(t *testing.T, from, to string, edit func(name string, data []byte) []byte) {
+	t.Helper()
+	zr, err := zip.OpenReader(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	out, err := os.Create(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	zw := zip.NewWriter(out)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writeZipFile(zw, f.Name, edit(f.Name, data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -25,14 +25,17 @@ type work struct {
 func (u *uploader) findWork() work {
 	localdir, uploaddir := u.dir.LocalDir(), u.dir.UploadDir()
 	var ans work
+	if !u.dryRun {
+		u.retryQuarantined()
+	}
 	fis, err := os.ReadDir(localdir)
 	if err != nil {
-		u.logger.Printf("Could not find work: failed to read local dir %s: %v", localdir, err)
+		u.logger.Error("could not find work: failed to read local dir", "dir", localdir, "err", err)
 		return ans
 	}
 
 	mode, asof := u.dir.Mode()
-	u.logger.Printf("Finding work: mode %s asof %s", mode, asof)
+	u.logger.Info("finding work", "mode", mode, "asof", asof)
 
 	// count files end in .v1.count
 	// reports end in .json. If they are not to be uploaded they
@@ -40,14 +43,17 @@ func (u *uploader) findWork() work {
 	for _, fi := range fis {
 		if strings.HasSuffix(fi.Name(), ".v1.count") {
 			fname := filepath.Join(localdir, fi.Name())
-			_, expiry, err := u.counterDateSpan(fname)
+			_, expiry, err := u.countFileSpan(fname)
 			switch {
 			case err != nil:
-				u.logger.Printf("Error reading expiry for count file %s: %v", fi.Name(), err)
+				u.logger.Warn("error reading expiry for count file", "file", fi.Name(), "err", err)
+				if !u.dryRun {
+					u.quarantine(fname, err)
+				}
 			case expiry.After(u.startTime):
-				u.logger.Printf("Skipping count file %s: still active", fi.Name())
+				u.logger.Debug("skipping count file: still active", "file", fi.Name())
 			default:
-				u.logger.Printf("Collecting count file %s", fi.Name())
+				u.logger.Debug("collecting count file", "file", fi.Name())
 				ans.countfiles = append(ans.countfiles, fname)
 			}
 		} else if strings.HasPrefix(fi.Name(), "local.") {
@@ -68,7 +74,7 @@ func (u *uploader) findWork() work {
 					//
 					// TODO(rfindley): store the begin date in reports, so that we can
 					// verify this assumption.
-					u.logger.Printf("Uploadable: %s", fi.Name())
+					u.logger.Debug("uploadable", "file", fi.Name())
 					ans.readyfiles = append(ans.readyfiles, filepath.Join(localdir, fi.Name()))
 				}
 			} else {
@@ -78,7 +84,7 @@ func (u *uploader) findWork() work {
 				// TODO(rfindley): invert this logic following more testing. We
 				// should only upload if we know both the asof date and the report
 				// date, and they are acceptable.
-				u.logger.Printf("Uploadable (missing date): %s", fi.Name())
+				u.logger.Debug("uploadable (missing date)", "file", fi.Name())
 				ans.readyfiles = append(ans.readyfiles, filepath.Join(localdir, fi.Name()))
 			}
 		}
@@ -86,7 +92,9 @@ func (u *uploader) findWork() work {
 
 	fis, err = os.ReadDir(uploaddir)
 	if err != nil {
-		os.MkdirAll(uploaddir, 0777)
+		if !u.dryRun {
+			os.MkdirAll(uploaddir, 0777)
+		}
 		return ans
 	}
 	// There should be only one of these per day; maybe sometime
@@ -94,7 +102,7 @@ func (u *uploader) findWork() work {
 	ans.uploaded = make(map[string]bool)
 	for _, fi := range fis {
 		if strings.HasSuffix(fi.Name(), ".json") {
-			u.logger.Printf("Already uploaded: %s", fi.Name())
+			u.logger.Debug("already uploaded", "file", fi.Name())
 			ans.uploaded[fi.Name()] = true
 		}
 	}
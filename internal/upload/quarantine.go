@@ -0,0 +1,147 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upload
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// Count files that cannot be parsed are moved to the quarantine directory,
+// rather than being left in the local directory (where they would be
+// retried forever) or deleted (which would abandon their data, in case the
+// failure is due to a bug in this package). Beside each quarantined count
+// file is a record, with the added suffix quarantineSuffix, of the most
+// recent error and the number of failed attempts to parse it.
+//
+// Each upload retries the quarantined files that have failed fewer than
+// maxQuarantineAttempts times, and returns those that can now be parsed to
+// the local directory. The others are kept for inspection, until removed by
+// "gotelemetry clean".
+
+const (
+	quarantineSuffix      = ".err"
+	maxQuarantineAttempts = 3
+
+	// quarantineDelay is how long a count file must have gone unmodified
+	// before it is quarantined, so that files still in use are left alone.
+	quarantineDelay = 7 * 24 * time.Hour
+)
+
+// A QuarantinedFile describes a count file in the quarantine directory.
+type QuarantinedFile struct {
+	Name     string // base name of the count file
+	Err      string // the most recent error parsing it
+	Attempts int    // the number of failed attempts to parse it
+}
+
+// Quarantined returns the count files in the quarantine directory of dir,
+// sorted by name.
+func Quarantined(dir telemetry.Dir) ([]QuarantinedFile, error) {
+	entries, err := os.ReadDir(dir.QuarantineDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []QuarantinedFile
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), quarantineSuffix) {
+			continue
+		}
+		q, err := readQuarantineRecord(filepath.Join(dir.QuarantineDir(), e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, q)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+func readQuarantineRecord(path string) (QuarantinedFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return QuarantinedFile{}, err
+	}
+	var q QuarantinedFile
+	if err := json.Unmarshal(data, &q); err != nil {
+		return QuarantinedFile{}, err
+	}
+	q.Name = strings.TrimSuffix(filepath.Base(path), quarantineSuffix)
+	return q, nil
+}
+
+func writeQuarantineRecord(path string, q QuarantinedFile) error {
+	data, err := json.MarshalIndent(q, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0666)
+}
+
+// quarantine moves the count file fname, which could not be parsed because
+// of parseErr, to the quarantine directory, if it has not been modified
+// recently.
+func (u *uploader) quarantine(fname string, parseErr error) {
+	fi, err := os.Stat(fname)
+	if err != nil || u.startTime.Sub(fi.ModTime()) < quarantineDelay {
+		return
+	}
+	qdir := u.dir.QuarantineDir()
+	if err := os.MkdirAll(qdir, 0777); err != nil {
+		u.logger.Warn("failed to create quarantine dir", "dir", qdir, "err", err)
+		return
+	}
+	qname := filepath.Join(qdir, filepath.Base(fname))
+	q := QuarantinedFile{Err: parseErr.Error(), Attempts: 1}
+	if err := writeQuarantineRecord(qname+quarantineSuffix, q); err != nil {
+		u.logger.Warn("failed to record quarantined count file", "file", filepath.Base(fname), "err", err)
+		return
+	}
+	if err := os.Rename(fname, qname); err != nil {
+		u.logger.Warn("failed to quarantine count file", "file", filepath.Base(fname), "err", err)
+		os.Remove(qname + quarantineSuffix)
+		return
+	}
+	u.logger.Warn("quarantined unparseable count file", "file", filepath.Base(fname), "err", parseErr)
+}
+
+// retryQuarantined tries again to parse the quarantined count files, moving
+// those that succeed back to the local directory.
+func (u *uploader) retryQuarantined() {
+	files, err := Quarantined(u.dir)
+	if err != nil {
+		u.logger.Warn("failed to read quarantine dir", "err", err)
+		return
+	}
+	for _, q := range files {
+		if q.Attempts >= maxQuarantineAttempts {
+			continue
+		}
+		qname := filepath.Join(u.dir.QuarantineDir(), q.Name)
+		if _, _, err := u.countFileSpan(qname); err != nil {
+			q.Attempts++
+			q.Err = err.Error()
+			if err := writeQuarantineRecord(qname+quarantineSuffix, q); err != nil {
+				u.logger.Warn("failed to record quarantined count file", "file", q.Name, "err", err)
+			}
+			continue
+		}
+		if err := os.Rename(qname, filepath.Join(u.dir.LocalDir(), q.Name)); err != nil {
+			u.logger.Warn("failed to restore quarantined count file", "file", q.Name, "err", err)
+			continue
+		}
+		os.Remove(qname + quarantineSuffix)
+		u.logger.Info("restored quarantined count file", "file", q.Name)
+	}
+}
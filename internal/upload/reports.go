@@ -18,6 +18,7 @@ import (
 	"golang.org/x/telemetry/internal/config"
 	"golang.org/x/telemetry/internal/counter"
 	"golang.org/x/telemetry/internal/telemetry"
+	"golang.org/x/telemetry/internal/uploadable"
 )
 
 // reports generates reports from inactive count files
@@ -31,15 +32,15 @@ func (u *uploader) reports(todo *work) ([]string, error) {
 	if lastWeek >= today { //should never happen
 		lastWeek = ""
 	}
-	u.logger.Printf("Last week: %s, today: %s", lastWeek, today)
+	u.logger.Info("selecting weeks", "lastWeek", lastWeek, "today", today)
 	countFiles := make(map[string][]string) // expiry date string->filenames
 	earliest := make(map[string]time.Time)  // earliest begin time for any counter
 	for _, f := range todo.countfiles {
-		begin, end, err := u.counterDateSpan(f)
+		begin, end, err := u.countFileSpan(f)
 		if err != nil {
 			// This shouldn't happen: we should have already skipped count files that
 			// don't contain valid start or end times.
-			u.logger.Printf("BUG: failed to parse expiry for collected count file: %v", err)
+			u.logger.Error("BUG: failed to parse expiry for collected count file", "file", filepath.Base(f), "err", err)
 			continue
 		}
 
@@ -53,7 +54,7 @@ func (u *uploader) reports(todo *work) ([]string, error) {
 	}
 	for expiry, files := range countFiles {
 		if notNeeded(expiry, *todo) {
-			u.logger.Printf("Files for %s not needed, deleting %v", expiry, files)
+			u.logger.Info("files not needed, deleting", "week", expiry, "files", files)
 			// The report already exists.
 			// There's another check in createReport.
 			u.deleteFiles(files)
@@ -61,11 +62,11 @@ func (u *uploader) reports(todo *work) ([]string, error) {
 		}
 		fname, err := u.createReport(earliest[expiry], expiry, files, lastWeek)
 		if err != nil {
-			u.logger.Printf("Failed to create report for %s: %v", expiry, err)
+			u.logger.Error("failed to create report", "week", expiry, "err", err)
 			continue
 		}
 		if fname != "" {
-			u.logger.Printf("Ready to upload: %s", filepath.Base(fname))
+			u.logger.Info("ready to upload", "week", expiry, "file", filepath.Base(fname))
 			todo.readyfiles = append(todo.readyfiles, fname)
 		}
 	}
@@ -110,7 +111,7 @@ func (u *uploader) deleteFiles(files []string) {
 			// this could be a race condition.
 			// conversely, on Windows, err may be nil and
 			// the file not deleted if anyone has it open.
-			u.logger.Printf("%v failed to remove %s", err, f)
+			u.logger.Warn("failed to remove file", "file", f, "err", err)
 		}
 	}
 }
@@ -121,22 +122,6 @@ func (u *uploader) deleteFiles(files []string) {
 // It may delete the count files once local and upload report
 // files are successfully created.
 func (u *uploader) createReport(start time.Time, expiryDate string, countFiles []string, lastWeek string) (string, error) {
-	uploadOK := true
-	mode, asof := u.dir.Mode()
-	if mode != "on" {
-		u.logger.Printf("No upload config or mode %q is not 'on'", mode)
-		uploadOK = false // no config, nothing to upload
-	}
-	if u.tooOld(expiryDate, u.startTime) {
-		u.logger.Printf("Expiry date %s is too old", expiryDate)
-		uploadOK = false
-	}
-	// If the mode is recorded with an asof date, don't upload if the report
-	// includes any data on or before the asof date.
-	if !asof.IsZero() && !asof.Before(start) {
-		u.logger.Printf("As-of date %s is not before start %s", asof, start)
-		uploadOK = false
-	}
 	// TODO(rfindley): check that all the x.Meta are consistent for GOOS, GOARCH, etc.
 	report := &telemetry.Report{
 		Config:   u.configVersion,
@@ -144,35 +129,12 @@ func (u *uploader) createReport(start time.Time, expiryDate string, countFiles [
 		Week:     expiryDate,
 		LastWeek: lastWeek,
 	}
-	if report.X > u.config.SampleRate && u.config.SampleRate > 0 {
-		u.logger.Printf("X: %f > SampleRate:%f, not uploadable", report.X, u.config.SampleRate)
-		uploadOK = false
+	blockers := u.uploadBlockers(start, expiryDate, report.X)
+	for _, b := range blockers {
+		u.logger.Info("not uploadable: "+b, "week", expiryDate)
 	}
-	var succeeded bool
-	for _, f := range countFiles {
-		fok := false
-		x, err := u.parseCountFile(f)
-		if err != nil {
-			u.logger.Printf("Unparseable count file %s: %v", filepath.Base(f), err)
-			continue
-		}
-		prog := findProgReport(x.Meta, report)
-		for k, v := range x.Count {
-			if counter.IsStackCounter(k) {
-				// stack
-				prog.Stacks[k] += int64(v)
-			} else {
-				// counter
-				prog.Counters[k] += int64(v)
-			}
-			succeeded = true
-			fok = true
-		}
-		if !fok {
-			u.logger.Printf("no counters found in %s", f)
-		}
-	}
-	if !succeeded {
+	uploadOK := len(blockers) == 0
+	if !u.addCounts(report, countFiles) {
 		return "", fmt.Errorf("none of the %d count files for %s contained counters", len(countFiles), expiryDate)
 	}
 	// 1. generate the local report
@@ -190,45 +152,7 @@ func (u *uploader) createReport(start time.Time, expiryDate string, countFiles [
 	var uploadContents []byte
 	if uploadOK {
 		// 2. create the uploadable version
-		cfg := config.NewConfig(u.config)
-		upload := &telemetry.Report{
-			Week:     report.Week,
-			LastWeek: report.LastWeek,
-			X:        report.X,
-			Config:   report.Config,
-		}
-		for _, p := range report.Programs {
-			// does the uploadConfig want this program?
-			// if so, copy over the Stacks and Counters
-			// that the uploadConfig mentions.
-			if !cfg.HasGoVersion(p.GoVersion) || !cfg.HasProgram(p.Program) || !cfg.HasVersion(p.Program, p.Version) {
-				continue
-			}
-			x := &telemetry.ProgramReport{
-				Program:   p.Program,
-				Version:   p.Version,
-				GOOS:      p.GOOS,
-				GOARCH:    p.GOARCH,
-				GoVersion: p.GoVersion,
-				Counters:  make(map[string]int64),
-				Stacks:    make(map[string]int64),
-			}
-			upload.Programs = append(upload.Programs, x)
-			for k, v := range p.Counters {
-				if cfg.HasCounter(p.Program, k) && report.X <= cfg.Rate(p.Program, k) {
-					x.Counters[k] = v
-				}
-			}
-			// and the same for Stacks
-			// this can be made more efficient, when it matters
-			for k, v := range p.Stacks {
-				before, _, _ := strings.Cut(k, "\n")
-				if cfg.HasStack(p.Program, before) && report.X <= cfg.Rate(p.Program, before) {
-					x.Stacks[k] = v
-				}
-			}
-		}
-
+		upload := uploadableReport(config.NewConfig(u.config), report)
 		uploadContents, err = json.MarshalIndent(upload, "", " ")
 		if err != nil {
 			return "", fmt.Errorf("failed to marshal upload report for %s: %v", expiryDate, err)
@@ -265,7 +189,7 @@ func (u *uploader) createReport(start time.Time, expiryDate string, countFiles [
 	if errUpload != nil {
 		return "", fmt.Errorf("failed to write upload file %s (%v)", uploadFileName, errUpload)
 	}
-	u.logger.Printf("Created %s, deleting %d count files", filepath.Base(uploadFileName), len(countFiles))
+	u.logger.Info("created report, deleting count files", "file", filepath.Base(uploadFileName), "uploadable", uploadOK, "countFiles", len(countFiles))
 	u.deleteFiles(countFiles)
 	if uploadOK {
 		return uploadFileName, nil
@@ -273,6 +197,168 @@ func (u *uploader) createReport(start time.Time, expiryDate string, countFiles [
 	return "", nil
 }
 
+// uploadBlockers returns the reasons, if any, that the report for the week
+// ending on expiryDate, containing data from start onward and with the given
+// X, may not be uploaded.
+func (u *uploader) uploadBlockers(start time.Time, expiryDate string, x float64) []string {
+	var reasons []string
+	mode, asof := u.dir.Mode()
+	if mode != "on" {
+		reasons = append(reasons, fmt.Sprintf("mode is %q, not \"on\"", mode))
+	}
+	if u.tooOld(expiryDate, u.startTime) {
+		reasons = append(reasons, "too old")
+	}
+	// If the mode is recorded with an asof date, don't upload if the report
+	// includes any data on or before the asof date.
+	if !asof.IsZero() && !asof.Before(start) {
+		reasons = append(reasons, fmt.Sprintf("as-of date %s is not before start %s", asof.Format(time.RFC3339), start.Format(time.RFC3339)))
+	}
+	if x > u.config.SampleRate && u.config.SampleRate > 0 {
+		reasons = append(reasons, fmt.Sprintf("X %g exceeds sample rate %g", x, u.config.SampleRate))
+	}
+	return reasons
+}
+
+// addCounts adds the counters in countFiles to report, and reports whether
+// any counters were found.
+func (u *uploader) addCounts(report *telemetry.Report, countFiles []string) bool {
+	var succeeded bool
+	for _, f := range countFiles {
+		fok := false
+		x, err := u.parseCountFile(f)
+		if err != nil {
+			u.logger.Warn("unparseable count file", "file", filepath.Base(f), "err", err)
+			continue
+		}
+		prog := findProgReport(x.Meta, report)
+		if u.isSkewed(f) {
+			prog.Counters[clockSkewCounter]++
+		}
+		for k, v := range x.Count {
+			if counter.IsStackCounter(k) {
+				// stack
+				prog.Stacks[k] += int64(v)
+			} else if counter.IsEventCounter(k) {
+				// event
+				if prog.Events == nil {
+					prog.Events = make(map[string]int64)
+				}
+				prog.Events[k] += int64(v)
+			} else {
+				// counter
+				prog.Counters[k] += int64(v)
+			}
+			succeeded = true
+			fok = true
+		}
+		if !fok {
+			u.logger.Debug("no counters found", "file", filepath.Base(f))
+		}
+	}
+	return succeeded
+}
+
+// uploadableReport returns the subset of report that is permitted by cfg.
+//
+// Programs are included only if cfg mentions their program, version, Go
+// version, GOOS, and GOARCH. Counters, stacks, and events are included only if cfg mentions them and the
+// report's X is no greater than their configured Rate, so that a counter with
+// rate r appears in approximately a fraction r of all uploads.
+func uploadableReport(cfg *config.Config, report *telemetry.Report) *telemetry.Report {
+	return filterReport(cfg, report, nil)
+}
+
+// filterReport is like uploadableReport, but additionally calls drop (if
+// non-nil) for each counter, stack, or event of report that is omitted, with the
+// reason it is omitted.
+func filterReport(cfg *config.Config, report *telemetry.Report, drop func(p *telemetry.ProgramReport, name, reason string)) *telemetry.Report {
+	if drop == nil {
+		drop = func(*telemetry.ProgramReport, string, string) {}
+	}
+	upload := &telemetry.Report{
+		Week:     report.Week,
+		LastWeek: report.LastWeek,
+		X:        report.X,
+		Config:   report.Config,
+	}
+	for _, p := range report.Programs {
+		// does the uploadConfig want this program?
+		// if so, copy over the Stacks and Counters
+		// that the uploadConfig mentions.
+		meta := uploadable.Meta{
+			Program:   p.Program,
+			Version:   p.Version,
+			GoVersion: p.GoVersion,
+			GOOS:      p.GOOS,
+			GOARCH:    p.GOARCH,
+		}
+		if d, reason := uploadable.DecideProgram(cfg, meta); d == uploadable.Drop {
+			for k := range p.Counters {
+				drop(p, k, reason.String())
+			}
+			for k := range p.Stacks {
+				drop(p, k, reason.String())
+			}
+			for k := range p.Events {
+				drop(p, k, reason.String())
+			}
+			continue
+		}
+		x := &telemetry.ProgramReport{
+			Program:   p.Program,
+			Version:   p.Version,
+			GOOS:      p.GOOS,
+			GOARCH:    p.GOARCH,
+			GoVersion: p.GoVersion,
+			Counters:  make(map[string]int64),
+			Stacks:    make(map[string]int64),
+		}
+		upload.Programs = append(upload.Programs, x)
+		// keep reports whether the counter or stack named name should be
+		// uploaded.
+		keep := func(name string) bool {
+			if d, reason := uploadable.Decide(cfg, meta, name); d == uploadable.Drop {
+				drop(p, name, reason.String())
+				return false
+			}
+			if rate := cfg.Rate(p.Program, uploadable.ConfigName(name)); !sampled(report.X, rate) {
+				drop(p, name, fmt.Sprintf("not sampled: X %g exceeds rate %g", report.X, rate))
+				return false
+			}
+			return true
+		}
+		for k, v := range p.Counters {
+			if keep(k) {
+				x.Counters[k] = v
+			}
+		}
+		for k, v := range p.Stacks {
+			if keep(k) {
+				// Stacks deeper than the config allows are truncated,
+				// merging the counts of stacks that then coincide.
+				depth := cfg.Depth(p.Program, uploadable.ConfigName(k))
+				x.Stacks[uploadable.TruncateStack(k, depth)] += v
+			}
+		}
+		for k, v := range p.Events {
+			if keep(k) {
+				if x.Events == nil {
+					x.Events = make(map[string]int64)
+				}
+				x.Events[k] = v
+			}
+		}
+	}
+	return upload
+}
+
+// sampled reports whether a counter with the given configured rate should be
+// included in a report with the given X.
+func sampled(x, rate float64) bool {
+	return x <= rate
+}
+
 // exclusiveWrite attempts to create filename exclusively, and if successful,
 // writes content to the resulting file handle.
 //
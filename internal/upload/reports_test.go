@@ -0,0 +1,228 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upload
+
+import (
+	"testing"
+
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestUploadableReport_Rates(t *testing.T) {
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:       []string{"linux"},
+		GOARCH:     []string{"amd64"},
+		GoVersion:  []string{"go1.23.0"},
+		SampleRate: 1,
+		Programs: []*telemetry.ProgramConfig{{
+			Name:     "prog",
+			Versions: []string{"v1.0.0"},
+			Counters: []telemetry.CounterConfig{
+				{Name: "always", Rate: 1},
+				{Name: "tenth", Rate: 0.1},
+				{Name: "never", Rate: 0},
+			},
+			Stacks: []telemetry.CounterConfig{
+				{Name: "stack", Rate: 0.1, Depth: 4},
+			},
+		}},
+	})
+
+	// As with TestRandom, this test is statistical. With N=10000 and p=0.1,
+	// the number of inclusions has mean 1000 and sigma 30, so a tolerance of
+	// 250 is more than 8 sigma.
+	const N = 10000
+	counts := make(map[string]int)
+	for i := 0; i < N; i++ {
+		report := &telemetry.Report{
+			Week: "2024-01-01",
+			X:    computeRandom(),
+			Programs: []*telemetry.ProgramReport{{
+				Program:   "prog",
+				Version:   "v1.0.0",
+				GoVersion: "go1.23.0",
+				GOOS:      "linux",
+				GOARCH:    "amd64",
+				Counters: map[string]int64{
+					"always":  1,
+					"tenth":   1,
+					"never":   1,
+					"unknown": 1,
+				},
+				Stacks: map[string]int64{
+					"stack\nframe1\nframe2": 1,
+				},
+			}},
+		}
+		upload := uploadableReport(cfg, report)
+		if len(upload.Programs) != 1 {
+			t.Fatalf("got %d uploaded programs, want 1", len(upload.Programs))
+		}
+		p := upload.Programs[0]
+		for name := range p.Counters {
+			counts[name]++
+		}
+		for name := range p.Stacks {
+			counts[name]++
+		}
+	}
+
+	if got := counts["always"]; got != N {
+		t.Errorf("counter with rate 1 included %d times, want %d", got, N)
+	}
+	if got := counts["never"]; got != 0 {
+		t.Errorf("counter with rate 0 included %d times, want 0", got)
+	}
+	if got := counts["unknown"]; got != 0 {
+		t.Errorf("unconfigured counter included %d times, want 0", got)
+	}
+	for _, name := range []string{"tenth", "stack\nframe1\nframe2"} {
+		if got := counts[name]; got < 750 || got > 1250 {
+			t.Errorf("%q with rate 0.1 included %d times out of %d, want approximately %d", name, got, N, N/10)
+		}
+	}
+}
+
+func TestUploadableReport_StackDepth(t *testing.T) {
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:      []string{"linux"},
+		GOARCH:    []string{"amd64"},
+		GoVersion: []string{"go1.23.0"},
+		Programs: []*telemetry.ProgramConfig{{
+			Name:     "prog",
+			Versions: []string{"v1.0.0"},
+			Stacks: []telemetry.CounterConfig{
+				{Name: "shallow", Rate: 1, Depth: 2},
+				{Name: "unlimited", Rate: 1},
+			},
+		}},
+	})
+	report := &telemetry.Report{
+		Week: "2024-01-01",
+		X:    0.5,
+		Programs: []*telemetry.ProgramReport{{
+			Program:   "prog",
+			Version:   "v1.0.0",
+			GoVersion: "go1.23.0",
+			GOOS:      "linux",
+			GOARCH:    "amd64",
+			Stacks: map[string]int64{
+				"shallow\nf\ng\nh":   1,
+				"shallow\nf\ng\ni":   2,
+				"shallow\nf":         4,
+				"unlimited\nf\ng\nh": 8,
+			},
+		}},
+	}
+	upload := uploadableReport(cfg, report)
+	if len(upload.Programs) != 1 {
+		t.Fatalf("got %d uploaded programs, want 1", len(upload.Programs))
+	}
+	got := upload.Programs[0].Stacks
+	want := map[string]int64{
+		"shallow\nf\ng":      3,
+		"shallow\nf":         4,
+		"unlimited\nf\ng\nh": 8,
+	}
+	if len(got) != len(want) {
+		t.Errorf("uploaded stacks %q, want %q", got, want)
+	}
+	for name, n := range want {
+		if got[name] != n {
+			t.Errorf("uploaded stack %q with count %d, want %d", name, got[name], n)
+		}
+	}
+}
+
+func TestUploadableReport_Programs(t *testing.T) {
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:      []string{"linux"},
+		GOARCH:    []string{"amd64"},
+		GoVersion: []string{"go1.23.0"},
+		Programs: []*telemetry.ProgramConfig{{
+			Name:     "prog",
+			Versions: []string{"v1.0.0"},
+			Counters: []telemetry.CounterConfig{{Name: "c", Rate: 1}},
+		}},
+	})
+	report := &telemetry.Report{
+		Week: "2024-01-01",
+		X:    0.5,
+		Programs: []*telemetry.ProgramReport{
+			{Program: "prog", Version: "v1.0.0", GoVersion: "go1.23.0", GOOS: "linux", GOARCH: "amd64", Counters: map[string]int64{"c": 1}},
+			{Program: "prog", Version: "v2.0.0", GoVersion: "go1.23.0", GOOS: "linux", GOARCH: "amd64", Counters: map[string]int64{"c": 1}},
+			{Program: "prog", Version: "v1.0.0", GoVersion: "go1.21.0", GOOS: "linux", GOARCH: "amd64", Counters: map[string]int64{"c": 1}},
+			{Program: "other", Version: "v1.0.0", GoVersion: "go1.23.0", GOOS: "linux", GOARCH: "amd64", Counters: map[string]int64{"c": 1}},
+			{Program: "prog", Version: "v1.0.0", GoVersion: "go1.23.0", GOOS: "plan9", GOARCH: "amd64", Counters: map[string]int64{"c": 1}},
+		},
+	}
+	upload := uploadableReport(cfg, report)
+	if got, want := len(upload.Programs), 1; got != want {
+		t.Fatalf("got %d uploaded programs, want %d", got, want)
+	}
+	if got := upload.Programs[0]; got.Version != "v1.0.0" || got.GoVersion != "go1.23.0" || got.GOOS != "linux" || got.Counters["c"] != 1 {
+		t.Errorf("uploaded unexpected program %+v", got)
+	}
+}
+
+func TestUploadableReport_Events(t *testing.T) {
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:      []string{"linux"},
+		GOARCH:    []string{"amd64"},
+		GoVersion: []string{"go1.23.0"},
+		Programs: []*telemetry.ProgramConfig{{
+			Name:     "prog",
+			Versions: []string{"v1.0.0"},
+			Events: []telemetry.EventConfig{
+				{
+					Name:  "switch",
+					Attrs: []telemetry.AttrConfig{{Name: "to", Values: []string{"go1.22", "go1.23"}}},
+					Rate:  1,
+				},
+				{
+					Name:  "rare",
+					Attrs: []telemetry.AttrConfig{{Name: "kind", Values: []string{"a"}}},
+					Rate:  0.1,
+				},
+			},
+		}},
+	})
+	report := &telemetry.Report{
+		Week: "2024-01-01",
+		X:    0.5,
+		Programs: []*telemetry.ProgramReport{{
+			Program:   "prog",
+			Version:   "v1.0.0",
+			GoVersion: "go1.23.0",
+			GOOS:      "linux",
+			GOARCH:    "amd64",
+			Events: map[string]int64{
+				"switch{to=go1.22}":   1,
+				"switch{to=go1.23}":   2,
+				"switch{to=go1.24rc}": 4, // not enumerated
+				"rare{kind=a}":        8, // not sampled
+				"unknown{kind=a}":     16,
+			},
+		}},
+	}
+	upload := uploadableReport(cfg, report)
+	if len(upload.Programs) != 1 {
+		t.Fatalf("got %d uploaded programs, want 1", len(upload.Programs))
+	}
+	got := upload.Programs[0].Events
+	want := map[string]int64{
+		"switch{to=go1.22}": 1,
+		"switch{to=go1.23}": 2,
+	}
+	if len(got) != len(want) {
+		t.Errorf("uploaded events %v, want %v", got, want)
+	}
+	for name, n := range want {
+		if got[name] != n {
+			t.Errorf("uploaded event %q with count %d, want %d", name, got[name], n)
+		}
+	}
+}
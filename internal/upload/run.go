@@ -5,14 +5,17 @@
 package upload
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/telemetry/internal/configstore"
@@ -23,11 +26,27 @@ import (
 //
 // All fields are optional, for testing or observability.
 type RunConfig struct {
-	TelemetryDir string    // if set, overrides the telemetry data directory
-	UploadURL    string    // if set, overrides the telemetry upload endpoint
-	LogWriter    io.Writer // if set, used for detailed logging of the upload process
-	Env          []string  // if set, appended to the config download environment
-	StartTime    time.Time // if set, overrides the upload start time
+	TelemetryDir string     // if set, overrides the telemetry data directory
+	UploadURL    string     // if set, overrides the telemetry upload endpoint
+	LogWriter    io.Writer  // if set, used for detailed logging of the upload process
+	LogLevel     slog.Level // minimum level of records logged to LogWriter; defaults to slog.LevelInfo
+	Env          []string   // if set, appended to the config download environment
+	StartTime    time.Time  // if set, overrides the upload start time
+
+	// TolerateClockSkew, if set, causes count files whose recorded time span
+	// is inconsistent with their modification time or the weekends file to be
+	// re-dated using their modification time, rather than being treated as
+	// too old or still active. The local reports of programs with such files
+	// are annotated with an "upload/clockskew" counter, which is uploaded
+	// only if the upload config includes it for the program.
+	//
+	// This is intended for environments with unreliable clocks.
+	TolerateClockSkew bool
+
+	// DryRun, if set, causes Run to log the count files it would turn into
+	// reports and the reports it would upload, without changing the telemetry
+	// directory or contacting the upload server.
+	DryRun bool
 }
 
 // Run generates and uploads reports, as allowed by the mode file.
@@ -58,8 +77,16 @@ type uploader struct {
 
 	cache parsedCache
 
+	tolerateClockSkew bool
+	dryRun            bool
+	spans             sync.Map // count file name -> fileSpan
+
+	weekendOnce sync.Once
+	weekendDay  time.Weekday
+	haveWeekend bool
+
 	logFile *os.File
-	logger  *log.Logger
+	logger  *slog.Logger
 }
 
 // newUploader creates a new uploader to use for running the upload for the
@@ -85,31 +112,31 @@ func newUploader(rcfg RunConfig) (*uploader, error) {
 	//
 	// This depends on the provided rcfg.LogWriter and the presence of
 	// dir.DebugDir, as follows:
-	//  1. If LogWriter is present, log to it.
-	//  2. If DebugDir is present, log to a file within it.
-	//  3. If both LogWriter and DebugDir are present, log to a multi writer.
+	//  1. If LogWriter is present, log records at or above LogLevel to it.
+	//  2. If DebugDir is present, log records at all levels to a file within
+	//     it, as the debug directory exists to collect complete logs.
+	//  3. If both LogWriter and DebugDir are present, log to both.
 	//  4. If neither LogWriter nor DebugDir are present, log to a noop logger.
-	var logWriters []io.Writer
+	var handlers multiHandler
 	logFile, err := debugLogFile(dir.DebugDir())
 	if err != nil {
 		logFile = nil
 	}
 	if logFile != nil {
-		logWriters = append(logWriters, logFile)
+		handlers = append(handlers, newHandler(logFile, slog.LevelDebug))
 	}
 	if rcfg.LogWriter != nil {
-		logWriters = append(logWriters, rcfg.LogWriter)
+		handlers = append(handlers, newHandler(rcfg.LogWriter, rcfg.LogLevel))
 	}
-	var logWriter io.Writer
-	switch len(logWriters) {
+	var logger *slog.Logger
+	switch len(handlers) {
 	case 0:
-		logWriter = io.Discard
+		logger = newLogger(io.Discard, rcfg.LogLevel)
 	case 1:
-		logWriter = logWriters[0]
+		logger = slog.New(handlers[0])
 	default:
-		logWriter = io.MultiWriter(logWriters...)
+		logger = slog.New(handlers)
 	}
-	logger := log.New(logWriter, "", log.Ltime|log.Lmicroseconds|log.Lshortfile)
 
 	// Fetch the upload config, if it is not provided.
 	var (
@@ -145,6 +172,9 @@ func newUploader(rcfg RunConfig) (*uploader, error) {
 		uploadServerURL: uploadURL,
 		startTime:       startTime,
 
+		tolerateClockSkew: rcfg.TolerateClockSkew,
+		dryRun:            rcfg.DryRun,
+
 		logFile: logFile,
 		logger:  logger,
 	}, nil
@@ -164,18 +194,94 @@ func (u *uploader) Run() error {
 		return nil
 	}
 	todo := u.findWork()
+	if u.dryRun {
+		for _, f := range todo.countfiles {
+			u.logger.Info("would create report from count file", "file", filepath.Base(f))
+		}
+		for _, f := range todo.readyfiles {
+			u.logger.Info("would upload report", "file", filepath.Base(f))
+		}
+		return nil
+	}
 	ready, err := u.reports(&todo)
 	if err != nil {
-		u.logger.Printf("Error building reports: %v", err)
+		u.logger.Error("error building reports", "err", err)
 		return fmt.Errorf("reports failed: %v", err)
 	}
-	u.logger.Printf("Uploading %d reports", len(ready))
+	u.logger.Info("uploading reports", "count", len(ready))
 	for _, f := range ready {
 		u.uploadReport(f)
 	}
 	return nil
 }
 
+// newLogger returns a structured logger writing records at or above the given
+// level to w, in slog's text format.
+//
+// Records include the short file name and line of the logging call, to help
+// triage failures from debug logs collected in the field.
+func newLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(newHandler(w, level))
+}
+
+// newHandler returns the handler of a logger returned by newLogger.
+func newHandler(w io.Writer, level slog.Level) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.SourceKey && len(groups) == 0 {
+				if src, ok := a.Value.Any().(*slog.Source); ok {
+					a.Value = slog.StringValue(fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line))
+				}
+			}
+			return a
+		},
+	})
+}
+
+// A multiHandler is a slog.Handler that passes each record to those of its
+// handlers that are enabled for the record's level.
+type multiHandler []slog.Handler
+
+func (h multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range h {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range h {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (h multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hs := make(multiHandler, len(h))
+	for i, h := range h {
+		hs[i] = h.WithAttrs(attrs)
+	}
+	return hs
+}
+
+func (h multiHandler) WithGroup(name string) slog.Handler {
+	hs := make(multiHandler, len(h))
+	for i, h := range h {
+		hs[i] = h.WithGroup(name)
+	}
+	return hs
+}
+
 // debugLogFile arranges to write a log file in the given debug directory, if
 // it exists.
 func debugLogFile(debugDir string) (*os.File, error) {
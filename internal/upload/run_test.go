@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -235,6 +236,17 @@ func TestRun_Retries(t *testing.T) {
 			telemetryFiles{localReports: 1, unuploadedReports: 1},
 			telemetryFiles{localReports: 1, uploadedReports: 1},
 		},
+		{
+			// Throttled reports are retried, not discarded.
+			http.StatusTooManyRequests,
+			telemetryFiles{localReports: 1, unuploadedReports: 1},
+			telemetryFiles{localReports: 1, uploadedReports: 1},
+		},
+		{
+			http.StatusServiceUnavailable,
+			telemetryFiles{localReports: 1, unuploadedReports: 1},
+			telemetryFiles{localReports: 1, uploadedReports: 1},
+		},
 	}
 
 	for _, test := range tests {
@@ -431,6 +443,86 @@ func TestRun_MissingDate(t *testing.T) {
 	}
 }
 
+func TestRun_Quarantine(t *testing.T) {
+	// This test verifies that a count file that cannot be parsed is moved to
+	// the quarantine directory, retried a bounded number of times, and
+	// restored if it can be parsed again.
+
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewIncProgram(t, "prog", "counter")
+
+	telemetryDir := t.TempDir()
+	dir := telemetry.NewDir(telemetryDir)
+	asof := time.Now().Add(-15 * 24 * time.Hour)
+	if out, err := regtest.RunProgAsOf(t, telemetryDir, asof, prog); err != nil {
+		t.Fatalf("failed to run program: %s", out)
+	}
+	countFiles, err := filepath.Glob(filepath.Join(dir.LocalDir(), "*.v1.count"))
+	if err != nil || len(countFiles) != 1 {
+		t.Fatalf("after RunProgAsOf, found count files %v (err: %v), want 1", countFiles, err)
+	}
+	countFile := countFiles[0]
+	quarantined := filepath.Join(dir.QuarantineDir(), filepath.Base(countFile))
+	data, err := os.ReadFile(countFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted := bytes.Replace(data, []byte(`TimeBegin:`), []byte(`TimxBegin:`), 1)
+	if err := os.WriteFile(countFile, corrupted, 0666); err != nil {
+		t.Fatal(err)
+	}
+	// Recently modified files may still be in use, and are not quarantined.
+	if err := os.Chtimes(countFile, asof, asof); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, getUploads := runConfig(t, telemetryDir, []string{"counter"}, nil)
+	checkQuarantine := func(wantAttempts int) {
+		t.Helper()
+		got, err := upload.Quarantined(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].Name != filepath.Base(countFile) || got[0].Attempts != wantAttempts || got[0].Err == "" {
+			t.Errorf("Quarantined() = %+v, want %s with %d attempts", got, filepath.Base(countFile), wantAttempts)
+		}
+	}
+
+	for i := 1; i <= 4; i++ {
+		if err := upload.Run(cfg); err != nil {
+			t.Fatal(err)
+		}
+		checkTelemetryFiles(t, telemetryDir, telemetryFiles{})
+		// The file is retried until it has failed maxQuarantineAttempts (3) times.
+		checkQuarantine(min(i, 3))
+	}
+
+	// Even once repaired, a file that has used up its attempts stays put.
+	if err := os.WriteFile(quarantined, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := upload.Run(cfg); err != nil {
+		t.Fatal(err)
+	}
+	checkQuarantine(3)
+
+	// Give it another attempt, as if it had failed only once.
+	record := quarantined + ".err"
+	if err := os.WriteFile(record, []byte(`{"Err": "corrupt", "Attempts": 1}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := upload.Run(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := upload.Quarantined(dir); err != nil || len(got) != 0 {
+		t.Errorf("after repair, Quarantined() = %+v, %v, want none", got, err)
+	}
+	if got := len(getUploads()); got != 1 {
+		t.Errorf("after repair, got %d uploads, want 1", got)
+	}
+}
+
 func TestRun_ModeHandling(t *testing.T) {
 	// This test verifies that the uploader honors the telemetry mode, as well as
 	// its asof date.
@@ -543,6 +635,8 @@ func TestRun_DebugLog(t *testing.T) {
 			}
 
 			cfg, getUploads := runConfig(t, telemetryDir, []string{"counter"}, nil)
+			var buf syncBuffer
+			cfg.LogWriter = &buf
 			if err := upload.Run(cfg); err != nil {
 				t.Fatal(err)
 			}
@@ -555,6 +649,21 @@ func TestRun_DebugLog(t *testing.T) {
 			if gotDebugLogs := len(debugLogs); gotDebugLogs != test.wantDebugLogs {
 				t.Fatalf("got %d debug logs, want %d", gotDebugLogs, test.wantDebugLogs)
 			}
+
+			// Debug logs record debug messages, whatever the level of the
+			// LogWriter.
+			for _, name := range debugLogs {
+				data, err := os.ReadFile(filepath.Join(telemetryDir, "debug", name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Contains(data, []byte("level=DEBUG")) {
+					t.Errorf("debug log %s has no debug records:\n%s", name, data)
+				}
+			}
+			if logs := buf.String(); strings.Contains(logs, "level=DEBUG") {
+				t.Errorf("LogWriter received debug records at level %s:\n%s", cfg.LogLevel, logs)
+			}
 		})
 	}
 }
@@ -614,6 +723,159 @@ func TestRun_Concurrent(t *testing.T) {
 	}
 }
 
+func TestRun_LogLevel(t *testing.T) {
+	// This test verifies that the uploader emits structured records for key
+	// events, and honors the configured log level.
+
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewIncProgram(t, "prog1", "counter")
+
+	tests := []struct {
+		level     slog.Level
+		wantDebug bool
+	}{
+		{slog.LevelInfo, false},
+		{slog.LevelDebug, true},
+	}
+	for _, test := range tests {
+		t.Run(test.level.String(), func(t *testing.T) {
+			telemetryDir := t.TempDir()
+			asof := time.Now().Add(-8 * 24 * time.Hour)
+			if out, err := regtest.RunProgAsOf(t, telemetryDir, asof, prog); err != nil {
+				t.Fatalf("failed to run program: %s", out)
+			}
+
+			cfg, getUploads := runConfig(t, telemetryDir, []string{"counter"}, nil)
+			var buf syncBuffer
+			cfg.LogWriter = &buf
+			cfg.LogLevel = test.level
+			if err := upload.Run(cfg); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(getUploads()); got != 1 {
+				t.Fatalf("got %d uploads, want 1", got)
+			}
+
+			logs := buf.String()
+			for _, want := range []string{
+				`level=INFO`,
+				`msg="selecting weeks"`,
+				`msg=uploaded`,
+				`status=200`,
+				`source=`,
+			} {
+				if !strings.Contains(logs, want) {
+					t.Errorf("logs do not contain %q:\n%s", want, logs)
+				}
+			}
+			if got := strings.Contains(logs, "level=DEBUG"); got != test.wantDebug {
+				t.Errorf("logs contain debug records: %t, want %t:\n%s", got, test.wantDebug, logs)
+			}
+		})
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	// This test verifies that a dry run reports the work to be done without
+	// doing it.
+
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewIncProgram(t, "prog", "counter")
+	telemetryDir := t.TempDir()
+	if out, err := regtest.RunProgAsOf(t, telemetryDir, time.Now().Add(-8*24*time.Hour), prog); err != nil {
+		t.Fatalf("failed to run program: %s", out)
+	}
+
+	cfg, getUploads := runConfig(t, telemetryDir, []string{"counter"}, nil)
+	var buf syncBuffer
+	cfg.LogWriter = &buf
+	cfg.DryRun = true
+	if err := upload.Run(cfg); err != nil {
+		t.Fatal(err)
+	}
+	checkTelemetryFiles(t, telemetryDir, telemetryFiles{counterFiles: 1})
+	if got := len(getUploads()); got != 0 {
+		t.Errorf("dry run made %d uploads, want 0", got)
+	}
+	if logs := buf.String(); !strings.Contains(logs, `msg="would create report from count file"`) {
+		t.Errorf("dry run logs do not mention the count file:\n%s", logs)
+	}
+
+	cfg.DryRun = false
+	if err := upload.Run(cfg); err != nil {
+		t.Fatal(err)
+	}
+	checkTelemetryFiles(t, telemetryDir, telemetryFiles{localReports: 1, uploadedReports: 1})
+}
+
+func TestExport_CountFiles(t *testing.T) {
+	// This test verifies that Export creates reports from expired count files
+	// using the supplied config, without downloading it.
+
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewIncProgram(t, "prog", "counter")
+	telemetryDir := t.TempDir()
+	if out, err := regtest.RunProgAsOf(t, telemetryDir, time.Now().Add(-8*24*time.Hour), prog); err != nil {
+		t.Fatalf("failed to run program: %s", out)
+	}
+	if err := telemetry.NewDir(telemetryDir).SetModeAsOf("on", time.Now().Add(-365*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	checkTelemetryFiles(t, telemetryDir, telemetryFiles{counterFiles: 1})
+
+	downloads := configstore.Downloads()
+	ucfg := upload.CreateTestUploadConfig(t, []string{"counter"}, nil)
+	archive := filepath.Join(t.TempDir(), "export.zip")
+	n, err := upload.Export(telemetryDir, archive, ucfg, "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("Export exported %d reports, want 1", n)
+	}
+	if got := configstore.Downloads(); got != downloads {
+		t.Errorf("Export downloaded the upload config %d times, want 0", got-downloads)
+	}
+	checkTelemetryFiles(t, telemetryDir, telemetryFiles{localReports: 1, uploadedReports: 1})
+
+	srv, getUploads := upload.CreateTestUploadServer(t)
+	if _, _, err := upload.Import(archive, upload.RunConfig{UploadURL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	uploads := getUploads()
+	if len(uploads) != 1 {
+		t.Fatalf("got %d uploads, want 1", len(uploads))
+	}
+	var got telemetry.Report
+	if err := json.Unmarshal(uploads[0], &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Config != "v1.2.3" || len(got.Programs) != 1 || got.Programs[0].Counters["counter"] != 1 {
+		t.Errorf("imported report = %+v, want config v1.2.3 with counter=1", got)
+	}
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
 func getDebugLogs(t *testing.T, debugDir string) []string {
 	t.Helper()
 	if stat, err := os.Stat(debugDir); err != nil || !stat.IsDir() {
@@ -630,7 +892,7 @@ func getDebugLogs(t *testing.T, debugDir string) []string {
 			continue
 		}
 		contents, err := os.ReadFile(filepath.Join(debugDir, f.Name()))
-		if err != nil || !bytes.Contains(contents, []byte("mode on")) {
+		if err != nil || !bytes.Contains(contents, []byte("mode=on")) {
 			t.Logf("Ignoring %v - unreadable or unexpected contents (err: %v)", f.Name(), err)
 			continue
 		}
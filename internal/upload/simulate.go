@@ -0,0 +1,170 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upload
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// A Simulation is the result of [Simulate]: the reports that would be
+// created for a week, and why data would be left out of the upload.
+type Simulation struct {
+	// Local is the complete report, as saved in the local directory.
+	Local *telemetry.Report
+	// Upload is the report that would be uploaded, if Blockers is empty.
+	Upload *telemetry.Report
+	// Blockers are the reasons the report would not be uploaded at all.
+	Blockers []string
+	// Dropped lists the counters of Local that are omitted from Upload,
+	// sorted by program and then counter name.
+	Dropped []DroppedCounter
+}
+
+// A DroppedCounter is a counter or stack counter that is omitted from an
+// upload, and the reason.
+type DroppedCounter struct {
+	Program string // program path, version, Go version, and GOOS/GOARCH
+	Counter string
+	Reason  string
+}
+
+// Simulate computes the report for the week ending on week (YYYY-MM-DD) from
+// the count files in the telemetry directory, as an upload would, using the
+// given upload config and config version. The week need not have ended.
+//
+// Simulate neither modifies the telemetry directory nor uses the network.
+// Only the TelemetryDir, LogWriter, LogLevel, StartTime, and
+// TolerateClockSkew fields of rcfg are used.
+//
+// As with a real upload, the report's X is chosen at random, so the sampled
+// counters differ from one simulation to the next.
+func Simulate(rcfg RunConfig, week string, ucfg *telemetry.UploadConfig, configVersion string) (*Simulation, error) {
+	if _, err := time.Parse(dateFormat, week); err != nil {
+		return nil, fmt.Errorf("invalid week %q: %v", week, err)
+	}
+	return simulate(rcfg, week, ucfg, configVersion, computeRandom())
+}
+
+// Preview is like Simulate, but with sampling disabled: the report's X is 0,
+// so the upload includes every counter that the config permits, whatever its
+// rate. If week is empty, Preview uses the earliest week of the count files,
+// which is the week the next report would cover.
+func Preview(rcfg RunConfig, week string, ucfg *telemetry.UploadConfig, configVersion string) (*Simulation, error) {
+	if week != "" {
+		if _, err := time.Parse(dateFormat, week); err != nil {
+			return nil, fmt.Errorf("invalid week %q: %v", week, err)
+		}
+	}
+	return simulate(rcfg, week, ucfg, configVersion, 0)
+}
+
+// simulate implements Simulate and Preview, using x as the report's X. An
+// empty week selects the earliest week of the count files.
+func simulate(rcfg RunConfig, week string, ucfg *telemetry.UploadConfig, configVersion string, x float64) (*Simulation, error) {
+	dir := telemetry.Default
+	if rcfg.TelemetryDir != "" {
+		dir = telemetry.NewDir(rcfg.TelemetryDir)
+	}
+	logWriter := rcfg.LogWriter
+	if logWriter == nil {
+		logWriter = io.Discard
+	}
+	startTime := time.Now().UTC()
+	if !rcfg.StartTime.IsZero() {
+		startTime = rcfg.StartTime
+	}
+	u := &uploader{
+		config:            ucfg,
+		configVersion:     configVersion,
+		dir:               dir,
+		startTime:         startTime,
+		tolerateClockSkew: rcfg.TolerateClockSkew,
+		logger:            newLogger(logWriter, rcfg.LogLevel),
+	}
+
+	fis, err := os.ReadDir(dir.LocalDir())
+	if err != nil {
+		return nil, err
+	}
+	var (
+		countFiles []string
+		begin      time.Time // earliest begin time of any count file
+		earliest   = week == ""
+	)
+	for _, fi := range fis {
+		if !strings.HasSuffix(fi.Name(), ".v1.count") {
+			continue
+		}
+		fname := filepath.Join(dir.LocalDir(), fi.Name())
+		b, e, err := u.countFileSpan(fname)
+		if err != nil {
+			u.logger.Warn("error reading expiry for count file", "file", fi.Name(), "err", err)
+			continue
+		}
+		if earliest && (week == "" || e.Format(dateFormat) < week) {
+			week, countFiles, begin = e.Format(dateFormat), nil, time.Time{}
+		}
+		if e.Format(dateFormat) != week {
+			continue
+		}
+		countFiles = append(countFiles, fname)
+		if begin.IsZero() || b.Before(begin) {
+			begin = b
+		}
+	}
+	if len(countFiles) == 0 {
+		if earliest {
+			return nil, fmt.Errorf("no count files")
+		}
+		return nil, fmt.Errorf("no count files for the week ending %s", week)
+	}
+
+	// As in reports, LastWeek is the date of the latest uploaded report.
+	uploaded := make(map[string]bool)
+	if fis, err := os.ReadDir(dir.UploadDir()); err == nil {
+		for _, fi := range fis {
+			uploaded[fi.Name()] = true
+		}
+	}
+	lastWeek := latestReport(uploaded)
+	if lastWeek >= startTime.Format(telemetry.DateOnly) {
+		lastWeek = ""
+	}
+
+	report := &telemetry.Report{
+		Config:   configVersion,
+		X:        x,
+		Week:     week,
+		LastWeek: lastWeek,
+	}
+	sim := &Simulation{
+		Local:    report,
+		Blockers: u.uploadBlockers(begin, week, report.X),
+	}
+	if !u.addCounts(report, countFiles) {
+		return nil, fmt.Errorf("none of the %d count files for %s contained counters", len(countFiles), week)
+	}
+	sim.Upload = filterReport(config.NewConfig(ucfg), report, func(p *telemetry.ProgramReport, name, reason string) {
+		prog := fmt.Sprintf("%s@%s %s %s/%s", p.Program, p.Version, p.GoVersion, p.GOOS, p.GOARCH)
+		sim.Dropped = append(sim.Dropped, DroppedCounter{prog, name, reason})
+	})
+	sort.Slice(sim.Dropped, func(i, j int) bool {
+		di, dj := sim.Dropped[i], sim.Dropped[j]
+		if di.Program != dj.Program {
+			return di.Program < dj.Program
+		}
+		return di.Counter < dj.Counter
+	})
+	return sim, nil
+}
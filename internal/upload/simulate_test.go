@@ -0,0 +1,167 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upload_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"golang.org/x/telemetry/counter"
+	icounter "golang.org/x/telemetry/internal/counter"
+	"golang.org/x/telemetry/internal/regtest"
+	"golang.org/x/telemetry/internal/telemetry"
+	"golang.org/x/telemetry/internal/testenv"
+	"golang.org/x/telemetry/internal/upload"
+)
+
+func TestSimulate(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewProgram(t, "prog", func() int {
+		counter.Inc("knownCounter")
+		counter.Inc("unknownCounter")
+		return 0
+	})
+	telemetryDir := t.TempDir()
+	if out, err := regtest.RunProgAsOf(t, telemetryDir, time.Now().Add(-8*24*time.Hour), prog); err != nil {
+		t.Fatalf("failed to run program: %s", out)
+	}
+	if err := telemetry.NewDir(telemetryDir).SetModeAsOf("on", time.Now().Add(-365*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	week := countFileWeek(t, telemetryDir)
+	before := dirContents(t, telemetryDir)
+
+	ucfg := upload.CreateTestUploadConfig(t, []string{"knownCounter"}, nil)
+	sim, err := upload.Simulate(upload.RunConfig{TelemetryDir: telemetryDir}, week, ucfg, "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sim.Blockers) != 0 {
+		t.Errorf("Simulate blockers = %q, want none", sim.Blockers)
+	}
+	if sim.Upload.Week != week || sim.Upload.Config != "v1.2.3" {
+		t.Errorf("Simulate upload has week %s, config %s; want %s, v1.2.3", sim.Upload.Week, sim.Upload.Config, week)
+	}
+	if len(sim.Upload.Programs) != 1 {
+		t.Fatalf("Simulate upload has %d programs, want 1", len(sim.Upload.Programs))
+	}
+	if got, want := sim.Upload.Programs[0].Counters, map[string]int64{"knownCounter": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Simulate uploaded counters = %v, want %v", got, want)
+	}
+	if got := sim.Local.Programs[0].Counters["unknownCounter"]; got != 1 {
+		t.Errorf("Simulate local unknownCounter = %d, want 1", got)
+	}
+	if len(sim.Dropped) != 1 || sim.Dropped[0].Counter != "unknownCounter" || sim.Dropped[0].Reason != "counter is not in the config" {
+		t.Errorf("Simulate dropped %+v, want unknownCounter (not in config)", sim.Dropped)
+	}
+
+	// Simulation leaves the directory as it was.
+	if after := dirContents(t, telemetryDir); !reflect.DeepEqual(after, before) {
+		t.Errorf("Simulate modified the telemetry directory:\nbefore: %v\nafter:  %v", before, after)
+	}
+
+	// The report would not be uploaded in local mode.
+	if err := telemetry.NewDir(telemetryDir).SetMode("local"); err != nil {
+		t.Fatal(err)
+	}
+	sim, err = upload.Simulate(upload.RunConfig{TelemetryDir: telemetryDir}, week, ucfg, "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sim.Blockers) == 0 {
+		t.Errorf("Simulate in local mode has no blockers")
+	}
+
+	if _, err := upload.Simulate(upload.RunConfig{TelemetryDir: telemetryDir}, "2001-01-01", ucfg, "v1.2.3"); err == nil {
+		t.Errorf("Simulate for a week without count files succeeded")
+	}
+}
+
+func TestPreview(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewProgram(t, "prog", func() int {
+		counter.Inc("rareCounter")
+		return 0
+	})
+	telemetryDir := t.TempDir()
+	if out, err := regtest.RunProgAsOf(t, telemetryDir, time.Now().Add(-8*24*time.Hour), prog); err != nil {
+		t.Fatalf("failed to run program: %s", out)
+	}
+	if err := telemetry.NewDir(telemetryDir).SetModeAsOf("on", time.Now().Add(-365*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	week := countFileWeek(t, telemetryDir)
+
+	// A counter with a tiny rate is almost never sampled by Simulate, but
+	// Preview disables sampling.
+	ucfg := upload.CreateTestUploadConfig(t, []string{"rareCounter"}, nil)
+	ucfg.Programs[0].Counters[0].Rate = 1e-12
+	sim, err := upload.Preview(upload.RunConfig{TelemetryDir: telemetryDir}, "", ucfg, "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sim.Upload.Week != week || sim.Upload.X != 0 {
+		t.Errorf("Preview upload has week %s, X %g; want %s, 0", sim.Upload.Week, sim.Upload.X, week)
+	}
+	if len(sim.Upload.Programs) != 1 {
+		t.Fatalf("Preview upload has %d programs, want 1", len(sim.Upload.Programs))
+	}
+	if got, want := sim.Upload.Programs[0].Counters, map[string]int64{"rareCounter": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Preview uploaded counters = %v, want %v", got, want)
+	}
+
+	if _, err := upload.Preview(upload.RunConfig{TelemetryDir: telemetryDir}, "2001-01-01", ucfg, "v1.2.3"); err == nil {
+		t.Errorf("Preview for a week without count files succeeded")
+	}
+}
+
+// countFileWeek returns the end date of the single count file in
+// telemetryDir.
+func countFileWeek(t *testing.T, telemetryDir string) string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(telemetry.NewDir(telemetryDir).LocalDir(), "*.count"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("got count files %v (err %v), want 1", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := icounter.Parse(files[0], data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	end, err := time.Parse(time.RFC3339, f.Meta["TimeEnd"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return end.Format(telemetry.DateOnly)
+}
+
+// dirContents returns the relative names and modification times of the files
+// in dir.
+func dirContents(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, rel+" "+info.ModTime().String())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
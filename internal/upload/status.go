@@ -0,0 +1,109 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upload
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+// A Status summarizes the state of the upload process for a telemetry
+// directory.
+type Status struct {
+	Mode     string
+	ModeAsOf time.Time // zero if the mode file has no date
+
+	ActiveCountFiles  int // count files whose week has not ended
+	ExpiredCountFiles int // count files waiting to be turned into reports
+	ReadyReports      int // reports waiting to be uploaded
+
+	// LastUpload is the time of the most recent successful upload, and
+	// LastUploadWeek the week of the report it uploaded. Both are zero if no
+	// report has been uploaded.
+	LastUpload     time.Time
+	LastUploadWeek string
+
+	// NextReport is the time at which the earliest active count file
+	// expires, after which the next upload may produce a report. If there
+	// are no active count files, it is the next occurrence of the weekday
+	// recorded in the weekends file. It is zero if neither is known.
+	NextReport time.Time
+}
+
+// ReadStatus reports the status of the upload process for dir, as of now.
+// It does not change anything in dir or use the network.
+func ReadStatus(dir telemetry.Dir, now time.Time) (*Status, error) {
+	u := &uploader{
+		config:    &telemetry.UploadConfig{},
+		dir:       dir,
+		startTime: now,
+		dryRun:    true,
+		logger:    newLogger(io.Discard, 0),
+	}
+	s := new(Status)
+	s.Mode, s.ModeAsOf = dir.Mode()
+
+	todo := u.findWork()
+	s.ExpiredCountFiles = len(todo.countfiles)
+	for _, f := range todo.readyfiles {
+		if !todo.uploaded[filepath.Base(f)] {
+			s.ReadyReports++
+		}
+	}
+
+	entries, err := os.ReadDir(dir.LocalDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".v1.count") {
+			continue
+		}
+		_, expiry, err := u.countFileSpan(filepath.Join(dir.LocalDir(), e.Name()))
+		if err != nil || !expiry.After(now) {
+			continue // unparseable or expired
+		}
+		s.ActiveCountFiles++
+		if s.NextReport.IsZero() || expiry.Before(s.NextReport) {
+			s.NextReport = expiry
+		}
+	}
+	if s.NextReport.IsZero() {
+		if day, ok := u.weekend(); ok {
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			days := (int(day) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			s.NextReport = today.AddDate(0, 0, days)
+		}
+	}
+
+	entries, err = os.ReadDir(dir.UploadDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		// Uploaded reports are copied to the upload directory once the
+		// upload succeeds.
+		if fi.ModTime().After(s.LastUpload) {
+			s.LastUpload = fi.ModTime()
+			s.LastUploadWeek = strings.TrimSuffix(e.Name(), ".json")
+		}
+	}
+	return s, nil
+}
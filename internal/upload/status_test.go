@@ -0,0 +1,69 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package upload_test
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/telemetry/internal/regtest"
+	"golang.org/x/telemetry/internal/telemetry"
+	"golang.org/x/telemetry/internal/testenv"
+	"golang.org/x/telemetry/internal/upload"
+)
+
+func TestReadStatus(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+
+	prog := regtest.NewIncProgram(t, "prog", "counter")
+	telemetryDir := t.TempDir()
+	dir := telemetry.NewDir(telemetryDir)
+
+	readStatus := func() *upload.Status {
+		t.Helper()
+		s, err := upload.ReadStatus(dir, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	// An empty directory.
+	if s := readStatus(); s.ActiveCountFiles != 0 || s.ExpiredCountFiles != 0 || !s.LastUpload.IsZero() || !s.NextReport.IsZero() {
+		t.Errorf("ReadStatus(empty dir) = %+v, want nothing", s)
+	}
+
+	if out, err := regtest.RunProgAsOf(t, telemetryDir, time.Now().Add(-8*24*time.Hour), prog); err != nil {
+		t.Fatalf("failed to run program: %s", out)
+	}
+	cfg, _ := runConfig(t, telemetryDir, []string{"counter"}, nil)
+	s := readStatus()
+	if s.Mode != "on" || s.ModeAsOf.IsZero() || s.ExpiredCountFiles != 1 || s.ActiveCountFiles != 0 || s.ReadyReports != 0 {
+		t.Errorf("ReadStatus(expired count file) = %+v, want mode on and 1 expired count file", s)
+	}
+	if s.NextReport.IsZero() || !s.NextReport.After(time.Now()) {
+		t.Errorf("ReadStatus(expired count file).NextReport = %v, want a time after now", s.NextReport)
+	}
+	// ReadStatus changes nothing.
+	checkTelemetryFiles(t, telemetryDir, telemetryFiles{counterFiles: 1})
+
+	start := time.Now()
+	if err := upload.Run(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := regtest.RunProg(t, telemetryDir, prog); err != nil {
+		t.Fatalf("failed to run program: %s", out)
+	}
+	s = readStatus()
+	if s.ExpiredCountFiles != 0 || s.ActiveCountFiles != 1 || s.ReadyReports != 0 {
+		t.Errorf("ReadStatus(after upload) = %+v, want 1 active count file", s)
+	}
+	if s.LastUpload.Before(start.Add(-time.Second)) || s.LastUploadWeek == "" {
+		t.Errorf("ReadStatus(after upload) last upload = %v (week %q), want after %v", s.LastUpload, s.LastUploadWeek, start)
+	}
+	if !s.NextReport.After(time.Now()) {
+		t.Errorf("ReadStatus(after upload).NextReport = %v, want a time after now", s.NextReport)
+	}
+}
@@ -27,12 +27,12 @@ var (
 func (u *uploader) uploadReportDate(fname string) time.Time {
 	match := dateRE.FindStringSubmatch(fname)
 	if match == nil || len(match) < 2 {
-		u.logger.Printf("malformed report name: missing date: %q", filepath.Base(fname))
+		u.logger.Warn("malformed report name: missing date", "file", filepath.Base(fname))
 		return time.Time{}
 	}
 	d, err := time.Parse(dateFormat, match[1])
 	if err != nil {
-		u.logger.Printf("malformed report name: bad date: %q", filepath.Base(fname))
+		u.logger.Warn("malformed report name: bad date", "file", filepath.Base(fname))
 		return time.Time{}
 	}
 	return d
@@ -46,14 +46,14 @@ func (u *uploader) uploadReport(fname string) {
 	today := thisInstant.Format(telemetry.DateOnly)
 	match := dateRE.FindStringSubmatch(fname)
 	if match == nil || len(match) < 2 {
-		u.logger.Printf("Report name %q missing date", filepath.Base(fname))
+		u.logger.Warn("report name missing date", "file", filepath.Base(fname))
 	} else if match[1] > today {
-		u.logger.Printf("Report date for %q is later than today (%s)", filepath.Base(fname), today)
+		u.logger.Warn("report date is later than today", "file", filepath.Base(fname), "today", today)
 		return // report is in the future, which shouldn't happen
 	}
 	buf, err := os.ReadFile(fname)
 	if err != nil {
-		u.logger.Printf("%v reading %s", err, fname)
+		u.logger.Error("failed to read report", "file", fname, "err", err)
 		return
 	}
 	if u.uploadReportContents(fname, buf) {
@@ -73,7 +73,7 @@ func (u *uploader) uploadReportContents(fname string, buf []byte) bool {
 		lockname := newname + ".lock"
 		lockfile, err := os.OpenFile(lockname, os.O_CREATE|os.O_EXCL, 0666)
 		if err != nil {
-			u.logger.Printf("Failed to acquire lock %s: %v", lockname, err)
+			u.logger.Info("failed to acquire lock", "file", lockname, "err", err)
 			return false
 		}
 		_ = lockfile.Close()
@@ -83,7 +83,7 @@ func (u *uploader) uploadReportContents(fname string, buf []byte) bool {
 	if _, err := os.Stat(newname); err == nil {
 		// Another process uploaded but failed to clean up (or hasn't yet cleaned
 		// up). Ensure that cleanup occurs.
-		u.logger.Printf("After acquire: report already uploaded")
+		u.logger.Info("after acquire: report already uploaded", "file", filepath.Base(fname))
 		_ = os.Remove(fname)
 		return false
 	}
@@ -92,18 +92,19 @@ func (u *uploader) uploadReportContents(fname string, buf []byte) bool {
 	b := bytes.NewReader(buf)
 	resp, err := http.Post(endpoint, "application/json", b)
 	if err != nil {
-		u.logger.Printf("Error upload %s to %s: %v", filepath.Base(fname), endpoint, err)
+		u.logger.Error("upload failed", "file", filepath.Base(fname), "endpoint", endpoint, "err", err)
 		return false
 	}
-	// hope for a 200, remove file on a 4xx, otherwise it will be retried by another process
+	// hope for a 200, remove file on a 4xx other than 429 Too Many Requests,
+	// otherwise it will be retried by another process
 	if resp.StatusCode != 200 {
-		u.logger.Printf("Failed to upload %s to %s: %s", filepath.Base(fname), endpoint, resp.Status)
-		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		u.logger.Error("upload rejected", "file", filepath.Base(fname), "endpoint", endpoint, "status", resp.StatusCode)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
 			err := os.Remove(fname)
 			if err == nil {
-				u.logger.Printf("Removed local/%s", filepath.Base(fname))
+				u.logger.Info("removed rejected report", "file", filepath.Base(fname))
 			} else {
-				u.logger.Printf("Error removing local/%s: %v", filepath.Base(fname), err)
+				u.logger.Warn("failed to remove rejected report", "file", filepath.Base(fname), "err", err)
 			}
 		}
 		return false
@@ -112,6 +113,6 @@ func (u *uploader) uploadReportContents(fname string, buf []byte) bool {
 	if err := os.WriteFile(newname, buf, 0644); err == nil {
 		os.Remove(fname) // if it exists
 	}
-	u.logger.Printf("Uploaded %s to %q", fdate+".json", endpoint)
+	u.logger.Info("uploaded", "file", fdate+".json", "endpoint", endpoint, "status", resp.StatusCode)
 	return true
 }
@@ -0,0 +1,154 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package uploadable decides whether the counters in a count file are
+// eligible for upload under an upload config.
+//
+// The uploader, the local viewer, and tests must all agree on which counters
+// would be uploaded, so they share this logic rather than querying the
+// config themselves. The same rules are enforced by the upload server.
+package uploadable
+
+import (
+	"strings"
+
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/counter"
+)
+
+// Meta describes the program that recorded a count file.
+type Meta struct {
+	Program   string
+	Version   string
+	GoVersion string
+	GOOS      string
+	GOARCH    string
+}
+
+// MetaOf returns the Meta recorded in the metadata of a count file.
+func MetaOf(meta map[string]string) Meta {
+	return Meta{
+		Program:   meta["Program"],
+		Version:   meta["Version"],
+		GoVersion: meta["GoVersion"],
+		GOOS:      meta["GOOS"],
+		GOARCH:    meta["GOARCH"],
+	}
+}
+
+// A Decision says whether a counter is eligible for upload.
+type Decision int
+
+const (
+	Drop   Decision = iota // the counter is never uploaded
+	Upload                 // the counter is uploaded, if sampled
+)
+
+func (d Decision) String() string {
+	if d == Upload {
+		return "upload"
+	}
+	return "drop"
+}
+
+// A Reason explains a Drop decision.
+type Reason int
+
+const (
+	OK               Reason = iota // not dropped
+	UnknownProgram                 // the program is not in the config
+	UnknownGOOS                    // the GOOS is not in the config
+	UnknownGOARCH                  // the GOARCH is not in the config
+	UnknownGoVersion               // the Go version is not in the config
+	UnknownVersion                 // the program version is not in the config
+	UnknownCounter                 // the counter is not in the config
+)
+
+var reasons = [...]string{
+	OK:               "ok",
+	UnknownProgram:   "program is not in the config",
+	UnknownGOOS:      "GOOS is not in the config",
+	UnknownGOARCH:    "GOARCH is not in the config",
+	UnknownGoVersion: "Go version is not in the config",
+	UnknownVersion:   "program version is not in the config",
+	UnknownCounter:   "counter is not in the config",
+}
+
+func (r Reason) String() string {
+	if r < 0 || int(r) >= len(reasons) {
+		return "unknown reason"
+	}
+	return reasons[r]
+}
+
+// DecideProgram reports whether the counters of the program described by
+// meta may be uploaded under cfg at all. If the decision is Drop, the reason
+// is the first of the program's properties that is missing from cfg, checked
+// in the order program, GOOS, GOARCH, Go version, and program version.
+func DecideProgram(cfg *config.Config, meta Meta) (Decision, Reason) {
+	switch {
+	case !cfg.HasProgram(meta.Program):
+		return Drop, UnknownProgram
+	case !cfg.HasGOOS(meta.GOOS):
+		return Drop, UnknownGOOS
+	case !cfg.HasGOARCH(meta.GOARCH):
+		return Drop, UnknownGOARCH
+	case !cfg.HasGoVersion(meta.GoVersion):
+		return Drop, UnknownGoVersion
+	case !cfg.HasVersion(meta.Program, meta.Version):
+		return Drop, UnknownVersion
+	}
+	return Upload, OK
+}
+
+// Decide reports whether the counter with the given name, recorded by the
+// program described by meta, may be uploaded under cfg. The name of a stack
+// counter includes its stack, and the name of an event counter its
+// attribute values, as in a count file.
+//
+// An Upload decision does not account for sampling: the counter is uploaded
+// only if the report's X does not exceed the counter's rate, which is
+// cfg.Rate(meta.Program, ConfigName(name)).
+func Decide(cfg *config.Config, meta Meta, name string) (Decision, Reason) {
+	if d, r := DecideProgram(cfg, meta); d == Drop {
+		return d, r
+	}
+	if cfg.CounterType(meta.Program, name) == "" {
+		return Drop, UnknownCounter
+	}
+	return Upload, OK
+}
+
+// ConfigName returns the name under which the counter with the given name
+// appears in an upload config. For a stack counter, this is the first line
+// of its name; for an event counter, the name of the event; for other
+// counters, it is the name itself.
+func ConfigName(name string) string {
+	prefix, _, isStack := strings.Cut(name, "\n")
+	if !isStack {
+		if event, _, _, ok := counter.DecodeEvent(name); ok {
+			return event
+		}
+	}
+	return prefix
+}
+
+// TruncateStack returns the name of the stack counter with the given name,
+// keeping at most depth frames of its stack. A depth of zero, or a name that
+// is not a stack counter, leaves the name unchanged.
+//
+// The depth of uploaded stacks is limited by the depth in the upload config,
+// cfg.Depth(meta.Program, ConfigName(name)), so that programs may record
+// deeper stacks locally than have been approved for upload.
+func TruncateStack(name string, depth int) string {
+	if depth <= 0 {
+		return name
+	}
+	// The first line is the counter's config name.
+	lines := strings.SplitN(name, "\n", depth+2)
+	if len(lines) <= depth+1 {
+		return name
+	}
+	return strings.Join(lines[:depth+1], "\n")
+}
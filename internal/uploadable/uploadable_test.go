@@ -0,0 +1,107 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package uploadable
+
+import (
+	"testing"
+
+	"golang.org/x/telemetry/internal/config"
+	"golang.org/x/telemetry/internal/telemetry"
+)
+
+func TestDecide(t *testing.T) {
+	cfg := config.NewConfig(&telemetry.UploadConfig{
+		GOOS:      []string{"linux"},
+		GOARCH:    []string{"amd64"},
+		GoVersion: []string{"go1.23.0"},
+		Programs: []*telemetry.ProgramConfig{{
+			Name:     "prog",
+			Versions: []string{"v1.0.0"},
+			Counters: []telemetry.CounterConfig{
+				{Name: "c", Rate: 1},
+				{Name: "editor:{emacs,vim}", Rate: 1},
+			},
+			Stacks: []telemetry.CounterConfig{{Name: "crash", Rate: 1, Depth: 4}},
+			Events: []telemetry.EventConfig{{
+				Name: "switch",
+				Attrs: []telemetry.AttrConfig{
+					{Name: "from", Values: []string{"go1.22", "go1.23"}},
+					{Name: "to", Values: []string{"go1.23"}},
+				},
+				Rate: 1,
+			}},
+		}},
+	})
+	good := Meta{Program: "prog", Version: "v1.0.0", GoVersion: "go1.23.0", GOOS: "linux", GOARCH: "amd64"}
+	with := func(f func(*Meta)) Meta {
+		m := good
+		f(&m)
+		return m
+	}
+	tests := []struct {
+		meta     Meta
+		name     string
+		decision Decision
+		reason   Reason
+	}{
+		{good, "c", Upload, OK},
+		{good, "editor:vim", Upload, OK},
+		{good, "crash\nmain.f:+1\nmain.main:+2", Upload, OK},
+		{good, "crash", Drop, UnknownCounter}, // a stack counter in the config is not a plain counter
+		{good, "unknown", Drop, UnknownCounter},
+		{good, "editor:nano", Drop, UnknownCounter},
+		{good, "c\nmain.f:+1", Drop, UnknownCounter},
+		{good, "switch{from=go1.22,to=go1.23}", Upload, OK},
+		{good, "switch{from=go1.21,to=go1.23}", Drop, UnknownCounter}, // value not enumerated
+		{good, "switch{to=go1.23,from=go1.22}", Drop, UnknownCounter}, // attributes out of order
+		{good, "switch{from=go1.22}", Drop, UnknownCounter},
+		{good, "switch", Drop, UnknownCounter},
+		{with(func(m *Meta) { m.Program = "other" }), "c", Drop, UnknownProgram},
+		{with(func(m *Meta) { m.GOOS = "plan9" }), "c", Drop, UnknownGOOS},
+		{with(func(m *Meta) { m.GOARCH = "mips" }), "c", Drop, UnknownGOARCH},
+		{with(func(m *Meta) { m.GoVersion = "go1.21.0" }), "c", Drop, UnknownGoVersion},
+		{with(func(m *Meta) { m.Version = "v2.0.0" }), "c", Drop, UnknownVersion},
+		// The first missing property is reported.
+		{with(func(m *Meta) { m.Version = "v2.0.0"; m.GoVersion = "go1.21.0" }), "c", Drop, UnknownGoVersion},
+	}
+	for _, test := range tests {
+		d, r := Decide(cfg, test.meta, test.name)
+		if d != test.decision || r != test.reason {
+			t.Errorf("Decide(%+v, %q) = %v, %q; want %v, %q", test.meta, test.name, d, r, test.decision, test.reason)
+		}
+	}
+}
+
+func TestConfigName(t *testing.T) {
+	for name, want := range map[string]string{
+		"c":                     "c",
+		"crash\nmain.f:+1":      "crash",
+		"crash\nmain.f:+1\nx:2": "crash",
+		"switch{from=a,to=b}":   "switch",
+	} {
+		if got := ConfigName(name); got != want {
+			t.Errorf("ConfigName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestTruncateStack(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		depth int
+		want  string
+	}{
+		{"c", 1, "c"},
+		{"crash\nf:+1\ng:+2\nh:+3", 0, "crash\nf:+1\ng:+2\nh:+3"},
+		{"crash\nf:+1\ng:+2\nh:+3", 3, "crash\nf:+1\ng:+2\nh:+3"},
+		{"crash\nf:+1\ng:+2\nh:+3", 4, "crash\nf:+1\ng:+2\nh:+3"},
+		{"crash\nf:+1\ng:+2\nh:+3", 2, "crash\nf:+1\ng:+2"},
+		{"crash\nf:+1\ng:+2\nh:+3", 1, "crash\nf:+1"},
+	} {
+		if got := TruncateStack(test.name, test.depth); got != test.want {
+			t.Errorf("TruncateStack(%q, %d) = %q, want %q", test.name, test.depth, got, test.want)
+		}
+	}
+}
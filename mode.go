@@ -4,7 +4,12 @@
 
 package telemetry
 
-import "golang.org/x/telemetry/internal/telemetry"
+import (
+	"context"
+	"time"
+
+	"golang.org/x/telemetry/internal/telemetry"
+)
 
 // Mode returns the current telemetry mode.
 //
@@ -36,3 +41,49 @@ func Mode() string {
 func SetMode(mode string) error {
 	return telemetry.Default.SetMode(mode)
 }
+
+// modePollInterval is the interval at which WatchMode checks the mode.
+const modePollInterval = 5 * time.Second
+
+// WatchMode arranges for f to be called with the new telemetry mode whenever
+// the mode changes, for example because the user ran "gotelemetry off", until
+// ctx is done. Long-running programs that cache the result of [Mode] may use
+// it to stop recording promptly after the user opts out.
+//
+// The mode at the time of the call is not reported. WatchMode returns
+// immediately. It checks the mode periodically (currently every few seconds)
+// in a separate goroutine, from which f is called; calls to f are not
+// concurrent.
+func WatchMode(ctx context.Context, f func(mode string)) {
+	telemetry.Default.WatchMode(ctx, modePollInterval, f)
+}
+
+// ConsentState reports the state of the user's choice of telemetry mode: the
+// current mode (see [Mode]), the date from which it took effect, and whether
+// the user has been prompted to choose it by any program that called
+// [RecordConsent].
+//
+// The date is zero if the mode has never been set. Programs that prompt the
+// user to choose the mode can use prompted to avoid asking a user who has
+// already been asked by another program.
+func ConsentState() (mode string, asof time.Time, prompted bool) {
+	mode, asof = telemetry.Default.Mode()
+	n, _ := telemetry.Default.Prompts()
+	return mode, asof, n > 0
+}
+
+// RecordConsent records the user's response to a prompt to choose the
+// telemetry mode. It sets the mode to the given value, as [SetMode] does,
+// and increments the number of times that the user has been prompted, which
+// is shared by all programs that use RecordConsent.
+//
+// If mode is empty, RecordConsent records only the prompt, as when the user
+// dismisses it without making a choice.
+func RecordConsent(mode string) error {
+	if mode != "" {
+		if err := telemetry.Default.SetMode(mode); err != nil {
+			return err
+		}
+	}
+	return telemetry.Default.RecordPrompt()
+}
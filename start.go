@@ -5,11 +5,16 @@
 package telemetry
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -29,6 +34,19 @@ type Config struct {
 	// ReportCrashes is a non-functional unless the program is built with go1.23+.
 	ReportCrashes bool
 
+	// CrashStackDepth, if positive, is the number of frames of the
+	// crashing goroutine's stack that ReportCrashes records, in place of
+	// the default of 16. Deeper stacks are recorded locally, but are only
+	// uploaded to the depth approved by the upload config.
+	CrashStackDepth int
+
+	// IncludeAllGoroutineHeads, if set, causes ReportCrashes to also record
+	// the innermost non-runtime frame of every goroutine in the crash
+	// report, in the crash/goroutine-heads stack counter. This helps to
+	// diagnose crashes, such as deadlocks, in which the running goroutine
+	// is not at fault.
+	IncludeAllGoroutineHeads bool
+
 	// Upload causes this program to periodically upload approved counters
 	// from the local telemetry database to telemetry.go.dev.
 	//
@@ -43,7 +61,8 @@ type Config struct {
 
 	// TelemetryDir, if set, will specify an alternate telemetry
 	// directory to write data to. If not set, it uses the default
-	// directory.
+	// directory, which users may relocate by setting the GOTELEMETRYDIR
+	// environment variable to an absolute path.
 	// This field is intended to be used for isolating testing environments.
 	TelemetryDir string
 
@@ -59,6 +78,78 @@ type Config struct {
 	// UploadURL, if set, overrides the URL used to receive uploaded reports. If
 	// unset, this URL defaults to https://telemetry.go.dev/upload.
 	UploadURL string
+
+	// UploadPeriod, if positive and Upload is set, causes the telemetry
+	// sidecar to remain running for as long as this process does, and to
+	// attempt another upload each time the period elapses. This is intended
+	// for long-running programs, such as language servers, whose sessions may
+	// span several weeks.
+	//
+	// Reports are only created for weeks that have ended, and uploads are
+	// limited to one per day per machine across all programs, so there is
+	// little benefit to a period shorter than a few hours.
+	//
+	// As the sidecar runs until this process exits, [StartResult.Wait] does
+	// not wait for it when UploadPeriod is set.
+	UploadPeriod time.Duration
+
+	// TolerateClockSkew, if set, causes uploads to re-date count files
+	// whose recorded time span is inconsistent with their modification
+	// time, as when they were written while the system clock was badly
+	// wrong, rather than discarding them as too old or keeping them as
+	// still active. This is intended for environments with unreliable
+	// clocks.
+	//
+	// The local report of a program with re-dated count files has an
+	// "upload/clockskew" counter, which, like any other counter, is only
+	// uploaded if the upload config includes it for that program.
+	TolerateClockSkew bool
+
+	// Logger, if set, receives diagnostics about starting the telemetry
+	// sidecar process, and each line of the sidecar's own log output, for as
+	// long as this process is running. If unset, start failures are logged
+	// with the log package, and the sidecar logs only to local/debug/sidecar.log
+	// in the telemetry directory, if that directory exists.
+	//
+	// The sidecar continues to log to local/debug/sidecar.log, if it exists,
+	// regardless of Logger, as it may outlive this process.
+	Logger *slog.Logger
+
+	// NoSidecar, if set, prevents Start from executing the current
+	// executable as a sidecar process, for environments such as build
+	// sandboxes that forbid it.
+	//
+	// Without a sidecar, ReportCrashes saves any crash report to a file in
+	// the telemetry directory, and the crash is recorded the next time the
+	// same executable calls Start. Upload and UploadPeriod have no effect.
+	NoSidecar bool
+
+	// NoDaemonize, if set, prevents Start from detaching the sidecar from
+	// this process's session (on Unix) or console and job object (on
+	// Windows). By default, the sidecar is detached so that it survives
+	// the closing of the terminal in which the program runs, and never
+	// opens a console window. Programs whose process supervisor expects
+	// to stop all of their processes together may prefer to set it.
+	NoDaemonize bool
+
+	// DisableCounters, if set, prevents Start from opening the local
+	// counter file, for programs that want only crash reports. Counters
+	// incremented by the program are then not recorded, and the local
+	// telemetry directory is created only when there is a crash to
+	// record, or when crash reports are saved without a sidecar.
+	DisableCounters bool
+}
+
+// crashOptions returns the crashmonitor options for config.
+func crashOptions(config Config) crashmonitor.Options {
+	opts := crashmonitor.Options{
+		StackDepth:     config.CrashStackDepth,
+		GoroutineHeads: config.IncludeAllGoroutineHeads,
+	}
+	if config.DisableCounters {
+		opts.OpenCounters = counter.Open
+	}
+	return opts
 }
 
 // Start initializes telemetry using the specified configuration.
@@ -72,10 +163,10 @@ type Config struct {
 //
 // If [Config.ReportCrashes] is set, any fatal crash will be
 // recorded by incrementing a counter named for the stack of the
-// first running goroutine in the traceback.
+// crashing goroutine in the traceback.
 //
-// If either of these flags is set, Start re-executes the current
-// executable as a child process, in a special mode in which it
+// Unless [Config.NoSidecar] is set, if either of these flags is set,
+// Start re-executes the current executable as a child process, in a special mode in which it
 // acts as a telemetry sidecar for the parent process (the application).
 // In that mode, the call to Start will never return, so Start must
 // be called immediately within main, even before such things as
@@ -86,10 +177,19 @@ type Config struct {
 // Start returns a StartResult, which may be awaited via [StartResult.Wait] to
 // wait for all work done by Start to complete.
 func Start(config Config) *StartResult {
+	return start(config, nil)
+}
+
+// start implements Start and StartPrepared. If p is nil, the state of the
+// process is recorded now.
+func start(config Config, p *Prepared) *StartResult {
 	switch v := os.Getenv(telemetryChildVar); v {
 	case "":
 		// The subprocess started by parent has GO_TELEMETRY_CHILD=1.
-		return parent(config)
+		if p == nil {
+			p = snapshot()
+		}
+		return parent(config, p)
 	case "1":
 		child(config) // child will exit the process when it's done.
 	case "2":
@@ -113,11 +213,145 @@ func MaybeChild(config Config) {
 	// will be handled by telemetry.Start.
 }
 
+// A Prepared is the state of a process recorded by [Prepare], from which
+// [StartPrepared] starts telemetry.
+type Prepared struct {
+	exe    string   // executable to run as the sidecar
+	exeErr error    // error from os.Executable, if any
+	env    []string // environment of the sidecar
+
+	// passConfig reports whether the sidecar receives its Config from
+	// the parent, as it runs within Prepare, before the program has
+	// determined its Config.
+	passConfig bool
+}
+
+// Prepare prepares to start telemetry, for programs that cannot call [Start]
+// before doing anything else, for example because the telemetry directory
+// is given by a command-line flag. Such a program calls Prepare immediately
+// within main, and passes the result to [StartPrepared] once its
+// configuration is known.
+//
+// In the telemetry sidecar process, Prepare acts as the sidecar, using the
+// configuration passed by the parent's call to StartPrepared, and never
+// returns. In the application, Prepare records the executable and
+// environment of the process, so that the sidecar is unaffected by any
+// changes that the program makes to them before calling StartPrepared.
+func Prepare() *Prepared {
+	if os.Getenv(telemetryChildVar) == "1" {
+		if config, ok := passedConfig(); ok {
+			child(config) // child will exit the process when it's done.
+		}
+		// Otherwise the parent called Start, and StartPrepared
+		// will run the child with the program's own Config.
+	}
+	p := snapshot()
+	p.passConfig = true
+	return p
+}
+
+// StartPrepared is like [Start], but starts the sidecar from the state
+// recorded by p, which must be the result of [Prepare]. Unlike Start, it may
+// be called at any point in the program, such as after parsing flags.
+//
+// Config.Logger is not passed to the sidecar, which logs to its standard
+// error as usual.
+func StartPrepared(p *Prepared, config Config) *StartResult {
+	return start(config, p)
+}
+
+// snapshot records the state of this process needed to start the sidecar.
+func snapshot() *Prepared {
+	exe, err := os.Executable()
+	return &Prepared{exe: exe, exeErr: err, env: os.Environ()}
+}
+
+// A sidecarConfig is the part of a Config needed by a sidecar that receives
+// its Config from the parent, in the telemetryConfigVar environment
+// variable.
+type sidecarConfig struct {
+	ReportCrashes            bool
+	CrashStackDepth          int
+	IncludeAllGoroutineHeads bool
+	Upload                   bool
+	TelemetryDir             string
+	UploadStartTime          time.Time
+	UploadURL                string
+	UploadPeriod             time.Duration
+	TolerateClockSkew        bool
+	DisableCounters          bool
+}
+
+// encodeConfig encodes the part of config needed by the sidecar.
+func encodeConfig(config Config) (string, error) {
+	sc := sidecarConfig{
+		ReportCrashes:            config.ReportCrashes,
+		CrashStackDepth:          config.CrashStackDepth,
+		IncludeAllGoroutineHeads: config.IncludeAllGoroutineHeads,
+		Upload:                   config.Upload,
+		TelemetryDir:             config.TelemetryDir,
+		UploadStartTime:          config.UploadStartTime,
+		UploadURL:                config.UploadURL,
+		UploadPeriod:             config.UploadPeriod,
+		TolerateClockSkew:        config.TolerateClockSkew,
+		DisableCounters:          config.DisableCounters,
+	}
+	if sc.TelemetryDir != "" {
+		// The sidecar runs in a different directory.
+		dir, err := filepath.Abs(sc.TelemetryDir)
+		if err != nil {
+			return "", err
+		}
+		sc.TelemetryDir = dir
+	}
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// passedConfig returns the Config passed to the sidecar by its parent, if
+// any.
+func passedConfig() (Config, bool) {
+	v := os.Getenv(telemetryConfigVar)
+	if v == "" {
+		return Config{}, false
+	}
+	var sc sidecarConfig
+	if err := json.Unmarshal([]byte(v), &sc); err != nil {
+		log.Fatalf("invalid %s: %v", telemetryConfigVar, err)
+	}
+	return Config{
+		ReportCrashes:            sc.ReportCrashes,
+		CrashStackDepth:          sc.CrashStackDepth,
+		IncludeAllGoroutineHeads: sc.IncludeAllGoroutineHeads,
+		Upload:                   sc.Upload,
+		TelemetryDir:             sc.TelemetryDir,
+		UploadStartTime:          sc.UploadStartTime,
+		UploadURL:                sc.UploadURL,
+		UploadPeriod:             sc.UploadPeriod,
+		TolerateClockSkew:        sc.TolerateClockSkew,
+		DisableCounters:          sc.DisableCounters,
+	}, true
+}
+
 // A StartResult is a handle to the result of a call to [Start]. Call
 // [StartResult.Wait] to wait for the completion of all work done on behalf of
 // Start.
 type StartResult struct {
-	wg sync.WaitGroup
+	wg  sync.WaitGroup
+	err error // error starting the sidecar or crash reporting
+}
+
+// Err returns the error that prevented Start from starting the telemetry
+// sidecar process, or from setting up crash reporting without one, or nil if
+// there was no such error.
+func (res *StartResult) Err() error {
+	if res == nil {
+		return nil
+	}
+	return res.err
 }
 
 // Wait waits for the completion of all work initiated by [Start].
@@ -128,8 +362,14 @@ func (res *StartResult) Wait() {
 	res.wg.Wait()
 }
 
+// daemonize detaches the sidecar started by cmd from this process, where
+// supported.
 var daemonize = func(cmd *exec.Cmd) {}
 
+// ignoreSIGPIPE causes writes to broken pipes to fail, rather than terminate
+// the process, where that is not already the case.
+var ignoreSIGPIPE = func() {}
+
 // If telemetryChildVar is set to "1" in the environment, this is the telemetry
 // child.
 //
@@ -141,7 +381,16 @@ const telemetryChildVar = "GO_TELEMETRY_CHILD"
 // acquired by the parent, and the child should attempt an upload.
 const telemetryUploadVar = "GO_TELEMETRY_CHILD_UPLOAD"
 
-func parent(config Config) *StartResult {
+// If telemetryLogVar is set in the environment, it is the path of a log file
+// to which the child should write, in addition to its stderr, which is
+// connected to the parent's Config.Logger.
+const telemetryLogVar = "GO_TELEMETRY_CHILD_LOG"
+
+// If telemetryConfigVar is set in the environment, it is the JSON encoding of
+// the sidecarConfig of a child started by StartPrepared.
+const telemetryConfigVar = "GO_TELEMETRY_CHILD_CONFIG"
+
+func parent(config Config, p *Prepared) *StartResult {
 	if config.TelemetryDir != "" {
 		telemetry.Default = telemetry.NewDir(config.TelemetryDir)
 	}
@@ -155,44 +404,99 @@ func parent(config Config) *StartResult {
 		return result
 	}
 
-	counter.Open()
+	if config.DisableCounters {
+		if telemetry.Default.LocalDir() == "" {
+			// The telemetry dir wasn't initialized properly, probably
+			// because os.UserConfigDir did not complete successfully.
+			return result
+		}
+	} else {
+		counter.Open()
+
+		if _, err := os.Stat(telemetry.Default.LocalDir()); err != nil {
+			// There was a problem statting LocalDir, which is needed for both
+			// crash monitoring and counter uploading. Most likely, there was an
+			// error creating telemetry.LocalDir in the counter.Open call above.
+			// Don't start the child.
+			return result
+		}
+	}
 
-	if _, err := os.Stat(telemetry.Default.LocalDir()); err != nil {
-		// There was a problem statting LocalDir, which is needed for both
-		// crash monitoring and counter uploading. Most likely, there was an
-		// error creating telemetry.LocalDir in the counter.Open call above.
-		// Don't start the child.
+	reportCrashes := config.ReportCrashes && crashmonitor.Supported()
+	if config.NoSidecar {
+		if reportCrashes {
+			crashDir := filepath.Join(telemetry.Default.LocalDir(), "crash")
+			if err := crashmonitor.InProcess(crashDir, crashOptions(config)); err != nil {
+				result.err = fmt.Errorf("failed to set up crash reporting: %v", err)
+				logStartError(config.Logger, result.err)
+			}
+		}
 		return result
 	}
 
 	childShouldUpload := config.Upload && acquireUploadToken()
-	reportCrashes := config.ReportCrashes && crashmonitor.Supported()
+	uploadPeriodically := config.Upload && config.UploadPeriod > 0
 
-	if reportCrashes || childShouldUpload {
-		startChild(reportCrashes, childShouldUpload, result)
+	if reportCrashes || childShouldUpload || uploadPeriodically {
+		startChild(config, p, reportCrashes, childShouldUpload, uploadPeriodically, result)
 	}
 
 	return result
 }
 
-func startChild(reportCrashes, upload bool, result *StartResult) {
+func startChild(config Config, p *Prepared, reportCrashes, upload, uploadPeriodically bool, result *StartResult) {
+	logger := config.Logger
+
+	// fail records and logs a failure to start the child.
+	fail := func(format string, args ...any) {
+		result.err = fmt.Errorf(format, args...)
+		logStartError(logger, result.err)
+		sidecarStartFailed.Inc()
+	}
+
+	// goWait runs f in a new goroutine, which StartResult.Wait awaits unless
+	// the child is to run for as long as this process does.
+	goWait := func(f func()) {
+		if uploadPeriodically {
+			go f()
+			return
+		}
+		result.wg.Add(1)
+		go func() {
+			defer result.wg.Done()
+			f()
+		}()
+	}
+
 	// This process is the application (parent).
 	// Fork+exec the telemetry child.
-	exe, err := os.Executable()
-	if err != nil {
+	if p.exeErr != nil {
 		// There was an error getting os.Executable. It's possible
 		// for this to happen on AIX if os.Args[0] is not an absolute
 		// path and we can't find os.Args[0] in PATH.
-		log.Printf("failed to start telemetry sidecar: os.Executable: %v", err)
+		fail("failed to start telemetry sidecar: os.Executable: %v", p.exeErr)
 		return
 	}
-	cmd := exec.Command(exe, "** telemetry **") // this unused arg is just for ps(1)
-	daemonize(cmd)
-	cmd.Env = append(os.Environ(), telemetryChildVar+"=1")
+	cmd := exec.Command(p.exe, "** telemetry **") // this unused arg is just for ps(1)
+	if !config.NoDaemonize {
+		daemonize(cmd)
+	}
+	cmd.Env = append(slices.Clip(p.env), telemetryChildVar+"=1")
 	if upload {
 		cmd.Env = append(cmd.Env, telemetryUploadVar+"=1")
 	}
+	if p.passConfig {
+		v, err := encodeConfig(config)
+		if err != nil {
+			fail("failed to start telemetry sidecar: %v", err)
+			return
+		}
+		cmd.Env = append(cmd.Env, telemetryConfigVar+"="+v)
+	}
 	cmd.Dir = telemetry.Default.LocalDir()
+	if config.DisableCounters {
+		cmd.Dir = os.TempDir() // LocalDir may not exist
+	}
 
 	// The child process must write to a log file, not
 	// the stderr file it inherited from the parent, as
@@ -203,30 +507,50 @@ func startChild(reportCrashes, upload bool, result *StartResult) {
 	// By default, we discard the child process's stderr,
 	// but in line with the uploader, log to a file in debug
 	// only if that directory was created by the user.
+	var childLogPath string
 	fd, err := os.Stat(telemetry.Default.DebugDir())
 	if err != nil {
 		if !os.IsNotExist(err) {
-			log.Printf("failed to stat debug directory: %v", err)
+			fail("failed to stat debug directory: %v", err)
 			return
 		}
 	} else if fd.IsDir() {
-		// local/debug exists and is a directory. Set stderr to a log file path
-		// in local/debug.
-		childLogPath := filepath.Join(telemetry.Default.DebugDir(), "sidecar.log")
+		// local/debug exists and is a directory. Log to a file in it.
+		childLogPath = filepath.Join(telemetry.Default.DebugDir(), "sidecar.log")
+	}
+	if logger != nil {
+		// Forward the child's stderr to the logger. Since the child may
+		// outlive this process, it writes to the log file itself.
+		r, w, err := os.Pipe()
+		if err != nil {
+			fail("creating sidecar log pipe: %v", err)
+			return
+		}
+		defer w.Close() // the child has its own copy
+		cmd.Stderr = w
+		if childLogPath != "" {
+			cmd.Env = append(cmd.Env, telemetryLogVar+"="+childLogPath)
+		}
+		goWait(func() { forwardLog(logger, r) })
+	} else if childLogPath != "" {
+		// Set stderr to the log file.
 		childLog, err := os.OpenFile(childLogPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
 		if err != nil {
-			log.Printf("opening sidecar log file for child: %v", err)
+			fail("opening sidecar log file for child: %v", err)
 			return
 		}
 		defer childLog.Close()
 		cmd.Stderr = childLog
 	}
 
+	// The child's stdin is connected to a pipe that stays open until this
+	// process exits, so that the child can tell when it does. Crash reports
+	// are written to the same pipe.
 	var crashOutputFile *os.File
-	if reportCrashes {
+	if reportCrashes || uploadPeriodically {
 		pipe, err := cmd.StdinPipe()
 		if err != nil {
-			log.Printf("StdinPipe: %v", err)
+			fail("StdinPipe: %v", err)
 			return
 		}
 
@@ -235,22 +559,67 @@ func startChild(reportCrashes, upload bool, result *StartResult) {
 
 	if err := cmd.Start(); err != nil {
 		// The child couldn't be started. Log the failure.
-		log.Printf("can't start telemetry child process: %v", err)
+		fail("can't start telemetry child process: %v", err)
 		return
 	}
 	if reportCrashes {
 		crashmonitor.Parent(crashOutputFile)
 	}
-	result.wg.Add(1)
-	go func() {
-		cmd.Wait() // Release resources if cmd happens not to outlive this process.
-		result.wg.Done()
-	}()
+	goWait(func() {
+		// Release resources if cmd happens not to outlive this process.
+		// The child only exits before this process does once its work
+		// is done, so an error means that it failed.
+		if err := cmd.Wait(); err != nil {
+			sidecarCrashed.Inc()
+			if logger != nil {
+				logger.Warn("telemetry sidecar failed", "err", err)
+			}
+		}
+	})
+}
+
+// Counters of failures of the sidecar mechanism itself, recorded by the
+// parent.
+var (
+	sidecarStartFailed = counter.New("telemetry/sidecar-start-failed")
+	sidecarCrashed     = counter.New("telemetry/sidecar-crashed")
+)
+
+// logStartError logs an error from Start to logger, if set, or with the log
+// package.
+func logStartError(logger *slog.Logger, err error) {
+	if logger != nil {
+		logger.Error("telemetry.Start failed", "err", err)
+	} else {
+		log.Print(err)
+	}
+}
+
+// forwardLog logs each line read from r to logger, until r is closed by the
+// child.
+func forwardLog(logger *slog.Logger, r *os.File) {
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		logger.Info("telemetry sidecar", "log", scanner.Text())
+	}
 }
 
 func child(config Config) {
 	log.SetPrefix(fmt.Sprintf("telemetry-sidecar (pid %v): ", os.Getpid()))
 
+	// If stderr is connected to the parent, the parent may exit first. Don't
+	// let that kill the child, and keep logging to the log file, if any.
+	ignoreSIGPIPE()
+	var logWriter io.Writer = os.Stderr
+	if logPath := os.Getenv(telemetryLogVar); logPath != "" {
+		if f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600); err == nil {
+			defer f.Close()
+			logWriter = io.MultiWriter(f, bestEffortWriter{os.Stderr})
+		}
+	}
+	log.SetOutput(logWriter)
+
 	if config.TelemetryDir != "" {
 		telemetry.Default = telemetry.NewDir(config.TelemetryDir)
 	}
@@ -268,11 +637,13 @@ func child(config Config) {
 	upload := os.Getenv(telemetryUploadVar) == "1"
 
 	reportCrashes := config.ReportCrashes && crashmonitor.Supported()
-	uploadStartTime := config.UploadStartTime
-	uploadURL := config.UploadURL
+	uploadPeriodically := config.Upload && config.UploadPeriod > 0
 
 	// The crashmonitor and/or upload process may themselves record counters.
-	counter.Open()
+	// Without counters, the crashmonitor opens them only to record a crash.
+	if !config.DisableCounters {
+		counter.Open()
+	}
 
 	// Start crashmonitoring and uploading depending on what's requested
 	// and wait for the longer running child to complete before exiting:
@@ -282,13 +653,28 @@ func child(config Config) {
 
 	if reportCrashes {
 		g.Go(func() error {
-			crashmonitor.Child()
+			crashmonitor.Child(crashOptions(config))
 			return nil
 		})
 	}
 	if upload {
 		g.Go(func() error {
-			uploaderChild(uploadStartTime, uploadURL)
+			uploaderChild(config, config.UploadStartTime, logWriter)
+			return nil
+		})
+	}
+	if uploadPeriodically {
+		// When reporting crashes, the crash monitor consumes stdin, and exits
+		// the process once the parent exits.
+		parentExited := make(chan struct{})
+		if !reportCrashes {
+			go func() {
+				io.Copy(io.Discard, os.Stdin)
+				close(parentExited)
+			}()
+		}
+		g.Go(func() error {
+			uploadPeriodicallyChild(config, parentExited, logWriter)
 			return nil
 		})
 	}
@@ -297,16 +683,57 @@ func child(config Config) {
 	os.Exit(0)
 }
 
-func uploaderChild(asof time.Time, uploadURL string) {
+// uploaderChild runs an upload for config, using asof as the upload start
+// time.
+func uploaderChild(config Config, asof time.Time, logWriter io.Writer) {
 	if err := upload.Run(upload.RunConfig{
-		UploadURL: uploadURL,
-		LogWriter: os.Stderr,
-		StartTime: asof,
+		UploadURL:         config.UploadURL,
+		LogWriter:         logWriter,
+		StartTime:         asof,
+		TolerateClockSkew: config.TolerateClockSkew,
 	}); err != nil {
 		log.Printf("upload failed: %v", err)
 	}
 }
 
+// uploadPeriodicallyChild attempts an upload each time config.UploadPeriod
+// elapses, until parentExited is closed. As for the parent's initial upload,
+// each attempt must first acquire the upload token.
+func uploadPeriodicallyChild(config Config, parentExited <-chan struct{}, logWriter io.Writer) {
+	start := time.Now()
+	ticker := time.NewTicker(config.UploadPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-parentExited:
+			return
+		case now := <-ticker.C:
+			if !acquireUploadToken() {
+				continue
+			}
+			// Keep any overridden start time as far from the actual time as
+			// it was at startup.
+			asof := config.UploadStartTime
+			if !asof.IsZero() {
+				asof = asof.Add(now.Sub(start))
+			}
+			uploaderChild(config, asof, logWriter)
+		}
+	}
+}
+
+// A bestEffortWriter is an io.Writer that ignores the errors of its
+// underlying writer, so that it doesn't prevent writes to the other writers
+// of an io.MultiWriter.
+type bestEffortWriter struct {
+	w io.Writer
+}
+
+func (w bestEffortWriter) Write(p []byte) (int, error) {
+	w.w.Write(p)
+	return len(p), nil
+}
+
 // acquireUploadToken acquires a token permitting the caller to upload.
 // To limit the frequency of uploads, only one token is issue per
 // machine per time period.
@@ -8,11 +8,13 @@ package telemetry
 
 import (
 	"os/exec"
+	"os/signal"
 	"syscall"
 )
 
 func init() {
 	daemonize = daemonizePosix
+	ignoreSIGPIPE = func() { signal.Ignore(syscall.SIGPIPE) }
 }
 
 func daemonizePosix(cmd *exec.Cmd) {
@@ -7,10 +7,13 @@ package telemetry_test
 import (
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -99,28 +102,88 @@ func runProg(prog string) int {
 		})
 		res.Wait()
 
+	case "uploadlog":
+		res := telemetry.Start(telemetry.Config{
+			TelemetryDir:    telemetryDir,
+			Upload:          true,
+			UploadURL:       mustGetEnv(uploadURLEnv),
+			UploadStartTime: asof,
+			Logger:          slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		})
+		res.Wait()
+		if err := res.Err(); err != nil {
+			log.Fatalf("Start failed: %v", err)
+		}
+
+	case "periodic":
+		telemetry.Start(telemetry.Config{
+			TelemetryDir:    telemetryDir,
+			Upload:          true,
+			UploadURL:       mustGetEnv(uploadURLEnv),
+			UploadStartTime: asof,
+			UploadPeriod:    testUploadPeriod,
+		})
+		// Run until stdin is closed.
+		io.Copy(io.Discard, os.Stdin)
+
+	case "sidecarfail":
+		if os.Getenv("GO_TELEMETRY_CHILD") == "1" {
+			os.Exit(3) // the sidecar fails before doing any work
+		}
+		res := telemetry.Start(telemetry.Config{
+			TelemetryDir: telemetryDir,
+			Upload:       true,
+		})
+		res.Wait()
+
+	case "prepared":
+		p := telemetry.Prepare()
+		if os.Getenv("GO_TELEMETRY_CHILD") == "1" {
+			log.Fatal("Prepare returned in the sidecar")
+		}
+		// Simulate a program that learns its telemetry directory from its
+		// flags, and then modifies its environment.
+		os.Setenv(telemetryDirEnv, "")
+		telemetry.StartPrepared(p, telemetry.Config{
+			TelemetryDir:  telemetryDir,
+			ReportCrashes: true,
+		})
+		panic("crash!")
+
+	case "nocounters", "nocounters.crash":
+		telemetry.Start(telemetry.Config{
+			TelemetryDir:    telemetryDir,
+			ReportCrashes:   true,
+			DisableCounters: true,
+		})
+		counter.Inc("teststart/counter")
+		if prog == "nocounters.crash" {
+			panic("crash!")
+		}
+
+	case "envdir":
+		// The telemetry directory comes from GOTELEMETRYDIR alone.
+		telemetry.Start(telemetry.Config{
+			ReportCrashes: true,
+		})
+		counter.Inc("teststart/counter")
+		panic("crash!")
+
 	default:
 		log.Fatalf("unknown program %q", prog)
 	}
 	return 0
 }
 
-func execProg(t *testing.T, telemetryDir, prog string, asof time.Time, expectFailure bool, env ...string) {
-	// Run the runStart function below, via a fork+exec trick.
-	exe, err := os.Executable()
-	if err != nil {
-		t.Error(err)
-		return
+// execProg runs the given program, and returns its standard output.
+func execProg(t *testing.T, telemetryDir, prog string, asof time.Time, expectFailure bool, env ...string) []byte {
+	cmd := progCommand(t, telemetryDir, prog, asof, env...)
+	if cmd == nil {
+		return nil
 	}
-	cmd := exec.Command(exe, "** TestStart **") // this unused arg is just for ps(1)
 	if !expectFailure {
 		cmd.Stderr = os.Stderr
 	}
-	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, asofEnv+"="+asof.Format(it.DateOnly))
-	cmd.Env = append(cmd.Env, telemetryDirEnv+"="+telemetryDir)
-	cmd.Env = append(cmd.Env, runStartEnv+"="+prog) // see TestMain
-	cmd.Env = append(cmd.Env, env...)
 	out, err := cmd.Output()
 	if expectFailure {
 		if err == nil {
@@ -129,6 +192,24 @@ func execProg(t *testing.T, telemetryDir, prog string, asof time.Time, expectFai
 	} else if err != nil {
 		t.Errorf("program failed unexpectedly (%v):\n%s", err, out)
 	}
+	return out
+}
+
+// progCommand returns a command to run the given program.
+func progCommand(t *testing.T, telemetryDir, prog string, asof time.Time, env ...string) *exec.Cmd {
+	// Run the runStart function below, via a fork+exec trick.
+	exe, err := os.Executable()
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	cmd := exec.Command(exe, "** TestStart **") // this unused arg is just for ps(1)
+	cmd.Env = os.Environ()
+	cmd.Env = append(cmd.Env, asofEnv+"="+asof.Format(it.DateOnly))
+	cmd.Env = append(cmd.Env, telemetryDirEnv+"="+telemetryDir)
+	cmd.Env = append(cmd.Env, runStartEnv+"="+prog) // see TestMain
+	cmd.Env = append(cmd.Env, env...)
+	return cmd
 }
 
 func TestStart(t *testing.T) {
@@ -177,6 +258,240 @@ func TestStart(t *testing.T) {
 	}
 }
 
+func TestStartLogger(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	testenv.MustHaveExec(t)
+
+	telemetryDir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	uploadEnv := []string{uploadURLEnv + "=" + server.URL}
+	uc := regtest.CreateTestUploadConfig(t, []string{"teststart/counter"}, nil)
+	uploadEnv = append(uploadEnv, configtest.LocalProxyEnv(t, uc, "v1.2.3")...)
+
+	now := time.Now()
+	execProg(t, telemetryDir, "setmode", now.Add(-30*24*time.Hour), false)
+	execProg(t, telemetryDir, "inc", now.Add(-8*24*time.Hour), false)
+
+	// With a debug directory, the sidecar also logs to a file.
+	debugDir := it.NewDir(telemetryDir).DebugDir()
+	if err := os.MkdirAll(debugDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(execProg(t, telemetryDir, "uploadlog", now, false, uploadEnv...))
+	const want = "uploading reports"
+	if !strings.Contains(out, `msg="telemetry sidecar"`) || !strings.Contains(out, want) {
+		t.Errorf("Logger output does not contain sidecar log %q:\n%s", want, out)
+	}
+	data, err := os.ReadFile(filepath.Join(debugDir, "sidecar.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), want) {
+		t.Errorf("sidecar.log does not contain %q:\n%s", want, data)
+	}
+}
+
+func TestStartSidecarFailure(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	testenv.MustHaveExec(t)
+
+	telemetryDir := t.TempDir()
+	execProg(t, telemetryDir, "sidecarfail", time.Now(), false)
+
+	counts := readCounts(t, telemetryDir)
+	if got := counts["telemetry/sidecar-crashed"]; got != 1 {
+		t.Errorf("telemetry/sidecar-crashed = %d, want 1 (all counts: %v)", got, counts)
+	}
+	if got := counts["telemetry/sidecar-start-failed"]; got != 0 {
+		t.Errorf("telemetry/sidecar-start-failed = %d, want 0", got)
+	}
+}
+
+// readCounts returns the counts of all counters in the count files of
+// telemetryDir.
+func readCounts(t *testing.T, telemetryDir string) map[string]uint64 {
+	t.Helper()
+	counts, err := countsIn(telemetryDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return counts
+}
+
+func countsIn(telemetryDir string) (map[string]uint64, error) {
+	counts := make(map[string]uint64)
+	files, err := filepath.Glob(filepath.Join(it.NewDir(telemetryDir).LocalDir(), "*.count"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		f, err := ic.Parse(file, data)
+		if err != nil {
+			return nil, err
+		}
+		for name, n := range f.Count {
+			counts[name] += n
+		}
+	}
+	return counts, nil
+}
+
+// waitForCrash waits for the sidecar of a program that crashed to record
+// the crash in the count files of telemetryDir, and returns their counts.
+// Count files that can not be read or parsed may still be being created by
+// the sidecar, so they are read again rather than failing the test.
+func waitForCrash(t *testing.T, telemetryDir string) map[string]uint64 {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		counts, err := countsIn(telemetryDir)
+		if err == nil && counts["crash/reason:panic"] == 1 {
+			return counts
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("crash was not recorded (all counts: %v, error: %v)", counts, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStartPrepared(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	testenv.MustHaveExec(t)
+
+	if !crashmonitor.Supported() {
+		t.Skip("crashmonitor not supported")
+	}
+
+	telemetryDir := t.TempDir()
+	execProg(t, telemetryDir, "prepared", time.Now(), true)
+
+	// The sidecar records the crash after the program exits.
+	waitForCrash(t, telemetryDir)
+}
+
+func TestStartDisableCounters(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	testenv.MustHaveExec(t)
+
+	if !crashmonitor.Supported() {
+		t.Skip("crashmonitor not supported")
+	}
+
+	telemetryDir := t.TempDir()
+	localDir := it.NewDir(telemetryDir).LocalDir()
+	execProg(t, telemetryDir, "nocounters", time.Now(), false)
+	if _, err := os.Stat(localDir); !os.IsNotExist(err) {
+		t.Fatalf("after running without a crash, stat(local dir) = %v, want not exist", err)
+	}
+
+	execProg(t, telemetryDir, "nocounters.crash", time.Now(), true)
+	// The sidecar records the crash after the program exits.
+	counts := waitForCrash(t, telemetryDir)
+	if n := counts["teststart/counter"]; n != 0 {
+		t.Errorf("teststart/counter = %d, want 0 with counters disabled", n)
+	}
+}
+
+func TestStartEnvTelemetryDir(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	testenv.MustHaveExec(t)
+
+	if !crashmonitor.Supported() {
+		t.Skip("crashmonitor not supported")
+	}
+
+	telemetryDir := t.TempDir()
+	execProg(t, telemetryDir, "envdir", time.Now(), true, it.DirEnv+"="+telemetryDir)
+	// Both the program and its sidecar must write to GOTELEMETRYDIR.
+	counts := waitForCrash(t, telemetryDir)
+	if n := counts["teststart/counter"]; n != 1 {
+		t.Errorf("teststart/counter = %d, want 1", n)
+	}
+}
+
+// testUploadPeriod is the upload period of the "periodic" program.
+const testUploadPeriod = 50 * time.Millisecond
+
+func TestStartUploadPeriod(t *testing.T) {
+	testenv.SkipIfUnsupportedPlatform(t)
+	testenv.MustHaveExec(t)
+
+	telemetryDir := t.TempDir()
+	var (
+		uploadMu sync.Mutex
+		uploads  []string // report dates
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploadMu.Lock()
+		uploads = append(uploads, path.Base(r.URL.Path))
+		uploadMu.Unlock()
+	}))
+	defer server.Close()
+	numUploads := func() int {
+		uploadMu.Lock()
+		defer uploadMu.Unlock()
+		return len(uploads)
+	}
+	waitForUploads := func(n int) {
+		t.Helper()
+		for deadline := time.Now().Add(30 * time.Second); numUploads() < n; {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %d uploads; got %d", n, numUploads())
+			}
+			time.Sleep(testUploadPeriod)
+		}
+	}
+	uploadEnv := []string{uploadURLEnv + "=" + server.URL}
+	uc := regtest.CreateTestUploadConfig(t, []string{"teststart/counter"}, nil)
+	uploadEnv = append(uploadEnv, configtest.LocalProxyEnv(t, uc, "v1.2.3")...)
+	token := filepath.Join(it.NewDir(telemetryDir).LocalDir(), "upload.token")
+
+	now := time.Now()
+	execProg(t, telemetryDir, "setmode", now.Add(-30*24*time.Hour), false)
+	execProg(t, telemetryDir, "inc", now.Add(-8*24*time.Hour), false)
+
+	cmd := progCommand(t, telemetryDir, "periodic", now, uploadEnv...)
+	if cmd == nil {
+		return
+	}
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitForUploads(1)
+
+	// While the program runs, the sidecar uploads new reports, once the
+	// upload token can be acquired again.
+	execProg(t, telemetryDir, "inc", now.Add(-15*24*time.Hour), false)
+	if err := os.Remove(token); err != nil {
+		t.Fatal(err)
+	}
+	waitForUploads(2)
+
+	// Once the program exits, so does the sidecar.
+	stdin.Close()
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * testUploadPeriod)
+	execProg(t, telemetryDir, "inc", now.Add(-22*24*time.Hour), false)
+	os.Remove(token)
+	time.Sleep(20 * testUploadPeriod)
+	if got := numUploads(); got != 2 {
+		t.Errorf("after exit, got %d uploads, want 2", got)
+	}
+}
+
 func TestConcurrentStart(t *testing.T) {
 	testenv.SkipIfUnsupportedPlatform(t)
 	testenv.MustHaveExec(t)
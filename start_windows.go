@@ -9,6 +9,7 @@ package telemetry
 import (
 	"os/exec"
 	"syscall"
+	"unsafe"
 
 	"golang.org/x/sys/windows"
 )
@@ -23,7 +24,31 @@ func daemonizeWindows(cmd *exec.Cmd) {
 	// does not kill the child.
 	// See documentation of creation flags in the Microsoft documentation:
 	// https://learn.microsoft.com/en-us/windows/win32/procthread/process-creation-flags
+	flags := uint32(windows.DETACHED_PROCESS)
+
+	// Service managers, terminals and IDEs may run the parent in a job
+	// object, all of whose processes are terminated when the job is
+	// closed. Leave the job if it allows that.
+	if jobAllowsBreakaway() {
+		flags |= windows.CREATE_BREAKAWAY_FROM_JOB
+	}
 	cmd.SysProcAttr = &syscall.SysProcAttr{
-		CreationFlags: windows.DETACHED_PROCESS,
+		CreationFlags: flags,
+		// The sidecar never shows a window, even if the executable
+		// is a GUI application.
+		HideWindow: true,
 	}
 }
+
+// jobAllowsBreakaway reports whether this process is in a job object that
+// allows its child processes to break away from it.
+func jobAllowsBreakaway() bool {
+	var info windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+	err := windows.QueryInformationJobObject(
+		0, // the job of this process, if any
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+		nil)
+	return err == nil && info.BasicLimitInformation.LimitFlags&windows.JOB_OBJECT_LIMIT_BREAKAWAY_OK != 0
+}